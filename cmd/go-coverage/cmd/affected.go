@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/impact"
+)
+
+// ErrUnsupportedAffectedFormat indicates an unknown affected output format
+var ErrUnsupportedAffectedFormat = errors.New("unsupported affected format")
+
+// Output formats supported by the affected command
+const (
+	affectedFormatList = "list"
+	affectedFormatJSON = "json"
+)
+
+// newAffectedCmd creates the affected command
+func (c *Commands) newAffectedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "affected",
+		Short: "List the packages whose tests cover a change",
+		Long: `Map the files changed since a git ref onto the packages that contain them and
+every package that imports those, directly or through its tests. Running the tests
+of the affected packages is enough to refresh coverage for the change.
+
+Changes to go.mod, go.sum or go.work affect every package; Markdown files affect none.
+The list format prints one import path per line for use with go test, and the json
+format can be fed to a CI matrix to shard the test run.`,
+		Example: `  go test -cover $(go-coverage affected --diff origin/master)
+  go-coverage affected --diff HEAD~1 --format json -o affected.json`,
+		RunE: c.runAffected,
+	}
+
+	cmd.Flags().String("diff", "", "Git ref to diff against (defaults to the merge base with the upstream branch)")
+	cmd.Flags().String("format", affectedFormatList, "Output format (list or json)")
+	cmd.Flags().StringP("output", "o", "", "Write the result to a file instead of the console")
+	return cmd
+}
+
+// runAffected executes the affected command
+func (c *Commands) runAffected(cmd *cobra.Command, _ []string) error {
+	base, _ := cmd.Flags().GetString("diff")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if format != affectedFormatList && format != affectedFormatJSON {
+		return fmt.Errorf("%w: %q (expected list or json)", ErrUnsupportedAffectedFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := affectedPackages(ctx, base)
+	if err != nil {
+		return err
+	}
+
+	var output string
+	if format == affectedFormatJSON {
+		data, marshalErr := json.MarshalIndent(result, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal affected packages: %w", marshalErr)
+		}
+		output = string(data) + "\n"
+	} else if len(result.Affected) > 0 {
+		output = strings.Join(result.Affected, "\n") + "\n"
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+		return nil
+	}
+	if err = os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write affected packages: %w", err)
+	}
+	cmd.Printf("Affected packages written to %s\n", outputPath)
+	return nil
+}
+
+// affectedPackages runs test impact analysis for the files changed since base, which
+// defaults to the merge base with the upstream branch
+func affectedPackages(ctx context.Context, base string) (*impact.Result, error) {
+	var err error
+	if base == "" {
+		if base, err = pushBase(ctx); err != nil {
+			return nil, err
+		}
+	}
+	files, err := gitChangedFiles(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	graph, err := impact.LoadGraph(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Affected(root, files), nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/impact"
+)
+
+func TestAffectedCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := initHookRepo(t)
+	git := func(args ...string) string {
+		out, err := exec.Command("git", args...).Output() //nolint:gosec,noctx // fixed test arguments
+		require.NoError(t, err)
+		return string(bytes.TrimSpace(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	write("go.mod", "module example.com/impact\n\ngo 1.21\n")
+	write("calc/calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n")
+	write("app/app.go", "package app\n\nimport \"example.com/impact/calc\"\n\nfunc Run() int {\n\treturn calc.Add(1, calc.Sub(3, 2))\n}\n")
+	write("app/app_test.go", "package app\n\nimport \"testing\"\n\nfunc TestRun(t *testing.T) {\n\tif Run() != 2 {\n\t\tt.Fatal(\"bad run\")\n\t}\n}\n")
+	write("other/other.go", "package other\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "base")
+	base := git("rev-parse", "HEAD")
+
+	write("calc/calc.go", "package calc\n\n// Add returns a+b\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n")
+	write("calc/README.md", "calc\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "calc")
+
+	t.Run("list", func(t *testing.T) {
+		output, err := executeCommand(t, "affected", "--diff", base)
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/impact/app\nexample.com/impact/calc\n", output)
+	})
+
+	t.Run("json to file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "affected.json")
+		_, err := executeCommand(t, "affected", "--diff", base, "--format", "json", "-o", outputPath)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(outputPath) //nolint:gosec // test file path
+		require.NoError(t, err)
+		var result impact.Result
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, []string{"calc/README.md", "calc/calc.go"}, result.Files)
+		assert.Equal(t, []string{"example.com/impact/calc"}, result.Changed)
+		assert.Equal(t, []string{"example.com/impact/app", "example.com/impact/calc"}, result.Affected)
+		assert.False(t, result.All)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := executeCommand(t, "affected", "--diff", base, "--format", "yaml")
+		require.ErrorIs(t, err, ErrUnsupportedAffectedFormat)
+	})
+
+	t.Run("hooks run tests dependents", func(t *testing.T) {
+		t.Setenv("GO_COVERAGE_THRESHOLD", "100")
+		output, err := executeCommand(t, "hooks", "run", "--base", base)
+		require.ErrorIs(t, err, ErrCoverageBelowThreshold, "calc has no tests of its own")
+
+		output, err = executeCommand(t, "hooks", "run", "--base", base, "--affected")
+		require.NoError(t, err, output)
+		assert.Contains(t, output, "Running tests of 2 affected package(s)")
+		assert.Contains(t, output, "Changed packages coverage: 100.00%")
+	})
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/schema"
+)
+
+// ErrUnsupportedAnalyzeFormat indicates an unknown analyze output format
+var ErrUnsupportedAnalyzeFormat = errors.New("unsupported analyze format")
+
+// Output formats supported by the analyze command
+const (
+	analyzeFormatJSON     = "json"
+	analyzeFormatMarkdown = "markdown"
+	analyzeFormatHTML     = "html"
+	analyzeFormatSVG      = "svg"
+)
+
+// analyzeReportSchema versions the JSON output of the analyze command
+//
+//nolint:gochecknoglobals // read-only schema definition
+var analyzeReportSchema = schema.Schema{
+	Name:       "trend report",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// analyzeReport is the JSON output of the analyze command: the trend report of a branch
+type analyzeReport struct {
+	SchemaVersion int    `json:"schema_version"`
+	Branch        string `json:"branch"`
+	Days          int    `json:"days"`
+	*analytics.TrendReport
+}
+
+// newAnalyzeCmd creates the analyze command
+func (c *Commands) newAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze coverage trends of a branch",
+		Long: `Analyze the coverage history of a branch: short, medium and long-term trends, volatility,
+predictions, data quality, insights and recommendations.
+
+JSON output is the full trend report for automation; Markdown and HTML outputs are
+readable reports for wikis, job summaries and release notes. SVG output is a static chart of
+coverage, its moving average and the prediction band, for embedding in READMEs. The format
+follows the --output extension (.md, .html or .svg) unless --format is given.`,
+		Example: `  # Trend report of the last 90 days of main as JSON
+  go-coverage analyze --branch main --days 90 --format json
+
+  # Readable report of a repository merging weekly
+  go-coverage analyze --cadence 168h --output trend.md
+
+  # Trend chart for the README
+  go-coverage analyze --output docs/coverage-trend.svg`,
+		RunE: runAnalyze,
+	}
+
+	cmd.Flags().StringP("branch", "b", "", "Branch to analyze (default: the current or default branch)")
+	cmd.Flags().IntP("days", "d", 90, "Number of days of history to analyze")
+	cmd.Flags().String("format", analyzeFormatJSON, "Output format (json, markdown, html or svg)")
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file instead of the console (the extension implies the format)")
+	cmd.Flags().Duration("cadence", 0, "Expected time between runs, such as 24h or 168h (default: inferred from the history)")
+
+	return cmd
+}
+
+// runAnalyze executes the analyze command
+func runAnalyze(cmd *cobra.Command, _ []string) error {
+	branch, _ := cmd.Flags().GetString("branch")
+	days, _ := cmd.Flags().GetInt("days")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+	cadence, _ := cmd.Flags().GetDuration("cadence")
+
+	formats := []string{analyzeFormatJSON, analyzeFormatMarkdown, analyzeFormatHTML, analyzeFormatSVG}
+	if err := checkOutputPath(outputPath, formats...); err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("format") {
+		if extFormat, ok := analyzeExtensionFormats()[strings.ToLower(filepath.Ext(outputPath))]; ok {
+			format = extFormat
+		}
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("%w: %q (expected json, markdown, html or svg)", ErrUnsupportedAnalyzeFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if branch == "" {
+		branch = getDefaultBranch()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tracker := history.NewWithConfig(&history.Config{
+		StoragePath: historyStoragePath(cfg),
+		Repository:  cfg.RepositorySlug(),
+		MaxEntries:  cfg.History.MaxEntries,
+	})
+
+	analyzerConfig := analytics.DefaultAnalyzerConfig()
+	analyzerConfig.LongTermDays = days
+	analyzerConfig.ExpectedCadence = cadence
+	analyzer := analytics.NewTrendAnalyzer(analyzerConfig)
+	if err = analyzer.LoadHistoryData(ctx, tracker, branch, days); err != nil {
+		return err
+	}
+	trendReport, err := analyzer.AnalyzeTrends(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to analyze coverage trends of %s: %w", branch, err)
+	}
+
+	report := &analyzeReport{
+		SchemaVersion: analyzeReportSchema.Version(),
+		Branch:        branch,
+		Days:          days,
+		TrendReport:   trendReport,
+	}
+	var output string
+	switch format {
+	case analyzeFormatMarkdown:
+		output = renderAnalyzeMarkdown(report)
+	case analyzeFormatHTML:
+		if output, err = renderAnalyzeHTML(report); err != nil {
+			return err
+		}
+	case analyzeFormatSVG:
+		output = string(analytics.RenderTrendSVG(report.ChartData, "Coverage trend: "+branch))
+	default:
+		data, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal trend report: %w", marshalErr)
+		}
+		output = string(data) + "\n"
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+		return nil
+	}
+	if err = os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write trend report: %w", err)
+	}
+	cmd.Printf("Trend report written to %s\n", outputPath)
+	return nil
+}
+
+// analyzeExtensionFormats maps --output file extensions to the format they imply
+func analyzeExtensionFormats() map[string]string {
+	return map[string]string{
+		".json": analyzeFormatJSON,
+		".md":   analyzeFormatMarkdown,
+		".html": analyzeFormatHTML,
+		".svg":  analyzeFormatSVG,
+	}
+}
+
+// analyzeTrends returns the period trends of a report, shortest first
+func analyzeTrends(report *analyzeReport) []analytics.TrendAnalysis {
+	return []analytics.TrendAnalysis{report.ShortTermTrend, report.MediumTermTrend, report.LongTermTrend}
+}
+
+// renderAnalyzeMarkdown renders a trend report as Markdown
+func renderAnalyzeMarkdown(report *analyzeReport) string {
+	var b strings.Builder
+	summary := report.Summary
+
+	fmt.Fprintf(&b, "# 📊 Coverage trends: `%s`\n\n", report.Branch)
+	fmt.Fprintf(&b, "Last %d days, %d %s, generated %s.\n\n",
+		report.Days, report.DataPointCount, pluralRuns(report.DataPointCount), report.GeneratedAt.UTC().Format(time.RFC3339))
+	b.WriteString("| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| **Current** | %.2f%% (grade %s) |\n", summary.CurrentCoverage, summary.QualityGrade)
+	fmt.Fprintf(&b, "| **Last change** | %+.2f%% (%s, %s) |\n", summary.Change, summary.Direction, summary.Magnitude)
+	fmt.Fprintf(&b, "| **Volatility** | %s (σ %.2f) |\n", report.Volatility.VolatilityLevel, report.Volatility.StandardDeviation)
+	fmt.Fprintf(&b, "| **Data quality** | %s (%.0f%% complete) |\n\n", report.QualityMetrics.ReliabilityGrade, report.QualityMetrics.DataCompleteness)
+
+	b.WriteString("## Trends\n\n| Period | Direction | Slope | R² | Momentum |\n|---|---|---:|---:|---|\n")
+	for _, trend := range analyzeTrends(report) {
+		fmt.Fprintf(&b, "| %s | %s | %+.3f | %.2f | %s |\n", trend.Period, trend.Direction, trend.Slope, trend.RSquared, trend.Momentum)
+	}
+	b.WriteString("\n")
+
+	if len(report.Predictions) > 0 {
+		last := report.Predictions[len(report.Predictions)-1]
+		fmt.Fprintf(&b, "**Prediction:** %.2f%% by %s (%.2f%% – %.2f%%)\n\n",
+			last.PredictedCoverage, last.Date.Format("2006-01-02"), last.ConfidenceInterval.Lower, last.ConfidenceInterval.Upper)
+	}
+
+	if len(report.Insights) > 0 {
+		b.WriteString("## Insights\n\n")
+		for _, insight := range report.Insights {
+			fmt.Fprintf(&b, "- **%s** (%s): %s\n", insight.Title, insight.Severity, insight.Description)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(report.Recommendations) > 0 {
+		b.WriteString("## Recommendations\n\n")
+		for _, recommendation := range report.Recommendations {
+			fmt.Fprintf(&b, "### %s (%s priority)\n\n%s\n\n", recommendation.Title, recommendation.Priority, recommendation.Description)
+			for _, action := range recommendation.Actions {
+				fmt.Fprintf(&b, "- %s\n", action)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// analyzeHTMLTemplate renders a trend report as a standalone HTML page
+//
+//nolint:gochecknoglobals // parsed once at startup
+var analyzeHTMLTemplate = template.Must(template.New("analyze").Funcs(template.FuncMap{
+	"pct":    func(value float64) string { return fmt.Sprintf("%.2f%%", value) },
+	"signed": func(value float64) string { return fmt.Sprintf("%+.3f", value) },
+	"date":   func(value time.Time) string { return value.Format("2006-01-02") },
+	"trends": analyzeTrends,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Coverage trends: {{.Branch}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.4rem 0.8rem; text-align: left; }
+.critical { color: #cf222e; } .warning { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>Coverage trends: <code>{{.Branch}}</code></h1>
+<p>Last {{.Days}} days, {{.DataPointCount}} runs, generated {{.GeneratedAt.UTC.Format "2006-01-02 15:04 UTC"}}.</p>
+<table>
+<tr><th>Current</th><td>{{pct .Summary.CurrentCoverage}} (grade {{.Summary.QualityGrade}})</td></tr>
+<tr><th>Last change</th><td>{{pct .Summary.Change}} ({{.Summary.Direction}}, {{.Summary.Magnitude}})</td></tr>
+<tr><th>Volatility</th><td>{{.Volatility.VolatilityLevel}}</td></tr>
+<tr><th>Data quality</th><td>{{.QualityMetrics.ReliabilityGrade}} ({{pct .QualityMetrics.DataCompleteness}} complete)</td></tr>
+</table>
+<h2>Trends</h2>
+<table>
+<tr><th>Period</th><th>Direction</th><th>Slope</th><th>Momentum</th></tr>
+{{range trends .}}<tr><td>{{.Period}}</td><td>{{.Direction}}</td><td>{{signed .Slope}}</td><td>{{.Momentum}}</td></tr>
+{{end}}</table>
+{{with .Predictions}}<h2>Predictions</h2>
+<table>
+<tr><th>Date</th><th>Coverage</th><th>Range</th></tr>
+{{range .}}<tr><td>{{date .Date}}</td><td>{{pct .PredictedCoverage}}</td><td>{{pct .ConfidenceInterval.Lower}} – {{pct .ConfidenceInterval.Upper}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Insights}}<h2>Insights</h2>
+<ul>
+{{range .}}<li class="{{.Severity}}"><strong>{{.Title}}</strong>: {{.Description}}</li>
+{{end}}</ul>
+{{end}}{{with .Recommendations}}<h2>Recommendations</h2>
+{{range .}}<h3>{{.Title}} ({{.Priority}} priority)</h3>
+<p>{{.Description}}</p>
+<ul>
+{{range .Actions}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// renderAnalyzeHTML renders a trend report as a standalone HTML page
+func renderAnalyzeHTML(report *analyzeReport) (string, error) {
+	var b strings.Builder
+	if err := analyzeHTMLTemplate.Execute(&b, report); err != nil {
+		return "", fmt.Errorf("failed to render trend report: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// setupAnalyze records a rising coverage history for the main branch
+func setupAnalyze(t *testing.T, runs int) {
+	t.Helper()
+	isolateOfflineEnv(t)
+
+	historyDir := filepath.Join(t.TempDir(), "history")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", historyDir)
+
+	tracker := history.NewWithConfig(&history.Config{StoragePath: historyDir, MaxEntries: 100})
+	for i := range runs {
+		coverage := &parser.CoverageData{Percentage: 70 + float64(i), TotalLines: 100, CoveredLines: 70 + i}
+		require.NoError(t, tracker.Record(context.Background(), coverage, history.WithBranch("main")))
+	}
+}
+
+func TestAnalyzeCommandOutputs(t *testing.T) {
+	setupAnalyze(t, 6)
+
+	output, err := executeCommand(t, "analyze", "--branch", "main", "--days", "90", "--format", formatJSON)
+	require.NoError(t, err)
+	var report analyzeReport
+	require.NoError(t, analyzeReportSchema.Decode([]byte(output), &report))
+	assert.Equal(t, analyzeReportSchema.Version(), report.SchemaVersion)
+	assert.Equal(t, "main", report.Branch)
+	assert.Equal(t, 90, report.Days)
+	assert.Equal(t, 6, report.DataPointCount)
+	assert.InDelta(t, 75.0, report.Summary.CurrentCoverage, 0.01)
+	assert.NotEmpty(t, report.Predictions)
+
+	output, err = executeCommand(t, "analyze", "--branch", "main", "--format", "markdown")
+	require.NoError(t, err)
+	assert.Contains(t, output, "# 📊 Coverage trends: `main`")
+	assert.Contains(t, output, "| **Current** | 75.00%")
+	assert.Contains(t, output, "## Trends")
+
+	output, err = executeCommand(t, "analyze", "--branch", "main", "--format", "html")
+	require.NoError(t, err)
+	assert.Contains(t, output, "<!DOCTYPE html>")
+	assert.Contains(t, output, "<h1>Coverage trends: <code>main</code></h1>")
+
+	output, err = executeCommand(t, "analyze", "--branch", "main", "--format", "svg")
+	require.NoError(t, err)
+	assert.Contains(t, output, `<svg xmlns="http://www.w3.org/2000/svg"`)
+	assert.Contains(t, output, "Coverage trend: main")
+	assert.Contains(t, output, "<polyline")
+}
+
+func TestAnalyzeCommandOutputFile(t *testing.T) {
+	setupAnalyze(t, 6)
+	dir := t.TempDir()
+
+	svgPath := filepath.Join(dir, "trend.svg")
+	output, err := executeCommand(t, "analyze", "--branch", "main", "--output", svgPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Trend report written to "+svgPath)
+	data, err := os.ReadFile(svgPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<svg xmlns="http://www.w3.org/2000/svg"`)
+
+	mdPath := filepath.Join(dir, "trend.txt")
+	_, err = executeCommand(t, "analyze", "--branch", "main", "--format", "markdown", "--output", mdPath)
+	require.NoError(t, err)
+	data, err = os.ReadFile(mdPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# 📊 Coverage trends: `main`")
+}
+
+func TestAnalyzeCommandErrors(t *testing.T) {
+	setupAnalyze(t, 2)
+
+	_, err := executeCommand(t, "analyze", "--format", "xml")
+	require.ErrorIs(t, err, ErrUnsupportedAnalyzeFormat)
+
+	_, err = executeCommand(t, "analyze", "--output", "markdown")
+	require.ErrorIs(t, err, ErrOutputIsFormat)
+	require.ErrorContains(t, err, "--format markdown")
+
+	_, err = executeCommand(t, "analyze", "--branch", "main")
+	require.ErrorIs(t, err, analytics.ErrInsufficientDataPoints)
+}
+
+func TestRenderAnalyzeMarkdownRecommendations(t *testing.T) {
+	report := &analyzeReport{
+		Branch: "main",
+		Days:   30,
+		TrendReport: &analytics.TrendReport{
+			Insights: []analytics.Insight{{Title: "Coverage declining", Severity: analytics.SeverityWarning, Description: "Down 3% this week"}},
+			Recommendations: []analytics.Recommendation{{
+				Title:    "Add tests",
+				Priority: analytics.PriorityHigh,
+				Actions:  []string{"Cover the parser"},
+			}},
+		},
+	}
+
+	output := renderAnalyzeMarkdown(report)
+	assert.Contains(t, output, "- **Coverage declining** (warning): Down 3% this week")
+	assert.Contains(t, output, "### Add tests (high priority)")
+	assert.Contains(t, output, "- Cover the parser")
+}
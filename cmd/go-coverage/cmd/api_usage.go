@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// apiUsageSummary is the API usage of a run in the json log format
+type apiUsageSummary struct {
+	Usage      github.UsageReport `json:"github_api_usage"`
+	Budget     int                `json:"budget,omitempty"`
+	OverBudget bool               `json:"over_budget,omitempty"`
+}
+
+// reportAPIUsage summarizes the GitHub API requests of a run on stderr, by category and with the
+// quota left, and warns when they exceed GO_COVERAGE_GITHUB_API_BUDGET. Runs without requests
+// print nothing.
+func reportAPIUsage(cmd *cobra.Command, report github.UsageReport) {
+	if report.Total == 0 {
+		return
+	}
+
+	printAPIUsage(cmd, report, config.GitHubAPIBudget())
+}
+
+// printAPIUsage writes the API usage summary, as text or, with the json log format, as a JSON
+// object
+func printAPIUsage(cmd *cobra.Command, report github.UsageReport, budget int) {
+	overBudget := budget > 0 && report.Total > budget
+
+	if logFormat(cmd) == "json" {
+		data, err := json.Marshal(apiUsageSummary{Usage: report, Budget: budget, OverBudget: overBudget})
+		if err == nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), string(data))
+		}
+		return
+	}
+
+	categories := make([]string, 0, len(report.Calls))
+	for _, count := range report.Categories() {
+		categories = append(categories, fmt.Sprintf("%s %d", count.Category, count.Calls))
+	}
+	line := fmt.Sprintf("📡 GitHub API: %d requests (%s)", report.Total, strings.Join(categories, ", "))
+	if rate := report.RateLimit; rate.Known() {
+		line += fmt.Sprintf(", %d of %d left", rate.Remaining, rate.Limit)
+		if !rate.Reset.IsZero() {
+			line += " until " + rate.Reset.UTC().Format("15:04 MST")
+		}
+	}
+	_, _ = fmt.Fprintln(cmd.ErrOrStderr(), line)
+
+	if overBudget {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  GitHub API budget exceeded: %d requests, budget %d (GO_COVERAGE_GITHUB_API_BUDGET)\n",
+			report.Total, budget)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+func TestPrintAPIUsage(t *testing.T) {
+	report := github.UsageReport{
+		Total:     14,
+		Calls:     map[string]int{"comments": 6, "statuses": 6, "pulls": 2},
+		RateLimit: github.RateLimit{Limit: 5000, Remaining: 4321, Reset: time.Date(2026, 10, 17, 14, 5, 0, 0, time.UTC)},
+	}
+	run := func(t *testing.T, budget int) string {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.PersistentFlags().String("log-format", "text", "")
+		var out bytes.Buffer
+		cmd.SetErr(&out)
+		printAPIUsage(cmd, report, budget)
+		return out.String()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Setenv(envLogFormat, "")
+		output := run(t, 0)
+		assert.Equal(t, "📡 GitHub API: 14 requests (comments 6, statuses 6, pulls 2), 4321 of 5000 left until 14:05 UTC\n", output)
+
+		output = run(t, 10)
+		assert.Contains(t, output, "⚠️  GitHub API budget exceeded: 14 requests, budget 10")
+		assert.NotContains(t, run(t, 20), "budget exceeded")
+	})
+
+	t.Run("json log format", func(t *testing.T) {
+		t.Setenv(envLogFormat, "json")
+		var summary apiUsageSummary
+		require.NoError(t, json.Unmarshal([]byte(run(t, 10)), &summary))
+		assert.Equal(t, 14, summary.Usage.Total)
+		assert.Equal(t, 6, summary.Usage.Calls["statuses"])
+		assert.Equal(t, 10, summary.Budget)
+		assert.True(t, summary.OverBudget)
+	})
+
+	t.Run("no requests", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		var out bytes.Buffer
+		cmd.SetErr(&out)
+		reportAPIUsage(cmd, github.UsageReport{})
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("budget from the environment", func(t *testing.T) {
+		t.Setenv(envLogFormat, "")
+		t.Setenv("GO_COVERAGE_GITHUB_API_BUDGET", "10")
+		cmd := &cobra.Command{}
+		cmd.PersistentFlags().String("log-format", "text", "")
+		var out bytes.Buffer
+		cmd.SetErr(&out)
+		reportAPIUsage(cmd, report)
+		assert.Contains(t, out.String(), "budget 10")
+	})
+}
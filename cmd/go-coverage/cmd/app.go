@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/githubapp"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+var (
+	// ErrAppIDRequired indicates that the GitHub App ID is not configured
+	ErrAppIDRequired = errors.New("GitHub App ID is required (GO_COVERAGE_APP_ID)")
+	// ErrAppPrivateKeyRequired indicates that the GitHub App private key is not configured
+	ErrAppPrivateKeyRequired = errors.New("GitHub App private key is required (GO_COVERAGE_APP_PRIVATE_KEY or GO_COVERAGE_APP_PRIVATE_KEY_FILE)")
+	// ErrAppWebhookSecretRequired indicates that the webhook secret is not configured
+	ErrAppWebhookSecretRequired = errors.New("GitHub App webhook secret is required (GO_COVERAGE_APP_WEBHOOK_SECRET)")
+)
+
+const (
+	// appQueueSize bounds the deliveries waiting for the worker; GitHub redelivers rejected ones
+	appQueueSize = 100
+	// appEventTimeout bounds the work done for a single delivery
+	appEventTimeout = 2 * time.Minute
+	// appShutdownTimeout bounds the wait for in-flight requests on shutdown
+	appShutdownTimeout = 15 * time.Second
+	// appUserAgent identifies the requests the App server makes with installation tokens
+	appUserAgent = "go-coverage-app/1.0"
+)
+
+// newAppCmd creates the app command
+func (c *Commands) newAppCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app",
+		Short: "Enforce coverage centrally as a GitHub App",
+		Long: `Run go-coverage as a GitHub App that enforces coverage for every repository it is
+installed on, so the policy lives on one server instead of in each repository's workflow.`,
+	}
+
+	cmd.AddCommand(c.newAppServeCmd())
+	return cmd
+}
+
+// newAppServeCmd creates the app serve command
+func (c *Commands) newAppServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Receive GitHub App webhooks and post coverage checks and comments",
+		Long: `Start a long-running server that receives the webhooks of a GitHub App.
+
+Repositories upload the coverage-handoff artifact from their workflows (set
+GO_COVERAGE_FORK_MODE=always) and need no write token. When a check suite completes, the
+server downloads the artifact of each of its workflow runs, verifies that it belongs to the
+pull request head it claims, evaluates the coverage gates with the server's own policy and
+posts the comment and status checks as the App. New pull request heads get a pending
+coverage status until their results arrive.
+
+The App needs the Checks, Actions (read), Pull requests and Commit statuses permissions and
+the check_suite and pull_request events. Configure it with GO_COVERAGE_APP_ID,
+GO_COVERAGE_APP_PRIVATE_KEY (or GO_COVERAGE_APP_PRIVATE_KEY_FILE) and
+GO_COVERAGE_APP_WEBHOOK_SECRET. Webhooks are received on /webhook; /healthz reports liveness.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			addr, _ := cmd.Flags().GetString("addr")
+			artifactName, _ := cmd.Flags().GetString("artifact")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err = requireNetwork(cmd, cfg, "the app server"); err != nil {
+				return err
+			}
+			if addr == "" {
+				addr = cfg.App.Addr
+			}
+			if artifactName == "" {
+				artifactName = cfg.App.Artifact
+			}
+
+			app, err := newGitHubApp(cfg)
+			if err != nil {
+				return err
+			}
+
+			processor := &appProcessor{
+				cmd:      cmd,
+				cfg:      cfg,
+				tokens:   app,
+				artifact: artifactName,
+				newClient: func(token string) (github.API, error) {
+					clientCfg := *cfg
+					clientCfg.GitHub.Token = token
+					return newGitHubClient(&clientCfg, appUserAgent)
+				},
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return serveApp(ctx, cmd, addr, cfg.App.WebhookSecret, processor)
+		},
+	}
+
+	cmd.Flags().String("addr", "", "Address to listen on (default: GO_COVERAGE_APP_ADDR or :8080)")
+	cmd.Flags().String("artifact", "", "Name of the handoff artifact (default: GO_COVERAGE_APP_ARTIFACT or coverage-handoff)")
+
+	return cmd
+}
+
+// newGitHubApp validates the App settings and authenticates as the App
+func newGitHubApp(cfg *config.Config) (*githubapp.App, error) {
+	if cfg.App.ID == 0 {
+		return nil, ErrAppIDRequired
+	}
+	if cfg.App.WebhookSecret == "" {
+		return nil, ErrAppWebhookSecretRequired
+	}
+
+	key := []byte(cfg.App.PrivateKey)
+	if len(key) == 0 {
+		if cfg.App.PrivateKeyFile == "" {
+			return nil, ErrAppPrivateKeyRequired
+		}
+		data, err := os.ReadFile(cfg.App.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+		key = data
+	}
+
+	httpClient, err := cfg.NewHTTPClient(cfg.GitHub.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	return githubapp.New(cfg.App.ID, key, githubAPIBaseURL, httpClient)
+}
+
+// serveApp receives webhooks until ctx is canceled. Deliveries are acknowledged right away and
+// processed one at a time by a single worker, which keeps API usage within the rate limits.
+func serveApp(ctx context.Context, cmd *cobra.Command, addr, secret string, processor *appProcessor) error {
+	queue := make(chan *githubapp.Event, appQueueSize)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for event := range queue {
+			processor.handle(ctx, event)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", &githubapp.Handler{
+		Secret: secret,
+		Dispatch: func(event *githubapp.Event) bool {
+			select {
+			case queue <- event:
+				return true
+			default:
+				return false
+			}
+		},
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	cmd.Printf("🤖 GitHub App server listening on %s\n", addr)
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		cmd.Printf("🛑 Shutting down\n")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), appShutdownTimeout)
+		err = server.Shutdown(shutdownCtx)
+		cancel()
+	}
+
+	// The handler no longer dispatches once the server is closed
+	close(queue)
+	wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// installationTokenSource mints installation access tokens; implemented by githubapp.App
+type installationTokenSource interface {
+	InstallationToken(ctx context.Context, installationID int64) (string, error)
+}
+
+// appProcessor acts on the webhook events of the App
+type appProcessor struct {
+	cmd       *cobra.Command
+	cfg       *config.Config
+	tokens    installationTokenSource
+	newClient func(token string) (github.API, error)
+	artifact  string
+}
+
+// handle processes an event, logging failures; GitHub has already been answered
+func (p *appProcessor) handle(ctx context.Context, event *githubapp.Event) {
+	ctx, cancel := context.WithTimeout(ctx, appEventTimeout)
+	defer cancel()
+
+	p.cmd.Printf("📥 %s.%s for %s/%s@%s (delivery %s)\n", event.Name, event.Action,
+		event.Owner, event.Repository, shortSHA(event.HeadSHA), event.Delivery)
+	if err := p.process(ctx, event); err != nil {
+		p.cmd.Printf("❌ %s/%s: %v\n", event.Owner, event.Repository, err)
+	}
+}
+
+// process acts on a single event with an installation token of the repository
+func (p *appProcessor) process(ctx context.Context, event *githubapp.Event) error {
+	token, err := p.tokens.InstallationToken(ctx, event.InstallationID)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate installation %d: %w", event.InstallationID, err)
+	}
+	client, err := p.newClient(token)
+	if err != nil {
+		return err
+	}
+	cfg := p.eventConfig(event)
+
+	switch event.Name {
+	case githubapp.EventPullRequest:
+		return client.CreateStatus(ctx, event.Owner, event.Repository, event.HeadSHA, &github.StatusRequest{
+			State:       github.StatusPending,
+			Description: "Waiting for coverage results",
+			Context:     "go-coverage/" + github.ContextCoverage,
+		})
+	case githubapp.EventCheckSuite:
+		return p.relayCheckSuite(ctx, cfg, client, event)
+	}
+	return nil
+}
+
+// eventConfig returns the server configuration pointed at the repository and commit of an event
+func (p *appProcessor) eventConfig(event *githubapp.Event) *config.Config {
+	cfg := *p.cfg
+	cfg.GitHub.Token = ""
+	cfg.GitHub.Owner = event.Owner
+	cfg.GitHub.Repository = event.Repository
+	cfg.GitHub.CommitSHA = event.HeadSHA
+	run := &ci.Context{
+		Provider:   "github-app",
+		Event:      event.Name,
+		Owner:      event.Owner,
+		Repository: event.Repository,
+		Branch:     event.HeadBranch,
+		BaseBranch: event.BaseBranch,
+		CommitSHA:  event.HeadSHA,
+	}
+	if len(event.PullRequests) > 0 {
+		run.PullRequest = event.PullRequests[0]
+		cfg.GitHub.PullRequest = run.PullRequest
+	}
+	cfg.CI = run
+	return &cfg
+}
+
+// relayCheckSuite posts the coverage handed off by the workflow runs of a completed check suite.
+// The statuses recorded by the repository are ignored: the gates are evaluated with the
+// server's policy, so a workflow cannot report its own verdict.
+func (p *appProcessor) relayCheckSuite(ctx context.Context, cfg *config.Config, client github.API, event *githubapp.Event) error {
+	runs, err := client.ListCheckSuiteWorkflowRuns(ctx, event.Owner, event.Repository, event.CheckSuiteID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	relayed := 0
+	for _, run := range runs {
+		artifact, err := client.FindWorkflowRunArtifact(ctx, event.Owner, event.Repository, run.ID, p.artifact)
+		if errors.Is(err, github.ErrArtifactNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		p.cmd.Printf("⬇️  Downloading artifact %s from run %d\n", artifact.Name, run.ID)
+		archive, err := client.DownloadArtifact(ctx, event.Owner, event.Repository, artifact.ID, maxHandoffArtifactSize)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		payload, err := handoff.FromZip(archive)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = verifyRelayPayload(ctx, cfg, client, payload, run.HeadSHA); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		payload.Statuses = nil
+		payload.CreateStatusChecks = true
+		p.cmd.Printf("📨 Posting coverage for PR #%d (%s/%s)\n", payload.PullRequest, event.Owner, event.Repository)
+		if err = postRelayPayload(ctx, p.cmd, cfg, client, payload, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		relayed++
+	}
+
+	if relayed == 0 && len(errs) == 0 {
+		p.cmd.Printf("   No %s artifact in check suite %d\n", p.artifact, event.CheckSuiteID)
+	}
+	return errors.Join(errs...)
+}
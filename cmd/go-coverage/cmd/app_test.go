@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/githubapp"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+// staticTokens hands out one token per installation
+type staticTokens map[int64]string
+
+func (s staticTokens) InstallationToken(_ context.Context, installationID int64) (string, error) {
+	token, ok := s[installationID]
+	if !ok {
+		return "", errors.New("installation not found")
+	}
+	return token, nil
+}
+
+func newTestAppProcessor(fake *github.Fake, threshold float64) (*appProcessor, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	return &appProcessor{
+		cmd:      cmd,
+		cfg:      &config.Config{Coverage: config.CoverageConfig{Threshold: threshold}},
+		tokens:   staticTokens{1: "installation-token"},
+		artifact: "coverage-handoff",
+		newClient: func(token string) (github.API, error) {
+			if token != "installation-token" {
+				return nil, errors.New("unexpected token")
+			}
+			return fake, nil
+		},
+	}, out
+}
+
+func checkSuiteEvent() *githubapp.Event {
+	return &githubapp.Event{
+		Name:           githubapp.EventCheckSuite,
+		Action:         "completed",
+		InstallationID: 1,
+		Owner:          "owner",
+		Repository:     "repo",
+		HeadSHA:        relayHeadSHA,
+		HeadBranch:     "feature",
+		BaseBranch:     "master",
+		CheckSuiteID:   9,
+		PullRequests:   []int{7},
+	}
+}
+
+func TestAppProcessorCheckSuite(t *testing.T) {
+	payload := relayPayload()
+	payload.Coverage = &handoff.Coverage{Percentage: 50, TotalStatements: 10, CoveredStatements: 5}
+	// The repository claims success; the server applies its own threshold instead
+	payload.Statuses = []handoff.Status{{State: "success", Context: "go-coverage/coverage/total", Description: "forged"}}
+
+	fake := github.NewFake()
+	fake.PullRequests[7] = &github.PullRequest{Number: 7, Head: github.PullRequestRef{SHA: relayHeadSHA}}
+	fake.WorkflowRuns[5] = &github.WorkflowRun{ID: 5, CheckSuiteID: 9, HeadSHA: relayHeadSHA}
+	fake.WorkflowRuns[6] = &github.WorkflowRun{ID: 6, CheckSuiteID: 9, HeadSHA: relayHeadSHA}
+	fake.Artifacts[5] = []github.Artifact{{ID: 2, Name: "coverage-handoff"}}
+	fake.ArtifactData[2] = zipHandoff(t, payload)
+
+	processor, out := newTestAppProcessor(fake, 80)
+	require.NoError(t, processor.process(context.Background(), checkSuiteEvent()))
+
+	require.Len(t, fake.Comments[7], 1)
+	assert.Equal(t, "## Coverage 85%\n\n<!-- go-coverage:comment owner/repo -->\n", fake.Comments[7][0].Body)
+	assert.Contains(t, out.String(), "Downloading artifact coverage-handoff from run 5")
+
+	var total *github.StatusRequest
+	for i, status := range fake.Statuses[relayHeadSHA] {
+		assert.NotEqual(t, "forged", status.Description, "statuses of the handoff are not posted")
+		if status.Context == "go-coverage/"+github.ContextCoverage {
+			total = &fake.Statuses[relayHeadSHA][i]
+		}
+	}
+	require.NotNil(t, total)
+	assert.Equal(t, github.StatusFailure, total.State)
+}
+
+func TestAppProcessorRejectsStaleHandoff(t *testing.T) {
+	fake := github.NewFake()
+	fake.PullRequests[7] = &github.PullRequest{Number: 7, Head: github.PullRequestRef{SHA: "moved"}}
+	fake.WorkflowRuns[5] = &github.WorkflowRun{ID: 5, CheckSuiteID: 9, HeadSHA: relayHeadSHA}
+	fake.Artifacts[5] = []github.Artifact{{ID: 2, Name: "coverage-handoff"}}
+	fake.ArtifactData[2] = zipHandoff(t, relayPayload())
+
+	processor, _ := newTestAppProcessor(fake, 80)
+	err := processor.process(context.Background(), checkSuiteEvent())
+	require.ErrorIs(t, err, ErrRelayStalePullRequest)
+	assert.Empty(t, fake.Comments[7])
+}
+
+func TestAppProcessorWithoutArtifact(t *testing.T) {
+	fake := github.NewFake()
+	fake.WorkflowRuns[5] = &github.WorkflowRun{ID: 5, CheckSuiteID: 9, HeadSHA: relayHeadSHA}
+
+	processor, out := newTestAppProcessor(fake, 80)
+	require.NoError(t, processor.process(context.Background(), checkSuiteEvent()))
+	assert.Contains(t, out.String(), "No coverage-handoff artifact in check suite 9")
+	assert.Empty(t, fake.Statuses)
+}
+
+func TestAppProcessorPullRequest(t *testing.T) {
+	fake := github.NewFake()
+	processor, _ := newTestAppProcessor(fake, 80)
+
+	event := checkSuiteEvent()
+	event.Name = githubapp.EventPullRequest
+	event.Action = "synchronize"
+	require.NoError(t, processor.process(context.Background(), event))
+
+	require.Len(t, fake.Statuses[relayHeadSHA], 1)
+	assert.Equal(t, github.StatusPending, fake.Statuses[relayHeadSHA][0].State)
+	assert.Equal(t, "go-coverage/"+github.ContextCoverage, fake.Statuses[relayHeadSHA][0].Context)
+
+	event.InstallationID = 2
+	require.Error(t, processor.process(context.Background(), event))
+}
+
+func TestNewGitHubApp(t *testing.T) {
+	_, err := newGitHubApp(&config.Config{})
+	require.ErrorIs(t, err, ErrAppIDRequired)
+
+	_, err = newGitHubApp(&config.Config{App: config.AppConfig{ID: 1}})
+	require.ErrorIs(t, err, ErrAppWebhookSecretRequired)
+
+	_, err = newGitHubApp(&config.Config{App: config.AppConfig{ID: 1, WebhookSecret: "secret"}})
+	require.ErrorIs(t, err, ErrAppPrivateKeyRequired)
+
+	_, err = newGitHubApp(&config.Config{App: config.AppConfig{ID: 1, WebhookSecret: "secret", PrivateKey: "not a key"}})
+	require.ErrorIs(t, err, githubapp.ErrInvalidPrivateKey)
+}
+
+func TestServeAppShutdown(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	processor, _ := newTestAppProcessor(github.NewFake(), 80)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, serveApp(ctx, cmd, "127.0.0.1:0", "secret", processor))
+	assert.Contains(t, out.String(), "Shutting down")
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/attribution"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ErrInvalidLineRef indicates a line reference that is not file:line
+var ErrInvalidLineRef = errors.New("line must be given as file:line")
+
+// attributeTimeout bounds the whole analysis, which runs go test once per test
+const attributeTimeout = 2 * time.Hour
+
+// newAttributeCmd creates the attribute command
+func (c *Commands) newAttributeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attribute",
+		Short: "Attribute covered lines to the tests that execute them",
+		Long: `Run every test of a go test -json output on its own, with a coverage directory of its
+own, and record which tests executed each covered block. This is much slower than a
+single test run, as go test runs once per test, and is meant to run on demand or on a
+schedule rather than on every change.
+
+The index is written to test-attribution.json in the output directory, where the report
+server answers "which test covers this line?" at /tests. attribute lookup answers the
+same question on the console.`,
+		Example: `  go test -json ./... > test-results.json   # or gotestsum --jsonfile test-results.json
+  go-coverage attribute --test-json test-results.json
+  go-coverage attribute lookup internal/api/server.go:42`,
+		RunE: c.runAttribute,
+	}
+
+	cmd.Flags().String("test-json", "", "go test -json output naming the tests to run (required)")
+	cmd.Flags().String("coverpkg", "./...", "Packages whose coverage is attributed, as given to go test -coverpkg")
+	cmd.Flags().String("dir", ".", "Directory of the module the tests run in")
+	cmd.Flags().StringP("output", "o", "", "Index file (default: test-attribution.json in GO_COVERAGE_OUTPUT_DIR)")
+	_ = cmd.MarkFlagRequired("test-json")
+
+	cmd.AddCommand(c.newAttributeLookupCmd())
+	return cmd
+}
+
+// newAttributeLookupCmd creates the attribute lookup command
+func (c *Commands) newAttributeLookupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "lookup <file:line>",
+		Short:   "List the tests that execute a line",
+		Example: `  go-coverage attribute lookup internal/api/server.go:42`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			indexPath, _ := cmd.Flags().GetString("index")
+
+			file, line, err := parseLineRef(args[0])
+			if err != nil {
+				return err
+			}
+			if indexPath == "" {
+				cfg, loadErr := config.Load()
+				if loadErr != nil {
+					return fmt.Errorf("failed to load configuration: %w", loadErr)
+				}
+				indexPath = filepath.Join(cfg.Coverage.OutputDir, attribution.IndexFile)
+			}
+			ix, err := attribution.Load(indexPath)
+			if err != nil {
+				return err
+			}
+
+			tests := ix.Lookup(file, line)
+			if len(tests) == 0 {
+				cmd.Printf("No test executes %s:%d\n", file, line)
+				return nil
+			}
+			for _, test := range tests {
+				cmd.Println(test.String())
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("index", "", "Index file (default: test-attribution.json in GO_COVERAGE_OUTPUT_DIR)")
+	return cmd
+}
+
+// runAttribute executes the attribute command
+func (c *Commands) runAttribute(cmd *cobra.Command, _ []string) error {
+	testJSON, _ := cmd.Flags().GetString("test-json")
+	coverPkg, _ := cmd.Flags().GetString("coverpkg")
+	dir, _ := cmd.Flags().GetString("dir")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if outputPath == "" {
+		outputPath = filepath.Join(cfg.Coverage.OutputDir, attribution.IndexFile)
+	}
+
+	results, err := os.Open(testJSON) //nolint:gosec // path is provided by the user
+	if err != nil {
+		return fmt.Errorf("failed to open test results: %w", err)
+	}
+	tests, err := attribution.ListTests(results)
+	_ = results.Close()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), attributeTimeout)
+	defer cancel()
+
+	ix, err := attribution.Run(ctx, tests, attribution.Options{
+		Dir:      dir,
+		CoverPkg: coverPkg,
+		Parser: parser.NewWithConfig(&parser.Config{
+			ExcludePaths:     cfg.Coverage.ExcludePaths,
+			ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+			ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+			ExcludePresets:   cfg.Coverage.ExcludePresets,
+			Limits:           cfg.ParserLimits(),
+			ModuleRewrites:   cfg.ModuleRewrites(),
+			ModuleRoot:       cfg.Coverage.ModuleRoot,
+			DiscoveryIgnore:  cfg.Coverage.DiscoveryIgnore,
+		}),
+		Progress: func(done, total int, test attribution.Test) {
+			cmd.Printf("🔬 [%d/%d] %s\n", done+1, total, test)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = ix.Save(outputPath); err != nil {
+		return err
+	}
+
+	cmd.Printf("✅ Attributed %d files to %d tests in %s\n", len(ix.Files), len(ix.Tests), outputPath)
+	for _, test := range ix.Failed {
+		cmd.Printf("⚠️  %s failed; the coverage it produced is attributed to it\n", test)
+	}
+	return nil
+}
+
+// parseLineRef splits a file:line reference
+func parseLineRef(ref string) (string, int, error) {
+	i := strings.LastIndex(ref, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidLineRef, ref)
+	}
+	line, err := strconv.Atoi(ref[i+1:])
+	if err != nil || line <= 0 {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidLineRef, ref)
+	}
+	return ref[:i], line, nil
+}
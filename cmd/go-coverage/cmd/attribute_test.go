@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/attribution"
+)
+
+func TestParseLineRef(t *testing.T) {
+	file, line, err := parseLineRef("internal/api/server.go:42")
+	require.NoError(t, err)
+	assert.Equal(t, "internal/api/server.go", file)
+	assert.Equal(t, 42, line)
+
+	for _, ref := range []string{"server.go", ":42", "server.go:", "server.go:0", "server.go:x"} {
+		_, _, err = parseLineRef(ref)
+		require.ErrorIs(t, err, ErrInvalidLineRef, ref)
+	}
+}
+
+func TestAttributeLookupCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+
+	path := filepath.Join(t.TempDir(), attribution.IndexFile)
+	ix := attribution.NewIndex([]attribution.Test{
+		{Package: "example.com/app/internal/api", Name: "TestServe"},
+		{Package: "example.com/app/internal/api", Name: "TestRetry"},
+	})
+	ix.Files["internal/api/server.go"] = []attribution.Block{{StartLine: 40, EndLine: 44, Tests: []int{0, 1}}}
+	require.NoError(t, ix.Save(path))
+
+	output, err := executeCommand(t, "attribute", "lookup", "internal/api/server.go:42", "--index", path)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/app/internal/api.TestRetry\nexample.com/app/internal/api.TestServe\n", output)
+
+	output, err = executeCommand(t, "attribute", "lookup", "internal/api/server.go:45", "--index", path)
+	require.NoError(t, err)
+	assert.Contains(t, output, "No test executes internal/api/server.go:45")
+
+	_, err = executeCommand(t, "attribute", "--test-json", filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
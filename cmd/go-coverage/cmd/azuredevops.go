@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/azuredevops"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// ErrAzureDevOpsContextRequired indicates that the project or repository is unknown
+var ErrAzureDevOpsContextRequired = errors.New("azure devops collection, project and repository are required (set SYSTEM_COLLECTIONURI, SYSTEM_TEAMPROJECT and BUILD_REPOSITORY_ID, as Azure Pipelines do)")
+
+// azureDevOpsStatusGenre groups the statuses of go-coverage on pull requests and commits
+const azureDevOpsStatusGenre = "go-coverage"
+
+// newAzureDevOpsCmd creates the azuredevops command
+func (c *Commands) newAzureDevOpsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "azuredevops",
+		Short: "Report coverage to Azure DevOps",
+		Long: `Report coverage to Azure DevOps: the coverage summary on the Code Coverage tab of the
+pipeline run, a status on the pull request (or the commit outside pull requests) with the result
+of the coverage gates, and a coverage thread on the pull request, updated in place on later runs.
+
+The project, repository, build and pull request are read from the variables set by Azure
+Pipelines (SYSTEM_COLLECTIONURI, SYSTEM_TEAMPROJECT, BUILD_REPOSITORY_ID, BUILD_BUILDID,
+SYSTEM_PULLREQUEST_PULLREQUESTID and SYSTEM_PULLREQUEST_TARGETBRANCH). Authenticate with the job
+access token by mapping SYSTEM_ACCESSTOKEN into the step, or with a personal access token in
+GO_COVERAGE_AZURE_DEVOPS_TOKEN.
+
+The gates are evaluated against the newest history entry of the pull request's target branch
+(the built branch outside pull requests), or against --base-coverage.`,
+		Example: `  # Report coverage from an Azure Pipelines step with SYSTEM_ACCESSTOKEN mapped
+  go-coverage azuredevops --input coverage.txt
+
+  # Same as the comment command in Azure Pipelines
+  go-coverage comment --provider azuredevops --input coverage.txt`,
+		RunE: c.runAzureDevOps,
+	}
+
+	addCoverageInputFlags(cmd)
+	cmd.Flags().IntP("pr", "p", 0, "Pull request ID (default: SYSTEM_PULLREQUEST_PULLREQUESTID)")
+	cmd.Flags().String("base-coverage", "", "Path to base branch coverage file for the gates")
+	cmd.Flags().String("report-url", "", "URL of the hosted coverage report (default: GO_COVERAGE_AZURE_DEVOPS_REPORT_URL)")
+	cmd.Flags().Bool("publish", true, "Publish the coverage summary of the pipeline run")
+	cmd.Flags().Bool("status", true, "Create a status on the pull request or commit")
+	cmd.Flags().Bool("comment", true, "Comment on the pull request")
+	addDryRunFlag(cmd, "Print the coverage summary, status and comment without sending them")
+
+	return cmd
+}
+
+// azureDevOpsOptions are the settings of an Azure DevOps report given on the command line
+type azureDevOpsOptions struct {
+	inputFile string
+	baseFile  string
+	reportURL string
+	pr        int
+	publish   bool
+	status    bool
+	comment   bool
+	dryRun    bool
+}
+
+// runAzureDevOps executes the azuredevops command
+func (c *Commands) runAzureDevOps(cmd *cobra.Command, _ []string) error {
+	opts := &azureDevOpsOptions{inputFile: getCoverageInputFlag(cmd)}
+	opts.pr, _ = cmd.Flags().GetInt("pr")
+	opts.baseFile, _ = cmd.Flags().GetString("base-coverage")
+	opts.reportURL, _ = cmd.Flags().GetString("report-url")
+	opts.publish, _ = cmd.Flags().GetBool("publish")
+	opts.status, _ = cmd.Flags().GetBool("status")
+	opts.comment, _ = cmd.Flags().GetBool("comment")
+	opts.dryRun, _ = cmd.Flags().GetBool(flagNameDryRun)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return reportAzureDevOps(cmd, cfg, opts)
+}
+
+// reportAzureDevOps evaluates the gates and reports them to Azure DevOps
+func reportAzureDevOps(cmd *cobra.Command, cfg *config.Config, opts *azureDevOpsOptions) error {
+	ado := &cfg.AzureDevOps
+	if opts.pr > 0 {
+		ado.PullRequest = opts.pr
+	}
+	if opts.reportURL != "" {
+		ado.ReportURL = opts.reportURL
+	}
+	if !cfg.IsAzureDevOpsContext() {
+		return ErrAzureDevOpsContextRequired
+	}
+	inputFile := opts.inputFile
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	targetBranch := ado.Branch
+	if ado.PullRequest > 0 && ado.BaseBranch != "" {
+		targetBranch = ado.BaseBranch
+	}
+	if targetBranch == "" {
+		targetBranch = getDefaultBranch()
+	}
+	gates, err := evaluateGates(ctx, cmd, cfg, inputFile, opts.baseFile, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	// The coverage summary belongs to a pipeline run and the status to a pull request or commit
+	publish := opts.publish && ado.BuildID > 0
+	createStatus := opts.status && (ado.PullRequest > 0 || ado.CommitSHA != "")
+	postComment := opts.comment && ado.PullRequest > 0
+
+	stats := []azuredevops.CoverageStatistics{{
+		Label:    "Statements",
+		Position: 1,
+		Total:    gates.Coverage.TotalLines,
+		Covered:  gates.Coverage.CoveredLines,
+	}}
+	status := newAzureDevOpsStatus(cfg, gates, targetBranch)
+	threadStatus := azuredevops.ThreadFixed
+	if !gates.Decision.Passed {
+		threadStatus = azuredevops.ThreadActive
+	}
+	var comment string
+	if postComment {
+		comment = renderGateMarkdown(azuredevops.CommentMarker, gates, targetBranch, ado.ReportURL)
+	}
+
+	if opts.dryRun {
+		if publish {
+			cmd.Printf("🧪 DRY RUN: Would publish coverage summary of build %d: %d/%d statements\n",
+				ado.BuildID, gates.Coverage.CoveredLines, gates.Coverage.TotalLines)
+		}
+		if createStatus {
+			cmd.Printf("🧪 DRY RUN: Would create status %s on %s: %s\n", status.State, describeAzureDevOpsTarget(ado), status.Description)
+		}
+		if postComment {
+			cmd.Printf("🧪 DRY RUN: Would comment on pull request #%d (thread %s)\n", ado.PullRequest, threadStatus)
+			cmd.Printf("=====================================\n%s\n=====================================\n", comment)
+		}
+		return nil
+	}
+	if !publish && !createStatus && !postComment {
+		return nil
+	}
+
+	if err = requireNetwork(cmd, cfg, "reporting to Azure DevOps"); err != nil {
+		return err
+	}
+	httpClient, err := cfg.NewHTTPClient(30 * time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	client, err := azuredevops.New(&azuredevops.Config{
+		CollectionURI: ado.CollectionURI,
+		Project:       ado.Project,
+		AccessToken:   ado.AccessToken,
+		Token:         ado.Token,
+		UserAgent:     "go-coverage/2.0",
+		RetryPolicy:   cfg.RetryPolicy(retry.OpAzureDevOpsAPI),
+		HTTPClient:    httpClient,
+	})
+	if err != nil {
+		return err
+	}
+
+	if publish {
+		if err = client.PublishCodeCoverage(ctx, ado.BuildID, stats); err != nil {
+			return err
+		}
+		cmd.Printf("✅ Coverage summary published for build %d\n", ado.BuildID)
+	}
+	if createStatus {
+		if ado.PullRequest > 0 {
+			err = client.CreatePullRequestStatus(ctx, ado.RepositoryID, ado.PullRequest, status)
+		} else {
+			err = client.CreateCommitStatus(ctx, ado.RepositoryID, ado.CommitSHA, status)
+		}
+		if err != nil {
+			return err
+		}
+		cmd.Printf("✅ Status %s created on %s\n", status.State, describeAzureDevOpsTarget(ado))
+	}
+	if postComment {
+		thread, created, threadErr := client.UpsertPullRequestThread(ctx, ado.RepositoryID, ado.PullRequest, comment, threadStatus)
+		if threadErr != nil {
+			return threadErr
+		}
+		action := "updated"
+		if created {
+			action = "created"
+		}
+		cmd.Printf("✅ Coverage thread %s on pull request #%d (thread %d, %s)\n", action, ado.PullRequest, thread.ID, thread.Status)
+	}
+	return nil
+}
+
+// newAzureDevOpsStatus returns the status reporting the gates, linked to the hosted report or
+// to the Code Coverage tab of the pipeline run
+func newAzureDevOpsStatus(cfg *config.Config, gates *gateResult, targetBranch string) *azuredevops.GitStatus {
+	status := &azuredevops.GitStatus{
+		State:       azuredevops.StateSucceeded,
+		Description: describeGates(gates, targetBranch),
+		TargetURL:   cfg.AzureDevOps.ReportURL,
+		Context:     azuredevops.StatusContext{Name: cfg.AzureDevOps.StatusName, Genre: azureDevOpsStatusGenre},
+	}
+	if !gates.Decision.Passed {
+		status.State = azuredevops.StateFailed
+	}
+	if status.TargetURL == "" && cfg.AzureDevOps.BuildID > 0 {
+		status.TargetURL = fmt.Sprintf("%s/%s/_build/results?buildId=%d&view=codecoverage-tab",
+			strings.TrimRight(cfg.AzureDevOps.CollectionURI, "/"), url.PathEscape(cfg.AzureDevOps.Project), cfg.AzureDevOps.BuildID)
+	}
+	return status
+}
+
+// describeAzureDevOpsTarget names what the status is created on for console output
+func describeAzureDevOpsTarget(ado *config.AzureDevOpsConfig) string {
+	if ado.PullRequest > 0 {
+		return fmt.Sprintf("pull request #%d", ado.PullRequest)
+	}
+	return "commit " + shortSHA(ado.CommitSHA)
+}
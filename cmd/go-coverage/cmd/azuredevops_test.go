@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/azuredevops"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// setupAzureDevOps isolates the Azure Pipelines environment and writes the coverage profile for the azuredevops command
+func setupAzureDevOps(t *testing.T) string {
+	t.Helper()
+	return setupProviderProfile(t,
+		"SYSTEM_COLLECTIONURI", "SYSTEM_TEAMPROJECT", "BUILD_REPOSITORY_ID", "BUILD_REPOSITORY_NAME",
+		"BUILD_REPOSITORY_PROVIDER", "BUILD_BUILDID", "BUILD_SOURCEVERSION", "BUILD_SOURCEBRANCH",
+		"SYSTEM_PULLREQUEST_PULLREQUESTID", "SYSTEM_PULLREQUEST_SOURCEBRANCH", "SYSTEM_PULLREQUEST_TARGETBRANCH",
+		"SYSTEM_ACCESSTOKEN", "GO_COVERAGE_AZURE_DEVOPS_TOKEN", "GO_COVERAGE_AZURE_DEVOPS_STATUS_NAME",
+		"GO_COVERAGE_AZURE_DEVOPS_REPORT_URL", "GO_COVERAGE_BRANCH_RULES",
+	)
+}
+
+func TestAzureDevOpsCommandRequiresContext(t *testing.T) {
+	profile := setupAzureDevOps(t)
+
+	_, err := executeCommand(t, "azuredevops", "--input", profile)
+	require.ErrorIs(t, err, ErrAzureDevOpsContextRequired)
+}
+
+func TestAzureDevOpsCommandReports(t *testing.T) {
+	profile := setupAzureDevOps(t)
+
+	var (
+		paths  []string
+		status azuredevops.GitStatus
+		thread map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /org/service/_apis/git/repositories/repo-id/pullRequests/12/statuses":
+			_ = json.Unmarshal(body, &status)
+		case "GET /org/service/_apis/git/repositories/repo-id/pullRequests/12/threads":
+			_, _ = w.Write([]byte(`{"value":[]}`))
+			return
+		case "POST /org/service/_apis/git/repositories/repo-id/pullRequests/12/threads":
+			_ = json.Unmarshal(body, &thread)
+			_, _ = w.Write([]byte(`{"id":5,"status":"fixed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SYSTEM_COLLECTIONURI", server.URL+"/org/")
+	t.Setenv("SYSTEM_TEAMPROJECT", "service")
+	t.Setenv("BUILD_REPOSITORY_ID", "repo-id")
+	t.Setenv("BUILD_BUILDID", "77")
+	t.Setenv("BUILD_SOURCEVERSION", "abc1234def")
+	t.Setenv("SYSTEM_PULLREQUEST_PULLREQUESTID", "12")
+	t.Setenv("SYSTEM_PULLREQUEST_TARGETBRANCH", "refs/heads/main")
+	t.Setenv("SYSTEM_ACCESSTOKEN", "job-token")
+
+	output, err := executeCommand(t, "azuredevops", "--input", profile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Coverage summary published for build 77")
+	assert.Contains(t, output, "Status succeeded created on pull request #12")
+	assert.Contains(t, output, "Coverage thread created on pull request #12 (thread 5, fixed)")
+	assert.Equal(t, []string{
+		"POST /org/service/_apis/test/codecoverage",
+		"POST /org/service/_apis/git/repositories/repo-id/pullRequests/12/statuses",
+		"GET /org/service/_apis/git/repositories/repo-id/pullRequests/12/threads",
+		"POST /org/service/_apis/git/repositories/repo-id/pullRequests/12/threads",
+	}, paths)
+	assert.Equal(t, "coverage", status.Context.Name)
+	assert.Equal(t, "50.00% coverage", status.Description)
+	assert.Equal(t, server.URL+"/org/service/_build/results?buildId=77&view=codecoverage-tab", status.TargetURL)
+	assert.Equal(t, azuredevops.ThreadFixed, thread["status"])
+
+	// Outside pull requests the status is created on the commit
+	paths = nil
+	t.Setenv("SYSTEM_PULLREQUEST_PULLREQUESTID", "")
+	output, err = executeCommand(t, "azuredevops", "--input", profile, "--publish=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Status succeeded created on commit abc1234")
+	assert.Equal(t, []string{"POST /org/service/_apis/git/repositories/repo-id/commits/abc1234def/statuses"}, paths)
+}
+
+func TestCommentCommandProvider(t *testing.T) {
+	profile := setupAzureDevOps(t)
+	t.Setenv("SYSTEM_COLLECTIONURI", "https://dev.azure.com/org/")
+	t.Setenv("SYSTEM_TEAMPROJECT", "service")
+	t.Setenv("BUILD_REPOSITORY_ID", "repo-id")
+	t.Setenv("BUILD_REPOSITORY_PROVIDER", "TfsGit")
+	t.Setenv("BUILD_BUILDID", "77")
+	t.Setenv("SYSTEM_PULLREQUEST_PULLREQUESTID", "12")
+
+	t.Run("azure devops is detected in azure pipelines", func(t *testing.T) {
+		output, err := executeCommand(t, "comment", "--input", profile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "DRY RUN: Would publish coverage summary of build 77: 5/10 statements")
+		assert.Contains(t, output, "DRY RUN: Would create status succeeded on pull request #12: 50.00% coverage")
+		assert.Contains(t, output, "DRY RUN: Would comment on pull request #12 (thread fixed)")
+		assert.Contains(t, output, azuredevops.CommentMarker)
+	})
+
+	t.Run("explicit provider", func(t *testing.T) {
+		output, err := executeCommand(t, "comment", "--provider", "azuredevops", "--input", profile, "--status=false", "--dry-run")
+		require.NoError(t, err)
+		assert.NotContains(t, output, "Would create status")
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := executeCommand(t, "comment", "--provider", "gitlab", "--input", profile)
+		require.ErrorIs(t, err, ErrUnknownProvider)
+	})
+}
+
+func TestNewAzureDevOpsStatus(t *testing.T) {
+	cfg := &config.Config{AzureDevOps: config.AzureDevOpsConfig{StatusName: "coverage", ReportURL: "https://coverage.example.com/"}}
+	gates := &gateResult{
+		Coverage: &parser.CoverageData{Percentage: 70},
+		Decision: &policy.Decision{Results: []policy.Result{{Rule: "threshold", Outcome: policy.OutcomeFail}}},
+	}
+
+	status := newAzureDevOpsStatus(cfg, gates, "main")
+	assert.Equal(t, azuredevops.StateFailed, status.State)
+	assert.Equal(t, "70.00% coverage, failed threshold", status.Description)
+	assert.Equal(t, "https://coverage.example.com/", status.TargetURL)
+	assert.Equal(t, azureDevOpsStatusGenre, status.Context.Genre)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/bitbucket"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// ErrBitbucketContextRequired indicates that the repository or commit is unknown
+var ErrBitbucketContextRequired = errors.New("bitbucket workspace, repository and commit are required (set BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG and BITBUCKET_COMMIT, as Bitbucket Pipelines do)")
+
+// newBitbucketCmd creates the bitbucket command
+func (c *Commands) newBitbucketCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bitbucket",
+		Short: "Report coverage to Bitbucket Cloud",
+		Long: `Report coverage to Bitbucket Cloud: a build status on the commit with the result of the
+coverage gates, and a coverage comment on the pull request, updated in place on later runs.
+
+The repository, commit and pull request are read from the variables set by Bitbucket Pipelines
+(BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG, BITBUCKET_COMMIT, BITBUCKET_PR_ID and
+BITBUCKET_PR_DESTINATION_BRANCH). Authenticate with an access token in
+GO_COVERAGE_BITBUCKET_TOKEN, or with GO_COVERAGE_BITBUCKET_USERNAME and
+GO_COVERAGE_BITBUCKET_APP_PASSWORD.
+
+The gates are evaluated against the newest history entry of the pull request's destination
+branch (the pipeline branch outside pull requests), or against --base-coverage.`,
+		Example: `  # Report coverage from a Bitbucket Pipelines step
+  go-coverage bitbucket --input coverage.txt
+
+  # Link the build status to the report published by an earlier step
+  go-coverage bitbucket --report-url https://coverage.example.com/my-repo/`,
+		RunE: c.runBitbucket,
+	}
+
+	addCoverageInputFlags(cmd)
+	cmd.Flags().IntP("pr", "p", 0, "Pull request ID (default: BITBUCKET_PR_ID)")
+	cmd.Flags().String("base-coverage", "", "Path to base branch coverage file for the gates")
+	cmd.Flags().String("report-url", "", "URL of the hosted coverage report (default: GO_COVERAGE_BITBUCKET_REPORT_URL)")
+	cmd.Flags().Bool("status", true, "Create a build status on the commit")
+	cmd.Flags().Bool("comment", true, "Comment on the pull request")
+	addDryRunFlag(cmd, "Print the build status and comment without sending them")
+
+	return cmd
+}
+
+// bitbucketOptions are the settings of a Bitbucket report given on the command line
+type bitbucketOptions struct {
+	inputFile string
+	baseFile  string
+	reportURL string
+	pr        int
+	status    bool
+	comment   bool
+	dryRun    bool
+}
+
+// runBitbucket executes the bitbucket command
+func (c *Commands) runBitbucket(cmd *cobra.Command, _ []string) error {
+	opts := &bitbucketOptions{inputFile: getCoverageInputFlag(cmd)}
+	opts.pr, _ = cmd.Flags().GetInt("pr")
+	opts.baseFile, _ = cmd.Flags().GetString("base-coverage")
+	opts.reportURL, _ = cmd.Flags().GetString("report-url")
+	opts.status, _ = cmd.Flags().GetBool("status")
+	opts.comment, _ = cmd.Flags().GetBool("comment")
+	opts.dryRun, _ = cmd.Flags().GetBool(flagNameDryRun)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return reportBitbucket(cmd, cfg, opts)
+}
+
+// reportBitbucket evaluates the gates and reports them to Bitbucket Cloud
+func reportBitbucket(cmd *cobra.Command, cfg *config.Config, opts *bitbucketOptions) error {
+	if opts.pr > 0 {
+		cfg.Bitbucket.PullRequest = opts.pr
+	}
+	if opts.reportURL != "" {
+		cfg.Bitbucket.ReportURL = opts.reportURL
+	}
+	if !cfg.IsBitbucketContext() {
+		return ErrBitbucketContextRequired
+	}
+	inputFile := opts.inputFile
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	targetBranch := cfg.Bitbucket.Branch
+	if cfg.Bitbucket.PullRequest > 0 && cfg.Bitbucket.BaseBranch != "" {
+		targetBranch = cfg.Bitbucket.BaseBranch
+	}
+	if targetBranch == "" {
+		targetBranch = getDefaultBranch()
+	}
+	gates, err := evaluateGates(ctx, cmd, cfg, inputFile, opts.baseFile, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	status := newBitbucketBuildStatus(cfg, gates, targetBranch)
+	var comment string
+	postComment := opts.comment && cfg.Bitbucket.PullRequest > 0
+	if postComment {
+		comment = renderGateMarkdown(bitbucket.CommentMarker, gates, targetBranch, cfg.Bitbucket.ReportURL)
+	}
+
+	if opts.dryRun {
+		if opts.status {
+			cmd.Printf("🧪 DRY RUN: Would create build status %s on commit %s: %s (%s)\n",
+				status.State, shortSHA(cfg.Bitbucket.CommitSHA), status.Description, status.URL)
+		}
+		if postComment {
+			cmd.Printf("🧪 DRY RUN: Would comment on pull request #%d\n", cfg.Bitbucket.PullRequest)
+			cmd.Printf("=====================================\n%s\n=====================================\n", comment)
+		}
+		return nil
+	}
+	if !opts.status && !postComment {
+		return nil
+	}
+
+	if err = requireNetwork(cmd, cfg, "reporting to Bitbucket"); err != nil {
+		return err
+	}
+	httpClient, err := cfg.NewHTTPClient(30 * time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	client, err := bitbucket.New(&bitbucket.Config{
+		BaseURL:     cfg.Bitbucket.APIURL,
+		Token:       cfg.Bitbucket.Token,
+		Username:    cfg.Bitbucket.Username,
+		AppPassword: cfg.Bitbucket.AppPassword,
+		UserAgent:   "go-coverage/2.0",
+		RetryPolicy: cfg.RetryPolicy(retry.OpBitbucketAPI),
+		HTTPClient:  httpClient,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.status {
+		if err = client.CreateBuildStatus(ctx, cfg.Bitbucket.Workspace, cfg.Bitbucket.Repository, cfg.Bitbucket.CommitSHA, status); err != nil {
+			return err
+		}
+		cmd.Printf("✅ Build status %s created on commit %s\n", status.State, shortSHA(cfg.Bitbucket.CommitSHA))
+	}
+	if postComment {
+		posted, created, commentErr := client.UpsertPullRequestComment(ctx, cfg.Bitbucket.Workspace, cfg.Bitbucket.Repository, cfg.Bitbucket.PullRequest, comment)
+		if commentErr != nil {
+			return commentErr
+		}
+		action := "updated"
+		if created {
+			action = "created"
+		}
+		cmd.Printf("✅ Coverage comment %s on pull request #%d (comment %d)\n", action, cfg.Bitbucket.PullRequest, posted.ID)
+	}
+	return nil
+}
+
+// newBitbucketBuildStatus returns the build status reporting the gates. Bitbucket requires a
+// link, so without a hosted report the status links to the pipeline or the commit.
+func newBitbucketBuildStatus(cfg *config.Config, gates *gateResult, targetBranch string) *bitbucket.BuildStatus {
+	status := &bitbucket.BuildStatus{
+		Key:         cfg.Bitbucket.StatusKey,
+		State:       bitbucket.StateSuccessful,
+		Name:        "Coverage",
+		URL:         cfg.Bitbucket.ReportURL,
+		Description: describeGates(gates, targetBranch),
+	}
+	if !gates.Decision.Passed {
+		status.State = bitbucket.StateFailed
+	}
+
+	if status.URL == "" {
+		repositoryURL := fmt.Sprintf("https://bitbucket.org/%s/%s", cfg.Bitbucket.Workspace, cfg.Bitbucket.Repository)
+		if cfg.Bitbucket.BuildNumber > 0 {
+			status.URL = fmt.Sprintf("%s/pipelines/results/%d", repositoryURL, cfg.Bitbucket.BuildNumber)
+		} else {
+			status.URL = repositoryURL + "/commits/" + cfg.Bitbucket.CommitSHA
+		}
+	}
+	return status
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/bitbucket"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// setupBitbucket isolates the Bitbucket environment and writes the coverage profile for the bitbucket command
+func setupBitbucket(t *testing.T) string {
+	t.Helper()
+	return setupProviderProfile(t,
+		"BITBUCKET_WORKSPACE", "BITBUCKET_REPO_SLUG", "BITBUCKET_COMMIT", "BITBUCKET_PR_ID",
+		"BITBUCKET_BRANCH", "BITBUCKET_PR_DESTINATION_BRANCH", "BITBUCKET_BUILD_NUMBER",
+		"GO_COVERAGE_BITBUCKET_API_URL", "GO_COVERAGE_BITBUCKET_TOKEN", "GO_COVERAGE_BITBUCKET_USERNAME",
+		"GO_COVERAGE_BITBUCKET_APP_PASSWORD", "GO_COVERAGE_BITBUCKET_STATUS_KEY",
+		"GO_COVERAGE_BITBUCKET_REPORT_URL", "GO_COVERAGE_BRANCH_RULES",
+	)
+}
+
+func TestBitbucketCommandRequiresContext(t *testing.T) {
+	profile := setupBitbucket(t)
+
+	_, err := executeCommand(t, "bitbucket", "--input", profile)
+	require.ErrorIs(t, err, ErrBitbucketContextRequired)
+}
+
+func TestBitbucketCommandReports(t *testing.T) {
+	profile := setupBitbucket(t)
+
+	var (
+		status  bitbucket.BuildStatus
+		comment string
+		paths   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/repositories/team/service/commit/abc1234def/statuses/build":
+			_ = json.Unmarshal(body, &status)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"values":[]}`))
+		default:
+			var input struct {
+				Content struct {
+					Raw string `json:"raw"`
+				} `json:"content"`
+			}
+			_ = json.Unmarshal(body, &input)
+			comment = input.Content.Raw
+			_, _ = w.Write([]byte(`{"id":9}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GO_COVERAGE_BITBUCKET_API_URL", server.URL)
+	t.Setenv("GO_COVERAGE_BITBUCKET_TOKEN", "token")
+	t.Setenv("BITBUCKET_WORKSPACE", "team")
+	t.Setenv("BITBUCKET_REPO_SLUG", "service")
+	t.Setenv("BITBUCKET_COMMIT", "abc1234def")
+	t.Setenv("BITBUCKET_PR_ID", "12")
+	t.Setenv("BITBUCKET_PR_DESTINATION_BRANCH", "main")
+	t.Setenv("BITBUCKET_BUILD_NUMBER", "33")
+
+	output, err := executeCommand(t, "bitbucket", "--input", profile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Build status SUCCESSFUL created on commit abc1234")
+	assert.Contains(t, output, "Coverage comment created on pull request #12 (comment 9)")
+	assert.Equal(t, []string{
+		"POST /repositories/team/service/commit/abc1234def/statuses/build",
+		"GET /repositories/team/service/pullrequests/12/comments",
+		"POST /repositories/team/service/pullrequests/12/comments",
+	}, paths)
+	assert.Equal(t, "go-coverage", status.Key)
+	assert.Equal(t, "https://bitbucket.org/team/service/pipelines/results/33", status.URL)
+	assert.Equal(t, "50.00% coverage", status.Description)
+	assert.Contains(t, comment, bitbucket.CommentMarker)
+	assert.Contains(t, comment, "## 📊 Coverage: 50.00%")
+
+	// Without a pull request only the build status is reported
+	paths = nil
+	t.Setenv("BITBUCKET_PR_ID", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "90")
+	_, err = executeCommand(t, "bitbucket", "--input", profile)
+	require.NoError(t, err)
+	assert.Len(t, paths, 1)
+	assert.Equal(t, bitbucket.StateFailed, status.State)
+}
+
+func TestBitbucketCommandDryRun(t *testing.T) {
+	profile := setupBitbucket(t)
+	t.Setenv("BITBUCKET_WORKSPACE", "team")
+	t.Setenv("BITBUCKET_REPO_SLUG", "service")
+	t.Setenv("BITBUCKET_COMMIT", "abc1234def")
+
+	output, err := executeCommand(t, "bitbucket", "--input", profile, "--pr", "5", "--report-url", "https://coverage.example.com/", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, output, "DRY RUN: Would create build status SUCCESSFUL on commit abc1234: 50.00% coverage (https://coverage.example.com/)")
+	assert.Contains(t, output, "DRY RUN: Would comment on pull request #5")
+	assert.Contains(t, output, "[Coverage report](https://coverage.example.com/)")
+}
+
+func TestNewBitbucketBuildStatus(t *testing.T) {
+	cfg := &config.Config{Bitbucket: config.BitbucketConfig{
+		Workspace: "team", Repository: "service", CommitSHA: "abc", StatusKey: "coverage",
+	}}
+	gates := &gateResult{
+		Coverage: &parser.CoverageData{Percentage: 82.5},
+		Previous: []float64{81.3},
+		Decision: &policy.Decision{Results: []policy.Result{
+			{Rule: "threshold", Outcome: policy.OutcomeFail},
+			{Rule: "max-drop", Outcome: policy.OutcomePass},
+		}},
+	}
+
+	status := newBitbucketBuildStatus(cfg, gates, "main")
+	assert.Equal(t, bitbucket.StateFailed, status.State)
+	assert.Equal(t, "82.50% coverage (+1.20% vs main), failed threshold", status.Description)
+	assert.Equal(t, "https://bitbucket.org/team/service/commits/abc", status.URL)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// emergencyBypass returns why the gates of the run are downgraded to warnings, or "" when no
+// bypass applies: the commit message or pull request title carries a bypass token, or every
+// changed file matches a bypass path. changed lists the files of the change when the caller
+// knows them; nil reads them from the commit under test.
+func emergencyBypass(ctx context.Context, cfg *config.Config, changed []string) string {
+	if len(cfg.Policy.BypassTokens) > 0 {
+		message := runMessage(ctx, cfg)
+		for _, token := range cfg.Policy.BypassTokens {
+			token = strings.TrimSpace(token)
+			if token != "" && strings.Contains(strings.ToLower(message), strings.ToLower(token)) {
+				return fmt.Sprintf("bypass token %s in %q", token, firstLine(message))
+			}
+		}
+	}
+
+	if len(cfg.Policy.BypassPaths) > 0 {
+		if changed == nil {
+			changed = commitChangedFiles(ctx)
+		}
+		if len(changed) > 0 && allMatch(cfg.Policy.BypassPaths, changed) {
+			return fmt.Sprintf("all %d changed files match bypass paths %s", len(changed), strings.Join(cfg.Policy.BypassPaths, ", "))
+		}
+	}
+	return ""
+}
+
+// applyBypass downgrades the failed rules of decision when an emergency bypass applies
+func applyBypass(decision *policy.Decision, reason string) {
+	if reason != "" {
+		decision.Downgrade(reason)
+	}
+}
+
+// printBypass announces an emergency bypass, so it stands out in the job log
+func printBypass(cmd *cobra.Command, reason string) {
+	if reason != "" {
+		cmd.Printf("🚨 Emergency bypass: %s - failed coverage gates are reported as warnings\n", reason)
+	}
+}
+
+// runMessage returns the commit message or pull request title of the run, as reported by the CI
+// provider, falling back to the message of the commit under test in the local clone
+func runMessage(ctx context.Context, cfg *config.Config) string {
+	if message := runContext(cfg).Message; message != "" {
+		return message
+	}
+	commit := cfg.GitHub.CommitSHA
+	if commit == "" || strings.HasPrefix(commit, "-") {
+		commit = "HEAD"
+	}
+	output, err := exec.CommandContext(ctx, "git", "log", "-1", "--format=%B", commit).Output() //nolint:gosec // commit cannot be an option
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commitChangedFiles lists the files the commit under test changed against its first parent,
+// relative to the repository root. For the merge commit of a pull request that is the whole
+// change; shallow clones without the parent list nothing.
+func commitChangedFiles(ctx context.Context) []string {
+	output, err := exec.CommandContext(ctx, "git", "diff", "--name-only", "HEAD^1", "HEAD").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(output))
+}
+
+// prDiffFilenames lists the files of the pull request diff, nil without a diff
+func prDiffFilenames(prDiff *github.PRDiff) []string {
+	if prDiff == nil {
+		return nil
+	}
+	files := make([]string, 0, len(prDiff.Files))
+	for i := range prDiff.Files {
+		files = append(files, prDiff.Files[i].Filename)
+	}
+	return files
+}
+
+// allMatch reports whether every file matches one of the patterns
+func allMatch(patterns, files []string) bool {
+	for _, file := range files {
+		matched := false
+		for _, pattern := range patterns {
+			if branchmatch.Match(strings.TrimSpace(pattern), file) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// firstLine returns the first line of a commit message
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(line)
+}
+
+// newBypassEvents lists the bypassed runs of the history, newest first, for the dashboard audit
+func newBypassEvents(entries []history.Entry) []dashboard.BypassEvent {
+	var events []dashboard.BypassEvent
+	for _, entry := range entries {
+		if entry.Bypass != "" {
+			events = append(events, dashboard.BypassEvent{
+				Timestamp: entry.Timestamp,
+				Branch:    entry.Branch,
+				CommitSHA: entry.CommitSHA,
+				Reason:    entry.Bypass,
+			})
+		}
+	}
+	return events
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestEmergencyBypass(t *testing.T) {
+	ctx := context.Background()
+	newConfig := func(message string) *config.Config {
+		return &config.Config{
+			CI: &ci.Context{Message: message},
+			Policy: config.PolicyConfig{
+				BypassTokens: []string{"[hotfix]"},
+				BypassPaths:  []string{"deploy/**", "*.md"},
+			},
+		}
+	}
+
+	t.Run("token in the message", func(t *testing.T) {
+		reason := emergencyBypass(ctx, newConfig("Roll back the release [HOTFIX]\n\nDetails"), []string{"main.go"})
+		assert.Equal(t, `bypass token [hotfix] in "Roll back the release [HOTFIX]"`, reason)
+	})
+
+	t.Run("changes confined to bypass paths", func(t *testing.T) {
+		reason := emergencyBypass(ctx, newConfig("Bump replicas"), []string{"deploy/prod/values.yaml", "README.md"})
+		assert.Equal(t, "all 2 changed files match bypass paths deploy/**, *.md", reason)
+	})
+
+	t.Run("no bypass", func(t *testing.T) {
+		assert.Empty(t, emergencyBypass(ctx, newConfig("Add a feature"), []string{"deploy/values.yaml", "main.go"}))
+		assert.Empty(t, emergencyBypass(ctx, newConfig("Add a feature"), []string{}))
+		assert.Empty(t, emergencyBypass(ctx, &config.Config{CI: &ci.Context{Message: "[hotfix]"}}, nil))
+	})
+}
+
+func TestPRDiffFilenames(t *testing.T) {
+	assert.Nil(t, prDiffFilenames(nil))
+	diff := &github.PRDiff{Files: []github.PRFile{{Filename: "deploy/values.yaml"}, {Filename: "README.md"}}}
+	assert.Equal(t, []string{"deploy/values.yaml", "README.md"}, prDiffFilenames(diff))
+}
+
+func TestNewBypassEvents(t *testing.T) {
+	now := time.Now()
+	events := newBypassEvents([]history.Entry{
+		{Timestamp: now, Branch: "main", CommitSHA: "abc", Bypass: "bypass token [hotfix] in \"Fix\""},
+		{Timestamp: now.Add(-time.Hour), Branch: "main", CommitSHA: "def"},
+	})
+	require.Len(t, events, 1)
+	assert.Equal(t, "abc", events[0].CommitSHA)
+	assert.Equal(t, "bypass token [hotfix] in \"Fix\"", events[0].Reason)
+}
+
+func TestCompleteCommandBypass(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_LOCAL", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "90")
+	t.Setenv("GO_COVERAGE_POLICY_BYPASS_TOKENS", "[hotfix]")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	historyDir := filepath.Join(tempDir, "history")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", historyDir)
+	require.NoError(t, os.WriteFile(coverageFile, []byte(compareHeadProfile), 0o600))
+
+	t.Setenv("GO_COVERAGE_CI_MESSAGE", "Add a feature")
+	_, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", filepath.Join(tempDir, "failed"))
+	require.ErrorIs(t, err, ErrCoverageBelowThreshold)
+
+	t.Setenv("GO_COVERAGE_CI_MESSAGE", "Roll back the release [hotfix]")
+	output, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", filepath.Join(tempDir, "bypassed"))
+	require.NoError(t, err)
+	assert.Contains(t, output, `🚨 Emergency bypass: bypass token [hotfix] in "Roll back the release [hotfix]"`)
+	assert.Contains(t, output, "🚦 Coverage policy: BYPASSED")
+
+	html, err := os.ReadFile(filepath.Join(tempDir, "bypassed", "index.html")) //nolint:gosec // test output
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "Coverage Gates Bypassed")
+
+	tracker := history.NewWithConfig(&history.Config{StoragePath: historyDir})
+	trend, err := tracker.GetTrend(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, trend.Entries)
+	assert.Equal(t, `bypass token [hotfix] in "Roll back the release [hotfix]"`, trend.Entries[0].Bypass)
+}
+
+func TestBypassedGates(t *testing.T) {
+	cfg := &config.Config{Coverage: config.CoverageConfig{Threshold: 80}, Policy: config.PolicyConfig{MaxDrop: -1}}
+	coverage := &parser.CoverageData{Percentage: 70}
+
+	decision := evaluatePolicy(cfg, coverage, nil, nil, nil)
+	applyBypass(decision, "")
+	assert.False(t, decision.Passed, "no reason, no bypass")
+
+	applyBypass(decision, "bypass token [hotfix] in \"Fix\"")
+	require.NoError(t, policyError(cfg, coverage.Percentage, decision))
+	gates := &gateResult{Coverage: coverage, Decision: decision}
+	assert.Equal(t, "70.00% coverage, gates bypassed", describeGates(gates, "main"))
+	assert.Contains(t, renderGateMarkdown("<!-- marker -->", gates, "main", ""), "### 🚨 Coverage policy bypassed\n\nbypass token [hotfix] in \"Fix\"")
+	assert.True(t, newPolicyTemplateData(decision).Passed)
+	assert.Equal(t, decision.Bypass, newPolicyTemplateData(decision).Bypass)
+}
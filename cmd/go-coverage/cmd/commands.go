@@ -3,17 +3,41 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/redact"
 )
 
 // Commands holds all CLI commands and their configuration
 type Commands struct {
-	Root       *cobra.Command
-	Complete   *cobra.Command
-	History    *cobra.Command
-	Comment    *cobra.Command
-	Parse      *cobra.Command
-	SetupPages *cobra.Command
-	Upgrade    *cobra.Command
+	Root        *cobra.Command
+	Affected    *cobra.Command
+	Analyze     *cobra.Command
+	App         *cobra.Command
+	Attribute   *cobra.Command
+	AzureDevOps *cobra.Command
+	Bitbucket   *cobra.Command
+	Complete    *cobra.Command
+	Config      *cobra.Command
+	History     *cobra.Command
+	Comment     *cobra.Command
+	Compare     *cobra.Command
+	DeadCode    *cobra.Command
+	Deployment  *cobra.Command
+	DiffReport  *cobra.Command
+	Digest      *cobra.Command
+	Finalize    *cobra.Command
+	Gerrit      *cobra.Command
+	Health      *cobra.Command
+	Hooks       *cobra.Command
+	Parse       *cobra.Command
+	Publish     *cobra.Command
+	Readme      *cobra.Command
+	Serve       *cobra.Command
+	SetupPages  *cobra.Command
+	Templates   *cobra.Command
+	Upgrade     *cobra.Command
+	Waivers     *cobra.Command
 
 	// Version information
 	Version VersionInfo
@@ -36,21 +60,63 @@ func NewCommands(version VersionInfo) *Commands {
 	cmds.Root = cmds.newRootCmd()
 
 	// Initialize subcommands
+	cmds.Affected = cmds.newAffectedCmd()
+	cmds.Analyze = cmds.newAnalyzeCmd()
+	cmds.App = cmds.newAppCmd()
+	cmds.Attribute = cmds.newAttributeCmd()
+	cmds.AzureDevOps = cmds.newAzureDevOpsCmd()
+	cmds.Bitbucket = cmds.newBitbucketCmd()
 	cmds.Complete = cmds.newCompleteCmd()
+	cmds.Config = cmds.newConfigCmd()
 	cmds.History = cmds.newHistoryCmd()
 	cmds.Comment = cmds.newCommentCmd()
+	cmds.Compare = cmds.newCompareCmd()
+	cmds.DeadCode = cmds.newDeadCodeCmd()
+	cmds.Deployment = cmds.newDeploymentCmd()
+	cmds.DiffReport = cmds.newDiffReportCmd()
+	cmds.Digest = cmds.newDigestCmd()
+	cmds.Finalize = cmds.newFinalizeCmd()
+	cmds.Gerrit = cmds.newGerritCmd()
+	cmds.Health = cmds.newHealthCmd()
+	cmds.Hooks = cmds.newHooksCmd()
 	cmds.Parse = cmds.newParseCmd()
+	cmds.Publish = cmds.newPublishCheckCmd()
+	cmds.Readme = cmds.newReadmeCmd()
+	cmds.Serve = cmds.newServeCmd()
 	cmds.SetupPages = cmds.newSetupPagesCmd()
+	cmds.Templates = cmds.newTemplatesCmd()
 	cmds.Upgrade = cmds.newUpgradeCmd()
+	cmds.Waivers = cmds.newWaiversCmd()
 
 	// Add subcommands to root
 	cmds.Root.AddCommand(
+		cmds.Affected,
+		cmds.Analyze,
+		cmds.App,
+		cmds.Attribute,
+		cmds.AzureDevOps,
+		cmds.Bitbucket,
 		cmds.Complete,
+		cmds.Config,
 		cmds.History,
 		cmds.Comment,
+		cmds.Compare,
+		cmds.DeadCode,
+		cmds.Deployment,
+		cmds.DiffReport,
+		cmds.Digest,
+		cmds.Finalize,
+		cmds.Gerrit,
+		cmds.Health,
+		cmds.Hooks,
 		cmds.Parse,
+		cmds.Publish,
+		cmds.Readme,
+		cmds.Serve,
 		cmds.SetupPages,
+		cmds.Templates,
 		cmds.Upgrade,
+		cmds.Waivers,
 	)
 
 	// Set version on root command
@@ -62,9 +128,14 @@ func NewCommands(version VersionInfo) *Commands {
 	return cmds
 }
 
-// Execute runs the root command
+// Execute runs the root command and summarizes the GitHub API requests it made. Failures with an
+// error code are followed by their remediation.
 func (c *Commands) Execute() error {
-	return c.Root.Execute()
+	before := github.RunUsage()
+	err := c.Root.Execute()
+	reportAPIUsage(c.Root, github.RunUsage().Since(before))
+	reportDiagnosis(c.Root, err)
+	return err
 }
 
 // newRootCmd creates the root command
@@ -78,12 +149,29 @@ the simplicity and performance that Go developers expect.
 
 Built as a bolt-on solution completely encapsulated within the .github folder,
 this tool replaces Codecov with zero external service dependencies.`,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			redactOutput(cmd, redact.FromEnv())
+			applyRepoRoot(cmd)
+			return applyFixtureMode(cmd)
+		},
 	}
 
 	// Global flags
 	cmd.PersistentFlags().Bool("debug", false, "Enable debug mode")
 	cmd.PersistentFlags().StringP("log-level", "l", "info", "Log level (debug, info, warn, error)")
 	cmd.PersistentFlags().String("log-format", "text", "Log format (text, json, pretty)")
+	cmd.PersistentFlags().Bool(flagNameOffline, false, "Disable all network access (GitHub API, uploads, remote assets)")
+	cmd.PersistentFlags().String(flagNameRecordFixtures, "", "Record API responses, input profiles and environment into this fixture directory")
+	cmd.PersistentFlags().String(flagNameReplayFixtures, "", "Replay a run from this fixture directory without network access")
+	cmd.PersistentFlags().String(flagNameRepoRoot, "", "Repository root (default: detected from git or GITHUB_WORKSPACE)")
 
 	return cmd
 }
+
+// redactOutput routes the command's output through the redactor so tokens echoed in
+// API URLs or error bodies never reach the console. cmd.Printf writes to OutOrStderr,
+// which is kept as the destination to preserve where output goes.
+func redactOutput(cmd *cobra.Command, redactor *redact.Redactor) {
+	cmd.SetOut(redactor.Writer(cmd.OutOrStderr()))
+	cmd.SetErr(redactor.Writer(cmd.ErrOrStderr()))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/mrz1836/go-coverage/internal/badge"
 	"github.com/mrz1836/go-coverage/internal/config"
 	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
 	"github.com/mrz1836/go-coverage/internal/history"
 	"github.com/mrz1836/go-coverage/internal/parser"
 	"github.com/mrz1836/go-coverage/internal/templates"
@@ -27,6 +29,8 @@ var (
 	ErrGitHubRepoRequired = errors.New("GitHub repository name is required")
 	// ErrPRNumberRequired indicates PR number was not provided
 	ErrPRNumberRequired = errors.New("pull request number is required")
+	// ErrUnknownProvider indicates an unsupported --provider value
+	ErrUnknownProvider = errors.New("unknown provider, expected github, bitbucket or azuredevops")
 )
 
 // newCommentCmd creates the comment command
@@ -42,11 +46,15 @@ Features:
 - Dynamic template rendering with multiple template options
 - PR-specific badge generation with unique naming
 - GitHub status check integration for blocking PR merges
-- Smart update logic and lifecycle management`,
+- Smart update logic and lifecycle management
+
+The provider is detected from the CI environment: GitHub in GitHub Actions, Azure DevOps in
+Azure Pipelines building an Azure Repos repository, Bitbucket in Bitbucket Pipelines. Select it
+with --provider; the bitbucket and azuredevops providers report as the commands of the same name.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Get flags
 			prNumber, _ := cmd.Flags().GetInt("pr")
-			inputFile, _ := cmd.Flags().GetString("coverage")
+			inputFile := getCoverageInputFlag(cmd)
 			baseCoverageFile, _ := cmd.Flags().GetString("base-coverage")
 			badgeURL, _ := cmd.Flags().GetString("badge-url")
 			reportURL, _ := cmd.Flags().GetString("report-url")
@@ -55,16 +63,51 @@ Features:
 			generateBadges, _ := cmd.Flags().GetBool("generate-badges")
 			enableAnalysis, _ := cmd.Flags().GetBool("enable-analysis")
 			antiSpam, _ := cmd.Flags().GetBool("anti-spam")
+			maxCommentLength, _ := cmd.Flags().GetInt("max-comment-length")
+			templateDataFile, _ := cmd.Flags().GetString("template-data")
+			overflowDir, _ := cmd.Flags().GetString("overflow-dir")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			provider, _ := cmd.Flags().GetString("provider")
 
 			// Load configuration
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load configuration: %w", err)
 			}
+			applyFailWithoutTestsFlag(cmd, cfg)
+
+			if provider == "" {
+				provider = cfg.DetectProvider()
+			}
+			switch provider {
+			case config.ProviderGitHub:
+			case config.ProviderBitbucket:
+				return reportBitbucket(cmd, cfg, &bitbucketOptions{
+					inputFile: inputFile, baseFile: baseCoverageFile, reportURL: reportURL,
+					pr: prNumber, status: createStatus, comment: !cfg.HasLabel(config.LabelSkipComment), dryRun: dryRun,
+				})
+			case config.ProviderAzureDevOps:
+				return reportAzureDevOps(cmd, cfg, &azureDevOpsOptions{
+					inputFile: inputFile, baseFile: baseCoverageFile, reportURL: reportURL,
+					pr: prNumber, publish: true, status: createStatus, comment: !cfg.HasLabel(config.LabelSkipComment), dryRun: dryRun,
+				})
+			default:
+				return fmt.Errorf("%w: %q", ErrUnknownProvider, provider)
+			}
+
+			// PR comments live on GitHub, so there is nothing useful to do offline
+			if err = requireNetwork(cmd, cfg, "the comment command"); err != nil {
+				return err
+			}
+
+			// Fork pull requests get a read-only token, so results are handed off instead of posted
+			forkSafe, forkErr := forkSafeMode(cfg)
+			if forkErr != nil {
+				cmd.Printf("Warning: failed to detect fork pull request: %v\n", forkErr)
+			}
 
 			// Validate GitHub configuration
-			if cfg.GitHub.Token == "" {
+			if cfg.GitHub.Token == "" && !forkSafe {
 				return ErrGitHubTokenRequired
 			}
 			if cfg.GitHub.Owner == "" {
@@ -78,7 +121,9 @@ Features:
 			if prNumber == 0 {
 				prNumber = cfg.GitHub.PullRequest
 			}
-			if prNumber == 0 {
+			// Merge queue runs have no single pull request to comment on, only a merge commit to check
+			mergeGroup := prNumber == 0 && runContext(cfg).IsMergeGroup()
+			if prNumber == 0 && !mergeGroup {
 				return ErrPRNumberRequired
 			}
 
@@ -86,6 +131,12 @@ Features:
 			if inputFile == "" {
 				inputFile = cfg.Coverage.InputFile
 			}
+			if inputFile, err = fixtureInput(cfg, inputFile); err != nil {
+				return err
+			}
+			if baseCoverageFile, err = fixtureInput(cfg, baseCoverageFile); err != nil {
+				return err
+			}
 			if badgeURL == "" {
 				badgeURL = cfg.GetBadgeURL()
 			}
@@ -94,11 +145,55 @@ Features:
 			}
 			// URLs will be passed to template data below
 
-			// Parse current coverage data
-			p := parser.New()
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
+			// Create GitHub client
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+
+			// Find out what the token may do before anything is posted, instead of failing with a 403
+			if !dryRun && !forkSafe {
+				features := &preflightFeatures{Statuses: createStatus, Comments: !mergeGroup}
+				preflightGitHub(ctx, cmd, cfg, client, prNumber, features)
+				createStatus = features.Statuses
+				if !mergeGroup && !features.Comments {
+					cmd.Printf("💡 Handing the results off instead, as for fork pull requests\n\n")
+					forkSafe = true
+				}
+			}
+
+			if mergeGroup {
+				coverage, parseErr := parser.New().WithModuleRewrites(cfg.ModuleRewrites()).ParseFile(ctx, inputFile)
+				if parseErr != nil {
+					return fmt.Errorf("failed to parse coverage file: %w", parseErr)
+				}
+				return gateMergeGroup(ctx, cmd, cfg, client, coverage, createStatus, dryRun)
+			}
+
+			// Analyze PR files to understand the impact
+			var prFileAnalysis *github.PRFileAnalysis
+			var prDiff *github.PRDiff
+			if enableAnalysis {
+				var diffErr error
+				prDiff, diffErr = client.GetPRDiff(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber)
+				if diffErr != nil {
+					cmd.Printf("Warning: failed to get PR diff: %v\n", diffErr)
+				} else {
+					prFileAnalysis = github.AnalyzePRFiles(prDiff)
+					cmd.Printf("📋 PR Analysis: %s\n", prFileAnalysis.Summary.GetSummaryText())
+				}
+			}
+
+			// Coverage cannot change without code changes, so skip parsing and policy checks
+			if prFileAnalysis != nil && !prFileAnalysis.HasCodeChanges() && cfg.Policy.NoCodeChanges != config.NoCodeChangesFull {
+				return postNoCodeChangesComment(ctx, cmd, cfg, client, prNumber, prFileAnalysis, badgeURL, reportURL, createStatus, dryRun, forkSafe)
+			}
+
+			// Parse current coverage data
+			p := parser.New().WithModuleRewrites(cfg.ModuleRewrites())
 			coverage, err := p.ParseFile(ctx, inputFile)
 			if err != nil {
 				return fmt.Errorf("failed to parse coverage file: %w", err)
@@ -116,9 +211,12 @@ Features:
 
 			// Get trend information if history is enabled
 			trend := "stable"
+			var previous []float64
+			var baseLatest *history.Entry
 			if cfg.History.Enabled {
 				historyConfig := &history.Config{
-					StoragePath:    cfg.History.StoragePath,
+					StoragePath:    historyStoragePath(cfg),
+					Repository:     cfg.RepositorySlug(),
 					RetentionDays:  cfg.History.RetentionDays,
 					MaxEntries:     cfg.History.MaxEntries,
 					AutoCleanup:    cfg.History.AutoCleanup,
@@ -126,40 +224,22 @@ Features:
 				}
 				tracker := history.NewWithConfig(historyConfig)
 
-				// Get latest entry to compare
-				branch := cfg.GitHub.CommitSHA
-				if branch == "" {
-					branch = defaultBranch
-				}
-
-				if latest, latestErr := tracker.GetLatestEntry(ctx, branch); latestErr == nil {
+				// Compare with the latest entry of the branch the pull request targets
+				if latest, latestErr := tracker.GetLatestEntry(ctx, runBaseBranch(cfg)); latestErr == nil {
+					baseLatest = latest
 					if coverage.Percentage > latest.Coverage.Percentage {
 						trend = "up"
 					} else if coverage.Percentage < latest.Coverage.Percentage {
 						trend = "down"
 					}
 				}
-			}
-
-			// Create GitHub client
-			githubConfig := &github.Config{
-				Token:      cfg.GitHub.Token,
-				BaseURL:    "https://api.github.com",
-				Timeout:    cfg.GitHub.Timeout,
-				RetryCount: 3,
-				UserAgent:  "go-coverage/2.0",
-			}
-			client := github.NewWithConfig(githubConfig)
 
-			// Analyze PR files to understand the impact
-			var prFileAnalysis *github.PRFileAnalysis
-			if enableAnalysis {
-				prDiff, diffErr := client.GetPRDiff(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber)
-				if diffErr != nil {
-					cmd.Printf("Warning: failed to get PR diff: %v\n", diffErr)
-				} else {
-					prFileAnalysis = github.AnalyzePRFiles(prDiff)
-					cmd.Printf("📋 PR Analysis: %s\n", prFileAnalysis.Summary.GetSummaryText())
+				// Trend-based gating and the confidence band look at earlier runs of the PR branch
+				if cfg.Policy.DeclineRuns > 0 || cfg.Policy.ConfidenceRuns > 0 {
+					var prevErr error
+					if previous, prevErr = previousCoverage(ctx, tracker, runHeadBranch(cfg), cfg.History.RetentionDays, policyHistoryDepth(cfg)); prevErr != nil {
+						cmd.Printf("Warning: failed to load previous runs for policy evaluation: %v\n", prevErr)
+					}
 				}
 			}
 
@@ -167,7 +247,7 @@ Features:
 			prCommentConfig := &github.PRCommentConfig{
 				MinUpdateIntervalMinutes: 5,
 				MaxCommentsPerPR:         1,
-				CommentSignature:         "go-coverage-v1",
+				CommentSignature:         cfg.GitHub.CommentSignature,
 				IncludeTrend:             true,
 				IncludeCoverageDetails:   true,
 				IncludeFileAnalysis:      enableAnalysis,
@@ -176,6 +256,8 @@ Features:
 				FailBelowThreshold:       true,
 				CoverageThreshold:        cfg.Coverage.Threshold,
 				BlockMergeOnFailure:      blockOnFailure,
+				ResolveMode:              cfg.GitHub.CommentResolve,
+				CelebrateThreshold:       cfg.GitHub.CommentCelebrate,
 			}
 
 			// Adjust settings for anti-spam mode
@@ -188,14 +270,21 @@ Features:
 
 			// Perform coverage comparison and analysis if base coverage is available
 			var comparison *github.CoverageComparison
+			var comparisonResult *analysis.ComparisonResult
 			if baseCoverage != nil && enableAnalysis {
 				comparisonEngine := analysis.NewComparisonEngine(nil)
 
-				// Convert parser data to comparison snapshots
-				baseSnapshot := convertToSnapshot(baseCoverage, defaultBranch, "")
-				prSnapshot := convertToSnapshot(coverage, "current", cfg.GitHub.CommitSHA)
+				// Convert parser data to comparison snapshots; renames and moved blocks in the PR diff
+				// match code across files so that moving it is not reported as a regression
+				baseSnapshot := newComparisonSnapshot(baseCoverage, runBaseBranch(cfg), "")
+				prSnapshot := newComparisonSnapshot(coverage, runHeadBranch(cfg), cfg.GitHub.CommitSHA)
+				if prDiff != nil {
+					prSnapshot.Renames = prDiff.Renames()
+					prSnapshot.MovedBlocks = movedBlocks(baseCoverage, coverage, prDiff.Files)
+				}
 
-				comparisonResult, compErr := comparisonEngine.CompareCoverage(ctx, baseSnapshot, prSnapshot)
+				var compErr error
+				comparisonResult, compErr = comparisonEngine.CompareCoverage(ctx, baseSnapshot, prSnapshot)
 				if compErr != nil {
 					cmd.Printf("Warning: failed to perform coverage comparison: %v\n", compErr)
 				} else {
@@ -206,7 +295,7 @@ Features:
 							TotalStatements:   baseCoverage.TotalLines,   // Actually statement count, not line count
 							CoveredStatements: baseCoverage.CoveredLines, // Actually covered statement count, not line count
 							CommitSHA:         "",
-							Branch:            defaultBranch,
+							Branch:            runBaseBranch(cfg),
 							Timestamp:         time.Now(),
 						},
 						PRCoverage: github.CoverageData{
@@ -214,7 +303,7 @@ Features:
 							TotalStatements:   coverage.TotalLines,   // Actually statement count, not line count
 							CoveredStatements: coverage.CoveredLines, // Actually covered statement count, not line count
 							CommitSHA:         cfg.GitHub.CommitSHA,
-							Branch:            "current",
+							Branch:            runHeadBranch(cfg),
 							Timestamp:         time.Now(),
 						},
 						Difference:       coverage.Percentage - baseCoverage.Percentage,
@@ -245,7 +334,7 @@ Features:
 						TotalStatements:   coverage.TotalLines,   // Actually contains statement count, not line count
 						CoveredStatements: coverage.CoveredLines, // Actually contains covered statement count, not line count
 						CommitSHA:         cfg.GitHub.CommitSHA,
-						Branch:            "current",
+						Branch:            runHeadBranch(cfg),
 						Timestamp:         time.Now(),
 					},
 					Difference: 0, // No meaningful difference without baseline
@@ -259,26 +348,54 @@ Features:
 				}
 			}
 
+			// New files are held to their floor from the comparison with the base branch
+			decision := evaluatePolicy(cfg, coverage, baseCoverage, previous, prDiff)
+			addNewFileResult(cfg, decision, comparisonResult)
+			applyNoTests(cfg, decision, detectNoTests(cmd, cfg))
+			bypass := emergencyBypass(ctx, cfg, prDiffFilenames(prDiff))
+			applyBypass(decision, bypass)
+			warmup := loadWarmupState(ctx, cmd, cfg)
+			applyWarmup(cfg, decision, warmup)
+			printOrgPolicy(cmd, cfg)
+			printBranchRule(cmd, cfg)
+			printLabelOverrides(cmd, cfg)
+			printBypass(cmd, bypass)
+			printWarmup(cmd, cfg, warmup)
+			printPolicyDecision(cmd, decision)
+			forecast := newForecastData(cfg, baseLatest, comparisonResult)
+			printForecast(cmd, forecast)
+
 			// Initialize template engine for comment generation
-			templateEngine := templates.NewPRTemplateEngine(&templates.TemplateConfig{
-				IncludeEmojis:          true,
-				IncludeCharts:          true,
-				MaxFileChanges:         20,
-				MaxRecommendations:     5,
-				UseMarkdownTables:      true,
-				UseCollapsibleSections: true,
-				IncludeProgressBars:    true,
-				BrandingEnabled:        true,
-			})
+			templateEngine := templates.NewPRTemplateEngine(commentTemplateConfig(cfg, maxCommentLength))
 
 			// Build template data
 			templateData := buildTemplateData(cfg, prNumber, comparison, coverage, badgeURL, reportURL)
+			templateData.Policy = newPolicyTemplateData(decision)
+			templateData.Policy.WarmupEnded = warmup.Ended
+			templateData.Forecast = forecast
+			templateData.Insights = commentInsights(comparisonResult, reportURL, templateData.PullRequest.URL)
+			templateData.Resources.FullReportURL = overflowFileURL(cfg, reportURL, prNumber, overflowCommentFile)
+			if overflowDir == "" {
+				overflowDir = overflowCommentDir(cfg, reportURL, prNumber)
+			}
+			packageDiffURL, diffErr := writePackageDiff(cfg, overflowDir,
+				overflowFileURL(cfg, reportURL, prNumber, packageDiffFile), comparisonResult, dryRun)
+			if diffErr != nil {
+				cmd.Printf("Warning: %v\n", diffErr)
+			}
+			templateData.Resources.PackageDiffURL = packageDiffURL
+			if templateDataFile != "" {
+				if err = writeCommentTemplateData(cfg, templateDataFile, templateData); err != nil {
+					cmd.Printf("Warning: %v\n", err)
+				}
+			}
 
-			// Render comment using template engine
-			commentBody, renderErr := templateEngine.RenderComment(ctx, "", templateData)
+			// Render comment using template engine, shortened to fit GitHub's comment size limit
+			rendered, renderErr := templateEngine.RenderBudgetedComment(ctx, templateData)
 			if renderErr != nil {
 				return fmt.Errorf("failed to render comment template: %w", renderErr)
 			}
+			commentBody := rendered.Body
 
 			if dryRun {
 				// Display preview for dry run
@@ -298,6 +415,10 @@ Features:
 				cmd.Printf("  - Badge Generation: %v\n", generateBadges)
 				cmd.Printf("  - Merge Blocking: %v\n", blockOnFailure)
 				cmd.Printf("  - Anti-spam: %v\n", antiSpam)
+				if rendered.Truncated() {
+					cmd.Printf("Shortened sections: %s (full version: %s)\n",
+						strings.Join(rendered.Shortened, ", "), filepath.Join(overflowDir, overflowCommentFile))
+				}
 				cmd.Printf("=====================================\n")
 				cmd.Println(commentBody)
 				cmd.Printf("=====================================\n")
@@ -305,10 +426,54 @@ Features:
 				return nil
 			}
 
+			if rendered.Truncated() {
+				if err = writeOverflowComment(cmd, cfg, overflowDir, rendered); err != nil {
+					cmd.Printf("Warning: %v\n", err)
+				}
+			}
+
+			measured := &handoff.Coverage{
+				Percentage:        coverage.Percentage,
+				TotalStatements:   coverage.TotalLines,
+				CoveredStatements: coverage.CoveredLines,
+				BasePercentage:    comparison.BaseCoverage.Percentage,
+				Difference:        comparison.Difference,
+				Trend:             comparison.TrendAnalysis.Direction,
+				Threshold:         cfg.Coverage.Threshold,
+			}
+
+			if forkSafe {
+				payload := &handoff.Payload{
+					PullRequest:        prNumber,
+					Comment:            commentBody,
+					Coverage:           measured,
+					CreateStatusChecks: createStatus && cfg.GitHub.CommitSHA != "",
+					BlockMerge:         blockOnFailure,
+				}
+				if payload.CreateStatusChecks {
+					payload.Statuses = criticalHandoffStatus(decision, reportURL)
+				}
+				return writeHandoff(cmd, cfg, payload)
+			}
+
 			// Create or update PR comment
 			ctx, cancel = context.WithTimeout(context.Background(), 60*time.Second)
 			defer cancel()
 
+			// A reviewer asked for no comment on this pull request; the statuses still report coverage
+			if cfg.HasLabel(config.LabelSkipComment) {
+				cmd.Printf("💬 Comment skipped: %s%s label\n", cfg.Labels.Prefix, config.LabelSkipComment)
+				if createStatus && cfg.GitHub.CommitSHA != "" {
+					createCoverageStatusChecks(ctx, cmd, cfg, client, prNumber, cfg.GitHub.CommitSHA, measured, criticalContexts(decision))
+					createCriticalStatus(ctx, cmd, cfg, client, cfg.GitHub.CommitSHA, reportURL, decision)
+				}
+				return nil
+			}
+
+			// One GraphQL query serves the pull request and label lookups of the comment and the
+			// status checks; on failure they fall back to REST
+			_, _ = client.GetPullRequests(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, []int{prNumber})
+
 			result, err := prCommentManager.CreateOrUpdatePRComment(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber, commentBody, comparison)
 			if err != nil {
 				return fmt.Errorf("failed to create PR comment: %w", err)
@@ -321,74 +486,12 @@ Features:
 				cmd.Printf("Change: %+.2f%% vs base\n", comparison.Difference)
 			}
 			cmd.Printf("Action taken: %s (%s)\n", result.Action, result.Reason)
+			printCommentTidy(cmd, result)
 
 			// Create status checks if requested
 			if createStatus && cfg.GitHub.CommitSHA != "" {
-				statusManager := github.NewStatusCheckManager(client, &github.StatusCheckConfig{
-					ContextPrefix:          "go-coverage",
-					MainContext:            "coverage/total",
-					AdditionalContexts:     []string{"coverage/trend", "coverage/quality"},
-					EnableBlocking:         true,
-					BlockOnFailure:         true,
-					BlockOnError:           false,
-					RequireAllPassing:      false,
-					CoverageThreshold:      cfg.Coverage.Threshold,
-					QualityThreshold:       "C",
-					AllowThresholdOverride: cfg.Coverage.AllowLabelOverride,
-					AllowLabelOverride:     cfg.Coverage.AllowLabelOverride,
-					EnableQualityGates:     true,
-					IncludeTargetURLs:      true,
-					UpdateStrategy:         github.UpdateAlways,
-					StatusTimeout:          30 * time.Second,
-					RetrySettings: github.RetrySettings{
-						MaxRetries:    3,
-						RetryDelay:    1 * time.Second,
-						BackoffFactor: 2.0,
-					},
-				})
-
-				statusRequest := &github.StatusCheckRequest{
-					Owner:      cfg.GitHub.Owner,
-					Repository: cfg.GitHub.Repository,
-					CommitSHA:  cfg.GitHub.CommitSHA,
-					PRNumber:   prNumber,
-					Branch:     "current",
-					BaseBranch: defaultBranch,
-					Coverage: github.CoverageStatusData{
-						Percentage:        coverage.Percentage,
-						TotalStatements:   coverage.TotalLines,
-						CoveredStatements: coverage.CoveredLines,
-						Change:            comparison.Difference,
-						Trend:             comparison.TrendAnalysis.Direction,
-					},
-					Comparison: github.ComparisonStatusData{
-						BasePercentage:    comparison.BaseCoverage.Percentage,
-						CurrentPercentage: comparison.PRCoverage.Percentage,
-						Difference:        comparison.Difference,
-						IsSignificant:     comparison.Difference > 1.0 || comparison.Difference < -1.0,
-						Direction:         comparison.TrendAnalysis.Direction,
-					},
-					Quality: github.QualityStatusData{
-						Grade:     calculateQualityGrade(coverage.Percentage),
-						Score:     coverage.Percentage,
-						RiskLevel: calculateRiskLevel(coverage.Percentage),
-					},
-				}
-
-				statusResult, err := statusManager.CreateStatusChecks(ctx, statusRequest)
-				if err != nil {
-					cmd.Printf("Warning: failed to create status checks: %v\n", err)
-				} else {
-					cmd.Printf("Created %d status checks\n", statusResult.TotalChecks)
-					cmd.Printf("Passed: %d, Failed: %d, Errors: %d\n",
-						statusResult.PassedChecks, statusResult.FailedChecks, statusResult.ErrorChecks)
-					if statusResult.BlockingPR {
-						cmd.Printf("⚠️ PR merge is blocked due to failed required checks\n")
-					}
-					if len(statusResult.RequiredFailed) > 0 {
-						cmd.Printf("Failed required checks: %v\n", statusResult.RequiredFailed)
-					}
-				}
+				createCoverageStatusChecks(ctx, cmd, cfg, client, prNumber, cfg.GitHub.CommitSHA, measured, criticalContexts(decision))
+				createCriticalStatus(ctx, cmd, cfg, client, cfg.GitHub.CommitSHA, reportURL, decision)
 			}
 
 			return nil
@@ -397,7 +500,8 @@ Features:
 
 	// Add flags
 	cmd.Flags().IntP("pr", "p", 0, "Pull request number")
-	cmd.Flags().StringP("coverage", "c", "", "Path to coverage profile file")
+	cmd.Flags().String("provider", "", "Code hosting provider: github, bitbucket or azuredevops (default: detected from the CI environment)")
+	addCoverageInputFlags(cmd)
 	cmd.Flags().String("base-coverage", "", "Path to base branch coverage file for comparison")
 	cmd.Flags().String("badge-url", "", "Custom badge URL (optional)")
 	cmd.Flags().String("report-url", "", "Custom report URL (optional)")
@@ -406,11 +510,194 @@ Features:
 	cmd.Flags().Bool("generate-badges", false, "Generate PR-specific badges")
 	cmd.Flags().Bool("enable-analysis", true, "Enable code quality analysis")
 	cmd.Flags().Bool("anti-spam", true, "Enable anti-spam features")
-	cmd.Flags().Bool("dry-run", false, "Show what would be posted without actually posting")
+	cmd.Flags().Int("max-comment-length", templates.MaxCommentLength, "Maximum comment length in characters; longer comments are shortened")
+	cmd.Flags().String("overflow-dir", "", "Directory for the full version of a shortened comment (default: the report directory under the output directory)")
+	addFailWithoutTestsFlag(cmd)
+	cmd.Flags().String("template-data", "", "Write the comment template data as JSON to this file, for templates preview")
+	addDryRunFlag(cmd, "Show what would be posted without actually posting")
+
+	cmd.AddCommand(c.newCommentRelayCmd(), c.newCommentBatchCmd())
 
 	return cmd
 }
 
+// printCommentTidy reports how the comment thread was tidied after posting
+func printCommentTidy(cmd *cobra.Command, result *github.PRCommentResponse) {
+	switch {
+	case result.Minimized:
+		cmd.Printf("✅ Coverage passes again: comment minimized as resolved\n")
+	case result.Resolved:
+		cmd.Printf("✅ Coverage passes again after failing\n")
+	}
+	if result.Reacted {
+		cmd.Printf("🎉 Coverage improved by %+.2f%%\n", result.CoverageData.Difference)
+	}
+}
+
+// createCoverageStatusChecks creates the coverage status checks for a pull request commit and
+// marks the contexts of earlier runs it no longer reports as obsolete, except the retained
+// contexts the caller posts itself. Failures are reported as warnings because the comment has
+// already been posted.
+func createCoverageStatusChecks(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	prNumber int, commitSHA string, result *handoff.Coverage, retained []string,
+) {
+	statusManager := github.NewStatusCheckManager(client, &github.StatusCheckConfig{
+		ContextPrefix:          "go-coverage",
+		MainContext:            "coverage/total",
+		AdditionalContexts:     []string{"coverage/trend", "coverage/quality"},
+		EnableBlocking:         true,
+		BlockOnFailure:         true,
+		BlockOnError:           false,
+		RequireAllPassing:      false,
+		CoverageThreshold:      cfg.Coverage.Threshold,
+		QualityThreshold:       "C",
+		AllowThresholdOverride: cfg.Coverage.AllowLabelOverride,
+		AllowLabelOverride:     cfg.Coverage.AllowLabelOverride,
+		EnableQualityGates:     true,
+		IncludeTargetURLs:      true,
+		CleanupStale:           cfg.GitHub.CleanupStatuses,
+		RetainedContexts:       retained,
+		UpdateStrategy:         github.UpdateAlways,
+		StatusTimeout:          30 * time.Second,
+		// Transient API failures are already retried by the client's retry policy
+		RetrySettings: github.RetrySettings{
+			MaxRetries: 0,
+		},
+	})
+
+	statusRequest := &github.StatusCheckRequest{
+		Owner:      cfg.GitHub.Owner,
+		Repository: cfg.GitHub.Repository,
+		CommitSHA:  commitSHA,
+		PRNumber:   prNumber,
+		Branch:     runHeadBranch(cfg),
+		BaseBranch: runBaseBranch(cfg),
+		Coverage: github.CoverageStatusData{
+			Percentage:        result.Percentage,
+			TotalStatements:   result.TotalStatements,
+			CoveredStatements: result.CoveredStatements,
+			Change:            result.Difference,
+			Trend:             result.Trend,
+		},
+		Comparison: github.ComparisonStatusData{
+			BasePercentage:    result.BasePercentage,
+			CurrentPercentage: result.Percentage,
+			Difference:        result.Difference,
+			IsSignificant:     result.Difference > 1.0 || result.Difference < -1.0,
+			Direction:         result.Trend,
+		},
+		Quality: github.QualityStatusData{
+			Grade:     calculateQualityGrade(result.Percentage),
+			Score:     result.Percentage,
+			RiskLevel: calculateRiskLevel(result.Percentage),
+		},
+	}
+
+	statusResult, err := statusManager.CreateStatusChecks(ctx, statusRequest)
+	if err != nil {
+		cmd.Printf("Warning: failed to create status checks: %v\n", err)
+		return
+	}
+	cmd.Printf("Created %d status checks\n", statusResult.TotalChecks)
+	cmd.Printf("Passed: %d, Failed: %d, Errors: %d\n",
+		statusResult.PassedChecks, statusResult.FailedChecks, statusResult.ErrorChecks)
+	if statusResult.BlockingPR {
+		cmd.Printf("⚠️ PR merge is blocked due to failed required checks\n")
+	}
+	if len(statusResult.RequiredFailed) > 0 {
+		cmd.Printf("Failed required checks: %v\n", statusResult.RequiredFailed)
+	}
+	if len(statusResult.ObsoleteContexts) > 0 {
+		cmd.Printf("Marked %d obsolete status checks: %v\n", len(statusResult.ObsoleteContexts), statusResult.ObsoleteContexts)
+	}
+}
+
+// noCodeChangesDescription is the status description for pull requests without code changes
+const noCodeChangesDescription = "No code changes, coverage unaffected"
+
+// postNoCodeChangesComment posts the short comment for a PR that changes no code and, under
+// the success policy, a passing coverage status, without reading any coverage profile
+func postNoCodeChangesComment(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	prNumber int, prFileAnalysis *github.PRFileAnalysis, badgeURL, reportURL string, createStatus, dryRun, forkSafe bool,
+) error {
+	cmd.Printf("⏭️  No code changes, coverage is unaffected (policy: %s)\n", cfg.Policy.NoCodeChanges)
+
+	templateEngine := templates.NewPRTemplateEngine(&templates.TemplateConfig{
+		IncludeEmojis:   true,
+		CustomHeader:    cfg.GitHub.CommentHeader,
+		CustomFooter:    cfg.GitHub.CommentFooter,
+		BrandingEnabled: cfg.GitHub.CommentBranding,
+		Signature:       cfg.GitHub.CommentSignature,
+	})
+	commentBody, err := templateEngine.RenderNoCodeChangesComment(ctx, &templates.TemplateData{
+		PRFiles:   convertPRFileAnalysis(prFileAnalysis),
+		Resources: templates.ResourceLinks{BadgeURL: badgeURL, ReportURL: reportURL},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render comment template: %w", err)
+	}
+
+	postStatus := createStatus && cfg.GitHub.CommitSHA != "" && cfg.Policy.NoCodeChanges != config.NoCodeChangesComment
+	if dryRun {
+		cmd.Printf("PR Comment Preview (Dry Run)\n")
+		cmd.Printf("=====================================\n")
+		cmd.Printf("Template: no-code-changes\n")
+		cmd.Printf("PR: %d\n", prNumber)
+		cmd.Printf("Success status: %v\n", postStatus)
+		cmd.Printf("=====================================\n")
+		cmd.Println(commentBody)
+		cmd.Printf("=====================================\n")
+		return nil
+	}
+
+	// Report success under the contexts a full run would use, so required checks are satisfied
+	statusContexts := []string{handoff.StatusContextPrefix + "coverage/total", handoff.PRStatusContext}
+
+	if forkSafe {
+		payload := &handoff.Payload{PullRequest: prNumber, Comment: commentBody}
+		if postStatus {
+			for _, statusContext := range statusContexts {
+				payload.Statuses = append(payload.Statuses, handoff.Status{
+					State:       github.StatusStateSuccess,
+					Context:     statusContext,
+					Description: noCodeChangesDescription,
+					TargetURL:   reportURL,
+				})
+			}
+		}
+		return writeHandoff(cmd, cfg, payload)
+	}
+
+	prCommentManager := github.NewPRCommentManager(client, &github.PRCommentConfig{
+		MinUpdateIntervalMinutes: 0,
+		MaxCommentsPerPR:         1,
+		CommentSignature:         cfg.GitHub.CommentSignature,
+	})
+	comparison := &github.CoverageComparison{PRFileAnalysis: prFileAnalysis}
+	result, err := prCommentManager.CreateOrUpdatePRComment(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber, commentBody, comparison)
+	if err != nil {
+		return fmt.Errorf("failed to create PR comment: %w", err)
+	}
+	cmd.Printf("Coverage comment %s successfully!\n", result.Action)
+
+	if !postStatus {
+		return nil
+	}
+	for _, statusContext := range statusContexts {
+		err = client.CreateStatus(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.GitHub.CommitSHA, &github.StatusRequest{
+			State:       github.StatusStateSuccess,
+			TargetURL:   reportURL,
+			Description: noCodeChangesDescription,
+			Context:     statusContext,
+		})
+		if err != nil {
+			cmd.Printf("Warning: failed to create %s status: %v\n", statusContext, err)
+		}
+	}
+	cmd.Printf("Created success status for a PR without code changes\n")
+	return nil
+}
+
 // Helper functions for converting data structures
 
 func convertToSnapshot(coverage *parser.CoverageData, branch, commitSHA string) *analysis.CoverageSnapshot {
@@ -450,13 +737,15 @@ func convertFileChanges(changes []analysis.FileChangeAnalysis) []github.FileChan
 	fileChanges := make([]github.FileChange, 0, len(changes))
 	for _, change := range changes {
 		fileChanges = append(fileChanges, github.FileChange{
-			Filename:      change.Filename,
-			BaseCoverage:  change.BasePercentage,
-			PRCoverage:    change.PRPercentage,
-			Difference:    change.PercentageChange,
-			LinesAdded:    change.LinesAdded,
-			LinesRemoved:  change.LinesRemoved,
-			IsSignificant: change.IsSignificant,
+			Filename:         change.Filename,
+			PreviousFilename: change.PreviousFilename,
+			IsMoved:          change.Direction == analysis.DirectionMoved,
+			BaseCoverage:     change.BasePercentage,
+			PRCoverage:       change.PRPercentage,
+			Difference:       change.PercentageChange,
+			LinesAdded:       change.LinesAdded,
+			LinesRemoved:     change.LinesRemoved,
+			IsSignificant:    change.IsSignificant,
 		})
 	}
 	return fileChanges
@@ -483,8 +772,8 @@ func buildTemplateData(cfg *config.Config, prNumber int, comparison *github.Cove
 		PullRequest: templates.PullRequestInfo{
 			Number:     prNumber,
 			Title:      "",
-			Branch:     "current",
-			BaseBranch: defaultBranch,
+			Branch:     runHeadBranch(cfg),
+			BaseBranch: runBaseBranch(cfg),
 			Author:     "",
 			CommitSHA:  cfg.GitHub.CommitSHA,
 			URL:        fmt.Sprintf("https://github.com/%s/%s/pull/%d", cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber),
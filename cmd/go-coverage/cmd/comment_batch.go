@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+var (
+	// ErrBatchPRsRequired indicates that no pull request numbers were given to the batch command
+	ErrBatchPRsRequired = errors.New("at least one pull request number is required")
+	// ErrBatchFailures indicates that one or more pull requests of a batch failed
+	ErrBatchFailures = errors.New("some pull requests in the batch failed")
+)
+
+// Outcomes of a pull request processed by the batch command
+const (
+	batchOutcomePosted  = "posted"
+	batchOutcomeDryRun  = "dry_run"
+	batchOutcomeSkipped = "skipped"
+	batchOutcomeFailed  = "failed"
+)
+
+// prPlaceholder is replaced by the pull request number in the batch input pattern
+const prPlaceholder = "{pr}"
+
+// batchResult is the outcome of one pull request in a batch
+type batchResult struct {
+	PullRequest int     `json:"pull_request"`
+	Outcome     string  `json:"outcome"`
+	Action      string  `json:"action,omitempty"`
+	CommitSHA   string  `json:"commit_sha,omitempty"`
+	Coverage    float64 `json:"coverage,omitempty"`
+	Passed      bool    `json:"passed"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// batchSummary is the JSON document written by the batch command
+type batchSummary struct {
+	Total     int               `json:"total"`
+	Posted    int               `json:"posted"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Results   []batchResult     `json:"results"`
+	RateLimit *github.RateLimit `json:"rate_limit,omitempty"`
+}
+
+// batchOptions holds the settings shared by every pull request of a batch
+type batchOptions struct {
+	inputPattern  string
+	baseCoverage  *parser.CoverageData
+	createStatus  bool
+	dryRun        bool
+	rateReserve   int
+	maxRateWait   time.Duration
+	perPRDeadline time.Duration
+}
+
+// newCommentBatchCmd creates the comment batch command
+func (c *Commands) newCommentBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch [pr...]",
+		Short: "Post coverage comments on many pull requests in one run",
+		Long: `Post coverage comments and status checks on a list of pull requests, for example
+after a dependency bot rebased a wave of them. One GitHub client is shared by all pull
+requests and the remaining API quota is checked before each one: when it drops below
+--rate-limit-reserve the batch waits for the quota to reset, or skips the remaining pull
+requests if the reset is further away than --max-rate-wait.
+
+The coverage profile of each pull request is read from --input-pattern, in which {pr} is
+replaced by the pull request number. Closed pull requests are skipped. The per-PR
+outcomes are printed as JSON, or written to --output.`,
+		Example: `  # Profiles downloaded to coverage-<pr>.txt
+  go-coverage comment batch 101 102 103 --base-coverage main.txt
+
+  # From a list, with profiles in per-PR directories
+  go-coverage comment batch --prs 101,102 --input-pattern "profiles/{pr}/coverage.txt" --output batch.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prs, _ := cmd.Flags().GetIntSlice("prs")
+			inputPattern, _ := cmd.Flags().GetString("input-pattern")
+			baseCoverageFile, _ := cmd.Flags().GetString("base-coverage")
+			createStatus, _ := cmd.Flags().GetBool("status")
+			rateReserve, _ := cmd.Flags().GetInt("rate-limit-reserve")
+			maxRateWait, _ := cmd.Flags().GetDuration("max-rate-wait")
+			outputFile, _ := cmd.Flags().GetString("output")
+			dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+			for _, arg := range args {
+				number, err := strconv.Atoi(arg)
+				if err != nil || number <= 0 {
+					return fmt.Errorf("%w: invalid pull request number %q", ErrBatchPRsRequired, arg)
+				}
+				prs = append(prs, number)
+			}
+			if len(prs) == 0 {
+				return ErrBatchPRsRequired
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err = requireNetwork(cmd, cfg, "the comment batch command"); err != nil {
+				return err
+			}
+			if cfg.GitHub.Token == "" {
+				return ErrGitHubTokenRequired
+			}
+			if cfg.GitHub.Owner == "" {
+				return ErrGitHubOwnerRequired
+			}
+			if cfg.GitHub.Repository == "" {
+				return ErrGitHubRepoRequired
+			}
+
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+
+			opts := batchOptions{
+				inputPattern:  inputPattern,
+				createStatus:  createStatus,
+				dryRun:        dryRun,
+				rateReserve:   rateReserve,
+				maxRateWait:   maxRateWait,
+				perPRDeadline: 60 * time.Second,
+			}
+			if baseCoverageFile != "" {
+				if opts.baseCoverage, err = parser.New().WithModuleRewrites(cfg.ModuleRewrites()).ParseFile(context.Background(), baseCoverageFile); err != nil {
+					return fmt.Errorf("failed to parse base coverage file: %w", err)
+				}
+			}
+
+			summary := runCommentBatch(context.Background(), cmd, cfg, client, prs, opts)
+			if err = writeBatchSummary(cmd, summary, outputFile, cfg.Storage.FileMode); err != nil {
+				return err
+			}
+			if summary.Failed > 0 {
+				return fmt.Errorf("%w: %d of %d", ErrBatchFailures, summary.Failed, summary.Total)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntSlice("prs", nil, "Pull request numbers (comma-separated, in addition to arguments)")
+	cmd.Flags().String("input-pattern", "coverage-"+prPlaceholder+".txt", "Coverage profile path per pull request, {pr} is replaced by the number")
+	cmd.Flags().String("base-coverage", "", "Path to base branch coverage file shared by all pull requests")
+	cmd.Flags().Bool("status", true, "Create GitHub commit statuses on each pull request head")
+	cmd.Flags().Int("rate-limit-reserve", 100, "API requests to keep in reserve; below this the batch waits for the quota reset")
+	cmd.Flags().Duration("max-rate-wait", 5*time.Minute, "Longest wait for a quota reset before skipping the remaining pull requests")
+	cmd.Flags().StringP("output", "o", "", "Write the JSON summary to this file instead of stdout")
+	addDryRunFlag(cmd, "Render the comments without posting them")
+
+	return cmd
+}
+
+// runCommentBatch processes the pull requests in order with one client, never failing the whole
+// batch for a single pull request
+func runCommentBatch(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API, prs []int, opts batchOptions) *batchSummary {
+	summary := &batchSummary{Total: len(prs), Results: make([]batchResult, 0, len(prs))}
+
+	// Fetch the metadata of all pull requests in one GraphQL query; the client serves the
+	// per-PR lookups below from it. If it fails, each pull request is fetched over REST.
+	_, _ = client.GetPullRequests(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prs)
+
+	rateLimited := false
+	for _, prNumber := range prs {
+		var result batchResult
+		if !rateLimited {
+			ok, err := client.WaitForRateLimit(ctx, opts.rateReserve, opts.maxRateWait)
+			if err != nil || !ok {
+				rateLimited = true
+			}
+		}
+		if rateLimited {
+			result = batchResult{PullRequest: prNumber, Outcome: batchOutcomeSkipped, Reason: "API rate limit reserve reached"}
+		} else {
+			prCtx, cancel := context.WithTimeout(ctx, opts.perPRDeadline)
+			result = processBatchPR(prCtx, cmd, cfg, client, prNumber, opts)
+			cancel()
+		}
+
+		switch result.Outcome {
+		case batchOutcomePosted, batchOutcomeDryRun:
+			summary.Posted++
+		case batchOutcomeSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+		summary.Results = append(summary.Results, result)
+	}
+
+	if rate := client.RateLimit(); rate.Known() {
+		summary.RateLimit = &rate
+	}
+	return summary
+}
+
+// processBatchPR renders and posts the coverage comment and statuses of one pull request
+func processBatchPR(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API, prNumber int, opts batchOptions) batchResult {
+	result := batchResult{PullRequest: prNumber}
+	fail := func(err error) batchResult {
+		result.Outcome = batchOutcomeFailed
+		result.Reason = err.Error()
+		return result
+	}
+
+	pr, err := client.GetPullRequest(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber)
+	if err != nil {
+		return fail(err)
+	}
+	result.CommitSHA = pr.Head.SHA
+	if pr.State != "" && pr.State != "open" {
+		result.Outcome = batchOutcomeSkipped
+		result.Reason = "pull request is " + pr.State
+		return result
+	}
+
+	coverage, err := parser.New().WithModuleRewrites(cfg.ModuleRewrites()).ParseFile(ctx, strings.ReplaceAll(opts.inputPattern, prPlaceholder, strconv.Itoa(prNumber)))
+	if err != nil {
+		return fail(err)
+	}
+	result.Coverage = coverage.Percentage
+
+	decision := evaluatePolicy(cfg, coverage, opts.baseCoverage, nil, nil)
+	result.Passed = decision.Passed
+
+	// URLs, branches and statuses refer to the pull request, not to the run of the batch
+	prCfg := *cfg
+	prCfg.GitHub.PullRequest = prNumber
+	prCfg.GitHub.CommitSHA = pr.Head.SHA
+	run := runContext(cfg)
+	prCfg.CI = &ci.Context{
+		Provider:    run.Provider,
+		Event:       run.Event,
+		Owner:       cfg.GitHub.Owner,
+		Repository:  cfg.GitHub.Repository,
+		PullRequest: prNumber,
+		Branch:      pr.Head.Ref,
+		BaseBranch:  pr.Base.Ref,
+		CommitSHA:   pr.Head.SHA,
+	}
+	comparison := newSimpleComparison(coverage, opts.baseCoverage, runHeadBranch(&prCfg), runBaseBranch(&prCfg), pr.Head.SHA)
+
+	templateData := buildTemplateData(&prCfg, prNumber, comparison, coverage, prCfg.GetBadgeURL(), prCfg.GetReportURL())
+	templateData.Policy = newPolicyTemplateData(decision)
+	commentBody, err := templates.NewPRTemplateEngine(&templates.TemplateConfig{
+		IncludeEmojis:          true,
+		IncludeCharts:          true,
+		MaxFileChanges:         20,
+		MaxRecommendations:     5,
+		UseMarkdownTables:      true,
+		UseCollapsibleSections: true,
+		IncludeProgressBars:    true,
+		CustomHeader:           cfg.GitHub.CommentHeader,
+		CustomFooter:           cfg.GitHub.CommentFooter,
+		BrandingEnabled:        cfg.GitHub.CommentBranding,
+		Signature:              cfg.GitHub.CommentSignature,
+	}).RenderComment(ctx, "", templateData)
+	if err != nil {
+		return fail(fmt.Errorf("failed to render comment template: %w", err))
+	}
+
+	if opts.dryRun {
+		result.Outcome = batchOutcomeDryRun
+		return result
+	}
+
+	prCommentManager := github.NewPRCommentManager(client, &github.PRCommentConfig{
+		MinUpdateIntervalMinutes: 0,
+		MaxCommentsPerPR:         1,
+		CommentSignature:         cfg.GitHub.CommentSignature,
+		ResolveMode:              cfg.GitHub.CommentResolve,
+		CelebrateThreshold:       cfg.GitHub.CommentCelebrate,
+	})
+	response, err := prCommentManager.CreateOrUpdatePRComment(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber, commentBody, comparison)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create PR comment: %w", err))
+	}
+	result.Outcome = batchOutcomePosted
+	result.Action = response.Action
+
+	if opts.createStatus && pr.Head.SHA != "" {
+		createCoverageStatusChecks(ctx, cmd, &prCfg, client, prNumber, pr.Head.SHA, &handoff.Coverage{
+			Percentage:        coverage.Percentage,
+			TotalStatements:   coverage.TotalLines,
+			CoveredStatements: coverage.CoveredLines,
+			BasePercentage:    comparison.BaseCoverage.Percentage,
+			Difference:        comparison.Difference,
+			Trend:             comparison.TrendAnalysis.Direction,
+			Threshold:         cfg.Coverage.Threshold,
+		}, []string{criticalStatusContext}) // Critical paths are not evaluated here, so their status is kept
+	}
+	return result
+}
+
+// newSimpleComparison compares coverage with an optional baseline without file-level analysis
+func newSimpleComparison(coverage, baseCoverage *parser.CoverageData, branch, baseBranch, commitSHA string) *github.CoverageComparison {
+	comparison := &github.CoverageComparison{
+		PRCoverage: github.CoverageData{
+			Percentage:        coverage.Percentage,
+			TotalStatements:   coverage.TotalLines,
+			CoveredStatements: coverage.CoveredLines,
+			CommitSHA:         commitSHA,
+			Branch:            branch,
+			Timestamp:         time.Now(),
+		},
+		TrendAnalysis: github.TrendData{Direction: "stable", Magnitude: "minor", Momentum: "steady"},
+	}
+	if baseCoverage == nil {
+		return comparison
+	}
+
+	comparison.BaseCoverage = github.CoverageData{
+		Percentage:        baseCoverage.Percentage,
+		TotalStatements:   baseCoverage.TotalLines,
+		CoveredStatements: baseCoverage.CoveredLines,
+		Branch:            baseBranch,
+		Timestamp:         time.Now(),
+	}
+	comparison.Difference = coverage.Percentage - baseCoverage.Percentage
+	switch {
+	case comparison.Difference > 0:
+		comparison.TrendAnalysis.Direction = "up"
+	case comparison.Difference < 0:
+		comparison.TrendAnalysis.Direction = "down"
+	}
+	return comparison
+}
+
+// writeBatchSummary prints the summary as JSON or writes it to outputFile
+func writeBatchSummary(cmd *cobra.Command, summary *batchSummary, outputFile string, mode os.FileMode) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch summary: %w", err)
+	}
+	if outputFile == "" {
+		cmd.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(outputFile, append(data, '\n'), mode); err != nil {
+		return fmt.Errorf("failed to write batch summary: %w", err)
+	}
+	cmd.Printf("Batch summary written to %s\n", outputFile)
+	return nil
+}
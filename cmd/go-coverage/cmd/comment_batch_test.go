@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestRunCommentBatch(t *testing.T) {
+	var mu sync.Mutex
+	commented := map[int]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		switch {
+		case r.URL.Path == "/graphql":
+			// All pull requests are fetched at once, so there are no per-PR REST lookups
+			_, _ = w.Write([]byte(`{"data":{"repository":{
+				"pr1":{"number":1,"state":"OPEN","headRefOid":"sha1"},
+				"pr2":{"number":2,"state":"MERGED","headRefOid":"sha2"},
+				"pr3":{"number":3,"state":"OPEN","headRefOid":"sha3"}}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/1/comments":
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/1/comments":
+			commented[1]++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":10}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	profile := "mode: set\ngithub.com/owner/repo/main.go:1.1,2.2 3 1\ngithub.com/owner/repo/main.go:3.1,4.2 1 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "coverage-1.txt"), []byte(profile), 0o600))
+
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 50},
+		GitHub:   config.GitHubConfig{Owner: "owner", Repository: "repo"},
+	}
+	client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+
+	summary := runCommentBatch(context.Background(), cmd, cfg, client, []int{1, 2, 3}, batchOptions{
+		inputPattern:  filepath.Join(dir, "coverage-"+prPlaceholder+".txt"),
+		rateReserve:   10,
+		maxRateWait:   time.Second,
+		perPRDeadline: 10 * time.Second,
+	})
+
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Posted)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Results, 3)
+	assert.Equal(t, batchOutcomePosted, summary.Results[0].Outcome)
+	assert.Equal(t, "sha1", summary.Results[0].CommitSHA)
+	assert.InDelta(t, 75.0, summary.Results[0].Coverage, 0.01)
+	assert.True(t, summary.Results[0].Passed)
+	assert.Equal(t, batchOutcomeSkipped, summary.Results[1].Outcome)
+	assert.Equal(t, batchOutcomeFailed, summary.Results[2].Outcome)
+	assert.Equal(t, 1, commented[1])
+	require.NotNil(t, summary.RateLimit)
+	assert.Equal(t, 4000, summary.RateLimit.Remaining)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, writeBatchSummary(cmd, summary, "", 0o644))
+	var decoded batchSummary
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, summary.Posted, decoded.Posted)
+}
+
+func TestRunCommentBatchStopsAtRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "5")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		_, _ = w.Write([]byte(`{"number":1,"state":"closed","head":{"sha":"sha1"}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+	client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	summary := runCommentBatch(context.Background(), &cobra.Command{}, cfg, client, []int{1, 2, 3}, batchOptions{
+		rateReserve:   10,
+		maxRateWait:   time.Minute,
+		perPRDeadline: 10 * time.Second,
+	})
+	assert.Equal(t, 3, summary.Skipped)
+	assert.Equal(t, "API rate limit reserve reached", summary.Results[1].Reason)
+	assert.Equal(t, "API rate limit reserve reached", summary.Results[2].Reason)
+}
+
+func TestNewSimpleComparison(t *testing.T) {
+	coverage := &parser.CoverageData{Percentage: 80, TotalLines: 10, CoveredLines: 8}
+
+	comparison := newSimpleComparison(coverage, nil, "feature", "master", "sha")
+	assert.Zero(t, comparison.BaseCoverage.Percentage)
+	assert.Equal(t, "stable", comparison.TrendAnalysis.Direction)
+	assert.Equal(t, "feature", comparison.PRCoverage.Branch)
+
+	comparison = newSimpleComparison(coverage, &parser.CoverageData{Percentage: 85}, "feature", "master", "sha")
+	assert.InDelta(t, -5.0, comparison.Difference, 0.001)
+	assert.Equal(t, "down", comparison.TrendAnalysis.Direction)
+	assert.Equal(t, "master", comparison.BaseCoverage.Branch)
+}
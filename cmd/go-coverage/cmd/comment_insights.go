@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+const (
+	// insightLowCoverage is the coverage below which new files are called out
+	insightLowCoverage = 50.0
+
+	// insightMaxFiles limits the files named in one insight
+	insightMaxFiles = 3
+
+	// Insight priorities, as in comparison recommendations
+	insightPriorityHigh   = "high"
+	insightPriorityMedium = "medium"
+)
+
+// commentInsights turns a coverage comparison into actionable insights for the PR comment:
+// new files with low coverage, files that lost coverage, and the recommendations of the
+// comparison engine. Insights link to the coverage report, or to the changed files of the PR.
+func commentInsights(result *analysis.ComparisonResult, reportURL, prURL string) []templates.InsightData {
+	if result == nil {
+		return nil
+	}
+
+	link := reportURL
+	if link == "" && prURL != "" {
+		link = prURL + "/files"
+	}
+
+	var insights []templates.InsightData
+
+	var lowNewFiles, droppedFiles []analysis.FileChangeAnalysis
+	for _, change := range result.FileChanges {
+		switch {
+		case change.IsNewFile && change.PRPercentage < insightLowCoverage:
+			lowNewFiles = append(lowNewFiles, change)
+		case !change.IsNewFile && !change.IsDeleted && change.IsSignificant && change.Direction == analysis.DirectionDegraded:
+			droppedFiles = append(droppedFiles, change)
+		}
+	}
+
+	if len(lowNewFiles) > 0 {
+		slices.SortStableFunc(lowNewFiles, func(a, b analysis.FileChangeAnalysis) int {
+			return cmp.Compare(a.PRPercentage, b.PRPercentage)
+		})
+		insights = append(insights, templates.InsightData{
+			Priority: insightPriorityHigh,
+			Title:    fmt.Sprintf("%d new %s under %.0f%% coverage", len(lowNewFiles), pluralFiles(len(lowNewFiles)), insightLowCoverage),
+			Detail: insightFiles(lowNewFiles, func(change analysis.FileChangeAnalysis) string {
+				return fmt.Sprintf("%.1f%%", change.PRPercentage)
+			}),
+			URL: link,
+		})
+	}
+
+	if len(droppedFiles) > 0 {
+		slices.SortStableFunc(droppedFiles, func(a, b analysis.FileChangeAnalysis) int {
+			return cmp.Compare(a.PercentageChange, b.PercentageChange)
+		})
+		insights = append(insights, templates.InsightData{
+			Priority: insightPriorityMedium,
+			Title:    fmt.Sprintf("%d %s lost coverage", len(droppedFiles), pluralFiles(len(droppedFiles))),
+			Detail: insightFiles(droppedFiles, func(change analysis.FileChangeAnalysis) string {
+				return fmt.Sprintf("%+.1f%%", change.PercentageChange)
+			}),
+			URL: link,
+		})
+	}
+
+	for _, recommendation := range result.Recommendations {
+		insights = append(insights, templates.InsightData{
+			Priority: recommendation.Priority,
+			Title:    recommendation.Title,
+			Detail:   recommendation.Description,
+			URL:      link,
+		})
+	}
+
+	return insights
+}
+
+// insightFiles names the first files of an insight with a value each, noting how many more there are
+func insightFiles(changes []analysis.FileChangeAnalysis, value func(analysis.FileChangeAnalysis) string) string {
+	names := make([]string, 0, insightMaxFiles)
+	for _, change := range changes[:min(len(changes), insightMaxFiles)] {
+		names = append(names, fmt.Sprintf("`%s` (%s)", change.Filename, value(change)))
+	}
+	detail := strings.Join(names, ", ")
+	if more := len(changes) - insightMaxFiles; more > 0 {
+		detail += fmt.Sprintf(" and %d more", more)
+	}
+	return detail
+}
+
+// pluralFiles returns "file" or "files" for a count
+func pluralFiles(count int) string {
+	if count == 1 {
+		return "file"
+	}
+	return "files"
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+)
+
+func TestCommentInsights(t *testing.T) {
+	assert.Nil(t, commentInsights(nil, "", ""))
+
+	result := &analysis.ComparisonResult{
+		FileChanges: []analysis.FileChangeAnalysis{
+			{Filename: "a.go", PRPercentage: 40, IsNewFile: true},
+			{Filename: "b.go", PRPercentage: 10, IsNewFile: true},
+			{Filename: "c.go", PRPercentage: 30, IsNewFile: true},
+			{Filename: "d.go", PRPercentage: 20, IsNewFile: true},
+			{Filename: "e.go", PRPercentage: 90, IsNewFile: true},
+			{Filename: "f.go", PRPercentage: 60, PercentageChange: -15, IsSignificant: true, Direction: analysis.DirectionDegraded},
+			{Filename: "g.go", PRPercentage: 70, PercentageChange: -0.5, Direction: analysis.DirectionDegraded},
+		},
+		Recommendations: []analysis.Recommendation{
+			{Priority: "high", Title: "Improve Overall Coverage", Description: "Current coverage 60.0% is below acceptable threshold of 70.0%"},
+		},
+	}
+
+	insights := commentInsights(result, "", "https://github.com/owner/repo/pull/7")
+	require.Len(t, insights, 3)
+
+	assert.Equal(t, "high", insights[0].Priority)
+	assert.Equal(t, "4 new files under 50% coverage", insights[0].Title)
+	assert.Equal(t, "`b.go` (10.0%), `d.go` (20.0%), `c.go` (30.0%) and 1 more", insights[0].Detail)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7/files", insights[0].URL)
+
+	assert.Equal(t, "medium", insights[1].Priority)
+	assert.Equal(t, "1 file lost coverage", insights[1].Title)
+	assert.Equal(t, "`f.go` (-15.0%)", insights[1].Detail)
+
+	assert.Equal(t, "Improve Overall Coverage", insights[2].Title)
+
+	insights = commentInsights(result, "https://owner.github.io/repo/coverage/pr/7/", "https://github.com/owner/repo/pull/7")
+	assert.Equal(t, "https://owner.github.io/repo/coverage/pr/7/", insights[0].URL)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// overflowCommentFile holds the untruncated comment next to the PR report
+const overflowCommentFile = "comment.md"
+
+// overflowSitePath returns the directory, relative to the site root, that the files linked from
+// the PR comment are published in: the directory of the report URL when it is on the site, so
+// they are deployed next to the report, or pr/<number> otherwise. Both the directory they are
+// written to and their URLs derive from it, so the links resolve once the output is deployed.
+func overflowSitePath(cfg *config.Config, reportURL string, prNumber int) string {
+	fallback := path.Join("pr", strconv.Itoa(prNumber))
+	site, err := url.Parse(cfg.SiteBaseURL())
+	if err != nil || site.Host == "" {
+		return fallback
+	}
+	report, err := url.Parse(reportURL)
+	if err != nil || report.Scheme != site.Scheme || report.Host != site.Host {
+		return fallback
+	}
+
+	dir := report.Path
+	if i := strings.LastIndex(dir, "/"); i >= 0 && strings.Contains(dir[i:], ".") {
+		dir = dir[:i]
+	}
+	rel, ok := strings.CutPrefix(path.Clean("/"+dir), path.Clean("/"+site.Path))
+	if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+		return fallback
+	}
+	rel = strings.TrimPrefix(rel, "/")
+	if rel == "" {
+		return "."
+	}
+	if _, err = parser.SafeOutputPath(".", rel); err != nil {
+		return fallback
+	}
+	return rel
+}
+
+// overflowCommentDir returns the directory the files linked from the PR comment are written to
+func overflowCommentDir(cfg *config.Config, reportURL string, prNumber int) string {
+	return filepath.Join(cfg.Coverage.OutputDir, filepath.FromSlash(overflowSitePath(cfg, reportURL, prNumber)))
+}
+
+// overflowFileURL returns the URL of a file written to the overflow directory, or "" without a
+// report URL or a known site URL
+func overflowFileURL(cfg *config.Config, reportURL string, prNumber int, file string) string {
+	base := strings.TrimSuffix(cfg.SiteBaseURL(), "/")
+	if reportURL == "" || base == "" {
+		return ""
+	}
+	sitePath := overflowSitePath(cfg, reportURL, prNumber)
+	if sitePath == "." {
+		return base + "/" + file
+	}
+	return base + "/" + sitePath + "/" + file
+}
+
+// writeOverflowComment stores the untruncated version of a shortened comment in the PR report
+// directory, so the "full report" links of the posted comment resolve once it is deployed
+func writeOverflowComment(cmd *cobra.Command, cfg *config.Config, dir string, rendered *templates.RenderedComment) error {
+	if err := os.MkdirAll(dir, cfg.Storage.DirMode); err != nil {
+		return fmt.Errorf("failed to create overflow directory: %w", err)
+	}
+
+	file := filepath.Join(dir, overflowCommentFile)
+	if err := os.WriteFile(file, []byte(rendered.Full), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write full comment: %w", err)
+	}
+	cmd.Printf("✂️  Comment shortened to fit GitHub's size limit (%s); full version written to %s\n",
+		strings.Join(rendered.Shortened, ", "), file)
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+func TestOverflowLocation(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{OutputDir: filepath.Join("pages", "deploy")},
+		GitHub:   config.GitHubConfig{Owner: "owner", Repository: "repo"},
+	}
+	tests := []struct {
+		reportURL string
+		sitePath  string
+	}{
+		{"https://owner.github.io/repo/reports/pr/1/coverage.html", "reports/pr/1"},
+		{"https://owner.github.io/repo/coverage/branch/feature/", "coverage/branch/feature"},
+		{"https://owner.github.io/repo/coverage/branch/feature", "coverage/branch/feature"},
+		{"https://owner.github.io/repo/", "."},
+		{"https://codecov.io/gh/owner/repo/pull/1", "pr/1"},
+		{"https://owner.github.io/repository/coverage.html", "pr/1"},
+		{"https://owner.github.io/repo/a/../../escape/", "pr/1"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.sitePath, overflowSitePath(cfg, tt.reportURL, 1), tt.reportURL)
+
+		// The link points at the directory the file is written to, relative to the deployed output
+		dir := overflowCommentDir(cfg, tt.reportURL, 1)
+		rel, err := filepath.Rel(cfg.Coverage.OutputDir, filepath.Join(dir, overflowCommentFile))
+		require.NoError(t, err)
+		assert.Equal(t, "https://owner.github.io/repo/"+filepath.ToSlash(rel),
+			overflowFileURL(cfg, tt.reportURL, 1, overflowCommentFile), tt.reportURL)
+	}
+
+	assert.Empty(t, overflowFileURL(cfg, "", 1, overflowCommentFile), "no report to link")
+	assert.Empty(t, overflowFileURL(&config.Config{}, "https://example.com/coverage.html", 1, overflowCommentFile), "no site")
+	assert.Equal(t, filepath.Join("pages", "deploy", "pr", "1"), overflowCommentDir(&config.Config{
+		Coverage: cfg.Coverage,
+	}, "https://example.com/coverage.html", 1))
+}
+
+func TestWriteOverflowComment(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{OutputDir: t.TempDir()},
+		Storage:  config.StorageConfig{DirMode: 0o750, FileMode: 0o600},
+	}
+	dir := overflowCommentDir(cfg, "", 42)
+	assert.Equal(t, filepath.Join(cfg.Coverage.OutputDir, "pr", "42"), dir)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	rendered := &templates.RenderedComment{Body: "short", Full: "full comment", Shortened: []string{"File Changes (300)"}}
+	require.NoError(t, writeOverflowComment(cmd, cfg, dir, rendered))
+
+	data, err := os.ReadFile(filepath.Join(dir, overflowCommentFile)) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Equal(t, "full comment", string(data))
+	assert.Contains(t, out.String(), "File Changes (300)")
+}
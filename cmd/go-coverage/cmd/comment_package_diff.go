@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// packageDiffFile is the image of package coverage before and after the pull request, written
+// next to the PR report
+const packageDiffFile = "coverage-diff.svg"
+
+// packageDiffRows is the number of packages drawn in the package diff image
+const packageDiffRows = 10
+
+// writePackageDiff renders the package coverage changes of a comparison into dir, which is
+// published at imageURL, and returns that URL. It returns "" when the image is disabled, there is
+// no URL to publish it at, or no package moved; in a dry run nothing is written.
+func writePackageDiff(cfg *config.Config, dir, imageURL string, result *analysis.ComparisonResult, dryRun bool) (string, error) {
+	if !cfg.GitHub.CommentDiffImage || imageURL == "" || result == nil {
+		return "", nil
+	}
+	image := analysis.RenderPackageDiffSVG(result.PackageChanges, packageDiffRows)
+	if image == nil {
+		return "", nil
+	}
+
+	if !dryRun {
+		if err := os.MkdirAll(dir, cfg.Storage.DirMode); err != nil {
+			return "", fmt.Errorf("failed to create package diff directory: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, packageDiffFile), image, cfg.Storage.FileMode); err != nil {
+			return "", fmt.Errorf("failed to write package diff image: %w", err)
+		}
+	}
+	return imageURL, nil
+}
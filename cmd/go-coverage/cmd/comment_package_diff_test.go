@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+func TestWritePackageDiff(t *testing.T) {
+	isolateOfflineEnv(t)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "pr", "7")
+	imageURL := "https://owner.github.io/repo/pr/7/coverage-diff.svg"
+	result := &analysis.ComparisonResult{
+		PackageChanges: []analysis.PackageChangeAnalysis{
+			{Package: "internal/parser", BasePercentage: 70, PRPercentage: 75, PercentageChange: 5, Direction: analysis.DirectionImproved},
+		},
+	}
+
+	url, err := writePackageDiff(cfg, dir, imageURL, result, true)
+	require.NoError(t, err)
+	assert.Equal(t, imageURL, url)
+	assert.NoFileExists(t, filepath.Join(dir, packageDiffFile), "a dry run writes nothing")
+
+	url, err = writePackageDiff(cfg, dir, imageURL, result, false)
+	require.NoError(t, err)
+	assert.NotEmpty(t, url)
+	assert.FileExists(t, filepath.Join(dir, packageDiffFile))
+
+	for name, args := range map[string]struct {
+		imageURL string
+		result   *analysis.ComparisonResult
+	}{
+		"no url":        {"", result},
+		"no comparison": {imageURL, nil},
+		"nothing moved": {imageURL, &analysis.ComparisonResult{}},
+	} {
+		url, err = writePackageDiff(cfg, dir, args.imageURL, args.result, false)
+		require.NoError(t, err, name)
+		assert.Empty(t, url, name)
+	}
+
+	cfg.GitHub.CommentDiffImage = false
+	url, err = writePackageDiff(cfg, dir, imageURL, result, false)
+	require.NoError(t, err)
+	assert.Empty(t, url)
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/diagnostics"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+var (
+	// ErrRelayRunRequired indicates that neither a run ID nor a handoff file was given
+	ErrRelayRunRequired = errors.New("a workflow run ID (--run-id or a workflow_run event) or --file is required")
+	// ErrRelayCommitMismatch indicates that the handoff does not belong to the commit the run tested
+	ErrRelayCommitMismatch = errors.New("handoff commit does not match the workflow run")
+	// ErrRelayStalePullRequest indicates that the pull request head moved since the handoff was written
+	ErrRelayStalePullRequest = errors.New("pull request head does not match the handoff commit")
+)
+
+// maxHandoffArtifactSize bounds the size of a downloaded handoff artifact archive
+const maxHandoffArtifactSize = 10 << 20
+
+// newCommentRelayCmd creates the comment relay command
+func (c *Commands) newCommentRelayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relay",
+		Short: "Post a coverage comment handed off by a fork pull request run",
+		Long: `Post the coverage comment and status checks produced by an untrusted pull request
+workflow, typically one running for a fork.
+
+Run it from a workflow triggered by workflow_run, which has a token with write access.
+The coverage-handoff artifact of the triggering run is downloaded and validated: it must
+target this repository, belong to the commit the run tested, and that commit must still be
+the head of the pull request. Use --file to relay a handoff that was downloaded already.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			runID, _ := cmd.Flags().GetInt64("run-id")
+			artifactName, _ := cmd.Flags().GetString("artifact")
+			file, _ := cmd.Flags().GetString("file")
+			createStatus, _ := cmd.Flags().GetBool("status")
+			dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err = requireNetwork(cmd, cfg, "the comment relay command"); err != nil {
+				return err
+			}
+			if cfg.GitHub.Token == "" {
+				return ErrGitHubTokenRequired
+			}
+			if cfg.GitHub.Owner == "" {
+				return ErrGitHubOwnerRequired
+			}
+			if cfg.GitHub.Repository == "" {
+				return ErrGitHubRepoRequired
+			}
+
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+
+			// Without actions: read the artifact download would fail with a 403, so stop before it
+			if file == "" {
+				features := &preflightFeatures{Artifacts: true}
+				preflightGitHub(ctx, cmd, cfg, client, 0, features)
+				if !features.Artifacts {
+					return diagnostics.Wrap(diagnostics.CodeTokenForbidden,
+						fmt.Errorf("%w: actions: read is needed to download the handoff artifact", ErrTokenPermission))
+				}
+			}
+
+			payload, runHeadSHA, err := loadRelayPayload(ctx, cmd, cfg, client, runID, artifactName, file)
+			if err != nil {
+				return err
+			}
+			if err = verifyRelayPayload(ctx, cfg, client, payload, runHeadSHA); err != nil {
+				return err
+			}
+
+			cmd.Printf("📨 Relaying coverage comment for PR #%d (%s/%s)\n", payload.PullRequest, payload.Owner, payload.Repository)
+			if dryRun {
+				cmd.Printf("PR Comment Preview (Dry Run)\n")
+				cmd.Printf("=====================================\n")
+				cmd.Printf("Commit: %s\n", payload.CommitSHA)
+				cmd.Printf("Status checks: %v, statuses: %d\n", createStatus && payload.CreateStatusChecks, len(payload.Statuses))
+				cmd.Printf("=====================================\n")
+				cmd.Println(payload.Comment)
+				cmd.Printf("=====================================\n")
+				return nil
+			}
+
+			return postRelayPayload(ctx, cmd, cfg, client, payload, createStatus)
+		},
+	}
+
+	cmd.Flags().Int64("run-id", 0, "Workflow run that uploaded the handoff artifact (default: the triggering workflow_run)")
+	cmd.Flags().String("artifact", "coverage-handoff", "Name of the handoff artifact")
+	cmd.Flags().String("file", "", "Relay a downloaded handoff file or directory instead of fetching the artifact")
+	cmd.Flags().Bool("status", true, "Create the status checks recorded in the handoff")
+	addDryRunFlag(cmd, "Show what would be posted without actually posting")
+
+	return cmd
+}
+
+// loadRelayPayload reads the handoff from a local file or from the artifact of the workflow run,
+// returning the head commit of the run when it is known
+func loadRelayPayload(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	runID int64, artifactName, file string,
+) (*handoff.Payload, string, error) {
+	var runHeadSHA string
+	if runID == 0 {
+		if eventPath := os.Getenv(envEventPath); eventPath != "" {
+			run, err := github.ReadWorkflowRunEvent(eventPath)
+			if err != nil {
+				return nil, "", err
+			}
+			if run != nil {
+				runID, runHeadSHA = run.ID, run.HeadSHA
+			}
+		}
+	}
+
+	if file != "" {
+		payload, err := handoff.Read(file)
+		return payload, runHeadSHA, err
+	}
+	if runID == 0 {
+		return nil, "", ErrRelayRunRequired
+	}
+
+	if runHeadSHA == "" {
+		run, err := client.GetWorkflowRun(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, runID)
+		if err != nil {
+			return nil, "", err
+		}
+		runHeadSHA = run.HeadSHA
+	}
+
+	artifact, err := client.FindWorkflowRunArtifact(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, runID, artifactName)
+	if err != nil {
+		return nil, "", err
+	}
+	cmd.Printf("⬇️  Downloading artifact %s from run %d\n", artifact.Name, runID)
+	archive, err := client.DownloadArtifact(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, artifact.ID, maxHandoffArtifactSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload, err := handoff.FromZip(archive)
+	return payload, runHeadSHA, err
+}
+
+// verifyRelayPayload ensures an untrusted handoff can only comment on the pull request it was
+// produced for: same repository, the commit the run tested, still the head of that pull request
+func verifyRelayPayload(ctx context.Context, cfg *config.Config, client github.API, payload *handoff.Payload, runHeadSHA string) error {
+	if err := payload.CheckRepository(cfg.GitHub.Owner, cfg.GitHub.Repository); err != nil {
+		return err
+	}
+
+	commitSHA := payload.CommitSHA
+	if runHeadSHA != "" {
+		if commitSHA != "" && commitSHA != runHeadSHA {
+			return fmt.Errorf("%w: %s (run tested %s)", ErrRelayCommitMismatch, commitSHA, runHeadSHA)
+		}
+		commitSHA = runHeadSHA
+	}
+	if commitSHA == "" {
+		return nil
+	}
+
+	pr, err := client.GetPullRequest(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, payload.PullRequest)
+	if err != nil {
+		return err
+	}
+	if pr.Head.SHA != commitSHA {
+		return fmt.Errorf("%w: PR #%d is at %s, handoff is for %s", ErrRelayStalePullRequest, payload.PullRequest, pr.Head.SHA, commitSHA)
+	}
+	return nil
+}
+
+// postRelayPayload posts the handed off comment and the statuses it records
+func postRelayPayload(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	payload *handoff.Payload, createStatus bool,
+) error {
+	comparison := &github.CoverageComparison{}
+	if payload.Coverage != nil {
+		comparison.PRCoverage = github.CoverageData{
+			Percentage:        payload.Coverage.Percentage,
+			TotalStatements:   payload.Coverage.TotalStatements,
+			CoveredStatements: payload.Coverage.CoveredStatements,
+			CommitSHA:         payload.CommitSHA,
+		}
+		comparison.BaseCoverage = github.CoverageData{Percentage: payload.Coverage.BasePercentage}
+		comparison.Difference = payload.Coverage.Difference
+		comparison.TrendAnalysis = github.TrendData{Direction: payload.Coverage.Trend}
+	}
+
+	prCommentManager := github.NewPRCommentManager(client, &github.PRCommentConfig{
+		MinUpdateIntervalMinutes: 0,
+		MaxCommentsPerPR:         1,
+		CommentSignature:         cfg.GitHub.CommentSignature,
+		ResolveMode:              cfg.GitHub.CommentResolve,
+		CelebrateThreshold:       cfg.GitHub.CommentCelebrate,
+	})
+	result, err := prCommentManager.CreateOrUpdatePRComment(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, payload.PullRequest, payload.Comment, comparison)
+	if err != nil {
+		return fmt.Errorf("failed to create PR comment: %w", err)
+	}
+	cmd.Printf("Coverage comment %s successfully!\n", result.Action)
+	printCommentTidy(cmd, result)
+
+	if !createStatus || payload.CommitSHA == "" {
+		return nil
+	}
+	retained := make([]string, 0, len(payload.Statuses))
+	for _, status := range payload.Statuses {
+		retained = append(retained, status.Context)
+		err = client.CreateStatus(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, payload.CommitSHA, &github.StatusRequest{
+			State:       status.State,
+			TargetURL:   status.TargetURL,
+			Description: status.Description,
+			Context:     status.Context,
+		})
+		if err != nil {
+			cmd.Printf("Warning: failed to create %s status: %v\n", status.Context, err)
+		}
+	}
+	if payload.CreateStatusChecks && payload.Coverage != nil {
+		createCoverageStatusChecks(ctx, cmd, cfg, client, payload.PullRequest, payload.CommitSHA, payload.Coverage, retained)
+	}
+	return nil
+}
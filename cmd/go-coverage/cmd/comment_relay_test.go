@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+const relayHeadSHA = "0123456789abcdef0123456789abcdef01234567"
+
+// relayServer fakes the GitHub endpoints used by the relay command
+type relayServer struct {
+	mu       sync.Mutex
+	archive  []byte
+	prHead   string
+	comments []string
+	statuses []string
+}
+
+func (s *relayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Path == "/repos/owner/repo/actions/runs/5":
+		_, _ = w.Write([]byte(`{"id":5,"head_sha":"` + relayHeadSHA + `"}`))
+	case r.URL.Path == "/repos/owner/repo/actions/runs/5/artifacts":
+		_, _ = w.Write([]byte(`{"total_count":2,"artifacts":[{"id":1,"name":"other"},{"id":2,"name":"coverage-handoff"}]}`))
+	case r.URL.Path == "/repos/owner/repo/actions/artifacts/2/zip":
+		_, _ = w.Write(s.archive)
+	case r.URL.Path == "/repos/owner/repo/pulls/7":
+		_, _ = w.Write([]byte(`{"number":7,"head":{"sha":"` + s.prHead + `"}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		_, _ = w.Write([]byte(`[]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		var req github.CommentRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.comments = append(s.comments, req.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/statuses/"+relayHeadSHA:
+		var req github.StatusRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.statuses = append(s.statuses, req.Context)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func zipHandoff(t *testing.T, payload *handoff.Payload) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	path, err := handoff.Write(dir, payload, 0o644)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	require.NoError(t, err)
+	return zipHandoffJSON(t, data)
+}
+
+// zipHandoffJSON zips a payload file as written by a workflow, valid or not
+func zipHandoffJSON(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, err := writer.Create(handoff.FileName)
+	require.NoError(t, err)
+	_, err = entry.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func relayPayload() *handoff.Payload {
+	return &handoff.Payload{
+		Owner:       "owner",
+		Repository:  "repo",
+		PullRequest: 7,
+		CommitSHA:   relayHeadSHA,
+		Comment:     "## Coverage 85%",
+		Statuses:    []handoff.Status{{State: "success", Context: "go-coverage/coverage/total", Description: "ok"}},
+	}
+}
+
+func TestCommentRelay(t *testing.T) {
+	t.Setenv(envEventPath, "")
+	fake := &relayServer{prHead: relayHeadSHA}
+	fake.archive = zipHandoff(t, relayPayload())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+	client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	ctx := context.Background()
+
+	payload, runHeadSHA, err := loadRelayPayload(ctx, cmd, cfg, client, 5, "coverage-handoff", "")
+	require.NoError(t, err)
+	assert.Equal(t, relayHeadSHA, runHeadSHA)
+	require.NoError(t, verifyRelayPayload(ctx, cfg, client, payload, runHeadSHA))
+	require.NoError(t, postRelayPayload(ctx, cmd, cfg, client, payload, true))
+
+	assert.Equal(t, []string{"## Coverage 85%\n\n<!-- go-coverage:comment owner/repo -->\n"}, fake.comments)
+	assert.Equal(t, []string{"go-coverage/coverage/total"}, fake.statuses)
+}
+
+func TestCommentRelayRejectsUntrustedPayloads(t *testing.T) {
+	t.Setenv(envEventPath, "")
+	fake := &relayServer{prHead: relayHeadSHA}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+	client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	other := relayPayload()
+	other.Repository = "elsewhere"
+	require.ErrorIs(t, verifyRelayPayload(ctx, cfg, client, other, relayHeadSHA), handoff.ErrRepositoryMismatch)
+
+	require.ErrorIs(t, verifyRelayPayload(ctx, cfg, client, relayPayload(), "ffffffffffffffffffffffffffffffffffffffff"), ErrRelayCommitMismatch)
+
+	fake.prHead = "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	require.ErrorIs(t, verifyRelayPayload(ctx, cfg, client, relayPayload(), relayHeadSHA), ErrRelayStalePullRequest)
+
+	_, _, err := loadRelayPayload(ctx, &cobra.Command{}, cfg, client, 0, "coverage-handoff", "")
+	require.ErrorIs(t, err, ErrRelayRunRequired)
+
+	// A fork must not set statuses of other required checks on its head commit
+	forged := relayPayload()
+	forged.SchemaVersion = handoff.SchemaVersion
+	forged.Statuses = append(forged.Statuses, handoff.Status{State: "success", Context: "ci/build"})
+	data, err := json.Marshal(forged)
+	require.NoError(t, err)
+	fake.prHead = relayHeadSHA
+	fake.archive = zipHandoffJSON(t, data)
+	_, _, err = loadRelayPayload(ctx, &cobra.Command{}, cfg, client, 5, "coverage-handoff", "")
+	require.ErrorIs(t, err, handoff.ErrInvalidPayload)
+	assert.Empty(t, fake.statuses)
+}
+
+func TestCommentRelayReadsWorkflowRunEvent(t *testing.T) {
+	dir := t.TempDir()
+	eventPath := filepath.Join(dir, "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(`{"workflow_run":{"id":5,"head_sha":"`+relayHeadSHA+`"}}`), 0o600))
+	t.Setenv(envEventPath, eventPath)
+
+	handoffDir := filepath.Join(dir, "handoff")
+	_, err := handoff.Write(handoffDir, relayPayload(), 0o644)
+	require.NoError(t, err)
+
+	payload, runHeadSHA, err := loadRelayPayload(context.Background(), &cobra.Command{}, &config.Config{}, nil, 0, "coverage-handoff", handoffDir)
+	require.NoError(t, err)
+	assert.Equal(t, relayHeadSHA, runHeadSHA)
+	assert.Equal(t, 7, payload.PullRequest)
+}
+
+func TestCommentRelayMarksObsoleteStatuses(t *testing.T) {
+	ctx := context.Background()
+	fake := github.NewFake()
+	fake.PullRequests[7] = &github.PullRequest{Number: 7, Head: github.PullRequestRef{SHA: relayHeadSHA}}
+	for _, context := range []string{"go-coverage/coverage/threshold-old", criticalStatusContext} {
+		require.NoError(t, fake.CreateStatus(ctx, "owner", "repo", relayHeadSHA, &github.StatusRequest{State: "failure", Context: context}))
+	}
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo", CleanupStatuses: true}}
+	payload := relayPayload()
+	payload.Statuses = []handoff.Status{{State: "failure", Context: criticalStatusContext, Description: "1 of 1 critical paths below their required coverage"}}
+	payload.CreateStatusChecks = true
+	payload.Coverage = &handoff.Coverage{Percentage: 85, Threshold: 80}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, postRelayPayload(ctx, cmd, cfg, fake, payload, true))
+	assert.Contains(t, out.String(), "Marked 1 obsolete status checks: [go-coverage/coverage/threshold-old]")
+
+	status, _ := fake.LatestStatus(relayHeadSHA, "go-coverage/coverage/threshold-old")
+	assert.Equal(t, github.ObsoleteStatusDescription, status.Description)
+	status, _ = fake.LatestStatus(relayHeadSHA, criticalStatusContext)
+	assert.Equal(t, "failure", status.State)
+}
@@ -1,15 +1,24 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mrz1836/go-coverage/internal/analysis"
 	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/ci"
 	"github.com/mrz1836/go-coverage/internal/config"
 	"github.com/mrz1836/go-coverage/internal/github"
 	"github.com/mrz1836/go-coverage/internal/parser"
@@ -111,6 +120,7 @@ func TestConvertFileChanges(t *testing.T) {
 		},
 		{
 			Filename:         "helper.go",
+			PreviousFilename: "util.go",
 			BasePercentage:   75.0,
 			PRPercentage:     74.0,
 			PercentageChange: -1.0,
@@ -132,6 +142,8 @@ func TestConvertFileChanges(t *testing.T) {
 	require.True(t, result[0].IsSignificant)
 
 	require.Equal(t, "helper.go", result[1].Filename)
+	require.Equal(t, "util.go", result[1].PreviousFilename)
+	require.Empty(t, result[0].PreviousFilename)
 	require.InDelta(t, 75.0, result[1].BaseCoverage, 0.001)
 	require.InDelta(t, 74.0, result[1].PRCoverage, 0.001)
 	require.InDelta(t, -1.0, result[1].Difference, 0.001)
@@ -189,6 +201,7 @@ func TestBuildTemplateData(t *testing.T) {
 			Repository: "testrepo",
 			CommitSHA:  "abc123def456",
 		},
+		CI: &ci.Context{Branch: "feature", BaseBranch: "master"},
 	}
 
 	prNumber := 123
@@ -235,7 +248,7 @@ func TestBuildTemplateData(t *testing.T) {
 	require.Equal(t, "https://github.com/testowner/testrepo", result.Repository.URL)
 
 	require.Equal(t, prNumber, result.PullRequest.Number)
-	require.Equal(t, "current", result.PullRequest.Branch)
+	require.Equal(t, "feature", result.PullRequest.Branch)
 	require.Equal(t, defaultBranch, result.PullRequest.BaseBranch)
 	require.Equal(t, cfg.GitHub.CommitSHA, result.PullRequest.CommitSHA)
 	require.Equal(t, "https://github.com/testowner/testrepo/pull/123", result.PullRequest.URL)
@@ -888,6 +901,10 @@ func TestNewCommentCmdValidationErrors(t *testing.T) {
 
 // TestNewCommentCmdDryRunMode tests the dry-run functionality
 func TestNewCommentCmdDryRunMode(t *testing.T) {
+	// Keep the history of the run out of the repository
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", t.TempDir())
+
 	// Create a minimal test coverage file
 	tempFile, err := os.CreateTemp("", "coverage_test_*.out")
 	require.NoError(t, err)
@@ -1018,6 +1035,10 @@ func TestNewCommentCmdWithInvalidCoverageFile(t *testing.T) {
 
 // TestNewCommentCmdFlagCombinations tests various flag combinations
 func TestNewCommentCmdFlagCombinations(t *testing.T) {
+	// Keep the history of the run out of the repository
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", t.TempDir())
+
 	// Create test coverage file
 	coverageFile, err := os.CreateTemp("", "coverage_*.out")
 	require.NoError(t, err)
@@ -1118,3 +1139,74 @@ github.com/test/repo/main.go:1.1,5.10 2 1
 		})
 	}
 }
+
+func TestPostNoCodeChangesComment(t *testing.T) {
+	var mu sync.Mutex
+	var commentBody string
+	var statuses []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/pulls/7":
+			_, _ = w.Write([]byte(`{"number":7,"head":{"sha":"abc123"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+			var req github.CommentRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			commentBody = req.Body
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/statuses/abc123":
+			var req github.StatusRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, github.StatusStateSuccess, req.State)
+			statuses = append(statuses, req.Context)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	analysisResult := github.AnalyzePRFiles(&github.PRDiff{Files: []github.PRFile{
+		{Filename: "README.md", Status: "modified"},
+		{Filename: "docs/guide.md", Status: "added"},
+	}})
+	require.False(t, analysisResult.HasCodeChanges())
+
+	run := func(t *testing.T, mode string) string {
+		t.Helper()
+		commentBody, statuses = "", nil
+		cfg := &config.Config{
+			GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo", CommitSHA: "abc123"},
+			Policy: config.PolicyConfig{NoCodeChanges: mode},
+		}
+		client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+		cmd := &cobra.Command{}
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		err := postNoCodeChangesComment(context.Background(), cmd, cfg, client, 7, analysisResult,
+			"https://example.com/badge.svg", "https://example.com/report.html", true, false, false)
+		require.NoError(t, err)
+		return out.String()
+	}
+
+	t.Run("success", func(t *testing.T) {
+		output := run(t, config.NoCodeChangesSuccess)
+		assert.Contains(t, output, "No code changes")
+		assert.Contains(t, commentBody, "No code changes — coverage unaffected")
+		assert.Contains(t, commentBody, "2 documentation files")
+		assert.Contains(t, commentBody, "https://example.com/badge.svg")
+		assert.ElementsMatch(t, []string{"go-coverage/coverage/total", "Go-Coverage/Coverage-PR"}, statuses)
+	})
+
+	t.Run("comment only", func(t *testing.T) {
+		run(t, config.NoCodeChangesComment)
+		assert.Contains(t, commentBody, "No code changes")
+		assert.Empty(t, statuses)
+	})
+}
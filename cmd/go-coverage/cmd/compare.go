@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/schema"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// Compare command errors
+var (
+	ErrCompareBaseRequired      = errors.New("--base is required")
+	ErrCompareBaseNotFound      = errors.New("no coverage found for base ref")
+	ErrUnsupportedCompareFormat = errors.New("unsupported compare format")
+)
+
+const (
+	compareFormatMarkdown = "markdown"
+	compareFormatJSON     = "json"
+
+	// compareSourceProfile, compareSourceHistory and compareSourceArtifact describe where the base
+	// coverage came from
+	compareSourceProfile  = "profile"
+	compareSourceHistory  = "history"
+	compareSourceArtifact = "artifact"
+)
+
+// compareSide describes one side of a comparison
+type compareSide struct {
+	Ref               string  `json:"ref,omitempty"`
+	Source            string  `json:"source"`
+	Profile           string  `json:"profile,omitempty"`
+	Branch            string  `json:"branch,omitempty"`
+	CommitSHA         string  `json:"commit_sha,omitempty"`
+	Coverage          float64 `json:"coverage"`
+	CoveredStatements int     `json:"covered_statements"`
+	TotalStatements   int     `json:"total_statements"`
+}
+
+// compareReportSchema versions the JSON output of the compare command. Version 1 added the
+// schema version to the unversioned format.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var compareReportSchema = schema.Schema{
+	Name:       "comparison",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// compareReport is the JSON output of the compare command
+type compareReport struct {
+	SchemaVersion     int                              `json:"schema_version"`
+	Base              compareSide                      `json:"base"`
+	Head              compareSide                      `json:"head"`
+	OverallChange     analysis.OverallChangeAnalysis   `json:"overall_change"`
+	PackageChanges    []analysis.PackageChangeAnalysis `json:"package_changes"`
+	FileChanges       []analysis.FileChangeAnalysis    `json:"file_changes"`
+	QualityAssessment analysis.QualityAssessment       `json:"quality_assessment"`
+	Recommendations   []analysis.Recommendation        `json:"recommendations,omitempty"`
+	Summary           analysis.ComparisonSummary       `json:"summary"`
+	GeneratedAt       time.Time                        `json:"generated_at"`
+}
+
+// newCompareCmd creates the compare command
+func (c *Commands) newCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare coverage against a commit, tag or branch",
+		Long: `Compare a coverage profile against the coverage recorded for any ref.
+
+The base may be a commit SHA (full or abbreviated), a tag, a branch name or the
+path to a coverage profile. Refs are resolved with git when possible and looked
+up in the coverage history; branches resolve to their most recent entry. When the
+history has no entry for a commit, the profile is downloaded from the --artifact of
+the commit's newest successful workflow run (needs a GitHub token, skipped offline).
+
+Useful for release readiness checks outside the pull request flow, e.g.:
+  go-coverage compare --base v1.4.0 --head coverage.txt --output coverage-report.md
+
+Markdown output is a standalone report rendered from the PR comment template, for release
+checklists, wikis or code review tools outside GitHub. The format follows the --output
+extension (.json writes JSON) unless --format is given.`,
+		RunE: c.runCompare,
+	}
+
+	cmd.Flags().String("base", "", "Base commit SHA, tag, branch or coverage profile path")
+	cmd.Flags().String("head", "", "Head coverage profile (defaults to the configured input file)")
+	cmd.Flags().String("format", compareFormatMarkdown, "Output format (markdown or json)")
+	cmd.Flags().StringP("output", "o", "", "Write the comparison to a file instead of the console (a .json file implies --format json)")
+	cmd.Flags().String("artifact", defaultCompareArtifact, "Workflow artifact with the base profile when history has no entry (empty to skip)")
+
+	return cmd
+}
+
+// runCompare executes the compare command
+func (c *Commands) runCompare(cmd *cobra.Command, _ []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	headFile, _ := cmd.Flags().GetString("head")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+	artifactName, _ := cmd.Flags().GetString("artifact")
+
+	if base == "" {
+		return ErrCompareBaseRequired
+	}
+	if err := checkOutputPath(outputPath, compareFormatMarkdown, compareFormatJSON); err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("format") && strings.EqualFold(filepath.Ext(outputPath), ".json") {
+		format = compareFormatJSON
+	}
+	if format != compareFormatMarkdown && format != compareFormatJSON {
+		return fmt.Errorf("%w: %q (expected markdown or json)", ErrUnsupportedCompareFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if headFile == "" {
+		headFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	p := parser.NewWithConfig(&parser.Config{
+		ExcludePaths:     cfg.Coverage.ExcludePaths,
+		ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+		ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+		ExcludePresets:   cfg.Coverage.ExcludePresets,
+		Limits:           cfg.ParserLimits(),
+		ModuleRewrites:   cfg.ModuleRewrites(),
+		ModuleRoot:       cfg.Coverage.ModuleRoot,
+	})
+
+	headCoverage, err := p.ParseFile(ctx, headFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse head coverage: %w", err)
+	}
+	head := compareSide{
+		Source:    compareSourceProfile,
+		Profile:   headFile,
+		Branch:    cfg.GetCurrentBranch(),
+		CommitSHA: cfg.GitHub.CommitSHA,
+	}
+
+	tracker := history.NewWithConfig(&history.Config{
+		StoragePath: historyStoragePath(cfg),
+		Repository:  cfg.RepositorySlug(),
+		MaxEntries:  cfg.History.MaxEntries,
+	})
+	var artifacts *compareArtifactSource
+	if artifactName != "" && !applyOfflineMode(cmd, cfg) &&
+		cfg.GitHub.Token != "" && cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
+		client, clientErr := newGitHubClient(cfg, "go-coverage/2.0")
+		if clientErr != nil {
+			return clientErr
+		}
+		artifacts = &compareArtifactSource{
+			client:  client,
+			owner:   cfg.GitHub.Owner,
+			repo:    cfg.GitHub.Repository,
+			name:    artifactName,
+			profile: filepath.Base(cfg.Coverage.InputFile),
+			maxSize: cfg.ParserLimits().MaxFileSize,
+		}
+	}
+	baseCoverage, baseSide, err := resolveBaseCoverage(ctx, p, tracker, artifacts, base)
+	if err != nil {
+		return err
+	}
+
+	engine := analysis.NewComparisonEngine(nil)
+	result, err := engine.CompareCoverage(ctx,
+		newComparisonSnapshot(baseCoverage, baseSide.Branch, baseSide.CommitSHA),
+		newComparisonSnapshot(headCoverage, head.Branch, head.CommitSHA))
+	if err != nil {
+		return fmt.Errorf("failed to compare coverage: %w", err)
+	}
+
+	report := newCompareReport(baseSide, head, baseCoverage, headCoverage, result)
+
+	var output string
+	if format == compareFormatJSON {
+		data, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal comparison: %w", marshalErr)
+		}
+		output = string(data) + "\n"
+	} else if output, err = renderCompareMarkdown(ctx, cfg, report, result); err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+		return nil
+	}
+	if err := os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write comparison: %w", err)
+	}
+	cmd.Printf("Comparison written to %s\n", outputPath)
+	return nil
+}
+
+// resolveBaseCoverage loads the base coverage from a profile path, from the history entry for the
+// ref or, when artifacts is set and the ref is a commit, from the profile artifact of its workflow run
+func resolveBaseCoverage(ctx context.Context, p *parser.Parser, tracker *history.Tracker, artifacts *compareArtifactSource,
+	base string,
+) (*parser.CoverageData, compareSide, error) {
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		coverage, err := p.ParseFile(ctx, base)
+		if err != nil {
+			return nil, compareSide{}, fmt.Errorf("failed to parse base coverage: %w", err)
+		}
+		return coverage, compareSide{Ref: base, Source: compareSourceProfile, Profile: base}, nil
+	}
+
+	refs := []string{base}
+	sha := resolveGitRef(ctx, base)
+	if sha != "" && sha != base {
+		refs = append(refs, sha)
+	}
+
+	entry, err := tracker.FindEntry(ctx, refs...)
+	if err != nil {
+		if !errors.Is(err, history.ErrNoEntriesFound) {
+			return nil, compareSide{}, err
+		}
+		if sha == "" && commitSHAPattern.MatchString(base) {
+			sha = base
+		}
+		if artifacts != nil && sha != "" {
+			coverage, run, artifactErr := artifacts.findCoverage(ctx, p, sha)
+			if artifactErr != nil {
+				return nil, compareSide{}, fmt.Errorf("failed to load base coverage from workflow artifacts: %w", artifactErr)
+			}
+			if coverage != nil {
+				return coverage, compareSide{
+					Ref:       base,
+					Source:    compareSourceArtifact,
+					Branch:    run.HeadBranch,
+					CommitSHA: run.HeadSHA,
+				}, nil
+			}
+		}
+		return nil, compareSide{}, fmt.Errorf("%w %q: record it with 'history --add', upload it as a workflow artifact or pass a coverage profile",
+			ErrCompareBaseNotFound, base)
+	}
+	if entry.Coverage == nil {
+		return nil, compareSide{}, fmt.Errorf("%w %q: history entry has no coverage data", ErrCompareBaseNotFound, base)
+	}
+
+	return entry.Coverage, compareSide{
+		Ref:       base,
+		Source:    compareSourceHistory,
+		Branch:    entry.Branch,
+		CommitSHA: entry.CommitSHA,
+	}, nil
+}
+
+// resolveGitRef returns the commit SHA a tag or branch points to, or "" when git cannot resolve it
+func resolveGitRef(ctx context.Context, ref string) string {
+	if strings.HasPrefix(ref, "-") {
+		return ""
+	}
+	output, err := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output() //nolint:gosec // ref is passed as a single argument and cannot be an option
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// newComparisonSnapshot converts parsed coverage into a snapshot with package and file metrics
+func newComparisonSnapshot(coverage *parser.CoverageData, branch, commitSHA string) *analysis.CoverageSnapshot {
+	snapshot := convertToSnapshot(coverage, branch, commitSHA)
+	for name, pkg := range coverage.Packages {
+		snapshot.PackageCoverage[name] = analysis.PackageMetrics{
+			Package:           name,
+			Percentage:        pkg.Percentage,
+			TotalStatements:   pkg.TotalLines,
+			CoveredStatements: pkg.CoveredLines,
+			FileCount:         len(pkg.Files),
+		}
+		for filename, file := range pkg.Files {
+			snapshot.FileCoverage[filename] = analysis.FileMetrics{
+				Filename:          filename,
+				Package:           name,
+				Percentage:        file.Percentage,
+				TotalStatements:   file.TotalLines,
+				CoveredStatements: file.CoveredLines,
+			}
+		}
+	}
+	return snapshot
+}
+
+// newCompareReport assembles the report, listing the largest changes first
+func newCompareReport(base, head compareSide, baseCoverage, headCoverage *parser.CoverageData, result *analysis.ComparisonResult) *compareReport {
+	base.Coverage, base.CoveredStatements, base.TotalStatements = baseCoverage.Percentage, baseCoverage.CoveredLines, baseCoverage.TotalLines
+	head.Coverage, head.CoveredStatements, head.TotalStatements = headCoverage.Percentage, headCoverage.CoveredLines, headCoverage.TotalLines
+
+	packages := make([]analysis.PackageChangeAnalysis, 0, len(result.PackageChanges))
+	for _, change := range result.PackageChanges {
+		if change.PercentageChange != 0 {
+			packages = append(packages, change)
+		}
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		return math.Abs(packages[i].PercentageChange) > math.Abs(packages[j].PercentageChange)
+	})
+
+	files := make([]analysis.FileChangeAnalysis, 0, len(result.FileChanges))
+	for _, change := range result.FileChanges {
+		if change.PercentageChange != 0 || change.IsNewFile || change.IsDeleted || change.IsRenamed {
+			files = append(files, change)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return math.Abs(files[i].PercentageChange) > math.Abs(files[j].PercentageChange)
+	})
+
+	return &compareReport{
+		SchemaVersion:     compareReportSchema.Version(),
+		Base:              base,
+		Head:              head,
+		OverallChange:     result.OverallChange,
+		PackageChanges:    packages,
+		FileChanges:       files,
+		QualityAssessment: result.QualityAssessment,
+		Recommendations:   result.Recommendations,
+		Summary:           result.Summary,
+		GeneratedAt:       time.Now().UTC(),
+	}
+}
+
+// renderCompareMarkdown renders the comparison through the PR comment template, without the
+// comment size budget, so the standalone report has the content of the PR comment
+func renderCompareMarkdown(ctx context.Context, cfg *config.Config, report *compareReport, result *analysis.ComparisonResult) (string, error) {
+	templateConfig := commentTemplateConfig(cfg, 0)
+	templateConfig.UseCollapsibleSections = false // plain Markdown renders outside GitHub as well
+	rendered, err := templates.NewPRTemplateEngine(templateConfig).RenderBudgetedComment(ctx, compareTemplateData(cfg, report, result))
+	if err != nil {
+		return "", fmt.Errorf("failed to render comparison: %w", err)
+	}
+	return rendered.Full, nil
+}
+
+// compareTemplateData converts the comparison into the data the PR comment is rendered from
+func compareTemplateData(cfg *config.Config, report *compareReport, result *analysis.ComparisonResult) *templates.TemplateData {
+	comparison := &github.CoverageComparison{
+		BaseCoverage: github.CoverageData{
+			Percentage:        report.Base.Coverage,
+			TotalStatements:   report.Base.TotalStatements,
+			CoveredStatements: report.Base.CoveredStatements,
+			CommitSHA:         report.Base.CommitSHA,
+			Branch:            report.Base.Branch,
+		},
+		PRCoverage: github.CoverageData{
+			Percentage:        report.Head.Coverage,
+			TotalStatements:   report.Head.TotalStatements,
+			CoveredStatements: report.Head.CoveredStatements,
+			CommitSHA:         report.Head.CommitSHA,
+			Branch:            report.Head.Branch,
+		},
+		Difference:       report.OverallChange.PercentageChange,
+		TrendAnalysis:    convertTrendData(result.TrendAnalysis),
+		FileChanges:      convertFileChanges(result.FileChanges),
+		SignificantFiles: extractSignificantFiles(result.FileChanges),
+	}
+
+	data := buildTemplateData(cfg, 0, comparison, nil, "", "")
+	data.PullRequest = templates.PullRequestInfo{
+		Branch:     describeCompareSide(report.Head),
+		BaseBranch: describeCompareSide(report.Base),
+		CommitSHA:  report.Head.CommitSHA,
+	}
+	if cfg.GitHub.Owner == "" || cfg.GitHub.Repository == "" {
+		data.Repository.URL = ""
+		data.Resources = templates.ResourceLinks{}
+	}
+	data.Timestamp = report.GeneratedAt
+	data.Insights = commentInsights(result, "", "")
+	return data
+}
+
+// describeCompareSide returns a short label for a comparison side
+func describeCompareSide(side compareSide) string {
+	switch {
+	case side.Ref != "":
+		return side.Ref
+	case side.CommitSHA != "":
+		return shortSHA(side.CommitSHA)
+	case side.Branch != "":
+		return side.Branch
+	default:
+		return side.Profile
+	}
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// defaultCompareArtifact is the artifact the coverage workflow uploads the profile in
+const defaultCompareArtifact = "coverage-data"
+
+// commitSHAPattern matches full and abbreviated commit SHAs
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// compareArtifactSource finds base coverage in the profile artifact of a commit's workflow runs
+type compareArtifactSource struct {
+	client  github.ArtifactAPI
+	owner   string
+	repo    string
+	name    string
+	profile string
+	maxSize int64
+}
+
+// findCoverage downloads the profile of the newest successful run of the commit that uploaded
+// the artifact, returning the run and nil coverage when no run has it
+func (s *compareArtifactSource) findCoverage(ctx context.Context, p *parser.Parser, sha string) (*parser.CoverageData, *github.WorkflowRun, error) {
+	runs, err := s.client.ListCommitWorkflowRuns(ctx, s.owner, s.repo, sha)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range runs {
+		artifact, err := s.client.FindWorkflowRunArtifact(ctx, s.owner, s.repo, runs[i].ID, s.name)
+		if errors.Is(err, github.ErrArtifactNotFound) || errors.Is(err, github.ErrArtifactExpired) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		archive, err := s.client.DownloadArtifact(ctx, s.owner, s.repo, artifact.ID, s.maxSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		profile, err := profileFromZip(archive, s.profile, s.maxSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("artifact %s of run %d: %w", s.name, runs[i].ID, err)
+		}
+		coverage, err := p.Parse(ctx, bytes.NewReader(profile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse base coverage from artifact %s: %w", s.name, err)
+		}
+		return coverage, &runs[i], nil
+	}
+
+	return nil, nil, nil
+}
+
+// profileFromZip extracts the named coverage profile from an artifact archive, refusing
+// profiles larger than maxSize bytes
+func profileFromZip(archive []byte, name string, maxSize int64) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if filepath.Base(filepath.FromSlash(file.Name)) != name {
+			continue
+		}
+		if maxSize > 0 && file.UncompressedSize64 > uint64(maxSize) {
+			return nil, fmt.Errorf("%w: %s", github.ErrArtifactTooLarge, file.Name)
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in artifact: %w", file.Name, err)
+		}
+		var buf bytes.Buffer
+		var src io.Reader = rc
+		if maxSize > 0 {
+			src = io.LimitReader(rc, maxSize)
+		}
+		_, err = buf.ReadFrom(src)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in artifact: %w", file.Name, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", github.ErrArtifactNotFound, name)
+}
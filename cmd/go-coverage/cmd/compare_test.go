@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+const (
+	compareBaseProfile = `mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 3 0
+`
+	compareHeadProfile = `mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 3 1
+github.com/example/repo/api/api.go:5.2,6.10 5 0
+`
+)
+
+// setupCompare writes base and head profiles and isolates configuration for the compare command
+func setupCompare(t *testing.T) (baseFile, headFile, historyDir string) {
+	t.Helper()
+	isolateOfflineEnv(t)
+
+	dir := t.TempDir()
+	baseFile = filepath.Join(dir, "base.txt")
+	headFile = filepath.Join(dir, "head.txt")
+	historyDir = filepath.Join(dir, "history")
+	require.NoError(t, os.WriteFile(baseFile, []byte(compareBaseProfile), 0o600))
+	require.NoError(t, os.WriteFile(headFile, []byte(compareHeadProfile), 0o600))
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", historyDir)
+	return baseFile, headFile, historyDir
+}
+
+func TestCompareCommandValidation(t *testing.T) {
+	_, headFile, _ := setupCompare(t)
+
+	_, err := executeCommand(t, "compare", "--head", headFile)
+	require.ErrorIs(t, err, ErrCompareBaseRequired)
+
+	_, err = executeCommand(t, "compare", "--base", "v1.0.0", "--head", headFile, "--format", "xml")
+	require.ErrorIs(t, err, ErrUnsupportedCompareFormat)
+
+	// A format name given to --output is rejected instead of written to a file by that name
+	_, err = executeCommand(t, "compare", "--base", "v1.0.0", "--head", headFile, "--output", "markdown")
+	require.ErrorIs(t, err, ErrOutputIsFormat)
+	require.ErrorContains(t, err, "--format markdown")
+	assert.NoFileExists(t, "markdown")
+}
+
+func TestCompareCommandAgainstProfile(t *testing.T) {
+	baseFile, headFile, _ := setupCompare(t)
+
+	output, err := executeCommand(t, "compare", "--base", baseFile, "--head", headFile)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "# Code Coverage Analysis")
+	assert.Contains(t, output, "| **Statements** | 5/10 |")
+	assert.Contains(t, output, "## Insights")
+	assert.Contains(t, output, "`repo/api/api.go` (0.0%)")
+}
+
+func TestCompareCommandAgainstHistory(t *testing.T) {
+	baseFile, headFile, historyDir := setupCompare(t)
+
+	// Record the base profile as a release commit
+	baseCoverage, err := parser.New().ParseFile(context.Background(), baseFile)
+	require.NoError(t, err)
+	tracker := history.NewWithConfig(&history.Config{StoragePath: historyDir, MaxEntries: 10})
+	require.NoError(t, tracker.Record(context.Background(), baseCoverage,
+		history.WithBranch("release"), history.WithCommit("a1b2c3d4e5f60718293a", "")))
+
+	outputFile := filepath.Join(t.TempDir(), "compare.json")
+	_, err = executeCommand(t, "compare", "--base", "a1b2c3d", "--head", headFile, "--format", formatJSON, "--output", outputFile)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile) //nolint:gosec // test file path
+	require.NoError(t, err)
+
+	var report compareReport
+	require.NoError(t, compareReportSchema.Decode(data, &report))
+	assert.Equal(t, compareReportSchema.Version(), report.SchemaVersion)
+	assert.Equal(t, compareSourceHistory, report.Base.Source)
+	assert.Equal(t, "a1b2c3d4e5f60718293a", report.Base.CommitSHA)
+	assert.Equal(t, "release", report.Base.Branch)
+	assert.InDelta(t, 40.0, report.Base.Coverage, 0.01)
+	assert.InDelta(t, 50.0, report.Head.Coverage, 0.01)
+	assert.InDelta(t, 10.0, report.OverallChange.PercentageChange, 0.01)
+	assert.Equal(t, analysis.DirectionImproved, report.OverallChange.Direction)
+	assert.NotEmpty(t, report.PackageChanges)
+
+	// Branch names resolve to the newest entry for the branch
+	_, err = executeCommand(t, "compare", "--base", "release", "--head", headFile)
+	require.NoError(t, err)
+}
+
+func TestCompareCommandMarkdownArtifact(t *testing.T) {
+	baseFile, headFile, _ := setupCompare(t)
+	dir := t.TempDir()
+
+	markdownFile := filepath.Join(dir, "coverage-report.md")
+	_, err := executeCommand(t, "compare", "--base", baseFile, "--head", headFile, "--output", markdownFile)
+	require.NoError(t, err)
+	data, err := os.ReadFile(markdownFile) //nolint:gosec // test file path
+	require.NoError(t, err)
+	output := string(data)
+	assert.Contains(t, output, "Coverage **improved** by")
+	assert.Contains(t, output, "*Generated via [go-coverage]")
+
+	// A .json output file implies the JSON format
+	jsonFile := filepath.Join(dir, "compare.json")
+	_, err = executeCommand(t, "compare", "--base", baseFile, "--head", headFile, "--output", jsonFile)
+	require.NoError(t, err)
+	data, err = os.ReadFile(jsonFile) //nolint:gosec // test file path
+	require.NoError(t, err)
+	var report compareReport
+	require.NoError(t, compareReportSchema.Decode(data, &report))
+	assert.InDelta(t, 50.0, report.Head.Coverage, 0.01)
+}
+
+func TestCompareCommandUnknownRef(t *testing.T) {
+	_, headFile, _ := setupCompare(t)
+
+	_, err := executeCommand(t, "compare", "--base", "v0.0.0-missing", "--head", headFile)
+	require.ErrorIs(t, err, ErrCompareBaseNotFound)
+}
+
+func TestResolveBaseCoverageFromArtifact(t *testing.T) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	entry, err := writer.Create("coverage.txt")
+	require.NoError(t, err)
+	_, err = entry.Write([]byte(compareBaseProfile))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	fake := github.NewFake()
+	fake.WorkflowRuns[4] = &github.WorkflowRun{ID: 4, HeadSHA: relayHeadSHA, HeadBranch: "master", Conclusion: "success"}
+	fake.WorkflowRuns[5] = &github.WorkflowRun{ID: 5, HeadSHA: relayHeadSHA, Conclusion: "success"}
+	fake.Artifacts[4] = []github.Artifact{{ID: 2, Name: defaultCompareArtifact}}
+	fake.ArtifactData[2] = archive.Bytes()
+
+	tracker := history.NewWithConfig(&history.Config{StoragePath: t.TempDir()})
+	artifacts := &compareArtifactSource{client: fake, owner: "owner", repo: "repo", name: defaultCompareArtifact, profile: "coverage.txt"}
+	ctx := context.Background()
+
+	coverage, side, err := resolveBaseCoverage(ctx, parser.New(), tracker, artifacts, relayHeadSHA)
+	require.NoError(t, err)
+	assert.Equal(t, 5, coverage.TotalLines)
+	assert.Equal(t, compareSourceArtifact, side.Source)
+	assert.Equal(t, "master", side.Branch, "run 5 has no artifact, run 4 does")
+	assert.Equal(t, relayHeadSHA, side.CommitSHA)
+
+	_, _, err = resolveBaseCoverage(ctx, parser.New(), tracker, artifacts, "fedcba9")
+	require.ErrorIs(t, err, ErrCompareBaseNotFound, "no run of the commit has the artifact")
+	_, _, err = resolveBaseCoverage(ctx, parser.New(), tracker, nil, relayHeadSHA)
+	require.ErrorIs(t, err, ErrCompareBaseNotFound, "artifact lookup disabled")
+
+	artifacts.profile = "other.txt"
+	_, _, err = resolveBaseCoverage(ctx, parser.New(), tracker, artifacts, relayHeadSHA)
+	require.ErrorIs(t, err, github.ErrArtifactNotFound)
+}
+
+func TestRenderCompareMarkdownMatchesComment(t *testing.T) {
+	isolateOfflineEnv(t)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	result := &analysis.ComparisonResult{
+		TrendAnalysis: analysis.TrendAnalysis{Direction: analysis.DirectionDegraded},
+		FileChanges: []analysis.FileChangeAnalysis{
+			{Filename: "pkg/new.go", IsNewFile: true, PRPercentage: 10},
+			{Filename: "pkg/old.go", PercentageChange: -30, BasePercentage: 90, PRPercentage: 60, IsSignificant: true, Direction: analysis.DirectionDegraded},
+		},
+	}
+	report := &compareReport{
+		Base:          compareSide{Ref: "v1.0.0", Coverage: 80, CoveredStatements: 80, TotalStatements: 100},
+		Head:          compareSide{Profile: "coverage.txt", Coverage: 70, CoveredStatements: 70, TotalStatements: 100},
+		OverallChange: analysis.OverallChangeAnalysis{PercentageChange: -10, Direction: analysis.DirectionDegraded},
+	}
+
+	output, err := renderCompareMarkdown(context.Background(), cfg, report, result)
+	require.NoError(t, err)
+
+	// The report has the sections of the PR comment rendered from the same data
+	data := compareTemplateData(cfg, report, result)
+	comment, err := templates.NewPRTemplateEngine(commentTemplateConfig(cfg, 0)).RenderBudgetedComment(context.Background(), data)
+	require.NoError(t, err)
+	for _, section := range []string{"# Code Coverage Analysis", "## Coverage Metrics", "## Insights", "`pkg/new.go` (10.0%)", "`pkg/old.go`"} {
+		assert.Contains(t, comment.Full, section)
+		assert.Contains(t, output, section)
+	}
+	assert.Contains(t, output, "Coverage **decreased** by")
+	assert.NotContains(t, output, "<details>")
+}
@@ -16,13 +16,21 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
 	"github.com/mrz1836/go-coverage/internal/analytics/report"
 	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/checkpoint"
+	"github.com/mrz1836/go-coverage/internal/ci"
 	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/debt"
+	"github.com/mrz1836/go-coverage/internal/examples"
 	"github.com/mrz1836/go-coverage/internal/github"
 	"github.com/mrz1836/go-coverage/internal/history"
 	"github.com/mrz1836/go-coverage/internal/parser"
-	"github.com/mrz1836/go-coverage/internal/urlutil"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+	"github.com/mrz1836/go-coverage/internal/snapshot"
+	"github.com/mrz1836/go-coverage/internal/testrun"
 )
 
 // getMainBranches returns the list of main branches from environment variable or default
@@ -55,15 +63,19 @@ func getPrimaryMainBranch() string {
 	return "master"
 }
 
-// getDefaultBranch returns the default branch name, checking environment variables first
+// getDefaultBranch returns the branch of the current run (the source branch of a pull
+// request, never its "<number>/merge" ref), falling back to the default branch
 func getDefaultBranch() string {
-	if branch := os.Getenv("GITHUB_REF_NAME"); branch != "" {
+	if branch := ci.FromEnv().Branch; branch != "" {
 		return branch
 	}
 	// Default to master (this repository's default branch)
 	return history.DefaultBranch
 }
 
+// flagNameLocal writes every output into the output directory without network access
+const flagNameLocal = "local"
+
 // ErrCoverageBelowThreshold indicates that coverage percentage is below the configured threshold
 var ErrCoverageBelowThreshold = errors.New("coverage is below threshold")
 
@@ -78,12 +90,19 @@ func (c *Commands) newCompleteCmd() *cobra.Command {
 		Long: `Run the complete coverage pipeline: parse coverage, generate badge and report,
 update history, and create GitHub PR comment if in PR context.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			startedAt := time.Now()
+
 			// Get flags
-			inputFile, _ := cmd.Flags().GetString("input")
+			inputFile := getCoverageInputFlag(cmd)
 			outputDir, _ := cmd.Flags().GetString("output")
 			skipHistory, _ := cmd.Flags().GetBool("skip-history")
 			skipGitHub, _ := cmd.Flags().GetBool("skip-github")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			variantArgs, _ := cmd.Flags().GetStringArray(flagNameVariant)
+			extraInputs, _ := cmd.Flags().GetStringArray(flagNameExtraInput)
+			editorFormats, _ := cmd.Flags().GetStringSlice(flagNameEditor)
+			local, _ := cmd.Flags().GetBool(flagNameLocal)
+			resume, _ := cmd.Flags().GetBool(flagNameResume)
 
 			// Load configuration
 			cfg, err := config.Load()
@@ -95,63 +114,294 @@ update history, and create GitHub PR comment if in PR context.`,
 			if inputFile == "" {
 				inputFile = cfg.Coverage.InputFile
 			}
+			if inputFile, err = fixtureInput(cfg, inputFile); err != nil {
+				return err
+			}
 			if outputDir == "" {
 				outputDir = cfg.Coverage.OutputDir
 			}
+			if len(variantArgs) == 0 {
+				variantArgs = cfg.Coverage.Variants
+			}
+			if len(extraInputs) == 0 {
+				extraInputs = cfg.Coverage.ExtraInputs
+			}
+			if len(editorFormats) > 0 {
+				cfg.Editor.Formats = editorFormats
+			}
+			applyTestResultsFlag(cmd, cfg)
+			applyFailWithoutTestsFlag(cmd, cfg)
+
+			// Local mode keeps everything on the build agent, so nothing is posted to GitHub
+			if local {
+				cfg.Report.Local = true
+			}
+			if cfg.Report.Local {
+				cfg.Network.Offline = true
+				cfg.GitHub.PostComments, cfg.GitHub.CreateStatuses = false, false
+			}
 
 			// Validate configuration
 			if err = cfg.Validate(); err != nil {
 				return fmt.Errorf("configuration validation failed: %w", err)
 			}
+			offline := applyOfflineMode(cmd, cfg)
+
+			// Published JSON is scrubbed of tokens and configured patterns before writing
+			redactor, err := cfg.NewRedactor()
+			if err != nil {
+				return fmt.Errorf("failed to configure redaction: %w", err)
+			}
 
 			cmd.Printf("Starting Go Coverage Pipeline\n")
 			cmd.Printf("====================================\n")
-			cmd.Printf("Input: %s\n", inputFile)
+			if len(variantArgs) > 0 {
+				cmd.Printf("Input: %s (build tag variants)\n", strings.Join(variantArgs, ", "))
+			} else {
+				cmd.Printf("Input: %s\n", inputFile)
+			}
+			if len(extraInputs) > 0 {
+				cmd.Printf("Extra Inputs: %s\n", strings.Join(extraInputs, ", "))
+			}
 			cmd.Printf("Output Directory: %s\n", outputDir)
+			printOrgPolicy(cmd, cfg)
+			printBranchRule(cmd, cfg)
+			printLabelOverrides(cmd, cfg)
 			if dryRun {
 				cmd.Printf("Mode: DRY RUN\n")
 			}
+			if cfg.Report.Local {
+				cmd.Printf("Mode: LOCAL (all outputs written to %s, network access disabled)\n", outputDir)
+			} else if offline {
+				cmd.Printf("Mode: OFFLINE (network access disabled)\n")
+			}
+			if run := runContext(cfg); run.BuildURL != "" {
+				cmd.Printf("CI Build: %s (%s)\n", run.BuildURL, run.Provider)
+			}
 			cmd.Printf("\n")
 
+			// Find out whether the token may set the commit status before the pipeline runs
+			if !offline && !dryRun && !skipGitHub && cfg.IsGitHubContext() && cfg.GitHub.Token != "" &&
+				cfg.GitHub.CreateStatuses && cfg.GitHub.CommitSHA != "" {
+				if client, clientErr := newGitHubClient(cfg, "go-coverage/1.0"); clientErr == nil {
+					preflightCtx, preflightCancel := context.WithTimeout(context.Background(), cfg.GitHub.Timeout)
+					features := &preflightFeatures{Statuses: true}
+					preflightGitHub(preflightCtx, cmd, cfg, client, 0, features)
+					preflightCancel()
+					cfg.GitHub.CreateStatuses = features.Statuses
+				}
+			}
+
 			// Step 1: Parse coverage data
 			cmd.Printf("🔍 Step 1: Parsing coverage data...\n")
 			parserConfig := &parser.Config{
-				ExcludePaths:     cfg.Coverage.ExcludePaths,
-				ExcludeFiles:     cfg.Coverage.ExcludeFiles,
-				ExcludeGenerated: cfg.Coverage.ExcludeTests,
+				ExcludePaths:       cfg.Coverage.ExcludePaths,
+				ExcludeFiles:       cfg.Coverage.ExcludeFiles,
+				ExcludeGenerated:   cfg.Coverage.ExcludeTests,
+				ExcludePresets:     cfg.Coverage.ExcludePresets,
+				Limits:             cfg.ParserLimits(),
+				ModuleRewrites:     cfg.ModuleRewrites(),
+				ModuleRoot:         cfg.Coverage.ModuleRoot,
+				DiscoveryIgnore:    cfg.Coverage.DiscoveryIgnore,
+				TestHelperPatterns: cfg.Coverage.TestHelpers,
+			}
+
+			// The modules of a go.work workspace are reported and gated one by one
+			var workspaceRoot string
+			var workspaceModules []parser.WorkspaceModule
+			if cfg.Coverage.Workspace && len(variantArgs) == 0 {
+				if workspaceRoot, err = cfg.GetRepositoryRoot(); err != nil {
+					return fmt.Errorf("failed to resolve workspace root: %w", err)
+				}
+				if workspaceModules, err = parser.LoadWorkspace(workspaceRoot); err != nil {
+					return fmt.Errorf("failed to load workspace: %w", err)
+				}
+				parserConfig.WorkspaceModules = workspaceModules
 			}
 			p := parser.NewWithConfig(parserConfig)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			coverage, err := p.ParseFile(ctx, inputFile)
+			// Profiles produced under different build tags are merged so code covered by any variant counts
+			var coverage *parser.CoverageData
+			var variantBreakdown *parser.VariantBreakdown
+			switch {
+			case len(variantArgs) > 0:
+				coverage, variantBreakdown, err = parseVariants(ctx, p, variantArgs)
+			case len(workspaceModules) > 0:
+				coverage, err = parseWorkspace(ctx, p, workspaceModules, workspaceRoot, inputFile)
+			default:
+				coverage, err = p.ParseFile(ctx, inputFile)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to parse coverage file: %w", err)
 			}
+			if err = checkCoverMode(cmd, cfg, coverage); err != nil {
+				return err
+			}
+
+			// Reports of other languages are added to the Go profile so one dashboard covers the repository
+			if len(extraInputs) > 0 {
+				if err = mergeExtraInputs(ctx, p, coverage, extraInputs); err != nil {
+					return err
+				}
+			}
+
+			// Test helper packages get numbers of their own instead of counting toward the totals
+			var testHelpers *parser.CoverageData
+			if cfg.Coverage.ExcludeTestHelpers {
+				coverage, testHelpers = p.SplitTestHelpers(coverage)
+			}
+
+			// Example programs and fuzz harnesses are reported with the Example functions and fuzz
+			// targets of the tests, so whether examples are exercised shows apart from the totals
+			var exampleCoverage *parser.CoverageData
+			var exampleReport *examples.Report
+			if cfg.Coverage.SeparateExamples {
+				coverage, exampleCoverage = coverage.SplitClasses(parser.ClassExample, parser.ClassFuzz)
+				exampleRoot, rootErr := cfg.GetRepositoryRoot()
+				if rootErr != nil {
+					exampleRoot = "."
+				}
+				if exampleReport, err = examples.Scan(p.ModuleDir(exampleRoot)); err != nil {
+					cmd.Printf("   ⚠️  Failed to find examples and fuzz targets: %v\n", err)
+				}
+			}
 
 			cmd.Printf("   ✅ Coverage: %.2f%% (%d/%d lines)\n",
 				coverage.Percentage, coverage.CoveredLines, coverage.TotalLines)
+			if variantBreakdown != nil {
+				for _, variant := range variantBreakdown.Variants {
+					cmd.Printf("      🏷️  %s: %.2f%% (%d/%d statements, %d only under this tag)\n", variant.Name,
+						variant.Percentage, variant.CoveredStatements, variant.TotalStatements, variant.ExclusiveStatements)
+				}
+				if variantBreakdown.UncoveredStatements > 0 {
+					cmd.Printf("      ⛔ %d statements not covered under any tag\n", variantBreakdown.UncoveredStatements)
+				}
+			}
+			if len(extraInputs) > 0 {
+				for _, language := range coverage.LanguageBreakdown() {
+					cmd.Printf("      🌐 %s: %.2f%% (%d/%d statements in %d files)\n", language.Language,
+						language.Percentage, language.CoveredStatements, language.TotalStatements, language.Files)
+				}
+			}
 			cmd.Printf("   📦 Packages: %d\n", len(coverage.Packages))
+			if coverage.Mode != "" {
+				cmd.Printf("   🎚️  Cover mode: %s\n", coverage.Mode)
+			}
+			if exampleReport != nil {
+				total, run := exampleReport.Count(examples.KindExample)
+				targets, seeded := exampleReport.Count(examples.KindFuzz)
+				cmd.Printf("   📘 Examples: %d/%d run by go test, fuzz targets: %d/%d with seed inputs\n", run, total, seeded, targets)
+			}
+			if exampleCoverage != nil && exampleCoverage.TotalLines > 0 {
+				cmd.Printf("   📘 Example and fuzz harness code: %.2f%% (%d/%d statements, not counted in the totals)\n",
+					exampleCoverage.Percentage, exampleCoverage.CoveredLines, exampleCoverage.TotalLines)
+			}
+			if testHelpers != nil && len(testHelpers.Packages) > 0 {
+				cmd.Printf("   🧪 Test helpers: %.2f%% (%d/%d statements in %d packages, not counted in the totals)\n",
+					testHelpers.Percentage, testHelpers.CoveredLines, testHelpers.TotalLines, len(testHelpers.Packages))
+			}
+			modules := newModuleCoverage(cfg, coverage, workspaceModules)
+			for _, module := range modules {
+				cmd.Printf("   🧩 Module %s: %.2f%% (%d/%d statements in %d files, threshold %.2f%%)\n", module.Module.Path,
+					module.Percentage, module.CoveredStatements, module.TotalStatements, module.Files, module.threshold)
+			}
+			teams := newTeamCoverage(cfg, coverage)
+			for _, team := range teams {
+				cmd.Printf("   👥 Team %s: %.2f%% (%d/%d statements, threshold %.2f%%)\n", team.team.Name,
+					team.coverage.Percentage, team.coverage.CoveredLines, team.coverage.TotalLines, team.threshold)
+			}
+			if len(coverage.ExclusionPresets) > 0 {
+				cmd.Printf("   🚫 Exclusion presets: %s\n", strings.Join(coverage.ExclusionPresets, ", "))
+			}
 
 			// Check threshold
 			if coverage.Percentage < cfg.Coverage.Threshold {
 				cmd.Printf("   ⚠️  Below threshold %.2f%%\n", cfg.Coverage.Threshold)
 			}
+
+			// Test time is tracked against coverage when the test results of the run are given
+			var tests *testrun.Summary
+			if cfg.Analytics.TestResults != "" {
+				if tests, err = parseTestResults(cfg); err != nil {
+					return err
+				}
+				cmd.Printf("   ⏱️  Tests: %d in %s (%.1f covered statements per test second)\n",
+					tests.Tests, tests.Duration().Round(time.Millisecond), testrun.Efficiency(coverage.CoveredLines, tests.Seconds))
+			}
+
+			// Coverage debt weighs the uncovered statements by complexity and criticality
+			var coverageDebt *debt.Report
+			if cfg.Analytics.CoverageDebt {
+				coverageDebt = measureDebt(cfg, coverage)
+				cmd.Printf("   💸 Coverage debt: %.0f (%d uncovered statements)\n", coverageDebt.Debt, coverageDebt.Uncovered)
+			}
+
+			// Editor plugins read these from the repository root for gutter highlighting
+			if len(cfg.Editor.Formats) > 0 && !dryRun {
+				if written, editorErr := writeEditorOutput(cfg, coverage, cfg.Editor.Formats); editorErr != nil {
+					cmd.Printf("   ⚠️  Failed to write editor coverage: %v\n", editorErr)
+				} else {
+					for _, path := range written {
+						cmd.Printf("   🖊️  Editor coverage: %s\n", path)
+					}
+				}
+			}
+
+			// An emergency bypass downgrades failed gates to warnings and is recorded for auditing
+			bypass := emergencyBypass(ctx, cfg, nil)
+			printBypass(cmd, bypass)
+
+			// A repository without test files gets a get started dashboard instead of a failing 0%
+			noTests := detectNoTests(cmd, cfg)
+
+			// Warm-up counts the runs recorded before this one, so it is read ahead of the history step
+			warmup := loadWarmupState(ctx, cmd, cfg)
+			printWarmup(cmd, cfg, warmup)
+
+			// The environment is recorded with the run so coverage differences between Go versions,
+			// platforms, runners, tags and profile flags can be told apart
+			environment := runenv.FromEnv(ctx, coverage.Mode)
+			cmd.Printf("   🖥️  Environment: %s\n", runenv.Label(environment))
 			cmd.Printf("\n")
 
 			// Create output directory structure for GitHub Pages
 			// Structure depends on context:
+			// - Local mode: outputDir/, published by the CI server as is
 			// - Branch: outputDir/reports/branch/{branchName}/
 			// - PR: outputDir/pr/{prNumber}/
 			branch := getDefaultBranch()
 			var targetOutputDir string
-			if cfg.IsPullRequestContext() {
+			switch {
+			case cfg.Report.Local:
+				targetOutputDir = outputDir
+			case cfg.IsPullRequestContext():
 				// PR context: outputDir/pr/{prNumber}/
 				targetOutputDir = filepath.Join(outputDir, "pr", fmt.Sprintf("%d", cfg.GitHub.PullRequest))
-			} else {
+			default:
 				// Branch context: outputDir/reports/branch/{branchName}/
-				targetOutputDir = filepath.Join(outputDir, "reports", "branch", branch)
+				branchDir, pathErr := parser.SafeOutputPath(filepath.Join(outputDir, "reports", "branch"), branch)
+				if pathErr != nil {
+					return fmt.Errorf("invalid branch output directory: %w", pathErr)
+				}
+				targetOutputDir = branchDir
+			}
+
+			// Badge and report file names come from the configuration, so they may not leave the
+			// output directories either
+			badgeFile, pathErr := parser.SafeOutputPath(targetOutputDir, cfg.Badge.OutputFile)
+			if pathErr != nil {
+				return fmt.Errorf("invalid badge output file: %w", pathErr)
+			}
+			rootBadgeFile, pathErr := parser.SafeOutputPath(outputDir, cfg.Badge.OutputFile)
+			if pathErr != nil {
+				return fmt.Errorf("invalid badge output file: %w", pathErr)
+			}
+			reportFile, pathErr := parser.SafeOutputPath(targetOutputDir, cfg.Report.OutputFile)
+			if pathErr != nil {
+				return fmt.Errorf("invalid report output file: %w", pathErr)
 			}
 
 			if cfg.Storage.AutoCreate && !dryRun {
@@ -165,101 +415,105 @@ update history, and create GitHub PR comment if in PR context.`,
 				}
 			}
 
+			// A checkpoint in the output directory records the finished steps, so --resume can skip them
+			steps := newPipelineSteps(cmd, filepath.Join(outputDir, checkpoint.FileName),
+				checkpointKey(cfg, branch, inputFile, variantArgs), cfg.Storage.FileMode, resume, !dryRun)
+
 			// Step 2: Generate badge
-			cmd.Printf("🏷️  Step 2: Generating coverage badge...\n")
 			// Badge goes in target directory and also at root for easy access
-			badgeFile := filepath.Join(targetOutputDir, cfg.Badge.OutputFile)
-			rootBadgeFile := filepath.Join(outputDir, cfg.Badge.OutputFile)
+			if !steps.skip(stepBadge, "🏷️  Step 2: Generating coverage badge") {
+				steps.begin(stepBadge)
+				cmd.Printf("🏷️  Step 2: Generating coverage badge...\n")
 
-			var badgeOptions []badge.Option
-			if cfg.Badge.Label != "coverage" {
-				badgeOptions = append(badgeOptions, badge.WithLabel(cfg.Badge.Label))
-			}
-			if cfg.Badge.Style != "flat" {
-				badgeOptions = append(badgeOptions, badge.WithStyle(cfg.Badge.Style))
-			}
-			if cfg.Badge.Logo != "" {
-				badgeOptions = append(badgeOptions, badge.WithLogo(cfg.Badge.Logo))
-			}
-			if cfg.Badge.LogoColor != "" {
-				badgeOptions = append(badgeOptions, badge.WithLogoColor(cfg.Badge.LogoColor))
-			}
-
-			badgeGen := badge.New()
-			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			svgContent, err := badgeGen.Generate(ctx, coverage.Percentage, badgeOptions...)
-			if err != nil {
-				return fmt.Errorf("failed to generate badge: %w", err)
-			}
-
-			if !dryRun {
-				// Ensure target directory exists before writing badge
-				if mkdirErr := os.MkdirAll(filepath.Dir(badgeFile), cfg.Storage.DirMode); mkdirErr != nil {
-					return fmt.Errorf("failed to create badge directory: %w", mkdirErr)
+				var badgeOptions []badge.Option
+				if cfg.Badge.Label != "coverage" {
+					badgeOptions = append(badgeOptions, badge.WithLabel(cfg.Badge.Label))
+				}
+				if cfg.Badge.Style != "flat" {
+					badgeOptions = append(badgeOptions, badge.WithStyle(cfg.Badge.Style))
+				}
+				if cfg.Badge.Logo != "" {
+					badgeOptions = append(badgeOptions, badge.WithLogo(cfg.Badge.Logo))
 				}
-				if writeErr := os.WriteFile(badgeFile, svgContent, cfg.Storage.FileMode); writeErr != nil {
-					return fmt.Errorf("failed to write badge file: %w", writeErr)
+				if cfg.Badge.LogoColor != "" {
+					badgeOptions = append(badgeOptions, badge.WithLogoColor(cfg.Badge.LogoColor))
 				}
 
-				// Also write badge to root for easy access
-				if rootMkdirErr := os.MkdirAll(filepath.Dir(rootBadgeFile), cfg.Storage.DirMode); rootMkdirErr != nil {
-					cmd.Printf("   ⚠️  Failed to create root badge directory: %v\n", rootMkdirErr)
-				} else if writeErr := os.WriteFile(rootBadgeFile, svgContent, cfg.Storage.FileMode); writeErr != nil {
-					cmd.Printf("   ⚠️  Failed to write root badge file: %v\n", writeErr)
+				badgeGen := badge.New()
+				ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+
+				svgContent, err := badgeGen.Generate(ctx, coverage.Percentage, badgeOptions...)
+				if err != nil {
+					return fmt.Errorf("failed to generate badge: %w", err)
 				}
 
-				// Generate badge style variants for URL-based style selection
-				badgeStyles := []string{"flat", "flat-square", "for-the-badge"}
-				for _, style := range badgeStyles {
-					// Build options for this style variant
-					variantOptions := []badge.Option{badge.WithStyle(style)}
-					if cfg.Badge.Label != "coverage" {
-						variantOptions = append(variantOptions, badge.WithLabel(cfg.Badge.Label))
-					}
-					if cfg.Badge.Logo != "" {
-						variantOptions = append(variantOptions, badge.WithLogo(cfg.Badge.Logo))
+				if !dryRun {
+					// Ensure target directory exists before writing badge
+					if mkdirErr := os.MkdirAll(filepath.Dir(badgeFile), cfg.Storage.DirMode); mkdirErr != nil {
+						return fmt.Errorf("failed to create badge directory: %w", mkdirErr)
 					}
-					if cfg.Badge.LogoColor != "" {
-						variantOptions = append(variantOptions, badge.WithLogoColor(cfg.Badge.LogoColor))
+					if writeErr := os.WriteFile(badgeFile, svgContent, cfg.Storage.FileMode); writeErr != nil {
+						return fmt.Errorf("failed to write badge file: %w", writeErr)
 					}
 
-					// Create fresh context for each variant with adequate timeout for logo fetching
-					// (Simple Icons CDN can be slow and has retry logic with delays)
-					variantCtx, variantCancel := context.WithTimeout(context.Background(), 30*time.Second)
-					variantSVG, variantErr := badgeGen.Generate(variantCtx, coverage.Percentage, variantOptions...)
-					variantCancel()
-					if variantErr != nil {
-						cmd.Printf("   ⚠️  Failed to generate %s badge variant: %v\n", style, variantErr)
-						continue
+					// Also write badge to root for easy access
+					if rootMkdirErr := os.MkdirAll(filepath.Dir(rootBadgeFile), cfg.Storage.DirMode); rootMkdirErr != nil {
+						cmd.Printf("   ⚠️  Failed to create root badge directory: %v\n", rootMkdirErr)
+					} else if writeErr := os.WriteFile(rootBadgeFile, svgContent, cfg.Storage.FileMode); writeErr != nil {
+						cmd.Printf("   ⚠️  Failed to write root badge file: %v\n", writeErr)
 					}
 
-					// Write variant to BOTH target directory AND root for deployment
-					variantFilename := fmt.Sprintf("coverage-%s.svg", style)
+					// Generate badge style variants for URL-based style selection
+					badgeStyles := []string{"flat", "flat-square", "for-the-badge"}
+					for _, style := range badgeStyles {
+						// Build options for this style variant
+						variantOptions := []badge.Option{badge.WithStyle(style)}
+						if cfg.Badge.Label != "coverage" {
+							variantOptions = append(variantOptions, badge.WithLabel(cfg.Badge.Label))
+						}
+						if cfg.Badge.Logo != "" {
+							variantOptions = append(variantOptions, badge.WithLogo(cfg.Badge.Logo))
+						}
+						if cfg.Badge.LogoColor != "" {
+							variantOptions = append(variantOptions, badge.WithLogoColor(cfg.Badge.LogoColor))
+						}
 
-					// Write to target directory (for deployment to branch-specific location)
-					variantTargetPath := filepath.Join(targetOutputDir, variantFilename)
-					if writeErr := os.WriteFile(variantTargetPath, variantSVG, cfg.Storage.FileMode); writeErr != nil {
-						cmd.Printf("   ⚠️  Failed to write %s variant to target: %v\n", style, writeErr)
-					}
+						// Create fresh context for each variant with adequate timeout for logo fetching
+						// (Simple Icons CDN can be slow and has retry logic with delays)
+						variantCtx, variantCancel := context.WithTimeout(context.Background(), 30*time.Second)
+						variantSVG, variantErr := badgeGen.Generate(variantCtx, coverage.Percentage, variantOptions...)
+						variantCancel()
+						if variantErr != nil {
+							cmd.Printf("   ⚠️  Failed to generate %s badge variant: %v\n", style, variantErr)
+							continue
+						}
 
-					// Also write to root for easy access
-					variantRootPath := filepath.Join(outputDir, variantFilename)
-					if writeErr := os.WriteFile(variantRootPath, variantSVG, cfg.Storage.FileMode); writeErr != nil {
-						cmd.Printf("   ⚠️  Failed to write %s variant to root: %v\n", style, writeErr)
-					} else {
-						cmd.Printf("   ✅ Badge variant saved: %s\n", variantFilename)
+						// Write variant to BOTH target directory AND root for deployment
+						variantFilename := fmt.Sprintf("coverage-%s.svg", style)
+
+						// Write to target directory (for deployment to branch-specific location)
+						variantTargetPath := filepath.Join(targetOutputDir, variantFilename)
+						if writeErr := os.WriteFile(variantTargetPath, variantSVG, cfg.Storage.FileMode); writeErr != nil {
+							cmd.Printf("   ⚠️  Failed to write %s variant to target: %v\n", style, writeErr)
+						}
+
+						// Also write to root for easy access
+						variantRootPath := filepath.Join(outputDir, variantFilename)
+						if writeErr := os.WriteFile(variantRootPath, variantSVG, cfg.Storage.FileMode); writeErr != nil {
+							cmd.Printf("   ⚠️  Failed to write %s variant to root: %v\n", style, writeErr)
+						} else {
+							cmd.Printf("   ✅ Badge variant saved: %s\n", variantFilename)
+						}
 					}
 				}
-			}
 
-			cmd.Printf("   ✅ Badge saved: %s\n", badgeFile)
-			cmd.Printf("\n")
+				cmd.Printf("   ✅ Badge saved: %s\n", badgeFile)
+				cmd.Printf("\n")
+				steps.complete(stepBadge, nil)
+			}
 
 			// Step 3: Generate HTML report
-			cmd.Printf("📊 Step 3: Generating HTML report...\n")
-
 			// Get PR number if in PR context
 			var prNumber string
 			if cfg.IsPullRequestContext() && cfg.GitHub.PullRequest > 0 {
@@ -273,344 +527,453 @@ update history, and create GitHub PR comment if in PR context.`,
 				BranchName:      getDefaultBranch(),
 				CommitSHA:       cfg.GitHub.CommitSHA,
 				PRNumber:        prNumber,
+				MaxPageBytes:    cfg.Report.MaxPageKB * 1024,
+				ModuleRewrites:  cfg.ModuleRewrites(),
+				ModuleRoot:      cfg.Coverage.ModuleRoot,
 			}
 
 			reportGen := report.NewGenerator(reportConfig)
 			ctx, cancel = context.WithTimeout(context.Background(), 60*time.Second)
 			defer cancel()
 
-			if !dryRun {
-				if reportErr := reportGen.Generate(ctx, coverage); reportErr != nil {
-					return fmt.Errorf("failed to generate report: %w", reportErr)
+			var reportPages int
+			if steps.skip(stepReport, "📊 Step 3: Generating HTML report") {
+				var data reportStepData
+				if steps.restore(stepReport, &data) {
+					reportPages = data.Pages
+				}
+			} else {
+				steps.begin(stepReport)
+				cmd.Printf("📊 Step 3: Generating HTML report...\n")
+				if !dryRun {
+					if reportErr := reportGen.Generate(ctx, coverage); reportErr != nil {
+						return fmt.Errorf("failed to generate report: %w", reportErr)
+					}
 				}
-			}
 
-			cmd.Printf("   ✅ Report saved: %s/coverage.html\n", targetOutputDir)
-			cmd.Printf("\n")
+				cmd.Printf("   ✅ Report saved: %s/coverage.html\n", targetOutputDir)
+				reportPages = reportGen.Pages()
+				if reportPages > 1 {
+					cmd.Printf("   📑 Report split into %d pages to stay within %d KiB per page\n", reportPages, cfg.Report.MaxPageKB)
+				}
+				cmd.Printf("\n")
+				steps.complete(stepReport, reportStepData{Pages: reportPages})
+			}
 
 			// Step 4: Generate dashboard
-			cmd.Printf("🎯 Step 4: Generating coverage dashboard...\n")
-
-			// Prepare coverage data for dashboard
-			// branch already declared earlier
-
-			coverageData := &dashboard.CoverageData{
-				ProjectName:    cfg.Report.Title,
-				RepositoryURL:  fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repository),
-				Branch:         branch,
-				CommitSHA:      cfg.GitHub.CommitSHA,
-				PRNumber:       "",
-				BadgeURL:       fmt.Sprintf("https://%s.github.io/%s/coverage.svg", cfg.GitHub.Owner, cfg.GitHub.Repository),
-				Timestamp:      time.Now(),
-				TotalCoverage:  coverage.Percentage,
-				TotalLines:     coverage.TotalLines,
-				CoveredLines:   coverage.CoveredLines,
-				MissedLines:    coverage.TotalLines - coverage.CoveredLines,
-				TotalFiles:     0,
-				CoveredFiles:   0,
-				PartialFiles:   0,
-				UncoveredFiles: 0,
-			}
+			if !steps.skip(stepDashboard, "🎯 Step 4: Generating coverage dashboard") {
+				steps.begin(stepDashboard)
+				cmd.Printf("🎯 Step 4: Generating coverage dashboard...\n")
+
+				// Prepare coverage data for dashboard
+				// branch already declared earlier
+
+				coverageData := &dashboard.CoverageData{
+					SchemaVersion:  dashboard.CoverageDataSchema.Version(),
+					ProjectName:    cfg.Report.Title,
+					RepositoryURL:  fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repository),
+					Branch:         branch,
+					CommitSHA:      cfg.GitHub.CommitSHA,
+					CoverMode:      coverage.Mode,
+					PRNumber:       "",
+					BadgeURL:       fmt.Sprintf("https://%s.github.io/%s/coverage.svg", cfg.GitHub.Owner, cfg.GitHub.Repository),
+					Timestamp:      time.Now(),
+					TotalCoverage:  coverage.Percentage,
+					TotalLines:     coverage.TotalLines,
+					CoveredLines:   coverage.CoveredLines,
+					MissedLines:    coverage.TotalLines - coverage.CoveredLines,
+					TotalFiles:     0,
+					CoveredFiles:   0,
+					PartialFiles:   0,
+					UncoveredFiles: 0,
+				}
 
-			// Detect workflow run context
-			if runNumberStr := os.Getenv("GITHUB_RUN_NUMBER"); runNumberStr != "" {
-				if runNumber, parseErr := strconv.Atoi(runNumberStr); parseErr == nil {
-					coverageData.WorkflowRunNumber = runNumber
-					// Consider it the first run if run number is 1-3 (allowing for a few initial failures)
-					coverageData.IsFirstRun = runNumber <= 3
-					// HasPreviousRuns will be determined later based on actual history data availability
-					cmd.Printf("   📊 Workflow run #%d detected\n", runNumber)
-					if coverageData.IsFirstRun {
-						cmd.Printf("   🚀 This appears to be one of the first workflow runs\n")
+				// Detect workflow run context
+				if runNumberStr := os.Getenv("GITHUB_RUN_NUMBER"); runNumberStr != "" {
+					if runNumber, parseErr := strconv.Atoi(runNumberStr); parseErr == nil {
+						coverageData.WorkflowRunNumber = runNumber
+						// Consider it the first run if run number is 1-3 (allowing for a few initial failures)
+						coverageData.IsFirstRun = runNumber <= 3
+						// HasPreviousRuns will be determined later based on actual history data availability
+						cmd.Printf("   📊 Workflow run #%d detected\n", runNumber)
+						if coverageData.IsFirstRun {
+							cmd.Printf("   🚀 This appears to be one of the first workflow runs\n")
+						}
 					}
 				}
-			}
 
-			// Discover all eligible Go files to get accurate total count
-			// Get repository root path - we're in coverage/cmd/go-coverage
-			workingDir, wdErr := os.Getwd()
-			if wdErr != nil {
-				cmd.Printf("   ⚠️  Failed to get working directory: %v\n", wdErr)
-			}
-			repoRoot := filepath.Join(workingDir, "../../../../")
-			repoRoot, pathErr := filepath.Abs(repoRoot)
-			if pathErr != nil {
-				cmd.Printf("   ⚠️  Failed to resolve repository root: %v\n", pathErr)
-				repoRoot = "../../../../"
-			}
-
-			eligibleFiles, err := p.DiscoverEligibleFiles(ctx, repoRoot)
-			if err != nil {
-				cmd.Printf("   ⚠️  Failed to discover all Go files: %v\n", err)
-				// Fall back to counting only files in coverage data
-				totalFiles := 0
-				for _, pkg := range coverage.Packages {
-					totalFiles += len(pkg.Files)
+				// Discover all eligible Go files to get accurate total count
+				repoRoot, rootErr := cfg.GetRepositoryRoot()
+				if rootErr != nil {
+					cmd.Printf("   ⚠️  Failed to resolve repository root: %v\n", rootErr)
+					repoRoot = "."
 				}
-				coverageData.TotalFiles = totalFiles
-			} else {
-				coverageData.TotalFiles = len(eligibleFiles)
-			}
 
-			// Count coverage status for files that have coverage data
-			// Any file with >0% coverage is considered "covered"
-			filesInProfile := 0
-			for _, pkg := range coverage.Packages {
-				for _, file := range pkg.Files {
-					filesInProfile++
-					if file.Percentage > 0 {
-						// Any coverage > 0% counts as "covered"
-						coverageData.CoveredFiles++
-					} else {
-						// 0% coverage files in profile are uncovered
-						coverageData.UncoveredFiles++
+				eligibleFiles, err := p.DiscoverEligibleFiles(ctx, repoRoot)
+				if err != nil {
+					cmd.Printf("   ⚠️  Failed to discover all Go files: %v\n", err)
+					// Fall back to counting only files in coverage data
+					totalFiles := 0
+					for _, pkg := range coverage.Packages {
+						totalFiles += len(pkg.Files)
 					}
+					coverageData.TotalFiles = totalFiles
+				} else {
+					coverageData.TotalFiles = len(eligibleFiles)
 				}
-			}
 
-			// Files not in coverage profile are considered uncovered
-			if coverageData.TotalFiles > filesInProfile {
-				additionalUncovered := coverageData.TotalFiles - filesInProfile
-				coverageData.UncoveredFiles += additionalUncovered
-			}
-
-			// Debug output for file counting
-			cmd.Printf("   📊 File Analysis:\n")
-			cmd.Printf("      Total eligible files: %d\n", coverageData.TotalFiles)
-			cmd.Printf("      Files in coverage profile: %d\n", filesInProfile)
-			cmd.Printf("      Files with coverage >0%%: %d\n", coverageData.CoveredFiles)
-			cmd.Printf("      Files with no coverage: %d\n", coverageData.UncoveredFiles)
-
-			// Add package data
-			coverageData.Packages = make([]dashboard.PackageCoverage, 0, len(coverage.Packages))
-			for pkgName, pkg := range coverage.Packages {
-				pkgCoverage := dashboard.PackageCoverage{
-					Name:         pkgName,
-					Path:         pkgName, // Use package name as path for now
-					Coverage:     pkg.Percentage,
-					TotalLines:   pkg.TotalLines,
-					CoveredLines: pkg.CoveredLines,
-					MissedLines:  pkg.TotalLines - pkg.CoveredLines,
-				}
-
-				// Add GitHub URL for package directory if we have GitHub info
-				if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
-					pkgCoverage.GitHubURL = fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s",
-						cfg.GitHub.Owner, cfg.GitHub.Repository, branch, pkgName)
-				}
-
-				// Add file coverage if available
-				if pkg.Files != nil {
-					pkgCoverage.Files = make([]dashboard.FileCoverage, 0, len(pkg.Files))
-					for fileName, file := range pkg.Files {
-						fileCoverage := dashboard.FileCoverage{
-							Name:         filepath.Base(fileName),
-							Path:         fileName,
-							Coverage:     file.Percentage,
-							TotalLines:   file.TotalLines,
-							CoveredLines: file.CoveredLines,
-							MissedLines:  file.TotalLines - file.CoveredLines,
-						}
-						if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
-							fileCoverage.GitHubURL = urlutil.BuildGitHubFileURL(
-								cfg.GitHub.Owner, cfg.GitHub.Repository, branch, fileName,
-							)
+				// Count coverage status for files that have coverage data
+				// Any file with >0% coverage is considered "covered"
+				filesInProfile := 0
+				for _, pkg := range coverage.Packages {
+					for _, file := range pkg.Files {
+						filesInProfile++
+						if file.Percentage > 0 {
+							// Any coverage > 0% counts as "covered"
+							coverageData.CoveredFiles++
+						} else {
+							// 0% coverage files in profile are uncovered
+							coverageData.UncoveredFiles++
 						}
-						pkgCoverage.Files = append(pkgCoverage.Files, fileCoverage)
 					}
 				}
 
-				coverageData.Packages = append(coverageData.Packages, pkgCoverage)
-			}
-
-			// Set PR number if in PR context
-			if cfg.IsPullRequestContext() {
-				coverageData.PRNumber = fmt.Sprintf("%d", cfg.GitHub.PullRequest)
-			}
-
-			// Populate history data for dashboard
-			// Always try to load history for display, even if history tracking is disabled
-			// This ensures trends are shown when history data exists from previous runs
-			{
-				// branch already declared at function level
+				// Files not in coverage profile are considered uncovered
+				if coverageData.TotalFiles > filesInProfile {
+					additionalUncovered := coverageData.TotalFiles - filesInProfile
+					coverageData.UncoveredFiles += additionalUncovered
+				}
 
-				// Resolve absolute path for history storage (same logic as Step 5)
-				dashboardHistoryPath := cfg.History.StoragePath
-				if resolvedPath, err := cfg.ResolveHistoryStoragePath(); err == nil {
-					dashboardHistoryPath = resolvedPath
+				// Debug output for file counting
+				cmd.Printf("   📊 File Analysis:\n")
+				cmd.Printf("      Total eligible files: %d\n", coverageData.TotalFiles)
+				cmd.Printf("      Files in coverage profile: %d\n", filesInProfile)
+				cmd.Printf("      Files with coverage >0%%: %d\n", coverageData.CoveredFiles)
+				cmd.Printf("      Files with no coverage: %d\n", coverageData.UncoveredFiles)
+
+				// Files no test executes or links are candidates for deletion
+				if cfg.Analytics.DeadCode && err == nil {
+					if deadCode, deadErr := deadCodeCandidates(ctx, coverage, p.ModuleDir(repoRoot), eligibleFiles); deadErr != nil {
+						cmd.Printf("   ⚠️  Failed to find dead code: %v\n", deadErr)
+					} else {
+						coverageData.DeadCode = newDeadCodeDashboardData(deadCode)
+						cmd.Printf("      Dead code candidates: %d\n", len(deadCode.Candidates))
+					}
 				}
 
-				// Initialize history tracker to get historical data
-				historyConfig := &history.Config{
-					StoragePath:    dashboardHistoryPath,
-					RetentionDays:  cfg.History.RetentionDays,
-					MaxEntries:     cfg.History.MaxEntries,
-					AutoCleanup:    false, // Don't cleanup when just reading for display
-					MetricsEnabled: false, // Don't track metrics when just reading
+				// Add package data
+				coverageData.Packages = newPackageDashboardData(cfg, branch, coverage)
+
+				// Split totals by handwritten, generated and test code so they are not blended
+				for _, class := range coverage.ClassBreakdown() {
+					coverageData.Classes = append(coverageData.Classes, dashboard.ClassCoverage{
+						Class:        string(class.Class),
+						Files:        class.Files,
+						Coverage:     class.Percentage,
+						TotalLines:   class.TotalStatements,
+						CoveredLines: class.CoveredStatements,
+						MissedLines:  class.TotalStatements - class.CoveredStatements,
+					})
+					cmd.Printf("      %s code: %.2f%% (%d/%d statements in %d files)\n", class.Class,
+						class.Percentage, class.CoveredStatements, class.TotalStatements, class.Files)
 				}
-				tracker := history.NewWithConfig(historyConfig)
 
-				// Get historical data for trends
-				historyCtx, historyCancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer historyCancel()
+				coverageData.Variants = newVariantDashboardData(cfg, branch, variantBreakdown)
+				if len(extraInputs) > 0 {
+					coverageData.Languages = newLanguageDashboardData(coverage.LanguageBreakdown())
+				}
+				coverageData.Modules = newModuleDashboardData(modules)
+				coverageData.TestHelpers = newTestHelperDashboardData(cfg, branch, testHelpers)
+				if cfg.Coverage.SeparateExamples {
+					coverageData.Examples = newExampleDashboardData(cfg, branch, exampleCoverage, exampleReport)
+				}
+				if coverageData.HitCounts = newHitCountDashboardData(cfg, branch, coverage); coverageData.HitCounts != nil {
+					cmd.Printf("      Hit counts: %d/%d covered statements executed once, %d executed %d+ times\n",
+						coverageData.HitCounts.Once, coverageData.HitCounts.Covered,
+						coverageData.HitCounts.HotspotStatements, coverageData.HitCounts.HotspotHits)
+				}
 
-				trendData, err := tracker.GetTrend(historyCtx, history.WithTrendBranch(branch), history.WithTrendDays(30))
+				// Set PR number if in PR context
+				if cfg.IsPullRequestContext() {
+					coverageData.PRNumber = fmt.Sprintf("%d", cfg.GitHub.PullRequest)
+				}
 
-				// If no history for current branch and it's not a main branch, try to get primary main branch history
-				primaryMainBranch := getPrimaryMainBranch()
-				if (err != nil || trendData == nil || trendData.Summary.TotalEntries == 0) && branch != primaryMainBranch {
-					cmd.Printf("   📊 No history for branch '%s', checking %s branch...\n", branch, primaryMainBranch)
-					if mainTrendData, mainErr := tracker.GetTrend(historyCtx, history.WithTrendBranch(primaryMainBranch), history.WithTrendDays(30)); mainErr == nil && mainTrendData != nil {
-						// Use primary main branch data for comparison
-						trendData = mainTrendData
-						cmd.Printf("   ✅ Found %d history entries from %s branch\n", trendData.Summary.TotalEntries, primaryMainBranch)
+				// Populate history data for dashboard
+				var rollupBaseline *parser.CoverageData
+				var historyEntries []history.Entry
+				// Always try to load history for display, even if history tracking is disabled
+				// This ensures trends are shown when history data exists from previous runs
+				{
+					// branch already declared at function level
+
+					// Initialize history tracker to get historical data
+					historyConfig := &history.Config{
+						StoragePath:    historyStoragePath(cfg),
+						Repository:     cfg.RepositorySlug(),
+						RetentionDays:  cfg.History.RetentionDays,
+						MaxEntries:     cfg.History.MaxEntries,
+						AutoCleanup:    false, // Don't cleanup when just reading for display
+						MetricsEnabled: false, // Don't track metrics when just reading
 					}
-				}
+					tracker := history.NewWithConfig(historyConfig)
+
+					// Get historical data for trends
+					historyCtx, historyCancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer historyCancel()
+
+					envFilter, _ := runenv.ParseFilter(cfg.History.Environment) // validated with the config
+					trendData, err := tracker.GetTrend(historyCtx, history.WithTrendBranch(branch), history.WithTrendDays(30),
+						history.WithTrendEnvironment(envFilter))
+
+					// If no history for current branch and it's not a main branch, try to get primary main branch history
+					primaryMainBranch := getPrimaryMainBranch()
+					if (err != nil || trendData == nil || trendData.Summary.TotalEntries == 0) && branch != primaryMainBranch {
+						cmd.Printf("   📊 No history for branch '%s', checking %s branch...\n", branch, primaryMainBranch)
+						if mainTrendData, mainErr := tracker.GetTrend(historyCtx, history.WithTrendBranch(primaryMainBranch), history.WithTrendDays(30),
+							history.WithTrendEnvironment(envFilter)); mainErr == nil && mainTrendData != nil {
+							// Use primary main branch data for comparison
+							trendData = mainTrendData
+							cmd.Printf("   ✅ Found %d history entries from %s branch\n", trendData.Summary.TotalEntries, primaryMainBranch)
+						}
+					}
+
+					if err == nil && trendData != nil {
+						historyEntries = trendData.Entries
+
+						// Populate trend data if we have enough entries
+						if trendData.Summary.TotalEntries > 1 {
+							// Use short-term trend analysis if available
+							changePercent := 0.0
+							direction := trendData.Summary.CurrentTrend
+							if trendData.Analysis != nil && trendData.Analysis.ShortTermTrend != nil {
+								changePercent = trendData.Analysis.ShortTermTrend.ChangePercent
+								direction = trendData.Analysis.ShortTermTrend.Direction
+							}
 
-				if err == nil && trendData != nil {
-					// Populate trend data if we have enough entries
-					if trendData.Summary.TotalEntries > 1 {
-						// Use short-term trend analysis if available
-						changePercent := 0.0
-						direction := trendData.Summary.CurrentTrend
-						if trendData.Analysis != nil && trendData.Analysis.ShortTermTrend != nil {
-							changePercent = trendData.Analysis.ShortTermTrend.ChangePercent
-							direction = trendData.Analysis.ShortTermTrend.Direction
+							coverageData.TrendData = &dashboard.TrendData{
+								Direction:     direction,
+								ChangePercent: changePercent,
+								ChangeLines:   int(changePercent * float64(coverage.TotalLines) / 100),
+							}
 						}
 
-						coverageData.TrendData = &dashboard.TrendData{
-							Direction:     direction,
-							ChangePercent: changePercent,
-							ChangeLines:   int(changePercent * float64(coverage.TotalLines) / 100),
+						// Populate historical points from entries
+						// Newest entry from an earlier commit, for directory rollup changes
+						for _, entry := range trendData.Entries {
+							if entry.Coverage != nil && entry.CommitSHA != cfg.GitHub.CommitSHA {
+								rollupBaseline = entry.Coverage
+								break
+							}
 						}
-					}
 
-					// Populate historical points from entries
-					if len(trendData.Entries) > 0 {
-						coverageData.History = make([]dashboard.HistoricalPoint, 0, len(trendData.Entries))
+						// Confidence band from the run-to-run noise of earlier commits
+						previous := make([]float64, 0, len(trendData.Entries))
 						for _, entry := range trendData.Entries {
-							if entry.Coverage != nil {
-								coverageData.History = append(coverageData.History, dashboard.HistoricalPoint{
-									Timestamp:    entry.Timestamp,
-									CommitSHA:    entry.CommitSHA,
-									Coverage:     entry.Coverage.Percentage,
-									TotalLines:   entry.Coverage.TotalLines,
-									CoveredLines: entry.Coverage.CoveredLines,
-								})
+							if entry.Coverage != nil && entry.CommitSHA != cfg.GitHub.CommitSHA {
+								previous = append(previous, entry.Coverage.Percentage)
 							}
 						}
+						coverageData.Confidence = confidenceInterval(cfg, coverage.Percentage, previous)
+
+						if len(trendData.Entries) > 0 {
+							coverageData.History = make([]dashboard.HistoricalPoint, 0, len(trendData.Entries))
+							for _, entry := range trendData.Entries {
+								if entry.Coverage != nil {
+									coverageData.History = append(coverageData.History, dashboard.HistoricalPoint{
+										Timestamp:    entry.Timestamp,
+										CommitSHA:    entry.CommitSHA,
+										Coverage:     entry.Coverage.Percentage,
+										TotalLines:   entry.Coverage.TotalLines,
+										CoveredLines: entry.Coverage.CoveredLines,
+										Annotations:  annotationNotes(entry.Annotations),
+									})
+								}
+							}
+						}
+						coverageData.TrendChart = newTrendChart(historyCtx, trendData.Entries)
 					}
+
+					cmd.Printf("   📊 History data loaded: %d entries, trend: %s\n",
+						len(coverageData.History),
+						func() string {
+							if coverageData.TrendData != nil {
+								return coverageData.TrendData.Direction
+							}
+							return "none"
+						}())
 				}
 
-				cmd.Printf("   📊 History data loaded: %d entries, trend: %s\n",
-					len(coverageData.History),
-					func() string {
-						if coverageData.TrendData != nil {
-							return coverageData.TrendData.Direction
-						}
-						return "none"
-					}())
-			}
+				// Covered statements per test second, charted over the runs that recorded test results
+				if efficiency := newTestEfficiencyData(cfg, tests, coverage, historyEntries); efficiency != nil {
+					coverageData.TestEfficiency = efficiency
+					if efficiency.Outpaced {
+						cmd.Printf("   ⚠️  Test time grew %.0f%% while coverage grew %.0f%% over the last %d runs\n",
+							efficiency.TimeGrowth, efficiency.CoverageGrowth, len(efficiency.Points))
+					}
+				}
+				coverageData.CoverageDebt = newCoverageDebtData(cfg, coverageDebt, historyEntries)
+				coverageData.NoTests = noTests
+				coverageData.Bypass = bypass
+				coverageData.Bypasses = newBypassEvents(historyEntries)
+				coverageData.Environments = newEnvironmentData(environment, coverage.Percentage, cfg.GitHub.CommitSHA, historyEntries)
+				coverageData.EnvironmentFilter = cfg.History.Environment
+
+				// Roll packages up by directory so large repositories can be browsed top-down
+				if cfg.Report.RollupDepth > 0 {
+					coverageData.Directories = newDirectoryDashboardData(cfg, branch, coverage, rollupBaseline)
+					cmd.Printf("   🗂️  Directory rollup: %d top-level entries (depth %d)\n", len(coverageData.Directories), cfg.Report.RollupDepth)
+				}
 
-			// Set HasPreviousRuns based on actual history data availability, not just run number
-			// This provides more accurate status messages in the dashboard
-			if len(coverageData.History) > 0 || (coverageData.TrendData != nil && coverageData.TrendData.Direction != "none") {
-				coverageData.HasPreviousRuns = false // We have history data, so don't show "failed to record" message
-				cmd.Printf("   ✅ Valid historical data available for trend analysis\n")
-			} else {
-				// Only consider it as "has previous runs" if run number > 1 but no history exists
-				// This will trigger the "Previous workflow runs failed to record history" message
-				if coverageData.WorkflowRunNumber > 1 {
-					coverageData.HasPreviousRuns = true
-					cmd.Printf("   ⚠️ Run #%d but no historical data found - previous runs may have failed\n", coverageData.WorkflowRunNumber)
+				// Set HasPreviousRuns based on actual history data availability, not just run number
+				// This provides more accurate status messages in the dashboard
+				if len(coverageData.History) > 0 || (coverageData.TrendData != nil && coverageData.TrendData.Direction != "none") {
+					coverageData.HasPreviousRuns = false // We have history data, so don't show "failed to record" message
+					cmd.Printf("   ✅ Valid historical data available for trend analysis\n")
 				} else {
-					coverageData.HasPreviousRuns = false
-					cmd.Printf("   ℹ️ First few runs, no historical data expected\n")
+					// Only consider it as "has previous runs" if run number > 1 but no history exists
+					// This will trigger the "Previous workflow runs failed to record history" message
+					if coverageData.WorkflowRunNumber > 1 {
+						coverageData.HasPreviousRuns = true
+						cmd.Printf("   ⚠️ Run #%d but no historical data found - previous runs may have failed\n", coverageData.WorkflowRunNumber)
+					} else {
+						coverageData.HasPreviousRuns = false
+						cmd.Printf("   ℹ️ First few runs, no historical data expected\n")
+					}
 				}
-			}
-
-			// Generate dashboard
-			dashboardConfig := &dashboard.GeneratorConfig{
-				ProjectName:      cfg.Report.Title,
-				RepositoryOwner:  cfg.GitHub.Owner,
-				RepositoryName:   cfg.GitHub.Repository,
-				OutputDir:        targetOutputDir, // Dashboard goes in target directory
-				GeneratorVersion: c.Version.Version,
-				GitHubToken:      cfg.GitHub.Token,
-			}
 
-			dashboardGen := dashboard.NewGenerator(dashboardConfig)
-			ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+				// Each team gets a dashboard of its own, linked from this one
+				if len(teams) > 0 && !dryRun {
+					teamCtx, teamCancel := context.WithTimeout(context.Background(), 30*time.Second)
+					coverageData.Teams = writeTeamDashboards(teamCtx, cmd, cfg, teams, branch, targetOutputDir, c.Version.Version)
+					teamCancel()
+				}
 
-			if !dryRun {
-				if err := dashboardGen.Generate(ctx, coverageData); err != nil {
-					cmd.Printf("   ❌ Failed to generate dashboard: %v\n", err)
-					return fmt.Errorf("failed to generate dashboard: %w", err)
+				// Generate dashboard (without a token offline so no API calls are attempted)
+				dashboardToken := cfg.GitHub.Token
+				if offline {
+					dashboardToken = ""
+				}
+				dashboardConfig := &dashboard.GeneratorConfig{
+					ProjectName:      cfg.Report.Title,
+					RepositoryOwner:  cfg.GitHub.Owner,
+					RepositoryName:   cfg.GitHub.Repository,
+					TemplateDir:      cfg.Report.TemplateDir,
+					OutputDir:        targetOutputDir, // Dashboard goes in target directory
+					GeneratorVersion: c.Version.Version,
+					GitHubToken:      dashboardToken,
+					Sections:         cfg.Report.Sections,
 				}
-				cmd.Printf("   ✅ Dashboard saved: %s/index.html\n", targetOutputDir)
 
-				// Also create dashboard.html for GitHub Pages deployment compatibility
-				indexPath := filepath.Join(targetOutputDir, "index.html")
-				dashboardPath := filepath.Join(targetOutputDir, "dashboard.html")
+				dashboardGen := dashboard.NewGenerator(dashboardConfig)
+				ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
 
-				// Verify index.html was created successfully
-				if _, statErr := os.Stat(indexPath); statErr != nil {
-					cmd.Printf("   ❌ index.html was not created successfully: %v\n", statErr)
-					return fmt.Errorf("index.html generation failed: %w", statErr)
-				}
+				if !dryRun {
+					if err := dashboardGen.Generate(ctx, coverageData); err != nil {
+						cmd.Printf("   ❌ Failed to generate dashboard: %v\n", err)
+						return fmt.Errorf("failed to generate dashboard: %w", err)
+					}
+					cmd.Printf("   ✅ Dashboard saved: %s/index.html\n", targetOutputDir)
 
-				// Read the generated index.html and copy it to dashboard.html
-				indexContent, readErr := os.ReadFile(indexPath) //nolint:gosec // path is constructed from validated config
-				if readErr != nil {
-					cmd.Printf("   ❌ Failed to read index.html for dashboard.html creation: %v\n", readErr)
-					return fmt.Errorf("failed to read generated index.html: %w", readErr)
-				}
+					// Also create dashboard.html for GitHub Pages deployment compatibility
+					indexPath := filepath.Join(targetOutputDir, "index.html")
+					dashboardPath := filepath.Join(targetOutputDir, "dashboard.html")
 
-				if len(indexContent) == 0 {
-					cmd.Printf("   ❌ index.html is empty, cannot create dashboard.html\n")
-					return ErrEmptyIndexHTML
-				}
+					// Verify index.html was created successfully
+					if _, statErr := os.Stat(indexPath); statErr != nil {
+						cmd.Printf("   ❌ index.html was not created successfully: %v\n", statErr)
+						return fmt.Errorf("index.html generation failed: %w", statErr)
+					}
 
-				if writeErr := os.WriteFile(dashboardPath, indexContent, cfg.Storage.FileMode); writeErr != nil { //nolint:gosec // G703: dashboardPath is constructed from config paths, not user-controlled
-					cmd.Printf("   ❌ Failed to create dashboard.html: %v\n", writeErr)
-					return fmt.Errorf("failed to create dashboard.html: %w", writeErr)
-				}
+					// Read the generated index.html and copy it to dashboard.html
+					indexContent, readErr := os.ReadFile(indexPath) //nolint:gosec // path is constructed from validated config
+					if readErr != nil {
+						cmd.Printf("   ❌ Failed to read index.html for dashboard.html creation: %v\n", readErr)
+						return fmt.Errorf("failed to read generated index.html: %w", readErr)
+					}
 
-				// Verify dashboard.html was created successfully
-				dashboardStat, statErr := os.Stat(dashboardPath)
-				if statErr != nil {
-					cmd.Printf("   ❌ dashboard.html was not created successfully: %v\n", statErr)
-					return fmt.Errorf("dashboard.html creation verification failed: %w", statErr)
-				}
-				cmd.Printf("   ✅ Dashboard also saved as: %s (%d bytes)\n", dashboardPath, dashboardStat.Size())
+					if len(indexContent) == 0 {
+						cmd.Printf("   ❌ index.html is empty, cannot create dashboard.html\n")
+						return ErrEmptyIndexHTML
+					}
 
-				// Also save coverage data as JSON for pages deployment
-				dataPath := filepath.Join(outputDir, "coverage-data.json")
-				jsonData, err := json.Marshal(coverageData)
-				if err != nil {
-					cmd.Printf("   ⚠️  Failed to marshal coverage data: %v\n", err)
-				}
-				if err == nil && len(jsonData) > 0 {
-					if err := os.WriteFile(dataPath, jsonData, cfg.Storage.FileMode); err != nil {
-						cmd.Printf("   ⚠️  Failed to save coverage data: %v\n", err)
+					if writeErr := os.WriteFile(dashboardPath, indexContent, cfg.Storage.FileMode); writeErr != nil { //nolint:gosec // G703: dashboardPath is constructed from config paths, not user-controlled
+						cmd.Printf("   ❌ Failed to create dashboard.html: %v\n", writeErr)
+						return fmt.Errorf("failed to create dashboard.html: %w", writeErr)
 					}
+
+					// Verify dashboard.html was created successfully
+					dashboardStat, statErr := os.Stat(dashboardPath)
+					if statErr != nil {
+						cmd.Printf("   ❌ dashboard.html was not created successfully: %v\n", statErr)
+						return fmt.Errorf("dashboard.html creation verification failed: %w", statErr)
+					}
+					cmd.Printf("   ✅ Dashboard also saved as: %s (%d bytes)\n", dashboardPath, dashboardStat.Size())
+
+					// Static trend chart for READMEs and PR comments
+					if svg := analytics.RenderTrendSVG(coverageData.TrendChart, "Coverage trend: "+branch); svg != nil {
+						chartPath := filepath.Join(targetOutputDir, trendChartFile)
+						if writeErr := os.WriteFile(chartPath, svg, cfg.Storage.FileMode); writeErr != nil {
+							cmd.Printf("   ⚠️  Failed to write trend chart: %v\n", writeErr)
+						} else {
+							cmd.Printf("   ✅ Trend chart saved: %s\n", chartPath)
+						}
+					}
+
+					// Also save coverage data as JSON for pages deployment, at the root for the latest
+					// run and as a snapshot of the pull request or branch next to its report
+					dataPath := filepath.Join(outputDir, snapshot.DataFile)
+					jsonData, err := json.Marshal(coverageData)
+					if err != nil {
+						cmd.Printf("   ⚠️  Failed to marshal coverage data: %v\n", err)
+					}
+					if err == nil && len(jsonData) > 0 {
+						jsonData = redactor.Bytes(jsonData)
+						if err := os.WriteFile(dataPath, jsonData, cfg.Storage.FileMode); err != nil {
+							cmd.Printf("   ⚠️  Failed to save coverage data: %v\n", err)
+						}
+						if targetOutputDir != outputDir {
+							if err := writeSnapshot(cmd, cfg, outputDir, targetOutputDir, branch, coverageData, jsonData); err != nil {
+								cmd.Printf("   ⚠️  Failed to save coverage data snapshot: %v\n", err)
+							}
+						}
+					}
+				} else {
+					cmd.Printf("   📊 Would generate dashboard at: %s/index.html\n", outputDir)
+					cmd.Printf("   📊 Would also create: %s/dashboard.html\n", outputDir)
 				}
-			} else {
-				cmd.Printf("   📊 Would generate dashboard at: %s/index.html\n", outputDir)
-				cmd.Printf("   📊 Would also create: %s/dashboard.html\n", outputDir)
-			}
 
-			cmd.Printf("\n")
+				cmd.Printf("\n")
+				steps.complete(stepDashboard, nil)
+			}
 
 			// Step 5: Update history (if enabled)
 			trend := "stable"
-			cmd.Printf("📈 Step 5: Coverage history analysis...\n")
-			cmd.Printf("   🔍 History enabled: %t\n", cfg.History.Enabled)
-			cmd.Printf("   🔍 Skip history flag: %t\n", skipHistory)
-			cmd.Printf("   🔍 History storage path: %s\n", cfg.History.StoragePath)
+			var previous []float64
+			historyResumed := cfg.History.Enabled && !skipHistory && steps.skip(stepHistory, "📈 Step 5: Coverage history analysis")
+			if historyResumed {
+				// The entry is already recorded, so the trend is restored instead of read back from it
+				var data historyStepData
+				if steps.restore(stepHistory, &data) {
+					trend, previous = data.Trend, data.Previous
+				}
+			} else {
+				cmd.Printf("📈 Step 5: Coverage history analysis...\n")
+				cmd.Printf("   🔍 History enabled: %t\n", cfg.History.Enabled)
+				cmd.Printf("   🔍 Skip history flag: %t\n", skipHistory)
+				cmd.Printf("   🔍 History storage path: %s\n", cfg.History.StoragePath)
+			}
+
+			// A merge queue tests a temporary branch: compare against the branch being merged into and
+			// leave recording to the run for the merged commit
+			mergeGroup := runContext(cfg).IsMergeGroup()
+			historyBranch := branch
+			if mergeGroup {
+				historyBranch = runBaseBranch(cfg)
+			}
 
-			if cfg.History.Enabled && !skipHistory {
+			if cfg.History.Enabled && !skipHistory && !historyResumed {
+				steps.begin(stepHistory)
 				cmd.Printf("   📊 Proceeding with history update...\n")
 
 				// Resolve absolute path for history storage to fix working directory issues
@@ -626,6 +989,7 @@ update history, and create GitHub PR comment if in PR context.`,
 
 				historyConfig := &history.Config{
 					StoragePath:    historyStoragePath,
+					Repository:     cfg.RepositorySlug(),
 					RetentionDays:  cfg.History.RetentionDays,
 					MaxEntries:     cfg.History.MaxEntries,
 					AutoCleanup:    cfg.History.AutoCleanup,
@@ -647,7 +1011,7 @@ update history, and create GitHub PR comment if in PR context.`,
 				}
 
 				// Debug: List existing history files before adding new entry
-				if historyFiles, err := filepath.Glob(filepath.Join(historyStoragePath, "*.json")); err == nil {
+				if historyFiles, err := tracker.EntryFiles(ctx); err == nil {
 					cmd.Printf("   📊 Existing history entries: %d\n", len(historyFiles))
 					if len(historyFiles) > 0 {
 						cmd.Printf("   📝 Recent entries:\n")
@@ -664,9 +1028,9 @@ update history, and create GitHub PR comment if in PR context.`,
 
 				// Get trend before adding new entry
 				// branch already declared at function level
-				cmd.Printf("   🌿 Using branch: %s\n", branch)
+				cmd.Printf("   🌿 Using branch: %s\n", historyBranch)
 
-				if latest, err := tracker.GetLatestEntry(ctx, branch); err == nil {
+				if latest, err := tracker.GetLatestEntry(ctx, historyBranch); err == nil {
 					commitDisplay := latest.CommitSHA
 					if len(commitDisplay) > 8 {
 						commitDisplay = commitDisplay[:8]
@@ -685,15 +1049,28 @@ update history, and create GitHub PR comment if in PR context.`,
 					cmd.Printf("   🚀 No previous entry found (first run or new branch): %v\n", err)
 				}
 
-				// Add new entry
+				// Earlier runs feed the drop and sustained-decline policies
+				var prevErr error
+				if previous, prevErr = previousCoverage(ctx, tracker, historyBranch, cfg.History.RetentionDays, policyHistoryDepth(cfg)); prevErr != nil {
+					cmd.Printf("   ⚠️  Failed to load previous runs for policy evaluation: %v\n", prevErr)
+				}
 				if !dryRun {
+					writeBadgeChart(ctx, cmd, cfg, tracker, historyBranch, coverage.Percentage, targetOutputDir, outputDir)
+				}
+
+				// Add new entry
+				if mergeGroup {
+					cmd.Printf("   🚂 Merge queue run: not recording history for %s\n", branch)
+				} else if !dryRun {
 					cmd.Printf("   📝 Recording new history entry...\n")
 					var historyOptions []history.Option
 					historyOptions = append(historyOptions, history.WithBranch(branch))
 					cmd.Printf("   🔧 Branch: %s\n", branch)
 
 					if cfg.GitHub.CommitSHA != "" {
-						historyOptions = append(historyOptions, history.WithCommit(cfg.GitHub.CommitSHA, ""))
+						historyOptions = append(historyOptions,
+							history.WithCommit(cfg.GitHub.CommitSHA, ""),
+							history.WithCommitDepth(commitDepth(ctx, cfg.GitHub.CommitSHA)))
 						cmd.Printf("   🔧 Commit SHA: %s\n", cfg.GitHub.CommitSHA)
 					} else {
 						cmd.Printf("   ⚠️  No commit SHA available\n")
@@ -709,6 +1086,17 @@ update history, and create GitHub PR comment if in PR context.`,
 					}
 
 					cmd.Printf("   💾 Coverage data: %.2f%% (%d/%d lines)\n", coverage.Percentage, coverage.CoveredLines, coverage.TotalLines)
+					if tests != nil {
+						historyOptions = append(historyOptions, history.WithTestRun(tests))
+					}
+					if coverageDebt != nil {
+						historyOptions = append(historyOptions, history.WithDebt(newHistoryDebt(coverageDebt)))
+					}
+					historyOptions = append(historyOptions, history.WithEnvironment(environment))
+					if bypass != "" {
+						historyOptions = append(historyOptions, history.WithBypass(bypass))
+						cmd.Printf("   🚨 Bypass: %s\n", bypass)
+					}
 
 					if err := tracker.Record(ctx, coverage, historyOptions...); err != nil {
 						cmd.Printf("   ❌ Failed to record history: %v\n", err)
@@ -718,7 +1106,7 @@ update history, and create GitHub PR comment if in PR context.`,
 					cmd.Printf("   ✅ History entry recorded successfully\n")
 
 					// Verify the entry was actually written
-					if historyFiles, err := filepath.Glob(filepath.Join(historyStoragePath, "*.json")); err == nil {
+					if historyFiles, err := tracker.EntryFiles(ctx); err == nil {
 						cmd.Printf("   📊 Total history entries after recording: %d\n", len(historyFiles))
 						if len(historyFiles) > 0 {
 							cmd.Printf("   📁 History files are located at: %s\n", historyStoragePath)
@@ -732,7 +1120,8 @@ update history, and create GitHub PR comment if in PR context.`,
 
 				cmd.Printf("   ✅ History update completed (trend: %s)\n", trend)
 				cmd.Printf("\n")
-			} else {
+				steps.complete(stepHistory, historyStepData{Trend: trend, Previous: previous})
+			} else if !historyResumed {
 				if !cfg.History.Enabled {
 					cmd.Printf("   ℹ️  History tracking is disabled in configuration\n")
 				}
@@ -742,22 +1131,47 @@ update history, and create GitHub PR comment if in PR context.`,
 				cmd.Printf("   📈 Coverage history step skipped\n\n")
 			}
 
+			// Additional badges give branch READMEs a richer status row; pull requests get the comment
+			if len(cfg.Badge.Extra) > 0 || cfg.Badge.IncludeTrend {
+				if cfg.IsPullRequestContext() {
+					cmd.Printf("🏷️  Additional badges skipped: pull request context\n\n")
+				} else if dryRun {
+					cmd.Printf("🏷️  DRY RUN: Would write additional badges to %s\n\n", targetOutputDir)
+				} else {
+					cmd.Printf("🏷️  Generating additional badges...\n")
+					writeExtraBadges(ctx, cmd, cfg, coverage.Percentage, previous, tests, targetOutputDir, outputDir)
+					cmd.Printf("\n")
+				}
+			}
+
+			waiverReport := buildWaiverReport(cmd, cfg, time.Now())
+			printWaiverSummary(cmd, waiverReport)
+
+			decision := evaluatePolicy(cfg, coverage, nil, previous, nil)
+			addTeamResults(decision, teams)
+			addModuleResults(decision, modules)
+			addWaiverResults(cfg, decision, waiverReport)
+			applyNoTests(cfg, decision, noTests)
+			applyBypass(decision, bypass)
+			applyWarmup(cfg, decision, warmup)
+			printPolicyDecision(cmd, decision)
+			cmd.Printf("\n")
+
 			// Step 6: GitHub integration (if in GitHub context)
-			if cfg.IsGitHubContext() && !skipGitHub {
+			if offline {
+				cmd.Printf("🐙 Step 6: GitHub integration (skipped: offline mode)\n\n")
+			} else if cfg.IsGitHubContext() && !skipGitHub && steps.skip(stepGitHub, "🐙 Step 6: GitHub integration") {
+				// The commit status was already set by the run being resumed
+			} else if cfg.IsGitHubContext() && !skipGitHub {
 				cmd.Printf("🐙 Step 6: GitHub integration...\n")
 
 				if cfg.GitHub.Token == "" {
 					cmd.Printf("   ⚠️  Skipped: No GitHub token provided\n\n")
+				} else if client, clientErr := newGitHubClient(cfg, "go-coverage/1.0"); clientErr != nil {
+					cmd.Printf("   ⚠️  Skipped: %v\n\n", clientErr)
 				} else {
-					// Create GitHub client
-					githubConfig := &github.Config{
-						Token:      cfg.GitHub.Token,
-						BaseURL:    "https://api.github.com",
-						Timeout:    cfg.GitHub.Timeout,
-						RetryCount: 3,
-						UserAgent:  "go-coverage/1.0",
-					}
-					client := github.NewWithConfig(githubConfig)
+					steps.begin(stepGitHub)
+					var githubErr error
 
 					// Create PR comment if in PR context - this is deprecated in favor of the comment command
 					if cfg.IsPullRequestContext() && cfg.GitHub.PostComments {
@@ -770,13 +1184,30 @@ update history, and create GitHub PR comment if in PR context.`,
 						var state string
 						var description string
 
-						if coverage.Percentage >= cfg.Coverage.Threshold {
+						switch {
+						case noTests && decision.Passed:
+							state = github.StatusSuccess
+							description = "No tests yet: coverage gates skipped"
+						case noTests:
+							state = github.StatusFailure
+							description = "No test files found"
+						case decision.Bypass != "":
+							state = github.StatusSuccess
+							description = fmt.Sprintf("Coverage: %.2f%% (gates bypassed)", coverage.Percentage)
+						case decision.Warmup != "":
+							state = github.StatusSuccess
+							description = fmt.Sprintf("Coverage: %.2f%% (warm-up, %s)", coverage.Percentage, decision.Warmup)
+						case decision.Passed:
 							state = github.StatusSuccess
 							description = fmt.Sprintf("Coverage: %.2f%% ✅", coverage.Percentage)
-						} else {
+						case decision.Failed(policy.RuleThreshold):
 							state = github.StatusFailure
 							description = fmt.Sprintf("Coverage: %.2f%% (below %.2f%% threshold)",
 								coverage.Percentage, cfg.Coverage.Threshold)
+						default:
+							state = github.StatusFailure
+							description = fmt.Sprintf("Coverage: %.2f%% (%s policy failed)",
+								coverage.Percentage, decision.Failures()[0].Rule)
 						}
 
 						statusReq := &github.StatusRequest{
@@ -792,13 +1223,63 @@ update history, and create GitHub PR comment if in PR context.`,
 							err := client.CreateStatus(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository,
 								cfg.GitHub.CommitSHA, statusReq)
 							if err != nil {
-								cmd.Printf("   ⚠️  Failed to create commit status: %v\n", err)
+								cmd.Printf("   ❌ Failed to create commit status: %v\n", err)
+								githubErr = fmt.Errorf("failed to create commit status: %w", err)
 							} else {
 								cmd.Printf("   ✅ Commit status created: %s\n", state)
 							}
 						}
+
+						// Critical paths fail loud under a context of their own, so branch protection can require it
+						if criticalState, criticalDescription, ok := criticalStatus(decision); ok {
+							if dryRun {
+								cmd.Printf("   🚨 Would create critical paths status: %s\n", criticalState)
+							} else {
+								err := client.CreateStatus(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.GitHub.CommitSHA, &github.StatusRequest{
+									State:       criticalState,
+									TargetURL:   cfg.GetReportURL(),
+									Description: criticalDescription,
+									Context:     github.ContextCritical,
+								})
+								if err != nil {
+									cmd.Printf("   ❌ Failed to create critical paths status: %v\n", err)
+									githubErr = fmt.Errorf("failed to create critical paths status: %w", err)
+								} else {
+									cmd.Printf("   ✅ Critical paths status created: %s (%s)\n", criticalState, criticalDescription)
+								}
+							}
+						}
+					}
+
+					// Runs of the main branch keep the regression issue up to date; failures only warn
+					if cfg.GitHub.RegressionIssue && !cfg.IsPullRequestContext() && branch == getPrimaryMainBranch() {
+						if dryRun {
+							cmd.Printf("   📉 Would check %s for a sustained coverage regression\n", branch)
+						} else if entries, historyErr := regressionHistory(ctx, cfg, branch); historyErr != nil {
+							cmd.Printf("   ⚠️  Skipped the regression issue: %v\n", historyErr)
+						} else if issueErr := updateRegressionIssue(ctx, cmd, cfg, client, branch, coverage, entries); issueErr != nil {
+							cmd.Printf("   ⚠️  Failed to update the regression issue: %v\n", issueErr)
+						}
+					}
+
+					// Runs of the main branch record the progress toward the coverage goal; failures only warn
+					if cfg.Goal.Target > 0 && cfg.Goal.Tracker != config.GoalTrackerOff &&
+						!cfg.IsPullRequestContext() && branch == getPrimaryMainBranch() {
+						if dryRun {
+							cmd.Printf("   🎯 Would update the coverage goal %s\n", cfg.Goal.Tracker)
+						} else if goalClient, clientErr := newGoalClient(cfg, client); clientErr != nil {
+							cmd.Printf("   ⚠️  Skipped the coverage goal: %v\n", clientErr)
+						} else if goalErr := updateCoverageGoal(ctx, cmd, cfg, goalClient, branch, coverage.Percentage); goalErr != nil {
+							cmd.Printf("   ⚠️  Failed to update the coverage goal %s: %v\n", cfg.Goal.Tracker, goalErr)
+						}
 					}
 
+					// A failed status does not stop the pipeline; the step is retried with --resume
+					if githubErr != nil {
+						steps.fail(stepGitHub, githubErr)
+					} else {
+						steps.complete(stepGitHub, nil)
+					}
 					cmd.Printf("\n")
 				}
 			} else {
@@ -807,75 +1288,153 @@ update history, and create GitHub PR comment if in PR context.`,
 
 			// Step 7: Copy critical files to root for GitHub Actions validation
 			if !dryRun {
-				cmd.Printf("📋 Step 7: Copying critical files to root output directory...\n")
+				if cfg.Report.Local {
+					cmd.Printf("📋 Step 7: Copying critical files to root output directory (skipped: local mode writes there directly)\n\n")
+				} else if !steps.skip(stepPublish, "📋 Step 7: Copying critical files to root output directory") {
+					steps.begin(stepPublish)
+					cmd.Printf("📋 Step 7: Copying critical files to root output directory...\n")
+
+					// Files to copy from target directory to root
+					filesToCopy := []struct {
+						filename string
+						source   string
+					}{
+						{"index.html", filepath.Join(targetOutputDir, "index.html")},
+						{"dashboard.html", filepath.Join(targetOutputDir, "dashboard.html")},
+						{"coverage.html", reportFile},
+					}
 
-				// Files to copy from target directory to root
-				filesToCopy := []struct {
-					filename string
-					source   string
-				}{
-					{"index.html", filepath.Join(targetOutputDir, "index.html")},
-					{"dashboard.html", filepath.Join(targetOutputDir, "dashboard.html")},
-					{"coverage.html", filepath.Join(targetOutputDir, cfg.Report.OutputFile)},
-				}
+					for _, file := range filesToCopy {
+						sourceFile := file.source
+						destFile := filepath.Join(outputDir, file.filename)
 
-				for _, file := range filesToCopy {
-					sourceFile := file.source
-					destFile := filepath.Join(outputDir, file.filename)
+						// Read source file
+						content, err := os.ReadFile(sourceFile) //nolint:gosec // sourceFile is constructed from validated config paths
+						if err != nil {
+							cmd.Printf("   ⚠️  Failed to read %s: %v\n", file.filename, err)
+							continue
+						}
 
-					// Read source file
-					content, err := os.ReadFile(sourceFile) //nolint:gosec // sourceFile is constructed from validated config paths
-					if err != nil {
-						cmd.Printf("   ⚠️  Failed to read %s: %v\n", file.filename, err)
-						continue
+						// Write to root output directory
+						if err := os.WriteFile(destFile, content, cfg.Storage.FileMode); err != nil { //nolint:gosec // G703: destFile is constructed from config paths, not user-controlled
+							cmd.Printf("   ⚠️  Failed to copy %s to root: %v\n", file.filename, err)
+						} else {
+							cmd.Printf("   ✅ Copied %s to root output directory\n", file.filename)
+						}
 					}
 
-					// Write to root output directory
-					if err := os.WriteFile(destFile, content, cfg.Storage.FileMode); err != nil { //nolint:gosec // G703: destFile is constructed from config paths, not user-controlled
-						cmd.Printf("   ⚠️  Failed to copy %s to root: %v\n", file.filename, err)
-					} else {
-						cmd.Printf("   ✅ Copied %s to root output directory\n", file.filename)
+					// Copy the extra pages and file chunks of a paginated report, replacing those of earlier runs
+					if err := report.RemovePages(outputDir); err != nil {
+						cmd.Printf("   ⚠️  Failed to remove earlier report pages: %v\n", err)
+					}
+					for page := 2; page <= reportPages; page++ {
+						name := report.PageName(page)
+						if err := copyFile(cmd, filepath.Join(targetOutputDir, name), filepath.Join(outputDir, name)); err != nil {
+							cmd.Printf("   ⚠️  Failed to copy %s to root: %v\n", name, err)
+						}
+					}
+					chunkDir := filepath.Join(targetOutputDir, report.FilesDir)
+					if _, statErr := os.Stat(chunkDir); statErr == nil {
+						if err := copyDir(cmd, chunkDir, filepath.Join(outputDir, report.FilesDir)); err != nil {
+							cmd.Printf("   ⚠️  Failed to copy report file chunks: %v\n", err)
+						}
+					}
+					teamsDir := filepath.Join(targetOutputDir, cfg.Teams.Dir)
+					if _, statErr := os.Stat(teamsDir); statErr == nil && len(cfg.Teams.Teams) > 0 {
+						if err := copyDir(cmd, teamsDir, filepath.Join(outputDir, cfg.Teams.Dir)); err != nil {
+							cmd.Printf("   ⚠️  Failed to copy team dashboards: %v\n", err)
+						}
 					}
-				}
 
-				// Copy assets directory to root
-				sourceAssetsDir := filepath.Join(targetOutputDir, "assets")
-				destAssetsDir := filepath.Join(outputDir, "assets")
+					// Copy assets directory to root
+					sourceAssetsDir := filepath.Join(targetOutputDir, "assets")
+					destAssetsDir := filepath.Join(outputDir, "assets")
 
-				if _, err := os.Stat(sourceAssetsDir); err == nil {
-					cmd.Printf("   📁 Copying assets directory to root...\n")
-					if err := copyDir(cmd, sourceAssetsDir, destAssetsDir); err != nil {
-						cmd.Printf("   ⚠️  Failed to copy assets directory: %v\n", err)
+					if _, err := os.Stat(sourceAssetsDir); err == nil {
+						cmd.Printf("   📁 Copying assets directory to root...\n")
+						if err := copyDir(cmd, sourceAssetsDir, destAssetsDir); err != nil {
+							cmd.Printf("   ⚠️  Failed to copy assets directory: %v\n", err)
+						} else {
+							cmd.Printf("   ✅ Copied assets directory to root output directory\n")
+						}
 					} else {
-						cmd.Printf("   ✅ Copied assets directory to root output directory\n")
+						cmd.Printf("   ⚠️  No assets directory found at: %s\n", sourceAssetsDir)
 					}
-				} else {
-					cmd.Printf("   ⚠️  No assets directory found at: %s\n", sourceAssetsDir)
-				}
-
-				// Create root index.html redirect only if index.html copy failed and we're on master
-				rootIndexPath := filepath.Join(outputDir, "index.html")
-				if _, err := os.Stat(rootIndexPath); os.IsNotExist(err) && branch == "master" && !cfg.IsPullRequestContext() {
-					cmd.Printf("   ℹ️  Creating fallback redirect for master branch\n")
-					redirectHTML := `<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="utf-8">
-    <title>Coverage Report - Redirecting...</title>
-    <meta http-equiv="refresh" content="0; url=reports/branch/master/">
-    <script>window.location.href = "reports/branch/master/";</script>
-</head>
-<body>
-    <p>Redirecting to <a href="reports/branch/master/">coverage report</a>...</p>
-</body>
-</html>`
-					if err := os.WriteFile(rootIndexPath, []byte(redirectHTML), cfg.Storage.FileMode); err != nil {
-						cmd.Printf("   ⚠️  Failed to create fallback root index.html: %v\n", err)
-					} else {
-						cmd.Printf("   ✅ Fallback root index.html redirect created\n")
+
+					// Create root index.html redirect only if index.html copy failed and we're on master
+					rootIndexPath := filepath.Join(outputDir, "index.html")
+					if _, err := os.Stat(rootIndexPath); os.IsNotExist(err) && branch == "master" && !cfg.IsPullRequestContext() {
+						cmd.Printf("   ℹ️  Creating fallback redirect for master branch\n")
+						redirectHTML := `<!DOCTYPE html>
+	<html>
+	<head>
+	    <meta charset="utf-8">
+	    <title>Coverage Report - Redirecting...</title>
+	    <meta http-equiv="refresh" content="0; url=reports/branch/master/">
+	    <script>window.location.href = "reports/branch/master/";</script>
+	</head>
+	<body>
+	    <p>Redirecting to <a href="reports/branch/master/">coverage report</a>...</p>
+	</body>
+	</html>`
+						if err := os.WriteFile(rootIndexPath, []byte(redirectHTML), cfg.Storage.FileMode); err != nil {
+							cmd.Printf("   ⚠️  Failed to create fallback root index.html: %v\n", err)
+						} else {
+							cmd.Printf("   ✅ Fallback root index.html redirect created\n")
+						}
 					}
+					cmd.Printf("\n")
+					steps.complete(stepPublish, nil)
+				}
+
+				// Step 8: Publish only the badge of a private repository to its public location
+				if cfg.PublicBadge.Target != "" {
+					if reason := publicBadgeSkipReason(cfg, branch, offline); reason != "" {
+						cmd.Printf("🔓 Step 8: Publishing public badge (skipped: %s)\n\n", reason)
+					} else if !steps.skip(stepPublicBadge, "🔓 Step 8: Publishing public badge") {
+						steps.begin(stepPublicBadge)
+						cmd.Printf("🔓 Step 8: Publishing public badge to %s...\n", cfg.PublicBadge.Target)
+						publishCtx, publishCancel := context.WithTimeout(context.Background(), cfg.GitHub.Timeout)
+						if err := publishPublicBadge(publishCtx, cmd, cfg, githubAPIBaseURL, badgeFile, coverage.Percentage); err != nil {
+							cmd.Printf("   ❌ Failed to publish public badge: %v\n", err)
+							steps.fail(stepPublicBadge, err)
+						} else {
+							steps.complete(stepPublicBadge, nil)
+						}
+						publishCancel()
+						cmd.Printf("\n")
+					}
+				}
+
+				// Machine-readable summary for tooling that cannot scrape the console output
+				summary := newPipelineSummary(coverage, cfg, branch, trend, offline, decision)
+				summary.Badge = badgeFile
+				summary.Report = reportFile
+				summary.GeneratorVersion = c.Version.Version
+				summaryFile := filepath.Join(outputDir, pipelineSummaryFile)
+				if err := writePipelineSummary(summaryFile, summary, redactor, cfg.Storage.FileMode); err != nil {
+					cmd.Printf("⚠️  Failed to write pipeline summary: %v\n\n", err)
+				} else {
+					cmd.Printf("🧾 Summary saved: %s\n\n", summaryFile)
+				}
+
+				// Provenance record answering "which version generated this site"
+				metadataFile := filepath.Join(outputDir, siteMetadataFile)
+				profilePath := inputFile
+				if len(variantArgs) > 0 {
+					profilePath = ""
+				}
+				meta, metaErr := newSiteMetadata(c.Version, cfg, branch, profilePath, startedAt)
+				if metaErr == nil {
+					metaErr = meta.addVariantProfiles(variantArgs)
+				}
+				if metaErr != nil {
+					cmd.Printf("⚠️  Failed to collect site metadata: %v\n\n", metaErr)
+				} else if metaErr = writeSiteMetadata(metadataFile, meta, redactor, cfg.Storage.FileMode); metaErr != nil {
+					cmd.Printf("⚠️  Failed to write site metadata: %v\n\n", metaErr)
+				} else {
+					cmd.Printf("🧾 Metadata saved: %s\n\n", metadataFile)
 				}
-				cmd.Printf("\n")
 			}
 
 			// Final summary
@@ -886,32 +1445,29 @@ update history, and create GitHub PR comment if in PR context.`,
 			cmd.Printf("Badge: %s\n", badgeFile)
 			cmd.Printf("Report: %s/coverage.html\n", targetOutputDir)
 
-			if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
+			if cfg.Report.Local {
+				cmd.Printf("Publish: %s (open index.html)\n", outputDir)
+			} else if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
 				cmd.Printf("Badge URL: %s\n", cfg.GetBadgeURL())
 				cmd.Printf("Report URL: %s\n", cfg.GetReportURL())
 			}
+			stepsErr := steps.finish()
 
-			// Check if we should skip threshold check due to label override
+			// Check if we should skip policy checks due to label override
 			skipThresholdCheck := false
-			if coverage.Percentage < cfg.Coverage.Threshold {
+			if !decision.Passed {
 				// Check for label override if we're in PR context and it's enabled
-				if cfg.IsPullRequestContext() && cfg.Coverage.AllowLabelOverride && cfg.GitHub.Token != "" {
-					cmd.Printf("📊 Coverage below threshold, checking for override label...\n")
+				if offline && cfg.IsPullRequestContext() && cfg.Coverage.AllowLabelOverride {
+					cmd.Printf("📊 Coverage policy failed, override label check skipped: offline mode\n")
+				} else if cfg.IsPullRequestContext() && cfg.Coverage.AllowLabelOverride && cfg.GitHub.Token != "" {
+					cmd.Printf("📊 Coverage policy failed, checking for override label...\n")
 
 					// Create GitHub client to fetch PR labels
-					githubConfig := &github.Config{
-						Token:      cfg.GitHub.Token,
-						BaseURL:    "https://api.github.com",
-						Timeout:    cfg.GitHub.Timeout,
-						RetryCount: 3,
-						UserAgent:  "go-coverage/1.0",
-					}
-					client := github.NewWithConfig(githubConfig)
-
-					// Fetch PR details to get labels
-					pr, err := client.GetPullRequest(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.GitHub.PullRequest)
+					client, err := newGitHubClient(cfg, "go-coverage/1.0")
 					if err != nil {
 						cmd.Printf("   ⚠️  Failed to fetch PR labels: %v\n", err)
+					} else if pr, err := client.GetPullRequest(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.GitHub.PullRequest); err != nil {
+						cmd.Printf("   ⚠️  Failed to fetch PR labels: %v\n", err)
 					} else {
 						// Check for coverage-override label
 						for _, label := range pr.Labels {
@@ -929,21 +1485,30 @@ update history, and create GitHub PR comment if in PR context.`,
 				}
 			}
 
-			// Return error if below threshold and no override
-			if coverage.Percentage < cfg.Coverage.Threshold && !skipThresholdCheck {
-				return fmt.Errorf("%w: %.2f%% is below threshold %.2f%%", ErrCoverageBelowThreshold, coverage.Percentage, cfg.Coverage.Threshold)
+			// Return error if a policy failed and there is no override
+			if !skipThresholdCheck {
+				if err := policyError(cfg, coverage.Percentage, decision); err != nil {
+					return err
+				}
 			}
 
-			return nil
+			return stepsErr
 		},
 	}
 
 	// Add flags
-	cmd.Flags().StringP("input", "i", "", "Input coverage file")
+	addCoverageInputFlags(cmd)
 	cmd.Flags().StringP("output", "o", "", "Output directory")
 	cmd.Flags().Bool("skip-history", false, "Skip history tracking")
 	cmd.Flags().Bool("skip-github", false, "Skip GitHub integration")
-	cmd.Flags().Bool("dry-run", false, "Show what would be done without actually doing it")
+	cmd.Flags().Bool(flagNameLocal, false, "Write all outputs flat into the output directory without network access, e.g. for the Jenkins HTML Publisher")
+	cmd.Flags().StringSlice(flagNameEditor, nil, "Write coverage for editor plugins in these formats (lcov, json)")
+	addTestResultsFlag(cmd)
+	addFailWithoutTestsFlag(cmd)
+	cmd.Flags().StringArray(flagNameVariant, nil, "Coverage profile produced under build tags as name=path (repeatable, replaces --input)")
+	cmd.Flags().StringArray(flagNameExtraInput, nil, "LCOV or Cobertura report of another language as [language=]path (repeatable)")
+	cmd.Flags().Bool(flagNameResume, false, "Skip the steps a failed run of the same commit and profile already completed")
+	addDryRunFlag(cmd, "Show what would be done without actually doing it")
 
 	return cmd
 }
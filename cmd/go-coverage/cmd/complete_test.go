@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/parser"
 )
 
 func TestGetMainBranches(t *testing.T) {
@@ -147,6 +151,8 @@ func TestGetDefaultBranch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			isolateRunContextEnv(t)
+
 			// Save and restore environment
 			original := os.Getenv("GITHUB_REF_NAME")
 			defer func() {
@@ -234,6 +240,7 @@ func TestCompleteCommandFlags(t *testing.T) {
 		"output":       {flagTypeString, ""},
 		"skip-history": {"bool", flagBoolFalse},
 		"skip-github":  {"bool", flagBoolFalse},
+		flagNameLocal:  {"bool", flagBoolFalse},
 		flagDryRun:     {"bool", flagBoolFalse},
 	}
 
@@ -434,3 +441,326 @@ func TestCopyFileErrors(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to open source file")
 }
+
+func TestCompleteCommandCodeClasses(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("GO_COVERAGE_EXCLUDE_FILES", "*.skip")
+	t.Setenv("GO_COVERAGE_EXCLUDE_GENERATED", "false")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 2 1
+github.com/example/repo/api/api.pb.go:5.2,6.10 6 0
+`), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--input", coverageFile,
+		"--output", outputDir,
+		"--skip-history",
+	})
+	require.NoError(t, commands.Execute())
+	assert.Contains(t, buf.String(), "generated code: 0.00% (0/6 statements in 1 files)")
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "coverage-data.json")) //nolint:gosec // test file path
+	require.NoError(t, err)
+
+	var coverageData dashboard.CoverageData
+	require.NoError(t, json.Unmarshal(data, &coverageData))
+	require.Len(t, coverageData.Classes, 2)
+	assert.Equal(t, "handwritten", coverageData.Classes[0].Class)
+	assert.InDelta(t, 100.0, coverageData.Classes[0].Coverage, 0.01)
+	assert.Equal(t, "generated", coverageData.Classes[1].Class)
+	assert.Equal(t, 6, coverageData.Classes[1].MissedLines)
+}
+
+func TestCompleteCommandLocal(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_LOCAL", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("JENKINS_URL", "https://ci.example.com/")
+	t.Setenv("BUILD_URL", "https://ci.example.com/job/repo/12/")
+	t.Setenv("GIT_URL", "https://github.com/test/repo.git")
+	t.Setenv("GIT_BRANCH", "origin/main")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	outputDir := filepath.Join(tempDir, "coverage")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/test/repo/main.go:10.2,12.16 2 1
+github.com/test/repo/main.go:15.2,17.16 2 0
+`), 0o600))
+
+	output, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", outputDir, "--skip-history")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Mode: LOCAL")
+	assert.Contains(t, output, "CI Build: https://ci.example.com/job/repo/12/ (jenkins)")
+	assert.Contains(t, output, "Publish: "+outputDir)
+	for _, name := range []string{"index.html", "coverage.html", "coverage.svg"} {
+		assert.FileExists(t, filepath.Join(outputDir, name))
+	}
+	assert.NoDirExists(t, filepath.Join(outputDir, "reports"))
+}
+
+func TestCompleteCommandUnsafeOutputFile(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("GO_COVERAGE_BADGE_OUTPUT", "../escaped.svg")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	outputDir := filepath.Join(tempDir, "coverage")
+	require.NoError(t, os.WriteFile(coverageFile, []byte("mode: set\ngithub.com/test/repo/main.go:10.2,12.16 2 1\n"), 0o600))
+
+	_, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", outputDir, "--skip-history")
+	require.ErrorIs(t, err, parser.ErrUnsafeFilePath)
+	assert.NoFileExists(t, filepath.Join(tempDir, "escaped.svg"))
+}
+
+func TestCompleteCommandBuildTagVariants(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	unitFile := filepath.Join(tempDir, "coverage-unit.txt")
+	integrationFile := filepath.Join(tempDir, "coverage-integration.txt")
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.WriteFile(unitFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 3 0
+github.com/example/repo/lib/lib.go:20.2,21.10 1 0
+`), 0o600))
+	require.NoError(t, os.WriteFile(integrationFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 0
+github.com/example/repo/lib/lib.go:15.2,17.16 3 1
+github.com/example/repo/lib/lib.go:20.2,21.10 1 0
+`), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--variant", "unit=" + unitFile,
+		"--variant", "integration=" + integrationFile,
+		"--output", outputDir,
+		"--skip-history",
+	})
+	require.NoError(t, commands.Execute())
+	output := buf.String()
+	assert.Contains(t, output, "Coverage: 83.33% (5/6 lines)")
+	assert.Contains(t, output, "unit: 33.33% (2/6 statements, 2 only under this tag)")
+	assert.Contains(t, output, "1 statements not covered under any tag")
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "coverage-data.json")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	var coverageData dashboard.CoverageData
+	require.NoError(t, json.Unmarshal(data, &coverageData))
+	require.NotNil(t, coverageData.Variants)
+	require.Len(t, coverageData.Variants.Tags, 2)
+	assert.Equal(t, "integration", coverageData.Variants.Tags[1].Name)
+	assert.Equal(t, 3, coverageData.Variants.Tags[1].ExclusiveLines)
+	assert.Equal(t, 1, coverageData.Variants.UncoveredLines)
+
+	metadata, err := os.ReadFile(filepath.Join(outputDir, siteMetadataFile)) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), `"variant_profiles"`)
+	assert.NotContains(t, string(metadata), `"input_profile"`)
+}
+
+func TestCompleteCommandExtraInputs(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	lcovFile := filepath.Join(tempDir, "lcov.info")
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/lib/lib.go:15.2,17.16 1 0
+`), 0o600))
+	require.NoError(t, os.WriteFile(lcovFile, []byte(`TN:
+SF:web/src/api.ts
+DA:1,1
+DA:2,0
+end_of_record
+`), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--input", coverageFile,
+		"--extra-input", lcovFile,
+		"--output", outputDir,
+		"--skip-history",
+	})
+	require.NoError(t, commands.Execute())
+	output := buf.String()
+	assert.Contains(t, output, "Coverage: 66.67% (4/6 lines)")
+	assert.Contains(t, output, "go: 75.00% (3/4 statements in 1 files)")
+	assert.Contains(t, output, "typescript: 50.00% (1/2 statements in 1 files)")
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "coverage-data.json")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	var coverageData dashboard.CoverageData
+	require.NoError(t, json.Unmarshal(data, &coverageData))
+	require.Len(t, coverageData.Languages, 2)
+	assert.Equal(t, "typescript", coverageData.Languages[1].Language)
+	assert.Equal(t, 2, coverageData.Languages[1].TotalLines)
+
+	dashboardHTML, err := os.ReadFile(filepath.Join(outputDir, "index.html")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(dashboardHTML), "Coverage by Language")
+}
+
+func TestCompleteCommandTeams(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("GO_COVERAGE_TEAMS", "backend: paths=lib, threshold=90; web: paths=web; docs: paths=docs")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	lcovFile := filepath.Join(tempDir, "lcov.info")
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/lib/lib.go:15.2,17.16 1 0
+`), 0o600))
+	require.NoError(t, os.WriteFile(lcovFile, []byte("SF:web/src/api.ts\nDA:1,1\nDA:2,0\nend_of_record\n"), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--input", coverageFile,
+		"--extra-input", lcovFile,
+		"--output", outputDir,
+		"--skip-history",
+	})
+
+	// The backend team is below its own threshold although the global threshold passes
+	err := commands.Execute()
+	require.ErrorIs(t, err, ErrCoveragePolicyFailed)
+	assert.Contains(t, err.Error(), "backend: coverage 75.00% is below the 90.00% threshold")
+	output := buf.String()
+	assert.Contains(t, output, "Team backend: 75.00% (3/4 statements, threshold 90.00%)")
+	assert.Contains(t, output, "Team web: 50.00% (1/2 statements, threshold 0.00%)")
+	assert.NotContains(t, output, "Team docs")
+
+	for _, team := range []string{"backend", "web"} {
+		assert.FileExists(t, filepath.Join(outputDir, "teams", team, "index.html"))
+		assert.FileExists(t, filepath.Join(outputDir, "teams", team, "coverage.svg"))
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "coverage-data.json")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	var coverageData dashboard.CoverageData
+	require.NoError(t, json.Unmarshal(data, &coverageData))
+	require.Len(t, coverageData.Teams, 2)
+	assert.Equal(t, "backend", coverageData.Teams[0].Name)
+	assert.Equal(t, "teams/backend/", coverageData.Teams[0].URL)
+	assert.InDelta(t, 90.0, coverageData.Teams[0].Threshold, 0.001)
+
+	dashboardHTML, err := os.ReadFile(filepath.Join(outputDir, "index.html")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(dashboardHTML), `href="teams/web/"`)
+}
+
+func TestCompleteCommandEditorOutput(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	t.Setenv("GO_COVERAGE_EDITOR_DIR", tempDir)
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,11.16 2 1
+github.com/example/repo/lib/lib.go:15.2,15.16 3 0
+`), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--input", coverageFile,
+		"--output", filepath.Join(tempDir, "output"),
+		"--skip-history",
+		"--editor", "lcov,json",
+	})
+	require.NoError(t, commands.Execute())
+	assert.Contains(t, buf.String(), "Editor coverage: "+filepath.Join(tempDir, "lcov.info"))
+
+	lcov, err := os.ReadFile(filepath.Join(tempDir, "lcov.info")) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Equal(t, "SF:lib/lib.go\nDA:10,1\nDA:11,1\nDA:15,0\nLF:3\nLH:2\nend_of_record\n", string(lcov))
+	assert.FileExists(t, filepath.Join(tempDir, "coverage.json"))
+}
+
+func TestCompleteCommandTestResults(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_LOCAL", "")
+	t.Setenv("GO_COVERAGE_TEST_RESULTS", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	resultsFile := filepath.Join(tempDir, "test.json")
+	outputDir := filepath.Join(tempDir, "coverage")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/test/repo/main.go:10.2,12.16 2 1
+github.com/test/repo/main.go:15.2,17.16 2 0
+`), 0o600))
+	require.NoError(t, os.WriteFile(resultsFile, []byte(`{"Action":"pass","Package":"github.com/test/repo","Test":"TestMain","Elapsed":0.2}
+{"Action":"pass","Package":"github.com/test/repo","Elapsed":0.5}
+`), 0o600))
+
+	output, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", outputDir,
+		"--skip-history", "--test-results", resultsFile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "⏱️  Tests: 1 in 500ms (")
+	html, err := os.ReadFile(filepath.Join(outputDir, "index.html")) //nolint:gosec // test output
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "Test Efficiency")
+
+	_, err = executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", outputDir,
+		"--skip-history", "--test-results", filepath.Join(tempDir, "missing.json"))
+	require.ErrorContains(t, err, "failed to parse test results")
+}
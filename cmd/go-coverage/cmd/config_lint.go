@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/configlint"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+var (
+	// ErrConfigLint indicates a configuration with errors, or warnings in strict mode
+	ErrConfigLint = errors.New("configuration lint failed")
+	// ErrUnsupportedLintFormat is returned for an unknown --format of the config lint command
+	ErrUnsupportedLintFormat = errors.New("unsupported lint format")
+)
+
+// Output formats supported by the config lint command
+const (
+	lintFormatText = "text"
+	lintFormatJSON = "json"
+)
+
+// newConfigCmd creates the config command
+func (c *Commands) newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	cmd.AddCommand(c.newConfigLintCmd())
+	return cmd
+}
+
+// newConfigLintCmd creates the config lint command
+func (c *Commands) newConfigLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Find settings that do not do what was meant",
+		Long: `Check the configuration for mistakes that pass validation but break the reports:
+
+  - thresholds outside 0-100, or that the exclusions leave no statements to meet
+  - critical paths whose files are all excluded
+  - exclusion paths and file patterns that match no Go file of the repository
+  - file patterns with a slash, which never match as they are compared to file names
+  - history kept for fewer days or entries than the trends and policies read
+  - misspelled badge styles and report themes
+
+Each finding names the environment variable to change and how. Errors make the command
+exit with an error; warnings do too with --strict. Profile checks run when the coverage
+profile exists.`,
+		Example: `  go-coverage config lint
+  go-coverage config lint --strict --format json`,
+		RunE: c.runConfigLint,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "Coverage profile (defaults to the configured input file)")
+	cmd.Flags().String("format", lintFormatText, "Output format (text or json)")
+	cmd.Flags().Bool("strict", false, "Exit with an error on warnings too")
+	return cmd
+}
+
+// runConfigLint executes the config lint command
+func (c *Commands) runConfigLint(cmd *cobra.Command, _ []string) error {
+	inputFile, _ := cmd.Flags().GetString("input")
+	format, _ := cmd.Flags().GetString("format")
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	if format != lintFormatText && format != lintFormatJSON {
+		return fmt.Errorf("%w: %q (expected text or json)", ErrUnsupportedLintFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	in := configlint.Input{Config: cfg, Profile: inputFile}
+	if root, rootErr := cfg.GetRepositoryRoot(); rootErr == nil {
+		if in.Files, err = listGoFiles(root); err != nil {
+			return err
+		}
+	}
+	if _, statErr := os.Stat(inputFile); statErr == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		in.Coverage, err = parser.NewWithConfig(&parser.Config{
+			ExcludePaths:     cfg.Coverage.ExcludePaths,
+			ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+			ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+			ExcludePresets:   cfg.Coverage.ExcludePresets,
+			Limits:           cfg.ParserLimits(),
+			ModuleRewrites:   cfg.ModuleRewrites(),
+			ModuleRoot:       cfg.Coverage.ModuleRoot,
+			DiscoveryIgnore:  cfg.Coverage.DiscoveryIgnore,
+		}).ParseFile(ctx, inputFile)
+		if err != nil {
+			return fmt.Errorf("failed to parse coverage: %w", err)
+		}
+	}
+
+	findings := configlint.Lint(in)
+	if format == lintFormatJSON {
+		if findings == nil {
+			findings = []configlint.Finding{}
+		}
+		data, marshalErr := json.MarshalIndent(findings, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to encode lint findings: %w", marshalErr)
+		}
+		cmd.Println(string(data))
+	} else {
+		cmd.Print(formatLintFindings(findings))
+	}
+
+	if errs := configlint.Errors(findings); errs > 0 {
+		return fmt.Errorf("%w: %d error(s)", ErrConfigLint, errs)
+	}
+	if strict && len(findings) > 0 {
+		return fmt.Errorf("%w: %d warning(s) in strict mode", ErrConfigLint, len(findings))
+	}
+	return nil
+}
+
+// listGoFiles returns the Go files under root relative to it, skipping hidden directories
+func listGoFiles(root string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(entry.Name(), ".go") {
+			rel, relErr := filepath.Rel(root, path)
+			if relErr != nil {
+				return relErr
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Go files: %w", err)
+	}
+	return files, nil
+}
+
+// formatLintFindings renders lint findings for the console
+func formatLintFindings(findings []configlint.Finding) string {
+	if len(findings) == 0 {
+		return "✅ No configuration problems found\n"
+	}
+	var sb strings.Builder
+	for _, finding := range findings {
+		icon := "⚠️ "
+		if finding.Severity == configlint.SeverityError {
+			icon = "❌"
+		}
+		fmt.Fprintf(&sb, "%s %s [%s]: %s\n", icon, finding.Setting, finding.Check, finding.Message)
+		if finding.Fix != "" {
+			fmt.Fprintf(&sb, "   → %s\n", finding.Fix)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/configlint"
+)
+
+// isolateConfigLintEnv pins the settings config lint checks, which earlier tests may have loaded
+// from env files
+func isolateConfigLintEnv(t *testing.T, dir string) {
+	t.Helper()
+	for key, value := range map[string]string{
+		"GO_COVERAGE_TEST_CONFIG_DIR":           "/nonexistent-test-isolation-dir",
+		"GO_COVERAGE_REPO_ROOT":                 dir,
+		"GO_COVERAGE_INPUT_FILE":                "coverage.txt",
+		"GO_COVERAGE_THRESHOLD":                 "80",
+		"GO_COVERAGE_EXCLUDE_PATHS":             "vendor/",
+		"GO_COVERAGE_EXCLUDE_FILES":             "*_test.go",
+		"GO_COVERAGE_WAIVERS":                   "",
+		"GO_COVERAGE_POLICY_CRITICAL_PATHS":     "",
+		"GO_COVERAGE_POLICY_DECLINE_RUNS":       "0",
+		"GO_COVERAGE_POLICY_WARMUP_RUNS":        "0",
+		"GO_COVERAGE_POLICY_WARMUP_DAYS":        "0",
+		"GO_COVERAGE_HISTORY_ENABLED":           "true",
+		"GO_COVERAGE_HISTORY_RETENTION":         "90",
+		"GO_COVERAGE_HISTORY_MAX_ENTRIES":       "1000",
+		"GO_COVERAGE_CONFIDENCE_RUNS":           "10",
+		"GO_COVERAGE_BADGE_STYLE":               "flat",
+		"GO_COVERAGE_REPORT_THEME":              "github-dark",
+		"GO_COVERAGE_POLICY_NEW_FILE_THRESHOLD": "70",
+	} {
+		t.Setenv(key, value)
+	}
+}
+
+func TestRunConfigLint(t *testing.T) {
+	dir := t.TempDir()
+	isolateConfigLintEnv(t, dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "internal", "api"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal", "api", "server.go"), []byte("package api\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "coverage.txt"), []byte(
+		"mode: set\nexample.com/app/internal/api/server.go:3.20,5.2 1 1\n"), 0o600))
+	t.Chdir(dir)
+
+	output, err := executeCommand(t, "config", "lint")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No configuration problems found")
+
+	t.Setenv("GO_COVERAGE_EXCLUDE_PATHS", "vendor/,internal/legacy/")
+	output, err = executeCommand(t, "config", "lint")
+	require.NoError(t, err, "warnings pass")
+	assert.Contains(t, output, `GO_COVERAGE_EXCLUDE_PATHS [exclusions]: path "internal/legacy/" matches no Go file`)
+	_, err = executeCommand(t, "config", "lint", "--strict")
+	require.ErrorIs(t, err, ErrConfigLint)
+
+	t.Setenv("GO_COVERAGE_EXCLUDE_PATHS", "internal/")
+	t.Setenv("GO_COVERAGE_BADGE_STYLE", "flat-sqare")
+	output, err = executeCommand(t, "config", "lint")
+	require.ErrorIs(t, err, ErrConfigLint)
+	assert.Contains(t, output, `did you mean "flat-square"?`)
+	assert.Contains(t, output, "the exclusions leave no statements of coverage.txt")
+
+	_, err = executeCommand(t, "config", "lint", "--format", "yaml")
+	require.ErrorIs(t, err, ErrUnsupportedLintFormat)
+}
+
+func TestFormatLintFindings(t *testing.T) {
+	assert.Equal(t, "✅ No configuration problems found\n", formatLintFindings(nil))
+	assert.Equal(t, "❌ GO_COVERAGE_BADGE_STYLE [badge-style]: unknown badge style \"flat_square\"\n   → did you mean \"flat-square\"?\n"+
+		"⚠️  GO_COVERAGE_HISTORY_RETENTION [history]: history keeps 30 days\n",
+		formatLintFindings([]configlint.Finding{
+			{Check: configlint.CheckBadgeStyle, Severity: configlint.SeverityError, Setting: "GO_COVERAGE_BADGE_STYLE",
+				Message: `unknown badge style "flat_square"`, Fix: `did you mean "flat-square"?`},
+			{Check: configlint.CheckHistory, Severity: configlint.SeverityWarning, Setting: "GO_COVERAGE_HISTORY_RETENTION",
+				Message: "history keeps 30 days"},
+		}))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoCoverData indicates a GOCOVERDIR directory holding no coverage data
+var ErrNoCoverData = errors.New("no coverage data found in directory")
+
+// coverProfile returns the text profile of path. A directory is taken as the binary coverage
+// data go writes to GOCOVERDIR, such as during benchmark runs with -test.gocoverdir, and is
+// converted with go tool covdata into a temporary profile that cleanup removes.
+func coverProfile(ctx context.Context, path string) (profile string, cleanup func(), err error) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return path, func() {}, nil //nolint:nilerr // the parser reports missing profiles
+	}
+
+	tmpDir, err := os.MkdirTemp("", "go-coverage-covdata-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() { _ = os.RemoveAll(tmpDir) }
+
+	profile = filepath.Join(tmpDir, "coverage.txt")
+	output, err := exec.CommandContext(ctx, "go", "tool", "covdata", "textfmt", "-i="+path, "-o="+profile).CombinedOutput() //nolint:gosec // path is provided by the user
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to convert coverage data in %s: %w: %s", path, err, strings.TrimSpace(string(output)))
+	}
+	if info, err = os.Stat(profile); err != nil || info.Size() == 0 {
+		cleanup()
+		return "", nil, fmt.Errorf("%w: %s", ErrNoCoverData, path)
+	}
+	return profile, cleanup, nil
+}
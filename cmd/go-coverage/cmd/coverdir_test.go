@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("profile is used as is", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "coverage.txt")
+		require.NoError(t, os.WriteFile(path, []byte("mode: set\n"), 0o600))
+
+		profile, cleanup, err := coverProfile(ctx, path)
+		require.NoError(t, err)
+		defer cleanup()
+		assert.Equal(t, path, profile)
+	})
+
+	t.Run("missing path is left to the parser", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.txt")
+
+		profile, cleanup, err := coverProfile(ctx, path)
+		require.NoError(t, err)
+		defer cleanup()
+		assert.Equal(t, path, profile)
+	})
+
+	t.Run("empty coverage directory", func(t *testing.T) {
+		_, _, err := coverProfile(ctx, t.TempDir())
+		require.ErrorIs(t, err, ErrNoCoverData)
+	})
+}
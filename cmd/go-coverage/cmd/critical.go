@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// criticalMaxFiles limits the files below the requirement listed in the trace of a critical path
+const criticalMaxFiles = 5
+
+// criticalPathResults holds the files matching every critical path pattern to its requirement,
+// separately from the threshold. Patterns matching no file of the profile are skipped, since
+// the code may not exist on every branch.
+func criticalPathResults(cfg *config.Config, coverage *parser.CoverageData) []policy.Result {
+	rules, err := cfg.Policy.CriticalPathRules()
+	if err != nil {
+		return nil // Reported by config validation
+	}
+
+	results := make([]policy.Result, 0, len(rules))
+	for _, rule := range rules {
+		critical := coverage.Subset(func(file string) bool {
+			return branchmatch.Match(rule.Pattern, urlutil.CleanModulePathWithRepo(file, cfg.GitHub.Repository))
+		})
+		result := policy.Result{Rule: policy.RuleCritical, Outcome: policy.OutcomePass}
+		switch {
+		case critical.TotalLines == 0:
+			result.Outcome = policy.OutcomeSkip
+			result.Message = fmt.Sprintf("%s: no statements in the profile", rule.Pattern)
+		case critical.Percentage < rule.Threshold:
+			result.Outcome = policy.OutcomeFail
+			result.Message = fmt.Sprintf("%s: coverage %.2f%% is below the required %.2f%% (%d of %d statements missed)",
+				rule.Pattern, critical.Percentage, rule.Threshold, critical.TotalLines-critical.CoveredLines, critical.TotalLines)
+			result.Trace = criticalFilesBelow(cfg, critical, rule.Threshold)
+		default:
+			result.Message = fmt.Sprintf("%s: coverage %.2f%% meets the required %.2f%%", rule.Pattern, critical.Percentage, rule.Threshold)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// criticalFilesBelow lists the files of a critical path below its requirement, most missed
+// statements first
+func criticalFilesBelow(cfg *config.Config, critical *parser.CoverageData, threshold float64) []string {
+	type fileBelow struct {
+		path   string
+		file   *parser.FileCoverage
+		missed int
+	}
+	var files []fileBelow
+	for _, pkg := range critical.Packages {
+		for name, file := range pkg.Files {
+			if file.TotalLines > 0 && file.Percentage < threshold {
+				files = append(files, fileBelow{urlutil.CleanModulePathWithRepo(name, cfg.GitHub.Repository), file, file.TotalLines - file.CoveredLines})
+			}
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].missed != files[j].missed {
+			return files[i].missed > files[j].missed
+		}
+		return files[i].path < files[j].path
+	})
+
+	trace := make([]string, 0, criticalMaxFiles+1)
+	for i, below := range files {
+		if i == criticalMaxFiles {
+			trace = append(trace, fmt.Sprintf("… and %d more files", len(files)-criticalMaxFiles))
+			break
+		}
+		trace = append(trace, fmt.Sprintf("%s: %.2f%% (%d of %d statements missed)",
+			below.path, below.file.Percentage, below.missed, below.file.TotalLines))
+	}
+	return trace
+}
+
+// criticalStatus returns the commit status of the critical paths, or false when no critical
+// path matched any statement. A bypassed failure passes but stays visible in the description.
+func criticalStatus(decision *policy.Decision) (state, description string, ok bool) {
+	evaluated, failed, bypassed := 0, 0, 0
+	for _, result := range decision.Results {
+		if result.Rule != policy.RuleCritical || result.Outcome == policy.OutcomeSkip {
+			continue
+		}
+		evaluated++
+		switch result.Outcome {
+		case policy.OutcomeFail:
+			failed++
+		case policy.OutcomeWarn:
+			bypassed++
+		}
+	}
+
+	switch {
+	case evaluated == 0:
+		return "", "", false
+	case failed > 0:
+		return github.StatusFailure, fmt.Sprintf("%d of %d critical paths below their required coverage", failed, evaluated), true
+	case bypassed > 0:
+		return github.StatusSuccess, fmt.Sprintf("%d of %d critical paths below their required coverage (bypassed)", bypassed, evaluated), true
+	default:
+		return github.StatusSuccess, fmt.Sprintf("%d of %d critical paths meet their required coverage", evaluated, evaluated), true
+	}
+}
+
+// criticalStatusContext is the context of the critical path status on pull request commits
+const criticalStatusContext = "go-coverage/" + github.ContextCritical
+
+// criticalContexts returns the context createCriticalStatus posts for the decision, which the
+// stale status cleanup of the coverage status checks leaves alone
+func criticalContexts(decision *policy.Decision) []string {
+	if _, _, ok := criticalStatus(decision); !ok {
+		return nil
+	}
+	return []string{criticalStatusContext}
+}
+
+// criticalHandoffStatus returns the critical path status for the relay of a fork pull request
+func criticalHandoffStatus(decision *policy.Decision, reportURL string) []handoff.Status {
+	state, description, ok := criticalStatus(decision)
+	if !ok {
+		return nil
+	}
+	return []handoff.Status{{State: state, Context: criticalStatusContext, Description: description, TargetURL: reportURL}}
+}
+
+// createCriticalStatus posts the critical path status next to the coverage status checks of a
+// pull request. Failures are reported as warnings because the comment has already been posted.
+func createCriticalStatus(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	commitSHA, reportURL string, decision *policy.Decision,
+) {
+	state, description, ok := criticalStatus(decision)
+	if !ok {
+		return
+	}
+	err := client.CreateStatus(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, commitSHA, &github.StatusRequest{
+		State:       state,
+		TargetURL:   reportURL,
+		Description: description,
+		Context:     criticalStatusContext,
+	})
+	if err != nil {
+		cmd.Printf("Warning: failed to create critical paths status: %v\n", err)
+		return
+	}
+	cmd.Printf("Created critical paths status: %s (%s)\n", state, description)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+const criticalProfile = `mode: set
+github.com/example/repo/internal/auth/token.go:10.2,12.16 3 1
+github.com/example/repo/internal/auth/token.go:15.2,17.16 1 0
+github.com/example/repo/internal/auth/session.go:5.2,6.16 2 1
+github.com/example/repo/pkg/crypto/hash.go:3.2,4.16 2 1
+github.com/example/repo/lib/lib.go:3.2,4.16 10 0
+`
+
+func parseCriticalProfile(t *testing.T) *parser.CoverageData {
+	t.Helper()
+	coverage, err := parser.New().Parse(context.Background(), strings.NewReader(criticalProfile))
+	require.NoError(t, err)
+	return coverage
+}
+
+func TestCriticalPathResults(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Repository: "repo"},
+		Policy: config.PolicyConfig{
+			CriticalPaths:     []string{"internal/auth/**", "pkg/crypto/**=90", "internal/billing/**"},
+			CriticalThreshold: 100,
+		},
+	}
+	results := criticalPathResults(cfg, parseCriticalProfile(t))
+	require.Len(t, results, 3)
+
+	assert.Equal(t, policy.Result{
+		Rule:    policy.RuleCritical,
+		Outcome: policy.OutcomeFail,
+		Message: "internal/auth/**: coverage 83.33% is below the required 100.00% (1 of 6 statements missed)",
+		Trace:   []string{"internal/auth/token.go: 75.00% (1 of 4 statements missed)"},
+	}, results[0])
+	assert.Equal(t, policy.OutcomePass, results[1].Outcome)
+	assert.Equal(t, "pkg/crypto/**: coverage 100.00% meets the required 90.00%", results[1].Message)
+	assert.Equal(t, policy.OutcomeSkip, results[2].Outcome)
+
+	// The global threshold passes while the critical path fails the decision
+	cfg.Coverage.Threshold = 0
+	cfg.Policy.MaxDrop = -1
+	decision := evaluatePolicy(cfg, parseCriticalProfile(t), nil, nil, nil)
+	assert.False(t, decision.Passed)
+	assert.True(t, decision.Failed(policy.RuleCritical))
+	assert.False(t, decision.Failed(policy.RuleThreshold))
+
+	data := newPolicyTemplateData(decision)
+	require.Len(t, data.CriticalFailures, 1)
+	assert.Equal(t, results[0].Message, data.CriticalFailures[0].Message)
+}
+
+func TestCriticalStatus(t *testing.T) {
+	_, _, ok := criticalStatus(&policy.Decision{Results: []policy.Result{
+		{Rule: policy.RuleThreshold, Outcome: policy.OutcomeFail},
+		{Rule: policy.RuleCritical, Outcome: policy.OutcomeSkip},
+	}})
+	assert.False(t, ok, "no critical path was evaluated")
+
+	decision := &policy.Decision{}
+	decision.Add(policy.Result{Rule: policy.RuleCritical, Outcome: policy.OutcomePass})
+	decision.Add(policy.Result{Rule: policy.RuleCritical, Outcome: policy.OutcomeFail})
+	state, description, ok := criticalStatus(decision)
+	require.True(t, ok)
+	assert.Equal(t, github.StatusFailure, state)
+	assert.Equal(t, "1 of 2 critical paths below their required coverage", description)
+
+	decision.Downgrade("bypass token [hotfix] in the commit message")
+	state, description, _ = criticalStatus(decision)
+	assert.Equal(t, github.StatusSuccess, state)
+	assert.Equal(t, "1 of 2 critical paths below their required coverage (bypassed)", description)
+
+	statuses := criticalHandoffStatus(decision, "https://example.com/report")
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "go-coverage/coverage/critical", statuses[0].Context)
+}
+
+func TestCreateCriticalStatus(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+	fake := github.NewFake()
+	decision := &policy.Decision{Passed: true}
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	// Nothing is posted without critical paths
+	createCriticalStatus(context.Background(), cmd, cfg, fake, mergeGroupSHA, "", decision)
+	_, ok := fake.LatestStatus(mergeGroupSHA, "go-coverage/coverage/critical")
+	assert.False(t, ok)
+
+	decision.Add(policy.Result{Rule: policy.RuleCritical, Outcome: policy.OutcomePass})
+	createCriticalStatus(context.Background(), cmd, cfg, fake, mergeGroupSHA, "https://example.com/report", decision)
+	status, ok := fake.LatestStatus(mergeGroupSHA, "go-coverage/coverage/critical")
+	require.True(t, ok)
+	assert.Equal(t, github.StatusSuccess, status.State)
+	assert.Equal(t, "1 of 1 critical paths meet their required coverage", status.Description)
+	assert.Contains(t, out.String(), "Created critical paths status: success")
+}
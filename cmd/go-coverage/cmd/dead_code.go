@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/impact"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ErrUnsupportedDeadCodeFormat is returned for an unknown --format of the dead-code command
+var ErrUnsupportedDeadCodeFormat = errors.New("unsupported dead code format")
+
+// Output formats supported by the dead-code command
+const (
+	deadCodeFormatList     = "list"
+	deadCodeFormatMarkdown = "markdown"
+	deadCodeFormatJSON     = "json"
+)
+
+// deadCodeIssueMarker identifies the issue body written by the dead-code command
+const deadCodeIssueMarker = "[//]: # (go-coverage-dead-code)"
+
+// deadCodeReport lists the Go files no test executes and no test package links
+type deadCodeReport struct {
+	EligibleFiles int            `json:"eligible_files"`
+	Candidates    []deadCodeFile `json:"candidates"`
+	Statements    int            `json:"statements"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+}
+
+// deadCodeFile is a candidate for deletion
+type deadCodeFile struct {
+	Path       string `json:"path"`
+	Statements int    `json:"statements"` // 0 when the profile has no entry for the file
+}
+
+// newDeadCodeCmd creates the dead-code command
+func (c *Commands) newDeadCodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-code",
+		Short: "List Go files no test executes or links as candidates for deletion",
+		Long: `List the Go files that are eligible for coverage, have no covered statement in the
+profile, and belong to a package no test binary of the module links: the package has no
+tests, and no package with tests imports it, directly or indirectly. Such files are likely
+unused and candidates for deletion.
+
+The package graph is loaded with go list, so the command runs from the repository root (or
+--dir), and scans the module in GO_COVERAGE_MODULE_ROOT within it. Code reached only through build tags, reflection, go:linkname or other modules is
+reported too; check for callers before deleting.
+
+With --issue, one open issue titled GO_COVERAGE_DEAD_CODE_ISSUE_TITLE is created or updated
+with the report. Set GO_COVERAGE_DEAD_CODE to show the counts on the dashboard.`,
+		Example: `  go-coverage dead-code
+  go-coverage dead-code --format markdown -o dead-code.md
+  go-coverage dead-code --issue`,
+		RunE: c.runDeadCode,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "Coverage profile (defaults to the configured input file)")
+	cmd.Flags().String("dir", ".", "Root of the repository to scan")
+	cmd.Flags().String("format", deadCodeFormatList, "Output format (list, markdown or json)")
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file instead of the console")
+	cmd.Flags().Bool("issue", false, "Create or update the dead code issue with the report")
+	addDryRunFlag(cmd, "Print the report without updating the issue")
+	return cmd
+}
+
+// runDeadCode executes the dead-code command
+func (c *Commands) runDeadCode(cmd *cobra.Command, _ []string) error {
+	inputFile, _ := cmd.Flags().GetString("input")
+	dir, _ := cmd.Flags().GetString("dir")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+	issue, _ := cmd.Flags().GetBool("issue")
+	dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+	if format != deadCodeFormatList && format != deadCodeFormatMarkdown && format != deadCodeFormatJSON {
+		return fmt.Errorf("%w: %q (expected list, markdown or json)", ErrUnsupportedDeadCodeFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	p := parser.NewWithConfig(&parser.Config{
+		ExcludePaths:     cfg.Coverage.ExcludePaths,
+		ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+		ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+		ExcludePresets:   cfg.Coverage.ExcludePresets,
+		Limits:           cfg.ParserLimits(),
+		ModuleRewrites:   cfg.ModuleRewrites(),
+		ModuleRoot:       cfg.Coverage.ModuleRoot,
+		DiscoveryIgnore:  cfg.Coverage.DiscoveryIgnore,
+	})
+	coverage, err := p.ParseFile(ctx, inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse coverage: %w", err)
+	}
+
+	report, err := findDeadCode(ctx, p, coverage, dir)
+	if err != nil {
+		return err
+	}
+
+	var output string
+	switch format {
+	case deadCodeFormatMarkdown:
+		output = renderDeadCodeMarkdown(report)
+	case deadCodeFormatJSON:
+		data, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to encode dead code report: %w", marshalErr)
+		}
+		output = string(data) + "\n"
+	default:
+		output = renderDeadCodeList(report)
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+	} else {
+		if err = os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+			return fmt.Errorf("failed to write dead code report: %w", err)
+		}
+		cmd.Printf("Dead code report written to %s\n", outputPath)
+	}
+
+	if !issue || dryRun {
+		return nil
+	}
+	if err = requireNetwork(cmd, cfg, "updating the dead code issue"); err != nil {
+		return err
+	}
+	if cfg.GitHub.Token == "" {
+		return ErrGitHubTokenRequired
+	}
+	if cfg.GitHub.Owner == "" {
+		return ErrGitHubOwnerRequired
+	}
+	if cfg.GitHub.Repository == "" {
+		return ErrGitHubRepoRequired
+	}
+
+	client, err := newGitHubClient(cfg, "go-coverage/2.0")
+	if err != nil {
+		return err
+	}
+	return updateDeadCodeIssue(ctx, cmd, cfg, client, report)
+}
+
+// findDeadCode discovers the eligible Go files of the module in the repository at root and
+// keeps the dead code candidates
+func findDeadCode(ctx context.Context, p *parser.Parser, coverage *parser.CoverageData, root string) (*deadCodeReport, error) {
+	eligible, err := p.DiscoverEligibleFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return deadCodeCandidates(ctx, coverage, p.ModuleDir(root), eligible)
+}
+
+// deadCodeCandidates keeps the eligible files, relative to root, with no covered statement
+// whose package no test binary links
+func deadCodeCandidates(ctx context.Context, coverage *parser.CoverageData, root string, eligible []string) (*deadCodeReport, error) {
+	report := &deadCodeReport{GeneratedAt: time.Now().UTC(), Candidates: []deadCodeFile{}}
+	statements := make(map[string]int)
+	var uncovered []string
+	for _, file := range eligible {
+		if strings.HasSuffix(file, "_test.go") || strings.Contains("/"+filepath.ToSlash(file), "/testdata/") {
+			continue
+		}
+		report.EligibleFiles++
+		fileCoverage := findFileCoverage(coverage, filepath.ToSlash(file))
+		if fileCoverage != nil && fileCoverage.CoveredLines > 0 {
+			continue
+		}
+		if fileCoverage != nil {
+			statements[file] = fileCoverage.TotalLines
+		}
+		uncovered = append(uncovered, file)
+	}
+	if len(uncovered) == 0 {
+		return report, nil
+	}
+
+	// The package directories from go list are absolute
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	graph, err := impact.LoadGraph(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range graph.Untested(root, uncovered) {
+		report.Candidates = append(report.Candidates, deadCodeFile{Path: filepath.ToSlash(file), Statements: statements[file]})
+		report.Statements += statements[file]
+	}
+	return report, nil
+}
+
+// newDeadCodeDashboardData summarizes the candidates for the dashboard
+func newDeadCodeDashboardData(report *deadCodeReport) *dashboard.DeadCode {
+	files := make([]string, 0, len(report.Candidates))
+	for _, file := range report.Candidates {
+		files = append(files, file.Path)
+	}
+	return &dashboard.DeadCode{EligibleFiles: report.EligibleFiles, Statements: report.Statements, Files: files}
+}
+
+// renderDeadCodeList renders the candidates one per line, for the console and scripts
+func renderDeadCodeList(report *deadCodeReport) string {
+	var b strings.Builder
+	for _, file := range report.Candidates {
+		b.WriteString(file.Path)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderDeadCodeMarkdown renders the report as Markdown, for files and the issue body
+func renderDeadCodeMarkdown(report *deadCodeReport) string {
+	var b strings.Builder
+	b.WriteString("## 🧹 Dead code candidates\n\n")
+	if len(report.Candidates) == 0 {
+		fmt.Fprintf(&b, "None of the %d Go files lacks both coverage and a test package linking it.\n", report.EligibleFiles)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "**%d** of %d Go files (%d statements) have no coverage and are not linked into any test binary. ",
+		len(report.Candidates), report.EligibleFiles, report.Statements)
+	b.WriteString("They may be unused: check for callers through build tags, reflection, `go:linkname` or other modules before deleting them.\n\n")
+	b.WriteString("| File | Statements |\n")
+	b.WriteString("|------|-----------:|\n")
+	for _, file := range report.Candidates {
+		fmt.Fprintf(&b, "| `%s` | %d |\n", file.Path, file.Statements)
+	}
+	return b.String()
+}
+
+// updateDeadCodeIssue creates the dead code issue or refreshes its body when the report changed
+func updateDeadCodeIssue(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, report *deadCodeReport) error {
+	title := cfg.Analytics.DeadCodeIssueTitle
+	body := deadCodeIssueMarker + "\n\n" + renderDeadCodeMarkdown(report)
+
+	thread, err := client.FindThread(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, github.ThreadIssue, title)
+	if errors.Is(err, github.ErrThreadNotFound) {
+		if len(report.Candidates) == 0 {
+			cmd.Printf("No dead code candidates, no issue to open\n")
+			return nil
+		}
+		thread, err = client.CreateThread(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, github.ThreadIssue, "", title, body)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("🧹 Created dead code issue #%d: %s\n", thread.Number, thread.URL)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if thread.Body == body {
+		cmd.Printf("Dead code issue #%d is up to date: %s\n", thread.Number, thread.URL)
+		return nil
+	}
+	if err = client.UpdateThreadBody(ctx, thread, body); err != nil {
+		return err
+	}
+	cmd.Printf("🧹 Updated dead code issue #%d: %s\n", thread.Number, thread.URL)
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// initDeadCodeModule writes a module where app is tested and links lib, while legacy and tools
+// are neither tested nor imported by anything tested
+func initDeadCodeModule(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for name, content := range map[string]string{
+		"go.mod":              "module example.com/dead\n\ngo 1.21\n",
+		"app/app.go":          "package app\n\nimport \"example.com/dead/lib\"\n\nfunc Run() int {\n\treturn lib.Value()\n}\n",
+		"app/extra.go":        "package app\n\nfunc Extra() int {\n\treturn 2\n}\n",
+		"app/app_test.go":     "package app\n\nimport \"testing\"\n\nfunc TestRun(t *testing.T) {\n\tRun()\n}\n",
+		"lib/lib.go":          "package lib\n\nfunc Value() int {\n\treturn 1\n}\n",
+		"legacy/legacy.go":    "package legacy\n\nfunc Old() int {\n\tx := 1\n\treturn x\n}\n",
+		"tools/gen.go":        "package tools\n\nfunc Gen() {}\n",
+		"app/testdata/fix.go": "package fix\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	profile := filepath.Join(t.TempDir(), "coverage.txt")
+	require.NoError(t, os.WriteFile(profile, []byte(`mode: set
+example.com/dead/app/app.go:5.18,7.2 1 1
+example.com/dead/app/extra.go:3.20,5.2 1 0
+example.com/dead/lib/lib.go:3.20,5.2 1 1
+example.com/dead/legacy/legacy.go:3.18,6.2 2 0
+`), 0o600))
+	return dir, profile
+}
+
+func TestDeadCodeCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	_, profile := initDeadCodeModule(t)
+
+	t.Run("list", func(t *testing.T) {
+		output, err := executeCommand(t, "dead-code", "-i", profile)
+		require.NoError(t, err)
+		assert.Equal(t, "legacy/legacy.go\ntools/gen.go\n", output)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "dead-code.md")
+		output, err := executeCommand(t, "dead-code", "-i", profile, "--format", "markdown", "-o", outputPath)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Dead code report written to")
+
+		data, err := os.ReadFile(outputPath) //nolint:gosec // test file path
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "**2** of 5 Go files (2 statements) have no coverage")
+		assert.Contains(t, string(data), "| `legacy/legacy.go` | 2 |")
+		assert.Contains(t, string(data), "| `tools/gen.go` | 0 |")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := executeCommand(t, "dead-code", "-i", profile, "--format", "json")
+		require.NoError(t, err)
+		assert.Contains(t, output, `"eligible_files": 5`)
+		assert.Contains(t, output, `"path": "legacy/legacy.go"`)
+	})
+
+	t.Run("issue dry run", func(t *testing.T) {
+		_, err := executeCommand(t, "dead-code", "-i", profile, "--issue", "--dry-run")
+		require.NoError(t, err)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := executeCommand(t, "dead-code", "-i", profile, "--format", "pdf")
+		require.ErrorIs(t, err, ErrUnsupportedDeadCodeFormat)
+
+		_, err = executeCommand(t, "dead-code", "-i", filepath.Join(t.TempDir(), "missing.txt"))
+		require.Error(t, err)
+	})
+}
+
+func TestNewDeadCodeDashboardData(t *testing.T) {
+	data := newDeadCodeDashboardData(&deadCodeReport{
+		EligibleFiles: 6,
+		Statements:    2,
+		Candidates:    []deadCodeFile{{Path: "legacy/legacy.go", Statements: 2}, {Path: "tools/gen.go"}},
+	})
+	assert.Equal(t, 6, data.EligibleFiles)
+	assert.Equal(t, 2, data.Statements)
+	assert.Equal(t, []string{"legacy/legacy.go", "tools/gen.go"}, data.Files)
+}
+
+func TestUpdateDeadCodeIssue(t *testing.T) {
+	cfg := &config.Config{
+		GitHub:    config.GitHubConfig{Owner: "owner", Repository: "repo"},
+		Analytics: config.AnalyticsConfig{DeadCodeIssueTitle: "Dead code candidates"},
+	}
+	report := &deadCodeReport{EligibleFiles: 3, Statements: 2, Candidates: []deadCodeFile{{Path: "legacy/legacy.go", Statements: 2}}}
+	current := deadCodeIssueMarker + "\n\n" + renderDeadCodeMarkdown(report)
+
+	tests := []struct {
+		name       string
+		body       string
+		report     *deadCodeReport
+		operations []string
+		output     string
+	}{
+		{"create", "", report, []string{"search", "repository", "createIssue"}, "Created dead code issue #2"},
+		{"nothing to report", "", &deadCodeReport{EligibleFiles: 3}, []string{"search"}, "no issue to open"},
+		{"changed", deadCodeIssueMarker + "\n\nolder report", report, []string{"search", "updateIssue"}, "Updated dead code issue #1"},
+		{"up to date", current, report, []string{"search"}, "is up to date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, operations := threadServer(t, cfg.Analytics.DeadCodeIssueTitle, tt.body)
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+
+			require.NoError(t, updateDeadCodeIssue(context.Background(), cmd, cfg, client, tt.report))
+			assert.Equal(t, tt.operations, *operations)
+			assert.Contains(t, out.String(), tt.output)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/debt"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// debtMaxFiles limits the files with the highest debt listed on the dashboard
+const debtMaxFiles = 5
+
+// measureDebt weighs the uncovered statements of the profile by the complexity of their
+// functions, read from the repository, and by the critical weight on critical paths
+func measureDebt(cfg *config.Config, coverage *parser.CoverageData) *debt.Report {
+	root, err := cfg.GetRepositoryRoot()
+	if err != nil {
+		root = "."
+	}
+	rules, _ := cfg.Policy.CriticalPathRules() // Reported by config validation
+	return debt.Measure(coverage, debt.Options{
+		Root: root,
+		Path: func(file string) string {
+			return urlutil.CleanModulePathWithRepo(file, cfg.GitHub.Repository)
+		},
+		Critical: func(path string) bool {
+			return slices.ContainsFunc(rules, func(rule config.CriticalPath) bool {
+				return branchmatch.Match(rule.Pattern, path)
+			})
+		},
+		CriticalWeight: cfg.Analytics.DebtCriticalWeight,
+	})
+}
+
+// newHistoryDebt is the coverage debt recorded with the run in the history
+func newHistoryDebt(report *debt.Report) *history.Debt {
+	if report == nil {
+		return nil
+	}
+	return &history.Debt{Uncovered: report.Uncovered, Weighted: report.Debt}
+}
+
+// newCoverageDebtData follows the weighted coverage debt over the earlier runs that recorded it,
+// newest first, and the current run, with the files of the current run holding the most debt
+func newCoverageDebtData(cfg *config.Config, report *debt.Report, entries []history.Entry) *dashboard.CoverageDebt {
+	if report == nil {
+		return nil
+	}
+
+	coverageDebt := &dashboard.CoverageDebt{Uncovered: report.Uncovered, Weighted: report.Debt}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Debt == nil || entry.CommitSHA == cfg.GitHub.CommitSHA {
+			continue
+		}
+		coverageDebt.Points = append(coverageDebt.Points, dashboard.DebtPoint{
+			Timestamp: entry.Timestamp,
+			CommitSHA: entry.CommitSHA,
+			Weighted:  entry.Debt.Weighted,
+		})
+	}
+	slices.Reverse(coverageDebt.Points)
+	if len(coverageDebt.Points) > 0 {
+		coverageDebt.Reduced = coverageDebt.Points[0].Weighted - report.Debt
+	}
+	coverageDebt.Points = append(coverageDebt.Points, dashboard.DebtPoint{
+		CommitSHA: cfg.GitHub.CommitSHA,
+		Weighted:  report.Debt,
+	})
+
+	for _, file := range report.Files[:min(len(report.Files), debtMaxFiles)] {
+		coverageDebt.Files = append(coverageDebt.Files, dashboard.DebtFile{
+			Path:      file.Path,
+			Uncovered: file.Uncovered,
+			Weighted:  file.Debt,
+			Critical:  file.Critical,
+		})
+	}
+	return coverageDebt
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/debt"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestMeasureDebt(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "auth"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "auth", "token.go"), []byte(`package auth
+
+func Check(ok bool) bool {
+	if ok {
+		return true
+	}
+	return false
+}
+`), 0o600))
+
+	file := &parser.FileCoverage{Statements: []parser.Statement{
+		{StartLine: 4, EndLine: 6, NumStmt: 1, Count: 1},
+		{StartLine: 7, EndLine: 7, NumStmt: 1},
+	}}
+	coverage := &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"github.com/owner/repo/auth": {Files: map[string]*parser.FileCoverage{"github.com/owner/repo/auth/token.go": file}},
+	}}
+	cfg := &config.Config{
+		GitHub:    config.GitHubConfig{Repository: "repo"},
+		Analytics: config.AnalyticsConfig{CoverageDebt: true, DebtCriticalWeight: 2},
+		Policy:    config.PolicyConfig{CriticalPaths: []string{"auth/**"}},
+	}
+	t.Chdir(root)
+
+	report := measureDebt(cfg, coverage)
+	assert.Equal(t, 1, report.Uncovered)
+	assert.InDelta(t, 2.2, report.Debt, 1e-9) // Complexity 2, on a critical path
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, debt.File{Path: "auth/token.go", Uncovered: 1, Debt: report.Debt, Critical: true}, report.Files[0])
+	assert.Equal(t, &history.Debt{Uncovered: 1, Weighted: report.Debt}, newHistoryDebt(report))
+	assert.Nil(t, newHistoryDebt(nil))
+}
+
+func TestNewCoverageDebtData(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{CommitSHA: "head"}}
+	now := time.Now()
+	entries := []history.Entry{ // newest first, as the history returns them
+		{CommitSHA: "head", Debt: &history.Debt{Weighted: 1}},
+		{CommitSHA: "second", Timestamp: now.Add(-time.Hour), Debt: &history.Debt{Uncovered: 700, Weighted: 1000}},
+		{CommitSHA: "unmeasured"},
+		{CommitSHA: "first", Timestamp: now.Add(-2 * time.Hour), Debt: &history.Debt{Uncovered: 800, Weighted: 1200}},
+	}
+
+	assert.Nil(t, newCoverageDebtData(cfg, nil, entries))
+
+	files := make([]debt.File, 0, debtMaxFiles+2)
+	for range debtMaxFiles + 2 {
+		files = append(files, debt.File{Path: "file.go", Uncovered: 1, Debt: 1})
+	}
+	coverageDebt := newCoverageDebtData(cfg, &debt.Report{Uncovered: 640, Debt: 900, Files: files}, entries)
+	require.NotNil(t, coverageDebt)
+	require.Len(t, coverageDebt.Points, 3)
+	assert.Equal(t, []string{"first", "second", "head"},
+		[]string{coverageDebt.Points[0].CommitSHA, coverageDebt.Points[1].CommitSHA, coverageDebt.Points[2].CommitSHA})
+	assert.InDelta(t, 300, coverageDebt.Reduced, 1e-9)
+	assert.Len(t, coverageDebt.Files, debtMaxFiles)
+
+	// The first run measuring debt has nothing to compare with
+	coverageDebt = newCoverageDebtData(cfg, &debt.Report{Debt: 900}, nil)
+	assert.Len(t, coverageDebt.Points, 1)
+	assert.Zero(t, coverageDebt.Reduced)
+}
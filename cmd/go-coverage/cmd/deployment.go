@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+var (
+	// ErrDeploymentCommitRequired is returned when the commit a deployment belongs to is unknown
+	ErrDeploymentCommitRequired = errors.New("commit SHA is required for a deployment")
+	// ErrDeploymentURLRequired is returned when no report URL can be derived for a deployment
+	ErrDeploymentURLRequired = errors.New("report URL is required for a deployment")
+	// ErrInvalidDeploymentState is returned for a deployment state GitHub does not accept
+	ErrInvalidDeploymentState = errors.New("invalid deployment state")
+)
+
+// deploymentStates are the states the deployment command sets
+var deploymentStates = []string{github.DeploymentSuccess, github.DeploymentFailure, github.DeploymentInProgress}
+
+// deploymentOptions describes the deployment created for a Pages publish
+type deploymentOptions struct {
+	environment string
+	url         string // Environment URL, the report
+	sha         string
+	state       string
+	logURL      string
+	dryRun      bool
+}
+
+// newDeploymentCmd creates the deployment command
+func (c *Commands) newDeploymentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deployment",
+		Short: "Record a Pages publish as a GitHub Deployment",
+		Long: `Create a GitHub Deployment of the commit under test to the coverage environment, with the
+report as the environment URL, after the coverage site was published to GitHub Pages. The
+"View deployment" button of the commit and its pull request then opens the coverage report:
+the dashboard for the main branch, the report of the pull request otherwise.
+
+Deployments are only created with GO_COVERAGE_REPORT_DEPLOYMENT=true, so the command can run
+unconditionally after the publish step. Earlier deployments of the environment are marked
+inactive, and the deployment does not wait for the commit statuses, so a failing coverage
+gate never keeps its report from being linked. The workflow token needs the
+"deployments: write" permission.`,
+		Example: `  # After the Pages publish step
+  go-coverage deployment
+
+  # Link a report served elsewhere, in a custom environment
+  go-coverage deployment --environment coverage-report --url https://coverage.example.com/`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts := deploymentOptions{}
+			opts.environment, _ = cmd.Flags().GetString("environment")
+			opts.url, _ = cmd.Flags().GetString("url")
+			opts.sha, _ = cmd.Flags().GetString("sha")
+			opts.state, _ = cmd.Flags().GetString("state")
+			opts.dryRun, _ = cmd.Flags().GetBool(flagNameDryRun)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if !cfg.Report.Deployment {
+				cmd.Println("ℹ️  Deployments are disabled (set GO_COVERAGE_REPORT_DEPLOYMENT=true to create them)")
+				return nil
+			}
+			opts.environment = cmp.Or(opts.environment, cfg.Report.DeploymentEnvironment)
+			opts.url = cmp.Or(opts.url, cfg.GetReportURL())
+			opts.sha = cmp.Or(opts.sha, cfg.GitHub.CommitSHA)
+			opts.logURL = workflowRunURL(cfg)
+
+			if err = requireNetwork(cmd, cfg, "creating a deployment"); err != nil {
+				return err
+			}
+			if cfg.GitHub.Token == "" {
+				return ErrGitHubTokenRequired
+			}
+			if cfg.GitHub.Owner == "" {
+				return ErrGitHubOwnerRequired
+			}
+			if cfg.GitHub.Repository == "" {
+				return ErrGitHubRepoRequired
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+			return createDeployment(ctx, cmd, cfg, client, opts)
+		},
+	}
+
+	cmd.Flags().StringP("environment", "e", "", "Deployment environment (default: GO_COVERAGE_REPORT_DEPLOYMENT_ENVIRONMENT)")
+	cmd.Flags().String("url", "", "Environment URL (default: the report URL of the run)")
+	cmd.Flags().String("sha", "", "Commit the deployment belongs to (default: the commit under test)")
+	cmd.Flags().String("state", github.DeploymentSuccess, "Deployment state (success, failure or in_progress)")
+	addDryRunFlag(cmd, "Print the deployment without creating it")
+
+	return cmd
+}
+
+// createDeployment creates a deployment of the commit to the environment and sets its state,
+// which links the environment to the report
+func createDeployment(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.DeploymentAPI, opts deploymentOptions) error {
+	if !slices.Contains(deploymentStates, opts.state) {
+		return fmt.Errorf("%w: %q (expected success, failure or in_progress)", ErrInvalidDeploymentState, opts.state)
+	}
+	if opts.sha == "" {
+		return ErrDeploymentCommitRequired
+	}
+	if opts.url == "" {
+		return ErrDeploymentURLRequired
+	}
+
+	description := "Coverage report of " + shortSHA(opts.sha)
+	if cfg.GitHub.PullRequest > 0 {
+		description = fmt.Sprintf("Coverage report of pull request #%d", cfg.GitHub.PullRequest)
+	}
+
+	cmd.Printf("🚀 Deployment to %s for %s: %s\n", opts.environment, shortSHA(opts.sha), opts.url)
+	if opts.dryRun {
+		cmd.Println("   Dry run, no deployment created")
+		return nil
+	}
+
+	owner, repo := cfg.GitHub.Owner, cfg.GitHub.Repository
+	deployment, err := client.CreateDeployment(ctx, owner, repo, &github.DeploymentRequest{
+		Ref:              opts.sha,
+		Environment:      opts.environment,
+		Description:      description,
+		RequiredContexts: []string{},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = client.CreateDeploymentStatus(ctx, owner, repo, deployment.ID, &github.DeploymentStatusRequest{
+		State:          opts.state,
+		EnvironmentURL: opts.url,
+		LogURL:         opts.logURL,
+		Description:    description,
+		AutoInactive:   true,
+	}); err != nil {
+		return err
+	}
+
+	cmd.Printf("   ✅ Deployment %d is %s\n", deployment.ID, opts.state)
+	return nil
+}
+
+// workflowRunURL returns the URL of the GitHub Actions run, empty outside Actions
+func workflowRunURL(cfg *config.Config) string {
+	runID := os.Getenv("GITHUB_RUN_ID")
+	if runID == "" || cfg.GitHub.Owner == "" || cfg.GitHub.Repository == "" {
+		return ""
+	}
+	server := cmp.Or(os.Getenv("GITHUB_SERVER_URL"), "https://github.com")
+	return fmt.Sprintf("%s/%s/%s/actions/runs/%s", server, cfg.GitHub.Owner, cfg.GitHub.Repository, runID)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+func TestCreateDeployment(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo", PullRequest: 7}}
+	run := func(t *testing.T, fake *github.Fake, opts deploymentOptions) (string, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		err := createDeployment(ctx, cmd, cfg, fake, opts)
+		return out.String(), err
+	}
+	opts := deploymentOptions{
+		environment: "coverage",
+		url:         "https://owner.github.io/repo/reports/pr/7/coverage.html",
+		sha:         "abc1234def",
+		state:       github.DeploymentSuccess,
+		logURL:      "https://github.com/owner/repo/actions/runs/1",
+	}
+
+	t.Run("created", func(t *testing.T) {
+		fake := github.NewFake()
+		output, err := run(t, fake, opts)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Deployment 1 is success")
+
+		require.Len(t, fake.Deployments, 1)
+		assert.Equal(t, "coverage", fake.Deployments[0].Environment)
+		assert.Equal(t, "abc1234def", fake.Deployments[0].Ref)
+		assert.Equal(t, "Coverage report of pull request #7", fake.Deployments[0].Description)
+		assert.Equal(t, []github.DeploymentStatusRequest{{
+			State:          github.DeploymentSuccess,
+			EnvironmentURL: opts.url,
+			LogURL:         opts.logURL,
+			Description:    "Coverage report of pull request #7",
+			AutoInactive:   true,
+		}}, fake.DeploymentStatuses[1])
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := github.NewFake()
+		dryRun := opts
+		dryRun.dryRun = true
+		output, err := run(t, fake, dryRun)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Deployment to coverage for abc1234")
+		assert.Empty(t, fake.Deployments)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := opts
+		invalid.state = "done"
+		_, err := run(t, github.NewFake(), invalid)
+		require.ErrorIs(t, err, ErrInvalidDeploymentState)
+
+		invalid = opts
+		invalid.sha = ""
+		_, err = run(t, github.NewFake(), invalid)
+		require.ErrorIs(t, err, ErrDeploymentCommitRequired)
+
+		invalid = opts
+		invalid.url = ""
+		_, err = run(t, github.NewFake(), invalid)
+		require.ErrorIs(t, err, ErrDeploymentURLRequired)
+	})
+}
+
+func TestWorkflowRunURL(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+
+	t.Setenv("GITHUB_RUN_ID", "")
+	assert.Empty(t, workflowRunURL(cfg))
+
+	t.Setenv("GITHUB_RUN_ID", "123")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.example.com")
+	assert.Equal(t, "https://github.example.com/owner/repo/actions/runs/123", workflowRunURL(cfg))
+}
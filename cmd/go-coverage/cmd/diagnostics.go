@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/diagnostics"
+	"github.com/mrz1836/go-coverage/internal/redact"
+)
+
+// envLogFormat is the environment variable equivalent of --log-format
+const envLogFormat = "GO_COVERAGE_LOG_FORMAT"
+
+// reportDiagnosis follows a failure that has an error code with its remediation: on stderr, as
+// text or, with the json log format, as a JSON object, and in the workflow step summary
+func reportDiagnosis(cmd *cobra.Command, err error) {
+	diagnosis, ok := diagnostics.Classify(err)
+	if !ok {
+		return
+	}
+	diagnosis.Message = redact.FromEnv().String(diagnosis.Message)
+
+	if logFormat(cmd) == "json" {
+		data, marshalErr := json.Marshal(diagnosis)
+		if marshalErr == nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), string(data))
+		}
+	} else {
+		_, _ = fmt.Fprint(cmd.ErrOrStderr(), diagnosis.Text())
+	}
+
+	if summaryErr := appendStepSummary(diagnosis.Markdown()); summaryErr != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to write step summary: %v\n", summaryErr)
+	}
+}
+
+// logFormat returns the --log-format of the run, or its environment variable when the flag is
+// not set
+func logFormat(cmd *cobra.Command) string {
+	format, _ := cmd.PersistentFlags().GetString("log-format")
+	if !cmd.PersistentFlags().Changed("log-format") && os.Getenv(envLogFormat) != "" {
+		format = os.Getenv(envLogFormat)
+	}
+	return format
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/diagnostics"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestReportDiagnosis(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+
+	t.Run("text and step summary", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		t.Setenv(envLogFormat, "")
+		summaryPath := filepath.Join(t.TempDir(), "summary.md")
+		t.Setenv(envStepSummary, summaryPath)
+
+		output, err := executeCommand(t, "parse", "--file", missing)
+		require.ErrorIs(t, err, parser.ErrProfileNotFound)
+		assert.Contains(t, output, "[GCV101] Coverage profile not found")
+		assert.Contains(t, output, diagnostics.DocsURL+"#gcv101")
+
+		summary, readErr := os.ReadFile(summaryPath) //nolint:gosec // test file
+		require.NoError(t, readErr)
+		assert.Contains(t, string(summary), "> [!CAUTION]")
+		assert.Contains(t, string(summary), "GCV101")
+	})
+
+	t.Run("json log format", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		t.Setenv(envLogFormat, "json")
+		t.Setenv(envStepSummary, "")
+
+		output, err := executeCommand(t, "parse", "--file", missing)
+		require.Error(t, err)
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		var diagnosis diagnostics.Diagnosis
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &diagnosis))
+		assert.Equal(t, diagnostics.CodeProfileNotFound, diagnosis.Code)
+		assert.Contains(t, diagnosis.Message, "missing.txt")
+	})
+
+	t.Run("unclassified errors are not reported", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		t.Setenv(envStepSummary, "")
+
+		output, err := executeCommand(t, "health", "--format", "yaml")
+		require.ErrorIs(t, err, ErrUnsupportedHealthFormat)
+		assert.NotContains(t, output, "[GCV")
+	})
+}
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// Diff report command errors
+var (
+	ErrDiffReportSinceRequired     = errors.New("--since is required")
+	ErrUnsupportedDiffReportFormat = errors.New("unsupported diff report format")
+)
+
+// Output formats supported by the diff-report command
+const (
+	diffReportFormatMarkdown = "markdown"
+	diffReportFormatHTML     = "html"
+)
+
+// diffReport is the coverage of the statements on lines changed since a ref
+type diffReport struct {
+	Since        string
+	SinceSHA     string
+	ChangedFiles int
+	Files        []diffReportFile
+	Statements   int
+	Covered      int
+	Coverage     float64
+	GeneratedAt  time.Time
+}
+
+// diffReportFile is the coverage of the changed statements of one file
+type diffReportFile struct {
+	Path         string
+	ChangedLines int
+	Statements   int
+	Covered      int
+	Coverage     float64
+	Uncovered    string // Line ranges of the missed statements, such as "12-14, 20"
+}
+
+// newDiffReportCmd creates the diff-report command
+func (c *Commands) newDiffReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff-report",
+		Short: "Report the coverage of the lines changed since a ref",
+		Long: `Compute the coverage of the statements on lines added or modified between a git ref
+and HEAD, independent of pull requests. Useful for trunk-based teams that want the
+coverage of a release, a sprint or this week's changes.
+
+Every file is listed with the number of changed statements, their coverage and the
+lines of the statements the tests miss. Files without statements on changed lines,
+such as documentation or test files, are counted but not listed.
+
+The format follows the --output extension (.html writes HTML) unless --format is given.`,
+		Example: `  go-coverage diff-report --since v1.4.0
+  go-coverage diff-report --since "$(git rev-list -1 --before='1 week ago' HEAD)" -o changes.html`,
+		RunE: c.runDiffReport,
+	}
+
+	cmd.Flags().String("since", "", "Git ref whose changes up to HEAD are reported")
+	cmd.Flags().StringP("input", "i", "", "Coverage profile of HEAD (defaults to the configured input file)")
+	cmd.Flags().String("format", diffReportFormatMarkdown, "Output format (markdown or html)")
+	cmd.Flags().StringP("output", "o", "", "Write the report to a file instead of the console (a .html file implies --format html)")
+	return cmd
+}
+
+// runDiffReport executes the diff-report command
+func (c *Commands) runDiffReport(cmd *cobra.Command, _ []string) error {
+	since, _ := cmd.Flags().GetString("since")
+	inputFile, _ := cmd.Flags().GetString("input")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if since == "" {
+		return ErrDiffReportSinceRequired
+	}
+	if !cmd.Flags().Changed("format") && strings.EqualFold(filepath.Ext(outputPath), ".html") {
+		format = diffReportFormatHTML
+	}
+	if format != diffReportFormatMarkdown && format != diffReportFormatHTML {
+		return fmt.Errorf("%w: %q (expected markdown or html)", ErrUnsupportedDiffReportFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	files, err := gitDiffFiles(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	p := parser.NewWithConfig(&parser.Config{
+		ExcludePaths:     cfg.Coverage.ExcludePaths,
+		ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+		ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+		ExcludePresets:   cfg.Coverage.ExcludePresets,
+		Limits:           cfg.ParserLimits(),
+		ModuleRewrites:   cfg.ModuleRewrites(),
+		ModuleRoot:       cfg.Coverage.ModuleRoot,
+	})
+	coverage, err := p.ParseFile(ctx, inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse coverage: %w", err)
+	}
+
+	report := newDiffReport(coverage, files)
+	report.Since = since
+	report.SinceSHA = resolveGitRef(ctx, since)
+
+	output := renderDiffReportMarkdown(report)
+	if format == diffReportFormatHTML {
+		if output, err = renderDiffReportHTML(report); err != nil {
+			return err
+		}
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+		return nil
+	}
+	if err = os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write diff report: %w", err)
+	}
+	cmd.Printf("Diff report written to %s\n", outputPath)
+	return nil
+}
+
+// gitDiffFiles returns the files under the working directory changed between since and HEAD,
+// with the patch of each, in the form the pull request diff uses
+func gitDiffFiles(ctx context.Context, since string) ([]github.PRFile, error) {
+	if strings.HasPrefix(since, "-") {
+		return nil, fmt.Errorf("%w: invalid ref %q", ErrDiffReportSinceRequired, since)
+	}
+	output, err := exec.CommandContext(ctx, "git", "diff", "--unified=0", "--no-color", "--no-ext-diff", //nolint:gosec // since cannot be an option
+		"--relative", "--diff-filter=d", since, "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", since, err)
+	}
+	return parseGitDiff(string(output)), nil
+}
+
+// parseGitDiff splits the output of git diff into files, keeping the hunks of each as its patch
+func parseGitDiff(diff string) []github.PRFile {
+	var files []github.PRFile
+	var patch []string
+	var current *github.PRFile
+	flush := func() {
+		if current != nil && current.Filename != "" {
+			current.Patch = strings.Join(patch, "\n")
+			files = append(files, *current)
+		}
+		current, patch = nil, nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			current = &github.PRFile{Status: "modified"}
+		case current == nil:
+			// Outside of a file
+		case len(patch) == 0 && strings.HasPrefix(line, "+++ "):
+			if name := strings.TrimPrefix(line, "+++ "); name != "/dev/null" {
+				current.Filename = strings.TrimPrefix(name, "b/")
+			}
+		case len(patch) == 0 && strings.HasPrefix(line, "new file"):
+			current.Status = "added"
+		case strings.HasPrefix(line, "@@"), len(patch) > 0:
+			patch = append(patch, line)
+		}
+	}
+	flush()
+	return files
+}
+
+// newDiffReport computes the coverage of the statements on the added lines of every file,
+// listing the files with the most missed statements first
+func newDiffReport(coverage *parser.CoverageData, files []github.PRFile) *diffReport {
+	report := &diffReport{ChangedFiles: len(files), GeneratedAt: time.Now().UTC()}
+	for i := range files {
+		added := files[i].AddedLines()
+		if len(added) == 0 {
+			continue
+		}
+		fileCoverage := findFileCoverage(coverage, files[i].Filename)
+		if fileCoverage == nil {
+			continue
+		}
+
+		file := diffReportFile{Path: files[i].Filename, ChangedLines: len(added)}
+		var uncovered []string
+		for _, stmt := range fileCoverage.Statements {
+			for _, line := range added {
+				if line < stmt.StartLine || line > stmt.EndLine {
+					continue
+				}
+				file.Statements += stmt.NumStmt
+				switch {
+				case stmt.Count > 0:
+					file.Covered += stmt.NumStmt
+				case stmt.StartLine == stmt.EndLine:
+					uncovered = append(uncovered, fmt.Sprintf("%d", stmt.StartLine))
+				default:
+					uncovered = append(uncovered, fmt.Sprintf("%d-%d", stmt.StartLine, stmt.EndLine))
+				}
+				break
+			}
+		}
+		if file.Statements == 0 {
+			continue
+		}
+		file.Coverage = float64(file.Covered) / float64(file.Statements) * 100
+		file.Uncovered = strings.Join(uncovered, ", ")
+		report.Files = append(report.Files, file)
+		report.Statements += file.Statements
+		report.Covered += file.Covered
+	}
+	if report.Statements > 0 {
+		report.Coverage = float64(report.Covered) / float64(report.Statements) * 100
+	}
+
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		missedI := report.Files[i].Statements - report.Files[i].Covered
+		missedJ := report.Files[j].Statements - report.Files[j].Covered
+		if missedI != missedJ {
+			return missedI > missedJ
+		}
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report
+}
+
+// describeDiffReportSince names the ref of the report, with its commit when git resolved it
+func describeDiffReportSince(report *diffReport) string {
+	if report.SinceSHA == "" || strings.HasPrefix(report.SinceSHA, report.Since) {
+		return report.Since
+	}
+	return fmt.Sprintf("%s (%s)", report.Since, shortSHA(report.SinceSHA))
+}
+
+// renderDiffReportMarkdown formats the report as a standalone Markdown document
+func renderDiffReportMarkdown(report *diffReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Coverage of changes since `%s`\n\n", describeDiffReportSince(report))
+	if report.Statements == 0 {
+		fmt.Fprintf(&b, "%d changed file(s), none with changed statements in the coverage profile.\n", report.ChangedFiles)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "**%.2f%%** of %d changed statements are covered (%d covered, %d missed) in %d of %d changed file(s).\n\n",
+		report.Coverage, report.Statements, report.Covered, report.Statements-report.Covered, len(report.Files), report.ChangedFiles)
+	b.WriteString("| File | Changed lines | Statements | Coverage | Uncovered lines |\n")
+	b.WriteString("|---|---:|---:|---:|---|\n")
+	for _, file := range report.Files {
+		fmt.Fprintf(&b, "| `%s` | %d | %d/%d | %.2f%% | %s |\n",
+			file.Path, file.ChangedLines, file.Covered, file.Statements, file.Coverage, file.Uncovered)
+	}
+	fmt.Fprintf(&b, "\n*Generated %s via [go-coverage](https://github.com/mrz1836/go-coverage)*\n", report.GeneratedAt.Format("2006-01-02 15:04 UTC"))
+	return b.String()
+}
+
+// diffReportHTMLTemplate renders a diff report as a standalone HTML page
+//
+//nolint:gochecknoglobals // parsed once at startup
+var diffReportHTMLTemplate = template.Must(template.New("diff-report").Funcs(template.FuncMap{
+	"pct":   func(value float64) string { return fmt.Sprintf("%.2f%%", value) },
+	"since": describeDiffReportSince,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Coverage of changes since {{.Since}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.4rem 0.8rem; text-align: left; }
+td.number { text-align: right; } .missed { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Coverage of changes since <code>{{since .}}</code></h1>
+{{if .Statements}}<p><strong>{{pct .Coverage}}</strong> of {{.Statements}} changed statements are covered ({{.Covered}} covered, {{len .Files}} of {{.ChangedFiles}} changed files), generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}}.</p>
+<table>
+<tr><th>File</th><th>Changed lines</th><th>Statements</th><th>Coverage</th><th>Uncovered lines</th></tr>
+{{range .Files}}<tr><td><code>{{.Path}}</code></td><td class="number">{{.ChangedLines}}</td><td class="number">{{.Covered}}/{{.Statements}}</td><td class="number">{{pct .Coverage}}</td><td class="missed">{{.Uncovered}}</td></tr>
+{{end}}</table>
+{{else}}<p>{{.ChangedFiles}} changed file(s), none with changed statements in the coverage profile.</p>
+{{end}}</body>
+</html>
+`))
+
+// renderDiffReportHTML renders a diff report as a standalone HTML page
+func renderDiffReportHTML(report *diffReport) (string, error) {
+	var b strings.Builder
+	if err := diffReportHTMLTemplate.Execute(&b, report); err != nil {
+		return "", fmt.Errorf("failed to render diff report: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestDiffReportCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := initHookRepo(t)
+	git := func(args ...string) {
+		require.NoError(t, exec.Command("git", args...).Run()) //nolint:gosec,noctx // fixed test arguments
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	write("calc/calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "base")
+	git("tag", "v1.0.0")
+
+	write("calc/calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n\nfunc Mul(a, b int) int {\n\treturn a * b\n}\n")
+	write("README.md", "calc\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "sub and mul")
+
+	profile := filepath.Join(t.TempDir(), "coverage.txt")
+	require.NoError(t, os.WriteFile(profile, []byte(`mode: set
+example.com/diff/calc/calc.go:3.24,5.2 1 1
+example.com/diff/calc/calc.go:7.24,9.2 1 1
+example.com/diff/calc/calc.go:11.24,13.2 1 0
+`), 0o600))
+
+	t.Run("markdown", func(t *testing.T) {
+		output, err := executeCommand(t, "diff-report", "--since", "v1.0.0", "-i", profile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "# Coverage of changes since `v1.0.0 (")
+		assert.Contains(t, output, "**50.00%** of 2 changed statements are covered (1 covered, 1 missed) in 1 of 2 changed file(s)")
+		assert.Contains(t, output, "| `calc/calc.go` | 8 | 1/2 | 50.00% | 11-13 |")
+	})
+
+	t.Run("html from extension", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "changes.html")
+		output, err := executeCommand(t, "diff-report", "--since", "v1.0.0", "-i", profile, "-o", outputPath)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Diff report written to")
+
+		data, err := os.ReadFile(outputPath) //nolint:gosec // test file path
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "<strong>50.00%</strong> of 2 changed statements")
+		assert.Contains(t, string(data), `<td class="missed">11-13</td>`)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		output, err := executeCommand(t, "diff-report", "--since", "HEAD", "-i", profile)
+		require.NoError(t, err)
+		assert.Contains(t, output, "0 changed file(s), none with changed statements")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := executeCommand(t, "diff-report", "-i", profile)
+		require.ErrorIs(t, err, ErrDiffReportSinceRequired)
+
+		_, err = executeCommand(t, "diff-report", "--since", "v1.0.0", "--format", "pdf")
+		require.ErrorIs(t, err, ErrUnsupportedDiffReportFormat)
+
+		_, err = executeCommand(t, "diff-report", "--since", "--output=x", "-i", profile)
+		require.ErrorIs(t, err, ErrDiffReportSinceRequired)
+
+		_, err = executeCommand(t, "diff-report", "--since", "no-such-ref", "-i", profile)
+		require.Error(t, err)
+	})
+}
+
+func TestParseGitDiff(t *testing.T) {
+	files := parseGitDiff(`diff --git a/lib/lib.go b/lib/lib.go
+index 1111111..2222222 100644
+--- a/lib/lib.go
++++ b/lib/lib.go
+@@ -3 +3,2 @@ package lib
+-	return 1
++	return 2
++++counter
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++package lib
+diff --git a/image.png b/image.png
+Binary files a/image.png and b/image.png differ
+`)
+	require.Len(t, files, 2)
+	assert.Equal(t, "lib/lib.go", files[0].Filename)
+	assert.Equal(t, "modified", files[0].Status)
+	assert.Equal(t, []int{3, 4}, files[0].AddedLines())
+	assert.Equal(t, "new.go", files[1].Filename)
+	assert.Equal(t, "added", files[1].Status)
+	assert.Equal(t, []int{1}, files[1].AddedLines())
+}
+
+func TestNewDiffReportSkipsFilesOutsideProfile(t *testing.T) {
+	coverage := &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"example.com/repo/lib": {Files: map[string]*parser.FileCoverage{
+			"example.com/repo/lib/lib.go": {Statements: []parser.Statement{
+				{StartLine: 1, EndLine: 1, NumStmt: 2, Count: 0},
+				{StartLine: 5, EndLine: 6, NumStmt: 1, Count: 3},
+			}},
+		}},
+	}}
+	report := newDiffReport(coverage, []github.PRFile{
+		{Filename: "lib/lib.go", Patch: "@@ -1,0 +1,1 @@\n+x\n@@ -9 +9 @@\n-y\n+z"},
+		{Filename: "docs/guide.md", Patch: "@@ -1 +1 @@\n-a\n+b"},
+	})
+	assert.Equal(t, 2, report.ChangedFiles)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, diffReportFile{Path: "lib/lib.go", ChangedLines: 2, Statements: 2, Uncovered: "1"}, report.Files[0])
+	assert.InDelta(t, 0.0, report.Coverage, 0.001)
+}
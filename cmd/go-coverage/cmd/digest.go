@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+)
+
+// digestMonthFormat keys the digest thread by calendar month
+const digestMonthFormat = "2006-01"
+
+// digestMaxPackages limits the package table of the digest
+const digestMaxPackages = 5
+
+// digestMonthMarker records the month of the digest last written to the thread body
+var digestMonthMarker = regexp.MustCompile(`\[//\]: # \(digest-month: (\d{4}-\d{2})\)`)
+
+// newDigestCmd creates the digest command
+func (c *Commands) newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize recent coverage history and update the digest thread",
+		Long: `Summarize the coverage history of a branch as a Markdown digest: current coverage,
+change over the period, range, and the packages that moved the most.
+
+With GO_COVERAGE_DIGEST_THREAD (or --thread) set to discussion or issue, the digest is also
+kept in one long-lived repository thread, found by its title. The thread is created on the
+first run, and each calendar month its body is replaced with the latest digest and the
+digest is added as a comment, so runs later in the same month change nothing unless --force
+is given. Run it from a scheduled workflow to keep the thread current.`,
+		Example: `  # Print the digest of the last 30 days
+  go-coverage digest
+
+  # Keep a discussion in the "Announcements" category up to date
+  GO_COVERAGE_DIGEST_CATEGORY=Announcements go-coverage digest --thread discussion`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			branch, _ := cmd.Flags().GetString("branch")
+			days, _ := cmd.Flags().GetInt("days")
+			thread, _ := cmd.Flags().GetString("thread")
+			force, _ := cmd.Flags().GetBool("force")
+			outputFile, _ := cmd.Flags().GetString("output")
+			dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if thread != "" {
+				cfg.GitHub.DigestThread = strings.ToLower(strings.TrimSpace(thread))
+				if err = cfg.Validate(); err != nil {
+					return err
+				}
+			}
+			if branch == "" {
+				branch = getDefaultBranch()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+
+			tracker := history.NewWithConfig(&history.Config{
+				StoragePath:    historyStoragePath(cfg),
+				Repository:     cfg.RepositorySlug(),
+				RetentionDays:  cfg.History.RetentionDays,
+				MaxEntries:     cfg.History.MaxEntries,
+				AutoCleanup:    false,
+				MetricsEnabled: cfg.History.MetricsEnabled,
+			})
+			trend, err := tracker.GetTrend(ctx, history.WithTrendBranch(branch), history.WithTrendDays(days))
+			if err != nil {
+				return fmt.Errorf("failed to load coverage history: %w", err)
+			}
+
+			now := time.Now().UTC()
+			digest := renderDigest(trend, branch, days, now)
+			if outputFile != "" {
+				if err = os.WriteFile(outputFile, []byte(digest), cfg.Storage.FileMode); err != nil {
+					return fmt.Errorf("failed to write digest: %w", err)
+				}
+			}
+
+			if cfg.GitHub.DigestThread == "" || cfg.GitHub.DigestThread == config.DigestThreadOff || dryRun {
+				cmd.Print(digest)
+				return nil
+			}
+
+			if err = requireNetwork(cmd, cfg, "updating the digest thread"); err != nil {
+				return err
+			}
+			if cfg.GitHub.Token == "" {
+				return ErrGitHubTokenRequired
+			}
+			if cfg.GitHub.Owner == "" {
+				return ErrGitHubOwnerRequired
+			}
+			if cfg.GitHub.Repository == "" {
+				return ErrGitHubRepoRequired
+			}
+
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+			return updateDigestThread(ctx, cmd, cfg, client, digest, now.Format(digestMonthFormat), force)
+		},
+	}
+
+	cmd.Flags().StringP("branch", "b", "", "Branch to summarize (default: the current or default branch)")
+	cmd.Flags().IntP("days", "d", 30, "Number of days of history to summarize")
+	cmd.Flags().String("thread", "", "Thread to update: off, discussion or issue (default: GO_COVERAGE_DIGEST_THREAD)")
+	cmd.Flags().Bool("force", false, "Update the thread even if it already has this month's digest")
+	cmd.Flags().StringP("output", "o", "", "Also write the digest Markdown to this file")
+	addDryRunFlag(cmd, "Print the digest without updating the thread")
+
+	return cmd
+}
+
+// updateDigestThread creates the digest thread or, once per month, refreshes its body and
+// comments the new digest
+func updateDigestThread(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client,
+	digest, month string, force bool,
+) error {
+	kind := cfg.GitHub.DigestThread
+	body := digestThreadBody(cfg, digest, month)
+
+	thread, err := client.FindThread(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, kind, cfg.GitHub.DigestTitle)
+	if errors.Is(err, github.ErrThreadNotFound) {
+		thread, err = client.CreateThread(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, kind, cfg.GitHub.DigestCategory, cfg.GitHub.DigestTitle, body)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("🧵 Created digest %s #%d: %s\n", kind, thread.Number, thread.URL)
+		if kind == config.DigestThreadIssue {
+			if pinErr := client.PinIssue(ctx, thread.ID); pinErr != nil {
+				cmd.Printf("Warning: %v\n", pinErr)
+			}
+		} else {
+			cmd.Printf("   Pin the discussion once from the GitHub UI; the API cannot pin discussions\n")
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !force && digestThreadMonth(thread.Body) == month {
+		cmd.Printf("Digest %s #%d already has the %s digest: %s\n", kind, thread.Number, month, thread.URL)
+		return nil
+	}
+
+	if err = client.AddThreadComment(ctx, thread, digest); err != nil {
+		return err
+	}
+	if err = client.UpdateThreadBody(ctx, thread, body); err != nil {
+		return err
+	}
+	cmd.Printf("🧵 Updated digest %s #%d: %s\n", kind, thread.Number, thread.URL)
+	return nil
+}
+
+// digestThreadBody is the body of the digest thread: an introduction and the latest digest
+func digestThreadBody(cfg *config.Config, digest, month string) string {
+	var b strings.Builder
+	b.WriteString("[//]: # (go-coverage-digest)\n")
+	fmt.Fprintf(&b, "[//]: # (digest-month: %s)\n\n", month)
+	fmt.Fprintf(&b, "Coverage of `%s/%s` is summarized here every month by go-coverage, ", cfg.GitHub.Owner, cfg.GitHub.Repository)
+	b.WriteString("so the long-term conversation about it lives in one place. ")
+	b.WriteString("The latest digest is below; earlier digests are in the comments.\n\n")
+	b.WriteString(digest)
+	return b.String()
+}
+
+// digestThreadMonth returns the month of the digest in a thread body, or "" if unknown
+func digestThreadMonth(body string) string {
+	matches := digestMonthMarker.FindStringSubmatch(body)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// renderDigest summarizes the history of a branch as Markdown
+func renderDigest(trend *history.TrendData, branch string, days int, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## 📊 Coverage digest: %s\n\n", now.Format("January 2006"))
+
+	entries := slices.DeleteFunc(slices.Clone(trend.Entries), func(entry history.Entry) bool {
+		return entry.Coverage == nil
+	})
+	if len(entries) == 0 {
+		fmt.Fprintf(&b, "No coverage was recorded on `%s` in the last %d days.\n", branch, days)
+		return b.String()
+	}
+
+	// Entries are ordered from the newest to the oldest
+	latest, oldest := entries[0], entries[len(entries)-1]
+	summary := trend.Summary
+	fmt.Fprintf(&b, "Branch `%s`, last %d days (%d %s).\n\n", branch, days, len(entries), pluralRuns(len(entries)))
+	b.WriteString("| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| **Current** | %.1f%% |\n", latest.Coverage.Percentage)
+	fmt.Fprintf(&b, "| **Change** | %+.1f%% (%.1f%% → %.1f%%) |\n",
+		latest.Coverage.Percentage-oldest.Coverage.Percentage, oldest.Coverage.Percentage, latest.Coverage.Percentage)
+	fmt.Fprintf(&b, "| **Range** | %.1f%% – %.1f%% |\n", summary.MinPercentage, summary.MaxPercentage)
+	fmt.Fprintf(&b, "| **Average** | %.1f%% |\n", summary.AveragePercentage)
+	fmt.Fprintf(&b, "| **Statements** | %d/%d |\n", latest.Coverage.CoveredLines, latest.Coverage.TotalLines)
+
+	movers := digestPackageMovers(oldest, latest)
+	if len(movers) > 0 {
+		b.WriteString("\n### Biggest package changes\n\n| Package | Before | After | Change |\n|---------|--------|-------|--------|\n")
+		for _, mover := range movers {
+			fmt.Fprintf(&b, "| `%s` | %.1f%% | %.1f%% | %+.1f%% |\n", mover.name, mover.before, mover.after, mover.after-mover.before)
+		}
+	}
+	return b.String()
+}
+
+// digestPackage is the coverage of a package at the start and end of the digest period
+type digestPackage struct {
+	name          string
+	before, after float64
+}
+
+// digestPackageMovers returns the packages whose coverage changed the most between two entries
+func digestPackageMovers(oldest, latest history.Entry) []digestPackage {
+	var movers []digestPackage
+	for name, pkg := range latest.Coverage.Packages {
+		before, ok := oldest.Coverage.Packages[name]
+		if !ok || pkg == nil || before == nil || math.Abs(pkg.Percentage-before.Percentage) < 0.05 {
+			continue
+		}
+		movers = append(movers, digestPackage{name: name, before: before.Percentage, after: pkg.Percentage})
+	}
+	slices.SortFunc(movers, func(a, b digestPackage) int {
+		if diff := math.Abs(b.after-b.before) - math.Abs(a.after-a.before); diff != 0 {
+			if diff > 0 {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	if len(movers) > digestMaxPackages {
+		movers = movers[:digestMaxPackages]
+	}
+	return movers
+}
+
+// pluralRuns returns "run" or "runs" for a count
+func pluralRuns(count int) string {
+	if count == 1 {
+		return "run"
+	}
+	return "runs"
+}
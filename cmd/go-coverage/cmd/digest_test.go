@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func digestEntry(percentage float64, packages map[string]float64) history.Entry {
+	coverage := &parser.CoverageData{
+		Percentage:   percentage,
+		TotalLines:   200,
+		CoveredLines: int(percentage * 2),
+		Packages:     map[string]*parser.PackageCoverage{},
+	}
+	for name, pct := range packages {
+		coverage.Packages[name] = &parser.PackageCoverage{Name: name, Percentage: pct}
+	}
+	return history.Entry{Coverage: coverage}
+}
+
+func TestRenderDigest(t *testing.T) {
+	now := time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC)
+	trend := &history.TrendData{
+		Entries: []history.Entry{
+			digestEntry(82.5, map[string]float64{"pkg/a": 90, "pkg/b": 60, "pkg/c": 75, "pkg/new": 100}),
+			{},
+			digestEntry(78, map[string]float64{"pkg/a": 80, "pkg/b": 65, "pkg/c": 75}),
+		},
+		Summary: &history.TrendSummary{AveragePercentage: 80.25, MinPercentage: 78, MaxPercentage: 82.5},
+	}
+
+	digest := renderDigest(trend, "main", 30, now)
+	assert.Contains(t, digest, "## 📊 Coverage digest: March 2026")
+	assert.Contains(t, digest, "Branch `main`, last 30 days (2 runs).")
+	assert.Contains(t, digest, "| **Current** | 82.5% |")
+	assert.Contains(t, digest, "| **Change** | +4.5% (78.0% → 82.5%) |")
+	assert.Contains(t, digest, "| **Range** | 78.0% – 82.5% |")
+	assert.Contains(t, digest, "| **Statements** | 165/200 |")
+	assert.Contains(t, digest, "| `pkg/a` | 80.0% | 90.0% | +10.0% |")
+	assert.Contains(t, digest, "| `pkg/b` | 65.0% | 60.0% | -5.0% |")
+	assert.Less(t, strings.Index(digest, "pkg/a"), strings.Index(digest, "pkg/b"))
+	assert.NotContains(t, digest, "pkg/c")
+	assert.NotContains(t, digest, "pkg/new")
+
+	empty := renderDigest(&history.TrendData{Summary: &history.TrendSummary{}}, "main", 30, now)
+	assert.Contains(t, empty, "No coverage was recorded on `main` in the last 30 days.")
+}
+
+func TestDigestPackageMoversLimit(t *testing.T) {
+	before := map[string]float64{}
+	after := map[string]float64{}
+	for i, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		before[name] = 50
+		after[name] = 50 + float64(i+1)
+	}
+
+	movers := digestPackageMovers(digestEntry(50, before), digestEntry(55, after))
+	require.Len(t, movers, digestMaxPackages)
+	assert.Equal(t, "g", movers[0].name)
+	assert.Equal(t, "c", movers[digestMaxPackages-1].name)
+}
+
+func TestDigestThreadMonth(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"}}
+	body := digestThreadBody(cfg, "## digest\n", "2026-03")
+	assert.Equal(t, "2026-03", digestThreadMonth(body))
+	assert.Contains(t, body, "`owner/repo`")
+	assert.True(t, strings.HasSuffix(body, "## digest\n"))
+	assert.Empty(t, digestThreadMonth("a thread written by hand"))
+}
+
+// threadServer fakes the GraphQL API with an existing issue of the given title and body, or
+// none when the body is empty, and records the operations called, including the REST call
+// assigning the issue
+func threadServer(t *testing.T, title, body string) (*github.Client, *[]string) {
+	t.Helper()
+	var operations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/assignees") {
+			operations = append(operations, "assignees")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		var request struct {
+			Query string `json:"query"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		for _, operation := range []string{"search", "repository", "createIssue", "pinIssue", "addComment", "updateIssue", "closeIssue"} {
+			if !strings.Contains(request.Query, operation+"(") {
+				continue
+			}
+			operations = append(operations, operation)
+			switch operation {
+			case "search":
+				nodes := "[]"
+				if body != "" {
+					encoded, _ := json.Marshal(body)
+					encodedTitle, _ := json.Marshal(title)
+					nodes = `[{"id":"I_1","number":1,"title":` + string(encodedTitle) + `,"url":"https://github.com/owner/repo/issues/1","body":` + string(encoded) + `}]`
+				}
+				_, _ = w.Write([]byte(`{"data":{"search":{"nodes":` + nodes + `}}}`))
+			case "repository":
+				_, _ = w.Write([]byte(`{"data":{"repository":{"id":"R_1","discussionCategories":{"nodes":[]}}}}`))
+			case "createIssue":
+				_, _ = w.Write([]byte(`{"data":{"createIssue":{"issue":{"id":"I_2","number":2,"url":"https://github.com/owner/repo/issues/2"}}}}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":{}}`))
+			}
+			return
+		}
+		t.Errorf("unexpected query %s", request.Query)
+	}))
+	t.Cleanup(server.Close)
+
+	return github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second}), &operations
+}
+
+func TestUpdateDigestThread(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{
+		Owner:        "owner",
+		Repository:   "repo",
+		DigestThread: config.DigestThreadIssue,
+		DigestTitle:  "Coverage digest",
+	}}
+	current := digestThreadBody(cfg, "## digest\n", "2026-03")
+	previous := digestThreadBody(cfg, "## digest\n", "2026-02")
+
+	tests := []struct {
+		name       string
+		body       string
+		force      bool
+		operations []string
+		output     string
+	}{
+		{"create and pin", "", false, []string{"search", "repository", "createIssue", "pinIssue"}, "Created digest issue #2"},
+		{"new month", previous, false, []string{"search", "addComment", "updateIssue"}, "Updated digest issue #1"},
+		{"same month", current, false, []string{"search"}, "already has the 2026-03 digest"},
+		{"same month forced", current, true, []string{"search", "addComment", "updateIssue"}, "Updated digest issue #1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, operations := threadServer(t, cfg.GitHub.DigestTitle, tt.body)
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+
+			require.NoError(t, updateDigestThread(context.Background(), cmd, cfg, client, "## digest\n", "2026-03", tt.force))
+			assert.Equal(t, tt.operations, *operations)
+			assert.Contains(t, out.String(), tt.output)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/editor"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// flagNameEditor selects the editor plugin formats written next to the sources
+const flagNameEditor = "editor"
+
+// writeEditorOutput writes coverage for editor plugins into cfg.Editor.Dir. Profile paths are
+// made relative to that directory so plugins can match them against the open workspace.
+func writeEditorOutput(cfg *config.Config, coverage *parser.CoverageData, formats []string) ([]string, error) {
+	repoName := cfg.GitHub.Repository
+	if repoName == "" {
+		if absDir, err := filepath.Abs(cfg.Editor.Dir); err == nil {
+			repoName = filepath.Base(absDir)
+		}
+	}
+
+	files := editor.Files(coverage, func(path string) string {
+		return urlutil.CleanModulePathWithRepo(path, repoName)
+	})
+	return editor.Write(cfg.Editor.Dir, formats, files, cfg.Storage.FileMode)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+)
+
+// flagNameEnv is the flag restricting history queries to runs of an environment
+const flagNameEnv = "env"
+
+// addEnvFilterFlag adds the flag restricting history queries to runs of an environment
+func addEnvFilterFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagNameEnv, "", "Only show runs of an environment, as key=value pairs such as os=linux,go_version=go1.25* (keys: "+strings.Join(runenv.Keys(), ", ")+")")
+}
+
+// printEnvironment prints the environment recorded with a history entry, if any
+func printEnvironment(cmd *cobra.Command, metadata map[string]string) {
+	if label := runenv.Label(metadata); label != "" {
+		cmd.Printf("Environment: %s\n", label)
+	}
+}
+
+// newEnvironmentData compares the coverage of the environments of the history, newest first,
+// with the environment of this run first. Entries recorded without an environment are left out.
+func newEnvironmentData(current map[string]string, percentage float64, commitSHA string, entries []history.Entry) []dashboard.EnvironmentCoverage {
+	var (
+		environments []dashboard.EnvironmentCoverage
+		totals       []float64
+		index        = make(map[string]int)
+	)
+	add := func(label string, coverage float64) {
+		if label == "" {
+			return
+		}
+		i, seen := index[label]
+		if !seen {
+			i = len(environments)
+			index[label] = i
+			environments = append(environments, dashboard.EnvironmentCoverage{Label: label, Latest: coverage})
+			totals = append(totals, 0)
+		}
+		environments[i].Runs++
+		totals[i] += coverage
+	}
+
+	add(runenv.Label(current), percentage)
+	if len(environments) > 0 {
+		environments[0].Current = true
+	}
+	for _, entry := range entries {
+		if entry.Coverage != nil && (commitSHA == "" || entry.CommitSHA != commitSHA) {
+			add(runenv.Label(entry.Metadata), entry.Coverage.Percentage)
+		}
+	}
+	for i := range environments {
+		environments[i].Average = totals[i] / float64(environments[i].Runs)
+	}
+	return environments
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+)
+
+func TestHistoryEnvironment(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "")
+	t.Setenv("GOFLAGS", "")
+	dir := t.TempDir()
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(dir, "history"))
+	profile := filepath.Join(dir, "coverage.txt")
+	require.NoError(t, os.WriteFile(profile, []byte(compareHeadProfile), 0o600))
+
+	t.Setenv(runenv.EnvRunner, "self-hosted-arm")
+	t.Setenv(runenv.EnvTestTags, "integration")
+	output, err := executeCommand(t, cmdHistory, "--add", profile, "--branch", "main", "--commit", "abc1234def")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Environment: go")
+	assert.Contains(t, output, "self-hosted-arm tags=integration -covermode=set")
+
+	output, err = executeCommand(t, cmdHistory, "--branch", "main", "--env", "test_tags=integration,runner=self-hosted*")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Commit: abc1234def")
+	assert.Contains(t, output, "self-hosted-arm tags=integration")
+
+	output, err = executeCommand(t, cmdHistory, "--trend", "--branch", "main", "--env", "test_tags=unit")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Environment: test_tags=unit")
+	assert.Contains(t, output, "Total Entries: 0")
+
+	_, err = executeCommand(t, cmdHistory, "--branch", "main", "--env", "test_tags=unit")
+	require.ErrorIs(t, err, history.ErrNoEntriesFound)
+
+	_, err = executeCommand(t, cmdHistory, "--branch", "main", "--env", "linux")
+	require.ErrorIs(t, err, runenv.ErrInvalidFilter)
+}
+
+func TestNewEnvironmentData(t *testing.T) {
+	linux := map[string]string{runenv.KeyGoVersion: "go1.25.1", runenv.KeyOS: "linux", runenv.KeyArch: "amd64"}
+	windows := map[string]string{runenv.KeyGoVersion: "go1.25.1", runenv.KeyOS: "windows", runenv.KeyArch: "amd64"}
+	entries := []history.Entry{
+		{CommitSHA: "current", Metadata: linux, Coverage: &parser.CoverageData{Percentage: 99}},
+		{CommitSHA: "c3", Metadata: windows, Coverage: &parser.CoverageData{Percentage: 78}},
+		{CommitSHA: "c2", Metadata: linux, Coverage: &parser.CoverageData{Percentage: 82}},
+		{CommitSHA: "c1", Metadata: map[string]string{"project": "owner/repo"}, Coverage: &parser.CoverageData{Percentage: 70}},
+		{CommitSHA: "c0", Metadata: windows, Coverage: &parser.CoverageData{Percentage: 76}},
+	}
+
+	assert.Equal(t, []dashboard.EnvironmentCoverage{
+		{Label: "go1.25.1 linux/amd64", Runs: 2, Latest: 84, Average: 83, Current: true},
+		{Label: "go1.25.1 windows/amd64", Runs: 2, Latest: 78, Average: 77},
+	}, newEnvironmentData(linux, 84, "current", entries))
+
+	assert.Empty(t, newEnvironmentData(nil, 84, "", nil))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/examples"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// newExampleDashboardData combines the coverage of the example programs and fuzz harnesses left
+// out of the totals with the Example functions and fuzz targets found in the tests
+func newExampleDashboardData(cfg *config.Config, branch string, coverage *parser.CoverageData, report *examples.Report) *dashboard.ExampleCoverage {
+	data := &dashboard.ExampleCoverage{}
+	if coverage != nil {
+		for _, pkg := range coverage.Packages {
+			data.Files += len(pkg.Files)
+		}
+		data.Coverage, data.TotalLines, data.CoveredLines = coverage.Percentage, coverage.TotalLines, coverage.CoveredLines
+	}
+	if report != nil {
+		data.Examples, data.ExamplesRun = report.Count(examples.KindExample)
+		data.FuzzTargets, data.FuzzSeeded = report.Count(examples.KindFuzz)
+		for _, fn := range report.Idle() {
+			idle := dashboard.IdleFunction{Name: fn.Name, Kind: fn.Kind, Path: fn.File}
+			if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" && branch != "" {
+				idle.GitHubURL = fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s#L%d", cfg.GitHub.Owner,
+					cfg.GitHub.Repository, branch, urlutil.RepoPath(cfg.Coverage.ModuleRoot, fn.File), fn.Line)
+			}
+			data.Idle = append(data.Idle, idle)
+		}
+	}
+	if data.TotalLines == 0 && data.Examples == 0 && data.FuzzTargets == 0 {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/examples"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestNewExampleDashboardData(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.GitHub.Owner, cfg.GitHub.Repository = "owner", "repo"
+	cfg.Coverage.ModuleRoot = "services/api"
+
+	assert.Nil(t, newExampleDashboardData(cfg, "main", &parser.CoverageData{}, &examples.Report{}))
+
+	coverage := &parser.CoverageData{
+		Packages: map[string]*parser.PackageCoverage{
+			"examples/server": {Files: map[string]*parser.FileCoverage{"examples/server/main.go": {}}},
+		},
+		TotalLines: 4, CoveredLines: 1, Percentage: 25,
+	}
+	report := &examples.Report{Functions: []examples.Function{
+		{Name: "ExampleParse", Kind: examples.KindExample, File: "lib/example_test.go", Line: 7, Exercised: true},
+		{Name: "ExampleFormat", Kind: examples.KindExample, File: "lib/example_test.go", Line: 12},
+		{Name: "FuzzParse", Kind: examples.KindFuzz, File: "lib/fuzz_test.go", Line: 5, Exercised: true},
+	}}
+
+	data := newExampleDashboardData(cfg, "main", coverage, report)
+	require.NotNil(t, data)
+	assert.Equal(t, 1, data.Files)
+	assert.InDelta(t, 25.0, data.Coverage, 0.001)
+	assert.Equal(t, 2, data.Examples)
+	assert.Equal(t, 1, data.ExamplesRun)
+	assert.Equal(t, 1, data.FuzzTargets)
+	assert.Equal(t, 1, data.FuzzSeeded)
+	require.Len(t, data.Idle, 1)
+	assert.Equal(t, "https://github.com/owner/repo/blob/main/services/api/lib/example_test.go#L12", data.Idle[0].GitHubURL)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/testrun"
+)
+
+// extraBadgeFiles are the suffixes of the additional badges written next to the coverage badge:
+// coverage.svg gets coverage-grade.svg, coverage-delta.svg and coverage-tests.svg. The trend
+// badge is not named -trend, which is taken by the trend chart of the dashboard.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var extraBadgeFiles = map[string]string{
+	config.BadgeExtraGrade: "-grade.svg",
+	config.BadgeExtraTrend: "-delta.svg",
+	config.BadgeExtraTests: "-tests.svg",
+}
+
+// extraBadgeFile returns the file name of an additional badge written next to a badge
+func extraBadgeFile(badgeFile, extra string) string {
+	return strings.TrimSuffix(badgeFile, ".svg") + extraBadgeFiles[extra]
+}
+
+// writeExtraBadges writes the additional badges enabled in the configuration next to the
+// coverage badge in each distinct directory. The trend badge needs an earlier run (previous is
+// newest first) and the tests badge the test results of the run; each is skipped without them.
+func writeExtraBadges(ctx context.Context, cmd *cobra.Command, cfg *config.Config, current float64, previous []float64, tests *testrun.Summary, dirs ...string) {
+	generator := badge.New()
+	style := badge.WithStyle(cfg.Badge.Style)
+
+	for _, extra := range []string{config.BadgeExtraGrade, config.BadgeExtraTrend, config.BadgeExtraTests} {
+		if !cfg.Badge.WantsExtra(extra) {
+			continue
+		}
+
+		var svg []byte
+		var err error
+		switch extra {
+		case config.BadgeExtraGrade:
+			svg, err = generator.GenerateGradeBadge(ctx, calculateQualityGrade(current), style)
+		case config.BadgeExtraTrend:
+			if len(previous) == 0 {
+				cmd.Printf("   ℹ️  Trend badge skipped: no earlier run of the branch\n")
+				continue
+			}
+			svg, err = generator.GenerateTrendBadge(ctx, current, previous[0], style)
+		case config.BadgeExtraTests:
+			if tests == nil {
+				cmd.Printf("   ℹ️  Tests badge skipped: no test results (GO_COVERAGE_TEST_RESULTS)\n")
+				continue
+			}
+			svg, err = generator.GenerateTestsBadge(ctx, tests.Tests, tests.Failed, style)
+		}
+		if err != nil {
+			cmd.Printf("   ⚠️  Failed to generate %s badge: %v\n", extra, err)
+			continue
+		}
+
+		for _, dir := range slices.Compact(dirs) {
+			badgePath := filepath.Join(dir, extraBadgeFile(cfg.Badge.OutputFile, extra))
+			if writeErr := os.WriteFile(badgePath, svg, cfg.Storage.FileMode); writeErr != nil {
+				cmd.Printf("   ⚠️  Failed to write %s badge: %v\n", extra, writeErr)
+				continue
+			}
+			cmd.Printf("   ✅ %s badge saved: %s\n", strings.ToUpper(extra[:1])+extra[1:], badgePath)
+		}
+	}
+}
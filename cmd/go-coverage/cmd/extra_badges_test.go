@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/testrun"
+)
+
+func TestExtraBadgeFile(t *testing.T) {
+	assert.Equal(t, "coverage-grade.svg", extraBadgeFile("coverage.svg", config.BadgeExtraGrade))
+	assert.Equal(t, "coverage-delta.svg", extraBadgeFile("coverage.svg", config.BadgeExtraTrend))
+	assert.Equal(t, "badge-tests.svg", extraBadgeFile("badge.svg", config.BadgeExtraTests))
+}
+
+func TestWriteExtraBadges(t *testing.T) {
+	isolateOfflineEnv(t)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Badge.Extra = []string{config.BadgeExtraGrade, config.BadgeExtraTrend, config.BadgeExtraTests}
+
+	outputDir := t.TempDir()
+	read := func(name string) string {
+		content, readErr := os.ReadFile(filepath.Join(outputDir, name)) //nolint:gosec // test file path
+		require.NoError(t, readErr)
+		return string(content)
+	}
+	cmd := &cobra.Command{}
+	output := &bytes.Buffer{}
+	cmd.SetOut(output)
+
+	writeExtraBadges(context.Background(), cmd, cfg, 92, nil, nil, outputDir, outputDir)
+	assert.Contains(t, read("coverage-grade.svg"), "A")
+	assert.NoFileExists(t, filepath.Join(outputDir, "coverage-delta.svg"), "no earlier run")
+	assert.NoFileExists(t, filepath.Join(outputDir, "coverage-tests.svg"), "no test results")
+	assert.Contains(t, output.String(), "Trend badge skipped")
+
+	writeExtraBadges(context.Background(), cmd, cfg, 92, []float64{90}, &testrun.Summary{Tests: 120, Failed: 2}, outputDir)
+	assert.Contains(t, read("coverage-delta.svg"), "+2.0%")
+	assert.Contains(t, read("coverage-tests.svg"), "118 passed, 2 failed")
+
+	cfg.Badge.Extra = nil
+	require.NoError(t, os.Remove(filepath.Join(outputDir, "coverage-grade.svg")))
+	writeExtraBadges(context.Background(), cmd, cfg, 92, nil, nil, outputDir)
+	assert.NoFileExists(t, filepath.Join(outputDir, "coverage-grade.svg"))
+}
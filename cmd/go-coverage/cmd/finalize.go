@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/snapshot"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// ErrPullRequestOpen is returned when finalize is run for a pull request that is still open
+var ErrPullRequestOpen = errors.New("pull request is still open")
+
+// finalizeOptions selects the steps of finalizing a closed pull request
+type finalizeOptions struct {
+	inputFile   string // Coverage profile of the merge commit, instead of the last run of the pull request
+	outputDir   string // Site the reports of the pull request are removed from
+	skipComment bool
+	skipHistory bool
+	skipCleanup bool
+	dryRun      bool
+}
+
+// finalImpact is the coverage a merged pull request brought to its base branch
+type finalImpact struct {
+	Base     *history.Entry       // Last run of the base branch before the merge, nil without one
+	Coverage *parser.CoverageData // Coverage of the merge, nil when unknown
+	Recorded bool                 // The base branch history already has the merge commit
+}
+
+// newFinalizeCmd creates the finalize command
+func (c *Commands) newFinalizeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "finalize",
+		Short: "Wrap up the coverage of a closed pull request",
+		Long: `Wrap up the coverage of a pull request once it is merged or closed, from a workflow
+triggered by the pull_request closed event:
+
+  - The earlier coverage comments are minimized as outdated, or marked archived when they
+    cannot be minimized, and a final summary comment with the coverage impact of the merge
+    is posted. Running finalize again updates the summary.
+  - A merged pull request is recorded in the history of its base branch at the merge commit,
+    with the pull request number, title, head branch and head commit as metadata. Its coverage
+    is the --input profile, or the last run of the pull request recorded in the history.
+  - The badges, reports and coverage data snapshot of the pull request are removed from the
+    site in the output directory, unless GO_COVERAGE_REPORT_KEEP_CLOSED_PRS is set, which
+    leaves them to the snapshot retention.`,
+		Example: `  # In a workflow on pull_request: closed, with the pages branch checked out in coverage/
+  go-coverage finalize --output coverage
+
+  # Preview the final summary of pull request 42
+  go-coverage finalize --pr 42 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			prNumber, _ := cmd.Flags().GetInt("pr")
+			opts := finalizeOptions{}
+			opts.inputFile, _ = cmd.Flags().GetString("input")
+			opts.outputDir, _ = cmd.Flags().GetString("output")
+			opts.skipComment, _ = cmd.Flags().GetBool("skip-comment")
+			opts.skipHistory, _ = cmd.Flags().GetBool("skip-history")
+			opts.skipCleanup, _ = cmd.Flags().GetBool("skip-cleanup")
+			opts.dryRun, _ = cmd.Flags().GetBool(flagNameDryRun)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if prNumber == 0 {
+				prNumber = cfg.GitHub.PullRequest
+			}
+			if prNumber == 0 {
+				return ErrPRNumberRequired
+			}
+			if opts.outputDir == "" {
+				opts.outputDir = cfg.Coverage.OutputDir
+			}
+
+			if err = requireNetwork(cmd, cfg, "finalizing a pull request"); err != nil {
+				return err
+			}
+			if cfg.GitHub.Token == "" {
+				return ErrGitHubTokenRequired
+			}
+			if cfg.GitHub.Owner == "" {
+				return ErrGitHubOwnerRequired
+			}
+			if cfg.GitHub.Repository == "" {
+				return ErrGitHubRepoRequired
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			client, err := newGitHubClient(cfg, "go-coverage/2.0")
+			if err != nil {
+				return err
+			}
+			return finalizePR(ctx, cmd, cfg, client, prNumber, opts)
+		},
+	}
+
+	cmd.Flags().IntP("pr", "p", 0, "Pull request number (default: the pull request of the event)")
+	cmd.Flags().StringP("input", "i", "", "Coverage profile of the merge commit (default: the last run of the pull request)")
+	cmd.Flags().StringP("output", "o", "", "Site directory the reports of the pull request are removed from (default: the output directory)")
+	cmd.Flags().Bool("skip-comment", false, "Skip the final comment and the archiving of earlier comments")
+	cmd.Flags().Bool("skip-history", false, "Skip recording the merge in the history")
+	cmd.Flags().Bool("skip-cleanup", false, "Keep the reports of the pull request")
+	addDryRunFlag(cmd, "Print the final summary without changing the pull request, history or site")
+
+	return cmd
+}
+
+// finalizePR archives the coverage comments of a closed pull request behind a final summary,
+// records a merge in the history of the base branch and removes the reports of the pull request
+func finalizePR(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API, prNumber int, opts finalizeOptions) error {
+	pr, err := client.GetPullRequest(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if pr.State == "open" {
+		return fmt.Errorf("%w: #%d", ErrPullRequestOpen, prNumber)
+	}
+
+	var tracker *history.Tracker
+	if cfg.History.Enabled {
+		tracker = history.NewWithConfig(&history.Config{
+			StoragePath:    historyStoragePath(cfg),
+			Repository:     cfg.RepositorySlug(),
+			RetentionDays:  cfg.History.RetentionDays,
+			MaxEntries:     cfg.History.MaxEntries,
+			AutoCleanup:    false,
+			MetricsEnabled: false,
+		})
+	}
+
+	impact := &finalImpact{}
+	if pr.Merged {
+		if impact, err = loadFinalImpact(ctx, cfg, tracker, pr, opts.inputFile); err != nil {
+			return err
+		}
+	}
+
+	removed := !opts.skipCleanup && !cfg.Report.KeepClosedPRs
+	body := renderFinalComment(cfg, pr, impact, removed)
+	if opts.dryRun {
+		cmd.Printf("Final Comment Preview (Dry Run)\n")
+		cmd.Printf("=====================================\n")
+		cmd.Println(body)
+		cmd.Printf("=====================================\n")
+		return nil
+	}
+
+	if !opts.skipComment {
+		manager := github.NewPRCommentManager(client, &github.PRCommentConfig{CommentSignature: cfg.GitHub.CommentSignature})
+		response, commentErr := manager.FinalizePRComments(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, prNumber, body)
+		if commentErr != nil {
+			return fmt.Errorf("failed to post final comment: %w", commentErr)
+		}
+		cmd.Printf("🏁 Final comment %s on #%d (%d earlier comments minimized, %d marked archived)\n",
+			response.Action, prNumber, response.Minimized, response.Marked)
+	}
+
+	if pr.Merged && !opts.skipHistory && tracker != nil {
+		recordMerge(ctx, cmd, cfg, tracker, pr, impact)
+	}
+
+	if removed {
+		cleanupPRReports(cmd, cfg, opts.outputDir, prNumber)
+	} else {
+		cmd.Printf("🗂️  Keeping the reports of #%d\n", prNumber)
+	}
+	return nil
+}
+
+// loadFinalImpact finds the coverage of a merge, from the profile of the merge commit or the last
+// run of the pull request, and the last run of the base branch before it
+func loadFinalImpact(ctx context.Context, cfg *config.Config, tracker *history.Tracker, pr *github.PullRequest, inputFile string) (*finalImpact, error) {
+	impact := &finalImpact{}
+	if inputFile != "" {
+		p := parser.NewWithConfig(&parser.Config{
+			ExcludePaths:     cfg.Coverage.ExcludePaths,
+			ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+			ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+			ExcludePresets:   cfg.Coverage.ExcludePresets,
+			Limits:           cfg.ParserLimits(),
+			ModuleRewrites:   cfg.ModuleRewrites(),
+			ModuleRoot:       cfg.Coverage.ModuleRoot,
+		})
+		coverage, err := p.ParseFile(ctx, inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse coverage: %w", err)
+		}
+		impact.Coverage = coverage
+	}
+	if tracker == nil {
+		return impact, nil
+	}
+
+	if impact.Coverage == nil {
+		if head, err := tracker.FindEntry(ctx, pr.Head.SHA, pr.Head.Ref); err == nil {
+			impact.Coverage = head.Coverage
+		}
+	}
+
+	trend, err := tracker.GetTrend(ctx,
+		history.WithTrendBranch(pr.Base.Ref),
+		history.WithTrendDays(cfg.History.RetentionDays),
+		history.WithMaxDataPoints(cfg.History.MaxEntries))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the history of %s: %w", pr.Base.Ref, err)
+	}
+	for i := range trend.Entries {
+		entry := &trend.Entries[i]
+		switch {
+		case pr.MergeCommitSHA != "" && entry.CommitSHA == pr.MergeCommitSHA:
+			impact.Recorded = true
+		case impact.Base == nil && entry.Coverage != nil:
+			impact.Base = entry
+		}
+	}
+	return impact, nil
+}
+
+// recordMerge records a merged pull request in the history of its base branch at the merge
+// commit, unless a run of the base branch already recorded it
+func recordMerge(ctx context.Context, cmd *cobra.Command, cfg *config.Config, tracker *history.Tracker, pr *github.PullRequest, impact *finalImpact) {
+	switch {
+	case impact.Recorded:
+		cmd.Printf("📝 Merge commit %s is already in the history of %s\n", shortSHA(pr.MergeCommitSHA), pr.Base.Ref)
+		return
+	case impact.Coverage == nil:
+		cmd.Printf("⚠️  No coverage for the merge of #%d: pass --input or run complete on the pull request first\n", pr.Number)
+		return
+	}
+
+	options := []history.Option{
+		history.WithBranch(pr.Base.Ref),
+		history.WithCommit(pr.MergeCommitSHA, ""),
+		history.WithMetadata("project", cfg.GitHub.Owner+"/"+cfg.GitHub.Repository),
+		history.WithMetadata("pr_number", strconv.Itoa(pr.Number)),
+		history.WithMetadata("pr_title", pr.Title),
+		history.WithMetadata("pr_head_branch", pr.Head.Ref),
+		history.WithMetadata("pr_head_sha", pr.Head.SHA),
+	}
+	if err := tracker.Record(ctx, impact.Coverage, options...); err != nil {
+		cmd.Printf("⚠️  Failed to record the merge of #%d: %v\n", pr.Number, err)
+		return
+	}
+	cmd.Printf("📝 Recorded the merge of #%d in the history of %s: %.2f%%\n", pr.Number, pr.Base.Ref, impact.Coverage.Percentage)
+}
+
+// cleanupPRReports removes the badges, reports and coverage data snapshot of a pull request from
+// the site in dir. Failures are reported but do not fail the command.
+func cleanupPRReports(cmd *cobra.Command, cfg *config.Config, dir string, prNumber int) {
+	number := strconv.Itoa(prNumber)
+	removed := 0
+	for _, rel := range []string{
+		filepath.Join("pr", number),
+		filepath.Join("badges", "pr", number),
+		filepath.Join("reports", "pr", number),
+	} {
+		path := filepath.Join(dir, rel)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			cmd.Printf("⚠️  Failed to remove %s: %v\n", path, err)
+			continue
+		}
+		removed++
+	}
+
+	index, err := snapshot.Load(dir)
+	if err != nil {
+		cmd.Printf("⚠️  Failed to update %s: %v\n", snapshot.IndexFile, err)
+		return
+	}
+	kept := index.Snapshots[:0]
+	for _, entry := range index.Snapshots {
+		if entry.Kind != snapshot.KindPullRequest || entry.Name != number {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) != len(index.Snapshots) {
+		index.Snapshots = kept
+		if err = index.Save(dir, cfg.Storage.FileMode); err != nil {
+			cmd.Printf("⚠️  Failed to update %s: %v\n", snapshot.IndexFile, err)
+		}
+	}
+	cmd.Printf("🧹 Removed %d report directories of #%d from %s\n", removed, prNumber, dir)
+}
+
+// renderFinalComment renders the final summary of a closed pull request
+func renderFinalComment(cfg *config.Config, pr *github.PullRequest, impact *finalImpact, removed bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[//]: # (%s)\n\n", cmp.Or(cfg.GitHub.CommentSignature, templates.DefaultSignature))
+	fmt.Fprintf(&b, "# %s\n\n", cmp.Or(cfg.GitHub.CommentHeader, "Code Coverage Analysis"))
+
+	if !pr.Merged {
+		b.WriteString("🚪 **Closed without merging**: coverage of the base branch is unaffected.\n")
+	} else {
+		fmt.Fprintf(&b, "🏁 **Merged into `%s`**", pr.Base.Ref)
+		if pr.MergeCommitSHA != "" {
+			fmt.Fprintf(&b, " at `%s`", shortSHA(pr.MergeCommitSHA))
+		}
+		b.WriteString("\n")
+
+		if impact.Coverage != nil {
+			b.WriteString("\n| | Coverage |\n|---|---|\n")
+			if impact.Base != nil {
+				fmt.Fprintf(&b, "| `%s` before the merge | %.2f%% |\n", pr.Base.Ref, impact.Base.Coverage.Percentage)
+			}
+			fmt.Fprintf(&b, "| After the merge | %.2f%% |\n", impact.Coverage.Percentage)
+			if impact.Base != nil {
+				fmt.Fprintf(&b, "| **Impact** | **%+.2f%%** |\n", impact.Coverage.Percentage-impact.Base.Coverage.Percentage)
+			}
+		} else {
+			b.WriteString("\nThe coverage of the merge is not known yet: no run of this pull request was recorded.\n")
+		}
+	}
+
+	if removed {
+		b.WriteString("\nThe coverage reports and badges of this pull request were removed from the site.\n")
+	}
+	b.WriteString("\n---\n\n")
+	if cfg.GitHub.CommentFooter != "" {
+		b.WriteString(cfg.GitHub.CommentFooter + "\n")
+	} else {
+		b.WriteString("*Final coverage summary by [go-coverage](https://github.com/mrz1836/go-coverage)*\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/snapshot"
+)
+
+func TestFinalizePR(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, merged bool) (*config.Config, *github.Fake, *history.Tracker, string) {
+		t.Helper()
+		storage := t.TempDir()
+		site := t.TempDir()
+		cfg := &config.Config{
+			GitHub:  config.GitHubConfig{Owner: "owner", Repository: "repo", CommentSignature: "go-coverage-v1"},
+			History: config.HistoryConfig{Enabled: true, StoragePath: storage, RetentionDays: 30, MaxEntries: 100},
+			Storage: config.StorageConfig{FileMode: 0o644, DirMode: 0o755},
+		}
+
+		tracker := history.NewWithConfig(&history.Config{StoragePath: storage, Repository: "owner/repo", RetentionDays: 30, MaxEntries: 100})
+		require.NoError(t, tracker.Record(ctx, &parser.CoverageData{Percentage: 80, TotalLines: 100, CoveredLines: 80},
+			history.WithBranch("main"), history.WithCommit("basesha", "")))
+		require.NoError(t, tracker.Record(ctx, &parser.CoverageData{Percentage: 82.5, TotalLines: 200, CoveredLines: 165},
+			history.WithBranch("feature"), history.WithCommit("headsha", "")))
+
+		fake := github.NewFake()
+		fake.PullRequests[7] = &github.PullRequest{
+			Number: 7, Title: "Add widgets", State: "closed", Merged: merged, MergeCommitSHA: "mergesha1234",
+			Head: github.PullRequestRef{Ref: "feature", SHA: "headsha"},
+			Base: github.PullRequestRef{Ref: "main", SHA: "basesha"},
+		}
+		_, err := fake.AddComment(ctx, "owner", "repo", 7, "[//]: # (go-coverage-v1)\n# Code Coverage Analysis\n")
+		require.NoError(t, err)
+
+		for _, dir := range []string{"pr/7", "badges/pr/7", "reports/pr/7", "pr/8"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(site, dir), 0o750))
+		}
+		index := &snapshot.Index{Snapshots: []snapshot.Entry{
+			{Kind: snapshot.KindPullRequest, Name: "7", Path: "pr/7/coverage-data.json", UpdatedAt: time.Now()},
+			{Kind: snapshot.KindPullRequest, Name: "8", Path: "pr/8/coverage-data.json", UpdatedAt: time.Now()},
+		}}
+		require.NoError(t, index.Save(site, 0o644))
+		return cfg, fake, tracker, site
+	}
+	run := func(t *testing.T, cfg *config.Config, fake *github.Fake, opts finalizeOptions) (string, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		err := finalizePR(ctx, cmd, cfg, fake, 7, opts)
+		return out.String(), err
+	}
+
+	t.Run("merged", func(t *testing.T) {
+		cfg, fake, tracker, site := setup(t, true)
+		output, err := run(t, cfg, fake, finalizeOptions{outputDir: site})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Final comment created")
+
+		comments := fake.Comments[7]
+		require.Len(t, comments, 2)
+		assert.Equal(t, github.MinimizeOutdated, fake.Minimized[comments[0].NodeID])
+		assert.Contains(t, comments[1].Body, "🏁 **Merged into `main`** at `mergesh`")
+		assert.Contains(t, comments[1].Body, "| `main` before the merge | 80.00% |")
+		assert.Contains(t, comments[1].Body, "| **Impact** | **+2.50%** |")
+
+		entry, err := tracker.FindEntry(ctx, "mergesha1234")
+		require.NoError(t, err)
+		assert.Equal(t, "main", entry.Branch)
+		assert.InDelta(t, 82.5, entry.Coverage.Percentage, 0.001)
+		assert.Equal(t, "7", entry.Metadata["pr_number"])
+		assert.Equal(t, "Add widgets", entry.Metadata["pr_title"])
+		assert.Equal(t, "headsha", entry.Metadata["pr_head_sha"])
+
+		for _, dir := range []string{"pr/7", "badges/pr/7", "reports/pr/7"} {
+			assert.NoDirExists(t, filepath.Join(site, dir))
+		}
+		assert.DirExists(t, filepath.Join(site, "pr/8"))
+		index, err := snapshot.Load(site)
+		require.NoError(t, err)
+		require.Len(t, index.Snapshots, 1)
+		assert.Equal(t, "8", index.Snapshots[0].Name)
+
+		// A second run updates the summary and does not record the merge again
+		output, err = run(t, cfg, fake, finalizeOptions{outputDir: site})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Final comment updated")
+		assert.Contains(t, output, "already in the history of main")
+		assert.Len(t, fake.Comments[7], 2)
+	})
+
+	t.Run("closed without merging", func(t *testing.T) {
+		cfg, fake, tracker, site := setup(t, false)
+		cfg.Report.KeepClosedPRs = true
+		output, err := run(t, cfg, fake, finalizeOptions{outputDir: site})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Keeping the reports of #7")
+		assert.Contains(t, fake.Comments[7][1].Body, "Closed without merging")
+		assert.NotContains(t, fake.Comments[7][1].Body, "removed from the site")
+
+		_, err = tracker.FindEntry(ctx, "mergesha1234")
+		require.ErrorIs(t, err, history.ErrNoEntriesFound)
+		assert.DirExists(t, filepath.Join(site, "pr/7"))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		cfg, fake, _, site := setup(t, true)
+		output, err := run(t, cfg, fake, finalizeOptions{outputDir: site, dryRun: true})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Final Comment Preview (Dry Run)")
+		assert.Contains(t, output, "**+2.50%**")
+		assert.Len(t, fake.Comments[7], 1)
+		assert.DirExists(t, filepath.Join(site, "pr/7"))
+	})
+
+	t.Run("open pull request", func(t *testing.T) {
+		cfg, fake, _, site := setup(t, false)
+		fake.PullRequests[7].State = "open"
+		_, err := run(t, cfg, fake, finalizeOptions{outputDir: site})
+		require.ErrorIs(t, err, ErrPullRequestOpen)
+	})
+}
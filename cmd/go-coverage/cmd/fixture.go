@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/fixture"
+)
+
+const (
+	// flagNameRecordFixtures is the global flag that records a run into a fixture directory
+	flagNameRecordFixtures = "record-fixtures"
+	// flagNameReplayFixtures is the global flag that replays a run from a fixture directory
+	flagNameReplayFixtures = "replay-fixtures"
+	// envRecordFixtures is the environment variable equivalent of --record-fixtures
+	envRecordFixtures = "GO_COVERAGE_RECORD_FIXTURES"
+	// envReplayFixtures is the environment variable equivalent of --replay-fixtures
+	envReplayFixtures = "GO_COVERAGE_REPLAY_FIXTURES"
+)
+
+// applyFixtureMode starts recording or replaying fixtures when requested by flag or environment.
+// The directory is recorded in the environment so every configuration loaded by the command
+// sees it. Recording captures the command and its environment; replaying restores the recorded
+// environment, without overriding variables that are already set so paths can be redirected.
+func applyFixtureMode(cmd *cobra.Command) error {
+	if dir, _ := cmd.Flags().GetString(flagNameRecordFixtures); dir != "" {
+		_ = os.Setenv(envRecordFixtures, dir)
+	}
+	if dir, _ := cmd.Flags().GetString(flagNameReplayFixtures); dir != "" {
+		_ = os.Setenv(envReplayFixtures, dir)
+	}
+	recordDir, replayDir := os.Getenv(envRecordFixtures), os.Getenv(envReplayFixtures)
+
+	switch {
+	case recordDir != "" && replayDir != "":
+		return config.ErrInvalidFixtureMode
+	case recordDir != "":
+		manifest := fixture.NewManifest(fixtureCommand(cmd), os.Environ(), envRecordFixtures, envReplayFixtures)
+		if err := fixture.StartRecording(recordDir, manifest); err != nil {
+			return err
+		}
+		cmd.PrintErrf("⏺️  Recording fixtures to %s\n", recordDir)
+	case replayDir != "":
+		manifest, err := fixture.StartReplay(replayDir)
+		if err != nil {
+			return err
+		}
+		for name, value := range manifest.Environment {
+			if _, set := os.LookupEnv(name); !set {
+				_ = os.Setenv(name, value)
+			}
+		}
+		cmd.PrintErrf("🔁 Replaying fixtures from %s (recorded %s: %s)\n", replayDir,
+			manifest.RecordedAt.Format("2006-01-02 15:04 MST"), strings.Join(manifest.Command, " "))
+	}
+	return nil
+}
+
+// fixtureCommand returns the command line of a run as recorded in its fixture manifest
+func fixtureCommand(cmd *cobra.Command) []string {
+	command := strings.Fields(cmd.CommandPath())
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name != flagNameRecordFixtures {
+			command = append(command, fmt.Sprintf("--%s=%s", flag.Name, flag.Value))
+		}
+	})
+	return append(command, cmd.Flags().Args()...)
+}
+
+// fixtureInput returns the input file to read: while recording the file is copied into the
+// fixture directory, and while replaying the recorded copy is read when there is one
+func fixtureInput(cfg *config.Config, path string) (string, error) {
+	switch {
+	case path == "":
+		return path, nil
+	case cfg.Network.RecordFixtures != "":
+		return path, fixture.RecordInput(cfg.Network.RecordFixtures, path)
+	case cfg.Network.ReplayFixtures != "":
+		recorded := fixture.InputPath(cfg.Network.ReplayFixtures, path)
+		if _, err := os.Stat(recorded); err == nil {
+			return recorded, nil
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/fixture"
+)
+
+func TestCompleteCommandRecordAndReplayFixtures(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_LOCAL", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("GO_COVERAGE_BADGE_LABEL", "recorded")
+
+	tempDir := t.TempDir()
+	fixtures := filepath.Join(tempDir, "fixtures")
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/test/repo/main.go:10.2,12.16 2 1
+github.com/test/repo/main.go:15.2,17.16 2 0
+`), 0o600))
+
+	output, err := executeCommand(t, cmdComplete, "--record-fixtures", fixtures, "--local",
+		"--input", coverageFile, "--output", filepath.Join(tempDir, "recorded"), "--skip-history")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Recording fixtures to "+fixtures)
+	assert.FileExists(t, fixture.InputPath(fixtures, coverageFile))
+
+	manifest, err := fixture.StartReplay(fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded", manifest.Environment["GO_COVERAGE_BADGE_LABEL"])
+	assert.Contains(t, manifest.Command, "--local=true")
+	assert.NotContains(t, manifest.Environment, envRecordFixtures)
+
+	// The replay reads the recorded profile and environment, not the current ones
+	require.NoError(t, os.Remove(coverageFile))
+	t.Setenv(envRecordFixtures, "")
+	require.NoError(t, os.Unsetenv("GO_COVERAGE_BADGE_LABEL"))
+
+	replayed := filepath.Join(tempDir, "replayed")
+	output, err = executeCommand(t, cmdComplete, "--replay-fixtures", fixtures, "--local",
+		"--input", coverageFile, "--output", replayed, "--skip-history")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Replaying fixtures from "+fixtures)
+	assert.Contains(t, output, "Coverage:")
+	assert.Equal(t, "recorded", os.Getenv("GO_COVERAGE_BADGE_LABEL"))
+	assert.FileExists(t, filepath.Join(replayed, "coverage.svg"))
+}
+
+func TestFixtureModesAreExclusive(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := t.TempDir()
+
+	_, err := executeCommand(t, "--record-fixtures", dir, "--replay-fixtures", dir, "templates", "sample")
+	require.ErrorIs(t, err, config.ErrInvalidFixtureMode)
+}
+
+func TestReplayFixturesRequiresManifest(t *testing.T) {
+	isolateOfflineEnv(t)
+
+	_, err := executeCommand(t, "--replay-fixtures", t.TempDir(), "templates", "sample")
+	require.ErrorIs(t, err, fixture.ErrNoManifest)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrOutputIsFormat indicates a format name passed to --output, which takes a file path
+var ErrOutputIsFormat = errors.New("--output takes a file path, not a format")
+
+// Shared flag names used by more than one command
+const (
+	flagNameInput    = "input"
+	flagNameCoverage = "coverage"
+	flagNameDryRun   = "dry-run"
+)
+
+// addCoverageInputFlags registers the coverage profile input flags on a command.
+// Both spellings (--input/-i and --coverage/-c) are accepted by every command that
+// reads a coverage profile so the pipeline commands cannot drift apart.
+func addCoverageInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(flagNameInput, "i", "", "Input coverage file")
+	cmd.Flags().StringP(flagNameCoverage, "c", "", "Path to coverage profile file (alias for --input)")
+}
+
+// getCoverageInputFlag returns the coverage profile path, preferring --input over --coverage
+func getCoverageInputFlag(cmd *cobra.Command) string {
+	if input, _ := cmd.Flags().GetString(flagNameInput); input != "" {
+		return input
+	}
+	coverage, _ := cmd.Flags().GetString(flagNameCoverage)
+	return coverage
+}
+
+// addDryRunFlag registers the shared --dry-run flag with a command-specific description
+func addDryRunFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool(flagNameDryRun, false, usage)
+}
+
+// checkOutputPath rejects an --output value that names one of the formats of the command, as
+// written for commands whose --output used to take the format, instead of writing a file by that name
+func checkOutputPath(path string, formats ...string) error {
+	for _, format := range formats {
+		if strings.EqualFold(strings.TrimSpace(path), format) {
+			return fmt.Errorf("%w: use --format %s instead of --output %s", ErrOutputIsFormat, format, path)
+		}
+	}
+	return nil
+}
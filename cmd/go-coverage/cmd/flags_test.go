@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageInputFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "no flags",
+			args:     []string{},
+			expected: "",
+		},
+		{
+			name:     "input long flag",
+			args:     []string{"--input", "a.txt"},
+			expected: "a.txt",
+		},
+		{
+			name:     "input short flag",
+			args:     []string{"-i", "a.txt"},
+			expected: "a.txt",
+		},
+		{
+			name:     "coverage alias",
+			args:     []string{"--coverage", "b.txt"},
+			expected: "b.txt",
+		},
+		{
+			name:     "coverage short alias",
+			args:     []string{"-c", "b.txt"},
+			expected: "b.txt",
+		},
+		{
+			name:     "input takes precedence",
+			args:     []string{"-c", "b.txt", "-i", "a.txt"},
+			expected: "a.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: testCoverageLabel}
+			addCoverageInputFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			assert.Equal(t, tt.expected, getCoverageInputFlag(cmd))
+		})
+	}
+}
+
+func TestSharedFlagsAcrossCommands(t *testing.T) {
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+
+	// Commands that read a coverage profile must accept the same input flags
+	for _, cmd := range []*cobra.Command{commands.Complete, commands.Comment} {
+		t.Run(cmd.Name(), func(t *testing.T) {
+			for _, name := range []string{flagNameInput, flagNameCoverage} {
+				flag := cmd.Flags().Lookup(name)
+				require.NotNil(t, flag, "flag %s should exist", name)
+				assert.Equal(t, flagTypeString, flag.Value.Type())
+			}
+			assert.Equal(t, "i", cmd.Flags().Lookup(flagNameInput).Shorthand)
+			assert.Equal(t, "c", cmd.Flags().Lookup(flagNameCoverage).Shorthand)
+		})
+	}
+
+	// Every command with a dry-run mode shares the same flag definition
+	for _, cmd := range []*cobra.Command{commands.Complete, commands.Comment, commands.SetupPages} {
+		flag := cmd.Flags().Lookup(flagDryRun)
+		require.NotNil(t, flag, "%s should have a dry-run flag", cmd.Name())
+		assert.Equal(t, flagBoolFalse, flag.DefValue)
+	}
+}
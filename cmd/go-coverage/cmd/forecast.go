@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// newForecastData forecasts the coverage of the base branch once the pull request is merged, from
+// the latest run of the branch in the history and the change the pull request makes against its
+// base profile. The projection is checked against the threshold and max drop of the base branch,
+// which its branch rule sets. It is nil without history or comparison.
+func newForecastData(cfg *config.Config, latest *history.Entry, comparison *analysis.ComparisonResult) *templates.ForecastData {
+	if latest == nil || latest.Coverage == nil || comparison == nil {
+		return nil
+	}
+	current := analysis.CoverageMetrics{
+		Percentage:        latest.Coverage.Percentage,
+		TotalStatements:   latest.Coverage.TotalLines,   // Actually statement count
+		CoveredStatements: latest.Coverage.CoveredLines, // Actually covered statement count
+	}
+	forecast := analysis.ForecastMerge(runBaseBranch(cfg), current, comparison, analysis.ForecastLimits{
+		Threshold: cfg.Coverage.Threshold,
+		MaxDrop:   cfg.Policy.MaxDrop,
+	})
+	return &templates.ForecastData{
+		Branch:     forecast.Branch,
+		Current:    forecast.Current,
+		Projected:  forecast.Projected,
+		Violations: forecast.Violations,
+	}
+}
+
+// printForecast announces the merge forecast in the job log
+func printForecast(cmd *cobra.Command, forecast *templates.ForecastData) {
+	if forecast == nil {
+		return
+	}
+	cmd.Printf("🔮 Merge forecast: %s %.2f%% → %.2f%%\n", forecast.Branch, forecast.Current, forecast.Projected)
+	if len(forecast.Violations) > 0 {
+		cmd.Printf("   ⚠️  Limits of %s the merge would break: %s\n", forecast.Branch, strings.Join(forecast.Violations, ", "))
+	}
+}
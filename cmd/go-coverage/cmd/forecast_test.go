@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestNewForecastData(t *testing.T) {
+	cfg := &config.Config{
+		CI:       &ci.Context{Branch: "feature", BaseBranch: "main"},
+		Coverage: config.CoverageConfig{Threshold: 80},
+		Policy:   config.PolicyConfig{MaxDrop: 0.5},
+	}
+	latest := &history.Entry{Coverage: &parser.CoverageData{Percentage: 81.2, TotalLines: 1000, CoveredLines: 812}}
+	comparison := func(statements, covered int) *analysis.ComparisonResult {
+		return &analysis.ComparisonResult{OverallChange: analysis.OverallChangeAnalysis{
+			StatementChange: statements, CoveredStatementChange: covered,
+		}}
+	}
+
+	assert.Nil(t, newForecastData(cfg, nil, comparison(10, 10)))
+	assert.Nil(t, newForecastData(cfg, latest, nil))
+
+	forecast := newForecastData(cfg, latest, comparison(10, 10))
+	require.NotNil(t, forecast)
+	assert.Equal(t, "main", forecast.Branch)
+	assert.InDelta(t, 81.2, forecast.Current, 0.001)
+	assert.InDelta(t, 822.0/1010*100, forecast.Projected, 0.001)
+	assert.Empty(t, forecast.Violations)
+
+	forecast = newForecastData(cfg, latest, comparison(100, 0))
+	require.NotNil(t, forecast)
+	assert.Equal(t, []string{"threshold of 80.00%", "max drop of 0.50 points (7.38 projected)"}, forecast.Violations)
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	printForecast(cmd, forecast)
+	assert.Contains(t, out.String(), "🔮 Merge forecast: main 81.20% → 73.82%")
+	assert.Contains(t, out.String(), "Limits of main the merge would break: threshold of 80.00%, max drop")
+}
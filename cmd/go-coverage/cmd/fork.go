@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+const (
+	// envEventPath points at the JSON payload of the event that triggered the workflow
+	envEventPath = "GITHUB_EVENT_PATH"
+	// envStepSummary is the Markdown file rendered on the workflow run summary page
+	envStepSummary = "GITHUB_STEP_SUMMARY"
+)
+
+// forkSafeMode reports whether results must be handed off instead of posted, because the run
+// belongs to a pull request from a fork and its token cannot write comments or statuses
+func forkSafeMode(cfg *config.Config) (bool, error) {
+	switch cfg.GitHub.ForkMode {
+	case config.ForkModeAlways:
+		return true, nil
+	case config.ForkModeNever:
+		return false, nil
+	}
+
+	eventPath := os.Getenv(envEventPath)
+	if eventPath == "" {
+		return false, nil
+	}
+	return github.IsForkPullRequest(eventPath)
+}
+
+// writeHandoff stores the results for a trusted workflow to post and adds the comment to the
+// step summary, so the results are visible on the run even if nothing relays them
+func writeHandoff(cmd *cobra.Command, cfg *config.Config, payload *handoff.Payload) error {
+	redactor, err := cfg.NewRedactor()
+	if err != nil {
+		return fmt.Errorf("failed to configure redaction: %w", err)
+	}
+
+	payload.Owner = cfg.GitHub.Owner
+	payload.Repository = cfg.GitHub.Repository
+	payload.CommitSHA = cfg.GitHub.CommitSHA
+	payload.Comment = redactor.String(payload.Comment)
+	payload.GeneratedAt = time.Now().UTC()
+
+	path, err := handoff.Write(cfg.GitHub.HandoffDir, payload, cfg.Storage.FileMode)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("🍴 Fork pull request: results written to %s\n", path)
+	cmd.Printf("   Upload %s as a workflow artifact and post it from a workflow_run workflow\n", cfg.GitHub.HandoffDir)
+
+	if err := appendStepSummary(payload.Comment); err != nil {
+		cmd.Printf("Warning: failed to write step summary: %v\n", err)
+	}
+	return nil
+}
+
+// appendStepSummary appends Markdown to the step summary when running in GitHub Actions
+func appendStepSummary(markdown string) error {
+	path := os.Getenv(envStepSummary)
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // path is provided by the runner
+	if err != nil {
+		return fmt.Errorf("failed to open step summary: %w", err)
+	}
+	if _, err = fmt.Fprintf(file, "%s\n\n", markdown); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write step summary: %w", err)
+	}
+	return file.Close()
+}
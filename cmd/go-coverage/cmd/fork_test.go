@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+)
+
+const forkEventPayload = `{"pull_request":{"head":{"repo":{"full_name":"contributor/repo"}},"base":{"repo":{"full_name":"owner/repo"}}}}`
+
+func TestForkSafeMode(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(forkEventPayload), 0o600))
+
+	tests := []struct {
+		name      string
+		mode      string
+		eventPath string
+		expected  bool
+	}{
+		{name: "auto detects fork", mode: config.ForkModeAuto, eventPath: eventPath, expected: true},
+		{name: "auto without event", mode: config.ForkModeAuto, expected: false},
+		{name: "always", mode: config.ForkModeAlways, expected: true},
+		{name: "never ignores fork", mode: config.ForkModeNever, eventPath: eventPath, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envEventPath, tt.eventPath)
+			cfg := &config.Config{GitHub: config.GitHubConfig{ForkMode: tt.mode}}
+			forkSafe, err := forkSafeMode(cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, forkSafe)
+		})
+	}
+}
+
+func TestPostNoCodeChangesCommentForkSafe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	summaryPath := filepath.Join(dir, "summary.md")
+	t.Setenv(envStepSummary, summaryPath)
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Owner:      "owner",
+			Repository: "repo",
+			CommitSHA:  "0123456789abcdef0123456789abcdef01234567",
+			HandoffDir: filepath.Join(dir, "handoff"),
+		},
+		Policy:  config.PolicyConfig{NoCodeChanges: config.NoCodeChangesSuccess},
+		Storage: config.StorageConfig{FileMode: 0o644},
+	}
+	client := github.NewWithConfig(&github.Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	analysisResult := github.AnalyzePRFiles(&github.PRDiff{Files: []github.PRFile{{Filename: "README.md", Status: "modified"}}})
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := postNoCodeChangesComment(context.Background(), cmd, cfg, client, 7, analysisResult,
+		"https://example.com/badge.svg", "https://example.com/report.html", true, false, true)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Fork pull request")
+
+	payload, err := handoff.Read(cfg.GitHub.HandoffDir)
+	require.NoError(t, err)
+	assert.Equal(t, 7, payload.PullRequest)
+	assert.Equal(t, "owner", payload.Owner)
+	assert.Nil(t, payload.Coverage)
+	require.Len(t, payload.Statuses, 2)
+	assert.Equal(t, github.StatusStateSuccess, payload.Statuses[0].State)
+	assert.Contains(t, payload.Comment, "No code changes")
+
+	summary, err := os.ReadFile(summaryPath) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "No code changes")
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/gerrit"
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// ErrGerritChangeRequired indicates that the change under review is unknown
+var ErrGerritChangeRequired = errors.New("gerrit change and patchset are required (set GERRIT_CHANGE_NUMBER and GERRIT_PATCHSET_NUMBER or pass --change and --patchset)")
+
+// newGerritCmd creates the gerrit command
+func (c *Commands) newGerritCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gerrit",
+		Short: "Post a coverage review on a Gerrit change",
+		Long: `Post the coverage summary as a review message on a Gerrit change and vote on a label
+with the result of the coverage gates.
+
+The change is read from the variables set by the Jenkins Gerrit Trigger (GERRIT_CHANGE_NUMBER,
+GERRIT_PATCHSET_NUMBER, GERRIT_PATCHSET_REVISION, GERRIT_PROJECT and GERRIT_BRANCH). The review
+is posted with the REST API when GO_COVERAGE_GERRIT_USERNAME and GO_COVERAGE_GERRIT_PASSWORD are
+set, otherwise with "gerrit review" over SSH to GERRIT_HOST.
+
+The gates are evaluated against the newest history entry of the target branch, or against
+--base-coverage. The command succeeds when the review is posted; the vote carries the gate
+result.`,
+		Example: `  # Vote Code-Coverage +1/-1 on the change that triggered the build
+  GO_COVERAGE_GERRIT_LABEL=Code-Coverage go-coverage gerrit --input coverage.txt
+
+  # Preview the review without posting it
+  go-coverage gerrit --change 12345 --patchset 2 --dry-run`,
+		RunE: c.runGerrit,
+	}
+
+	addCoverageInputFlags(cmd)
+	cmd.Flags().Int("change", 0, "Change number (default: GERRIT_CHANGE_NUMBER)")
+	cmd.Flags().Int("patchset", 0, "Patchset number (default: GERRIT_PATCHSET_NUMBER)")
+	cmd.Flags().String("base-coverage", "", "Path to base branch coverage file for the gates")
+	cmd.Flags().String("label", "", "Label to vote on (default: GO_COVERAGE_GERRIT_LABEL or Verified)")
+	cmd.Flags().Bool("vote", true, "Vote on the label with the gate result")
+	addDryRunFlag(cmd, "Print the review without posting it")
+
+	return cmd
+}
+
+// runGerrit executes the gerrit command
+func (c *Commands) runGerrit(cmd *cobra.Command, _ []string) error {
+	inputFile := getCoverageInputFlag(cmd)
+	change, _ := cmd.Flags().GetInt("change")
+	patchset, _ := cmd.Flags().GetInt("patchset")
+	baseFile, _ := cmd.Flags().GetString("base-coverage")
+	label, _ := cmd.Flags().GetString("label")
+	vote, _ := cmd.Flags().GetBool("vote")
+	dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if change > 0 {
+		cfg.Gerrit.Change = change
+	}
+	if patchset > 0 {
+		cfg.Gerrit.Patchset = patchset
+	}
+	if label != "" {
+		cfg.Gerrit.Label = label
+	}
+	if !cfg.IsGerritContext() {
+		return ErrGerritChangeRequired
+	}
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	targetBranch := cfg.Gerrit.Branch
+	if targetBranch == "" {
+		targetBranch = getDefaultBranch()
+	}
+	gates, err := evaluateGates(ctx, cmd, cfg, inputFile, baseFile, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	review := &gerrit.Review{
+		Message: renderGerritMessage(gates, targetBranch),
+		Tag:     gerrit.Tag,
+	}
+	if vote && cfg.Gerrit.Label != "" {
+		score := cfg.Gerrit.PassVote
+		if !gates.Decision.Passed {
+			score = cfg.Gerrit.FailVote
+		}
+		review.Labels = map[string]int{cfg.Gerrit.Label: score}
+	}
+
+	target := gerrit.Change{
+		Project:  cfg.Gerrit.Project,
+		Number:   cfg.Gerrit.Change,
+		Patchset: cfg.Gerrit.Patchset,
+		Revision: cfg.Gerrit.Revision,
+	}
+	if dryRun {
+		cmd.Printf("🧪 DRY RUN: Would post review on change %s%s\n", target, describeGerritVote(review))
+		cmd.Printf("=====================================\n%s\n=====================================\n", review.Message)
+		return nil
+	}
+
+	if err = requireNetwork(cmd, cfg, "posting the Gerrit review"); err != nil {
+		return err
+	}
+	httpClient, err := cfg.NewHTTPClient(30 * time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	reviewer, err := gerrit.New(&gerrit.Config{
+		URL:         cfg.Gerrit.URL,
+		Username:    cfg.Gerrit.Username,
+		Password:    cfg.Gerrit.Password,
+		SSHHost:     cfg.Gerrit.SSHHost,
+		SSHPort:     cfg.Gerrit.SSHPort,
+		SSHUser:     cfg.Gerrit.SSHUser,
+		SSHKey:      cfg.Gerrit.SSHKey,
+		UserAgent:   "go-coverage/2.0",
+		RetryPolicy: cfg.RetryPolicy(retry.OpGerritAPI),
+		HTTPClient:  httpClient,
+	})
+	if err != nil {
+		return err
+	}
+	if err = reviewer.SetReview(ctx, target, review); err != nil {
+		return err
+	}
+
+	cmd.Printf("✅ Coverage review posted on change %s%s\n", target, describeGerritVote(review))
+	return nil
+}
+
+// renderGerritMessage formats the coverage summary as a Gerrit review message. Gerrit renders
+// change messages as plain text with simple bullet lists, so no Markdown tables are used.
+func renderGerritMessage(gates *gateResult, targetBranch string) string {
+	var b strings.Builder
+
+	coverage := gates.Coverage
+	fmt.Fprintf(&b, "Coverage: %.2f%% (%d/%d statements)\n", coverage.Percentage, coverage.CoveredLines, coverage.TotalLines)
+	switch baseline, ok := gates.baseline(); {
+	case ok && gates.Base != nil:
+		fmt.Fprintf(&b, "Change: %+.2f%% vs base coverage (%.2f%%)\n", coverage.Percentage-baseline, baseline)
+	case ok:
+		fmt.Fprintf(&b, "Change: %+.2f%% vs %s (%.2f%%)\n", coverage.Percentage-baseline, targetBranch, baseline)
+	default:
+		fmt.Fprintf(&b, "No coverage recorded for %s yet\n", targetBranch)
+	}
+
+	decision := gates.Decision
+	if decision.Passed {
+		b.WriteString("\nCoverage policy: PASSED\n")
+	} else {
+		b.WriteString("\nCoverage policy: FAILED\n")
+	}
+	for _, result := range decision.Results {
+		fmt.Fprintf(&b, "* %s %s: %s\n", result.Outcome.Icon(), result.Rule, result.Message)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// describeGerritVote returns the vote of the review for console output
+func describeGerritVote(review *gerrit.Review) string {
+	for label, score := range review.Labels {
+		return fmt.Sprintf(" with %s%+d", label, score)
+	}
+	return ""
+}
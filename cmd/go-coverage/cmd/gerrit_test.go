@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/gerrit"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// setupGerrit isolates the Gerrit environment and writes the coverage profile for the gerrit command
+func setupGerrit(t *testing.T) string {
+	t.Helper()
+	return setupProviderProfile(t,
+		"GERRIT_CHANGE_URL", "GERRIT_HOST", "GERRIT_PORT", "GERRIT_PROJECT", "GERRIT_BRANCH",
+		"GERRIT_CHANGE_NUMBER", "GERRIT_PATCHSET_NUMBER", "GERRIT_PATCHSET_REVISION",
+		"GO_COVERAGE_GERRIT_URL", "GO_COVERAGE_GERRIT_USERNAME", "GO_COVERAGE_GERRIT_PASSWORD",
+		"GO_COVERAGE_GERRIT_SSH_USER", "GO_COVERAGE_GERRIT_SSH_KEY", "GO_COVERAGE_GERRIT_LABEL",
+		"GO_COVERAGE_GERRIT_PASS_VOTE", "GO_COVERAGE_GERRIT_FAIL_VOTE", "GO_COVERAGE_BRANCH_RULES",
+	)
+}
+
+func TestGerritCommandRequiresChange(t *testing.T) {
+	profile := setupGerrit(t)
+
+	_, err := executeCommand(t, "gerrit", "--input", profile)
+	require.ErrorIs(t, err, ErrGerritChangeRequired)
+}
+
+func TestGerritCommandPostsReview(t *testing.T) {
+	profile := setupGerrit(t)
+
+	var (
+		path   string
+		review gerrit.Review
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &review)
+		_, _ = w.Write([]byte(")]}'\n{}"))
+	}))
+	defer server.Close()
+
+	t.Setenv("GERRIT_CHANGE_URL", server.URL+"/c/service/+/321")
+	t.Setenv("GERRIT_PROJECT", "service")
+	t.Setenv("GERRIT_CHANGE_NUMBER", "321")
+	t.Setenv("GERRIT_PATCHSET_NUMBER", "4")
+	t.Setenv("GO_COVERAGE_GERRIT_USERNAME", "ci")
+	t.Setenv("GO_COVERAGE_GERRIT_PASSWORD", "secret")
+	t.Setenv("GO_COVERAGE_GERRIT_LABEL", "Code-Coverage")
+
+	output, err := executeCommand(t, "gerrit", "--input", profile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Coverage review posted on change 321,4 with Code-Coverage+1")
+	assert.Equal(t, "/a/changes/service~321/revisions/4/review", path)
+	assert.Equal(t, map[string]int{"Code-Coverage": 1}, review.Labels)
+	assert.Equal(t, gerrit.Tag, review.Tag)
+	assert.Contains(t, review.Message, "Coverage: 50.00% (5/10 statements)")
+
+	// A failed gate votes the fail score
+	t.Setenv("GO_COVERAGE_THRESHOLD", "90")
+	output, err = executeCommand(t, "gerrit", "--input", profile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "with Code-Coverage-1")
+	assert.Contains(t, review.Message, "Coverage policy: FAILED")
+}
+
+func TestGerritCommandDryRun(t *testing.T) {
+	profile := setupGerrit(t)
+
+	output, err := executeCommand(t, "gerrit", "--input", profile, "--change", "7", "--patchset", "1", "--vote=false", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, output, "DRY RUN: Would post review on change 7,1\n")
+	assert.Contains(t, output, "Coverage policy: PASSED")
+}
+
+func TestRenderGerritMessage(t *testing.T) {
+	gates := &gateResult{
+		Coverage: &parser.CoverageData{Percentage: 82.5, TotalLines: 400, CoveredLines: 330},
+		Previous: []float64{81.3},
+		Decision: &policy.Decision{Passed: true},
+	}
+	message := renderGerritMessage(gates, "main")
+	assert.Equal(t, "Coverage: 82.50% (330/400 statements)\nChange: +1.20% vs main (81.30%)\n\nCoverage policy: PASSED", message)
+
+	gates.Base = &parser.CoverageData{Percentage: 85}
+	assert.Contains(t, renderGerritMessage(gates, "main"), "Change: -2.50% vs base coverage (85.00%)")
+
+	gates.Base, gates.Previous = nil, nil
+	assert.Contains(t, renderGerritMessage(gates, "main"), "No coverage recorded for main yet")
+}
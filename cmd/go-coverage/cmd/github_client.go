@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// githubAPIBaseURL is the GitHub REST API endpoint used by all commands
+const githubAPIBaseURL = "https://api.github.com"
+
+// newGitHubClient creates a GitHub client using the configured retry policy and network settings
+func newGitHubClient(cfg *config.Config, userAgent string) (*github.Client, error) {
+	httpClient, err := cfg.NewHTTPClient(cfg.GitHub.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+
+	return github.NewWithConfig(&github.Config{
+		Token:               cfg.GitHub.Token,
+		BaseURL:             githubAPIBaseURL,
+		Timeout:             cfg.GitHub.Timeout,
+		RetryPolicy:         cfg.RetryPolicy(retry.OpGitHubAPI),
+		ArtifactRetryPolicy: cfg.RetryPolicy(retry.OpArtifactDownload),
+		UserAgent:           userAgent,
+		HTTPClient:          httpClient,
+	}), nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// goalBarWidth is the number of cells of the text progress bar
+const goalBarWidth = 20
+
+// goalProgress returns how far coverage has come from the start of the goal to the goal, as a
+// percentage between 0 and 100
+func goalProgress(goal config.GoalConfig, coverage float64) float64 {
+	if goal.Target <= goal.Start {
+		return 100
+	}
+	return math.Max(0, math.Min(100, (coverage-goal.Start)/(goal.Target-goal.Start)*100))
+}
+
+// goalItemTitle is the title of the project item, which shows the progress on the board
+func goalItemTitle(goal config.GoalConfig, progress float64) string {
+	return fmt.Sprintf("%s: %.0f%% complete", goal.Title, math.Floor(progress))
+}
+
+// isGoalItemTitle reports whether a project item title is the title of the goal item
+func isGoalItemTitle(goal config.GoalConfig, title string) bool {
+	return title == goal.Title || strings.HasPrefix(title, goal.Title+": ")
+}
+
+// renderGoalProgress describes the progress toward the goal, for the milestone description and
+// the body of the project item
+func renderGoalProgress(cfg *config.Config, branch string, coverage, progress float64) string {
+	goal := cfg.Goal
+	filled := int(math.Round(progress / 100 * goalBarWidth))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Coverage of `%s` is **%.2f%%**, working toward **%.2f%%** from %.2f%%: **%.0f%% complete**.\n\n",
+		branch, coverage, goal.Target, goal.Start, math.Floor(progress))
+	fmt.Fprintf(&b, "`%s%s` %.0f%%\n\n", strings.Repeat("█", filled), strings.Repeat("░", goalBarWidth-filled), math.Floor(progress))
+	switch {
+	case coverage >= goal.Target:
+		b.WriteString("🎉 The goal is reached.")
+	default:
+		fmt.Fprintf(&b, "%.2f percentage points to go.", goal.Target-coverage)
+	}
+	if goal.Due != "" {
+		fmt.Fprintf(&b, " Due %s.", goal.Due)
+	}
+	if cfg.GitHub.CommitSHA != "" {
+		fmt.Fprintf(&b, " Last measured at `%s`.", shortSHA(cfg.GitHub.CommitSHA))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// newGoalClient returns the client of the goal tracker: a client for GO_COVERAGE_GOAL_TOKEN when
+// it is set, since the repository token of GitHub Actions cannot reach projects, or else client
+func newGoalClient(cfg *config.Config, client *github.Client) (*github.Client, error) {
+	if cfg.Goal.Token == "" || cfg.Goal.Token == cfg.GitHub.Token {
+		return client, nil
+	}
+	goalCfg := *cfg
+	goalCfg.GitHub.Token = cfg.Goal.Token
+	return newGitHubClient(&goalCfg, "go-coverage/2.0")
+}
+
+// updateCoverageGoal records the progress toward the coverage goal in the configured tracker
+func updateCoverageGoal(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, branch string, coverage float64) error {
+	progress := goalProgress(cfg.Goal, coverage)
+	body := renderGoalProgress(cfg, branch, coverage, progress)
+	cmd.Printf("   🎯 Coverage goal: %.0f%% complete (%.2f%% of %.2f%%)\n", math.Floor(progress), coverage, cfg.Goal.Target)
+
+	switch cfg.Goal.Tracker {
+	case config.GoalTrackerMilestone:
+		return updateGoalMilestone(ctx, cmd, cfg, client, body, coverage >= cfg.Goal.Target)
+	case config.GoalTrackerProject:
+		return updateGoalProjectItem(ctx, cmd, cfg, client, goalItemTitle(cfg.Goal, progress), body)
+	default:
+		return nil
+	}
+}
+
+// updateGoalMilestone creates the goal milestone or refreshes its description, closing it once
+// the goal is reached and reopening it when coverage falls back below the goal
+func updateGoalMilestone(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, description string, reached bool) error {
+	owner, repo, title := cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.Goal.Title
+	state := github.MilestoneOpen
+	if reached {
+		state = github.MilestoneClosed
+	}
+	dueOn := ""
+	if cfg.Goal.Due != "" {
+		dueOn = cfg.Goal.Due + "T00:00:00Z"
+	}
+
+	milestone, err := client.FindMilestone(ctx, owner, repo, title)
+	if errors.Is(err, github.ErrMilestoneNotFound) {
+		milestone, err = client.CreateMilestone(ctx, owner, repo, github.MilestoneRequest{
+			Title: title, Description: description, State: state, DueOn: dueOn,
+		})
+		if err != nil {
+			return err
+		}
+		cmd.Printf("   🎯 Created goal milestone: %s\n", milestone.URL)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if milestone.Description == description && milestone.State == state {
+		cmd.Printf("   🎯 Goal milestone is up to date: %s\n", milestone.URL)
+		return nil
+	}
+	if milestone, err = client.UpdateMilestone(ctx, owner, repo, milestone.Number, github.MilestoneRequest{
+		Description: description, State: state, DueOn: dueOn,
+	}); err != nil {
+		return err
+	}
+	cmd.Printf("   🎯 Updated goal milestone: %s\n", milestone.URL)
+	return nil
+}
+
+// updateGoalProjectItem adds the goal item to the project or refreshes its title and body
+func updateGoalProjectItem(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, title, body string) error {
+	owner := cfg.Goal.ProjectOwner
+	if owner == "" {
+		owner = cfg.GitHub.Owner
+	}
+	project, err := client.GetProject(ctx, owner, cfg.Goal.Project)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range project.DraftIssues {
+		if !isGoalItemTitle(cfg.Goal, item.Title) {
+			continue
+		}
+		if item.Title == title && item.Body == body {
+			cmd.Printf("   🎯 Goal item in %s is up to date: %s\n", project.Title, project.URL)
+			return nil
+		}
+		if err = client.UpdateProjectDraftIssue(ctx, item.ID, title, body); err != nil {
+			return err
+		}
+		cmd.Printf("   🎯 Updated goal item in %s: %s\n", project.Title, project.URL)
+		return nil
+	}
+
+	if _, err = client.AddProjectDraftIssue(ctx, project.ID, title, body); err != nil {
+		return err
+	}
+	cmd.Printf("   🎯 Added goal item to %s: %s\n", project.Title, project.URL)
+	return nil
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+func TestGoalProgress(t *testing.T) {
+	goal := config.GoalConfig{Target: 85, Start: 70}
+	assert.InDelta(t, 0, goalProgress(goal, 65), 0.001)
+	assert.InDelta(t, 56, goalProgress(goal, 78.4), 0.001)
+	assert.InDelta(t, 100, goalProgress(goal, 90), 0.001)
+	assert.InDelta(t, 50, goalProgress(config.GoalConfig{Target: 80}, 40), 0.001)
+
+	assert.Equal(t, "Coverage goal: 56% complete", goalItemTitle(config.GoalConfig{Title: "Coverage goal"}, 56.9))
+	assert.True(t, isGoalItemTitle(config.GoalConfig{Title: "Coverage goal"}, "Coverage goal: 12% complete"))
+	assert.True(t, isGoalItemTitle(config.GoalConfig{Title: "Coverage goal"}, "Coverage goal"))
+	assert.False(t, isGoalItemTitle(config.GoalConfig{Title: "Coverage goal"}, "Coverage goals for Q4"))
+}
+
+func TestRenderGoalProgress(t *testing.T) {
+	cfg := &config.Config{
+		Goal:   config.GoalConfig{Target: 85, Start: 70, Due: "2026-12-31"},
+		GitHub: config.GitHubConfig{CommitSHA: "0123456789abcdef"},
+	}
+	body := renderGoalProgress(cfg, "master", 78.4, 56)
+	assert.Equal(t, "Coverage of `master` is **78.40%**, working toward **85.00%** from 70.00%: **56% complete**.\n\n"+
+		"`"+strings.Repeat("█", 11)+strings.Repeat("░", 9)+"` 56%\n\n"+
+		"6.60 percentage points to go. Due 2026-12-31. Last measured at `0123456`.\n", body)
+
+	cfg.Goal.Due = ""
+	assert.Contains(t, renderGoalProgress(cfg, "master", 86, 100), "🎉 The goal is reached. Last measured")
+}
+
+// milestoneServer fakes the milestones API with one milestone, or none when existing is nil,
+// and records the requests as "METHOD path"
+func milestoneServer(t *testing.T, existing *github.Milestone) (*github.Client, *[]string, *github.MilestoneRequest) {
+	t.Helper()
+	var requests []string
+	var saved github.MilestoneRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			milestones := []github.Milestone{}
+			if existing != nil {
+				milestones = append(milestones, *existing)
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(milestones))
+			return
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&saved))
+		_, _ = w.Write([]byte(`{"number":3,"title":"Coverage goal","html_url":"https://github.com/owner/repo/milestone/3"}`))
+	}))
+	t.Cleanup(server.Close)
+	return github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second}), &requests, &saved
+}
+
+func TestUpdateGoalMilestone(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"},
+		Goal:   config.GoalConfig{Target: 85, Tracker: config.GoalTrackerMilestone, Title: "Coverage goal", Due: "2026-12-31"},
+	}
+
+	t.Run("create", func(t *testing.T) {
+		client, requests, saved := milestoneServer(t, nil)
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+
+		require.NoError(t, updateGoalMilestone(context.Background(), cmd, cfg, client, "progress", false))
+		assert.Equal(t, []string{"GET /repos/owner/repo/milestones", "POST /repos/owner/repo/milestones"}, *requests)
+		assert.Equal(t, github.MilestoneRequest{Title: "Coverage goal", Description: "progress", State: github.MilestoneOpen,
+			DueOn: "2026-12-31T00:00:00Z"}, *saved)
+		assert.Contains(t, out.String(), "Created goal milestone")
+	})
+
+	t.Run("reached", func(t *testing.T) {
+		client, requests, saved := milestoneServer(t, &github.Milestone{Number: 3, Title: "Coverage goal", Description: "old", State: github.MilestoneOpen})
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+
+		require.NoError(t, updateGoalMilestone(context.Background(), cmd, cfg, client, "progress", true))
+		assert.Equal(t, []string{"GET /repos/owner/repo/milestones", "PATCH /repos/owner/repo/milestones/3"}, *requests)
+		assert.Equal(t, github.MilestoneClosed, saved.State)
+		assert.Contains(t, out.String(), "Updated goal milestone")
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		client, requests, _ := milestoneServer(t, &github.Milestone{Number: 3, Title: "Coverage goal", Description: "progress", State: github.MilestoneOpen})
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+
+		require.NoError(t, updateGoalMilestone(context.Background(), cmd, cfg, client, "progress", false))
+		assert.Len(t, *requests, 1)
+		assert.Contains(t, out.String(), "is up to date")
+	})
+}
+
+// projectServer fakes the projects GraphQL API with a project holding the given draft issue
+// items, and records the operations called
+func projectServer(t *testing.T, items string) (*github.Client, *[]string) {
+	t.Helper()
+	var operations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		switch {
+		case strings.Contains(request.Query, "repositoryOwner("):
+			operations = append(operations, "project:"+request.Variables["owner"].(string))
+			_, _ = w.Write([]byte(`{"data":{"repositoryOwner":{"projectV2":{"id":"PVT_1","title":"Roadmap","url":"https://github.com/orgs/acme/projects/4",` +
+				`"items":{"pageInfo":{"hasNextPage":false},"nodes":` + items + `}}}}}`))
+		case strings.Contains(request.Query, "addProjectV2DraftIssue("):
+			operations = append(operations, "add:"+request.Variables["title"].(string))
+			_, _ = w.Write([]byte(`{"data":{"addProjectV2DraftIssue":{"projectItem":{"id":"PVTI_9"}}}}`))
+		case strings.Contains(request.Query, "updateProjectV2DraftIssue("):
+			operations = append(operations, "update:"+request.Variables["title"].(string))
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		default:
+			t.Errorf("unexpected query %s", request.Query)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second}), &operations
+}
+
+func TestUpdateGoalProjectItem(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"},
+		Goal:   config.GoalConfig{Target: 85, Tracker: config.GoalTrackerProject, Title: "Coverage goal", Project: 4, ProjectOwner: "acme"},
+	}
+	other := `{"id":"PVTI_1","content":{"id":"DI_1","title":"Coverage goals for Q4","body":""}}`
+	goalItem := `{"id":"PVTI_2","content":{"id":"DI_2","title":"Coverage goal: 40% complete","body":"old"}}`
+
+	tests := []struct {
+		name       string
+		items      string
+		body       string
+		operations []string
+		output     string
+	}{
+		{"add", "[" + other + "]", "progress", []string{"project:acme", "add:Coverage goal: 56% complete"}, "Added goal item to Roadmap"},
+		{"update", "[" + other + "," + goalItem + "]", "progress", []string{"project:acme", "update:Coverage goal: 56% complete"}, "Updated goal item"},
+		{"up to date", `[{"id":"PVTI_2","content":{"id":"DI_2","title":"Coverage goal: 56% complete","body":"progress"}}]`, "progress",
+			[]string{"project:acme"}, "is up to date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, operations := projectServer(t, tt.items)
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+
+			require.NoError(t, updateGoalProjectItem(context.Background(), cmd, cfg, client, "Coverage goal: 56% complete", tt.body))
+			assert.Equal(t, tt.operations, *operations)
+			assert.Contains(t, out.String(), tt.output)
+		})
+	}
+}
+
+func TestUpdateCoverageGoal(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo"},
+		Goal:   config.GoalConfig{Target: 85, Start: 70, Tracker: config.GoalTrackerProject, Title: "Coverage goal", Project: 4},
+	}
+	client, operations := projectServer(t, "[]")
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	require.NoError(t, updateCoverageGoal(context.Background(), cmd, cfg, client, "master", 78.4))
+	assert.Equal(t, []string{"project:owner", "add:Coverage goal: 56% complete"}, *operations)
+	assert.Contains(t, out.String(), "Coverage goal: 56% complete (78.40% of 85.00%)")
+
+	same, err := newGoalClient(cfg, client)
+	require.NoError(t, err)
+	assert.Same(t, client, same)
+	cfg.Goal.Token = "project-token"
+	other, err := newGoalClient(cfg, client)
+	require.NoError(t, err)
+	assert.NotSame(t, client, other)
+}
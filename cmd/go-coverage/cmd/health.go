@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/diagnostics"
+	"github.com/mrz1836/go-coverage/internal/health"
+)
+
+var (
+	// ErrHealthCheckFailed indicates that at least one health check failed
+	ErrHealthCheckFailed = errors.New("health check failed")
+	// ErrUnsupportedHealthFormat indicates an unknown health output format
+	ErrUnsupportedHealthFormat = errors.New("unsupported health format")
+)
+
+// Output formats supported by the health command
+const (
+	healthFormatText = "text"
+	healthFormatJSON = "json"
+)
+
+// newHealthCmd creates the health command
+func (c *Commands) newHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Check that Pages, the token and history storage are ready",
+		Long: `Check the environment a coverage run depends on before it fails halfway:
+
+  - GitHub Pages: the badge URL answers a HEAD request
+  - GitHub token: the token may create commit statuses and PR comments, when enabled
+  - History storage: the history directory is writable
+
+Failed checks print how to fix them and make the command exit with an error. Network
+checks are skipped in offline mode.`,
+		Example: `  # Before the first coverage run
+  go-coverage health
+
+  # Check a custom domain
+  go-coverage health --badge-url https://coverage.example.com/coverage.svg --format json`,
+		RunE: c.runHealth,
+	}
+
+	cmd.Flags().String("badge-url", "", "Badge URL to request (defaults to the GitHub Pages badge of the repository)")
+	cmd.Flags().String("format", healthFormatText, "Output format (text or json)")
+	return cmd
+}
+
+// runHealth executes the health command
+func (c *Commands) runHealth(cmd *cobra.Command, _ []string) error {
+	badgeURL, _ := cmd.Flags().GetString("badge-url")
+	format, _ := cmd.Flags().GetString("format")
+
+	if format != healthFormatText && format != healthFormatJSON {
+		return fmt.Errorf("%w: %q (expected text or json)", ErrUnsupportedHealthFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if badgeURL == "" {
+		badgeURL = cfg.GetBadgeURL()
+	}
+
+	checkers, err := healthCheckers(cmd, cfg, badgeURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	results := health.Run(ctx, checkers...)
+
+	if format == healthFormatJSON {
+		data, marshalErr := json.MarshalIndent(results, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal health results: %w", marshalErr)
+		}
+		cmd.Println(string(data))
+	} else {
+		cmd.Print(formatHealthResults(results))
+	}
+
+	// The first failure with an error code gets its remediation printed after the results
+	for _, result := range results {
+		if result.Status == health.StatusFail && result.Code != "" {
+			return diagnostics.Wrap(result.Code, ErrHealthCheckFailed)
+		}
+	}
+	if !health.Healthy(results) {
+		return ErrHealthCheckFailed
+	}
+	return nil
+}
+
+// healthCheckers returns the checks for the configuration; network checks are replaced by
+// skipped results in offline mode
+func healthCheckers(cmd *cobra.Command, cfg *config.Config, badgeURL string) ([]health.Checker, error) {
+	storage := &health.StorageChecker{Path: historyStoragePath(cfg), DirMode: cfg.Storage.DirMode}
+
+	if applyOfflineMode(cmd, cfg) {
+		return []health.Checker{
+			skippedCheck{name: (&health.PagesChecker{}).Name()},
+			skippedCheck{name: (&health.TokenChecker{}).Name()},
+			storage,
+		}, nil
+	}
+
+	httpClient, err := cfg.NewHTTPClient(cfg.GitHub.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	client, err := newGitHubClient(cfg, "go-coverage/2.0")
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []string
+	if cfg.GitHub.CreateStatuses {
+		operations = append(operations, health.OperationStatuses)
+	}
+	if cfg.GitHub.PostComments {
+		operations = append(operations, health.OperationComments)
+	}
+
+	return []health.Checker{
+		&health.PagesChecker{URL: badgeURL, Client: httpClient},
+		&health.TokenChecker{
+			API:        client,
+			Token:      cfg.GitHub.Token,
+			Owner:      cfg.GitHub.Owner,
+			Repository: cfg.GitHub.Repository,
+			Operations: operations,
+		},
+		storage,
+	}, nil
+}
+
+// skippedCheck stands in for a network check in offline mode
+type skippedCheck struct {
+	name string
+}
+
+// Name implements health.Checker
+func (s skippedCheck) Name() string {
+	return s.name
+}
+
+// Check implements health.Checker
+func (s skippedCheck) Check(context.Context) health.Result {
+	return health.Result{Status: health.StatusSkip, Message: "skipped: offline mode"}
+}
+
+// formatHealthResults renders health results for the console
+func formatHealthResults(results []health.Result) string {
+	var sb strings.Builder
+	for _, result := range results {
+		icon := "✅"
+		switch result.Status {
+		case health.StatusWarn:
+			icon = "⚠️ "
+		case health.StatusFail:
+			icon = "❌"
+		case health.StatusSkip:
+			icon = "⏭️ "
+		case health.StatusPass:
+		}
+		fmt.Fprintf(&sb, "%s %s: %s\n", icon, result.Name, result.Message)
+		if result.Remediation != "" && result.Status != health.StatusPass {
+			fmt.Fprintf(&sb, "   → %s\n", result.Remediation)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/health"
+)
+
+func TestHealthCommandOffline(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(t.TempDir(), "history"))
+
+	output, err := executeCommand(t, "--offline", "health")
+	require.NoError(t, err)
+	assert.Contains(t, output, "⏭️  GitHub Pages: skipped: offline mode")
+	assert.Contains(t, output, "✅ History storage")
+
+	output, err = executeCommand(t, "--offline", "health", "--format", "json")
+	require.NoError(t, err)
+	var results []health.Result
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results, 3)
+	assert.Equal(t, health.StatusPass, results[2].Status)
+}
+
+func TestHealthCommandFailure(t *testing.T) {
+	isolateOfflineEnv(t)
+	history := filepath.Join(t.TempDir(), "history")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(history, "nested"))
+	require.NoError(t, os.WriteFile(history, nil, 0o600))
+
+	output, err := executeCommand(t, "--offline", "health")
+	require.ErrorIs(t, err, ErrHealthCheckFailed)
+	assert.Contains(t, output, "❌ History storage")
+	assert.Contains(t, output, "→ Make")
+
+	_, err = executeCommand(t, "--offline", "health", "--format", "xml")
+	require.ErrorIs(t, err, ErrUnsupportedHealthFormat)
+}
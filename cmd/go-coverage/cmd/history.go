@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,8 +14,12 @@ import (
 	"github.com/mrz1836/go-coverage/internal/config"
 	"github.com/mrz1836/go-coverage/internal/history"
 	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/runenv"
 )
 
+// flagNameSuite names the test suite history entries are recorded and read under
+const flagNameSuite = "suite"
+
 // newHistoryCmd creates the history command
 func (c *Commands) newHistoryCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -28,18 +35,34 @@ func (c *Commands) newHistoryCmd() *cobra.Command {
 			showTrend, _ := cmd.Flags().GetBool("trend")
 			showStats, _ := cmd.Flags().GetBool("stats")
 			cleanup, _ := cmd.Flags().GetBool("cleanup")
+			pruneBranches, _ := cmd.Flags().GetBool(flagNamePruneBranches)
+			dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
 			days, _ := cmd.Flags().GetInt("days")
 			format, _ := cmd.Flags().GetString("format")
+			envSpec, _ := cmd.Flags().GetString(flagNameEnv)
+			envFilter, err := runenv.ParseFilter(envSpec)
+			if err != nil {
+				return err
+			}
 
 			// Load configuration
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load configuration: %w", err)
 			}
+			applyTestResultsFlag(cmd, cfg)
+			if cmd.Flags().Changed(flagNameSuite) {
+				cfg.History.Suite, _ = cmd.Flags().GetString(flagNameSuite)
+			}
+			suite, err := history.ParseSuite(cfg.History.Suite)
+			if err != nil {
+				return err
+			}
 
 			// Create history tracker
 			historyConfig := &history.Config{
-				StoragePath:    cfg.History.StoragePath,
+				StoragePath:    historyStoragePath(cfg),
+				Repository:     cfg.RepositorySlug(),
 				RetentionDays:  cfg.History.RetentionDays,
 				MaxEntries:     cfg.History.MaxEntries,
 				AutoCleanup:    cfg.History.AutoCleanup,
@@ -52,37 +75,83 @@ func (c *Commands) newHistoryCmd() *cobra.Command {
 			// Handle different operations
 			switch {
 			case inputFile != "":
-				return addToHistory(ctx, tracker, inputFile, branch, commit, commitURL, cfg, cmd)
+				return addToHistory(ctx, tracker, inputFile, branch, commit, commitURL, suite, cfg, cmd)
 			case showTrend:
-				return showTrendData(ctx, tracker, branch, days, format, cmd)
+				return showTrendData(ctx, tracker, branch, days, envFilter, suite, format, cmd)
 			case showStats:
 				return showStatistics(ctx, tracker, format, cmd)
 			case cleanup:
 				return cleanupHistory(ctx, tracker, cmd)
+			case pruneBranches:
+				return pruneDeletedBranches(ctx, cmd, cfg, tracker, dryRun)
 			default:
-				return showLatestEntry(ctx, tracker, branch, format, cmd)
+				return showLatestEntry(ctx, tracker, branch, envFilter, suite, format, cmd)
 			}
 		},
 	}
 
 	// Add flags
-	cmd.Flags().StringP("add", "a", "", "Add coverage file to history")
+	cmd.Flags().StringP("add", "a", "", "Add coverage file, or GOCOVERDIR directory of binary coverage data, to history")
+	cmd.Flags().String(flagNameSuite, "", "Test suite the coverage comes from, such as bench, tracked apart from the unit tests (GO_COVERAGE_HISTORY_SUITE)")
 	cmd.Flags().StringP("branch", "b", "", "Branch name (for add operation)")
 	cmd.Flags().StringP("commit", "c", "", "Commit SHA (for add operation)")
 	cmd.Flags().String("commit-url", "", "Commit URL (for add operation)")
+	addTestResultsFlag(cmd)
 	cmd.Flags().Bool("trend", false, "Show coverage trend")
 	cmd.Flags().Bool("stats", false, "Show coverage statistics")
 	cmd.Flags().Bool("cleanup", false, "Clean up old history entries")
+	cmd.Flags().Bool(flagNamePruneBranches, false, "Archive or delete the history of branches deleted on GitHub (GO_COVERAGE_HISTORY_PRUNE_MODE)")
+	addDryRunFlag(cmd, "List the branches --prune-branches would prune without changing the history")
 	cmd.Flags().IntP("days", "d", 30, "Number of days to analyze")
 	cmd.Flags().String("format", "text", "Output format (text or json)")
+	addEnvFilterFlag(cmd)
 
+	cmd.AddCommand(c.newHistoryAnnotateCmd())
 	return cmd
 }
 
-func addToHistory(ctx context.Context, tracker *history.Tracker, inputFile, branch, commit, commitURL string, cfg *config.Config, cmd *cobra.Command) error {
+// historyStoragePath returns the history storage path resolved against the repository root, so
+// every command reads the same history whatever its working directory; the configured path is
+// used as is when the repository root cannot be found
+func historyStoragePath(cfg *config.Config) string {
+	if resolvedPath, err := cfg.ResolveHistoryStoragePath(); err == nil {
+		return resolvedPath
+	}
+	return cfg.History.StoragePath
+}
+
+// commitDepth returns the number of commits reachable from the commit in the local clone, its
+// position in the commit graph, or 0 when git does not know it or the clone is shallow
+func commitDepth(ctx context.Context, commit string) int {
+	if commit == "" || strings.HasPrefix(commit, "-") {
+		return 0
+	}
+	shallow, err := exec.CommandContext(ctx, "git", "rev-parse", "--is-shallow-repository").Output()
+	if err != nil || strings.TrimSpace(string(shallow)) != "false" {
+		return 0
+	}
+	output, err := exec.CommandContext(ctx, "git", "rev-list", "--count", commit).Output() //nolint:gosec // commit cannot be an option
+	if err != nil {
+		return 0
+	}
+	depth, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0
+	}
+	return depth
+}
+
+func addToHistory(ctx context.Context, tracker *history.Tracker, inputFile, branch, commit, commitURL, suite string, cfg *config.Config, cmd *cobra.Command) error {
+	// Coverage captured with GOCOVERDIR, as during benchmark runs, is converted to a profile first
+	profile, cleanup, err := coverProfile(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	// Parse coverage data
-	p := parser.New()
-	coverage, err := p.ParseFile(ctx, inputFile)
+	p := parser.New().WithModuleRewrites(cfg.ModuleRewrites())
+	coverage, err := p.ParseFile(ctx, profile)
 	if err != nil {
 		return fmt.Errorf("failed to parse coverage file: %w", err)
 	}
@@ -104,11 +173,23 @@ func addToHistory(ctx context.Context, tracker *history.Tracker, inputFile, bran
 		options = append(options, history.WithBranch(branch))
 	}
 	if commit != "" {
-		options = append(options, history.WithCommit(commit, commitURL))
+		options = append(options, history.WithCommit(commit, commitURL), history.WithCommitDepth(commitDepth(ctx, commit)))
 	}
 	if cfg.GitHub.Owner != "" {
 		options = append(options, history.WithMetadata("project", cfg.GitHub.Owner+"/"+cfg.GitHub.Repository))
 	}
+	if cfg.Analytics.TestResults != "" {
+		tests, testsErr := parseTestResults(cfg)
+		if testsErr != nil {
+			return testsErr
+		}
+		options = append(options, history.WithTestRun(tests))
+	}
+	if cfg.Analytics.CoverageDebt {
+		options = append(options, history.WithDebt(newHistoryDebt(measureDebt(cfg, coverage))))
+	}
+	environment := runenv.FromEnv(ctx, coverage.Mode)
+	options = append(options, history.WithEnvironment(environment), history.WithSuite(suite))
 
 	err = tracker.Record(ctx, coverage, options...)
 	if err != nil {
@@ -118,13 +199,17 @@ func addToHistory(ctx context.Context, tracker *history.Tracker, inputFile, bran
 	cmd.Printf("Coverage recorded successfully!\n")
 	cmd.Printf("Branch: %s\n", branch)
 	cmd.Printf("Commit: %s\n", commit)
+	if suite != "" {
+		cmd.Printf("Suite: %s\n", suite)
+	}
 	cmd.Printf("Coverage: %.2f%% (%d/%d lines)\n",
 		coverage.Percentage, coverage.CoveredLines, coverage.TotalLines)
+	printEnvironment(cmd, environment)
 
 	return nil
 }
 
-func showTrendData(ctx context.Context, tracker *history.Tracker, branch string, days int, format string, cmd *cobra.Command) error {
+func showTrendData(ctx context.Context, tracker *history.Tracker, branch string, days int, envFilter map[string]string, suite, format string, cmd *cobra.Command) error {
 	if branch == "" {
 		branch = history.DefaultBranch
 	}
@@ -132,9 +217,11 @@ func showTrendData(ctx context.Context, tracker *history.Tracker, branch string,
 		days = 30
 	}
 
-	options := make([]history.TrendOption, 0, 2)
+	options := make([]history.TrendOption, 0, 4)
 	options = append(options, history.WithTrendBranch(branch))
 	options = append(options, history.WithTrendDays(days))
+	options = append(options, history.WithTrendEnvironment(envFilter))
+	options = append(options, history.WithTrendSuite(suite))
 
 	trendData, err := tracker.GetTrend(ctx, options...)
 	if err != nil {
@@ -153,6 +240,12 @@ func showTrendData(ctx context.Context, tracker *history.Tracker, branch string,
 		cmd.Printf("======================\n")
 		cmd.Printf("Branch: %s\n", branch)
 		cmd.Printf("Period: %d days\n", days)
+		if suite != "" {
+			cmd.Printf("Suite: %s\n", suite)
+		}
+		if len(envFilter) > 0 {
+			cmd.Printf("Environment: %s\n", runenv.FormatFilter(envFilter))
+		}
 		cmd.Printf("Total Entries: %d\n", trendData.Summary.TotalEntries)
 
 		if trendData.Summary.TotalEntries > 0 {
@@ -160,6 +253,9 @@ func showTrendData(ctx context.Context, tracker *history.Tracker, branch string,
 			cmd.Printf("Min Coverage: %.2f%%\n", trendData.Summary.MinPercentage)
 			cmd.Printf("Max Coverage: %.2f%%\n", trendData.Summary.MaxPercentage)
 			cmd.Printf("Current Trend: %s\n", trendData.Summary.CurrentTrend)
+			if trendData.Summary.SkewedEntries > 0 {
+				cmd.Printf("Clock Skew: %d entries had out-of-order timestamps, normalized to the order of the runs\n", trendData.Summary.SkewedEntries)
+			}
 
 			if trendData.Analysis.Volatility > 0 {
 				cmd.Printf("Volatility: %.2f\n", trendData.Analysis.Volatility)
@@ -224,6 +320,13 @@ func showStatistics(ctx context.Context, tracker *history.Tracker, format string
 				cmd.Printf("  %s: %d entries\n", branch, count)
 			}
 		}
+
+		if len(stats.UniqueSuites) > 0 {
+			cmd.Printf("\nSuites (besides %s tests):\n", history.DefaultSuite)
+			for suite, count := range stats.UniqueSuites {
+				cmd.Printf("  %s: %d entries\n", suite, count)
+			}
+		}
 	}
 
 	return nil
@@ -239,12 +342,12 @@ func cleanupHistory(ctx context.Context, tracker *history.Tracker, cmd *cobra.Co
 	return nil
 }
 
-func showLatestEntry(ctx context.Context, tracker *history.Tracker, branch, format string, cmd *cobra.Command) error {
+func showLatestEntry(ctx context.Context, tracker *history.Tracker, branch string, envFilter map[string]string, suite, format string, cmd *cobra.Command) error {
 	if branch == "" {
 		branch = history.DefaultBranch
 	}
 
-	entry, err := tracker.GetLatestEntry(ctx, branch)
+	entry, err := tracker.GetLatestEntry(ctx, branch, history.WithTrendEnvironment(envFilter), history.WithTrendSuite(suite))
 	if err != nil {
 		return fmt.Errorf("failed to get latest entry: %w", err)
 	}
@@ -261,9 +364,18 @@ func showLatestEntry(ctx context.Context, tracker *history.Tracker, branch, form
 		cmd.Printf("====================\n")
 		cmd.Printf("Branch: %s\n", entry.Branch)
 		cmd.Printf("Commit: %s\n", entry.CommitSHA)
+		cmd.Printf("Suite: %s\n", entry.SuiteName())
 		cmd.Printf("Timestamp: %s\n", entry.Timestamp.Format(time.RFC3339))
 		cmd.Printf("Coverage: %.2f%% (%d/%d lines)\n",
 			entry.Coverage.Percentage, entry.Coverage.CoveredLines, entry.Coverage.TotalLines)
+		printEnvironment(cmd, entry.Metadata)
+
+		if len(entry.Annotations) > 0 {
+			cmd.Printf("\nAnnotations:\n")
+			for _, note := range annotationNotes(entry.Annotations) {
+				cmd.Printf("  %s\n", note)
+			}
+		}
 
 		if len(entry.Metadata) > 0 {
 			cmd.Printf("\nMetadata:\n")
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+)
+
+// newHistoryAnnotateCmd creates the history annotate command
+func (c *Commands) newHistoryAnnotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Add a note to the history entry of a commit",
+		Long: `Add a note to the history entries recorded for a commit, to explain why coverage
+jumped or dropped there. The dashboard marks annotated runs on the coverage history chart and
+shows the note when hovering the marker. Notes are kept with the entry, so publish the history
+again for the dashboard to pick them up.`,
+		Example: `  # Explain a jump in coverage
+  go-coverage history annotate --commit 3f2a9c1 --note "migrated to testify"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			commit, _ := cmd.Flags().GetString("commit")
+			note, _ := cmd.Flags().GetString("note")
+			author, _ := cmd.Flags().GetString("author")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			tracker := history.NewWithConfig(&history.Config{
+				StoragePath:    historyStoragePath(cfg),
+				Repository:     cfg.RepositorySlug(),
+				RetentionDays:  cfg.History.RetentionDays,
+				MaxEntries:     cfg.History.MaxEntries,
+				AutoCleanup:    false,
+				MetricsEnabled: false,
+			})
+
+			count, err := tracker.Annotate(context.Background(), commit, history.Annotation{Note: note, Author: author})
+			if err != nil {
+				return fmt.Errorf("failed to annotate commit: %w", err)
+			}
+			entries := "entries"
+			if count == 1 {
+				entries = "entry"
+			}
+			cmd.Printf("📝 Annotated %d history %s of %s: %s\n", count, entries, shortSHA(commit), note)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("commit", "", "Commit SHA of the history entry, at least 7 characters (required)")
+	cmd.Flags().String("note", "", "Note explaining the coverage of the commit (required)")
+	cmd.Flags().String("author", "", "Author of the note")
+	_ = cmd.MarkFlagRequired("commit")
+	_ = cmd.MarkFlagRequired("note")
+
+	return cmd
+}
+
+// annotationNotes formats the annotations of a history entry for the dashboard, naming the author
+func annotationNotes(annotations []history.Annotation) []string {
+	notes := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		if annotation.Author != "" {
+			notes = append(notes, fmt.Sprintf("%s (%s)", annotation.Note, annotation.Author))
+		} else {
+			notes = append(notes, annotation.Note)
+		}
+	}
+	return notes
+}
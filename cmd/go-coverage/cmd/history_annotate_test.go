@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestHistoryAnnotateCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "")
+	historyDir := filepath.Join(t.TempDir(), "history")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", historyDir)
+
+	const commit = "3f2a9c1d4e5b6a7f"
+	tracker := history.NewWithConfig(&history.Config{StoragePath: historyDir})
+	coverage := &parser.CoverageData{Percentage: 82.5, TotalLines: 200, CoveredLines: 165, Packages: map[string]*parser.PackageCoverage{}}
+	require.NoError(t, tracker.Record(context.Background(), coverage, history.WithBranch("main"), history.WithCommit(commit, "")))
+
+	output, err := executeCommand(t, cmdHistory, "annotate", "--commit", commit[:7], "--note", "migrated to testify", "--author", "alice")
+	require.NoError(t, err)
+	assert.Contains(t, output, "📝 Annotated 1 history entry of 3f2a9c1: migrated to testify")
+
+	entry, err := tracker.FindEntry(context.Background(), commit)
+	require.NoError(t, err)
+	require.Len(t, entry.Annotations, 1)
+	assert.Equal(t, "migrated to testify", entry.Annotations[0].Note)
+	assert.Equal(t, "alice", entry.Annotations[0].Author)
+
+	output, err = executeCommand(t, cmdHistory, "--branch", "main")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Annotations:\n  migrated to testify (alice)")
+
+	_, err = executeCommand(t, cmdHistory, "annotate", "--commit", "0000000", "--note", "unknown")
+	require.ErrorIs(t, err, history.ErrNoEntriesFound)
+
+	_, err = executeCommand(t, cmdHistory, "annotate", "--commit", commit)
+	require.ErrorContains(t, err, `required flag(s) "note" not set`)
+}
+
+func TestAnnotationNotes(t *testing.T) {
+	assert.Empty(t, annotationNotes(nil))
+	assert.Equal(t, []string{"migrated to testify (alice)", "flaky tests skipped"}, annotationNotes([]history.Annotation{
+		{Note: "migrated to testify", Author: "alice"},
+		{Note: "flaky tests skipped"},
+	}))
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+)
+
+// flagNamePruneBranches prunes the history of branches deleted from the repository
+const flagNamePruneBranches = "prune-branches"
+
+// pruneDeletedBranches archives or deletes, as configured, the entries of the branches that have
+// history but no longer exist on GitHub. Main branches are always kept, and so is a deleted
+// branch until its newest entry is older than the grace period.
+func pruneDeletedBranches(ctx context.Context, cmd *cobra.Command, cfg *config.Config, tracker *history.Tracker, dryRun bool) error {
+	if cfg.GitHub.Owner == "" {
+		return ErrGitHubOwnerRequired
+	}
+	if cfg.GitHub.Repository == "" {
+		return ErrGitHubRepoRequired
+	}
+	if cfg.GitHub.Token == "" {
+		return ErrGitHubTokenRequired
+	}
+	if err := requireNetwork(cmd, cfg, "pruning the history of deleted branches"); err != nil {
+		return err
+	}
+
+	recorded, err := tracker.Branches(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := newGitHubClient(cfg, "go-coverage/2.0")
+	if err != nil {
+		return err
+	}
+	remote, err := client.ListBranches(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository)
+	if err != nil {
+		return err
+	}
+
+	protected := append(getMainBranches(), history.DefaultBranch)
+	grace := time.Duration(cfg.History.PruneGraceDays) * 24 * time.Hour
+	stale := staleBranches(recorded, remote, protected, grace, time.Now())
+	if len(stale) == 0 {
+		cmd.Printf("No history of deleted branches to prune (%d branches with history, %d on GitHub)\n", len(recorded), len(remote))
+		return nil
+	}
+
+	mode, archiveDir := config.PruneModeArchive, cfg.History.ArchivePath
+	if cfg.History.PruneMode == config.PruneModeDelete {
+		mode, archiveDir = config.PruneModeDelete, ""
+	}
+	cmd.Printf("Branches with history deleted from %s/%s:\n", cfg.GitHub.Owner, cfg.GitHub.Repository)
+	for _, branch := range stale {
+		cmd.Printf("  %s (last run %s)\n", branch, recorded[branch].Format("2006-01-02"))
+	}
+	if dryRun {
+		cmd.Printf("🧪 DRY RUN: Would %s the history of %d deleted branches\n", mode, len(stale))
+		return nil
+	}
+
+	pruned, err := tracker.PruneBranches(ctx, stale, archiveDir)
+	if err != nil {
+		return err
+	}
+	if archiveDir != "" {
+		cmd.Printf("🗄️  Archived %d history entries of %d deleted branches to %s\n", pruned, len(stale), archiveDir)
+	} else {
+		cmd.Printf("🗑️  Deleted %d history entries of %d deleted branches\n", pruned, len(stale))
+	}
+	return nil
+}
+
+// staleBranches returns the branches with history that are neither on the remote nor protected,
+// and whose newest entry is older than the grace period, sorted
+func staleBranches(recorded map[string]time.Time, remote []github.Branch, protected []string, grace time.Duration, now time.Time) []string {
+	existing := make(map[string]bool, len(remote))
+	for _, branch := range remote {
+		existing[branch.Name] = true
+	}
+
+	var stale []string
+	for branch, newest := range recorded {
+		if existing[branch] || slices.Contains(protected, branch) || now.Sub(newest) < grace {
+			continue
+		}
+		stale = append(stale, branch)
+	}
+	slices.Sort(stale)
+	return stale
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+func TestStaleBranches(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	recorded := map[string]time.Time{
+		"main":          now.AddDate(0, -2, 0),
+		"feature/login": now.AddDate(0, 0, -30),
+		"fix-typo":      now.AddDate(0, 0, -10),
+		"just-merged":   now.AddDate(0, 0, -2),
+		"release/1.2":   now.AddDate(0, 0, -40),
+	}
+	remote := []github.Branch{{Name: "release/1.2"}, {Name: "develop"}}
+
+	assert.Equal(t, []string{"feature/login", "fix-typo"},
+		staleBranches(recorded, remote, []string{"main", "master"}, 7*24*time.Hour, now))
+	assert.Equal(t, []string{"feature/login", "fix-typo", "just-merged"},
+		staleBranches(recorded, remote, []string{"main", "master"}, 0, now))
+	assert.Empty(t, staleBranches(map[string]time.Time{"main": now}, nil, []string{"main"}, 0, now))
+}
+
+func TestHistoryPruneBranchesRequirements(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "")
+	t.Setenv("GO_COVERAGE_CI_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(t.TempDir(), "history"))
+
+	_, err := executeCommand(t, cmdHistory, "--prune-branches")
+	require.ErrorIs(t, err, ErrGitHubOwnerRequired)
+
+	t.Setenv("GO_COVERAGE_CI_REPOSITORY", "owner/repo")
+	_, err = executeCommand(t, cmdHistory, "--prune-branches")
+	require.ErrorIs(t, err, ErrGitHubTokenRequired)
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	_, err = executeCommand(t, cmdHistory, "--prune-branches", "--offline")
+	require.ErrorIs(t, err, ErrOfflineMode)
+}
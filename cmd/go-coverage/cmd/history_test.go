@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -115,7 +117,7 @@ github.com/test/repo/main.go:15.2,17.16 1 0
 
 	// Test addToHistory function
 	ctx := context.Background()
-	err := addToHistory(ctx, tracker, coverageFile, "main", "abc123", "https://github.com/test/repo/commit/abc123", cfg, cmd)
+	err := addToHistory(ctx, tracker, coverageFile, "main", "abc123", "https://github.com/test/repo/commit/abc123", "", cfg, cmd)
 	require.NoError(t, err)
 
 	// Check output
@@ -126,6 +128,20 @@ github.com/test/repo/main.go:15.2,17.16 1 0
 	assert.Contains(t, output, "Coverage:")
 }
 
+func TestCommitDepth(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Zero(t, commitDepth(ctx, ""))
+	assert.Zero(t, commitDepth(ctx, "--all"))
+	assert.Zero(t, commitDepth(ctx, "0000000000000000000000000000000000000000"))
+
+	shallow, err := exec.CommandContext(ctx, "git", "rev-parse", "--is-shallow-repository").Output()
+	if err != nil || strings.TrimSpace(string(shallow)) != "false" {
+		t.Skip("needs a full git clone")
+	}
+	assert.Positive(t, commitDepth(ctx, "HEAD"))
+}
+
 func TestAddToHistoryWithDefaults(t *testing.T) {
 	tempDir := t.TempDir()
 	coverageFile := filepath.Join(tempDir, "coverage.txt")
@@ -164,7 +180,7 @@ github.com/test/repo/main.go:10.2,12.16 1 1
 	cmd.SetOut(&buf)
 
 	ctx := context.Background()
-	err := addToHistory(ctx, tracker, coverageFile, "", "", "", cfg, cmd)
+	err := addToHistory(ctx, tracker, coverageFile, "", "", "", "", cfg, cmd)
 	require.NoError(t, err)
 
 	// Should use defaults
@@ -193,7 +209,7 @@ func TestAddToHistoryInvalidFile(t *testing.T) {
 	cmd.SetOut(&buf)
 
 	ctx := context.Background()
-	err := addToHistory(ctx, tracker, "/nonexistent/coverage.txt", "main", "abc123", "", cfg, cmd)
+	err := addToHistory(ctx, tracker, "/nonexistent/coverage.txt", "main", "abc123", "", "", cfg, cmd)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse coverage file")
 }
@@ -234,7 +250,7 @@ func TestShowTrendData(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetOut(&buf)
 
-	err = showTrendData(ctx, tracker, "main", 30, "text", cmd)
+	err = showTrendData(ctx, tracker, "main", 30, nil, "", "text", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
@@ -270,7 +286,7 @@ func TestShowTrendDataJSON(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetOut(&buf)
 
-	err = showTrendData(ctx, tracker, "main", 30, "json", cmd)
+	err = showTrendData(ctx, tracker, "main", 30, nil, "", "json", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
@@ -298,7 +314,7 @@ func TestShowTrendDataDefaults(t *testing.T) {
 
 	ctx := context.Background()
 	// Test with empty branch (should use default) and 0 days (should use 30)
-	err := showTrendData(ctx, tracker, "", 0, "text", cmd)
+	err := showTrendData(ctx, tracker, "", 0, nil, "", "text", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
@@ -420,7 +436,7 @@ func TestShowLatestEntry(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetOut(&buf)
 
-	err = showLatestEntry(ctx, tracker, "main", "text", cmd)
+	err = showLatestEntry(ctx, tracker, "main", nil, "", "text", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
@@ -453,7 +469,7 @@ func TestShowLatestEntryJSON(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetOut(&buf)
 
-	err = showLatestEntry(ctx, tracker, "main", "json", cmd)
+	err = showLatestEntry(ctx, tracker, "main", nil, "", "json", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
@@ -483,7 +499,7 @@ func TestShowLatestEntryDefaults(t *testing.T) {
 	cmd.SetOut(&buf)
 
 	// Test with empty branch (should use default)
-	err = showLatestEntry(ctx, tracker, "", "text", cmd)
+	err = showLatestEntry(ctx, tracker, "", nil, "", "text", cmd)
 	require.NoError(t, err)
 
 	output := buf.String()
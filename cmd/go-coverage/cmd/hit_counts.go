@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// ErrNoHitCounts indicates a set mode profile while the hit count features are enabled
+var ErrNoHitCounts = errors.New("the coverage profile was written with -covermode=set, which records no hit counts")
+
+// maxHotspots bounds the hotspots kept for the dashboard
+const maxHotspots = 10
+
+// newHitCountDashboardData analyzes how often the covered statements were executed, nil when
+// the analysis is disabled or the profile only records whether statements ran
+func newHitCountDashboardData(cfg *config.Config, branch string, coverage *parser.CoverageData) *dashboard.HitCounts {
+	if cfg.Coverage.HotspotHits == 0 {
+		return nil
+	}
+	dist := coverage.HitDistribution(cfg.Coverage.HotspotHits, maxHotspots)
+	if dist == nil || dist.Covered == 0 {
+		return nil
+	}
+
+	data := &dashboard.HitCounts{
+		Covered:           dist.Covered,
+		Once:              dist.Once,
+		HotspotStatements: dist.HotspotStatements,
+		HotspotHits:       dist.HotspotHits,
+	}
+	for _, bucket := range dist.Buckets {
+		data.Buckets = append(data.Buckets, dashboard.HitBucket{Min: bucket.Min, Max: bucket.Max, Statements: bucket.Statements})
+	}
+	for _, spot := range dist.Hotspots {
+		hotspot := dashboard.Hotspot{
+			Path:       spot.Path,
+			StartLine:  spot.StartLine,
+			EndLine:    spot.EndLine,
+			Statements: spot.Statements,
+			Hits:       spot.Hits,
+		}
+		if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" && branch != "" {
+			hotspot.GitHubURL = fmt.Sprintf("%s#L%d-L%d", urlutil.BuildGitHubModuleFileURL(cfg.GitHub.Owner,
+				cfg.GitHub.Repository, branch, cfg.Coverage.ModuleRoot, spot.Path), spot.StartLine, spot.EndLine)
+		}
+		data.Hotspots = append(data.Hotspots, hotspot)
+	}
+	return data
+}
+
+// checkCoverMode applies GO_COVERAGE_COVERMODE_CHECK to a profile without hit counts while the
+// hit count features are enabled: it is reported, rejected or accepted silently
+func checkCoverMode(cmd *cobra.Command, cfg *config.Config, coverage *parser.CoverageData) error {
+	if coverage.HasHitCounts() || cfg.Coverage.HotspotHits == 0 {
+		return nil
+	}
+	switch cfg.Coverage.CoverModeCheck {
+	case config.CoverModeCheckOff:
+	case config.CoverModeCheckFail:
+		return fmt.Errorf("%w: run go test with -covermode=count or -covermode=atomic, or set GO_COVERAGE_HOTSPOT_HITS=0", ErrNoHitCounts)
+	default:
+		cmd.Printf("   ⚠️  Cover mode %q records no hit counts, so the hit count analysis is skipped; use -covermode=count or -covermode=atomic\n", coverage.Mode)
+	}
+	return nil
+}
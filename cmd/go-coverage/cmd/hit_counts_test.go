@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestNewHitCountDashboardData(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.GitHub.Owner, cfg.GitHub.Repository = "owner", "repo"
+	cfg.Coverage.HotspotHits = 100
+
+	coverage := &parser.CoverageData{
+		Mode: "atomic",
+		Packages: map[string]*parser.PackageCoverage{
+			"api": {Files: map[string]*parser.FileCoverage{
+				"api/server.go": {Path: "api/server.go", Statements: []parser.Statement{
+					{StartLine: 3, EndLine: 5, NumStmt: 2, Count: 1},
+					{StartLine: 8, EndLine: 9, NumStmt: 1, Count: 150},
+				}},
+			}},
+		},
+	}
+
+	data := newHitCountDashboardData(cfg, "main", coverage)
+	require.NotNil(t, data)
+	assert.Equal(t, 3, data.Covered)
+	assert.Equal(t, 2, data.Once)
+	assert.Len(t, data.Buckets, 6)
+	require.Len(t, data.Hotspots, 1)
+	assert.Equal(t, "https://github.com/owner/repo/blob/main/api/server.go#L8-L9", data.Hotspots[0].GitHubURL)
+
+	cfg.Coverage.HotspotHits = 0
+	assert.Nil(t, newHitCountDashboardData(cfg, "main", coverage), "the analysis is disabled")
+
+	cfg.Coverage.HotspotHits = 100
+	coverage.Mode = "set"
+	assert.Nil(t, newHitCountDashboardData(cfg, "main", coverage))
+}
+
+func TestCheckCoverMode(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Coverage.HotspotHits = parser.DefaultHotspotHits
+	set := &parser.CoverageData{Mode: "set"}
+
+	for _, tc := range []struct {
+		check   string
+		wantErr bool
+		warns   bool
+	}{
+		{config.CoverModeCheckWarn, false, true},
+		{config.CoverModeCheckFail, true, false},
+		{config.CoverModeCheckOff, false, false},
+	} {
+		t.Run(tc.check, func(t *testing.T) {
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&output)
+			cfg.Coverage.CoverModeCheck = tc.check
+			err := checkCoverMode(cmd, cfg, set)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrNoHitCounts)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.warns, strings.Contains(output.String(), "records no hit counts"))
+		})
+	}
+
+	cfg.Coverage.CoverModeCheck = config.CoverModeCheckFail
+	require.NoError(t, checkCoverMode(&cobra.Command{}, cfg, &parser.CoverageData{Mode: "count"}))
+	cfg.Coverage.HotspotHits = 0
+	require.NoError(t, checkCoverMode(&cobra.Command{}, cfg, set), "no hit count feature is enabled")
+}
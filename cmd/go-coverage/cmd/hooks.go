@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// Hook command errors
+var (
+	ErrHookExists       = errors.New("a pre-push hook not installed by go-coverage already exists")
+	ErrHookNotInstalled = errors.New("no go-coverage pre-push hook is installed")
+	ErrNoPushBase       = errors.New("cannot determine which commits are being pushed")
+)
+
+const (
+	// hookMarker identifies hooks written by go-coverage so they are never overwritten by accident
+	hookMarker = "# go-coverage pre-push hook"
+	// envSkipHooks bypasses the pre-push coverage check when set to a non-empty value
+	envSkipHooks = "GO_COVERAGE_SKIP_HOOKS"
+	// prePushHook is the git hook the coverage check is installed as
+	prePushHook = "pre-push"
+)
+
+// newHooksCmd creates the hooks command
+func (c *Commands) newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage git hooks that check coverage before pushing",
+		Long: `Manage a git pre-push hook that runs a fast coverage check before commits leave
+the machine. Only the packages changed since the upstream branch are tested, and
+their combined coverage is held to the configured threshold.
+
+Set ` + envSkipHooks + `=1 to bypass the check for a single push.`,
+	}
+
+	cmd.AddCommand(c.newHooksInstallCmd(), c.newHooksUninstallCmd(), c.newHooksRunCmd())
+	return cmd
+}
+
+// newHooksInstallCmd creates the hooks install command
+func (c *Commands) newHooksInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the pre-push coverage hook",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			command, _ := cmd.Flags().GetString("command")
+
+			hookPath, err := gitHookPath(cmd.Context(), prePushHook)
+			if err != nil {
+				return err
+			}
+
+			if existing, readErr := os.ReadFile(hookPath); readErr == nil && //nolint:gosec // hook path comes from git
+				!strings.Contains(string(existing), hookMarker) && !force {
+				return fmt.Errorf("%w: %s (use --force to replace it)", ErrHookExists, hookPath)
+			}
+
+			if err = os.MkdirAll(filepath.Dir(hookPath), 0o750); err != nil {
+				return fmt.Errorf("failed to create hooks directory: %w", err)
+			}
+			if err = os.WriteFile(hookPath, []byte(prePushScript(command)), 0o755); err != nil { //nolint:gosec // git hooks must be executable
+				return fmt.Errorf("failed to write pre-push hook: %w", err)
+			}
+
+			cmd.Printf("✅ Installed pre-push hook: %s\n", hookPath)
+			cmd.Printf("   Bypass a single push with %s=1 git push\n", envSkipHooks)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Replace an existing pre-push hook")
+	cmd.Flags().String("command", "go-coverage", "Command the hook runs to invoke go-coverage")
+	return cmd
+}
+
+// newHooksUninstallCmd creates the hooks uninstall command
+func (c *Commands) newHooksUninstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the pre-push coverage hook",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			hookPath, err := gitHookPath(cmd.Context(), prePushHook)
+			if err != nil {
+				return err
+			}
+
+			existing, err := os.ReadFile(hookPath) //nolint:gosec // hook path comes from git
+			if err != nil || !strings.Contains(string(existing), hookMarker) {
+				return fmt.Errorf("%w: %s", ErrHookNotInstalled, hookPath)
+			}
+			if err = os.Remove(hookPath); err != nil {
+				return fmt.Errorf("failed to remove pre-push hook: %w", err)
+			}
+
+			cmd.Printf("🗑️  Removed pre-push hook: %s\n", hookPath)
+			return nil
+		},
+	}
+}
+
+// newHooksRunCmd creates the hooks run command invoked by the installed hook
+func (c *Commands) newHooksRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the pre-push coverage check for the changed packages",
+		Args:  cobra.ArbitraryArgs, // git passes the remote name and URL
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if os.Getenv(envSkipHooks) != "" {
+				cmd.Printf("⏭️  Coverage check skipped (%s is set)\n", envSkipHooks)
+				return nil
+			}
+			base, _ := cmd.Flags().GetString("base")
+			affected, _ := cmd.Flags().GetBool("affected")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+
+			if base == "" {
+				if base, err = pushBase(ctx); err != nil {
+					return err
+				}
+			}
+			changed, err := gitChangedFiles(ctx, base)
+			if err != nil {
+				return err
+			}
+			packages := changedPackages(changed)
+			if len(packages) == 0 {
+				cmd.Printf("✅ No Go packages changed since %s, skipping coverage check\n", shortSHA(base))
+				return nil
+			}
+
+			cmd.Printf("🧪 Checking coverage of %d changed package(s) since %s...\n", len(packages), shortSHA(base))
+			var coverPkg []string
+			if affected {
+				// Run the tests of every dependent package too, while still measuring only the changed ones
+				result, affectedErr := affectedPackages(ctx, base)
+				if affectedErr != nil {
+					return affectedErr
+				}
+				if len(result.Affected) > 0 {
+					cmd.Printf("🔗 Running tests of %d affected package(s)\n", len(result.Affected))
+					coverPkg = []string{"-coverpkg=" + strings.Join(packages, ",")}
+					packages = result.Affected
+				}
+			}
+			profile, err := os.CreateTemp("", "go-coverage-pre-push-*.txt")
+			if err != nil {
+				return fmt.Errorf("failed to create coverage profile: %w", err)
+			}
+			_ = profile.Close()
+			defer func() { _ = os.Remove(profile.Name()) }()
+
+			args := append([]string{"test", "-covermode=set", "-coverprofile=" + profile.Name()}, coverPkg...)
+			args = append(args, packages...)
+			testCmd := exec.CommandContext(ctx, "go", args...) //nolint:gosec // package paths come from git and are passed as separate arguments
+			testCmd.Stdout = cmd.OutOrStdout()
+			testCmd.Stderr = cmd.ErrOrStderr()
+			if err = testCmd.Run(); err != nil {
+				return fmt.Errorf("go test failed: %w", err)
+			}
+
+			p := parser.NewWithConfig(&parser.Config{
+				ExcludePaths:     cfg.Coverage.ExcludePaths,
+				ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+				ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+				ExcludePresets:   cfg.Coverage.ExcludePresets,
+				Limits:           cfg.ParserLimits(),
+				ModuleRewrites:   cfg.ModuleRewrites(),
+				ModuleRoot:       cfg.Coverage.ModuleRoot,
+
+				MergeRepeatedBlocks: len(coverPkg) > 0,
+			})
+			coverage, err := p.ParseFile(ctx, profile.Name())
+			if err != nil {
+				return fmt.Errorf("failed to parse coverage profile: %w", err)
+			}
+
+			cmd.Printf("📊 Changed packages coverage: %.2f%% (threshold %.2f%%)\n", coverage.Percentage, cfg.Coverage.Threshold)
+			if coverage.Percentage < cfg.Coverage.Threshold {
+				cmd.Printf("❌ Push blocked; set %s=1 to bypass\n", envSkipHooks)
+				return fmt.Errorf("%w: %.2f%% < %.2f%%", ErrCoverageBelowThreshold, coverage.Percentage, cfg.Coverage.Threshold)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("base", "", "Commit to diff against (defaults to the upstream branch)")
+	cmd.Flags().Bool("affected", false, "Also run the tests of packages that import the changed packages")
+	return cmd
+}
+
+// prePushScript returns the hook script that runs the coverage check through command
+func prePushScript(command string) string {
+	return `#!/bin/sh
+` + hookMarker + `
+# Installed by "go-coverage hooks install"; remove with "go-coverage hooks uninstall".
+# Set ` + envSkipHooks + `=1 to bypass the coverage check for a single push.
+if [ -n "$` + envSkipHooks + `" ]; then
+	exit 0
+fi
+exec ` + command + ` hooks run "$@"
+`
+}
+
+// gitHookPath returns the path of the named hook, honoring core.hooksPath
+func gitHookPath(ctx context.Context, hook string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "hooks/"+hook).Output() //nolint:gosec // hook is a fixed name
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git hooks directory (not a git repository?): %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// pushBase returns the commit the push is compared against: the merge base with the
+// upstream branch, falling back to the remote's default branch
+func pushBase(ctx context.Context) (string, error) {
+	for _, ref := range []string{"@{upstream}", "origin/HEAD"} {
+		output, err := exec.CommandContext(ctx, "git", "merge-base", "HEAD", ref).Output() //nolint:gosec // refs are fixed
+		if err == nil {
+			return strings.TrimSpace(string(output)), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no upstream branch or origin/HEAD (use --base)", ErrNoPushBase)
+}
+
+// gitChangedFiles lists the files under the working directory changed between base and HEAD,
+// relative to the working directory so they line up with go test package patterns
+func gitChangedFiles(ctx context.Context, base string) ([]string, error) {
+	if strings.HasPrefix(base, "-") {
+		return nil, fmt.Errorf("%w: invalid base %q", ErrNoPushBase, base)
+	}
+	output, err := exec.CommandContext(ctx, "git", "diff", "--name-only", "--relative", "--diff-filter=d", base, "HEAD").Output() //nolint:gosec // base cannot be an option
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// changedPackages maps changed Go files to the package patterns go test accepts,
+// skipping directories the go tool ignores
+func changedPackages(files []string) []string {
+	var packages []string
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		dir := path.Dir(filepath.ToSlash(file))
+		ignored := false
+		for _, segment := range strings.Split(dir, "/") {
+			hidden := segment != "." && strings.HasPrefix(segment, ".")
+			if hidden || strings.HasPrefix(segment, "_") || segment == "testdata" || segment == "vendor" {
+				ignored = true
+				break
+			}
+		}
+		if ignored {
+			continue
+		}
+
+		pkg := "./" + dir
+		if dir == "." {
+			pkg = "."
+		}
+		if !slices.Contains(packages, pkg) {
+			packages = append(packages, pkg)
+		}
+	}
+	slices.Sort(packages)
+	return packages
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initHookRepo creates a git repository in a temporary directory and makes it the working directory
+func initHookRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.email", "dev@example.com"},
+		{"config", "user.name", "Dev"},
+	} {
+		require.NoError(t, exec.Command("git", args...).Run()) //nolint:gosec,noctx // fixed test arguments
+	}
+	return dir
+}
+
+func TestChangedPackages(t *testing.T) {
+	assert.Equal(t, []string{".", "./internal/lib", "./lib"}, changedPackages([]string{
+		"main.go",
+		"lib/lib.go",
+		"lib/lib_test.go",
+		"internal/lib/store.go",
+		"README.md",
+		"lib/testdata/fixture.go",
+		"vendor/github.com/dep/dep.go",
+		".github/tools/tool.go",
+		"_examples/demo.go",
+	}))
+	assert.Empty(t, changedPackages([]string{"docs/guide.md"}))
+}
+
+func TestHooksInstallAndUninstall(t *testing.T) {
+	dir := initHookRepo(t)
+	hookPath := filepath.Join(dir, ".git", "hooks", prePushHook)
+
+	output, err := executeCommand(t, "hooks", "install")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Installed pre-push hook")
+
+	info, err := os.Stat(hookPath)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0o100, "hook must be executable")
+	script, err := os.ReadFile(hookPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(script), hookMarker)
+	assert.Contains(t, string(script), `exec go-coverage hooks run "$@"`)
+
+	_, err = executeCommand(t, "hooks", "install", "--command", "/usr/local/bin/go-coverage")
+	require.NoError(t, err, "reinstalling over our own hook is allowed")
+
+	_, err = executeCommand(t, "hooks", "uninstall")
+	require.NoError(t, err)
+	assert.NoFileExists(t, hookPath)
+
+	_, err = executeCommand(t, "hooks", "uninstall")
+	require.ErrorIs(t, err, ErrHookNotInstalled)
+
+	require.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\n"), 0o600))
+	_, err = executeCommand(t, "hooks", "install")
+	require.ErrorIs(t, err, ErrHookExists)
+	_, err = executeCommand(t, "hooks", "uninstall")
+	require.ErrorIs(t, err, ErrHookNotInstalled, "foreign hooks are never removed")
+
+	_, err = executeCommand(t, "hooks", "install", "--force")
+	require.NoError(t, err)
+}
+
+func TestHooksRun(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := initHookRepo(t)
+	git := func(args ...string) string {
+		out, err := exec.Command("git", args...).Output() //nolint:gosec,noctx // fixed test arguments
+		require.NoError(t, err)
+		return string(bytes.TrimSpace(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	write("go.mod", "module example.com/hooked\n\ngo 1.21\n")
+	write("README.md", "hooked\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "base")
+	base := git("rev-parse", "HEAD")
+
+	t.Run("no go changes", func(t *testing.T) {
+		write("README.md", "hooked, again\n")
+		git("commit", "--quiet", "-am", "docs")
+		output, err := executeCommand(t, "hooks", "run", "--base", base)
+		require.NoError(t, err)
+		assert.Contains(t, output, "No Go packages changed")
+	})
+
+	write("calc/calc.go", "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n")
+	write("calc/calc_test.go", "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal(\"bad sum\")\n\t}\n}\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "calc")
+
+	t.Run("below threshold blocks the push", func(t *testing.T) {
+		t.Setenv("GO_COVERAGE_THRESHOLD", "80")
+		output, err := executeCommand(t, "hooks", "run", "--base", base)
+		require.ErrorIs(t, err, ErrCoverageBelowThreshold)
+		assert.Contains(t, output, "Changed packages coverage: 50.00%")
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		t.Setenv("GO_COVERAGE_THRESHOLD", "80")
+		t.Setenv(envSkipHooks, "1")
+		output, err := executeCommand(t, "hooks", "run", "--base", base)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Coverage check skipped")
+	})
+
+	t.Run("meets threshold", func(t *testing.T) {
+		t.Setenv("GO_COVERAGE_THRESHOLD", "50")
+		_, err := executeCommand(t, "hooks", "run", "--base", base)
+		require.NoError(t, err)
+	})
+}
@@ -155,6 +155,9 @@ func TestParseCommand(t *testing.T) {
 }
 
 func TestHistoryCommand(t *testing.T) {
+	// The environment files would replace the temporary history directory
+	t.Setenv("GO_COVERAGE_TEST_CONFIG_DIR", "/nonexistent-test-isolation-dir")
+
 	// Disable GitHub integration for tests
 	_ = os.Setenv("GO_COVERAGE_POST_COMMENTS", flagBoolFalse)
 	_ = os.Setenv("GO_COVERAGE_CREATE_STATUSES", flagBoolFalse)
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// flagNameExtraInput is the repeatable [language=]path flag for LCOV and Cobertura reports
+const flagNameExtraInput = "extra-input"
+
+// mergeExtraInputs parses the LCOV and Cobertura reports of other languages and adds their
+// files to the Go coverage
+func mergeExtraInputs(ctx context.Context, p *parser.Parser, coverage *parser.CoverageData, args []string) error {
+	reports := make([]*parser.CoverageData, 0, len(args))
+	for _, arg := range args {
+		language, path, err := parser.ParseInputArg(arg)
+		if err != nil {
+			return err
+		}
+		report, err := p.ParseReportFile(ctx, path, language)
+		if err != nil {
+			return fmt.Errorf("failed to parse extra input: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	coverage.MergeReports(reports...)
+	return nil
+}
+
+// newLanguageDashboardData converts the per-language totals into the dashboard's language dimension
+func newLanguageDashboardData(breakdown []parser.LanguageCoverage) []dashboard.LanguageCoverage {
+	languages := make([]dashboard.LanguageCoverage, 0, len(breakdown))
+	for _, language := range breakdown {
+		languages = append(languages, dashboard.LanguageCoverage{
+			Language:     language.Language,
+			Files:        language.Files,
+			Coverage:     language.Percentage,
+			TotalLines:   language.TotalStatements,
+			CoveredLines: language.CoveredStatements,
+		})
+	}
+	return languages
+}
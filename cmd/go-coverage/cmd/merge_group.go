@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/handoff"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// gateMergeGroup evaluates the coverage gates for a merge queue run against the branch being
+// merged into and reports them on the queue's merge commit. A merge group can batch several
+// pull requests, so nothing is commented.
+func gateMergeGroup(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client github.API,
+	coverage *parser.CoverageData, createStatus, dryRun bool,
+) error {
+	baseBranch := runBaseBranch(cfg)
+	cmd.Printf("🚂 Merge queue run into %s: checking merge commit %s, skipping PR comment\n",
+		baseBranch, shortSHA(cfg.GitHub.CommitSHA))
+
+	measured := &handoff.Coverage{
+		Percentage:        coverage.Percentage,
+		TotalStatements:   coverage.TotalLines,
+		CoveredStatements: coverage.CoveredLines,
+		Trend:             "stable",
+		Threshold:         cfg.Coverage.Threshold,
+	}
+
+	// The newest run of the target branch is the baseline for the drop rules
+	var previous []float64
+	if cfg.History.Enabled {
+		tracker := history.NewWithConfig(&history.Config{
+			StoragePath:    historyStoragePath(cfg),
+			Repository:     cfg.RepositorySlug(),
+			RetentionDays:  cfg.History.RetentionDays,
+			MaxEntries:     cfg.History.MaxEntries,
+			AutoCleanup:    false,
+			MetricsEnabled: false,
+		})
+		var prevErr error
+		if previous, prevErr = previousCoverage(ctx, tracker, baseBranch, cfg.History.RetentionDays, policyHistoryDepth(cfg)); prevErr != nil {
+			cmd.Printf("Warning: failed to load previous runs for policy evaluation: %v\n", prevErr)
+		}
+	}
+	if len(previous) > 0 {
+		measured.BasePercentage = previous[0]
+		measured.Difference = coverage.Percentage - previous[0]
+		switch {
+		case measured.Difference > 0:
+			measured.Trend = "up"
+		case measured.Difference < 0:
+			measured.Trend = "down"
+		}
+	}
+
+	decision := evaluatePolicy(cfg, coverage, nil, previous, nil)
+	printOrgPolicy(cmd, cfg)
+	printBranchRule(cmd, cfg)
+	printLabelOverrides(cmd, cfg)
+	printPolicyDecision(cmd, decision)
+
+	if !createStatus || cfg.GitHub.CommitSHA == "" {
+		return nil
+	}
+	if dryRun {
+		cmd.Printf("🧪 DRY RUN: Would create status checks on merge commit %s\n", cfg.GitHub.CommitSHA)
+		return nil
+	}
+	createCoverageStatusChecks(ctx, cmd, cfg, client, 0, cfg.GitHub.CommitSHA, measured, criticalContexts(decision))
+	createCriticalStatus(ctx, cmd, cfg, client, cfg.GitHub.CommitSHA, cfg.GetReportURL(), decision)
+	return nil
+}
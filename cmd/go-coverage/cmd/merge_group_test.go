@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+const mergeGroupSHA = "abcdefabcdefabcdefabcdefabcdefabcdefabcd"
+
+func TestGateMergeGroup(t *testing.T) {
+	var mu sync.Mutex
+	var statuses []github.StatusRequest
+	var comments int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/statuses/"+mergeGroupSHA:
+			var req github.StatusRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			statuses = append(statuses, req)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case strings.Contains(r.URL.Path, "/comments"):
+			comments++
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	storage := t.TempDir()
+	tracker := history.NewWithConfig(&history.Config{StoragePath: storage, RetentionDays: 30, MaxEntries: 10})
+	require.NoError(t, tracker.Record(context.Background(), &parser.CoverageData{Percentage: 80, TotalLines: 100, CoveredLines: 80},
+		history.WithBranch("main"), history.WithCommit("basesha", "")))
+
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 70},
+		GitHub: config.GitHubConfig{
+			Owner: "owner", Repository: "repo", CommitSHA: mergeGroupSHA,
+		},
+		CI: &ci.Context{
+			Event: ci.EventMergeGroup, Branch: "gh-readonly-queue/main/pr-42-basesha",
+			BaseBranch: "main", CommitSHA: mergeGroupSHA,
+		},
+		History: config.HistoryConfig{Enabled: true, StoragePath: storage, RetentionDays: 30, MaxEntries: 10},
+	}
+	client := github.NewWithConfig(&github.Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	coverage := &parser.CoverageData{Percentage: 85, TotalLines: 100, CoveredLines: 85}
+
+	t.Run("dry run reports without posting", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		require.NoError(t, gateMergeGroup(context.Background(), cmd, cfg, client, coverage, true, true))
+		assert.Contains(t, out.String(), "Merge queue run into main")
+		assert.Contains(t, out.String(), "Would create status checks on merge commit "+mergeGroupSHA)
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("statuses on the merge commit, no comment", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		require.NoError(t, gateMergeGroup(context.Background(), cmd, cfg, client, coverage, true, false))
+
+		mu.Lock()
+		defer mu.Unlock()
+		// Status checks are created in map order, so look the total up by context
+		var total *github.StatusRequest
+		for i := range statuses {
+			if statuses[i].Context == "go-coverage/coverage/total" {
+				total = &statuses[i]
+			}
+		}
+		require.NotNil(t, total)
+		assert.Equal(t, github.StatusSuccess, total.State)
+		assert.Zero(t, comments)
+		assert.Contains(t, out.String(), "Coverage policy: PASSED")
+	})
+}
+
+func TestGateMergeGroupWithFakeClient(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 70},
+		GitHub: config.GitHubConfig{
+			Owner: "owner", Repository: "repo", CommitSHA: mergeGroupSHA,
+		},
+		CI: &ci.Context{
+			Event: ci.EventMergeGroup, Branch: "gh-readonly-queue/main/pr-42-basesha",
+			BaseBranch: "main", CommitSHA: mergeGroupSHA,
+		},
+	}
+	fake := github.NewFake()
+	coverage := &parser.CoverageData{Percentage: 60, TotalLines: 100, CoveredLines: 60}
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	require.NoError(t, gateMergeGroup(context.Background(), cmd, cfg, fake, coverage, true, false))
+
+	status, ok := fake.LatestStatus(mergeGroupSHA, "go-coverage/coverage/total")
+	require.True(t, ok)
+	assert.Equal(t, github.StatusFailure, status.State)
+	assert.Empty(t, fake.Comments, "merge groups are never commented")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// movedBlocks finds the code the PR removed from one file and added unchanged to another,
+// matched by the hash of the normalized block content, and counts the statements each block
+// holds in the base and PR profiles
+func movedBlocks(base, head *parser.CoverageData, files []github.PRFile) []analysis.MovedBlock {
+	type addedBlock struct {
+		file  *github.PRFile
+		block github.DiffBlock
+	}
+	added := make(map[string][]addedBlock)
+	removed := make([][]github.DiffBlock, len(files))
+	for i := range files {
+		var fileAdded []github.DiffBlock
+		removed[i], fileAdded = files[i].ChangedBlocks()
+		for _, block := range fileAdded {
+			added[block.Hash] = append(added[block.Hash], addedBlock{file: &files[i], block: block})
+		}
+	}
+
+	var blocks []analysis.MovedBlock
+	for i := range files {
+		baseName := files[i].Filename
+		if files[i].PreviousFilename != "" {
+			baseName = files[i].PreviousFilename
+		}
+		basePath, baseFile := findFile(base, baseName)
+		if baseFile == nil {
+			continue
+		}
+		for _, block := range removed[i] {
+			candidates := added[block.Hash]
+			match := -1
+			for j, candidate := range candidates {
+				if candidate.file != &files[i] {
+					match = j
+					break
+				}
+			}
+			if match < 0 {
+				continue
+			}
+			target := candidates[match]
+			added[block.Hash] = slices.Delete(candidates, match, match+1)
+
+			prPath, prFile := findFile(head, target.file.Filename)
+			if prFile == nil {
+				continue
+			}
+			moved := analysis.MovedBlock{From: basePath, To: prPath}
+			moved.BaseStatements, moved.BaseCovered = statementsInRange(baseFile, block.StartLine, block.EndLine)
+			moved.PRStatements, moved.PRCovered = statementsInRange(prFile, target.block.StartLine, target.block.EndLine)
+			if moved.BaseStatements > 0 || moved.PRStatements > 0 {
+				blocks = append(blocks, moved)
+			}
+		}
+	}
+	return blocks
+}
+
+// statementsInRange counts the statements of the blocks that lie within the given lines
+func statementsInRange(file *parser.FileCoverage, startLine, endLine int) (total, covered int) {
+	for _, stmt := range file.Statements {
+		if stmt.StartLine < startLine || stmt.EndLine > endLine {
+			continue
+		}
+		total += stmt.NumStmt
+		if stmt.Count > 0 {
+			covered += stmt.NumStmt
+		}
+	}
+	return total, covered
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestMovedBlocks(t *testing.T) {
+	const module = "github.com/owner/repo/"
+	profile := func(files map[string][]parser.Statement) *parser.CoverageData {
+		pkg := &parser.PackageCoverage{Files: make(map[string]*parser.FileCoverage)}
+		for name, statements := range files {
+			pkg.Files[module+name] = &parser.FileCoverage{Path: module + name, Statements: statements}
+		}
+		return &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{"pkg": pkg}}
+	}
+
+	base := profile(map[string][]parser.Statement{
+		"a.go": {{StartLine: 5, EndLine: 7, NumStmt: 2, Count: 1}, {StartLine: 20, EndLine: 21, NumStmt: 1}},
+		"b.go": {},
+	})
+	head := profile(map[string][]parser.Statement{
+		"a.go": {{StartLine: 16, EndLine: 17, NumStmt: 1}},
+		"b.go": {{StartLine: 11, EndLine: 13, NumStmt: 2, Count: 3}},
+	})
+	files := []github.PRFile{
+		{Filename: "a.go", Status: "modified", Patch: "@@ -4,5 +4,1 @@\n x()\n-func A() {\n-\tdo()\n-\tmore()\n-}"},
+		{Filename: "b.go", Status: "modified", Patch: "@@ -9,1 +9,5 @@\n y()\n+\n+func A() {\n+\tdo()\n+\tmore()\n+}"},
+	}
+
+	blocks := movedBlocks(base, head, files)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, analysis.MovedBlock{
+		From: module + "a.go", To: module + "b.go",
+		BaseStatements: 2, BaseCovered: 2, PRStatements: 2, PRCovered: 2,
+	}, blocks[0])
+	assert.True(t, blocks[0].IsNeutral())
+
+	// Code removed and added within the same file is not a move between files
+	sameFile := []github.PRFile{{Filename: "a.go", Patch: "@@ -5,3 +5,3 @@\n-func A() {\n-\tdo()\n-\tmore()\n+func A() {\n+\tdo()\n+\tmore()"}}
+	assert.Empty(t, movedBlocks(base, head, sameFile))
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// newFileResult holds the files a pull request adds to the coverage floor of new files. Files
+// without statements are not counted. It returns false when the floor is disabled or the
+// comparison with the base branch is not available.
+func newFileResult(cfg *config.Config, comparison *analysis.ComparisonResult) (policy.Result, bool) {
+	floor := cfg.Policy.NewFileThreshold
+	if floor <= 0 || comparison == nil {
+		return policy.Result{}, false
+	}
+
+	var added []analysis.FileChangeAnalysis
+	var below []analysis.FileChangeAnalysis
+	for _, change := range comparison.FileChanges {
+		if !change.IsNewFile || change.StatementChange == 0 {
+			continue
+		}
+		added = append(added, change)
+		if change.PRPercentage < floor {
+			below = append(below, change)
+		}
+	}
+
+	result := policy.Result{Rule: policy.RuleNewFiles, Outcome: policy.OutcomePass}
+	switch {
+	case len(added) == 0:
+		result.Outcome = policy.OutcomeSkip
+		result.Message = "no new files with statements"
+	case len(below) > 0:
+		sort.Slice(below, func(i, j int) bool {
+			if below[i].PRPercentage != below[j].PRPercentage {
+				return below[i].PRPercentage < below[j].PRPercentage
+			}
+			return below[i].Filename < below[j].Filename
+		})
+		result.Outcome = policy.OutcomeFail
+		result.Message = fmt.Sprintf("%d of %d new files are below the %.2f%% floor", len(below), len(added), floor)
+		for _, change := range below {
+			result.Trace = append(result.Trace, fmt.Sprintf("%s: %.2f%% (%d of %d statements covered)",
+				urlutil.CleanModulePathWithRepo(change.Filename, cfg.GitHub.Repository), change.PRPercentage,
+				change.CoveredStatementChange, change.StatementChange))
+		}
+	default:
+		result.Message = fmt.Sprintf("all %d new files meet the %.2f%% floor", len(added), floor)
+	}
+	return result, true
+}
+
+// addNewFileResult adds the coverage floor of new files to the decision, when it applies
+func addNewFileResult(cfg *config.Config, decision *policy.Decision, comparison *analysis.ComparisonResult) {
+	if result, ok := newFileResult(cfg, comparison); ok {
+		decision.Add(result)
+	}
+}
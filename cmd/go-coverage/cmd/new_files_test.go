@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analysis"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+func TestNewFileResult(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Repository: "repo"},
+		Policy: config.PolicyConfig{NewFileThreshold: 70},
+	}
+	comparison := &analysis.ComparisonResult{FileChanges: []analysis.FileChangeAnalysis{
+		{Filename: "github.com/owner/repo/api/handler.go", IsNewFile: true, PRPercentage: 45, StatementChange: 20, CoveredStatementChange: 9},
+		{Filename: "github.com/owner/repo/api/routes.go", IsNewFile: true, PRPercentage: 90, StatementChange: 10, CoveredStatementChange: 9},
+		{Filename: "github.com/owner/repo/api/doc.go", IsNewFile: true},
+		{Filename: "github.com/owner/repo/api/server.go", PRPercentage: 10, StatementChange: 5},
+	}}
+
+	result, ok := newFileResult(cfg, comparison)
+	require.True(t, ok)
+	assert.Equal(t, policy.RuleNewFiles, result.Rule)
+	assert.Equal(t, policy.OutcomeFail, result.Outcome)
+	assert.Equal(t, "1 of 2 new files are below the 70.00% floor", result.Message)
+	assert.Equal(t, []string{"api/handler.go: 45.00% (9 of 20 statements covered)"}, result.Trace)
+
+	cfg.Policy.NewFileThreshold = 40
+	result, _ = newFileResult(cfg, comparison)
+	assert.Equal(t, policy.OutcomePass, result.Outcome)
+	assert.Equal(t, "all 2 new files meet the 40.00% floor", result.Message)
+
+	result, _ = newFileResult(cfg, &analysis.ComparisonResult{FileChanges: comparison.FileChanges[2:]})
+	assert.Equal(t, policy.OutcomeSkip, result.Outcome)
+
+	_, ok = newFileResult(cfg, nil)
+	assert.False(t, ok, "no comparison")
+	cfg.Policy.NewFileThreshold = 0
+	_, ok = newFileResult(cfg, comparison)
+	assert.False(t, ok, "disabled")
+
+	decision := &policy.Decision{Passed: true}
+	cfg.Policy.NewFileThreshold = 70
+	addNewFileResult(cfg, decision, comparison)
+	assert.True(t, decision.Failed(policy.RuleNewFiles))
+}
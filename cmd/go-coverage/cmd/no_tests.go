@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// flagNameFailWithoutTests fails the gates of a repository without test files
+const flagNameFailWithoutTests = "fail-without-tests"
+
+// addFailWithoutTestsFlag adds the flag failing the gates of a repository without test files
+func addFailWithoutTestsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagNameFailWithoutTests, false, "Fail when the repository has no test files, instead of skipping the gates (default: GO_COVERAGE_FAIL_WITHOUT_TESTS)")
+}
+
+// applyFailWithoutTestsFlag lets the flag turn on GO_COVERAGE_FAIL_WITHOUT_TESTS
+func applyFailWithoutTestsFlag(cmd *cobra.Command, cfg *config.Config) {
+	if fail, _ := cmd.Flags().GetBool(flagNameFailWithoutTests); fail {
+		cfg.Coverage.FailWithoutTests = true
+	}
+}
+
+// hasTestFiles reports whether a _test.go file exists under root, skipping the directories the
+// go command ignores: vendor, testdata and names starting with . or _
+func hasTestFiles(root string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+// detectNoTests reports whether the repository has no test files at all, in which case its 0%
+// coverage says nothing about the code. When the repository cannot be read, tests are assumed.
+func detectNoTests(cmd *cobra.Command, cfg *config.Config) bool {
+	root, err := cfg.GetRepositoryRoot()
+	if err != nil {
+		return false
+	}
+	found, err := hasTestFiles(root)
+	if err != nil || found {
+		return false
+	}
+	if cfg.Coverage.FailWithoutTests {
+		cmd.Printf("🧪 No test files found in %s: the coverage gates fail until the first test is added\n", root)
+	} else {
+		cmd.Printf("🧪 No test files found in %s: the coverage gates are skipped until the first test is added\n", root)
+	}
+	return true
+}
+
+// applyNoTests replaces the decision of a repository without test files by the tests rule alone,
+// skipped or failed as configured, since no other rule is meaningful yet
+func applyNoTests(cfg *config.Config, decision *policy.Decision, noTests bool) {
+	if !noTests {
+		return
+	}
+	result := policy.Result{
+		Rule:    policy.RuleTests,
+		Outcome: policy.OutcomeSkip,
+		Message: "no test files found: gates are skipped until the first test is added",
+	}
+	if cfg.Coverage.FailWithoutTests {
+		result.Outcome = policy.OutcomeFail
+		result.Message = "no test files found in the repository"
+	}
+	*decision = policy.Decision{Passed: result.Outcome != policy.OutcomeFail, Results: []policy.Result{result}}
+}
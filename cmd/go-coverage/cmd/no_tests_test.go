@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+func TestHasTestFiles(t *testing.T) {
+	root := t.TempDir()
+	write := func(name string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n"), 0o600))
+	}
+
+	write("main.go")
+	write("vendor/example.com/lib/lib_test.go")
+	write("testdata/fixture_test.go")
+	write(".github/tools/tool_test.go")
+	write("_examples/example_test.go")
+	found, err := hasTestFiles(root)
+	require.NoError(t, err)
+	assert.False(t, found, "ignored directories do not count")
+
+	write("internal/app/app_test.go")
+	found, err = hasTestFiles(root)
+	require.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestDetectNoTests(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o600))
+	t.Chdir(root)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cfg := &config.Config{}
+	assert.True(t, detectNoTests(cmd, cfg))
+	assert.Contains(t, out.String(), "the coverage gates are skipped until the first test is added")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main_test.go"), []byte("package main\n"), 0o600))
+	assert.False(t, detectNoTests(cmd, cfg))
+}
+
+func TestApplyNoTests(t *testing.T) {
+	cfg := &config.Config{Coverage: config.CoverageConfig{Threshold: 80}, Policy: config.PolicyConfig{MaxDrop: -1}}
+	coverage := &parser.CoverageData{}
+
+	decision := evaluatePolicy(cfg, coverage, nil, nil, nil)
+	applyNoTests(cfg, decision, false)
+	assert.False(t, decision.Passed, "tests exist")
+
+	applyNoTests(cfg, decision, true)
+	assert.True(t, decision.Passed)
+	require.Len(t, decision.Results, 1)
+	assert.Equal(t, policy.RuleTests, decision.Results[0].Rule)
+	assert.Equal(t, policy.OutcomeSkip, decision.Results[0].Outcome)
+	require.NoError(t, policyError(cfg, coverage.Percentage, decision))
+
+	cfg.Coverage.FailWithoutTests = true
+	decision = evaluatePolicy(cfg, coverage, nil, nil, nil)
+	applyNoTests(cfg, decision, true)
+	assert.True(t, decision.Failed(policy.RuleTests))
+	require.Error(t, policyError(cfg, coverage.Percentage, decision))
+}
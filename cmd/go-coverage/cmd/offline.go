@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// ErrOfflineMode indicates that a command needing network access was run in offline mode
+var ErrOfflineMode = errors.New("network access is disabled in offline mode")
+
+const (
+	// flagNameOffline is the global flag that disables all network access
+	flagNameOffline = "offline"
+	// envOffline is the environment variable equivalent of --offline
+	envOffline = "GO_COVERAGE_OFFLINE"
+)
+
+// applyOfflineMode reports whether offline mode is enabled by the --offline flag or the
+// configuration. When enabled it is recorded in both the configuration and the environment
+// so that packages loading their own configuration (badge logos, etc.) stay offline too.
+// A nil configuration is treated as the defaults.
+func applyOfflineMode(cmd *cobra.Command, cfg *config.Config) bool {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	if offline, _ := cmd.Flags().GetBool(flagNameOffline); offline {
+		cfg.Network.Offline = true
+	}
+	if cfg.Network.Offline {
+		_ = os.Setenv(envOffline, "true")
+	}
+	return cfg.Network.Offline
+}
+
+// requireNetwork fails fast with a descriptive error when a networked feature is requested offline
+func requireNetwork(cmd *cobra.Command, cfg *config.Config, feature string) error {
+	if !applyOfflineMode(cmd, cfg) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s requires network access (remove --offline or unset %s)", ErrOfflineMode, feature, envOffline)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// isolateOfflineEnv restores GO_COVERAGE_OFFLINE after a test that may enable offline mode
+func isolateOfflineEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(envOffline, "")
+	t.Setenv(envRecordFixtures, "")
+	t.Setenv(envReplayFixtures, "")
+	t.Setenv("GO_COVERAGE_TEST_CONFIG_DIR", "/nonexistent-test-isolation-dir")
+}
+
+func TestOfflineFlagIsGlobal(t *testing.T) {
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+
+	flag := commands.Root.PersistentFlags().Lookup(flagNameOffline)
+	require.NotNil(t, flag)
+	assert.Equal(t, flagBoolFalse, flag.DefValue)
+}
+
+func TestApplyOfflineMode(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		cmd := &cobra.Command{Use: testCoverageLabel}
+		cmd.Flags().Bool(flagNameOffline, false, "")
+
+		cfg := &config.Config{}
+		assert.False(t, applyOfflineMode(cmd, cfg))
+		assert.Empty(t, os.Getenv(envOffline))
+	})
+
+	t.Run("flag enables offline mode", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		cmd := &cobra.Command{Use: testCoverageLabel}
+		cmd.Flags().Bool(flagNameOffline, false, "")
+		require.NoError(t, cmd.ParseFlags([]string{"--offline"}))
+
+		cfg := &config.Config{}
+		assert.True(t, applyOfflineMode(cmd, cfg))
+		assert.True(t, cfg.Network.Offline)
+		assert.Equal(t, "true", os.Getenv(envOffline), "nested config loads must see offline mode")
+	})
+
+	t.Run("configuration enables offline mode", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		cmd := &cobra.Command{Use: testCoverageLabel}
+
+		cfg := &config.Config{Network: config.NetworkConfig{Offline: true}}
+		assert.True(t, applyOfflineMode(cmd, cfg))
+	})
+
+	t.Run("nil configuration", func(t *testing.T) {
+		isolateOfflineEnv(t)
+		cmd := &cobra.Command{Use: testCoverageLabel}
+		assert.False(t, applyOfflineMode(cmd, nil))
+	})
+}
+
+func TestNetworkCommandsFailFastOffline(t *testing.T) {
+	for _, name := range []string{"comment", "upgrade", "setup-pages"} {
+		t.Run(name, func(t *testing.T) {
+			isolateOfflineEnv(t)
+			commands := NewCommands(VersionInfo{Version: testVersionStr})
+
+			var buf bytes.Buffer
+			commands.Root.SetOut(&buf)
+			commands.Root.SetErr(&buf)
+			commands.Root.SetArgs([]string{name, "--offline"})
+
+			err := commands.Execute()
+			require.ErrorIs(t, err, ErrOfflineMode)
+			assert.Contains(t, err.Error(), name)
+		})
+	}
+}
+
+func TestCompleteCommandOffline(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 3 0
+`), 0o600))
+
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs([]string{
+		cmdComplete, "--offline",
+		"--input", coverageFile,
+		"--output", outputDir,
+		"--skip-history",
+	})
+
+	require.NoError(t, commands.Execute())
+
+	output := buf.String()
+	assert.Contains(t, output, "Mode: OFFLINE")
+	assert.Contains(t, output, "GitHub integration (skipped: offline mode)")
+
+	// Local artifacts are still produced
+	assert.FileExists(t, filepath.Join(outputDir, "coverage.svg"))
+	assert.FileExists(t, filepath.Join(outputDir, "coverage.html"))
+
+	data, err := os.ReadFile(filepath.Join(outputDir, pipelineSummaryFile)) //nolint:gosec // test file path
+	require.NoError(t, err)
+
+	var summary pipelineSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.True(t, summary.Offline)
+	assert.True(t, summary.Passed)
+	assert.InDelta(t, 40.0, summary.Coverage, 0.01)
+	assert.Equal(t, 2, summary.CoveredStatements)
+	assert.Equal(t, 5, summary.TotalStatements)
+	assert.Equal(t, "abc123", summary.CommitSHA)
+	assert.Equal(t, testVersionStr, summary.GeneratorVersion)
+	assert.False(t, summary.GeneratedAt.IsZero())
+
+	// Provenance is recorded alongside the summary
+	assert.FileExists(t, filepath.Join(outputDir, siteMetadataFile))
+}
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/orgpolicy"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// ErrCoveragePolicyFailed indicates that a gating policy other than the threshold failed
+var ErrCoveragePolicyFailed = errors.New("coverage policy failed")
+
+// previousCoverage returns the coverage of up to limit earlier runs on branch, newest first
+func previousCoverage(ctx context.Context, tracker *history.Tracker, branch string, retentionDays, limit int) ([]float64, error) {
+	trendData, err := tracker.GetTrend(ctx,
+		history.WithTrendBranch(branch),
+		history.WithTrendDays(retentionDays),
+		history.WithMaxDataPoints(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(trendData.Entries))
+	for _, entry := range trendData.Entries {
+		if entry.Coverage != nil {
+			values = append(values, entry.Coverage.Percentage)
+		}
+	}
+	return values, nil
+}
+
+// policyHistoryDepth is the number of previous runs the configured policy needs
+func policyHistoryDepth(cfg *config.Config) int {
+	return max(cfg.Policy.DeclineRuns, cfg.Policy.ConfidenceRuns, 1)
+}
+
+// confidenceInterval estimates the confidence band around coverage from up to ConfidenceRuns
+// previous runs, newest first; nil when the band is disabled or the history is too short
+func confidenceInterval(cfg *config.Config, coverage float64, previous []float64) *policy.Interval {
+	if cfg.Policy.ConfidenceRuns <= 0 {
+		return nil
+	}
+	band, ok := policy.ConfidenceInterval(coverage, previous[:min(len(previous), cfg.Policy.ConfidenceRuns)], cfg.Policy.ConfidenceLevel)
+	if !ok {
+		return nil
+	}
+	return &band
+}
+
+// gateResult is the coverage of a run and the outcome of its gates
+type gateResult struct {
+	Coverage *parser.CoverageData
+	Base     *parser.CoverageData // Base profile given on the command line (nil when none)
+	Previous []float64            // Previous runs of the target branch, newest first
+	Decision *policy.Decision
+}
+
+// baseline returns the coverage the run is compared with: the base profile, or the newest
+// previous run of the target branch
+func (g *gateResult) baseline() (float64, bool) {
+	switch {
+	case g.Base != nil:
+		return g.Base.Percentage, true
+	case len(g.Previous) > 0:
+		return g.Previous[0], true
+	default:
+		return 0, false
+	}
+}
+
+// evaluateGates parses the coverage profile and the optional base profile, loads the previous
+// runs of the target branch from the history and evaluates the configured policy, for the
+// integrations that report the gates outside GitHub
+func evaluateGates(ctx context.Context, cmd *cobra.Command, cfg *config.Config, inputFile, baseFile, targetBranch string) (*gateResult, error) {
+	p := parser.NewWithConfig(&parser.Config{
+		ExcludePaths:     cfg.Coverage.ExcludePaths,
+		ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+		ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+		ExcludePresets:   cfg.Coverage.ExcludePresets,
+		Limits:           cfg.ParserLimits(),
+		ModuleRewrites:   cfg.ModuleRewrites(),
+		ModuleRoot:       cfg.Coverage.ModuleRoot,
+	})
+
+	result := &gateResult{}
+	var err error
+	if result.Coverage, err = p.ParseFile(ctx, inputFile); err != nil {
+		return nil, fmt.Errorf("failed to parse coverage file: %w", err)
+	}
+	if baseFile != "" {
+		if result.Base, err = p.ParseFile(ctx, baseFile); err != nil {
+			return nil, fmt.Errorf("failed to parse base coverage: %w", err)
+		}
+	}
+
+	if cfg.History.Enabled {
+		tracker := history.NewWithConfig(&history.Config{
+			StoragePath:    historyStoragePath(cfg),
+			Repository:     cfg.RepositorySlug(),
+			RetentionDays:  cfg.History.RetentionDays,
+			MaxEntries:     cfg.History.MaxEntries,
+			AutoCleanup:    false,
+			MetricsEnabled: false,
+		})
+		if result.Previous, err = previousCoverage(ctx, tracker, targetBranch, cfg.History.RetentionDays, policyHistoryDepth(cfg)); err != nil {
+			cmd.Printf("Warning: failed to load previous runs for policy evaluation: %v\n", err)
+		}
+	}
+
+	result.Decision = evaluatePolicy(cfg, result.Coverage, result.Base, result.Previous, nil)
+	applyNoTests(cfg, result.Decision, detectNoTests(cmd, cfg))
+	bypass := emergencyBypass(ctx, cfg, nil)
+	applyBypass(result.Decision, bypass)
+	warmup := loadWarmupState(ctx, cmd, cfg)
+	applyWarmup(cfg, result.Decision, warmup)
+	printOrgPolicy(cmd, cfg)
+	printBranchRule(cmd, cfg)
+	printLabelOverrides(cmd, cfg)
+	printBypass(cmd, bypass)
+	printWarmup(cmd, cfg, warmup)
+	printPolicyDecision(cmd, result.Decision)
+	return result, nil
+}
+
+// describeGates summarizes the coverage and the failed rules in one line for commit statuses
+func describeGates(gates *gateResult, targetBranch string) string {
+	description := fmt.Sprintf("%.2f%% coverage", gates.Coverage.Percentage)
+	if band := gates.Decision.Confidence; band != nil {
+		description = fmt.Sprintf("%.2f%% ± %.2f coverage", gates.Coverage.Percentage, band.Margin)
+	}
+	if baseline, ok := gates.baseline(); ok {
+		description += fmt.Sprintf(" (%+.2f%% vs %s)", gates.Coverage.Percentage-baseline, targetBranch)
+	}
+	if gates.Decision.Bypass != "" {
+		description += ", gates bypassed"
+	}
+	if gates.Decision.Warmup != "" {
+		description += ", warm-up"
+	}
+	if !gates.Decision.Passed {
+		failures := gates.Decision.Failures()
+		rules := make([]string, 0, len(failures))
+		for _, result := range failures {
+			rules = append(rules, result.Rule)
+		}
+		description += ", failed " + strings.Join(rules, ", ")
+	}
+	return description
+}
+
+// renderGateMarkdown formats the coverage comment posted by the integrations outside GitHub.
+// Their Markdown renders no HTML, so the comment uses tables and links only, and the marker
+// is a link reference definition that identifies the comment without being displayed.
+func renderGateMarkdown(marker string, gates *gateResult, targetBranch, reportURL string) string {
+	var b strings.Builder
+
+	coverage := gates.Coverage
+	b.WriteString(marker + "\n\n")
+	if band := gates.Decision.Confidence; band != nil {
+		fmt.Fprintf(&b, "## 📊 Coverage: %.2f%% ± %.2f\n\n", coverage.Percentage, band.Margin)
+	} else {
+		fmt.Fprintf(&b, "## 📊 Coverage: %.2f%%\n\n", coverage.Percentage)
+	}
+	fmt.Fprintf(&b, "**%d/%d** statements covered", coverage.CoveredLines, coverage.TotalLines)
+	if baseline, ok := gates.baseline(); ok {
+		fmt.Fprintf(&b, ", **%+.2f%%** vs `%s` (%.2f%%)", coverage.Percentage-baseline, targetBranch, baseline)
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case gates.Decision.Bypass != "":
+		fmt.Fprintf(&b, "### 🚨 Coverage policy bypassed\n\n%s: failed rules are reported as warnings.\n\n", gates.Decision.Bypass)
+	case gates.Decision.Warmup != "":
+		fmt.Fprintf(&b, "### 🌱 Coverage policy warming up\n\n%s: failed rules are reported as warnings while the coverage history accumulates.\n\n", gates.Decision.Warmup)
+	case gates.Decision.Passed:
+		b.WriteString("### ✅ Coverage policy passed\n\n")
+	default:
+		b.WriteString("### ❌ Coverage policy failed\n\n")
+	}
+	if len(gates.Decision.Results) > 0 {
+		b.WriteString("| Rule | Result | Details |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, result := range gates.Decision.Results {
+			fmt.Fprintf(&b, "| `%s` | %s %s | %s |\n", result.Rule, result.Outcome.Icon(), result.Outcome, result.Message)
+		}
+		b.WriteString("\n")
+	}
+
+	if reportURL != "" {
+		fmt.Fprintf(&b, "📊 [Coverage report](%s)\n\n", reportURL)
+	}
+	b.WriteString("---\n\n*Generated via [go-coverage](https://github.com/mrz1836/go-coverage)*")
+	return b.String()
+}
+
+// evaluatePolicy runs the configured policy engine. The base profile is the baseline for the
+// drop rules when provided, otherwise the newest previous run is used. Patch coverage is
+// computed from the PR diff when one is available. Critical paths are held to their own
+// requirement on top of the engine's rules.
+func evaluatePolicy(cfg *config.Config, coverage, base *parser.CoverageData, previous []float64, prDiff *github.PRDiff) *policy.Decision {
+	input := policy.Input{
+		Coverage:   coverage.Percentage,
+		Previous:   previous,
+		Statements: coverage.TotalLines,
+		Covered:    coverage.CoveredLines,
+		Confidence: confidenceInterval(cfg, coverage.Percentage, previous),
+	}
+	if prDiff != nil {
+		input.Patch, input.HasPatch = patchCoverage(coverage, prDiff.Files)
+	}
+	switch {
+	case base != nil:
+		input.HasBase = true
+		input.Base = base.Percentage
+	case len(previous) > 0:
+		input.HasBase = true
+		input.Base = previous[0]
+	}
+	decision := cfg.NewPolicyEngine().Evaluate(input)
+	for _, result := range criticalPathResults(cfg, coverage) {
+		decision.Add(result)
+	}
+	return decision
+}
+
+// patchCoverage returns the coverage of the statements on lines added by the PR,
+// or false when the change adds no covered statements
+func patchCoverage(coverage *parser.CoverageData, files []github.PRFile) (float64, bool) {
+	total, covered := 0, 0
+	for i := range files {
+		added := files[i].AddedLines()
+		if len(added) == 0 {
+			continue
+		}
+		fileCoverage := findFileCoverage(coverage, files[i].Filename)
+		if fileCoverage == nil {
+			continue
+		}
+		for _, stmt := range fileCoverage.Statements {
+			for _, line := range added {
+				if line >= stmt.StartLine && line <= stmt.EndLine {
+					total += stmt.NumStmt
+					if stmt.Count > 0 {
+						covered += stmt.NumStmt
+					}
+					break
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(covered) / float64(total) * 100, true
+}
+
+// findFileCoverage finds the profile entry for a repository relative path; profiles use module import paths
+func findFileCoverage(coverage *parser.CoverageData, filename string) *parser.FileCoverage {
+	_, file := findFile(coverage, filename)
+	return file
+}
+
+// findFile returns the profile path and entry for a repository relative path
+func findFile(coverage *parser.CoverageData, filename string) (string, *parser.FileCoverage) {
+	for _, pkg := range coverage.Packages {
+		for path, file := range pkg.Files {
+			if path == filename || strings.HasSuffix(path, "/"+filename) {
+				return path, file
+			}
+		}
+	}
+	return "", nil
+}
+
+// printOrgPolicy names the organization policy merged under the local configuration
+func printOrgPolicy(cmd *cobra.Command, cfg *config.Config) {
+	if cfg.OrgPolicy.Source == "" {
+		return
+	}
+	cmd.Printf("🏢 Organization policy %s (%s): %d settings applied\n", cfg.OrgPolicy.URL, cfg.OrgPolicy.Source, len(cfg.OrgPolicy.Applied))
+	if cfg.OrgPolicy.Source == orgpolicy.SourceStaleCache {
+		cmd.Printf("   ⚠️  Download failed, using the expired cached copy\n")
+	}
+}
+
+// printBranchRule reports the branch rule whose settings replaced the global ones
+func printBranchRule(cmd *cobra.Command, cfg *config.Config) {
+	if rule := cfg.Branches.Applied; rule != nil {
+		cmd.Printf("🌿 Branch rule %s applies to %s\n", rule.Pattern, cfg.Branches.Target)
+	}
+}
+
+// printLabelOverrides reports the pull request labels whose overrides replaced the settings
+func printLabelOverrides(cmd *cobra.Command, cfg *config.Config) {
+	if len(cfg.Labels.Applied) > 0 {
+		cmd.Printf("🏷️  Pull request labels apply: %s%s\n", cfg.Labels.Prefix,
+			strings.Join(cfg.Labels.Applied, ", "+cfg.Labels.Prefix))
+	}
+}
+
+// printPolicyDecision explains every rule outcome, including gate evaluation traces
+func printPolicyDecision(cmd *cobra.Command, decision *policy.Decision) {
+	switch {
+	case decision.Bypass != "":
+		cmd.Printf("🚦 Coverage policy: BYPASSED (%s)\n", decision.Bypass)
+	case decision.Warmup != "":
+		cmd.Printf("🚦 Coverage policy: WARM-UP (%s)\n", decision.Warmup)
+	case decision.Passed:
+		cmd.Printf("🚦 Coverage policy: PASSED\n")
+	default:
+		cmd.Printf("🚦 Coverage policy: FAILED\n")
+	}
+	if band := decision.Confidence; band != nil {
+		cmd.Printf("   📏 Confidence: %s (%.0f%% band %.2f%%-%.2f%%, from %d runs)\n", band, band.Level, band.Lower, band.Upper, band.Runs)
+	}
+	for _, result := range decision.Results {
+		cmd.Printf("   %s %s: %s\n", result.Outcome.Icon(), result.Rule, result.Message)
+		for _, step := range result.Trace {
+			cmd.Printf("      %s\n", step)
+		}
+	}
+}
+
+// policyError converts a failed decision into an error, keeping ErrCoverageBelowThreshold for threshold failures
+func policyError(cfg *config.Config, coverage float64, decision *policy.Decision) error {
+	if decision.Passed {
+		return nil
+	}
+	if decision.Failed(policy.RuleThreshold) {
+		if band := decision.Confidence; band != nil && cfg.Policy.LowerBound {
+			return fmt.Errorf("%w: lower bound %.2f%% of %s is below threshold %.2f%%", ErrCoverageBelowThreshold, band.Lower, band, cfg.Coverage.Threshold)
+		}
+		return fmt.Errorf("%w: %.2f%% is below threshold %.2f%%", ErrCoverageBelowThreshold, coverage, cfg.Coverage.Threshold)
+	}
+
+	failures := decision.Failures()
+	messages := make([]string, 0, len(failures))
+	for _, result := range failures {
+		messages = append(messages, result.Rule+": "+result.Message)
+	}
+	return fmt.Errorf("%w: %s", ErrCoveragePolicyFailed, strings.Join(messages, "; "))
+}
+
+// newPolicyTemplateData converts a decision for rendering in the PR comment
+func newPolicyTemplateData(decision *policy.Decision) *templates.PolicyData {
+	if decision == nil {
+		return nil
+	}
+	data := &templates.PolicyData{
+		Passed:  decision.Passed,
+		Results: make([]templates.PolicyResultData, 0, len(decision.Results)),
+		Bypass:  decision.Bypass,
+		Warmup:  decision.Warmup,
+	}
+	if band := decision.Confidence; band != nil {
+		data.Confidence = &templates.ConfidenceData{
+			Margin: band.Margin,
+			Lower:  band.Lower,
+			Upper:  band.Upper,
+			Level:  band.Level,
+			Runs:   band.Runs,
+		}
+	}
+	for _, result := range decision.Results {
+		data.Results = append(data.Results, templates.PolicyResultData{
+			Rule:    result.Rule,
+			Outcome: string(result.Outcome),
+			Icon:    result.Outcome.Icon(),
+			Message: result.Message,
+			Trace:   result.Trace,
+		})
+		if result.Rule == policy.RuleCritical && (result.Outcome == policy.OutcomeFail || result.Outcome == policy.OutcomeWarn) {
+			data.CriticalFailures = append(data.CriticalFailures, data.Results[len(data.Results)-1])
+		}
+	}
+	return data
+}
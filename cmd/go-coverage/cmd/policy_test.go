@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/orgpolicy"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+func TestEvaluatePolicyBaseline(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 50},
+		Policy:   config.PolicyConfig{MaxDrop: 1},
+	}
+
+	t.Run("previous run is the default baseline", func(t *testing.T) {
+		decision := evaluatePolicy(cfg, &parser.CoverageData{Percentage: 70}, nil, []float64{75}, nil)
+		assert.False(t, decision.Passed)
+		assert.True(t, decision.Failed(policy.RuleMaxDrop))
+	})
+
+	t.Run("base profile takes precedence", func(t *testing.T) {
+		decision := evaluatePolicy(cfg, &parser.CoverageData{Percentage: 70}, &parser.CoverageData{Percentage: 70.5}, []float64{75}, nil)
+		assert.True(t, decision.Passed)
+	})
+
+	t.Run("no baseline skips drop rule", func(t *testing.T) {
+		decision := evaluatePolicy(cfg, &parser.CoverageData{Percentage: 70}, nil, nil, nil)
+		assert.True(t, decision.Passed)
+		assert.Equal(t, policy.OutcomeSkip, decision.Results[1].Outcome)
+	})
+}
+
+func TestEvaluatePolicyBranchRule(t *testing.T) {
+	threshold, maxDrop := 70.0, 3.0
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		Policy:   config.PolicyConfig{MaxDrop: 0.5},
+		Branches: config.BranchConfig{Rules: []config.BranchRule{{Pattern: "release/*", Threshold: &threshold, MaxDrop: &maxDrop}}},
+	}
+	coverage := &parser.CoverageData{Percentage: 72}
+
+	assert.False(t, evaluatePolicy(cfg, coverage, nil, []float64{74}, nil).Passed)
+
+	require.NotNil(t, cfg.ApplyBranchRules("release/1.x"))
+	assert.True(t, evaluatePolicy(cfg, coverage, nil, []float64{74}, nil).Passed)
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	printBranchRule(cmd, cfg)
+	assert.Equal(t, "🌿 Branch rule release/* applies to release/1.x\n", out.String())
+}
+
+func TestPrintOrgPolicy(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	printOrgPolicy(cmd, &config.Config{})
+	assert.Empty(t, out.String())
+
+	printOrgPolicy(cmd, &config.Config{OrgPolicy: config.OrgPolicyConfig{
+		URL:     "https://example.com/policy.env",
+		Source:  orgpolicy.SourceStaleCache,
+		Applied: []string{"GO_COVERAGE_THRESHOLD"},
+	}})
+	assert.Equal(t, "🏢 Organization policy https://example.com/policy.env (stale cache): 1 settings applied\n"+
+		"   ⚠️  Download failed, using the expired cached copy\n", out.String())
+}
+
+func TestEvaluatePolicyGateWithPatch(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 0},
+		Policy:   config.PolicyConfig{MaxDrop: -1, Gate: "total >= 40 && patch >= 90"},
+	}
+	coverage := &parser.CoverageData{
+		Percentage:   40,
+		TotalLines:   5,
+		CoveredLines: 2,
+		Packages: map[string]*parser.PackageCoverage{
+			"github.com/example/repo/lib": {Files: map[string]*parser.FileCoverage{
+				"github.com/example/repo/lib/lib.go": {Statements: []parser.Statement{
+					{StartLine: 10, EndLine: 12, NumStmt: 2, Count: 1},
+					{StartLine: 15, EndLine: 17, NumStmt: 3, Count: 0},
+				}},
+			}},
+		},
+	}
+	prDiff := &github.PRDiff{Files: []github.PRFile{
+		{Filename: "lib/lib.go", Patch: "@@ -9,3 +9,4 @@\n a\n+b\n c\n d\n@@ -20,1 +16,2 @@\n e\n+f"},
+		{Filename: "README.md", Patch: "@@ -1 +1 @@\n-x\n+y"},
+	}}
+
+	percent, ok := patchCoverage(coverage, prDiff.Files)
+	require.True(t, ok)
+	assert.InDelta(t, 40.0, percent, 0.01)
+
+	decision := evaluatePolicy(cfg, coverage, nil, nil, prDiff)
+	assert.True(t, decision.Failed(policy.RuleGate))
+	gate := decision.Results[len(decision.Results)-1]
+	assert.Equal(t, []string{"✅ total >= 40 (40 >= 40)", "❌ patch >= 90 (40 >= 90)"}, gate.Trace)
+
+	decision = evaluatePolicy(cfg, coverage, nil, nil, nil)
+	assert.True(t, decision.Passed, "patch comparisons are skipped without a PR diff")
+}
+
+func TestEvaluatePolicyConfidence(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		Policy:   config.PolicyConfig{MaxDrop: -1, ConfidenceRuns: 4, ConfidenceLevel: 95},
+	}
+	coverage := &parser.CoverageData{Percentage: 80.5}
+	previous := []float64{79.5, 80.7, 79.9, 80.4, 60}
+
+	decision := evaluatePolicy(cfg, coverage, nil, previous, nil)
+	require.NotNil(t, decision.Confidence)
+	assert.Equal(t, 4, decision.Confidence.Runs, "only ConfidenceRuns previous runs are used")
+	assert.True(t, decision.Passed)
+	assert.Equal(t, 4, policyHistoryDepth(cfg))
+
+	gates := &gateResult{Coverage: coverage, Decision: decision}
+	assert.Contains(t, describeGates(gates, "main"), "80.50% ± ")
+	assert.Contains(t, renderGateMarkdown("<!-- marker -->", gates, "main", ""), "## 📊 Coverage: 80.50% ± ")
+
+	cfg.Policy.LowerBound = true
+	decision = evaluatePolicy(cfg, coverage, nil, previous, nil)
+	assert.True(t, decision.Failed(policy.RuleThreshold))
+	err := policyError(cfg, coverage.Percentage, decision)
+	require.ErrorIs(t, err, ErrCoverageBelowThreshold)
+	assert.Contains(t, err.Error(), "lower bound")
+
+	assert.Nil(t, evaluatePolicy(cfg, coverage, nil, previous[:2], nil).Confidence, "too little history for a band")
+	cfg.Policy.ConfidenceRuns = 0
+	assert.Nil(t, evaluatePolicy(cfg, coverage, nil, previous, nil).Confidence)
+}
+
+func TestPolicyError(t *testing.T) {
+	cfg := &config.Config{Coverage: config.CoverageConfig{Threshold: 80}}
+
+	require.NoError(t, policyError(cfg, 85, &policy.Decision{Passed: true}))
+
+	err := policyError(cfg, 75, &policy.Decision{Results: []policy.Result{
+		{Rule: policy.RuleThreshold, Outcome: policy.OutcomeFail, Message: "below"},
+	}})
+	require.ErrorIs(t, err, ErrCoverageBelowThreshold)
+
+	err = policyError(cfg, 85, &policy.Decision{Results: []policy.Result{
+		{Rule: policy.RuleThreshold, Outcome: policy.OutcomePass, Message: "ok"},
+		{Rule: policy.RuleSustainedDecline, Outcome: policy.OutcomeFail, Message: "declined 3 runs"},
+	}})
+	require.ErrorIs(t, err, ErrCoveragePolicyFailed)
+	assert.Contains(t, err.Error(), "sustained-decline: declined 3 runs")
+}
+
+func TestNewPolicyTemplateData(t *testing.T) {
+	assert.Nil(t, newPolicyTemplateData(nil))
+
+	data := newPolicyTemplateData(&policy.Decision{Passed: true, Results: []policy.Result{
+		{Rule: policy.RuleMaxDrop, Outcome: policy.OutcomeWarn, Message: "tolerated"},
+	}})
+	require.Len(t, data.Results, 1)
+	assert.True(t, data.Passed)
+	assert.Equal(t, "warn", data.Results[0].Outcome)
+	assert.Equal(t, "⚠️", data.Results[0].Icon)
+	assert.Nil(t, data.Confidence)
+
+	data = newPolicyTemplateData(&policy.Decision{Passed: true, Confidence: &policy.Interval{Margin: 0.6, Lower: 81.8, Upper: 83, Level: 95, Runs: 10}})
+	require.NotNil(t, data.Confidence)
+	assert.InDelta(t, 0.6, data.Confidence.Margin, 0)
+	assert.Equal(t, 10, data.Confidence.Runs)
+}
+
+func TestCompleteCommandMaxDropPolicy(t *testing.T) {
+	isolateOfflineEnv(t)
+	isolateRunContextEnv(t)
+	dir := t.TempDir()
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GITHUB_REF_NAME", "feature-policy")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(dir, "history"))
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+	t.Setenv("GO_COVERAGE_POLICY_MAX_DROP", "5")
+
+	run := func(profile string) (string, error) {
+		coverageFile := filepath.Join(dir, "coverage.txt")
+		require.NoError(t, os.WriteFile(coverageFile, []byte(profile), 0o600))
+
+		commands := NewCommands(VersionInfo{Version: testVersionStr})
+		var buf bytes.Buffer
+		commands.Root.SetOut(&buf)
+		commands.Root.SetErr(&buf)
+		commands.Root.SetArgs([]string{
+			cmdComplete, "--offline",
+			"--input", coverageFile,
+			"--output", filepath.Join(dir, "output"),
+		})
+		err := commands.Execute()
+		return buf.String(), err
+	}
+
+	output, err := run(compareBaseProfile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Coverage policy: PASSED")
+
+	output, err = run(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+github.com/example/repo/lib/lib.go:15.2,17.16 3 0
+github.com/example/repo/api/api.go:5.2,6.10 5 0
+`)
+	require.ErrorIs(t, err, ErrCoveragePolicyFailed)
+	assert.Contains(t, output, "Coverage policy: FAILED")
+	assert.Contains(t, output, "max-drop: coverage dropped 20.00 pts (40.00% → 20.00%)")
+}
+
+func TestLabelOverrides(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		Policy:   config.PolicyConfig{MaxDrop: 3},
+		Labels:   config.LabelConfig{Enabled: true, Prefix: "coverage:"},
+	}
+	coverage := &parser.CoverageData{Percentage: 82}
+	assert.True(t, evaluatePolicy(cfg, coverage, nil, []float64{83}, nil).Passed)
+
+	assert.Equal(t, []string{config.LabelStrict, config.LabelReportAllFiles},
+		cfg.ApplyLabels([]string{"coverage:strict", "coverage:report-all-files", "bug"}))
+	assert.False(t, evaluatePolicy(cfg, coverage, nil, []float64{83}, nil).Passed, "strict gates fail any decrease")
+	assert.Greater(t, commentTemplateConfig(cfg, 0).MaxFileChanges, 20)
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	printLabelOverrides(cmd, cfg)
+	assert.Equal(t, "🏷️  Pull request labels apply: coverage:strict, coverage:report-all-files\n", out.String())
+}
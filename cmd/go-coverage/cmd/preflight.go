@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// ErrTokenPermission indicates that the GitHub token may not perform an operation a command needs
+var ErrTokenPermission = errors.New("the GitHub token lacks a required permission")
+
+// tokenProber finds out which operations the GitHub token may perform
+type tokenProber interface {
+	ProbeTokenAccess(ctx context.Context, owner, repo string, pr int) (*github.TokenAccess, error)
+}
+
+// preflightFeatures are the features of a run that need token permissions. The preflight turns
+// off the ones the token may not use.
+type preflightFeatures struct {
+	Statuses  bool
+	Comments  bool
+	Artifacts bool
+}
+
+// preflightFeature describes a feature for the preflight report
+type preflightFeature struct {
+	name       string
+	permission string
+	enabled    *bool
+	access     github.Access
+}
+
+// preflightGitHub probes the token's permissions before anything is posted and turns off the
+// requested features it may not use, printing what will be skipped. It is a no-op when
+// GO_COVERAGE_PREFLIGHT is off; when the probes fail the features are left on.
+func preflightGitHub(ctx context.Context, cmd *cobra.Command, cfg *config.Config, prober tokenProber, pr int, features *preflightFeatures) {
+	if !cfg.GitHub.Preflight || (!features.Statuses && !features.Comments && !features.Artifacts) {
+		return
+	}
+
+	cmd.Printf("🔐 Preflight: checking token permissions...\n")
+	access, err := prober.ProbeTokenAccess(ctx, cfg.GitHub.Owner, cfg.GitHub.Repository, pr)
+	if err != nil {
+		cmd.Printf("   ⚠️  Could not check permissions, continuing: %v\n\n", err)
+		return
+	}
+
+	for _, feature := range []preflightFeature{
+		{"Commit statuses", "statuses: write", &features.Statuses, access.Statuses},
+		{"PR comments", "pull-requests: write", &features.Comments, access.Comments},
+		{"Workflow artifacts", "actions: read", &features.Artifacts, access.Artifacts},
+	} {
+		if !*feature.enabled {
+			continue
+		}
+		switch feature.access {
+		case github.AccessGranted:
+			cmd.Printf("   ✅ %s\n", feature.name)
+		case github.AccessDenied:
+			*feature.enabled = false
+			cmd.Printf("   ⏭️  %s: skipped, the token lacks %s\n", feature.name, feature.permission)
+		default:
+			cmd.Printf("   ⚠️  %s: could not be verified, trying anyway\n", feature.name)
+		}
+	}
+	cmd.Println()
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// fakeTokenProber returns fixed probe results
+type fakeTokenProber struct {
+	access *github.TokenAccess
+	err    error
+	calls  int
+}
+
+func (f *fakeTokenProber) ProbeTokenAccess(context.Context, string, string, int) (*github.TokenAccess, error) {
+	f.calls++
+	return f.access, f.err
+}
+
+func TestPreflightGitHub(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo", Preflight: true}}
+	run := func(prober *fakeTokenProber, features *preflightFeatures) string {
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		preflightGitHub(context.Background(), cmd, cfg, prober, 7, features)
+		return out.String()
+	}
+
+	t.Run("denied features are skipped", func(t *testing.T) {
+		prober := &fakeTokenProber{access: &github.TokenAccess{
+			Statuses: github.AccessGranted, Comments: github.AccessDenied, Artifacts: github.AccessUnknown,
+		}}
+		features := &preflightFeatures{Statuses: true, Comments: true, Artifacts: true}
+		output := run(prober, features)
+
+		assert.Equal(t, &preflightFeatures{Statuses: true, Artifacts: true}, features)
+		assert.Contains(t, output, "✅ Commit statuses")
+		assert.Contains(t, output, "PR comments: skipped, the token lacks pull-requests: write")
+		assert.Contains(t, output, "Workflow artifacts: could not be verified")
+	})
+
+	t.Run("only requested features are reported", func(t *testing.T) {
+		prober := &fakeTokenProber{access: &github.TokenAccess{Statuses: github.AccessDenied, Comments: github.AccessDenied}}
+		features := &preflightFeatures{Statuses: true}
+		output := run(prober, features)
+
+		assert.False(t, features.Statuses)
+		assert.NotContains(t, output, "PR comments")
+	})
+
+	t.Run("probe failures leave features on", func(t *testing.T) {
+		features := &preflightFeatures{Statuses: true, Comments: true}
+		output := run(&fakeTokenProber{err: errors.New("connection refused")}, features)
+
+		assert.Equal(t, &preflightFeatures{Statuses: true, Comments: true}, features)
+		assert.Contains(t, output, "Could not check permissions")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg.GitHub.Preflight = false
+		defer func() { cfg.GitHub.Preflight = true }()
+		prober := &fakeTokenProber{}
+		assert.Empty(t, run(prober, &preflightFeatures{Statuses: true}))
+		assert.Zero(t, prober.calls)
+	})
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/publicbadge"
+)
+
+// publicBadgeSkipReason explains why a run does not publish the public badge, empty when it does.
+// Only main branch runs publish it, so pull requests cannot change the public badge.
+func publicBadgeSkipReason(cfg *config.Config, branch string, offline bool) string {
+	switch {
+	case offline:
+		return "offline mode"
+	case cfg.IsPullRequestContext():
+		return "pull request"
+	}
+	for _, pattern := range getMainBranches() {
+		if branchmatch.Match(pattern, branch) {
+			return ""
+		}
+	}
+	return "not a main branch"
+}
+
+// publishPublicBadge publishes the badge SVG and its shields.io endpoint JSON to the public
+// location of a private repository; nothing else of the run leaves the private site
+func publishPublicBadge(ctx context.Context, cmd *cobra.Command, cfg *config.Config, apiBaseURL, badgeFile string, percentage float64) error {
+	svg, err := os.ReadFile(badgeFile) //nolint:gosec // badgeFile is the badge written by this run
+	if err != nil {
+		return fmt.Errorf("failed to read badge: %w", err)
+	}
+	endpoint, err := badge.New().GenerateEndpoint(percentage, badge.WithLabel(cfg.Badge.Label), badge.WithStyle(cfg.Badge.Style))
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := cfg.NewHTTPClient(cfg.GitHub.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+	name := cfg.PublicBadge.Name
+	published, err := publicbadge.Publish(ctx, publicbadge.Options{
+		Target:     cfg.PublicBadge.Target,
+		Token:      cfg.PublicBadge.Token,
+		GistID:     cfg.PublicBadge.GistID,
+		Repository: cfg.PublicBadge.Repository,
+		Branch:     cfg.PublicBadge.Branch,
+		Dir:        strings.Trim(cfg.PublicBadge.Dir, "/"),
+		URL:        cfg.PublicBadge.URL,
+		APIBaseURL: apiBaseURL,
+		Client:     httpClient,
+		UserAgent:  "go-coverage/1.0",
+	}, []publicbadge.File{
+		{Name: name + ".svg", Content: svg, ContentType: publicbadge.ContentTypeSVG},
+		{Name: name + ".json", Content: endpoint, ContentType: publicbadge.ContentTypeJSON},
+	})
+	for _, file := range published {
+		if file.Unchanged {
+			cmd.Printf("   ✅ %s unchanged: %s\n", file.Name, file.URL)
+		} else {
+			cmd.Printf("   ✅ Published %s: %s\n", file.Name, file.URL)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(published) == 2 {
+		cmd.Printf("   🔗 Endpoint badge: https://img.shields.io/endpoint?url=%s\n", url.QueryEscape(published[1].URL))
+	}
+	return nil
+}
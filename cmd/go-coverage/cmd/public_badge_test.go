@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/publicbadge"
+)
+
+func TestPublicBadgeSkipReason(t *testing.T) {
+	t.Setenv("MAIN_BRANCHES", "main,release/*")
+	cfg := &config.Config{}
+
+	assert.Empty(t, publicBadgeSkipReason(cfg, "main", false))
+	assert.Empty(t, publicBadgeSkipReason(cfg, "release/1.x", false))
+	assert.Equal(t, "not a main branch", publicBadgeSkipReason(cfg, "feature", false))
+	assert.Equal(t, "offline mode", publicBadgeSkipReason(cfg, "main", true))
+
+	cfg.GitHub = config.GitHubConfig{Owner: "owner", Repository: "repo", CommitSHA: "abc", PullRequest: 7}
+	assert.Equal(t, "pull request", publicBadgeSkipReason(cfg, "main", false))
+}
+
+func TestPublishPublicBadge(t *testing.T) {
+	var mu sync.Mutex
+	uploads := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, _ := io.ReadAll(r.Body)
+		uploads[r.URL.Path] = string(data)
+	}))
+	defer server.Close()
+
+	badgeFile := filepath.Join(t.TempDir(), "coverage.svg")
+	require.NoError(t, os.WriteFile(badgeFile, []byte("<svg>85.0%</svg>"), 0o600))
+
+	cfg := &config.Config{
+		Badge: config.BadgeConfig{Label: "coverage", Style: "flat"},
+		PublicBadge: config.PublicBadgeConfig{
+			Target: publicbadge.TargetHTTP,
+			URL:    server.URL + "/badges",
+			Name:   "acme-api",
+		},
+	}
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	require.NoError(t, publishPublicBadge(context.Background(), cmd, cfg, server.URL, badgeFile, 85))
+	assert.Equal(t, "<svg>85.0%</svg>", uploads["/badges/acme-api.svg"])
+	assert.JSONEq(t, `{"schemaVersion":1,"label":"coverage","message":"85.0%","color":"3fb950","style":"flat"}`, uploads["/badges/acme-api.json"])
+	assert.Len(t, uploads, 2, "only the badge is published")
+	assert.Contains(t, out.String(), "Endpoint badge: https://img.shields.io/endpoint?url=")
+
+	require.Error(t, publishPublicBadge(context.Background(), cmd, cfg, server.URL, filepath.Join(t.TempDir(), "missing.svg"), 85))
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/publish"
+)
+
+// ErrUnsupportedPublishCheckFormat indicates an unknown publish-check output format
+var ErrUnsupportedPublishCheckFormat = errors.New("unsupported publish-check format")
+
+// Output formats supported by the publish-check command
+const (
+	publishCheckFormatText = "text"
+	publishCheckFormatJSON = "json"
+)
+
+// newPublishCheckCmd creates the publish-check command
+func (c *Commands) newPublishCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish-check",
+		Short: "Check whether a generated site differs from the published one",
+		Long: `Compare a newly generated coverage site and badge with the published versions by
+content hash, ignoring generation timestamps and commit SHAs that change on every run.
+When nothing else changed the deployment can be skipped, which avoids a Pages build
+and a gh-pages commit for pull requests that do not touch Go code.
+
+Without --published the site directory must be a git checkout of the pages branch with
+the new site copied in and staged; the staged changes are compared against its HEAD.
+With --published the two directories are compared file by file.`,
+		Example: `  # In the gh-pages checkout after "git add ."
+  go-coverage publish-check --format json | jq -e '.changed'
+
+  go-coverage publish-check --site coverage --published /tmp/gh-pages --ignore 'data/'`,
+		RunE: c.runPublishCheck,
+	}
+
+	cmd.Flags().String("site", ".", "Directory containing the generated site")
+	cmd.Flags().String("published", "", "Directory containing the published site (defaults to the HEAD commit of --site)")
+	cmd.Flags().StringSlice("ignore", nil, "Path patterns whose changes never require publishing (repeatable)")
+	cmd.Flags().String("format", publishCheckFormatText, "Output format (text or json)")
+	cmd.Flags().StringP("output", "o", "", "Write the result to a file instead of the console")
+	return cmd
+}
+
+// runPublishCheck executes the publish-check command
+func (c *Commands) runPublishCheck(cmd *cobra.Command, _ []string) error {
+	site, _ := cmd.Flags().GetString("site")
+	published, _ := cmd.Flags().GetString("published")
+	ignore, _ := cmd.Flags().GetStringSlice("ignore")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if format != publishCheckFormatText && format != publishCheckFormatJSON {
+		return fmt.Errorf("%w: %q (expected text or json)", ErrUnsupportedPublishCheckFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Pages without a commit link only show the short SHA, which is not recognizable on its own
+	opts := publish.Options{Ignore: ignore}
+	if sha := cfg.GitHub.CommitSHA; sha != "" {
+		opts.Volatile = append(opts.Volatile, sha, shortSHA(sha))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var result *publish.Result
+	if published != "" {
+		result, err = publish.CompareDirs(site, published, opts)
+	} else {
+		result, err = publish.CompareStaged(ctx, site, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	var output string
+	if format == publishCheckFormatJSON {
+		data, marshalErr := json.MarshalIndent(result, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal publish check: %w", marshalErr)
+		}
+		output = string(data) + "\n"
+	} else {
+		output = formatPublishCheck(result)
+	}
+
+	if outputPath == "" {
+		cmd.Print(output)
+		return nil
+	}
+	if err = os.WriteFile(outputPath, []byte(output), cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write publish check: %w", err)
+	}
+	cmd.Printf("Publish check written to %s\n", outputPath)
+	return nil
+}
+
+// formatPublishCheck renders a publish check result for the console
+func formatPublishCheck(result *publish.Result) string {
+	if !result.Changed {
+		return "✅ Site unchanged apart from timestamps and commit SHAs, publishing can be skipped\n"
+	}
+	if len(result.Files) == 0 {
+		return "🚀 Nothing has been published yet, publishing is required\n"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🚀 %d file(s) changed, publishing is required\n", len(result.Files))
+	for _, file := range result.Files {
+		fmt.Fprintf(&sb, "   %-8s %s\n", file.Status, file.Path)
+	}
+	return sb.String()
+}
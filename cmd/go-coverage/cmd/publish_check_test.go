@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/publish"
+)
+
+func TestPublishCheckCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_SHA", "0123456789abcdef0123456789abcdef01234567")
+
+	published := t.TempDir()
+	site := t.TempDir()
+	write := func(dir, name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	write(published, "coverage.svg", "<svg>85.2%</svg>")
+	write(published, "index.html", `<a href="commit/fedcba9876543210fedcba9876543210fedcba98">fedcba9</a> 2026-01-02 03:04:05 UTC`)
+	write(site, "coverage.svg", "<svg>85.2%</svg>")
+	write(site, "index.html", `<a href="commit/0123456789abcdef0123456789abcdef01234567">0123456</a> 2026-05-06 07:08:09 UTC`)
+
+	t.Run("unchanged", func(t *testing.T) {
+		output, err := executeCommand(t, "publish-check", "--site", site, "--published", published)
+		require.NoError(t, err)
+		assert.Contains(t, output, "Site unchanged")
+	})
+
+	t.Run("changed json", func(t *testing.T) {
+		write(site, "coverage.svg", "<svg>86.0%</svg>")
+		output, err := executeCommand(t, "publish-check", "--site", site, "--published", published, "--format", "json")
+		require.NoError(t, err)
+
+		var result publish.Result
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.True(t, result.Changed)
+		assert.Equal(t, []publish.Change{{Path: "coverage.svg", Status: publish.StatusModified}}, result.Files)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := executeCommand(t, "publish-check", "--site", site, "--published", published, "--format", "xml")
+		require.ErrorIs(t, err, ErrUnsupportedPublishCheckFormat)
+	})
+}
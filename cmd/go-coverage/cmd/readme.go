@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ErrReadmeMarkersNotFound is returned when the README has no stats section to rewrite
+var ErrReadmeMarkersNotFound = errors.New("README has no coverage stats markers")
+
+const (
+	// readmeStartMarker and readmeEndMarker delimit the section of the README the stats are written to
+	readmeStartMarker = "<!-- go-coverage:stats:start -->"
+	readmeEndMarker   = "<!-- go-coverage:stats:end -->"
+	// readmeDateFormat is the format of the last-updated date in the stats section
+	readmeDateFormat = "2006-01-02"
+	// readmeCommitMessage is the message of the commit --commit creates
+	readmeCommitMessage = "docs: update coverage stats [skip ci]"
+)
+
+// readmeUpdatedDate finds the last-updated date of a stats section
+var readmeUpdatedDate = regexp.MustCompile(`\| (\d{4}-\d{2}-\d{2}) \|\n$`)
+
+// readmeStats are the values written to the stats section
+type readmeStats struct {
+	Coverage float64
+	Previous *history.Entry // The last earlier run of the branch, nil without history
+	Updated  string
+}
+
+// newReadmeCmd creates the readme command
+func (c *Commands) newReadmeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "readme",
+		Short: "Keep coverage stats in the README",
+		Long: `Keep a text summary of the coverage in the README, for repositories that prefer
+stats over badges.`,
+	}
+
+	cmd.AddCommand(c.newReadmeUpdateCmd())
+	return cmd
+}
+
+// newReadmeUpdateCmd creates the readme update command
+func (c *Commands) newReadmeUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Rewrite the coverage stats section of the README",
+		Long: `Rewrite the section of the README between the markers
+
+  ` + readmeStartMarker + `
+  ` + readmeEndMarker + `
+
+with a table of the current coverage, its trend since the last run of the branch in the
+history, the quality grade and the date the stats last changed. Everything outside the
+markers is left untouched, and the file is not written when only the date would change.
+
+With --commit, the README is committed so the pipeline only has to push it.`,
+		Example: `  go-coverage readme update
+  go-coverage readme update --file docs/README.md --commit`,
+		RunE: c.runReadmeUpdate,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "Coverage profile (defaults to the configured input file)")
+	cmd.Flags().String("file", "README.md", "README to update")
+	cmd.Flags().StringP("branch", "b", "", "Branch the trend is measured on (default: the current or default branch)")
+	cmd.Flags().Bool("commit", false, "Commit the updated README")
+	addDryRunFlag(cmd, "Print the stats section without writing the README")
+	return cmd
+}
+
+// runReadmeUpdate executes the readme update command
+func (c *Commands) runReadmeUpdate(cmd *cobra.Command, _ []string) error {
+	inputFile, _ := cmd.Flags().GetString("input")
+	readmePath, _ := cmd.Flags().GetString("file")
+	branch, _ := cmd.Flags().GetString("branch")
+	commit, _ := cmd.Flags().GetBool("commit")
+	dryRun, _ := cmd.Flags().GetBool(flagNameDryRun)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if inputFile == "" {
+		inputFile = cfg.Coverage.InputFile
+	}
+	if branch == "" {
+		branch = getDefaultBranch()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	readme, err := os.ReadFile(readmePath) //nolint:gosec // path chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", readmePath, err)
+	}
+
+	p := parser.NewWithConfig(&parser.Config{
+		ExcludePaths:     cfg.Coverage.ExcludePaths,
+		ExcludeFiles:     cfg.Coverage.ExcludeFiles,
+		ExcludeGenerated: cfg.Coverage.ExcludeGenerated,
+		ExcludePresets:   cfg.Coverage.ExcludePresets,
+		Limits:           cfg.ParserLimits(),
+		ModuleRewrites:   cfg.ModuleRewrites(),
+		ModuleRoot:       cfg.Coverage.ModuleRoot,
+	})
+	coverage, err := p.ParseFile(ctx, inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse coverage: %w", err)
+	}
+
+	stats := readmeStats{Coverage: coverage.Percentage, Updated: time.Now().UTC().Format(readmeDateFormat)}
+	if cfg.History.Enabled {
+		if stats.Previous, err = readmePreviousRun(ctx, cfg, branch); err != nil {
+			cmd.Printf("⚠️  Trend unavailable: %v\n", err)
+		}
+	}
+
+	updated, changed, err := updateReadmeStats(string(readme), stats)
+	if err != nil {
+		return fmt.Errorf("%w in %s", err, readmePath)
+	}
+	if dryRun {
+		cmd.Print(renderReadmeStats(stats))
+		return nil
+	}
+	if !changed {
+		cmd.Printf("Coverage stats in %s are up to date\n", readmePath)
+		return nil
+	}
+
+	info, err := os.Stat(readmePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", readmePath, err)
+	}
+	if err = os.WriteFile(readmePath, []byte(updated), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", readmePath, err)
+	}
+	cmd.Printf("📝 Updated coverage stats in %s\n", readmePath)
+
+	if !commit {
+		return nil
+	}
+	if err = commitReadme(ctx, readmePath); err != nil {
+		return err
+	}
+	cmd.Printf("Committed %s\n", readmePath)
+	return nil
+}
+
+// readmePreviousRun returns the latest run of the branch in the history other than the current
+// commit, which the pipeline may already have recorded
+func readmePreviousRun(ctx context.Context, cfg *config.Config, branch string) (*history.Entry, error) {
+	storagePath, err := cfg.ResolveHistoryStoragePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve history storage path: %w", err)
+	}
+	tracker := history.NewWithConfig(&history.Config{
+		StoragePath:    storagePath,
+		Repository:     cfg.RepositorySlug(),
+		RetentionDays:  cfg.History.RetentionDays,
+		MaxEntries:     cfg.History.MaxEntries,
+		AutoCleanup:    false,
+		MetricsEnabled: false,
+	})
+	trend, err := tracker.GetTrend(ctx,
+		history.WithTrendBranch(branch),
+		history.WithTrendDays(cfg.History.RetentionDays),
+		history.WithMaxDataPoints(2))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coverage history: %w", err)
+	}
+	for i := range trend.Entries {
+		entry := &trend.Entries[i]
+		if entry.Coverage != nil && (cfg.GitHub.CommitSHA == "" || entry.CommitSHA != cfg.GitHub.CommitSHA) {
+			return entry, nil
+		}
+	}
+	return nil, nil //nolint:nilnil // no earlier run is not an error
+}
+
+// updateReadmeStats replaces the stats section of the README. The last-updated date is kept
+// when nothing else changed, so the README only changes with the stats.
+func updateReadmeStats(readme string, stats readmeStats) (string, bool, error) {
+	start := strings.Index(readme, readmeStartMarker)
+	end := strings.Index(readme, readmeEndMarker)
+	if start < 0 || end < start {
+		return "", false, fmt.Errorf("%w: add %s and %s", ErrReadmeMarkersNotFound, readmeStartMarker, readmeEndMarker)
+	}
+	current := readme[start+len(readmeStartMarker) : end]
+
+	if match := readmeUpdatedDate.FindStringSubmatch(current); match != nil {
+		unchanged := stats
+		unchanged.Updated = match[1]
+		if "\n"+renderReadmeStats(unchanged) == current {
+			return readme, false, nil
+		}
+	}
+	updated := readme[:start+len(readmeStartMarker)] + "\n" + renderReadmeStats(stats) + readme[end:]
+	return updated, updated != readme, nil
+}
+
+// renderReadmeStats renders the stats as a one-row Markdown table
+func renderReadmeStats(stats readmeStats) string {
+	var b strings.Builder
+	b.WriteString("| Coverage | Trend | Grade | Last updated |\n")
+	b.WriteString("|---------:|-------|:-----:|--------------|\n")
+	fmt.Fprintf(&b, "| %.2f%% | %s | %s | %s |\n",
+		stats.Coverage, readmeTrend(stats), calculateQualityGrade(stats.Coverage), stats.Updated)
+	return b.String()
+}
+
+// readmeTrend describes the change since the previous run
+func readmeTrend(stats readmeStats) string {
+	if stats.Previous == nil {
+		return "—"
+	}
+	change := stats.Coverage - stats.Previous.Coverage.Percentage
+	since := ""
+	if stats.Previous.CommitSHA != "" {
+		since = fmt.Sprintf(" since `%s`", shortSHA(stats.Previous.CommitSHA))
+	}
+	switch {
+	case math.Abs(change) < 0.005:
+		return "→ stable" + since
+	case change > 0:
+		return fmt.Sprintf("↑ +%.2f%%%s", change, since)
+	default:
+		return fmt.Sprintf("↓ %.2f%%%s", change, since)
+	}
+}
+
+// commitReadme commits the README, leaving any other staged change out of the commit
+func commitReadme(ctx context.Context, readmePath string) error {
+	if strings.HasPrefix(readmePath, "-") {
+		readmePath = "./" + readmePath
+	}
+	var stderr bytes.Buffer
+	add := exec.CommandContext(ctx, "git", "add", "--", readmePath) //nolint:gosec // path cannot be an option
+	add.Stderr = &stderr
+	if err := add.Run(); err != nil {
+		return fmt.Errorf("failed to stage %s: %w: %s", readmePath, err, strings.TrimSpace(stderr.String()))
+	}
+	stderr.Reset()
+	commit := exec.CommandContext(ctx, "git", "commit", "-m", readmeCommitMessage, "--", readmePath) //nolint:gosec // path cannot be an option
+	commit.Stderr = &stderr
+	if err := commit.Run(); err != nil {
+		return fmt.Errorf("failed to commit %s: %w: %s", readmePath, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestUpdateReadmeStats(t *testing.T) {
+	previous := &history.Entry{CommitSHA: "abcdef0123456789", Coverage: &parser.CoverageData{Percentage: 80}}
+	stats := readmeStats{Coverage: 85.5, Previous: previous, Updated: "2026-10-17"}
+	readme := "# Project\n\n" + readmeStartMarker + "\nold stats\n" + readmeEndMarker + "\n\n## Usage\n"
+
+	updated, changed, err := updateReadmeStats(readme, stats)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "# Project\n\n"+readmeStartMarker+"\n"+
+		"| Coverage | Trend | Grade | Last updated |\n"+
+		"|---------:|-------|:-----:|--------------|\n"+
+		"| 85.50% | ↑ +5.50% since `abcdef0` | B+ | 2026-10-17 |\n"+
+		readmeEndMarker+"\n\n## Usage\n", updated)
+
+	// A later run with the same stats keeps the date and leaves the README alone
+	stats.Updated = "2026-10-18"
+	again, changed, err := updateReadmeStats(updated, stats)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, updated, again)
+
+	// Changed stats move the date
+	stats.Coverage = 79
+	again, changed, err = updateReadmeStats(updated, stats)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, again, "| 79.00% | ↓ -1.00% since `abcdef0` | C | 2026-10-18 |")
+
+	_, _, err = updateReadmeStats("# Project\n", stats)
+	require.ErrorIs(t, err, ErrReadmeMarkersNotFound)
+}
+
+func TestReadmeTrend(t *testing.T) {
+	previous := &history.Entry{Coverage: &parser.CoverageData{Percentage: 80}}
+	assert.Equal(t, "—", readmeTrend(readmeStats{Coverage: 80}))
+	assert.Equal(t, "→ stable", readmeTrend(readmeStats{Coverage: 80.001, Previous: previous}))
+	assert.Equal(t, "↑ +1.25%", readmeTrend(readmeStats{Coverage: 81.25, Previous: previous}))
+}
+
+func TestReadmeUpdateCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_HISTORY_ENABLED", "false")
+	dir := initHookRepo(t)
+
+	readmePath := filepath.Join(dir, "README.md")
+	require.NoError(t, os.WriteFile(readmePath, []byte("# Project\n\n"+readmeStartMarker+"\n"+readmeEndMarker+"\n"), 0o600))
+	profile := filepath.Join(t.TempDir(), "coverage.txt")
+	require.NoError(t, os.WriteFile(profile, []byte("mode: set\nexample.com/app/app.go:3.20,5.2 3 1\nexample.com/app/app.go:6.20,8.2 1 0\n"), 0o600))
+
+	t.Run("dry run", func(t *testing.T) {
+		output, err := executeCommand(t, "readme", "update", "-i", profile, "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "| 75.00% | — | C |")
+
+		data, err := os.ReadFile(readmePath) //nolint:gosec // test file path
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "75.00%")
+	})
+
+	t.Run("update and commit", func(t *testing.T) {
+		require.NoError(t, exec.Command("git", "add", "README.md").Run())              //nolint:noctx // test setup
+		require.NoError(t, exec.Command("git", "commit", "-q", "-m", "initial").Run()) //nolint:noctx // test setup
+		output, err := executeCommand(t, "readme", "update", "-i", profile, "--commit")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Updated coverage stats in README.md")
+		assert.Contains(t, output, "Committed README.md")
+
+		log, err := exec.Command("git", "log", "-1", "--format=%s").Output() //nolint:noctx // test check
+		require.NoError(t, err)
+		assert.Equal(t, readmeCommitMessage, strings.TrimSpace(string(log)))
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		output, err := executeCommand(t, "readme", "update", "-i", profile, "--commit")
+		require.NoError(t, err)
+		assert.Contains(t, output, "are up to date")
+	})
+
+	t.Run("missing markers", func(t *testing.T) {
+		other := filepath.Join(dir, "OTHER.md")
+		require.NoError(t, os.WriteFile(other, []byte("# Other\n"), 0o600))
+		_, err := executeCommand(t, "readme", "update", "-i", profile, "--file", other)
+		require.ErrorIs(t, err, ErrReadmeMarkersNotFound)
+	})
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/codeowners"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// regressionIssueMarker identifies the issue body written for a sustained regression
+const regressionIssueMarker = "[//]: # (go-coverage-regression)"
+
+// Limits of the regression issue: runs drawn in the trend chart, packages listed and users
+// assigned, which GitHub caps at 10 per issue
+const (
+	regressionChartRuns   = 20
+	regressionMaxPackages = 5
+	regressionMaxAssignee = 10
+)
+
+// regression is coverage of a branch staying below the threshold or declining run after run
+type regression struct {
+	Reason   string
+	Baseline *history.Entry // Last run before the regression, nil when the history starts with it
+}
+
+// regressionPackage is a package that lost coverage during a regression
+type regressionPackage struct {
+	Name    string
+	Before  float64
+	After   float64
+	Missed  int
+	Owners  []string
+	HasBase bool
+}
+
+// regressionHistory loads the earlier runs of the branch, newest first, enough for the trend
+// chart and for detecting the regression
+func regressionHistory(ctx context.Context, cfg *config.Config, branch string) ([]history.Entry, error) {
+	storagePath, err := cfg.ResolveHistoryStoragePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve history storage path: %w", err)
+	}
+	tracker := history.NewWithConfig(&history.Config{
+		StoragePath:    storagePath,
+		Repository:     cfg.RepositorySlug(),
+		RetentionDays:  cfg.History.RetentionDays,
+		MaxEntries:     cfg.History.MaxEntries,
+		AutoCleanup:    false,
+		MetricsEnabled: false,
+	})
+	trend, err := tracker.GetTrend(ctx,
+		history.WithTrendBranch(branch),
+		history.WithTrendDays(cfg.History.RetentionDays),
+		history.WithMaxDataPoints(max(regressionChartRuns, cfg.GitHub.RegressionIssueRuns)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coverage history: %w", err)
+	}
+	return trend.Entries, nil
+}
+
+// detectRegression reports whether the current run extends a regression of at least runs runs,
+// from the runs before it, newest first
+func detectRegression(threshold float64, runs int, current float64, previous []history.Entry) (*regression, bool) {
+	values := make([]float64, 0, len(previous))
+	for _, entry := range previous {
+		values = append(values, entry.Coverage.Percentage)
+	}
+	baseline := func(index int) *history.Entry {
+		if index < len(previous) {
+			return &previous[index]
+		}
+		return nil
+	}
+
+	if declines := policy.ConsecutiveDeclines(current, values); declines >= runs {
+		return &regression{
+			Reason:   fmt.Sprintf("Coverage declined %d runs in a row, from %.2f%% to %.2f%%.", declines, values[declines-1], current),
+			Baseline: baseline(declines - 1),
+		}, true
+	}
+
+	below := 0
+	for _, value := range append([]float64{current}, values...) {
+		if threshold <= 0 || value >= threshold {
+			break
+		}
+		below++
+	}
+	if below >= runs {
+		return &regression{
+			Reason:   fmt.Sprintf("Coverage has been below the %.2f%% threshold for %d runs in a row.", threshold, below),
+			Baseline: baseline(below - 1),
+		}, true
+	}
+	return nil, false
+}
+
+// regressionOffenders returns the packages that lost the most coverage since the baseline or,
+// without a baseline or a package that lost coverage, the packages missing the most statements
+func regressionOffenders(cfg *config.Config, coverage *parser.CoverageData, baseline *history.Entry, owners *codeowners.File) []regressionPackage {
+	var packages []regressionPackage
+	for name, pkg := range coverage.Packages {
+		offender := regressionPackage{Name: name, After: pkg.Percentage, Missed: pkg.TotalLines - pkg.CoveredLines}
+		if baseline != nil {
+			if before, ok := baseline.Coverage.Packages[name]; ok && before != nil {
+				offender.Before, offender.HasBase = before.Percentage, true
+			}
+		}
+		packages = append(packages, offender)
+	}
+
+	dropped := slices.DeleteFunc(slices.Clone(packages), func(pkg regressionPackage) bool {
+		return !pkg.HasBase || pkg.Before-pkg.After < 0.05
+	})
+	if len(dropped) > 0 {
+		packages = dropped
+		slices.SortFunc(packages, func(a, b regressionPackage) int {
+			if drop := (b.Before - b.After) - (a.Before - a.After); math.Abs(drop) > 1e-9 {
+				if drop > 0 {
+					return 1
+				}
+				return -1
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	} else {
+		packages = slices.DeleteFunc(packages, func(pkg regressionPackage) bool { return pkg.Missed == 0 })
+		slices.SortFunc(packages, func(a, b regressionPackage) int {
+			if a.Missed != b.Missed {
+				return b.Missed - a.Missed
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	if len(packages) > regressionMaxPackages {
+		packages = packages[:regressionMaxPackages]
+	}
+
+	if owners != nil {
+		for i := range packages {
+			packages[i].Owners = packageOwners(cfg, coverage.Packages[packages[i].Name], owners)
+		}
+	}
+	return packages
+}
+
+// packageOwners returns the CODEOWNERS owners of the files of a package, in the order found
+func packageOwners(cfg *config.Config, pkg *parser.PackageCoverage, owners *codeowners.File) []string {
+	files := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		files = append(files, name)
+	}
+	slices.Sort(files)
+
+	var result []string
+	for _, file := range files {
+		for _, owner := range owners.Owners(urlutil.CleanModulePathWithRepo(file, cfg.GitHub.Repository)) {
+			if !slices.Contains(result, owner) {
+				result = append(result, owner)
+			}
+		}
+	}
+	return result
+}
+
+// renderRegressionIssue renders the body of the regression issue: the reason, a trend chart of
+// the last runs and the packages that lost the most coverage
+func renderRegressionIssue(cfg *config.Config, branch string, current float64, previous []history.Entry,
+	found *regression, offenders []regressionPackage,
+) string {
+	var b strings.Builder
+	b.WriteString(regressionIssueMarker + "\n\n")
+	fmt.Fprintf(&b, "## 📉 Sustained coverage regression on `%s`\n\n", branch)
+	b.WriteString(found.Reason + "\n\n")
+
+	b.WriteString("| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| **Current** | %.2f%% (`%s`) |\n", current, shortSHA(cfg.GitHub.CommitSHA))
+	if found.Baseline != nil {
+		fmt.Fprintf(&b, "| **Before the regression** | %.2f%% (`%s`) |\n", found.Baseline.Coverage.Percentage, shortSHA(found.Baseline.CommitSHA))
+	}
+	if cfg.Coverage.Threshold > 0 {
+		fmt.Fprintf(&b, "| **Threshold** | %.2f%% |\n", cfg.Coverage.Threshold)
+	}
+
+	b.WriteString("\n### Trend\n\n")
+	b.WriteString(renderRegressionChart(cfg, branch, current, previous))
+
+	if len(offenders) > 0 {
+		b.WriteString("\n### Top offending packages\n\n")
+		b.WriteString("| Package | Before | Now | Missed statements | Owners |\n|---------|--------|-----|-------------------|--------|\n")
+		for _, pkg := range offenders {
+			before := "–"
+			if pkg.HasBase {
+				before = fmt.Sprintf("%.2f%%", pkg.Before)
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %.2f%% | %d | %s |\n", urlutil.CleanModulePathWithRepo(pkg.Name, cfg.GitHub.Repository),
+				before, pkg.After, pkg.Missed, strings.Join(pkg.Owners, " "))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nThis issue is updated by go-coverage on every run of `%s` and closed once coverage recovers.\n", branch)
+	return b.String()
+}
+
+// renderRegressionChart draws coverage over the last runs, oldest first, as a Mermaid chart,
+// with the threshold as a second line
+func renderRegressionChart(cfg *config.Config, branch string, current float64, previous []history.Entry) string {
+	runs := previous[:min(len(previous), regressionChartRuns-1)]
+	labels := make([]string, 0, len(runs)+1)
+	values := make([]float64, 0, len(runs)+1)
+	for i := len(runs) - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%q", shortSHA(runs[i].CommitSHA)))
+		values = append(values, runs[i].Coverage.Percentage)
+	}
+	labels = append(labels, fmt.Sprintf("%q", shortSHA(cfg.GitHub.CommitSHA)))
+	values = append(values, current)
+
+	lowest, highest := slices.Min(values), slices.Max(values)
+	if cfg.Coverage.Threshold > 0 {
+		lowest, highest = min(lowest, cfg.Coverage.Threshold), max(highest, cfg.Coverage.Threshold)
+	}
+	formatted := make([]string, 0, len(values))
+	thresholds := make([]string, 0, len(values))
+	for _, value := range values {
+		formatted = append(formatted, fmt.Sprintf("%.2f", value))
+		thresholds = append(thresholds, fmt.Sprintf("%.2f", cfg.Coverage.Threshold))
+	}
+
+	var b strings.Builder
+	b.WriteString("```mermaid\nxychart-beta\n")
+	fmt.Fprintf(&b, "    title \"Coverage of %s, last %d runs\"\n", branch, len(values))
+	fmt.Fprintf(&b, "    x-axis [%s]\n", strings.Join(labels, ", "))
+	fmt.Fprintf(&b, "    y-axis \"Coverage (%%)\" %.0f --> %.0f\n", math.Max(0, math.Floor(lowest-1)), math.Min(100, math.Ceil(highest+1)))
+	fmt.Fprintf(&b, "    line [%s]\n", strings.Join(formatted, ", "))
+	if cfg.Coverage.Threshold > 0 {
+		fmt.Fprintf(&b, "    line [%s]\n", strings.Join(thresholds, ", "))
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// updateRegressionIssue keeps one open issue about a sustained regression of the branch: it is
+// opened and assigned to the code owners of the offending packages when the regression starts,
+// refreshed while it lasts and closed once coverage recovers. previous holds the earlier runs of
+// the branch, newest first.
+func updateRegressionIssue(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client,
+	branch string, coverage *parser.CoverageData, previous []history.Entry,
+) error {
+	owner, repo, title := cfg.GitHub.Owner, cfg.GitHub.Repository, cfg.GitHub.RegressionIssueTitle
+	previous = slices.DeleteFunc(slices.Clone(previous), func(entry history.Entry) bool {
+		return entry.Coverage == nil || (cfg.GitHub.CommitSHA != "" && entry.CommitSHA == cfg.GitHub.CommitSHA)
+	})
+	found, regressing := detectRegression(cfg.Coverage.Threshold, cfg.GitHub.RegressionIssueRuns, coverage.Percentage, previous)
+
+	thread, err := client.FindThread(ctx, owner, repo, github.ThreadIssue, title)
+	if err != nil && !errors.Is(err, github.ErrThreadNotFound) {
+		return err
+	}
+	existing := err == nil
+
+	if !regressing {
+		if !existing {
+			cmd.Printf("   ✅ No sustained coverage regression on %s\n", branch)
+			return nil
+		}
+		if err = client.AddThreadComment(ctx, thread, fmt.Sprintf("✅ Coverage recovered to %.2f%% at `%s`; closing this issue.",
+			coverage.Percentage, shortSHA(cfg.GitHub.CommitSHA))); err != nil {
+			return err
+		}
+		if err = client.CloseIssue(ctx, thread.ID); err != nil {
+			return err
+		}
+		cmd.Printf("   ✅ Coverage recovered, closed regression issue #%d: %s\n", thread.Number, thread.URL)
+		return nil
+	}
+
+	var owners *codeowners.File
+	if root, rootErr := cfg.GetRepositoryRoot(); rootErr == nil {
+		if owners, err = codeowners.Load(root); err != nil && !errors.Is(err, codeowners.ErrNotFound) {
+			cmd.Printf("   ⚠️  Failed to read CODEOWNERS: %v\n", err)
+		}
+	}
+	offenders := regressionOffenders(cfg, coverage, found.Baseline, owners)
+	body := renderRegressionIssue(cfg, branch, coverage.Percentage, previous, found, offenders)
+
+	if existing {
+		if err = client.UpdateThreadBody(ctx, thread, body); err != nil {
+			return err
+		}
+		cmd.Printf("   📉 Updated regression issue #%d: %s\n", thread.Number, thread.URL)
+		return nil
+	}
+
+	if thread, err = client.CreateThread(ctx, owner, repo, github.ThreadIssue, "", title, body); err != nil {
+		return err
+	}
+	cmd.Printf("   📉 Opened regression issue #%d: %s\n", thread.Number, thread.URL)
+
+	var assignees []string
+	for _, pkg := range offenders {
+		for _, login := range codeowners.Users(pkg.Owners) {
+			if !slices.Contains(assignees, login) && len(assignees) < regressionMaxAssignee {
+				assignees = append(assignees, login)
+			}
+		}
+	}
+	if len(assignees) > 0 {
+		if err = client.AddAssignees(ctx, owner, repo, thread.Number, assignees); err != nil {
+			cmd.Printf("   ⚠️  Failed to assign the regression issue: %v\n", err)
+		} else {
+			cmd.Printf("   👤 Assigned to %s\n", strings.Join(assignees, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/codeowners"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// regressionRuns returns history entries, newest first, with the given coverage
+func regressionRuns(values ...float64) []history.Entry {
+	entries := make([]history.Entry, 0, len(values))
+	for i, value := range values {
+		entries = append(entries, history.Entry{
+			CommitSHA: strings.Repeat(string(rune('a'+i)), 40),
+			Coverage: &parser.CoverageData{Percentage: value, Packages: map[string]*parser.PackageCoverage{
+				"github.com/owner/repo/internal/api": {Percentage: value},
+			}},
+		})
+	}
+	return entries
+}
+
+func TestDetectRegression(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		current   float64
+		previous  []history.Entry
+		reason    string
+		baseline  float64
+	}{
+		{"declining", 0, 77, regressionRuns(78, 79, 80, 81), "Coverage declined 4 runs in a row, from 81.00% to 77.00%.", 81},
+		{"declining from the first run", 0, 77, regressionRuns(78, 79), "Coverage declined 2 runs in a row, from 79.00% to 77.00%.", 79},
+		{"below threshold", 80, 79, regressionRuns(79.5, 79, 85), "Coverage has been below the 80.00% threshold for 3 runs in a row.", 85},
+		{"below threshold since the first run", 80, 79, regressionRuns(79, 79), "Coverage has been below the 80.00% threshold for 3 runs in a row.", 0},
+		{"recovering", 80, 81, regressionRuns(79, 78, 77), "", 0},
+		{"short decline", 0, 77, regressionRuns(78, 76), "", 0},
+		{"too few runs below", 80, 79, regressionRuns(81, 79), "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, ok := detectRegression(tt.threshold, 2, tt.current, tt.previous)
+			if tt.reason == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.reason, found.Reason)
+			if tt.baseline == 0 {
+				assert.Nil(t, found.Baseline)
+			} else {
+				require.NotNil(t, found.Baseline)
+				assert.InDelta(t, tt.baseline, found.Baseline.Coverage.Percentage, 0.001)
+			}
+		})
+	}
+}
+
+func TestRegressionOffenders(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{Repository: "repo"}}
+	owners, err := codeowners.Parse(strings.NewReader("* @org/maintainers\n/internal/api/ @alice @org/api\n/internal/store/ @bob\n"))
+	require.NoError(t, err)
+
+	coverage := &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"github.com/owner/repo/internal/api": {Percentage: 70, TotalLines: 100, CoveredLines: 70, Files: map[string]*parser.FileCoverage{
+			"github.com/owner/repo/internal/api/api.go": {},
+		}},
+		"github.com/owner/repo/internal/store": {Percentage: 85, TotalLines: 20, CoveredLines: 17, Files: map[string]*parser.FileCoverage{
+			"github.com/owner/repo/internal/store/store.go": {},
+		}},
+		"github.com/owner/repo/internal/cli": {Percentage: 50, TotalLines: 40, CoveredLines: 20},
+	}}
+	baseline := &history.Entry{Coverage: &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"github.com/owner/repo/internal/api":   {Percentage: 80},
+		"github.com/owner/repo/internal/store": {Percentage: 90},
+		"github.com/owner/repo/internal/cli":   {Percentage: 50},
+	}}}
+
+	offenders := regressionOffenders(cfg, coverage, baseline, owners)
+	require.Len(t, offenders, 2)
+	assert.Equal(t, regressionPackage{Name: "github.com/owner/repo/internal/api", Before: 80, After: 70, Missed: 30,
+		Owners: []string{"@alice", "@org/api"}, HasBase: true}, offenders[0])
+	assert.Equal(t, []string{"@bob"}, offenders[1].Owners)
+
+	// Without a baseline, the packages missing the most statements offend
+	offenders = regressionOffenders(cfg, coverage, nil, nil)
+	require.Len(t, offenders, 3)
+	assert.Equal(t, "github.com/owner/repo/internal/api", offenders[0].Name)
+	assert.Equal(t, "github.com/owner/repo/internal/cli", offenders[1].Name)
+	assert.Empty(t, offenders[0].Owners)
+}
+
+func TestRenderRegressionIssue(t *testing.T) {
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		GitHub:   config.GitHubConfig{Repository: "repo", CommitSHA: "0123456789abcdef"},
+	}
+	previous := regressionRuns(78.5, 85)
+	found, ok := detectRegression(80, 2, 79, previous)
+	require.True(t, ok)
+
+	body := renderRegressionIssue(cfg, "master", 79, previous, found, []regressionPackage{
+		{Name: "github.com/owner/repo/internal/api", Before: 85, After: 78, Missed: 22, Owners: []string{"@alice"}, HasBase: true},
+	})
+	assert.True(t, strings.HasPrefix(body, regressionIssueMarker))
+	for _, want := range []string{
+		"## 📉 Sustained coverage regression on `master`",
+		"Coverage has been below the 80.00% threshold for 2 runs in a row.",
+		"| **Current** | 79.00% (`0123456`) |",
+		"| **Before the regression** | 85.00% (`bbbbbbb`) |",
+		"```mermaid\nxychart-beta\n",
+		`    x-axis ["bbbbbbb", "aaaaaaa", "0123456"]`,
+		`    y-axis "Coverage (%)" 77 --> 86`,
+		"    line [85.00, 78.50, 79.00]\n    line [80.00, 80.00, 80.00]\n",
+		"| `internal/api` | 85.00% | 78.00% | 22 | @alice |",
+	} {
+		assert.Contains(t, body, want)
+	}
+}
+
+func TestUpdateRegressionIssue(t *testing.T) {
+	dir := initHookRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("/internal/ @alice @org/backend\n"), 0o600))
+
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		GitHub: config.GitHubConfig{Owner: "owner", Repository: "repo", CommitSHA: "0123456789abcdef",
+			RegressionIssueRuns: 2, RegressionIssueTitle: "Sustained coverage regression"},
+	}
+	regressing := &parser.CoverageData{Percentage: 78, Packages: map[string]*parser.PackageCoverage{
+		"github.com/owner/repo/internal/api": {Percentage: 70, TotalLines: 10, CoveredLines: 7, Files: map[string]*parser.FileCoverage{
+			"github.com/owner/repo/internal/api/api.go": {},
+		}},
+	}}
+	recovered := &parser.CoverageData{Percentage: 82}
+	previous := regressionRuns(79, 85)
+
+	tests := []struct {
+		name       string
+		body       string
+		coverage   *parser.CoverageData
+		operations []string
+		output     string
+	}{
+		{"open and assign", "", regressing, []string{"search", "repository", "createIssue", "assignees"}, "Assigned to alice"},
+		{"update", "older body", regressing, []string{"search", "updateIssue"}, "Updated regression issue #1"},
+		{"recovered", "older body", recovered, []string{"search", "addComment", "closeIssue"}, "closed regression issue #1"},
+		{"healthy", "", recovered, []string{"search"}, "No sustained coverage regression on master"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, operations := threadServer(t, cfg.GitHub.RegressionIssueTitle, tt.body)
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+
+			require.NoError(t, updateRegressionIssue(context.Background(), cmd, cfg, client, "master", tt.coverage, previous))
+			assert.Equal(t, tt.operations, *operations)
+			assert.Contains(t, out.String(), tt.output)
+		})
+	}
+}
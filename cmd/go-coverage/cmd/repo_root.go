@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// flagNameRepoRoot is the global flag that sets the repository root
+	flagNameRepoRoot = "repo-root"
+	// envRepoRoot is the environment variable equivalent of --repo-root
+	envRepoRoot = "GO_COVERAGE_REPO_ROOT"
+)
+
+// applyRepoRoot records the --repo-root flag in the environment so every configuration loaded
+// by the command resolves file discovery and history paths against the same root
+func applyRepoRoot(cmd *cobra.Command) {
+	if root, _ := cmd.Flags().GetString(flagNameRepoRoot); root != "" {
+		_ = os.Setenv(envRepoRoot, root)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+func TestRepoRootFlagIsGlobal(t *testing.T) {
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	require.NotNil(t, commands.Root.PersistentFlags().Lookup(flagNameRepoRoot))
+}
+
+func TestApplyRepoRoot(t *testing.T) {
+	t.Setenv(envRepoRoot, "")
+	root := t.TempDir()
+
+	cmd := &cobra.Command{Use: testCoverageLabel}
+	cmd.Flags().String(flagNameRepoRoot, "", "")
+	applyRepoRoot(cmd)
+	assert.Empty(t, os.Getenv(envRepoRoot))
+
+	require.NoError(t, cmd.ParseFlags([]string{"--repo-root", root}))
+	applyRepoRoot(cmd)
+	assert.Equal(t, root, os.Getenv(envRepoRoot))
+
+	// Configurations loaded by the command resolve against the flag's root
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	resolved, err := cfg.GetRepositoryRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root, resolved)
+}
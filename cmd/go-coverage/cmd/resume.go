@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/checkpoint"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ErrPipelineStepsFailed indicates that the pipeline ran to the end but some of its steps failed
+var ErrPipelineStepsFailed = errors.New("pipeline steps failed")
+
+// flagNameResume is the flag that skips the steps a previous run completed
+const flagNameResume = "resume"
+
+// Steps of the complete command recorded in the checkpoint, in pipeline order
+const (
+	stepBadge       = "badge"
+	stepReport      = "report"
+	stepDashboard   = "dashboard"
+	stepHistory     = "history"
+	stepGitHub      = "github"
+	stepPublish     = "publish"
+	stepPublicBadge = "public-badge"
+)
+
+// reportStepData is what later steps need from the report step when it is skipped
+type reportStepData struct {
+	Pages int `json:"pages"`
+}
+
+// historyStepData is what the policy evaluation needs from the history step when it is skipped
+type historyStepData struct {
+	Trend    string    `json:"trend"`
+	Previous []float64 `json:"previous,omitempty"`
+}
+
+// pipelineSteps records the progress of a complete run in a checkpoint and, when resuming,
+// skips the steps a previous run of the same commit and profile completed
+type pipelineSteps struct {
+	cmd *cobra.Command
+	// checkpoint is nil when progress is not recorded, e.g. in dry-run mode
+	checkpoint *checkpoint.Checkpoint
+	resume     bool
+	skipped    []string
+	failed     []string
+}
+
+// newPipelineSteps starts recording the steps of a run in the checkpoint at path, loading the
+// previous checkpoint when resuming
+func newPipelineSteps(cmd *cobra.Command, path, key string, mode os.FileMode, resume, record bool) *pipelineSteps {
+	steps := &pipelineSteps{cmd: cmd, resume: resume}
+	if !record {
+		return steps
+	}
+
+	steps.checkpoint = checkpoint.New(path, key, mode)
+	if !resume {
+		return steps
+	}
+
+	previous, err := checkpoint.Load(path, key, mode)
+	switch {
+	case err != nil:
+		cmd.Printf("⚠️  Cannot resume, running every step: %v\n\n", err)
+	case previous.Empty():
+		cmd.Printf("♻️  Resume: no checkpoint for this commit and profile, running every step\n\n")
+	default:
+		steps.checkpoint = previous
+		cmd.Printf("♻️  Resuming from %s\n", path)
+		for _, step := range []string{stepBadge, stepReport, stepDashboard, stepHistory, stepGitHub, stepPublish, stepPublicBadge} {
+			switch previous.State(step) {
+			case checkpoint.StateCompleted:
+				cmd.Printf("   ✅ %s: completed, skipping\n", step)
+			case checkpoint.StateFailed:
+				cmd.Printf("   🔁 %s: failed (%s), retrying\n", step, previous.Steps[step].Error)
+			case checkpoint.StateRunning:
+				cmd.Printf("   🔁 %s: did not finish, retrying\n", step)
+			}
+		}
+		cmd.Printf("\n")
+	}
+	return steps
+}
+
+// skip reports whether a step completed in the run being resumed, printing the step title as skipped
+func (s *pipelineSteps) skip(step, title string) bool {
+	if !s.resume || s.checkpoint == nil || !s.checkpoint.Done(step) {
+		return false
+	}
+	s.cmd.Printf("%s (skipped: completed by a previous run)\n\n", title)
+	s.skipped = append(s.skipped, step)
+	return true
+}
+
+// restore decodes the data a skipped step recorded into v
+func (s *pipelineSteps) restore(step string, v any) bool {
+	if s.checkpoint == nil {
+		return false
+	}
+	if err := s.checkpoint.Data(step, v); err != nil {
+		s.cmd.Printf("   ⚠️  Failed to restore %s step: %v\n", step, err)
+		return false
+	}
+	return true
+}
+
+// begin records that a step started; a step left running marks where a crashed run stopped
+func (s *pipelineSteps) begin(step string) {
+	if s.checkpoint != nil {
+		s.warn(s.checkpoint.Begin(step))
+	}
+}
+
+// complete records that a step finished, with the data to restore when it is skipped
+func (s *pipelineSteps) complete(step string, data any) {
+	if s.checkpoint != nil {
+		s.warn(s.checkpoint.Complete(step, data))
+	}
+}
+
+// fail records a step that failed without stopping the pipeline
+func (s *pipelineSteps) fail(step string, err error) {
+	s.failed = append(s.failed, step)
+	if s.checkpoint != nil {
+		s.warn(s.checkpoint.Fail(step, err))
+	}
+}
+
+// warn reports a checkpoint that could not be saved; the run itself goes on
+func (s *pipelineSteps) warn(err error) {
+	if err != nil {
+		s.cmd.Printf("   ⚠️  Failed to save checkpoint: %v\n", err)
+	}
+}
+
+// finish prints the skipped and failed steps and returns an error when a step failed
+func (s *pipelineSteps) finish() error {
+	if len(s.skipped) > 0 {
+		s.cmd.Printf("Skipped steps (completed by a previous run): %s\n", strings.Join(s.skipped, ", "))
+	}
+	if len(s.failed) == 0 {
+		return nil
+	}
+	s.cmd.Printf("Failed steps: %s\n", strings.Join(s.failed, ", "))
+	if s.checkpoint != nil {
+		s.cmd.Printf("💡 Re-run with --%s to retry only the failed steps\n", flagNameResume)
+	}
+	return fmt.Errorf("%w: %s", ErrPipelineStepsFailed, strings.Join(s.failed, ", "))
+}
+
+// checkpointKey identifies a run by its commit, pull request, branch and coverage profiles, so a
+// checkpoint is never resumed for different coverage
+func checkpointKey(cfg *config.Config, branch, inputFile string, variantArgs []string) string {
+	parts := []string{cfg.GitHub.CommitSHA, strconv.Itoa(cfg.GitHub.PullRequest), branch}
+
+	paths := []string{inputFile}
+	if len(variantArgs) > 0 {
+		paths = paths[:0]
+		for _, arg := range variantArgs {
+			if _, path, err := parser.ParseVariantArg(arg); err == nil {
+				paths = append(paths, path)
+			}
+		}
+	}
+	for _, path := range paths {
+		parts = append(parts, path, fileDigest(path))
+	}
+	return checkpoint.Key(parts...)
+}
+
+// fileDigest returns the SHA-256 of a file, or an empty string when it cannot be read
+func fileDigest(path string) string {
+	file, err := os.Open(path) //nolint:gosec // path is the coverage profile given by the operator
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/checkpoint"
+)
+
+func TestCompleteCommandResume(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "test")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "0.0")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	outputDir := filepath.Join(tempDir, "output")
+	checkpointPath := filepath.Join(outputDir, checkpoint.FileName)
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 1
+`), 0o600))
+
+	args := []string{cmdComplete, "--offline", "--input", coverageFile, "--output", outputDir, "--skip-history"}
+
+	output, err := executeCommand(t, args...)
+	require.NoError(t, err)
+	assert.NotContains(t, output, "skipped: completed by a previous run")
+
+	data, err := os.ReadFile(checkpointPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"publish"`)
+
+	// Outputs of skipped steps are not written again
+	require.NoError(t, os.Remove(filepath.Join(outputDir, "coverage.html")))
+
+	output, err = executeCommand(t, append(args, "--resume")...)
+	require.NoError(t, err)
+	assert.Contains(t, output, "♻️  Resuming from")
+	assert.Contains(t, output, "Step 2: Generating coverage badge (skipped: completed by a previous run)")
+	assert.Contains(t, output, "Step 3: Generating HTML report (skipped: completed by a previous run)")
+	assert.Contains(t, output, "Step 7: Copying critical files to root output directory (skipped: completed by a previous run)")
+	assert.Contains(t, output, "Skipped steps (completed by a previous run): badge, report, dashboard, publish")
+	assert.NoFileExists(t, filepath.Join(outputDir, "coverage.html"), "skipped steps write nothing")
+
+	// A different profile never resumes the checkpoint of another
+	require.NoError(t, os.WriteFile(coverageFile, []byte(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 2 0
+`), 0o600))
+	output, err = executeCommand(t, append(args, "--resume")...)
+	require.NoError(t, err)
+	assert.Contains(t, output, "no checkpoint for this commit and profile, running every step")
+	assert.FileExists(t, filepath.Join(outputDir, "coverage.html"))
+}
+
+func TestPipelineSteps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), checkpoint.FileName)
+	newSteps := func(resume bool) (*pipelineSteps, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		return newPipelineSteps(cmd, path, "key", 0o600, resume, true), out
+	}
+
+	steps, _ := newSteps(false)
+	steps.begin(stepReport)
+	steps.complete(stepReport, reportStepData{Pages: 4})
+	steps.begin(stepHistory)
+	steps.begin(stepGitHub)
+	steps.fail(stepGitHub, errors.New("403 Forbidden"))
+	require.ErrorIs(t, steps.finish(), ErrPipelineStepsFailed)
+
+	resumed, out := newSteps(true)
+	assert.Contains(t, out.String(), "report: completed, skipping")
+	assert.Contains(t, out.String(), "history: did not finish, retrying")
+	assert.Contains(t, out.String(), "github: failed (403 Forbidden), retrying")
+
+	assert.True(t, resumed.skip(stepReport, "Step 3"))
+	assert.False(t, resumed.skip(stepHistory, "Step 5"))
+	assert.False(t, resumed.skip(stepGitHub, "Step 6"))
+
+	var data reportStepData
+	require.True(t, resumed.restore(stepReport, &data))
+	assert.Equal(t, 4, data.Pages)
+	require.NoError(t, resumed.finish())
+
+	t.Run("not recorded", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		steps := newPipelineSteps(cmd, path, "key", 0o600, true, false)
+
+		assert.False(t, steps.skip(stepReport, "Step 3"))
+		steps.fail(stepGitHub, errors.New("boom"))
+		require.ErrorIs(t, steps.finish(), ErrPipelineStepsFailed)
+		assert.NotContains(t, out.String(), "--resume")
+	})
+}
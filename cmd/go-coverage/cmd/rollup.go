@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// newDirectoryDashboardData rolls coverage up by directory to cfg.Report.RollupDepth levels.
+// When previous coverage is known, each directory carries its change since then.
+func newDirectoryDashboardData(cfg *config.Config, branch string, coverage, previous *parser.CoverageData) []dashboard.DirectoryCoverage {
+	resolvePath := func(path string) string {
+		return urlutil.CleanModulePathWithRepo(path, cfg.GitHub.Repository)
+	}
+
+	rollup := coverage.DirectoryRollup(cfg.Report.RollupDepth, resolvePath)
+	if len(rollup) == 0 {
+		return nil
+	}
+
+	previousPercentages := make(map[string]float64)
+	var collect func([]parser.DirectoryRollup)
+	collect = func(dirs []parser.DirectoryRollup) {
+		for _, dir := range dirs {
+			previousPercentages[dir.Path] = dir.Percentage
+			collect(dir.Children)
+		}
+	}
+	collect(previous.DirectoryRollup(cfg.Report.RollupDepth, resolvePath))
+
+	var convert func([]parser.DirectoryRollup) []dashboard.DirectoryCoverage
+	convert = func(dirs []parser.DirectoryRollup) []dashboard.DirectoryCoverage {
+		result := make([]dashboard.DirectoryCoverage, 0, len(dirs))
+		for _, dir := range dirs {
+			dirCoverage := dashboard.DirectoryCoverage{
+				Path:         dir.Path,
+				Files:        dir.Files,
+				Coverage:     dir.Percentage,
+				TotalLines:   dir.TotalStatements,
+				CoveredLines: dir.CoveredStatements,
+				MissedLines:  dir.TotalStatements - dir.CoveredStatements,
+				Children:     convert(dir.Children),
+			}
+			if before, ok := previousPercentages[dir.Path]; ok {
+				change := dir.Percentage - before
+				dirCoverage.Change = &change
+			}
+			if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" && branch != "" && dir.Path != parser.RootDirectory {
+				dirCoverage.GitHubURL = fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s",
+					cfg.GitHub.Owner, cfg.GitHub.Repository, branch, urlutil.RepoPath(cfg.Coverage.ModuleRoot, dir.Path))
+			}
+			result = append(result, dirCoverage)
+		}
+		return result
+	}
+	return convert(rollup)
+}
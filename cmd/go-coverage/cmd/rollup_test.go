@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestNewDirectoryDashboardData(t *testing.T) {
+	coverageOf := func(covered int) *parser.CoverageData {
+		return &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+			"lib": {Files: map[string]*parser.FileCoverage{
+				"github.com/example/repo/lib/lib.go":       {TotalLines: 4, CoveredLines: covered},
+				"github.com/example/repo/lib/store/sql.go": {TotalLines: 4, CoveredLines: 4},
+			}},
+		}}
+	}
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Owner: "example", Repository: "repo"},
+		Report: config.ReportConfig{RollupDepth: 2},
+	}
+
+	dirs := newDirectoryDashboardData(cfg, "master", coverageOf(4), coverageOf(2))
+	require.Len(t, dirs, 1)
+	lib := dirs[0]
+	assert.Equal(t, "lib", lib.Path)
+	assert.InDelta(t, 100.0, lib.Coverage, 0.001)
+	require.NotNil(t, lib.Change)
+	assert.InDelta(t, 25.0, *lib.Change, 0.001)
+	assert.Equal(t, "https://github.com/example/repo/tree/master/lib", lib.GitHubURL)
+	require.Len(t, lib.Children, 1)
+	assert.Equal(t, "lib/store", lib.Children[0].Path)
+	require.NotNil(t, lib.Children[0].Change)
+	assert.Zero(t, *lib.Children[0].Change)
+
+	dirs = newDirectoryDashboardData(cfg, "master", coverageOf(4), nil)
+	assert.Nil(t, dirs[0].Change, "no change without a previous run")
+
+	cfg.Report.RollupDepth = 0
+	assert.Nil(t, newDirectoryDashboardData(cfg, "master", coverageOf(4), nil))
+}
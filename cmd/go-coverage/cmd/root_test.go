@@ -308,3 +308,23 @@ func TestRootCommandValidation(t *testing.T) {
 	err := testCmd.Execute()
 	require.NoError(t, err)
 }
+
+func TestRootCommandRedactsOutput(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "printed-secret-token")
+
+	commands := NewCommands(VersionInfo{Version: "test"})
+	commands.Root.AddCommand(&cobra.Command{
+		Use: "leak",
+		Run: func(cmd *cobra.Command, _ []string) {
+			cmd.Printf("calling https://api.github.com?access_token=%s\n", os.Getenv("GITHUB_TOKEN"))
+		},
+	})
+
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetArgs([]string{"leak"})
+	require.NoError(t, commands.Execute())
+
+	assert.NotContains(t, buf.String(), "printed-secret-token")
+	assert.Contains(t, buf.String(), "access_token=[REDACTED]")
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// runContext returns the context of the run the configuration was loaded for, resolving it
+// from the environment when the configuration was built by hand
+func runContext(cfg *config.Config) *ci.Context {
+	if cfg.CI != nil {
+		return cfg.CI
+	}
+	return ci.FromEnv()
+}
+
+// runHeadBranch returns the branch under test: the source branch of a pull request or the
+// branch of the run
+func runHeadBranch(cfg *config.Config) string {
+	if branch := runContext(cfg).Branch; branch != "" {
+		return branch
+	}
+	return cfg.GetCurrentBranch()
+}
+
+// runBaseBranch returns the branch a pull request targets, or the primary main branch
+func runBaseBranch(cfg *config.Config) string {
+	if branch := runContext(cfg).BaseBranch; branch != "" {
+		return branch
+	}
+	return getPrimaryMainBranch()
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// isolateRunContextEnv clears the CI variables the run context is resolved from, so tests
+// behave the same inside and outside CI
+func isolateRunContextEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"GITHUB_EVENT_NAME", "GITHUB_EVENT_PATH", "GITHUB_REF", "GITHUB_REF_NAME", "GITHUB_REF_TYPE",
+		"GITHUB_HEAD_REF", "GITHUB_BASE_REF", "GITHUB_PR_NUMBER", "GITHUB_SHA", "GITHUB_ACTIONS",
+		"GITLAB_CI", "CIRCLECI", "BUILDKITE", "JENKINS_URL", "BUILD_URL", "GO_COVERAGE_CI",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestRunBranches(t *testing.T) {
+	t.Run("from the loaded context", func(t *testing.T) {
+		isolateRunContextEnv(t)
+		cfg := &config.Config{CI: &ci.Context{Branch: "feature", BaseBranch: "release/1.x"}}
+		assert.Equal(t, "feature", runHeadBranch(cfg))
+		assert.Equal(t, "release/1.x", runBaseBranch(cfg))
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		isolateRunContextEnv(t)
+		t.Setenv("GITHUB_REF", "refs/pull/12/merge")
+		t.Setenv("GITHUB_REF_NAME", "12/merge")
+		t.Setenv("GITHUB_HEAD_REF", "feature-env")
+		t.Setenv("GITHUB_BASE_REF", "main")
+		cfg := &config.Config{}
+		assert.Equal(t, "feature-env", runHeadBranch(cfg))
+		assert.Equal(t, "main", runBaseBranch(cfg))
+		assert.Equal(t, "feature-env", getDefaultBranch())
+	})
+
+	t.Run("base falls back to the primary main branch", func(t *testing.T) {
+		isolateRunContextEnv(t)
+		t.Setenv("DEFAULT_MAIN_BRANCH", "trunk")
+		cfg := &config.Config{CI: &ci.Context{Branch: "feature"}}
+		assert.Equal(t, "trunk", runBaseBranch(cfg))
+	})
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/access"
+	"github.com/mrz1836/go-coverage/internal/attribution"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+var (
+	// ErrServeDirNotFound indicates that the directory to serve does not exist
+	ErrServeDirNotFound = errors.New("report directory not found")
+	// ErrServeLinkSecretRequired indicates that signed links need a link secret
+	ErrServeLinkSecretRequired = errors.New("a link secret is required to sign links (GO_COVERAGE_SERVE_LINK_SECRET)")
+)
+
+// serveShutdownTimeout bounds the wait for in-flight requests on shutdown
+const serveShutdownTimeout = 15 * time.Second
+
+// newServeCmd creates the serve command
+func (c *Commands) newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the coverage site with optional access protection",
+		Long: `Serve the generated coverage site (dashboard, reports and badges) over HTTP, for teams
+who cannot publish coverage details on a public GitHub Pages site.
+
+Access is protected when any of these is configured:
+  GO_COVERAGE_SERVE_TOKEN        Shared token, given as ?token=... or a bearer token
+  GO_COVERAGE_SERVE_USERNAME     Basic auth username (with GO_COVERAGE_SERVE_PASSWORD)
+  GO_COVERAGE_SERVE_LINK_SECRET  Secret of signed, time-limited links to single reports
+
+When go-coverage attribute wrote test-attribution.json to the site, /tests answers which
+tests execute a line: /tests?file=internal/api/server.go&line=42, or as JSON with
+&format=json.
+
+Readers who open a link with the token or a valid signature get a cookie, so the pages and
+assets of the report load without it. A signed link only opens the directory of the report it
+points to. Badges stay public unless GO_COVERAGE_SERVE_PUBLIC_BADGES=false, so READMEs can
+show them; /healthz reports liveness.
+
+Set GO_COVERAGE_SERVE_URL to the address of the server and the same link secret in the
+coverage workflow, and the pull request comments and statuses link to the report with a
+signed link valid for GO_COVERAGE_SERVE_LINK_TTL (default 168h).`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			addr, _ := cmd.Flags().GetString("addr")
+			dir, _ := cmd.Flags().GetString("dir")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if addr == "" {
+				addr = cfg.Serve.Addr
+			}
+			if dir == "" {
+				dir = cfg.Coverage.OutputDir
+			}
+
+			handler, err := newReportServer(cfg, dir)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return serveReports(ctx, cmd, cfg, addr, dir, handler)
+		},
+	}
+
+	cmd.Flags().String("addr", "", "Address to listen on (default: GO_COVERAGE_SERVE_ADDR or :8000)")
+	cmd.Flags().String("dir", "", "Directory of the coverage site (default: GO_COVERAGE_OUTPUT_DIR)")
+
+	cmd.AddCommand(c.newServeLinkCmd())
+	return cmd
+}
+
+// newServeLinkCmd creates the serve link command
+func (c *Commands) newServeLinkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link <path>",
+		Short: "Print a signed, time-limited link to a report",
+		Long: `Print a signed link to a page of the protected report server, valid for
+GO_COVERAGE_SERVE_LINK_TTL or --ttl. The link opens the directory of the page only, e.g. a
+link to reports/pr/42/coverage.html opens the report of pull request 42 but no other report.`,
+		Example: `  go-coverage serve link reports/pr/42/coverage.html
+  go-coverage serve link reports/branch/release/coverage.html --ttl 24h`,
+		Args: cobra.ExactArgs(1),
+		// The signed link is the output, so it is not masked like the signatures in other output
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			applyRepoRoot(cmd)
+			return applyFixtureMode(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ttl, _ := cmd.Flags().GetDuration("ttl")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if ttl <= 0 {
+				ttl = cfg.Serve.LinkTTL
+			}
+
+			link, err := signReportLink(cfg, args[0], ttl)
+			if err != nil {
+				return err
+			}
+			cmd.Println(link)
+			return nil
+		},
+	}
+
+	cmd.Flags().Duration("ttl", 0, "How long the link stays valid (default: GO_COVERAGE_SERVE_LINK_TTL or 168h)")
+	return cmd
+}
+
+// signReportLink signs a link to a page of the report server, absolute when the URL of the
+// server is configured
+func signReportLink(cfg *config.Config, page string, ttl time.Duration) (string, error) {
+	if cfg.Serve.LinkSecret == "" {
+		return "", ErrServeLinkSecretRequired
+	}
+	signer, err := access.NewSigner(cfg.Serve.LinkSecret)
+	if err != nil {
+		return "", err
+	}
+	return signer.Sign(cfg.Serve.URL+"/"+strings.TrimPrefix(page, "/"), ttl)
+}
+
+// newReportServer serves the coverage site in dir behind the configured protection
+func newReportServer(cfg *config.Config, dir string) (http.Handler, error) {
+	if err := cfg.ValidateServe(); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrServeDirNotFound, dir)
+	}
+
+	opts := access.Options{
+		Token:    cfg.Serve.Token,
+		Username: cfg.Serve.Username,
+		Password: cfg.Serve.Password,
+		Realm:    "go-coverage",
+	}
+	if cfg.Serve.LinkSecret != "" {
+		signer, err := access.NewSigner(cfg.Serve.LinkSecret)
+		if err != nil {
+			return nil, err
+		}
+		opts.Signer = signer
+	}
+	if cfg.Serve.PublicBadges {
+		opts.Public = isBadgePath
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", access.Protect(http.FileServer(http.Dir(dir)), opts))
+	mux.Handle("/tests", access.Protect(testLookupHandler(dir), opts))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux, nil
+}
+
+// testLookupPage renders the answer of /tests and a form to ask again
+var testLookupPage = template.Must(template.New("tests").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Which test covers this line?</title></head>
+<body>
+<h1>Which test covers this line?</h1>
+<form method="get" action="/tests">
+<input name="file" placeholder="internal/api/server.go" value="{{.File}}" size="60">
+<input name="line" type="number" min="1" placeholder="42" value="{{if .Line}}{{.Line}}{{end}}">
+<button type="submit">Look up</button>
+</form>
+{{- if .Error}}
+<p>{{.Error}}</p>
+{{- else if .Line}}
+{{- if .Tests}}
+<p>{{len .Tests}} test(s) execute {{.File}}:{{.Line}}</p>
+<ul>{{range .Tests}}<li><code>{{.}}</code></li>{{end}}</ul>
+{{- else}}
+<p>No test executes {{.File}}:{{.Line}}</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// testLookup is the answer of /tests
+type testLookup struct {
+	File  string             `json:"file"`
+	Line  int                `json:"line"`
+	Tests []attribution.Test `json:"tests"`
+	Error string             `json:"error,omitempty"`
+}
+
+// testLookupHandler answers which tests execute a line from the attribution index in dir. The
+// index is read on every request, so a new attribute run is picked up without a restart.
+func testLookupHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		lookup := testLookup{File: query.Get("file"), Tests: []attribution.Test{}}
+		status := http.StatusOK
+		if lineParam := query.Get("line"); lookup.File != "" || lineParam != "" {
+			ix, err := attribution.Load(filepath.Join(dir, attribution.IndexFile))
+			switch line, atoiErr := strconv.Atoi(lineParam); {
+			case err != nil:
+				status, lookup.Error = http.StatusNotFound, "No test attribution has been recorded; run go-coverage attribute"
+			case lookup.File == "" || atoiErr != nil || line <= 0:
+				status, lookup.Error = http.StatusBadRequest, "Give a file and a line number"
+			default:
+				lookup.Line = line
+				lookup.Tests = ix.Lookup(lookup.File, line)
+			}
+		}
+
+		if query.Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(lookup)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		_ = testLookupPage.Execute(w, lookup)
+	})
+}
+
+// isBadgePath reports whether a URL path is a badge: an SVG at the root of the site (the main
+// branch badges) or under badges/
+func isBadgePath(urlPath string) bool {
+	cleaned := path.Clean("/" + urlPath)
+	if path.Ext(cleaned) != ".svg" {
+		return false
+	}
+	return path.Dir(cleaned) == "/" || strings.HasPrefix(cleaned, "/badges/")
+}
+
+// serveReports serves the site until ctx is canceled
+func serveReports(ctx context.Context, cmd *cobra.Command, cfg *config.Config, addr, dir string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	cmd.Printf("📊 Serving %s on %s\n", dir, addr)
+	var methods []string
+	if cfg.Serve.Token != "" {
+		methods = append(methods, "token")
+	}
+	if cfg.Serve.Username != "" {
+		methods = append(methods, "basic auth")
+	}
+	if cfg.Serve.LinkSecret != "" {
+		methods = append(methods, "signed links")
+	}
+	if len(methods) == 0 {
+		cmd.Printf("⚠️  No access protection configured: anyone who can reach the server can read the reports\n")
+	} else {
+		cmd.Printf("🔒 Access protected by %s\n", strings.Join(methods, ", "))
+	}
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		cmd.Printf("🛑 Shutting down\n")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		err = server.Shutdown(shutdownCtx)
+		cancel()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/access"
+	"github.com/mrz1836/go-coverage/internal/attribution"
+	"github.com/mrz1836/go-coverage/internal/config"
+)
+
+// writeSite writes a minimal coverage site
+func writeSite(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"index.html":                     "dashboard",
+		"coverage.svg":                   "<svg/>",
+		"badges/pr/42/coverage.svg":      "<svg/>",
+		"reports/pr/42/coverage.html":    "report 42",
+		"reports/pr/43/coverage.html":    "report 43",
+		"reports/pr/42/assets/style.css": "body{}",
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o750))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func get(t *testing.T, handler http.Handler, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReportServer(t *testing.T) {
+	cfg := &config.Config{Serve: config.ServeConfig{
+		Token:        "serve-token",
+		LinkSecret:   "link-secret",
+		LinkTTL:      time.Hour,
+		PublicBadges: true,
+	}}
+	handler, err := newReportServer(cfg, writeSite(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, get(t, handler, "/healthz").Code)
+	assert.Equal(t, http.StatusOK, get(t, handler, "/coverage.svg").Code, "badges are public")
+	assert.Equal(t, http.StatusOK, get(t, handler, "/badges/pr/42/coverage.svg").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, handler, "/").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, handler, "/reports/pr/42/coverage.html").Code)
+
+	rec := get(t, handler, "/?token=serve-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "dashboard", rec.Body.String())
+
+	link, err := signReportLink(cfg, "reports/pr/42/coverage.html", time.Hour)
+	require.NoError(t, err)
+	rec = get(t, handler, link)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "report 42", rec.Body.String())
+
+	cookies := rec.Result().Cookies()
+	assert.Equal(t, http.StatusOK, get(t, handler, "/reports/pr/42/assets/style.css", cookies...).Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, handler, "/reports/pr/43/coverage.html", cookies...).Code)
+
+	cfg.Serve.PublicBadges = false
+	handler, err = newReportServer(cfg, writeSite(t))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, get(t, handler, "/coverage.svg").Code)
+}
+
+func TestReportServerErrors(t *testing.T) {
+	_, err := newReportServer(&config.Config{}, filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, ErrServeDirNotFound)
+
+	_, err = newReportServer(&config.Config{Serve: config.ServeConfig{Username: "team"}}, t.TempDir())
+	require.ErrorIs(t, err, config.ErrInvalidServe)
+}
+
+func TestIsBadgePath(t *testing.T) {
+	assert.True(t, isBadgePath("/coverage.svg"))
+	assert.True(t, isBadgePath("/badges/feature/coverage.svg"))
+	assert.False(t, isBadgePath("/reports/pr/42/chart.svg"))
+	assert.False(t, isBadgePath("/badges/../reports/pr/42/chart.svg"))
+	assert.False(t, isBadgePath("/index.html"))
+}
+
+func TestServeLinkCommand(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_SERVE_LINK_SECRET", "")
+
+	_, err := executeCommand(t, "serve", "link", "reports/pr/42/coverage.html")
+	require.ErrorIs(t, err, ErrServeLinkSecretRequired)
+
+	t.Setenv("GO_COVERAGE_SERVE_LINK_SECRET", "link-secret")
+	t.Setenv("GO_COVERAGE_SERVE_URL", "https://coverage.internal.example.com")
+	output, err := executeCommand(t, "serve", "link", "reports/pr/42/coverage.html", "--ttl", "1h")
+	require.NoError(t, err)
+
+	link, err := url.Parse(strings.TrimSpace(output))
+	require.NoError(t, err)
+	assert.Equal(t, "coverage.internal.example.com", link.Host)
+	assert.Equal(t, "/reports/pr/42/coverage.html", link.Path)
+
+	signer, err := access.NewSigner("link-secret")
+	require.NoError(t, err)
+	require.NoError(t, signer.Verify(httptest.NewRequest(http.MethodGet, link.RequestURI(), nil)))
+
+	// The global --repo-root flag applies although the command replaces the root's pre-run hook
+	t.Setenv(envRepoRoot, "")
+	root := t.TempDir()
+	_, err = executeCommand(t, "serve", "link", "reports/pr/42/coverage.html", "--repo-root", root)
+	require.NoError(t, err)
+	assert.Equal(t, root, os.Getenv(envRepoRoot))
+}
+
+func TestTestLookupHandler(t *testing.T) {
+	dir := writeSite(t)
+	handler, err := newReportServer(&config.Config{}, dir)
+	require.NoError(t, err)
+
+	rec := get(t, handler, "/tests?file=internal/api/server.go&line=42&format=json")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "no index has been recorded")
+
+	ix := attribution.NewIndex([]attribution.Test{{Package: "example.com/app/internal/api", Name: "TestServe"}})
+	ix.Files["internal/api/server.go"] = []attribution.Block{{StartLine: 40, EndLine: 44, Tests: []int{0}}}
+	require.NoError(t, ix.Save(filepath.Join(dir, attribution.IndexFile)))
+
+	rec = get(t, handler, "/tests?file=api/server.go&line=42&format=json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"file":"api/server.go","line":42,"tests":[{"package":"example.com/app/internal/api","name":"TestServe"}]}`, rec.Body.String())
+
+	rec = get(t, handler, "/tests?file=api/server.go&line=42")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<code>example.com/app/internal/api.TestServe</code>")
+
+	rec = get(t, handler, "/tests?file=api/server.go&line=50")
+	assert.Contains(t, rec.Body.String(), "No test executes api/server.go:50")
+	assert.Equal(t, http.StatusOK, get(t, handler, "/tests").Code, "the form needs no index")
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/tests?file=api/server.go&line=x").Code)
+}
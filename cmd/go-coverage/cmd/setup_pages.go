@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
 )
 
 // ErrGitHubCLINotFound indicates that the GitHub CLI is not installed or available
@@ -96,6 +98,11 @@ Examples:
 			customDomain, _ := cmd.Flags().GetString("custom-domain")
 			protectBranches, _ := cmd.Flags().GetBool("protect-branches")
 
+			cfg, _ := config.Load()
+			if err := requireNetwork(cmd, cfg, "the setup-pages command"); err != nil {
+				return err
+			}
+
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 			defer cancel()
 
@@ -219,7 +226,7 @@ Examples:
 	}
 
 	// Add flags
-	cmd.Flags().Bool("dry-run", false, "Preview changes without making them")
+	addDryRunFlag(cmd, "Preview changes without making them")
 	cmd.Flags().BoolP("verbose", "v", false, "Show detailed output")
 	cmd.Flags().String("custom-domain", "", "Custom domain for GitHub Pages (optional)")
 	cmd.Flags().Bool("protect-branches", false, "Enable branch protection rules")
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/redact"
+)
+
+const (
+	// siteMetadataFile is written to the output root to record how the site was generated
+	siteMetadataFile = "metadata.json"
+	// siteMetadataSchemaVersion is bumped whenever fields are removed or change meaning
+	siteMetadataSchemaVersion = "1"
+)
+
+// siteMetadata records the provenance of a generated coverage site
+type siteMetadata struct {
+	SchemaVersion string            `json:"schema_version"`
+	Generator     generatorMetadata `json:"generator"`
+	Repository    repoMetadata      `json:"repository"`
+	ConfigHash    string            `json:"config_hash"`
+	InputProfile  *profileMetadata  `json:"input_profile,omitempty"`
+	StartedAt     time.Time         `json:"started_at"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+
+	// VariantProfiles fingerprints the build tag variant profiles merged instead of a single input profile
+	VariantProfiles map[string]profileMetadata `json:"variant_profiles,omitempty"`
+}
+
+// generatorMetadata identifies the go-coverage build that produced the site
+type generatorMetadata struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+}
+
+// repoMetadata identifies the source revision the coverage was collected for
+type repoMetadata struct {
+	Owner       string `json:"owner,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+	CommitSHA   string `json:"commit_sha,omitempty"`
+	PullRequest int    `json:"pull_request,omitempty"`
+}
+
+// profileMetadata fingerprints the coverage profile that was processed
+type profileMetadata struct {
+	Path       string    `json:"path"`
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// newSiteMetadata collects provenance for a pipeline run that started at startedAt.
+// An empty profilePath leaves the input profile out, e.g. when build tag variants were merged.
+func newSiteMetadata(version VersionInfo, cfg *config.Config, branch, profilePath string, startedAt time.Time) (*siteMetadata, error) {
+	configHash, err := cfg.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	var profile *profileMetadata
+	if profilePath != "" {
+		fingerprint, fingerprintErr := fingerprintProfile(profilePath)
+		if fingerprintErr != nil {
+			return nil, fingerprintErr
+		}
+		profile = &fingerprint
+	}
+
+	meta := &siteMetadata{
+		SchemaVersion: siteMetadataSchemaVersion,
+		Generator: generatorMetadata{
+			Name:      "go-coverage",
+			Version:   version.Version,
+			Commit:    version.Commit,
+			BuildDate: version.BuildDate,
+		},
+		Repository: repoMetadata{
+			Owner:     cfg.GitHub.Owner,
+			Name:      cfg.GitHub.Repository,
+			Branch:    branch,
+			CommitSHA: cfg.GitHub.CommitSHA,
+		},
+		ConfigHash:   configHash,
+		InputProfile: profile,
+		StartedAt:    startedAt.UTC(),
+		GeneratedAt:  time.Now().UTC(),
+	}
+	if cfg.IsPullRequestContext() {
+		meta.Repository.PullRequest = cfg.GitHub.PullRequest
+	}
+	return meta, nil
+}
+
+// addVariantProfiles fingerprints the profile of every name=path build tag variant
+func (m *siteMetadata) addVariantProfiles(variantArgs []string) error {
+	for _, arg := range variantArgs {
+		name, path, err := parser.ParseVariantArg(arg)
+		if err != nil {
+			return err
+		}
+		profile, err := fingerprintProfile(path)
+		if err != nil {
+			return err
+		}
+		if m.VariantProfiles == nil {
+			m.VariantProfiles = make(map[string]profileMetadata, len(variantArgs))
+		}
+		m.VariantProfiles[name] = profile
+	}
+	return nil
+}
+
+// fingerprintProfile hashes the coverage profile without loading it into memory
+func fingerprintProfile(path string) (profileMetadata, error) {
+	file, err := os.Open(path) //nolint:gosec // path is the coverage profile the pipeline just parsed
+	if err != nil {
+		return profileMetadata{}, fmt.Errorf("failed to open coverage profile: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return profileMetadata{}, fmt.Errorf("failed to stat coverage profile: %w", err)
+	}
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return profileMetadata{}, fmt.Errorf("failed to hash coverage profile: %w", err)
+	}
+
+	return profileMetadata{
+		Path:       filepath.ToSlash(path),
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
+		Size:       info.Size(),
+		ModifiedAt: info.ModTime().UTC(),
+	}, nil
+}
+
+// writeSiteMetadata writes the metadata as indented JSON with secrets redacted
+func writeSiteMetadata(path string, meta *siteMetadata, redactor *redact.Redactor, mode os.FileMode) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal site metadata: %w", err)
+	}
+	if err := os.WriteFile(path, append(redactor.Bytes(data), '\n'), mode); err != nil {
+		return fmt.Errorf("failed to write site metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/redact"
+)
+
+func TestNewSiteMetadata(t *testing.T) {
+	profile := []byte("mode: set\npkg/a.go:1.1,2.2 1 1\n")
+	profilePath := filepath.Join(t.TempDir(), "coverage.txt")
+	require.NoError(t, os.WriteFile(profilePath, profile, 0o600))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{
+			Owner:       "owner",
+			Repository:  "repo",
+			CommitSHA:   "abc123",
+			PullRequest: 42,
+			Token:       "metadata-secret-token",
+		},
+	}
+	version := VersionInfo{Version: "1.2.3", Commit: "deadbeef", BuildDate: "2024-01-01"}
+	startedAt := time.Now().Add(-time.Minute)
+
+	meta, err := newSiteMetadata(version, cfg, "feature", profilePath, startedAt)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(profile)
+	assert.Equal(t, siteMetadataSchemaVersion, meta.SchemaVersion)
+	assert.Equal(t, generatorMetadata{Name: "go-coverage", Version: "1.2.3", Commit: "deadbeef", BuildDate: "2024-01-01"}, meta.Generator)
+	assert.Equal(t, repoMetadata{Owner: "owner", Name: "repo", Branch: "feature", CommitSHA: "abc123", PullRequest: 42}, meta.Repository)
+	assert.Equal(t, hex.EncodeToString(sum[:]), meta.InputProfile.SHA256)
+	assert.Equal(t, int64(len(profile)), meta.InputProfile.Size)
+	assert.Len(t, meta.ConfigHash, 64)
+	assert.True(t, meta.GeneratedAt.After(meta.StartedAt))
+
+	outPath := filepath.Join(t.TempDir(), siteMetadataFile)
+	redactor, err := redact.New([]string{cfg.GitHub.Token})
+	require.NoError(t, err)
+	require.NoError(t, writeSiteMetadata(outPath, meta, redactor, 0o600))
+
+	data, err := os.ReadFile(outPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), cfg.GitHub.Token)
+
+	var decoded siteMetadata
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, meta.ConfigHash, decoded.ConfigHash)
+}
+
+func TestNewSiteMetadataMissingProfile(t *testing.T) {
+	_, err := newSiteMetadata(VersionInfo{}, &config.Config{}, "main", filepath.Join(t.TempDir(), "missing.txt"), time.Now())
+	require.Error(t, err)
+}
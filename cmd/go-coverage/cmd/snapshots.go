@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/snapshot"
+)
+
+// writeSnapshot saves the coverage data of the run next to the report of its pull request or
+// branch and records it in the snapshot index at the site root, dropping the snapshots that
+// outlived the retention
+func writeSnapshot(cmd *cobra.Command, cfg *config.Config, outputDir, targetOutputDir, branch string,
+	coverageData *dashboard.CoverageData, jsonData []byte,
+) error {
+	snapshotPath := filepath.Join(targetOutputDir, snapshot.DataFile)
+	if err := os.WriteFile(snapshotPath, jsonData, cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	rel, err := filepath.Rel(outputDir, snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	entry := snapshot.Entry{
+		Kind:      snapshot.KindBranch,
+		Name:      branch,
+		Path:      filepath.ToSlash(rel),
+		CommitSHA: coverageData.CommitSHA,
+		Coverage:  coverageData.TotalCoverage,
+		UpdatedAt: coverageData.Timestamp,
+	}
+	if cfg.IsPullRequestContext() {
+		entry.Kind = snapshot.KindPullRequest
+		entry.Name = strconv.Itoa(cfg.GitHub.PullRequest)
+	}
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = time.Now().UTC()
+	}
+
+	index, err := snapshot.Load(outputDir)
+	if err != nil {
+		return err
+	}
+	index.Upsert(entry)
+	retention := time.Duration(cfg.Report.SnapshotRetentionDays) * 24 * time.Hour
+	for _, expired := range index.Prune(time.Now(), retention) {
+		if removeErr := snapshot.Remove(outputDir, expired); removeErr != nil {
+			cmd.Printf("   ⚠️  Failed to remove expired snapshot %s: %v\n", expired.Path, removeErr)
+		}
+	}
+	if err = index.Save(outputDir, cfg.Storage.FileMode); err != nil {
+		return err
+	}
+	cmd.Printf("   ✅ Coverage data snapshot saved: %s (%d in %s)\n", snapshotPath, len(index.Snapshots), snapshot.IndexFile)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/snapshot"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	outputDir := t.TempDir()
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cfg := &config.Config{
+		Storage: config.StorageConfig{FileMode: 0o600},
+		Report:  config.ReportConfig{SnapshotRetentionDays: 30},
+	}
+
+	// An expired pull request snapshot left by an earlier run
+	stale := filepath.Join(outputDir, "pr", "3", snapshot.DataFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o750))
+	require.NoError(t, os.WriteFile(stale, []byte("{}"), 0o600))
+	index := &snapshot.Index{Snapshots: []snapshot.Entry{{
+		Kind: snapshot.KindPullRequest, Name: "3", Path: "pr/3/coverage-data.json",
+		UpdatedAt: time.Now().AddDate(0, 0, -60),
+	}}}
+	require.NoError(t, index.Save(outputDir, 0o600))
+
+	branchDir := filepath.Join(outputDir, "reports", "branch", "master")
+	require.NoError(t, os.MkdirAll(branchDir, 0o750))
+	data := &dashboard.CoverageData{CommitSHA: "abc123", TotalCoverage: 81.5, Timestamp: time.Now()}
+	require.NoError(t, writeSnapshot(cmd, cfg, outputDir, branchDir, "master", data, []byte(`{"total_coverage":81.5}`)))
+
+	cfg.GitHub = config.GitHubConfig{Owner: "owner", Repository: "repo", CommitSHA: "def456", PullRequest: 42}
+	prDir := filepath.Join(outputDir, "pr", "42")
+	require.NoError(t, os.MkdirAll(prDir, 0o750))
+	data = &dashboard.CoverageData{CommitSHA: "def456", TotalCoverage: 83, Timestamp: time.Now()}
+	require.NoError(t, writeSnapshot(cmd, cfg, outputDir, prDir, "feature", data, []byte(`{"total_coverage":83}`)))
+
+	assert.FileExists(t, filepath.Join(branchDir, snapshot.DataFile))
+	assert.FileExists(t, filepath.Join(prDir, snapshot.DataFile))
+	assert.NoFileExists(t, stale, "expired snapshots are removed")
+
+	index, err := snapshot.Load(outputDir)
+	require.NoError(t, err)
+	require.Len(t, index.Snapshots, 2)
+	assert.Equal(t, snapshot.Entry{
+		Kind: snapshot.KindPullRequest, Name: "42", Path: "pr/42/coverage-data.json",
+		CommitSHA: "def456", Coverage: 83, UpdatedAt: index.Snapshots[0].UpdatedAt,
+	}, index.Snapshots[0])
+	assert.Equal(t, "reports/branch/master/coverage-data.json", index.Snapshots[1].Path)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/redact"
+	"github.com/mrz1836/go-coverage/internal/schema"
+)
+
+// pipelineSummaryFile is the name of the machine-readable summary written by the complete command
+const pipelineSummaryFile = "coverage-summary.json"
+
+// pipelineSummarySchema versions coverage-summary.json. Version 1 added the schema version
+// to the unversioned format.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var pipelineSummarySchema = schema.Schema{
+	Name:       "coverage summary",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// pipelineSummary is the machine-readable result of a complete pipeline run
+type pipelineSummary struct {
+	SchemaVersion     int              `json:"schema_version"`
+	Coverage          float64          `json:"coverage"`
+	Threshold         float64          `json:"threshold"`
+	Passed            bool             `json:"passed"`
+	Policy            *policy.Decision `json:"policy,omitempty"`
+	CoveredStatements int              `json:"covered_statements"`
+	TotalStatements   int              `json:"total_statements"`
+	CoverMode         string           `json:"cover_mode,omitempty"`
+	Packages          int              `json:"packages"`
+	Branch            string           `json:"branch"`
+	CommitSHA         string           `json:"commit_sha,omitempty"`
+	PullRequest       int              `json:"pull_request,omitempty"`
+	Trend             string           `json:"trend"`
+	Offline           bool             `json:"offline"`
+	Badge             string           `json:"badge"`
+	Report            string           `json:"report"`
+	GeneratorVersion  string           `json:"generator_version,omitempty"`
+	GeneratedAt       time.Time        `json:"generated_at"`
+}
+
+// newPipelineSummary builds a summary from the parsed coverage, the run configuration and the policy decision
+func newPipelineSummary(coverage *parser.CoverageData, cfg *config.Config, branch, trend string, offline bool, decision *policy.Decision) *pipelineSummary {
+	summary := &pipelineSummary{
+		SchemaVersion:     pipelineSummarySchema.Version(),
+		Coverage:          coverage.Percentage,
+		Threshold:         cfg.Coverage.Threshold,
+		Passed:            coverage.Percentage >= cfg.Coverage.Threshold,
+		Policy:            decision,
+		CoveredStatements: coverage.CoveredLines,
+		TotalStatements:   coverage.TotalLines,
+		CoverMode:         coverage.Mode,
+		Packages:          len(coverage.Packages),
+		Branch:            branch,
+		CommitSHA:         cfg.GitHub.CommitSHA,
+		Trend:             trend,
+		Offline:           offline,
+		GeneratedAt:       time.Now().UTC(),
+	}
+	if decision != nil {
+		summary.Passed = decision.Passed
+	}
+	if cfg.IsPullRequestContext() {
+		summary.PullRequest = cfg.GitHub.PullRequest
+	}
+	return summary
+}
+
+// writePipelineSummary writes the summary as indented JSON with secrets redacted
+func writePipelineSummary(path string, summary *pipelineSummary, redactor *redact.Redactor, mode os.FileMode) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create summary directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(redactor.Bytes(data), '\n'), mode); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
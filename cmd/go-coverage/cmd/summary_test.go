@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/redact"
+)
+
+func TestNewPipelineSummary(t *testing.T) {
+	coverage := &parser.CoverageData{
+		Mode:         "atomic",
+		Percentage:   75,
+		CoveredLines: 3,
+		TotalLines:   4,
+		Packages:     map[string]*parser.PackageCoverage{"pkg": {}},
+	}
+	cfg := &config.Config{
+		Coverage: config.CoverageConfig{Threshold: 80},
+		GitHub:   config.GitHubConfig{CommitSHA: "abc123"},
+	}
+
+	summary := newPipelineSummary(coverage, cfg, "main", "up", false, nil)
+	assert.Equal(t, pipelineSummarySchema.Version(), summary.SchemaVersion)
+	assert.False(t, summary.Passed)
+	assert.Equal(t, 1, summary.Packages)
+	assert.Equal(t, "main", summary.Branch)
+	assert.Equal(t, "abc123", summary.CommitSHA)
+	assert.Equal(t, "atomic", summary.CoverMode)
+	assert.Zero(t, summary.PullRequest)
+}
+
+func TestWritePipelineSummaryRedacts(t *testing.T) {
+	redactor, err := redact.New(nil, `internal\.corp\.example`)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "nested", pipelineSummaryFile)
+	summary := &pipelineSummary{Report: "https://internal.corp.example/coverage.html", Coverage: 50}
+	require.NoError(t, writePipelineSummary(path, summary, redactor, 0o600))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file path
+	require.NoError(t, err)
+	require.True(t, json.Valid(data))
+	assert.NotContains(t, string(data), "internal.corp.example")
+	assert.Contains(t, string(data), redact.Mask)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// teamCoverage is the coverage of the files one team owns
+type teamCoverage struct {
+	team      config.Team
+	coverage  *parser.CoverageData
+	threshold float64
+}
+
+// newTeamCoverage splits the coverage by the configured teams. Teams without a threshold of their
+// own use the global one; teams owning no files of the profile are left out.
+func newTeamCoverage(cfg *config.Config, coverage *parser.CoverageData) []teamCoverage {
+	teams := make([]teamCoverage, 0, len(cfg.Teams.Teams))
+	for _, team := range cfg.Teams.Teams {
+		owned := coverage.Subset(func(file string) bool {
+			return team.Owns(urlutil.CleanModulePathWithRepo(file, cfg.GitHub.Repository))
+		})
+		if len(owned.Packages) == 0 {
+			continue
+		}
+		threshold := cfg.Coverage.Threshold
+		if team.Threshold != nil {
+			threshold = *team.Threshold
+		}
+		teams = append(teams, teamCoverage{team: team, coverage: owned, threshold: threshold})
+	}
+	return teams
+}
+
+// addTeamResults records the threshold of every team in the policy decision, so a team below its
+// threshold fails the run like the global threshold does
+func addTeamResults(decision *policy.Decision, teams []teamCoverage) {
+	for _, team := range teams {
+		result := policy.Result{Rule: policy.RuleTeam, Outcome: policy.OutcomePass,
+			Message: fmt.Sprintf("%s: coverage %.2f%% meets the %.2f%% threshold", team.team.Name, team.coverage.Percentage, team.threshold)}
+		if team.coverage.Percentage < team.threshold {
+			result.Outcome = policy.OutcomeFail
+			result.Message = fmt.Sprintf("%s: coverage %.2f%% is below the %.2f%% threshold", team.team.Name, team.coverage.Percentage, team.threshold)
+		}
+		decision.Add(result)
+	}
+}
+
+// writeTeamDashboards writes a dashboard and a badge for every team below the report directory
+// and returns the entries linking them from the root dashboard
+func writeTeamDashboards(ctx context.Context, cmd *cobra.Command, cfg *config.Config, teams []teamCoverage, branch, reportDir, version string) []dashboard.TeamCoverage {
+	entries := make([]dashboard.TeamCoverage, 0, len(teams))
+	for _, team := range teams {
+		dir, err := parser.SafeOutputPath(filepath.Join(reportDir, cfg.Teams.Dir), team.team.Name)
+		if err != nil {
+			cmd.Printf("   ⚠️  Skipping dashboard of team %s: %v\n", team.team.Name, err)
+			continue
+		}
+		if err = writeTeamDashboard(ctx, cfg, team, branch, dir, version); err != nil {
+			cmd.Printf("   ⚠️  Failed to write dashboard of team %s: %v\n", team.team.Name, err)
+			continue
+		}
+		cmd.Printf("   👥 Team %s: %s/index.html\n", team.team.Name, dir)
+
+		files := 0
+		for _, pkg := range team.coverage.Packages {
+			files += len(pkg.Files)
+		}
+		entries = append(entries, dashboard.TeamCoverage{
+			Name:         team.team.Name,
+			Paths:        team.team.Paths,
+			URL:          path.Join(filepath.ToSlash(cfg.Teams.Dir), team.team.Name) + "/",
+			Files:        files,
+			Coverage:     team.coverage.Percentage,
+			Threshold:    team.threshold,
+			TotalLines:   team.coverage.TotalLines,
+			CoveredLines: team.coverage.CoveredLines,
+		})
+	}
+	return entries
+}
+
+// writeTeamDashboard writes the badge and dashboard of one team into dir
+func writeTeamDashboard(ctx context.Context, cfg *config.Config, team teamCoverage, branch, dir, version string) error {
+	if err := os.MkdirAll(dir, cfg.Storage.DirMode); err != nil {
+		return fmt.Errorf("failed to create team directory: %w", err)
+	}
+
+	badgeOptions := []badge.Option{badge.WithLabel(team.team.Name)}
+	if cfg.Badge.Style != "flat" {
+		badgeOptions = append(badgeOptions, badge.WithStyle(cfg.Badge.Style))
+	}
+	badgeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	svg, err := badge.New().Generate(badgeCtx, team.coverage.Percentage, badgeOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to generate badge: %w", err)
+	}
+	badgePath, err := parser.SafeOutputPath(dir, cfg.Badge.OutputFile)
+	if err != nil {
+		return fmt.Errorf("invalid badge output file: %w", err)
+	}
+	if err = os.WriteFile(badgePath, svg, cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+
+	coverage := team.coverage
+	data := &dashboard.CoverageData{
+		SchemaVersion: dashboard.CoverageDataSchema.Version(),
+		ProjectName:   cfg.Report.Title + " · " + team.team.Name,
+		RepositoryURL: fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repository),
+		Branch:        branch,
+		CommitSHA:     cfg.GitHub.CommitSHA,
+		Timestamp:     time.Now(),
+		TotalCoverage: coverage.Percentage,
+		TotalLines:    coverage.TotalLines,
+		CoveredLines:  coverage.CoveredLines,
+		MissedLines:   coverage.TotalLines - coverage.CoveredLines,
+		Packages:      newPackageDashboardData(cfg, branch, coverage),
+	}
+	for _, pkg := range coverage.Packages {
+		for _, file := range pkg.Files {
+			data.TotalFiles++
+			if file.Percentage > 0 {
+				data.CoveredFiles++
+			} else {
+				data.UncoveredFiles++
+			}
+		}
+	}
+	if languages := coverage.LanguageBreakdown(); len(languages) > 1 {
+		data.Languages = newLanguageDashboardData(languages)
+	}
+
+	// Team dashboards are built from the profile alone, so no API calls are made for them
+	generator := dashboard.NewGenerator(&dashboard.GeneratorConfig{
+		ProjectName:      data.ProjectName,
+		RepositoryOwner:  cfg.GitHub.Owner,
+		RepositoryName:   cfg.GitHub.Repository,
+		TemplateDir:      cfg.Report.TemplateDir,
+		OutputDir:        dir,
+		GeneratorVersion: version,
+	})
+	if err = generator.Generate(ctx, data); err != nil {
+		return fmt.Errorf("failed to generate dashboard: %w", err)
+	}
+	return nil
+}
+
+// newPackageDashboardData converts the packages of a profile for the dashboard, linking packages
+// and files to GitHub when the repository is known
+func newPackageDashboardData(cfg *config.Config, branch string, coverage *parser.CoverageData) []dashboard.PackageCoverage {
+	packages := make([]dashboard.PackageCoverage, 0, len(coverage.Packages))
+	for pkgName, pkg := range coverage.Packages {
+		pkgCoverage := dashboard.PackageCoverage{
+			Name:         pkgName,
+			Path:         pkgName, // Use package name as path for now
+			Coverage:     pkg.Percentage,
+			TotalLines:   pkg.TotalLines,
+			CoveredLines: pkg.CoveredLines,
+			MissedLines:  pkg.TotalLines - pkg.CoveredLines,
+		}
+
+		// Add GitHub URL for package directory if we have GitHub info
+		if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
+			pkgCoverage.GitHubURL = fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s",
+				cfg.GitHub.Owner, cfg.GitHub.Repository, branch, urlutil.RepoPath(cfg.Coverage.ModuleRoot, pkgName))
+		}
+
+		// Add file coverage if available
+		if pkg.Files != nil {
+			pkgCoverage.Files = make([]dashboard.FileCoverage, 0, len(pkg.Files))
+			for fileName, file := range pkg.Files {
+				fileCoverage := dashboard.FileCoverage{
+					Name:         filepath.Base(fileName),
+					Path:         fileName,
+					Coverage:     file.Percentage,
+					TotalLines:   file.TotalLines,
+					CoveredLines: file.CoveredLines,
+					MissedLines:  file.TotalLines - file.CoveredLines,
+					Class:        string(file.Class),
+				}
+				if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
+					fileCoverage.GitHubURL = urlutil.BuildGitHubModuleFileURL(
+						cfg.GitHub.Owner, cfg.GitHub.Repository, branch, cfg.Coverage.ModuleRoot, fileName,
+					)
+				}
+				pkgCoverage.Files = append(pkgCoverage.Files, fileCoverage)
+			}
+		}
+
+		packages = append(packages, pkgCoverage)
+	}
+	return packages
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+// Template preview errors
+var (
+	ErrUnsupportedTemplateKind = errors.New("unsupported template kind")
+	ErrSectionTemplateRequired = errors.New("previewing a dashboard section requires --template")
+)
+
+const (
+	templateKindComment = "comment"
+	templateKindSection = "section"
+)
+
+// commentTemplateConfig returns the template settings of the PR comment
+func commentTemplateConfig(cfg *config.Config, maxCommentLength int) *templates.TemplateConfig {
+	maxFileChanges := 20
+	if cfg.GitHub.CommentAllFiles {
+		maxFileChanges = math.MaxInt32
+	}
+	return &templates.TemplateConfig{
+		IncludeEmojis:          true,
+		IncludeCharts:          true,
+		MaxFileChanges:         maxFileChanges,
+		MaxRecommendations:     5,
+		MaxInsights:            cfg.GitHub.CommentInsights,
+		UseMarkdownTables:      true,
+		UseCollapsibleSections: true,
+		IncludeProgressBars:    true,
+		MaxCommentLength:       maxCommentLength,
+		CustomHeader:           cfg.GitHub.CommentHeader,
+		CustomFooter:           cfg.GitHub.CommentFooter,
+		BrandingEnabled:        cfg.GitHub.CommentBranding,
+		Signature:              cfg.GitHub.CommentSignature,
+	}
+}
+
+// writeCommentTemplateData captures the data a PR comment was rendered from, so custom
+// templates can be previewed against a real pull request
+func writeCommentTemplateData(cfg *config.Config, path string, data *templates.TemplateData) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal comment template data: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), cfg.Storage.DirMode); err != nil {
+		return fmt.Errorf("failed to create template data directory: %w", err)
+	}
+	if err = os.WriteFile(path, content, cfg.Storage.FileMode); err != nil {
+		return fmt.Errorf("failed to write comment template data: %w", err)
+	}
+	return nil
+}
+
+// newTemplatesCmd creates the templates command
+func (c *Commands) newTemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Preview PR comment and dashboard section templates",
+		Long: `Render PR comment templates and custom dashboard sections locally, with sample data or
+data captured from a real run, so templates can be written without opening pull requests.`,
+	}
+
+	cmd.AddCommand(c.newTemplatesPreviewCmd(), c.newTemplatesSampleCmd())
+	return cmd
+}
+
+// newTemplatesPreviewCmd creates the templates preview command
+func (c *Commands) newTemplatesPreviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Render a template with sample or captured data",
+		Long: `Render a PR comment template or a custom dashboard section and print the result.
+
+A comment template is a Go text template executed with the comment data; without --template the
+built-in comment is rendered. A section is a file named like the files of the dashboard template
+directory (top-notes.md, after-metrics-links.html) and is rendered with the dashboard data.
+
+Without --data, sample data is used. Capture the data of a real pull request with
+"comment --template-data", and use the coverage-data.json written by "complete" for sections.`,
+		Example: `  # Built-in comment with sample data
+  go-coverage templates preview
+
+  # Custom comment template with the data captured from a pull request
+  go-coverage comment --dry-run --template-data comment-data.json
+  go-coverage templates preview --template custom.tmpl --data comment-data.json
+
+  # Dashboard section with the data of the last run
+  go-coverage templates preview --kind section --template .github/coverage-templates/sections/top-notes.html --data coverage/coverage-data.json`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			kind, _ := cmd.Flags().GetString("kind")
+			templateFile, _ := cmd.Flags().GetString("template")
+			dataFile, _ := cmd.Flags().GetString("data")
+			outputFile, _ := cmd.Flags().GetString("output")
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			var rendered string
+			switch strings.ToLower(strings.TrimSpace(kind)) {
+			case templateKindComment:
+				rendered, err = previewComment(cmd.Context(), cfg, templateFile, dataFile)
+			case templateKindSection:
+				rendered, err = previewSection(cmd.Context(), cmd, templateFile, dataFile)
+			default:
+				return fmt.Errorf("%w: %q (expected comment or section)", ErrUnsupportedTemplateKind, kind)
+			}
+			if err != nil {
+				return err
+			}
+
+			if outputFile == "" {
+				cmd.Println(rendered)
+				return nil
+			}
+			if err = os.WriteFile(outputFile, []byte(rendered), cfg.Storage.FileMode); err != nil {
+				return fmt.Errorf("failed to write preview: %w", err)
+			}
+			cmd.Printf("✅ Preview written to %s\n", outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("kind", "k", templateKindComment, "Kind of template: comment or section")
+	cmd.Flags().StringP("template", "t", "", "Template file (default: the built-in comment)")
+	cmd.Flags().StringP("data", "d", "", "JSON data file (default: sample data)")
+	cmd.Flags().StringP("output", "o", "", "Write the rendered template to this file instead of stdout")
+	return cmd
+}
+
+// newTemplatesSampleCmd creates the templates sample command
+func (c *Commands) newTemplatesSampleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sample",
+		Short: "Print the sample data templates are previewed with",
+		Long: `Print the sample data of a template kind as JSON, as a starting point for data files that
+exercise the edge cases of a template.`,
+		Example: `  go-coverage templates sample > comment-data.json
+  go-coverage templates sample --kind section > coverage-data.json`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			kind, _ := cmd.Flags().GetString("kind")
+
+			var sample any
+			switch strings.ToLower(strings.TrimSpace(kind)) {
+			case templateKindComment:
+				sample = templates.SampleTemplateData()
+			case templateKindSection:
+				sample = dashboard.SampleCoverageData()
+			default:
+				return fmt.Errorf("%w: %q (expected comment or section)", ErrUnsupportedTemplateKind, kind)
+			}
+
+			content, err := json.MarshalIndent(sample, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal sample data: %w", err)
+			}
+			cmd.Println(string(content))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("kind", "k", templateKindComment, "Kind of template: comment or section")
+	return cmd
+}
+
+// previewComment renders a comment template, or the built-in comment, with captured or sample data
+func previewComment(ctx context.Context, cfg *config.Config, templateFile, dataFile string) (string, error) {
+	data := templates.SampleTemplateData()
+	if dataFile != "" {
+		content, err := os.ReadFile(dataFile) //nolint:gosec // path given on the command line
+		if err != nil {
+			return "", fmt.Errorf("failed to read template data: %w", err)
+		}
+		data = &templates.TemplateData{}
+		if err = json.Unmarshal(content, data); err != nil {
+			return "", fmt.Errorf("failed to parse template data %s: %w", dataFile, err)
+		}
+	}
+
+	var content []byte
+	if templateFile != "" {
+		var err error
+		if content, err = os.ReadFile(templateFile); err != nil { //nolint:gosec // path given on the command line
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+	}
+
+	engine := templates.NewPRTemplateEngine(commentTemplateConfig(cfg, templates.MaxCommentLength))
+	return engine.PreviewComment(ctx, string(content), data)
+}
+
+// previewSection renders a dashboard section file with captured or sample coverage data
+func previewSection(ctx context.Context, cmd *cobra.Command, templateFile, dataFile string) (string, error) {
+	if templateFile == "" {
+		return "", ErrSectionTemplateRequired
+	}
+
+	data := dashboard.SampleCoverageData()
+	if dataFile != "" {
+		content, err := os.ReadFile(dataFile) //nolint:gosec // path given on the command line
+		if err != nil {
+			return "", fmt.Errorf("failed to read coverage data: %w", err)
+		}
+		if data, err = dashboard.DecodeCoverageData(content); err != nil {
+			return "", fmt.Errorf("failed to parse coverage data %s: %w", dataFile, err)
+		}
+	}
+
+	position, html, err := dashboard.NewGenerator(&dashboard.GeneratorConfig{ProjectName: data.ProjectName}).
+		PreviewSection(ctx, templateFile, data)
+	if err != nil {
+		return "", err
+	}
+	cmd.PrintErrf("📍 Section position: %s\n", position)
+	return string(html), nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/templates"
+)
+
+func TestTemplatesPreviewComment(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := t.TempDir()
+
+	output, err := executeCommand(t, "templates", "preview")
+	require.NoError(t, err)
+	assert.Contains(t, output, "## Coverage Metrics")
+	assert.Contains(t, output, "84.6%")
+
+	templateFile := filepath.Join(dir, "custom.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`PR #{{ .PullRequest.Number }}: {{ formatPercent .Coverage.Overall.Percentage }}`), 0o600))
+	output, err = executeCommand(t, "templates", "preview", "--template", templateFile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "PR #42: 84.6%")
+
+	data := templates.SampleTemplateData()
+	data.PullRequest.Number = 7
+	dataFile := filepath.Join(dir, "comment-data.json")
+	content, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dataFile, content, 0o600))
+	outputFile := filepath.Join(dir, "preview.md")
+	_, err = executeCommand(t, "templates", "preview", "--template", templateFile, "--data", dataFile, "--output", outputFile)
+	require.NoError(t, err)
+	written, err := os.ReadFile(outputFile) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Equal(t, "PR #7: 84.6%", string(written))
+
+	require.NoError(t, os.WriteFile(templateFile, []byte(`{{ .Missing`), 0o600))
+	_, err = executeCommand(t, "templates", "preview", "--template", templateFile)
+	require.Error(t, err)
+}
+
+func TestTemplatesPreviewSection(t *testing.T) {
+	isolateOfflineEnv(t)
+	dir := t.TempDir()
+
+	_, err := executeCommand(t, "templates", "preview", "--kind", "section")
+	require.ErrorIs(t, err, ErrSectionTemplateRequired)
+
+	sectionFile := filepath.Join(dir, "top-notes.html")
+	require.NoError(t, os.WriteFile(sectionFile, []byte(`<p>{{.ProjectName}} on {{.Branch}}</p>`), 0o600))
+	output, err := executeCommand(t, "templates", "preview", "--kind", "section", "--template", sectionFile)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Section position: top")
+	assert.Contains(t, output, "<p>project on main</p>")
+
+	_, err = executeCommand(t, "templates", "preview", "--kind", "page")
+	require.ErrorIs(t, err, ErrUnsupportedTemplateKind)
+}
+
+func TestTemplatesSample(t *testing.T) {
+	isolateOfflineEnv(t)
+
+	output, err := executeCommand(t, "templates", "sample")
+	require.NoError(t, err)
+	var data templates.TemplateData
+	require.NoError(t, json.Unmarshal([]byte(output), &data))
+	assert.Equal(t, 42, data.PullRequest.Number)
+
+	output, err = executeCommand(t, "templates", "sample", "--kind", "section")
+	require.NoError(t, err)
+	assert.Contains(t, output, `"schema_version"`)
+
+	_, err = executeCommand(t, "templates", "sample", "--kind", "page")
+	require.ErrorIs(t, err, ErrUnsupportedTemplateKind)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// newTestHelperDashboardData converts the coverage of the test helper packages left out of the
+// totals into the dashboard's appendix
+func newTestHelperDashboardData(cfg *config.Config, branch string, helpers *parser.CoverageData) *dashboard.TestHelperCoverage {
+	if helpers == nil || len(helpers.Packages) == 0 {
+		return nil
+	}
+
+	files := 0
+	for _, pkg := range helpers.Packages {
+		files += len(pkg.Files)
+	}
+	return &dashboard.TestHelperCoverage{
+		Files:        files,
+		Coverage:     helpers.Percentage,
+		TotalLines:   helpers.TotalLines,
+		CoveredLines: helpers.CoveredLines,
+		Packages:     newPackageDashboardData(cfg, branch, helpers),
+	}
+}
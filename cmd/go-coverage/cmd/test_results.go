@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/testrun"
+)
+
+// flagNameTestResults is the flag naming the go test -json output or JUnit report of the run
+const flagNameTestResults = "test-results"
+
+// addTestResultsFlag adds the flag naming the test results of the run
+func addTestResultsFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagNameTestResults, "", "go test -json output or JUnit XML report of the run, for test efficiency tracking (default: GO_COVERAGE_TEST_RESULTS)")
+}
+
+// applyTestResultsFlag lets the flag override GO_COVERAGE_TEST_RESULTS
+func applyTestResultsFlag(cmd *cobra.Command, cfg *config.Config) {
+	if path, _ := cmd.Flags().GetString(flagNameTestResults); path != "" {
+		cfg.Analytics.TestResults = path
+	}
+}
+
+// parseTestResults reads the test results of the run named by GO_COVERAGE_TEST_RESULTS or the flag
+func parseTestResults(cfg *config.Config) (*testrun.Summary, error) {
+	summary, err := testrun.ParseFile(cfg.Analytics.TestResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse test results %s: %w", cfg.Analytics.TestResults, err)
+	}
+	return summary, nil
+}
+
+// newTestEfficiencyData follows the covered statements per test second over the earlier runs
+// with test results, newest first, and the current run, flagging test time that grew more than
+// the configured ratio faster than coverage since the oldest of them
+func newTestEfficiencyData(cfg *config.Config, tests *testrun.Summary, coverage *parser.CoverageData, entries []history.Entry) *dashboard.TestEfficiency {
+	if tests == nil {
+		return nil
+	}
+
+	efficiency := &dashboard.TestEfficiency{
+		Tests:      tests.Tests,
+		Seconds:    tests.Seconds,
+		Efficiency: testrun.Efficiency(coverage.CoveredLines, tests.Seconds),
+	}
+	var first *history.Entry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Tests == nil || entry.Coverage == nil || entry.Tests.Seconds <= 0 || entry.CommitSHA == cfg.GitHub.CommitSHA {
+			continue
+		}
+		efficiency.Points = append(efficiency.Points, dashboard.EfficiencyPoint{
+			Timestamp:  entry.Timestamp,
+			CommitSHA:  entry.CommitSHA,
+			Seconds:    entry.Tests.Seconds,
+			Efficiency: testrun.Efficiency(entry.Coverage.CoveredLines, entry.Tests.Seconds),
+		})
+		first = entry
+	}
+	slices.Reverse(efficiency.Points)
+	efficiency.Points = append(efficiency.Points, dashboard.EfficiencyPoint{
+		CommitSHA:  cfg.GitHub.CommitSHA,
+		Seconds:    tests.Seconds,
+		Efficiency: efficiency.Efficiency,
+	})
+
+	if first != nil {
+		growth, ok := testrun.CompareGrowth(
+			testrun.Sample{Seconds: first.Tests.Seconds, Covered: first.Coverage.CoveredLines},
+			testrun.Sample{Seconds: tests.Seconds, Covered: coverage.CoveredLines})
+		if ok {
+			efficiency.TimeGrowth = growth.Time * 100
+			efficiency.CoverageGrowth = growth.Coverage * 100
+			efficiency.Outpaced = growth.Outpaces(cfg.Analytics.TestTimeGrowthRatio)
+		}
+	}
+	return efficiency
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/testrun"
+)
+
+func TestNewTestEfficiencyData(t *testing.T) {
+	cfg := &config.Config{
+		GitHub:    config.GitHubConfig{CommitSHA: "head"},
+		Analytics: config.AnalyticsConfig{TestTimeGrowthRatio: 2},
+	}
+	now := time.Now()
+	entries := []history.Entry{ // newest first, as the history returns them
+		{CommitSHA: "head", Coverage: &parser.CoverageData{CoveredLines: 1}, Tests: &testrun.Summary{Seconds: 1}},
+		{CommitSHA: "second", Timestamp: now.Add(-time.Hour), Coverage: &parser.CoverageData{CoveredLines: 1050}, Tests: &testrun.Summary{Seconds: 25}},
+		{CommitSHA: "untimed", Coverage: &parser.CoverageData{CoveredLines: 1020}},
+		{CommitSHA: "first", Timestamp: now.Add(-2 * time.Hour), Coverage: &parser.CoverageData{CoveredLines: 1000}, Tests: &testrun.Summary{Seconds: 20}},
+	}
+
+	assert.Nil(t, newTestEfficiencyData(cfg, nil, &parser.CoverageData{}, entries))
+
+	tests := &testrun.Summary{Tests: 80, Seconds: 30}
+	efficiency := newTestEfficiencyData(cfg, tests, &parser.CoverageData{CoveredLines: 1100}, entries)
+	require.NotNil(t, efficiency)
+	assert.InDelta(t, 1100.0/30, efficiency.Efficiency, 1e-9)
+	require.Len(t, efficiency.Points, 3)
+	assert.Equal(t, []string{"first", "second", "head"},
+		[]string{efficiency.Points[0].CommitSHA, efficiency.Points[1].CommitSHA, efficiency.Points[2].CommitSHA})
+	assert.InDelta(t, 50, efficiency.TimeGrowth, 1e-9)
+	assert.InDelta(t, 10, efficiency.CoverageGrowth, 1e-9)
+	assert.True(t, efficiency.Outpaced)
+
+	cfg.Analytics.TestTimeGrowthRatio = 0
+	assert.False(t, newTestEfficiencyData(cfg, tests, &parser.CoverageData{CoveredLines: 1100}, entries).Outpaced)
+
+	// The first run with test results has nothing to compare with
+	efficiency = newTestEfficiencyData(cfg, tests, &parser.CoverageData{CoveredLines: 1100}, nil)
+	assert.Len(t, efficiency.Points, 1)
+	assert.False(t, efficiency.Outpaced)
+}
+
+func TestAddToHistoryTestResults(t *testing.T) {
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	require.NoError(t, os.WriteFile(coverageFile, []byte("mode: set\ngithub.com/test/repo/main.go:10.2,12.16 1 1\n"), 0o600))
+	resultsFile := filepath.Join(tempDir, "junit.xml")
+	require.NoError(t, os.WriteFile(resultsFile, []byte(`<testsuite tests="3" time="1.5"></testsuite>`), 0o600))
+
+	cfg := &config.Config{
+		History:   config.HistoryConfig{StoragePath: filepath.Join(tempDir, "history")},
+		Analytics: config.AnalyticsConfig{TestResults: resultsFile},
+	}
+	tracker := history.NewWithConfig(&history.Config{StoragePath: cfg.History.StoragePath})
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+
+	ctx := context.Background()
+	require.NoError(t, addToHistory(ctx, tracker, coverageFile, "main", "abc123", "", "", cfg, cmd))
+	latest, err := tracker.GetLatestEntry(ctx, "main")
+	require.NoError(t, err)
+	require.NotNil(t, latest.Tests)
+	assert.Equal(t, testrun.FormatJUnit, latest.Tests.Format)
+	assert.Equal(t, 3, latest.Tests.Tests)
+
+	cfg.Analytics.TestResults = filepath.Join(tempDir, "missing.json")
+	err = addToHistory(ctx, tracker, coverageFile, "main", "def456", "", "", cfg, cmd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse test results")
+}
@@ -1,5 +1,14 @@
 package cmd
 
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
 const (
 	testVersionStr    = "1.2.3"
 	flagDryRun        = "dry-run"
@@ -16,3 +25,32 @@ const (
 	flagTypeString    = "string"
 	testFullRepo      = "testowner/testrepo"
 )
+
+// executeCommand runs the command line args and returns its combined output
+func executeCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	commands := NewCommands(VersionInfo{Version: testVersionStr})
+	var buf bytes.Buffer
+	commands.Root.SetOut(&buf)
+	commands.Root.SetErr(&buf)
+	commands.Root.SetArgs(args)
+	err := commands.Execute()
+	return buf.String(), err
+}
+
+// setupProviderProfile clears the CI provider variables in env, isolates the configuration and
+// writes the head profile of the compare tests with a 40% threshold, returning its path
+func setupProviderProfile(t *testing.T, env ...string) string {
+	t.Helper()
+	isolateOfflineEnv(t)
+	for _, name := range env {
+		t.Setenv(name, "")
+	}
+
+	dir := t.TempDir()
+	profile := filepath.Join(dir, "coverage.txt")
+	require.NoError(t, os.WriteFile(profile, []byte(compareHeadProfile), 0o600))
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(dir, "history"))
+	t.Setenv("GO_COVERAGE_THRESHOLD", "40")
+	return profile
+}
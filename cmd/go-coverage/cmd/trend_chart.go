@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/badge"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+)
+
+// trendChartFile is the static trend chart written next to the dashboard, for embedding in
+// READMEs and PR comments
+const trendChartFile = "coverage-trend.svg"
+
+// newTrendChart analyzes history entries, newest first, into the chart payload of a trend
+// report, or returns nil when there are too few runs to analyze
+func newTrendChart(ctx context.Context, entries []history.Entry) *analytics.TrendChart {
+	analyzer := analytics.NewTrendAnalyzer(nil)
+	analyzer.LoadEntries(entries)
+	report, err := analyzer.AnalyzeTrends(ctx)
+	if err != nil {
+		return nil
+	}
+	return report.ChartData
+}
+
+// writeBadgeChart writes the line chart of the last runs of a branch, ending with the current
+// coverage, next to the badge in each distinct directory. A branch without earlier runs gets no
+// chart.
+func writeBadgeChart(ctx context.Context, cmd *cobra.Command, cfg *config.Config, tracker *history.Tracker, branch string, current float64, dirs ...string) {
+	if cfg.Badge.ChartPoints < 2 {
+		return
+	}
+	previous, err := previousCoverage(ctx, tracker, branch, cfg.History.RetentionDays, cfg.Badge.ChartPoints-1)
+	if err != nil {
+		cmd.Printf("   ⚠️  Failed to load runs for the badge chart: %v\n", err)
+		return
+	}
+	if len(previous) == 0 {
+		return
+	}
+
+	// History is newest first, the chart oldest first
+	points := slices.Clone(previous)
+	slices.Reverse(points)
+	points = append(points, current)
+	chart, err := badge.New().GenerateTrendChart(ctx, points, cfg.Coverage.Threshold)
+	if err != nil {
+		cmd.Printf("   ⚠️  Failed to generate badge chart: %v\n", err)
+		return
+	}
+
+	for _, dir := range slices.Compact(dirs) {
+		chartPath := filepath.Join(dir, badge.TrendChartFile(cfg.Badge.OutputFile))
+		if writeErr := os.WriteFile(chartPath, chart, cfg.Storage.FileMode); writeErr != nil {
+			cmd.Printf("   ⚠️  Failed to write badge chart: %v\n", writeErr)
+			continue
+		}
+		cmd.Printf("   ✅ Badge chart saved: %s\n", chartPath)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestWriteBadgeChart(t *testing.T) {
+	isolateOfflineEnv(t)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Coverage.Threshold = 70
+
+	tracker := history.NewWithConfig(&history.Config{StoragePath: filepath.Join(t.TempDir(), "history"), MaxEntries: 100})
+	outputDir := t.TempDir()
+	chartPath := filepath.Join(outputDir, "coverage-chart.svg")
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+
+	writeBadgeChart(context.Background(), cmd, cfg, tracker, "main", 80, outputDir, outputDir)
+	assert.NoFileExists(t, chartPath, "a branch without earlier runs gets no chart")
+
+	for _, percentage := range []float64{72, 75} {
+		require.NoError(t, tracker.Record(context.Background(), &parser.CoverageData{Percentage: percentage, TotalLines: 100}, history.WithBranch("main")))
+	}
+	writeBadgeChart(context.Background(), cmd, cfg, tracker, "main", 80, outputDir, outputDir)
+	content, err := os.ReadFile(chartPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "over the last 3 runs")
+	assert.Contains(t, string(content), "threshold 70.0%")
+
+	cfg.Badge.ChartPoints = 0
+	require.NoError(t, os.Remove(chartPath))
+	writeBadgeChart(context.Background(), cmd, cfg, tracker, "main", 80, outputDir)
+	assert.NoFileExists(t, chartPath)
+}
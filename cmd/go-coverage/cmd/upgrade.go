@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mrz1836/go-coverage/internal/config"
 	"github.com/mrz1836/go-coverage/internal/version"
 )
 
@@ -47,6 +50,11 @@ This command will:
   # Force upgrade even if already on latest
   go-coverage upgrade --force`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, _ := config.Load()
+			if err := requireNetwork(cmd, cfg, "the upgrade command"); err != nil {
+				return err
+			}
+
 			config := UpgradeConfig{}
 			var err error
 
@@ -88,7 +96,7 @@ func (c *Commands) runUpgradeWithConfig(cmd *cobra.Command, config UpgradeConfig
 
 	// Fetch latest release
 	cmd.Printf("Checking for updates...\n")
-	release, err := version.GetLatestRelease("mrz1836", "go-coverage")
+	release, err := version.GetLatestReleaseWithClient(newUpgradeHTTPClient(cmd), "mrz1836", "go-coverage")
 	if err != nil {
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
@@ -262,3 +270,21 @@ func isDevelopmentVersion(version string) bool {
 	}
 	return false
 }
+
+// newUpgradeHTTPClient returns an HTTP client honoring the configured proxy and TLS settings,
+// falling back to a plain client when the configuration cannot be applied
+func newUpgradeHTTPClient(cmd *cobra.Command) *http.Client {
+	const timeout = 10 * time.Second
+
+	cfg, err := config.Load()
+	if err != nil {
+		return &http.Client{Timeout: timeout}
+	}
+
+	client, err := cfg.NewHTTPClient(timeout)
+	if err != nil {
+		cmd.Printf("⚠️  Ignoring network settings: %v\n", err)
+		return &http.Client{Timeout: timeout}
+	}
+	return client
+}
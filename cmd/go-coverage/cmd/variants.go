@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/urlutil"
+)
+
+// flagNameVariant is the repeatable name=path flag for build tag variant profiles
+const flagNameVariant = "variant"
+
+// parseVariants parses the profile of every build tag variant and merges them into one profile
+func parseVariants(ctx context.Context, p *parser.Parser, args []string) (*parser.CoverageData, *parser.VariantBreakdown, error) {
+	variants := make([]parser.Variant, 0, len(args))
+	for _, arg := range args {
+		name, path, err := parser.ParseVariantArg(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		coverage, err := p.ParseFile(ctx, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s variant: %w", name, err)
+		}
+		variants = append(variants, parser.Variant{Name: name, Coverage: coverage})
+	}
+	return p.MergeVariants(variants)
+}
+
+// newVariantDashboardData converts a variant breakdown into the dashboard's build tag dimension
+func newVariantDashboardData(cfg *config.Config, branch string, breakdown *parser.VariantBreakdown) *dashboard.VariantCoverage {
+	if breakdown == nil {
+		return nil
+	}
+
+	variants := &dashboard.VariantCoverage{
+		Tags:           make([]dashboard.TagCoverage, 0, len(breakdown.Variants)),
+		UncoveredLines: breakdown.UncoveredStatements,
+		Files:          make([]dashboard.VariantFileCoverage, 0, len(breakdown.Files)),
+	}
+	for _, variant := range breakdown.Variants {
+		variants.Tags = append(variants.Tags, dashboard.TagCoverage{
+			Name:           variant.Name,
+			Coverage:       variant.Percentage,
+			TotalLines:     variant.TotalStatements,
+			CoveredLines:   variant.CoveredStatements,
+			ExclusiveLines: variant.ExclusiveStatements,
+		})
+	}
+	for _, file := range breakdown.Files {
+		fileCoverage := dashboard.VariantFileCoverage{
+			Path:           file.Path,
+			ExclusiveLines: file.Exclusive,
+			UncoveredLines: file.Uncovered,
+		}
+		if cfg.GitHub.Owner != "" && cfg.GitHub.Repository != "" {
+			fileCoverage.GitHubURL = urlutil.BuildGitHubModuleFileURL(cfg.GitHub.Owner, cfg.GitHub.Repository, branch, cfg.Coverage.ModuleRoot, file.Path)
+		}
+		variants.Files = append(variants.Files, fileCoverage)
+	}
+	return variants
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/codeowners"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/waivers"
+)
+
+var (
+	// ErrUnsupportedWaiversFormat is returned for an unknown --format of the waivers command
+	ErrUnsupportedWaiversFormat = errors.New("unsupported waivers format")
+	// ErrExpiredWaivers indicates exclusions whose waivers expired and must be revisited
+	ErrExpiredWaivers = errors.New("coverage waivers expired")
+)
+
+// Output formats supported by the waivers command
+const (
+	waiversFormatText = "text"
+	waiversFormatJSON = "json"
+)
+
+// newWaiversCmd creates the waivers command
+func (c *Commands) newWaiversCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "waivers",
+		Short: "List coverage exclusions with their reasons, owners and expiry dates",
+		Long: `List every path and file pattern excluded from coverage with the waiver that explains it:
+its reason, its owners and its expiry date. Waivers are set in GO_COVERAGE_WAIVERS; owners
+not named by a waiver come from CODEOWNERS. Exclusions past their expiry date are listed
+first, to be revisited, followed by those expiring within 30 days and those nobody gave a
+reason for.
+
+With --fail-expired, or GO_COVERAGE_FAIL_EXPIRED_WAIVERS, the command exits with an error
+when a waiver has expired.`,
+		Example: `  GO_COVERAGE_WAIVERS="internal/legacy/: reason=rewrite in progress, owner=@org/core, expires=2026-12-31" \
+    go-coverage waivers
+  go-coverage waivers --format json --fail-expired`,
+		RunE: c.runWaivers,
+	}
+
+	cmd.Flags().String("format", waiversFormatText, "Output format (text or json)")
+	cmd.Flags().Bool("fail-expired", false, "Exit with an error when a waiver has expired (default: GO_COVERAGE_FAIL_EXPIRED_WAIVERS)")
+	return cmd
+}
+
+// runWaivers executes the waivers command
+func (c *Commands) runWaivers(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != waiversFormatText && format != waiversFormatJSON {
+		return fmt.Errorf("%w: %q (expected text or json)", ErrUnsupportedWaiversFormat, format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	failExpired := cfg.Coverage.FailExpiredWaivers
+	if cmd.Flags().Changed("fail-expired") {
+		failExpired, _ = cmd.Flags().GetBool("fail-expired")
+	}
+
+	report := buildWaiverReport(cmd, cfg, time.Now())
+	if format == waiversFormatJSON {
+		data, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to encode waivers report: %w", marshalErr)
+		}
+		cmd.Println(string(data))
+	} else {
+		cmd.Print(renderWaiverReport(report))
+	}
+
+	if failExpired && report.Expired > 0 {
+		return fmt.Errorf("%w: %d exclusion(s) past their expiry date", ErrExpiredWaivers, report.Expired)
+	}
+	return nil
+}
+
+// buildWaiverReport reports the configured exclusions, with owners from CODEOWNERS when it exists
+func buildWaiverReport(cmd *cobra.Command, cfg *config.Config, now time.Time) *waivers.Report {
+	var owners func(string) []string
+	if root, err := cfg.GetRepositoryRoot(); err == nil {
+		file, loadErr := codeowners.Load(root)
+		switch {
+		case loadErr == nil:
+			owners = file.Owners
+		case !errors.Is(loadErr, codeowners.ErrNotFound):
+			cmd.Printf("⚠️  Failed to read CODEOWNERS: %v\n", loadErr)
+		}
+	}
+	return waivers.Build(cfg.Coverage.ExcludePaths, cfg.Coverage.ExcludeFiles, cfg.Coverage.Waivers, owners, now)
+}
+
+// renderWaiverReport renders the exclusions as a console table
+func renderWaiverReport(report *waivers.Report) string {
+	if len(report.Exclusions) == 0 {
+		return "No paths or files are excluded from coverage\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧾 %d exclusion(s): %d expired, %d expiring, %d without a reason\n\n",
+		len(report.Exclusions), report.Expired, report.Expiring, report.Unexplained)
+	fmt.Fprintf(&sb, "%-12s %-5s %-30s %-11s %-20s %s\n", "STATUS", "KIND", "PATTERN", "EXPIRES", "OWNERS", "REASON")
+	for _, exclusion := range report.Exclusions {
+		expires := "-"
+		if !exclusion.Expires.IsZero() {
+			expires = exclusion.Expires.Format(waivers.DateLayout)
+		}
+		owners := strings.Join(exclusion.Owners, " ")
+		if owners == "" {
+			owners = "-"
+		}
+		reason := exclusion.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(&sb, "%-12s %-5s %-30s %-11s %-20s %s\n", exclusion.Status, exclusion.Kind, exclusion.Pattern, expires, owners, reason)
+	}
+	return sb.String()
+}
+
+// addWaiverResults adds a result per expired waiver, failing the gate when
+// GO_COVERAGE_FAIL_EXPIRED_WAIVERS is set and warning otherwise
+func addWaiverResults(cfg *config.Config, decision *policy.Decision, report *waivers.Report) {
+	outcome := policy.OutcomeWarn
+	if cfg.Coverage.FailExpiredWaivers {
+		outcome = policy.OutcomeFail
+	}
+	for _, exclusion := range report.ExpiredWaivers() {
+		decision.Add(policy.Result{Rule: policy.RuleWaiver, Outcome: outcome,
+			Message: fmt.Sprintf("%s: waiver expired on %s, revisit the exclusion", exclusion.Pattern, exclusion.Expires.Format(waivers.DateLayout))})
+	}
+}
+
+// printWaiverSummary prints the expired and expiring waivers of the complete command
+func printWaiverSummary(cmd *cobra.Command, report *waivers.Report) {
+	if report.Expired == 0 && report.Expiring == 0 {
+		return
+	}
+	cmd.Printf("🧾 Coverage waivers: %d expired, %d expiring\n", report.Expired, report.Expiring)
+	for _, exclusion := range report.Exclusions {
+		if exclusion.Status == waivers.StatusExpired || exclusion.Status == waivers.StatusExpiring {
+			cmd.Printf("   %s %s (%s, %s)\n", exclusion.Pattern, exclusion.Status, exclusion.Expires.Format(waivers.DateLayout), strings.Join(exclusion.Owners, " "))
+		}
+	}
+	cmd.Printf("\n")
+}
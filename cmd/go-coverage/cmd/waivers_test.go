@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/waivers"
+)
+
+func TestWaiverReport(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Coverage.RepoRoot = t.TempDir()
+	cfg.Coverage.ExcludePaths = []string{"vendor/", "internal/legacy/"}
+	cfg.Coverage.ExcludeFiles = []string{"*_mock.go"}
+	cfg.Coverage.Waivers = []waivers.Waiver{
+		{Pattern: "internal/legacy/", Reason: "rewrite in progress", Owner: "@org/core", Expires: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	var output bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&output)
+	report := buildWaiverReport(cmd, cfg, now)
+	assert.Empty(t, output.String(), "a missing CODEOWNERS is not an error")
+
+	rendered := renderWaiverReport(report)
+	assert.Contains(t, rendered, "3 exclusion(s): 1 expired, 0 expiring, 1 without a reason")
+	lines := strings.Split(strings.TrimSpace(rendered), "\n")
+	require.Len(t, lines, 6)
+	assert.Regexp(t, `^expired\s+path\s+internal/legacy/\s+2026-10-01\s+@org/core\s+rewrite in progress$`, lines[3])
+	assert.Regexp(t, `^unexplained\s+file\s+\*_mock.go\s+-\s+-\s+-$`, lines[4])
+	assert.Equal(t, "No paths or files are excluded from coverage\n", renderWaiverReport(&waivers.Report{}))
+
+	printWaiverSummary(cmd, report)
+	assert.Contains(t, output.String(), "Coverage waivers: 1 expired, 0 expiring")
+	assert.Contains(t, output.String(), "internal/legacy/ expired (2026-10-01, @org/core)")
+
+	decision := &policy.Decision{Passed: true}
+	addWaiverResults(cfg, decision, report)
+	require.Len(t, decision.Results, 1)
+	assert.Equal(t, policy.OutcomeWarn, decision.Results[0].Outcome)
+	assert.True(t, decision.Passed, "expired waivers only warn by default")
+
+	cfg.Coverage.FailExpiredWaivers = true
+	decision = &policy.Decision{Passed: true}
+	addWaiverResults(cfg, decision, report)
+	assert.True(t, decision.Failed(policy.RuleWaiver))
+	assert.False(t, decision.Passed)
+}
+
+func TestRunWaivers(t *testing.T) {
+	t.Setenv("GO_COVERAGE_REPO_ROOT", t.TempDir())
+	t.Setenv("GO_COVERAGE_WAIVERS", "internal/legacy/: reason=rewrite, expires=2020-01-01")
+
+	cmds := NewCommands(VersionInfo{Version: testVersionStr})
+	var output bytes.Buffer
+	cmds.Root.SetOut(&output)
+	cmds.Root.SetArgs([]string{"waivers", "--format", "json"})
+	require.NoError(t, cmds.Root.Execute())
+	assert.Contains(t, output.String(), `"status": "expired"`)
+
+	cmds = NewCommands(VersionInfo{Version: testVersionStr})
+	cmds.Root.SetOut(&output)
+	cmds.Root.SetErr(&output)
+	cmds.Root.SetArgs([]string{"waivers", "--fail-expired"})
+	require.ErrorIs(t, cmds.Root.Execute(), ErrExpiredWaivers)
+
+	cmds = NewCommands(VersionInfo{Version: testVersionStr})
+	cmds.Root.SetOut(&output)
+	cmds.Root.SetErr(&output)
+	cmds.Root.SetArgs([]string{"waivers", "--format", "csv"})
+	require.ErrorIs(t, cmds.Root.Execute(), ErrUnsupportedWaiversFormat)
+}
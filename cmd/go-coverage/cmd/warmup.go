@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// warmupState is where the history of the main branch stands against the warm-up limits
+type warmupState struct {
+	Runs   int       // Runs recorded on the main branch before this one
+	Since  time.Time // Timestamp of the oldest of them, zero without history
+	Now    time.Time
+	Active bool // The gates of this run are reported as warnings
+	Ended  bool // This run is the first whose gates are enforced
+}
+
+// newWarmupState places the run against the warm-up limits, given the runs of the main branch,
+// newest first. A rerun of the commit does not count as an earlier run.
+func newWarmupState(cfg *config.Config, entries []history.Entry, now time.Time) warmupState {
+	earlier := make([]history.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.CommitSHA == "" || entry.CommitSHA != cfg.GitHub.CommitSHA {
+			earlier = append(earlier, entry)
+		}
+	}
+
+	state := warmupState{Runs: len(earlier), Now: now}
+	if len(earlier) > 0 {
+		state.Since = earlier[len(earlier)-1].Timestamp
+	}
+	state.Active = inWarmup(cfg.Policy, state.Runs, state.Since, now)
+
+	// The newest earlier run saw the runs before it
+	if !state.Active && len(earlier) > 0 {
+		var since time.Time
+		if len(earlier) > 1 {
+			since = state.Since
+		}
+		state.Ended = inWarmup(cfg.Policy, len(earlier)-1, since, earlier[0].Timestamp)
+	}
+	return state
+}
+
+// inWarmup reports whether a run with the given earlier runs, the oldest recorded at since, is
+// still warming up: warm-up ends once either configured limit is reached
+func inWarmup(p config.PolicyConfig, runs int, since, now time.Time) bool {
+	if !p.WarmupEnabled() {
+		return false
+	}
+	if p.WarmupRuns > 0 && runs >= p.WarmupRuns {
+		return false
+	}
+	if p.WarmupDays > 0 && !since.IsZero() && now.Sub(since) >= time.Duration(p.WarmupDays)*24*time.Hour {
+		return false
+	}
+	return true
+}
+
+// progress describes how far the warm-up has come, e.g. "run 3 of 10, day 2 of 14"
+func (w warmupState) progress(p config.PolicyConfig) string {
+	var parts []string
+	if p.WarmupRuns > 0 {
+		parts = append(parts, fmt.Sprintf("run %d of %d", w.Runs+1, p.WarmupRuns))
+	}
+	if p.WarmupDays > 0 {
+		day := 1
+		if !w.Since.IsZero() {
+			day += int(w.Now.Sub(w.Since) / (24 * time.Hour))
+		}
+		parts = append(parts, fmt.Sprintf("day %d of %d", day, p.WarmupDays))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadWarmupState reads the runs of the main branch from the history when warm-up is enabled;
+// without history there is nothing to count, so the gates are enforced
+func loadWarmupState(ctx context.Context, cmd *cobra.Command, cfg *config.Config) warmupState {
+	if !cfg.Policy.WarmupEnabled() || !cfg.History.Enabled {
+		return warmupState{}
+	}
+	tracker := history.NewWithConfig(&history.Config{
+		StoragePath:    historyStoragePath(cfg),
+		Repository:     cfg.RepositorySlug(),
+		RetentionDays:  cfg.History.RetentionDays,
+		MaxEntries:     cfg.History.MaxEntries,
+		AutoCleanup:    false,
+		MetricsEnabled: false,
+	})
+	trend, err := tracker.GetTrend(ctx,
+		history.WithTrendBranch(getPrimaryMainBranch()),
+		history.WithTrendDays(max(cfg.History.RetentionDays, cfg.Policy.WarmupDays)),
+		history.WithMaxDataPoints(max(cfg.History.MaxEntries, cfg.Policy.WarmupRuns+1)))
+	if err != nil {
+		cmd.Printf("Warning: failed to load the history for the warm-up: %v\n", err)
+		return warmupState{}
+	}
+	return newWarmupState(cfg, trend.Entries, time.Now())
+}
+
+// applyWarmup reports the failed rules of decision as warnings while the repository warms up.
+// An emergency bypass already downgraded them and is reported instead.
+func applyWarmup(cfg *config.Config, decision *policy.Decision, state warmupState) {
+	if state.Active && decision.Bypass == "" {
+		decision.WarmUp(state.progress(cfg.Policy))
+	}
+}
+
+// printWarmup announces the warm-up, or the switch to enforced gates, in the job log
+func printWarmup(cmd *cobra.Command, cfg *config.Config, state warmupState) {
+	switch {
+	case state.Active:
+		cmd.Printf("🌱 Warm-up (%s): failed coverage gates are reported as warnings while the history accumulates\n", state.progress(cfg.Policy))
+	case state.Ended:
+		cmd.Printf("🎓 Warm-up complete after %d runs: coverage gates are now enforced\n", state.Runs)
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestNewWarmupState(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	runs := func(ages ...time.Duration) []history.Entry { // newest first
+		entries := make([]history.Entry, 0, len(ages))
+		for i, age := range ages {
+			entries = append(entries, history.Entry{CommitSHA: string(rune('a' + i)), Timestamp: now.Add(-age)})
+		}
+		return entries
+	}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		policy   config.PolicyConfig
+		entries  []history.Entry
+		active   bool
+		ended    bool
+		progress string
+	}{
+		{"disabled", config.PolicyConfig{}, nil, false, false, ""},
+		{"first run", config.PolicyConfig{WarmupRuns: 3}, nil, true, false, "run 1 of 3"},
+		{"last warm-up run", config.PolicyConfig{WarmupRuns: 3}, runs(time.Hour, 2*time.Hour), true, false, "run 3 of 3"},
+		{"first enforced run", config.PolicyConfig{WarmupRuns: 3}, runs(time.Hour, 2*time.Hour, 3*time.Hour), false, true, ""},
+		{"enforced", config.PolicyConfig{WarmupRuns: 3}, runs(time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour), false, false, ""},
+		{"within days", config.PolicyConfig{WarmupDays: 14}, runs(time.Hour, 3*day), true, false, "day 4 of 14"},
+		{"days elapsed", config.PolicyConfig{WarmupDays: 14}, runs(time.Hour, 14*day), false, true, ""},
+		{"enforced after days", config.PolicyConfig{WarmupDays: 14}, runs(time.Hour, 15*day), false, false, ""},
+		{"either limit ends it", config.PolicyConfig{WarmupRuns: 10, WarmupDays: 14}, runs(time.Hour, 14*day), false, true, ""},
+		{"both limits", config.PolicyConfig{WarmupRuns: 10, WarmupDays: 14}, runs(time.Hour, day+time.Hour), true, false, "run 3 of 10, day 2 of 14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Policy: tt.policy}
+			state := newWarmupState(cfg, tt.entries, now)
+			assert.Equal(t, tt.active, state.Active)
+			assert.Equal(t, tt.ended, state.Ended)
+			if tt.active {
+				assert.Equal(t, tt.progress, state.progress(tt.policy))
+			}
+		})
+	}
+
+	// A rerun of the commit does not count toward the warm-up
+	cfg := &config.Config{Policy: config.PolicyConfig{WarmupRuns: 2}, GitHub: config.GitHubConfig{CommitSHA: "a"}}
+	assert.True(t, newWarmupState(cfg, runs(time.Hour, 2*time.Hour), now).Active)
+}
+
+func TestWarmedUpGates(t *testing.T) {
+	cfg := &config.Config{Coverage: config.CoverageConfig{Threshold: 80}, Policy: config.PolicyConfig{MaxDrop: -1, WarmupRuns: 5}}
+	coverage := &parser.CoverageData{Percentage: 70}
+
+	decision := evaluatePolicy(cfg, coverage, nil, nil, nil)
+	applyWarmup(cfg, decision, warmupState{})
+	assert.False(t, decision.Passed, "warm-up is over")
+
+	applyWarmup(cfg, decision, warmupState{Runs: 1, Active: true})
+	require.NoError(t, policyError(cfg, coverage.Percentage, decision))
+	gates := &gateResult{Coverage: coverage, Decision: decision}
+	assert.Equal(t, "70.00% coverage, warm-up", describeGates(gates, "main"))
+	assert.Contains(t, renderGateMarkdown("<!-- marker -->", gates, "main", ""), "### 🌱 Coverage policy warming up\n\nrun 2 of 5")
+	assert.Equal(t, "run 2 of 5", newPolicyTemplateData(decision).Warmup)
+
+	// An emergency bypass is reported rather than the warm-up
+	decision = evaluatePolicy(cfg, coverage, nil, nil, nil)
+	applyBypass(decision, "bypass token [hotfix] in \"Fix\"")
+	applyWarmup(cfg, decision, warmupState{Active: true})
+	assert.Empty(t, decision.Warmup)
+}
+
+func TestCompleteCommandWarmup(t *testing.T) {
+	isolateOfflineEnv(t)
+	t.Setenv("GO_COVERAGE_LOCAL", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GO_COVERAGE_THRESHOLD", "90")
+	t.Setenv("GO_COVERAGE_POLICY_WARMUP_RUNS", "2")
+
+	tempDir := t.TempDir()
+	coverageFile := filepath.Join(tempDir, "coverage.txt")
+	t.Setenv("GO_COVERAGE_HISTORY_PATH", filepath.Join(tempDir, "history"))
+	require.NoError(t, os.WriteFile(coverageFile, []byte(compareHeadProfile), 0o600))
+
+	output, err := executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", filepath.Join(tempDir, "first"))
+	require.NoError(t, err)
+	assert.Contains(t, output, "🌱 Warm-up (run 1 of 2)")
+	assert.Contains(t, output, "🚦 Coverage policy: WARM-UP (run 1 of 2)")
+
+	output, err = executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", filepath.Join(tempDir, "second"))
+	require.NoError(t, err)
+	assert.Contains(t, output, "🚦 Coverage policy: WARM-UP (run 2 of 2)")
+
+	output, err = executeCommand(t, cmdComplete, "--local", "--input", coverageFile, "--output", filepath.Join(tempDir, "third"))
+	require.ErrorIs(t, err, ErrCoverageBelowThreshold)
+	assert.Contains(t, output, "🎓 Warm-up complete after 2 runs: coverage gates are now enforced")
+}
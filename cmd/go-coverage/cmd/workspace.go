@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mrz1836/go-coverage/internal/analytics/dashboard"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+)
+
+// moduleCoverage is the coverage of the files of one workspace module
+type moduleCoverage struct {
+	parser.ModuleCoverage
+
+	threshold float64
+}
+
+// parseWorkspace parses the profile of every module of the go.work workspace in root together
+// with the input profile. A module's profile is the input file name inside the module
+// directory; modules without one contribute through the input profile only.
+func parseWorkspace(ctx context.Context, p *parser.Parser, modules []parser.WorkspaceModule, root, inputFile string) (*parser.CoverageData, error) {
+	var profiles []string
+	seen := make(map[string]struct{}, len(modules)+1)
+	add := func(profile string) {
+		abs, err := filepath.Abs(profile)
+		if err != nil {
+			abs = profile
+		}
+		if _, ok := seen[abs]; ok {
+			return
+		}
+		seen[abs] = struct{}{}
+		profiles = append(profiles, profile)
+	}
+
+	if _, err := os.Stat(inputFile); err == nil {
+		add(inputFile)
+	}
+	for _, module := range modules {
+		profile := filepath.Join(root, filepath.FromSlash(module.Dir), filepath.Base(inputFile))
+		if _, err := os.Stat(profile); err == nil {
+			add(profile)
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("%w: %s", parser.ErrProfileNotFound, inputFile)
+	}
+	return p.ParseFiles(ctx, profiles...)
+}
+
+// newModuleCoverage totals the coverage of every workspace module with its threshold, falling
+// back to the global threshold for modules without one of their own
+func newModuleCoverage(cfg *config.Config, coverage *parser.CoverageData, modules []parser.WorkspaceModule) []moduleCoverage {
+	breakdown := coverage.ModuleBreakdown(modules)
+	result := make([]moduleCoverage, 0, len(breakdown))
+	for _, module := range breakdown {
+		result = append(result, moduleCoverage{ModuleCoverage: module, threshold: cfg.ModuleThreshold(module.Module.Dir)})
+	}
+	return result
+}
+
+// addModuleResults records the threshold of every workspace module in the policy decision, so a
+// module below its threshold fails the run like the workspace threshold does
+func addModuleResults(decision *policy.Decision, modules []moduleCoverage) {
+	for _, module := range modules {
+		result := policy.Result{Rule: policy.RuleModule, Outcome: policy.OutcomePass,
+			Message: fmt.Sprintf("%s: coverage %.2f%% meets the %.2f%% threshold", module.Module.Path, module.Percentage, module.threshold)}
+		if module.Percentage < module.threshold {
+			result.Outcome = policy.OutcomeFail
+			result.Message = fmt.Sprintf("%s: coverage %.2f%% is below the %.2f%% threshold", module.Module.Path, module.Percentage, module.threshold)
+		}
+		decision.Add(result)
+	}
+}
+
+// newModuleDashboardData converts the workspace module totals into the dashboard's module split
+func newModuleDashboardData(modules []moduleCoverage) []dashboard.ModuleCoverage {
+	if len(modules) == 0 {
+		return nil
+	}
+	result := make([]dashboard.ModuleCoverage, 0, len(modules))
+	for _, module := range modules {
+		result = append(result, dashboard.ModuleCoverage{
+			Path:         module.Module.Path,
+			Dir:          module.Module.Dir,
+			Files:        module.Files,
+			Coverage:     module.Percentage,
+			Threshold:    module.threshold,
+			TotalLines:   module.TotalStatements,
+			CoveredLines: module.CoveredStatements,
+		})
+	}
+	return result
+}
@@ -0,0 +1,249 @@
+// Package access protects served coverage reports for teams who cannot expose coverage details
+// publicly. Readers authenticate with a shared token, with basic auth, or with a signed link
+// that grants one report directory until it expires.
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Static error definitions
+var (
+	ErrLinkExpired      = errors.New("signed link has expired")
+	ErrInvalidSignature = errors.New("invalid link signature")
+	ErrSecretRequired   = errors.New("a secret is required to sign links")
+)
+
+// Query parameters and cookie used by the protection
+const (
+	// TokenParam carries the shared token in a URL
+	TokenParam = "token"
+	// ExpiresParam carries the expiry of a signed link as Unix seconds
+	ExpiresParam = "expires"
+	// SignatureParam carries the signature of a signed link
+	SignatureParam = "signature"
+	// CookieName holds the grant of an authenticated reader, so the pages and assets a report
+	// links to load without the token or signature in their URLs
+	CookieName = "go_coverage_access"
+)
+
+// sessionLifetime bounds the grant of readers authenticated with the token or basic auth
+const sessionLifetime = 12 * time.Hour
+
+// Signer signs time-limited links. A link grants the directory of its path, so the link of a
+// pull request report also opens the assets and pages next to it, but not other reports.
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner creates a signer from a shared secret
+func NewSigner(secret string) (*Signer, error) {
+	if secret == "" {
+		return nil, ErrSecretRequired
+	}
+	return &Signer{secret: []byte(secret), now: time.Now}, nil
+}
+
+// Scope returns the directory a link to urlPath grants: the path itself when it names a
+// directory, its parent otherwise
+func Scope(urlPath string) string {
+	if urlPath == "" || urlPath == "/" {
+		return "/"
+	}
+	if strings.HasSuffix(urlPath, "/") {
+		return path.Clean(urlPath) + "/"
+	}
+	dir := path.Dir(path.Clean("/" + urlPath))
+	if dir == "/" {
+		return "/"
+	}
+	return dir + "/"
+}
+
+// signature returns the signature of a grant of scope until expires
+func (s *Signer) signature(scope string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%d", scope, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Sign adds the expiry and signature of a link valid for ttl to rawURL
+func (s *Signer) Sign(rawURL string, ttl time.Duration) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	expires := s.now().Add(ttl).Unix()
+	query := u.Query()
+	query.Set(ExpiresParam, strconv.FormatInt(expires, 10))
+	query.Set(SignatureParam, s.signature(Scope(u.Path), expires))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// verify checks a grant of scope until the expiry given as Unix seconds
+func (s *Signer) verify(scope, expiresParam, signature string) (time.Time, error) {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: bad expiry", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(scope, expires))) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	expiry := time.Unix(expires, 0)
+	if !s.now().Before(expiry) {
+		return time.Time{}, ErrLinkExpired
+	}
+	return expiry, nil
+}
+
+// Verify checks the signed link of a request
+func (s *Signer) Verify(r *http.Request) error {
+	query := r.URL.Query()
+	_, err := s.verify(Scope(r.URL.Path), query.Get(ExpiresParam), query.Get(SignatureParam))
+	return err
+}
+
+// Options configure the protection; without a token, basic auth credentials or a signer
+// every request is allowed
+type Options struct {
+	// Token is the shared token accepted in the token query parameter or as a bearer token
+	Token string
+	// Username and Password are the basic auth credentials
+	Username string
+	Password string
+	// Signer verifies signed links and the grants stored in the cookie
+	Signer *Signer
+	// Public reports paths served without authentication, such as badges
+	Public func(urlPath string) bool
+	// Realm is the basic auth realm
+	Realm string
+}
+
+// Enabled reports whether any protection is configured
+func (o *Options) Enabled() bool {
+	return o.Token != "" || o.Username != "" || o.Signer != nil
+}
+
+// Protect wraps next with the configured protection
+func Protect(next http.Handler, opts Options) http.Handler {
+	if !opts.Enabled() {
+		return next
+	}
+	// Grants of token and basic auth readers are signed too, with a key derived from the
+	// credentials when no link secret is configured
+	cookieSigner := opts.Signer
+	if cookieSigner == nil {
+		cookieSigner = &Signer{secret: []byte("cookie\n" + opts.Token + "\n" + opts.Username + "\n" + opts.Password), now: time.Now}
+	}
+	realm := opts.Realm
+	if realm == "" {
+		realm = "go-coverage"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if opts.Public != nil && opts.Public(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if grantCovers(cookieSigner, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		switch {
+		case opts.Token != "" && (equal(query.Get(TokenParam), opts.Token) || equal(bearerToken(r), opts.Token)):
+			setGrant(w, r, cookieSigner, "/", cookieSigner.now().Add(sessionLifetime))
+		case opts.Username != "" && basicAuth(r, opts.Username, opts.Password):
+			// Browsers resend basic auth credentials by themselves
+		case opts.Signer != nil && query.Get(SignatureParam) != "":
+			scope := Scope(r.URL.Path)
+			expiry, err := opts.Signer.verify(scope, query.Get(ExpiresParam), query.Get(SignatureParam))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			setGrant(w, r, cookieSigner, scope, expiry)
+		default:
+			if opts.Username != "" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", realm))
+			}
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// grantCovers reports whether the request carries a valid grant for its path
+func grantCovers(signer *Signer, r *http.Request) bool {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return false
+	}
+	encodedScope, rest, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return false
+	}
+	expires, signature, ok := strings.Cut(rest, ".")
+	if !ok {
+		return false
+	}
+	scopeBytes, err := base64.RawURLEncoding.DecodeString(encodedScope)
+	if err != nil {
+		return false
+	}
+	scope := string(scopeBytes)
+	if !strings.HasPrefix(path.Clean("/"+r.URL.Path)+"/", scope) {
+		return false
+	}
+	_, err = signer.verify(scope, expires, signature)
+	return err == nil
+}
+
+// setGrant stores a signed grant of scope in the cookie
+func setGrant(w http.ResponseWriter, r *http.Request, signer *Signer, scope string, expiry time.Time) {
+	expires := expiry.Unix()
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(scope)) + "." + strconv.FormatInt(expires, 10) + "." + signer.signature(scope, expires),
+		Path:     scope,
+		Expires:  expiry,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// bearerToken returns the bearer token of the Authorization header
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+// basicAuth checks the basic auth credentials of a request
+func basicAuth(r *http.Request, username, password string) bool {
+	user, pass, ok := r.BasicAuth()
+	return ok && equal(user, username) && equal(pass, password)
+}
+
+// equal compares secrets in constant time
+func equal(a, b string) bool {
+	return a != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
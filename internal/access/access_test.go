@@ -0,0 +1,150 @@
+package access
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("report"))
+})
+
+func serve(t *testing.T, handler http.Handler, target string, prepare func(*http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if prepare != nil {
+		prepare(req)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func withCookies(cookies []*http.Cookie) func(*http.Request) {
+	return func(req *http.Request) {
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	assert.Equal(t, "/", Scope(""))
+	assert.Equal(t, "/", Scope("/index.html"))
+	assert.Equal(t, "/reports/pr/42/", Scope("/reports/pr/42/"))
+	assert.Equal(t, "/reports/pr/42/", Scope("/reports/pr/42/index.html"))
+	assert.Equal(t, "/reports/pr/", Scope("/reports/pr/42/../7"))
+}
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner("")
+	require.ErrorIs(t, err, ErrSecretRequired)
+
+	signer, err := NewSigner("link-secret")
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	signer.now = func() time.Time { return now }
+
+	link, err := signer.Sign("https://coverage.example.com/reports/pr/42/index.html?tab=files", time.Hour)
+	require.NoError(t, err)
+	assert.Contains(t, link, "expires=1700003600")
+	assert.Contains(t, link, "tab=files")
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.NoError(t, signer.Verify(httptest.NewRequest(http.MethodGet, u.RequestURI(), nil)))
+
+	// The signature grants the directory of the report, not other reports
+	other := strings.Replace(u.RequestURI(), "/pr/42/", "/pr/43/", 1)
+	require.ErrorIs(t, signer.Verify(httptest.NewRequest(http.MethodGet, other, nil)), ErrInvalidSignature)
+
+	forged := strings.Replace(u.RequestURI(), "expires=1700003600", "expires=1800000000", 1)
+	require.ErrorIs(t, signer.Verify(httptest.NewRequest(http.MethodGet, forged, nil)), ErrInvalidSignature)
+
+	now = now.Add(2 * time.Hour)
+	require.ErrorIs(t, signer.Verify(httptest.NewRequest(http.MethodGet, u.RequestURI(), nil)), ErrLinkExpired)
+}
+
+func TestProtectDisabled(t *testing.T) {
+	rec := serve(t, Protect(okHandler, Options{}), "/index.html", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestProtectToken(t *testing.T) {
+	handler := Protect(okHandler, Options{
+		Token:  "shared-token",
+		Public: func(urlPath string) bool { return strings.HasSuffix(urlPath, ".svg") },
+	})
+
+	assert.Equal(t, http.StatusOK, serve(t, handler, "/coverage.svg", nil).Code, "public paths are served")
+	assert.Equal(t, http.StatusUnauthorized, serve(t, handler, "/index.html", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(t, handler, "/index.html?token=wrong", nil).Code)
+	assert.Empty(t, serve(t, handler, "/index.html", nil).Header().Get("WWW-Authenticate"))
+
+	rec := serve(t, handler, "/index.html?token=shared-token", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, "/", cookies[0].Path)
+
+	// The cookie lets the pages and assets of the report load without the token
+	assert.Equal(t, http.StatusOK, serve(t, handler, "/assets/app.js", withCookies(cookies)).Code)
+
+	bearer := serve(t, handler, "/index.html", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer shared-token")
+	})
+	assert.Equal(t, http.StatusOK, bearer.Code)
+
+	forged := serve(t, handler, "/index.html", withCookies([]*http.Cookie{{Name: CookieName, Value: "Lw.9999999999.forged"}}))
+	assert.Equal(t, http.StatusUnauthorized, forged.Code)
+}
+
+func TestProtectBasicAuth(t *testing.T) {
+	handler := Protect(okHandler, Options{Username: "team", Password: "s3cret", Realm: "coverage"})
+
+	rec := serve(t, handler, "/index.html", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="coverage", charset="UTF-8"`, rec.Header().Get("WWW-Authenticate"))
+
+	rec = serve(t, handler, "/index.html", func(req *http.Request) { req.SetBasicAuth("team", "wrong") })
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serve(t, handler, "/index.html", func(req *http.Request) { req.SetBasicAuth("team", "s3cret") })
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestProtectSignedLinks(t *testing.T) {
+	signer, err := NewSigner("link-secret")
+	require.NoError(t, err)
+	handler := Protect(okHandler, Options{Token: "shared-token", Signer: signer})
+
+	link, err := signer.Sign("/reports/pr/42/index.html", time.Hour)
+	require.NoError(t, err)
+	rec := serve(t, handler, link, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "/reports/pr/42/", cookies[0].Path)
+
+	// The grant covers the report directory only
+	assert.Equal(t, http.StatusOK, serve(t, handler, "/reports/pr/42/files.html", withCookies(cookies)).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(t, handler, "/reports/pr/43/index.html", withCookies(cookies)).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(t, handler, "/index.html", withCookies(cookies)).Code)
+
+	tampered := strings.Replace(link, "/pr/42/", "/pr/43/", 1)
+	assert.Equal(t, http.StatusForbidden, serve(t, handler, tampered, nil).Code)
+
+	expired, err := signer.Sign("/reports/pr/42/index.html", -time.Minute)
+	require.NoError(t, err)
+	rec = serve(t, handler, expired, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "expired")
+}
@@ -14,6 +14,8 @@ import (
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-coverage/internal/schema"
 )
 
 // Coverage change direction constants
@@ -21,6 +23,9 @@ const (
 	DirectionImproved = "improved"
 	DirectionDegraded = "degraded"
 	DirectionStable   = "stable"
+	// DirectionMoved marks a file whose coverage only changed because covered code moved
+	// between files unchanged
+	DirectionMoved = "moved"
 )
 
 // Priority and risk level constants
@@ -45,6 +50,7 @@ type ComparisonConfig struct {
 	AnalyzeFileChanges bool // Whether to analyze individual file changes
 	MaxFilesToAnalyze  int  // Maximum number of files to analyze in detail
 	IgnoreTestFiles    bool // Whether to ignore test files in analysis
+	IgnoreMovedCode    bool // Whether to report changes caused only by moved code as moved instead of a regression
 
 	// Trend analysis settings
 	EnableTrendAnalysis bool // Whether to perform trend analysis
@@ -56,8 +62,27 @@ type ComparisonConfig struct {
 	AcceptableCoverageThreshold float64 // Threshold for acceptable coverage
 }
 
+// SnapshotSchema versions stored coverage snapshots. Version 1 added the schema version to
+// the unversioned format.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var SnapshotSchema = schema.Schema{
+	Name:       "coverage snapshot",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// ComparisonResultSchema versions saved comparison results. Version 1 added the schema
+// version to the unversioned format.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var ComparisonResultSchema = schema.Schema{
+	Name:       "comparison result",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
 // CoverageSnapshot represents a coverage snapshot for comparison
 type CoverageSnapshot struct {
+	SchemaVersion   int                       `json:"schema_version,omitempty"`
 	Branch          string                    `json:"branch"`
 	CommitSHA       string                    `json:"commit_sha"`
 	Timestamp       time.Time                 `json:"timestamp"`
@@ -65,6 +90,27 @@ type CoverageSnapshot struct {
 	FileCoverage    map[string]FileMetrics    `json:"file_coverage"`
 	PackageCoverage map[string]PackageMetrics `json:"package_coverage"`
 	TestMetadata    TestMetadata              `json:"test_metadata"`
+	// Renames maps the new path of each file renamed by the PR to its previous path, both
+	// relative to the repository root as reported by the PR diff
+	Renames map[string]string `json:"renames,omitempty"`
+	// MovedBlocks are the code blocks the PR moved unchanged from one file to another
+	MovedBlocks []MovedBlock `json:"moved_blocks,omitempty"`
+}
+
+// MovedBlock is a block of code the PR removed from one file and added unchanged to another,
+// with the statements it holds before and after the move
+type MovedBlock struct {
+	From           string `json:"from"` // File name in the base snapshot
+	To             string `json:"to"`   // File name in the PR snapshot
+	BaseStatements int    `json:"base_statements"`
+	BaseCovered    int    `json:"base_covered"`
+	PRStatements   int    `json:"pr_statements"`
+	PRCovered      int    `json:"pr_covered"`
+}
+
+// IsNeutral reports whether the block holds as many covered statements after the move as before
+func (b *MovedBlock) IsNeutral() bool {
+	return b.BaseStatements == b.PRStatements && b.BaseCovered == b.PRCovered
 }
 
 // CoverageMetrics represents overall coverage metrics
@@ -114,6 +160,7 @@ type TestMetadata struct {
 
 // ComparisonResult represents the result of comparing two coverage snapshots
 type ComparisonResult struct {
+	SchemaVersion     int                     `json:"schema_version"`
 	BaseSnapshot      CoverageSnapshot        `json:"base_snapshot"`
 	PRSnapshot        CoverageSnapshot        `json:"pr_snapshot"`
 	OverallChange     OverallChangeAnalysis   `json:"overall_change"`
@@ -148,6 +195,9 @@ type FileChangeAnalysis struct {
 	IsSignificant          bool    `json:"is_significant"`
 	IsNewFile              bool    `json:"is_new_file"`
 	IsDeleted              bool    `json:"is_deleted"`
+	IsRenamed              bool    `json:"is_renamed"`
+	PreviousFilename       string  `json:"previous_filename,omitempty"` // Base path of a renamed file
+	MovedStatements        int     `json:"moved_statements,omitempty"`  // Statements moved into or out of the file
 	LinesAdded             int     `json:"lines_added"`
 	LinesRemoved           int     `json:"lines_removed"`
 	Risk                   string  `json:"risk"` // "high", "medium", "low"
@@ -230,6 +280,7 @@ func NewComparisonEngine(config *ComparisonConfig) *ComparisonEngine {
 			AnalyzeFileChanges:          true,
 			MaxFilesToAnalyze:           50,
 			IgnoreTestFiles:             false,
+			IgnoreMovedCode:             true,
 			EnableTrendAnalysis:         true,
 			TrendHistoryDays:            30,
 			ExcellentCoverageThreshold:  90.0,
@@ -315,10 +366,22 @@ func (e *ComparisonEngine) analyzeFileChanges(base, pr *CoverageSnapshot) []File
 	prFiles := make(map[string]FileMetrics)
 	maps.Copy(prFiles, pr.FileCoverage)
 
+	// A renamed file is compared with its base under the previous name instead of being
+	// reported as one new and one deleted file
+	renamedFrom := matchRenames(baseFiles, prFiles, pr.Renames)
+	renamedTo := make(map[string]bool, len(renamedFrom))
+	for _, previous := range renamedFrom {
+		renamedTo[previous] = true
+	}
+
+	moves := movedCodeByFile(pr.MovedBlocks, renamedFrom)
+
 	// Analyze all files present in either snapshot
 	allFiles := make(map[string]bool)
 	for filename := range baseFiles {
-		allFiles[filename] = true
+		if !renamedTo[filename] {
+			allFiles[filename] = true
+		}
 	}
 	for filename := range prFiles {
 		allFiles[filename] = true
@@ -329,7 +392,11 @@ func (e *ComparisonEngine) analyzeFileChanges(base, pr *CoverageSnapshot) []File
 			continue
 		}
 
-		baseMetrics, existsInBase := baseFiles[filename]
+		baseName := filename
+		if previous, ok := renamedFrom[filename]; ok {
+			baseName = previous
+		}
+		baseMetrics, existsInBase := baseFiles[baseName]
 		prMetrics, existsInPR := prFiles[filename]
 
 		change := FileChangeAnalysis{
@@ -337,6 +404,10 @@ func (e *ComparisonEngine) analyzeFileChanges(base, pr *CoverageSnapshot) []File
 			IsNewFile: !existsInBase && existsInPR,
 			IsDeleted: existsInBase && !existsInPR,
 		}
+		if baseName != filename {
+			change.IsRenamed = true
+			change.PreviousFilename = baseName
+		}
 
 		if change.IsNewFile {
 			change.PRPercentage = prMetrics.Percentage
@@ -376,6 +447,14 @@ func (e *ComparisonEngine) analyzeFileChanges(base, pr *CoverageSnapshot) []File
 			math.Abs(float64(change.StatementChange)) >= float64(e.config.SignificantLineChange)
 
 		change.Risk = e.calculateRisk(change)
+		if e.config.IgnoreMovedCode {
+			if moved, ok := moves[filename]; ok && moved.isNetNeutral(baseMetrics, prMetrics) {
+				change.Direction = DirectionMoved
+				change.MovedStatements = moved.statements
+				change.IsSignificant = false
+				change.Risk = priorityLow
+			}
+		}
 
 		changes = append(changes, change)
 	}
@@ -399,6 +478,100 @@ func (e *ComparisonEngine) analyzeFileChanges(base, pr *CoverageSnapshot) []File
 	return changes
 }
 
+// fileMoves sums the moved blocks of one file
+type fileMoves struct {
+	neutral                                            bool
+	statements                                         int
+	outStatements, outCovered, inStatements, inCovered int
+}
+
+// movedCodeByFile groups moved blocks by the file name used in the file changes, the PR name
+// for renamed files
+func movedCodeByFile(blocks []MovedBlock, renamedFrom map[string]string) map[string]*fileMoves {
+	prName := make(map[string]string, len(renamedFrom))
+	for renamed, previous := range renamedFrom {
+		prName[previous] = renamed
+	}
+
+	moves := make(map[string]*fileMoves)
+	get := func(filename string) *fileMoves {
+		if renamed, ok := prName[filename]; ok {
+			filename = renamed
+		}
+		if moves[filename] == nil {
+			moves[filename] = &fileMoves{neutral: true}
+		}
+		return moves[filename]
+	}
+	for i := range blocks {
+		block := &blocks[i]
+		from, to := get(block.From), get(block.To)
+		from.outStatements += block.BaseStatements
+		from.outCovered += block.BaseCovered
+		from.statements += block.BaseStatements
+		to.inStatements += block.PRStatements
+		to.inCovered += block.PRCovered
+		to.statements += block.PRStatements
+		if !block.IsNeutral() {
+			from.neutral, to.neutral = false, false
+		}
+	}
+	return moves
+}
+
+// isNetNeutral reports whether the coverage of a file is unchanged once the statements moved
+// out of the base and into the PR version are left out
+func (m *fileMoves) isNetNeutral(base, pr FileMetrics) bool {
+	if !m.neutral || m.statements == 0 {
+		return false
+	}
+	baseStatements, baseCovered := base.TotalStatements-m.outStatements, base.CoveredStatements-m.outCovered
+	prStatements, prCovered := pr.TotalStatements-m.inStatements, pr.CoveredStatements-m.inCovered
+	switch {
+	case baseStatements <= 0 && prStatements <= 0:
+		return true
+	case baseStatements <= 0 || prStatements <= 0:
+		return false
+	}
+	change := float64(prCovered)/float64(prStatements)*100 - float64(baseCovered)/float64(baseStatements)*100
+	return math.Abs(change) <= 0.1
+}
+
+// matchRenames pairs the files of the PR snapshot with their base snapshot name for every
+// rename in the PR diff. Snapshot names are module import paths while the diff uses paths
+// relative to the repository root, so the previous name keeps the module prefix of the new
+// one. Renames whose new name already existed in the base or whose previous name is still
+// present in the PR are ignored.
+func matchRenames(baseFiles, prFiles map[string]FileMetrics, renames map[string]string) map[string]string {
+	matched := make(map[string]string)
+	if len(renames) == 0 {
+		return matched
+	}
+	claimed := make(map[string]bool)
+
+	for prName := range prFiles {
+		if _, existsInBase := baseFiles[prName]; existsInBase {
+			continue
+		}
+		for newPath, previousPath := range renames {
+			if prName != newPath && !strings.HasSuffix(prName, "/"+newPath) {
+				continue
+			}
+			baseName := strings.TrimSuffix(prName, newPath) + previousPath
+			if _, existsInBase := baseFiles[baseName]; !existsInBase {
+				continue
+			}
+			if _, stillInPR := prFiles[baseName]; stillInPR || claimed[baseName] {
+				continue
+			}
+			claimed[baseName] = true
+			matched[prName] = baseName
+			break
+		}
+	}
+	return matched
+}
+
 // analyzePackageChanges analyzes coverage changes at the package level
 func (e *ComparisonEngine) analyzePackageChanges(base, pr *CoverageSnapshot) []PackageChangeAnalysis {
 	changes := make([]PackageChangeAnalysis, 0, len(base.PackageCoverage)+len(pr.PackageCoverage))
@@ -644,6 +817,16 @@ func (e *ComparisonEngine) generateSummary(result *ComparisonResult) ComparisonS
 		keyChanges = append(keyChanges, fmt.Sprintf("%d files with significant coverage changes", significantFileChanges))
 	}
 
+	movedFiles := 0
+	for _, fileChange := range result.FileChanges {
+		if fileChange.Direction == DirectionMoved {
+			movedFiles++
+		}
+	}
+	if movedFiles > 0 {
+		keyChanges = append(keyChanges, fmt.Sprintf("Code moved between %d files, net neutral", movedFiles))
+	}
+
 	// Critical issues
 	coverage := result.PRSnapshot.OverallCoverage.Percentage
 	if coverage < e.config.AcceptableCoverageThreshold {
@@ -693,7 +876,7 @@ func (e *ComparisonEngine) LoadCoverageSnapshot(_ context.Context, filePath stri
 	}
 
 	var snapshot CoverageSnapshot
-	if err := json.Unmarshal(data, &snapshot); err != nil {
+	if err := SnapshotSchema.Decode(data, &snapshot); err != nil {
 		return nil, fmt.Errorf("failed to parse coverage snapshot: %w", err)
 	}
 
@@ -702,6 +885,9 @@ func (e *ComparisonEngine) LoadCoverageSnapshot(_ context.Context, filePath stri
 
 // SaveComparisonResult saves a comparison result to a file
 func (e *ComparisonEngine) SaveComparisonResult(_ context.Context, result *ComparisonResult, filePath string) error {
+	result.SchemaVersion = ComparisonResultSchema.Version()
+	result.BaseSnapshot.SchemaVersion = SnapshotSchema.Version()
+	result.PRSnapshot.SchemaVersion = SnapshotSchema.Version()
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal comparison result: %w", err)
@@ -397,6 +397,109 @@ func TestAnalyzeFileChanges(t *testing.T) {
 	require.Equal(t, "deleted", deletedChange.Direction)
 }
 
+func TestAnalyzeFileChangesRenames(t *testing.T) {
+	engine := NewComparisonEngine(nil)
+	const module = "github.com/owner/repo/"
+
+	baseSnapshot := &CoverageSnapshot{
+		FileCoverage: map[string]FileMetrics{
+			module + "internal/old.go":  {Percentage: 60, TotalStatements: 10, CoveredStatements: 6},
+			module + "internal/gone.go": {Percentage: 50, TotalStatements: 4, CoveredStatements: 2},
+		},
+	}
+	prSnapshot := &CoverageSnapshot{
+		FileCoverage: map[string]FileMetrics{
+			module + "pkg/new.go":   {Percentage: 70, TotalStatements: 10, CoveredStatements: 7},
+			module + "pkg/fresh.go": {Percentage: 90, TotalStatements: 10, CoveredStatements: 9},
+		},
+		Renames: map[string]string{
+			"pkg/new.go":     "internal/old.go",
+			"pkg/missing.go": "internal/nowhere.go",
+		},
+	}
+
+	changes := engine.analyzeFileChanges(baseSnapshot, prSnapshot)
+	byName := make(map[string]FileChangeAnalysis, len(changes))
+	for _, change := range changes {
+		byName[change.Filename] = change
+	}
+	require.Len(t, byName, 3)
+	require.NotContains(t, byName, module+"internal/old.go")
+
+	renamed := byName[module+"pkg/new.go"]
+	require.True(t, renamed.IsRenamed)
+	require.False(t, renamed.IsNewFile)
+	require.Equal(t, module+"internal/old.go", renamed.PreviousFilename)
+	require.InDelta(t, 60.0, renamed.BasePercentage, 0.001)
+	require.InDelta(t, 10.0, renamed.PercentageChange, 0.001)
+	require.Equal(t, DirectionImproved, renamed.Direction)
+
+	require.True(t, byName[module+"pkg/fresh.go"].IsNewFile)
+	require.True(t, byName[module+"internal/gone.go"].IsDeleted)
+}
+
+func TestAnalyzeFileChangesMovedCode(t *testing.T) {
+	baseSnapshot := &CoverageSnapshot{
+		FileCoverage: map[string]FileMetrics{
+			"a.go": {Percentage: 50, TotalStatements: 20, CoveredStatements: 10},
+			"b.go": {Percentage: 80, TotalStatements: 10, CoveredStatements: 8},
+			"c.go": {Percentage: 80, TotalStatements: 10, CoveredStatements: 8},
+		},
+	}
+	// Ten uncovered statements moved from a.go to b.go; c.go lost coverage on its own
+	prSnapshot := &CoverageSnapshot{
+		FileCoverage: map[string]FileMetrics{
+			"a.go": {Percentage: 100, TotalStatements: 10, CoveredStatements: 10},
+			"b.go": {Percentage: 40, TotalStatements: 20, CoveredStatements: 8},
+			"c.go": {Percentage: 50, TotalStatements: 10, CoveredStatements: 5},
+		},
+		MovedBlocks: []MovedBlock{{From: "a.go", To: "b.go", BaseStatements: 10, PRStatements: 10}},
+	}
+
+	byName := func(changes []FileChangeAnalysis) map[string]FileChangeAnalysis {
+		result := make(map[string]FileChangeAnalysis, len(changes))
+		for _, change := range changes {
+			result[change.Filename] = change
+		}
+		return result
+	}
+
+	engine := NewComparisonEngine(nil)
+	changes := byName(engine.analyzeFileChanges(baseSnapshot, prSnapshot))
+	for _, name := range []string{"a.go", "b.go"} {
+		require.Equal(t, DirectionMoved, changes[name].Direction, name)
+		require.False(t, changes[name].IsSignificant, name)
+		require.Equal(t, 10, changes[name].MovedStatements, name)
+		require.Equal(t, priorityLow, changes[name].Risk, name)
+	}
+	require.Equal(t, DirectionDegraded, changes["c.go"].Direction)
+
+	result, err := engine.CompareCoverage(context.Background(), baseSnapshot, prSnapshot)
+	require.NoError(t, err)
+	require.Contains(t, result.Summary.KeyChanges, "Code moved between 2 files, net neutral")
+
+	// Losing coverage while moving is not neutral
+	prSnapshot.MovedBlocks[0].PRCovered = 0
+	prSnapshot.MovedBlocks[0].BaseCovered = 5
+	changes = byName(engine.analyzeFileChanges(baseSnapshot, prSnapshot))
+	require.Equal(t, DirectionDegraded, changes["b.go"].Direction)
+
+	// Detection can be turned off
+	prSnapshot.MovedBlocks[0].BaseCovered = 0
+	engine = NewComparisonEngine(&ComparisonConfig{SignificantPercentageChange: 1, SignificantLineChange: 10, MaxFilesToAnalyze: 50})
+	changes = byName(engine.analyzeFileChanges(baseSnapshot, prSnapshot))
+	require.Equal(t, DirectionDegraded, changes["b.go"].Direction)
+}
+
+func TestMatchRenamesIgnoresSurvivingFiles(t *testing.T) {
+	files := map[string]FileMetrics{"a.go": {}}
+	// The previous file still exists in the PR, so the new file is a copy rather than a rename
+	prFiles := map[string]FileMetrics{"a.go": {}, "b.go": {}}
+	require.Empty(t, matchRenames(files, prFiles, map[string]string{"b.go": "a.go"}))
+	require.Equal(t, map[string]string{"b.go": "a.go"},
+		matchRenames(files, map[string]FileMetrics{"b.go": {}}, map[string]string{"b.go": "a.go"}))
+}
+
 func TestAnalyzeFileChangesIgnoreTestFiles(t *testing.T) {
 	config := &ComparisonConfig{
 		IgnoreTestFiles:             true,
@@ -936,6 +1039,9 @@ func TestSaveComparisonResult(t *testing.T) {
 	var loaded ComparisonResult
 	err = json.Unmarshal(data, &loaded)
 	require.NoError(t, err)
+	require.Equal(t, ComparisonResultSchema.Version(), loaded.SchemaVersion)
+	require.Equal(t, SnapshotSchema.Version(), loaded.BaseSnapshot.SchemaVersion)
+	require.Equal(t, SnapshotSchema.Version(), loaded.PRSnapshot.SchemaVersion)
 	require.Equal(t, result.BaseSnapshot.Branch, loaded.BaseSnapshot.Branch)
 	require.Equal(t, result.PRSnapshot.Branch, loaded.PRSnapshot.Branch)
 	require.InDelta(t, result.OverallChange.PercentageChange, loaded.OverallChange.PercentageChange, 0.001)
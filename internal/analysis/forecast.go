@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+)
+
+// ForecastLimits are the limits of the base branch a merge forecast is checked against
+type ForecastLimits struct {
+	Threshold float64 // Minimum coverage of the branch in percent (0 disables)
+	MaxDrop   float64 // Largest allowed coverage decrease in percentage points (negative disables)
+}
+
+// Forecast is the coverage of the base branch expected once a pull request is merged
+type Forecast struct {
+	Branch    string  `json:"branch"`
+	Current   float64 `json:"current"`   // Latest coverage of the branch
+	Projected float64 `json:"projected"` // Coverage of the branch after the merge
+	// Violations are the limits of the branch the projected coverage breaks
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Change is the coverage change the merge is expected to make, in percentage points
+func (f *Forecast) Change() float64 {
+	return f.Projected - f.Current
+}
+
+// ForecastMerge projects the coverage of a branch after merging the pull request compared in
+// result. The statements the pull request adds and covers are applied to the latest statement
+// counts of the branch, so changes merged since the pull request branched off are accounted for;
+// without statement counts the percentage change is applied instead.
+func ForecastMerge(branch string, current CoverageMetrics, result *ComparisonResult, limits ForecastLimits) *Forecast {
+	forecast := &Forecast{Branch: branch, Current: current.Percentage}
+
+	change := result.OverallChange
+	total := current.TotalStatements + change.StatementChange
+	if current.TotalStatements > 0 && total > 0 {
+		covered := min(max(current.CoveredStatements+change.CoveredStatementChange, 0), total)
+		forecast.Projected = float64(covered) / float64(total) * 100
+	} else {
+		forecast.Projected = math.Min(math.Max(current.Percentage+change.PercentageChange, 0), 100)
+	}
+
+	if limits.Threshold > 0 && forecast.Projected < limits.Threshold {
+		forecast.Violations = append(forecast.Violations, fmt.Sprintf("threshold of %.2f%%", limits.Threshold))
+	}
+	if limits.MaxDrop >= 0 && -forecast.Change() > limits.MaxDrop {
+		forecast.Violations = append(forecast.Violations,
+			fmt.Sprintf("max drop of %.2f points (%.2f projected)", limits.MaxDrop, -forecast.Change()))
+	}
+	return forecast
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastMerge(t *testing.T) {
+	main := CoverageMetrics{Percentage: 80, TotalStatements: 1000, CoveredStatements: 800}
+	change := func(percentage float64, statements, covered int) *ComparisonResult {
+		return &ComparisonResult{OverallChange: OverallChangeAnalysis{
+			PercentageChange: percentage, StatementChange: statements, CoveredStatementChange: covered,
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		current    CoverageMetrics
+		result     *ComparisonResult
+		limits     ForecastLimits
+		projected  float64
+		violations []string
+	}{
+		{
+			name:      "statements added and covered",
+			current:   main,
+			result:    change(1.5, 100, 100),
+			limits:    ForecastLimits{Threshold: 80, MaxDrop: -1},
+			projected: 900.0 / 1100 * 100,
+		},
+		{
+			name:       "drop below the threshold",
+			current:    main,
+			result:     change(-2, 100, 0),
+			limits:     ForecastLimits{Threshold: 80, MaxDrop: -1},
+			projected:  800.0 / 1100 * 100,
+			violations: []string{"threshold of 80.00%"},
+		},
+		{
+			name:       "drop beyond the max drop",
+			current:    main,
+			result:     change(-2, 100, 0),
+			limits:     ForecastLimits{MaxDrop: 1},
+			projected:  800.0 / 1100 * 100,
+			violations: []string{"max drop of 1.00 points (7.27 projected)"},
+		},
+		{
+			name:      "percentage without statement counts",
+			current:   CoverageMetrics{Percentage: 81.2},
+			result:    change(0.4, 10, 10),
+			limits:    ForecastLimits{Threshold: 80, MaxDrop: 0},
+			projected: 81.6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := ForecastMerge("main", tt.current, tt.result, tt.limits)
+			assert.Equal(t, "main", forecast.Branch)
+			assert.InDelta(t, tt.current.Percentage, forecast.Current, 0.001)
+			assert.InDelta(t, tt.projected, forecast.Projected, 0.001)
+			assert.InDelta(t, tt.projected-tt.current.Percentage, forecast.Change(), 0.001)
+			assert.Equal(t, tt.violations, forecast.Violations)
+		})
+	}
+}
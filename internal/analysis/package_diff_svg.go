@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Layout of the package diff image in SVG user units
+const (
+	packageDiffWidth     = 720.0
+	packageDiffHeader    = 34.0
+	packageDiffRow       = 24.0
+	packageDiffNameWidth = 230.0
+	packageDiffBarWidth  = 320.0
+	packageDiffNameRunes = 34
+)
+
+// RenderPackageDiffSVG renders the packages whose coverage moved the most as a before and after
+// bar chart for embedding in PR comments: per package, a gray bar for the base coverage above a
+// bar for the PR coverage, green when it improved and red when it degraded, and the change. It
+// shows at most limit packages, largest change first, and returns nil when no package moved.
+func RenderPackageDiffSVG(changes []PackageChangeAnalysis, limit int) []byte {
+	moved := make([]PackageChangeAnalysis, 0, len(changes))
+	for _, change := range changes {
+		if change.Direction != DirectionStable {
+			moved = append(moved, change)
+		}
+	}
+	if len(moved) == 0 || limit <= 0 {
+		return nil
+	}
+	slices.SortStableFunc(moved, func(a, b PackageChangeAnalysis) int {
+		return cmp.Compare(math.Abs(b.PercentageChange), math.Abs(a.PercentageChange))
+	})
+	hidden := max(0, len(moved)-limit)
+	moved = moved[:len(moved)-hidden]
+
+	height := packageDiffHeader + packageDiffRow*float64(len(moved)) + 12
+	if hidden > 0 {
+		height += 16
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" role="img" aria-label="Package coverage before and after the pull request">`+"\n",
+		packageDiffWidth, height, packageDiffWidth, height)
+	b.WriteString(`<style>text{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;font-size:11px;fill:#57606a}</style>` + "\n")
+	fmt.Fprintf(&b, `<rect width="%.0f" height="%.0f" rx="6" fill="#ffffff" stroke="#d0d7de"/>`+"\n", packageDiffWidth, height)
+	b.WriteString(`<text x="12" y="20" style="font-size:13px;font-weight:600;fill:#24292f">Package coverage</text>` + "\n")
+	fmt.Fprintf(&b, `<text x="%.0f" y="20"><tspan fill="#8c959f">■ base</tspan>  <tspan fill="#1a7f37">■ improved</tspan>  <tspan fill="#cf222e">■ degraded</tspan></text>`+"\n",
+		packageDiffNameWidth)
+
+	for i, change := range moved {
+		top := packageDiffHeader + packageDiffRow*float64(i)
+		color := "#1a7f37"
+		if change.Direction == DirectionDegraded {
+			color = "#cf222e"
+		}
+		fmt.Fprintf(&b, `<text x="12" y="%.1f"><title>%s</title>%s</text>`+"\n",
+			top+14, html.EscapeString(change.Package), html.EscapeString(shortenPackage(change.Package)))
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.1f" width="%.0f" height="16" fill="#f6f8fa"/>`+"\n", packageDiffNameWidth, top+2, packageDiffBarWidth)
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.1f" width="%.1f" height="7" fill="#8c959f"/>`+"\n",
+			packageDiffNameWidth, top+2, barWidth(change.BasePercentage))
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.1f" width="%.1f" height="8" fill="%s"/>`+"\n",
+			packageDiffNameWidth, top+10, barWidth(change.PRPercentage), color)
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.1f">%.1f%% → %.1f%% <tspan fill="%s" font-weight="600">%+.1f%%</tspan></text>`+"\n",
+			packageDiffNameWidth+packageDiffBarWidth+10, top+14, change.BasePercentage, change.PRPercentage, color, change.PercentageChange)
+	}
+	if hidden > 0 {
+		noun := "packages"
+		if hidden == 1 {
+			noun = "package"
+		}
+		fmt.Fprintf(&b, `<text x="12" y="%.1f">and %d more %s</text>`+"\n", height-14, hidden, noun)
+	}
+	b.WriteString("</svg>\n")
+
+	return []byte(b.String())
+}
+
+// barWidth returns the width of the bar of a coverage percentage
+func barWidth(percentage float64) float64 {
+	return packageDiffBarWidth * min(100, max(0, percentage)) / 100
+}
+
+// shortenPackage keeps the end of long import paths, which tells packages apart
+func shortenPackage(name string) string {
+	runes := []rune(name)
+	if len(runes) <= packageDiffNameRunes {
+		return name
+	}
+	return "…" + string(runes[len(runes)-packageDiffNameRunes+1:])
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPackageDiffSVG(t *testing.T) {
+	stable := []PackageChangeAnalysis{{Package: "internal/config", BasePercentage: 80, PRPercentage: 80, Direction: DirectionStable}}
+	require.Nil(t, RenderPackageDiffSVG(stable, 10), "nothing to draw when no package moved")
+
+	changes := append(stable,
+		PackageChangeAnalysis{Package: "internal/parser", BasePercentage: 70, PRPercentage: 75, PercentageChange: 5, Direction: DirectionImproved},
+		PackageChangeAnalysis{Package: "internal/<badge>", BasePercentage: 90, PRPercentage: 78, PercentageChange: -12, Direction: DirectionDegraded},
+	)
+	svg := string(RenderPackageDiffSVG(changes, 10))
+	require.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	require.NotContains(t, svg, "internal/config", "stable packages are left out")
+	require.Less(t, strings.Index(svg, "internal/&lt;badge&gt;"), strings.Index(svg, "internal/parser"), "the largest change comes first")
+	require.Contains(t, svg, `90.0% → 78.0% <tspan fill="#cf222e" font-weight="600">-12.0%</tspan>`)
+	require.Contains(t, svg, `<rect x="230" y="68.0" width="240.0" height="8" fill="#1a7f37"/>`, "75% of the bar, green")
+	require.NotContains(t, svg, "more package")
+
+	many := make([]PackageChangeAnalysis, 0, 12)
+	for i := range 12 {
+		many = append(many, PackageChangeAnalysis{Package: fmt.Sprintf("pkg%d", i), PRPercentage: 50, PercentageChange: float64(i + 1), Direction: DirectionImproved})
+	}
+	svg = string(RenderPackageDiffSVG(many, 10))
+	require.Contains(t, svg, "and 2 more packages")
+	require.NotContains(t, svg, ">pkg0<", "the smallest changes are left out")
+}
+
+func TestShortenPackage(t *testing.T) {
+	require.Equal(t, "internal/parser", shortenPackage("internal/parser"))
+	long := "github.com/example/project/internal/analytics/dashboard"
+	short := shortenPackage(long)
+	require.Len(t, []rune(short), packageDiffNameRunes)
+	require.True(t, strings.HasPrefix(short, "…"))
+	require.True(t, strings.HasSuffix(long, strings.TrimPrefix(short, "…")))
+}
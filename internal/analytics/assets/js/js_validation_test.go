@@ -393,6 +393,7 @@ func TestSpecificJavaScriptFeatures(t *testing.T) {
 		expectedFunctions := []string{
 			"toggleTheme",
 			"togglePackage",
+			"loadPackageFiles",
 			"copyBadgeURL",
 			"fetchLatestGitHubTag",
 			"updateVersionDisplay",
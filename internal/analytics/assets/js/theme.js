@@ -21,12 +21,79 @@ function togglePackage(packageName) {
   if (packageEl.style.display === 'none' || !packageEl.style.display) {
     packageEl.style.display = 'block';
     toggleIcon.textContent = '▼';
+    if (packageEl.dataset.src && !packageEl.dataset.loaded) {
+      loadPackageFiles(packageEl);
+    }
   } else {
     packageEl.style.display = 'none';
     toggleIcon.textContent = '▶';
   }
 }
 
+// Coverage level class, matching the thresholds used by the report template
+function coverageLevel(percentage) {
+  if (percentage >= 95) return 'excellent';
+  if (percentage >= 85) return 'success';
+  if (percentage >= 75) return 'warning';
+  if (percentage >= 65) return 'low';
+  return 'danger';
+}
+
+// Lazily load the file list of a package in a paginated report
+async function loadPackageFiles(packageEl) {
+  packageEl.dataset.loaded = 'true';
+  try {
+    const response = await fetch(packageEl.dataset.src);
+    if (!response.ok) throw new Error('HTTP ' + response.status);
+    const files = await response.json();
+
+    packageEl.replaceChildren(...files.map(file => {
+      const level = coverageLevel(file.percentage);
+      const item = document.createElement('div');
+      item.className = 'file-item';
+
+      const info = document.createElement('div');
+      info.className = 'file-info';
+      const icon = document.createElement('span');
+      icon.className = 'file-icon';
+      icon.textContent = '📄';
+      const name = document.createElement(file.url ? 'a' : 'span');
+      name.className = 'file-name';
+      name.textContent = file.name;
+      if (file.url) {
+        name.href = file.url;
+        name.target = '_blank';
+        name.rel = 'noopener noreferrer';
+      }
+      const stats = document.createElement('span');
+      stats.className = 'file-stats';
+      stats.textContent = file.covered_lines + ' / ' + file.total_lines + ' lines';
+      info.append(icon, name, stats);
+
+      const coverage = document.createElement('div');
+      coverage.className = 'file-coverage';
+      const percentage = document.createElement('span');
+      percentage.className = 'coverage-percentage ' + level;
+      percentage.textContent = file.percentage.toFixed(1) + '%';
+      const bar = document.createElement('div');
+      bar.className = 'coverage-bar mini';
+      const fill = document.createElement('div');
+      fill.className = 'coverage-fill ' + level;
+      fill.style.width = file.percentage + '%';
+      bar.append(fill);
+      coverage.append(percentage, bar);
+
+      item.append(info, coverage);
+      return item;
+    }));
+  } catch (error) {
+    console.warn('Failed to load package files:', error);
+    delete packageEl.dataset.loaded;
+    const item = packageEl.querySelector('.file-loading');
+    if (item) item.textContent = 'Failed to load files';
+  }
+}
+
 // Search functionality
 const searchInput = document.getElementById('searchInput');
 if (searchInput) {
@@ -2,15 +2,40 @@ package dashboard
 
 import (
 	"time"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/schema"
 )
 
+// CoverageDataSchema versions coverage-data.json and data/coverage.json. Version 1 added the
+// schema version to the unversioned format.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var CoverageDataSchema = schema.Schema{
+	Name:       "coverage data",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// DecodeCoverageData reads coverage data written by this or an earlier release
+func DecodeCoverageData(data []byte) (*CoverageData, error) {
+	var coverage CoverageData
+	if err := CoverageDataSchema.Decode(data, &coverage); err != nil {
+		return nil, err
+	}
+	return &coverage, nil
+}
+
 // CoverageData represents the complete coverage data for dashboard generation
 type CoverageData struct {
+	SchemaVersion int `json:"schema_version"`
+
 	// Project information
 	ProjectName      string    `json:"project_name"`
 	RepositoryURL    string    `json:"repository_url"`
 	Branch           string    `json:"branch"`
 	CommitSHA        string    `json:"commit_sha"`
+	CoverMode        string    `json:"cover_mode,omitempty"` // -covermode of the profile: set, count or atomic
 	PRNumber         string    `json:"pr_number,omitempty"`
 	PRTitle          string    `json:"pr_title,omitempty"`
 	BaselineCoverage float64   `json:"baseline_coverage,omitempty"`
@@ -23,6 +48,9 @@ type CoverageData struct {
 	CoveredLines  int     `json:"covered_lines"`
 	MissedLines   int     `json:"missed_lines"`
 
+	// Confidence band around TotalCoverage from the run-to-run noise of recent history
+	Confidence *policy.Interval `json:"confidence,omitempty"`
+
 	// File metrics
 	TotalFiles     int `json:"total_files"`
 	CoveredFiles   int `json:"covered_files"`
@@ -32,12 +60,64 @@ type CoverageData struct {
 	// Package metrics
 	Packages []PackageCoverage `json:"packages"`
 
+	// Coverage rolled up by directory, for drilling down from top-level directories
+	Directories []DirectoryCoverage `json:"directories,omitempty"`
+
+	// Coverage split by handwritten, generated and test code
+	Classes []ClassCoverage `json:"classes,omitempty"`
+
+	// Coverage of the directories each team owns, linking to the team dashboards
+	Teams []TeamCoverage `json:"teams,omitempty"`
+
+	// Coverage split by language when reports of other languages were merged
+	Languages []LanguageCoverage `json:"languages,omitempty"`
+
+	// Coverage of every module of a go.work workspace
+	Modules []ModuleCoverage `json:"modules,omitempty"`
+
+	// Coverage of the test helper packages left out of the totals
+	TestHelpers *TestHelperCoverage `json:"test_helpers,omitempty"`
+
+	// Example programs, Example functions and fuzz targets, reported apart from the totals
+	Examples *ExampleCoverage `json:"examples,omitempty"`
+
+	// How often the covered statements were executed, for profiles in count or atomic mode
+	HitCounts *HitCounts `json:"hit_counts,omitempty"`
+
+	// Coverage split by build tag variant when several profiles were merged
+	Variants *VariantCoverage `json:"variants,omitempty"`
+
+	// Covered statements per second of test time across runs, when test results were given
+	TestEfficiency *TestEfficiency `json:"test_efficiency,omitempty"`
+
+	// Uncovered statements weighted by complexity and criticality across runs, when enabled
+	CoverageDebt *CoverageDebt `json:"coverage_debt,omitempty"`
+
+	// Files with no coverage that no test package links, when dead code detection is enabled
+	DeadCode *DeadCode `json:"dead_code,omitempty"`
+
+	// NoTests is set when the repository has no test files, for the get started state
+	NoTests bool `json:"no_tests,omitempty"`
+
+	// Why an emergency bypass downgraded the gates of this run to warnings, empty when none applied
+	Bypass string `json:"bypass,omitempty"`
+	// Earlier runs whose gates were bypassed, newest first, for auditing
+	Bypasses []BypassEvent `json:"bypasses,omitempty"`
+
+	// Coverage of the runs of each environment found in the history, this run's first
+	Environments []EnvironmentCoverage `json:"environments,omitempty"`
+	// EnvironmentFilter is the filter the history was restricted to, empty for all environments
+	EnvironmentFilter string `json:"environment_filter,omitempty"`
+
 	// Trend data
 	TrendData *TrendData `json:"trend_data,omitempty"`
 
 	// Historical data
 	History []HistoricalPoint `json:"history,omitempty"`
 
+	// Chart payload of the trend analysis of the history, nil without enough history
+	TrendChart *analytics.TrendChart `json:"trend_chart,omitempty"`
+
 	// Build status information
 	BuildStatus *BuildStatus `json:"build_status,omitempty"`
 
@@ -60,6 +140,19 @@ type PackageCoverage struct {
 	Functions    []FunctionCoverage `json:"functions,omitempty"`
 }
 
+// DirectoryCoverage represents the coverage of every file below a directory
+type DirectoryCoverage struct {
+	Path         string              `json:"path"`
+	Files        int                 `json:"files"`
+	Coverage     float64             `json:"coverage"`
+	TotalLines   int                 `json:"total_lines"`
+	CoveredLines int                 `json:"covered_lines"`
+	MissedLines  int                 `json:"missed_lines"`
+	Change       *float64            `json:"change,omitempty"` // Percentage points since the previous run
+	GitHubURL    string              `json:"github_url,omitempty"`
+	Children     []DirectoryCoverage `json:"children,omitempty"`
+}
+
 // FileCoverage represents coverage data for a single file
 type FileCoverage struct {
 	Name         string      `json:"name"`
@@ -70,6 +163,138 @@ type FileCoverage struct {
 	MissedLines  int         `json:"missed_lines"`
 	GitHubURL    string      `json:"github_url,omitempty"`
 	LineHits     map[int]int `json:"line_hits,omitempty"` // Line number -> hit count
+	Class        string      `json:"class,omitempty"`     // handwritten, generated or test
+}
+
+// ClassCoverage represents the coverage total for one class of files
+type ClassCoverage struct {
+	Class        string  `json:"class"` // handwritten, generated or test
+	Files        int     `json:"files"`
+	Coverage     float64 `json:"coverage"`
+	TotalLines   int     `json:"total_lines"`
+	CoveredLines int     `json:"covered_lines"`
+	MissedLines  int     `json:"missed_lines"`
+}
+
+// TeamCoverage represents the coverage of the directories one team owns
+type TeamCoverage struct {
+	Name         string   `json:"name"`
+	Paths        []string `json:"paths"`
+	URL          string   `json:"url"` // Team dashboard, relative to this dashboard
+	Files        int      `json:"files"`
+	Coverage     float64  `json:"coverage"`
+	Threshold    float64  `json:"threshold"`
+	TotalLines   int      `json:"total_lines"`
+	CoveredLines int      `json:"covered_lines"`
+}
+
+// LanguageCoverage represents the coverage total for the files of one language
+type LanguageCoverage struct {
+	Language     string  `json:"language"`
+	Files        int     `json:"files"`
+	Coverage     float64 `json:"coverage"`
+	TotalLines   int     `json:"total_lines"`
+	CoveredLines int     `json:"covered_lines"`
+}
+
+// ModuleCoverage represents the coverage total for the files of one workspace module
+type ModuleCoverage struct {
+	Path         string  `json:"path"` // Module path declared in its go.mod
+	Dir          string  `json:"dir"`  // Directory relative to the workspace root
+	Files        int     `json:"files"`
+	Coverage     float64 `json:"coverage"`
+	Threshold    float64 `json:"threshold"`
+	TotalLines   int     `json:"total_lines"`
+	CoveredLines int     `json:"covered_lines"`
+}
+
+// TestHelperCoverage represents the test helper packages, such as testutil or mocks, reported
+// apart from the production totals
+type TestHelperCoverage struct {
+	Files        int               `json:"files"`
+	Coverage     float64           `json:"coverage"`
+	TotalLines   int               `json:"total_lines"`
+	CoveredLines int               `json:"covered_lines"`
+	Packages     []PackageCoverage `json:"packages"`
+}
+
+// ExampleCoverage represents whether the documented examples and fuzz targets are exercised:
+// the coverage of example programs and fuzz harnesses, and the Example functions and fuzz
+// targets of the tests go test runs
+type ExampleCoverage struct {
+	Files        int     `json:"files"`
+	Coverage     float64 `json:"coverage"`
+	TotalLines   int     `json:"total_lines"`
+	CoveredLines int     `json:"covered_lines"`
+	Examples     int     `json:"examples"`
+	ExamplesRun  int     `json:"examples_run"` // Examples with an output comment, which go test runs
+	FuzzTargets  int     `json:"fuzz_targets"`
+	FuzzSeeded   int     `json:"fuzz_seeded"` // Fuzz targets with seed inputs, which go test runs
+	// Idle are the Example functions and fuzz targets go test does not run
+	Idle []IdleFunction `json:"idle,omitempty"`
+}
+
+// IdleFunction is an Example function or fuzz target go test does not run
+type IdleFunction struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Path      string `json:"path"`
+	GitHubURL string `json:"github_url,omitempty"`
+}
+
+// HitCounts represents how often the covered statements were executed: code executed a great
+// many times points at slow or redundant tests, code executed once at coverage that hangs on a
+// single test
+type HitCounts struct {
+	Covered  int         `json:"covered"`
+	Once     int         `json:"once"`
+	Buckets  []HitBucket `json:"buckets"`
+	Hotspots []Hotspot   `json:"hotspots,omitempty"`
+	// HotspotStatements are the statements of all hotspots, of which Hotspots may list fewer
+	HotspotStatements int `json:"hotspot_statements"`
+	HotspotHits       int `json:"hotspot_hits"`
+}
+
+// HitBucket counts the covered statements executed between Min and Max times, Max being zero
+// for the last bucket
+type HitBucket struct {
+	Min        int `json:"min"`
+	Max        int `json:"max,omitempty"`
+	Statements int `json:"statements"`
+}
+
+// Hotspot is a block executed at least as often as the hotspot threshold
+type Hotspot struct {
+	Path       string `json:"path"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Statements int    `json:"statements"`
+	Hits       int    `json:"hits"`
+	GitHubURL  string `json:"github_url,omitempty"`
+}
+
+// VariantCoverage represents the build tag dimension of a merged coverage profile
+type VariantCoverage struct {
+	Tags           []TagCoverage         `json:"tags"`
+	UncoveredLines int                   `json:"uncovered_lines"` // Not covered under any tag
+	Files          []VariantFileCoverage `json:"files,omitempty"`
+}
+
+// TagCoverage represents the coverage of the profile produced under one build tag variant
+type TagCoverage struct {
+	Name           string  `json:"name"`
+	Coverage       float64 `json:"coverage"`
+	TotalLines     int     `json:"total_lines"`
+	CoveredLines   int     `json:"covered_lines"`
+	ExclusiveLines int     `json:"exclusive_lines"` // Covered only under this tag
+}
+
+// VariantFileCoverage represents a file whose coverage depends on the build tags used
+type VariantFileCoverage struct {
+	Path           string         `json:"path"`
+	ExclusiveLines map[string]int `json:"exclusive_lines,omitempty"` // Tag name -> lines covered only under it
+	UncoveredLines int            `json:"uncovered_lines"`
+	GitHubURL      string         `json:"github_url,omitempty"`
 }
 
 // FunctionCoverage represents coverage data for a single function
@@ -98,6 +323,83 @@ type HistoricalPoint struct {
 	Coverage     float64   `json:"coverage"`
 	TotalLines   int       `json:"total_lines"`
 	CoveredLines int       `json:"covered_lines"`
+	// Annotations are the notes added to the run with history annotate
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// DeadCode lists the Go files no test executes and no test package links, candidates for deletion
+type DeadCode struct {
+	EligibleFiles int      `json:"eligible_files"`
+	Statements    int      `json:"statements"` // Statements of the candidates in the profile
+	Files         []string `json:"files"`
+}
+
+// TestEfficiency tracks how much coverage each second of test time buys across runs
+type TestEfficiency struct {
+	Tests      int     `json:"tests"`
+	Seconds    float64 `json:"seconds"`    // Total test time of this run
+	Efficiency float64 `json:"efficiency"` // Covered statements per test second of this run
+	// Runs with test results, oldest first, ending with this run
+	Points []EfficiencyPoint `json:"points,omitempty"`
+	// Growth of test time and covered statements from the first point to this run, in percent
+	TimeGrowth     float64 `json:"time_growth,omitempty"`
+	CoverageGrowth float64 `json:"coverage_growth,omitempty"`
+	// Outpaced is set when test time grew much faster than coverage
+	Outpaced bool `json:"outpaced,omitempty"`
+}
+
+// EfficiencyPoint is the test time and efficiency of one run
+type EfficiencyPoint struct {
+	Timestamp  time.Time `json:"timestamp"`
+	CommitSHA  string    `json:"commit_sha"`
+	Seconds    float64   `json:"seconds"`
+	Efficiency float64   `json:"efficiency"`
+}
+
+// CoverageDebt tracks the uncovered statements of the runs, weighted by the complexity of their
+// functions and whether they are on a critical path
+type CoverageDebt struct {
+	Uncovered int     `json:"uncovered"`
+	Weighted  float64 `json:"weighted"`
+	// Runs with a recorded debt, oldest first, ending with this run
+	Points []DebtPoint `json:"points,omitempty"`
+	// Reduced is how much the weighted debt fell since the first point, negative when it grew
+	Reduced float64 `json:"reduced,omitempty"`
+	// Files with the highest debt in this run
+	Files []DebtFile `json:"files,omitempty"`
+}
+
+// DebtPoint is the weighted coverage debt of one run
+type DebtPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	CommitSHA string    `json:"commit_sha"`
+	Weighted  float64   `json:"weighted"`
+}
+
+// DebtFile is the coverage debt of one file
+type DebtFile struct {
+	Path      string  `json:"path"`
+	Uncovered int     `json:"uncovered"`
+	Weighted  float64 `json:"weighted"`
+	Critical  bool    `json:"critical,omitempty"`
+}
+
+// BypassEvent is a run whose failed gates an emergency bypass downgraded to warnings
+type BypassEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Branch    string    `json:"branch"`
+	CommitSHA string    `json:"commit_sha"`
+	Reason    string    `json:"reason"`
+}
+
+// EnvironmentCoverage is the coverage of the runs of one environment: Go version, platform,
+// runner, test tags and profile flags
+type EnvironmentCoverage struct {
+	Label   string  `json:"label"`
+	Runs    int     `json:"runs"`
+	Latest  float64 `json:"latest"`  // Coverage of the newest run of the environment
+	Average float64 `json:"average"` // Mean coverage of the runs of the environment
+	Current bool    `json:"current"` // Set for the environment of this run
 }
 
 // BranchInfo represents information about a branch
@@ -118,7 +420,7 @@ type BranchInfo struct {
 type Metadata struct {
 	GeneratedAt      time.Time    `json:"generated_at"`
 	GeneratorVersion string       `json:"generator_version"`
-	DataVersion      string       `json:"data_version"`
+	DataVersion      string       `json:"data_version"` // schema version of coverage.json
 	Branches         []BranchInfo `json:"branches"`
 	LastUpdated      time.Time    `json:"last_updated"`
 }
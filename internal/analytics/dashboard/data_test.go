@@ -93,6 +93,26 @@ func TestCoverageData_JSON(t *testing.T) {
 	}
 }
 
+func TestDecodeCoverageData(t *testing.T) {
+	// coverage-data.json as published before it carried a schema version
+	legacy := []byte(`{"project_name":"legacy","branch":"master","total_coverage":72.5,"packages":[{"name":"pkg"}]}`)
+
+	data, err := DecodeCoverageData(legacy)
+	if err != nil {
+		t.Fatalf("Failed to decode legacy coverage data: %v", err)
+	}
+	if data.SchemaVersion != CoverageDataSchema.Version() {
+		t.Errorf("Expected schema version %d, got %d", CoverageDataSchema.Version(), data.SchemaVersion)
+	}
+	if data.ProjectName != "legacy" || data.TotalCoverage != 72.5 || len(data.Packages) != 1 {
+		t.Errorf("Unexpected decoded coverage data: %+v", data)
+	}
+
+	if _, err := DecodeCoverageData([]byte(`{"schema_version":99}`)); err == nil {
+		t.Error("Expected an error for coverage data from a newer release")
+	}
+}
+
 func TestPackageCoverage_Calculations(t *testing.T) {
 	pkg := &PackageCoverage{
 		Name:         "test-package",
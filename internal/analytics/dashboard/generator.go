@@ -10,12 +10,18 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mrz1836/go-coverage/internal/analytics/assets"
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
 	globalconfig "github.com/mrz1836/go-coverage/internal/config"
 	"github.com/mrz1836/go-coverage/internal/github"
 )
@@ -29,7 +35,7 @@ func isMainBranch(branchName string) bool {
 
 	branches := strings.Split(mainBranches, ",")
 	for _, branch := range branches {
-		if strings.TrimSpace(branch) == branchName {
+		if branchmatch.Match(strings.TrimSpace(branch), branchName) {
 			return true
 		}
 	}
@@ -55,6 +61,8 @@ type GeneratorConfig struct {
 	AssetsDir        string
 	GeneratorVersion string
 	GitHubToken      string // GitHub token for API access (optional)
+	// Sections holds Markdown snippets keyed by section position (top, after-metrics, bottom)
+	Sections map[string]string
 }
 
 // RepositoryInfo contains information extracted from a Git repository
@@ -117,6 +125,13 @@ func (g *Generator) generateDashboardHTML(ctx context.Context, data *CoverageDat
 	// Prepare template data
 	templateData := g.prepareTemplateData(ctx, data)
 
+	// Load custom sections from the template directory and configured snippets
+	sections, err := loadCustomSections(g.config.TemplateDir, g.config.Sections, templateData)
+	if err != nil {
+		return "", fmt.Errorf("loading custom sections: %w", err)
+	}
+	templateData["CustomSections"] = sections
+
 	// Store template data for later use (e.g., build status generation)
 	g.lastTemplateData = templateData
 
@@ -312,12 +327,27 @@ func (g *Generator) prepareTemplateData(ctx context.Context, data *CoverageData)
 		"Branch":             data.Branch,
 		"BranchURL":          branchURL,
 		"Branches":           branches,
+		"Bypass":             data.Bypass,
+		"Bypasses":           g.prepareBypassData(data.Bypasses),
 		"BuildStatus":        buildStatus,
 		"CommitSHA":          g.formatCommitSHA(data.CommitSHA),
+		"CoverMode":          data.CoverMode,
 		"CommitURL":          commitURL,
+		"Confidence":         data.Confidence,
+		"CodeClasses":        g.prepareClassData(data.Classes),
+		"BuildTags":          g.prepareVariantData(data.Variants),
+		"Languages":          g.prepareLanguageData(data.Languages),
+		"Teams":              g.prepareTeamData(data.Teams),
+		"Modules":            g.prepareModuleData(data.Modules),
+		"TestHelpers":        g.prepareTestHelperData(data.TestHelpers),
+		"Examples":           g.prepareExampleData(data.Examples),
+		"HitCounts":          g.prepareHitCountData(data.HitCounts),
 		"CoverageTrend":      coverageTrend,
+		"Directories":        g.prepareDirectoryData(data.Directories),
 		"CoveredFiles":       data.CoveredFiles,
 		"DefaultBranch":      data.Branch,
+		"Environments":       g.prepareEnvironmentData(data.Environments),
+		"EnvironmentFilter":  data.EnvironmentFilter,
 		"FilesPercent":       fmt.Sprintf("%.1f", filesPercent),
 		"FilesTrend":         filesTrend,
 		"GoogleAnalyticsID":  globalConfig.Analytics.GoogleAnalyticsID,
@@ -331,6 +361,7 @@ func (g *Generator) prepareTemplateData(ctx context.Context, data *CoverageData)
 		"LatestTag":          latestTag,
 		"LinesToCover":       data.MissedLines,
 		"LinesToCoverTrend":  linesToCoverTrend,
+		"NoTests":            data.NoTests,
 		"OwnerURL":           ownerURL,
 		"PRNumber":           data.PRNumber,
 		"PRTitle":            data.PRTitle,
@@ -340,10 +371,14 @@ func (g *Generator) prepareTemplateData(ctx context.Context, data *CoverageData)
 		"RepositoryName":     repositoryName,
 		"RepositoryOwner":    repositoryOwner,
 		"RepositoryURL":      repositoryURL,
+		"TestEfficiency":     g.prepareEfficiencyData(data.TestEfficiency),
+		"CoverageDebt":       g.prepareDebtData(data.CoverageDebt),
+		"DeadCode":           g.prepareDeadCodeData(data.DeadCode),
 		"Timestamp":          data.Timestamp,
 		"TimestampFormatted": data.Timestamp.Format("2006-01-02 15:04:05 UTC"),
 		"TotalCoverage":      roundToDecimals(data.TotalCoverage, 2),
 		"TotalFiles":         data.TotalFiles,
+		"TrendChart":         g.prepareTrendChart(data.History, data.TrendChart),
 		"TrendDirection":     trendDirection,
 		"WorkflowRunNumber":  data.WorkflowRunNumber,
 		// Missing fields for template consistency with coverage report
@@ -359,6 +394,217 @@ func (g *Generator) prepareTemplateData(ctx context.Context, data *CoverageData)
 	}
 }
 
+// prepareBypassData prepares the audit trail of bypassed runs for display
+func (g *Generator) prepareBypassData(events []BypassEvent) []map[string]any {
+	bypasses := make([]map[string]any, 0, len(events))
+	for _, event := range events {
+		bypasses = append(bypasses, map[string]any{
+			"Branch":    event.Branch,
+			"CommitSHA": g.formatCommitSHA(event.CommitSHA),
+			"Reason":    event.Reason,
+			"Time":      event.Timestamp.Format("2006-01-02 15:04 UTC"),
+		})
+	}
+	return bypasses
+}
+
+// prepareEnvironmentData prepares the coverage of each environment for display, when the history
+// holds more than one environment to compare
+func (g *Generator) prepareEnvironmentData(environments []EnvironmentCoverage) []map[string]any {
+	if len(environments) < 2 {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(environments))
+	for _, environment := range environments {
+		result = append(result, map[string]any{
+			"Label":   environment.Label,
+			"Runs":    environment.Runs,
+			"Latest":  roundToDecimals(environment.Latest, 2),
+			"Average": roundToDecimals(environment.Average, 2),
+			"Current": environment.Current,
+		})
+	}
+	return result
+}
+
+// Size of the test efficiency chart in SVG user units
+const (
+	efficiencyChartWidth  = 300.0
+	efficiencyChartHeight = 80.0
+)
+
+// prepareEfficiencyData prepares test efficiency for display, with the efficiency and test time
+// of every run drawn as lines scaled to their own maximum
+func (g *Generator) prepareEfficiencyData(efficiency *TestEfficiency) map[string]any {
+	if efficiency == nil {
+		return nil
+	}
+
+	data := map[string]any{
+		"Tests":          efficiency.Tests,
+		"Duration":       formatSeconds(efficiency.Seconds),
+		"Efficiency":     fmt.Sprintf("%.1f", efficiency.Efficiency),
+		"Runs":           len(efficiency.Points),
+		"TimeGrowth":     fmt.Sprintf("%.0f", efficiency.TimeGrowth),
+		"CoverageGrowth": fmt.Sprintf("%.0f", efficiency.CoverageGrowth),
+		"Outpaced":       efficiency.Outpaced,
+	}
+	if len(efficiency.Points) > 1 {
+		times := make([]float64, 0, len(efficiency.Points))
+		efficiencies := make([]float64, 0, len(efficiency.Points))
+		for _, point := range efficiency.Points {
+			times = append(times, point.Seconds)
+			efficiencies = append(efficiencies, point.Efficiency)
+		}
+		data["TimeLine"] = chartPoints(times)
+		data["EfficiencyLine"] = chartPoints(efficiencies)
+	}
+	return data
+}
+
+// prepareDebtData prepares coverage debt for display, with the weighted debt of every run drawn
+// as a line and the change since the first of them
+func (g *Generator) prepareDebtData(coverageDebt *CoverageDebt) map[string]any {
+	if coverageDebt == nil {
+		return nil
+	}
+
+	files := make([]map[string]any, 0, len(coverageDebt.Files))
+	for _, file := range coverageDebt.Files {
+		files = append(files, map[string]any{
+			"Path":      file.Path,
+			"Uncovered": file.Uncovered,
+			"Weighted":  fmt.Sprintf("%.0f", file.Weighted),
+			"Critical":  file.Critical,
+		})
+	}
+	data := map[string]any{
+		"Uncovered": coverageDebt.Uncovered,
+		"Weighted":  fmt.Sprintf("%.0f", coverageDebt.Weighted),
+		"Runs":      len(coverageDebt.Points),
+		"Files":     files,
+	}
+	if len(coverageDebt.Points) > 1 {
+		weights := make([]float64, 0, len(coverageDebt.Points))
+		for _, point := range coverageDebt.Points {
+			weights = append(weights, point.Weighted)
+		}
+		data["Line"] = chartPoints(weights)
+		data["Since"] = coverageDebt.Points[0].Timestamp.Format("2006-01-02")
+		data["Reduced"] = fmt.Sprintf("%.0f", math.Abs(coverageDebt.Reduced))
+		data["Grew"] = coverageDebt.Reduced < 0
+	}
+	return data
+}
+
+// prepareTrendChart draws coverage over the history, oldest first, scaled between its lowest and
+// highest value so small changes stay visible, with a marker on every annotated run. With the
+// chart payload of a trend analysis, runs are placed by time and drawn with their moving average
+// and the prediction band.
+func (g *Generator) prepareTrendChart(history []HistoricalPoint, trend *analytics.TrendChart) map[string]any {
+	if len(history) < 2 {
+		return nil
+	}
+
+	points := slices.Clone(history)
+	slices.SortFunc(points, func(a, b HistoricalPoint) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	lowest, highest := points[0].Coverage, points[0].Coverage
+	for _, point := range points {
+		lowest = min(lowest, point.Coverage)
+		highest = max(highest, point.Coverage)
+	}
+
+	var layout *analytics.ChartLayout
+	if trend != nil && len(trend.Points) > 1 {
+		fitted := analytics.NewChartLayout(trend, efficiencyChartWidth, efficiencyChartHeight)
+		layout = &fitted
+	}
+
+	const padding = 4.0
+	line := make([]string, 0, len(points))
+	markers := make([]map[string]any, 0)
+	notes := make([]map[string]any, 0)
+	for i, point := range points {
+		x := efficiencyChartWidth * float64(i) / float64(len(points)-1)
+		y := efficiencyChartHeight / 2
+		if highest > lowest {
+			y = efficiencyChartHeight - padding - (point.Coverage-lowest)/(highest-lowest)*(efficiencyChartHeight-2*padding)
+		}
+		if layout != nil {
+			x, y = layout.X(point.Timestamp), layout.Y(point.Coverage)
+		}
+		line = append(line, fmt.Sprintf("%.1f,%.1f", x, y))
+		if len(point.Annotations) == 0 {
+			continue
+		}
+
+		commit := g.formatCommitSHA(point.CommitSHA)
+		markers = append(markers, map[string]any{
+			"X":       fmt.Sprintf("%.1f", x),
+			"Tooltip": fmt.Sprintf("%s · %.1f%% · %s", commit, point.Coverage, strings.Join(point.Annotations, "; ")),
+		})
+		for _, note := range point.Annotations {
+			notes = append(notes, map[string]any{"CommitSHA": commit, "Date": point.Timestamp.Format("2006-01-02"), "Note": note})
+		}
+	}
+
+	chart := map[string]any{
+		"Line":    strings.Join(line, " "),
+		"Markers": markers,
+		"Notes":   notes,
+		"Runs":    len(points),
+		"Lowest":  fmt.Sprintf("%.1f", lowest),
+		"Highest": fmt.Sprintf("%.1f", highest),
+	}
+	if layout != nil {
+		chart["Average"] = layout.Polyline(trend.MovingAverage)
+		chart["Window"] = trend.MovingAverageWindow
+		if len(trend.Prediction) > 0 {
+			last := trend.Prediction[len(trend.Prediction)-1]
+			chart["Band"] = layout.Band(trend.Prediction)
+			chart["Forecast"] = layout.Polyline(trend.Forecast())
+			chart["Predicted"] = fmt.Sprintf("%.1f", last.Value)
+			chart["PredictedDate"] = last.Timestamp.Format("2006-01-02")
+		}
+	}
+	return chart
+}
+
+// chartPoints returns the SVG polyline points of values spread across the chart width, with the
+// largest value near the top and 0 at the bottom
+func chartPoints(values []float64) string {
+	peak := 0.0
+	for _, value := range values {
+		peak = max(peak, value)
+	}
+
+	const padding = 4.0
+	points := make([]string, 0, len(values))
+	for i, value := range values {
+		x := efficiencyChartWidth * float64(i) / float64(max(len(values)-1, 1))
+		y := efficiencyChartHeight - padding
+		if peak > 0 {
+			y -= value / peak * (efficiencyChartHeight - 2*padding)
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+// formatSeconds formats a test time, e.g. "42.5s" or "3m 12s"
+func formatSeconds(seconds float64) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%.1fs", seconds)
+	}
+	total := int(seconds + 0.5)
+	if total < 3600 {
+		return fmt.Sprintf("%dm %ds", total/60, total%60)
+	}
+	return fmt.Sprintf("%dh %dm", total/3600, total%3600/60)
+}
+
 // formatCommitSHA formats commit SHA for display
 func (g *Generator) formatCommitSHA(sha string) string {
 	if len(sha) > 7 {
@@ -409,6 +655,20 @@ func roundToDecimals(value float64, decimals int) float64 {
 	return math.Round(value*multiplier) / multiplier
 }
 
+// formatCount formats a count with thousands separators, e.g. 12,500
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	start := len(digits) % 3
+	if start == 0 {
+		start = 3
+	}
+	formatted := digits[:start]
+	for i := start; i < len(digits); i += 3 {
+		formatted += "," + digits[i:i+3]
+	}
+	return formatted
+}
+
 // preparePackageData prepares package data for display
 func (g *Generator) preparePackageData(packages []PackageCoverage) []map[string]any {
 	result := make([]map[string]any, 0, len(packages))
@@ -437,6 +697,297 @@ func (g *Generator) preparePackageData(packages []PackageCoverage) []map[string]
 	return result
 }
 
+// prepareDirectoryData prepares the directory rollup tree, keeping each level in path order
+func (g *Generator) prepareDirectoryData(directories []DirectoryCoverage) []map[string]any {
+	if len(directories) == 0 {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(directories))
+	for _, dir := range directories {
+		entry := map[string]any{
+			"Path":         dir.Path,
+			"Name":         path.Base(dir.Path),
+			"Files":        dir.Files,
+			"Coverage":     roundToDecimals(dir.Coverage, 2),
+			"CoveredLines": dir.CoveredLines,
+			"TotalLines":   dir.TotalLines,
+			"GitHubURL":    dir.GitHubURL,
+			"Children":     g.prepareDirectoryData(dir.Children),
+		}
+		if dir.Change != nil {
+			change := roundToDecimals(*dir.Change, 2)
+			entry["HasChange"] = true
+			entry["Change"] = change
+			entry["ChangeUp"] = change > 0
+			entry["ChangeDown"] = change < 0
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// prepareClassData prepares the per-class coverage split; a single class adds nothing over the totals
+func (g *Generator) prepareClassData(classes []ClassCoverage) []map[string]any {
+	if len(classes) < 2 {
+		return nil
+	}
+	labels := map[string]string{
+		"handwritten": "✍️ Handwritten",
+		"generated":   "⚙️ Generated",
+		"example":     "📘 Example",
+		"fuzz":        "🎲 Fuzz",
+		"test":        "🧪 Test",
+	}
+	result := make([]map[string]any, 0, len(classes))
+	for _, class := range classes {
+		label := labels[class.Class]
+		if label == "" {
+			label = class.Class
+		}
+		result = append(result, map[string]any{
+			"Class":        class.Class,
+			"Label":        label,
+			"Files":        class.Files,
+			"Coverage":     roundToDecimals(class.Coverage, 2),
+			"CoveredLines": class.CoveredLines,
+			"TotalLines":   class.TotalLines,
+		})
+	}
+	return result
+}
+
+// prepareTeamData prepares the team split, marking the teams below their threshold
+func (g *Generator) prepareTeamData(teams []TeamCoverage) []map[string]any {
+	if len(teams) == 0 {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(teams))
+	for _, team := range teams {
+		result = append(result, map[string]any{
+			"Name":         team.Name,
+			"Paths":        strings.Join(team.Paths, ", "),
+			"URL":          team.URL,
+			"Files":        team.Files,
+			"Coverage":     roundToDecimals(team.Coverage, 2),
+			"Threshold":    roundToDecimals(team.Threshold, 2),
+			"Passed":       team.Coverage >= team.Threshold,
+			"CoveredLines": team.CoveredLines,
+			"TotalLines":   team.TotalLines,
+		})
+	}
+	return result
+}
+
+// prepareModuleData prepares the workspace module split, marking the modules below their threshold
+func (g *Generator) prepareModuleData(modules []ModuleCoverage) []map[string]any {
+	if len(modules) == 0 {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(modules))
+	for _, module := range modules {
+		result = append(result, map[string]any{
+			"Path":         module.Path,
+			"Dir":          module.Dir,
+			"Files":        module.Files,
+			"Coverage":     roundToDecimals(module.Coverage, 2),
+			"Threshold":    roundToDecimals(module.Threshold, 2),
+			"Passed":       module.Coverage >= module.Threshold,
+			"CoveredLines": module.CoveredLines,
+			"TotalLines":   module.TotalLines,
+		})
+	}
+	return result
+}
+
+// prepareTestHelperData prepares the appendix of test helper packages in package order
+func (g *Generator) prepareTestHelperData(helpers *TestHelperCoverage) map[string]any {
+	if helpers == nil || len(helpers.Packages) == 0 {
+		return nil
+	}
+	packages := slices.Clone(helpers.Packages)
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return map[string]any{
+		"Files":        helpers.Files,
+		"Coverage":     roundToDecimals(helpers.Coverage, 2),
+		"CoveredLines": helpers.CoveredLines,
+		"TotalLines":   helpers.TotalLines,
+		"Packages":     g.preparePackageData(packages),
+	}
+}
+
+// prepareExampleData prepares the examples and fuzz targets section, listing the first
+// functions go test does not run
+func (g *Generator) prepareExampleData(examples *ExampleCoverage) map[string]any {
+	const maxIdle = 10
+
+	if examples == nil || (examples.TotalLines == 0 && examples.Examples == 0 && examples.FuzzTargets == 0) {
+		return nil
+	}
+	idle := make([]map[string]any, 0, min(len(examples.Idle), maxIdle))
+	for _, fn := range examples.Idle[:min(len(examples.Idle), maxIdle)] {
+		reason := "no output comment"
+		if fn.Kind == "fuzz" {
+			reason = "no seed inputs"
+		}
+		idle = append(idle, map[string]any{
+			"Name":      fn.Name,
+			"Path":      fn.Path,
+			"GitHubURL": fn.GitHubURL,
+			"Reason":    reason,
+		})
+	}
+	return map[string]any{
+		"Files":        examples.Files,
+		"Coverage":     roundToDecimals(examples.Coverage, 2),
+		"CoveredLines": examples.CoveredLines,
+		"TotalLines":   examples.TotalLines,
+		"Examples":     examples.Examples,
+		"ExamplesRun":  examples.ExamplesRun,
+		"FuzzTargets":  examples.FuzzTargets,
+		"FuzzSeeded":   examples.FuzzSeeded,
+		"Idle":         idle,
+		"MoreIdle":     len(examples.Idle) - len(idle),
+	}
+}
+
+// prepareHitCountData prepares the hit count histogram, scaling each bar to the largest bucket,
+// and the most executed blocks
+func (g *Generator) prepareHitCountData(hits *HitCounts) map[string]any {
+	const maxHotspots = 10
+
+	if hits == nil || hits.Covered == 0 {
+		return nil
+	}
+	largest := 0
+	for _, bucket := range hits.Buckets {
+		largest = max(largest, bucket.Statements)
+	}
+	buckets := make([]map[string]any, 0, len(hits.Buckets))
+	for _, bucket := range hits.Buckets {
+		label := formatCount(bucket.Min) + "+"
+		switch {
+		case bucket.Max == bucket.Min:
+			label = formatCount(bucket.Min)
+		case bucket.Max > bucket.Min:
+			label = formatCount(bucket.Min) + "–" + formatCount(bucket.Max)
+		}
+		buckets = append(buckets, map[string]any{
+			"Label":      label,
+			"Statements": bucket.Statements,
+			"Height":     roundToDecimals(float64(bucket.Statements)/float64(largest)*100, 1),
+		})
+	}
+
+	hotspots := make([]map[string]any, 0, min(len(hits.Hotspots), maxHotspots))
+	for _, spot := range hits.Hotspots[:min(len(hits.Hotspots), maxHotspots)] {
+		lines := fmt.Sprintf("%d", spot.StartLine)
+		if spot.EndLine > spot.StartLine {
+			lines = fmt.Sprintf("%d-%d", spot.StartLine, spot.EndLine)
+		}
+		hotspots = append(hotspots, map[string]any{
+			"Path":       spot.Path,
+			"Lines":      lines,
+			"Hits":       formatCount(spot.Hits),
+			"Statements": spot.Statements,
+			"GitHubURL":  spot.GitHubURL,
+		})
+	}
+	return map[string]any{
+		"Covered":           hits.Covered,
+		"Once":              hits.Once,
+		"OncePercent":       roundToDecimals(float64(hits.Once)/float64(hits.Covered)*100, 1),
+		"Buckets":           buckets,
+		"Hotspots":          hotspots,
+		"HotspotStatements": hits.HotspotStatements,
+		"HotspotHits":       formatCount(hits.HotspotHits),
+	}
+}
+
+// prepareLanguageData prepares the per-language coverage split; a single language adds nothing over the totals
+func (g *Generator) prepareLanguageData(languages []LanguageCoverage) []map[string]any {
+	if len(languages) < 2 {
+		return nil
+	}
+	result := make([]map[string]any, 0, len(languages))
+	for _, language := range languages {
+		result = append(result, map[string]any{
+			"Language":     language.Language,
+			"Files":        language.Files,
+			"Coverage":     roundToDecimals(language.Coverage, 2),
+			"CoveredLines": language.CoveredLines,
+			"TotalLines":   language.TotalLines,
+		})
+	}
+	return result
+}
+
+// prepareVariantData prepares the build tag dimension, listing the files whose coverage depends
+// on the tags with the files covered by no tag at all first
+func (g *Generator) prepareVariantData(variants *VariantCoverage) map[string]any {
+	const maxFiles = 10
+
+	if variants == nil || len(variants.Tags) < 2 {
+		return nil
+	}
+
+	tags := make([]map[string]any, 0, len(variants.Tags))
+	for _, tag := range variants.Tags {
+		tags = append(tags, map[string]any{
+			"Name":           tag.Name,
+			"Coverage":       roundToDecimals(tag.Coverage, 2),
+			"CoveredLines":   tag.CoveredLines,
+			"TotalLines":     tag.TotalLines,
+			"ExclusiveLines": tag.ExclusiveLines,
+		})
+	}
+
+	sorted := make([]VariantFileCoverage, len(variants.Files))
+	copy(sorted, variants.Files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UncoveredLines > sorted[j].UncoveredLines
+	})
+
+	files := make([]map[string]any, 0, min(len(sorted), maxFiles))
+	for _, file := range sorted[:min(len(sorted), maxFiles)] {
+		onlyUnder := make([]string, 0, len(file.ExclusiveLines))
+		for _, tag := range variants.Tags {
+			if lines := file.ExclusiveLines[tag.Name]; lines > 0 {
+				onlyUnder = append(onlyUnder, fmt.Sprintf("%s (%d)", tag.Name, lines))
+			}
+		}
+		files = append(files, map[string]any{
+			"Path":           file.Path,
+			"GitHubURL":      file.GitHubURL,
+			"UncoveredLines": file.UncoveredLines,
+			"OnlyUnder":      strings.Join(onlyUnder, ", "),
+		})
+	}
+
+	return map[string]any{
+		"Tags":           tags,
+		"UncoveredLines": variants.UncoveredLines,
+		"Files":          files,
+		"MoreFiles":      len(sorted) - len(files),
+	}
+}
+
+// prepareDeadCodeData prepares the dead code candidates, listing the first files by path
+func (g *Generator) prepareDeadCodeData(deadCode *DeadCode) map[string]any {
+	const maxFiles = 10
+
+	if deadCode == nil {
+		return nil
+	}
+	files := deadCode.Files[:min(len(deadCode.Files), maxFiles)]
+	return map[string]any{
+		"Candidates":    len(deadCode.Files),
+		"EligibleFiles": deadCode.EligibleFiles,
+		"Statements":    deadCode.Statements,
+		"Files":         files,
+		"MoreFiles":     len(deadCode.Files) - len(files),
+	}
+}
+
 // prepareHistoryJSON prepares history data as JSON string
 func (g *Generator) prepareHistoryJSON(history []HistoricalPoint) string {
 	if len(history) == 0 {
@@ -483,6 +1034,10 @@ func (g *Generator) generateDataJSON(_ context.Context, data *CoverageData) erro
 		return fmt.Errorf("creating data directory: %w", err)
 	}
 
+	if data.SchemaVersion == 0 {
+		data.SchemaVersion = CoverageDataSchema.Version()
+	}
+
 	// Marshal coverage data
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -499,7 +1054,7 @@ func (g *Generator) generateDataJSON(_ context.Context, data *CoverageData) erro
 	metadata := &Metadata{
 		GeneratedAt:      time.Now(),
 		GeneratorVersion: g.config.GeneratorVersion,
-		DataVersion:      "1.0",
+		DataVersion:      strconv.Itoa(data.SchemaVersion),
 		LastUpdated:      data.Timestamp,
 	}
 
@@ -538,20 +1093,13 @@ func NewRenderer(templateDir string) *Renderer {
 	}
 }
 
-// RenderDashboard renders the dashboard template
+// RenderDashboard renders the dashboard template, preferring dashboard.html from the template directory
 func (r *Renderer) RenderDashboard(_ context.Context, data map[string]any) (string, error) {
-	// Create template function map
-	funcMap := template.FuncMap{
-		"sub": func(a, b float64) float64 {
-			return a - b
-		},
-		"printf": fmt.Sprintf,
+	tmpl, err := r.loadTemplate()
+	if err != nil {
+		return "", err
 	}
 
-	// For now, use embedded template
-	// In the future, load from file
-	tmpl := template.Must(template.New("dashboard").Funcs(funcMap).Parse(getDashboardTemplate()))
-
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
 		return "", fmt.Errorf("executing template: %w", err)
@@ -559,3 +1107,23 @@ func (r *Renderer) RenderDashboard(_ context.Context, data map[string]any) (stri
 
 	return buf.String(), nil
 }
+
+// loadTemplate parses the dashboard override from the template directory, falling back to the embedded template
+func (r *Renderer) loadTemplate() (*template.Template, error) {
+	if r.templateDir != "" {
+		overridePath := filepath.Join(r.templateDir, dashboardOverrideFile)
+		content, err := os.ReadFile(overridePath) //nolint:gosec // path comes from the configured template directory
+		switch {
+		case err == nil:
+			tmpl, parseErr := template.New("dashboard").Funcs(templateFuncs()).Parse(string(content))
+			if parseErr != nil {
+				return nil, fmt.Errorf("parsing %s: %w", overridePath, parseErr)
+			}
+			return tmpl, nil
+		case !os.IsNotExist(err):
+			return nil, fmt.Errorf("reading %s: %w", overridePath, err)
+		}
+	}
+
+	return template.Must(template.New("dashboard").Funcs(templateFuncs()).Parse(getDashboardTemplate())), nil
+}
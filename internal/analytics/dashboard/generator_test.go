@@ -4,8 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/policy"
 )
 
 func TestNewGenerator(t *testing.T) {
@@ -947,6 +952,656 @@ func TestPreparePackageDataEdgeCases(t *testing.T) {
 	}
 }
 
+func TestPrepareClassData(t *testing.T) {
+	gen := &Generator{}
+
+	single := []ClassCoverage{{Class: "handwritten", Files: 3, Coverage: 80, TotalLines: 10, CoveredLines: 8}}
+	if result := gen.prepareClassData(single); result != nil {
+		t.Errorf("prepareClassData() with one class = %v, want nil", result)
+	}
+
+	result := gen.prepareClassData(append(single, ClassCoverage{Class: "generated", Files: 1, Coverage: 12.345, TotalLines: 100, CoveredLines: 12}))
+	if len(result) != 2 {
+		t.Fatalf("prepareClassData() returned %d classes, want 2", len(result))
+	}
+	if result[1]["Label"] != "⚙️ Generated" {
+		t.Errorf("Label = %v, want ⚙️ Generated", result[1]["Label"])
+	}
+	if result[1]["Coverage"] != 12.35 {
+		t.Errorf("Coverage = %v, want 12.35", result[1]["Coverage"])
+	}
+}
+
+func TestPrepareLanguageData(t *testing.T) {
+	gen := &Generator{}
+
+	single := []LanguageCoverage{{Language: "go", Files: 3, Coverage: 80, TotalLines: 10, CoveredLines: 8}}
+	if result := gen.prepareLanguageData(single); result != nil {
+		t.Errorf("prepareLanguageData() with one language = %v, want nil", result)
+	}
+
+	result := gen.prepareLanguageData(append(single, LanguageCoverage{Language: "typescript", Files: 2, Coverage: 66.666, TotalLines: 3, CoveredLines: 2}))
+	if len(result) != 2 {
+		t.Fatalf("prepareLanguageData() returned %d languages, want 2", len(result))
+	}
+	if result[1]["Language"] != "typescript" {
+		t.Errorf("Language = %v, want typescript", result[1]["Language"])
+	}
+	if result[1]["Coverage"] != 66.67 {
+		t.Errorf("Coverage = %v, want 66.67", result[1]["Coverage"])
+	}
+}
+
+func TestPrepareModuleData(t *testing.T) {
+	gen := &Generator{}
+
+	if result := gen.prepareModuleData(nil); result != nil {
+		t.Errorf("prepareModuleData() without modules = %v, want nil", result)
+	}
+
+	result := gen.prepareModuleData([]ModuleCoverage{
+		{Path: "example.com/api", Dir: "api", Files: 2, Coverage: 91.234, Threshold: 90, TotalLines: 10, CoveredLines: 9},
+		{Path: "example.com/worker", Dir: "worker", Files: 1, Coverage: 50, Threshold: 80, TotalLines: 4, CoveredLines: 2},
+	})
+	if len(result) != 2 {
+		t.Fatalf("prepareModuleData() returned %d modules, want 2", len(result))
+	}
+	if result[0]["Coverage"] != 91.23 || result[0]["Passed"] != true {
+		t.Errorf("api module = %v, want 91.23%% passing", result[0])
+	}
+	if result[1]["Passed"] != false {
+		t.Errorf("worker module Passed = %v, want false", result[1]["Passed"])
+	}
+}
+
+func TestPrepareTestHelperData(t *testing.T) {
+	gen := &Generator{}
+
+	if result := gen.prepareTestHelperData(nil); result != nil {
+		t.Errorf("prepareTestHelperData() without helpers = %v, want nil", result)
+	}
+
+	result := gen.prepareTestHelperData(&TestHelperCoverage{
+		Files: 2, Coverage: 33.333, TotalLines: 6, CoveredLines: 2,
+		Packages: []PackageCoverage{
+			{Name: "store/mocks", Coverage: 0, TotalLines: 3},
+			{Name: "internal/testutil", Coverage: 66.666, TotalLines: 3, CoveredLines: 2},
+		},
+	})
+	if result["Coverage"] != 33.33 {
+		t.Errorf("Coverage = %v, want 33.33", result["Coverage"])
+	}
+	packages, ok := result["Packages"].([]map[string]any)
+	if !ok || len(packages) != 2 {
+		t.Fatalf("Packages = %v, want 2 packages", result["Packages"])
+	}
+	if packages[0]["Name"] != "internal/testutil" {
+		t.Errorf("first package = %v, want internal/testutil", packages[0]["Name"])
+	}
+}
+
+func TestPrepareExampleData(t *testing.T) {
+	gen := &Generator{}
+
+	if result := gen.prepareExampleData(&ExampleCoverage{}); result != nil {
+		t.Errorf("prepareExampleData() without examples = %v, want nil", result)
+	}
+
+	idle := make([]IdleFunction, 0, 12)
+	idle = append(idle, IdleFunction{Name: "FuzzParse", Kind: "fuzz", Path: "lib/fuzz_test.go"})
+	for range 11 {
+		idle = append(idle, IdleFunction{Name: "ExampleParse", Kind: "example", Path: "lib/example_test.go"})
+	}
+	result := gen.prepareExampleData(&ExampleCoverage{Examples: 14, ExamplesRun: 3, FuzzTargets: 1, Idle: idle})
+	rows, ok := result["Idle"].([]map[string]any)
+	if !ok || len(rows) != 10 {
+		t.Fatalf("Idle = %v, want 10 functions", result["Idle"])
+	}
+	if rows[0]["Reason"] != "no seed inputs" || rows[1]["Reason"] != "no output comment" {
+		t.Errorf("reasons = %v, %v", rows[0]["Reason"], rows[1]["Reason"])
+	}
+	if result["MoreIdle"] != 2 {
+		t.Errorf("MoreIdle = %v, want 2", result["MoreIdle"])
+	}
+}
+
+func TestGenerateDashboardHTMLCodeClasses(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 50,
+		TotalFiles:    2,
+		Classes: []ClassCoverage{
+			{Class: "handwritten", Files: 1, Coverage: 90, TotalLines: 10, CoveredLines: 9},
+			{Class: "generated", Files: 1, Coverage: 10, TotalLines: 10, CoveredLines: 1},
+		},
+	}
+
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{"Coverage by Code Type", "✍️ Handwritten", "⚙️ Generated", "1/10 statements in 1 file"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.Classes = data.Classes[:1]
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Coverage by Code Type") {
+		t.Error("dashboard should not show the split for a single class")
+	}
+}
+
+func TestGenerateDashboardHTMLBuildTags(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 75,
+		Variants: &VariantCoverage{
+			Tags: []TagCoverage{
+				{Name: "unit", Coverage: 70, TotalLines: 10, CoveredLines: 7, ExclusiveLines: 4},
+				{Name: "integration", Coverage: 62.5, TotalLines: 8, CoveredLines: 5, ExclusiveLines: 2},
+			},
+			UncoveredLines: 3,
+			Files: []VariantFileCoverage{
+				{Path: "lib/lib.go", ExclusiveLines: map[string]int{"integration": 2}, UncoveredLines: 1},
+				{Path: "lib/unix.go", ExclusiveLines: map[string]int{"unit": 4}},
+				{Path: "db/db.go", UncoveredLines: 2},
+			},
+		},
+	}
+
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage by Build Tag",
+		"7/10 statements, 4 covered only under this tag",
+		"3 statements are not covered under any build tag",
+		"lib/unix.go — only covered under unit (4)",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+	if strings.Index(html, "db/db.go") > strings.Index(html, "lib/lib.go") {
+		t.Error("files covered by no tag should be listed first")
+	}
+
+	data.Variants.Tags = data.Variants.Tags[:1]
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Coverage by Build Tag") {
+		t.Error("dashboard should not show the tag dimension for a single variant")
+	}
+}
+
+func TestGenerateDashboardHTMLConfidence(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 82.4,
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "confidence band") {
+		t.Error("dashboard should not show a confidence band without one")
+	}
+
+	data.Confidence = &policy.Interval{Coverage: 82.4, Lower: 81.8, Upper: 83, Margin: 0.6, Level: 95, Runs: 10}
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if !strings.Contains(html, `82.4% <span title="95% confidence band from the last 10 runs: 81.8%–83.0%">± 0.6</span>`) {
+		t.Error("dashboard should show the confidence band next to overall coverage")
+	}
+}
+
+func TestGenerateDashboardHTMLTestEfficiency(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 80,
+		TestEfficiency: &TestEfficiency{
+			Tests: 120, Seconds: 75, Efficiency: 10.7,
+			Points: []EfficiencyPoint{
+				{Seconds: 50, Efficiency: 14},
+				{Seconds: 75, Efficiency: 10.7},
+			},
+			TimeGrowth: 50, CoverageGrowth: 14.6, Outpaced: true,
+		},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Test Efficiency",
+		"<strong>10.7</strong> covered statements per test second",
+		"120 tests in 1m 15s",
+		`<polyline points="0.0,28.0 300.0,4.0"`,
+		"Test time grew 50% while coverage grew 15%",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.TestEfficiency.Points = data.TestEfficiency.Points[1:]
+	data.TestEfficiency.Outpaced = false
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "<polyline") || strings.Contains(html, "Test time grew") {
+		t.Error("dashboard should chart test efficiency only with earlier runs and flag only outpaced test time")
+	}
+}
+
+func TestGenerateDashboardHTMLCoverageDebt(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 80,
+		CoverageDebt: &CoverageDebt{
+			Uncovered: 640, Weighted: 900,
+			Points: []DebtPoint{
+				{Timestamp: time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), Weighted: 1200},
+				{Weighted: 900},
+			},
+			Reduced: 300,
+			Files:   []DebtFile{{Path: "internal/auth/token.go", Uncovered: 40, Weighted: 96.4, Critical: true}},
+		},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage Debt",
+		"<strong>900</strong> weighted uncovered statements",
+		`<polyline points="0.0,4.0 300.0,22.0"`,
+		"Reduced by 300 statements since 2026-07-01, over the last 2 runs",
+		"internal/auth/token.go — 96 (40 uncovered) · critical path",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.CoverageDebt.Reduced = -150
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if !strings.Contains(html, "Grew by 150 statements since 2026-07-01") {
+		t.Error("dashboard should report a growing coverage debt")
+	}
+}
+
+func TestGenerateDashboardHTMLDeadCode(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	files := make([]string, 0, 12)
+	for i := range 12 {
+		files = append(files, "legacy/file"+strconv.Itoa(10+i)+".go")
+	}
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 80,
+		DeadCode:      &DeadCode{EligibleFiles: 40, Statements: 57, Files: files},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Dead Code Candidates",
+		"<strong>12</strong> of 40 Go files have no coverage",
+		"<li>legacy/file19.go</li>",
+		"and 2 more files",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+	if strings.Contains(html, "legacy/file20.go") {
+		t.Error("dashboard should list only the first dead code candidates")
+	}
+
+	data.DeadCode = &DeadCode{EligibleFiles: 40}
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if !strings.Contains(html, "Every one of the 40 Go files is covered or linked by a test package") {
+		t.Error("dashboard should report when there are no dead code candidates")
+	}
+}
+
+func TestGenerateDashboardHTMLBypass(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 70,
+		Bypass:        "bypass token [hotfix] in the commit message",
+		Bypasses: []BypassEvent{{
+			Timestamp: time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+			Branch:    "master",
+			CommitSHA: "abc1234def",
+			Reason:    "all 2 changed files match bypass paths deploy/**",
+		}},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage Gates Bypassed",
+		"bypass token [hotfix] in the commit message: failed rules were reported as warnings",
+		"Bypass Audit",
+		"<code>abc1234</code> on master at 2026-10-01 09:30 UTC: all 2 changed files match bypass paths deploy/**",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.Bypass, data.Bypasses = "", nil
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Bypass") {
+		t.Error("dashboard should show bypasses only when there are any")
+	}
+}
+
+func TestGenerateDashboardHTMLNoTests(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{Branch: "master", Timestamp: time.Now(), NoTests: true}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Get Started with Coverage",
+		"No test files were found in this repository",
+		"<code>go test -coverprofile=coverage.txt ./...</code>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.NoTests = false
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Get Started with Coverage") {
+		t.Error("dashboard should show the get started state only without tests")
+	}
+}
+
+func TestGenerateDashboardHTMLTrendChart(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 84,
+		// Newest first, as history returns them
+		History: []HistoricalPoint{
+			{Timestamp: start.Add(2 * time.Hour), CommitSHA: "cccccccc", Coverage: 84},
+			{Timestamp: start.Add(time.Hour), CommitSHA: "bbbbbbbb", Coverage: 84, Annotations: []string{"migrated to testify"}},
+			{Timestamp: start, CommitSHA: "aaaaaaaa", Coverage: 72},
+		},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage History",
+		`<polyline points="0.0,76.0 150.0,4.0 300.0,4.0"`,
+		`<line x1="150.0" y1="0" x2="150.0" y2="80"`,
+		"<title>bbbbbbb · 84.0% · migrated to testify</title>",
+		"72.0%–84.0% over the last 3 runs",
+		"<code>bbbbbbb</code> 2026-10-01: migrated to testify",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.History = data.History[:1]
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Coverage History") {
+		t.Error("dashboard should chart coverage history only with earlier runs")
+	}
+}
+
+func TestGenerateDashboardHTMLTrendAnalysis(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 80,
+		History: []HistoricalPoint{
+			{Timestamp: start.Add(24 * time.Hour), CommitSHA: "bbbbbbbb", Coverage: 80},
+			{Timestamp: start, CommitSHA: "aaaaaaaa", Coverage: 70},
+		},
+		TrendChart: &analytics.TrendChart{
+			Points:              []analytics.ChartPoint{{Timestamp: start, Value: 70}, {Timestamp: start.Add(24 * time.Hour), Value: 80}},
+			MovingAverage:       []analytics.ChartPoint{{Timestamp: start, Value: 70}, {Timestamp: start.Add(24 * time.Hour), Value: 75}},
+			MovingAverageWindow: 7,
+			Prediction:          []analytics.ChartBandPoint{{Timestamp: start.Add(48 * time.Hour), Value: 85, Lower: 80, Upper: 90}},
+		},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		`<polyline points="0.0,76.0 150.0,40.0"`,
+		`<polygon points="300.0,4.0 300.0,40.0"`,
+		"7-run average",
+		"predicted 85.0% by 2026-10-03",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+}
+
+func TestGenerateDashboardHTMLEnvironments(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 84,
+		Environments: []EnvironmentCoverage{
+			{Label: "go1.25.1 linux/amd64 github-actions/github-hosted", Runs: 3, Latest: 84, Average: 83.5, Current: true},
+			{Label: "go1.25.1 windows/amd64 github-actions/github-hosted", Runs: 1, Latest: 79.25, Average: 79.25},
+		},
+		EnvironmentFilter: "go_version=go1.25*",
+		History: []HistoricalPoint{
+			{Timestamp: time.Now(), CommitSHA: "bbbbbbbb", Coverage: 84},
+			{Timestamp: time.Now().Add(-time.Hour), CommitSHA: "aaaaaaaa", Coverage: 83},
+		},
+	}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage by Environment",
+		"<code>go1.25.1 linux/amd64 github-actions/github-hosted</code> <strong>(this run)</strong>",
+		"3 runs, average 83.5%",
+		"1 run, average 79.25%",
+		"over the last 2 runs matching <code>go_version=go1.25*</code>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+
+	data.Environments = data.Environments[:1]
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Coverage by Environment") {
+		t.Error("dashboard should compare environments only when the history holds several")
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	tests := map[float64]string{
+		12.34: "12.3s",
+		75:    "1m 15s",
+		3725:  "1h 2m",
+	}
+	for seconds, expected := range tests {
+		if got := formatSeconds(seconds); got != expected {
+			t.Errorf("formatSeconds(%v) = %q, want %q", seconds, got, expected)
+		}
+	}
+}
+
+func TestGenerateDashboardHTMLDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(tempDir, "output"),
+	})
+
+	up, down := 2.5, -1.25
+	data := &CoverageData{
+		Branch:        "master",
+		Timestamp:     time.Now(),
+		TotalCoverage: 75,
+		Directories: []DirectoryCoverage{
+			{Path: "cmd", Files: 1, Coverage: 40, TotalLines: 10, CoveredLines: 4, Change: &down},
+			{
+				Path: "internal", Files: 3, Coverage: 80, TotalLines: 20, CoveredLines: 16, Change: &up,
+				GitHubURL: "https://github.com/owner/repo/tree/master/internal",
+				Children: []DirectoryCoverage{
+					{Path: "internal/parser", Files: 2, Coverage: 90, TotalLines: 10, CoveredLines: 9},
+				},
+			},
+		},
+	}
+
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage by Directory",
+		"cmd/ <span",
+		"4/10 statements in 1 file",
+		"-1.25%",
+		`<a href="https://github.com/owner/repo/tree/master/internal" target="_blank">internal/</a>`,
+		"+2.5%",
+		"internal/parser/",
+		"9/10 statements in 2 files",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("dashboard HTML missing %q", want)
+		}
+	}
+	if strings.Index(html, "internal/parser/") < strings.Index(html, "internal/</a>") {
+		t.Error("subdirectories should be nested below their parent")
+	}
+
+	data.Directories = nil
+	html, err = gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Coverage by Directory") {
+		t.Error("dashboard should not show the directory rollup when it is disabled")
+	}
+}
+
 // TestGenerator_GenerateMarshalingErrors tests JSON marshaling error paths
 func TestGenerator_GenerateMarshalingErrors(t *testing.T) {
 	tempDir := t.TempDir()
@@ -1755,3 +2410,78 @@ func TestRenderDashboardWithSubFunction(t *testing.T) {
 		}
 	}
 }
+
+func TestPrepareHitCountData(t *testing.T) {
+	gen := &Generator{}
+
+	if result := gen.prepareHitCountData(&HitCounts{}); result != nil {
+		t.Errorf("prepareHitCountData() without covered statements = %v, want nil", result)
+	}
+
+	result := gen.prepareHitCountData(&HitCounts{
+		Covered: 40,
+		Once:    10,
+		Buckets: []HitBucket{
+			{Min: 1, Max: 1, Statements: 10},
+			{Min: 2, Max: 9, Statements: 20},
+			{Min: 10000, Statements: 10},
+		},
+		Hotspots:          []Hotspot{{Path: "api/server.go", StartLine: 8, EndLine: 9, Hits: 12500}},
+		HotspotStatements: 10,
+		HotspotHits:       1000,
+	})
+	if result["OncePercent"] != 25.0 {
+		t.Errorf("OncePercent = %v, want 25", result["OncePercent"])
+	}
+	buckets, ok := result["Buckets"].([]map[string]any)
+	if !ok || len(buckets) != 3 {
+		t.Fatalf("Buckets = %v, want 3 buckets", result["Buckets"])
+	}
+	for i, want := range []struct {
+		label  string
+		height float64
+	}{{"1", 50}, {"2–9", 100}, {"10,000+", 50}} {
+		if buckets[i]["Label"] != want.label || buckets[i]["Height"] != want.height {
+			t.Errorf("bucket %d = %v, want %s at %v%%", i, buckets[i], want.label, want.height)
+		}
+	}
+	hotspots, ok := result["Hotspots"].([]map[string]any)
+	if !ok || len(hotspots) != 1 || hotspots[0]["Lines"] != "8-9" || hotspots[0]["Hits"] != "12,500" {
+		t.Errorf("Hotspots = %v", result["Hotspots"])
+	}
+	if result["HotspotHits"] != "1,000" {
+		t.Errorf("HotspotHits = %v, want 1,000", result["HotspotHits"])
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 7: "7", 999: "999", 1000: "1,000", 25000: "25,000", 1234567: "1,234,567"} {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestGenerateDashboardHTMLCoverMode(t *testing.T) {
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName: testProjectName,
+		OutputDir:   filepath.Join(t.TempDir(), "output"),
+	})
+
+	data := &CoverageData{Branch: "master", Timestamp: time.Now(), CoverMode: "atomic"}
+	html, err := gen.generateDashboardHTML(context.Background(), data)
+	if err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if !strings.Contains(html, "Cover mode") || !strings.Contains(html, ">atomic<") {
+		t.Error("dashboard HTML should show the cover mode")
+	}
+
+	data.CoverMode = ""
+	if html, err = gen.generateDashboardHTML(context.Background(), data); err != nil {
+		t.Fatalf("generateDashboardHTML failed: %v", err)
+	}
+	if strings.Contains(html, "Cover mode") {
+		t.Error("dashboard HTML should not show an unknown cover mode")
+	}
+}
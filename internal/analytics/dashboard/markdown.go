@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Inline Markdown patterns, applied to already escaped text
+var (
+	markdownLinkPattern   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBoldPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalicPattern = regexp.MustCompile(`(^|[^*])\*([^*\s][^*]*)\*`)
+)
+
+// renderMarkdown converts the small Markdown subset used for dashboard sections into HTML.
+//
+// Supported: ATX headings, paragraphs, "-" / "*" bullet lists, `code`, **bold**, *italic*
+// and [links](https://example.com). All text is escaped first, so raw HTML in the input is
+// displayed rather than executed, and links are limited to http(s), mailto and relative URLs.
+func renderMarkdown(src string) template.HTML {
+	var out strings.Builder
+	var paragraph []string
+	inList := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, " ") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			out.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case strings.HasPrefix(trimmed, "#"):
+			flushParagraph()
+			closeList()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := strings.TrimSpace(trimmed[level:])
+			// Dashboard cards use h3 headings, so "#" maps to h3
+			tag := "h" + string(rune('0'+min(level+2, 6)))
+			out.WriteString("<" + tag + ">" + renderInlineMarkdown(text) + "</" + tag + ">\n")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushParagraph()
+			if !inList {
+				out.WriteString("<ul>\n")
+				inList = true
+			}
+			out.WriteString("<li>" + renderInlineMarkdown(strings.TrimSpace(trimmed[2:])) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderInlineMarkdown(trimmed))
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return template.HTML(out.String()) //nolint:gosec // every text fragment is escaped before markup is added
+}
+
+// renderInlineMarkdown escapes text and applies inline formatting outside of code spans
+func renderInlineMarkdown(text string) string {
+	parts := strings.Split(text, "`")
+	var out strings.Builder
+	for i, part := range parts {
+		escaped := template.HTMLEscapeString(part)
+		// Odd segments are inside a closed code span
+		if i%2 == 1 && i < len(parts)-1 {
+			out.WriteString("<code>" + escaped + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			escaped = "`" + escaped
+		}
+		out.WriteString(formatInlineMarkdown(escaped))
+	}
+	return out.String()
+}
+
+// formatInlineMarkdown applies link and emphasis formatting to escaped text
+func formatInlineMarkdown(escaped string) string {
+	escaped = markdownLinkPattern.ReplaceAllStringFunc(escaped, func(match string) string {
+		groups := markdownLinkPattern.FindStringSubmatch(match)
+		label, href := groups[1], groups[2]
+		if !isSafeMarkdownURL(href) {
+			return label
+		}
+		return `<a href="` + href + `" class="custom-section-link" target="_blank" rel="noopener noreferrer">` + label + `</a>`
+	})
+	escaped = markdownBoldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	return markdownItalicPattern.ReplaceAllString(escaped, "$1<em>$2</em>")
+}
+
+// isSafeMarkdownURL allows web, mail and relative links but not script URLs
+func isSafeMarkdownURL(href string) bool {
+	lower := strings.ToLower(href)
+	for _, prefix := range []string{"https://", "http://", "mailto:", "/", "./", "../", "#"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	// Relative paths without a scheme such as "reports/index.html"
+	return !strings.Contains(lower, ":")
+}
@@ -0,0 +1,61 @@
+package dashboard
+
+import (
+	"context"
+	"html/template"
+	"time"
+)
+
+// PreviewSection renders a custom section file with the template data the dashboard would have
+// for data, and returns the position the file name selects and the rendered HTML. It lets
+// template authors iterate on sections without running the pipeline.
+func (g *Generator) PreviewSection(ctx context.Context, path string, data *CoverageData) (string, template.HTML, error) {
+	position, section, err := loadSectionFile(path, g.prepareTemplateData(ctx, data))
+	if err != nil {
+		return "", "", err
+	}
+	return position, section.HTML, nil
+}
+
+// SampleCoverageData returns the coverage data of a small project with a few runs of history.
+// Section previews use it when no captured coverage-data.json is given.
+func SampleCoverageData() *CoverageData {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return &CoverageData{
+		SchemaVersion:  CoverageDataSchema.Version(),
+		ProjectName:    "project",
+		RepositoryURL:  "https://github.com/example/project",
+		Branch:         "main",
+		CommitSHA:      "4f2c9d1e8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d",
+		Timestamp:      now,
+		TotalCoverage:  84.6,
+		TotalLines:     1300,
+		CoveredLines:   1100,
+		MissedLines:    200,
+		TotalFiles:     6,
+		CoveredFiles:   4,
+		PartialFiles:   2,
+		UncoveredFiles: 0,
+		Packages: []PackageCoverage{
+			{
+				Name: "client", Path: "internal/client", Coverage: 82.1, TotalLines: 900, CoveredLines: 739, MissedLines: 161,
+				Files: []FileCoverage{
+					{Name: "client.go", Path: "internal/client/client.go", Coverage: 72.5, TotalLines: 400, CoveredLines: 290, MissedLines: 110},
+					{Name: "retry.go", Path: "internal/client/retry.go", Coverage: 91.3, TotalLines: 500, CoveredLines: 449, MissedLines: 51},
+				},
+			},
+			{
+				Name: "config", Path: "internal/config", Coverage: 90.3, TotalLines: 400, CoveredLines: 361, MissedLines: 39,
+				Files: []FileCoverage{
+					{Name: "config.go", Path: "internal/config/config.go", Coverage: 90.3, TotalLines: 400, CoveredLines: 361, MissedLines: 39},
+				},
+			},
+		},
+		TrendData: &TrendData{Direction: "up", ChangePercent: 0.7, ChangeLines: 9, ComparedTo: "previous run"},
+		History: []HistoricalPoint{
+			{Timestamp: now, CommitSHA: "4f2c9d1e", Coverage: 84.6, TotalLines: 1300, CoveredLines: 1100},
+			{Timestamp: now.Add(-24 * time.Hour), CommitSHA: "9a8b7c6d", Coverage: 83.9, TotalLines: 1280, CoveredLines: 1074},
+			{Timestamp: now.Add(-48 * time.Hour), CommitSHA: "1e2d3c4b", Coverage: 83.1, TotalLines: 1270, CoveredLines: 1055},
+		},
+	}
+}
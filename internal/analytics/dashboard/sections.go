@@ -0,0 +1,201 @@
+package dashboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Custom section errors
+var (
+	ErrTemplateDirNotFound    = errors.New("dashboard template directory not found")
+	ErrUnknownSectionPosition = errors.New("unknown dashboard section position")
+	ErrUnsupportedSectionFile = errors.New("unsupported dashboard section file")
+	ErrInvalidSectionTemplate = errors.New("invalid dashboard section template")
+)
+
+// Positions at which custom sections can be injected into the dashboard
+const (
+	SectionTop          = "top"           // Above the metrics grid
+	SectionAfterMetrics = "after-metrics" // Between the metrics grid and the reports links
+	SectionBottom       = "bottom"        // Below the package list
+)
+
+const (
+	// dashboardOverrideFile replaces the embedded dashboard template when present in the template directory
+	dashboardOverrideFile = "dashboard.html"
+	// sectionsDirName is the template directory subfolder holding section files
+	sectionsDirName = "sections"
+)
+
+// SectionPositions returns the supported section positions in page order
+func SectionPositions() []string {
+	return []string{SectionTop, SectionAfterMetrics, SectionBottom}
+}
+
+// CustomSection is a user supplied fragment rendered into the dashboard
+type CustomSection struct {
+	ID   string        // Anchor ID derived from the file or snippet name
+	HTML template.HTML // Rendered section content
+}
+
+// CustomSections groups custom sections by position
+type CustomSections struct {
+	Top          []CustomSection
+	AfterMetrics []CustomSection
+	Bottom       []CustomSection
+}
+
+// add appends a section at the given position
+func (s *CustomSections) add(position string, section CustomSection) error {
+	switch position {
+	case SectionTop:
+		s.Top = append(s.Top, section)
+	case SectionAfterMetrics:
+		s.AfterMetrics = append(s.AfterMetrics, section)
+	case SectionBottom:
+		s.Bottom = append(s.Bottom, section)
+	default:
+		return fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownSectionPosition, position, strings.Join(SectionPositions(), ", "))
+	}
+	return nil
+}
+
+// loadCustomSections collects sections from <templateDir>/sections and the configured snippets.
+//
+// Section files are named <position>.html, <position>.md or <position>-<name>.{html,md}
+// and are added in file name order. HTML files are executed as templates with the
+// dashboard data, so they may reference values such as {{.Branch}}. Markdown files and
+// snippets are converted to escaped HTML.
+func loadCustomSections(templateDir string, snippets map[string]string, data map[string]any) (*CustomSections, error) {
+	sections := &CustomSections{}
+
+	if templateDir != "" {
+		if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateDirNotFound, templateDir)
+		}
+
+		sectionsDir := filepath.Join(templateDir, sectionsDirName)
+		entries, err := os.ReadDir(sectionsDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading dashboard sections: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			position, section, err := loadSectionFile(filepath.Join(sectionsDir, entry.Name()), data)
+			if err != nil {
+				return nil, err
+			}
+			if err := sections.add(position, section); err != nil {
+				return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	for position := range snippets {
+		if !isSectionPosition(position) {
+			return nil, fmt.Errorf("snippet: %w: %q", ErrUnknownSectionPosition, position)
+		}
+	}
+
+	// Snippets follow file sections so they can be used to append to a shared template directory
+	for _, position := range SectionPositions() {
+		snippet := strings.TrimSpace(snippets[position])
+		if snippet == "" {
+			continue
+		}
+		section := CustomSection{ID: "snippet-" + position, HTML: renderMarkdown(snippet)}
+		if err := sections.add(position, section); err != nil {
+			return nil, err
+		}
+	}
+
+	return sections, nil
+}
+
+// loadSectionFile reads a single section file and returns its position and rendered content
+func loadSectionFile(path string, data map[string]any) (string, CustomSection, error) {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	position := sectionPosition(base)
+	if position == "" {
+		return "", CustomSection{}, fmt.Errorf("%w: %s (name must start with one of %s)",
+			ErrUnknownSectionPosition, name, strings.Join(SectionPositions(), ", "))
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // path comes from the configured template directory
+	if err != nil {
+		return "", CustomSection{}, fmt.Errorf("reading dashboard section %s: %w", name, err)
+	}
+
+	section := CustomSection{ID: sectionID(base)}
+	switch ext {
+	case ".html", ".htm":
+		tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(string(content))
+		if err != nil {
+			return "", CustomSection{}, fmt.Errorf("%w %s: %w", ErrInvalidSectionTemplate, name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", CustomSection{}, fmt.Errorf("%w %s: %w", ErrInvalidSectionTemplate, name, err)
+		}
+		section.HTML = template.HTML(buf.String()) //nolint:gosec // output of html/template is already escaped
+	case ".md", ".markdown":
+		section.HTML = renderMarkdown(string(content))
+	default:
+		return "", CustomSection{}, fmt.Errorf("%w: %s (use .html or .md)", ErrUnsupportedSectionFile, name)
+	}
+
+	return position, section, nil
+}
+
+// sectionPosition returns the position a section file name starts with, preferring the longest match
+func sectionPosition(base string) string {
+	base = strings.ToLower(base)
+	match := ""
+	for _, position := range SectionPositions() {
+		if (base == position || strings.HasPrefix(base, position+"-")) && len(position) > len(match) {
+			match = position
+		}
+	}
+	return match
+}
+
+// isSectionPosition reports whether position is a supported section position
+func isSectionPosition(position string) bool {
+	for _, p := range SectionPositions() {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
+
+// nonIDChars matches characters that are not allowed in generated section anchors
+var nonIDChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// sectionID converts a file name into an HTML anchor ID
+func sectionID(base string) string {
+	id := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	return "section-" + id
+}
+
+// templateFuncs returns the functions available to the dashboard template and section files
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"sub": func(a, b float64) float64 {
+			return a - b
+		},
+		"printf": fmt.Sprintf,
+	}
+}
@@ -0,0 +1,297 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSectionFiles creates a template directory with the given section files
+func writeSectionFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	templateDir := t.TempDir()
+	sectionsDir := filepath.Join(templateDir, sectionsDirName)
+	if err := os.MkdirAll(sectionsDir, 0o750); err != nil {
+		t.Fatalf("failed to create sections dir: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(sectionsDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return templateDir
+}
+
+func TestLoadCustomSections(t *testing.T) {
+	templateDir := writeSectionFiles(t, map[string]string{
+		"top.md":                 "# Runbook\nSee [the runbook](https://wiki.example.com/runbook).",
+		"after-metrics-links.md": "- one\n- two",
+		"bottom-b.html":          `<p>Branch: {{.Branch}}</p>`,
+		"bottom-a.html":          `<p class="note">{{.Note}}</p>`,
+		".hidden.md":             "ignored",
+	})
+
+	data := map[string]any{"Branch": "main", "Note": "<script>alert(1)</script>"}
+	snippets := map[string]string{SectionBottom: "**Owned** by the platform team"}
+
+	sections, err := loadCustomSections(templateDir, snippets, data)
+	if err != nil {
+		t.Fatalf("loadCustomSections failed: %v", err)
+	}
+
+	if len(sections.Top) != 1 || sections.Top[0].ID != "section-top" {
+		t.Fatalf("unexpected top sections: %+v", sections.Top)
+	}
+	if !strings.Contains(string(sections.Top[0].HTML), `<h3>Runbook</h3>`) {
+		t.Errorf("markdown heading not rendered: %s", sections.Top[0].HTML)
+	}
+
+	if len(sections.AfterMetrics) != 1 || sections.AfterMetrics[0].ID != "section-after-metrics-links" {
+		t.Fatalf("after-metrics-links.md must not be treated as an \"after\" section: %+v", sections.AfterMetrics)
+	}
+
+	// Files are added in name order, followed by the snippet
+	if len(sections.Bottom) != 3 {
+		t.Fatalf("expected 3 bottom sections, got %d", len(sections.Bottom))
+	}
+	if sections.Bottom[0].ID != "section-bottom-a" || sections.Bottom[1].ID != "section-bottom-b" || sections.Bottom[2].ID != "snippet-bottom" {
+		t.Errorf("unexpected bottom order: %s, %s, %s", sections.Bottom[0].ID, sections.Bottom[1].ID, sections.Bottom[2].ID)
+	}
+	if strings.Contains(string(sections.Bottom[0].HTML), "<script>") {
+		t.Errorf("template data must be escaped: %s", sections.Bottom[0].HTML)
+	}
+	if !strings.Contains(string(sections.Bottom[1].HTML), "Branch: main") {
+		t.Errorf("HTML section not executed with dashboard data: %s", sections.Bottom[1].HTML)
+	}
+	if !strings.Contains(string(sections.Bottom[2].HTML), "<strong>Owned</strong>") {
+		t.Errorf("snippet not rendered as markdown: %s", sections.Bottom[2].HTML)
+	}
+}
+
+func TestLoadCustomSectionsErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		templateDir func(t *testing.T) string
+		snippets    map[string]string
+		wantErr     error
+	}{
+		{
+			name:        "missing template directory",
+			templateDir: func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing") },
+			wantErr:     ErrTemplateDirNotFound,
+		},
+		{
+			name: "unknown file position",
+			templateDir: func(t *testing.T) string {
+				return writeSectionFiles(t, map[string]string{"sidebar.md": "nope"})
+			},
+			wantErr: ErrUnknownSectionPosition,
+		},
+		{
+			name: "unsupported extension",
+			templateDir: func(t *testing.T) string {
+				return writeSectionFiles(t, map[string]string{"top.txt": "nope"})
+			},
+			wantErr: ErrUnsupportedSectionFile,
+		},
+		{
+			name: "invalid HTML template",
+			templateDir: func(t *testing.T) string {
+				return writeSectionFiles(t, map[string]string{"top.html": "{{.Broken"})
+			},
+			wantErr: ErrInvalidSectionTemplate,
+		},
+		{
+			name:        "unknown snippet position",
+			templateDir: func(*testing.T) string { return "" },
+			snippets:    map[string]string{"middle": "text"},
+			wantErr:     ErrUnknownSectionPosition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadCustomSections(tt.templateDir(t), tt.snippets, map[string]any{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadCustomSectionsWithoutSectionsDir(t *testing.T) {
+	sections, err := loadCustomSections(t.TempDir(), nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("a template directory without sections must be accepted: %v", err)
+	}
+	if len(sections.Top)+len(sections.AfterMetrics)+len(sections.Bottom) != 0 {
+		t.Errorf("expected no sections, got %+v", sections)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "headings map below the dashboard card heading",
+			input:    "# Title\n## Sub",
+			contains: []string{"<h3>Title</h3>", "<h4>Sub</h4>"},
+		},
+		{
+			name:     "paragraphs join wrapped lines",
+			input:    "first line\nsecond line\n\nnext paragraph",
+			contains: []string{"<p>first line second line</p>", "<p>next paragraph</p>"},
+		},
+		{
+			name:     "lists",
+			input:    "- one\n* two",
+			contains: []string{"<ul>", "<li>one</li>", "<li>two</li>", "</ul>"},
+		},
+		{
+			name:     "inline formatting",
+			input:    "**bold** and *em* with `a*b*c`",
+			contains: []string{"<strong>bold</strong>", "<em>em</em>", "<code>a*b*c</code>"},
+		},
+		{
+			name:     "safe links",
+			input:    "[docs](https://example.com/docs?a=1&b=2) [local](reports/index.html)",
+			contains: []string{`href="https://example.com/docs?a=1&amp;b=2"`, `href="reports/index.html"`, `rel="noopener noreferrer"`},
+		},
+		{
+			name:     "script links are dropped",
+			input:    "[click](javascript:alert(1))",
+			excludes: []string{"href", "javascript:alert"},
+			contains: []string{"click"},
+		},
+		{
+			name:     "raw HTML is escaped",
+			input:    `<img src=x onerror="alert(1)">`,
+			contains: []string{"&lt;img src=x onerror=&#34;alert(1)&#34;&gt;"},
+			excludes: []string{"<img"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := string(renderMarkdown(tt.input))
+			for _, want := range tt.contains {
+				if !strings.Contains(html, want) {
+					t.Errorf("expected %q in %q", want, html)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(html, unwanted) {
+					t.Errorf("did not expect %q in %q", unwanted, html)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateWithCustomSections(t *testing.T) {
+	templateDir := writeSectionFiles(t, map[string]string{
+		"top.md": "Custom **top** banner",
+	})
+	outputDir := t.TempDir()
+
+	gen := NewGenerator(&GeneratorConfig{
+		ProjectName:     testProjectName,
+		RepositoryOwner: testRepoOwner,
+		RepositoryName:  testRepoName,
+		TemplateDir:     templateDir,
+		OutputDir:       outputDir,
+		Sections:        map[string]string{SectionAfterMetrics: "[Runbook](https://wiki.example.com)"},
+	})
+
+	data := &CoverageData{
+		ProjectName:   testProjectName,
+		Branch:        testBranchMain,
+		TotalCoverage: 80,
+		TotalFiles:    1,
+		CoveredFiles:  1,
+		Timestamp:     time.Now(),
+	}
+	if err := gen.Generate(context.Background(), data); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "index.html")) //nolint:gosec // test output path
+	if err != nil {
+		t.Fatalf("failed to read dashboard: %v", err)
+	}
+	html := string(content)
+
+	top := strings.Index(html, `id="section-top"`)
+	metrics := strings.Index(html, `class="metrics-grid"`)
+	runbook := strings.Index(html, `id="snippet-after-metrics"`)
+	links := strings.Index(html, "Coverage Reports & Tools")
+
+	if top == -1 || runbook == -1 {
+		t.Fatalf("custom sections missing from dashboard")
+	}
+	if top >= metrics || metrics >= runbook || runbook >= links {
+		t.Errorf("sections rendered out of order: top=%d metrics=%d after-metrics=%d links=%d", top, metrics, runbook, links)
+	}
+	if !strings.Contains(html, "Custom <strong>top</strong> banner") {
+		t.Error("markdown section content not rendered")
+	}
+}
+
+func TestRenderDashboardTemplateOverride(t *testing.T) {
+	templateDir := t.TempDir()
+	override := `<html><body>{{.ProjectName}} at {{printf "%.1f" .TotalCoverage}}%</body></html>`
+	if err := os.WriteFile(filepath.Join(templateDir, dashboardOverrideFile), []byte(override), 0o600); err != nil {
+		t.Fatalf("failed to write override: %v", err)
+	}
+
+	html, err := NewRenderer(templateDir).RenderDashboard(context.Background(), map[string]any{
+		"ProjectName":   testProjectName,
+		"TotalCoverage": 72.5,
+	})
+	if err != nil {
+		t.Fatalf("RenderDashboard failed: %v", err)
+	}
+	if html != "<html><body>test-project at 72.5%</body></html>" {
+		t.Errorf("override template not used: %q", html)
+	}
+
+	if err := os.WriteFile(filepath.Join(templateDir, dashboardOverrideFile), []byte("{{.Broken"), 0o600); err != nil {
+		t.Fatalf("failed to write override: %v", err)
+	}
+	if _, err := NewRenderer(templateDir).RenderDashboard(context.Background(), map[string]any{}); err == nil {
+		t.Error("expected an error for an invalid override template")
+	}
+}
+
+func TestPreviewSection(t *testing.T) {
+	templateDir := writeSectionFiles(t, map[string]string{
+		"after-metrics-links.html": `<a href="{{.RepositoryURL}}">{{.TotalCoverage}}%</a>`,
+		"sidebar.md":               "# Sidebar",
+	})
+	gen := NewGenerator(&GeneratorConfig{ProjectName: testProjectName})
+	sectionsDir := filepath.Join(templateDir, sectionsDirName)
+
+	position, html, err := gen.PreviewSection(context.Background(), filepath.Join(sectionsDir, "after-metrics-links.html"), SampleCoverageData())
+	if err != nil {
+		t.Fatalf("PreviewSection failed: %v", err)
+	}
+	if position != SectionAfterMetrics {
+		t.Errorf("position = %q, want %q", position, SectionAfterMetrics)
+	}
+	if !strings.Contains(string(html), "84.6%") {
+		t.Errorf("section rendered without the sample data: %s", html)
+	}
+
+	_, _, err = gen.PreviewSection(context.Background(), filepath.Join(sectionsDir, "sidebar.md"), SampleCoverageData())
+	if !errors.Is(err, ErrUnknownSectionPosition) {
+		t.Errorf("expected ErrUnknownSectionPosition, got %v", err)
+	}
+}
@@ -96,6 +96,13 @@ func getDashboardTemplate() string {
                         </div>
                         {{- end}}
                     {{- end}}
+                    {{- if .CoverMode}}
+                    <div class="repo-item" title="Only count and atomic profiles record how often each statement ran">
+                        <span class="repo-icon">🎚️</span>
+                        <span class="repo-label">Cover mode</span>
+                        <span class="repo-value">{{.CoverMode}}</span>
+                    </div>
+                    {{- end}}
                 </div>
 
                 <div class="header-actions">
@@ -116,10 +123,28 @@ func getDashboardTemplate() string {
         </header>
 
         <main>
+            {{- with .CustomSections}}{{template "customSections" .Top}}{{end}}
+            {{- if .NoTests}}
+            <div class="package-list dashboard" id="get-started" style="border-left: 4px solid #58a6ff;">
+                <h3 style="margin-bottom: 0.5rem;">🚀 Get Started with Coverage</h3>
+                <p>No test files were found in this repository, so there is no coverage to report yet and the coverage gates are not enforced.</p>
+                <ol style="margin-top: 0.5rem; padding-left: 1.25rem;">
+                    <li>Add a test next to the code it covers, e.g. <code>example_test.go</code> with a <code>func TestExample(t *testing.T)</code></li>
+                    <li>Run <code>go test -coverprofile=coverage.txt ./...</code> in the workflow</li>
+                    <li>Push, and this dashboard shows the coverage of every package</li>
+                </ol>
+            </div>
+            {{- end}}
+            {{- with .Bypass}}
+            <div class="package-list dashboard" id="bypass" style="border-left: 4px solid #f85149;">
+                <h3 style="margin-bottom: 0.5rem;">🚨 Coverage Gates Bypassed</h3>
+                <p>{{.}}: failed rules were reported as warnings for this run.</p>
+            </div>
+            {{- end}}
             <div class="metrics-grid">
                 <div class="metric-card">
                     <h3>📊 Overall Coverage</h3>
-                    <div class="metric-value success">{{.TotalCoverage}}%</div>
+                    <div class="metric-value success">{{.TotalCoverage}}%{{with .Confidence}} <span title="{{printf "%.0f" .Level}}% confidence band from the last {{.Runs}} runs: {{printf "%.1f" .Lower}}%–{{printf "%.1f" .Upper}}%">± {{printf "%.1f" .Margin}}</span>{{end}}</div>
                     {{- if .PRNumber}}
                     <div class="metric-label">PR Coverage{{- if .BaselineCoverage}} ({{if gt .TotalCoverage .BaselineCoverage}}+{{else if lt .TotalCoverage .BaselineCoverage}}-{{end}}{{printf "%.1f" (sub .TotalCoverage .BaselineCoverage)}}% vs base){{end}}</div>
                     {{- else}}
@@ -248,6 +273,278 @@ func getDashboardTemplate() string {
                 </div>
             </div>
 
+            {{- with .TrendChart}}
+            <div class="package-list dashboard" id="coverage-history">
+                <h3 style="margin-bottom: 1rem;">📈 Coverage History</h3>
+                <svg viewBox="0 0 300 80" preserveAspectRatio="none" style="width: 100%; height: 120px;" role="img" aria-label="Coverage over the last {{.Runs}} runs">
+                    {{- with .Band}}
+                    <polygon points="{{.}}" fill="#a371f7" fill-opacity="0.15"/>
+                    {{- end}}
+                    {{- with .Forecast}}
+                    <polyline points="{{.}}" fill="none" stroke="#a371f7" stroke-width="1.5" stroke-dasharray="5 4" vector-effect="non-scaling-stroke"/>
+                    {{- end}}
+                    {{- with .Average}}
+                    <polyline points="{{.}}" fill="none" stroke="#3fb950" stroke-width="1.5" stroke-opacity="0.8" vector-effect="non-scaling-stroke"/>
+                    {{- end}}
+                    <polyline points="{{.Line}}" fill="none" stroke="#58a6ff" stroke-width="2" vector-effect="non-scaling-stroke"/>
+                    {{- range .Markers}}
+                    <line x1="{{.X}}" y1="0" x2="{{.X}}" y2="80" stroke="#d29922" stroke-width="3" stroke-dasharray="4 3" vector-effect="non-scaling-stroke"><title>{{.Tooltip}}</title></line>
+                    {{- end}}
+                </svg>
+                <p style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Lowest}}%–{{.Highest}}% over the last {{.Runs}} runs{{with $.EnvironmentFilter}} matching <code>{{.}}</code>{{end}}{{if .Average}}; <span style="color: #3fb950;">━</span> {{.Window}}-run average{{end}}{{if .Forecast}}; <span style="color: #a371f7;">┅</span> predicted {{.Predicted}}% by {{.PredictedDate}}{{end}}{{if .Markers}}; <span style="color: #d29922;">┆</span> marks an annotated run, hover it to read the note{{end}}</p>
+                {{- with .Notes}}
+                <ul style="margin-top: 0.5rem;">
+                    {{- range .}}
+                    <li><code>{{.CommitSHA}}</code> {{.Date}}: {{.Note}}</li>
+                    {{- end}}
+                </ul>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- if .Environments}}
+            <div class="package-list dashboard" id="environments">
+                <h3 style="margin-bottom: 1rem;">🖥️ Coverage by Environment</h3>
+                {{- range .Environments}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard"><code>{{.Label}}</code>{{if .Current}} <strong>(this run)</strong>{{end}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Runs}} run{{- if ne .Runs 1}}s{{end -}}, average {{.Average}}%</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Latest 90.0}}#3fb950{{else if ge .Latest 80.0}}#58a6ff{{else if ge .Latest 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Latest}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Latest}}%; background: {{- if ge .Latest 90.0}}var(--gradient-success){{else if ge .Latest 80.0}}var(--gradient-primary){{else if ge .Latest 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- if .CodeClasses}}
+            <div class="package-list dashboard" id="code-classes">
+                <h3 style="margin-bottom: 1rem;">🧬 Coverage by Code Type</h3>
+                {{- range .CodeClasses}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard">{{.Label}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}}</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- if .Teams}}
+            <div class="package-list dashboard" id="teams">
+                <h3 style="margin-bottom: 1rem;">👥 Coverage by Team</h3>
+                {{- range .Teams}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard"><a href="{{.URL}}">{{.Name}}</a> <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Paths}} · {{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}} · {{if .Passed}}✅{{else}}❌{{end}} threshold {{.Threshold}}%</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- if .Modules}}
+            <div class="package-list dashboard" id="modules">
+                <h3 style="margin-bottom: 1rem;">📦 Coverage by Module</h3>
+                {{- range .Modules}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard">{{.Path}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Dir}} · {{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}} · {{if .Passed}}✅{{else}}❌{{end}} threshold {{.Threshold}}%</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- if .Languages}}
+            <div class="package-list dashboard" id="languages">
+                <h3 style="margin-bottom: 1rem;">🌐 Coverage by Language</h3>
+                {{- range .Languages}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard">{{.Language}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}}</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .BuildTags}}
+            <div class="package-list dashboard" id="build-tags">
+                <h3 style="margin-bottom: 1rem;">🏷️ Coverage by Build Tag</h3>
+                {{- range .Tags}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard">{{.Name}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements{{- if .ExclusiveLines}}, {{.ExclusiveLines}} covered only under this tag{{end -}}</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+                {{- if .UncoveredLines}}
+                <p style="margin-top: 1rem; color: #f85149;">⛔ {{.UncoveredLines}} statements are not covered under any build tag</p>
+                {{- end}}
+                {{- if .Files}}
+                <ul style="margin-top: 0.5rem; padding-left: 1.25rem; color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- range .Files}}
+                    <li>
+                        {{- if .GitHubURL}}<a href="{{.GitHubURL}}" target="_blank">{{.Path}}</a>{{else}}{{.Path}}{{end}}
+                        {{- if .UncoveredLines}} — {{.UncoveredLines}} uncovered under every tag{{end}}
+                        {{- if .OnlyUnder}} — only covered under {{.OnlyUnder}}{{end}}
+                    </li>
+                    {{- end}}
+                    {{- if .MoreFiles}}
+                    <li>and {{.MoreFiles}} more file{{- if ne .MoreFiles 1}}s{{end}}</li>
+                    {{- end}}
+                </ul>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .TestHelpers}}
+            <div class="package-list dashboard" id="test-helpers">
+                <h3 style="margin-bottom: 1rem;">🧪 Appendix: Test Helpers</h3>
+                <p><strong>{{.Coverage}}%</strong> of test helper code covered <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}}, not counted in the totals above</span></p>
+                {{- range .Packages}}
+                <div class="package-item dashboard">
+                    <div class="package-name dashboard">{{- if .GitHubURL}}<a href="{{.GitHubURL}}" target="_blank">{{.Name}}</a>{{else}}{{.Name}}{{end}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements</span></div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </div>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .Examples}}
+            <div class="package-list dashboard" id="examples">
+                <h3 style="margin-bottom: 1rem;">📘 Examples and Fuzz Targets</h3>
+                {{- if .TotalLines}}
+                <p><strong>{{.Coverage}}%</strong> of example and fuzz harness code covered <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}}, not counted in the totals above</span></p>
+                {{- end}}
+                {{- if .Examples}}
+                <p><strong>{{.ExamplesRun}}/{{.Examples}}</strong> Example functions run by go test</p>
+                {{- end}}
+                {{- if .FuzzTargets}}
+                <p><strong>{{.FuzzSeeded}}/{{.FuzzTargets}}</strong> fuzz targets with seed inputs run by go test</p>
+                {{- end}}
+                {{- if .Idle}}
+                <ul style="margin-top: 0.5rem; padding-left: 1.25rem; color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- range .Idle}}
+                    <li>{{- if .GitHubURL}}<a href="{{.GitHubURL}}" target="_blank">{{.Name}}</a>{{else}}{{.Name}}{{end}} in {{.Path}} — {{.Reason}}</li>
+                    {{- end}}
+                    {{- if .MoreIdle}}
+                    <li>and {{.MoreIdle}} more</li>
+                    {{- end}}
+                </ul>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .HitCounts}}
+            <div class="package-list dashboard" id="hit-counts">
+                <h3 style="margin-bottom: 1rem;">🔥 Hit Counts</h3>
+                <p><strong>{{.OncePercent}}%</strong> of covered statements executed exactly once <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Once}} of {{.Covered}} statements</span></p>
+                <div style="display: flex; align-items: flex-end; gap: 0.5rem; height: 120px; margin-top: 1rem;" role="img" aria-label="Covered statements by the number of times they were executed">
+                    {{- range .Buckets}}
+                    <div style="flex: 1; height: {{.Height}}%; min-height: 2px; background: var(--gradient-primary); border-radius: 4px 4px 0 0;" title="{{.Statements}} statements executed {{.Label}} times"></div>
+                    {{- end}}
+                </div>
+                <div style="display: flex; gap: 0.5rem; color: var(--color-text-secondary); font-size: 0.85rem; text-align: center;">
+                    {{- range .Buckets}}
+                    <div style="flex: 1;">{{.Label}}×<br>{{.Statements}}</div>
+                    {{- end}}
+                </div>
+                {{- if .Hotspots}}
+                <p style="margin-top: 1rem;"><strong>{{.HotspotStatements}}</strong> statements executed {{.HotspotHits}} times or more</p>
+                <ul style="margin-top: 0.5rem; padding-left: 1.25rem; color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- range .Hotspots}}
+                    <li>{{- if .GitHubURL}}<a href="{{.GitHubURL}}" target="_blank">{{.Path}}:{{.Lines}}</a>{{else}}{{.Path}}:{{.Lines}}{{end}} — {{.Hits}} hits</li>
+                    {{- end}}
+                </ul>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .TestEfficiency}}
+            <div class="package-list dashboard" id="test-efficiency">
+                <h3 style="margin-bottom: 1rem;">⏱️ Test Efficiency</h3>
+                <p><strong>{{.Efficiency}}</strong> covered statements per test second <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Tests}} tests in {{.Duration}}</span></p>
+                {{- if .EfficiencyLine}}
+                <svg viewBox="0 0 300 80" preserveAspectRatio="none" style="width: 100%; height: 120px; margin-top: 1rem;" role="img" aria-label="Test efficiency and test time over the last {{.Runs}} runs">
+                    <polyline points="{{.TimeLine}}" fill="none" stroke="#d29922" stroke-width="2" vector-effect="non-scaling-stroke"/>
+                    <polyline points="{{.EfficiencyLine}}" fill="none" stroke="#58a6ff" stroke-width="2" vector-effect="non-scaling-stroke"/>
+                </svg>
+                <p style="color: var(--color-text-secondary); font-size: 0.85rem;"><span style="color: #58a6ff;">━</span> statements per test second &nbsp; <span style="color: #d29922;">━</span> test time, over the last {{.Runs}} runs</p>
+                {{- end}}
+                {{- if .Outpaced}}
+                <p style="margin-top: 1rem; color: #f85149;">⚠️ Test time grew {{.TimeGrowth}}% while coverage grew {{.CoverageGrowth}}%</p>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .CoverageDebt}}
+            <div class="package-list dashboard" id="coverage-debt">
+                <h3 style="margin-bottom: 1rem;">💸 Coverage Debt</h3>
+                <p><strong>{{.Weighted}}</strong> weighted uncovered statements <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Uncovered}} uncovered, weighted by complexity and critical paths</span></p>
+                {{- if .Line}}
+                <svg viewBox="0 0 300 80" preserveAspectRatio="none" style="width: 100%; height: 120px; margin-top: 1rem;" role="img" aria-label="Coverage debt over the last {{.Runs}} runs">
+                    <polyline points="{{.Line}}" fill="none" stroke="#a371f7" stroke-width="2" vector-effect="non-scaling-stroke"/>
+                </svg>
+                <p style="color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- if .Grew}}Grew by {{.Reduced}} statements{{else}}Reduced by {{.Reduced}} statements{{end}} since {{.Since}}, over the last {{.Runs}} runs</p>
+                {{- end}}
+                {{- if .Files}}
+                <ul style="margin-top: 0.5rem; padding-left: 1.25rem; color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- range .Files}}
+                    <li>{{.Path}} — {{.Weighted}} ({{.Uncovered}} uncovered){{if .Critical}} · critical path{{end}}</li>
+                    {{- end}}
+                </ul>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .DeadCode}}
+            <div class="package-list dashboard" id="dead-code">
+                <h3 style="margin-bottom: 1rem;">🧹 Dead Code Candidates</h3>
+                {{- if .Candidates}}
+                <p><strong>{{.Candidates}}</strong> of {{.EligibleFiles}} Go files have no coverage and no test package linking them <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.Statements}} statements</span></p>
+                <ul style="margin-top: 0.5rem; padding-left: 1.25rem; color: var(--color-text-secondary); font-size: 0.85rem;">
+                    {{- range .Files}}
+                    <li>{{.}}</li>
+                    {{- end}}
+                    {{- if .MoreFiles}}
+                    <li>and {{.MoreFiles}} more file{{- if ne .MoreFiles 1}}s{{end}}</li>
+                    {{- end}}
+                </ul>
+                {{- else}}
+                <p>✅ Every one of the {{.EligibleFiles}} Go files is covered or linked by a test package</p>
+                {{- end}}
+            </div>
+            {{- end}}
+
+            {{- with .Bypasses}}
+            <div class="package-list dashboard" id="bypass-audit">
+                <h3 style="margin-bottom: 1rem;">🚨 Bypass Audit</h3>
+                <ul>
+                    {{- range .}}
+                    <li><code>{{.CommitSHA}}</code> on {{.Branch}} at {{.Time}}: {{.Reason}}</li>
+                    {{- end}}
+                </ul>
+            </div>
+            {{- end}}
+
+            {{- with .CustomSections}}{{template "customSections" .AfterMetrics}}{{end}}
+
             <div class="links-section">
                 <h3 style="margin-bottom: 1rem;">📋 Coverage Reports & Tools</h3>
                 <div class="links-grid">
@@ -275,6 +572,13 @@ func getDashboardTemplate() string {
                 </div>
             </div>
 
+            {{- if .Directories}}
+            <div class="package-list dashboard" id="directories">
+                <h3 style="margin-bottom: 1rem;">🗂️ Coverage by Directory</h3>
+                {{- template "directoryRollup" .Directories}}
+            </div>
+            {{- end}}
+
             {{- if .Packages}}
             <div class="package-list dashboard">
                 <h3 style="margin-bottom: 1rem;">📦 Package Coverage</h3>
@@ -289,11 +593,41 @@ func getDashboardTemplate() string {
                 {{- end}}
             </div>
             {{- end}}
+            {{- with .CustomSections}}{{template "customSections" .Bottom}}{{end}}
         </main>
 
 ` + templates.GetSharedFooter(" dashboard", "Timestamp") + `
     </div>
 
 </body>
-</html>`
+</html>
+{{- define "directoryRollup"}}
+            {{- range .}}
+            <details class="directory-rollup">
+                <summary class="package-item dashboard">
+                    <div class="package-name dashboard">
+                        {{- if .GitHubURL}}<a href="{{.GitHubURL}}" target="_blank">{{.Path}}/</a>{{else}}{{.Path}}/{{end}} <span style="color: var(--color-text-secondary); font-size: 0.85rem;">{{.CoveredLines}}/{{.TotalLines}} statements in {{.Files}} file{{- if ne .Files 1}}s{{end -}}
+                        {{- if .HasChange}}, <span style="color: {{- if .ChangeUp}}#3fb950{{else if .ChangeDown}}#f85149{{else}}var(--color-text-secondary){{end -}};">{{if .ChangeUp}}+{{end}}{{.Change}}%</span>{{end -}}
+                        </span>
+                    </div>
+                    <div class="package-coverage" style="color: {{- if ge .Coverage 90.0}}#3fb950{{else if ge .Coverage 80.0}}#58a6ff{{else if ge .Coverage 60.0}}#d29922{{else}}#f85149{{end -}};">{{.Coverage}}%</div>
+                    <div class="package-bar">
+                        <div class="package-bar-fill" style="width: {{.Coverage}}%; background: {{- if ge .Coverage 90.0}}var(--gradient-success){{else if ge .Coverage 80.0}}var(--gradient-primary){{else if ge .Coverage 60.0}}var(--gradient-warning){{else}}var(--gradient-danger){{end -}};"></div>
+                    </div>
+                </summary>
+                {{- if .Children}}
+                <div style="padding-left: 1.25rem;">
+                    {{- template "directoryRollup" .Children}}
+                </div>
+                {{- end}}
+            </details>
+            {{- end}}
+{{- end}}
+{{- define "customSections"}}
+            {{- range .}}
+            <section class="links-section custom-section" id="{{.ID}}">
+                {{.HTML}}
+            </section>
+            {{- end}}
+{{- end}}`
 }
@@ -2,6 +2,7 @@
 package history
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -49,6 +50,11 @@ type AnalyzerConfig struct {
 	// Quality thresholds
 	MinDataPoints int // Minimum data points for analysis
 	MaxGapDays    int // Maximum gap between data points
+
+	// ExpectedCadence is the expected time between data points, such as 24h for daily runs or
+	// 168h for weekly merges. Slopes are measured per cadence interval and completeness counts
+	// the points missing at this cadence. Zero infers it from the median gap between points.
+	ExpectedCadence time.Duration
 }
 
 // AnalysisDataPoint represents an enhanced data point for analysis
@@ -67,6 +73,10 @@ type AnalysisDataPoint struct {
 	Smoothed     float64   `json:"smoothed_value"`
 	Prediction   float64   `json:"prediction,omitempty"`
 	Confidence   float64   `json:"confidence,omitempty"`
+	// Sequence orders the runs of a branch ahead of their timestamps, when known
+	Sequence int64 `json:"sequence,omitempty"`
+	// ClockSkewed marks a point whose timestamp ran against the order of the runs and was normalized
+	ClockSkewed bool `json:"clock_skewed,omitempty"`
 }
 
 // TrendReport contains comprehensive trend analysis results
@@ -89,7 +99,7 @@ type TrendReport struct {
 	QualityMetrics QualityMetrics `json:"quality_metrics"`
 
 	// Chart data
-	ChartData any `json:"chart_data,omitempty"`
+	ChartData *TrendChart `json:"chart_data,omitempty"`
 
 	// Insights and recommendations
 	Insights        []Insight        `json:"insights"`
@@ -119,7 +129,7 @@ type TrendAnalysis struct {
 	StartDate      time.Time      `json:"start_date"`
 	EndDate        time.Time      `json:"end_date"`
 	Direction      TrendDirection `json:"direction"`
-	Slope          float64        `json:"slope"`
+	Slope          float64        `json:"slope"` // Change per cadence interval
 	RSquared       float64        `json:"r_squared"`
 	Confidence     float64        `json:"confidence"`
 	AverageChange  float64        `json:"average_change"`
@@ -161,6 +171,7 @@ type QualityMetrics struct {
 	DataCompleteness  float64       `json:"data_completeness"`
 	DataConsistency   float64       `json:"data_consistency"`
 	OutlierCount      int           `json:"outlier_count"`
+	SkewedPoints      int           `json:"skewed_points"`
 	MissingDataPoints int           `json:"missing_data_points"`
 	LargestGap        time.Duration `json:"largest_gap"`
 	QualityScore      float64       `json:"quality_score"`
@@ -296,24 +307,29 @@ const (
 	PriorityLow RecommendationPriority = "low"
 )
 
+// DefaultAnalyzerConfig returns the configuration used when none is given
+func DefaultAnalyzerConfig() *AnalyzerConfig {
+	return &AnalyzerConfig{
+		ShortTermDays:       7,
+		MediumTermDays:      30,
+		LongTermDays:        90,
+		MovingAvgWindow:     7,
+		ExponentialAlpha:    0.3,
+		SignificantChange:   1.0,
+		VolatilityThreshold: 5.0,
+		TrendConfidence:     0.7,
+		PredictionDays:      14,
+		SeasonalAdjustment:  true,
+		OutlierDetection:    true,
+		MinDataPoints:       5,
+		MaxGapDays:          7,
+	}
+}
+
 // NewTrendAnalyzer creates a new trend analyzer with default configuration
 func NewTrendAnalyzer(config *AnalyzerConfig) *TrendAnalyzer {
 	if config == nil {
-		config = &AnalyzerConfig{
-			ShortTermDays:       7,
-			MediumTermDays:      30,
-			LongTermDays:        90,
-			MovingAvgWindow:     7,
-			ExponentialAlpha:    0.3,
-			SignificantChange:   1.0,
-			VolatilityThreshold: 5.0,
-			TrendConfidence:     0.7,
-			PredictionDays:      14,
-			SeasonalAdjustment:  true,
-			OutlierDetection:    true,
-			MinDataPoints:       5,
-			MaxGapDays:          7,
-		}
+		config = DefaultAnalyzerConfig()
 	}
 
 	return &TrendAnalyzer{
@@ -334,29 +350,28 @@ func (ta *TrendAnalyzer) LoadHistoryData(ctx context.Context, historyTracker *hi
 		return fmt.Errorf("failed to load history data: %w", err)
 	}
 
-	// Convert trend entries to analysis data points
-	if trendData.Entries == nil {
-		ta.data = make([]AnalysisDataPoint, 0)
-		return nil
-	}
+	ta.LoadEntries(trendData.Entries)
+	return nil
+}
 
-	ta.data = make([]AnalysisDataPoint, 0, len(trendData.Entries))
-	for _, entry := range trendData.Entries {
+// LoadEntries loads history entries as returned by the tracker: ordered newest first, with
+// their timestamps normalized. Entries without coverage are skipped.
+func (ta *TrendAnalyzer) LoadEntries(entries []history.Entry) {
+	ta.data = make([]AnalysisDataPoint, 0, len(entries))
+	for _, entry := range slices.Backward(entries) {
+		if entry.Coverage == nil {
+			continue
+		}
 		point := AnalysisDataPoint{
-			Timestamp: entry.Timestamp,
-			Coverage:  entry.Coverage.Percentage,
-			Branch:    entry.Branch,
-			CommitSHA: entry.CommitSHA,
+			Timestamp:   entry.Timestamp,
+			Coverage:    entry.Coverage.Percentage,
+			Branch:      entry.Branch,
+			CommitSHA:   entry.CommitSHA,
+			Sequence:    entry.Sequence,
+			ClockSkewed: !entry.SkewedTimestamp.IsZero(),
 		}
 		ta.data = append(ta.data, point)
 	}
-
-	// Sort by timestamp
-	slices.SortFunc(ta.data, func(a, b AnalysisDataPoint) int {
-		return a.Timestamp.Compare(b.Timestamp)
-	})
-
-	return nil
 }
 
 // LoadCustomData loads custom analysis data points
@@ -364,10 +379,31 @@ func (ta *TrendAnalyzer) LoadCustomData(dataPoints []AnalysisDataPoint) {
 	ta.data = make([]AnalysisDataPoint, len(dataPoints))
 	copy(ta.data, dataPoints)
 
-	// Sort by timestamp
-	slices.SortFunc(ta.data, func(a, b AnalysisDataPoint) int {
+	// Order by sequence number where known, then by timestamp
+	slices.SortStableFunc(ta.data, func(a, b AnalysisDataPoint) int {
+		if c := cmp.Compare(a.Sequence, b.Sequence); c != 0 {
+			return c
+		}
 		return a.Timestamp.Compare(b.Timestamp)
 	})
+
+	ta.normalizeTimestamps()
+}
+
+// normalizeTimestamps replaces the timestamps that run against the order of the data points,
+// from runners with skewed clocks, so time spans and gaps are measured in the order of the runs
+func (ta *TrendAnalyzer) normalizeTimestamps() {
+	times := make([]time.Time, len(ta.data))
+	for i, point := range ta.data {
+		times[i] = point.Timestamp
+	}
+	normalized, skewed := history.NormalizeTimestamps(times, time.Now())
+	for i := range ta.data {
+		if skewed[i] {
+			ta.data[i].Timestamp = normalized[i]
+			ta.data[i].ClockSkewed = true
+		}
+	}
 }
 
 // AnalyzeTrends performs comprehensive trend analysis
@@ -411,7 +447,7 @@ func (ta *TrendAnalyzer) AnalyzeTrends(_ context.Context) (*TrendReport, error)
 	report.QualityMetrics = ta.calculateQualityMetrics()
 
 	// Generate chart data
-	report.ChartData = ta.generateChartData()
+	report.ChartData = ta.generateChartData(predictions)
 
 	// Generate insights and recommendations
 	report.Insights = ta.generateInsights(report)
@@ -520,8 +556,9 @@ func (ta *TrendAnalyzer) generateSummary() TrendSummary {
 
 // analyzePeriodTrend analyzes trend for a specific time period
 func (ta *TrendAnalyzer) analyzePeriodTrend(days int, period string) TrendAnalysis {
-	// Filter data for the specified period
-	cutoff := time.Now().AddDate(0, 0, -days)
+	// Filter data for the specified period, widened for sparse data to span a few cadence intervals
+	window := max(time.Duration(days)*24*time.Hour, time.Duration(minPeriodIntervals)*ta.cadence())
+	cutoff := time.Now().Add(-window)
 	var periodData []AnalysisDataPoint
 
 	for _, point := range ta.data {
@@ -583,50 +620,40 @@ func (ta *TrendAnalyzer) analyzePeriodTrend(days int, period string) TrendAnalys
 	}
 }
 
-// calculateLinearRegression calculates slope and R-squared for linear trend
+// calculateLinearRegression calculates slope and R-squared for linear trend. The points are placed
+// by their timestamps, in cadence intervals, and weighted by the time they stand for, so bursts of
+// runs do not outweigh a quiet week; points without a time span between them are placed by index.
 func (ta *TrendAnalyzer) calculateLinearRegression(data []AnalysisDataPoint) (float64, float64) {
 	if len(data) < 2 {
 		return 0, 0
 	}
 
-	n := float64(len(data))
-	var sumX, sumY, sumXY, sumX2, sumY2 float64
+	x, weights := ta.samplePositions(data)
 
+	var sumW, sumX, sumY float64
 	for i, point := range data {
-		x := float64(i)
-		y := point.Smoothed
-
-		sumX += x
-		sumY += y
-		sumXY += x * y
-		sumX2 += x * x
-		sumY2 += y * y
-	}
-
-	// Calculate slope (beta1)
-	denominator := n*sumX2 - sumX*sumX
-	if denominator == 0 {
-		return 0, 0
+		sumW += weights[i]
+		sumX += weights[i] * x[i]
+		sumY += weights[i] * point.Smoothed
 	}
+	xMean, yMean := sumX/sumW, sumY/sumW
 
-	slope := (n*sumXY - sumX*sumY) / denominator
-
-	// Calculate R-squared
-	yMean := sumY / n
-	var ssr, sst float64
-
+	var sxx, sxy, syy float64
 	for i, point := range data {
-		x := float64(i)
-		y := point.Smoothed
-		yPred := slope*x + (yMean - slope*(sumX/n))
-
-		ssr += (yPred - yMean) * (yPred - yMean)
-		sst += (y - yMean) * (y - yMean)
+		dx, dy := x[i]-xMean, point.Smoothed-yMean
+		sxx += weights[i] * dx * dx
+		sxy += weights[i] * dx * dy
+		syy += weights[i] * dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0
 	}
 
+	slope := sxy / sxx
 	rSquared := 0.0
-	if sst != 0 {
-		rSquared = ssr / sst
+	if syy != 0 {
+		// Rounding can push a perfect fit just past 1
+		rSquared = min(1, sxy*sxy/(sxx*syy))
 	}
 
 	return slope, rSquared
@@ -712,11 +739,12 @@ func (ta *TrendAnalyzer) generatePredictions() ([]PredictionPoint, error) {
 
 	lastPoint := ta.data[len(ta.data)-1]
 
+	intervalsPerDay := float64(24*time.Hour) / float64(ta.cadence())
 	for i := 1; i <= ta.config.PredictionDays; i++ {
 		futureDate := lastPoint.Timestamp.AddDate(0, 0, i)
 
-		// Simple linear prediction
-		predictedValue := lastPoint.Smoothed + slope*float64(i)
+		// Simple linear prediction, the slope being per cadence interval
+		predictedValue := lastPoint.Smoothed + slope*float64(i)*intervalsPerDay
 
 		// Clamp to reasonable bounds
 		predictedValue = math.Max(0, math.Min(100, predictedValue))
@@ -752,29 +780,27 @@ func (ta *TrendAnalyzer) calculateQualityMetrics() QualityMetrics {
 		return QualityMetrics{}
 	}
 
-	// Count outliers
-	outlierCount := 0
+	// Count outliers and points normalized for clock skew
+	outlierCount, skewedPoints := 0, 0
 	for _, point := range ta.data {
 		if point.IsOutlier {
 			outlierCount++
 		}
+		if point.ClockSkewed {
+			skewedPoints++
+		}
 	}
 
-	// Calculate data completeness (simplified)
-	expectedDataPoints := int(ta.data[len(ta.data)-1].Timestamp.Sub(ta.data[0].Timestamp).Hours() / 24)
-	completeness := float64(len(ta.data)) / float64(expectedDataPoints) * 100
-	if completeness > 100 {
-		completeness = 100
-	}
-
-	// Calculate largest gap
+	// Calculate data completeness from the points missing in gaps longer than the cadence
+	cadence := ta.cadence()
+	missing := 0
 	largestGap := time.Duration(0)
 	for i := 1; i < len(ta.data); i++ {
 		gap := ta.data[i].Timestamp.Sub(ta.data[i-1].Timestamp)
-		if gap > largestGap {
-			largestGap = gap
-		}
+		missing += max(0, int(math.Round(float64(gap)/float64(cadence)))-1)
+		largestGap = max(largestGap, gap)
 	}
+	completeness := float64(len(ta.data)) / float64(len(ta.data)+missing) * 100
 
 	// Calculate consistency (based on volatility)
 	volatility := ta.analyzeVolatility()
@@ -800,20 +826,14 @@ func (ta *TrendAnalyzer) calculateQualityMetrics() QualityMetrics {
 		DataCompleteness:  completeness,
 		DataConsistency:   consistency,
 		OutlierCount:      outlierCount,
-		MissingDataPoints: expectedDataPoints - len(ta.data),
+		SkewedPoints:      skewedPoints,
+		MissingDataPoints: missing,
 		LargestGap:        largestGap,
 		QualityScore:      qualityScore,
 		ReliabilityGrade:  reliabilityGrade,
 	}
 }
 
-// generateChartData creates chart data for visualization
-func (ta *TrendAnalyzer) generateChartData() any {
-	// Chart functionality not implemented - return nil for now
-	// This could be implemented later with a proper charting library
-	return nil
-}
-
 // Helper methods for calculations and determinations
 
 func (ta *TrendAnalyzer) determineDirection(change float64) TrendDirection {
@@ -220,6 +220,27 @@ func (suite *AnalyzerTestSuite) TestLoadCustomDataUnsorted() {
 	}
 }
 
+// TestLoadCustomDataClockSkew tests that sequence numbers order the data ahead of skewed timestamps
+func (suite *AnalyzerTestSuite) TestLoadCustomDataClockSkew() {
+	now := time.Now()
+	dataPoints := []AnalysisDataPoint{
+		{Timestamp: now.Add(-1 * time.Hour), Coverage: 80.0, Sequence: 4},
+		{Timestamp: now.Add(-9 * time.Hour), Coverage: 78.0, Sequence: 3},
+		{Timestamp: now.Add(-3 * time.Hour), Coverage: 76.0, Sequence: 2},
+		{Timestamp: now.Add(-4 * time.Hour), Coverage: 74.0, Sequence: 1},
+	}
+
+	suite.analyzer.LoadCustomData(dataPoints)
+
+	suite.Require().Len(suite.analyzer.data, 4)
+	for i, point := range suite.analyzer.data {
+		suite.Equal(int64(i+1), point.Sequence)
+	}
+	suite.True(suite.analyzer.data[2].ClockSkewed)
+	suite.Equal(suite.analyzer.data[1].Timestamp, suite.analyzer.data[2].Timestamp)
+	suite.Equal(1, suite.analyzer.calculateQualityMetrics().SkewedPoints)
+}
+
 // TestAnalyzeTrendsSuccess tests successful trend analysis
 func (suite *AnalyzerTestSuite) TestAnalyzeTrendsSuccess() {
 	ctx := context.Background()
@@ -0,0 +1,158 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TrendChart is the chart payload of a trend report: the measured coverage, its moving average
+// and the prediction band, all oldest first
+type TrendChart struct {
+	Points              []ChartPoint     `json:"points"`
+	MovingAverage       []ChartPoint     `json:"moving_average"`
+	MovingAverageWindow int              `json:"moving_average_window"`
+	Prediction          []ChartBandPoint `json:"prediction,omitempty"`
+}
+
+// ChartPoint is a coverage value at a point in time
+type ChartPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// ChartBandPoint is a predicted coverage value with its confidence bounds
+type ChartBandPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Lower     float64   `json:"lower"`
+	Upper     float64   `json:"upper"`
+}
+
+// generateChartData creates the chart payload from the loaded data and the predictions. The
+// moving average at each point is the mean of the last MovingAvgWindow points up to it.
+func (ta *TrendAnalyzer) generateChartData(predictions []PredictionPoint) *TrendChart {
+	if len(ta.data) == 0 {
+		return nil
+	}
+
+	window := max(1, ta.config.MovingAvgWindow)
+	chart := &TrendChart{
+		Points:              make([]ChartPoint, 0, len(ta.data)),
+		MovingAverage:       make([]ChartPoint, 0, len(ta.data)),
+		MovingAverageWindow: window,
+	}
+
+	sum := 0.0
+	for i, point := range ta.data {
+		sum += point.Coverage
+		if i >= window {
+			sum -= ta.data[i-window].Coverage
+		}
+		chart.Points = append(chart.Points, ChartPoint{Timestamp: point.Timestamp, Value: point.Coverage})
+		chart.MovingAverage = append(chart.MovingAverage, ChartPoint{Timestamp: point.Timestamp, Value: sum / float64(min(i+1, window))})
+	}
+
+	for _, prediction := range predictions {
+		chart.Prediction = append(chart.Prediction, ChartBandPoint{
+			Timestamp: prediction.Date,
+			Value:     prediction.PredictedCoverage,
+			Lower:     prediction.ConfidenceInterval.Lower,
+			Upper:     prediction.ConfidenceInterval.Upper,
+		})
+	}
+
+	return chart
+}
+
+// ChartLayout places a trend chart in an SVG viewport: time runs from the first point to the last
+// prediction, and coverage is scaled between the lowest and highest value drawn so small changes
+// stay visible
+type ChartLayout struct {
+	Width, Height   float64
+	Padding         float64
+	Start, End      time.Time
+	Lowest, Highest float64
+}
+
+// NewChartLayout fits a chart into a viewport of the given size
+func NewChartLayout(chart *TrendChart, width, height float64) ChartLayout {
+	layout := ChartLayout{Width: width, Height: height, Padding: 4}
+	if chart == nil || len(chart.Points) == 0 {
+		return layout
+	}
+
+	layout.Start, layout.End = chart.Points[0].Timestamp, chart.Points[len(chart.Points)-1].Timestamp
+	layout.Lowest, layout.Highest = chart.Points[0].Value, chart.Points[0].Value
+	include := func(values ...float64) {
+		for _, value := range values {
+			layout.Lowest = min(layout.Lowest, value)
+			layout.Highest = max(layout.Highest, value)
+		}
+	}
+	for _, point := range chart.Points {
+		include(point.Value)
+	}
+	for _, point := range chart.MovingAverage {
+		include(point.Value)
+	}
+	for _, point := range chart.Prediction {
+		include(point.Lower, point.Upper)
+		if point.Timestamp.After(layout.End) {
+			layout.End = point.Timestamp
+		}
+	}
+	return layout
+}
+
+// X returns the horizontal position of a time
+func (l ChartLayout) X(t time.Time) float64 {
+	span := l.End.Sub(l.Start)
+	if span <= 0 {
+		return 0
+	}
+	return l.Width * float64(t.Sub(l.Start)) / float64(span)
+}
+
+// Y returns the vertical position of a coverage value
+func (l ChartLayout) Y(value float64) float64 {
+	if l.Highest <= l.Lowest {
+		return l.Height / 2
+	}
+	return l.Height - l.Padding - (value-l.Lowest)/(l.Highest-l.Lowest)*(l.Height-2*l.Padding)
+}
+
+// Polyline returns the SVG polyline points of a series
+func (l ChartLayout) Polyline(points []ChartPoint) string {
+	coordinates := make([]string, 0, len(points))
+	for _, point := range points {
+		coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", l.X(point.Timestamp), l.Y(point.Value)))
+	}
+	return strings.Join(coordinates, " ")
+}
+
+// Band returns the SVG polygon points of a prediction band: along the upper bounds and back
+// along the lower bounds
+func (l ChartLayout) Band(points []ChartBandPoint) string {
+	coordinates := make([]string, 0, 2*len(points))
+	for _, point := range points {
+		coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", l.X(point.Timestamp), l.Y(point.Upper)))
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", l.X(points[i].Timestamp), l.Y(points[i].Lower)))
+	}
+	return strings.Join(coordinates, " ")
+}
+
+// Forecast returns the predicted values as a series continuing from the last measured point
+func (c *TrendChart) Forecast() []ChartPoint {
+	if len(c.Points) == 0 || len(c.Prediction) == 0 {
+		return nil
+	}
+	forecast := make([]ChartPoint, 0, len(c.Prediction)+1)
+	forecast = append(forecast, c.Points[len(c.Points)-1])
+	for _, point := range c.Prediction {
+		forecast = append(forecast, ChartPoint{Timestamp: point.Timestamp, Value: point.Value})
+	}
+	return forecast
+}
@@ -0,0 +1,64 @@
+package history
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Size of the static trend chart and the margins around its plot area, in SVG user units
+const (
+	svgChartWidth  = 600.0
+	svgChartHeight = 200.0
+	svgMarginLeft  = 48.0
+	svgMarginRight = 12.0
+	svgMarginTop   = 28.0
+	svgMarginBelow = 24.0
+)
+
+// RenderTrendSVG renders a trend chart as a self-contained SVG image for embedding in PR
+// comments and READMEs: the measured coverage, its moving average, and the prediction as a dashed
+// line in its shaded confidence band. It returns nil for charts with fewer than two points.
+func RenderTrendSVG(chart *TrendChart, title string) []byte {
+	if chart == nil || len(chart.Points) < 2 {
+		return nil
+	}
+
+	plotWidth := svgChartWidth - svgMarginLeft - svgMarginRight
+	plotHeight := svgChartHeight - svgMarginTop - svgMarginBelow
+	layout := NewChartLayout(chart, plotWidth, plotHeight)
+	last := chart.Points[len(chart.Points)-1]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" role="img" aria-label="%s">`+"\n",
+		svgChartWidth, svgChartHeight, svgChartWidth, svgChartHeight, html.EscapeString(title))
+	b.WriteString(`<style>text{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;font-size:11px;fill:#57606a}</style>` + "\n")
+	fmt.Fprintf(&b, `<rect width="%.0f" height="%.0f" rx="6" fill="#ffffff" stroke="#d0d7de"/>`+"\n", svgChartWidth, svgChartHeight)
+	fmt.Fprintf(&b, `<text x="%.0f" y="18" style="font-size:13px;font-weight:600;fill:#24292f">%s</text>`+"\n", svgMarginLeft, html.EscapeString(title))
+	fmt.Fprintf(&b, `<text x="%.0f" y="18" text-anchor="end">%.1f%%</text>`+"\n", svgChartWidth-svgMarginRight, last.Value)
+
+	fmt.Fprintf(&b, `<g transform="translate(%.0f %.0f)">`+"\n", svgMarginLeft, svgMarginTop)
+	for _, value := range []float64{layout.Highest, layout.Lowest} {
+		y := layout.Y(value)
+		fmt.Fprintf(&b, `<line x1="0" y1="%.1f" x2="%.0f" y2="%.1f" stroke="#eaeef2"/>`+"\n", y, plotWidth, y)
+		fmt.Fprintf(&b, `<text x="-6" y="%.1f" text-anchor="end" dominant-baseline="middle">%.1f%%</text>`+"\n", y, value)
+	}
+	if len(chart.Prediction) > 0 {
+		fmt.Fprintf(&b, `<polygon points="%s" fill="#8250df" fill-opacity="0.12"/>`+"\n", layout.Band(chart.Prediction))
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#8250df" stroke-width="1.5" stroke-dasharray="5 4"/>`+"\n", layout.Polyline(chart.Forecast()))
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#9a6700" stroke-width="1.5" stroke-opacity="0.8"/>`+"\n", layout.Polyline(chart.MovingAverage))
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#0969da" stroke-width="2"/>`+"\n", layout.Polyline(chart.Points))
+	b.WriteString("</g>\n")
+
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f">%s</text>`+"\n", svgMarginLeft, svgChartHeight-8, layout.Start.Format("2006-01-02"))
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="end">%s</text>`+"\n", svgChartWidth-svgMarginRight, svgChartHeight-8, layout.End.Format("2006-01-02"))
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="middle"><tspan fill="#0969da">━ coverage</tspan>  <tspan fill="#9a6700">━ %d-run average</tspan>`,
+		svgMarginLeft+plotWidth/2, svgChartHeight-8, chart.MovingAverageWindow)
+	if len(chart.Prediction) > 0 {
+		b.WriteString(`  <tspan fill="#8250df">┅ prediction</tspan>`)
+	}
+	b.WriteString("</text>\n</svg>\n")
+
+	return []byte(b.String())
+}
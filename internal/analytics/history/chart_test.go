@@ -0,0 +1,90 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateChartData(t *testing.T) {
+	analyzer := NewTrendAnalyzer(&AnalyzerConfig{MovingAvgWindow: 3})
+	assert.Nil(t, analyzer.generateChartData(nil))
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, coverage := range []float64{70, 73, 76, 79} {
+		analyzer.data = append(analyzer.data, AnalysisDataPoint{Timestamp: start.Add(time.Duration(i) * 24 * time.Hour), Coverage: coverage})
+	}
+	predictions := []PredictionPoint{{
+		Date:               start.Add(5 * 24 * time.Hour),
+		PredictedCoverage:  82,
+		ConfidenceInterval: ConfidenceInterval{Lower: 80, Upper: 84},
+	}}
+
+	chart := analyzer.generateChartData(predictions)
+	require.NotNil(t, chart)
+	require.Len(t, chart.Points, 4)
+	assert.Equal(t, 3, chart.MovingAverageWindow)
+	assert.InDelta(t, 70.0, chart.MovingAverage[0].Value, 0.001)
+	assert.InDelta(t, 71.5, chart.MovingAverage[1].Value, 0.001)
+	assert.InDelta(t, 76.0, chart.MovingAverage[3].Value, 0.001, "the average covers the last three runs")
+	require.Len(t, chart.Prediction, 1)
+	assert.InDelta(t, 84.0, chart.Prediction[0].Upper, 0.001)
+
+	forecast := chart.Forecast()
+	require.Len(t, forecast, 2)
+	assert.Equal(t, chart.Points[3], forecast[0], "the forecast continues from the last run")
+}
+
+func TestChartLayout(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	chart := &TrendChart{
+		Points:     []ChartPoint{{Timestamp: start, Value: 70}, {Timestamp: start.Add(24 * time.Hour), Value: 80}},
+		Prediction: []ChartBandPoint{{Timestamp: start.Add(48 * time.Hour), Value: 85, Lower: 80, Upper: 90}},
+	}
+
+	layout := NewChartLayout(chart, 200, 108)
+	assert.Equal(t, start.Add(48*time.Hour), layout.End, "time runs to the last prediction")
+	assert.InDelta(t, 70.0, layout.Lowest, 0.001)
+	assert.InDelta(t, 90.0, layout.Highest, 0.001)
+	assert.InDelta(t, 100.0, layout.X(start.Add(24*time.Hour)), 0.001)
+	assert.InDelta(t, 104.0, layout.Y(70), 0.001)
+	assert.InDelta(t, 4.0, layout.Y(90), 0.001)
+	assert.Equal(t, "0.0,104.0 100.0,54.0", layout.Polyline(chart.Points))
+	assert.Equal(t, "200.0,4.0 200.0,54.0", layout.Band(chart.Prediction))
+
+	flat := NewChartLayout(&TrendChart{Points: []ChartPoint{{Timestamp: start, Value: 75}}}, 200, 100)
+	assert.Zero(t, flat.X(start))
+	assert.InDelta(t, 50.0, flat.Y(75), 0.001)
+}
+
+func TestRenderTrendSVG(t *testing.T) {
+	assert.Nil(t, RenderTrendSVG(nil, "trend"))
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	chart := &TrendChart{
+		Points:              []ChartPoint{{Timestamp: start, Value: 70}},
+		MovingAverage:       []ChartPoint{{Timestamp: start, Value: 70}},
+		MovingAverageWindow: 7,
+	}
+	assert.Nil(t, RenderTrendSVG(chart, "trend"), "a single run has no trend to draw")
+
+	chart.Points = append(chart.Points, ChartPoint{Timestamp: start.Add(24 * time.Hour), Value: 80})
+	chart.MovingAverage = append(chart.MovingAverage, ChartPoint{Timestamp: start.Add(24 * time.Hour), Value: 75})
+	svg := string(RenderTrendSVG(chart, "Coverage trend: <main>"))
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, svg, "Coverage trend: &lt;main&gt;")
+	assert.Contains(t, svg, "80.0%")
+	assert.Contains(t, svg, "7-run average")
+	assert.Contains(t, svg, "2026-10-01")
+	assert.NotContains(t, svg, "<polygon", "no band without predictions")
+
+	chart.Prediction = []ChartBandPoint{{Timestamp: start.Add(48 * time.Hour), Value: 85, Lower: 80, Upper: 90}}
+	svg = string(RenderTrendSVG(chart, "trend"))
+	assert.Contains(t, svg, "<polygon")
+	assert.Contains(t, svg, `stroke-dasharray="5 4"`)
+	assert.Contains(t, svg, "prediction")
+	assert.Contains(t, svg, "2026-10-03", "the time axis runs to the last prediction")
+}
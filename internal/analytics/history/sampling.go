@@ -0,0 +1,62 @@
+package history
+
+import (
+	"slices"
+	"time"
+)
+
+// minPeriodIntervals is how many cadence intervals a period trend spans at least, so weekly data
+// still has points in the short-term window
+const minPeriodIntervals = 3
+
+// defaultCadence is the cadence assumed when it is neither configured nor can be inferred
+const defaultCadence = 24 * time.Hour
+
+// cadence returns the expected time between data points: the configured cadence, or else the
+// median gap between the loaded points
+func (ta *TrendAnalyzer) cadence() time.Duration {
+	if ta.config.ExpectedCadence > 0 {
+		return ta.config.ExpectedCadence
+	}
+
+	var gaps []time.Duration
+	for i := 1; i < len(ta.data); i++ {
+		if gap := ta.data[i].Timestamp.Sub(ta.data[i-1].Timestamp); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return defaultCadence
+	}
+	slices.Sort(gaps)
+	return gaps[len(gaps)/2]
+}
+
+// samplePositions places data points, oldest first, on the time axis in cadence intervals since
+// the first point, and weights each by the time it stands for: half the gap to each neighbor, each
+// gap capped at one interval and a missing neighbor counting as one. Regularly sampled points all
+// weigh 1. Points without a time span between them are placed by index with equal weights.
+func (ta *TrendAnalyzer) samplePositions(data []AnalysisDataPoint) ([]float64, []float64) {
+	x := make([]float64, len(data))
+	weights := make([]float64, len(data))
+
+	if len(data) == 0 || !data[len(data)-1].Timestamp.After(data[0].Timestamp) {
+		for i := range data {
+			x[i], weights[i] = float64(i), 1
+		}
+		return x, weights
+	}
+
+	cadence := float64(ta.cadence())
+	interval := func(i, j int) float64 {
+		if i < 0 || j >= len(data) {
+			return 1
+		}
+		return min(1, float64(data[j].Timestamp.Sub(data[i].Timestamp))/cadence)
+	}
+	for i := range data {
+		x[i] = float64(data[i].Timestamp.Sub(data[0].Timestamp)) / cadence
+		weights[i] = (interval(i-1, i) + interval(i, i+1)) / 2
+	}
+	return x, weights
+}
@@ -0,0 +1,103 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weeklyPoints returns one point a week until now, coverage rising a point a week, skipping the
+// given weeks ago
+func weeklyPoints(weeks int, skip ...int) []AnalysisDataPoint {
+	now := time.Now()
+	var points []AnalysisDataPoint
+	for week := weeks - 1; week >= 0; week-- {
+		if slices.Contains(skip, week) {
+			continue
+		}
+		points = append(points, AnalysisDataPoint{
+			Timestamp: now.Add(-time.Duration(week) * 7 * 24 * time.Hour),
+			Coverage:  float64(80 - week),
+		})
+	}
+	return points
+}
+
+func TestSparseWeeklyData(t *testing.T) {
+	analyzer := NewTrendAnalyzer(nil)
+	analyzer.LoadCustomData(weeklyPoints(10))
+	analyzer.preprocessData()
+
+	assert.Equal(t, 7*24*time.Hour, analyzer.cadence())
+
+	quality := analyzer.calculateQualityMetrics()
+	assert.InDelta(t, 100.0, quality.DataCompleteness, 0.001)
+	assert.Zero(t, quality.MissingDataPoints)
+
+	shortTerm := analyzer.analyzePeriodTrend(7, "short-term")
+	assert.Positive(t, shortTerm.Slope, "the short-term window widens to span a few weeks")
+	assert.False(t, shortTerm.StartDate.IsZero())
+
+	predictions, err := analyzer.generatePredictions()
+	require.NoError(t, err)
+	require.Len(t, predictions, 14)
+	last := analyzer.data[len(analyzer.data)-1].Smoothed
+	assert.InDelta(t, 1.0, predictions[6].PredictedCoverage-last, 0.5, "a week ahead is about one weekly interval of change")
+}
+
+func TestSparseDataGaps(t *testing.T) {
+	analyzer := NewTrendAnalyzer(nil)
+	analyzer.LoadCustomData(weeklyPoints(8, 3))
+
+	quality := analyzer.calculateQualityMetrics()
+	assert.Equal(t, 1, quality.MissingDataPoints)
+	assert.InDelta(t, 7.0/8*100, quality.DataCompleteness, 0.001)
+	assert.Equal(t, 14*24*time.Hour, quality.LargestGap)
+
+	daily := NewTrendAnalyzer(&AnalyzerConfig{ExpectedCadence: 24 * time.Hour})
+	daily.LoadCustomData(weeklyPoints(3))
+	assert.Equal(t, 12, daily.calculateQualityMetrics().MissingDataPoints, "six days are missing between weekly points at a daily cadence")
+}
+
+func TestSamplePositions(t *testing.T) {
+	now := time.Now()
+	week := 7 * 24 * time.Hour
+	analyzer := NewTrendAnalyzer(&AnalyzerConfig{ExpectedCadence: week})
+	data := []AnalysisDataPoint{
+		{Timestamp: now},
+		{Timestamp: now.Add(week / 7)},
+		{Timestamp: now.Add(week)},
+		{Timestamp: now.Add(3 * week)},
+	}
+
+	x, weights := analyzer.samplePositions(data)
+	assert.InDeltaSlice(t, []float64{0, 1.0 / 7, 1, 3}, x, 0.001)
+	assert.InDeltaSlice(t, []float64{(1 + 1.0/7) / 2, (1.0/7 + 6.0/7) / 2, (6.0/7 + 1) / 2, 1}, weights, 0.001)
+
+	x, weights = analyzer.samplePositions([]AnalysisDataPoint{{Timestamp: now}, {Timestamp: now}})
+	assert.Equal(t, []float64{0, 1}, x)
+	assert.Equal(t, []float64{1, 1}, weights)
+}
+
+func TestRegressionPlacesPointsByTime(t *testing.T) {
+	now := time.Now()
+	week := 7 * 24 * time.Hour
+	analyzer := NewTrendAnalyzer(&AnalyzerConfig{ExpectedCadence: week})
+
+	// Weekly points on a rising line, with a burst of reruns within minutes of the first one
+	var data []AnalysisDataPoint
+	data = append(data, AnalysisDataPoint{Timestamp: now, Smoothed: 70})
+	for i := range 10 {
+		data = append(data, AnalysisDataPoint{Timestamp: now.Add(time.Duration(i+1) * time.Minute), Smoothed: 70})
+	}
+	for i := 1; i < 6; i++ {
+		data = append(data, AnalysisDataPoint{Timestamp: now.Add(time.Duration(i) * week), Smoothed: 70 + float64(i)})
+	}
+
+	slope, rSquared := analyzer.calculateLinearRegression(data)
+	assert.InDelta(t, 1.0, slope, 0.001, "the reruns stand for minutes, not weeks")
+	assert.InDelta(t, 1.0, rSquared, 0.001)
+}
@@ -21,6 +21,7 @@ import (
 type Generator struct {
 	config   *Config
 	renderer *Renderer
+	pages    int
 }
 
 // Config holds report generation configuration
@@ -32,6 +33,13 @@ type Config struct {
 	CommitSHA         string
 	PRNumber          string
 	GoogleAnalyticsID string
+	// MaxPageBytes is the size budget of a report page; larger reports are paginated (0 = never)
+	MaxPageBytes int
+	// ModuleRewrites are applied to file paths before they are linked, for coverage data
+	// parsed without them
+	ModuleRewrites []parser.ModuleRewrite
+	// ModuleRoot is the directory of the Go module in the repository, prefixed to linked paths
+	ModuleRoot string
 }
 
 // Data represents the complete data needed for report generation
@@ -53,6 +61,8 @@ type Data struct {
 	LatestTag         string
 	GoogleAnalyticsID string
 	Config            map[string]any
+	ExclusionPresets  []parser.ExclusionPreset // Exclusion presets active while parsing
+	Pagination        *Pagination              // Set when the report is split across pages
 }
 
 // Summary provides high-level coverage statistics
@@ -74,16 +84,18 @@ type PackageReport struct {
 	TotalLines   int
 	CoveredLines int
 	Files        []FileReport
+	FileCount    int    // Number of files when they are loaded lazily
+	FilesURL     string // JSON chunk holding the files of a paginated report
 }
 
 // FileReport represents coverage data for a file
 type FileReport struct {
-	Name         string
-	Path         string
-	URL          string
-	Percentage   float64
-	TotalLines   int
-	CoveredLines int
+	Name         string  `json:"name"`
+	Path         string  `json:"path"`
+	URL          string  `json:"url,omitempty"`
+	Percentage   float64 `json:"percentage"`
+	TotalLines   int     `json:"total_lines"`
+	CoveredLines int     `json:"covered_lines"`
 }
 
 // NewGenerator creates a new report generator
@@ -101,6 +113,11 @@ func (g *Generator) Generate(ctx context.Context, coverage *parser.CoverageData)
 		return fmt.Errorf("creating output directory: %w", err)
 	}
 
+	// Drop the pages of an earlier report, which may have been split differently
+	if err := RemovePages(g.config.OutputDir); err != nil {
+		return err
+	}
+
 	// Build report data
 	data := g.buildReportData(ctx, coverage)
 
@@ -110,10 +127,18 @@ func (g *Generator) Generate(ctx context.Context, coverage *parser.CoverageData)
 		return fmt.Errorf("rendering report: %w", err)
 	}
 
-	// Write report HTML
-	reportPath := filepath.Join(g.config.OutputDir, "coverage.html")
-	if err := os.WriteFile(reportPath, html, 0o600); err != nil {
-		return fmt.Errorf("writing report HTML: %w", err)
+	// Split reports over the size budget into pages with lazily loaded file lists
+	g.pages = 1
+	if g.config.MaxPageBytes > 0 && len(html) > g.config.MaxPageBytes {
+		if g.pages, err = g.writePaginated(ctx, data, g.config.MaxPageBytes); err != nil {
+			return err
+		}
+	} else {
+		// Write report HTML
+		reportPath := filepath.Join(g.config.OutputDir, PageName(1))
+		if err := os.WriteFile(reportPath, html, 0o600); err != nil {
+			return fmt.Errorf("writing report HTML: %w", err)
+		}
 	}
 
 	// Copy assets
@@ -124,6 +149,11 @@ func (g *Generator) Generate(ctx context.Context, coverage *parser.CoverageData)
 	return nil
 }
 
+// Pages returns the number of pages written by the last call to Generate
+func (g *Generator) Pages() int {
+	return g.pages
+}
+
 // buildReportData constructs the report data structure
 func (g *Generator) buildReportData(ctx context.Context, coverage *parser.CoverageData) *Data {
 	var packages []PackageReport
@@ -175,8 +205,9 @@ func (g *Generator) buildReportData(ctx context.Context, coverage *parser.Covera
 				fileURL := ""
 				if g.config != nil && g.config.RepositoryOwner != "" && g.config.RepositoryName != "" && g.config.BranchName != "" {
 					// Use the original full file path from coverage data
-					fileURL = urlutil.BuildGitHubFileURL(
-						g.config.RepositoryOwner, g.config.RepositoryName, g.config.BranchName, fileName,
+					fileURL = urlutil.BuildGitHubModuleFileURL(
+						g.config.RepositoryOwner, g.config.RepositoryName, g.config.BranchName, g.config.ModuleRoot,
+						parser.RewriteModulePath(fileName, g.config.ModuleRewrites),
 					)
 				}
 
@@ -310,7 +341,23 @@ func (g *Generator) buildReportData(ctx context.Context, coverage *parser.Covera
 		Config: map[string]any{
 			"BrandingEnabled": globalConfig.Analytics.BrandingEnabled,
 		},
+		ExclusionPresets: activeExclusionPresets(coverage),
+	}
+}
+
+// activeExclusionPresets resolves the preset names recorded on the coverage data
+func activeExclusionPresets(coverage *parser.CoverageData) []parser.ExclusionPreset {
+	if coverage == nil {
+		return nil
+	}
+
+	var presets []parser.ExclusionPreset
+	for _, name := range coverage.ExclusionPresets {
+		if preset, err := parser.LookupExclusionPreset(name); err == nil {
+			presets = append(presets, preset)
+		}
 	}
+	return presets
 }
 
 // getLatestGitTag gets the latest git tag in the repository
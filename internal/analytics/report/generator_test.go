@@ -2,6 +2,8 @@ package report
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -252,6 +254,85 @@ func (suite *GeneratorTestSuite) TestBuildReportDataEmptyCoverage() {
 	suite.Equal(0, data.Summary.FileCount)
 }
 
+// TestBuildReportDataExclusionPresets tests that active exclusion presets are shown in the report
+func (suite *GeneratorTestSuite) TestBuildReportDataExclusionPresets() {
+	ctx := context.Background()
+	generator := NewGenerator(suite.config)
+	coverageData := suite.createSampleCoverageData()
+	coverageData.ExclusionPresets = []string{"vendor", "mocks"}
+
+	data := generator.buildReportData(ctx, coverageData)
+	suite.Require().Len(data.ExclusionPresets, 2)
+	suite.Equal("vendor", data.ExclusionPresets[0].Name)
+	suite.Equal("mocks", data.ExclusionPresets[1].Name)
+
+	html, err := generator.renderer.RenderReport(ctx, data)
+	suite.Require().NoError(err)
+	suite.Contains(string(html), "Excluded Code")
+	suite.Contains(string(html), `title="mocks/, mock/, mock_*.go, *_mock.go, *_mocks.go"`)
+
+	coverageData.ExclusionPresets = nil
+	html, err = generator.renderer.RenderReport(ctx, generator.buildReportData(ctx, coverageData))
+	suite.Require().NoError(err)
+	suite.NotContains(string(html), "Excluded Code")
+}
+
+// TestGeneratePaginated tests that reports over the size budget are split into pages with lazily loaded files
+func (suite *GeneratorTestSuite) TestGeneratePaginated() {
+	ctx := context.Background()
+	coverageData := &parser.CoverageData{Packages: make(map[string]*parser.PackageCoverage)}
+	for i := range 200 {
+		name := fmt.Sprintf("pkg%03d", i)
+		files := make(map[string]*parser.FileCoverage)
+		for j := range 5 {
+			files[fmt.Sprintf("%s/file%02d.go", name, j)] = &parser.FileCoverage{
+				Statements: []parser.Statement{{StartLine: 1, EndLine: 3, Count: j % 2, NumStmt: 2}},
+			}
+		}
+		coverageData.Packages[name] = &parser.PackageCoverage{Name: name, Percentage: 50, TotalLines: 40, CoveredLines: 20, Files: files}
+	}
+
+	single := NewGenerator(suite.config)
+	full, err := single.renderer.RenderReport(ctx, single.buildReportData(ctx, coverageData))
+	suite.Require().NoError(err)
+
+	suite.config.MaxPageBytes = len(full) / 8
+	generator := NewGenerator(suite.config)
+	suite.Require().NoError(generator.Generate(ctx, coverageData))
+	suite.Require().Greater(generator.Pages(), 1)
+
+	for page := 1; page <= generator.Pages(); page++ {
+		html, readErr := os.ReadFile(filepath.Join(suite.tempDir, PageName(page))) //nolint:gosec // test output path
+		suite.Require().NoError(readErr)
+		suite.LessOrEqual(len(html), suite.config.MaxPageBytes, "page %d exceeds the budget", page)
+		suite.Contains(string(html), fmt.Sprintf("page %d of %d", page, generator.Pages()))
+		suite.NotContains(string(html), "file00.go", "files are loaded lazily")
+	}
+	first, err := os.ReadFile(filepath.Join(suite.tempDir, PageName(1))) //nolint:gosec // test output path
+	suite.Require().NoError(err)
+	suite.Contains(string(first), `data-src="coverage-files/0.json"`)
+	suite.Contains(string(first), `href="coverage-2.html" class="page-link" rel="next"`)
+	suite.NoFileExists(filepath.Join(suite.tempDir, PageName(generator.Pages()+1)))
+
+	chunk, err := os.ReadFile(filepath.Join(suite.tempDir, FilesDir, "0.json"))
+	suite.Require().NoError(err)
+	var files []FileReport
+	suite.Require().NoError(json.Unmarshal(chunk, &files))
+	suite.Require().Len(files, 5)
+	suite.Equal("file00.go", files[0].Name)
+
+	// Reports within the budget stay on a single page, replacing the pages of the earlier report
+	other := filepath.Join(suite.tempDir, "coverage-diff.html")
+	suite.Require().NoError(os.WriteFile(other, []byte("diff"), 0o600))
+	suite.config.MaxPageBytes = len(full) * 2
+	generator = NewGenerator(suite.config)
+	suite.Require().NoError(generator.Generate(ctx, coverageData))
+	suite.Equal(1, generator.Pages())
+	suite.NoFileExists(filepath.Join(suite.tempDir, PageName(2)))
+	suite.NoDirExists(filepath.Join(suite.tempDir, FilesDir))
+	suite.FileExists(other, "only report pages are removed")
+}
+
 // TestBuildReportDataNoGitHubInfo tests building report data without GitHub info
 func (suite *GeneratorTestSuite) TestBuildReportDataNoGitHubInfo() {
 	ctx := context.Background()
@@ -315,6 +396,31 @@ func (suite *GeneratorTestSuite) TestBuildReportDataFileReports() {
 	}
 }
 
+// TestBuildReportDataModuleRewrites tests that file links use the rewritten module paths
+func (suite *GeneratorTestSuite) TestBuildReportDataModuleRewrites() {
+	suite.config.RepositoryName = "fork-repo"
+	suite.config.ModuleRewrites = []parser.ModuleRewrite{{From: "go.example.com/lib", To: "github.com/test-owner/fork-repo"}}
+	coverageData := &parser.CoverageData{
+		Mode: "set",
+		Packages: map[string]*parser.PackageCoverage{
+			"pkg": {
+				Name: "pkg",
+				Files: map[string]*parser.FileCoverage{
+					"go.example.com/lib/pkg/a.go": {
+						Path:       "go.example.com/lib/pkg/a.go",
+						Statements: []parser.Statement{{StartLine: 1, EndLine: 2, NumStmt: 1, Count: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	data := NewGenerator(suite.config).buildReportData(context.Background(), coverageData)
+	suite.Require().Len(data.Packages, 1)
+	suite.Require().Len(data.Packages[0].Files, 1)
+	suite.Equal("https://github.com/test-owner/fork-repo/blob/master/pkg/a.go", data.Packages[0].Files[0].URL)
+}
+
 // TestGenerateReportContent tests that the generated report contains expected content
 func (suite *GeneratorTestSuite) TestGenerateReportContent() {
 	ctx := context.Background()
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FilesDir is the directory below the output directory holding the lazily loaded file lists of a
+// paginated report, one JSON chunk per package
+const FilesDir = "coverage-files"
+
+// Pagination describes where a page sits in a report split across several pages
+type Pagination struct {
+	Page  int
+	Pages int
+	Prev  string
+	Next  string
+	Links []PageLink
+}
+
+// PageLink is a link to one page of a paginated report
+type PageLink struct {
+	Number  int
+	URL     string
+	Current bool
+}
+
+// PageName returns the file name of a report page; the first page keeps the report's own name
+func PageName(page int) string {
+	if page <= 1 {
+		return "coverage.html"
+	}
+	return "coverage-" + strconv.Itoa(page) + ".html"
+}
+
+// RemovePages deletes the extra pages and file chunks a paginated report left in dir, so a
+// report with fewer pages does not leave earlier pages behind. The first page is kept.
+func RemovePages(dir string) error {
+	if err := os.RemoveAll(filepath.Join(dir, FilesDir)); err != nil {
+		return fmt.Errorf("removing report file chunks: %w", err)
+	}
+
+	pages, err := filepath.Glob(filepath.Join(dir, "coverage-*.html"))
+	if err != nil {
+		return fmt.Errorf("listing report pages: %w", err)
+	}
+	for _, path := range pages {
+		name := filepath.Base(path)
+		page, convErr := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "coverage-"), ".html"))
+		if convErr != nil || page < 2 || PageName(page) != name {
+			continue
+		}
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing report page: %w", err)
+		}
+	}
+	return nil
+}
+
+// writePaginated splits the report into pages that fit within maxBytes. Package file lists are
+// moved into JSON chunks under FilesDir and fetched when a package is expanded. A single page
+// can still exceed the budget when one package alone is larger than it.
+func (g *Generator) writePaginated(ctx context.Context, data *Data, maxBytes int) (int, error) {
+	chunkDir := filepath.Join(g.config.OutputDir, FilesDir)
+	if err := os.MkdirAll(chunkDir, 0o750); err != nil {
+		return 0, fmt.Errorf("creating file chunk directory: %w", err)
+	}
+
+	packages := make([]PackageReport, len(data.Packages))
+	for i, pkg := range data.Packages {
+		chunk, err := json.Marshal(pkg.Files)
+		if err != nil {
+			return 0, fmt.Errorf("encoding files of package %s: %w", pkg.Name, err)
+		}
+		name := strconv.Itoa(i) + ".json"
+		if err = os.WriteFile(filepath.Join(chunkDir, name), chunk, 0o600); err != nil {
+			return 0, fmt.Errorf("writing files of package %s: %w", pkg.Name, err)
+		}
+
+		pkg.FileCount = len(pkg.Files)
+		pkg.FilesURL = FilesDir + "/" + name
+		pkg.Files = nil
+		packages[i] = pkg
+	}
+
+	perPage, err := g.packagesPerPage(ctx, data, packages, maxBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	pages := (len(packages) + perPage - 1) / perPage
+	for page := 1; page <= pages; page++ {
+		pageData := *data
+		pageData.Packages = packages[(page-1)*perPage : min(page*perPage, len(packages))]
+		pageData.Pagination = newPagination(page, pages)
+
+		html, renderErr := g.renderer.RenderReport(ctx, &pageData)
+		if renderErr != nil {
+			return 0, fmt.Errorf("rendering report page %d: %w", page, renderErr)
+		}
+		if err = os.WriteFile(filepath.Join(g.config.OutputDir, PageName(page)), html, 0o600); err != nil {
+			return 0, fmt.Errorf("writing report page %d: %w", page, err)
+		}
+	}
+	return pages, nil
+}
+
+// packagesPerPage estimates how many lazily loaded packages fit in maxBytes from the size of
+// the page without packages and the average size each package adds to it
+func (g *Generator) packagesPerPage(ctx context.Context, data *Data, packages []PackageReport, maxBytes int) (int, error) {
+	if len(packages) == 0 {
+		return 1, nil
+	}
+
+	measure := func(pkgs []PackageReport) (int, error) {
+		pageData := *data
+		pageData.Packages = pkgs
+		pageData.Pagination = newPagination(1, 2)
+		html, err := g.renderer.RenderReport(ctx, &pageData)
+		if err != nil {
+			return 0, fmt.Errorf("rendering report: %w", err)
+		}
+		return len(html), nil
+	}
+
+	base, err := measure(nil)
+	if err != nil {
+		return 0, err
+	}
+	full, err := measure(packages)
+	if err != nil {
+		return 0, err
+	}
+
+	perPackage := max(1, (full-base)/len(packages))
+	return max(1, (maxBytes-base)/perPackage), nil
+}
+
+// newPagination builds the navigation of page out of pages
+func newPagination(page, pages int) *Pagination {
+	pagination := &Pagination{Page: page, Pages: pages, Links: make([]PageLink, 0, pages)}
+	if page > 1 {
+		pagination.Prev = PageName(page - 1)
+	}
+	if page < pages {
+		pagination.Next = PageName(page + 1)
+	}
+	for number := 1; number <= pages; number++ {
+		pagination.Links = append(pagination.Links, PageLink{Number: number, URL: PageName(number), Current: number == page})
+	}
+	return pagination
+}
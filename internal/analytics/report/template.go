@@ -166,10 +166,24 @@ func getReportTemplate() string {
             </div>
         </section>
 
+        <!-- Exclusion Presets Section -->
+        {{- if .ExclusionPresets}}
+        <section class="exclusions-section">
+            <h2>Excluded Code</h2>
+            <ul class="exclusion-presets">
+                {{- range .ExclusionPresets}}
+                <li class="exclusion-preset" title="{{range $i, $pattern := .Patterns}}{{if $i}}, {{end}}{{$pattern}}{{end}}">
+                    <strong>{{.Name}}</strong> — {{.Description}}
+                </li>
+                {{- end}}
+            </ul>
+        </section>
+        {{- end}}
+
         <!-- Packages Section -->
         {{- if .Packages}}
         <section class="packages-section">
-            <h2>Package Coverage</h2>
+            <h2>Package Coverage{{with .Pagination}} <span class="page-indicator">page {{.Page}} of {{.Pages}}</span>{{end}}</h2>
             <div class="packages-container">
                 {{- range .Packages}}
                 <div class="package-card" data-package="{{.Name}}">
@@ -177,7 +191,7 @@ func getReportTemplate() string {
                         <div class="package-info">
                             <span class="package-toggle">▶</span>
                             <span class="package-name">{{.Name}}</span>
-                            <span class="package-stats">{{.CoveredLines}} / {{.TotalLines}} lines</span>
+                            <span class="package-stats">{{.CoveredLines}} / {{.TotalLines}} lines{{if .FilesURL}} • {{.FileCount}} files{{end}}</span>
                         </div>
                         <div class="package-coverage">
                             <span class="coverage-percentage {{- if ge .Percentage 95.0}} excellent{{else if ge .Percentage 85.0}} success{{else if ge .Percentage 75.0}} warning{{else if ge .Percentage 65.0}} low{{else}} danger{{end -}}">
@@ -215,10 +229,31 @@ func getReportTemplate() string {
                         </div>
                         {{- end}}
                     </div>
+                    {{- else if .FilesURL}}
+                    <div class="package-files" id="package-{{.Name}}" data-src="{{.FilesURL}}" style="display: none;">
+                        <div class="file-loading">Loading files…</div>
+                    </div>
                     {{- end}}
                 </div>
                 {{- end}}
             </div>
+            {{- with .Pagination}}
+            <nav class="pagination" aria-label="Report pages">
+                {{- if .Prev}}
+                <a href="{{.Prev}}" class="page-link" rel="prev">← Previous</a>
+                {{- end}}
+                {{- range .Links}}
+                {{- if .Current}}
+                <span class="page-link current" aria-current="page">{{.Number}}</span>
+                {{- else}}
+                <a href="{{.URL}}" class="page-link">{{.Number}}</a>
+                {{- end}}
+                {{- end}}
+                {{- if .Next}}
+                <a href="{{.Next}}" class="page-link" rel="next">Next →</a>
+                {{- end}}
+            </nav>
+            {{- end}}
         </section>
         {{- end}}
     </main>
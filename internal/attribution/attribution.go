@@ -0,0 +1,294 @@
+// Package attribution runs tests one at a time, each with its own coverage directory, so every
+// covered block can be traced back to the tests that executed it
+package attribution
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+var (
+	// ErrNoTests indicates go test -json output that names no tests
+	ErrNoTests = errors.New("no tests found in go test -json output")
+	// ErrRunTest indicates a test that could not be built or run
+	ErrRunTest = errors.New("failed to run test")
+	// ErrInvalidIndex indicates an attribution index that cannot be read
+	ErrInvalidIndex = errors.New("invalid test attribution index")
+)
+
+// IndexFile is the name of the attribution index in the output directory, where the report
+// server looks it up
+const IndexFile = "test-attribution.json"
+
+// Test is a top-level test of a package
+type Test struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+}
+
+// String returns the qualified name of the test, e.g. github.com/example/app/api.TestServe
+func (t Test) String() string {
+	return t.Package + "." + t.Name
+}
+
+// testEvent is a line of go test -json output (see go doc test2json)
+type testEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+}
+
+// ListTests reads the top-level tests of go test -json output, such as the jsonfile written by
+// gotestsum, in the order they first ran. Subtests run as part of their parent, and lines that
+// are not events are ignored.
+func ListTests(r io.Reader) ([]Test, error) {
+	var tests []Test
+	seen := make(map[Test]bool)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var event testEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		if event.Action != "run" || event.Test == "" || strings.Contains(event.Test, "/") {
+			continue
+		}
+		test := Test{Package: event.Package, Name: event.Test}
+		if !seen[test] {
+			seen[test] = true
+			tests = append(tests, test)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read test events: %w", err)
+	}
+	if len(tests) == 0 {
+		return nil, ErrNoTests
+	}
+	return tests, nil
+}
+
+// Block is a range of lines of a file and the tests that executed it, as indexes into the
+// tests of the Index
+type Block struct {
+	StartLine int   `json:"start_line"`
+	EndLine   int   `json:"end_line"`
+	Tests     []int `json:"tests"`
+}
+
+// Index maps the covered blocks of each file to the tests that executed them
+type Index struct {
+	Tests []Test `json:"tests"`
+	// Files are keyed by the paths the coverage reports use, their blocks sorted by line
+	Files map[string][]Block `json:"files"`
+	// Failed are the tests whose run failed; the coverage they produced is still attributed
+	Failed []Test `json:"failed,omitempty"`
+}
+
+// NewIndex returns an empty index of tests
+func NewIndex(tests []Test) *Index {
+	return &Index{Tests: tests, Files: make(map[string][]Block)}
+}
+
+// Add attributes the statements coverage executed to the test at index test
+func (ix *Index) Add(test int, coverage *parser.CoverageData) {
+	for _, pkg := range coverage.Packages {
+		for _, file := range pkg.Files {
+			for _, stmt := range file.Statements {
+				if stmt.Count > 0 {
+					ix.addBlock(file.Path, stmt.StartLine, stmt.EndLine, test)
+				}
+			}
+		}
+	}
+}
+
+// addBlock attributes a block to a test, merging it with the same block of other tests
+func (ix *Index) addBlock(file string, startLine, endLine, test int) {
+	blocks := ix.Files[file]
+	i, found := slices.BinarySearchFunc(blocks, Block{StartLine: startLine, EndLine: endLine}, compareBlocks)
+	if !found {
+		blocks = slices.Insert(blocks, i, Block{StartLine: startLine, EndLine: endLine})
+	}
+	if !slices.Contains(blocks[i].Tests, test) {
+		blocks[i].Tests = append(blocks[i].Tests, test)
+	}
+	ix.Files[file] = blocks
+}
+
+// compareBlocks orders blocks by their first line, then by their last
+func compareBlocks(a, b Block) int {
+	return cmp.Or(cmp.Compare(a.StartLine, b.StartLine), cmp.Compare(a.EndLine, b.EndLine))
+}
+
+// Lookup returns the tests that executed a line of a file, sorted by name. The file may be
+// given as its path in the reports or as any trailing part of it, e.g. api/server.go.
+func (ix *Index) Lookup(file string, line int) []Test {
+	file = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+	seen := make(map[int]bool)
+	for path, blocks := range ix.Files {
+		if path != file && !strings.HasSuffix(path, "/"+file) {
+			continue
+		}
+		for _, block := range blocks {
+			if block.StartLine > line {
+				break
+			}
+			if line > block.EndLine {
+				continue
+			}
+			for _, test := range block.Tests {
+				seen[test] = true
+			}
+		}
+	}
+
+	tests := make([]Test, 0, len(seen))
+	for test := range seen {
+		if test >= 0 && test < len(ix.Tests) {
+			tests = append(tests, ix.Tests[test])
+		}
+	}
+	slices.SortFunc(tests, func(a, b Test) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return tests
+}
+
+// Load reads an attribution index
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test attribution: %w", err)
+	}
+	var ix Index
+	if err = json.Unmarshal(data, &ix); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	if ix.Files == nil {
+		ix.Files = make(map[string][]Block)
+	}
+	return &ix, nil
+}
+
+// Save writes the index to path
+func (ix *Index) Save(path string) error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return fmt.Errorf("failed to marshal test attribution: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write test attribution: %w", err)
+	}
+	return nil
+}
+
+// Options configure the runs of the tests
+type Options struct {
+	// Dir is the directory of the module the tests run in
+	Dir string
+	// CoverPkg are the packages whose coverage is attributed, as given to -coverpkg
+	// (default ./...)
+	CoverPkg string
+	// Parser reads the coverage of each run, applying the exclusions of the reports
+	Parser *parser.Parser
+	// Progress is called before each test runs
+	Progress func(done, total int, test Test)
+}
+
+// Run runs the tests one at a time with go test -run and a coverage directory of their own,
+// and attributes the statements each run executed to its test. A failing test still counts;
+// a test that cannot be built or run stops the analysis.
+func Run(ctx context.Context, tests []Test, opts Options) (*Index, error) {
+	if opts.CoverPkg == "" {
+		opts.CoverPkg = "./..."
+	}
+	if opts.Parser == nil {
+		opts.Parser = parser.New()
+	}
+
+	ix := NewIndex(tests)
+	for i, test := range tests {
+		if opts.Progress != nil {
+			opts.Progress(i, len(tests), test)
+		}
+		coverage, failed, err := runTest(ctx, test, opts)
+		if err != nil {
+			return nil, err
+		}
+		if failed {
+			ix.Failed = append(ix.Failed, test)
+		}
+		if coverage != nil {
+			ix.Add(i, coverage)
+		}
+	}
+	return ix, nil
+}
+
+// runTest runs a single test and parses the coverage it wrote to its coverage directory, nil
+// when it wrote none
+func runTest(ctx context.Context, test Test, opts Options) (*parser.CoverageData, bool, error) {
+	coverDir, err := os.MkdirTemp("", "go-coverage-attribution-")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create coverage directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(coverDir) }()
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-cover", "-coverpkg="+opts.CoverPkg, //nolint:gosec // the test names are passed to go test without a shell
+		"-run", "^"+regexp.QuoteMeta(test.Name)+"$", test.Package, "-args", "-test.gocoverdir="+coverDir)
+	cmd.Dir = opts.Dir
+	output, err := cmd.CombinedOutput()
+	failed := false
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return nil, false, fmt.Errorf("%w %s: %w", ErrRunTest, test, err)
+		}
+		failed = true
+	}
+
+	entries, err := os.ReadDir(coverDir)
+	if err != nil || len(entries) == 0 {
+		if failed {
+			// Without coverage data the test never ran, e.g. because its package failed to build
+			return nil, false, fmt.Errorf("%w %s: %s", ErrRunTest, test, strings.TrimSpace(string(output)))
+		}
+		return nil, false, nil
+	}
+
+	profile := filepath.Join(coverDir, "profile.txt")
+	if output, err = exec.CommandContext(ctx, "go", "tool", "covdata", "textfmt", "-i="+coverDir, "-o="+profile).CombinedOutput(); err != nil { //nolint:gosec // the directory is created above
+		return nil, false, fmt.Errorf("failed to convert coverage of %s: %w: %s", test, err, strings.TrimSpace(string(output)))
+	}
+	if info, statErr := os.Stat(profile); statErr != nil || info.Size() == 0 {
+		return nil, failed, nil
+	}
+	coverage, err := opts.Parser.ParseFile(ctx, profile)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse coverage of %s: %w", test, err)
+	}
+	return coverage, failed, nil
+}
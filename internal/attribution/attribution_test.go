@@ -0,0 +1,138 @@
+package attribution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+const goTestJSON = `# github.com/example/app/internal/db
+{"Action":"start","Package":"github.com/example/app/internal/api"}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestServe"}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestServe/get"}
+{"Action":"pass","Package":"github.com/example/app/internal/api","Test":"TestServe","Elapsed":0.5}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestRetry"}
+{"Action":"fail","Package":"github.com/example/app/internal/api","Test":"TestRetry","Elapsed":1.2}
+{"Action":"run","Package":"github.com/example/app/internal/db","Test":"TestServe"}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestServe"}
+`
+
+func TestListTests(t *testing.T) {
+	tests, err := ListTests(strings.NewReader(goTestJSON))
+	require.NoError(t, err)
+	assert.Equal(t, []Test{
+		{Package: "github.com/example/app/internal/api", Name: "TestServe"},
+		{Package: "github.com/example/app/internal/api", Name: "TestRetry"},
+		{Package: "github.com/example/app/internal/db", Name: "TestServe"},
+	}, tests)
+	assert.Equal(t, "github.com/example/app/internal/api.TestServe", tests[0].String())
+
+	_, err = ListTests(strings.NewReader("ok  \tgithub.com/example/app\t0.01s\n"))
+	require.ErrorIs(t, err, ErrNoTests)
+}
+
+func coverage(path string, statements ...parser.Statement) *parser.CoverageData {
+	return &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		filepath.Dir(path): {Files: map[string]*parser.FileCoverage{
+			path: {Path: path, Statements: statements},
+		}},
+	}}
+}
+
+func TestIndex(t *testing.T) {
+	ix := NewIndex([]Test{{Package: "app/api", Name: "TestServe"}, {Package: "app/api", Name: "TestRetry"}})
+	ix.Add(0, coverage("internal/api/server.go",
+		parser.Statement{StartLine: 10, EndLine: 12, Count: 1},
+		parser.Statement{StartLine: 20, EndLine: 22, Count: 0},
+	))
+	ix.Add(1, coverage("internal/api/server.go",
+		parser.Statement{StartLine: 10, EndLine: 12, Count: 3},
+		parser.Statement{StartLine: 30, EndLine: 30, Count: 1},
+	))
+
+	assert.Equal(t, []Block{
+		{StartLine: 10, EndLine: 12, Tests: []int{0, 1}},
+		{StartLine: 30, EndLine: 30, Tests: []int{1}},
+	}, ix.Files["internal/api/server.go"])
+
+	assert.Equal(t, []Test{{Package: "app/api", Name: "TestRetry"}, {Package: "app/api", Name: "TestServe"}}, ix.Lookup("internal/api/server.go", 11))
+	assert.Equal(t, []Test{{Package: "app/api", Name: "TestRetry"}}, ix.Lookup("./api/server.go", 30))
+	assert.Empty(t, ix.Lookup("internal/api/server.go", 21), "uncovered lines have no tests")
+	assert.Empty(t, ix.Lookup("pi/server.go", 11), "only whole path elements match")
+
+	path := filepath.Join(t.TempDir(), "site", IndexFile)
+	require.NoError(t, ix.Save(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, ix, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = Load(path)
+	require.ErrorIs(t, err, ErrInvalidIndex)
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/calc\n\ngo 1.22\n",
+		"calc.go": `package calc
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func Abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+`,
+		"calc_test.go": `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fatal("Add")
+	}
+}
+
+func TestAbs(t *testing.T) {
+	if Abs(-1) != 2 {
+		t.Fatal("Abs is wrong on purpose")
+	}
+}
+`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	tests := []Test{{Package: "example.com/calc", Name: "TestAdd"}, {Package: "example.com/calc", Name: "TestAbs"}}
+	var progress []string
+	ix, err := Run(context.Background(), tests, Options{
+		Dir:      dir,
+		Progress: func(_, _ int, test Test) { progress = append(progress, test.Name) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestAdd", "TestAbs"}, progress)
+	assert.Equal(t, []Test{tests[1]}, ix.Failed, "failing tests still count")
+
+	assert.Equal(t, []Test{tests[0]}, ix.Lookup("calc.go", 4))
+	assert.Equal(t, []Test{tests[1]}, ix.Lookup("calc.go", 9))
+	assert.Empty(t, ix.Lookup("calc.go", 11), "no test reaches the positive branch")
+
+	_, err = Run(context.Background(), []Test{{Package: "example.com/missing", Name: "TestNothing"}}, Options{Dir: dir})
+	require.ErrorIs(t, err, ErrRunTest)
+}
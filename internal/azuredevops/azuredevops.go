@@ -0,0 +1,276 @@
+// Package azuredevops provides Azure DevOps Services API integration for coverage reporting
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// Static error definitions
+var (
+	ErrMissingCredentials  = errors.New("azure devops credentials are required: map SYSTEM_ACCESSTOKEN or set a personal access token")
+	ErrMissingProject      = errors.New("azure devops collection URI and project are required")
+	ErrAzureDevOpsAPIError = errors.New("azure devops API error")
+	ErrThreadNotFound      = errors.New("coverage thread not found")
+	errRetryableStatus     = errors.New("retryable azure devops API status")
+)
+
+// APIVersion is the REST API version of the git endpoints
+const APIVersion = "7.1"
+
+// codeCoverageAPIVersion is the REST API version of the code coverage summary endpoint
+const codeCoverageAPIVersion = "7.1-preview.1"
+
+// CommentMarker identifies the coverage thread of a pull request so it is updated in place
+const CommentMarker = "[//]: # (go-coverage-v1)"
+
+// Git status states
+const (
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+	StatePending   = "pending"
+)
+
+// Pull request thread statuses
+const (
+	ThreadActive = "active"
+	ThreadFixed  = "fixed"
+)
+
+// CoverageStatistics is one row of the build code coverage summary
+type CoverageStatistics struct {
+	Label            string `json:"label"`
+	Position         int    `json:"position"`
+	Total            int    `json:"total"`
+	Covered          int    `json:"covered"`
+	IsDeltaAvailable bool   `json:"isDeltaAvailable"`
+	Delta            int    `json:"delta"`
+}
+
+// GitStatus is a commit or pull request status
+type GitStatus struct {
+	State       string        `json:"state"`               // succeeded, failed, pending or error
+	Description string        `json:"description"`         // Short description
+	TargetURL   string        `json:"targetUrl,omitempty"` // Link to details
+	Context     StatusContext `json:"context"`             // Identifies the status so later runs replace it
+}
+
+// StatusContext identifies a status
+type StatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+// Thread is a pull request comment thread
+type Thread struct {
+	ID        int       `json:"id"`
+	Status    string    `json:"status"`
+	Comments  []Comment `json:"comments"`
+	IsDeleted bool      `json:"isDeleted"`
+}
+
+// Comment is a comment of a pull request thread
+type Comment struct {
+	ID        int    `json:"id"`
+	Content   string `json:"content"`
+	IsDeleted bool   `json:"isDeleted"`
+}
+
+// Config holds Azure DevOps client configuration
+type Config struct {
+	CollectionURI string // Organization URL, e.g. https://dev.azure.com/org/
+	Project       string // Team project
+	AccessToken   string // Pipeline job access token (SYSTEM_ACCESSTOKEN, sent as Bearer)
+	Token         string // Personal access token (used when no access token is set)
+	UserAgent     string // User agent string
+
+	// RetryPolicy controls retries of transient API failures (nil disables retries)
+	RetryPolicy *retry.Policy
+	// HTTPClient overrides the default client, e.g. for proxies or custom CAs
+	HTTPClient *http.Client
+}
+
+// Client handles Azure DevOps API operations for coverage reporting
+type Client struct {
+	config     *Config
+	projectURL string
+	httpClient *http.Client
+}
+
+// New creates an Azure DevOps client
+func New(config *Config) (*Client, error) {
+	if config.AccessToken == "" && config.Token == "" {
+		return nil, ErrMissingCredentials
+	}
+	if config.CollectionURI == "" || config.Project == "" {
+		return nil, ErrMissingProject
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	projectURL := strings.TrimRight(config.CollectionURI, "/") + "/" + url.PathEscape(config.Project)
+	return &Client{config: config, projectURL: projectURL, httpClient: httpClient}, nil
+}
+
+// PublishCodeCoverage publishes the code coverage summary of a build, shown on the
+// Code Coverage tab of the pipeline run
+func (c *Client) PublishCodeCoverage(ctx context.Context, buildID int, stats []CoverageStatistics) error {
+	endpoint := fmt.Sprintf("%s/_apis/test/codecoverage?buildId=%d&api-version=%s", c.projectURL, buildID, codeCoverageAPIVersion)
+	input := map[string]any{"coverageStats": stats}
+	if err := c.request(ctx, http.MethodPost, endpoint, input, nil); err != nil {
+		return fmt.Errorf("failed to publish code coverage: %w", err)
+	}
+	return nil
+}
+
+// CreateCommitStatus adds a status to a commit
+func (c *Client) CreateCommitStatus(ctx context.Context, repositoryID, commit string, status *GitStatus) error {
+	endpoint := fmt.Sprintf("%s/commits/%s/statuses?api-version=%s", c.repositoryURL(repositoryID), url.PathEscape(commit), APIVersion)
+	if err := c.request(ctx, http.MethodPost, endpoint, status, nil); err != nil {
+		return fmt.Errorf("failed to create commit status: %w", err)
+	}
+	return nil
+}
+
+// CreatePullRequestStatus adds a status to a pull request; branch policies can require it
+func (c *Client) CreatePullRequestStatus(ctx context.Context, repositoryID string, pr int, status *GitStatus) error {
+	endpoint := fmt.Sprintf("%s/pullRequests/%d/statuses?api-version=%s", c.repositoryURL(repositoryID), pr, APIVersion)
+	if err := c.request(ctx, http.MethodPost, endpoint, status, nil); err != nil {
+		return fmt.Errorf("failed to create pull request status: %w", err)
+	}
+	return nil
+}
+
+// UpsertPullRequestThread updates the coverage thread of a pull request, or creates it when
+// there is none yet, sets its status and reports whether it was created
+func (c *Client) UpsertPullRequestThread(ctx context.Context, repositoryID string, pr int, content, status string) (*Thread, bool, error) {
+	threadsURL := fmt.Sprintf("%s/pullRequests/%d/threads", c.repositoryURL(repositoryID), pr)
+	existing, err := c.findCoverageThread(ctx, threadsURL)
+	if err != nil && !errors.Is(err, ErrThreadNotFound) {
+		return nil, false, err
+	}
+
+	if existing != nil {
+		commentURL := fmt.Sprintf("%s/%d/comments/%d?api-version=%s", threadsURL, existing.ID, existing.Comments[0].ID, APIVersion)
+		if err = c.request(ctx, http.MethodPatch, commentURL, map[string]string{"content": content}, nil); err != nil {
+			return nil, false, fmt.Errorf("failed to update thread comment: %w", err)
+		}
+		if existing.Status != status {
+			threadURL := fmt.Sprintf("%s/%d?api-version=%s", threadsURL, existing.ID, APIVersion)
+			if err = c.request(ctx, http.MethodPatch, threadURL, map[string]string{"status": status}, nil); err != nil {
+				return nil, false, fmt.Errorf("failed to update thread status: %w", err)
+			}
+			existing.Status = status
+		}
+		existing.Comments[0].Content = content
+		return existing, false, nil
+	}
+
+	input := map[string]any{
+		"comments": []map[string]any{{"parentCommentId": 0, "content": content, "commentType": "text"}},
+		"status":   status,
+	}
+	var thread Thread
+	if err = c.request(ctx, http.MethodPost, fmt.Sprintf("%s?api-version=%s", threadsURL, APIVersion), input, &thread); err != nil {
+		return nil, false, fmt.Errorf("failed to create thread: %w", err)
+	}
+	return &thread, true, nil
+}
+
+// findCoverageThread returns the thread whose first comment carries CommentMarker
+func (c *Client) findCoverageThread(ctx context.Context, threadsURL string) (*Thread, error) {
+	var threads struct {
+		Value []Thread `json:"value"`
+	}
+	if err := c.request(ctx, http.MethodGet, fmt.Sprintf("%s?api-version=%s", threadsURL, APIVersion), nil, &threads); err != nil {
+		return nil, fmt.Errorf("failed to list threads: %w", err)
+	}
+	for i := range threads.Value {
+		thread := &threads.Value[i]
+		if thread.IsDeleted || len(thread.Comments) == 0 || thread.Comments[0].IsDeleted {
+			continue
+		}
+		if strings.Contains(thread.Comments[0].Content, CommentMarker) {
+			return thread, nil
+		}
+	}
+	return nil, ErrThreadNotFound
+}
+
+// repositoryURL returns the API URL of a git repository
+func (c *Client) repositoryURL(repositoryID string) string {
+	return fmt.Sprintf("%s/_apis/git/repositories/%s", c.projectURL, url.PathEscape(repositoryID))
+}
+
+// request sends an API request with the JSON encoded input and decodes the response into
+// output, retrying rate limits and server errors according to the retry policy
+func (c *Client) request(ctx context.Context, method, endpoint string, input, output any) error {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	send := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
+		if c.config.AccessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+		} else {
+			// Personal access tokens are sent as the password of an empty user
+			req.SetBasicAuth("", c.config.Token)
+		}
+		req.Header.Set("Accept", "application/json")
+		if input != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.config.UserAgent != "" {
+			req.Header.Set("User-Agent", c.config.UserAgent)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			statusErr := fmt.Errorf("%w: %d %s", ErrAzureDevOpsAPIError, resp.StatusCode, strings.TrimSpace(string(message)))
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("%w: %w", errRetryableStatus, statusErr)
+			}
+			return retry.Permanent(statusErr)
+		}
+		if output == nil {
+			return nil
+		}
+		if err = json.NewDecoder(resp.Body).Decode(output); err != nil {
+			return retry.Permanent(fmt.Errorf("failed to decode response: %w", err))
+		}
+		return nil
+	}
+
+	if c.config.RetryPolicy == nil {
+		return send(ctx)
+	}
+	return retry.Do(ctx, *c.config.RetryPolicy, send)
+}
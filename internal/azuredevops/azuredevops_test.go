@@ -0,0 +1,163 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// request is a request received by the test server
+type request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// newTestClient returns a client of a test server that records requests and answers with the
+// response for the request method, or {} when there is none
+func newTestClient(t *testing.T, responses map[string]string) (*Client, *[]request) {
+	t.Helper()
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received := request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		_ = json.Unmarshal(body, &received.Body)
+		requests = append(requests, received)
+		if response, ok := responses[r.Method]; ok {
+			_, _ = w.Write([]byte(response))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(&Config{CollectionURI: server.URL + "/org/", Project: "Team Project", AccessToken: "job-token"})
+	require.NoError(t, err)
+	return client, &requests
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{CollectionURI: "https://dev.azure.com/org/", Project: "p"})
+	require.ErrorIs(t, err, ErrMissingCredentials)
+
+	_, err = New(&Config{Token: "pat"})
+	require.ErrorIs(t, err, ErrMissingProject)
+
+	client, err := New(&Config{CollectionURI: "https://dev.azure.com/org/", Project: "Team Project", Token: "pat"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://dev.azure.com/org/Team%20Project", client.projectURL)
+}
+
+func TestAuthentication(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	for _, config := range []*Config{
+		{CollectionURI: server.URL, Project: "p", AccessToken: "job-token"},
+		{CollectionURI: server.URL, Project: "p", Token: "pat"},
+	} {
+		client, err := New(config)
+		require.NoError(t, err)
+		require.NoError(t, client.CreateCommitStatus(context.Background(), "repo", "abc", &GitStatus{}))
+	}
+	assert.Equal(t, []string{"Bearer job-token", "Basic OnBhdA=="}, auth)
+}
+
+func TestPublishCodeCoverage(t *testing.T) {
+	client, requests := newTestClient(t, nil)
+
+	err := client.PublishCodeCoverage(context.Background(), 42, []CoverageStatistics{{Label: "Statements", Position: 1, Total: 10, Covered: 8}})
+	require.NoError(t, err)
+	require.Len(t, *requests, 1)
+	received := (*requests)[0]
+	assert.Equal(t, "/org/Team Project/_apis/test/codecoverage", received.Path)
+	assert.Equal(t, "buildId=42&api-version=7.1-preview.1", received.Query)
+	stats := received.Body["coverageStats"].([]any)[0].(map[string]any)
+	assert.InDelta(t, 8, stats["covered"], 0)
+}
+
+func TestCreateStatuses(t *testing.T) {
+	client, requests := newTestClient(t, nil)
+	status := &GitStatus{State: StateFailed, Description: "70.00% coverage", Context: StatusContext{Name: "coverage", Genre: "go-coverage"}}
+
+	require.NoError(t, client.CreatePullRequestStatus(context.Background(), "repo-id", 7, status))
+	require.NoError(t, client.CreateCommitStatus(context.Background(), "repo-id", "abc123", status))
+
+	assert.Equal(t, "/org/Team Project/_apis/git/repositories/repo-id/pullRequests/7/statuses", (*requests)[0].Path)
+	assert.Equal(t, "/org/Team Project/_apis/git/repositories/repo-id/commits/abc123/statuses", (*requests)[1].Path)
+	assert.Equal(t, StateFailed, (*requests)[0].Body["state"])
+	assert.Equal(t, map[string]any{"name": "coverage", "genre": "go-coverage"}, (*requests)[0].Body["context"])
+}
+
+func TestCreateStatusAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := New(&Config{CollectionURI: server.URL, Project: "p", Token: "pat"})
+	require.NoError(t, err)
+
+	err = client.CreateCommitStatus(context.Background(), "repo", "abc", &GitStatus{})
+	require.ErrorIs(t, err, ErrAzureDevOpsAPIError)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestUpsertPullRequestThread(t *testing.T) {
+	t.Run("creates the thread", func(t *testing.T) {
+		client, requests := newTestClient(t, map[string]string{
+			http.MethodGet:  `{"value":[{"id":1,"comments":[{"id":1,"content":"Looks good"}]}]}`,
+			http.MethodPost: `{"id":2,"status":"active"}`,
+		})
+
+		thread, created, err := client.UpsertPullRequestThread(context.Background(), "repo-id", 7, CommentMarker+"\nbody", ThreadActive)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, 2, thread.ID)
+		require.Len(t, *requests, 2)
+		assert.Equal(t, http.MethodPost, (*requests)[1].Method)
+		assert.Equal(t, "/org/Team Project/_apis/git/repositories/repo-id/pullRequests/7/threads", (*requests)[1].Path)
+		assert.Equal(t, ThreadActive, (*requests)[1].Body["status"])
+	})
+
+	t.Run("updates the thread and resolves it", func(t *testing.T) {
+		client, requests := newTestClient(t, map[string]string{
+			http.MethodGet: `{"value":[
+				{"id":3,"isDeleted":true,"comments":[{"id":1,"content":"` + CommentMarker + `"}]},
+				{"id":4,"status":"active","comments":[{"id":1,"content":"` + CommentMarker + `\nold"},{"id":2,"content":"reply"}]}
+			]}`,
+		})
+
+		thread, created, err := client.UpsertPullRequestThread(context.Background(), "repo-id", 7, CommentMarker+"\nnew", ThreadFixed)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, 4, thread.ID)
+		assert.Equal(t, ThreadFixed, thread.Status)
+		require.Len(t, *requests, 3)
+		assert.Equal(t, "/org/Team Project/_apis/git/repositories/repo-id/pullRequests/7/threads/4/comments/1", (*requests)[1].Path)
+		assert.Equal(t, CommentMarker+"\nnew", (*requests)[1].Body["content"])
+		assert.Equal(t, "/org/Team Project/_apis/git/repositories/repo-id/pullRequests/7/threads/4", (*requests)[2].Path)
+		assert.Equal(t, ThreadFixed, (*requests)[2].Body["status"])
+	})
+
+	t.Run("keeps the thread status", func(t *testing.T) {
+		client, requests := newTestClient(t, map[string]string{
+			http.MethodGet: `{"value":[{"id":4,"status":"active","comments":[{"id":1,"content":"` + CommentMarker + `"}]}]}`,
+		})
+
+		_, _, err := client.UpsertPullRequestThread(context.Background(), "repo-id", 7, "body", ThreadActive)
+		require.NoError(t, err)
+		assert.Len(t, *requests, 2)
+	})
+}
@@ -4,6 +4,7 @@ package badge
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/retry"
 )
 
 // ErrIconFetchFailed is returned when fetching an icon from Simple Icons CDN fails
@@ -142,6 +144,40 @@ func (g *Generator) Generate(ctx context.Context, percentage float64, options ..
 	return g.renderSVG(ctx, badgeData)
 }
 
+// Endpoint is the JSON read by the shields.io endpoint badge
+// (https://shields.io/badges/endpoint-badge)
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	Style         string `json:"style,omitempty"`
+}
+
+// GenerateEndpoint creates the shields.io endpoint JSON of a coverage badge, with the label,
+// message and color of the SVG badge
+func (g *Generator) GenerateEndpoint(percentage float64, options ...Option) ([]byte, error) {
+	opts := &Options{
+		Style: g.config.Style,
+		Label: g.config.Label,
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	data, err := json.Marshal(&Endpoint{
+		SchemaVersion: 1,
+		Label:         sanitizeUTF8(opts.Label),
+		Message:       fmt.Sprintf("%.1f%%", percentage),
+		Color:         strings.TrimPrefix(g.getColorForPercentage(percentage), "#"),
+		Style:         sanitizeUTF8(opts.Style),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal badge endpoint: %w", err)
+	}
+	return data, nil
+}
+
 // GenerateTrendBadge creates a badge showing coverage trend
 func (g *Generator) GenerateTrendBadge(ctx context.Context, current, previous float64, options ...Option) ([]byte, error) {
 	diff := current - previous
@@ -182,6 +218,56 @@ func (g *Generator) GenerateTrendBadge(ctx context.Context, current, previous fl
 	return g.renderSVG(ctx, badgeData)
 }
 
+// GenerateGradeBadge creates a badge showing a quality grade from A+ to F
+func (g *Generator) GenerateGradeBadge(ctx context.Context, grade string, options ...Option) ([]byte, error) {
+	var color string
+	switch strings.TrimRight(grade, "+-") {
+	case "A":
+		color = g.getColorByName("excellent")
+	case "B":
+		color = g.getColorByName("good")
+	case "C":
+		color = g.getColorByName("acceptable")
+	case "D":
+		color = g.getColorByName("low")
+	default:
+		color = g.getColorByName("poor")
+	}
+	return g.generateMessage(ctx, "quality", grade, color, fmt.Sprintf("Quality grade: %s", grade), options)
+}
+
+// GenerateTestsBadge creates a badge showing the number of passed and failed tests
+func (g *Generator) GenerateTestsBadge(ctx context.Context, total, failed int, options ...Option) ([]byte, error) {
+	message := fmt.Sprintf("%d passed", total-failed)
+	color := g.getColorByName("excellent")
+	if failed > 0 {
+		message = fmt.Sprintf("%d passed, %d failed", total-failed, failed)
+		color = g.getColorByName("poor")
+	}
+	return g.generateMessage(ctx, "tests", message, color, fmt.Sprintf("Tests: %s", message), options)
+}
+
+// generateMessage creates a badge with a fixed message, labeled label unless an option says otherwise
+func (g *Generator) generateMessage(ctx context.Context, label, message, color, ariaLabel string, options []Option) ([]byte, error) {
+	opts := &Options{
+		Style: g.config.Style,
+		Label: label,
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	return g.renderSVG(ctx, Data{
+		Label:     sanitizeUTF8(opts.Label),
+		Message:   sanitizeUTF8(message),
+		Color:     color,
+		Style:     sanitizeUTF8(opts.Style),
+		Logo:      g.resolveLogo(ctx, opts.Logo, sanitizeUTF8(opts.LogoColor)),
+		LogoColor: sanitizeUTF8(opts.LogoColor),
+		AriaLabel: ariaLabel,
+	})
+}
+
 // getColorForPercentage returns the appropriate color based on coverage percentage
 func (g *Generator) getColorForPercentage(percentage float64) string {
 	switch {
@@ -250,6 +336,12 @@ func (g *Generator) resolveLogo(ctx context.Context, logo, color string) string
 				}
 			}
 
+			// Remote icons cannot be fetched in offline mode; render the badge without a logo
+			if cfg.Network.Offline {
+				log.Printf("Skipping logo '%s': offline mode is enabled", logoName)
+				return ""
+			}
+
 			// Create timeout context for logo operations
 			logoCtx, logoCancel := context.WithTimeout(ctx, cfg.Badge.LogoTimeout)
 			defer logoCancel()
@@ -423,6 +515,20 @@ func applySVGColorStatic(svgContent, color string) string {
 	return modifiedSVG
 }
 
+// logoHTTPClient returns the injected HTTP client, or one honoring the configured proxy and TLS settings
+func (g *Generator) logoHTTPClient(cfg *config.Config, timeout time.Duration) *http.Client {
+	if g.httpClient != nil {
+		return g.httpClient
+	}
+
+	client, err := cfg.NewHTTPClient(timeout)
+	if err != nil {
+		log.Printf("Warning: Failed to apply network settings for logo fetching: %v, using defaults", err)
+		return &http.Client{Timeout: timeout}
+	}
+	return client
+}
+
 // fetchSimpleIcon fetches an SVG icon from Simple Icons CDN with retry logic and returns it as a base64 data URI
 func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string, cfg *config.Config) (string, error) {
 	// Build the URL for Simple Icons CDN
@@ -439,6 +545,15 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 	maxRetries := cfg.Badge.LogoRetries
 	httpTimeout := cfg.Badge.LogoHTTPTimeout
 	const baseDelay = 200 * time.Millisecond // Reduced from 500ms to 200ms
+	backoff := retry.Policy{
+		InitialDelay: baseDelay,
+		MaxDelay:     baseDelay << 20, // cap growth to prevent overflow
+		Multiplier:   2,
+		Jitter:       cfg.Retry.Jitter,
+	}
+
+	// Use injected HTTP client if available, otherwise create one with timeout
+	client := g.logoHTTPClient(cfg, httpTimeout)
 
 	var lastErr error
 	for attempt := range maxRetries {
@@ -448,16 +563,6 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			return "", ctx.Err()
 		}
 
-		// Use injected HTTP client if available, otherwise create one with timeout
-		var client *http.Client
-		if g.httpClient != nil {
-			client = g.httpClient
-		} else {
-			client = &http.Client{
-				Timeout: httpTimeout,
-			}
-		}
-
 		// Create request with context
 		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
@@ -479,9 +584,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			}
 			// Wait before retry with exponential backoff
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
@@ -492,9 +595,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			lastErr = fmt.Errorf("%w: HTTP %d from %s (attempt %d/%d)", ErrIconFetchFailed, resp.StatusCode, url, attempt+1, maxRetries)
 			// Wait before retry with exponential backoff
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
@@ -506,9 +607,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			lastErr = fmt.Errorf("failed to read SVG content from %s (attempt %d/%d): %w", url, attempt+1, maxRetries, err)
 			// Wait before retry with exponential backoff
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
@@ -538,13 +637,6 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			return "", ctx.Err()
 		}
 
-		// Use injected HTTP client if available, otherwise create one with timeout
-		var client *http.Client
-		if g.httpClient != nil {
-			client = g.httpClient
-		} else {
-			client = &http.Client{Timeout: httpTimeout}
-		}
 		req, err := http.NewRequestWithContext(ctx, "GET", fallbackURL, nil)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to create request for %s: %w", fallbackURL, err)
@@ -562,9 +654,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 				return "", ctx.Err()
 			}
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
@@ -573,9 +663,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 			_ = resp.Body.Close()
 			lastErr = fmt.Errorf("%w: HTTP %d from %s (attempt %d/%d)", ErrIconFetchFailed, resp.StatusCode, fallbackURL, attempt+1, maxRetries)
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
@@ -585,9 +673,7 @@ func (g *Generator) fetchSimpleIcon(ctx context.Context, iconName, color string,
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read SVG content from %s (attempt %d/%d): %w", fallbackURL, attempt+1, maxRetries, err)
 			if attempt < maxRetries-1 {
-				shift := min(uint(attempt), 20) // cap shift to prevent overflow
-				delay := time.Duration(1<<shift) * baseDelay
-				time.Sleep(delay)
+				time.Sleep(backoff.Delay(attempt + 1))
 			}
 			continue
 		}
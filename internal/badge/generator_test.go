@@ -78,6 +78,18 @@ func TestGenerateWithOptions(t *testing.T) {
 	assert.Contains(t, svgStr, `shape-rendering="crispEdges"`) // flat-square style
 }
 
+func TestGenerateEndpoint(t *testing.T) {
+	generator := New()
+
+	data, err := generator.GenerateEndpoint(87.3)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion":1,"label":"coverage","message":"87.3%","color":"3fb950","style":"flat"}`, string(data))
+
+	data, err = generator.GenerateEndpoint(40, WithLabel("tests"), WithStyle("for-the-badge"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion":1,"label":"tests","message":"40.0%","color":"dc3545","style":"for-the-badge"}`, string(data))
+}
+
 func TestGenerateTrendBadge(t *testing.T) {
 	generator := New()
 	ctx := context.Background()
@@ -126,6 +138,47 @@ func TestGenerateTrendBadge(t *testing.T) {
 	}
 }
 
+func TestGenerateGradeBadge(t *testing.T) {
+	generator := New()
+	ctx := context.Background()
+
+	tests := []struct {
+		grade string
+		color string
+	}{
+		{"A+", "#28a745"},
+		{"B", colorGoodGreen},
+		{"C", "#ffc107"},
+		{"D", "#fd7e14"},
+		{"F", "#dc3545"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.grade, func(t *testing.T) {
+			svg, err := generator.GenerateGradeBadge(ctx, tt.grade)
+			require.NoError(t, err)
+			assert.Contains(t, string(svg), ">"+tt.grade+"<")
+			assert.Contains(t, string(svg), "quality")
+			assert.Contains(t, string(svg), tt.color)
+		})
+	}
+}
+
+func TestGenerateTestsBadge(t *testing.T) {
+	generator := New()
+	ctx := context.Background()
+
+	svg, err := generator.GenerateTestsBadge(ctx, 120, 0)
+	require.NoError(t, err)
+	assert.Contains(t, string(svg), "120 passed")
+	assert.Contains(t, string(svg), "#28a745")
+
+	svg, err = generator.GenerateTestsBadge(ctx, 120, 3, WithLabel("unit tests"))
+	require.NoError(t, err)
+	assert.Contains(t, string(svg), "117 passed, 3 failed")
+	assert.Contains(t, string(svg), "unit tests")
+	assert.Contains(t, string(svg), "#dc3545")
+}
+
 func TestGetColorForPercentage(t *testing.T) {
 	generator := New()
 
@@ -500,6 +553,15 @@ func TestResolveLogo(t *testing.T) {
 	}
 }
 
+func TestResolveLogoOffline(t *testing.T) {
+	t.Setenv("GO_COVERAGE_OFFLINE", "true")
+	generator := New()
+
+	// Simple Icons lookups are skipped entirely, local logos still resolve
+	assert.Empty(t, generator.resolveLogo(context.Background(), "github", "white"))
+	assert.Contains(t, generator.resolveLogo(context.Background(), "example", ""), "data:image/svg+xml;base64,")
+}
+
 func TestGenerateWithResolvedLogos(t *testing.T) {
 	// Create mock Simple Icons CDN server
 	mockServer := createMockSimpleIconsServer(t)
@@ -0,0 +1,85 @@
+package badge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTrendChartTooShort is returned when a trend chart is requested for fewer than two points
+var ErrTrendChartTooShort = errors.New("trend chart needs at least two coverage points")
+
+// Size of the trend chart and its plot area in pixels
+const (
+	trendChartWidth   = 160
+	trendChartHeight  = 40
+	trendChartPadding = 4
+	trendChartLabel   = 44 // Space on the right for the latest percentage
+)
+
+// GenerateTrendChart creates a small line chart of coverage points, oldest first, with the
+// threshold drawn as a dashed line when it is positive. The chart is plain SVG, so READMEs and
+// wikis can embed it like the badge.
+func (g *Generator) GenerateTrendChart(ctx context.Context, points []float64, threshold float64) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	if len(points) < 2 {
+		return nil, ErrTrendChartTooShort
+	}
+
+	// Scale between the lowest and highest value drawn so small changes stay visible
+	lowest, highest := points[0], points[0]
+	for _, point := range points {
+		lowest = min(lowest, point)
+		highest = max(highest, point)
+	}
+	if threshold > 0 {
+		lowest = min(lowest, threshold)
+		highest = max(highest, threshold)
+	}
+
+	plotWidth := float64(trendChartWidth - trendChartLabel - 2*trendChartPadding)
+	plotHeight := float64(trendChartHeight - 2*trendChartPadding)
+	y := func(value float64) float64 {
+		if highest <= lowest {
+			return trendChartPadding + plotHeight/2
+		}
+		return trendChartPadding + plotHeight - (value-lowest)/(highest-lowest)*plotHeight
+	}
+
+	coordinates := make([]string, 0, len(points))
+	for i, point := range points {
+		x := trendChartPadding + plotWidth*float64(i)/float64(len(points)-1)
+		coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", x, y(point)))
+	}
+
+	latest := points[len(points)-1]
+	label := fmt.Sprintf("Coverage trend: %.1f percent over the last %d runs", latest, len(points))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="%s">`+"\n",
+		trendChartWidth, trendChartHeight, label)
+	fmt.Fprintf(&b, "  <title>%s</title>\n", label)
+	fmt.Fprintf(&b, `  <rect width="%d" height="%d" rx="3" fill="#555"/>`+"\n", trendChartWidth, trendChartHeight)
+	if threshold > 0 {
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#dc3545" stroke-width="1" stroke-dasharray="3 2"><title>threshold %.1f%%</title></line>`+"\n",
+			trendChartPadding, y(threshold), trendChartPadding+plotWidth, y(threshold), threshold)
+	}
+	fmt.Fprintf(&b, `  <polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round" stroke-linecap="round"/>`+"\n",
+		strings.Join(coordinates, " "), g.getColorForPercentage(latest))
+	fmt.Fprintf(&b, `  <text x="%d" y="%d" fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">%.1f%%</text>`+"\n",
+		trendChartWidth-trendChartLabel/2, trendChartHeight/2+4, latest)
+	b.WriteString("</svg>")
+
+	return []byte(b.String()), nil
+}
+
+// TrendChartFile returns the file name of the trend chart written next to a badge:
+// coverage.svg gets coverage-chart.svg
+func TrendChartFile(badgeFile string) string {
+	return strings.TrimSuffix(badgeFile, ".svg") + "-chart.svg"
+}
@@ -0,0 +1,46 @@
+package badge
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTrendChart(t *testing.T) {
+	gen := New()
+
+	_, err := gen.GenerateTrendChart(context.Background(), []float64{80}, 0)
+	require.ErrorIs(t, err, ErrTrendChartTooShort)
+
+	chart, err := gen.GenerateTrendChart(context.Background(), []float64{70, 80, 90}, 0)
+	require.NoError(t, err)
+	svg := string(chart)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, svg, `<polyline points="4.0,36.0 58.0,20.0 112.0,4.0"`)
+	assert.Contains(t, svg, "90.0%")
+	assert.Contains(t, svg, "over the last 3 runs")
+	assert.NotContains(t, svg, "threshold")
+
+	chart, err = gen.GenerateTrendChart(context.Background(), []float64{85, 90}, 80)
+	require.NoError(t, err)
+	svg = string(chart)
+	assert.Contains(t, svg, `<line x1="4" y1="36.0"`, "the threshold extends the scale")
+	assert.Contains(t, svg, "threshold 80.0%")
+
+	chart, err = gen.GenerateTrendChart(context.Background(), []float64{75, 75}, 0)
+	require.NoError(t, err)
+	assert.Contains(t, string(chart), `<polyline points="4.0,20.0 112.0,20.0"`, "a flat history is drawn mid-height")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = gen.GenerateTrendChart(ctx, []float64{70, 80}, 0)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTrendChartFile(t *testing.T) {
+	assert.Equal(t, "coverage-chart.svg", TrendChartFile("coverage.svg"))
+	assert.Equal(t, "badges/main-chart.svg", TrendChartFile("badges/main.svg"))
+}
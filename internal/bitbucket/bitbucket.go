@@ -0,0 +1,219 @@
+// Package bitbucket provides Bitbucket Cloud API integration for coverage reporting
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// Static error definitions
+var (
+	ErrMissingCredentials = errors.New("bitbucket credentials are required: set an access token or a username and app password")
+	ErrBitbucketAPIError  = errors.New("bitbucket API error")
+	ErrCommentNotFound    = errors.New("coverage comment not found")
+	errRetryableStatus    = errors.New("retryable bitbucket API status")
+)
+
+// DefaultBaseURL is the Bitbucket Cloud API base URL
+const DefaultBaseURL = "https://api.bitbucket.org/2.0"
+
+// CommentMarker identifies the coverage comment of a pull request so it is updated in place
+const CommentMarker = "[//]: # (go-coverage-v1)"
+
+// Build status states
+const (
+	StateSuccessful = "SUCCESSFUL"
+	StateFailed     = "FAILED"
+	StateInProgress = "INPROGRESS"
+)
+
+// BuildStatus is a commit build status
+type BuildStatus struct {
+	Key         string `json:"key"`         // Unique key of the status on the commit
+	State       string `json:"state"`       // SUCCESSFUL, FAILED, INPROGRESS or STOPPED
+	Name        string `json:"name"`        // Name shown in the UI
+	URL         string `json:"url"`         // Link to details (required by Bitbucket)
+	Description string `json:"description"` // Short description
+}
+
+// Comment is a pull request comment
+type Comment struct {
+	ID      int `json:"id"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	Deleted bool `json:"deleted"`
+}
+
+// commentPage is one page of pull request comments
+type commentPage struct {
+	Values []Comment `json:"values"`
+	Next   string    `json:"next"`
+}
+
+// Config holds Bitbucket client configuration
+type Config struct {
+	BaseURL     string // API base URL (empty uses DefaultBaseURL)
+	Token       string // Repository, project or workspace access token (Bearer)
+	Username    string // Username for app password authentication
+	AppPassword string // App password (used when no token is set)
+	UserAgent   string // User agent string
+
+	// RetryPolicy controls retries of transient API failures (nil disables retries)
+	RetryPolicy *retry.Policy
+	// HTTPClient overrides the default client, e.g. for proxies or custom CAs
+	HTTPClient *http.Client
+}
+
+// Client handles Bitbucket Cloud API operations for coverage reporting
+type Client struct {
+	config     *Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New creates a Bitbucket client
+func New(config *Config) (*Client, error) {
+	if config.Token == "" && (config.Username == "" || config.AppPassword == "") {
+		return nil, ErrMissingCredentials
+	}
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{config: config, baseURL: baseURL, httpClient: httpClient}, nil
+}
+
+// CreateBuildStatus creates or updates the build status with the same key on a commit
+func (c *Client) CreateBuildStatus(ctx context.Context, workspace, repo, commit string, status *BuildStatus) error {
+	endpoint := fmt.Sprintf("%s/commit/%s/statuses/build", c.repositoryURL(workspace, repo), url.PathEscape(commit))
+	if err := c.request(ctx, http.MethodPost, endpoint, status, nil); err != nil {
+		return fmt.Errorf("failed to create build status: %w", err)
+	}
+	return nil
+}
+
+// UpsertPullRequestComment updates the coverage comment of a pull request, or creates it
+// when there is none yet, and reports whether it was created
+func (c *Client) UpsertPullRequestComment(ctx context.Context, workspace, repo string, pr int, body string) (*Comment, bool, error) {
+	commentsURL := fmt.Sprintf("%s/pullrequests/%d/comments", c.repositoryURL(workspace, repo), pr)
+	existing, err := c.findCoverageComment(ctx, commentsURL)
+	if err != nil && !errors.Is(err, ErrCommentNotFound) {
+		return nil, false, err
+	}
+
+	input := map[string]any{"content": map[string]string{"raw": body}}
+	var comment Comment
+	if existing != nil {
+		if err = c.request(ctx, http.MethodPut, fmt.Sprintf("%s/%d", commentsURL, existing.ID), input, &comment); err != nil {
+			return nil, false, fmt.Errorf("failed to update comment: %w", err)
+		}
+		return &comment, false, nil
+	}
+	if err = c.request(ctx, http.MethodPost, commentsURL, input, &comment); err != nil {
+		return nil, false, fmt.Errorf("failed to create comment: %w", err)
+	}
+	return &comment, true, nil
+}
+
+// findCoverageComment returns the comment carrying CommentMarker
+func (c *Client) findCoverageComment(ctx context.Context, commentsURL string) (*Comment, error) {
+	next := commentsURL + "?pagelen=100"
+	for next != "" {
+		var page commentPage
+		if err := c.request(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+		for i := range page.Values {
+			if !page.Values[i].Deleted && strings.Contains(page.Values[i].Content.Raw, CommentMarker) {
+				return &page.Values[i], nil
+			}
+		}
+		// Only follow pages of the same API so credentials are never sent elsewhere
+		next = ""
+		if strings.HasPrefix(page.Next, c.baseURL+"/") {
+			next = page.Next
+		}
+	}
+	return nil, ErrCommentNotFound
+}
+
+// repositoryURL returns the API URL of a repository
+func (c *Client) repositoryURL(workspace, repo string) string {
+	return fmt.Sprintf("%s/repositories/%s/%s", c.baseURL, url.PathEscape(workspace), url.PathEscape(repo))
+}
+
+// request sends an API request with the JSON encoded input and decodes the response into
+// output, retrying rate limits and server errors according to the retry policy
+func (c *Client) request(ctx context.Context, method, endpoint string, input, output any) error {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	send := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
+		if c.config.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.config.Token)
+		} else {
+			req.SetBasicAuth(c.config.Username, c.config.AppPassword)
+		}
+		req.Header.Set("Accept", "application/json")
+		if input != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.config.UserAgent != "" {
+			req.Header.Set("User-Agent", c.config.UserAgent)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			statusErr := fmt.Errorf("%w: %d %s", ErrBitbucketAPIError, resp.StatusCode, strings.TrimSpace(string(message)))
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("%w: %w", errRetryableStatus, statusErr)
+			}
+			return retry.Permanent(statusErr)
+		}
+		if output == nil {
+			return nil
+		}
+		if err = json.NewDecoder(resp.Body).Decode(output); err != nil {
+			return retry.Permanent(fmt.Errorf("failed to decode response: %w", err))
+		}
+		return nil
+	}
+
+	if c.config.RetryPolicy == nil {
+		return send(ctx)
+	}
+	return retry.Do(ctx, *c.config.RetryPolicy, send)
+}
@@ -0,0 +1,176 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+func TestNewRequiresCredentials(t *testing.T) {
+	_, err := New(&Config{})
+	require.ErrorIs(t, err, ErrMissingCredentials)
+
+	_, err = New(&Config{Username: "ci"})
+	require.ErrorIs(t, err, ErrMissingCredentials)
+
+	client, err := New(&Config{Username: "ci", AppPassword: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBaseURL, client.baseURL)
+}
+
+func TestCreateBuildStatus(t *testing.T) {
+	var (
+		path   string
+		auth   string
+		status BuildStatus
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &status)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := New(&Config{BaseURL: server.URL + "/", Token: "token"})
+	require.NoError(t, err)
+
+	err = client.CreateBuildStatus(context.Background(), "team", "service", "abc123", &BuildStatus{
+		Key: "go-coverage", State: StateSuccessful, Name: "Coverage", URL: "https://example.com", Description: "80.00% coverage",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "POST /repositories/team/service/commit/abc123/statuses/build", path)
+	assert.Equal(t, "Bearer token", auth)
+	assert.Equal(t, StateSuccessful, status.State)
+	assert.Equal(t, "80.00% coverage", status.Description)
+}
+
+func TestCreateBuildStatusAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"message":"forbidden"}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(&Config{BaseURL: server.URL, Username: "ci", AppPassword: "secret"})
+	require.NoError(t, err)
+
+	err = client.CreateBuildStatus(context.Background(), "team", "service", "abc123", &BuildStatus{})
+	require.ErrorIs(t, err, ErrBitbucketAPIError)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestCreateBuildStatusRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := New(&Config{
+		BaseURL:     server.URL,
+		Token:       "token",
+		RetryPolicy: &retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, client.CreateBuildStatus(context.Background(), "team", "service", "abc123", &BuildStatus{}))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestUpsertPullRequestComment(t *testing.T) {
+	t.Run("creates the comment", func(t *testing.T) {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method+" "+r.URL.Path)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"values":[{"id":1,"content":{"raw":"LGTM"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":2}`))
+		}))
+		defer server.Close()
+
+		client, err := New(&Config{BaseURL: server.URL, Token: "token"})
+		require.NoError(t, err)
+
+		comment, created, err := client.UpsertPullRequestComment(context.Background(), "team", "service", 7, CommentMarker+"\nbody")
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, 2, comment.ID)
+		assert.Equal(t, []string{
+			"GET /repositories/team/service/pullrequests/7/comments",
+			"POST /repositories/team/service/pullrequests/7/comments",
+		}, methods)
+	})
+
+	t.Run("updates the comment found on a later page", func(t *testing.T) {
+		var (
+			methods []string
+			updated string
+		)
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method+" "+r.URL.Path)
+			switch {
+			case r.Method == http.MethodGet && r.URL.Query().Get("page") == "":
+				_, _ = fmt.Fprintf(w, `{"values":[{"id":1,"content":{"raw":"LGTM"}}],"next":"%s/repositories/team/service/pullrequests/7/comments?page=2"}`, server.URL)
+			case r.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"values":[{"id":4,"content":{"raw":"` + CommentMarker + `"},"deleted":true},{"id":5,"content":{"raw":"` + CommentMarker + `"}}]}`))
+			default:
+				body, _ := io.ReadAll(r.Body)
+				updated = string(body)
+				_, _ = w.Write([]byte(`{"id":5}`))
+			}
+		}))
+		defer server.Close()
+
+		client, err := New(&Config{BaseURL: server.URL, Token: "token"})
+		require.NoError(t, err)
+
+		comment, created, err := client.UpsertPullRequestComment(context.Background(), "team", "service", 7, "new body")
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, 5, comment.ID)
+		assert.Equal(t, "PUT /repositories/team/service/pullrequests/7/comments/5", methods[len(methods)-1])
+		assert.Contains(t, updated, "new body")
+	})
+
+	t.Run("does not follow pages of other hosts", func(t *testing.T) {
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"values":[],"next":"https://attacker.example.com/comments?page=2"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":3}`))
+		}))
+		defer server.Close()
+
+		client, err := New(&Config{BaseURL: server.URL, Token: "token"})
+		require.NoError(t, err)
+
+		_, created, err := client.UpsertPullRequestComment(context.Background(), "team", "service", 7, "body")
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, 2, calls)
+	})
+}
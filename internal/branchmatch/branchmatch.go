@@ -0,0 +1,75 @@
+// Package branchmatch matches branch names against glob patterns such as release/*, the one
+// matching engine behind branch specific thresholds, history and badges
+package branchmatch
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrInvalidPattern indicates a malformed branch pattern
+var ErrInvalidPattern = errors.New("invalid branch pattern")
+
+// anySegments is the pattern segment matching any number of branch segments
+const anySegments = "**"
+
+// Validate returns an error when pattern is not a well-formed branch pattern
+func Validate(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("%w: empty pattern", ErrInvalidPattern)
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == anySegments {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidPattern, pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether branch matches pattern. Within a segment, * matches any characters,
+// ? a single character and [...] a character class, as in path.Match. A ** segment matches
+// any number of segments, so release/** matches release/1.x and release/1.x/hotfix. A pattern
+// without wildcards matches only the branch of that name; malformed patterns match nothing.
+func Match(pattern, branch string) bool {
+	if pattern == branch {
+		return true
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(branch, "/"))
+}
+
+// First returns the index of the first pattern matching branch, or -1 when none matches
+func First(patterns []string, branch string) int {
+	for i, pattern := range patterns {
+		if Match(pattern, branch) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchSegments matches the segments of a branch against the segments of a pattern
+func matchSegments(pattern, branch []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == anySegments {
+			for skip := 0; skip <= len(branch); skip++ {
+				if matchSegments(pattern[1:], branch[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(branch) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], branch[0]); err != nil || !matched {
+			return false
+		}
+		pattern, branch = pattern[1:], branch[1:]
+	}
+	return len(branch) == 0
+}
@@ -0,0 +1,49 @@
+package branchmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, branch string
+		expected        bool
+	}{
+		{"main", "main", true},
+		{"main", "master", false},
+		{"release/*", "release/1.x", true},
+		{"release/*", "release/1.x/hotfix", false},
+		{"release/*", "release", false},
+		{"release/**", "release/1.x/hotfix", true},
+		{"release/**", "release", true},
+		{"**/hotfix", "team/a/hotfix", true},
+		{"**", "anything/at/all", true},
+		{"v?.x", "v1.x", true},
+		{"v[0-9].x", "v2.x", true},
+		{"v[0-9].x", "vx.x", false},
+		{"release-*", "release-2024", true},
+		{"[", "release", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Match(tt.pattern, tt.branch), "%s ~ %s", tt.pattern, tt.branch)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	patterns := []string{"release/1.x", "release/*", "**"}
+	assert.Equal(t, 0, First(patterns, "release/1.x"))
+	assert.Equal(t, 1, First(patterns, "release/2.x"))
+	assert.Equal(t, 2, First(patterns, "feature/x"))
+	assert.Equal(t, -1, First(patterns[:2], "feature/x"))
+	assert.Equal(t, -1, First(nil, "main"))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate("release/*"))
+	require.NoError(t, Validate("release/**"))
+	require.ErrorIs(t, Validate(""), ErrInvalidPattern)
+	require.ErrorIs(t, Validate("release/[a"), ErrInvalidPattern)
+}
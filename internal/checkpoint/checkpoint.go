@@ -0,0 +1,169 @@
+// Package checkpoint records which steps of a pipeline run finished, so that a run that failed
+// halfway can be resumed without redoing the steps that already succeeded
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// SchemaVersion is the version of the checkpoint format written by this build
+	SchemaVersion = 1
+	// FileName is the name of the checkpoint inside the output directory
+	FileName = ".go-coverage-checkpoint.json"
+)
+
+// ErrUnknownStep indicates that a step has no recorded result
+var ErrUnknownStep = errors.New("step has no recorded result")
+
+// State is the outcome of a step
+type State string
+
+// Step states. A step that is still running when a checkpoint is loaded was interrupted.
+const (
+	StateRunning   State = "running"
+	StateCompleted State = "completed"
+	StateFailed    State = "failed"
+)
+
+// Step is the recorded outcome of a pipeline step
+type Step struct {
+	State State     `json:"state"`
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+	// Data holds what later steps need from this one when it is skipped
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Checkpoint is the progress of a run. It is saved after every change, so a crashed run leaves
+// the steps it finished behind.
+type Checkpoint struct {
+	SchemaVersion int              `json:"schema_version"`
+	Key           string           `json:"key"`
+	Steps         map[string]*Step `json:"steps"`
+
+	path string
+	mode os.FileMode
+}
+
+// Key identifies a run from its inputs; a checkpoint is only resumed by a run with the same key
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// New starts an empty checkpoint stored at path
+func New(path, key string, mode os.FileMode) *Checkpoint {
+	return &Checkpoint{SchemaVersion: SchemaVersion, Key: key, Steps: map[string]*Step{}, path: path, mode: mode}
+}
+
+// Load reads the checkpoint at path. A missing checkpoint, or one written by a run with another
+// key or schema version, yields an empty checkpoint.
+func Load(path, key string, mode os.FileMode) (*Checkpoint, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is the configured output directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(path, key, mode), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err = json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if checkpoint.SchemaVersion != SchemaVersion || checkpoint.Key != key || checkpoint.Steps == nil {
+		return New(path, key, mode), nil
+	}
+	checkpoint.path, checkpoint.mode = path, mode
+	return &checkpoint, nil
+}
+
+// Empty reports whether no step has been recorded
+func (c *Checkpoint) Empty() bool {
+	return len(c.Steps) == 0
+}
+
+// Done reports whether a step completed
+func (c *Checkpoint) Done(step string) bool {
+	result, ok := c.Steps[step]
+	return ok && result.State == StateCompleted
+}
+
+// State returns the state of a step, or an empty state when it was not recorded
+func (c *Checkpoint) State(step string) State {
+	if result, ok := c.Steps[step]; ok {
+		return result.State
+	}
+	return ""
+}
+
+// Begin records that a step started
+func (c *Checkpoint) Begin(step string) error {
+	c.Steps[step] = &Step{State: StateRunning, At: time.Now().UTC()}
+	return c.Save()
+}
+
+// Complete records that a step finished, with data to restore when it is skipped later
+func (c *Checkpoint) Complete(step string, data any) error {
+	result := &Step{State: StateCompleted, At: time.Now().UTC()}
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s step data: %w", step, err)
+		}
+		result.Data = encoded
+	}
+	c.Steps[step] = result
+	return c.Save()
+}
+
+// Fail records that a step failed
+func (c *Checkpoint) Fail(step string, stepErr error) error {
+	c.Steps[step] = &Step{State: StateFailed, Error: stepErr.Error(), At: time.Now().UTC()}
+	return c.Save()
+}
+
+// Data decodes the data a completed step recorded into v
+func (c *Checkpoint) Data(step string, v any) error {
+	result, ok := c.Steps[step]
+	if !ok || len(result.Data) == 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownStep, step)
+	}
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		return fmt.Errorf("failed to decode %s step data: %w", step, err)
+	}
+	return nil
+}
+
+// Save writes the checkpoint, replacing the previous file atomically
+func (c *Checkpoint) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err = os.WriteFile(tmp, append(data, '\n'), c.mode); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err = os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package checkpoint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, Key("a", "b"), Key("a", "b"))
+	assert.NotEqual(t, Key("ab", ""), Key("a", "b"))
+	assert.Len(t, Key(), 64)
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", FileName)
+	key := Key("sha", "profile")
+
+	checkpoint := New(path, key, 0o600)
+	assert.True(t, checkpoint.Empty())
+	require.NoError(t, checkpoint.Complete("report", map[string]int{"pages": 3}))
+	require.NoError(t, checkpoint.Begin("history"))
+	require.NoError(t, checkpoint.Fail("github", errors.New("403 Forbidden")))
+
+	loaded, err := Load(path, key, 0o600)
+	require.NoError(t, err)
+	assert.False(t, loaded.Empty())
+	assert.True(t, loaded.Done("report"))
+	assert.False(t, loaded.Done("history"))
+	assert.Equal(t, StateRunning, loaded.State("history"))
+	assert.Equal(t, StateFailed, loaded.State("github"))
+	assert.Equal(t, "403 Forbidden", loaded.Steps["github"].Error)
+	assert.Empty(t, loaded.State("publish"))
+
+	var data map[string]int
+	require.NoError(t, loaded.Data("report", &data))
+	assert.Equal(t, 3, data["pages"])
+	require.ErrorIs(t, loaded.Data("history", &data), ErrUnknownStep)
+
+	// The loaded checkpoint keeps saving to the same file
+	require.NoError(t, loaded.Complete("history", nil))
+	reloaded, err := Load(path, key, 0o600)
+	require.NoError(t, err)
+	assert.True(t, reloaded.Done("history"))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		checkpoint, err := Load(filepath.Join(dir, "missing.json"), "key", 0o600)
+		require.NoError(t, err)
+		assert.True(t, checkpoint.Empty())
+	})
+
+	t.Run("other run", func(t *testing.T) {
+		path := filepath.Join(dir, "other.json")
+		require.NoError(t, New(path, "old", 0o600).Complete("badge", nil))
+
+		checkpoint, err := Load(path, "new", 0o600)
+		require.NoError(t, err)
+		assert.True(t, checkpoint.Empty())
+		assert.Equal(t, "new", checkpoint.Key)
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(dir, "corrupt.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+		_, err := Load(path, "key", 0o600)
+		require.Error(t, err)
+	})
+}
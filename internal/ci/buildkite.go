@@ -0,0 +1,41 @@
+package ci
+
+// buildkite detects Buildkite builds
+type buildkite struct{}
+
+// Name returns the provider name
+func (buildkite) Name() string {
+	return ProviderBuildkite
+}
+
+// Detect reports whether the variables describe a Buildkite job
+func (buildkite) Detect(getenv func(string) string) bool {
+	return getenv("BUILDKITE") == "true"
+}
+
+// Resolve derives the context from the agent environment. BUILDKITE_PULL_REQUEST is "false"
+// outside pull request builds.
+func (buildkite) Resolve(getenv func(string) string) *Context {
+	ctx := &Context{BuildURL: getenv("BUILDKITE_BUILD_URL"), Message: getenv("BUILDKITE_MESSAGE")}
+	repository := getenv("BUILDKITE_REPO")
+	ctx.Owner, ctx.Repository = repositoryFromGitURL(repository)
+	// Builds triggered without a commit report HEAD until the agent checks it out
+	if commit := getenv("BUILDKITE_COMMIT"); commit != "HEAD" {
+		ctx.CommitSHA = commit
+	}
+
+	if tag := getenv("BUILDKITE_TAG"); tag != "" {
+		ctx.Tag = tag
+		return ctx
+	}
+	ctx.Branch = getenv("BUILDKITE_BRANCH")
+	if number := pullRequestNumber(getenv("BUILDKITE_PULL_REQUEST")); number > 0 {
+		ctx.PullRequest = number
+		ctx.BaseBranch = getenv("BUILDKITE_PULL_REQUEST_BASE_BRANCH")
+		if headRepository := getenv("BUILDKITE_PULL_REQUEST_REPO"); headRepository != "" && repository != "" {
+			headOwner, headRepo := repositoryFromGitURL(headRepository)
+			ctx.Fork = headOwner != ctx.Owner || headRepo != ctx.Repository
+		}
+	}
+	return ctx
+}
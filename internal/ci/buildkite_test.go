@@ -0,0 +1,62 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveBuildkite(t *testing.T) {
+	job := map[string]string{
+		"BUILDKITE": "true", "BUILDKITE_REPO": "git@github.com:owner/repo.git",
+		"BUILDKITE_COMMIT": "buildkitesha", "BUILDKITE_BUILD_URL": "https://buildkite.com/org/repo/builds/3",
+		"BUILDKITE_PULL_REQUEST": "false",
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Context
+	}{
+		{
+			name: "branch",
+			env:  map[string]string{"BUILDKITE_BRANCH": "main", "BUILDKITE_MESSAGE": "Roll back [hotfix]"},
+			expected: Context{
+				Provider: ProviderBuildkite, Owner: "owner", Repository: "repo", Branch: "main",
+				CommitSHA: "buildkitesha", BuildURL: "https://buildkite.com/org/repo/builds/3", Message: "Roll back [hotfix]",
+			},
+		},
+		{
+			name: "pull request from a fork",
+			env: map[string]string{
+				"BUILDKITE_BRANCH": "contributor:feature", "BUILDKITE_PULL_REQUEST": "42",
+				"BUILDKITE_PULL_REQUEST_BASE_BRANCH": "main", "BUILDKITE_PULL_REQUEST_REPO": "https://github.com/contributor/repo.git",
+			},
+			expected: Context{
+				Provider: ProviderBuildkite, Owner: "owner", Repository: "repo", PullRequest: 42, Branch: "contributor:feature",
+				BaseBranch: "main", CommitSHA: "buildkitesha", Fork: true, BuildURL: "https://buildkite.com/org/repo/builds/3",
+			},
+		},
+		{
+			name: "tag before checkout",
+			env:  map[string]string{"BUILDKITE_TAG": "v1.2.0", "BUILDKITE_COMMIT": "HEAD"},
+			expected: Context{
+				Provider: ProviderBuildkite, Owner: "owner", Repository: "repo", Tag: "v1.2.0",
+				BuildURL: "https://buildkite.com/org/repo/builds/3",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range job {
+				env[key] = value
+			}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			assert.Equal(t, tt.expected, *Resolve(getenvOf(env)))
+		})
+	}
+}
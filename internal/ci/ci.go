@@ -0,0 +1,231 @@
+// Package ci resolves what a CI run is about - the repository, the branch or tag, the commit
+// and the pull request - from the environment of the CI provider running it
+package ci
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownProvider indicates that GO_COVERAGE_CI names no supported provider
+var ErrUnknownProvider = errors.New("unknown CI provider")
+
+// EnvProvider selects the provider instead of detecting it: the name of a provider, or manual
+// to take the context from the GO_COVERAGE_CI_* variables alone
+const EnvProvider = "GO_COVERAGE_CI"
+
+// Providers
+const (
+	ProviderGitHubActions = "github-actions"
+	ProviderGitLab        = "gitlab"
+	ProviderCircleCI      = "circleci"
+	ProviderBuildkite     = "buildkite"
+	ProviderJenkins       = "jenkins"
+	ProviderManual        = "manual"
+)
+
+// Manual overrides, applied on top of what the provider reports
+const (
+	envRepository = "GO_COVERAGE_CI_REPOSITORY"
+	envBranch     = "GO_COVERAGE_CI_BRANCH"
+	envBaseBranch = "GO_COVERAGE_CI_BASE_BRANCH"
+	envTag        = "GO_COVERAGE_CI_TAG"
+	envCommit     = "GO_COVERAGE_CI_COMMIT"
+	envPR         = "GO_COVERAGE_CI_PR"
+	envBuildURL   = "GO_COVERAGE_CI_BUILD_URL"
+	envMessage    = "GO_COVERAGE_CI_MESSAGE"
+)
+
+// Context describes what a CI run is about: the repository, the branch or tag, the commit and,
+// when there is one, the pull request. It is resolved once from the environment of the CI
+// provider, so every command agrees on it.
+type Context struct {
+	// Provider is the CI provider the context was resolved from, empty outside CI
+	Provider string `json:"provider,omitempty"`
+	// Event is the name of the triggering event (GITHUB_EVENT_NAME), empty outside Actions
+	Event      string `json:"event,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	// PullRequest is the pull request number, 0 when the run is not about a pull request
+	PullRequest int `json:"pull_request,omitempty"`
+	// Branch is the branch the commit belongs to: the head branch of a pull request, the pushed
+	// branch, or the head branch of the triggering run. It is empty for tags.
+	Branch string `json:"branch,omitempty"`
+	// BaseBranch is the branch a pull request targets
+	BaseBranch string `json:"base_branch,omitempty"`
+	// Tag is the pushed tag, if the run is for a tag
+	Tag string `json:"tag,omitempty"`
+	// CommitSHA is the commit under test: the head commit of a pull request rather than the
+	// merge commit GitHub checks out, or the pushed or triggering commit otherwise
+	CommitSHA string `json:"commit_sha,omitempty"`
+	// Fork reports a pull request whose head lives in another repository
+	Fork bool `json:"fork,omitempty"`
+	// BuildURL links the build or pipeline on the CI provider, empty in GitHub Actions
+	BuildURL string `json:"build_url,omitempty"`
+	// Message is the message of the commit under test, or the title of the pull request when the
+	// provider reports one; empty when the provider reports neither
+	Message string `json:"message,omitempty"`
+	// Labels are the labels of the pull request when the event was triggered, empty when the
+	// provider does not report them
+	Labels []string `json:"labels,omitempty"`
+}
+
+// IsPullRequest reports whether the run is about a pull request
+func (c *Context) IsPullRequest() bool {
+	return c.PullRequest > 0
+}
+
+// IsMergeGroup reports whether the run tests a merge queue's merge commit. Branch is then the
+// temporary queue branch, BaseBranch the branch being merged into and CommitSHA the merge commit.
+func (c *Context) IsMergeGroup() bool {
+	return c.Event == EventMergeGroup
+}
+
+// Detector recognizes the environment of a CI provider and resolves the context of its runs
+type Detector interface {
+	// Name is the provider name, as accepted by GO_COVERAGE_CI
+	Name() string
+	// Detect reports whether the variables describe a run of the provider
+	Detect(getenv func(string) string) bool
+	// Resolve derives the context of the run from the variables
+	Resolve(getenv func(string) string) *Context
+}
+
+// Detectors returns the detectors of the supported providers, in the order they are tried
+func Detectors() []Detector {
+	return []Detector{githubActions{}, gitLab{}, circleCI{}, buildkite{}, jenkins{}}
+}
+
+// Detect returns the detector of the provider running the process, or of the provider
+// GO_COVERAGE_CI names, and nil outside CI or in manual mode
+func Detect(getenv func(string) string) Detector {
+	name := strings.ToLower(strings.TrimSpace(getenv(EnvProvider)))
+	for _, detector := range Detectors() {
+		if name == detector.Name() {
+			return detector
+		}
+	}
+	if name == ProviderManual {
+		return nil
+	}
+	for _, detector := range Detectors() {
+		if detector.Detect(getenv) {
+			return detector
+		}
+	}
+	return nil
+}
+
+// ValidateProvider returns ErrUnknownProvider unless name is empty, manual or a supported provider
+func ValidateProvider(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == ProviderManual {
+		return nil
+	}
+	for _, detector := range Detectors() {
+		if name == detector.Name() {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q (use %s, %s, %s, %s, %s or %s)", ErrUnknownProvider, name,
+		ProviderGitHubActions, ProviderGitLab, ProviderCircleCI, ProviderBuildkite, ProviderJenkins, ProviderManual)
+}
+
+// IsCI reports whether the process runs in CI: CI=true, as most providers set it, or a
+// detected provider
+func IsCI(getenv func(string) string) bool {
+	return getenv("CI") == "true" || Detect(getenv) != nil
+}
+
+// Resolve derives the context of the run from the variables read through getenv. Outside CI,
+// the GitHub Actions variables may still be set by hand. GITHUB_REPOSITORY, when set outside
+// Actions, names the repository, and the GO_COVERAGE_CI_* variables override the rest.
+// GITHUB_PR_NUMBER, if set, overrides the pull request number.
+func Resolve(getenv func(string) string) *Context {
+	var ctx *Context
+	switch detector := Detect(getenv); {
+	case detector != nil:
+		ctx = detector.Resolve(getenv)
+		ctx.Provider = detector.Name()
+	case strings.EqualFold(strings.TrimSpace(getenv(EnvProvider)), ProviderManual):
+		ctx = &Context{Provider: ProviderManual}
+	default:
+		ctx = githubActions{}.Resolve(getenv)
+	}
+
+	if ctx.Provider != "" && ctx.Provider != ProviderGitHubActions {
+		if owner, repo, ok := strings.Cut(getenv("GITHUB_REPOSITORY"), "/"); ok && owner != "" && repo != "" {
+			ctx.Owner, ctx.Repository = owner, repo
+		}
+	}
+	ctx.applyOverrides(getenv)
+
+	if number, err := strconv.Atoi(getenv("GITHUB_PR_NUMBER")); err == nil && number > 0 {
+		ctx.PullRequest = number
+	}
+	return ctx
+}
+
+// FromEnv resolves the context of the run from the process environment
+func FromEnv() *Context {
+	return Resolve(os.Getenv)
+}
+
+// applyOverrides replaces what the provider reported with the GO_COVERAGE_CI_* variables
+func (c *Context) applyOverrides(getenv func(string) string) {
+	if owner, repo, ok := strings.Cut(getenv(envRepository), "/"); ok && owner != "" && repo != "" {
+		c.Owner, c.Repository = owner, repo
+	}
+	if branch := getenv(envBranch); branch != "" {
+		c.Branch, c.Tag = branch, ""
+	}
+	if tag := getenv(envTag); tag != "" {
+		c.Tag, c.Branch = tag, ""
+	}
+	if base := getenv(envBaseBranch); base != "" {
+		c.BaseBranch = base
+	}
+	if commit := getenv(envCommit); commit != "" {
+		c.CommitSHA = commit
+	}
+	if number, err := strconv.Atoi(getenv(envPR)); err == nil && number > 0 {
+		c.PullRequest = number
+	}
+	if buildURL := getenv(envBuildURL); buildURL != "" {
+		c.BuildURL = buildURL
+	}
+	if message := getenv(envMessage); message != "" {
+		c.Message = message
+	}
+}
+
+// pullRequestNumber parses a pull request number, returning 0 for anything else, such as the
+// "false" some providers set outside pull requests
+func pullRequestNumber(value string) int {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || number < 0 {
+		return 0
+	}
+	return number
+}
+
+// repositoryFromGitURL returns the owner and repository of a github.com clone URL, in the
+// https://github.com/owner/repo.git or git@github.com:owner/repo.git form
+func repositoryFromGitURL(gitURL string) (string, string) {
+	var path string
+	if rest, ok := strings.CutPrefix(gitURL, "git@github.com:"); ok {
+		path = rest
+	} else if parsed, err := url.Parse(gitURL); err == nil && strings.EqualFold(parsed.Hostname(), "github.com") {
+		path = strings.TrimPrefix(parsed.Path, "/")
+	}
+
+	owner, repo, ok := strings.Cut(strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git"), "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", ""
+	}
+	return owner, repo
+}
@@ -0,0 +1,102 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getenvOf returns a getenv reading the variables of env
+func getenvOf(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"CI": "true"}, ""},
+		{map[string]string{"GITHUB_ACTIONS": "true"}, ProviderGitHubActions},
+		{map[string]string{"GITLAB_CI": "true"}, ProviderGitLab},
+		{map[string]string{"CIRCLECI": "true"}, ProviderCircleCI},
+		{map[string]string{"BUILDKITE": "true"}, ProviderBuildkite},
+		{map[string]string{"JENKINS_URL": "https://ci.example.com/"}, ProviderJenkins},
+		{map[string]string{"GITHUB_ACTIONS": "true", "JENKINS_URL": "https://ci.example.com/"}, ProviderGitHubActions},
+		{map[string]string{"GITLAB_CI": "true", EnvProvider: "Jenkins"}, ProviderJenkins},
+		{map[string]string{"GITLAB_CI": "true", EnvProvider: ProviderManual}, ""},
+		{map[string]string{"GITLAB_CI": "true", EnvProvider: "travis"}, ProviderGitLab},
+	}
+	for _, tt := range tests {
+		var name string
+		if detector := Detect(getenvOf(tt.env)); detector != nil {
+			name = detector.Name()
+		}
+		assert.Equal(t, tt.expected, name, tt.env)
+	}
+}
+
+func TestValidateProvider(t *testing.T) {
+	for _, name := range []string{"", ProviderManual, ProviderGitLab, " CircleCI "} {
+		require.NoError(t, ValidateProvider(name), name)
+	}
+	require.ErrorIs(t, ValidateProvider("travis"), ErrUnknownProvider)
+}
+
+func TestIsCI(t *testing.T) {
+	assert.False(t, IsCI(getenvOf(map[string]string{})))
+	assert.True(t, IsCI(getenvOf(map[string]string{"CI": "true"})))
+	assert.True(t, IsCI(getenvOf(map[string]string{"JENKINS_URL": "https://ci.example.com/"})))
+}
+
+func TestResolveOverrides(t *testing.T) {
+	t.Run("manual mode", func(t *testing.T) {
+		ctx := Resolve(getenvOf(map[string]string{
+			EnvProvider: "manual", "GITLAB_CI": "true", "CI_COMMIT_BRANCH": "ignored",
+			envRepository: "owner/repo", envBranch: "feature", envBaseBranch: "main", envCommit: "abc123",
+			envPR: "7", envBuildURL: "https://ci.example.com/7", envMessage: "Patch [hotfix]",
+		}))
+		assert.Equal(t, Context{
+			Provider: ProviderManual, Owner: "owner", Repository: "repo", PullRequest: 7,
+			Branch: "feature", BaseBranch: "main", CommitSHA: "abc123", BuildURL: "https://ci.example.com/7",
+			Message: "Patch [hotfix]",
+		}, *ctx)
+	})
+
+	t.Run("overrides replace the detected values", func(t *testing.T) {
+		ctx := Resolve(getenvOf(map[string]string{
+			"GITLAB_CI": "true", "CI_PROJECT_NAMESPACE": "group", "CI_PROJECT_NAME": "project",
+			"CI_COMMIT_BRANCH": "main", "CI_COMMIT_SHA": "gitlabsha",
+			"GITHUB_REPOSITORY": "mirror/project", envTag: "v1.0.0",
+		}))
+		assert.Equal(t, Context{
+			Provider: ProviderGitLab, Owner: "mirror", Repository: "project", Tag: "v1.0.0", CommitSHA: "gitlabsha",
+		}, *ctx)
+	})
+
+	t.Run("overrides apply outside CI", func(t *testing.T) {
+		ctx := Resolve(getenvOf(map[string]string{envBranch: "feature", "GITHUB_PR_NUMBER": "12"}))
+		assert.Equal(t, Context{PullRequest: 12, Branch: "feature"}, *ctx)
+	})
+}
+
+func TestRepositoryFromGitURL(t *testing.T) {
+	tests := []struct {
+		gitURL, owner, repo string
+	}{
+		{"https://github.com/owner/repo.git", "owner", "repo"},
+		{"https://github.com/owner/repo", "owner", "repo"},
+		{"git@github.com:owner/repo.git", "owner", "repo"},
+		{"ssh://git@github.com/owner/repo.git", "owner", "repo"},
+		{"https://gitlab.com/owner/repo.git", "", ""},
+		{"https://github.com/owner", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		owner, repo := repositoryFromGitURL(tt.gitURL)
+		assert.Equal(t, tt.owner, owner, tt.gitURL)
+		assert.Equal(t, tt.repo, repo, tt.gitURL)
+	}
+}
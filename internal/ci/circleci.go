@@ -0,0 +1,42 @@
+package ci
+
+import "strings"
+
+// circleCI detects CircleCI jobs
+type circleCI struct{}
+
+// Name returns the provider name
+func (circleCI) Name() string {
+	return ProviderCircleCI
+}
+
+// Detect reports whether the variables describe a CircleCI job
+func (circleCI) Detect(getenv func(string) string) bool {
+	return getenv("CIRCLECI") == "true"
+}
+
+// Resolve derives the context from the built-in environment variables. CircleCI does not
+// report the branch a pull request targets, so BaseBranch stays empty.
+func (circleCI) Resolve(getenv func(string) string) *Context {
+	ctx := &Context{
+		Owner:      getenv("CIRCLE_PROJECT_USERNAME"),
+		Repository: getenv("CIRCLE_PROJECT_REPONAME"),
+		CommitSHA:  getenv("CIRCLE_SHA1"),
+		BuildURL:   getenv("CIRCLE_BUILD_URL"),
+	}
+	if tag := getenv("CIRCLE_TAG"); tag != "" {
+		ctx.Tag = tag
+		return ctx
+	}
+	ctx.Branch = getenv("CIRCLE_BRANCH")
+
+	// CIRCLE_PR_NUMBER is only set for pull requests from forks; the others are known from
+	// the pull request URL, https://github.com/owner/repo/pull/42
+	if number := pullRequestNumber(getenv("CIRCLE_PR_NUMBER")); number > 0 {
+		ctx.PullRequest = number
+		ctx.Fork = true
+	} else if pullURL := getenv("CIRCLE_PULL_REQUEST"); pullURL != "" {
+		ctx.PullRequest = pullRequestNumber(pullURL[strings.LastIndex(pullURL, "/")+1:])
+	}
+	return ctx
+}
@@ -0,0 +1,66 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCircleCI(t *testing.T) {
+	job := map[string]string{
+		"CIRCLECI": "true", "CIRCLE_PROJECT_USERNAME": "owner", "CIRCLE_PROJECT_REPONAME": "repo",
+		"CIRCLE_SHA1": "circlesha", "CIRCLE_BUILD_URL": "https://circleci.com/gh/owner/repo/5",
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Context
+	}{
+		{
+			name: "branch",
+			env:  map[string]string{"CIRCLE_BRANCH": "main"},
+			expected: Context{
+				Provider: ProviderCircleCI, Owner: "owner", Repository: "repo", Branch: "main",
+				CommitSHA: "circlesha", BuildURL: "https://circleci.com/gh/owner/repo/5",
+			},
+		},
+		{
+			name: "pull request",
+			env:  map[string]string{"CIRCLE_BRANCH": "feature", "CIRCLE_PULL_REQUEST": "https://github.com/owner/repo/pull/42"},
+			expected: Context{
+				Provider: ProviderCircleCI, Owner: "owner", Repository: "repo", PullRequest: 42, Branch: "feature",
+				CommitSHA: "circlesha", BuildURL: "https://circleci.com/gh/owner/repo/5",
+			},
+		},
+		{
+			name: "pull request from a fork",
+			env:  map[string]string{"CIRCLE_BRANCH": "pull/7", "CIRCLE_PR_NUMBER": "7"},
+			expected: Context{
+				Provider: ProviderCircleCI, Owner: "owner", Repository: "repo", PullRequest: 7, Branch: "pull/7",
+				CommitSHA: "circlesha", Fork: true, BuildURL: "https://circleci.com/gh/owner/repo/5",
+			},
+		},
+		{
+			name: "tag",
+			env:  map[string]string{"CIRCLE_TAG": "v1.2.0"},
+			expected: Context{
+				Provider: ProviderCircleCI, Owner: "owner", Repository: "repo", Tag: "v1.2.0",
+				CommitSHA: "circlesha", BuildURL: "https://circleci.com/gh/owner/repo/5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range job {
+				env[key] = value
+			}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			assert.Equal(t, tt.expected, *Resolve(getenvOf(env)))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package ci
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Workflow events with their own rules for deriving the pull request context
+const (
+	EventPush              = "push"
+	EventPullRequest       = "pull_request"
+	EventPullRequestTarget = "pull_request_target"
+	EventWorkflowRun       = "workflow_run"
+	EventMergeGroup        = "merge_group"
+)
+
+// mergeQueueBranchPrefix starts the temporary branches a merge queue tests, named
+// gh-readonly-queue/<base branch>/pr-<number>-<sha>
+const mergeQueueBranchPrefix = "gh-readonly-queue/"
+
+// eventPayload holds the parts of the event payloads that describe the context
+type eventPayload struct {
+	Number      int            `json:"number"`
+	HeadCommit  *payloadCommit `json:"head_commit"`
+	PullRequest *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Head struct {
+			Ref  string `json:"ref"`
+			SHA  string `json:"sha"`
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+		Base struct {
+			Ref  string `json:"ref"`
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	WorkflowRun *struct {
+		HeadBranch     string         `json:"head_branch"`
+		HeadSHA        string         `json:"head_sha"`
+		HeadCommit     *payloadCommit `json:"head_commit"`
+		HeadRepository *struct {
+			FullName string `json:"full_name"`
+		} `json:"head_repository"`
+		Repository *struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		PullRequests []struct {
+			Number int `json:"number"`
+			Base   struct {
+				Ref string `json:"ref"`
+			} `json:"base"`
+		} `json:"pull_requests"`
+	} `json:"workflow_run"`
+	MergeGroup *struct {
+		HeadSHA    string         `json:"head_sha"`
+		HeadRef    string         `json:"head_ref"`
+		BaseRef    string         `json:"base_ref"`
+		HeadCommit *payloadCommit `json:"head_commit"`
+	} `json:"merge_group"`
+}
+
+// payloadCommit is a commit in an event payload
+type payloadCommit struct {
+	Message string `json:"message"`
+}
+
+// githubActions detects GitHub Actions runs
+type githubActions struct{}
+
+// Name returns the provider name
+func (githubActions) Name() string {
+	return ProviderGitHubActions
+}
+
+// Detect reports whether the process runs in a GitHub Actions workflow
+func (githubActions) Detect(getenv func(string) string) bool {
+	return getenv("GITHUB_ACTIONS") == "true" || getenv("GITHUB_EVENT_NAME") != ""
+}
+
+// Resolve derives the context of the run from the GitHub Actions environment and the event
+// payload at GITHUB_EVENT_PATH. An unreadable payload is ignored in favor of the environment.
+func (githubActions) Resolve(getenv func(string) string) *Context {
+	ctx := &Context{
+		Event:     getenv("GITHUB_EVENT_NAME"),
+		Owner:     getenv("GITHUB_REPOSITORY_OWNER"),
+		CommitSHA: getenv("GITHUB_SHA"),
+	}
+	if _, repo, ok := strings.Cut(getenv("GITHUB_REPOSITORY"), "/"); ok && !strings.Contains(repo, "/") {
+		ctx.Repository = repo
+	}
+
+	ref, refName := getenv("GITHUB_REF"), getenv("GITHUB_REF_NAME")
+	switch {
+	case strings.HasPrefix(ref, "refs/pull/"):
+		// refs/pull/<number>/merge is checked out for pull request events; its ref name
+		// "<number>/merge" is not a branch
+		number, _, _ := strings.Cut(strings.TrimPrefix(ref, "refs/pull/"), "/")
+		ctx.PullRequest, _ = strconv.Atoi(number)
+	case strings.HasPrefix(ref, "refs/tags/"):
+		ctx.Tag = strings.TrimPrefix(ref, "refs/tags/")
+	case getenv("GITHUB_REF_TYPE") == "tag":
+		ctx.Tag = refName
+	case refName != "":
+		ctx.Branch = refName
+	default:
+		ctx.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	}
+
+	if rest, ok := strings.CutPrefix(ctx.Branch, mergeQueueBranchPrefix); ok {
+		if base, _, found := strings.Cut(rest, "/pr-"); found {
+			ctx.BaseBranch = base
+		}
+	}
+
+	if headRef := getenv("GITHUB_HEAD_REF"); headRef != "" {
+		ctx.Branch = headRef
+		ctx.BaseBranch = getenv("GITHUB_BASE_REF")
+	}
+
+	if payload := readEventPayload(getenv("GITHUB_EVENT_PATH")); payload != nil {
+		ctx.applyPayload(payload)
+	}
+	return ctx
+}
+
+// applyPayload refines the context with the pull request or triggering run of the event
+func (c *Context) applyPayload(payload *eventPayload) {
+	if payload.HeadCommit != nil {
+		c.Message = payload.HeadCommit.Message
+	}
+	if pr := payload.PullRequest; pr != nil {
+		c.PullRequest = pr.Number
+		c.Message = pr.Title
+		for _, label := range pr.Labels {
+			c.Labels = append(c.Labels, label.Name)
+		}
+		if c.PullRequest == 0 {
+			c.PullRequest = payload.Number
+		}
+		if pr.Head.Ref != "" {
+			c.Branch = pr.Head.Ref
+		}
+		if pr.Base.Ref != "" {
+			c.BaseBranch = pr.Base.Ref
+		}
+		if pr.Head.SHA != "" {
+			c.CommitSHA = pr.Head.SHA
+		}
+		if pr.Base.Repo != nil {
+			c.Fork = pr.Head.Repo == nil || !strings.EqualFold(pr.Head.Repo.FullName, pr.Base.Repo.FullName)
+		}
+		return
+	}
+
+	if group := payload.MergeGroup; group != nil && c.Event == EventMergeGroup {
+		if group.HeadRef != "" {
+			c.Branch = strings.TrimPrefix(group.HeadRef, "refs/heads/")
+		}
+		if group.BaseRef != "" {
+			c.BaseBranch = strings.TrimPrefix(group.BaseRef, "refs/heads/")
+		}
+		if group.HeadSHA != "" {
+			c.CommitSHA = group.HeadSHA
+		}
+		if group.HeadCommit != nil {
+			c.Message = group.HeadCommit.Message
+		}
+		return
+	}
+
+	if run := payload.WorkflowRun; run != nil && c.Event == EventWorkflowRun {
+		c.Branch = run.HeadBranch
+		c.Tag = ""
+		if run.HeadSHA != "" {
+			c.CommitSHA = run.HeadSHA
+		}
+		if run.HeadCommit != nil {
+			c.Message = run.HeadCommit.Message
+		}
+		// GitHub lists pull requests of the triggering run only when they come from the same
+		// repository; fork runs have to be matched by other means
+		if len(run.PullRequests) > 0 {
+			c.PullRequest = run.PullRequests[0].Number
+			c.BaseBranch = run.PullRequests[0].Base.Ref
+		}
+		if run.HeadRepository != nil && run.Repository != nil {
+			c.Fork = !strings.EqualFold(run.HeadRepository.FullName, run.Repository.FullName)
+		}
+	}
+}
+
+// readEventPayload parses the event payload, returning nil when there is none or it cannot be read
+func readEventPayload(eventPath string) *eventPayload {
+	if eventPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(eventPath) //nolint:gosec // eventPath is GITHUB_EVENT_PATH, provided by the runner
+	if err != nil {
+		return nil
+	}
+	var payload eventPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+	return &payload
+}
@@ -0,0 +1,207 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pullRequestPayload = `{"number":42,"pull_request":{"number":42,"title":"Fix login [hotfix]",
+		"labels":[{"name":"bug"},{"name":"coverage:strict"}],
+		"head":{"ref":"feature","sha":"headsha","repo":{"full_name":"owner/repo"}},
+		"base":{"ref":"main","repo":{"full_name":"owner/repo"}}}}`
+	forkPullRequestPayload = `{"number":7,"pull_request":{"number":7,
+		"head":{"ref":"patch-1","sha":"forksha","repo":{"full_name":"contributor/repo"}},
+		"base":{"ref":"main","repo":{"full_name":"owner/repo"}}}}`
+	workflowRunPayload = `{"workflow_run":{"head_branch":"feature","head_sha":"runsha",
+		"head_repository":{"full_name":"owner/repo"},"repository":{"full_name":"owner/repo"},
+		"pull_requests":[{"number":42,"base":{"ref":"main"}}]}}`
+	pushPayload       = `{"head_commit":{"message":"Roll back the deploy [hotfix]"}}`
+	mergeGroupPayload = `{"merge_group":{"head_sha":"mergesha","head_ref":"refs/heads/gh-readonly-queue/main/pr-42-basesha",
+		"base_sha":"basesha","base_ref":"refs/heads/main"}}`
+	forkWorkflowRunPayload = `{"workflow_run":{"head_branch":"patch-1","head_sha":"forksha",
+		"head_repository":{"full_name":"contributor/repo"},"repository":{"full_name":"owner/repo"},
+		"pull_requests":[]}}`
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	writePayload := func(name, payload string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(payload), 0o600))
+		return path
+	}
+	repository := map[string]string{"GITHUB_REPOSITORY": "owner/repo", "GITHUB_REPOSITORY_OWNER": "owner"}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Context
+	}{
+		{
+			name:     "local run",
+			env:      map[string]string{},
+			expected: Context{},
+		},
+		{
+			name: "push to a branch",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPush, "GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main",
+				"GITHUB_REF_TYPE": "branch", "GITHUB_SHA": "pushsha",
+			},
+			expected: Context{Provider: ProviderGitHubActions, Event: EventPush, Owner: "owner", Repository: "repo", Branch: "main", CommitSHA: "pushsha"},
+		},
+		{
+			name: "push reports the head commit message",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPush, "GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main",
+				"GITHUB_REF_TYPE": "branch", "GITHUB_SHA": "pushsha", "GITHUB_EVENT_PATH": writePayload("push.json", pushPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventPush, Owner: "owner", Repository: "repo", Branch: "main",
+				CommitSHA: "pushsha", Message: "Roll back the deploy [hotfix]",
+			},
+		},
+		{
+			name: "push of a tag",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPush, "GITHUB_REF": "refs/tags/v1.2.0", "GITHUB_REF_NAME": "v1.2.0",
+				"GITHUB_REF_TYPE": "tag", "GITHUB_SHA": "tagsha",
+			},
+			expected: Context{Provider: ProviderGitHubActions, Event: EventPush, Owner: "owner", Repository: "repo", Tag: "v1.2.0", CommitSHA: "tagsha"},
+		},
+		{
+			name: "pull request uses the head commit, not the merge commit",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPullRequest, "GITHUB_REF": "refs/pull/42/merge", "GITHUB_REF_NAME": "42/merge",
+				"GITHUB_HEAD_REF": "feature", "GITHUB_BASE_REF": "main", "GITHUB_SHA": "mergesha",
+				"GITHUB_EVENT_PATH": writePayload("pull_request.json", pullRequestPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventPullRequest, Owner: "owner", Repository: "repo", PullRequest: 42,
+				Branch: "feature", BaseBranch: "main", CommitSHA: "headsha", Message: "Fix login [hotfix]",
+				Labels: []string{"bug", "coverage:strict"},
+			},
+		},
+		{
+			name: "pull request without a payload",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPullRequest, "GITHUB_REF": "refs/pull/42/merge", "GITHUB_REF_NAME": "42/merge",
+				"GITHUB_HEAD_REF": "feature", "GITHUB_BASE_REF": "main", "GITHUB_SHA": "mergesha",
+				"GITHUB_EVENT_PATH": filepath.Join(dir, "missing.json"),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventPullRequest, Owner: "owner", Repository: "repo", PullRequest: 42,
+				Branch: "feature", BaseBranch: "main", CommitSHA: "mergesha",
+			},
+		},
+		{
+			name: "pull_request_target from a fork",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPullRequestTarget, "GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main",
+				"GITHUB_SHA": "basesha", "GITHUB_EVENT_PATH": writePayload("fork.json", forkPullRequestPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventPullRequestTarget, Owner: "owner", Repository: "repo", PullRequest: 7,
+				Branch: "patch-1", BaseBranch: "main", CommitSHA: "forksha", Fork: true,
+			},
+		},
+		{
+			name: "workflow_run of a pull request",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventWorkflowRun, "GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main",
+				"GITHUB_SHA": "defaultsha", "GITHUB_EVENT_PATH": writePayload("workflow_run.json", workflowRunPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventWorkflowRun, Owner: "owner", Repository: "repo", PullRequest: 42,
+				Branch: "feature", BaseBranch: "main", CommitSHA: "runsha",
+			},
+		},
+		{
+			name: "workflow_run of a fork pull request",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventWorkflowRun, "GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main",
+				"GITHUB_SHA": "defaultsha", "GITHUB_EVENT_PATH": writePayload("fork_run.json", forkWorkflowRunPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventWorkflowRun, Owner: "owner", Repository: "repo",
+				Branch: "patch-1", CommitSHA: "forksha", Fork: true,
+			},
+		},
+		{
+			name: "merge group tests the queue's merge commit",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventMergeGroup, "GITHUB_REF": "refs/heads/gh-readonly-queue/main/pr-42-basesha",
+				"GITHUB_REF_NAME": "gh-readonly-queue/main/pr-42-basesha", "GITHUB_SHA": "runnersha",
+				"GITHUB_EVENT_PATH": writePayload("merge_group.json", mergeGroupPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventMergeGroup, Owner: "owner", Repository: "repo",
+				Branch: "gh-readonly-queue/main/pr-42-basesha", BaseBranch: "main", CommitSHA: "mergesha",
+			},
+		},
+		{
+			name: "merge group without a payload",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventMergeGroup, "GITHUB_REF": "refs/heads/gh-readonly-queue/release/1.x/pr-7-abc",
+				"GITHUB_REF_NAME": "gh-readonly-queue/release/1.x/pr-7-abc", "GITHUB_SHA": "mergesha",
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventMergeGroup, Owner: "owner", Repository: "repo",
+				Branch: "gh-readonly-queue/release/1.x/pr-7-abc", BaseBranch: "release/1.x", CommitSHA: "mergesha",
+			},
+		},
+		{
+			name: "explicit pull request number wins",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventWorkflowRun, "GITHUB_PR_NUMBER": "99",
+				"GITHUB_EVENT_PATH": writePayload("workflow_run_override.json", workflowRunPayload),
+			},
+			expected: Context{
+				Provider: ProviderGitHubActions, Event: EventWorkflowRun, Owner: "owner", Repository: "repo", PullRequest: 99,
+				Branch: "feature", BaseBranch: "main", CommitSHA: "runsha",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			if tt.name != "local run" {
+				for key, value := range repository {
+					env[key] = value
+				}
+			}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+
+			ctx := Resolve(func(key string) string { return env[key] })
+			assert.Equal(t, tt.expected, *ctx)
+			assert.Equal(t, tt.expected.PullRequest > 0, ctx.IsPullRequest())
+			assert.Equal(t, tt.expected.Event == EventMergeGroup, ctx.IsMergeGroup())
+		})
+	}
+}
+
+func TestResolveRepository(t *testing.T) {
+	tests := []struct {
+		repository, owner           string
+		expectedOwner, expectedRepo string
+	}{
+		{"owner/repo", "", "", "repo"},
+		{"owner/repo", "explicit", "explicit", "repo"},
+		{"invalid-format", "", "", ""},
+		{"a/b/c", "", "", ""},
+	}
+	for _, tt := range tests {
+		env := map[string]string{"GITHUB_REPOSITORY": tt.repository, "GITHUB_REPOSITORY_OWNER": tt.owner}
+		ctx := Resolve(func(key string) string { return env[key] })
+		assert.Equal(t, tt.expectedOwner, ctx.Owner, tt.repository)
+		assert.Equal(t, tt.expectedRepo, ctx.Repository, tt.repository)
+	}
+}
@@ -0,0 +1,51 @@
+package ci
+
+// gitLab detects GitLab CI pipelines
+type gitLab struct{}
+
+// Name returns the provider name
+func (gitLab) Name() string {
+	return ProviderGitLab
+}
+
+// Detect reports whether the variables describe a GitLab CI job
+func (gitLab) Detect(getenv func(string) string) bool {
+	return getenv("GITLAB_CI") == "true"
+}
+
+// Resolve derives the context from the predefined CI/CD variables. Merge request pipelines
+// report the merge request as the pull request, with its source and target branches.
+func (gitLab) Resolve(getenv func(string) string) *Context {
+	ctx := &Context{
+		Owner:      getenv("CI_PROJECT_NAMESPACE"),
+		Repository: getenv("CI_PROJECT_NAME"),
+		CommitSHA:  getenv("CI_COMMIT_SHA"),
+		BuildURL:   getenv("CI_PIPELINE_URL"),
+		Message:    getenv("CI_COMMIT_MESSAGE"),
+	}
+
+	if number := pullRequestNumber(getenv("CI_MERGE_REQUEST_IID")); number > 0 {
+		ctx.PullRequest = number
+		ctx.Branch = getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+		ctx.BaseBranch = getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME")
+		if title := getenv("CI_MERGE_REQUEST_TITLE"); title != "" {
+			ctx.Message = title
+		}
+		// Merged results pipelines check out a merge commit; the source branch head is under test
+		if sha := getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_SHA"); sha != "" {
+			ctx.CommitSHA = sha
+		}
+		source, target := getenv("CI_MERGE_REQUEST_SOURCE_PROJECT_ID"), getenv("CI_MERGE_REQUEST_PROJECT_ID")
+		ctx.Fork = source != "" && target != "" && source != target
+		return ctx
+	}
+	if tag := getenv("CI_COMMIT_TAG"); tag != "" {
+		ctx.Tag = tag
+		return ctx
+	}
+	ctx.Branch = getenv("CI_COMMIT_BRANCH")
+	if ctx.Branch == "" {
+		ctx.Branch = getenv("CI_COMMIT_REF_NAME")
+	}
+	return ctx
+}
@@ -0,0 +1,64 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveGitLab(t *testing.T) {
+	job := map[string]string{
+		"GITLAB_CI": "true", "CI_PROJECT_NAMESPACE": "group", "CI_PROJECT_NAME": "project",
+		"CI_COMMIT_SHA": "gitlabsha", "CI_PIPELINE_URL": "https://gitlab.com/group/project/-/pipelines/9",
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Context
+	}{
+		{
+			name: "branch pipeline",
+			env:  map[string]string{"CI_COMMIT_BRANCH": "main", "CI_COMMIT_REF_NAME": "main"},
+			expected: Context{
+				Provider: ProviderGitLab, Owner: "group", Repository: "project", Branch: "main",
+				CommitSHA: "gitlabsha", BuildURL: "https://gitlab.com/group/project/-/pipelines/9",
+			},
+		},
+		{
+			name: "tag pipeline",
+			env:  map[string]string{"CI_COMMIT_TAG": "v1.2.0", "CI_COMMIT_REF_NAME": "v1.2.0"},
+			expected: Context{
+				Provider: ProviderGitLab, Owner: "group", Repository: "project", Tag: "v1.2.0",
+				CommitSHA: "gitlabsha", BuildURL: "https://gitlab.com/group/project/-/pipelines/9",
+			},
+		},
+		{
+			name: "merged results pipeline from a fork",
+			env: map[string]string{
+				"CI_MERGE_REQUEST_IID": "42", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+				"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main", "CI_MERGE_REQUEST_SOURCE_BRANCH_SHA": "headsha",
+				"CI_MERGE_REQUEST_SOURCE_PROJECT_ID": "2", "CI_MERGE_REQUEST_PROJECT_ID": "1",
+				"CI_COMMIT_MESSAGE": "Merge branch 'feature'", "CI_MERGE_REQUEST_TITLE": "Fix login [hotfix]",
+			},
+			expected: Context{
+				Provider: ProviderGitLab, Owner: "group", Repository: "project", PullRequest: 42, Branch: "feature",
+				BaseBranch: "main", CommitSHA: "headsha", Fork: true, BuildURL: "https://gitlab.com/group/project/-/pipelines/9",
+				Message: "Fix login [hotfix]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range job {
+				env[key] = value
+			}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			assert.Equal(t, tt.expected, *Resolve(getenvOf(env)))
+		})
+	}
+}
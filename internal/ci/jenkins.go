@@ -0,0 +1,59 @@
+package ci
+
+import (
+	"strconv"
+	"strings"
+)
+
+// jenkins detects Jenkins builds
+type jenkins struct{}
+
+// Name returns the provider name
+func (jenkins) Name() string {
+	return ProviderJenkins
+}
+
+// Detect reports whether the variables describe a Jenkins build
+func (jenkins) Detect(getenv func(string) string) bool {
+	return getenv("JENKINS_URL") != "" || getenv("BUILD_URL") != ""
+}
+
+// Resolve derives the context from GIT_URL, GIT_COMMIT and GIT_BRANCH of the Git plugin, and
+// CHANGE_ID, CHANGE_BRANCH, CHANGE_TARGET, TAG_NAME and BRANCH_NAME of multibranch pipelines.
+// CHANGE_ID is the pull request number when the GitHub Branch Source plugin builds a pull request.
+func (jenkins) Resolve(getenv func(string) string) *Context {
+	ctx := &Context{BuildURL: getenv("BUILD_URL"), CommitSHA: getenv("GIT_COMMIT")}
+	ctx.Owner, ctx.Repository = repositoryFromGitURL(getenv("GIT_URL"))
+
+	if number, err := strconv.Atoi(getenv("CHANGE_ID")); err == nil && number > 0 {
+		ctx.PullRequest = number
+		ctx.Branch = getenv("CHANGE_BRANCH")
+		ctx.BaseBranch = getenv("CHANGE_TARGET")
+		return ctx
+	}
+	if tag := getenv("TAG_NAME"); tag != "" {
+		ctx.Tag = tag
+		return ctx
+	}
+	ctx.Branch = getenv("BRANCH_NAME")
+	if ctx.Branch == "" {
+		ctx.Branch = jenkinsBranch(getenv("GIT_LOCAL_BRANCH"), getenv("GIT_BRANCH"))
+	}
+	return ctx
+}
+
+// jenkinsBranch returns the branch checked out by the Git plugin, which reports the remote
+// tracking branch in GIT_BRANCH (origin/main) unless a local branch is configured
+func jenkinsBranch(localBranch, gitBranch string) string {
+	if localBranch != "" {
+		return localBranch
+	}
+	branch := strings.TrimPrefix(gitBranch, "refs/remotes/")
+	if rest, ok := strings.CutPrefix(branch, "refs/heads/"); ok {
+		return rest
+	}
+	if _, rest, ok := strings.Cut(branch, "/"); ok {
+		return rest
+	}
+	return branch
+}
@@ -0,0 +1,101 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveJenkins(t *testing.T) {
+	build := map[string]string{
+		"JENKINS_URL": "https://ci.example.com/", "BUILD_URL": "https://ci.example.com/job/repo/12/",
+		"GIT_URL": "https://github.com/owner/repo.git", "GIT_COMMIT": "jenkinssha",
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Context
+	}{
+		{
+			name: "freestyle job",
+			env:  map[string]string{"GIT_BRANCH": "origin/main"},
+			expected: Context{
+				Provider: ProviderJenkins, Owner: "owner", Repository: "repo", Branch: "main", CommitSHA: "jenkinssha",
+				BuildURL: "https://ci.example.com/job/repo/12/",
+			},
+		},
+		{
+			name: "local branch",
+			env:  map[string]string{"GIT_BRANCH": "origin/feature/x", "GIT_LOCAL_BRANCH": "feature/y"},
+			expected: Context{
+				Provider: ProviderJenkins, Owner: "owner", Repository: "repo", Branch: "feature/y", CommitSHA: "jenkinssha",
+				BuildURL: "https://ci.example.com/job/repo/12/",
+			},
+		},
+		{
+			name: "multibranch branch",
+			env:  map[string]string{"BRANCH_NAME": "release/1.x", "GIT_BRANCH": "release/1.x"},
+			expected: Context{
+				Provider: ProviderJenkins, Owner: "owner", Repository: "repo", Branch: "release/1.x", CommitSHA: "jenkinssha",
+				BuildURL: "https://ci.example.com/job/repo/12/",
+			},
+		},
+		{
+			name: "multibranch pull request",
+			env:  map[string]string{"BRANCH_NAME": "PR-42", "CHANGE_ID": "42", "CHANGE_BRANCH": "feature", "CHANGE_TARGET": "main"},
+			expected: Context{
+				Provider: ProviderJenkins, Owner: "owner", Repository: "repo", PullRequest: 42, Branch: "feature", BaseBranch: "main",
+				CommitSHA: "jenkinssha", BuildURL: "https://ci.example.com/job/repo/12/",
+			},
+		},
+		{
+			name: "multibranch tag",
+			env:  map[string]string{"BRANCH_NAME": "v1.2.0", "TAG_NAME": "v1.2.0"},
+			expected: Context{
+				Provider: ProviderJenkins, Owner: "owner", Repository: "repo", Tag: "v1.2.0", CommitSHA: "jenkinssha",
+				BuildURL: "https://ci.example.com/job/repo/12/",
+			},
+		},
+		{
+			name: "github actions take precedence",
+			env: map[string]string{
+				"GITHUB_EVENT_NAME": EventPush, "GITHUB_REPOSITORY": "other/project", "GITHUB_REF_NAME": "develop",
+				"GITHUB_REF_TYPE": "branch", "GITHUB_SHA": "actionssha", "GIT_BRANCH": "origin/main",
+			},
+			expected: Context{Provider: ProviderGitHubActions, Event: EventPush, Repository: "project", Branch: "develop", CommitSHA: "actionssha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range build {
+				env[key] = value
+			}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+
+			ctx := Resolve(func(key string) string { return env[key] })
+			assert.Equal(t, tt.expected, *ctx)
+		})
+	}
+}
+
+func TestJenkinsBranch(t *testing.T) {
+	tests := []struct {
+		localBranch, gitBranch, expected string
+	}{
+		{"", "origin/main", "main"},
+		{"", "origin/feature/login", "feature/login"},
+		{"", "refs/remotes/origin/main", "main"},
+		{"", "refs/heads/main", "main"},
+		{"", "main", "main"},
+		{"develop", "origin/main", "develop"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, jenkinsBranch(tt.localBranch, tt.gitBranch), tt.gitBranch)
+	}
+}
@@ -0,0 +1,116 @@
+// Package codeowners reads the CODEOWNERS file of a repository to find who owns a path
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+)
+
+// ErrNotFound is returned when the repository has no CODEOWNERS file
+var ErrNotFound = errors.New("no CODEOWNERS file found")
+
+// Locations are the paths GitHub reads the CODEOWNERS file from, in order of precedence
+//
+//nolint:gochecknoglobals // read-only lookup order
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule assigns owners to the paths matching a pattern
+type Rule struct {
+	Pattern string
+	Owners  []string // As written: @user, @org/team or an email address
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Rules []Rule
+}
+
+// Load reads the first CODEOWNERS file found under the repository root
+func Load(root string) (*File, error) {
+	for _, location := range Locations {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(location))) //nolint:gosec // fixed locations under the repository root
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", location, err)
+		}
+		file, err := Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return file, nil
+	}
+	return nil, fmt.Errorf("%w in %s", ErrNotFound, root)
+}
+
+// Parse reads CODEOWNERS rules, skipping blank lines and comments
+func Parse(r io.Reader) (*File, error) {
+	file := &File{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		file.Rules = append(file.Rules, Rule{Pattern: fields[0], Owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Owners returns the owners of a path relative to the repository root. As on GitHub, the last
+// matching rule wins, and a rule without owners leaves the path unowned.
+func (f *File) Owners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if match(f.Rules[i].Pattern, path) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Users returns the logins of the owners that are users, leaving out teams and email
+// addresses, which cannot be assigned to issues
+func Users(owners []string) []string {
+	var users []string
+	for _, owner := range owners {
+		if login, ok := strings.CutPrefix(owner, "@"); ok && login != "" && !strings.Contains(login, "/") {
+			users = append(users, login)
+		}
+	}
+	return users
+}
+
+// match reports whether a CODEOWNERS pattern matches a path. A pattern without a slash matches
+// at any depth, a leading slash anchors it to the root, and a pattern naming a directory owns
+// everything below it; a trailing /* owns only the files directly in the directory.
+func match(pattern, path string) bool {
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if !directory && branchmatch.Match(pattern, path) {
+		return true
+	}
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	return !strings.Contains(last, "*") && branchmatch.Match(pattern+"/**", path)
+}
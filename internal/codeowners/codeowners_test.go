@@ -0,0 +1,68 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCodeowners = `# Default owners
+*                  @org/maintainers
+
+*.md               @docs-writer
+/internal/         @alice @org/backend
+internal/parser/   @bob # parser experts
+docs/*             @carol
+**/testdata        @dave
+/internal/legacy/
+`
+
+func TestOwners(t *testing.T) {
+	file, err := Parse(strings.NewReader(testCodeowners))
+	require.NoError(t, err)
+	require.Len(t, file.Rules, 7)
+	assert.Equal(t, []string{"@bob"}, file.Rules[3].Owners)
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/maintainers"}},
+		{"README.md", []string{"@docs-writer"}},
+		{"internal/badge/badge.go", []string{"@alice", "@org/backend"}},
+		{"internal/parser/parser.go", []string{"@bob"}},
+		{"docs/guide.go", []string{"@carol"}},
+		{"docs/api/guide.go", []string{"@org/maintainers"}},
+		{"cmd/testdata/profile.txt", []string{"@dave"}},
+		{"internal/legacy/old.go", []string{}},
+		{"/internal/badge/badge.go", []string{"@alice", "@org/backend"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.owners, file.Owners(tt.path))
+		})
+	}
+}
+
+func TestUsers(t *testing.T) {
+	assert.Equal(t, []string{"alice", "bob"}, Users([]string{"@alice", "@org/backend", "dev@example.com", "@bob", "@"}))
+	assert.Empty(t, Users(nil))
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	_, err := Load(root)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o600))
+
+	file, err := Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@github"}, file.Owners("main.go"))
+}
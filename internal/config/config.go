@@ -3,31 +3,203 @@ package config
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mrz1836/go-coverage/internal/access"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/ci"
+	"github.com/mrz1836/go-coverage/internal/editor"
 	"github.com/mrz1836/go-coverage/internal/envfile"
+	"github.com/mrz1836/go-coverage/internal/history"
+	"github.com/mrz1836/go-coverage/internal/httpclient"
+	"github.com/mrz1836/go-coverage/internal/orgpolicy"
+	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/policy"
+	"github.com/mrz1836/go-coverage/internal/redact"
+	"github.com/mrz1836/go-coverage/internal/retry"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+	"github.com/mrz1836/go-coverage/internal/waivers"
 )
 
 // Static error definitions
 var (
 	ErrInvalidCoverageThreshold = errors.New("coverage threshold must be between 0 and 100")
 	ErrEmptyCoverageInput       = errors.New("coverage input file cannot be empty")
+	ErrInvalidModuleRoot        = errors.New("module root must be a directory within the repository")
+	ErrInvalidModuleThreshold   = errors.New("module threshold must be given as dir=percentage between 0 and 100")
+	ErrInvalidHotspotHits       = errors.New("hotspot hits cannot be negative")
+	ErrInvalidCoverModeCheck    = errors.New("invalid cover mode check")
 	ErrMissingGitHubToken       = errors.New("GitHub token is required for GitHub integration")
 	ErrMissingGitHubOwner       = errors.New("GitHub repository owner is required")
 	ErrMissingGitHubRepo        = errors.New("GitHub repository name is required")
 	ErrInvalidBadgeStyle        = errors.New("invalid badge style")
+	ErrInvalidBadgeChartPoints  = errors.New("badge chart points cannot be negative")
 	ErrInvalidReportTheme       = errors.New("invalid report theme")
 	ErrInvalidRetentionDays     = errors.New("history retention days must be positive")
 	ErrInvalidMaxEntries        = errors.New("history max entries must be positive")
 	ErrEnvFileNotFound          = errors.New("environment configuration file not found")
+	ErrInvalidRetryAttempts     = errors.New("retry max attempts cannot be negative")
+	ErrInvalidRetryMultiplier   = errors.New("retry backoff multiplier must be at least 1")
+	ErrInvalidRetryJitter       = errors.New("retry jitter must be between 0 and 1")
+	ErrInvalidPolicyGrace       = errors.New("policy grace drop cannot be negative and grace level must be between 0 and 100")
+	ErrInvalidPolicyDeclineRuns = errors.New("policy decline runs cannot be negative")
+	ErrInvalidWarmup            = errors.New("warm-up runs and days cannot be negative")
+	ErrInvalidTestTimeGrowth    = errors.New("test time growth ratio cannot be negative")
+	ErrInvalidConfidence        = errors.New("confidence runs cannot be negative and confidence level must be between 0 and 100")
+	ErrInvalidRollupDepth       = errors.New("report rollup depth cannot be negative")
+	ErrInvalidSnapshotRetention = errors.New("report snapshot retention cannot be negative")
+	ErrInvalidBadgeExtra        = errors.New("invalid additional badge")
+	ErrInvalidCommentSignature  = errors.New("comment signature must be a single line without parentheses")
+	ErrInvalidReportPageSize    = errors.New("report page size budget cannot be negative")
+	ErrInvalidNoCodeChanges     = errors.New("invalid no code changes policy")
+	ErrInvalidForkMode          = errors.New("invalid fork mode")
+	ErrInvalidCommentResolve    = errors.New("invalid comment resolve mode")
+	ErrInvalidCommentCelebrate  = errors.New("comment celebrate threshold cannot be negative")
+	ErrInvalidCommentInsights   = errors.New("comment insights count cannot be negative")
+	ErrInvalidAPIBudget         = errors.New("GitHub API budget cannot be negative")
+	ErrInvalidDigestThread      = errors.New("invalid digest thread kind")
+	ErrInvalidRegressionRuns    = errors.New("regression issue runs must be at least 1")
+	ErrInvalidBranchRule        = errors.New("invalid branch rule")
+	ErrInvalidGerritPort        = errors.New("gerrit SSH port must be between 1 and 65535")
+	ErrInvalidPruneMode         = errors.New("invalid history prune mode")
+	ErrInvalidPruneGrace        = errors.New("history prune grace days cannot be negative")
+	ErrInvalidFixtureMode       = errors.New("fixtures cannot be recorded and replayed in the same run")
+	ErrInvalidPublicBadge       = errors.New("invalid public badge settings")
+	ErrInvalidServe             = errors.New("invalid report server settings")
+	ErrUnknownBranchSetting     = errors.New("unknown setting, expected threshold, max_drop, decline_runs, gate, history or badge_label")
+	ErrInvalidTeam              = errors.New("invalid team")
+	ErrUnknownTeamSetting       = errors.New("unknown setting, expected paths or threshold")
+	ErrInvalidCriticalPath      = errors.New("invalid critical path")
+	ErrInvalidNewFileThreshold  = errors.New("new file threshold must be between 0 and 100")
+	ErrInvalidGoal              = errors.New("invalid coverage goal")
+	ErrInvalidDebtWeight        = errors.New("coverage debt critical weight must be at least 1")
+)
+
+// Ways of handling pull requests that change no code (see PolicyConfig.NoCodeChanges)
+const (
+	// NoCodeChangesSuccess posts a short comment and a success status without measuring coverage
+	NoCodeChangesSuccess = "success"
+	// NoCodeChangesComment posts the short comment and leaves the commit without a coverage status
+	NoCodeChangesComment = "comment"
+	// NoCodeChangesFull runs the full coverage analysis as for any other pull request
+	NoCodeChangesFull = "full"
+)
+
+// Ways of handling pull requests from forks, whose token cannot write comments or statuses
+// (see GitHubConfig.ForkMode)
+const (
+	// ForkModeAuto detects fork pull requests from the GitHub Actions event payload
+	ForkModeAuto = "auto"
+	// ForkModeAlways always writes the handoff artifact instead of posting
+	ForkModeAlways = "always"
+	// ForkModeNever always posts directly, as for pull requests from the same repository
+	ForkModeNever = "never"
+)
+
+// Ways of tidying the coverage comment once coverage passes after failing
+// (see GitHubConfig.CommentResolve)
+const (
+	// CommentResolveOff leaves the comment as it is
+	CommentResolveOff = "off"
+	// CommentResolveMark adds a resolved banner to the comment
+	CommentResolveMark = "mark"
+	// CommentResolveMinimize collapses the comment as resolved
+	CommentResolveMinimize = "minimize"
+)
+
+// Kinds of repository thread the monthly digest is posted to (see GitHubConfig.DigestThread)
+const (
+	// DigestThreadOff only prints the digest
+	DigestThreadOff = "off"
+	// DigestThreadDiscussion maintains a GitHub Discussion
+	DigestThreadDiscussion = "discussion"
+	// DigestThreadIssue maintains a pinned issue
+	DigestThreadIssue = "issue"
+)
+
+// Ways of handling -covermode=set profiles when hit count features are enabled
+// (see CoverageConfig.CoverModeCheck)
+const (
+	// CoverModeCheckWarn reports that the hit count features are skipped
+	CoverModeCheckWarn = "warn"
+	// CoverModeCheckFail rejects the profile
+	CoverModeCheckFail = "fail"
+	// CoverModeCheckOff skips the hit count features silently
+	CoverModeCheckOff = "off"
+)
+
+// Ways of pruning the history of branches deleted from the repository (see HistoryConfig.PruneMode)
+const (
+	// PruneModeArchive moves the entries into the archive directory
+	PruneModeArchive = "archive"
+	// PruneModeDelete deletes the entries
+	PruneModeDelete = "delete"
+)
+
+// Public locations of the badge of a private repository (see PublicBadgeConfig.Target)
+const (
+	// PublicBadgeGist updates the files of a gist
+	PublicBadgeGist = "gist"
+	// PublicBadgeRepo commits the files to a public repository
+	PublicBadgeRepo = "repo"
+	// PublicBadgeHTTP uploads the files with HTTP PUT, e.g. to a bucket
+	PublicBadgeHTTP = "http"
+)
+
+// Planning tools progress toward the coverage goal is tracked in (see GoalConfig.Tracker)
+const (
+	// GoalTrackerOff tracks nothing
+	GoalTrackerOff = "off"
+	// GoalTrackerMilestone keeps a repository milestone with the progress in its description
+	GoalTrackerMilestone = "milestone"
+	// GoalTrackerProject keeps a draft issue in a GitHub Project with the progress in its title
+	GoalTrackerProject = "project"
+)
+
+// Additional badges written next to the coverage badge of a branch (see BadgeConfig.Extra)
+const (
+	// BadgeExtraGrade shows the quality grade of the coverage, from A+ to F
+	BadgeExtraGrade = "grade"
+	// BadgeExtraTrend shows the change since the previous run of the branch
+	BadgeExtraTrend = "trend"
+	// BadgeExtraTests shows the passed and failed tests of the test results
+	BadgeExtraTests = "tests"
+)
+
+// Pull request labels overriding the configuration for a run, named after the label prefix
+// (see Config.ApplyLabels)
+const (
+	// LabelSkipComment skips the pull request comment; statuses are still set
+	LabelSkipComment = "skip-comment"
+	// LabelStrict holds the pull request to strict gates: no coverage decrease, no grace period,
+	// no bypass tokens or paths and no override label
+	LabelStrict = "strict"
+	// LabelReportAllFiles lists every changed file in the comment, not only the first 20
+	LabelReportAllFiles = "report-all-files"
+)
+
+// Code hosting providers the comment command reports to (see Config.DetectProvider)
+const (
+	// ProviderGitHub posts pull request comments and commit statuses on GitHub
+	ProviderGitHub = "github"
+	// ProviderBitbucket posts build statuses and pull request comments on Bitbucket Cloud
+	ProviderBitbucket = "bitbucket"
+	// ProviderAzureDevOps publishes the coverage summary, statuses and pull request threads on Azure DevOps
+	ProviderAzureDevOps = "azuredevops"
 )
 
 // isMainBranch checks if a branch name is one of the configured main branches
@@ -39,7 +211,7 @@ func isMainBranch(branchName string) bool {
 
 	branches := strings.Split(mainBranches, ",")
 	for _, branch := range branches {
-		if strings.TrimSpace(branch) == branchName {
+		if branchmatch.Match(strings.TrimSpace(branch), branchName) {
 			return true
 		}
 	}
@@ -51,6 +223,9 @@ func isMainBranch(branchName string) bool {
 type Config struct {
 	// Coverage settings
 	Coverage CoverageConfig `json:"coverage"`
+	// Context of the CI run that the GitHub owner, repository, pull request and commit were
+	// resolved from (nil when the config was not loaded from the environment)
+	CI *ci.Context `json:"ci,omitempty"`
 	// GitHub integration settings
 	GitHub GitHubConfig `json:"github"`
 	// Badge generation settings
@@ -65,6 +240,41 @@ type Config struct {
 	Log LogConfig `json:"log"`
 	// Analytics settings
 	Analytics AnalyticsConfig `json:"analytics"`
+	// Retry and backoff settings for network operations
+	Retry RetryConfig `json:"retry"`
+	// Proxy and TLS settings for outbound HTTP connections
+	Network NetworkConfig `json:"network"`
+	// Secret redaction settings for logs and published artifacts
+	Redaction RedactionConfig `json:"redaction"`
+	// Gating policies applied in addition to the coverage threshold
+	Policy PolicyConfig `json:"policy"`
+	// Coverage files for editor plugins
+	Editor EditorConfig `json:"editor"`
+	// Per-branch-pattern overrides, such as relaxed gates for release branches
+	Branches BranchConfig `json:"branches"`
+	// Per-pull-request overrides by label, such as coverage:strict
+	Labels LabelConfig `json:"labels"`
+	// Sub-dashboards for the directories owned by each team
+	Teams TeamConfig `json:"teams"`
+	// Gerrit code review integration settings
+	Gerrit GerritConfig `json:"gerrit"`
+	// Bitbucket Cloud integration settings
+	Bitbucket BitbucketConfig `json:"bitbucket"`
+	// Azure DevOps integration settings
+	AzureDevOps AzureDevOpsConfig `json:"azure_devops"`
+	// GitHub App server settings (go-coverage app serve)
+	App AppConfig `json:"app"`
+	// Organization policy merged under the local configuration
+	OrgPolicy OrgPolicyConfig `json:"org_policy"`
+	// Badge published publicly for a private repository
+	PublicBadge PublicBadgeConfig `json:"public_badge"`
+	// Protected report server settings (go-coverage serve)
+	Serve ServeConfig `json:"serve"`
+	// Coverage goal and the planning tool its progress is tracked in
+	Goal GoalConfig `json:"goal"`
+
+	// retryBudget is shared by every policy built from this configuration
+	retryBudget *retry.Budget
 }
 
 // CoverageConfig holds coverage analysis settings
@@ -81,10 +291,53 @@ type CoverageConfig struct {
 	ExcludePaths []string `json:"exclude_paths"`
 	// File patterns to exclude
 	ExcludeFiles []string `json:"exclude_files"`
+	// Reasons, owners and expiry dates of exclusions; their patterns are excluded as well
+	Waivers []waivers.Waiver `json:"waivers,omitempty"`
+	// Whether expired waivers fail the coverage check instead of warning
+	FailExpiredWaivers bool `json:"fail_expired_waivers"`
 	// Whether to exclude test files
 	ExcludeTests bool `json:"exclude_tests"`
 	// Whether to exclude generated files
 	ExcludeGenerated bool `json:"exclude_generated"`
+	// Built-in exclusion presets (vendor, third_party, examples, testdata, mocks)
+	ExcludePresets []string `json:"exclude_presets"`
+	// Coverage profiles produced under different build tags, as name=path
+	Variants []string `json:"variants,omitempty"`
+	// LCOV or Cobertura reports of other languages merged into the Go profile, as [language=]path
+	ExtraInputs []string `json:"extra_inputs,omitempty"`
+	// Module path prefixes rewritten in profile paths, as old-prefix=new-prefix
+	ModuleRewrites []string `json:"module_rewrites,omitempty"`
+	// Repository root, detected from git or GITHUB_WORKSPACE when empty
+	RepoRoot string `json:"repo_root,omitempty"`
+	// Directory of the Go module relative to the repository root, empty at the root
+	ModuleRoot string `json:"module_root,omitempty"`
+	// Name or path patterns of trees eligible file discovery skips, such as bazel-*
+	DiscoveryIgnore []string `json:"discovery_ignore"`
+	// Whether to read the modules of go.work and report and gate every module
+	Workspace bool `json:"workspace"`
+	// Thresholds of workspace modules, as dir=percentage; others use the global threshold
+	ModuleThresholds []string `json:"module_thresholds,omitempty"`
+	// Directory name patterns of test helper packages, such as testutil* or mocks
+	TestHelpers []string `json:"test_helpers"`
+	// Whether to leave test helper packages out of the totals, reporting them in an appendix
+	ExcludeTestHelpers bool `json:"exclude_test_helpers"`
+	// Whether to report example programs, Example functions and fuzz targets apart from the totals
+	SeparateExamples bool `json:"separate_examples"`
+	// Hit count from which a block counts as an over-tested hotspot (0 = no hit count analysis)
+	HotspotHits int `json:"hotspot_hits"`
+	// What to do with set mode profiles, which lack the hit counts the hit count features need:
+	// warn, fail or off
+	CoverModeCheck string `json:"cover_mode_check"`
+	// Maximum coverage profile size in MiB (0 = parser default)
+	MaxProfileSizeMB int `json:"max_profile_size_mb"`
+	// Maximum length of a single coverage profile line in bytes (0 = parser default)
+	MaxProfileLineLength int `json:"max_profile_line_length"`
+	// Maximum number of distinct files in a coverage profile (0 = parser default)
+	MaxProfileFiles int `json:"max_profile_files"`
+	// Maximum number of coverage blocks in a coverage profile (0 = parser default)
+	MaxProfileBlocks int `json:"max_profile_blocks"`
+	// Fail the gates of a repository without test files instead of skipping them
+	FailWithoutTests bool `json:"fail_without_tests"`
 }
 
 // GitHubConfig holds GitHub integration settings
@@ -103,8 +356,50 @@ type GitHubConfig struct {
 	PostComments bool `json:"post_comments"`
 	// Whether to create commit statuses
 	CreateStatuses bool `json:"create_statuses"`
+	// Mark coverage status contexts of earlier runs that a run no longer reports as obsolete
+	CleanupStatuses bool `json:"cleanup_statuses"`
+	// Probe the token's permissions before posting and skip what it may not do
+	Preflight bool `json:"preflight"`
 	// API timeout
 	Timeout time.Duration `json:"timeout"`
+	// Fork pull request handling (auto, always or never; empty means auto)
+	ForkMode string `json:"fork_mode"`
+	// Directory receiving the comment handoff artifact in fork safe mode
+	HandoffDir string `json:"handoff_dir"`
+	// Comment tidying once coverage passes after failing (off, mark or minimize; empty means off)
+	CommentResolve string `json:"comment_resolve"`
+	// React 🎉 to the comment when coverage improves by at least this many points (0 disables)
+	CommentCelebrate float64 `json:"comment_celebrate"`
+	// Most actionable insights shown in the comment (0 hides the section)
+	CommentInsights int `json:"comment_insights"`
+	// Embed an image of the package coverage before and after the pull request in the comment
+	CommentDiffImage bool `json:"comment_diff_image"`
+	// List every changed file in the comment, not only the first 20
+	CommentAllFiles bool `json:"comment_all_files"`
+	// Signature identifying the comment; earlier comments are still found after it changes
+	CommentSignature string `json:"comment_signature"`
+	// Heading of the comment
+	CommentHeader string `json:"comment_header"`
+	// Footer replacing the go-coverage credit (empty keeps the credit)
+	CommentFooter string `json:"comment_footer"`
+	// Credit go-coverage in the footer
+	CommentBranding bool `json:"comment_branding"`
+	// Thread updated monthly with the coverage digest (off, discussion or issue; empty means off)
+	DigestThread string `json:"digest_thread"`
+	// Discussion category of the digest thread
+	DigestCategory string `json:"digest_category"`
+	// Title identifying the digest thread
+	DigestTitle string `json:"digest_title"`
+	// Open an issue when coverage of the main branch stays below the threshold or declines for
+	// RegressionIssueRuns runs in a row, and close it once coverage recovers
+	RegressionIssue bool `json:"regression_issue"`
+	// Consecutive runs below the threshold or declining before the regression issue is opened
+	RegressionIssueRuns int `json:"regression_issue_runs"`
+	// Title identifying the regression issue
+	RegressionIssueTitle string `json:"regression_issue_title"`
+	// API requests a run may make before a warning is printed, for installations shared by many
+	// repositories (0 disables the warning)
+	APIBudget int `json:"api_budget"`
 }
 
 // BadgeConfig holds badge generation settings
@@ -121,6 +416,10 @@ type BadgeConfig struct {
 	OutputFile string `json:"output_file"`
 	// Whether to generate trend badge
 	IncludeTrend bool `json:"include_trend"`
+	// Additional branch badges: grade, trend and tests
+	Extra []string `json:"extra,omitempty"`
+	// Runs drawn in the trend chart written next to the badge (0 disables the chart)
+	ChartPoints int `json:"chart_points"`
 	// Max time for all logo fetch attempts
 	LogoTimeout time.Duration `json:"logo_timeout"`
 	// Per-request timeout for logo fetching
@@ -145,6 +444,25 @@ type ReportConfig struct {
 	ShowFiles bool `json:"show_files"`
 	// Whether to show missing lines
 	ShowMissing bool `json:"show_missing"`
+	// Directory levels shown in the dashboard's directory rollup (0 disables it)
+	RollupDepth int `json:"rollup_depth"`
+	// Size budget of a report page in KiB; larger reports are paginated (0 disables pagination)
+	MaxPageKB int `json:"max_page_kb"`
+	// Days the coverage data snapshots of pull requests and branches are kept (0 keeps them all)
+	SnapshotRetentionDays int `json:"snapshot_retention_days"`
+	// Keep the reports of a pull request when it is finalized, leaving them to the snapshot retention
+	KeepClosedPRs bool `json:"keep_closed_prs"`
+	// Create a GitHub Deployment linking the report for each Pages publish (go-coverage deployment)
+	Deployment bool `json:"deployment"`
+	// Environment of the deployments, whose "View deployment" button opens the report
+	DeploymentEnvironment string `json:"deployment_environment"`
+	// Directory with dashboard overrides (dashboard.html and sections/*.html|md)
+	TemplateDir string `json:"template_dir"`
+	// Markdown snippets injected into the dashboard, keyed by position (top, after-metrics, bottom)
+	Sections map[string]string `json:"sections,omitempty"`
+	// Write every output flat into the output directory without network access, for CI
+	// servers that publish the directory as is, such as the Jenkins HTML Publisher
+	Local bool `json:"local"`
 }
 
 // HistoryConfig holds history tracking settings
@@ -161,6 +479,19 @@ type HistoryConfig struct {
 	AutoCleanup bool `json:"auto_cleanup"`
 	// Whether to enable detailed metrics
 	MetricsEnabled bool `json:"metrics_enabled"`
+	// Environment restricts the history shown on the dashboard to runs of one environment, as
+	// comma separated key=value pairs of the recorded environment, such as "os=linux"
+	Environment string `json:"environment,omitempty"`
+	// Suite names the test run history --add records and history reads, such as bench, so its
+	// coverage is tracked apart from the unit tests (empty or "unit" for the unit tests)
+	Suite string `json:"suite,omitempty"`
+	// PruneMode is what history --prune-branches does with the entries of deleted branches:
+	// archive or delete
+	PruneMode string `json:"prune_mode"`
+	// ArchivePath receives the entries of deleted branches in archive mode
+	ArchivePath string `json:"archive_path"`
+	// PruneGraceDays keeps the entries of a deleted branch until its newest entry is this old
+	PruneGraceDays int `json:"prune_grace_days"`
 }
 
 // StorageConfig holds storage settings
@@ -191,6 +522,384 @@ type AnalyticsConfig struct {
 	GoogleAnalyticsID string `json:"google_analytics_id"`
 	// Whether to include branding in reports
 	BrandingEnabled bool `json:"branding_enabled"`
+	// go test -json output or JUnit XML report of the run, for tracking test time against coverage
+	TestResults string `json:"test_results"`
+	// Flag test time growing more than this many times as fast as coverage (0 disables)
+	TestTimeGrowthRatio float64 `json:"test_time_growth_ratio"`
+	// List the files with no coverage that no test package links as dead code candidates on the
+	// dashboard; loads the package graph with go list
+	DeadCode bool `json:"dead_code"`
+	// Title of the issue the dead-code command keeps up to date with --issue
+	DeadCodeIssueTitle string `json:"dead_code_issue_title"`
+	// Record the coverage debt, the uncovered statements weighted by complexity and
+	// criticality, with each run and chart it on the dashboard
+	CoverageDebt bool `json:"coverage_debt"`
+	// Weight of the uncovered statements on critical paths (GO_COVERAGE_POLICY_CRITICAL_PATHS)
+	DebtCriticalWeight float64 `json:"debt_critical_weight"`
+}
+
+// RetryConfig holds retry and backoff settings for network operations
+type RetryConfig struct {
+	// Total attempts per operation, including the first one
+	MaxAttempts int `json:"max_attempts"`
+	// Delay before the first retry
+	InitialDelay time.Duration `json:"initial_delay"`
+	// Upper bound for a single backoff delay
+	MaxDelay time.Duration `json:"max_delay"`
+	// Exponential backoff multiplier
+	Multiplier float64 `json:"multiplier"`
+	// Random jitter fraction applied to each delay (0-1)
+	Jitter float64 `json:"jitter"`
+	// Total retries allowed per run across all operations (0 = unlimited)
+	Budget int `json:"budget"`
+	// Max attempts override for GitHub API calls (0 = use MaxAttempts)
+	GitHubMaxAttempts int `json:"github_max_attempts"`
+	// Max attempts override for artifact downloads (0 = use MaxAttempts)
+	ArtifactDownloadMaxAttempts int `json:"artifact_download_max_attempts"`
+}
+
+// NetworkConfig holds proxy and TLS settings for outbound HTTP connections
+type NetworkConfig struct {
+	// Explicit proxy URL (empty = use HTTP_PROXY/HTTPS_PROXY/NO_PROXY)
+	ProxyURL string `json:"proxy_url"`
+	// Path to a PEM bundle of additional trusted CA certificates
+	CABundle string `json:"ca_bundle"`
+	// Disable TLS certificate verification (insecure, logs a warning)
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+	// Disable all network access (GitHub API, uploads, remote assets)
+	Offline bool `json:"offline"`
+	// Record API responses and input profiles into this fixture directory
+	RecordFixtures string `json:"record_fixtures"`
+	// Replay API responses and input profiles from this fixture directory instead of the network
+	ReplayFixtures string `json:"replay_fixtures"`
+}
+
+// RedactionConfig holds additional patterns scrubbed from logs and published artifacts
+type RedactionConfig struct {
+	// Regular expressions whose matches are replaced (tokens and credentials are always redacted)
+	Patterns []string `json:"patterns"`
+}
+
+// PolicyConfig holds gating policies evaluated alongside the coverage threshold
+type PolicyConfig struct {
+	// Largest allowed coverage decrease versus the previous run in percentage points (negative disables)
+	MaxDrop float64 `json:"max_drop"`
+	// Decrease tolerated while coverage stays at or above GraceAbove
+	GraceDrop float64 `json:"grace_drop"`
+	// Coverage level that unlocks GraceDrop (0 disables the grace period)
+	GraceAbove float64 `json:"grace_above"`
+	// Only fail after coverage declines this many runs in a row (0 fails immediately)
+	DeclineRuns int `json:"decline_runs"`
+	// Gate expression over the computed metrics, e.g. "total >= 80 && patch >= 90 && delta >= -0.5"
+	Gate string `json:"gate"`
+	// How the comment command handles pull requests without Go, test or module changes (success,
+	// comment or full; empty means success). The complete command always runs the full analysis.
+	NoCodeChanges string `json:"no_code_changes"`
+	// Earlier runs the confidence band around coverage is estimated from (0 disables the band)
+	ConfidenceRuns int `json:"confidence_runs"`
+	// Confidence level of the band in percent, e.g. 95
+	ConfidenceLevel float64 `json:"confidence_level"`
+	// Hold the lower bound of the confidence band, rather than coverage, to the threshold
+	LowerBound bool `json:"lower_bound"`
+	// Commit message or pull request title tokens, e.g. [hotfix], that downgrade failed gates to
+	// warnings for the run
+	BypassTokens []string `json:"bypass_tokens"`
+	// Path patterns, e.g. deploy/** (see branchmatch.Match); a change touching only matching files
+	// downgrades failed gates to warnings for the run
+	BypassPaths []string `json:"bypass_paths"`
+	// Path patterns of critical code, e.g. internal/auth/** (see branchmatch.Match), each optionally
+	// followed by =N for a requirement of its own; the files matching a pattern must reach the
+	// requirement regardless of the threshold
+	CriticalPaths []string `json:"critical_paths"`
+	// Coverage the critical paths require unless a pattern sets its own
+	CriticalThreshold float64 `json:"critical_threshold"`
+	// Failed gates are reported as warnings during the first WarmupRuns runs recorded on the main
+	// branch and the first WarmupDays days of its history, until either limit is reached (0 disables
+	// the limit; both 0 disables warm-up)
+	WarmupRuns int `json:"warmup_runs"`
+	WarmupDays int `json:"warmup_days"`
+	// Coverage every file a pull request adds must reach (0 disables the floor)
+	NewFileThreshold float64 `json:"new_file_threshold"`
+}
+
+// WarmupEnabled reports whether gates are reported as warnings while the history is new
+func (p *PolicyConfig) WarmupEnabled() bool {
+	return p.WarmupRuns > 0 || p.WarmupDays > 0
+}
+
+// CriticalPath is a critical path pattern with the coverage its files require
+type CriticalPath struct {
+	Pattern   string
+	Threshold float64
+}
+
+// EditorConfig holds the coverage output consumed by editor plugins for gutter highlighting
+type EditorConfig struct {
+	// Formats to write (lcov, json); empty disables editor output
+	Formats []string `json:"formats"`
+	// Directory the files are written to, normally the repository root
+	Dir string `json:"dir"`
+}
+
+// GerritConfig holds the Gerrit code review integration settings. The change under review is
+// read from the variables set by the Jenkins Gerrit Trigger (GERRIT_CHANGE_NUMBER and friends).
+type GerritConfig struct {
+	// REST API base URL, e.g. https://review.example.com (derived from GERRIT_CHANGE_URL when unset)
+	URL string `json:"url"`
+	// REST API username
+	Username string `json:"username"`
+	// REST API HTTP password
+	Password string `json:"password"`
+	// SSH host used when no REST credentials are configured
+	SSHHost string `json:"ssh_host"`
+	// SSH port
+	SSHPort int `json:"ssh_port"`
+	// SSH user (empty uses the SSH client configuration)
+	SSHUser string `json:"ssh_user"`
+	// SSH private key file (empty uses the SSH client configuration)
+	SSHKey string `json:"ssh_key"`
+	// Project of the change
+	Project string `json:"project"`
+	// Target branch of the change
+	Branch string `json:"branch"`
+	// Change number
+	Change int `json:"change"`
+	// Patchset number
+	Patchset int `json:"patchset"`
+	// Commit SHA of the patchset
+	Revision string `json:"revision"`
+	// Label voted on with the gate result (empty disables voting)
+	Label string `json:"label"`
+	// Vote when every gate passes
+	PassVote int `json:"pass_vote"`
+	// Vote when a gate fails
+	FailVote int `json:"fail_vote"`
+}
+
+// BitbucketConfig holds the Bitbucket Cloud integration settings. The repository, commit and
+// pull request are read from the variables set by Bitbucket Pipelines.
+type BitbucketConfig struct {
+	// Workspace of the repository
+	Workspace string `json:"workspace"`
+	// Repository slug
+	Repository string `json:"repository"`
+	// Commit SHA
+	CommitSHA string `json:"commit_sha"`
+	// Pull request ID (0 if not in a pull request pipeline)
+	PullRequest int `json:"pull_request"`
+	// Branch of the pipeline
+	Branch string `json:"branch"`
+	// Destination branch of the pull request
+	BaseBranch string `json:"base_branch"`
+	// Pipeline build number
+	BuildNumber int `json:"build_number"`
+	// API base URL, for proxies in front of the Bitbucket Cloud API
+	APIURL string `json:"api_url"`
+	// Repository, project or workspace access token
+	Token string `json:"token"`
+	// Username for app password authentication
+	Username string `json:"username"`
+	// App password, used when no access token is set
+	AppPassword string `json:"app_password"`
+	// Key of the build status on the commit
+	StatusKey string `json:"status_key"`
+	// URL of the hosted coverage report, linked from the build status and comment
+	ReportURL string `json:"report_url"`
+}
+
+// AzureDevOpsConfig holds the Azure DevOps integration settings. The project, repository,
+// build and pull request are read from the variables set by Azure Pipelines.
+type AzureDevOpsConfig struct {
+	// Organization URL, e.g. https://dev.azure.com/org/
+	CollectionURI string `json:"collection_uri"`
+	// Team project
+	Project string `json:"project"`
+	// Repository ID
+	RepositoryID string `json:"repository_id"`
+	// Repository name
+	Repository string `json:"repository"`
+	// Repository type of the pipeline, TfsGit for Azure Repos
+	RepositoryProvider string `json:"repository_provider"`
+	// Build ID of the pipeline run
+	BuildID int `json:"build_id"`
+	// Commit SHA
+	CommitSHA string `json:"commit_sha"`
+	// Pull request ID (0 if not in a pull request build)
+	PullRequest int `json:"pull_request"`
+	// Branch of the run: the source branch of a pull request, the built branch otherwise
+	Branch string `json:"branch"`
+	// Target branch of the pull request
+	BaseBranch string `json:"base_branch"`
+	// Job access token of the pipeline
+	AccessToken string `json:"access_token"`
+	// Personal access token, used when no job access token is mapped
+	Token string `json:"token"`
+	// Name of the pull request and commit status
+	StatusName string `json:"status_name"`
+	// URL of the hosted coverage report, linked from the status and thread
+	ReportURL string `json:"report_url"`
+}
+
+// AppConfig holds the settings of the GitHub App server, which enforces coverage for every
+// repository the App is installed on
+type AppConfig struct {
+	// GitHub App ID
+	ID int64 `json:"id"`
+	// PEM encoded private key of the App
+	PrivateKey string `json:"private_key"`
+	// File holding the private key, read when PrivateKey is empty
+	PrivateKeyFile string `json:"private_key_file"`
+	// Secret that signs the webhook deliveries
+	WebhookSecret string `json:"webhook_secret"`
+	// Address the webhook server listens on
+	Addr string `json:"addr"`
+	// Name of the handoff artifact uploaded by the coverage workflows
+	Artifact string `json:"artifact"`
+}
+
+// OrgPolicyConfig locates the signed policy file an organization publishes for all of its
+// repositories. Its settings apply where the local configuration sets none.
+type OrgPolicyConfig struct {
+	// URL of the policy file, e.g. the raw URL of a file in a central repository (empty disables)
+	URL string `json:"url"`
+	// URL of the detached signature (empty = URL + ".sig")
+	SignatureURL string `json:"signature_url"`
+	// Base64 Ed25519 public key the policy is signed with
+	PublicKey string `json:"public_key"`
+	// Directory caching the last verified policy
+	CacheDir string `json:"cache_dir"`
+	// How long a cached policy is used before it is downloaded again
+	CacheTTL time.Duration `json:"cache_ttl"`
+	// Where the policy was loaded from: network, cache or stale cache (empty without a policy)
+	Source string `json:"-"`
+	// Settings taken from the policy, the others being set locally
+	Applied []string `json:"-"`
+}
+
+// PublicBadgeConfig publishes only the badge of a private repository to a public location, a
+// gist, a public repository or a bucket, while the dashboard stays private
+type PublicBadgeConfig struct {
+	// Where the badge is published: gist, repo or http (empty disables)
+	Target string `json:"target"`
+	// Token with write access to the public location only, never the repository token
+	Token string `json:"token"`
+	// Gist updated by the gist target
+	GistID string `json:"gist_id"`
+	// Public repository (owner/name) written by the repo target
+	Repository string `json:"repository"`
+	// Branch of the public repository
+	Branch string `json:"branch"`
+	// Directory of the badge in the public repository
+	Dir string `json:"dir"`
+	// Base URL the http target uploads to with PUT
+	URL string `json:"url"`
+	// Base name of the published files (<name>.svg and <name>.json)
+	Name string `json:"name"`
+}
+
+// ServeConfig holds the settings of the report server, which protects the reports of teams who
+// cannot expose coverage details publicly
+type ServeConfig struct {
+	// Address the server listens on
+	Addr string `json:"addr"`
+	// Base URL the reports are served from, used in report links (empty = GitHub Pages)
+	URL string `json:"url"`
+	// Shared token accepted in the token query parameter or as a bearer token
+	Token string `json:"token"`
+	// Basic auth credentials
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Secret signing time-limited links to pull request reports
+	LinkSecret string `json:"link_secret"`
+	// How long a signed link stays valid
+	LinkTTL time.Duration `json:"link_ttl"`
+	// Serve badges without authentication, so READMEs can show them
+	PublicBadges bool `json:"public_badges"`
+}
+
+// GoalConfig sets a coverage goal for the main branch and where its progress is shown, so the
+// work toward it is visible in the planning tools of the project
+type GoalConfig struct {
+	// Coverage percentage to reach (0 disables the goal)
+	Target float64 `json:"target"`
+	// Coverage when the goal was set, where progress starts at 0%
+	Start float64 `json:"start"`
+	// Date the goal is due, YYYY-MM-DD (optional)
+	Due string `json:"due"`
+	// Where progress is tracked: off, milestone or project
+	Tracker string `json:"tracker"`
+	// Title of the milestone, or start of the title of the project item
+	Title string `json:"title"`
+	// Number of the GitHub Project the item is kept in
+	Project int `json:"project"`
+	// User or organization owning the project (default: the repository owner)
+	ProjectOwner string `json:"project_owner"`
+	// Token with access to the project, which the repository token of GitHub Actions lacks
+	// (default: the GitHub token)
+	Token string `json:"token"`
+}
+
+// TeamConfig routes the coverage of the directories a team owns to a dashboard of its own,
+// published below the report and linked from the root dashboard
+type TeamConfig struct {
+	// Teams and the directories they own
+	Teams []Team `json:"teams,omitempty"`
+	// Directory of the team dashboards, relative to the report directory
+	Dir string `json:"dir"`
+}
+
+// Team is a group of directories whose coverage gets its own dashboard, badge and threshold
+type Team struct {
+	// Name of the team, also the directory of its dashboard
+	Name string `json:"name"`
+	// Directories owned by the team, relative to the repository root
+	Paths []string `json:"paths"`
+	// Minimum coverage of the team's files (nil keeps the global threshold)
+	Threshold *float64 `json:"threshold,omitempty"`
+}
+
+// LabelConfig holds the pull request labels that give reviewers control over a single run
+// without editing workflows
+type LabelConfig struct {
+	// Whether pull request labels override the configuration
+	Enabled bool `json:"enabled"`
+	// Prefix of the override labels, e.g. coverage: for coverage:strict
+	Prefix string `json:"prefix"`
+	// Labels of the pull request, for CI providers whose context does not report them
+	PullRequest []string `json:"pull_request,omitempty"`
+	// Overrides applied to this run, without the prefix
+	Applied []string `json:"applied,omitempty"`
+}
+
+// BranchConfig holds the settings overridden for branches matching a pattern
+type BranchConfig struct {
+	// Overrides by branch pattern; the first rule matching the target branch applies
+	Rules []BranchRule `json:"rules,omitempty"`
+	// Branch the rules were matched against: the base branch of a pull request or merge
+	// queue run, the branch of the run otherwise
+	Target string `json:"target,omitempty"`
+	// Rule applied to this run (nil when none matched)
+	Applied *BranchRule `json:"applied,omitempty"`
+}
+
+// BranchRule overrides gates, the history namespace and the badge label for the branches
+// matching Pattern, so that for example backports to release/* are not held to the gates
+// tuned for main. Unset fields keep the global setting.
+type BranchRule struct {
+	// Branch pattern, e.g. release/* or hotfix/** (see branchmatch.Match)
+	Pattern string `json:"pattern"`
+	// Minimum coverage threshold
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Largest allowed coverage decrease in percentage points (negative disables)
+	MaxDrop *float64 `json:"max_drop,omitempty"`
+	// Declining runs in a row before failing (0 fails immediately)
+	DeclineRuns *int `json:"decline_runs,omitempty"`
+	// Gate expression (empty disables the global gate)
+	Gate *string `json:"gate,omitempty"`
+	// Subdirectory of the history storage path holding the history of these branches
+	HistoryNamespace string `json:"history_namespace,omitempty"`
+	// Badge label
+	BadgeLabel string `json:"badge_label,omitempty"`
 }
 
 // findEnvDir looks for the modular .github/env/ directory by walking up from the
@@ -286,7 +995,7 @@ func findCustomEnvFile(basePath string) string {
 
 // isCI returns true when running in a CI environment.
 func isCI() bool {
-	return os.Getenv("CI") == "true"
+	return ci.IsCI(os.Getenv)
 }
 
 // Load loads configuration from environment variables with defaults.
@@ -315,26 +1024,93 @@ func Load() (*Config, error) {
 		// If no env files found at all, continue silently (backward compatible)
 	}
 
+	// The organization policy fills in what the environment and the env files leave unset
+	orgPolicy, err := loadOrgPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ci.ValidateProvider(os.Getenv(ci.EnvProvider)); err != nil {
+		return nil, err
+	}
+	ciContext := ci.FromEnv()
+
+	branchRules, err := parseBranchRules(os.Getenv("GO_COVERAGE_BRANCH_RULES"))
+	if err != nil {
+		return nil, err
+	}
+	teams, err := parseTeams(os.Getenv("GO_COVERAGE_TEAMS"))
+	if err != nil {
+		return nil, err
+	}
+	coverageWaivers, err := waivers.Parse(os.Getenv("GO_COVERAGE_WAIVERS"))
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
+		CI: ciContext,
 		Coverage: CoverageConfig{
-			InputFile:          getEnvString("GO_COVERAGE_INPUT_FILE", "coverage.txt"),
-			OutputDir:          getEnvString("GO_COVERAGE_OUTPUT_DIR", "coverage"),
-			Threshold:          getEnvFloat("GO_COVERAGE_THRESHOLD", 80.0),
-			AllowLabelOverride: getEnvBool("GO_COVERAGE_ALLOW_LABEL_OVERRIDE", false),
-			ExcludePaths:       getEnvStringSlice("GO_COVERAGE_EXCLUDE_PATHS", []string{"vendor/", "test/", "testdata/"}),
-			ExcludeFiles:       getEnvStringSlice("GO_COVERAGE_EXCLUDE_FILES", []string{"*_test.go", "*.pb.go"}),
-			ExcludeTests:       getEnvBool("GO_COVERAGE_EXCLUDE_TESTS", true),
-			ExcludeGenerated:   getEnvBool("GO_COVERAGE_EXCLUDE_GENERATED", true),
+			InputFile:            getEnvString("GO_COVERAGE_INPUT_FILE", "coverage.txt"),
+			OutputDir:            getEnvString("GO_COVERAGE_OUTPUT_DIR", "coverage"),
+			Threshold:            getEnvFloat("GO_COVERAGE_THRESHOLD", 80.0),
+			AllowLabelOverride:   getEnvBool("GO_COVERAGE_ALLOW_LABEL_OVERRIDE", false),
+			ExcludePaths:         getEnvStringSlice("GO_COVERAGE_EXCLUDE_PATHS", DefaultExcludePaths()),
+			ExcludeFiles:         getEnvStringSlice("GO_COVERAGE_EXCLUDE_FILES", DefaultExcludeFiles()),
+			Waivers:              coverageWaivers,
+			FailExpiredWaivers:   getEnvBool("GO_COVERAGE_FAIL_EXPIRED_WAIVERS", false),
+			ExcludeTests:         getEnvBool("GO_COVERAGE_EXCLUDE_TESTS", true),
+			ExcludeGenerated:     getEnvBool("GO_COVERAGE_EXCLUDE_GENERATED", true),
+			ExcludePresets:       getExclusionPresets(),
+			Variants:             getEnvStringSlice("GO_COVERAGE_VARIANTS", nil),
+			ExtraInputs:          getEnvStringSlice("GO_COVERAGE_EXTRA_INPUTS", nil),
+			ModuleRewrites:       getEnvStringSlice("GO_COVERAGE_MODULE_REWRITES", nil),
+			RepoRoot:             getEnvString("GO_COVERAGE_REPO_ROOT", ""),
+			ModuleRoot:           getModuleRoot(),
+			DiscoveryIgnore:      getDiscoveryIgnore(),
+			Workspace:            getEnvBool("GO_COVERAGE_WORKSPACE", false),
+			ModuleThresholds:     getEnvStringSlice("GO_COVERAGE_MODULE_THRESHOLDS", nil),
+			TestHelpers:          getTestHelpers(),
+			ExcludeTestHelpers:   getEnvBool("GO_COVERAGE_EXCLUDE_TEST_HELPERS", false),
+			SeparateExamples:     getEnvBool("GO_COVERAGE_SEPARATE_EXAMPLES", false),
+			HotspotHits:          getEnvInt("GO_COVERAGE_HOTSPOT_HITS", parser.DefaultHotspotHits),
+			CoverModeCheck:       strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_COVERMODE_CHECK", CoverModeCheckWarn))),
+			MaxProfileSizeMB:     getEnvInt("GO_COVERAGE_MAX_PROFILE_SIZE_MB", 512),
+			MaxProfileLineLength: getEnvInt("GO_COVERAGE_MAX_PROFILE_LINE_LENGTH", 64*1024),
+			MaxProfileFiles:      getEnvInt("GO_COVERAGE_MAX_PROFILE_FILES", 100000),
+			MaxProfileBlocks:     getEnvInt("GO_COVERAGE_MAX_PROFILE_BLOCKS", 10000000),
+			FailWithoutTests:     getEnvBool("GO_COVERAGE_FAIL_WITHOUT_TESTS", false),
 		},
 		GitHub: GitHubConfig{
-			Token:          getEnvString("GITHUB_TOKEN", ""),
-			Owner:          getEnvString("GITHUB_REPOSITORY_OWNER", ""),
-			Repository:     getRepositoryFromEnv(),
-			PullRequest:    getEnvInt("GITHUB_PR_NUMBER", 0),
-			CommitSHA:      getEnvString("GITHUB_SHA", ""),
-			PostComments:   getEnvBool("GO_COVERAGE_POST_COMMENTS", true),
-			CreateStatuses: getEnvBool("GO_COVERAGE_CREATE_STATUSES", true),
-			Timeout:        getEnvDuration("GITHUB_TIMEOUT", 30*time.Second),
+			Token:            getEnvString("GITHUB_TOKEN", ""),
+			Owner:            ciContext.Owner,
+			Repository:       ciContext.Repository,
+			PullRequest:      ciContext.PullRequest,
+			CommitSHA:        ciContext.CommitSHA,
+			PostComments:     getEnvBool("GO_COVERAGE_POST_COMMENTS", true),
+			CreateStatuses:   getEnvBool("GO_COVERAGE_CREATE_STATUSES", true),
+			CleanupStatuses:  getEnvBool("GO_COVERAGE_CLEANUP_STATUSES", true),
+			Preflight:        getEnvBool("GO_COVERAGE_PREFLIGHT", true),
+			Timeout:          getEnvDuration("GITHUB_TIMEOUT", 30*time.Second),
+			ForkMode:         strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_FORK_MODE", ForkModeAuto))),
+			HandoffDir:       getEnvString("GO_COVERAGE_HANDOFF_DIR", "coverage-handoff"),
+			CommentResolve:   strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_COMMENT_RESOLVE", CommentResolveOff))),
+			CommentCelebrate: getEnvFloat("GO_COVERAGE_COMMENT_CELEBRATE", 0),
+			CommentInsights:  getEnvInt("GO_COVERAGE_COMMENT_INSIGHTS", 3),
+			CommentDiffImage: getEnvBool("GO_COVERAGE_COMMENT_DIFF_IMAGE", true),
+			CommentAllFiles:  getEnvBool("GO_COVERAGE_COMMENT_ALL_FILES", false),
+			CommentSignature: getEnvString("GO_COVERAGE_COMMENT_SIGNATURE", "go-coverage-v1"),
+			CommentHeader:    getEnvString("GO_COVERAGE_COMMENT_HEADER", "Code Coverage Analysis"),
+			CommentFooter:    getEnvString("GO_COVERAGE_COMMENT_FOOTER", ""),
+			CommentBranding:  getEnvBool("GO_COVERAGE_COMMENT_BRANDING", true),
+			DigestThread:     strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_DIGEST_THREAD", DigestThreadOff))),
+			DigestCategory:   getEnvString("GO_COVERAGE_DIGEST_CATEGORY", "General"),
+			DigestTitle:      getEnvString("GO_COVERAGE_DIGEST_TITLE", "Coverage digest"),
+
+			RegressionIssue:      getEnvBool("GO_COVERAGE_REGRESSION_ISSUE", false),
+			RegressionIssueRuns:  getEnvInt("GO_COVERAGE_REGRESSION_ISSUE_RUNS", 3),
+			RegressionIssueTitle: getEnvString("GO_COVERAGE_REGRESSION_ISSUE_TITLE", "Sustained coverage regression"),
+			APIBudget:            GitHubAPIBudget(),
 		},
 		Badge: BadgeConfig{
 			Style:              getEnvString("GO_COVERAGE_BADGE_STYLE", "flat"),
@@ -343,18 +1119,29 @@ func Load() (*Config, error) {
 			LogoColor:          getEnvString("GO_COVERAGE_BADGE_LOGO_COLOR", "white"),
 			OutputFile:         getEnvString("GO_COVERAGE_BADGE_OUTPUT", "coverage.svg"),
 			IncludeTrend:       getEnvBool("GO_COVERAGE_BADGE_TREND", false),
+			Extra:              normalizeBadgeExtras(getEnvStringSlice("GO_COVERAGE_BADGE_EXTRA", nil)),
+			ChartPoints:        getEnvInt("GO_COVERAGE_BADGE_CHART_POINTS", 20),
 			LogoTimeout:        getEnvDuration("GO_COVERAGE_LOGO_TIMEOUT", 8*time.Second),
 			LogoHTTPTimeout:    getEnvDuration("GO_COVERAGE_LOGO_HTTP_TIMEOUT", 3*time.Second),
 			LogoRetries:        getEnvInt("GO_COVERAGE_LOGO_RETRIES", 2),
 			LogoGitHubFallback: getEnvBool("GO_COVERAGE_LOGO_GITHUB_FALLBACK", true),
 		},
 		Report: ReportConfig{
-			OutputFile:   getEnvString("GO_COVERAGE_REPORT_OUTPUT", "coverage.html"),
-			Title:        getEnvString("GO_COVERAGE_REPORT_TITLE", "Coverage Report"),
-			Theme:        getEnvString("GO_COVERAGE_REPORT_THEME", "github-dark"),
-			ShowPackages: getEnvBool("GO_COVERAGE_REPORT_PACKAGES", true),
-			ShowFiles:    getEnvBool("GO_COVERAGE_REPORT_FILES", true),
-			ShowMissing:  getEnvBool("GO_COVERAGE_REPORT_MISSING", true),
+			OutputFile:            getEnvString("GO_COVERAGE_REPORT_OUTPUT", "coverage.html"),
+			Title:                 getEnvString("GO_COVERAGE_REPORT_TITLE", "Coverage Report"),
+			Theme:                 getEnvString("GO_COVERAGE_REPORT_THEME", "github-dark"),
+			ShowPackages:          getEnvBool("GO_COVERAGE_REPORT_PACKAGES", true),
+			ShowFiles:             getEnvBool("GO_COVERAGE_REPORT_FILES", true),
+			ShowMissing:           getEnvBool("GO_COVERAGE_REPORT_MISSING", true),
+			RollupDepth:           getEnvInt("GO_COVERAGE_REPORT_ROLLUP_DEPTH", 0),
+			MaxPageKB:             getEnvInt("GO_COVERAGE_REPORT_MAX_PAGE_KB", 4096),
+			SnapshotRetentionDays: getEnvInt("GO_COVERAGE_REPORT_SNAPSHOT_RETENTION", 90),
+			KeepClosedPRs:         getEnvBool("GO_COVERAGE_REPORT_KEEP_CLOSED_PRS", false),
+			Deployment:            getEnvBool("GO_COVERAGE_REPORT_DEPLOYMENT", false),
+			DeploymentEnvironment: getEnvString("GO_COVERAGE_REPORT_DEPLOYMENT_ENVIRONMENT", "coverage"),
+			TemplateDir:           getEnvString("GO_COVERAGE_REPORT_TEMPLATE_DIR", ""),
+			Local:                 getEnvBool("GO_COVERAGE_LOCAL", false),
+			Sections:              loadReportSections(),
 		},
 		History: HistoryConfig{
 			Enabled:        getEnvBool("GO_COVERAGE_HISTORY_ENABLED", true),
@@ -363,6 +1150,11 @@ func Load() (*Config, error) {
 			MaxEntries:     getEnvInt("GO_COVERAGE_HISTORY_MAX_ENTRIES", 1000),
 			AutoCleanup:    getEnvBool("GO_COVERAGE_HISTORY_CLEANUP", true),
 			MetricsEnabled: getEnvBool("GO_COVERAGE_HISTORY_METRICS", true),
+			Environment:    getEnvString("GO_COVERAGE_HISTORY_ENVIRONMENT", ""),
+			Suite:          getEnvString("GO_COVERAGE_HISTORY_SUITE", ""),
+			PruneMode:      getEnvString("GO_COVERAGE_HISTORY_PRUNE_MODE", PruneModeArchive),
+			ArchivePath:    getEnvString("GO_COVERAGE_HISTORY_ARCHIVE_PATH", "coverage/history-archive"),
+			PruneGraceDays: getEnvInt("GO_COVERAGE_HISTORY_PRUNE_GRACE_DAYS", 7),
 		},
 		Storage: StorageConfig{
 			BaseDir:    getEnvString("GO_COVERAGE_BASE_DIR", "coverage"),
@@ -376,14 +1168,273 @@ func Load() (*Config, error) {
 			Enabled: getEnvBool("GO_COVERAGE_LOG_ENABLED", true),
 		},
 		Analytics: AnalyticsConfig{
-			GoogleAnalyticsID: getEnvString("GOOGLE_ANALYTICS_ID", ""),
-			BrandingEnabled:   getEnvBool("GO_COVERAGE_BRANDING_ENABLED", true),
+			GoogleAnalyticsID:   getEnvString("GOOGLE_ANALYTICS_ID", ""),
+			BrandingEnabled:     getEnvBool("GO_COVERAGE_BRANDING_ENABLED", true),
+			TestResults:         getEnvString("GO_COVERAGE_TEST_RESULTS", ""),
+			TestTimeGrowthRatio: getEnvFloat("GO_COVERAGE_TEST_TIME_GROWTH_RATIO", 2),
+			DeadCode:            getEnvBool("GO_COVERAGE_DEAD_CODE", false),
+			DeadCodeIssueTitle:  getEnvString("GO_COVERAGE_DEAD_CODE_ISSUE_TITLE", "Dead code candidates"),
+			CoverageDebt:        getEnvBool("GO_COVERAGE_DEBT", true),
+			DebtCriticalWeight:  getEnvFloat("GO_COVERAGE_DEBT_CRITICAL_WEIGHT", 2),
+		},
+		Retry: RetryConfig{
+			MaxAttempts:                 getEnvInt("GO_COVERAGE_RETRY_MAX_ATTEMPTS", 3),
+			InitialDelay:                getEnvDuration("GO_COVERAGE_RETRY_INITIAL_DELAY", 500*time.Millisecond),
+			MaxDelay:                    getEnvDuration("GO_COVERAGE_RETRY_MAX_DELAY", 10*time.Second),
+			Multiplier:                  getEnvFloat("GO_COVERAGE_RETRY_MULTIPLIER", 2.0),
+			Jitter:                      getEnvFloat("GO_COVERAGE_RETRY_JITTER", 0.2),
+			Budget:                      getEnvInt("GO_COVERAGE_RETRY_BUDGET", 0),
+			GitHubMaxAttempts:           getEnvInt("GO_COVERAGE_RETRY_GITHUB_MAX_ATTEMPTS", 0),
+			ArtifactDownloadMaxAttempts: getEnvInt("GO_COVERAGE_RETRY_ARTIFACT_DOWNLOAD_MAX_ATTEMPTS", 0),
+		},
+		Network: NetworkConfig{
+			ProxyURL:           getEnvString("GO_COVERAGE_PROXY_URL", ""),
+			CABundle:           getEnvString("GO_COVERAGE_CA_BUNDLE", ""),
+			InsecureSkipVerify: getEnvBool("GO_COVERAGE_TLS_SKIP_VERIFY", false),
+			Offline:            getEnvBool("GO_COVERAGE_OFFLINE", false),
+			RecordFixtures:     getEnvString("GO_COVERAGE_RECORD_FIXTURES", ""),
+			ReplayFixtures:     getEnvString("GO_COVERAGE_REPLAY_FIXTURES", ""),
+		},
+		Redaction: RedactionConfig{
+			Patterns: getEnvStringSlice(redact.PatternsEnv, nil),
+		},
+		Policy: PolicyConfig{
+			MaxDrop:     getEnvFloat("GO_COVERAGE_POLICY_MAX_DROP", -1),
+			GraceDrop:   getEnvFloat("GO_COVERAGE_POLICY_GRACE_DROP", 0),
+			GraceAbove:  getEnvFloat("GO_COVERAGE_POLICY_GRACE_ABOVE", 0),
+			DeclineRuns: getEnvInt("GO_COVERAGE_POLICY_DECLINE_RUNS", 0),
+			Gate:        getEnvString("GO_COVERAGE_POLICY_GATE", ""),
+			NoCodeChanges: strings.ToLower(strings.TrimSpace(
+				getEnvString("GO_COVERAGE_POLICY_NO_CODE_CHANGES", NoCodeChangesSuccess))),
+			ConfidenceRuns:    getEnvInt("GO_COVERAGE_CONFIDENCE_RUNS", 10),
+			ConfidenceLevel:   getEnvFloat("GO_COVERAGE_CONFIDENCE_LEVEL", 95),
+			LowerBound:        getEnvBool("GO_COVERAGE_POLICY_LOWER_BOUND", false),
+			BypassTokens:      getEnvStringSlice("GO_COVERAGE_POLICY_BYPASS_TOKENS", nil),
+			BypassPaths:       getEnvStringSlice("GO_COVERAGE_POLICY_BYPASS_PATHS", nil),
+			CriticalPaths:     getEnvStringSlice("GO_COVERAGE_POLICY_CRITICAL_PATHS", nil),
+			CriticalThreshold: getEnvFloat("GO_COVERAGE_POLICY_CRITICAL_THRESHOLD", 100),
+			WarmupRuns:        getEnvInt("GO_COVERAGE_POLICY_WARMUP_RUNS", 0),
+			WarmupDays:        getEnvInt("GO_COVERAGE_POLICY_WARMUP_DAYS", 0),
+			NewFileThreshold:  getEnvFloat("GO_COVERAGE_POLICY_NEW_FILE_THRESHOLD", 70),
+		},
+		Editor: EditorConfig{
+			Formats: getEnvStringSlice("GO_COVERAGE_EDITOR_FORMATS", nil),
+			Dir:     getEnvString("GO_COVERAGE_EDITOR_DIR", "."),
+		},
+		Branches: BranchConfig{
+			Rules: branchRules,
+		},
+		Teams: TeamConfig{
+			Teams: teams,
+			Dir:   getEnvString("GO_COVERAGE_TEAMS_DIR", "teams"),
+		},
+		Labels: LabelConfig{
+			Enabled:     getEnvBool("GO_COVERAGE_LABEL_OVERRIDES", true),
+			Prefix:      getEnvString("GO_COVERAGE_LABEL_PREFIX", "coverage:"),
+			PullRequest: getEnvStringSlice("GO_COVERAGE_PR_LABELS", nil),
+		},
+		Gerrit: GerritConfig{
+			URL:      getEnvString("GO_COVERAGE_GERRIT_URL", gerritURLFromChangeURL(os.Getenv("GERRIT_CHANGE_URL"))),
+			Username: getEnvString("GO_COVERAGE_GERRIT_USERNAME", ""),
+			Password: getEnvString("GO_COVERAGE_GERRIT_PASSWORD", ""),
+			SSHHost:  getEnvString("GERRIT_HOST", ""),
+			SSHPort:  getEnvInt("GERRIT_PORT", 29418),
+			SSHUser:  getEnvString("GO_COVERAGE_GERRIT_SSH_USER", ""),
+			SSHKey:   getEnvString("GO_COVERAGE_GERRIT_SSH_KEY", ""),
+			Project:  getEnvString("GERRIT_PROJECT", ""),
+			Branch:   getEnvString("GERRIT_BRANCH", ""),
+			Change:   getEnvInt("GERRIT_CHANGE_NUMBER", 0),
+			Patchset: getEnvInt("GERRIT_PATCHSET_NUMBER", 0),
+			Revision: getEnvString("GERRIT_PATCHSET_REVISION", ""),
+			Label:    getEnvString("GO_COVERAGE_GERRIT_LABEL", "Verified"),
+			PassVote: getEnvInt("GO_COVERAGE_GERRIT_PASS_VOTE", 1),
+			FailVote: getEnvInt("GO_COVERAGE_GERRIT_FAIL_VOTE", -1),
+		},
+		Bitbucket: BitbucketConfig{
+			Workspace:   getEnvString("BITBUCKET_WORKSPACE", ""),
+			Repository:  getEnvString("BITBUCKET_REPO_SLUG", ""),
+			CommitSHA:   getEnvString("BITBUCKET_COMMIT", ""),
+			PullRequest: getEnvInt("BITBUCKET_PR_ID", 0),
+			Branch:      getEnvString("BITBUCKET_BRANCH", ""),
+			BaseBranch:  getEnvString("BITBUCKET_PR_DESTINATION_BRANCH", ""),
+			BuildNumber: getEnvInt("BITBUCKET_BUILD_NUMBER", 0),
+			APIURL:      getEnvString("GO_COVERAGE_BITBUCKET_API_URL", "https://api.bitbucket.org/2.0"),
+			Token:       getEnvString("GO_COVERAGE_BITBUCKET_TOKEN", ""),
+			Username:    getEnvString("GO_COVERAGE_BITBUCKET_USERNAME", ""),
+			AppPassword: getEnvString("GO_COVERAGE_BITBUCKET_APP_PASSWORD", ""),
+			StatusKey:   getEnvString("GO_COVERAGE_BITBUCKET_STATUS_KEY", "go-coverage"),
+			ReportURL:   getEnvString("GO_COVERAGE_BITBUCKET_REPORT_URL", ""),
+		},
+		AzureDevOps: AzureDevOpsConfig{
+			CollectionURI:      getEnvString("SYSTEM_COLLECTIONURI", ""),
+			Project:            getEnvString("SYSTEM_TEAMPROJECT", ""),
+			RepositoryID:       getEnvString("BUILD_REPOSITORY_ID", ""),
+			Repository:         getEnvString("BUILD_REPOSITORY_NAME", ""),
+			RepositoryProvider: getEnvString("BUILD_REPOSITORY_PROVIDER", ""),
+			BuildID:            getEnvInt("BUILD_BUILDID", 0),
+			CommitSHA:          getEnvString("BUILD_SOURCEVERSION", ""),
+			PullRequest:        getEnvInt("SYSTEM_PULLREQUEST_PULLREQUESTID", 0),
+			Branch:             strings.TrimPrefix(getEnvString("SYSTEM_PULLREQUEST_SOURCEBRANCH", getEnvString("BUILD_SOURCEBRANCH", "")), "refs/heads/"),
+			BaseBranch:         strings.TrimPrefix(getEnvString("SYSTEM_PULLREQUEST_TARGETBRANCH", ""), "refs/heads/"),
+			AccessToken:        getEnvString("SYSTEM_ACCESSTOKEN", ""),
+			Token:              getEnvString("GO_COVERAGE_AZURE_DEVOPS_TOKEN", ""),
+			StatusName:         getEnvString("GO_COVERAGE_AZURE_DEVOPS_STATUS_NAME", "coverage"),
+			ReportURL:          getEnvString("GO_COVERAGE_AZURE_DEVOPS_REPORT_URL", ""),
+		},
+		App: AppConfig{
+			ID:             int64(getEnvInt("GO_COVERAGE_APP_ID", 0)),
+			PrivateKey:     getEnvString("GO_COVERAGE_APP_PRIVATE_KEY", ""),
+			PrivateKeyFile: getEnvString("GO_COVERAGE_APP_PRIVATE_KEY_FILE", ""),
+			WebhookSecret:  getEnvString("GO_COVERAGE_APP_WEBHOOK_SECRET", ""),
+			Addr:           getEnvString("GO_COVERAGE_APP_ADDR", ":8080"),
+			Artifact:       getEnvString("GO_COVERAGE_APP_ARTIFACT", "coverage-handoff"),
+		},
+		OrgPolicy: *orgPolicy,
+		PublicBadge: PublicBadgeConfig{
+			Target:     strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_PUBLIC_BADGE_TARGET", ""))),
+			Token:      getEnvString("GO_COVERAGE_PUBLIC_BADGE_TOKEN", ""),
+			GistID:     getEnvString("GO_COVERAGE_PUBLIC_BADGE_GIST_ID", ""),
+			Repository: getEnvString("GO_COVERAGE_PUBLIC_BADGE_REPOSITORY", ""),
+			Branch:     getEnvString("GO_COVERAGE_PUBLIC_BADGE_BRANCH", "gh-pages"),
+			Dir:        getEnvString("GO_COVERAGE_PUBLIC_BADGE_DIR", ""),
+			URL:        getEnvString("GO_COVERAGE_PUBLIC_BADGE_URL", ""),
+			Name:       getEnvString("GO_COVERAGE_PUBLIC_BADGE_NAME", "coverage"),
+		},
+		Serve: ServeConfig{
+			Addr:         getEnvString("GO_COVERAGE_SERVE_ADDR", ":8000"),
+			URL:          strings.TrimSuffix(getEnvString("GO_COVERAGE_SERVE_URL", ""), "/"),
+			Token:        getEnvString("GO_COVERAGE_SERVE_TOKEN", ""),
+			Username:     getEnvString("GO_COVERAGE_SERVE_USERNAME", ""),
+			Password:     getEnvString("GO_COVERAGE_SERVE_PASSWORD", ""),
+			LinkSecret:   getEnvString("GO_COVERAGE_SERVE_LINK_SECRET", ""),
+			LinkTTL:      getEnvDuration("GO_COVERAGE_SERVE_LINK_TTL", 7*24*time.Hour),
+			PublicBadges: getEnvBool("GO_COVERAGE_SERVE_PUBLIC_BADGES", true),
+		},
+		Goal: GoalConfig{
+			Target:       getEnvFloat("GO_COVERAGE_GOAL", 0),
+			Start:        getEnvFloat("GO_COVERAGE_GOAL_START", 0),
+			Due:          strings.TrimSpace(getEnvString("GO_COVERAGE_GOAL_DUE", "")),
+			Tracker:      strings.ToLower(strings.TrimSpace(getEnvString("GO_COVERAGE_GOAL_TRACKER", GoalTrackerOff))),
+			Title:        getEnvString("GO_COVERAGE_GOAL_TITLE", "Coverage goal"),
+			Project:      getEnvInt("GO_COVERAGE_GOAL_PROJECT", 0),
+			ProjectOwner: getEnvString("GO_COVERAGE_GOAL_PROJECT_OWNER", ""),
+			Token:        getEnvString("GO_COVERAGE_GOAL_TOKEN", ""),
 		},
 	}
 
+	// Pull requests (GitHub, Bitbucket or Azure DevOps) and Gerrit changes are gated by the rules of the branch they target
+	if len(branchRules) > 0 {
+		target := ciContext.BaseBranch
+		if target == "" {
+			target = config.Gerrit.Branch
+		}
+		if target == "" {
+			target = config.Bitbucket.BaseBranch
+		}
+		if target == "" {
+			target = config.AzureDevOps.BaseBranch
+		}
+		if target == "" {
+			target = config.getCurrentBranch()
+		}
+		config.ApplyBranchRules(target)
+	}
+
+	// Labels apply last, so a reviewer's choice for the pull request wins over every setting
+	if config.Labels.Enabled {
+		labels := ciContext.Labels
+		if len(labels) == 0 {
+			labels = config.Labels.PullRequest
+		}
+		config.ApplyLabels(labels)
+	}
+
+	// A waiver excludes its pattern, so waived code needs no separate exclusion
+	for _, waiver := range config.Coverage.Waivers {
+		if waiver.Kind() == waivers.KindFile {
+			if !slices.Contains(config.Coverage.ExcludeFiles, waiver.Pattern) {
+				config.Coverage.ExcludeFiles = append(config.Coverage.ExcludeFiles, waiver.Pattern)
+			}
+		} else if !slices.Contains(config.Coverage.ExcludePaths, waiver.Pattern) {
+			config.Coverage.ExcludePaths = append(config.Coverage.ExcludePaths, waiver.Pattern)
+		}
+	}
+
 	return config, nil
 }
 
+// ApplyBranchRules applies the first branch rule matching branch on top of the global
+// settings and returns it, or nil when no rule matches. Load applies the rules for the
+// target branch of the run, so this is only needed for configurations built by hand.
+func (c *Config) ApplyBranchRules(branch string) *BranchRule {
+	c.Branches.Target = branch
+	c.Branches.Applied = nil
+	for i := range c.Branches.Rules {
+		rule := &c.Branches.Rules[i]
+		if !branchmatch.Match(rule.Pattern, branch) {
+			continue
+		}
+		if rule.Threshold != nil {
+			c.Coverage.Threshold = *rule.Threshold
+		}
+		if rule.MaxDrop != nil {
+			c.Policy.MaxDrop = *rule.MaxDrop
+		}
+		if rule.DeclineRuns != nil {
+			c.Policy.DeclineRuns = *rule.DeclineRuns
+		}
+		if rule.Gate != nil {
+			c.Policy.Gate = *rule.Gate
+		}
+		if rule.HistoryNamespace != "" {
+			c.History.StoragePath = filepath.Join(c.History.StoragePath, rule.HistoryNamespace)
+		}
+		if rule.BadgeLabel != "" {
+			c.Badge.Label = rule.BadgeLabel
+		}
+		c.Branches.Applied = rule
+		return rule
+	}
+	return nil
+}
+
+// ApplyLabels applies the override labels among labels, e.g. coverage:strict, on top of the
+// settings and returns the overrides applied. Labels without the prefix and unknown overrides
+// are ignored. Load applies the labels of the pull request of the run, so this is only needed
+// for configurations built by hand.
+func (c *Config) ApplyLabels(labels []string) []string {
+	c.Labels.Applied = nil
+	prefix := strings.ToLower(c.Labels.Prefix)
+	for _, label := range labels {
+		override, found := strings.CutPrefix(strings.ToLower(strings.TrimSpace(label)), prefix)
+		if !found || slices.Contains(c.Labels.Applied, override) {
+			continue
+		}
+		switch override {
+		case LabelSkipComment:
+			c.GitHub.PostComments = false
+		case LabelStrict:
+			c.Policy.MaxDrop = 0
+			c.Policy.GraceDrop = 0
+			c.Policy.DeclineRuns = 0
+			c.Policy.BypassTokens = nil
+			c.Policy.BypassPaths = nil
+			c.Coverage.AllowLabelOverride = false
+		case LabelReportAllFiles:
+			c.GitHub.CommentAllFiles = true
+		default:
+			continue
+		}
+		c.Labels.Applied = append(c.Labels.Applied, override)
+	}
+	return c.Labels.Applied
+}
+
+// HasLabel reports whether the override label was applied to this run
+func (c *Config) HasLabel(override string) bool {
+	return slices.Contains(c.Labels.Applied, override)
+}
+
 // Validate validates the configuration and returns an error if invalid
 func (c *Config) Validate() error {
 	// Validate coverage settings
@@ -393,10 +1444,83 @@ func (c *Config) Validate() error {
 
 	// No additional validation needed for AllowLabelOverride - it's just a boolean
 
+	for _, name := range c.Coverage.ExcludePresets {
+		if _, err := parser.LookupExclusionPreset(name); err != nil {
+			return err
+		}
+	}
+	for _, variant := range c.Coverage.Variants {
+		if _, _, err := parser.ParseVariantArg(variant); err != nil {
+			return err
+		}
+	}
+	for _, input := range c.Coverage.ExtraInputs {
+		if _, _, err := parser.ParseInputArg(input); err != nil {
+			return err
+		}
+	}
+	if _, err := parser.ParseModuleRewrites(c.Coverage.ModuleRewrites); err != nil {
+		return err
+	}
+	if root := c.Coverage.ModuleRoot; path.IsAbs(root) || filepath.IsAbs(root) || root == ".." || strings.HasPrefix(root, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidModuleRoot, root)
+	}
+	for _, arg := range c.Coverage.ModuleThresholds {
+		if _, _, err := parseModuleThreshold(arg); err != nil {
+			return err
+		}
+	}
+	if c.Coverage.HotspotHits < 0 {
+		return ErrInvalidHotspotHits
+	}
+	switch c.Coverage.CoverModeCheck {
+	case "", CoverModeCheckWarn, CoverModeCheckFail, CoverModeCheckOff:
+	default:
+		return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidCoverModeCheck, c.Coverage.CoverModeCheck,
+			CoverModeCheckWarn, CoverModeCheckFail, CoverModeCheckOff)
+	}
+
 	if c.Coverage.InputFile == "" {
 		return ErrEmptyCoverageInput
 	}
 
+	switch c.GitHub.ForkMode {
+	case "", ForkModeAuto, ForkModeAlways, ForkModeNever:
+	default:
+		return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidForkMode, c.GitHub.ForkMode,
+			ForkModeAuto, ForkModeAlways, ForkModeNever)
+	}
+
+	switch c.GitHub.CommentResolve {
+	case "", CommentResolveOff, CommentResolveMark, CommentResolveMinimize:
+	default:
+		return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidCommentResolve, c.GitHub.CommentResolve,
+			CommentResolveOff, CommentResolveMark, CommentResolveMinimize)
+	}
+	// The signature is written into a Markdown comment, [//]: # (signature)
+	if strings.ContainsAny(c.GitHub.CommentSignature, "()\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidCommentSignature, c.GitHub.CommentSignature)
+	}
+	if c.GitHub.CommentCelebrate < 0 {
+		return ErrInvalidCommentCelebrate
+	}
+	if c.GitHub.CommentInsights < 0 {
+		return ErrInvalidCommentInsights
+	}
+	if c.GitHub.APIBudget < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidAPIBudget, c.GitHub.APIBudget)
+	}
+
+	switch c.GitHub.DigestThread {
+	case "", DigestThreadOff, DigestThreadDiscussion, DigestThreadIssue:
+	default:
+		return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidDigestThread, c.GitHub.DigestThread,
+			DigestThreadOff, DigestThreadDiscussion, DigestThreadIssue)
+	}
+	if c.GitHub.RegressionIssue && c.GitHub.RegressionIssueRuns < 1 {
+		return fmt.Errorf("%w: got %d", ErrInvalidRegressionRuns, c.GitHub.RegressionIssueRuns)
+	}
+
 	// Validate GitHub settings if GitHub integration is enabled
 	if c.GitHub.PostComments || c.GitHub.CreateStatuses {
 		if c.GitHub.Token == "" {
@@ -410,17 +1534,43 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if err := c.validatePublicBadge(); err != nil {
+		return err
+	}
+	if err := c.ValidateServe(); err != nil {
+		return err
+	}
+	if err := c.validateGoal(); err != nil {
+		return err
+	}
+
+	if c.Gerrit.SSHHost != "" && (c.Gerrit.SSHPort < 1 || c.Gerrit.SSHPort > 65535) {
+		return fmt.Errorf("%w, got: %d", ErrInvalidGerritPort, c.Gerrit.SSHPort)
+	}
+
 	// Validate badge settings
-	validStyles := []string{"flat", "flat-square", "for-the-badge"}
+	validStyles := BadgeStyles()
 	if !contains(validStyles, c.Badge.Style) {
 		return fmt.Errorf("%w: %s, must be one of: %v", ErrInvalidBadgeStyle, c.Badge.Style, validStyles)
 	}
+	if c.Badge.ChartPoints < 0 {
+		return ErrInvalidBadgeChartPoints
+	}
 
 	// Validate report settings
-	validThemes := []string{"github-dark", "light", "github-light"}
+	validThemes := ReportThemes()
 	if !contains(validThemes, c.Report.Theme) {
 		return fmt.Errorf("%w: %s, must be one of: %v", ErrInvalidReportTheme, c.Report.Theme, validThemes)
 	}
+	if c.Report.RollupDepth < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidRollupDepth, c.Report.RollupDepth)
+	}
+	if c.Report.MaxPageKB < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidReportPageSize, c.Report.MaxPageKB)
+	}
+	if c.Report.SnapshotRetentionDays < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidSnapshotRetention, c.Report.SnapshotRetentionDays)
+	}
 
 	// Validate history settings
 	if c.History.Enabled {
@@ -431,10 +1581,452 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("%w: got %d", ErrInvalidMaxEntries, c.History.MaxEntries)
 		}
 	}
+	if _, err := runenv.ParseFilter(c.History.Environment); err != nil {
+		return err
+	}
+	if _, err := history.ParseSuite(c.History.Suite); err != nil {
+		return err
+	}
+	switch c.History.PruneMode {
+	case "", PruneModeArchive, PruneModeDelete:
+	default:
+		return fmt.Errorf("%w: %q (expected %s or %s)", ErrInvalidPruneMode, c.History.PruneMode, PruneModeArchive, PruneModeDelete)
+	}
+	if c.History.PruneGraceDays < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidPruneGrace, c.History.PruneGraceDays)
+	}
+
+	// Validate retry settings
+	if c.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidRetryAttempts, c.Retry.MaxAttempts)
+	}
+	if c.Retry.Multiplier != 0 && c.Retry.Multiplier < 1 {
+		return fmt.Errorf("%w: got %.2f", ErrInvalidRetryMultiplier, c.Retry.Multiplier)
+	}
+	if c.Retry.Jitter < 0 || c.Retry.Jitter > 1 {
+		return fmt.Errorf("%w: got %.2f", ErrInvalidRetryJitter, c.Retry.Jitter)
+	}
+
+	// Validate redaction settings
+	if _, err := c.NewRedactor(); err != nil {
+		return err
+	}
+
+	// Validate network settings
+	if c.Network.RecordFixtures != "" && c.Network.ReplayFixtures != "" {
+		return ErrInvalidFixtureMode
+	}
+
+	// Validate policy settings
+	if c.Policy.GraceDrop < 0 || c.Policy.GraceAbove < 0 || c.Policy.GraceAbove > 100 {
+		return fmt.Errorf("%w: grace drop %.2f, grace above %.2f", ErrInvalidPolicyGrace, c.Policy.GraceDrop, c.Policy.GraceAbove)
+	}
+	if c.Policy.DeclineRuns < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidPolicyDeclineRuns, c.Policy.DeclineRuns)
+	}
+	if c.Policy.WarmupRuns < 0 || c.Policy.WarmupDays < 0 {
+		return fmt.Errorf("%w: runs %d, days %d", ErrInvalidWarmup, c.Policy.WarmupRuns, c.Policy.WarmupDays)
+	}
+	if c.Analytics.TestTimeGrowthRatio < 0 {
+		return fmt.Errorf("%w: got %.2f", ErrInvalidTestTimeGrowth, c.Analytics.TestTimeGrowthRatio)
+	}
+	if c.Analytics.CoverageDebt && c.Analytics.DebtCriticalWeight < 1 {
+		return fmt.Errorf("%w: got %.2f", ErrInvalidDebtWeight, c.Analytics.DebtCriticalWeight)
+	}
+	if c.Policy.ConfidenceRuns < 0 || (c.Policy.ConfidenceRuns > 0 && (c.Policy.ConfidenceLevel <= 0 || c.Policy.ConfidenceLevel >= 100)) {
+		return fmt.Errorf("%w: runs %d, level %.2f", ErrInvalidConfidence, c.Policy.ConfidenceRuns, c.Policy.ConfidenceLevel)
+	}
+	for _, pattern := range c.Policy.BypassPaths {
+		if err := branchmatch.Validate(pattern); err != nil {
+			return fmt.Errorf("bypass path: %w", err)
+		}
+	}
+	if c.Policy.NewFileThreshold < 0 || c.Policy.NewFileThreshold > 100 {
+		return fmt.Errorf("%w: got %.2f", ErrInvalidNewFileThreshold, c.Policy.NewFileThreshold)
+	}
+	if _, err := c.Policy.CriticalPathRules(); err != nil {
+		return err
+	}
+	if c.Policy.Gate != "" {
+		if _, err := policy.ParseExpression(c.Policy.Gate); err != nil {
+			return err
+		}
+	}
+	switch c.Policy.NoCodeChanges {
+	case "", NoCodeChangesSuccess, NoCodeChangesComment, NoCodeChangesFull:
+	default:
+		return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidNoCodeChanges, c.Policy.NoCodeChanges,
+			NoCodeChangesSuccess, NoCodeChangesComment, NoCodeChangesFull)
+	}
+
+	for _, format := range c.Editor.Formats {
+		if err := editor.ValidateFormat(format); err != nil {
+			return err
+		}
+	}
+
+	for _, extra := range c.Badge.Extra {
+		switch extra {
+		case BadgeExtraGrade, BadgeExtraTrend, BadgeExtraTests:
+		default:
+			return fmt.Errorf("%w: %q (expected %s, %s or %s)", ErrInvalidBadgeExtra, extra,
+				BadgeExtraGrade, BadgeExtraTrend, BadgeExtraTests)
+		}
+	}
+
+	for i := range c.Branches.Rules {
+		if err := c.Branches.Rules[i].validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.validateTeams(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTeams checks that team names are unique directory names and that teams own
+// directories inside the repository
+func (c *Config) validateTeams() error {
+	if len(c.Teams.Teams) == 0 {
+		return nil
+	}
+	if !filepath.IsLocal(c.Teams.Dir) {
+		return fmt.Errorf("%w: team directory %q must be a relative path inside the report directory", ErrInvalidTeam, c.Teams.Dir)
+	}
+	seen := make(map[string]struct{}, len(c.Teams.Teams))
+	for _, team := range c.Teams.Teams {
+		if !validTeamName(team.Name) {
+			return fmt.Errorf("%w: name %q may only contain letters, digits, dots, dashes and underscores", ErrInvalidTeam, team.Name)
+		}
+		if _, ok := seen[team.Name]; ok {
+			return fmt.Errorf("%w: %s is defined twice", ErrInvalidTeam, team.Name)
+		}
+		seen[team.Name] = struct{}{}
+		if len(team.Paths) == 0 {
+			return fmt.Errorf("%w: %s owns no paths", ErrInvalidTeam, team.Name)
+		}
+		for _, dir := range team.Paths {
+			if !filepath.IsLocal(dir) {
+				return fmt.Errorf("%w: %s: path %q must be relative to the repository root", ErrInvalidTeam, team.Name, dir)
+			}
+		}
+		if team.Threshold != nil && (*team.Threshold < 0 || *team.Threshold > 100) {
+			return fmt.Errorf("%w: %s: %w, got: %.1f", ErrInvalidTeam, team.Name, ErrInvalidCoverageThreshold, *team.Threshold)
+		}
+	}
+	return nil
+}
+
+// validTeamName reports whether a team name is usable as a directory of the published site
+func validTeamName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsFunc(name, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '.' && r != '-' && r != '_'
+	})
+}
+
+// CriticalPathRules parses the critical path patterns, giving patterns without a requirement of
+// their own the critical threshold
+func (p *PolicyConfig) CriticalPathRules() ([]CriticalPath, error) {
+	if p.CriticalThreshold < 0 || p.CriticalThreshold > 100 {
+		return nil, fmt.Errorf("%w: threshold %.2f must be between 0 and 100", ErrInvalidCriticalPath, p.CriticalThreshold)
+	}
+	rules := make([]CriticalPath, 0, len(p.CriticalPaths))
+	for _, entry := range p.CriticalPaths {
+		pattern, value, hasThreshold := strings.Cut(entry, "=")
+		rule := CriticalPath{Pattern: strings.TrimSpace(pattern), Threshold: p.CriticalThreshold}
+		if err := branchmatch.Validate(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCriticalPath, err)
+		}
+		if hasThreshold {
+			threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || threshold < 0 || threshold > 100 {
+				return nil, fmt.Errorf("%w: %q: threshold must be a number between 0 and 100", ErrInvalidCriticalPath, entry)
+			}
+			rule.Threshold = threshold
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Owns reports whether a repository relative file path lies in one of the team's directories
+func (t *Team) Owns(file string) bool {
+	file = path.Clean(filepath.ToSlash(file))
+	for _, dir := range t.Paths {
+		dir = path.Clean(filepath.ToSlash(dir))
+		if file == dir || strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the pattern and the overridden settings of a branch rule
+func (r *BranchRule) validate() error {
+	if err := branchmatch.Validate(r.Pattern); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBranchRule, err)
+	}
+	if r.Threshold != nil && (*r.Threshold < 0 || *r.Threshold > 100) {
+		return fmt.Errorf("%w: %s: %w, got: %.1f", ErrInvalidBranchRule, r.Pattern, ErrInvalidCoverageThreshold, *r.Threshold)
+	}
+	if r.DeclineRuns != nil && *r.DeclineRuns < 0 {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidBranchRule, r.Pattern, ErrInvalidPolicyDeclineRuns)
+	}
+	if r.Gate != nil && *r.Gate != "" {
+		if _, err := policy.ParseExpression(*r.Gate); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidBranchRule, r.Pattern, err)
+		}
+	}
+	if r.HistoryNamespace != "" && !filepath.IsLocal(r.HistoryNamespace) {
+		return fmt.Errorf("%w: %s: history namespace %q must be a relative path inside the history directory",
+			ErrInvalidBranchRule, r.Pattern, r.HistoryNamespace)
+	}
+	return nil
+}
+
+// NewRedactor creates a redactor for the configured patterns, the GitHub token, the Gerrit,
+// Bitbucket, Azure DevOps, GitHub App, public badge, report server and goal tracker credentials and
+// the secrets held in the well-known token environment variables
+func (c *Config) NewRedactor() (*redact.Redactor, error) {
+	secrets := []string{
+		c.GitHub.Token, c.Gerrit.Password, c.Bitbucket.Token, c.Bitbucket.AppPassword,
+		c.AzureDevOps.AccessToken, c.AzureDevOps.Token, c.App.PrivateKey, c.App.WebhookSecret,
+		c.PublicBadge.Token, c.Serve.Token, c.Serve.Password, c.Serve.LinkSecret, c.Goal.Token,
+	}
+	for _, name := range redact.SecretEnvVars() {
+		secrets = append(secrets, os.Getenv(name))
+	}
+	return redact.New(secrets, c.Redaction.Patterns...)
+}
+
+// RetryPolicy returns the retry policy for the named operation (see the retry.Op* constants).
+// All policies returned by the same configuration share one retry budget.
+func (c *Config) RetryPolicy(operation string) *retry.Policy {
+	policy := retry.Policy{
+		MaxAttempts:  c.Retry.MaxAttempts,
+		InitialDelay: c.Retry.InitialDelay,
+		MaxDelay:     c.Retry.MaxDelay,
+		Multiplier:   c.Retry.Multiplier,
+		Jitter:       c.Retry.Jitter,
+	}
+
+	overrides := map[string]int{
+		retry.OpGitHubAPI:        c.Retry.GitHubMaxAttempts,
+		retry.OpArtifactDownload: c.Retry.ArtifactDownloadMaxAttempts,
+	}
+	if attempts := overrides[operation]; attempts > 0 {
+		policy.MaxAttempts = attempts
+	}
+
+	if c.Retry.Budget > 0 {
+		if c.retryBudget == nil {
+			c.retryBudget = retry.NewBudget(c.Retry.Budget)
+		}
+		policy.Budget = c.retryBudget
+	}
+
+	return &policy
+}
+
+// validatePublicBadge checks that the public badge target has its location and credentials
+func (c *Config) validatePublicBadge() error {
+	badge := &c.PublicBadge
+	switch badge.Target {
+	case "":
+		return nil
+	case PublicBadgeGist:
+		if badge.GistID == "" || badge.Token == "" {
+			return fmt.Errorf("%w: the gist target needs GO_COVERAGE_PUBLIC_BADGE_GIST_ID and GO_COVERAGE_PUBLIC_BADGE_TOKEN", ErrInvalidPublicBadge)
+		}
+	case PublicBadgeRepo:
+		if !strings.Contains(badge.Repository, "/") || badge.Token == "" {
+			return fmt.Errorf("%w: the repo target needs GO_COVERAGE_PUBLIC_BADGE_REPOSITORY (owner/name) and GO_COVERAGE_PUBLIC_BADGE_TOKEN", ErrInvalidPublicBadge)
+		}
+	case PublicBadgeHTTP:
+		if badge.URL == "" {
+			return fmt.Errorf("%w: the http target needs GO_COVERAGE_PUBLIC_BADGE_URL", ErrInvalidPublicBadge)
+		}
+	default:
+		return fmt.Errorf("%w: target %q (expected %s, %s or %s)", ErrInvalidPublicBadge, badge.Target,
+			PublicBadgeGist, PublicBadgeRepo, PublicBadgeHTTP)
+	}
+	if badge.Token != "" && badge.Token == c.GitHub.Token {
+		return fmt.Errorf("%w: GO_COVERAGE_PUBLIC_BADGE_TOKEN must not be the repository token", ErrInvalidPublicBadge)
+	}
+	if badge.Name == "" || strings.ContainsAny(badge.Name, `/\`) {
+		return fmt.Errorf("%w: name %q", ErrInvalidPublicBadge, badge.Name)
+	}
+	return nil
+}
+
+// validateGoal checks the coverage goal and the settings of its tracker
+func (c *Config) validateGoal() error {
+	goal := &c.Goal
+	if goal.Target < 0 || goal.Target > 100 {
+		return fmt.Errorf("%w: GO_COVERAGE_GOAL must be between 0 and 100, got %.1f", ErrInvalidGoal, goal.Target)
+	}
+	if goal.Target > 0 && (goal.Start < 0 || goal.Start >= goal.Target) {
+		return fmt.Errorf("%w: GO_COVERAGE_GOAL_START must be at least 0 and below the goal, got %.1f", ErrInvalidGoal, goal.Start)
+	}
+	if goal.Due != "" {
+		if _, err := time.Parse(time.DateOnly, goal.Due); err != nil {
+			return fmt.Errorf("%w: GO_COVERAGE_GOAL_DUE must be a date (YYYY-MM-DD), got %q", ErrInvalidGoal, goal.Due)
+		}
+	}
+	switch goal.Tracker {
+	case "", GoalTrackerOff:
+		return nil
+	case GoalTrackerMilestone:
+	case GoalTrackerProject:
+		if goal.Project <= 0 {
+			return fmt.Errorf("%w: the project tracker needs GO_COVERAGE_GOAL_PROJECT", ErrInvalidGoal)
+		}
+	default:
+		return fmt.Errorf("%w: tracker %q (expected %s, %s or %s)", ErrInvalidGoal, goal.Tracker,
+			GoalTrackerOff, GoalTrackerMilestone, GoalTrackerProject)
+	}
+	if strings.TrimSpace(goal.Title) == "" {
+		return fmt.Errorf("%w: GO_COVERAGE_GOAL_TITLE cannot be empty", ErrInvalidGoal)
+	}
+	return nil
+}
 
+// ValidateServe checks the credentials of the report server
+func (c *Config) ValidateServe() error {
+	serve := &c.Serve
+	if (serve.Username == "") != (serve.Password == "") {
+		return fmt.Errorf("%w: basic auth needs both GO_COVERAGE_SERVE_USERNAME and GO_COVERAGE_SERVE_PASSWORD", ErrInvalidServe)
+	}
+	if serve.LinkSecret != "" && serve.LinkTTL <= 0 {
+		return fmt.Errorf("%w: GO_COVERAGE_SERVE_LINK_TTL must be positive", ErrInvalidServe)
+	}
+	if serve.Token != "" && serve.Token == c.GitHub.Token {
+		return fmt.Errorf("%w: GO_COVERAGE_SERVE_TOKEN must not be the repository token", ErrInvalidServe)
+	}
 	return nil
 }
 
+// Hash returns a SHA-256 fingerprint of the effective configuration with secrets
+// removed, so two runs can be compared without exposing the GitHub token or other credentials.
+// The context of the run (commit, pull request, branch and labels) is left out as well, so
+// the fingerprint only changes when a setting does
+func (c *Config) Hash() (string, error) {
+	sanitized := *c
+	sanitized.CI = nil
+	sanitized.GitHub.PullRequest = 0
+	sanitized.GitHub.CommitSHA = ""
+	sanitized.Branches.Target = ""
+	sanitized.Branches.Applied = nil
+	sanitized.Labels.PullRequest = nil
+	sanitized.Labels.Applied = nil
+	sanitized.Gerrit.Branch = ""
+	sanitized.Gerrit.Change = 0
+	sanitized.Gerrit.Patchset = 0
+	sanitized.Gerrit.Revision = ""
+	sanitized.Bitbucket.CommitSHA = ""
+	sanitized.Bitbucket.PullRequest = 0
+	sanitized.Bitbucket.Branch = ""
+	sanitized.Bitbucket.BaseBranch = ""
+	sanitized.Bitbucket.BuildNumber = 0
+	sanitized.AzureDevOps.BuildID = 0
+	sanitized.AzureDevOps.CommitSHA = ""
+	sanitized.AzureDevOps.PullRequest = 0
+	sanitized.AzureDevOps.Branch = ""
+	sanitized.AzureDevOps.BaseBranch = ""
+	sanitized.GitHub.Token = ""
+	sanitized.Gerrit.Password = ""
+	sanitized.Bitbucket.Token = ""
+	sanitized.Bitbucket.AppPassword = ""
+	sanitized.AzureDevOps.AccessToken = ""
+	sanitized.AzureDevOps.Token = ""
+	sanitized.App.PrivateKey = ""
+	sanitized.App.WebhookSecret = ""
+	sanitized.PublicBadge.Token = ""
+	sanitized.Serve.Token = ""
+	sanitized.Serve.Password = ""
+	sanitized.Serve.LinkSecret = ""
+	sanitized.Goal.Token = ""
+
+	data, err := json.Marshal(&sanitized)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ParserLimits returns the resource limits applied when parsing coverage profiles
+func (c *Config) ParserLimits() parser.Limits {
+	return parser.Limits{
+		MaxFileSize:   int64(c.Coverage.MaxProfileSizeMB) << 20,
+		MaxLineLength: c.Coverage.MaxProfileLineLength,
+		MaxFiles:      c.Coverage.MaxProfileFiles,
+		MaxBlocks:     c.Coverage.MaxProfileBlocks,
+	}
+}
+
+// ModuleRewrites returns the module path rewrites applied to profile paths. Invalid rules
+// are skipped since they are rejected when the configuration is validated.
+func (c *Config) ModuleRewrites() []parser.ModuleRewrite {
+	rewrites := make([]parser.ModuleRewrite, 0, len(c.Coverage.ModuleRewrites))
+	for _, arg := range c.Coverage.ModuleRewrites {
+		if rewrite, err := parser.ParseModuleRewrite(arg); err == nil {
+			rewrites = append(rewrites, rewrite)
+		}
+	}
+	return rewrites
+}
+
+// ModuleThreshold returns the threshold of the workspace module in dir, relative to the
+// repository root, or the global threshold when the module has none of its own
+func (c *Config) ModuleThreshold(dir string) float64 {
+	dir = path.Clean(filepath.ToSlash(dir))
+	for _, arg := range c.Coverage.ModuleThresholds {
+		if moduleDir, threshold, err := parseModuleThreshold(arg); err == nil && moduleDir == dir {
+			return threshold
+		}
+	}
+	return c.Coverage.Threshold
+}
+
+// NewPolicyEngine creates the gating policy engine for the configured threshold and policies
+func (c *Config) NewPolicyEngine() *policy.Engine {
+	return policy.NewEngine(policy.Config{
+		Threshold:   c.Coverage.Threshold,
+		MaxDrop:     c.Policy.MaxDrop,
+		GraceDrop:   c.Policy.GraceDrop,
+		GraceAbove:  c.Policy.GraceAbove,
+		DeclineRuns: c.Policy.DeclineRuns,
+		Gate:        c.Policy.Gate,
+		LowerBound:  c.Policy.LowerBound,
+	})
+}
+
+// NewHTTPClient creates an HTTP client that honors the configured proxy and TLS settings
+// and records or replays fixtures when configured
+func (c *Config) NewHTTPClient(timeout time.Duration) (*http.Client, error) {
+	redactor, err := c.NewRedactor()
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.New(httpclient.Options{
+		Timeout:            timeout,
+		ProxyURL:           c.Network.ProxyURL,
+		CABundle:           c.Network.CABundle,
+		InsecureSkipVerify: c.Network.InsecureSkipVerify,
+		Offline:            c.Network.Offline,
+		RecordDir:          c.Network.RecordFixtures,
+		ReplayDir:          c.Network.ReplayFixtures,
+		Redactor:           redactor,
+	})
+}
+
 // IsGitHubContext returns true if running in a GitHub Actions context
 func (c *Config) IsGitHubContext() bool {
 	return c.GitHub.Owner != "" && c.GitHub.Repository != "" && c.GitHub.CommitSHA != ""
@@ -445,15 +2037,72 @@ func (c *Config) IsPullRequestContext() bool {
 	return c.IsGitHubContext() && c.GitHub.PullRequest > 0
 }
 
+// IsBitbucketContext reports whether the run is a Bitbucket Pipelines build
+func (c *Config) IsBitbucketContext() bool {
+	return c.Bitbucket.Workspace != "" && c.Bitbucket.Repository != "" && c.Bitbucket.CommitSHA != ""
+}
+
+// IsAzureDevOpsContext reports whether the run is an Azure Pipelines build
+func (c *Config) IsAzureDevOpsContext() bool {
+	return c.AzureDevOps.CollectionURI != "" && c.AzureDevOps.Project != "" && c.AzureDevOps.RepositoryID != ""
+}
+
+// DetectProvider returns the code hosting provider of the run: GitHub in GitHub Actions,
+// Azure DevOps in Azure Pipelines building an Azure Repos repository, Bitbucket in
+// Bitbucket Pipelines, and GitHub otherwise
+func (c *Config) DetectProvider() string {
+	switch {
+	case c.IsGitHubContext():
+		return ProviderGitHub
+	case c.IsAzureDevOpsContext() && c.AzureDevOps.RepositoryProvider == "TfsGit":
+		return ProviderAzureDevOps
+	case c.IsBitbucketContext():
+		return ProviderBitbucket
+	default:
+		return ProviderGitHub
+	}
+}
+
+// IsGerritContext reports whether the run is for a Gerrit change
+func (c *Config) IsGerritContext() bool {
+	return c.Gerrit.Change > 0 && c.Gerrit.Patchset > 0
+}
+
+// gerritURLFromChangeURL returns the server URL of a change URL such as
+// https://review.example.com/c/project/+/12345 or https://review.example.com/12345
+func gerritURLFromChangeURL(changeURL string) string {
+	parsed, err := url.Parse(strings.TrimRight(changeURL, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+	path := parsed.Path
+	if index := strings.Index(path, "/c/"); index >= 0 {
+		path = path[:index]
+	} else if index = strings.LastIndex(path, "/"); index >= 0 {
+		path = path[:index]
+	}
+	return parsed.Scheme + "://" + parsed.Host + path
+}
+
+// RepositorySlug returns the repository as owner/repo, the Bitbucket workspace/repository outside
+// GitHub, or an empty string when unknown
+func (c *Config) RepositorySlug() string {
+	if c.GitHub.Owner != "" && c.GitHub.Repository != "" {
+		return c.GitHub.Owner + "/" + c.GitHub.Repository
+	}
+	if c.Bitbucket.Workspace != "" && c.Bitbucket.Repository != "" {
+		return c.Bitbucket.Workspace + "/" + c.Bitbucket.Repository
+	}
+	return ""
+}
+
 // GetBadgeURL returns the URL for the coverage badge
 func (c *Config) GetBadgeURL() string {
-	if c.GitHub.Owner == "" || c.GitHub.Repository == "" {
+	baseURL := c.SiteBaseURL()
+	if baseURL == "" {
 		return ""
 	}
 
-	// Use GitHub Pages URL structure
-	baseURL := fmt.Sprintf("https://%s.github.io/%s", c.GitHub.Owner, c.GitHub.Repository)
-
 	// If in PR context, return PR-specific badge URL
 	if c.IsPullRequestContext() {
 		return fmt.Sprintf("%s/badges/pr/%d/coverage.svg", baseURL, c.GitHub.PullRequest)
@@ -470,18 +2119,42 @@ func (c *Config) GetBadgeURL() string {
 	return fmt.Sprintf("%s/badges/%s/coverage.svg", baseURL, branch)
 }
 
-// GetReportURL returns the URL for the coverage report
-func (c *Config) GetReportURL() string {
+// SiteBaseURL returns the URL the site is served from: the report server when one is
+// configured, GitHub Pages otherwise
+func (c *Config) SiteBaseURL() string {
+	if c.Serve.URL != "" {
+		return c.Serve.URL
+	}
 	if c.GitHub.Owner == "" || c.GitHub.Repository == "" {
 		return ""
 	}
+	return fmt.Sprintf("https://%s.github.io/%s", c.GitHub.Owner, c.GitHub.Repository)
+}
 
-	// Use GitHub Pages URL structure
-	baseURL := fmt.Sprintf("https://%s.github.io/%s", c.GitHub.Owner, c.GitHub.Repository)
+// GetReportURL returns the URL for the coverage report. With a link secret, the report of a
+// pull request is linked with a signed link, which opens it on a protected report server
+// until it expires.
+func (c *Config) GetReportURL() string {
+	baseURL := c.SiteBaseURL()
+	if baseURL == "" {
+		return ""
+	}
 
 	// If in PR context, return PR-specific report URL
 	if c.IsPullRequestContext() {
-		return fmt.Sprintf("%s/reports/pr/%d/coverage.html", baseURL, c.GitHub.PullRequest)
+		reportURL := fmt.Sprintf("%s/reports/pr/%d/coverage.html", baseURL, c.GitHub.PullRequest)
+		if c.Serve.LinkSecret == "" {
+			return reportURL
+		}
+		signer, err := access.NewSigner(c.Serve.LinkSecret)
+		if err != nil {
+			return reportURL
+		}
+		signed, err := signer.Sign(reportURL, c.Serve.LinkTTL)
+		if err != nil {
+			return reportURL
+		}
+		return signed
 	}
 
 	// For branch-specific reports, get current branch (default to master)
@@ -497,19 +2170,22 @@ func (c *Config) GetReportURL() string {
 
 // getCurrentBranch returns the current branch name, with intelligent fallback detection
 func (c *Config) getCurrentBranch() string {
-	// Try to get branch from environment variables (GitHub Actions context)
-	// For pull requests, prefer GITHUB_HEAD_REF (source branch)
-	if branch := os.Getenv("GITHUB_HEAD_REF"); branch != "" {
-		return branch
+	// Use the branch of the CI run: the source branch of a pull request, the pushed
+	// branch, or the head branch of the triggering run
+	ciContext := c.CI
+	if ciContext == nil {
+		ciContext = ci.FromEnv()
 	}
-	if branch := os.Getenv("GITHUB_REF_NAME"); branch != "" {
-		return branch
+	if ciContext.Branch != "" {
+		return ciContext.Branch
 	}
-	if ref := os.Getenv("GITHUB_REF"); ref != "" {
-		// Extract branch name from refs/heads/branch-name
-		if strings.HasPrefix(ref, "refs/heads/") {
-			return strings.TrimPrefix(ref, "refs/heads/")
-		}
+
+	// Bitbucket and Azure Pipelines build the pushed branch or the source branch of a pull request
+	if c.Bitbucket.Branch != "" {
+		return c.Bitbucket.Branch
+	}
+	if c.AzureDevOps.Branch != "" {
+		return c.AzureDevOps.Branch
 	}
 
 	// Try to get branch from Git command as fallback
@@ -527,9 +2203,18 @@ func (c *Config) GetCurrentBranch() string {
 	return c.getCurrentBranch()
 }
 
-// GetRepositoryRoot attempts to find the repository root directory
+// GetRepositoryRoot returns the repository root directory: the configured root (--repo-root or
+// GO_COVERAGE_REPO_ROOT), else the working directory when it holds .git, else the top level
+// git reports, else GITHUB_WORKSPACE, else the working directory
 func (c *Config) GetRepositoryRoot() (string, error) {
-	// Try to get working directory first
+	if c.Coverage.RepoRoot != "" {
+		repoRoot, err := filepath.Abs(c.Coverage.RepoRoot)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve repository root %q: %w", c.Coverage.RepoRoot, err)
+		}
+		return repoRoot, nil
+	}
+
 	workingDir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get working directory: %w", err)
@@ -545,26 +2230,10 @@ func (c *Config) GetRepositoryRoot() (string, error) {
 		return repoRoot, nil
 	}
 
-	// Fallback: handle different coverage directory structures
-	var repoRoot string
-	if strings.Contains(workingDir, "coverage/cmd/go-coverage") ||
-		strings.Contains(workingDir, "coverage"+string(filepath.Separator)+"cmd"+string(filepath.Separator)+"go-coverage") {
-		// New structure: coverage/cmd/go-coverage - go up 3 levels
-		repoRoot = filepath.Join(workingDir, "../../../")
-	} else if strings.Contains(workingDir, ".github/coverage/cmd/go-coverage") ||
-		strings.Contains(workingDir, ".github"+string(filepath.Separator)+"coverage"+string(filepath.Separator)+"cmd"+string(filepath.Separator)+"go-coverage") {
-		// Old structure: .github/coverage/cmd/go-coverage - go up 4 levels
-		repoRoot = filepath.Join(workingDir, "../../../../")
-	}
-
-	if repoRoot != "" {
-		if absPath, err := filepath.Abs(repoRoot); err == nil {
-			// Verify this looks like a repo root
-			if _, err := os.Stat(filepath.Join(absPath, ".git")); err == nil {
-				return absPath, nil
-			}
-			// Return it anyway as best guess
-			return absPath, nil
+	// The checkout of GitHub Actions, for runs outside a git work tree
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		if repoRoot, err := filepath.Abs(workspace); err == nil {
+			return repoRoot, nil
 		}
 	}
 
@@ -646,6 +2315,13 @@ func getEnvString(key, defaultValue string) string {
 	return defaultValue
 }
 
+// GitHubAPIBudget returns the GitHub API requests a run should stay within (0 = no budget). It
+// reads GO_COVERAGE_GITHUB_API_BUDGET alone, so the usage report after a command does not load
+// the whole configuration again.
+func GitHubAPIBudget() int {
+	return getEnvInt("GO_COVERAGE_GITHUB_API_BUDGET", 0)
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := strconv.Atoi(value); err == nil {
@@ -737,15 +2413,298 @@ func getEnvStringSlice(key string, defaultValue []string) []string {
 	return defaultValue
 }
 
-func getRepositoryFromEnv() string {
-	// GitHub Actions provides GITHUB_REPOSITORY in "owner/repo" format
-	if repo := os.Getenv("GITHUB_REPOSITORY"); repo != "" {
-		parts := strings.Split(repo, "/")
-		if len(parts) == 2 {
-			return parts[1]
+// normalizeBadgeExtras lowercases and trims the additional badges, dropping empty entries
+func normalizeBadgeExtras(extras []string) []string {
+	var normalized []string
+	for _, extra := range extras {
+		if extra = strings.ToLower(strings.TrimSpace(extra)); extra != "" {
+			normalized = append(normalized, extra)
 		}
 	}
-	return ""
+	return normalized
+}
+
+// WantsExtra reports whether the additional badge is enabled; GO_COVERAGE_BADGE_TREND also
+// enables the trend badge
+func (b *BadgeConfig) WantsExtra(extra string) bool {
+	return slices.Contains(b.Extra, extra) || (extra == BadgeExtraTrend && b.IncludeTrend)
+}
+
+// loadOrgPolicy fetches the organization policy configured by GO_COVERAGE_ORG_POLICY_URL and
+// sets the settings it holds that are not set already. The policy is fetched with the network
+// settings of the environment, since the configuration it completes is not loaded yet.
+func loadOrgPolicy() (*OrgPolicyConfig, error) {
+	cfg := &OrgPolicyConfig{
+		URL:          getEnvString("GO_COVERAGE_ORG_POLICY_URL", ""),
+		SignatureURL: getEnvString("GO_COVERAGE_ORG_POLICY_SIGNATURE_URL", ""),
+		PublicKey:    getEnvString("GO_COVERAGE_ORG_POLICY_PUBLIC_KEY", ""),
+		CacheDir:     getEnvString("GO_COVERAGE_ORG_POLICY_CACHE_DIR", defaultOrgPolicyCacheDir()),
+		CacheTTL:     getEnvDuration("GO_COVERAGE_ORG_POLICY_CACHE_TTL", time.Hour),
+	}
+	if cfg.URL == "" {
+		return cfg, nil
+	}
+
+	timeout := getEnvDuration("GITHUB_TIMEOUT", 30*time.Second)
+	client, err := httpclient.New(httpclient.Options{
+		Timeout:            timeout,
+		ProxyURL:           getEnvString("GO_COVERAGE_PROXY_URL", ""),
+		CABundle:           getEnvString("GO_COVERAGE_CA_BUNDLE", ""),
+		InsecureSkipVerify: getEnvBool("GO_COVERAGE_TLS_SKIP_VERIFY", false),
+		Offline:            getEnvBool("GO_COVERAGE_OFFLINE", false),
+		RecordDir:          getEnvString("GO_COVERAGE_RECORD_FIXTURES", ""),
+		ReplayDir:          getEnvString("GO_COVERAGE_REPLAY_FIXTURES", ""),
+		Redactor:           redact.FromEnv(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	policy, err := orgpolicy.Fetch(ctx, orgpolicy.Options{
+		URL:          cfg.URL,
+		SignatureURL: cfg.SignatureURL,
+		PublicKey:    cfg.PublicKey,
+		CacheDir:     cfg.CacheDir,
+		TTL:          cfg.CacheTTL,
+		Client:       client,
+		Offline:      getEnvBool("GO_COVERAGE_OFFLINE", false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load organization policy %s: %w", cfg.URL, err)
+	}
+	if cfg.Applied, err = policy.Apply(); err != nil {
+		return nil, err
+	}
+	cfg.Source = policy.Source
+	return cfg, nil
+}
+
+// defaultOrgPolicyCacheDir is the go-coverage directory of the user cache, or of the temporary
+// directory when the user has none
+func defaultOrgPolicyCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "go-coverage", "org-policy")
+}
+
+// getModuleRoot reads GO_COVERAGE_MODULE_ROOT as a clean slash-separated directory, empty
+// for the repository root
+func getModuleRoot() string {
+	root := strings.TrimSpace(getEnvString("GO_COVERAGE_MODULE_ROOT", ""))
+	if root == "" {
+		return ""
+	}
+	root = path.Clean(filepath.ToSlash(root))
+	if root == "." {
+		return ""
+	}
+	return strings.TrimSuffix(root, "/")
+}
+
+// parseModuleThreshold splits a "dir=percentage" module threshold into its clean
+// slash-separated directory and threshold
+func parseModuleThreshold(arg string) (string, float64, error) {
+	dir, value, ok := strings.Cut(arg, "=")
+	dir = strings.TrimSpace(dir)
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if !ok || dir == "" || err != nil || threshold < 0 || threshold > 100 {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidModuleThreshold, arg)
+	}
+	return path.Clean(filepath.ToSlash(dir)), threshold, nil
+}
+
+// getDiscoveryIgnore reads GO_COVERAGE_DISCOVERY_IGNORE, where "none" disables the defaults
+func getDiscoveryIgnore() []string {
+	patterns := getEnvStringSlice("GO_COVERAGE_DISCOVERY_IGNORE", parser.DefaultDiscoveryIgnore())
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.EqualFold(pattern, "none") {
+			continue
+		}
+		result = append(result, pattern)
+	}
+	return result
+}
+
+// getTestHelpers reads GO_COVERAGE_TEST_HELPERS, where "none" disables test helper detection
+func getTestHelpers() []string {
+	patterns := getEnvStringSlice("GO_COVERAGE_TEST_HELPERS", parser.DefaultTestHelperPatterns())
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.EqualFold(pattern, "none") {
+			continue
+		}
+		result = append(result, pattern)
+	}
+	return result
+}
+
+// getExclusionPresets reads GO_COVERAGE_EXCLUDE_PRESETS, where "none" disables all presets.
+// No preset is active by default, so the exclusions stay those of the configured paths and files.
+func getExclusionPresets() []string {
+	presets := getEnvStringSlice("GO_COVERAGE_EXCLUDE_PRESETS", nil)
+	names := make([]string, 0, len(presets))
+	for _, name := range presets {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseBranchRules parses GO_COVERAGE_BRANCH_RULES: rules separated by semicolons, each a
+// branch pattern followed by a colon and comma separated settings, e.g.
+// "release/*: threshold=70, max_drop=2, history=release; hotfix/**: gate=total >= 60"
+func parseBranchRules(value string) ([]BranchRule, error) {
+	var rules []BranchRule
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		pattern, settings, found := strings.Cut(entry, ":")
+		rule := BranchRule{Pattern: strings.TrimSpace(pattern)}
+		if !found || rule.Pattern == "" {
+			return nil, fmt.Errorf("%w: %q (expected pattern: key=value, ...)", ErrInvalidBranchRule, strings.TrimSpace(entry))
+		}
+		for _, setting := range strings.Split(settings, ",") {
+			if strings.TrimSpace(setting) == "" {
+				continue
+			}
+			key, raw, ok := strings.Cut(setting, "=")
+			key, raw = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(raw)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: %q is not key=value", ErrInvalidBranchRule, rule.Pattern, strings.TrimSpace(setting))
+			}
+			if err := rule.set(key, raw); err != nil {
+				return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidBranchRule, rule.Pattern, key, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseTeams parses GO_COVERAGE_TEAMS: teams separated by semicolons, each a name followed by
+// key=value settings, e.g. "web: paths=web|packages/ui, threshold=70; payments: paths=internal/payments"
+func parseTeams(value string) ([]Team, error) {
+	var teams []Team
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, settings, found := strings.Cut(entry, ":")
+		team := Team{Name: strings.TrimSpace(name)}
+		if !found || team.Name == "" {
+			return nil, fmt.Errorf("%w: %q (expected name: paths=dir|dir, threshold=number)", ErrInvalidTeam, strings.TrimSpace(entry))
+		}
+		for _, setting := range strings.Split(settings, ",") {
+			if strings.TrimSpace(setting) == "" {
+				continue
+			}
+			key, raw, ok := strings.Cut(setting, "=")
+			key, raw = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(raw)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: %q is not key=value", ErrInvalidTeam, team.Name, strings.TrimSpace(setting))
+			}
+			switch key {
+			case "paths":
+				for _, dir := range strings.Split(raw, "|") {
+					if dir = strings.Trim(strings.TrimSpace(dir), "/"); dir != "" {
+						team.Paths = append(team.Paths, dir)
+					}
+				}
+			case "threshold":
+				number, err := strconv.ParseFloat(raw, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidTeam, team.Name, key, err)
+				}
+				team.Threshold = &number
+			default:
+				return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidTeam, team.Name, key, ErrUnknownTeamSetting)
+			}
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// set assigns one key=value setting of a branch rule
+func (r *BranchRule) set(key, value string) error {
+	switch key {
+	case "threshold", "max_drop":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if key == "threshold" {
+			r.Threshold = &number
+		} else {
+			r.MaxDrop = &number
+		}
+	case "decline_runs":
+		runs, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		r.DeclineRuns = &runs
+	case "gate":
+		r.Gate = &value
+	case "history":
+		r.HistoryNamespace = value
+	case "badge_label":
+		r.BadgeLabel = value
+	default:
+		return ErrUnknownBranchSetting
+	}
+	return nil
+}
+
+// loadReportSections reads the dashboard Markdown snippets from GO_COVERAGE_REPORT_SECTION_<POSITION>
+func loadReportSections() map[string]string {
+	envByPosition := map[string]string{
+		"top":           "GO_COVERAGE_REPORT_SECTION_TOP",
+		"after-metrics": "GO_COVERAGE_REPORT_SECTION_AFTER_METRICS",
+		"bottom":        "GO_COVERAGE_REPORT_SECTION_BOTTOM",
+	}
+
+	var sections map[string]string
+	for position, key := range envByPosition {
+		if value := os.Getenv(key); value != "" {
+			if sections == nil {
+				sections = make(map[string]string, len(envByPosition))
+			}
+			sections[position] = value
+		}
+	}
+	return sections
+}
+
+// DefaultExcludePaths returns the paths excluded when GO_COVERAGE_EXCLUDE_PATHS is not set
+func DefaultExcludePaths() []string {
+	return []string{"vendor/", "test/", "testdata/"}
+}
+
+// DefaultExcludeFiles returns the file patterns excluded when GO_COVERAGE_EXCLUDE_FILES is not set
+func DefaultExcludeFiles() []string {
+	return []string{"*_test.go", "*.pb.go"}
+}
+
+// BadgeStyles returns the valid values of GO_COVERAGE_BADGE_STYLE
+func BadgeStyles() []string {
+	return []string{"flat", "flat-square", "for-the-badge"}
+}
+
+// ReportThemes returns the valid values of GO_COVERAGE_REPORT_THEME
+func ReportThemes() []string {
+	return []string{"github-dark", "light", "github-light"}
 }
 
 func contains(slice []string, item string) bool {
@@ -0,0 +1,332 @@
+// Package configlint finds settings that are valid on their own but do not do what was meant:
+// thresholds no run can meet, exclusions that match nothing, history too short for the windows
+// that read it, and misspelled values. Each finding names the setting and how to fix it.
+package configlint
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	analytics "github.com/mrz1836/go-coverage/internal/analytics/history"
+	"github.com/mrz1836/go-coverage/internal/branchmatch"
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+// Severities of findings; errors fail config lint, warnings only with --strict
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Checks that produce findings
+const (
+	CheckThreshold  = "threshold"
+	CheckExclusions = "exclusions"
+	CheckHistory    = "history"
+	CheckBadgeStyle = "badge-style"
+	CheckTheme      = "report-theme"
+)
+
+// Finding is a misconfiguration
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Setting  string   `json:"setting"` // Environment variable to change
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"`
+}
+
+// Input is what the checks inspect
+type Input struct {
+	Config *config.Config
+	// Files are the Go files of the repository relative to its root; nil skips the checks that
+	// match patterns against the repository
+	Files []string
+	// Coverage is the profile parsed with the exclusions of the configuration; nil skips the
+	// checks that need a profile
+	Coverage *parser.CoverageData
+	// Profile is the path Coverage was read from, for messages
+	Profile string
+}
+
+// Lint runs every check, returning errors before warnings
+func Lint(in Input) []Finding {
+	var findings []Finding
+	findings = append(findings, lintThresholds(in)...)
+	findings = append(findings, lintExclusions(in)...)
+	findings = append(findings, lintHistory(in.Config)...)
+	findings = append(findings, lintChoice(CheckBadgeStyle, "GO_COVERAGE_BADGE_STYLE", "badge style", in.Config.Badge.Style, config.BadgeStyles())...)
+	findings = append(findings, lintChoice(CheckTheme, "GO_COVERAGE_REPORT_THEME", "report theme", in.Config.Report.Theme, config.ReportThemes())...)
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Compare(severityRank(a.Severity), severityRank(b.Severity))
+	})
+	return findings
+}
+
+// Errors counts the findings that are errors
+func Errors(findings []Finding) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// severityRank orders errors first
+func severityRank(severity Severity) int {
+	if severity == SeverityError {
+		return 0
+	}
+	return 1
+}
+
+// lintThresholds finds thresholds out of range and thresholds the exclusions leave nothing to
+// measure against
+func lintThresholds(in Input) []Finding {
+	cfg := in.Config
+	var findings []Finding
+	for _, threshold := range []struct {
+		setting string
+		value   float64
+	}{
+		{"GO_COVERAGE_THRESHOLD", cfg.Coverage.Threshold},
+		{"GO_COVERAGE_POLICY_CRITICAL_THRESHOLD", cfg.Policy.CriticalThreshold},
+		{"GO_COVERAGE_POLICY_NEW_FILE_THRESHOLD", cfg.Policy.NewFileThreshold},
+	} {
+		if threshold.value < 0 || threshold.value > 100 {
+			findings = append(findings, Finding{
+				Check: CheckThreshold, Severity: SeverityError, Setting: threshold.setting,
+				Message: fmt.Sprintf("threshold %.2f%% can never be met", threshold.value),
+				Fix:     "set a percentage between 0 and 100",
+			})
+		}
+	}
+
+	if cfg.Coverage.Threshold > 0 && in.Coverage != nil && in.Coverage.TotalLines == 0 {
+		findings = append(findings, Finding{
+			Check: CheckThreshold, Severity: SeverityError, Setting: "GO_COVERAGE_EXCLUDE_PATHS",
+			Message: fmt.Sprintf("the exclusions leave no statements of %s, so the %.2f%% threshold can never be met", in.Profile, cfg.Coverage.Threshold),
+			Fix:     "narrow GO_COVERAGE_EXCLUDE_PATHS, GO_COVERAGE_EXCLUDE_FILES and GO_COVERAGE_EXCLUDE_PRESETS",
+		})
+	}
+
+	if in.Files == nil {
+		return findings
+	}
+	rules, err := cfg.Policy.CriticalPathRules()
+	if err != nil {
+		return findings // Reported by config validation
+	}
+	for _, rule := range rules {
+		matched, measured := false, false
+		for _, file := range in.Files {
+			if !branchmatch.Match(rule.Pattern, file) {
+				continue
+			}
+			matched = true
+			if !excluded(cfg, file) {
+				measured = true
+				break
+			}
+		}
+		if matched && !measured {
+			findings = append(findings, Finding{
+				Check: CheckThreshold, Severity: SeverityWarning, Setting: "GO_COVERAGE_POLICY_CRITICAL_PATHS",
+				Message: fmt.Sprintf("critical path %s only matches excluded files, so its %.2f%% requirement is never checked", rule.Pattern, rule.Threshold),
+				Fix:     "stop excluding the critical code or remove the critical path",
+			})
+		}
+	}
+	return findings
+}
+
+// lintExclusions finds exclusion patterns that are malformed or match no file of the repository
+func lintExclusions(in Input) []Finding {
+	if in.Files == nil {
+		return nil
+	}
+	cfg := in.Config
+	var findings []Finding
+
+	for _, pattern := range cfg.Coverage.ExcludePaths {
+		if pattern == "" || slices.Contains(config.DefaultExcludePaths(), pattern) {
+			continue
+		}
+		if !slices.ContainsFunc(in.Files, func(file string) bool { return strings.Contains(file, pattern) }) {
+			findings = append(findings, Finding{
+				Check: CheckExclusions, Severity: SeverityWarning, Setting: "GO_COVERAGE_EXCLUDE_PATHS",
+				Message: fmt.Sprintf("path %q matches no Go file", pattern),
+				Fix:     "remove it, or give a part of the file paths such as internal/legacy/",
+			})
+		}
+	}
+
+	for _, pattern := range cfg.Coverage.ExcludeFiles {
+		if pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			findings = append(findings, Finding{
+				Check: CheckExclusions, Severity: SeverityError, Setting: "GO_COVERAGE_EXCLUDE_FILES",
+				Message: fmt.Sprintf("pattern %q is not a valid glob: %v", pattern, err),
+				Fix:     "close brackets and escape special characters",
+			})
+			continue
+		}
+		if strings.Contains(pattern, "/") {
+			findings = append(findings, Finding{
+				Check: CheckExclusions, Severity: SeverityWarning, Setting: "GO_COVERAGE_EXCLUDE_FILES",
+				Message: fmt.Sprintf("pattern %q contains a slash, but file patterns are matched against file names only", pattern),
+				Fix:     "move the directory to GO_COVERAGE_EXCLUDE_PATHS and keep the file name pattern",
+			})
+			continue
+		}
+		if slices.Contains(config.DefaultExcludeFiles(), pattern) {
+			continue
+		}
+		if !slices.ContainsFunc(in.Files, func(file string) bool {
+			matched, _ := filepath.Match(pattern, filepath.Base(file))
+			return matched
+		}) {
+			findings = append(findings, Finding{
+				Check: CheckExclusions, Severity: SeverityWarning, Setting: "GO_COVERAGE_EXCLUDE_FILES",
+				Message: fmt.Sprintf("pattern %q matches no Go file", pattern),
+				Fix:     "remove it or correct the glob",
+			})
+		}
+	}
+	return findings
+}
+
+// excluded reports whether the path and file exclusions exclude a file
+func excluded(cfg *config.Config, file string) bool {
+	for _, pattern := range cfg.Coverage.ExcludePaths {
+		if pattern != "" && strings.Contains(file, pattern) {
+			return true
+		}
+	}
+	for _, pattern := range cfg.Coverage.ExcludeFiles {
+		if matched, _ := filepath.Match(pattern, filepath.Base(file)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// runWindow is a setting that reads a number of earlier runs from the history
+type runWindow struct {
+	setting string
+	runs    int
+}
+
+// lintHistory finds windows that read more history than is kept
+func lintHistory(cfg *config.Config) []Finding {
+	var findings []Finding
+	runs := []runWindow{
+		{"GO_COVERAGE_POLICY_DECLINE_RUNS", cfg.Policy.DeclineRuns},
+		{"GO_COVERAGE_POLICY_WARMUP_RUNS", cfg.Policy.WarmupRuns},
+	}
+
+	if !cfg.History.Enabled {
+		for _, window := range runs {
+			if window.runs > 0 {
+				findings = append(findings, Finding{
+					Check: CheckHistory, Severity: SeverityWarning, Setting: window.setting,
+					Message: fmt.Sprintf("%s counts %d earlier runs, but history tracking is disabled", window.setting, window.runs),
+					Fix:     "set GO_COVERAGE_HISTORY_ENABLED=true or unset " + window.setting,
+				})
+			}
+		}
+		return findings
+	}
+
+	longTerm := analytics.DefaultAnalyzerConfig().LongTermDays
+	if cfg.History.RetentionDays > 0 && cfg.History.RetentionDays < longTerm {
+		findings = append(findings, Finding{
+			Check: CheckHistory, Severity: SeverityWarning, Setting: "GO_COVERAGE_HISTORY_RETENTION",
+			Message: fmt.Sprintf("history keeps %d days, but the long-term trend and analyze look back %d days", cfg.History.RetentionDays, longTerm),
+			Fix:     fmt.Sprintf("keep at least %d days of history", longTerm),
+		})
+	}
+	if cfg.History.RetentionDays > 0 && cfg.History.RetentionDays < cfg.Policy.WarmupDays {
+		findings = append(findings, Finding{
+			Check: CheckHistory, Severity: SeverityWarning, Setting: "GO_COVERAGE_HISTORY_RETENTION",
+			Message: fmt.Sprintf("history keeps %d days, but warm-up lasts %d days and restarts once its first run is pruned", cfg.History.RetentionDays, cfg.Policy.WarmupDays),
+			Fix:     fmt.Sprintf("keep at least %d days of history or shorten GO_COVERAGE_POLICY_WARMUP_DAYS", cfg.Policy.WarmupDays),
+		})
+	}
+
+	runs = append(runs, runWindow{"GO_COVERAGE_CONFIDENCE_RUNS", cfg.Policy.ConfidenceRuns})
+	if cfg.GitHub.RegressionIssue {
+		runs = append(runs, runWindow{"GO_COVERAGE_REGRESSION_ISSUE_RUNS", cfg.GitHub.RegressionIssueRuns})
+	}
+	for _, window := range runs {
+		if cfg.History.MaxEntries > 0 && window.runs > cfg.History.MaxEntries {
+			findings = append(findings, Finding{
+				Check: CheckHistory, Severity: SeverityWarning, Setting: "GO_COVERAGE_HISTORY_MAX_ENTRIES",
+				Message: fmt.Sprintf("history keeps %d entries, but %s reads %d runs", cfg.History.MaxEntries, window.setting, window.runs),
+				Fix:     fmt.Sprintf("keep at least %d entries", window.runs),
+			})
+		}
+	}
+	return findings
+}
+
+// lintChoice finds a value that is not one of the choices, suggesting the closest
+func lintChoice(check, setting, name, value string, choices []string) []Finding {
+	if slices.Contains(choices, value) {
+		return nil
+	}
+	finding := Finding{
+		Check: check, Severity: SeverityError, Setting: setting,
+		Message: fmt.Sprintf("unknown %s %q", name, value),
+		Fix:     "use one of " + strings.Join(choices, ", "),
+	}
+	if suggestion := closest(value, choices); suggestion != "" {
+		finding.Fix = fmt.Sprintf("did you mean %q? (one of %s)", suggestion, strings.Join(choices, ", "))
+	}
+	return []Finding{finding}
+}
+
+// closest returns the choice a typo most likely meant, or "" when none is close
+func closest(value string, choices []string) string {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), "_", "-")
+	best, bestDistance := "", 3 // Up to two edits
+	for _, choice := range choices {
+		if distance := editDistance(normalized, choice); distance < bestDistance {
+			best, bestDistance = choice, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
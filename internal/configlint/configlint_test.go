@@ -0,0 +1,120 @@
+package configlint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/config"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// validConfig returns a configuration without findings
+func validConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Coverage.Threshold = 80
+	cfg.Coverage.ExcludePaths = config.DefaultExcludePaths()
+	cfg.Coverage.ExcludeFiles = config.DefaultExcludeFiles()
+	cfg.Policy.CriticalThreshold = 100
+	cfg.Policy.NewFileThreshold = 70
+	cfg.Policy.ConfidenceRuns = 10
+	cfg.History.Enabled = true
+	cfg.History.RetentionDays = 90
+	cfg.History.MaxEntries = 1000
+	cfg.Badge.Style = "flat"
+	cfg.Report.Theme = "github-dark"
+	return cfg
+}
+
+// settings returns the setting of each finding
+func settings(findings []Finding) []string {
+	result := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, finding.Setting)
+	}
+	return result
+}
+
+var files = []string{"main.go", "internal/auth/token.go", "internal/auth/token_test.go", "internal/api/server_mock.go"}
+
+func TestLintValid(t *testing.T) {
+	findings := Lint(Input{Config: validConfig(), Files: files, Coverage: &parser.CoverageData{TotalLines: 10}})
+	assert.Empty(t, findings)
+	assert.Zero(t, Errors(findings))
+}
+
+func TestLintThresholds(t *testing.T) {
+	cfg := validConfig()
+	cfg.Coverage.Threshold = 110
+	cfg.Policy.NewFileThreshold = -1
+	findings := Lint(Input{Config: cfg})
+	assert.Equal(t, []string{"GO_COVERAGE_THRESHOLD", "GO_COVERAGE_POLICY_NEW_FILE_THRESHOLD"}, settings(findings))
+	assert.Equal(t, 2, Errors(findings))
+
+	cfg = validConfig()
+	findings = Lint(Input{Config: cfg, Coverage: &parser.CoverageData{}, Profile: "coverage.txt"})
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+	assert.Contains(t, findings[0].Message, "leave no statements of coverage.txt")
+
+	cfg.Coverage.ExcludePaths = append(cfg.Coverage.ExcludePaths, "internal/auth/")
+	cfg.Policy.CriticalPaths = []string{"internal/auth/**", "internal/api/**=90"}
+	findings = Lint(Input{Config: cfg, Files: files})
+	require.Len(t, findings, 1, "internal/api has a file that is not excluded")
+	assert.Equal(t, CheckThreshold, findings[0].Check)
+	assert.Equal(t, SeverityWarning, findings[0].Severity)
+	assert.Contains(t, findings[0].Message, "internal/auth/**")
+}
+
+func TestLintExclusions(t *testing.T) {
+	cfg := validConfig()
+	cfg.Coverage.ExcludePaths = append(cfg.Coverage.ExcludePaths, "internal/api/", "internal/legacy/")
+	cfg.Coverage.ExcludeFiles = append(cfg.Coverage.ExcludeFiles, "*_mock.go", "*_mocks.go", "internal/*.go", "[x")
+
+	findings := Lint(Input{Config: cfg, Files: files})
+	require.Len(t, findings, 4)
+	assert.Equal(t, SeverityError, findings[0].Severity, "errors come first")
+	assert.Contains(t, findings[0].Message, `"[x" is not a valid glob`)
+	assert.Contains(t, findings[1].Message, `path "internal/legacy/" matches no Go file`)
+	assert.Contains(t, findings[2].Message, `"*_mocks.go" matches no Go file`)
+	assert.Contains(t, findings[3].Message, `"internal/*.go" contains a slash`)
+
+	assert.Len(t, Lint(Input{Config: cfg}), 0, "pattern checks need the repository files")
+}
+
+func TestLintHistory(t *testing.T) {
+	cfg := validConfig()
+	cfg.History.RetentionDays = 14
+	cfg.History.MaxEntries = 5
+	cfg.Policy.WarmupDays = 30
+	cfg.GitHub.RegressionIssue = true
+	cfg.GitHub.RegressionIssueRuns = 3
+	assert.Equal(t, []string{
+		"GO_COVERAGE_HISTORY_RETENTION",
+		"GO_COVERAGE_HISTORY_RETENTION",
+		"GO_COVERAGE_HISTORY_MAX_ENTRIES",
+	}, settings(Lint(Input{Config: cfg})))
+
+	cfg = validConfig()
+	cfg.History.Enabled = false
+	cfg.History.RetentionDays = 1
+	cfg.Policy.DeclineRuns = 3
+	findings := Lint(Input{Config: cfg})
+	assert.Equal(t, []string{"GO_COVERAGE_POLICY_DECLINE_RUNS"}, settings(findings))
+	assert.Contains(t, findings[0].Message, "history tracking is disabled")
+}
+
+func TestLintChoices(t *testing.T) {
+	cfg := validConfig()
+	cfg.Badge.Style = "Flat_Square"
+	cfg.Report.Theme = "solarized"
+	findings := Lint(Input{Config: cfg})
+	require.Len(t, findings, 2)
+	assert.Equal(t, `did you mean "flat-square"? (one of flat, flat-square, for-the-badge)`, findings[0].Fix)
+	assert.Equal(t, "use one of github-dark, light, github-light", findings[1].Fix)
+	assert.Equal(t, 2, Errors(findings))
+
+	assert.Equal(t, 0, editDistance("flat", "flat"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,180 @@
+// Package debt measures coverage debt: the uncovered statements of a profile, weighted by the
+// complexity of the functions they are in and by whether they are on a critical path
+package debt
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ComplexityWeight is the weight each decision point of a function adds to its uncovered
+// statements, so the statements of a function with a cyclomatic complexity of 11 count double
+const ComplexityWeight = 0.1
+
+// Options configure how uncovered statements are weighted
+type Options struct {
+	// Root is the directory the relative paths of the files are read from
+	Root string
+	// Path maps a file of the profile to its path relative to Root
+	Path func(file string) string
+	// Critical reports whether a relative path is on a critical path (optional)
+	Critical func(path string) bool
+	// CriticalWeight multiplies the weight of the statements of critical files
+	CriticalWeight float64
+}
+
+// Report is the coverage debt of a profile
+type Report struct {
+	// Uncovered is the number of statements no test executes
+	Uncovered int `json:"uncovered"`
+	// Debt is the weighted number of uncovered statements
+	Debt float64 `json:"debt"`
+	// Files with uncovered statements, highest debt first
+	Files []File `json:"files"`
+}
+
+// File is the coverage debt of one file
+type File struct {
+	Path      string  `json:"path"`
+	Uncovered int     `json:"uncovered"`
+	Debt      float64 `json:"debt"`
+	Critical  bool    `json:"critical,omitempty"`
+}
+
+// function is the line range and cyclomatic complexity of a function declaration
+type function struct {
+	start, end int
+	complexity int
+}
+
+// Measure weighs every uncovered statement by the complexity of its function and, on critical
+// paths, by the critical weight. Statements outside functions, in files that cannot be parsed and
+// in files of other languages weigh 1.
+func Measure(profile *parser.CoverageData, opts Options) *Report {
+	report := &Report{Files: []File{}}
+	for _, pkg := range profile.Packages {
+		for name, file := range pkg.Files {
+			path := name
+			if opts.Path != nil {
+				path = opts.Path(name)
+			}
+			result := measureFile(file, path, opts)
+			if result.Uncovered == 0 {
+				continue
+			}
+			report.Uncovered += result.Uncovered
+			report.Debt += result.Debt
+			report.Files = append(report.Files, result)
+		}
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		if report.Files[i].Debt != report.Files[j].Debt {
+			return report.Files[i].Debt > report.Files[j].Debt
+		}
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report
+}
+
+// measureFile weighs the uncovered statements of a file
+func measureFile(file *parser.FileCoverage, path string, opts Options) File {
+	result := File{Path: path}
+	factor := 1.0
+	if opts.Critical != nil && opts.Critical(path) {
+		result.Critical = true
+		factor = max(opts.CriticalWeight, 1)
+	}
+
+	if len(file.Statements) == 0 {
+		result.Uncovered = max(file.TotalLines-file.CoveredLines, 0)
+		result.Debt = float64(result.Uncovered) * factor
+		return result
+	}
+
+	var functions []function
+	if file.Language == "" && filepath.Ext(path) == ".go" {
+		functions = parseFunctions(filepath.Join(opts.Root, filepath.FromSlash(path)))
+	}
+	for _, statement := range file.Statements {
+		if statement.Count > 0 || statement.NumStmt == 0 {
+			continue
+		}
+		result.Uncovered += statement.NumStmt
+		result.Debt += float64(statement.NumStmt) * factor * complexityFactor(functions, statement.StartLine)
+	}
+	return result
+}
+
+// complexityFactor is the weight of a statement on a line, from the complexity of its function
+func complexityFactor(functions []function, line int) float64 {
+	for _, fn := range functions {
+		if line >= fn.start && line <= fn.end {
+			return 1 + ComplexityWeight*float64(fn.complexity-1)
+		}
+	}
+	return 1
+}
+
+// parseFunctions returns the functions of a Go file, or nil when it cannot be read or parsed
+func parseFunctions(filename string) []function {
+	src, err := os.ReadFile(filename) //nolint:gosec // file of the coverage profile under the repository root
+	if err != nil {
+		return nil
+	}
+	return functionsOf(src)
+}
+
+// functionsOf returns the function declarations of Go source with their complexity
+func functionsOf(src []byte) []function {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "", src, goparser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var functions []function
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		functions = append(functions, function{
+			start:      fset.Position(fn.Pos()).Line,
+			end:        fset.Position(fn.End()).Line,
+			complexity: Complexity(fn),
+		})
+	}
+	return functions
+}
+
+// Complexity returns the cyclomatic complexity of a function: one plus its decision points, the
+// branches, loops, cases and boolean operators, including those of the closures it declares
+func Complexity(fn *ast.FuncDecl) int {
+	complexity := 1
+	ast.Inspect(fn, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
@@ -0,0 +1,94 @@
+package debt
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+const source = `package app
+
+var ready = true
+
+func Simple() int {
+	return 1
+}
+
+func Branchy(values []int, ok bool) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 && ok {
+			total += v
+		}
+	}
+	switch total {
+	case 0:
+		return -1
+	default:
+		return total
+	}
+}
+`
+
+func TestComplexity(t *testing.T) {
+	file, err := goparser.ParseFile(token.NewFileSet(), "", source, 0)
+	require.NoError(t, err)
+
+	complexities := map[string]int{}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			complexities[fn.Name.Name] = Complexity(fn)
+		}
+	}
+	// Branchy: 1 + range + if + && + case 0
+	assert.Equal(t, map[string]int{"Simple": 1, "Branchy": 5}, complexities)
+
+	assert.Equal(t, []function{{start: 5, end: 7, complexity: 1}, {start: 9, end: 22, complexity: 5}}, functionsOf([]byte(source)))
+	assert.Nil(t, functionsOf([]byte("not go")))
+}
+
+func TestMeasure(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "app"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app", "app.go"), []byte(source), 0o600))
+
+	profile := &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"example.com/repo/app": {Files: map[string]*parser.FileCoverage{
+			"example.com/repo/app/app.go": {Statements: []parser.Statement{
+				{StartLine: 6, NumStmt: 1, Count: 0},  // Simple, weight 1
+				{StartLine: 10, NumStmt: 2, Count: 1}, // covered
+				{StartLine: 13, NumStmt: 2, Count: 0}, // Branchy, weight 1.4
+			}},
+			"example.com/repo/app/covered.go": {Statements: []parser.Statement{{StartLine: 3, NumStmt: 4, Count: 2}}},
+		}},
+		"example.com/repo/billing": {Files: map[string]*parser.FileCoverage{
+			"example.com/repo/billing/pay.go": {Statements: []parser.Statement{{StartLine: 1, NumStmt: 3}}}, // missing on disk
+		}},
+		"example.com/repo/web": {Files: map[string]*parser.FileCoverage{
+			"example.com/repo/web/app.ts": {Language: "typescript", TotalLines: 10, CoveredLines: 6},
+		}},
+	}}
+
+	report := Measure(profile, Options{
+		Root:           root,
+		Path:           func(file string) string { return strings.TrimPrefix(file, "example.com/repo/") },
+		Critical:       func(path string) bool { return strings.HasPrefix(path, "billing/") },
+		CriticalWeight: 2,
+	})
+	assert.Equal(t, 10, report.Uncovered)
+	assert.InDelta(t, 1+2.8+6+4, report.Debt, 0.0001)
+	require.Len(t, report.Files, 3)
+	assert.Equal(t, File{Path: "billing/pay.go", Uncovered: 3, Debt: 6, Critical: true}, report.Files[0])
+	assert.Equal(t, "web/app.ts", report.Files[1].Path)
+	assert.Equal(t, "app/app.go", report.Files[2].Path)
+	assert.InDelta(t, 3.8, report.Files[2].Debt, 0.0001)
+}
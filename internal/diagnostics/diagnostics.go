@@ -0,0 +1,176 @@
+// Package diagnostics classifies common failures into stable error codes with remediation text
+// and a link to the documentation, so that logs, step summaries and JSON output tell users how
+// to fix a problem instead of only what went wrong.
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// Code is a stable error code. Codes are never reused or renumbered.
+type Code string
+
+// Error codes, grouped by area: 1xx coverage profiles, 2xx GitHub token, 3xx GitHub Pages,
+// 4xx workflow artifacts
+const (
+	CodeProfileNotFound  Code = "GCV101"
+	CodeProfileInvalid   Code = "GCV102"
+	CodeProfileTooLarge  Code = "GCV103"
+	CodeTokenRejected    Code = "GCV201"
+	CodeTokenForbidden   Code = "GCV202"
+	CodePagesNotEnabled  Code = "GCV301"
+	CodeArtifactExpired  Code = "GCV401"
+	CodeArtifactNotFound Code = "GCV402"
+)
+
+// DocsURL is the page documenting every error code; each code is an anchor on it
+const DocsURL = "https://github.com/mrz1836/go-coverage/blob/master/docs/errors.md"
+
+// Entry describes an error code
+type Entry struct {
+	Code        Code
+	Title       string
+	Remediation string
+}
+
+// catalog is every error code with its remediation
+//
+//nolint:gochecknoglobals // read-only catalog
+var catalog = []Entry{
+	{CodeProfileNotFound, "Coverage profile not found",
+		"Check the profile path (--input or GO_COVERAGE_INPUT_FILE) and that `go test -coverprofile` ran before this step"},
+	{CodeProfileInvalid, "Coverage profile is not valid",
+		"Pass the file written by `go test -coverprofile`; the first line must be `mode: set`, `count` or `atomic`"},
+	{CodeProfileTooLarge, "Coverage profile exceeds a size limit",
+		"Raise the GO_COVERAGE_MAX_PROFILE_* limits or exclude generated code from the profile"},
+	{CodeTokenRejected, "GitHub rejected the token",
+		"The token is invalid or expired; create a new one or use the workflow's GITHUB_TOKEN"},
+	{CodeTokenForbidden, "The token lacks a permission",
+		"Grant the workflow `statuses: write`, `pull-requests: write` and `actions: read` as needed, or run `go-coverage health`"},
+	{CodePagesNotEnabled, "GitHub Pages is not enabled",
+		"Run `go-coverage setup-pages` and set the Pages source to GitHub Actions under Settings → Pages"},
+	{CodeArtifactExpired, "Workflow artifact has expired",
+		"Artifacts are deleted after their retention period; re-run the workflow that uploaded it"},
+	{CodeArtifactNotFound, "Workflow artifact not found",
+		"Check the artifact name (--artifact) and that the triggering run uploaded it"},
+}
+
+// Lookup returns the catalog entry of a code
+func Lookup(code Code) (Entry, bool) {
+	for _, entry := range catalog {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Catalog returns every error code
+func Catalog() []Entry {
+	return append([]Entry(nil), catalog...)
+}
+
+// Error attaches an error code to an error found outside the classified packages
+type Error struct {
+	Code Code
+	Err  error
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches an error code to an error
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Diagnosis is a classified failure
+type Diagnosis struct {
+	Code        Code   `json:"code"`
+	Title       string `json:"title"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation"`
+	DocsURL     string `json:"docs_url"`
+}
+
+// Classify returns the diagnosis of an error, or false when it has no error code
+func Classify(err error) (*Diagnosis, bool) {
+	if err == nil {
+		return nil, false
+	}
+	code, ok := classify(err)
+	if !ok {
+		return nil, false
+	}
+	entry, _ := Lookup(code)
+	return &Diagnosis{
+		Code:        code,
+		Title:       entry.Title,
+		Message:     err.Error(),
+		Remediation: entry.Remediation,
+		DocsURL:     DocsURL + "#" + strings.ToLower(string(code)),
+	}, true
+}
+
+// classify finds the error code of an error
+func classify(err error) (Code, bool) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+
+	switch {
+	case errors.Is(err, parser.ErrProfileNotFound):
+		return CodeProfileNotFound, true
+	case errors.Is(err, parser.ErrInvalidCoverageMode), errors.Is(err, parser.ErrMissingModeDeclaration),
+		errors.Is(err, parser.ErrInvalidStatementFormat), errors.Is(err, parser.ErrMissingColon),
+		errors.Is(err, parser.ErrMissingComma), errors.Is(err, parser.ErrMissingDot):
+		return CodeProfileInvalid, true
+	case errors.Is(err, parser.ErrProfileTooLarge), errors.Is(err, parser.ErrLineTooLong),
+		errors.Is(err, parser.ErrTooManyFiles), errors.Is(err, parser.ErrTooManyBlocks):
+		return CodeProfileTooLarge, true
+	case errors.Is(err, github.ErrBadCredentials):
+		return CodeTokenRejected, true
+	case errors.Is(err, github.ErrArtifactExpired):
+		return CodeArtifactExpired, true
+	case errors.Is(err, github.ErrArtifactNotFound):
+		return CodeArtifactNotFound, true
+	}
+
+	switch github.APIStatus(err) {
+	case http.StatusUnauthorized:
+		return CodeTokenRejected, true
+	case http.StatusForbidden:
+		return CodeTokenForbidden, true
+	case http.StatusGone:
+		return CodeArtifactExpired, true
+	}
+	return "", false
+}
+
+// Text renders a diagnosis for logs
+func (d *Diagnosis) Text() string {
+	return fmt.Sprintf("[%s] %s\n  Fix: %s\n  Docs: %s\n", d.Code, d.Title, d.Remediation, d.DocsURL)
+}
+
+// Markdown renders a diagnosis for the workflow step summary
+func (d *Diagnosis) Markdown() string {
+	return fmt.Sprintf("> [!CAUTION]\n> **[%s](%s) %s**\n>\n> `%s`\n>\n> %s",
+		d.Code, d.DocsURL, d.Title, d.Message, d.Remediation)
+}
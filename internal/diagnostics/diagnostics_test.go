@@ -0,0 +1,77 @@
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code Code
+	}{
+		{"missing profile", fmt.Errorf("failed to parse coverage file: %w", parser.ErrProfileNotFound), CodeProfileNotFound},
+		{"invalid profile", fmt.Errorf("failed to parse coverage file: %w", parser.ErrMissingModeDeclaration), CodeProfileInvalid},
+		{"profile limit", parser.ErrTooManyBlocks, CodeProfileTooLarge},
+		{"bad credentials", github.ErrBadCredentials, CodeTokenRejected},
+		{"api 401", fmt.Errorf("failed to create comment: %w", fmt.Errorf("%w: 401 Bad credentials", github.ErrGitHubAPIError)), CodeTokenRejected},
+		{"api 403", fmt.Errorf("%w: 403 Resource not accessible by integration", github.ErrGitHubAPIError), CodeTokenForbidden},
+		{"api 410", fmt.Errorf("%w: 410 Gone", github.ErrGitHubAPIError), CodeArtifactExpired},
+		{"expired artifact", github.ErrArtifactExpired, CodeArtifactExpired},
+		{"missing artifact", github.ErrArtifactNotFound, CodeArtifactNotFound},
+		{"wrapped", fmt.Errorf("health: %w", Wrap(CodePagesNotEnabled, errors.New("health check failed"))), CodePagesNotEnabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosis, ok := Classify(tt.err)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, diagnosis.Code)
+			assert.Equal(t, tt.err.Error(), diagnosis.Message)
+			assert.NotEmpty(t, diagnosis.Remediation)
+			assert.Equal(t, DocsURL+"#"+strings.ToLower(string(tt.code)), diagnosis.DocsURL)
+		})
+	}
+
+	for _, err := range []error{nil, errors.New("boom"), fmt.Errorf("%w: 500 oops", github.ErrGitHubAPIError)} {
+		_, ok := Classify(err)
+		assert.False(t, ok, "%v", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	require.NoError(t, Wrap(CodeTokenForbidden, nil))
+
+	base := errors.New("denied")
+	err := Wrap(CodeTokenForbidden, base)
+	require.ErrorIs(t, err, base)
+	assert.Equal(t, "denied", err.Error())
+}
+
+func TestRendering(t *testing.T) {
+	diagnosis, ok := Classify(github.ErrBadCredentials)
+	require.True(t, ok)
+	assert.Equal(t, "[GCV201] GitHub rejected the token\n  Fix: "+diagnosis.Remediation+"\n  Docs: "+DocsURL+"#gcv201\n", diagnosis.Text())
+	assert.Contains(t, diagnosis.Markdown(), "**["+string(CodeTokenRejected)+"]("+DocsURL+"#gcv201) GitHub rejected the token**")
+}
+
+func TestCatalogIsDocumented(t *testing.T) {
+	docs, err := os.ReadFile("../../docs/errors.md")
+	require.NoError(t, err)
+
+	seen := map[Code]bool{}
+	for _, entry := range Catalog() {
+		assert.False(t, seen[entry.Code], "duplicate code %s", entry.Code)
+		seen[entry.Code] = true
+		assert.Contains(t, string(docs), "### "+string(entry.Code)+"\n", "docs/errors.md should document %s", entry.Code)
+	}
+}
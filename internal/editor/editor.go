@@ -0,0 +1,154 @@
+// Package editor writes coverage in the formats read by editor plugins for in-editor gutter highlighting
+package editor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+// ErrUnknownFormat indicates an editor output format that is not supported
+var ErrUnknownFormat = errors.New("unknown editor output format")
+
+// Supported editor output formats
+const (
+	// FormatLCOV is the lcov tracefile read by Coverage Gutters and most other editor plugins
+	FormatLCOV = "lcov"
+	// FormatJSON is a per-line hit count document for VS Code extensions and scripts
+	FormatJSON = "json"
+)
+
+// Output file names, chosen so plugins pick them up from the repository root without configuration
+const (
+	LCOVFile = "lcov.info"
+	JSONFile = "coverage.json"
+)
+
+// Formats returns the supported editor output formats
+func Formats() []string {
+	return []string{FormatLCOV, FormatJSON}
+}
+
+// ValidateFormat returns ErrUnknownFormat when format is not one of Formats
+func ValidateFormat(format string) error {
+	if !slices.Contains(Formats(), format) {
+		return fmt.Errorf("%w: %q (supported: %s)", ErrUnknownFormat, format, strings.Join(Formats(), ", "))
+	}
+	return nil
+}
+
+// File is the line coverage of one source file
+type File struct {
+	Path  string `json:"path"`
+	Lines []Line `json:"lines"`
+}
+
+// Line is the hit count of one source line
+type Line struct {
+	Number int `json:"line"`
+	Hits   int `json:"hits"`
+}
+
+// jsonDocument is the layout of the JSON output format
+type jsonDocument struct {
+	Version int    `json:"version"`
+	Files   []File `json:"files"`
+}
+
+// Files converts coverage data into per-line hit counts, sorted by path. A line spanned by
+// several blocks takes the highest count. resolvePath maps profile paths to paths relative
+// to the repository root; nil keeps them as they are.
+func Files(coverage *parser.CoverageData, resolvePath func(string) string) []File {
+	if coverage == nil {
+		return nil
+	}
+
+	var files []File
+	for _, pkg := range coverage.Packages {
+		for path, file := range pkg.Files {
+			hits := make(map[int]int)
+			for _, stmt := range file.Statements {
+				for line := stmt.StartLine; line <= stmt.EndLine; line++ {
+					if current, ok := hits[line]; !ok || stmt.Count > current {
+						hits[line] = stmt.Count
+					}
+				}
+			}
+
+			lines := make([]Line, 0, len(hits))
+			for number, count := range hits {
+				lines = append(lines, Line{Number: number, Hits: count})
+			}
+			slices.SortFunc(lines, func(a, b Line) int { return a.Number - b.Number })
+
+			if resolvePath != nil {
+				path = resolvePath(path)
+			}
+			files = append(files, File{Path: filepath.ToSlash(path), Lines: lines})
+		}
+	}
+	slices.SortFunc(files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	return files
+}
+
+// WriteLCOV writes files as an lcov tracefile
+func WriteLCOV(w io.Writer, files []File) error {
+	buf := bufio.NewWriter(w)
+	for _, file := range files {
+		hit := 0
+		_, _ = fmt.Fprintf(buf, "SF:%s\n", file.Path)
+		for _, line := range file.Lines {
+			_, _ = fmt.Fprintf(buf, "DA:%d,%d\n", line.Number, line.Hits)
+			if line.Hits > 0 {
+				hit++
+			}
+		}
+		_, _ = fmt.Fprintf(buf, "LF:%d\nLH:%d\nend_of_record\n", len(file.Lines), hit)
+	}
+	return buf.Flush()
+}
+
+// WriteJSON writes files as a JSON document of per-line hit counts
+func WriteJSON(w io.Writer, files []File) error {
+	if files == nil {
+		files = []File{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonDocument{Version: 1, Files: files})
+}
+
+// Write writes files to dir in each of the given formats and returns the paths written
+func Write(dir string, formats []string, files []File, fileMode os.FileMode) ([]string, error) {
+	written := make([]string, 0, len(formats))
+	for _, format := range formats {
+		if err := ValidateFormat(format); err != nil {
+			return written, err
+		}
+
+		name, write := LCOVFile, WriteLCOV
+		if format == FormatJSON {
+			name, write = JSONFile, WriteJSON
+		}
+
+		var buf bytes.Buffer
+		if err := write(&buf, files); err != nil {
+			return written, fmt.Errorf("failed to encode %s output: %w", format, err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), fileMode); err != nil {
+			return written, fmt.Errorf("failed to write %s output: %w", format, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
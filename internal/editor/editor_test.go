@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/parser"
+)
+
+func sampleCoverage() *parser.CoverageData {
+	return &parser.CoverageData{Packages: map[string]*parser.PackageCoverage{
+		"lib": {Files: map[string]*parser.FileCoverage{
+			"repo/lib/lib.go": {Statements: []parser.Statement{
+				{StartLine: 10, EndLine: 12, NumStmt: 2, Count: 3},
+				{StartLine: 12, EndLine: 13, NumStmt: 1, Count: 0},
+			}},
+		}},
+		"api": {Files: map[string]*parser.FileCoverage{
+			"repo/api/api.go": {Statements: []parser.Statement{
+				{StartLine: 5, EndLine: 5, NumStmt: 1, Count: 0},
+			}},
+		}},
+	}}
+}
+
+func TestFiles(t *testing.T) {
+	files := Files(sampleCoverage(), func(path string) string { return strings.TrimPrefix(path, "repo/") })
+
+	assert.Equal(t, []File{
+		{Path: "api/api.go", Lines: []Line{{Number: 5, Hits: 0}}},
+		{Path: "lib/lib.go", Lines: []Line{{Number: 10, Hits: 3}, {Number: 11, Hits: 3}, {Number: 12, Hits: 3}, {Number: 13, Hits: 0}}},
+	}, files)
+	assert.Nil(t, Files(nil, nil))
+}
+
+func TestWriteLCOV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteLCOV(&buf, Files(sampleCoverage(), nil)))
+
+	assert.Equal(t, `SF:repo/api/api.go
+DA:5,0
+LF:1
+LH:0
+end_of_record
+SF:repo/lib/lib.go
+DA:10,3
+DA:11,3
+DA:12,3
+DA:13,0
+LF:4
+LH:3
+end_of_record
+`, buf.String())
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	written, err := Write(dir, []string{FormatLCOV, FormatJSON}, Files(sampleCoverage(), nil), 0o600)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, LCOVFile), filepath.Join(dir, JSONFile)}, written)
+
+	data, err := os.ReadFile(filepath.Join(dir, JSONFile)) //nolint:gosec // test file path
+	require.NoError(t, err)
+	var document jsonDocument
+	require.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, 1, document.Version)
+	require.Len(t, document.Files, 2)
+	assert.Equal(t, Line{Number: 13, Hits: 0}, document.Files[1].Lines[3])
+
+	_, err = Write(dir, []string{"cobertura"}, nil, 0o600)
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -91,6 +91,11 @@ func loadFile(filename string, overload bool) error {
 	return nil
 }
 
+// Parse parses the content of an env file into its key-value pairs without setting them
+func Parse(data []byte) map[string]string {
+	return parse(string(data))
+}
+
 // parse parses the content of an env file and returns a map of key-value pairs.
 func parse(content string) map[string]string {
 	envMap := make(map[string]string)
@@ -0,0 +1,204 @@
+// Package examples finds the Example functions and fuzz targets of a module's tests and tells
+// whether go test exercises them: examples only run when they declare their output, and fuzz
+// targets only run their seed corpus when they have one
+package examples
+
+import (
+	"go/ast"
+	"go/doc"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Function kinds
+const (
+	KindExample = "example"
+	KindFuzz    = "fuzz"
+)
+
+// Function is an Example function or fuzz target of a test file
+type Function struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	File string `json:"file"` // Path relative to the scanned root, slash-separated
+	Line int    `json:"line"`
+	// Exercised is whether go test runs the function: examples with an output comment and fuzz
+	// targets with seed inputs from f.Add or testdata/fuzz
+	Exercised bool `json:"exercised"`
+}
+
+// Report lists the Example functions and fuzz targets of a module in path order
+type Report struct {
+	Functions []Function `json:"functions"`
+}
+
+// Scan parses the test files below root the way go test finds packages, skipping vendor and
+// testdata directories and directories starting with "." or "_". Files that cannot be parsed
+// are skipped.
+func Scan(root string) (*Report, error) {
+	report := &Report{Functions: []Function{}}
+	err := filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != root && skipDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(entry.Name(), "_test.go") {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, name)
+		if relErr != nil {
+			return nil //nolint:nilerr // files outside the root cannot be reported
+		}
+		report.Functions = append(report.Functions, scanFile(name, filepath.ToSlash(rel))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(report.Functions, func(i, j int) bool {
+		a, b := report.Functions[i], report.Functions[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// Count returns the number of functions of a kind and how many of them go test exercises
+func (r *Report) Count(kind string) (total, exercised int) {
+	for _, fn := range r.Functions {
+		if fn.Kind != kind {
+			continue
+		}
+		total++
+		if fn.Exercised {
+			exercised++
+		}
+	}
+	return total, exercised
+}
+
+// Idle returns the functions go test does not exercise
+func (r *Report) Idle() []Function {
+	var idle []Function
+	for _, fn := range r.Functions {
+		if !fn.Exercised {
+			idle = append(idle, fn)
+		}
+	}
+	return idle
+}
+
+// skipDir reports whether go test ignores a directory
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// scanFile returns the Example functions and fuzz targets of a test file
+func scanFile(name, rel string) []Function {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, name, nil, goparser.ParseComments)
+	if err != nil {
+		return nil
+	}
+
+	var functions []Function
+	for _, example := range doc.Examples(file) {
+		functions = append(functions, Function{
+			Name:      "Example" + example.Name,
+			Kind:      KindExample,
+			File:      rel,
+			Line:      fset.Position(example.Code.Pos()).Line,
+			Exercised: example.Output != "" || example.EmptyOutput,
+		})
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !isFuzzTarget(fn) {
+			continue
+		}
+		functions = append(functions, Function{
+			Name:      fn.Name.Name,
+			Kind:      KindFuzz,
+			File:      rel,
+			Line:      fset.Position(fn.Pos()).Line,
+			Exercised: addsSeeds(fn) || hasSeedCorpus(filepath.Dir(name), fn.Name.Name),
+		})
+	}
+	return functions
+}
+
+// isFuzzTarget reports whether a function is a fuzz target go test runs: a top-level FuzzXxx
+// function taking a *testing.F
+func isFuzzTarget(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || !hasTestPrefix(fn.Name.Name, "Fuzz") || fn.Type.Params == nil || len(fn.Type.Params.List) != 1 {
+		return false
+	}
+	star, ok := fn.Type.Params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "F" {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "testing"
+}
+
+// hasTestPrefix reports whether name starts with prefix followed by nothing or a character
+// that is not a lower-case letter, as go test requires of test function names
+func hasTestPrefix(name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !unicode.IsLower(r)
+}
+
+// addsSeeds reports whether a fuzz target adds seed inputs by calling Add on its *testing.F
+func addsSeeds(fn *ast.FuncDecl) bool {
+	names := fn.Type.Params.List[0].Names
+	if len(names) == 0 || names[0].Name == "_" {
+		return false
+	}
+	param := names[0].Name
+
+	found := false
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Add" {
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == param {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// hasSeedCorpus reports whether testdata/fuzz/<target> of the package holds seed inputs
+func hasSeedCorpus(dir, target string) bool {
+	entries, err := os.ReadDir(filepath.Join(dir, "testdata", "fuzz", target))
+	return err == nil && len(entries) > 0
+}
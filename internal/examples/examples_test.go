@@ -0,0 +1,99 @@
+package examples
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile creates a file with the content at the slash-separated path below root
+func writeFile(t *testing.T, root, file, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(file))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "lib/example_test.go", `package lib_test
+
+import "fmt"
+
+func ExampleParse() {
+	fmt.Println("parsed")
+	// Output: parsed
+}
+
+func ExampleParse_silent() {
+	fmt.Println("never checked")
+}
+
+func ExampleFormat() {
+	// Output:
+}
+
+func Examplelower() {}
+`)
+	writeFile(t, root, "lib/fuzz_test.go", `package lib
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	f.Add("seed")
+	f.Fuzz(func(t *testing.T, s string) {})
+}
+
+func FuzzFormat(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {})
+}
+
+func FuzzDecode(fz *testing.F) {
+	fz.Fuzz(func(t *testing.T, b []byte) {})
+}
+
+func Fuzzy(f *testing.F) {}
+
+func FuzzHelper(t *testing.T) {}
+`)
+	writeFile(t, root, "lib/testdata/fuzz/FuzzDecode/seed1", "go test fuzz v1\n[]byte(\"x\")\n")
+	writeFile(t, root, "lib/broken_test.go", "package lib\n\nfunc ExampleBroken( {\n")
+	writeFile(t, root, "_scratch/skip_test.go", "package scratch\n\nfunc ExampleSkipped() {}\n")
+	writeFile(t, root, "vendor/dep/dep_test.go", "package dep\n\nfunc ExampleVendored() {}\n")
+
+	report, err := Scan(root)
+	require.NoError(t, err)
+
+	exercised := make(map[string]bool, len(report.Functions))
+	for _, fn := range report.Functions {
+		exercised[fn.Name] = fn.Exercised
+	}
+	assert.Equal(t, map[string]bool{
+		"ExampleParse":        true,
+		"ExampleParse_silent": false,
+		"ExampleFormat":       true,
+		"FuzzParse":           true,
+		"FuzzFormat":          false,
+		"FuzzDecode":          true,
+	}, exercised)
+
+	total, run := report.Count(KindExample)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, 2, run)
+	total, run = report.Count(KindFuzz)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, 2, run)
+
+	idle := report.Idle()
+	require.Len(t, idle, 2)
+	assert.Equal(t, Function{Name: "ExampleParse_silent", Kind: KindExample, File: "lib/example_test.go", Line: 10}, idle[0])
+	assert.Equal(t, "FuzzFormat", idle[1].Name)
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	_, err := Scan(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
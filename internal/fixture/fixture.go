@@ -0,0 +1,368 @@
+// Package fixture records the HTTP interactions, input files and environment of a run into a
+// fixture directory and replays them, so a pipeline run can be reproduced offline and
+// deterministically - in integration tests and when debugging a run that behaved differently
+// than expected.
+//
+// A fixture directory holds:
+//
+//	manifest.json           the command and the GO_COVERAGE_* and GITHUB_* environment of the run
+//	http/<key>-<n>.json     the n-th response to the request identified by key
+//	inputs/<path>           copies of the input files read by the run
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/redact"
+	"github.com/mrz1836/go-coverage/internal/schema"
+)
+
+// Static error definitions
+var (
+	ErrNoInteraction = errors.New("no recorded interaction for request")
+	ErrNoManifest    = errors.New("fixture directory has no manifest")
+)
+
+// File layout of a fixture directory
+const (
+	manifestFile    = "manifest.json"
+	interactionsDir = "http"
+	inputsDir       = "inputs"
+)
+
+// Directory and file modes of recorded fixtures
+const (
+	dirMode  = 0o750
+	fileMode = 0o600
+)
+
+// InteractionSchema versions recorded interactions
+//
+//nolint:gochecknoglobals // read-only schema definition
+var InteractionSchema = schema.Schema{
+	Name:       "fixture interaction",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// ManifestSchema versions fixture manifests
+//
+//nolint:gochecknoglobals // read-only schema definition
+var ManifestSchema = schema.Schema{
+	Name:       "fixture manifest",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// Interaction is one recorded HTTP request and its response. Secrets are masked in the URL
+// and both bodies; request headers are not recorded because they carry the credentials.
+type Interaction struct {
+	SchemaVersion int         `json:"schema_version"`
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestBody   string      `json:"request_body,omitempty"`
+	Status        int         `json:"status"`
+	Header        http.Header `json:"header,omitempty"`
+	Body          string      `json:"body"`
+}
+
+// Manifest describes the run a fixture was recorded from
+type Manifest struct {
+	SchemaVersion int               `json:"schema_version"`
+	RecordedAt    time.Time         `json:"recorded_at"`
+	Command       []string          `json:"command"`
+	Environment   map[string]string `json:"environment"`
+}
+
+// store hands out the sequence numbers of repeated requests. Every recording or replaying
+// HTTP client of a process shares the store of a directory, so the n-th identical request is
+// answered by the n-th recorded response whichever client sends it.
+type store struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// storeKey identifies the store of a fixture directory; recording and replaying keep
+// separate sequences
+type storeKey struct {
+	dir    string
+	replay bool
+}
+
+//nolint:gochecknoglobals // process-wide request sequence per fixture directory
+var (
+	storesMu sync.Mutex
+	stores   = map[storeKey]*store{}
+)
+
+// storeFor returns the recording or replaying store of a fixture directory
+func storeFor(dir string, replay bool) *store {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	key := storeKey{dir: filepath.Clean(dir), replay: replay}
+	s, ok := stores[key]
+	if !ok {
+		s = &store{counts: map[string]int{}}
+		stores[key] = s
+	}
+	return s
+}
+
+// rewind starts the request sequences of a fixture directory over
+func rewind(dir string) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	dir = filepath.Clean(dir)
+	delete(stores, storeKey{dir: dir})
+	delete(stores, storeKey{dir: dir, replay: true})
+}
+
+// next returns the sequence number of the next request with key
+func (s *store) next(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.counts[key]
+	s.counts[key] = n + 1
+	return n
+}
+
+// requestKey identifies requests by method and URL, with secrets in the URL masked so the key
+// does not depend on the credentials of the run
+func requestKey(req *http.Request) (key, url string) {
+	url = builtinRedactor().String(req.URL.String())
+	sum := sha256.Sum256([]byte(req.Method + " " + url))
+	return hex.EncodeToString(sum[:8]), url
+}
+
+// builtinRedactor masks the token patterns that are redacted regardless of configuration
+func builtinRedactor() *redact.Redactor {
+	// New cannot fail without patterns
+	r, _ := redact.New(nil)
+	return r
+}
+
+// interactionPath returns the file of the n-th response to the request with key
+func interactionPath(dir, key string, n int) string {
+	return filepath.Join(dir, interactionsDir, fmt.Sprintf("%s-%d.json", key, n))
+}
+
+// recorder is an http.RoundTripper that saves every response it passes through
+type recorder struct {
+	dir      string
+	next     http.RoundTripper
+	redactor *redact.Redactor
+	store    *store
+}
+
+// NewRecorder returns a transport that sends requests with next and records each response in
+// dir, with the secrets known to redactor masked. A nil redactor masks the built-in patterns.
+func NewRecorder(dir string, next http.RoundTripper, redactor *redact.Redactor) http.RoundTripper {
+	if redactor == nil {
+		redactor = builtinRedactor()
+	}
+	return &recorder{dir: dir, next: next, redactor: redactor, store: storeFor(dir, false)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		_ = req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	key, url := requestKey(req)
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	interaction := Interaction{
+		SchemaVersion: InteractionSchema.Version(),
+		Method:        req.Method,
+		URL:           url,
+		RequestBody:   r.redactor.String(string(requestBody)),
+		Status:        resp.StatusCode,
+		Header:        header,
+		Body:          r.redactor.String(string(body)),
+	}
+	if err = writeJSON(interactionPath(r.dir, key, r.store.next(key)), interaction); err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// player is an http.RoundTripper that answers requests from recorded interactions
+type player struct {
+	dir   string
+	store *store
+}
+
+// NewPlayer returns a transport that answers every request with the response recorded in dir
+// and fails requests that were not recorded with ErrNoInteraction. It never touches the network.
+func NewPlayer(dir string) http.RoundTripper {
+	return &player{dir: dir, store: storeFor(dir, true)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (p *player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	key, url := requestKey(req)
+	n := p.store.next(key)
+	data, err := os.ReadFile(interactionPath(p.dir, key, n))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (call %d)", ErrNoInteraction, req.Method, url, n+1)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interaction: %w", err)
+	}
+
+	var interaction Interaction
+	if err = InteractionSchema.Decode(data, &interaction); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header,
+		Body:          io.NopCloser(strings.NewReader(interaction.Body)),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       req,
+	}, nil
+}
+
+// InputPath returns where the copy of an input file is kept in a fixture directory. Relative
+// paths keep their layout below inputs/, and absolute paths are nested under it.
+func InputPath(dir, path string) string {
+	return filepath.Join(dir, inputsDir, filepath.Clean(string(filepath.Separator)+path))
+}
+
+// RecordInput copies an input file into a fixture directory
+func RecordInput(dir, path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // input file of the recorded run
+	if err != nil {
+		return fmt.Errorf("failed to read input %s: %w", path, err)
+	}
+	target := InputPath(dir, path)
+	if err = os.MkdirAll(filepath.Dir(target), dirMode); err != nil {
+		return fmt.Errorf("failed to create fixture inputs directory: %w", err)
+	}
+	if err = os.WriteFile(target, data, fileMode); err != nil {
+		return fmt.Errorf("failed to record input %s: %w", path, err)
+	}
+	return nil
+}
+
+// recordedEnvPrefixes select the environment variables recorded in the manifest
+func recordedEnvPrefixes() []string {
+	return []string{"GO_COVERAGE_", "GITHUB_"}
+}
+
+// NewManifest captures the command and the GO_COVERAGE_* and GITHUB_* environment of a run.
+// The values of secret variables are replaced by redact.Mask, so a replay still sees them set,
+// and variables named in skip are left out.
+func NewManifest(command []string, environ []string, skip ...string) *Manifest {
+	secrets := redact.SecretEnvVars()
+	environment := make(map[string]string)
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || slices.Contains(skip, name) {
+			continue
+		}
+		if !slices.ContainsFunc(recordedEnvPrefixes(), func(prefix string) bool { return strings.HasPrefix(name, prefix) }) {
+			continue
+		}
+		if slices.Contains(secrets, name) && value != "" {
+			value = redact.Mask
+		}
+		environment[name] = value
+	}
+
+	return &Manifest{
+		SchemaVersion: ManifestSchema.Version(),
+		RecordedAt:    time.Now().UTC(),
+		Command:       command,
+		Environment:   environment,
+	}
+}
+
+// StartRecording prepares dir for recording a run: interactions and inputs of an earlier
+// recording are removed, the request sequence starts over and the manifest is written
+func StartRecording(dir string, manifest *Manifest) error {
+	for _, sub := range []string{interactionsDir, inputsDir} {
+		if err := os.RemoveAll(filepath.Join(dir, sub)); err != nil {
+			return fmt.Errorf("failed to clear fixture directory: %w", err)
+		}
+	}
+	rewind(dir)
+	if err := writeJSON(filepath.Join(dir, manifestFile), manifest); err != nil {
+		return fmt.Errorf("failed to write fixture manifest: %w", err)
+	}
+	return nil
+}
+
+// StartReplay starts the request sequence of dir over and returns the manifest of its recording
+func StartReplay(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile)) //nolint:gosec // fixture directory given by the user
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoManifest, dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err = ManifestSchema.Decode(data, &manifest); err != nil {
+		return nil, err
+	}
+	rewind(dir)
+	return &manifest, nil
+}
+
+// writeJSON writes v as indented JSON, creating the parent directory
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, fileMode)
+}
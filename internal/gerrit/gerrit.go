@@ -0,0 +1,227 @@
+// Package gerrit posts coverage reviews to Gerrit changes over the REST API or SSH
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+// Static error definitions
+var (
+	ErrNotConfigured   = errors.New("gerrit is not configured: set a REST URL with username and password, or an SSH host")
+	ErrChangeRequired  = errors.New("gerrit change and patchset numbers are required")
+	ErrGerritAPIError  = errors.New("gerrit API error")
+	ErrSSHCommand      = errors.New("gerrit SSH command failed")
+	ErrInvalidSSHHost  = errors.New("invalid gerrit SSH host")
+	errRetryableStatus = errors.New("retryable gerrit API status")
+)
+
+// DefaultSSHPort is the port of the Gerrit SSH daemon
+const DefaultSSHPort = 29418
+
+// Tag marks the review messages of go-coverage so Gerrit can group them as automated
+const Tag = "autogenerated:go-coverage"
+
+// Change identifies the patchset a review is posted on
+type Change struct {
+	Project  string // Project of the change (optional, disambiguates the change number)
+	Number   int    // Change number
+	Patchset int    // Patchset number
+	Revision string // Commit SHA of the patchset (optional, preferred over the patchset number)
+}
+
+// String returns the change in the CHANGE,PATCHSET form of the Gerrit SSH commands
+func (c Change) String() string {
+	return fmt.Sprintf("%d,%d", c.Number, c.Patchset)
+}
+
+// validate checks that the change identifies a patchset
+func (c Change) validate() error {
+	if c.Number <= 0 || c.Patchset <= 0 {
+		return fmt.Errorf("%w: got change %d patchset %d", ErrChangeRequired, c.Number, c.Patchset)
+	}
+	return nil
+}
+
+// Review is the review posted on a patchset (the Gerrit ReviewInput entity)
+type Review struct {
+	Message string         `json:"message"`
+	Labels  map[string]int `json:"labels,omitempty"`
+	Tag     string         `json:"tag,omitempty"`
+}
+
+// Reviewer posts reviews on Gerrit changes
+type Reviewer interface {
+	SetReview(ctx context.Context, change Change, review *Review) error
+}
+
+// Config holds Gerrit client configuration. REST is used when URL, Username and Password
+// are set, SSH when SSHHost is set.
+type Config struct {
+	URL      string // REST API base URL, e.g. https://review.example.com
+	Username string // REST API username
+	Password string // REST API HTTP password
+	SSHHost  string // SSH host
+	SSHPort  int    // SSH port (0 uses DefaultSSHPort)
+	SSHUser  string // SSH user (empty uses the SSH client configuration)
+	SSHKey   string // SSH private key file (empty uses the SSH client configuration)
+
+	// UserAgent is sent with REST requests
+	UserAgent string
+	// RetryPolicy controls retries of transient REST failures (nil disables retries)
+	RetryPolicy *retry.Policy
+	// HTTPClient overrides the default client, e.g. for proxies or custom CAs
+	HTTPClient *http.Client
+}
+
+// New creates the reviewer for the configured transport, preferring REST over SSH
+func New(config *Config) (Reviewer, error) {
+	switch {
+	case config.URL != "" && config.Username != "" && config.Password != "":
+		httpClient := config.HTTPClient
+		if httpClient == nil {
+			httpClient = &http.Client{Timeout: 30 * time.Second}
+		}
+		return &RESTClient{config: config, httpClient: httpClient}, nil
+	case config.SSHHost != "":
+		if strings.HasPrefix(config.SSHHost, "-") || strings.HasPrefix(config.SSHUser, "-") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSSHHost, config.SSHHost)
+		}
+		return &SSHClient{config: config, run: runCommand}, nil
+	default:
+		return nil, ErrNotConfigured
+	}
+}
+
+// RESTClient posts reviews with the Gerrit REST API
+type RESTClient struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// SetReview posts the review on the patchset
+func (c *RESTClient) SetReview(ctx context.Context, change Change, review *Review) error {
+	if err := change.validate(); err != nil {
+		return err
+	}
+
+	changeID := strconv.Itoa(change.Number)
+	if change.Project != "" {
+		changeID = url.PathEscape(change.Project) + "~" + changeID
+	}
+	revisionID := change.Revision
+	if revisionID == "" {
+		revisionID = strconv.Itoa(change.Patchset)
+	}
+	endpoint := fmt.Sprintf("%s/a/changes/%s/revisions/%s/review", strings.TrimRight(c.config.URL, "/"), changeID, revisionID)
+
+	body, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	post := func(ctx context.Context) error {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if reqErr != nil {
+			return retry.Permanent(fmt.Errorf("failed to create request: %w", reqErr))
+		}
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+		if c.config.UserAgent != "" {
+			req.Header.Set("User-Agent", c.config.UserAgent)
+		}
+
+		resp, doErr := c.httpClient.Do(req)
+		if doErr != nil {
+			if ctx.Err() != nil {
+				return retry.Permanent(doErr)
+			}
+			return doErr
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		statusErr := fmt.Errorf("%w: %d %s", ErrGerritAPIError, resp.StatusCode, strings.TrimSpace(string(message)))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: %w", errRetryableStatus, statusErr)
+		}
+		return retry.Permanent(statusErr)
+	}
+
+	if c.config.RetryPolicy == nil {
+		err = post(ctx)
+	} else {
+		err = retry.Do(ctx, *c.config.RetryPolicy, post)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post review on change %s: %w", change, err)
+	}
+	return nil
+}
+
+// SSHClient posts reviews with the gerrit review command of the Gerrit SSH daemon
+type SSHClient struct {
+	config *Config
+	run    func(ctx context.Context, name string, args []string, stdin []byte) ([]byte, error)
+}
+
+// SetReview posts the review on the patchset. The review is passed as JSON on standard
+// input, so the message needs no shell quoting.
+func (c *SSHClient) SetReview(ctx context.Context, change Change, review *Review) error {
+	if err := change.validate(); err != nil {
+		return err
+	}
+
+	input, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	if output, err := c.run(ctx, "ssh", c.args(change), input); err != nil {
+		return fmt.Errorf("%w on change %s: %w: %s", ErrSSHCommand, change, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// args returns the ssh arguments running gerrit review for the change
+func (c *SSHClient) args(change Change) []string {
+	port := c.config.SSHPort
+	if port == 0 {
+		port = DefaultSSHPort
+	}
+	args := []string{"-p", strconv.Itoa(port), "-o", "BatchMode=yes"}
+	if c.config.SSHKey != "" {
+		args = append(args, "-i", c.config.SSHKey)
+	}
+	destination := c.config.SSHHost
+	if c.config.SSHUser != "" {
+		destination = c.config.SSHUser + "@" + destination
+	}
+	args = append(args, destination, "gerrit", "review", "--json")
+	if change.Project != "" {
+		args = append(args, "--project", change.Project)
+	}
+	return append(args, change.String())
+}
+
+// runCommand runs a command with the given standard input and returns its combined output
+func runCommand(ctx context.Context, name string, args []string, stdin []byte) ([]byte, error) {
+	command := exec.CommandContext(ctx, name, args...) //nolint:gosec // arguments are passed to ssh without a shell
+	command.Stdin = bytes.NewReader(stdin)
+	return command.CombinedOutput()
+}
@@ -0,0 +1,122 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+var errExit = errors.New("exit status 1")
+
+func TestNew(t *testing.T) {
+	reviewer, err := New(&Config{URL: "https://review.example.com", Username: "bot", Password: "secret", SSHHost: "review.example.com"})
+	require.NoError(t, err)
+	assert.IsType(t, &RESTClient{}, reviewer)
+
+	reviewer, err = New(&Config{URL: "https://review.example.com", SSHHost: "review.example.com"})
+	require.NoError(t, err)
+	assert.IsType(t, &SSHClient{}, reviewer)
+
+	_, err = New(&Config{URL: "https://review.example.com"})
+	require.ErrorIs(t, err, ErrNotConfigured)
+
+	_, err = New(&Config{SSHHost: "-oProxyCommand=evil"})
+	require.ErrorIs(t, err, ErrInvalidSSHHost)
+}
+
+func TestRESTClientSetReview(t *testing.T) {
+	var (
+		path     string
+		user     string
+		password string
+		review   Review
+		attempts int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		path = r.URL.EscapedPath()
+		user, password, _ = r.BasicAuth()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &review)
+		_, _ = w.Write([]byte(")]}'\n{\"labels\":{\"Verified\":1}}"))
+	}))
+	defer server.Close()
+
+	reviewer, err := New(&Config{
+		URL: server.URL + "/", Username: "bot", Password: "secret",
+		RetryPolicy: &retry.Policy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	err = reviewer.SetReview(context.Background(), Change{Project: "team/service", Number: 42, Patchset: 3, Revision: "abc123"},
+		&Review{Message: "Coverage: 81.00%", Labels: map[string]int{"Verified": 1}, Tag: Tag})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "/a/changes/team%2Fservice~42/revisions/abc123/review", path)
+	assert.Equal(t, "bot", user)
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, Review{Message: "Coverage: 81.00%", Labels: map[string]int{"Verified": 1}, Tag: Tag}, review)
+}
+
+func TestRESTClientSetReviewErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("restricted to Verified -1..0"))
+	}))
+	defer server.Close()
+
+	reviewer, err := New(&Config{URL: server.URL, Username: "bot", Password: "secret"})
+	require.NoError(t, err)
+
+	err = reviewer.SetReview(context.Background(), Change{Number: 42, Patchset: 3}, &Review{Message: "m"})
+	require.ErrorIs(t, err, ErrGerritAPIError)
+	assert.Contains(t, err.Error(), "restricted to Verified")
+
+	err = reviewer.SetReview(context.Background(), Change{Number: 42}, &Review{Message: "m"})
+	require.ErrorIs(t, err, ErrChangeRequired)
+}
+
+func TestSSHClientSetReview(t *testing.T) {
+	var (
+		gotName  string
+		gotArgs  []string
+		gotInput []byte
+	)
+	client := &SSHClient{
+		config: &Config{SSHHost: "review.example.com", SSHUser: "ci", SSHKey: "/keys/ci"},
+		run: func(_ context.Context, name string, args []string, stdin []byte) ([]byte, error) {
+			gotName, gotArgs, gotInput = name, args, stdin
+			return nil, nil
+		},
+	}
+
+	review := &Review{Message: "Coverage 'quoted' \"message\"", Labels: map[string]int{"Code-Coverage": -1}}
+	require.NoError(t, client.SetReview(context.Background(), Change{Project: "service", Number: 42, Patchset: 3}, review))
+	assert.Equal(t, "ssh", gotName)
+	assert.Equal(t, []string{
+		"-p", "29418", "-o", "BatchMode=yes", "-i", "/keys/ci", "ci@review.example.com",
+		"gerrit", "review", "--json", "--project", "service", "42,3",
+	}, gotArgs)
+	assert.JSONEq(t, `{"message":"Coverage 'quoted' \"message\"","labels":{"Code-Coverage":-1}}`, string(gotInput))
+
+	client.run = func(context.Context, string, []string, []byte) ([]byte, error) {
+		return []byte("fatal: not permitted\n"), errExit
+	}
+	err := client.SetReview(context.Background(), Change{Number: 42, Patchset: 3}, review)
+	require.ErrorIs(t, err, ErrSSHCommand)
+	assert.Contains(t, err.Error(), "fatal: not permitted")
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// CommentAPI is the pull request comment operations of the GitHub API
+type CommentAPI interface {
+	ListComments(ctx context.Context, owner, repo string, pr int) ([]Comment, error)
+	AddComment(ctx context.Context, owner, repo string, pr int, body string) (*Comment, error)
+	UpdateComment(ctx context.Context, owner, repo string, commentID int, body string) (*Comment, error)
+	DeleteComment(ctx context.Context, owner, repo string, commentID int) error
+	MinimizeComment(ctx context.Context, nodeID, classifier string) error
+	UnminimizeComment(ctx context.Context, nodeID string) error
+	AddCommentReaction(ctx context.Context, owner, repo string, commentID int, content string) error
+}
+
+// StatusAPI is the commit status operations of the GitHub API
+type StatusAPI interface {
+	CreateStatus(ctx context.Context, owner, repo, sha string, status *StatusRequest) error
+	ListStatuses(ctx context.Context, owner, repo, sha string) ([]StatusRequest, error)
+}
+
+// DeploymentAPI is the deployment operations of the GitHub API
+type DeploymentAPI interface {
+	CreateDeployment(ctx context.Context, owner, repo string, deployment *DeploymentRequest) (*Deployment, error)
+	CreateDeploymentStatus(ctx context.Context, owner, repo string, deploymentID int64, status *DeploymentStatusRequest) error
+}
+
+// PullRequestAPI is the pull request read operations of the GitHub API
+type PullRequestAPI interface {
+	GetPullRequest(ctx context.Context, owner, repo string, pr int) (*PullRequest, error)
+	GetPullRequests(ctx context.Context, owner, repo string, numbers []int) (map[int]*PullRequest, error)
+	GetPRDiff(ctx context.Context, owner, repo string, pr int) (*PRDiff, error)
+}
+
+// ArtifactAPI is the workflow run and artifact operations of the GitHub API
+type ArtifactAPI interface {
+	GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*WorkflowRun, error)
+	ListCheckSuiteWorkflowRuns(ctx context.Context, owner, repo string, checkSuiteID int64) ([]WorkflowRun, error)
+	ListCommitWorkflowRuns(ctx context.Context, owner, repo, sha string) ([]WorkflowRun, error)
+	ListWorkflowRunArtifacts(ctx context.Context, owner, repo string, runID int64) ([]Artifact, error)
+	FindWorkflowRunArtifact(ctx context.Context, owner, repo string, runID int64, name string) (*Artifact, error)
+	DownloadArtifact(ctx context.Context, owner, repo string, artifactID, maxSize int64) ([]byte, error)
+}
+
+// RateLimitAPI reports and waits for the API quota
+type RateLimitAPI interface {
+	RateLimit() RateLimit
+	WaitForRateLimit(ctx context.Context, reserve int, maxWait time.Duration) (bool, error)
+}
+
+// API is the GitHub operations the comment, status, deployment and relay workflows depend on. Client
+// implements it against GitHub and Fake in memory for tests.
+type API interface {
+	CommentAPI
+	StatusAPI
+	DeploymentAPI
+	PullRequestAPI
+	ArtifactAPI
+	RateLimitAPI
+}
+
+// PRCommentAPI is the GitHub operations PRCommentManager depends on
+type PRCommentAPI interface {
+	CommentAPI
+	StatusAPI
+	PullRequestAPI
+}
+
+// StatusCheckAPI is the GitHub operations StatusCheckManager depends on
+type StatusCheckAPI interface {
+	StatusAPI
+	PullRequestAPI
+}
+
+// Compile-time interface checks
+var (
+	_ API = (*Client)(nil)
+	_ API = (*Fake)(nil)
+)
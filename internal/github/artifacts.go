@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Static error definitions for workflow artifacts
+var (
+	ErrArtifactNotFound = errors.New("workflow artifact not found")
+	ErrArtifactExpired  = errors.New("workflow artifact has expired")
+	ErrArtifactTooLarge = errors.New("workflow artifact is too large")
+)
+
+// Artifact represents a GitHub Actions workflow artifact
+type Artifact struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	SizeInBytes        int64  `json:"size_in_bytes"`
+	ArchiveDownloadURL string `json:"archive_download_url"`
+	Expired            bool   `json:"expired"`
+}
+
+// ListWorkflowRunArtifacts lists the artifacts uploaded by a workflow run
+func (c *Client) ListWorkflowRunArtifacts(ctx context.Context, owner, repo string, runID int64) ([]Artifact, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/artifacts?per_page=100", c.baseURL, owner, repo, runID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow run artifacts: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		TotalCount int        `json:"total_count"`
+		Artifacts  []Artifact `json:"artifacts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode artifacts response: %w", err)
+	}
+
+	return response.Artifacts, nil
+}
+
+// ListCheckSuiteWorkflowRuns lists the workflow runs of a check suite
+func (c *Client) ListCheckSuiteWorkflowRuns(ctx context.Context, owner, repo string, checkSuiteID int64) ([]WorkflowRun, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs?check_suite_id=%d&per_page=100", c.baseURL, owner, repo, checkSuiteID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check suite workflow runs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var response WorkflowRunsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow runs response: %w", err)
+	}
+
+	return response.WorkflowRuns, nil
+}
+
+// ListCommitWorkflowRuns lists the successful workflow runs of a commit, newest first
+func (c *Client) ListCommitWorkflowRuns(ctx context.Context, owner, repo, sha string) ([]WorkflowRun, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs?head_sha=%s&status=success&per_page=100", c.baseURL, owner, repo, sha)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit workflow runs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var response WorkflowRunsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow runs response: %w", err)
+	}
+
+	return response.WorkflowRuns, nil
+}
+
+// FindWorkflowRunArtifact returns the named artifact of a workflow run
+func (c *Client) FindWorkflowRunArtifact(ctx context.Context, owner, repo string, runID int64, name string) (*Artifact, error) {
+	artifacts, err := c.ListWorkflowRunArtifacts(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range artifacts {
+		if artifacts[i].Name != name {
+			continue
+		}
+		if artifacts[i].Expired {
+			return nil, fmt.Errorf("%w: %s", ErrArtifactExpired, name)
+		}
+		return &artifacts[i], nil
+	}
+
+	return nil, fmt.Errorf("%w: %s in run %d", ErrArtifactNotFound, name, runID)
+}
+
+// DownloadArtifact downloads the zip archive of an artifact, refusing archives larger than maxSize bytes
+func (c *Client) DownloadArtifact(ctx context.Context, owner, repo string, artifactID, maxSize int64) ([]byte, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/artifacts/%d/zip", c.baseURL, owner, repo, artifactID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The API redirects to a signed storage URL; the client drops the token on the cross-host redirect
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.doArtifact(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artifact: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Expired artifacts stay listed, but their archive is gone
+	if resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: artifact %d", ErrArtifactExpired, artifactID)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrArtifactTooLarge, maxSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
+)
+
+func TestWorkflowRunArtifacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/runs/5/artifacts":
+			_, _ = w.Write([]byte(`{"total_count":2,"artifacts":[
+				{"id":1,"name":"coverage-handoff","expired":true},
+				{"id":2,"name":"coverage-stats"}
+			]}`))
+		case "/repos/owner/repo/actions/artifacts/2/zip":
+			assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte("0123456789"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	artifacts, err := client.ListWorkflowRunArtifacts(ctx, "owner", "repo", 5)
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+
+	artifact, err := client.FindWorkflowRunArtifact(ctx, "owner", "repo", 5, "coverage-stats")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), artifact.ID)
+
+	_, err = client.FindWorkflowRunArtifact(ctx, "owner", "repo", 5, "coverage-handoff")
+	require.ErrorIs(t, err, ErrArtifactExpired)
+	_, err = client.FindWorkflowRunArtifact(ctx, "owner", "repo", 5, "missing")
+	require.ErrorIs(t, err, ErrArtifactNotFound)
+
+	data, err := client.DownloadArtifact(ctx, "owner", "repo", 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+
+	_, err = client.DownloadArtifact(ctx, "owner", "repo", 2, 5)
+	require.ErrorIs(t, err, ErrArtifactTooLarge)
+
+	_, err = client.DownloadArtifact(ctx, "owner", "repo", 3, 10)
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
+
+func TestDownloadArtifactGone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := client.DownloadArtifact(context.Background(), "owner", "repo", 1, 1024)
+	require.ErrorIs(t, err, ErrArtifactExpired)
+}
+
+func TestDownloadArtifactRetryPolicy(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("archive"))
+	}))
+	defer server.Close()
+
+	// The API policy gives up after one attempt; the artifact policy retries the download
+	client := NewWithConfig(&Config{
+		Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second,
+		RetryPolicy:         &retry.Policy{MaxAttempts: 1},
+		ArtifactRetryPolicy: &retry.Policy{MaxAttempts: 3},
+	})
+	data, err := client.DownloadArtifact(context.Background(), "owner", "repo", 1, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(data))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestListCheckSuiteWorkflowRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/actions/runs", r.URL.Path)
+		assert.Equal(t, "77", r.URL.Query().Get("check_suite_id"))
+		_, _ = w.Write([]byte(`{"total_count":1,"workflow_runs":[{"id":5,"head_sha":"abc","check_suite_id":77}]}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	runs, err := client.ListCheckSuiteWorkflowRuns(context.Background(), "owner", "repo", 77)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, int64(5), runs[0].ID)
+	assert.Equal(t, "abc", runs[0].HeadSHA)
+}
+
+func TestListCommitWorkflowRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/actions/runs", r.URL.Path)
+		assert.Equal(t, "abc", r.URL.Query().Get("head_sha"))
+		assert.Equal(t, "success", r.URL.Query().Get("status"))
+		_, _ = w.Write([]byte(`{"total_count":1,"workflow_runs":[{"id":9,"head_sha":"abc","conclusion":"success"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	runs, err := client.ListCommitWorkflowRuns(context.Background(), "owner", "repo", "abc")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, int64(9), runs[0].ID)
+}
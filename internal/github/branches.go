@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// branchPageSize is the number of branches requested per page, the most the API returns
+const branchPageSize = 100
+
+// Branch is a branch of a repository
+type Branch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+}
+
+// ListBranches lists every branch of a repository, following the pages of the API
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	var branches []Branch
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/branches?per_page=%d&page=%d", c.baseURL, owner, repo, branchPageSize, page)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "token "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("User-Agent", c.config.UserAgent)
+
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+		}
+		var batch []Branch
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode branches response: %w", err)
+		}
+
+		branches = append(branches, batch...)
+		if len(batch) < branchPageSize {
+			return branches, nil
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBranches(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/branches", r.URL.Path)
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			names := make([]string, 0, branchPageSize)
+			for i := range branchPageSize {
+				names = append(names, fmt.Sprintf(`{"name":"feature-%d"}`, i))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(names, ",") + "]"))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"name":"main","protected":true}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	branches, err := client.ListBranches(context.Background(), "owner", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, pages)
+	require.Len(t, branches, branchPageSize+1)
+	assert.Equal(t, Branch{Name: "main", Protected: true}, branches[branchPageSize])
+}
+
+func TestListBranchesAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := client.ListBranches(context.Background(), "owner", "repo")
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
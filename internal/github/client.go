@@ -9,7 +9,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
 )
 
 // Static error definitions
@@ -17,6 +22,7 @@ var (
 	ErrGitHubAPIError   = errors.New("GitHub API error")
 	ErrCommentNotFound  = errors.New("coverage comment not found")
 	ErrWorkflowNotFound = errors.New("workflow not found")
+	errRetryableStatus  = errors.New("retryable GitHub API status")
 )
 
 // Client handles GitHub API operations for coverage reporting
@@ -25,15 +31,31 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	config     *Config
+
+	// rateMu guards rateLimit, the quota reported by the most recent response
+	rateMu    sync.Mutex
+	rateLimit RateLimit
+
+	// prMu guards pullRequests, the pull requests fetched by GetPullRequests
+	prMu         sync.Mutex
+	pullRequests map[string]*PullRequest
 }
 
 // Config holds GitHub client configuration
 type Config struct {
-	Token      string        // GitHub API token
-	BaseURL    string        // GitHub API base URL
-	Timeout    time.Duration // Request timeout
-	RetryCount int           // Number of retries
-	UserAgent  string        // User agent string
+	Token     string        // GitHub API token
+	BaseURL   string        // GitHub API base URL
+	Timeout   time.Duration // Request timeout
+	UserAgent string        // User agent string
+
+	// RetryPolicy controls retries of transient API failures (nil disables retries)
+	RetryPolicy *retry.Policy
+	// ArtifactRetryPolicy controls retries of artifact downloads (nil uses RetryPolicy)
+	ArtifactRetryPolicy *retry.Policy
+	// HTTPClient overrides the default client, e.g. for proxies or custom CAs (Timeout is then ignored)
+	HTTPClient *http.Client
+	// Usage counts the requests of the client (nil counts them in the usage of the run)
+	Usage *Usage
 }
 
 // CommentRequest represents a PR comment request
@@ -52,6 +74,7 @@ type StatusRequest struct {
 // Comment represents a GitHub PR comment
 type Comment struct {
 	ID        int    `json:"id"`
+	NodeID    string `json:"node_id"`
 	Body      string `json:"body"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
@@ -59,13 +82,22 @@ type Comment struct {
 
 // PullRequest represents a GitHub pull request
 type PullRequest struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	State  string `json:"state"`
-	Head   struct {
-		SHA string `json:"sha"`
-	} `json:"head"`
-	Labels []Label `json:"labels"`
+	Number int            `json:"number"`
+	Title  string         `json:"title"`
+	State  string         `json:"state"`
+	Head   PullRequestRef `json:"head"`
+	Base   PullRequestRef `json:"base"`
+	Labels []Label        `json:"labels"`
+
+	// Merged and MergeCommitSHA tell a merged pull request from one closed without merging
+	Merged         bool   `json:"merged"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+}
+
+// PullRequestRef is the head or base of a pull request
+type PullRequestRef struct {
+	Ref string `json:"ref"` // Branch name
+	SHA string `json:"sha"`
 }
 
 // Label represents a GitHub label
@@ -133,25 +165,126 @@ func New(token string) *Client {
 			Timeout: 30 * time.Second,
 		},
 		config: &Config{
-			Token:      token,
-			BaseURL:    "https://api.github.com",
-			Timeout:    30 * time.Second,
-			RetryCount: 3,
-			UserAgent:  "coverage-system/1.0",
+			Token:     token,
+			BaseURL:   "https://api.github.com",
+			Timeout:   30 * time.Second,
+			UserAgent: "coverage-system/1.0",
 		},
 	}
 }
 
 // NewWithConfig creates a new GitHub client with custom configuration
 func NewWithConfig(config *Config) *Client {
-	return &Client{
-		token:   config.Token,
-		baseURL: config.BaseURL,
-		httpClient: &http.Client{
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
 			Timeout: config.Timeout,
-		},
-		config: config,
+		}
+	}
+
+	return &Client{
+		token:      config.Token,
+		baseURL:    config.BaseURL,
+		httpClient: httpClient,
+		config:     config,
+	}
+}
+
+// do executes an API request and records the rate limit reported with the response
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var policy *retry.Policy
+	if c.config != nil {
+		policy = c.config.RetryPolicy
+	}
+	return c.doWithPolicy(req, policy)
+}
+
+// doArtifact executes an artifact download, retried by the artifact retry policy when one is set
+func (c *Client) doArtifact(req *http.Request) (*http.Response, error) {
+	if c.config != nil && c.config.ArtifactRetryPolicy != nil {
+		return c.doWithPolicy(req, c.config.ArtifactRetryPolicy)
+	}
+	return c.do(req)
+}
+
+// doWithPolicy executes an API request with the given retry policy and records the rate limit
+// reported with the response
+func (c *Client) doWithPolicy(req *http.Request, policy *retry.Policy) (*http.Response, error) {
+	resp, err := c.doWithRetry(req, policy)
+	if resp != nil {
+		c.recordRateLimit(resp.Header)
+	}
+	return resp, err
+}
+
+// send sends a single request attempt and counts it in the usage of the client
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	usage := processUsage
+	if c.config != nil && c.config.Usage != nil {
+		usage = c.config.Usage
 	}
+	usage.record(req, resp)
+	return resp, err
+}
+
+// doWithRetry executes an API request, retrying network errors, rate limits and server
+// errors according to the retry policy (nil disables retries). When every attempt fails
+// with a retryable status, the last response is returned so callers can report it.
+func (c *Client) doWithRetry(req *http.Request, policy *retry.Policy) (*http.Response, error) {
+	if policy == nil {
+		return c.send(req)
+	}
+
+	var lastResp *http.Response
+	err := retry.Do(req.Context(), *policy, func(ctx context.Context) error {
+		attemptReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return retry.Permanent(err)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := c.send(attemptReq)
+		if err != nil {
+			if ctx.Err() != nil {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			lastResp = resp
+			return nil
+		}
+
+		// Buffer the body so the final failed response can still be inspected
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		lastResp = resp
+		return fmt.Errorf("%w: %d", errRetryableStatus, resp.StatusCode)
+	})
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, err
+}
+
+// retryPolicyOr returns the client's retry policy, or the given fallback when none is configured
+func (c *Client) retryPolicyOr(fallback retry.Policy) retry.Policy {
+	if c.config != nil && c.config.RetryPolicy != nil {
+		return *c.config.RetryPolicy
+	}
+	return fallback
+}
+
+// isRetryableStatus reports whether an HTTP status indicates a transient failure
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
 }
 
 // CreateComment creates or updates a PR comment with coverage information
@@ -164,11 +297,11 @@ func (c *Client) CreateComment(ctx context.Context, owner, repo string, pr int,
 
 	if existing != nil {
 		// Update existing comment
-		return c.updateComment(ctx, owner, repo, existing.ID, body)
+		return c.UpdateComment(ctx, owner, repo, existing.ID, body)
 	}
 
 	// Create new comment
-	return c.createComment(ctx, owner, repo, pr, body)
+	return c.AddComment(ctx, owner, repo, pr, body)
 }
 
 // CreateStatus creates a commit status for coverage
@@ -189,7 +322,7 @@ func (c *Client) CreateStatus(ctx context.Context, owner, repo, sha string, stat
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to create status: %w", err)
 	}
@@ -203,8 +336,12 @@ func (c *Client) CreateStatus(ctx context.Context, owner, repo, sha string, stat
 	return nil
 }
 
-// GetPullRequest retrieves PR information
+// GetPullRequest retrieves PR information, reusing the result of an earlier GetPullRequests call
 func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, pr int) (*PullRequest, error) {
+	if cached := c.cachedPullRequest(owner, repo, pr); cached != nil {
+		return cached, nil
+	}
+
 	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, owner, repo, pr)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -215,7 +352,7 @@ func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, pr int)
 	req.Header.Set("Authorization", "token "+c.token)
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR: %w", err)
 	}
@@ -247,7 +384,7 @@ func (c *Client) findCoverageComment(ctx context.Context, owner, repo string, pr
 	req.Header.Set("Authorization", "token "+c.token)
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
@@ -273,7 +410,8 @@ func (c *Client) findCoverageComment(ctx context.Context, owner, repo string, pr
 	return nil, ErrCommentNotFound
 }
 
-func (c *Client) createComment(ctx context.Context, owner, repo string, pr int, body string) (*Comment, error) {
+// AddComment posts a new comment on a pull request
+func (c *Client) AddComment(ctx context.Context, owner, repo string, pr int, body string) (*Comment, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, pr)
 
 	commentReq := CommentRequest{Body: body}
@@ -291,7 +429,7 @@ func (c *Client) createComment(ctx context.Context, owner, repo string, pr int,
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
@@ -310,7 +448,8 @@ func (c *Client) createComment(ctx context.Context, owner, repo string, pr int,
 	return &comment, nil
 }
 
-func (c *Client) updateComment(ctx context.Context, owner, repo string, commentID int, body string) (*Comment, error) {
+// UpdateComment replaces the body of a pull request comment
+func (c *Client) UpdateComment(ctx context.Context, owner, repo string, commentID int, body string) (*Comment, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d", c.baseURL, owner, repo, commentID)
 
 	commentReq := CommentRequest{Body: body}
@@ -328,7 +467,7 @@ func (c *Client) updateComment(ctx context.Context, owner, repo string, commentI
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update comment: %w", err)
 	}
@@ -347,6 +486,79 @@ func (c *Client) updateComment(ctx context.Context, owner, repo string, commentI
 	return &comment, nil
 }
 
+// DeleteComment deletes a pull request comment
+func (c *Client) DeleteComment(ctx context.Context, owner, repo string, commentID int) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d", c.baseURL, owner, repo, commentID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// defaultCommentFetchPolicy returns the policy used to list PR comments when the client has no retry policy
+func defaultCommentFetchPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:  3,
+		InitialDelay: time.Second,
+		Multiplier:   2,
+	}
+}
+
+// ListComments lists the comments of a pull request. Failed attempts, including error
+// statuses, are retried with the client's retry policy.
+func (c *Client) ListComments(ctx context.Context, owner, repo string, pr int) ([]Comment, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, pr)
+
+	var comments []Comment
+	err := retry.Do(ctx, c.retryPolicyOr(defaultCommentFetchPolicy()), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "token "+c.token)
+		req.Header.Set("User-Agent", c.config.UserAgent)
+
+		resp, err := c.send(req)
+		if err != nil {
+			return fmt.Errorf("failed to get comments: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		c.recordRateLimit(resp.Header)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%w: %d", ErrGitHubAPIError, resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
+			return fmt.Errorf("failed to decode comments: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
 func containsCoverageMarker(body string) bool {
 	// Look for coverage report markers
 	markers := []string{
@@ -391,6 +603,7 @@ const (
 const (
 	ContextCoverage = "coverage/total"
 	ContextTrend    = "coverage/trend"
+	ContextCritical = "coverage/critical"
 )
 
 // GetWorkflowRuns retrieves the latest workflow runs for a repository
@@ -409,7 +622,7 @@ func (c *Client) GetWorkflowRuns(ctx context.Context, owner, repo string, limit
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
 	}
@@ -450,7 +663,7 @@ func (c *Client) GetWorkflowRunsByWorkflow(ctx context.Context, owner, repo, wor
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
 	}
@@ -482,7 +695,7 @@ func (c *Client) GetWorkflowRun(ctx context.Context, owner, repo string, runID i
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow run: %w", err)
 	}
@@ -514,7 +727,7 @@ func (c *Client) getWorkflowIDByName(ctx context.Context, owner, repo, workflowN
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get workflows: %w", err)
 	}
@@ -541,3 +754,21 @@ func (c *Client) getWorkflowIDByName(ctx context.Context, owner, repo, workflowN
 
 	return 0, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowName)
 }
+
+// APIStatus returns the HTTP status reported by an ErrGitHubAPIError, or 0 for other errors
+func APIStatus(err error) int {
+	if !errors.Is(err, ErrGitHubAPIError) {
+		return 0
+	}
+	message := err.Error()
+	prefix := ErrGitHubAPIError.Error() + ": "
+	i := strings.Index(message, prefix)
+	if i < 0 || len(message) < i+len(prefix)+3 {
+		return 0
+	}
+	status, convErr := strconv.Atoi(message[i+len(prefix) : i+len(prefix)+3])
+	if convErr != nil {
+		return 0
+	}
+	return status
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
 )
 
 func TestNew(t *testing.T) {
@@ -29,11 +32,10 @@ func TestNew(t *testing.T) {
 
 func TestNewWithConfig(t *testing.T) {
 	config := &Config{
-		Token:      "custom-token",
-		BaseURL:    "https://custom.api.com",
-		Timeout:    60 * time.Second,
-		RetryCount: 5,
-		UserAgent:  "custom-agent/2.0",
+		Token:     "custom-token",
+		BaseURL:   "https://custom.api.com",
+		Timeout:   60 * time.Second,
+		UserAgent: "custom-agent/2.0",
 	}
 
 	client := NewWithConfig(config)
@@ -315,9 +317,7 @@ func TestGetPullRequest(t *testing.T) {
 				Number: 123,
 				Title:  "Test PR",
 				State:  "open",
-				Head: struct {
-					SHA string `json:"sha"`
-				}{SHA: "abc123def456"},
+				Head:   PullRequestRef{SHA: "abc123def456"},
 			},
 			expectError: false,
 		},
@@ -957,3 +957,106 @@ func TestGetWorkflowRun(t *testing.T) {
 		})
 	}
 }
+
+func TestClientRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		failureStatus int
+		policy        *retry.Policy
+		expectError   bool
+		expectedCalls int
+	}{
+		{
+			name:          "no policy means a single attempt",
+			failures:      1,
+			failureStatus: http.StatusServiceUnavailable,
+			policy:        nil,
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "retries server errors until success",
+			failures:      2,
+			failureStatus: http.StatusBadGateway,
+			policy:        &retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+			expectError:   false,
+			expectedCalls: 3,
+		},
+		{
+			name:          "retries rate limiting",
+			failures:      1,
+			failureStatus: http.StatusTooManyRequests,
+			policy:        &retry.Policy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+			expectError:   false,
+			expectedCalls: 2,
+		},
+		{
+			name:          "does not retry client errors",
+			failures:      1,
+			failureStatus: http.StatusUnprocessableEntity,
+			policy:        &retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "returns last response when attempts run out",
+			failures:      5,
+			failureStatus: http.StatusInternalServerError,
+			policy:        &retry.Policy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+			expectError:   true,
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				// The request body must be replayed on every attempt
+				var status StatusRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+				assert.Equal(t, StatusSuccess, status.State)
+
+				if calls <= tt.failures {
+					w.WriteHeader(tt.failureStatus)
+					_, _ = w.Write([]byte("temporary failure"))
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			client := NewWithConfig(&Config{
+				Token:       testToken,
+				BaseURL:     server.URL,
+				Timeout:     5 * time.Second,
+				UserAgent:   testAgent,
+				RetryPolicy: tt.policy,
+			})
+
+			err := client.CreateStatus(context.Background(), "owner", "repo", testSHA, &StatusRequest{
+				State:   StatusSuccess,
+				Context: ContextCoverage,
+			})
+
+			if tt.expectError {
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrGitHubAPIError)
+				assert.Contains(t, err.Error(), fmt.Sprintf("%d", tt.failureStatus))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestAPIStatus(t *testing.T) {
+	assert.Equal(t, http.StatusForbidden, APIStatus(fmt.Errorf("failed: %w", fmt.Errorf("%w: 403 forbidden", ErrGitHubAPIError))))
+	assert.Equal(t, http.StatusNotFound, APIStatus(notFound("comment %d not found", 1)))
+	assert.Zero(t, APIStatus(fmt.Errorf("%w: no status", ErrGitHubAPIError)))
+	assert.Zero(t, APIStatus(errors.New("403 forbidden")))
+	assert.Zero(t, APIStatus(nil))
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Deployment states
+const (
+	DeploymentSuccess    = "success"
+	DeploymentFailure    = "failure"
+	DeploymentInProgress = "in_progress"
+)
+
+// Deployment is a deployment of a repository to an environment
+type Deployment struct {
+	ID          int64  `json:"id"`
+	Ref         string `json:"ref"`
+	SHA         string `json:"sha"`
+	Environment string `json:"environment"`
+	Description string `json:"description"`
+}
+
+// DeploymentRequest is the body of a request creating a deployment
+type DeploymentRequest struct {
+	Ref         string `json:"ref"`
+	Environment string `json:"environment"`
+	Description string `json:"description,omitempty"`
+	// AutoMerge merges the default branch into ref first; coverage deployments never want that
+	AutoMerge bool `json:"auto_merge"`
+	// RequiredContexts are the statuses that must pass before deploying. An empty list skips
+	// the check, so a failing coverage status does not block publishing its report.
+	RequiredContexts     []string `json:"required_contexts"`
+	TransientEnvironment bool     `json:"transient_environment"`
+}
+
+// DeploymentStatusRequest is the body of a request setting the state of a deployment
+type DeploymentStatusRequest struct {
+	State          string `json:"state"`
+	EnvironmentURL string `json:"environment_url,omitempty"`
+	LogURL         string `json:"log_url,omitempty"`
+	Description    string `json:"description,omitempty"`
+	// AutoInactive marks the earlier successful deployments of the environment inactive
+	AutoInactive bool `json:"auto_inactive"`
+}
+
+// CreateDeployment creates a deployment and returns it
+func (c *Client) CreateDeployment(ctx context.Context, owner, repo string, deployment *DeploymentRequest) (*Deployment, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/deployments", c.baseURL, owner, repo)
+
+	var result Deployment
+	if err := c.postDeployment(ctx, url, deployment, &result); err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	}
+	return &result, nil
+}
+
+// CreateDeploymentStatus sets the state of a deployment, and with it the URL the environment
+// links to
+func (c *Client) CreateDeploymentStatus(ctx context.Context, owner, repo string, deploymentID int64, status *DeploymentStatusRequest) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/deployments/%d/statuses", c.baseURL, owner, repo, deploymentID)
+
+	if err := c.postDeployment(ctx, url, status, nil); err != nil {
+		return fmt.Errorf("failed to create deployment status: %w", err)
+	}
+	return nil
+}
+
+// postDeployment sends a deployment request and decodes the response into result, if given
+func (c *Client) postDeployment(ctx context.Context, url string, body, result any) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// 202 means GitHub merged the default branch instead of deploying, which AutoMerge prevents
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(data))
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
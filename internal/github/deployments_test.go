@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDeployment(t *testing.T) {
+	var status DeploymentStatusRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/owner/repo/deployments":
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "abc123", body["ref"])
+			assert.Equal(t, "coverage", body["environment"])
+			assert.Equal(t, false, body["auto_merge"])
+			assert.Equal(t, []any{}, body["required_contexts"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":42,"ref":"abc123","sha":"abc123","environment":"coverage"}`))
+		case "/repos/owner/repo/deployments/42/statuses":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"state":"success"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+	deployment, err := client.CreateDeployment(ctx, "owner", "repo", &DeploymentRequest{
+		Ref: "abc123", Environment: "coverage", RequiredContexts: []string{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), deployment.ID)
+
+	require.NoError(t, client.CreateDeploymentStatus(ctx, "owner", "repo", deployment.ID, &DeploymentStatusRequest{
+		State: DeploymentSuccess, EnvironmentURL: "https://owner.github.io/repo/", AutoInactive: true,
+	}))
+	assert.Equal(t, DeploymentSuccess, status.State)
+	assert.Equal(t, "https://owner.github.io/repo/", status.EnvironmentURL)
+	assert.True(t, status.AutoInactive)
+}
+
+func TestCreateDeploymentNotCreated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"message":"Auto-merged main into topic on deployment."}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := client.CreateDeployment(context.Background(), "owner", "repo", &DeploymentRequest{Ref: "topic", Environment: "coverage"})
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
@@ -0,0 +1,218 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Static error definitions for coverage threads
+var (
+	ErrRepositoryNotFound         = errors.New("repository not found")
+	ErrDiscussionCategoryNotFound = errors.New("discussion category not found")
+	ErrInvalidThreadKind          = errors.New("invalid thread kind")
+	ErrThreadNotFound             = errors.New("coverage thread not found")
+)
+
+// Kinds of long-lived coverage threads
+const (
+	ThreadDiscussion = "discussion"
+	ThreadIssue      = "issue"
+)
+
+// Thread is a discussion or issue that collects coverage updates
+type Thread struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"` // GraphQL node ID
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+}
+
+// threadNode is a search result, which is a discussion or an issue
+type threadNode struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+}
+
+// FindThread returns the open discussion or issue with exactly the given title, or
+// ErrThreadNotFound if there is none
+func (c *Client) FindThread(ctx context.Context, owner, repo, kind, title string) (*Thread, error) {
+	searchType, qualifier, err := threadSearch(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `query($q: String!, $type: SearchType!) {
+  search(query: $q, type: $type, first: 20) {
+    nodes {
+      ... on Discussion { id number title body url }
+      ... on Issue { id number title body url }
+    }
+  }
+}`
+	q := fmt.Sprintf("repo:%s/%s in:title %s%q", owner, repo, qualifier, title)
+
+	var data struct {
+		Search struct {
+			Nodes []threadNode `json:"nodes"`
+		} `json:"search"`
+	}
+	if err := c.GraphQL(ctx, query, map[string]any{"q": q, "type": searchType}, &data); err != nil {
+		return nil, fmt.Errorf("failed to search %ss: %w", kind, err)
+	}
+
+	for _, node := range data.Search.Nodes {
+		if node.Title == title {
+			return &Thread{Kind: kind, ID: node.ID, Number: node.Number, Title: node.Title, Body: node.Body, URL: node.URL}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %q", ErrThreadNotFound, kind, title)
+}
+
+// CreateThread opens a discussion in the named category, or an issue, and returns it
+func (c *Client) CreateThread(ctx context.Context, owner, repo, kind, category, title, body string) (*Thread, error) {
+	repositoryID, categoryID, err := c.threadRepository(ctx, owner, repo, kind, category)
+	if err != nil {
+		return nil, err
+	}
+
+	var node threadNode
+	switch kind {
+	case ThreadDiscussion:
+		const mutation = `mutation($repositoryId: ID!, $categoryId: ID!, $title: String!, $body: String!) {
+  createDiscussion(input: {repositoryId: $repositoryId, categoryId: $categoryId, title: $title, body: $body}) {
+    discussion { id number title body url }
+  }
+}`
+		var data struct {
+			CreateDiscussion struct {
+				Discussion threadNode `json:"discussion"`
+			} `json:"createDiscussion"`
+		}
+		vars := map[string]any{"repositoryId": repositoryID, "categoryId": categoryID, "title": title, "body": body}
+		if err := c.GraphQL(ctx, mutation, vars, &data); err != nil {
+			return nil, fmt.Errorf("failed to create discussion: %w", err)
+		}
+		node = data.CreateDiscussion.Discussion
+	default:
+		const mutation = `mutation($repositoryId: ID!, $title: String!, $body: String!) {
+  createIssue(input: {repositoryId: $repositoryId, title: $title, body: $body}) {
+    issue { id number title body url }
+  }
+}`
+		var data struct {
+			CreateIssue struct {
+				Issue threadNode `json:"issue"`
+			} `json:"createIssue"`
+		}
+		vars := map[string]any{"repositoryId": repositoryID, "title": title, "body": body}
+		if err := c.GraphQL(ctx, mutation, vars, &data); err != nil {
+			return nil, fmt.Errorf("failed to create issue: %w", err)
+		}
+		node = data.CreateIssue.Issue
+	}
+
+	return &Thread{Kind: kind, ID: node.ID, Number: node.Number, Title: node.Title, Body: node.Body, URL: node.URL}, nil
+}
+
+// UpdateThreadBody replaces the body of a discussion or issue
+func (c *Client) UpdateThreadBody(ctx context.Context, thread *Thread, body string) error {
+	mutation := `mutation($id: ID!, $body: String!) { updateIssue(input: {id: $id, body: $body}) { issue { id } } }`
+	if thread.Kind == ThreadDiscussion {
+		mutation = `mutation($id: ID!, $body: String!) { updateDiscussion(input: {discussionId: $id, body: $body}) { discussion { id } } }`
+	}
+	if err := c.GraphQL(ctx, mutation, map[string]any{"id": thread.ID, "body": body}, nil); err != nil {
+		return fmt.Errorf("failed to update %s: %w", thread.Kind, err)
+	}
+	return nil
+}
+
+// AddThreadComment adds a comment to a discussion or issue
+func (c *Client) AddThreadComment(ctx context.Context, thread *Thread, body string) error {
+	mutation := `mutation($id: ID!, $body: String!) { addComment(input: {subjectId: $id, body: $body}) { clientMutationId } }`
+	if thread.Kind == ThreadDiscussion {
+		mutation = `mutation($id: ID!, $body: String!) { addDiscussionComment(input: {discussionId: $id, body: $body}) { clientMutationId } }`
+	}
+	if err := c.GraphQL(ctx, mutation, map[string]any{"id": thread.ID, "body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", thread.Kind, err)
+	}
+	return nil
+}
+
+// PinIssue pins an issue to the top of the repository's issue list. Discussions cannot be
+// pinned through the API.
+func (c *Client) PinIssue(ctx context.Context, issueID string) error {
+	const mutation = `mutation($id: ID!) { pinIssue(input: {issueId: $id}) { issue { id } } }`
+	if err := c.GraphQL(ctx, mutation, map[string]any{"id": issueID}, nil); err != nil {
+		return fmt.Errorf("failed to pin issue: %w", err)
+	}
+	return nil
+}
+
+// CloseIssue closes an issue as completed
+func (c *Client) CloseIssue(ctx context.Context, issueID string) error {
+	const mutation = `mutation($id: ID!) { closeIssue(input: {issueId: $id, stateReason: COMPLETED}) { issue { id } } }`
+	if err := c.GraphQL(ctx, mutation, map[string]any{"id": issueID}, nil); err != nil {
+		return fmt.Errorf("failed to close issue: %w", err)
+	}
+	return nil
+}
+
+// threadSearch returns the search type and the qualifiers, if any, that find open threads of a kind
+func threadSearch(kind string) (string, string, error) {
+	switch kind {
+	case ThreadDiscussion:
+		return "DISCUSSION", "", nil
+	case ThreadIssue:
+		return "ISSUE", "is:issue is:open ", nil
+	default:
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidThreadKind, kind)
+	}
+}
+
+// threadRepository looks up the repository node ID and, for discussions, the category ID
+func (c *Client) threadRepository(ctx context.Context, owner, repo, kind, category string) (string, string, error) {
+	if _, _, err := threadSearch(kind); err != nil {
+		return "", "", err
+	}
+
+	const query = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    id
+    discussionCategories(first: 50) { nodes { id name } }
+  }
+}`
+	var data struct {
+		Repository *struct {
+			ID                   string `json:"id"`
+			DiscussionCategories struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	if err := c.GraphQL(ctx, query, map[string]any{"owner": owner, "name": repo}, &data); err != nil {
+		return "", "", fmt.Errorf("failed to look up repository: %w", err)
+	}
+	if data.Repository == nil {
+		return "", "", fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, owner, repo)
+	}
+	if kind != ThreadDiscussion {
+		return data.Repository.ID, "", nil
+	}
+
+	for _, node := range data.Repository.DiscussionCategories.Nodes {
+		if strings.EqualFold(node.Name, category) {
+			return data.Repository.ID, node.ID, nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %q in %s/%s", ErrDiscussionCategoryNotFound, category, owner, repo)
+}
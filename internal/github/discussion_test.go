@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newThreadServer fakes the GraphQL API, answering each request with the response of the operation
+// its query calls and recording the requests
+func newThreadServer(t *testing.T, responses map[string]string) (*Client, *[]graphQLRequest) {
+	t.Helper()
+	var requests []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+		for operation, response := range responses {
+			if strings.Contains(request.Query, operation+"(") {
+				_, _ = w.Write([]byte(response))
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+	return client, &requests
+}
+
+func TestFindThread(t *testing.T) {
+	client, requests := newThreadServer(t, map[string]string{
+		"search": `{"data":{"search":{"nodes":[
+			{"id":"D_1","number":3,"title":"Coverage digest (old)","body":"old","url":"https://github.com/o/r/discussions/3"},
+			{"id":"D_2","number":4,"title":"Coverage digest","body":"current","url":"https://github.com/o/r/discussions/4"},
+			{}
+		]}}}`,
+	})
+
+	thread, err := client.FindThread(context.Background(), "o", "r", ThreadDiscussion, "Coverage digest")
+	require.NoError(t, err)
+	assert.Equal(t, &Thread{Kind: ThreadDiscussion, ID: "D_2", Number: 4, Title: "Coverage digest", Body: "current", URL: "https://github.com/o/r/discussions/4"}, thread)
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "DISCUSSION", (*requests)[0].Variables["type"])
+	assert.Equal(t, `repo:o/r in:title "Coverage digest"`, (*requests)[0].Variables["q"])
+
+	_, err = client.FindThread(context.Background(), "o", "r", ThreadIssue, "Missing")
+	require.ErrorIs(t, err, ErrThreadNotFound)
+	assert.Equal(t, "ISSUE", (*requests)[1].Variables["type"])
+	assert.Contains(t, (*requests)[1].Variables["q"], "is:issue is:open")
+
+	_, err = client.FindThread(context.Background(), "o", "r", "wiki", "Coverage digest")
+	require.ErrorIs(t, err, ErrInvalidThreadKind)
+}
+
+func TestCreateThread(t *testing.T) {
+	client, requests := newThreadServer(t, map[string]string{
+		"repository":       `{"data":{"repository":{"id":"R_1","discussionCategories":{"nodes":[{"id":"C_1","name":"General"},{"id":"C_2","name":"Announcements"}]}}}}`,
+		"createDiscussion": `{"data":{"createDiscussion":{"discussion":{"id":"D_9","number":9,"title":"Coverage digest","body":"body","url":"https://github.com/o/r/discussions/9"}}}}`,
+		"createIssue":      `{"data":{"createIssue":{"issue":{"id":"I_5","number":5,"title":"Coverage digest","body":"body","url":"https://github.com/o/r/issues/5"}}}}`,
+	})
+
+	thread, err := client.CreateThread(context.Background(), "o", "r", ThreadDiscussion, "announcements", "Coverage digest", "body")
+	require.NoError(t, err)
+	assert.Equal(t, "D_9", thread.ID)
+	assert.Equal(t, ThreadDiscussion, thread.Kind)
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "R_1", (*requests)[1].Variables["repositoryId"])
+	assert.Equal(t, "C_2", (*requests)[1].Variables["categoryId"])
+
+	thread, err = client.CreateThread(context.Background(), "o", "r", ThreadIssue, "", "Coverage digest", "body")
+	require.NoError(t, err)
+	assert.Equal(t, 5, thread.Number)
+	assert.Equal(t, ThreadIssue, thread.Kind)
+	assert.NotContains(t, (*requests)[3].Variables, "categoryId")
+
+	_, err = client.CreateThread(context.Background(), "o", "r", ThreadDiscussion, "Q&A", "Coverage digest", "body")
+	require.ErrorIs(t, err, ErrDiscussionCategoryNotFound)
+}
+
+func TestCreateThreadRepositoryNotFound(t *testing.T) {
+	client, _ := newThreadServer(t, map[string]string{
+		"repository": `{"data":{"repository":null}}`,
+	})
+
+	_, err := client.CreateThread(context.Background(), "o", "missing", ThreadIssue, "", "Coverage digest", "body")
+	require.ErrorIs(t, err, ErrRepositoryNotFound)
+}
+
+func TestThreadMutations(t *testing.T) {
+	client, requests := newThreadServer(t, nil)
+	ctx := context.Background()
+	discussion := &Thread{Kind: ThreadDiscussion, ID: "D_1"}
+	issue := &Thread{Kind: ThreadIssue, ID: "I_1"}
+
+	require.NoError(t, client.UpdateThreadBody(ctx, discussion, "new body"))
+	require.NoError(t, client.UpdateThreadBody(ctx, issue, "new body"))
+	require.NoError(t, client.AddThreadComment(ctx, discussion, "digest"))
+	require.NoError(t, client.AddThreadComment(ctx, issue, "digest"))
+	require.NoError(t, client.PinIssue(ctx, "I_1"))
+	require.NoError(t, client.CloseIssue(ctx, "I_1"))
+
+	operations := []string{"updateDiscussion", "updateIssue", "addDiscussionComment", "addComment", "pinIssue", "closeIssue"}
+	require.Len(t, *requests, len(operations))
+	for i, operation := range operations {
+		assert.Contains(t, (*requests)[i].Query, operation+"(")
+	}
+	assert.Equal(t, "new body", (*requests)[0].Variables["body"])
+	assert.Equal(t, "I_1", (*requests)[4].Variables["id"])
+	assert.Equal(t, "I_1", (*requests)[5].Variables["id"])
+}
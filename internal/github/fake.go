@@ -0,0 +1,409 @@
+package github
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory implementation of API for tests. It holds the data of a single
+// repository, so owner and repository arguments are ignored. Seed PullRequests, Diffs,
+// WorkflowRuns, Artifacts and ArtifactData before use and inspect Comments, Statuses,
+// Deployments, Minimized and Reactions afterwards; NewFake initializes every map. Errors makes an
+// operation, named like its method, fail.
+type Fake struct {
+	mu sync.Mutex
+
+	PullRequests map[int]*PullRequest
+	Diffs        map[int]*PRDiff
+	WorkflowRuns map[int64]*WorkflowRun
+	Artifacts    map[int64][]Artifact // Artifacts by workflow run ID
+	ArtifactData map[int64][]byte     // Archive contents by artifact ID
+	Limit        RateLimit
+
+	Comments  map[int][]Comment          // Comments by pull request number, oldest first
+	Statuses  map[string][]StatusRequest // Statuses by commit SHA, oldest first
+	Minimized map[string]string          // Classifier by comment node ID
+	Reactions map[int][]string           // Reactions by comment ID
+
+	Deployments        []Deployment                        // Deployments, oldest first
+	DeploymentStatuses map[int64][]DeploymentStatusRequest // Statuses by deployment ID, oldest first
+
+	Errors map[string]error // Error returned by an operation, by method name
+
+	nextCommentID int
+}
+
+// NewFake creates an empty fake
+func NewFake() *Fake {
+	return &Fake{
+		PullRequests:       map[int]*PullRequest{},
+		Diffs:              map[int]*PRDiff{},
+		WorkflowRuns:       map[int64]*WorkflowRun{},
+		Artifacts:          map[int64][]Artifact{},
+		ArtifactData:       map[int64][]byte{},
+		Comments:           map[int][]Comment{},
+		Statuses:           map[string][]StatusRequest{},
+		Minimized:          map[string]string{},
+		Reactions:          map[int][]string{},
+		DeploymentStatuses: map[int64][]DeploymentStatusRequest{},
+		Errors:             map[string]error{},
+	}
+}
+
+// notFound returns the error of a missing resource, shaped like the API's 404
+func notFound(format string, args ...any) error {
+	return fmt.Errorf("%w: 404 %s", ErrGitHubAPIError, fmt.Sprintf(format, args...))
+}
+
+// findComment returns the pull request and index of a comment; call with the lock held
+func (f *Fake) findComment(commentID int) (int, int, bool) {
+	for pr, comments := range f.Comments {
+		for i := range comments {
+			if comments[i].ID == commentID {
+				return pr, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ListComments implements CommentAPI
+func (f *Fake) ListComments(_ context.Context, _, _ string, pr int) ([]Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["ListComments"]; err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Comments[pr]), nil
+}
+
+// AddComment implements CommentAPI
+func (f *Fake) AddComment(_ context.Context, _, _ string, pr int, body string) (*Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["AddComment"]; err != nil {
+		return nil, err
+	}
+
+	f.nextCommentID++
+	now := time.Now().UTC().Format(time.RFC3339)
+	comment := Comment{
+		ID:        f.nextCommentID,
+		NodeID:    fmt.Sprintf("IC_%d", f.nextCommentID),
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	f.Comments[pr] = append(f.Comments[pr], comment)
+	return &comment, nil
+}
+
+// UpdateComment implements CommentAPI
+func (f *Fake) UpdateComment(_ context.Context, _, _ string, commentID int, body string) (*Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["UpdateComment"]; err != nil {
+		return nil, err
+	}
+
+	pr, i, ok := f.findComment(commentID)
+	if !ok {
+		return nil, notFound("comment %d not found", commentID)
+	}
+	comment := &f.Comments[pr][i]
+	comment.Body = body
+	comment.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	updated := *comment
+	return &updated, nil
+}
+
+// DeleteComment implements CommentAPI
+func (f *Fake) DeleteComment(_ context.Context, _, _ string, commentID int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["DeleteComment"]; err != nil {
+		return err
+	}
+
+	pr, i, ok := f.findComment(commentID)
+	if !ok {
+		return notFound("comment %d not found", commentID)
+	}
+	f.Comments[pr] = slices.Delete(f.Comments[pr], i, i+1)
+	return nil
+}
+
+// MinimizeComment implements CommentAPI
+func (f *Fake) MinimizeComment(_ context.Context, nodeID, classifier string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["MinimizeComment"]; err != nil {
+		return err
+	}
+	f.Minimized[nodeID] = classifier
+	return nil
+}
+
+// UnminimizeComment implements CommentAPI
+func (f *Fake) UnminimizeComment(_ context.Context, nodeID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["UnminimizeComment"]; err != nil {
+		return err
+	}
+	delete(f.Minimized, nodeID)
+	return nil
+}
+
+// AddCommentReaction implements CommentAPI
+func (f *Fake) AddCommentReaction(_ context.Context, _, _ string, commentID int, content string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["AddCommentReaction"]; err != nil {
+		return err
+	}
+	if !slices.Contains(f.Reactions[commentID], content) {
+		f.Reactions[commentID] = append(f.Reactions[commentID], content)
+	}
+	return nil
+}
+
+// CreateStatus implements StatusAPI
+func (f *Fake) CreateStatus(_ context.Context, _, _, sha string, status *StatusRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["CreateStatus"]; err != nil {
+		return err
+	}
+	f.Statuses[sha] = append(f.Statuses[sha], *status)
+	return nil
+}
+
+// ListStatuses implements StatusAPI
+func (f *Fake) ListStatuses(_ context.Context, _, _, sha string) ([]StatusRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["ListStatuses"]; err != nil {
+		return nil, err
+	}
+	var latest []StatusRequest
+	statuses := f.Statuses[sha]
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !slices.ContainsFunc(latest, func(status StatusRequest) bool { return status.Context == statuses[i].Context }) {
+			latest = append(latest, statuses[i])
+		}
+	}
+	return latest, nil
+}
+
+// LatestStatus returns the newest status of a context on a commit, as GitHub shows it
+func (f *Fake) LatestStatus(sha, name string) (StatusRequest, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	statuses := f.Statuses[sha]
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].Context == name {
+			return statuses[i], true
+		}
+	}
+	return StatusRequest{}, false
+}
+
+// CreateDeployment implements DeploymentAPI
+func (f *Fake) CreateDeployment(_ context.Context, _, _ string, deployment *DeploymentRequest) (*Deployment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["CreateDeployment"]; err != nil {
+		return nil, err
+	}
+	created := Deployment{
+		ID:          int64(len(f.Deployments) + 1),
+		Ref:         deployment.Ref,
+		SHA:         deployment.Ref,
+		Environment: deployment.Environment,
+		Description: deployment.Description,
+	}
+	f.Deployments = append(f.Deployments, created)
+	return &created, nil
+}
+
+// CreateDeploymentStatus implements DeploymentAPI
+func (f *Fake) CreateDeploymentStatus(_ context.Context, _, _ string, deploymentID int64, status *DeploymentStatusRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["CreateDeploymentStatus"]; err != nil {
+		return err
+	}
+	if deploymentID < 1 || deploymentID > int64(len(f.Deployments)) {
+		return notFound("deployment %d", deploymentID)
+	}
+	f.DeploymentStatuses[deploymentID] = append(f.DeploymentStatuses[deploymentID], *status)
+	return nil
+}
+
+// GetPullRequest implements PullRequestAPI
+func (f *Fake) GetPullRequest(_ context.Context, _, _ string, pr int) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["GetPullRequest"]; err != nil {
+		return nil, err
+	}
+
+	pullRequest, ok := f.PullRequests[pr]
+	if !ok {
+		return nil, notFound("pull request %d not found", pr)
+	}
+	found := *pullRequest
+	found.Labels = slices.Clone(pullRequest.Labels)
+	return &found, nil
+}
+
+// GetPullRequests implements PullRequestAPI; pull requests that do not exist are left out
+func (f *Fake) GetPullRequests(ctx context.Context, owner, repo string, numbers []int) (map[int]*PullRequest, error) {
+	f.mu.Lock()
+	err := f.Errors["GetPullRequests"]
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*PullRequest, len(numbers))
+	for _, number := range numbers {
+		if pr, getErr := f.GetPullRequest(ctx, owner, repo, number); getErr == nil {
+			result[number] = pr
+		}
+	}
+	return result, nil
+}
+
+// GetPRDiff implements PullRequestAPI
+func (f *Fake) GetPRDiff(_ context.Context, _, _ string, pr int) (*PRDiff, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["GetPRDiff"]; err != nil {
+		return nil, err
+	}
+
+	diff, ok := f.Diffs[pr]
+	if !ok {
+		return nil, notFound("pull request %d not found", pr)
+	}
+	return &PRDiff{Files: slices.Clone(diff.Files)}, nil
+}
+
+// GetWorkflowRun implements ArtifactAPI
+func (f *Fake) GetWorkflowRun(_ context.Context, _, _ string, runID int64) (*WorkflowRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["GetWorkflowRun"]; err != nil {
+		return nil, err
+	}
+
+	run, ok := f.WorkflowRuns[runID]
+	if !ok {
+		return nil, notFound("workflow run %d not found", runID)
+	}
+	found := *run
+	return &found, nil
+}
+
+// ListCheckSuiteWorkflowRuns implements ArtifactAPI
+func (f *Fake) ListCheckSuiteWorkflowRuns(_ context.Context, _, _ string, checkSuiteID int64) ([]WorkflowRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["ListCheckSuiteWorkflowRuns"]; err != nil {
+		return nil, err
+	}
+
+	var runs []WorkflowRun
+	for _, run := range f.WorkflowRuns {
+		if run.CheckSuiteID == checkSuiteID {
+			runs = append(runs, *run)
+		}
+	}
+	slices.SortFunc(runs, func(a, b WorkflowRun) int { return cmp.Compare(a.ID, b.ID) })
+	return runs, nil
+}
+
+// ListCommitWorkflowRuns implements ArtifactAPI
+func (f *Fake) ListCommitWorkflowRuns(_ context.Context, _, _, sha string) ([]WorkflowRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["ListCommitWorkflowRuns"]; err != nil {
+		return nil, err
+	}
+
+	var runs []WorkflowRun
+	for _, run := range f.WorkflowRuns {
+		if run.HeadSHA == sha && run.Conclusion == "success" {
+			runs = append(runs, *run)
+		}
+	}
+	slices.SortFunc(runs, func(a, b WorkflowRun) int { return cmp.Compare(b.ID, a.ID) })
+	return runs, nil
+}
+
+// ListWorkflowRunArtifacts implements ArtifactAPI
+func (f *Fake) ListWorkflowRunArtifacts(_ context.Context, _, _ string, runID int64) ([]Artifact, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["ListWorkflowRunArtifacts"]; err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Artifacts[runID]), nil
+}
+
+// FindWorkflowRunArtifact implements ArtifactAPI
+func (f *Fake) FindWorkflowRunArtifact(ctx context.Context, owner, repo string, runID int64, name string) (*Artifact, error) {
+	artifacts, err := f.ListWorkflowRunArtifacts(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range artifacts {
+		if artifacts[i].Name != name {
+			continue
+		}
+		if artifacts[i].Expired {
+			return nil, fmt.Errorf("%w: %s", ErrArtifactExpired, name)
+		}
+		return &artifacts[i], nil
+	}
+	return nil, fmt.Errorf("%w: %s in run %d", ErrArtifactNotFound, name, runID)
+}
+
+// DownloadArtifact implements ArtifactAPI
+func (f *Fake) DownloadArtifact(_ context.Context, _, _ string, artifactID, maxSize int64) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.Errors["DownloadArtifact"]; err != nil {
+		return nil, err
+	}
+
+	data, ok := f.ArtifactData[artifactID]
+	if !ok {
+		return nil, notFound("artifact %d not found", artifactID)
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrArtifactTooLarge, maxSize)
+	}
+	return slices.Clone(data), nil
+}
+
+// RateLimit implements RateLimitAPI
+func (f *Fake) RateLimit() RateLimit {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Limit
+}
+
+// WaitForRateLimit implements RateLimitAPI. The fake never waits: it reports whether reserve
+// requests remain, treating an unknown quota as unlimited.
+func (f *Fake) WaitForRateLimit(_ context.Context, reserve int, _ time.Duration) (bool, error) {
+	rate := f.RateLimit()
+	return !rate.Known() || rate.Remaining >= reserve, nil
+}
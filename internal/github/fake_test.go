@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeWithPR returns a fake holding pull request 7 with head commit abc123
+func newFakeWithPR() *Fake {
+	fake := NewFake()
+	fake.PullRequests[7] = &PullRequest{Number: 7, State: "open", Head: PullRequestRef{Ref: "feature", SHA: "abc123"}}
+	return fake
+}
+
+func TestPRCommentManagerWithFake(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeWithPR()
+	manager := NewPRCommentManager(fake, &PRCommentConfig{
+		MaxCommentsPerPR:   1,
+		CommentSignature:   "go-coverage-v1",
+		EnableStatusChecks: true,
+		CoverageThreshold:  80,
+	})
+	comparison := &CoverageComparison{PRCoverage: CoverageData{Percentage: 85}}
+
+	created, err := manager.CreateOrUpdatePRComment(ctx, "owner", "repo", 7, "<!-- go-coverage-v1 -->\nfirst", comparison)
+	require.NoError(t, err)
+	assert.Equal(t, "created", created.Action)
+	require.Len(t, fake.Comments[7], 1)
+
+	status, ok := fake.LatestStatus("abc123", "Go-Coverage/Coverage-PR")
+	require.True(t, ok, "a status should be set on the head commit")
+	assert.Equal(t, StatusStateSuccess, status.State)
+
+	updated, err := manager.CreateOrUpdatePRComment(ctx, "owner", "repo", 7, "<!-- go-coverage-v1 -->\nsecond", comparison)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", updated.Action)
+	assert.Equal(t, created.CommentID, updated.CommentID)
+	require.Len(t, fake.Comments[7], 1)
+	assert.Contains(t, fake.Comments[7][0].Body, "second")
+
+	require.NoError(t, manager.DeletePRComments(ctx, "owner", "repo", 7))
+	assert.Empty(t, fake.Comments[7])
+}
+
+func TestPRCommentManagerWithFakeErrors(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeWithPR()
+	errUnavailable := errors.New("service unavailable")
+	fake.Errors["AddComment"] = errUnavailable
+
+	manager := NewPRCommentManager(fake, nil)
+	_, err := manager.CreateOrUpdatePRComment(ctx, "owner", "repo", 7, "body", &CoverageComparison{})
+	require.ErrorIs(t, err, errUnavailable)
+
+	_, err = manager.CreateOrUpdatePRComment(ctx, "owner", "repo", 8, "body", &CoverageComparison{})
+	require.ErrorIs(t, err, ErrGitHubAPIError, "unknown pull requests are reported like a 404")
+}
+
+func TestFakeArtifacts(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	fake.Artifacts[42] = []Artifact{{ID: 1, Name: "coverage"}, {ID: 2, Name: "old", Expired: true}}
+	fake.ArtifactData[1] = []byte("zip")
+
+	artifact, err := fake.FindWorkflowRunArtifact(ctx, "owner", "repo", 42, "coverage")
+	require.NoError(t, err)
+	data, err := fake.DownloadArtifact(ctx, "owner", "repo", artifact.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("zip"), data)
+
+	_, err = fake.DownloadArtifact(ctx, "owner", "repo", artifact.ID, 2)
+	require.ErrorIs(t, err, ErrArtifactTooLarge)
+	_, err = fake.FindWorkflowRunArtifact(ctx, "owner", "repo", 42, "old")
+	require.ErrorIs(t, err, ErrArtifactExpired)
+	_, err = fake.FindWorkflowRunArtifact(ctx, "owner", "repo", 42, "missing")
+	require.ErrorIs(t, err, ErrArtifactNotFound)
+
+	fake.WorkflowRuns[42] = &WorkflowRun{ID: 42, CheckSuiteID: 7}
+	fake.WorkflowRuns[43] = &WorkflowRun{ID: 43, CheckSuiteID: 8}
+	runs, err := fake.ListCheckSuiteWorkflowRuns(ctx, "owner", "repo", 7)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, int64(42), runs[0].ID)
+}
+
+func TestFakeRateLimit(t *testing.T) {
+	fake := NewFake()
+	ok, err := fake.WaitForRateLimit(context.Background(), 100, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "an unknown quota is unlimited")
+
+	fake.Limit = RateLimit{Limit: 5000, Remaining: 10, Reset: time.Now().Add(time.Hour)}
+	ok, err = fake.WaitForRateLimit(context.Background(), 100, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
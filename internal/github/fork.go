@@ -0,0 +1,64 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// pullRequestEvent holds the parts of a pull_request event payload needed to detect forks
+type pullRequestEvent struct {
+	PullRequest *struct {
+		Head struct {
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+		Base struct {
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"base"`
+	} `json:"pull_request"`
+}
+
+// IsForkPullRequest reports whether the GitHub Actions event payload at eventPath describes a
+// pull request whose head lives in another repository. Such runs get a read-only token, so
+// comments and statuses cannot be written. A head repository that no longer exists (a deleted
+// fork) counts as a fork; payloads of other events are never forks.
+func IsForkPullRequest(eventPath string) (bool, error) {
+	data, err := os.ReadFile(eventPath) //nolint:gosec // eventPath is GITHUB_EVENT_PATH, provided by the runner
+	if err != nil {
+		return false, fmt.Errorf("failed to read event payload: %w", err)
+	}
+
+	var event pullRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return false, fmt.Errorf("failed to parse event payload: %w", err)
+	}
+	if event.PullRequest == nil || event.PullRequest.Base.Repo == nil {
+		return false, nil
+	}
+	if event.PullRequest.Head.Repo == nil {
+		return true, nil
+	}
+	return !strings.EqualFold(event.PullRequest.Head.Repo.FullName, event.PullRequest.Base.Repo.FullName), nil
+}
+
+// ReadWorkflowRunEvent returns the triggering run of a workflow_run event payload, or nil when
+// the payload belongs to another event
+func ReadWorkflowRunEvent(eventPath string) (*WorkflowRun, error) {
+	data, err := os.ReadFile(eventPath) //nolint:gosec // eventPath is GITHUB_EVENT_PATH, provided by the runner
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event payload: %w", err)
+	}
+
+	var event struct {
+		WorkflowRun *WorkflowRun `json:"workflow_run"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse event payload: %w", err)
+	}
+	return event.WorkflowRun, nil
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForkPullRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:     "fork",
+			payload:  `{"pull_request":{"head":{"repo":{"full_name":"contributor/repo"}},"base":{"repo":{"full_name":"owner/repo"}}}}`,
+			expected: true,
+		},
+		{
+			name:     "same repository with different case",
+			payload:  `{"pull_request":{"head":{"repo":{"full_name":"Owner/Repo"}},"base":{"repo":{"full_name":"owner/repo"}}}}`,
+			expected: false,
+		},
+		{
+			name:     "deleted fork",
+			payload:  `{"pull_request":{"head":{"repo":null},"base":{"repo":{"full_name":"owner/repo"}}}}`,
+			expected: true,
+		},
+		{
+			name:     "push event",
+			payload:  `{"ref":"refs/heads/master"}`,
+			expected: false,
+		},
+		{
+			name:    "invalid payload",
+			payload: `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "event.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.payload), 0o600))
+
+			fork, err := IsForkPullRequest(path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fork)
+		})
+	}
+
+	_, err := IsForkPullRequest(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestReadWorkflowRunEvent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "event.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"workflow_run":{"id":99,"head_sha":"abc","event":"pull_request"}}`), 0o600))
+
+	run, err := ReadWorkflowRunEvent(path)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, int64(99), run.ID)
+	assert.Equal(t, "abc", run.HeadSHA)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"pull_request":{}}`), 0o600))
+	run, err = ReadWorkflowRunEvent(path)
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
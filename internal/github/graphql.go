@@ -0,0 +1,183 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrGraphQL indicates that a GraphQL request returned errors
+var ErrGraphQL = errors.New("GitHub GraphQL error")
+
+// Classifiers accepted by the minimizeComment mutation
+const (
+	MinimizeResolved = "RESOLVED"
+	MinimizeOutdated = "OUTDATED"
+)
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLError is an entry of the errors array of a GraphQL response
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// GraphQLErrors are the errors returned with a GraphQL response. Any data returned alongside
+// them has been decoded, so callers of batched queries can keep the parts that succeeded.
+type GraphQLErrors []GraphQLError
+
+// Error joins the messages of the errors
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrGraphQL, strings.Join(messages, "; "))
+}
+
+// Unwrap makes GraphQLErrors match ErrGraphQL
+func (e GraphQLErrors) Unwrap() error {
+	return ErrGraphQL
+}
+
+// OnlyType reports whether every error has the given type, e.g. "NOT_FOUND"
+func (e GraphQLErrors) OnlyType(errorType string) bool {
+	for _, err := range e {
+		if err.Type != errorType {
+			return false
+		}
+	}
+	return len(e) > 0
+}
+
+// graphQLURL returns the GraphQL endpoint for the REST base URL: api.github.com/graphql on
+// github.com and <host>/api/graphql on GitHub Enterprise Server
+func (c *Client) graphQLURL() string {
+	base := strings.TrimSuffix(c.baseURL, "/")
+	if enterprise, ok := strings.CutSuffix(base, "/api/v3"); ok {
+		return enterprise + "/api/graphql"
+	}
+	return base + "/graphql"
+}
+
+// GraphQL executes a query or mutation and decodes its data into out (which may be nil). It
+// shares authentication, retries and rate limit tracking with the REST methods. When the
+// response carries errors they are returned as GraphQLErrors, after decoding any partial data.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	jsonData, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphQLURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute GraphQL request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if out != nil && len(response.Data) > 0 && !bytes.Equal(response.Data, []byte("null")) {
+		if err := json.Unmarshal(response.Data, out); err != nil {
+			return fmt.Errorf("failed to decode GraphQL data: %w", err)
+		}
+	}
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+	return nil
+}
+
+// GraphQLQuery executes a query and returns its data decoded as T. On GraphQLErrors the
+// partial data is returned along with the error.
+func GraphQLQuery[T any](ctx context.Context, c *Client, query string, variables map[string]any) (*T, error) {
+	var data T
+	if err := c.GraphQL(ctx, query, variables, &data); err != nil {
+		var graphQLErrors GraphQLErrors
+		if errors.As(err, &graphQLErrors) {
+			return &data, err
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// graphQLBatch builds a query that runs several lookups at once, each under its own alias, so
+// N objects cost one request instead of N
+type graphQLBatch struct {
+	fields []string
+}
+
+// add appends a lookup, e.g. add("pr12", `pullRequest(number: 12)`, "{ title }")
+func (b *graphQLBatch) add(alias, field, selection string) {
+	b.fields = append(b.fields, fmt.Sprintf("%s: %s %s", alias, field, selection))
+}
+
+// query wraps the lookups in the given parent field, e.g. `repository(owner: $owner, name: $name)`,
+// and declares the variables, e.g. "$owner: String!, $name: String!"
+func (b *graphQLBatch) query(declarations, parent string) string {
+	var q strings.Builder
+	q.WriteString("query")
+	if declarations != "" {
+		fmt.Fprintf(&q, "(%s)", declarations)
+	}
+	q.WriteString(" {\n")
+	if parent != "" {
+		fmt.Fprintf(&q, "  %s {\n", parent)
+	}
+	for _, field := range b.fields {
+		fmt.Fprintf(&q, "    %s\n", field)
+	}
+	if parent != "" {
+		q.WriteString("  }\n")
+	}
+	q.WriteString("}")
+	return q.String()
+}
+
+// MinimizeComment hides a comment behind the given classifier, e.g. MinimizeResolved.
+// nodeID is the GraphQL node ID of the comment, not its REST ID.
+func (c *Client) MinimizeComment(ctx context.Context, nodeID, classifier string) error {
+	const mutation = `mutation($id: ID!, $classifier: ReportedContentClassifiers!) {
+  minimizeComment(input: {subjectId: $id, classifier: $classifier}) { minimizedComment { isMinimized } }
+}`
+	return c.GraphQL(ctx, mutation, map[string]any{"id": nodeID, "classifier": classifier}, nil)
+}
+
+// UnminimizeComment shows a previously minimized comment again
+func (c *Client) UnminimizeComment(ctx context.Context, nodeID string) error {
+	const mutation = `mutation($id: ID!) {
+  unminimizeComment(input: {subjectId: $id}) { unminimizedComment { isMinimized } }
+}`
+	return c.GraphQL(ctx, mutation, map[string]any{"id": nodeID}, nil)
+}
@@ -0,0 +1,130 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLURL(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		expected string
+	}{
+		{"https://api.github.com", "https://api.github.com/graphql"},
+		{"https://api.github.com/", "https://api.github.com/graphql"},
+		{"https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+	}
+	for _, tt := range tests {
+		client := NewWithConfig(&Config{BaseURL: tt.baseURL})
+		assert.Equal(t, tt.expected, client.graphQLURL(), tt.baseURL)
+	}
+}
+
+func TestMinimizeComment(t *testing.T) {
+	var request graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "token "+testToken, r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(`{"data":{"minimizeComment":{"minimizedComment":{"isMinimized":true}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+	require.NoError(t, client.MinimizeComment(context.Background(), "IC_node", MinimizeResolved))
+	assert.Contains(t, request.Query, "minimizeComment")
+	assert.Equal(t, "IC_node", request.Variables["id"])
+	assert.Equal(t, MinimizeResolved, request.Variables["classifier"])
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"type":"FORBIDDEN","message":"Resource not accessible by integration"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second})
+	err := client.UnminimizeComment(context.Background(), "IC_node")
+	require.ErrorIs(t, err, ErrGraphQL)
+	assert.Contains(t, err.Error(), "Resource not accessible by integration")
+
+	client = NewWithConfig(&Config{Token: testToken, BaseURL: server.URL + "/api/v3", Timeout: 5 * time.Second})
+	err = client.UnminimizeComment(context.Background(), "IC_node")
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
+
+func TestAddCommentReaction(t *testing.T) {
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/issues/comments/7/reactions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		content = body["content"]
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second})
+	require.NoError(t, client.AddCommentReaction(context.Background(), "owner", "repo", 7, ReactionHooray))
+	assert.Equal(t, ReactionHooray, content)
+
+	err := client.AddCommentReaction(context.Background(), "owner", "repo", 8, ReactionHooray)
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
+
+func TestGraphQLQueryPartialData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		_, _ = w.Write([]byte(`{"data":{"a":{"name":"found"},"b":null},"errors":[{"type":"NOT_FOUND","path":["b"],"message":"not found"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second})
+	type node struct {
+		Name string `json:"name"`
+	}
+	data, err := GraphQLQuery[map[string]*node](context.Background(), client, "query { a: x b: y }", nil)
+	require.ErrorIs(t, err, ErrGraphQL)
+
+	var graphQLErrors GraphQLErrors
+	require.ErrorAs(t, err, &graphQLErrors)
+	assert.True(t, graphQLErrors.OnlyType("NOT_FOUND"))
+	assert.False(t, graphQLErrors.OnlyType("FORBIDDEN"))
+	require.NotNil(t, data)
+	assert.Equal(t, "found", (*data)["a"].Name)
+	assert.Nil(t, (*data)["b"])
+
+	// The GraphQL quota is separate from the REST quota that batches wait for
+	assert.False(t, client.RateLimit().Known())
+}
+
+func TestGraphQLBatch(t *testing.T) {
+	var batch graphQLBatch
+	batch.add("pr1", "pullRequest(number: 1)", "{ title }")
+	batch.add("pr2", "pullRequest(number: 2)", "{ title }")
+
+	expected := `query($owner: String!) {
+  repository(owner: $owner, name: "repo") {
+    pr1: pullRequest(number: 1) { title }
+    pr2: pullRequest(number: 2) { title }
+  }
+}`
+	assert.Equal(t, expected, batch.query("$owner: String!", `repository(owner: $owner, name: "repo")`))
+	assert.Equal(t, "query {\n    pr1: pullRequest(number: 1) { title }\n    pr2: pullRequest(number: 2) { title }\n}", batch.query("", ""))
+}
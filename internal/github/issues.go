@@ -0,0 +1,43 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// AddAssignees assigns users to an issue. Logins that cannot be assigned, such as users without
+// access to the repository, are dropped by GitHub without an error.
+func (c *Client) AddAssignees(ctx context.Context, owner, repo string, number int, logins []string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/assignees", c.baseURL, owner, repo, number)
+
+	jsonData, err := json.Marshal(map[string][]string{"assignees": logins})
+	if err != nil {
+		return fmt.Errorf("failed to marshal assignees: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to add assignees: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAssignees(t *testing.T) {
+	var request struct {
+		Assignees []string `json:"assignees"`
+	}
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/o/r/issues/7/assignees", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+
+	require.NoError(t, client.AddAssignees(context.Background(), "o", "r", 7, []string{"alice", "bob"}))
+	assert.Equal(t, []string{"alice", "bob"}, request.Assignees)
+
+	status = http.StatusForbidden
+	require.ErrorIs(t, client.AddAssignees(context.Background(), "o", "r", 7, []string{"alice"}), ErrGitHubAPIError)
+}
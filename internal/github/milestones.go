@@ -0,0 +1,132 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrMilestoneNotFound is returned when a repository has no milestone with the given title
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+// milestonePageSize is the number of milestones requested per page, the most the API returns
+const milestonePageSize = 100
+
+// Milestone states
+const (
+	MilestoneOpen   = "open"
+	MilestoneClosed = "closed"
+)
+
+// Milestone is a milestone of a repository
+type Milestone struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	DueOn       string `json:"due_on,omitempty"` // RFC 3339 timestamp
+	URL         string `json:"html_url"`
+}
+
+// MilestoneRequest is the body of a request creating or editing a milestone. Empty fields are
+// left unchanged.
+type MilestoneRequest struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+	DueOn       string `json:"due_on,omitempty"` // RFC 3339 timestamp
+}
+
+// FindMilestone returns the open or closed milestone with exactly the given title, or
+// ErrMilestoneNotFound if there is none
+func (c *Client) FindMilestone(ctx context.Context, owner, repo, title string) (*Milestone, error) {
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/milestones?state=all&per_page=%d&page=%d", c.baseURL, owner, repo, milestonePageSize, page)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "token "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("User-Agent", c.config.UserAgent)
+
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list milestones: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+		}
+		var batch []Milestone
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode milestones response: %w", err)
+		}
+
+		for i := range batch {
+			if batch[i].Title == title {
+				return &batch[i], nil
+			}
+		}
+		if len(batch) < milestonePageSize {
+			return nil, fmt.Errorf("%w: %q", ErrMilestoneNotFound, title)
+		}
+	}
+}
+
+// CreateMilestone creates a milestone and returns it
+func (c *Client) CreateMilestone(ctx context.Context, owner, repo string, milestone MilestoneRequest) (*Milestone, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/milestones", c.baseURL, owner, repo)
+	return c.sendMilestone(ctx, "POST", url, milestone)
+}
+
+// UpdateMilestone edits a milestone and returns it
+func (c *Client) UpdateMilestone(ctx context.Context, owner, repo string, number int, milestone MilestoneRequest) (*Milestone, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/milestones/%d", c.baseURL, owner, repo, number)
+	return c.sendMilestone(ctx, "PATCH", url, milestone)
+}
+
+// sendMilestone sends a milestone request and decodes the milestone in the response
+func (c *Client) sendMilestone(ctx context.Context, method, url string, milestone MilestoneRequest) (*Milestone, error) {
+	jsonData, err := json.Marshal(milestone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal milestone: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save milestone: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var result Milestone
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode milestone response: %w", err)
+	}
+	return &result, nil
+}
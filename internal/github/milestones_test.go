@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMilestone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/o/r/milestones", r.URL.Path)
+		assert.Equal(t, "all", r.URL.Query().Get("state"))
+		if r.URL.Query().Get("page") == "1" {
+			milestones := make([]Milestone, 0, milestonePageSize)
+			for i := range milestonePageSize {
+				milestones = append(milestones, Milestone{Number: i + 1, Title: fmt.Sprintf("v%d", i)})
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(milestones))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"number":101,"title":"Coverage goal","state":"closed","html_url":"https://github.com/o/r/milestone/101"}]`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+
+	milestone, err := client.FindMilestone(context.Background(), "o", "r", "Coverage goal")
+	require.NoError(t, err)
+	assert.Equal(t, &Milestone{Number: 101, Title: "Coverage goal", State: MilestoneClosed, URL: "https://github.com/o/r/milestone/101"}, milestone)
+
+	milestone, err = client.FindMilestone(context.Background(), "o", "r", "v3")
+	require.NoError(t, err)
+	assert.Equal(t, 4, milestone.Number)
+
+	_, err = client.FindMilestone(context.Background(), "o", "r", "Missing")
+	require.ErrorIs(t, err, ErrMilestoneNotFound)
+}
+
+func TestSaveMilestone(t *testing.T) {
+	var method, path string
+	var request map[string]any
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		request = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"number":3,"title":"Coverage goal","state":"open"}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+
+	milestone, err := client.CreateMilestone(context.Background(), "o", "r", MilestoneRequest{
+		Title: "Coverage goal", Description: "50% complete", DueOn: "2026-12-31T00:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, milestone.Number)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/repos/o/r/milestones", path)
+	assert.Equal(t, map[string]any{"title": "Coverage goal", "description": "50% complete", "due_on": "2026-12-31T00:00:00Z"}, request)
+
+	status = http.StatusOK
+	_, err = client.UpdateMilestone(context.Background(), "o", "r", 3, MilestoneRequest{Description: "done", State: MilestoneClosed})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, method)
+	assert.Equal(t, "/repos/o/r/milestones/3", path)
+	assert.Equal(t, map[string]any{"description": "done", "state": "closed"}, request)
+
+	status = http.StatusUnprocessableEntity
+	_, err = client.UpdateMilestone(context.Background(), "o", "r", 3, MilestoneRequest{State: MilestoneOpen})
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
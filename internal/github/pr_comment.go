@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -15,7 +14,7 @@ import (
 
 // PRCommentManager handles intelligent PR comment management with anti-spam and lifecycle features
 type PRCommentManager struct {
-	client *Client
+	client PRCommentAPI
 	config *PRCommentConfig
 	logger logger.Logger
 }
@@ -41,6 +40,10 @@ type PRCommentConfig struct {
 	FailBelowThreshold  bool    // Fail status if below threshold
 	CoverageThreshold   float64 // Coverage threshold for status checks
 	BlockMergeOnFailure bool    // Block PR merge on coverage failure
+
+	// Thread tidiness settings
+	ResolveMode        string  // Tidy the comment once coverage passes after failing: "", ResolveModeMark or ResolveModeMinimize
+	CelebrateThreshold float64 // React 🎉 when coverage improves by at least this many points (0 disables)
 }
 
 // CoverageComparison represents coverage comparison between base and PR branches
@@ -74,13 +77,15 @@ type TrendData struct {
 
 // FileChange represents coverage change for a specific file
 type FileChange struct {
-	Filename      string  `json:"filename"`
-	BaseCoverage  float64 `json:"base_coverage"`
-	PRCoverage    float64 `json:"pr_coverage"`
-	Difference    float64 `json:"difference"`
-	LinesAdded    int     `json:"lines_added"`
-	LinesRemoved  int     `json:"lines_removed"`
-	IsSignificant bool    `json:"is_significant"`
+	Filename         string  `json:"filename"`
+	PreviousFilename string  `json:"previous_filename,omitempty"` // Set when the PR renamed the file
+	BaseCoverage     float64 `json:"base_coverage"`
+	PRCoverage       float64 `json:"pr_coverage"`
+	Difference       float64 `json:"difference"`
+	LinesAdded       int     `json:"lines_added"`
+	LinesRemoved     int     `json:"lines_removed"`
+	IsSignificant    bool    `json:"is_significant"`
+	IsMoved          bool    `json:"is_moved,omitempty"` // Coverage only changed because code moved between files
 }
 
 // CommentMetadata represents metadata stored in comment for tracking
@@ -104,10 +109,13 @@ type PRCommentResponse struct {
 	CoverageData   CoverageComparison `json:"coverage_data"`
 	BadgeURLs      map[string]string  `json:"badge_urls"` // PR-specific badge URLs
 	StatusCheckURL string             `json:"status_check_url"`
+	Resolved       bool               `json:"resolved"`  // Coverage passes after the comment reported a failure
+	Minimized      bool               `json:"minimized"` // The comment was collapsed as resolved
+	Reacted        bool               `json:"reacted"`   // A 🎉 reaction was added for the improvement
 }
 
 // NewPRCommentManager creates a new PR comment manager with configuration
-func NewPRCommentManager(client *Client, config *PRCommentConfig) *PRCommentManager {
+func NewPRCommentManager(client PRCommentAPI, config *PRCommentConfig) *PRCommentManager {
 	if config == nil {
 		config = &PRCommentConfig{
 			MinUpdateIntervalMinutes: 5,
@@ -148,6 +156,7 @@ func (m *PRCommentManager) CreateOrUpdatePRComment(ctx context.Context, owner, r
 
 	// Determine action based on anti-spam rules
 	action, shouldUpdate, reason := m.determineCommentAction(existingComments, comparison)
+	commentBody = withCommentMarker(commentBody, owner, repo)
 
 	if !shouldUpdate {
 		return &PRCommentResponse{
@@ -157,12 +166,17 @@ func (m *PRCommentManager) CreateOrUpdatePRComment(ctx context.Context, owner, r
 		}, nil
 	}
 
+	plan := m.planTidy(existingComments, commentBody)
+	if plan.resolved && m.config.ResolveMode == ResolveModeMark {
+		commentBody = withResolvedBanner(commentBody)
+	}
+
 	var comment *Comment
 	var commentID int
 
 	if len(existingComments) > 0 {
 		// Update existing comment
-		comment, err = m.client.updateComment(ctx, owner, repo, existingComments[0].ID, commentBody)
+		comment, err = m.client.UpdateComment(ctx, owner, repo, existingComments[0].ID, commentBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update comment: %w", err)
 		}
@@ -170,7 +184,7 @@ func (m *PRCommentManager) CreateOrUpdatePRComment(ctx context.Context, owner, r
 		action = "updated"
 	} else {
 		// Create new comment
-		comment, err = m.client.createComment(ctx, owner, repo, prNumber, commentBody)
+		comment, err = m.client.AddComment(ctx, owner, repo, prNumber, commentBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create comment: %w", err)
 		}
@@ -218,7 +232,7 @@ func (m *PRCommentManager) CreateOrUpdatePRComment(ctx context.Context, owner, r
 		}
 	}
 
-	return &PRCommentResponse{
+	response := &PRCommentResponse{
 		CommentID:      commentID,
 		Action:         action,
 		Reason:         reason,
@@ -226,7 +240,10 @@ func (m *PRCommentManager) CreateOrUpdatePRComment(ctx context.Context, owner, r
 		CoverageData:   *comparison,
 		BadgeURLs:      badgeURLs,
 		StatusCheckURL: statusCheckURL,
-	}, nil
+	}
+	m.tidyComment(ctx, owner, repo, comment, plan, comparison, response)
+
+	return response, nil
 }
 
 // findExistingCoverageComments finds existing coverage comments by signature with retry logic
@@ -237,75 +254,12 @@ func (m *PRCommentManager) findExistingCoverageComments(ctx context.Context, own
 		"pr_number": prNumber,
 	})
 
-	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", m.client.baseURL, owner, repo, prNumber)
-
-	var allComments []Comment
-	var lastErr error
-
-	// Retry logic for robustness
-	maxRetries := 3
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		m.logger.Debug("Attempting to fetch PR comments", map[string]any{
-			"attempt": attempt,
-			"url":     url,
-		})
-
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			m.logger.Error("Failed to create request", map[string]any{
-				"error":   err,
-				"attempt": attempt,
-			})
-			continue
-		}
-
-		req.Header.Set("Authorization", "token "+m.client.token)
-		req.Header.Set("User-Agent", m.client.config.UserAgent)
-
-		resp, err := m.client.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to get comments: %w", err)
-			m.logger.Error("Failed to execute request", map[string]any{
-				"error":   err,
-				"attempt": attempt,
-			})
-			time.Sleep(time.Duration(attempt) * time.Second) // Exponential backoff
-			continue
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			lastErr = fmt.Errorf("%w: %d", ErrGitHubAPIError, resp.StatusCode)
-			m.logger.Error("GitHub API returned error status", map[string]any{
-				"status_code": resp.StatusCode,
-				"attempt":     attempt,
-			})
-			time.Sleep(time.Duration(attempt) * time.Second) // Exponential backoff
-			continue
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(&allComments); err != nil {
-			lastErr = fmt.Errorf("failed to decode comments: %w", err)
-			m.logger.Error("Failed to decode response", map[string]any{
-				"error":   err,
-				"attempt": attempt,
-			})
-			time.Sleep(time.Duration(attempt) * time.Second) // Exponential backoff
-			continue
-		}
-
-		// Success - break out of retry loop
-		lastErr = nil
-		break
-	}
-
-	if lastErr != nil {
+	allComments, err := m.client.ListComments(ctx, owner, repo, prNumber)
+	if err != nil {
 		m.logger.Error("All attempts to fetch comments failed", map[string]any{
-			"error":        lastErr,
-			"max_attempts": maxRetries,
+			"error": err,
 		})
-		return nil, lastErr
+		return nil, err
 	}
 
 	m.logger.Info("Successfully fetched PR comments", map[string]any{
@@ -315,7 +269,8 @@ func (m *PRCommentManager) findExistingCoverageComments(ctx context.Context, own
 	// Filter for our coverage comments with detailed logging
 	var coverageComments []Comment
 	for i, comment := range allComments {
-		isCoverage := m.isCoverageComment(comment.Body)
+		isCoverage := !strings.Contains(comment.Body, finalCommentMarker(owner, repo)) &&
+			(strings.Contains(comment.Body, commentMarker(owner, repo)) || m.isCoverageComment(comment.Body))
 		m.logger.Debug("Checking comment", map[string]any{
 			"comment_id":  comment.ID,
 			"comment_idx": i,
@@ -343,6 +298,21 @@ func (m *PRCommentManager) findExistingCoverageComments(ctx context.Context, own
 	return coverageComments, nil
 }
 
+// commentMarker is the hidden marker identifying the coverage comment of a repository, so the
+// comment is found again after its signature, header or footer change
+func commentMarker(owner, repo string) string {
+	return fmt.Sprintf("<!-- go-coverage:comment %s/%s -->", strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// withCommentMarker appends the hidden marker of the repository to a comment body without one
+func withCommentMarker(body, owner, repo string) string {
+	marker := commentMarker(owner, repo)
+	if strings.Contains(body, marker) {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + marker + "\n"
+}
+
 // isCoverageComment checks if a comment is our coverage comment by signature
 func (m *PRCommentManager) isCoverageComment(body string) bool {
 	signatures := []string{
@@ -526,21 +496,8 @@ func (m *PRCommentManager) DeletePRComments(ctx context.Context, owner, repo str
 	}
 
 	for _, comment := range existingComments {
-		url := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d", m.client.baseURL, owner, repo, comment.ID)
-
-		req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-		if err != nil {
-			continue // Skip this comment if request creation fails
-		}
-
-		req.Header.Set("Authorization", "token "+m.client.token)
-		req.Header.Set("User-Agent", m.client.config.UserAgent)
-
-		resp, err := m.client.httpClient.Do(req)
-		if err != nil {
-			continue // Skip this comment if deletion fails
-		}
-		_ = resp.Body.Close()
+		// Skip comments that cannot be deleted
+		_ = m.client.DeleteComment(ctx, owner, repo, comment.ID)
 	}
 
 	return nil
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// archivedMarker identifies earlier coverage comments marked as archived
+const archivedMarker = "[//]: # (go-coverage-archived)"
+
+// archivedBanner is added to earlier coverage comments that cannot be minimized
+const archivedBanner = archivedMarker + "\n> 🗄️ **Archived**: this pull request is closed, see the final coverage summary below.\n\n"
+
+// FinalizeResponse describes how the coverage comments of a closed pull request were archived
+type FinalizeResponse struct {
+	CommentID int    `json:"comment_id"`
+	Action    string `json:"action"`    // "created" or "updated"
+	Minimized int    `json:"minimized"` // Earlier coverage comments collapsed as outdated
+	Marked    int    `json:"marked"`    // Earlier coverage comments given the archived banner instead
+}
+
+// finalCommentMarker is the hidden marker identifying the final summary of a closed pull request,
+// which later coverage comments leave alone
+func finalCommentMarker(owner, repo string) string {
+	return fmt.Sprintf("<!-- go-coverage:final %s/%s -->", strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// FinalizePRComments posts the final summary of a closed pull request, or updates the one an
+// earlier run posted, and archives the earlier coverage comments: they are minimized as outdated,
+// or given an archived banner when they cannot be minimized.
+func (m *PRCommentManager) FinalizePRComments(ctx context.Context, owner, repo string, prNumber int, body string) (*FinalizeResponse, error) {
+	comments, err := m.client.ListComments(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing comments: %w", err)
+	}
+
+	marker := finalCommentMarker(owner, repo)
+	body = strings.TrimRight(body, "\n") + "\n\n" + marker + "\n"
+
+	var final *Comment
+	var earlier []Comment
+	for i := range comments {
+		switch {
+		case strings.Contains(comments[i].Body, marker):
+			if final == nil {
+				final = &comments[i]
+			}
+		case strings.Contains(comments[i].Body, commentMarker(owner, repo)) || m.isCoverageComment(comments[i].Body):
+			earlier = append(earlier, comments[i])
+		}
+	}
+
+	response := &FinalizeResponse{}
+	for i := range earlier {
+		m.archiveComment(ctx, owner, repo, &earlier[i], response)
+	}
+
+	if final != nil {
+		updated, updateErr := m.client.UpdateComment(ctx, owner, repo, final.ID, body)
+		if updateErr != nil {
+			return nil, fmt.Errorf("failed to update final comment: %w", updateErr)
+		}
+		response.CommentID, response.Action = updated.ID, "updated"
+		return response, nil
+	}
+
+	created, err := m.client.AddComment(ctx, owner, repo, prNumber, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create final comment: %w", err)
+	}
+	response.CommentID, response.Action = created.ID, "created"
+	return response, nil
+}
+
+// archiveComment minimizes an earlier coverage comment as outdated, falling back to the archived
+// banner. Failures are logged only, as they do not affect the final summary.
+func (m *PRCommentManager) archiveComment(ctx context.Context, owner, repo string, comment *Comment, response *FinalizeResponse) {
+	if comment.NodeID != "" {
+		err := m.client.MinimizeComment(ctx, comment.NodeID, MinimizeOutdated)
+		if err == nil {
+			response.Minimized++
+			return
+		}
+		m.logger.WithError(err).Warn("Failed to minimize coverage comment, marking it archived instead")
+	}
+
+	if strings.Contains(comment.Body, archivedMarker) {
+		response.Marked++
+		return
+	}
+	if _, err := m.client.UpdateComment(ctx, owner, repo, comment.ID, withBanner(comment.Body, archivedBanner)); err != nil {
+		m.logger.WithError(err).Warn("Failed to mark coverage comment archived")
+		return
+	}
+	response.Marked++
+}
@@ -0,0 +1,74 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizePRComments(t *testing.T) {
+	ctx := context.Background()
+	seed := func(t *testing.T) (*Fake, *PRCommentManager) {
+		t.Helper()
+		fake := NewFake()
+		_, err := fake.AddComment(ctx, "owner", "repo", 1, withCommentMarker(tidyCommentBody("failed"), "owner", "repo"))
+		require.NoError(t, err)
+		_, err = fake.AddComment(ctx, "owner", "repo", 1, "LGTM")
+		require.NoError(t, err)
+		return fake, NewPRCommentManager(fake, nil)
+	}
+
+	t.Run("archives earlier comments and posts the summary", func(t *testing.T) {
+		fake, manager := seed(t)
+
+		response, err := manager.FinalizePRComments(ctx, "owner", "repo", 1, "## Final coverage\n")
+		require.NoError(t, err)
+		assert.Equal(t, "created", response.Action)
+		assert.Equal(t, 1, response.Minimized)
+		assert.Equal(t, map[string]string{"IC_1": MinimizeOutdated}, fake.Minimized)
+
+		comments := fake.Comments[1]
+		require.Len(t, comments, 3)
+		assert.Equal(t, response.CommentID, comments[2].ID)
+		assert.Contains(t, comments[2].Body, finalCommentMarker("owner", "repo"))
+	})
+
+	t.Run("updates the summary of an earlier run", func(t *testing.T) {
+		fake, manager := seed(t)
+		_, err := manager.FinalizePRComments(ctx, "owner", "repo", 1, "first")
+		require.NoError(t, err)
+
+		response, err := manager.FinalizePRComments(ctx, "owner", "repo", 1, "second")
+		require.NoError(t, err)
+		assert.Equal(t, "updated", response.Action)
+		assert.Equal(t, 1, response.Minimized)
+		require.Len(t, fake.Comments[1], 3)
+		assert.Contains(t, fake.Comments[1][2].Body, "second")
+	})
+
+	t.Run("marks comments that cannot be minimized", func(t *testing.T) {
+		fake, manager := seed(t)
+		fake.Errors["MinimizeComment"] = errors.New("forbidden")
+
+		response, err := manager.FinalizePRComments(ctx, "owner", "repo", 1, "summary")
+		require.NoError(t, err)
+		assert.Equal(t, 0, response.Minimized)
+		assert.Equal(t, 1, response.Marked)
+		assert.Contains(t, fake.Comments[1][0].Body, "🗄️ **Archived**")
+		assert.Equal(t, "LGTM", fake.Comments[1][1].Body)
+	})
+
+	t.Run("final summary is not the coverage comment", func(t *testing.T) {
+		fake, manager := seed(t)
+		_, err := manager.FinalizePRComments(ctx, "owner", "repo", 1, "summary")
+		require.NoError(t, err)
+
+		existing, err := manager.findExistingCoverageComments(ctx, "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, existing, 1)
+		assert.Equal(t, fake.Comments[1][0].ID, existing[0].ID)
+	})
+}
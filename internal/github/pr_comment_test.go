@@ -160,11 +160,10 @@ func TestCreateOrUpdatePRComment(t *testing.T) {
 			defer server.Close()
 
 			client := NewWithConfig(&Config{
-				Token:      testToken,
-				BaseURL:    server.URL,
-				Timeout:    5 * time.Second,
-				RetryCount: 1,
-				UserAgent:  testAgent,
+				Token:     testToken,
+				BaseURL:   server.URL,
+				Timeout:   5 * time.Second,
+				UserAgent: testAgent,
 			})
 
 			manager := NewPRCommentManager(client, nil)
@@ -236,6 +235,30 @@ func TestFindExistingCoverageComments(t *testing.T) {
 			},
 			expectedCount: 2,
 		},
+		{
+			name: "comment with a custom signature found by its marker",
+			setupMockFn: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/repos/testowner/testrepo/issues/123/comments" && r.Method == "GET" {
+						comments := []map[string]any{
+							{
+								"id":   1,
+								"body": "[//]: # (acme-bot)\n# Test health\n\n<!-- go-coverage:comment testowner/testrepo -->\n",
+							},
+							{
+								"id":   2,
+								"body": "[//]: # (acme-bot)\n# Test health\n\n<!-- go-coverage:comment testowner/fork -->\n",
+							},
+						}
+						w.Header().Set("Content-Type", "application/json")
+						assert.NoError(t, json.NewEncoder(w).Encode(comments))
+					} else {
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}))
+			},
+			expectedCount: 1,
+		},
 		{
 			name: "API error",
 			setupMockFn: func() *httptest.Server {
@@ -253,11 +276,10 @@ func TestFindExistingCoverageComments(t *testing.T) {
 			defer server.Close()
 
 			client := NewWithConfig(&Config{
-				Token:      testToken,
-				BaseURL:    server.URL,
-				Timeout:    5 * time.Second,
-				RetryCount: 1,
-				UserAgent:  testAgent,
+				Token:     testToken,
+				BaseURL:   server.URL,
+				Timeout:   5 * time.Second,
+				UserAgent: testAgent,
 			})
 
 			manager := NewPRCommentManager(client, nil)
@@ -319,6 +341,12 @@ func TestIsCoverageComment(t *testing.T) {
 	}
 }
 
+func TestWithCommentMarker(t *testing.T) {
+	body := withCommentMarker("# Coverage\n\n", "Owner", "Repo")
+	assert.Equal(t, "# Coverage\n\n<!-- go-coverage:comment owner/repo -->\n", body)
+	assert.Equal(t, body, withCommentMarker(body, "owner", "repo"), "the marker is added once")
+}
+
 func TestDetermineCommentAction(t *testing.T) {
 	manager := NewPRCommentManager(New(testToken), &PRCommentConfig{
 		MinUpdateIntervalMinutes: 5,
@@ -489,11 +517,10 @@ func TestCreateCoverageStatusCheck(t *testing.T) {
 			defer server.Close()
 
 			client := NewWithConfig(&Config{
-				Token:      testToken,
-				BaseURL:    server.URL,
-				Timeout:    5 * time.Second,
-				RetryCount: 1,
-				UserAgent:  testAgent,
+				Token:     testToken,
+				BaseURL:   server.URL,
+				Timeout:   5 * time.Second,
+				UserAgent: testAgent,
 			})
 
 			manager := NewPRCommentManager(client, &PRCommentConfig{
@@ -604,11 +631,10 @@ func TestDeletePRComments(t *testing.T) {
 			defer server.Close()
 
 			client := NewWithConfig(&Config{
-				Token:      testToken,
-				BaseURL:    server.URL,
-				Timeout:    5 * time.Second,
-				RetryCount: 1,
-				UserAgent:  testAgent,
+				Token:     testToken,
+				BaseURL:   server.URL,
+				Timeout:   5 * time.Second,
+				UserAgent: testAgent,
 			})
 
 			manager := NewPRCommentManager(client, nil)
@@ -667,11 +693,10 @@ func TestGetPRCommentStats(t *testing.T) {
 			defer server.Close()
 
 			client := NewWithConfig(&Config{
-				Token:      testToken,
-				BaseURL:    server.URL,
-				Timeout:    5 * time.Second,
-				RetryCount: 1,
-				UserAgent:  testAgent,
+				Token:     testToken,
+				BaseURL:   server.URL,
+				Timeout:   5 * time.Second,
+				UserAgent: testAgent,
 			})
 
 			manager := NewPRCommentManager(client, nil)
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Ways of tidying the coverage comment once coverage passes after failing
+// (see PRCommentConfig.ResolveMode)
+const (
+	// ResolveModeMark adds a resolved banner to the comment
+	ResolveModeMark = "mark"
+	// ResolveModeMinimize collapses the comment as resolved with the minimizeComment mutation
+	ResolveModeMinimize = "minimize"
+)
+
+// Coverage states recorded in the comment metadata
+const (
+	commentStatusPassed = "passed"
+	commentStatusFailed = "failed"
+)
+
+// resolvedMarker identifies comments carrying the resolved banner
+const resolvedMarker = "[//]: # (go-coverage-resolved)"
+
+// resolvedBanner is added to the comment in ResolveModeMark
+const resolvedBanner = resolvedMarker + "\n> ✅ **Resolved**: coverage passes again after failing earlier in this pull request.\n\n"
+
+// commentMetadataLine matches the metadata written by the comment templates
+var commentMetadataLine = regexp.MustCompile(`(?m)^\[//\]: # \(metadata: (\{.*\})\)$`)
+
+// commentTidyPlan describes how a comment update changes the state of the PR thread
+type commentTidyPlan struct {
+	resolved  bool // coverage passes and the previous comment reported a failure (or was resolved)
+	recovered bool // the previous comment reported a failure, so this update is the transition
+	regressed bool // coverage fails and the previous comment reported a pass
+}
+
+// commentStatus returns the coverage state recorded in a comment's metadata, or "" if unknown
+func commentStatus(body string) string {
+	matches := commentMetadataLine.FindStringSubmatch(body)
+	if len(matches) < 2 {
+		return ""
+	}
+
+	var metadata struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(matches[1]), &metadata); err != nil {
+		return ""
+	}
+	return metadata.Status
+}
+
+// planTidy compares the state of the new comment body with the comment it replaces
+func (m *PRCommentManager) planTidy(existingComments []Comment, body string) commentTidyPlan {
+	if len(existingComments) == 0 {
+		return commentTidyPlan{}
+	}
+
+	previousBody := existingComments[0].Body
+	previous, current := commentStatus(previousBody), commentStatus(body)
+	recovered := previous == commentStatusFailed && current == commentStatusPassed
+	return commentTidyPlan{
+		resolved:  recovered || (current == commentStatusPassed && strings.Contains(previousBody, resolvedMarker)),
+		recovered: recovered,
+		regressed: previous == commentStatusPassed && current == commentStatusFailed,
+	}
+}
+
+// withResolvedBanner adds the resolved banner below the leading signature and metadata lines
+func withResolvedBanner(body string) string {
+	return withBanner(body, resolvedBanner)
+}
+
+// withBanner adds a banner below the leading signature and metadata lines
+func withBanner(body, banner string) string {
+	lines := strings.SplitAfter(body, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], "[//]: #") {
+		i++
+	}
+	head := strings.Join(lines[:i], "")
+	if head != "" && !strings.HasSuffix(head, "\n\n") {
+		head += "\n"
+	}
+	return head + banner + strings.TrimLeft(strings.Join(lines[i:], ""), "\n")
+}
+
+// tidyComment minimizes, restores or reacts to the posted comment. Failures are logged and
+// reported through the response flags only, as the comment itself was posted.
+func (m *PRCommentManager) tidyComment(ctx context.Context, owner, repo string, comment *Comment,
+	plan commentTidyPlan, comparison *CoverageComparison, response *PRCommentResponse,
+) {
+	response.Resolved = plan.resolved
+
+	if m.config.ResolveMode == ResolveModeMinimize && comment.NodeID != "" {
+		switch {
+		case plan.recovered:
+			if err := m.client.MinimizeComment(ctx, comment.NodeID, MinimizeResolved); err != nil {
+				m.logger.WithError(err).Warn("Failed to minimize resolved coverage comment")
+			} else {
+				response.Minimized = true
+			}
+		case plan.regressed:
+			if err := m.client.UnminimizeComment(ctx, comment.NodeID); err != nil {
+				m.logger.WithError(err).Warn("Failed to unminimize coverage comment")
+			}
+		}
+	}
+
+	threshold := m.config.CelebrateThreshold
+	if threshold > 0 && comparison.BaseCoverage.Percentage > 0 && comparison.Difference >= threshold {
+		if err := m.client.AddCommentReaction(ctx, owner, repo, comment.ID, ReactionHooray); err != nil {
+			m.logger.WithError(err).Warn("Failed to react to coverage comment")
+		} else {
+			response.Reacted = true
+		}
+	}
+}
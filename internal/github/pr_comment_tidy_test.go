@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tidyCommentBody renders a minimal coverage comment with the given metadata status
+func tidyCommentBody(status string) string {
+	return "[//]: # (go-coverage-v1)\n" +
+		`[//]: # (metadata: {"version":"2.0","template":"comprehensive","status":"` + status + `"})` +
+		"\n\n# Code Coverage Analysis\n"
+}
+
+func TestCommentStatus(t *testing.T) {
+	assert.Equal(t, commentStatusPassed, commentStatus(tidyCommentBody("passed")))
+	assert.Equal(t, commentStatusFailed, commentStatus(tidyCommentBody("failed")))
+	assert.Empty(t, commentStatus("[//]: # (go-coverage-v1)\n# Code Coverage Analysis\n"))
+	assert.Empty(t, commentStatus("[//]: # (metadata: {not json})\n"))
+}
+
+func TestWithResolvedBanner(t *testing.T) {
+	body := withResolvedBanner(tidyCommentBody("passed"))
+	assert.True(t, strings.HasPrefix(body, "[//]: # (go-coverage-v1)\n"))
+	assert.Contains(t, body, "\"status\":\"passed\"})\n\n"+resolvedMarker+"\n> ✅ **Resolved**")
+	assert.Contains(t, body, "passes again after failing earlier in this pull request.\n\n# Code Coverage Analysis")
+	assert.Equal(t, commentStatusPassed, commentStatus(body))
+}
+
+func TestPlanTidy(t *testing.T) {
+	manager := NewPRCommentManager(nil, nil)
+	existing := func(body string) []Comment { return []Comment{{ID: 1, Body: body}} }
+
+	tests := []struct {
+		name     string
+		existing []Comment
+		body     string
+		expected commentTidyPlan
+	}{
+		{"first comment", nil, tidyCommentBody("passed"), commentTidyPlan{}},
+		{"still passing", existing(tidyCommentBody("passed")), tidyCommentBody("passed"), commentTidyPlan{}},
+		{"recovered", existing(tidyCommentBody("failed")), tidyCommentBody("passed"), commentTidyPlan{resolved: true, recovered: true}},
+		{"stays resolved", existing(withResolvedBanner(tidyCommentBody("passed"))), tidyCommentBody("passed"), commentTidyPlan{resolved: true}},
+		{"regressed", existing(withResolvedBanner(tidyCommentBody("passed"))), tidyCommentBody("failed"), commentTidyPlan{regressed: true}},
+		{"unknown status", existing("old comment"), tidyCommentBody("passed"), commentTidyPlan{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, manager.planTidy(tt.existing, tt.body))
+		})
+	}
+}
+
+// tidyServer fakes the endpoints used when updating a coverage comment
+type tidyServer struct {
+	mu            sync.Mutex
+	previousBody  string
+	postedBody    string
+	graphQLQuery  string
+	reactionCount int
+}
+
+func (s *tidyServer) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/repos/owner/repo/pulls/1":
+			_, _ = w.Write([]byte(`{"number":1,"state":"open","head":{"sha":"abc123"}}`))
+		case r.URL.Path == "/repos/owner/repo/issues/1/comments":
+			assert.NoError(t, json.NewEncoder(w).Encode([]Comment{{
+				ID: 9, NodeID: "IC_9", Body: s.previousBody, UpdatedAt: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			}}))
+		case r.URL.Path == "/repos/owner/repo/issues/comments/9" && r.Method == http.MethodPatch:
+			var request CommentRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			s.postedBody = request.Body
+			assert.NoError(t, json.NewEncoder(w).Encode(Comment{ID: 9, NodeID: "IC_9", Body: request.Body}))
+		case r.URL.Path == "/repos/owner/repo/issues/comments/9/reactions":
+			s.reactionCount++
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/graphql":
+			data, _ := io.ReadAll(r.Body)
+			s.graphQLQuery = string(data)
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestCreateOrUpdatePRCommentTidiesThread(t *testing.T) {
+	comparison := &CoverageComparison{
+		BaseCoverage: CoverageData{Percentage: 70},
+		PRCoverage:   CoverageData{Percentage: 80},
+		Difference:   10,
+	}
+
+	tests := []struct {
+		name          string
+		config        PRCommentConfig
+		previous      string
+		body          string
+		wantBanner    bool
+		wantMinimized bool
+		wantMutation  string
+		wantReactions int
+	}{
+		{
+			name:          "minimize on recovery and celebrate",
+			config:        PRCommentConfig{ResolveMode: ResolveModeMinimize, CelebrateThreshold: 5},
+			previous:      tidyCommentBody("failed"),
+			body:          tidyCommentBody("passed"),
+			wantMinimized: true,
+			wantMutation:  "minimizeComment",
+			wantReactions: 1,
+		},
+		{
+			name:       "mark on recovery",
+			config:     PRCommentConfig{ResolveMode: ResolveModeMark, CelebrateThreshold: 20},
+			previous:   tidyCommentBody("failed"),
+			body:       tidyCommentBody("passed"),
+			wantBanner: true,
+		},
+		{
+			name:         "unminimize on regression",
+			config:       PRCommentConfig{ResolveMode: ResolveModeMinimize},
+			previous:     tidyCommentBody("passed"),
+			body:         tidyCommentBody("failed"),
+			wantMutation: "unminimizeComment",
+		},
+		{
+			name:     "disabled",
+			previous: tidyCommentBody("failed"),
+			body:     tidyCommentBody("passed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &tidyServer{previousBody: tt.previous}
+			server := httptest.NewServer(fake.handler(t))
+			defer server.Close()
+
+			client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+			config := tt.config
+			config.MaxCommentsPerPR = 1
+			config.CommentSignature = "go-coverage-v1"
+			manager := NewPRCommentManager(client, &config)
+
+			result, err := manager.CreateOrUpdatePRComment(context.Background(), "owner", "repo", 1, tt.body, comparison)
+			require.NoError(t, err)
+			assert.Equal(t, "updated", result.Action)
+			assert.Equal(t, tt.wantBanner, strings.Contains(fake.postedBody, resolvedMarker))
+			assert.Equal(t, tt.wantMinimized, result.Minimized)
+			assert.Equal(t, tt.wantReactions > 0, result.Reacted)
+			assert.Equal(t, tt.wantReactions, fake.reactionCount)
+			if tt.wantMutation == "" {
+				assert.Empty(t, fake.graphQLQuery)
+			} else {
+				assert.Contains(t, fake.graphQLQuery, tt.wantMutation+"(input")
+			}
+		})
+	}
+}
@@ -3,12 +3,15 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -85,7 +88,7 @@ func (c *Client) GetPRDiff(ctx context.Context, owner, repo string, pr int) (*PR
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", c.config.UserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR diff: %w", err)
 	}
@@ -158,6 +161,27 @@ func AnalyzePRFiles(prDiff *PRDiff) *PRFileAnalysis {
 	return analysis
 }
 
+// moduleFiles change the dependencies, and with them potentially the coverage, of every package
+var moduleFiles = []string{"go.mod", "go.sum", "go.work", "go.work.sum"}
+
+// HasCodeChanges reports whether any file in the PR can change coverage: Go code and tests,
+// including generated code, module files, and test fixtures under testdata
+func (a *PRFileAnalysis) HasCodeChanges() bool {
+	if len(a.GoFiles) > 0 || len(a.TestFiles) > 0 {
+		return true
+	}
+	for _, files := range [][]PRFile{a.ConfigFiles, a.DocumentationFiles, a.GeneratedFiles, a.OtherFiles} {
+		for _, file := range files {
+			name := filepath.ToSlash(file.Filename)
+			if filepath.Ext(name) == ".go" || slices.Contains(moduleFiles, filepath.Base(name)) ||
+				strings.HasPrefix(name, "testdata/") || strings.Contains(name, "/testdata/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // categorizeFile determines the type/category of a file based on its path and extension
 func categorizeFile(filename string) FileType {
 	basename := filepath.Base(filename)
@@ -285,6 +309,135 @@ func isGeneratedFile(basename, fullPath string) bool {
 	return false
 }
 
+// Renames maps the new path of every file the PR renamed to its previous path
+func (d *PRDiff) Renames() map[string]string {
+	renames := make(map[string]string)
+	for i := range d.Files {
+		if file := &d.Files[i]; file.Status == "renamed" && file.PreviousFilename != "" {
+			renames[file.Filename] = file.PreviousFilename
+		}
+	}
+	return renames
+}
+
+// AddedLines returns the line numbers in the new version of the file that the patch adds
+func (f *PRFile) AddedLines() []int {
+	var lines []int
+	newLine := 0
+	for _, line := range strings.Split(f.Patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			newLine = hunkStart(line)
+		case newLine == 0:
+			// Outside of a hunk
+		case strings.HasPrefix(line, "+"):
+			lines = append(lines, newLine)
+			newLine++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, "\\"):
+			// Removed lines and "\ No newline at end of file" markers do not exist in the new file
+		default:
+			newLine++
+		}
+	}
+	return lines
+}
+
+// minBlockLines is the number of non-blank lines a changed block needs to be hashed; shorter
+// blocks such as a lone closing brace match too easily to indicate moved code
+const minBlockLines = 3
+
+// DiffBlock is a run of consecutive lines a patch removes or adds
+type DiffBlock struct {
+	StartLine int // First line, in the old file for removed blocks and in the new file for added blocks
+	EndLine   int
+	// Hash identifies the content with indentation and blank lines ignored, so a block moved
+	// to another file or nesting level hashes the same
+	Hash string
+}
+
+// ChangedBlocks returns the runs of removed and added lines of the patch that have at least
+// minBlockLines non-blank lines
+func (f *PRFile) ChangedBlocks() (removed, added []DiffBlock) {
+	var current *DiffBlock
+	var content []string
+	currentRemoved := false
+	flush := func() {
+		if current != nil && len(content) >= minBlockLines {
+			sum := sha256.Sum256([]byte(strings.Join(content, "\n")))
+			current.Hash = hex.EncodeToString(sum[:])
+			if currentRemoved {
+				removed = append(removed, *current)
+			} else {
+				added = append(added, *current)
+			}
+		}
+		current, content = nil, nil
+	}
+	extend := func(line string, number int, isRemoved bool) {
+		if current == nil || currentRemoved != isRemoved {
+			flush()
+			current, currentRemoved = &DiffBlock{StartLine: number}, isRemoved
+		}
+		current.EndLine = number
+		if trimmed := strings.TrimSpace(line[1:]); trimmed != "" {
+			content = append(content, trimmed)
+		}
+	}
+
+	oldLine, newLine := 0, 0
+	for _, line := range strings.Split(f.Patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			oldLine, newLine = hunkOldStart(line), hunkStart(line)
+		case newLine == 0 && oldLine == 0:
+			// Outside of a hunk
+		case strings.HasPrefix(line, "-"):
+			extend(line, oldLine, true)
+			oldLine++
+		case strings.HasPrefix(line, "+"):
+			extend(line, newLine, false)
+			newLine++
+		case strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file" belongs to the previous line
+		default:
+			flush()
+			oldLine++
+			newLine++
+		}
+	}
+	flush()
+	return removed, added
+}
+
+// hunkOldStart returns the first old-file line of a hunk header such as "@@ -10,4 +12,6 @@", or 0 if invalid
+func hunkOldStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[1][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+// hunkStart returns the first new-file line of a hunk header such as "@@ -10,4 +12,6 @@", or 0 if invalid
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
 // GetSummaryText generates a human-readable summary of PR files
 func (s *PRFileSummary) GetSummaryText() string {
 	if s.TotalFiles == 0 {
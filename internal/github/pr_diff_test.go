@@ -211,6 +211,32 @@ func TestAnalyzePRFiles(t *testing.T) {
 	assert.Equal(t, 0, analysis.Summary.OtherFilesCount)
 }
 
+func TestPRFileAnalysisHasCodeChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected bool
+	}{
+		{"docs and workflows only", []string{"README.md", "docs/guide.md", ".github/workflows/ci.yml"}, false},
+		{"no files", nil, false},
+		{"go source", []string{"README.md", "internal/parser/parser.go"}, true},
+		{"test only", []string{"internal/parser/parser_test.go"}, true},
+		{"generated code", []string{"api/service.pb.go"}, true},
+		{"module file", []string{"go.sum"}, true},
+		{"test fixture", []string{"internal/parser/testdata/profile.txt"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := &PRDiff{}
+			for _, name := range tt.files {
+				diff.Files = append(diff.Files, PRFile{Filename: name, Status: "modified"})
+			}
+			assert.Equal(t, tt.expected, AnalyzePRFiles(diff).HasCodeChanges())
+		})
+	}
+}
+
 func TestPRFileSummary_GetSummaryText(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -416,3 +442,58 @@ func TestPluralize(t *testing.T) {
 		})
 	}
 }
+
+func TestPRDiffRenames(t *testing.T) {
+	diff := &PRDiff{Files: []PRFile{
+		{Filename: "pkg/new.go", Status: "renamed", PreviousFilename: "internal/old.go"},
+		{Filename: "pkg/copy.go", Status: "copied", PreviousFilename: "pkg/orig.go"},
+		{Filename: "main.go", Status: "modified"},
+	}}
+	assert.Equal(t, map[string]string{"pkg/new.go": "internal/old.go"}, diff.Renames())
+	assert.Empty(t, (&PRDiff{}).Renames())
+}
+
+func TestPRFileChangedBlocks(t *testing.T) {
+	removedFile := &PRFile{Patch: "@@ -10,6 +10,2 @@\n x := 1\n-func A() {\n-\treturn 1\n-}\n+y := 2\n \treturn"}
+	addedFile := &PRFile{Patch: "@@ -3,1 +3,5 @@\n package b\n+\n+  func A() {\n+      return 1\n+  }\n\\ No newline at end of file"}
+
+	removed, added := removedFile.ChangedBlocks()
+	require.Len(t, removed, 1)
+	assert.Empty(t, added, "one line blocks are not hashed")
+	assert.Equal(t, 11, removed[0].StartLine)
+	assert.Equal(t, 13, removed[0].EndLine)
+
+	removed, added = addedFile.ChangedBlocks()
+	assert.Empty(t, removed)
+	require.Len(t, added, 1)
+	assert.Equal(t, 4, added[0].StartLine)
+	assert.Equal(t, 7, added[0].EndLine)
+
+	removedBlocks, _ := removedFile.ChangedBlocks()
+	assert.Equal(t, removedBlocks[0].Hash, added[0].Hash, "indentation and blank lines are ignored")
+}
+
+func TestPRFileAddedLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected []int
+	}{
+		{"empty patch", "", nil},
+		{"new file", "@@ -0,0 +1,3 @@\n+package lib\n+\n+func A() {}", []int{1, 2, 3}},
+		{
+			name:     "multiple hunks",
+			patch:    "@@ -10,4 +10,5 @@ func A() {\n \tx := 1\n-\ty := 2\n+\ty := 3\n+\tz := 4\n \treturn\n@@ -40,2 +41,3 @@\n \tfoo()\n+\tbar()\n\\ No newline at end of file",
+			expected: []int{11, 12, 42},
+		},
+		{"removal only", "@@ -5,2 +5,1 @@\n a\n-b", nil},
+		{"malformed header", "@@ bogus @@\n+a", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &PRFile{Patch: tt.patch}
+			assert.Equal(t, tt.expected, file.AddedLines())
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrProjectNotFound is returned when the owner has no project with the given number, or the
+// token cannot see it
+var ErrProjectNotFound = errors.New("project not found")
+
+// Project is a GitHub Project (v2) with its draft issues
+type Project struct {
+	ID          string              `json:"id"` // GraphQL node ID
+	Title       string              `json:"title"`
+	URL         string              `json:"url"`
+	DraftIssues []ProjectDraftIssue `json:"draft_issues"`
+}
+
+// ProjectDraftIssue is a draft issue item of a project, which exists only in the project
+type ProjectDraftIssue struct {
+	ItemID string `json:"item_id"` // GraphQL node ID of the project item
+	ID     string `json:"id"`      // GraphQL node ID of the draft issue
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// projectItemNode is a project item, whose content is set only for draft issues
+type projectItemNode struct {
+	ID      string `json:"id"`
+	Content *struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"content"`
+}
+
+// draftIssue returns the draft issue of the item, or nil for issues and pull requests
+func (n projectItemNode) draftIssue() *ProjectDraftIssue {
+	if n.Content == nil || n.Content.ID == "" {
+		return nil
+	}
+	return &ProjectDraftIssue{ItemID: n.ID, ID: n.Content.ID, Title: n.Content.Title, Body: n.Content.Body}
+}
+
+// GetProject returns a project of a user or organization, with all of its draft issues
+func (c *Client) GetProject(ctx context.Context, owner string, number int) (*Project, error) {
+	const query = `query($owner: String!, $number: Int!, $cursor: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id title url
+        items(first: 100, after: $cursor) {
+          pageInfo { hasNextPage endCursor }
+          nodes { id content { ... on DraftIssue { id title body } } }
+        }
+      }
+    }
+  }
+}`
+	var project *Project
+	var cursor any
+	for {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectV2 *struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+					URL   string `json:"url"`
+					Items struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []projectItemNode `json:"nodes"`
+					} `json:"items"`
+				} `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+		vars := map[string]any{"owner": owner, "number": number, "cursor": cursor}
+		if err := c.GraphQL(ctx, query, vars, &data); err != nil {
+			var graphQLErrors GraphQLErrors
+			if errors.As(err, &graphQLErrors) && graphQLErrors.OnlyType("NOT_FOUND") {
+				return nil, fmt.Errorf("%w: %s #%d", ErrProjectNotFound, owner, number)
+			}
+			return nil, fmt.Errorf("failed to look up project: %w", err)
+		}
+		if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+			return nil, fmt.Errorf("%w: %s #%d", ErrProjectNotFound, owner, number)
+		}
+
+		page := data.RepositoryOwner.ProjectV2
+		if project == nil {
+			project = &Project{ID: page.ID, Title: page.Title, URL: page.URL, DraftIssues: []ProjectDraftIssue{}}
+		}
+		for _, node := range page.Items.Nodes {
+			if draft := node.draftIssue(); draft != nil {
+				project.DraftIssues = append(project.DraftIssues, *draft)
+			}
+		}
+		if !page.Items.PageInfo.HasNextPage {
+			return project, nil
+		}
+		cursor = page.Items.PageInfo.EndCursor
+	}
+}
+
+// AddProjectDraftIssue adds a draft issue to a project and returns it
+func (c *Client) AddProjectDraftIssue(ctx context.Context, projectID, title, body string) (*ProjectDraftIssue, error) {
+	const mutation = `mutation($projectId: ID!, $title: String!, $body: String!) {
+  addProjectV2DraftIssue(input: {projectId: $projectId, title: $title, body: $body}) {
+    projectItem { id content { ... on DraftIssue { id title body } } }
+  }
+}`
+	var data struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem projectItemNode `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+	vars := map[string]any{"projectId": projectID, "title": title, "body": body}
+	if err := c.GraphQL(ctx, mutation, vars, &data); err != nil {
+		return nil, fmt.Errorf("failed to add project draft issue: %w", err)
+	}
+	draft := data.AddProjectV2DraftIssue.ProjectItem.draftIssue()
+	if draft == nil {
+		return &ProjectDraftIssue{ItemID: data.AddProjectV2DraftIssue.ProjectItem.ID, Title: title, Body: body}, nil
+	}
+	return draft, nil
+}
+
+// UpdateProjectDraftIssue replaces the title and body of a draft issue
+func (c *Client) UpdateProjectDraftIssue(ctx context.Context, draftIssueID, title, body string) error {
+	const mutation = `mutation($id: ID!, $title: String!, $body: String!) {
+  updateProjectV2DraftIssue(input: {draftIssueId: $id, title: $title, body: $body}) { draftIssue { id } }
+}`
+	if err := c.GraphQL(ctx, mutation, map[string]any{"id": draftIssueID, "title": title, "body": body}, nil); err != nil {
+		return fmt.Errorf("failed to update project draft issue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProject(t *testing.T) {
+	pages := []string{
+		`{"data":{"repositoryOwner":{"projectV2":{"id":"PVT_1","title":"Roadmap","url":"https://github.com/orgs/o/projects/2","items":{
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+			"nodes":[{"id":"PVTI_1","content":{}},{"id":"PVTI_2","content":{"id":"DI_2","title":"Coverage goal: 12% complete","body":"old"}}]}}}}}`,
+		`{"data":{"repositoryOwner":{"projectV2":{"id":"PVT_1","title":"Roadmap","url":"https://github.com/orgs/o/projects/2","items":{
+			"pageInfo":{"hasNextPage":false},
+			"nodes":[{"id":"PVTI_3","content":{"id":"DI_3","title":"Release","body":""}},{"id":"PVTI_4","content":null}]}}}}}`,
+		`{"data":{"repositoryOwner":{}}}`,
+	}
+	var requests []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(pages[len(requests)]))
+		requests = append(requests, request)
+	}))
+	t.Cleanup(server.Close)
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+
+	project, err := client.GetProject(context.Background(), "o", 2)
+	require.NoError(t, err)
+	assert.Equal(t, &Project{ID: "PVT_1", Title: "Roadmap", URL: "https://github.com/orgs/o/projects/2", DraftIssues: []ProjectDraftIssue{
+		{ItemID: "PVTI_2", ID: "DI_2", Title: "Coverage goal: 12% complete", Body: "old"},
+		{ItemID: "PVTI_3", ID: "DI_3", Title: "Release"},
+	}}, project)
+	require.Len(t, requests, 2)
+	assert.Nil(t, requests[0].Variables["cursor"])
+	assert.Equal(t, "c1", requests[1].Variables["cursor"])
+	assert.InDelta(t, 2, requests[1].Variables["number"], 0)
+
+	_, err = client.GetProject(context.Background(), "o", 9)
+	require.ErrorIs(t, err, ErrProjectNotFound)
+}
+
+func TestProjectDraftIssueMutations(t *testing.T) {
+	client, requests := newThreadServer(t, map[string]string{
+		"addProjectV2DraftIssue": `{"data":{"addProjectV2DraftIssue":{"projectItem":{"id":"PVTI_9","content":{"id":"DI_9","title":"Coverage goal","body":"body"}}}}}`,
+	})
+
+	draft, err := client.AddProjectDraftIssue(context.Background(), "PVT_1", "Coverage goal", "body")
+	require.NoError(t, err)
+	assert.Equal(t, &ProjectDraftIssue{ItemID: "PVTI_9", ID: "DI_9", Title: "Coverage goal", Body: "body"}, draft)
+	assert.Equal(t, "PVT_1", (*requests)[0].Variables["projectId"])
+
+	require.NoError(t, client.UpdateProjectDraftIssue(context.Background(), "DI_9", "Coverage goal: 50% complete", "new body"))
+	assert.Contains(t, (*requests)[1].Query, "updateProjectV2DraftIssue(")
+	assert.Equal(t, map[string]any{"id": "DI_9", "title": "Coverage goal: 50% complete", "body": "new body"}, (*requests)[1].Variables)
+}
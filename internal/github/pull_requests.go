@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// pullRequestBatchSize is the number of pull requests fetched per GraphQL query
+const pullRequestBatchSize = 50
+
+// pullRequestSelection selects the pull request metadata used by the REST PullRequest type
+const pullRequestSelection = `{
+      number title state headRefName headRefOid baseRefName baseRefOid
+      labels(first: 100) { nodes { name color } }
+    }`
+
+// pullRequestNode is a pull request as returned by GraphQL
+type pullRequestNode struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	State       string `json:"state"`
+	HeadRefName string `json:"headRefName"`
+	HeadRefOid  string `json:"headRefOid"`
+	BaseRefName string `json:"baseRefName"`
+	BaseRefOid  string `json:"baseRefOid"`
+	Labels      struct {
+		Nodes []Label `json:"nodes"`
+	} `json:"labels"`
+}
+
+// pullRequest converts the node to the REST representation. GraphQL reports merged pull
+// requests as MERGED, which REST reports as closed.
+func (n *pullRequestNode) pullRequest() *PullRequest {
+	pr := &PullRequest{Number: n.Number, Title: n.Title, State: strings.ToLower(n.State), Labels: n.Labels.Nodes}
+	if pr.State == "merged" {
+		pr.State = "closed"
+	}
+	pr.Head = PullRequestRef{Ref: n.HeadRefName, SHA: n.HeadRefOid}
+	pr.Base = PullRequestRef{Ref: n.BaseRefName, SHA: n.BaseRefOid}
+	return pr
+}
+
+// GetPullRequests fetches the metadata of several pull requests, including their labels, with
+// one GraphQL query per 50 pull requests. Pull requests that do not exist are left out of the
+// result. The pull requests are remembered, so later GetPullRequest calls for them on this
+// client do not cost another request.
+func (c *Client) GetPullRequests(ctx context.Context, owner, repo string, numbers []int) (map[int]*PullRequest, error) {
+	result := make(map[int]*PullRequest, len(numbers))
+	for start := 0; start < len(numbers); start += pullRequestBatchSize {
+		chunk := numbers[start:min(start+pullRequestBatchSize, len(numbers))]
+
+		var batch graphQLBatch
+		for _, number := range chunk {
+			batch.add(fmt.Sprintf("pr%d", number), fmt.Sprintf("pullRequest(number: %d)", number), pullRequestSelection)
+		}
+		query := batch.query("$owner: String!, $name: String!", "repository(owner: $owner, name: $name)")
+
+		data, err := GraphQLQuery[struct {
+			Repository map[string]*pullRequestNode `json:"repository"`
+		}](ctx, c, query, map[string]any{"owner": owner, "name": repo})
+		var graphQLErrors GraphQLErrors
+		if err != nil && (!errors.As(err, &graphQLErrors) || !graphQLErrors.OnlyType("NOT_FOUND")) {
+			return nil, fmt.Errorf("failed to get pull requests: %w", err)
+		}
+
+		for _, node := range data.Repository {
+			if node != nil {
+				result[node.Number] = node.pullRequest()
+			}
+		}
+	}
+
+	c.prMu.Lock()
+	if c.pullRequests == nil {
+		c.pullRequests = make(map[string]*PullRequest, len(result))
+	}
+	for number, pr := range result {
+		c.pullRequests[pullRequestKey(owner, repo, number)] = pr
+	}
+	c.prMu.Unlock()
+
+	return result, nil
+}
+
+// cachedPullRequest returns a copy of a pull request fetched earlier by GetPullRequests, if any
+func (c *Client) cachedPullRequest(owner, repo string, number int) *PullRequest {
+	c.prMu.Lock()
+	defer c.prMu.Unlock()
+	cached, ok := c.pullRequests[pullRequestKey(owner, repo, number)]
+	if !ok {
+		return nil
+	}
+	pr := *cached
+	pr.Labels = append([]Label(nil), cached.Labels...)
+	return &pr
+}
+
+// pullRequestKey identifies a pull request in the cache
+func pullRequestKey(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, number)
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pullRequestFields matches the aliased pull request lookups of a batched query
+var pullRequestFields = regexp.MustCompile(`pr(\d+): pullRequest\(number: \d+\)`)
+
+func TestGetPullRequests(t *testing.T) {
+	var graphQLRequests, restRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			restRequests++
+			_, _ = w.Write([]byte(`{"number":7,"state":"open","head":{"sha":"rest"}}`))
+			return
+		}
+		graphQLRequests++
+
+		var request graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "owner", request.Variables["owner"])
+		assert.Equal(t, "repo", request.Variables["name"])
+
+		// Every pull request exists except 13, which GraphQL reports as NOT_FOUND
+		nodes := make([]string, 0)
+		var errs string
+		for _, match := range pullRequestFields.FindAllStringSubmatch(request.Query, -1) {
+			number := match[1]
+			if number == "13" {
+				nodes = append(nodes, `"pr13":null`)
+				errs = `,"errors":[{"type":"NOT_FOUND","path":["repository","pr13"],"message":"Could not resolve to a PullRequest with the number of 13."}]`
+				continue
+			}
+			state := "OPEN"
+			if number == "2" {
+				state = "MERGED"
+			}
+			nodes = append(nodes, fmt.Sprintf(`"pr%s":{"number":%s,"title":"PR %s","state":%q,"headRefOid":"sha%s","labels":{"nodes":[{"name":"coverage-override","color":"ededed"}]}}`,
+				number, number, number, state, number))
+		}
+		_, _ = fmt.Fprintf(w, `{"data":{"repository":{%s}}%s}`, strings.Join(nodes, ","), errs)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second, UserAgent: testAgent})
+	numbers := make([]int, 0, 60)
+	for number := 1; number <= 60; number++ {
+		numbers = append(numbers, number)
+	}
+
+	prs, err := client.GetPullRequests(context.Background(), "owner", "repo", numbers)
+	require.NoError(t, err)
+	assert.Equal(t, 2, graphQLRequests)
+	assert.Len(t, prs, 59)
+	assert.NotContains(t, prs, 13)
+	assert.Equal(t, "open", prs[1].State)
+	assert.Equal(t, "closed", prs[2].State)
+	assert.Equal(t, "sha42", prs[42].Head.SHA)
+	assert.Equal(t, []Label{{Name: "coverage-override", Color: "ededed"}}, prs[42].Labels)
+
+	pr, err := client.GetPullRequest(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "PR 42", pr.Title)
+	assert.Zero(t, restRequests)
+
+	pr, err = client.GetPullRequest(context.Background(), "other", "repo", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "rest", pr.Head.SHA)
+	assert.Equal(t, 1, restRequests)
+}
+
+func TestGetPullRequestsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"FORBIDDEN","message":"Resource not accessible by integration"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: testToken, BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := client.GetPullRequests(context.Background(), "owner", "repo", []int{1})
+	require.ErrorIs(t, err, ErrGraphQL)
+	assert.Nil(t, client.cachedPullRequest("owner", "repo", 1))
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the API quota reported by GitHub in the X-RateLimit-* response headers
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// Known reports whether any response has carried rate limit headers yet
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// RateLimit returns the quota reported by the most recent API response
+func (c *Client) RateLimit() RateLimit {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateLimit
+}
+
+// recordRateLimit stores the quota from response headers, ignoring responses without them.
+// GraphQL requests draw from a separate quota, which is not recorded.
+func (c *Client) recordRateLimit(header http.Header) {
+	if header.Get("X-RateLimit-Resource") == "graphql" {
+		return
+	}
+	rate, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	c.rateMu.Lock()
+	c.rateLimit = rate
+	c.rateMu.Unlock()
+}
+
+// parseRateLimit reads the quota from the X-RateLimit-* response headers
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	var reset time.Time
+	if seconds, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(seconds, 0)
+	}
+	return RateLimit{Limit: limit, Remaining: remaining, Reset: reset}, true
+}
+
+// WaitForRateLimit blocks until at least reserve requests remain in the quota. It returns false
+// without waiting when the quota resets later than maxWait from now, and the context error when
+// the context ends first. With an unknown quota it returns immediately.
+func (c *Client) WaitForRateLimit(ctx context.Context, reserve int, maxWait time.Duration) (bool, error) {
+	rate := c.RateLimit()
+	if !rate.Known() || rate.Remaining >= reserve {
+		return true, nil
+	}
+
+	wait := time.Until(rate.Reset)
+	if wait > maxWait {
+		return false, nil
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// The quota has been replenished, forget the stale numbers until the next response
+	c.rateMu.Lock()
+	c.rateLimit = RateLimit{}
+	c.rateMu.Unlock()
+	return true, nil
+}
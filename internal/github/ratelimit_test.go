@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRecordsRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		_, _ = w.Write([]byte(`{"number":1}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	assert.False(t, client.RateLimit().Known())
+
+	_, err := client.GetPullRequest(context.Background(), "owner", "repo", 1)
+	require.NoError(t, err)
+
+	rate := client.RateLimit()
+	assert.True(t, rate.Known())
+	assert.Equal(t, 5000, rate.Limit)
+	assert.Equal(t, 4321, rate.Remaining)
+	assert.Equal(t, reset, rate.Reset.Unix())
+}
+
+func TestWaitForRateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	client := NewWithConfig(&Config{})
+	ok, err := client.WaitForRateLimit(ctx, 100, 0)
+	require.NoError(t, err)
+	assert.True(t, ok, "unknown quota never waits")
+
+	client.rateLimit = RateLimit{Limit: 5000, Remaining: 10, Reset: time.Now().Add(time.Hour)}
+	ok, err = client.WaitForRateLimit(ctx, 100, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "reset beyond the maximum wait")
+
+	client.rateLimit = RateLimit{Limit: 5000, Remaining: 10, Reset: time.Now().Add(20 * time.Millisecond)}
+	ok, err = client.WaitForRateLimit(ctx, 100, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, client.RateLimit().Known())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	client.rateLimit = RateLimit{Limit: 5000, Remaining: 10, Reset: time.Now().Add(time.Second)}
+	_, err = client.WaitForRateLimit(canceled, 100, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+}
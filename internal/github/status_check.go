@@ -5,13 +5,16 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-coverage/internal/retry"
 )
 
 // StatusCheckManager handles GitHub status check creation and management for PR merge blocking
 type StatusCheckManager struct {
-	client *Client
+	client StatusCheckAPI
 	config *StatusCheckConfig
 }
 
@@ -27,6 +30,10 @@ const StatusStatePending = "pending"
 // StatusStateError represents an error status state
 const StatusStateError = "error"
 
+// ObsoleteStatusDescription describes a coverage context an earlier run created that the
+// current configuration no longer reports
+const ObsoleteStatusDescription = "Obsolete: no longer reported by go-coverage"
+
 // Risk level constants for quality assessment
 const (
 	riskLevelLow    = "low"
@@ -62,6 +69,10 @@ type StatusCheckConfig struct {
 	CustomDescriptions map[string]string // Custom status descriptions
 	IncludeTargetURLs  bool              // Include target URLs in statuses
 
+	// Stale status cleanup
+	CleanupStale     bool     // Mark contexts under the prefix that this run no longer reports as obsolete
+	RetainedContexts []string // Contexts under the prefix the caller reports itself, which cleanup leaves alone
+
 	// Advanced settings
 	UpdateStrategy UpdateStrategy // How to update existing statuses
 	RetrySettings  RetrySettings  // Retry settings for failed requests
@@ -115,6 +126,15 @@ type RetrySettings struct {
 	BackoffFactor float64       // Exponential backoff factor
 }
 
+// Policy converts the retry settings into a shared retry policy
+func (r RetrySettings) Policy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:  r.MaxRetries + 1,
+		InitialDelay: r.RetryDelay,
+		Multiplier:   r.BackoffFactor,
+	}
+}
+
 // StatusCheckRequest represents a request to create/update status checks
 type StatusCheckRequest struct {
 	// Repository information
@@ -184,6 +204,9 @@ type StatusCheckResponse struct {
 	BlockingPR     bool
 	RequiredFailed []string
 
+	// Contexts left by earlier runs that were marked obsolete
+	ObsoleteContexts []string
+
 	// URLs and references
 	StatusURL string
 	ChecksURL string
@@ -210,7 +233,7 @@ type StatusResult struct {
 }
 
 // NewStatusCheckManager creates a new status check manager
-func NewStatusCheckManager(client *Client, config *StatusCheckConfig) *StatusCheckManager {
+func NewStatusCheckManager(client StatusCheckAPI, config *StatusCheckConfig) *StatusCheckManager {
 	if config == nil {
 		config = &StatusCheckConfig{
 			ContextPrefix:          "go-coverage",
@@ -302,6 +325,10 @@ func (m *StatusCheckManager) CreateStatusChecks(ctx context.Context, request *St
 		}
 	}
 
+	if m.config.CleanupStale {
+		response.ObsoleteContexts = m.markObsoleteStatuses(ctx, request, statusChecks)
+	}
+
 	// Determine overall results
 	response.AllPassing = response.FailedChecks == 0 && response.ErrorChecks == 0
 	response.BlockingPR = m.shouldBlockPR(response, request)
@@ -623,32 +650,21 @@ func (m *StatusCheckManager) evaluateQualityGate(request *StatusCheckRequest, ga
 }
 
 // createSingleStatus creates a single status check
-func (m *StatusCheckManager) createSingleStatus(ctx context.Context, request *StatusCheckRequest, context string, statusInfo StatusInfo) StatusResult {
+func (m *StatusCheckManager) createSingleStatus(ctx context.Context, request *StatusCheckRequest, statusContext string, statusInfo StatusInfo) StatusResult {
 	statusReq := &StatusRequest{
 		State:       statusInfo.State,
 		TargetURL:   statusInfo.TargetURL,
 		Description: statusInfo.Description,
-		Context:     context,
+		Context:     statusContext,
 	}
 
 	// Apply retry logic
-	var err error
-	for attempt := 0; attempt <= m.config.RetrySettings.MaxRetries; attempt++ {
-		if attempt > 0 {
-			// Wait before retry
-			delay := time.Duration(float64(m.config.RetrySettings.RetryDelay) *
-				math.Pow(m.config.RetrySettings.BackoffFactor, float64(attempt-1)))
-			time.Sleep(delay)
-		}
-
-		err = m.client.CreateStatus(ctx, request.Owner, request.Repository, request.CommitSHA, statusReq)
-		if err == nil {
-			break
-		}
-	}
+	err := retry.Do(ctx, m.config.RetrySettings.Policy(), func(ctx context.Context) error {
+		return m.client.CreateStatus(ctx, request.Owner, request.Repository, request.CommitSHA, statusReq)
+	})
 
 	return StatusResult{
-		Context:     context,
+		Context:     statusContext,
 		State:       statusInfo.State,
 		Description: statusInfo.Description,
 		TargetURL:   statusInfo.TargetURL,
@@ -659,6 +675,43 @@ func (m *StatusCheckManager) createSingleStatus(ctx context.Context, request *St
 	}
 }
 
+// markObsoleteStatuses marks the contexts under the prefix that earlier runs created and this run
+// no longer reports, after the thresholds, gates or contexts changed, as successful with an
+// explanatory description, so they stop showing as pending or failed on the commit. Cleanup is
+// best effort: contexts that cannot be listed or updated are left as they are.
+func (m *StatusCheckManager) markObsoleteStatuses(ctx context.Context, request *StatusCheckRequest, active map[string]StatusInfo) []string {
+	if m.config.ContextPrefix == "" {
+		return nil // Without a prefix the contexts of other tools cannot be told apart
+	}
+
+	existing, err := m.client.ListStatuses(ctx, request.Owner, request.Repository, request.CommitSHA)
+	if err != nil {
+		return nil
+	}
+
+	var obsolete []string
+	for _, status := range existing {
+		_, reported := active[status.Context]
+		switch {
+		case !strings.HasPrefix(status.Context, m.config.ContextPrefix+"/"),
+			reported,
+			slices.Contains(m.config.RetainedContexts, status.Context),
+			status.State == StatusStateSuccess && status.Description == ObsoleteStatusDescription:
+			continue
+		}
+
+		result := m.createSingleStatus(ctx, request, status.Context, StatusInfo{
+			State:       StatusStateSuccess,
+			Description: ObsoleteStatusDescription,
+		})
+		if result.Success {
+			obsolete = append(obsolete, status.Context)
+		}
+	}
+	slices.Sort(obsolete)
+	return obsolete
+}
+
 // shouldBlockPR determines if the PR should be blocked based on status results
 func (m *StatusCheckManager) shouldBlockPR(response *StatusCheckResponse, request *StatusCheckRequest) bool {
 	if !m.config.EnableBlocking || request.SkipBlocking {
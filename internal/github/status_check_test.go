@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestStatusCheckManager_CreateStatusChecks tests the CreateStatusChecks function
@@ -793,3 +794,52 @@ func TestStatusCheckManager_shouldBlockPRMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusCheckManager_ObsoleteContexts(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake()
+	for _, status := range []StatusRequest{
+		{Context: "go-coverage/coverage/total", State: StatusStateFailure},
+		{Context: "go-coverage/coverage/threshold-old", State: StatusStateFailure},
+		{Context: "go-coverage/coverage/pending", State: StatusStatePending},
+		{Context: "go-coverage/coverage/critical", State: StatusStateFailure},
+		{Context: "ci/build", State: StatusStateFailure},
+	} {
+		require.NoError(t, fake.CreateStatus(ctx, "owner", "repo", "abc123", &status))
+	}
+
+	manager := NewStatusCheckManager(fake, &StatusCheckConfig{
+		ContextPrefix:     "go-coverage",
+		MainContext:       "coverage/total",
+		CoverageThreshold: 80,
+		CleanupStale:      true,
+		RetainedContexts:  []string{"go-coverage/coverage/critical"},
+	})
+	request := &StatusCheckRequest{
+		Owner: "owner", Repository: "repo", CommitSHA: "abc123",
+		Coverage: CoverageStatusData{Percentage: 85},
+	}
+
+	response, err := manager.CreateStatusChecks(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go-coverage/coverage/pending", "go-coverage/coverage/threshold-old"}, response.ObsoleteContexts)
+
+	for _, name := range response.ObsoleteContexts {
+		status, ok := fake.LatestStatus("abc123", name)
+		require.True(t, ok)
+		assert.Equal(t, StatusStateSuccess, status.State)
+		assert.Equal(t, ObsoleteStatusDescription, status.Description)
+	}
+	status, _ := fake.LatestStatus("abc123", "go-coverage/coverage/critical")
+	assert.Equal(t, StatusStateFailure, status.State)
+	status, _ = fake.LatestStatus("abc123", "ci/build")
+	assert.Equal(t, StatusStateFailure, status.State)
+	status, _ = fake.LatestStatus("abc123", "go-coverage/coverage/total")
+	assert.Equal(t, StatusStateSuccess, status.State)
+	assert.NotEqual(t, ObsoleteStatusDescription, status.Description)
+
+	// Contexts already marked are left alone on the next run
+	response, err = manager.CreateStatusChecks(ctx, request)
+	require.NoError(t, err)
+	assert.Empty(t, response.ObsoleteContexts)
+}
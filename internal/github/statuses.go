@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// statusPageSize is the number of statuses requested per page, the most the API returns
+const statusPageSize = 100
+
+// combinedStatus is the combined status of a commit, as the API returns it
+type combinedStatus struct {
+	Statuses []StatusRequest `json:"statuses"`
+}
+
+// ListStatuses returns the latest status of each context on a commit, following the pages of
+// the combined status
+func (c *Client) ListStatuses(ctx context.Context, owner, repo, sha string) ([]StatusRequest, error) {
+	var statuses []StatusRequest
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/commits/%s/status?per_page=%d&page=%d", c.baseURL, owner, repo, sha, statusPageSize, page)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "token "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("User-Agent", c.config.UserAgent)
+
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statuses: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+		}
+		var batch combinedStatus
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode statuses response: %w", err)
+		}
+
+		statuses = append(statuses, batch.Statuses...)
+		if len(batch.Statuses) < statusPageSize {
+			return statuses, nil
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListStatuses(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/commits/abc123/status", r.URL.Path)
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			statuses := make([]string, 0, statusPageSize)
+			for i := range statusPageSize {
+				statuses = append(statuses, fmt.Sprintf(`{"context":"ci/job-%d","state":"success"}`, i))
+			}
+			_, _ = w.Write([]byte(`{"state":"failure","statuses":[` + strings.Join(statuses, ",") + "]}"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":"failure","statuses":[{"context":"go-coverage/coverage/total","state":"failure","description":"Coverage: 70.00%"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	statuses, err := client.ListStatuses(context.Background(), "owner", "repo", "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, pages)
+	require.Len(t, statuses, statusPageSize+1)
+	assert.Equal(t, StatusRequest{Context: "go-coverage/coverage/total", State: StatusStateFailure, Description: "Coverage: 70.00%"}, statuses[statusPageSize])
+}
+
+func TestListStatusesAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := client.ListStatuses(context.Background(), "owner", "repo", "abc123")
+	require.ErrorIs(t, err, ErrGitHubAPIError)
+}
@@ -0,0 +1,190 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrBadCredentials is returned when GitHub rejects the token
+var ErrBadCredentials = errors.New("GitHub rejected the token")
+
+// TokenPermissions describes what the client's token may do in a repository
+type TokenPermissions struct {
+	// Private reports whether the repository is private
+	Private bool
+	// Scopes are the OAuth scopes of a classic personal access token
+	Scopes []string
+	// ScopesKnown reports whether GitHub listed OAuth scopes. GitHub App installation tokens,
+	// including the GITHUB_TOKEN of Actions, and fine-grained tokens have none.
+	ScopesKnown bool
+	// Push reports write access of the token's user, when GitHub reports it
+	Push bool
+	// PermissionsKnown reports whether GitHub reported the token user's repository permissions
+	PermissionsKnown bool
+}
+
+// HasScope reports whether the token has an OAuth scope, directly or through its parent scope
+// (repo covers repo:status and public_repo)
+func (p *TokenPermissions) HasScope(scope string) bool {
+	if slices.Contains(p.Scopes, scope) {
+		return true
+	}
+	parent, _, found := strings.Cut(scope, ":")
+	if found && slices.Contains(p.Scopes, parent) {
+		return true
+	}
+	return scope == "public_repo" && slices.Contains(p.Scopes, "repo")
+}
+
+// GetTokenPermissions reads the scopes and repository permissions of the client's token
+func (c *Client) GetTokenPermissions(ctx context.Context, owner, repo string) (*TokenPermissions, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, ErrBadCredentials
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, owner, repo)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %d %s", ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var repository struct {
+		Private     bool `json:"private"`
+		Permissions *struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repository); err != nil {
+		return nil, fmt.Errorf("failed to decode repository: %w", err)
+	}
+
+	permissions := &TokenPermissions{Private: repository.Private}
+	if values, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]; ok {
+		permissions.ScopesKnown = true
+		for _, value := range values {
+			for _, scope := range strings.Split(value, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					permissions.Scopes = append(permissions.Scopes, scope)
+				}
+			}
+		}
+	}
+	if repository.Permissions != nil {
+		permissions.PermissionsKnown = true
+		permissions.Push = repository.Permissions.Push
+	}
+
+	return permissions, nil
+}
+
+// Access is the outcome of a permission probe
+type Access string
+
+// Probe outcomes
+const (
+	AccessGranted Access = "granted"
+	AccessDenied  Access = "denied"
+	AccessUnknown Access = "unknown"
+)
+
+// probeSHA is a commit that never exists, so status probes cannot create anything
+const probeSHA = "0000000000000000000000000000000000000000"
+
+// TokenAccess is what a token may do in a repository, found by probing the API
+type TokenAccess struct {
+	// Statuses is write access to commit statuses (statuses: write)
+	Statuses Access
+	// Comments is write access to pull request comments (pull-requests: write); unknown
+	// without a pull request to probe
+	Comments Access
+	// Artifacts is read access to workflow artifacts (actions: read)
+	Artifacts Access
+}
+
+// ProbeTokenAccess finds out which operations the client's token may perform. GitHub App
+// installation tokens, such as the GITHUB_TOKEN of Actions, do not list their permissions, so
+// each permission is probed with a request that GitHub rejects before changing anything: a
+// status without a state on a commit that does not exist, and a comment without a body. A
+// permitted request fails validation (422), a forbidden one is refused (403).
+func (c *Client) ProbeTokenAccess(ctx context.Context, owner, repo string, pr int) (*TokenAccess, error) {
+	access := &TokenAccess{Comments: AccessUnknown}
+
+	var err error
+	statusesURL := fmt.Sprintf("%s/repos/%s/%s/statuses/%s", c.baseURL, owner, repo, probeSHA)
+	if access.Statuses, err = c.probe(ctx, http.MethodPost, statusesURL); err != nil {
+		return nil, err
+	}
+	if pr > 0 {
+		commentsURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, pr)
+		if access.Comments, err = c.probe(ctx, http.MethodPost, commentsURL); err != nil {
+			return nil, err
+		}
+	}
+	artifactsURL := fmt.Sprintf("%s/repos/%s/%s/actions/artifacts?per_page=1", c.baseURL, owner, repo)
+	if access.Artifacts, err = c.probe(ctx, http.MethodGet, artifactsURL); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// probe sends a request whose only possible effect is an error and classifies the response
+func (c *Client) probe(ctx context.Context, method, url string) (Access, error) {
+	var body io.Reader
+	if method != http.MethodGet {
+		body = strings.NewReader("{}")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return AccessUnknown, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return AccessUnknown, fmt.Errorf("failed to probe token permissions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return AccessUnknown, ErrBadCredentials
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return AccessUnknown, nil
+	case resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusNotFound:
+		return AccessDenied, nil
+	case resp.StatusCode == http.StatusUnprocessableEntity,
+		method == http.MethodGet && resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return AccessGranted, nil
+	default:
+		return AccessUnknown, nil
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/owner/classic":
+			w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+			_, _ = w.Write([]byte(`{"private":true,"permissions":{"push":true}}`))
+		case "/repos/owner/actions":
+			_, _ = w.Write([]byte(`{"private":false}`))
+		case "/repos/owner/rejected":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	permissions, err := client.GetTokenPermissions(ctx, "owner", "classic")
+	require.NoError(t, err)
+	assert.Equal(t, &TokenPermissions{
+		Private:          true,
+		Scopes:           []string{"repo", "read:org"},
+		ScopesKnown:      true,
+		Push:             true,
+		PermissionsKnown: true,
+	}, permissions)
+	assert.True(t, permissions.HasScope("repo:status"))
+	assert.True(t, permissions.HasScope("public_repo"))
+	assert.False(t, permissions.HasScope("workflow"))
+
+	permissions, err = client.GetTokenPermissions(ctx, "owner", "actions")
+	require.NoError(t, err)
+	assert.False(t, permissions.ScopesKnown)
+	assert.False(t, permissions.PermissionsKnown)
+
+	_, err = client.GetTokenPermissions(ctx, "owner", "rejected")
+	require.ErrorIs(t, err, ErrBadCredentials)
+	_, err = client.GetTokenPermissions(ctx, "owner", "missing")
+	require.ErrorIs(t, err, ErrRepositoryNotFound)
+}
+
+func TestProbeTokenAccess(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+		}
+		switch r.URL.Path {
+		case "/repos/owner/repo/statuses/" + probeSHA:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/repos/owner/repo/issues/7/comments":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+		case "/repos/owner/repo/actions/artifacts":
+			_, _ = w.Write([]byte(`{"total_count":0,"artifacts":[]}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithConfig(&Config{Token: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	access, err := client.ProbeTokenAccess(context.Background(), "owner", "repo", 7)
+	require.NoError(t, err)
+	assert.Equal(t, &TokenAccess{Statuses: AccessGranted, Comments: AccessDenied, Artifacts: AccessGranted}, access)
+	assert.Equal(t, []string{"{}", "{}"}, bodies, "probes never carry a state or a comment body")
+
+	access, err = client.ProbeTokenAccess(context.Background(), "owner", "repo", 0)
+	require.NoError(t, err)
+	assert.Equal(t, AccessUnknown, access.Comments, "comments cannot be probed without a pull request")
+
+	_, err = client.ProbeTokenAccess(context.Background(), "other", "repo", 0)
+	require.ErrorIs(t, err, ErrBadCredentials)
+}
@@ -0,0 +1,130 @@
+package github
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Usage counts the API requests of a run by category, with every retry attempt counted, as
+// each draws from the quota
+type Usage struct {
+	mu    sync.Mutex
+	calls map[string]int
+	rate  RateLimit
+}
+
+// UsageReport is the API usage of a run
+type UsageReport struct {
+	Total int            `json:"total"`
+	Calls map[string]int `json:"calls"` // Requests by category, e.g. comments, statuses or graphql
+	// RateLimit is the quota reported by the latest response, unknown without rate limit headers
+	RateLimit RateLimit `json:"rate_limit"`
+}
+
+// CategoryCount is the number of requests of a category
+type CategoryCount struct {
+	Category string
+	Calls    int
+}
+
+// NewUsage creates an empty usage counter
+func NewUsage() *Usage {
+	return &Usage{calls: map[string]int{}}
+}
+
+// processUsage counts the requests of every client without a usage counter of its own, so a
+// run reports its calls whichever commands and clients made them
+//
+//nolint:gochecknoglobals // process-wide API call counts of the run
+var processUsage = NewUsage()
+
+// RunUsage returns the API usage of every client of the process without a usage counter of
+// its own
+func RunUsage() UsageReport {
+	return processUsage.Report()
+}
+
+// record counts a request and keeps the quota reported with its response
+func (u *Usage) record(req *http.Request, resp *http.Response) {
+	category := requestCategory(req)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.calls[category]++
+	if resp != nil && resp.Header.Get("X-RateLimit-Resource") != "graphql" {
+		if rate, ok := parseRateLimit(resp.Header); ok {
+			u.rate = rate
+		}
+	}
+}
+
+// Report returns the requests counted so far
+func (u *Usage) Report() UsageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	report := UsageReport{Calls: make(map[string]int, len(u.calls)), RateLimit: u.rate}
+	for category, calls := range u.calls {
+		report.Calls[category] = calls
+		report.Total += calls
+	}
+	return report
+}
+
+// Since returns the requests made after the earlier report of the same usage was taken
+func (r UsageReport) Since(earlier UsageReport) UsageReport {
+	since := UsageReport{Calls: map[string]int{}, RateLimit: r.RateLimit}
+	for category, calls := range r.Calls {
+		if calls -= earlier.Calls[category]; calls > 0 {
+			since.Calls[category] = calls
+			since.Total += calls
+		}
+	}
+	return since
+}
+
+// Categories returns the request counts by category, most requests first
+func (r UsageReport) Categories() []CategoryCount {
+	counts := make([]CategoryCount, 0, len(r.Calls))
+	for category, calls := range r.Calls {
+		counts = append(counts, CategoryCount{Category: category, Calls: calls})
+	}
+	slices.SortFunc(counts, func(a, b CategoryCount) int {
+		return cmp.Or(cmp.Compare(b.Calls, a.Calls), strings.Compare(a.Category, b.Category))
+	})
+	return counts
+}
+
+// requestCategory groups a request by the resource it addresses: the segment after the
+// repository in repository paths, with issue comments counted as comments and combined
+// statuses as statuses, or the first segment of other paths
+func requestCategory(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if segments[len(segments)-1] == "graphql" {
+		return "graphql"
+	}
+
+	repos := slices.Index(segments, "repos")
+	if repos < 0 {
+		return cmp.Or(segments[0], "other")
+	}
+	if len(segments) <= repos+3 {
+		return "repos"
+	}
+
+	rest := segments[repos+3:]
+	switch rest[0] {
+	case "issues":
+		if slices.Contains(rest, "comments") {
+			return "comments"
+		}
+	case "commits":
+		if slices.Contains(rest, "status") || slices.Contains(rest, "statuses") {
+			return "statuses"
+		}
+	}
+	return rest[0]
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCategory(t *testing.T) {
+	tests := map[string]string{
+		"/repos/owner/repo/issues/7/comments":         "comments",
+		"/repos/owner/repo/issues/comments/12":        "comments",
+		"/repos/owner/repo/issues/7":                  "issues",
+		"/repos/owner/repo/statuses/abc123":           "statuses",
+		"/repos/owner/repo/commits/abc123/status":     "statuses",
+		"/repos/owner/repo/commits/abc123":            "commits",
+		"/repos/owner/repo/pulls/7/files":             "pulls",
+		"/repos/owner/repo/actions/runs/5/artifacts":  "actions",
+		"/api/v3/repos/owner/repo/deployments":        "deployments",
+		"/repos/owner/repo":                           "repos",
+		"/graphql":                                    "graphql",
+		"/api/graphql":                                "graphql",
+		"/rate_limit":                                 "rate_limit",
+		"/":                                           "other",
+		"/app/installations/1/access_tokens":          "app",
+		"/repos/owner/repo/environments/github-pages": "environments",
+	}
+	for path, category := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		assert.Equal(t, category, requestCategory(req), path)
+	}
+}
+
+func TestClientUsage(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		if r.URL.Path == "/repos/owner/repo/statuses/abc123" {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	usage := NewUsage()
+	policy := defaultCommentFetchPolicy()
+	policy.InitialDelay = time.Millisecond
+	client := NewWithConfig(&Config{Token: "token", BaseURL: server.URL, Timeout: 5 * time.Second, RetryPolicy: &policy, Usage: usage})
+	ctx := context.Background()
+
+	_, err := client.ListComments(ctx, "owner", "repo", 7)
+	require.NoError(t, err)
+	require.NoError(t, client.CreateStatus(ctx, "owner", "repo", "abc123", &StatusRequest{State: StatusStateSuccess}))
+
+	report := usage.Report()
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, []CategoryCount{{Category: "statuses", Calls: 2}, {Category: "comments", Calls: 1}}, report.Categories())
+	assert.Equal(t, 4990, report.RateLimit.Remaining)
+
+	_, err = client.ListComments(ctx, "owner", "repo", 7)
+	require.NoError(t, err)
+	since := usage.Report().Since(report)
+	assert.Equal(t, 1, since.Total)
+	assert.Equal(t, map[string]int{"comments": 1}, since.Calls)
+	assert.Equal(t, 4990, since.RateLimit.Remaining)
+}
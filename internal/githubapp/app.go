@@ -0,0 +1,175 @@
+// Package githubapp authenticates as a GitHub App and receives its webhook deliveries, so a
+// single server can enforce coverage across every repository the App is installed on
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// Static error definitions for App authentication
+var (
+	ErrInvalidPrivateKey = errors.New("invalid GitHub App private key")
+	ErrInvalidAppID      = errors.New("GitHub App ID must be positive")
+)
+
+const (
+	// jwtLifetime stays below the 10 minutes GitHub accepts
+	jwtLifetime = 9 * time.Minute
+	// clockSkew backdates the JWT for servers whose clock runs ahead of GitHub's
+	clockSkew = time.Minute
+	// tokenRefreshMargin renews installation tokens before they expire mid-request
+	tokenRefreshMargin = 5 * time.Minute
+)
+
+// App authenticates as a GitHub App and mints installation tokens. Tokens are cached until
+// shortly before they expire, so one App serves many deliveries with few token requests.
+type App struct {
+	id         int64
+	key        *rsa.PrivateKey
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	now        func() time.Time
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+// installationToken is a cached installation access token
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// New creates an App from its ID and PEM encoded private key (PKCS #1 or PKCS #8)
+func New(id int64, privateKeyPEM []byte, baseURL string, httpClient *http.Client) (*App, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidAppID, id)
+	}
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &App{
+		id:         id,
+		key:        key,
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		userAgent:  "go-coverage-app/1.0",
+		now:        time.Now,
+		tokens:     map[int64]installationToken{},
+	}, nil
+}
+
+// parsePrivateKey decodes the RSA key GitHub generates for an App
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidPrivateKey)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an RSA key", ErrInvalidPrivateKey)
+	}
+	return key, nil
+}
+
+// JWT returns a token that authenticates as the App itself
+func (a *App) JWT() (string, error) {
+	now := a.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-clockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": strconv.FormatInt(a.id, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// InstallationToken returns an access token for the repositories of an installation
+func (a *App) InstallationToken(ctx context.Context, installationID int64) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+	if ok && a.now().Add(tokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	jwt, err := a.JWT()
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", a.baseURL, installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", a.userAgent)
+
+	resp, err := a.httpClient.Do(req) //nolint:gosec // G704: the URL is the configured GitHub API
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("%w: %d %s", github.ErrGitHubAPIError, resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode installation token response: %w", err)
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = installationToken{token: response.Token, expiresAt: response.ExpiresAt}
+	a.mu.Unlock()
+	return response.Token, nil
+}
@@ -0,0 +1,108 @@
+package githubapp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// testKey returns a private key and its PKCS #1 PEM encoding
+func testKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestNew(t *testing.T) {
+	key, keyPEM := testKey(t)
+
+	_, err := New(0, keyPEM, "", nil)
+	require.ErrorIs(t, err, ErrInvalidAppID)
+	_, err = New(1, []byte("not a key"), "", nil)
+	require.ErrorIs(t, err, ErrInvalidPrivateKey)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	_, err = New(1, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), "", nil)
+	require.NoError(t, err)
+}
+
+func TestJWT(t *testing.T) {
+	key, keyPEM := testKey(t)
+	app, err := New(42, keyPEM, "", nil)
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	app.now = func() time.Time { return now }
+
+	jwt, err := app.JWT()
+	require.NoError(t, err)
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(t, "42", claims["iss"])
+	assert.InDelta(t, now.Add(-time.Minute).Unix(), claims["iat"], 0)
+	assert.InDelta(t, now.Add(9*time.Minute).Unix(), claims["exp"], 0)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestInstallationToken(t *testing.T) {
+	_, keyPEM := testKey(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		switch r.URL.Path {
+		case "/app/installations/7/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"token":"ghs_installation","expires_at":"` +
+				time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	app, err := New(42, keyPEM, server.URL, server.Client())
+	require.NoError(t, err)
+
+	token, err := app.InstallationToken(t.Context(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_installation", token)
+
+	_, err = app.InstallationToken(t.Context(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "the token is cached until shortly before it expires")
+
+	app.now = func() time.Time { return time.Now().Add(56 * time.Minute) }
+	_, err = app.InstallationToken(t.Context(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "a token about to expire is renewed")
+
+	_, err = app.InstallationToken(t.Context(), 8)
+	require.ErrorIs(t, err, github.ErrGitHubAPIError)
+}
@@ -0,0 +1,208 @@
+package githubapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Static error definitions for webhook deliveries
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrUnsupportedEvent = errors.New("unsupported webhook event")
+	ErrInvalidEvent     = errors.New("invalid webhook event")
+)
+
+// Webhook events the App acts on
+const (
+	EventCheckSuite  = "check_suite"
+	EventPullRequest = "pull_request"
+	EventPing        = "ping"
+)
+
+// maxPayloadSize is the largest payload GitHub delivers
+const maxPayloadSize = 25 << 20
+
+// Event is a webhook delivery reduced to what coverage enforcement needs
+type Event struct {
+	Name           string
+	Delivery       string
+	Action         string
+	InstallationID int64
+	Owner          string
+	Repository     string
+	HeadSHA        string
+	HeadBranch     string
+	BaseBranch     string
+	// CheckSuiteID is set for check_suite events
+	CheckSuiteID int64
+	// PullRequests are the pull requests of the head commit; check suites list only pull
+	// requests from branches of the repository itself
+	PullRequests []int
+}
+
+// Relevant reports whether the event calls for work: a finished check suite may have uploaded
+// coverage, and a new pull request head waits for it
+func (e *Event) Relevant() bool {
+	switch e.Name {
+	case EventCheckSuite:
+		return e.Action == "completed"
+	case EventPullRequest:
+		return e.Action == "opened" || e.Action == "reopened" || e.Action == "synchronize"
+	}
+	return false
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header of a delivery against the webhook secret
+func VerifySignature(secret string, body []byte, signature string) error {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return fmt.Errorf("%w: missing sha256 signature", ErrInvalidSignature)
+	}
+	received, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// pullRequestRef is the part of a pull request the events carry
+type pullRequestRef struct {
+	Number int `json:"number"`
+	Head   struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// payload holds the fields of check_suite and pull_request payloads
+type payload struct {
+	Action       string `json:"action"`
+	Installation *struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+	Repository *struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	CheckSuite *struct {
+		ID           int64            `json:"id"`
+		HeadSHA      string           `json:"head_sha"`
+		HeadBranch   string           `json:"head_branch"`
+		PullRequests []pullRequestRef `json:"pull_requests"`
+	} `json:"check_suite"`
+	PullRequest *pullRequestRef `json:"pull_request"`
+}
+
+// ParseEvent decodes a check_suite or pull_request delivery
+func ParseEvent(name string, body []byte) (*Event, error) {
+	if name != EventCheckSuite && name != EventPullRequest {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEvent, name)
+	}
+
+	var data payload
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+	if data.Installation == nil || data.Repository == nil {
+		return nil, fmt.Errorf("%w: %s without installation or repository", ErrInvalidEvent, name)
+	}
+
+	event := &Event{
+		Name:           name,
+		Action:         data.Action,
+		InstallationID: data.Installation.ID,
+		Owner:          data.Repository.Owner.Login,
+		Repository:     data.Repository.Name,
+	}
+	switch {
+	case name == EventCheckSuite && data.CheckSuite != nil:
+		event.CheckSuiteID = data.CheckSuite.ID
+		event.HeadSHA = data.CheckSuite.HeadSHA
+		event.HeadBranch = data.CheckSuite.HeadBranch
+		for _, pr := range data.CheckSuite.PullRequests {
+			event.PullRequests = append(event.PullRequests, pr.Number)
+			event.BaseBranch = pr.Base.Ref
+		}
+	case name == EventPullRequest && data.PullRequest != nil:
+		event.HeadSHA = data.PullRequest.Head.SHA
+		event.HeadBranch = data.PullRequest.Head.Ref
+		event.BaseBranch = data.PullRequest.Base.Ref
+		event.PullRequests = []int{data.PullRequest.Number}
+	default:
+		return nil, fmt.Errorf("%w: %s without %s", ErrInvalidEvent, name, name)
+	}
+	return event, nil
+}
+
+// Handler receives webhook deliveries. Verified, relevant events are passed to Dispatch and
+// acknowledged right away, since GitHub gives up on deliveries that take longer than 10 seconds.
+type Handler struct {
+	// Secret is the webhook secret of the App
+	Secret string
+	// Dispatch queues an event, returning false when the queue is full
+	Dispatch func(*Event) bool
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err = VerifySignature(h.Secret, body, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	name := r.Header.Get("X-GitHub-Event")
+	if name == EventPing {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	event, err := ParseEvent(name, body)
+	if errors.Is(err, ErrUnsupportedEvent) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !event.Relevant() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event.Delivery = r.Header.Get("X-GitHub-Delivery")
+	if !h.Dispatch(event) {
+		http.Error(w, "queue is full", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
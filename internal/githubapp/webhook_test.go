@@ -0,0 +1,112 @@
+package githubapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret = "webhook-secret"
+
+	checkSuitePayload = `{
+		"action": "completed",
+		"installation": {"id": 7},
+		"repository": {"name": "repo", "owner": {"login": "owner"}},
+		"check_suite": {
+			"id": 99, "head_sha": "abc", "head_branch": "feature",
+			"pull_requests": [{"number": 12, "base": {"ref": "main"}}]
+		}
+	}`
+	pullRequestPayload = `{
+		"action": "synchronize",
+		"installation": {"id": 7},
+		"repository": {"name": "repo", "owner": {"login": "owner"}},
+		"pull_request": {"number": 12, "head": {"ref": "feature", "sha": "abc"}, "base": {"ref": "main"}}
+	}`
+)
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"zen":"Keep it simple."}`)
+	require.NoError(t, VerifySignature(testSecret, body, sign(string(body))))
+	require.ErrorIs(t, VerifySignature("other", body, sign(string(body))), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(testSecret, body, ""), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(testSecret, body, "sha256=zz"), ErrInvalidSignature)
+}
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent(EventCheckSuite, []byte(checkSuitePayload))
+	require.NoError(t, err)
+	assert.Equal(t, &Event{
+		Name: EventCheckSuite, Action: "completed", InstallationID: 7, Owner: "owner", Repository: "repo",
+		HeadSHA: "abc", HeadBranch: "feature", BaseBranch: "main", CheckSuiteID: 99, PullRequests: []int{12},
+	}, event)
+	assert.True(t, event.Relevant())
+
+	event, err = ParseEvent(EventPullRequest, []byte(pullRequestPayload))
+	require.NoError(t, err)
+	assert.Equal(t, []int{12}, event.PullRequests)
+	assert.Equal(t, "main", event.BaseBranch)
+	assert.True(t, event.Relevant())
+
+	event.Action = "closed"
+	assert.False(t, event.Relevant())
+
+	_, err = ParseEvent("push", []byte(`{}`))
+	require.ErrorIs(t, err, ErrUnsupportedEvent)
+	_, err = ParseEvent(EventCheckSuite, []byte(`{"action":"completed"}`))
+	require.ErrorIs(t, err, ErrInvalidEvent)
+	_, err = ParseEvent(EventCheckSuite, []byte(`{`))
+	require.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestHandler(t *testing.T) {
+	var dispatched []*Event
+	accept := true
+	handler := &Handler{Secret: testSecret, Dispatch: func(event *Event) bool {
+		if accept {
+			dispatched = append(dispatched, event)
+		}
+		return accept
+	}}
+
+	deliver := func(method, name, body, signature string) int {
+		req := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", name)
+		req.Header.Set("X-GitHub-Delivery", "delivery-1")
+		req.Header.Set("X-Hub-Signature-256", signature)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusAccepted, deliver(http.MethodPost, EventCheckSuite, checkSuitePayload, sign(checkSuitePayload)))
+	require.Len(t, dispatched, 1)
+	assert.Equal(t, "delivery-1", dispatched[0].Delivery)
+
+	assert.Equal(t, http.StatusUnauthorized, deliver(http.MethodPost, EventCheckSuite, checkSuitePayload, sign("tampered")))
+	assert.Equal(t, http.StatusMethodNotAllowed, deliver(http.MethodGet, EventCheckSuite, "", ""))
+	assert.Equal(t, http.StatusOK, deliver(http.MethodPost, EventPing, `{}`, sign(`{}`)))
+	assert.Equal(t, http.StatusNoContent, deliver(http.MethodPost, "push", `{}`, sign(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, deliver(http.MethodPost, EventCheckSuite, `{}`, sign(`{}`)))
+
+	closed := strings.Replace(pullRequestPayload, "synchronize", "closed", 1)
+	assert.Equal(t, http.StatusNoContent, deliver(http.MethodPost, EventPullRequest, closed, sign(closed)))
+
+	accept = false
+	assert.Equal(t, http.StatusServiceUnavailable, deliver(http.MethodPost, EventPullRequest, pullRequestPayload, sign(pullRequestPayload)))
+	assert.Len(t, dispatched, 1)
+}
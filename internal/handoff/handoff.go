@@ -0,0 +1,258 @@
+// Package handoff carries coverage results from a pull request workflow that may not write to
+// the repository (fork pull requests) to a trusted workflow that posts the comment and statuses
+package handoff
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// SchemaVersion is the version of the payload format written by this build
+	SchemaVersion = 1
+	// FileName is the name of the payload inside the handoff directory
+	FileName = "coverage-handoff.json"
+	// CommentFileName is the rendered comment, kept next to the payload for humans
+	CommentFileName = "comment.md"
+
+	// maxPayloadSize bounds how much of an untrusted payload is read
+	maxPayloadSize = 1 << 20
+	// maxCommentLength is the largest comment body GitHub accepts
+	maxCommentLength = 65536
+
+	// StatusContextPrefix starts the contexts of the statuses go-coverage posts
+	StatusContextPrefix = "go-coverage/"
+	// PRStatusContext is the context of the pull request summary status
+	PRStatusContext = "Go-Coverage/Coverage-PR"
+)
+
+// Static error definitions
+var (
+	ErrPayloadTooLarge      = errors.New("handoff payload is too large")
+	ErrUnsupportedSchema    = errors.New("unsupported handoff schema version")
+	ErrInvalidPayload       = errors.New("invalid handoff payload")
+	ErrRepositoryMismatch   = errors.New("handoff payload targets a different repository")
+	ErrPayloadNotFound      = errors.New("handoff payload not found in artifact")
+	errMissingRepository    = errors.New("repository owner and name are required")
+	errInvalidPullRequest   = errors.New("pull request number must be positive")
+	errInvalidCommitSHA     = errors.New("commit SHA must be 40 hexadecimal characters")
+	errEmptyComment         = errors.New("comment body is empty")
+	errCommentTooLong       = errors.New("comment body exceeds the GitHub limit")
+	errInvalidPercentage    = errors.New("coverage percentages must be between 0 and 100")
+	errInvalidStatements    = errors.New("covered statements must be between 0 and the total")
+	errInvalidStatusState   = errors.New("status state must be success, failure, error or pending")
+	errMissingStatusContext = errors.New("status context is required")
+	errForeignStatusContext = errors.New("status context is not one go-coverage posts")
+	errInvalidTargetURL     = errors.New("status target URL must be an http or https URL")
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	shaPattern  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// Payload is everything the trusted workflow needs to publish the results of a pull request run
+type Payload struct {
+	SchemaVersion int    `json:"schema_version"`
+	Owner         string `json:"owner"`
+	Repository    string `json:"repository"`
+	PullRequest   int    `json:"pull_request"`
+	// CommitSHA receives the statuses; without it only the comment is posted
+	CommitSHA string `json:"commit_sha,omitempty"`
+	// Comment is the rendered PR comment body
+	Comment string `json:"comment"`
+	// Coverage is nil when no coverage was measured, e.g. for pull requests without code changes
+	Coverage *Coverage `json:"coverage,omitempty"`
+	// Statuses are commit statuses to post as-is; only go-coverage contexts are accepted
+	Statuses []Status `json:"statuses,omitempty"`
+	// CreateStatusChecks asks for the coverage status checks to be created from Coverage
+	CreateStatusChecks bool `json:"create_status_checks"`
+	// BlockMerge makes failed coverage checks block the merge
+	BlockMerge  bool      `json:"block_merge"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Coverage holds the measured coverage of the pull request
+type Coverage struct {
+	Percentage        float64 `json:"percentage"`
+	TotalStatements   int     `json:"total_statements"`
+	CoveredStatements int     `json:"covered_statements"`
+	BasePercentage    float64 `json:"base_percentage"`
+	Difference        float64 `json:"difference"`
+	Trend             string  `json:"trend"`
+	Threshold         float64 `json:"threshold"`
+}
+
+// Status is a commit status to post verbatim, limited to the contexts go-coverage owns so an
+// untrusted payload cannot satisfy other required checks
+type Status struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// Validate checks that the payload is complete and within the limits GitHub accepts. The payload
+// comes from an untrusted workflow, so every field used to address the API is checked.
+func (p *Payload) Validate() error {
+	if p.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("%w: %d (expected %d)", ErrUnsupportedSchema, p.SchemaVersion, SchemaVersion)
+	}
+	if !namePattern.MatchString(p.Owner) || !namePattern.MatchString(p.Repository) {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, errMissingRepository)
+	}
+	if p.PullRequest <= 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, errInvalidPullRequest)
+	}
+	if p.CommitSHA != "" && !shaPattern.MatchString(p.CommitSHA) {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, errInvalidCommitSHA)
+	}
+	if p.Comment == "" {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, errEmptyComment)
+	}
+	if len(p.Comment) > maxCommentLength {
+		return fmt.Errorf("%w: %w (%d bytes)", ErrInvalidPayload, errCommentTooLong, len(p.Comment))
+	}
+	if c := p.Coverage; c != nil {
+		for _, value := range []float64{c.Percentage, c.BasePercentage, c.Threshold} {
+			if value < 0 || value > 100 {
+				return fmt.Errorf("%w: %w", ErrInvalidPayload, errInvalidPercentage)
+			}
+		}
+		if c.CoveredStatements < 0 || c.CoveredStatements > c.TotalStatements {
+			return fmt.Errorf("%w: %w", ErrInvalidPayload, errInvalidStatements)
+		}
+	}
+	for _, status := range p.Statuses {
+		switch status.State {
+		case "success", "failure", "error", "pending":
+		default:
+			return fmt.Errorf("%w: %w, got %q", ErrInvalidPayload, errInvalidStatusState, status.State)
+		}
+		if status.Context == "" {
+			return fmt.Errorf("%w: %w", ErrInvalidPayload, errMissingStatusContext)
+		}
+		if !OwnStatusContext(status.Context) {
+			return fmt.Errorf("%w: %w, got %q", ErrInvalidPayload, errForeignStatusContext, status.Context)
+		}
+		if status.TargetURL != "" {
+			target, err := url.Parse(status.TargetURL)
+			if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+				return fmt.Errorf("%w: %w, got %q", ErrInvalidPayload, errInvalidTargetURL, status.TargetURL)
+			}
+		}
+	}
+	return nil
+}
+
+// OwnStatusContext reports whether a status context is one go-coverage posts
+func OwnStatusContext(context string) bool {
+	return context == PRStatusContext ||
+		(strings.HasPrefix(context, StatusContextPrefix) && len(context) > len(StatusContextPrefix))
+}
+
+// Write stores the payload and the rendered comment in dir and returns the payload path
+func Write(dir string, payload *Payload, mode os.FileMode) (string, error) {
+	if payload.SchemaVersion == 0 {
+		payload.SchemaVersion = SchemaVersion
+	}
+	if err := payload.Validate(); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal handoff payload: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create handoff directory: %w", err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, append(data, '\n'), mode); err != nil {
+		return "", fmt.Errorf("failed to write handoff payload: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, CommentFileName), []byte(payload.Comment), mode); err != nil {
+		return "", fmt.Errorf("failed to write handoff comment: %w", err)
+	}
+	return path, nil
+}
+
+// Read loads and validates a payload. path may be the payload file or the directory holding it.
+func Read(path string) (*Payload, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, FileName)
+	}
+
+	file, err := os.Open(path) //nolint:gosec // path is chosen by the operator of the trusted workflow
+	if err != nil {
+		return nil, fmt.Errorf("failed to open handoff payload: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return Decode(file)
+}
+
+// Decode reads and validates a payload from r, refusing payloads larger than 1 MiB
+func Decode(r io.Reader) (*Payload, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handoff payload: %w", err)
+	}
+	if len(data) > maxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// FromZip extracts and validates the payload from a downloaded artifact archive
+func FromZip(archive []byte) (*Payload, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if filepath.Base(filepath.FromSlash(file.Name)) != FileName {
+			continue
+		}
+		if file.UncompressedSize64 > maxPayloadSize {
+			return nil, ErrPayloadTooLarge
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in artifact: %w", file.Name, err)
+		}
+		defer func() { _ = rc.Close() }()
+		return Decode(rc)
+	}
+
+	return nil, ErrPayloadNotFound
+}
+
+// CheckRepository ensures the payload addresses the repository the trusted workflow runs in,
+// so an artifact cannot redirect comments to another repository the token can write to
+func (p *Payload) CheckRepository(owner, repository string) error {
+	if !strings.EqualFold(p.Owner, owner) || !strings.EqualFold(p.Repository, repository) {
+		return fmt.Errorf("%w: %s/%s (expected %s/%s)", ErrRepositoryMismatch, p.Owner, p.Repository, owner, repository)
+	}
+	return nil
+}
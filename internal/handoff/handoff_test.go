@@ -0,0 +1,139 @@
+package handoff
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validPayload() *Payload {
+	return &Payload{
+		Owner:       "owner",
+		Repository:  "repo",
+		PullRequest: 42,
+		CommitSHA:   "0123456789abcdef0123456789abcdef01234567",
+		Comment:     "## Coverage\n\n85%",
+		Coverage: &Coverage{
+			Percentage:        85,
+			TotalStatements:   100,
+			CoveredStatements: 85,
+			Threshold:         80,
+		},
+		CreateStatusChecks: true,
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "handoff")
+	path, err := Write(dir, validPayload(), 0o644)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, FileName), path)
+
+	comment, err := os.ReadFile(filepath.Join(dir, CommentFileName)) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Equal(t, "## Coverage\n\n85%", string(comment))
+
+	for _, source := range []string{dir, path} {
+		payload, err := Read(source)
+		require.NoError(t, err)
+		assert.Equal(t, SchemaVersion, payload.SchemaVersion)
+		assert.Equal(t, 42, payload.PullRequest)
+		assert.InDelta(t, 85.0, payload.Coverage.Percentage, 0.001)
+		assert.True(t, payload.CreateStatusChecks)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Payload)
+		err    error
+	}{
+		{name: "valid", modify: func(*Payload) {}},
+		{name: "no commit", modify: func(p *Payload) { p.CommitSHA = "" }},
+		{name: "future schema", modify: func(p *Payload) { p.SchemaVersion = 99 }, err: ErrUnsupportedSchema},
+		{name: "path in owner", modify: func(p *Payload) { p.Owner = "../other" }, err: ErrInvalidPayload},
+		{name: "no pull request", modify: func(p *Payload) { p.PullRequest = 0 }, err: ErrInvalidPayload},
+		{name: "short commit", modify: func(p *Payload) { p.CommitSHA = "abc123" }, err: ErrInvalidPayload},
+		{name: "empty comment", modify: func(p *Payload) { p.Comment = "" }, err: ErrInvalidPayload},
+		{name: "huge comment", modify: func(p *Payload) { p.Comment = strings.Repeat("x", maxCommentLength+1) }, err: ErrInvalidPayload},
+		{name: "bad percentage", modify: func(p *Payload) { p.Coverage.Percentage = 120 }, err: ErrInvalidPayload},
+		{name: "bad statements", modify: func(p *Payload) { p.Coverage.CoveredStatements = 101 }, err: ErrInvalidPayload},
+		{name: "own statuses", modify: func(p *Payload) {
+			p.Statuses = []Status{
+				{State: "success", Context: "go-coverage/critical", TargetURL: "https://owner.github.io/repo/"},
+				{State: "success", Context: PRStatusContext},
+			}
+		}},
+		{name: "bad status", modify: func(p *Payload) { p.Statuses = []Status{{State: "done", Context: "go-coverage/total"}} }, err: ErrInvalidPayload},
+		{name: "foreign status context", modify: func(p *Payload) { p.Statuses = []Status{{State: "success", Context: "ci/build"}} }, err: ErrInvalidPayload},
+		{name: "bare status prefix", modify: func(p *Payload) { p.Statuses = []Status{{State: "success", Context: StatusContextPrefix}} }, err: ErrInvalidPayload},
+		{name: "script target URL", modify: func(p *Payload) {
+			p.Statuses = []Status{{State: "success", Context: "go-coverage/total", TargetURL: "javascript:alert(1)"}}
+		}, err: ErrInvalidPayload},
+		{name: "status without context", modify: func(p *Payload) { p.Statuses = []Status{{State: "success"}} }, err: ErrInvalidPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := validPayload()
+			payload.SchemaVersion = SchemaVersion
+			tt.modify(payload)
+			err := payload.Validate()
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	_, err := Decode(strings.NewReader(strings.Repeat(" ", maxPayloadSize+1)))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	_, err = Decode(strings.NewReader("not json"))
+	require.ErrorIs(t, err, ErrInvalidPayload)
+}
+
+func TestCheckRepository(t *testing.T) {
+	payload := validPayload()
+	require.NoError(t, payload.CheckRepository("Owner", "REPO"))
+	require.ErrorIs(t, payload.CheckRepository("owner", "other"), ErrRepositoryMismatch)
+}
+
+func TestFromZip(t *testing.T) {
+	data, err := json.Marshal(func() *Payload { p := validPayload(); p.SchemaVersion = SchemaVersion; return p }())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, err := writer.Create("coverage-handoff/" + FileName)
+	require.NoError(t, err)
+	_, err = entry.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	payload, err := FromZip(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 42, payload.PullRequest)
+
+	buf.Reset()
+	writer = zip.NewWriter(&buf)
+	_, err = writer.Create("other.txt")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	_, err = FromZip(buf.Bytes())
+	require.ErrorIs(t, err, ErrPayloadNotFound)
+
+	_, err = FromZip([]byte("not a zip"))
+	require.Error(t, err)
+}
@@ -0,0 +1,278 @@
+// Package health checks that the environment a coverage run depends on is set up: the GitHub
+// Pages site is published, the token may do what the run asks of it and history storage is
+// writable. Every failed check carries a remediation message.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/diagnostics"
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+// Status is the outcome of a check
+type Status string
+
+// Check outcomes
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Operations a token can be checked for
+const (
+	OperationStatuses = "statuses"
+	OperationComments = "comments"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name        string `json:"name"`
+	Status      Status `json:"status"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+	// Code is the error code of a failure, when it has one
+	Code diagnostics.Code `json:"code,omitempty"`
+}
+
+// Checker checks one part of the environment
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) Result
+}
+
+// Run runs the checkers in order and returns their results
+func Run(ctx context.Context, checkers ...Checker) []Result {
+	results := make([]Result, 0, len(checkers))
+	for _, checker := range checkers {
+		result := checker.Check(ctx)
+		result.Name = checker.Name()
+		results = append(results, result)
+	}
+	return results
+}
+
+// Healthy reports whether no check failed
+func Healthy(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// PagesChecker verifies that the GitHub Pages site responds by requesting the badge with HEAD
+type PagesChecker struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name implements Checker
+func (c *PagesChecker) Name() string {
+	return "GitHub Pages"
+}
+
+// Check implements Checker
+func (c *PagesChecker) Check(ctx context.Context) Result {
+	if c.URL == "" {
+		return Result{
+			Status:      StatusSkip,
+			Message:     "no badge URL (repository unknown)",
+			Remediation: "Set GITHUB_REPOSITORY or pass --badge-url",
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.URL, nil)
+	if err != nil {
+		return Result{Status: StatusFail, Message: fmt.Sprintf("invalid badge URL %s: %v", c.URL, err)}
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{
+			Status:      StatusFail,
+			Message:     fmt.Sprintf("%s is unreachable: %v", c.URL, err),
+			Remediation: "Check the network, proxy (GO_COVERAGE_PROXY_URL) and CA bundle (GO_COVERAGE_CA_BUNDLE) settings",
+		}
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return Result{Status: StatusPass, Message: fmt.Sprintf("%s responds (%d)", c.URL, resp.StatusCode)}
+	case resp.StatusCode == http.StatusNotFound:
+		return Result{
+			Status:  StatusFail,
+			Code:    diagnostics.CodePagesNotEnabled,
+			Message: fmt.Sprintf("%s was not found", c.URL),
+			Remediation: "Run \"go-coverage setup-pages\", set the Pages source to GitHub Actions under " +
+				"Settings → Pages and let the coverage workflow deploy once from the default branch",
+		}
+	default:
+		return Result{
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("%s answered %d", c.URL, resp.StatusCode),
+			Remediation: "Check the Pages deployment in the Actions tab; GitHub Pages may be degraded (githubstatus.com)",
+		}
+	}
+}
+
+// TokenAPI reads the permissions of a token
+type TokenAPI interface {
+	GetTokenPermissions(ctx context.Context, owner, repo string) (*github.TokenPermissions, error)
+}
+
+// TokenChecker verifies that the token may perform the operations a run needs
+type TokenChecker struct {
+	API        TokenAPI
+	Token      string
+	Owner      string
+	Repository string
+	Operations []string // OperationStatuses and OperationComments
+}
+
+// Name implements Checker
+func (c *TokenChecker) Name() string {
+	return "GitHub token"
+}
+
+// Check implements Checker
+func (c *TokenChecker) Check(ctx context.Context) Result {
+	if len(c.Operations) == 0 {
+		return Result{Status: StatusSkip, Message: "no operation needs the token (comments and statuses are disabled)"}
+	}
+	if c.Token == "" {
+		return Result{
+			Status:      StatusFail,
+			Message:     "no token",
+			Remediation: "Set GITHUB_TOKEN, e.g. GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }} in the workflow",
+		}
+	}
+	if c.Owner == "" || c.Repository == "" {
+		return Result{Status: StatusSkip, Message: "repository unknown", Remediation: "Set GITHUB_REPOSITORY"}
+	}
+
+	permissions, err := c.API.GetTokenPermissions(ctx, c.Owner, c.Repository)
+	switch {
+	case errors.Is(err, github.ErrBadCredentials):
+		return Result{
+			Status:      StatusFail,
+			Code:        diagnostics.CodeTokenRejected,
+			Message:     "GitHub rejected the token",
+			Remediation: "The token is invalid or expired; create a new one or use the workflow's GITHUB_TOKEN",
+		}
+	case errors.Is(err, github.ErrRepositoryNotFound):
+		return Result{
+			Status:  StatusFail,
+			Message: fmt.Sprintf("the token cannot access %s/%s", c.Owner, c.Repository),
+			Remediation: "Grant the token access to the repository (repo scope for classic tokens, " +
+				"repository access for fine-grained tokens)",
+		}
+	case err != nil:
+		return Result{Status: StatusFail, Message: err.Error(), Remediation: "Check network access to api.github.com"}
+	}
+
+	if !permissions.ScopesKnown {
+		if permissions.PermissionsKnown && !permissions.Push {
+			return Result{
+				Status:      StatusFail,
+				Code:        diagnostics.CodeTokenForbidden,
+				Message:     fmt.Sprintf("the token has read-only access to %s/%s", c.Owner, c.Repository),
+				Remediation: "Use a token with write access to the repository",
+			}
+		}
+		return Result{
+			Status:      StatusWarn,
+			Message:     "the token's permissions cannot be inspected (GitHub Actions or fine-grained token)",
+			Remediation: "Make sure the workflow grants " + workflowPermissions(c.Operations),
+		}
+	}
+
+	var missing []string
+	for _, operation := range c.Operations {
+		if scope := requiredScope(operation, permissions.Private); !permissions.HasScope(scope) {
+			missing = append(missing, fmt.Sprintf("%s (for %s)", scope, operation))
+		}
+	}
+	if len(missing) > 0 {
+		return Result{
+			Status:      StatusFail,
+			Code:        diagnostics.CodeTokenForbidden,
+			Message:     "the token is missing the scopes " + strings.Join(missing, ", "),
+			Remediation: "Regenerate the token with these scopes under Settings → Developer settings → Personal access tokens",
+		}
+	}
+	return Result{Status: StatusPass, Message: "the token may create " + strings.Join(c.Operations, " and ")}
+}
+
+// requiredScope returns the OAuth scope a classic token needs for an operation
+func requiredScope(operation string, private bool) string {
+	switch {
+	case operation == OperationStatuses:
+		return "repo:status"
+	case private:
+		return "repo"
+	default:
+		return "public_repo"
+	}
+}
+
+// workflowPermissions returns the workflow permissions block entries the operations need
+func workflowPermissions(operations []string) string {
+	permissions := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		switch operation {
+		case OperationStatuses:
+			permissions = append(permissions, "statuses: write")
+		case OperationComments:
+			permissions = append(permissions, "pull-requests: write")
+		}
+	}
+	return strings.Join(permissions, " and ")
+}
+
+// StorageChecker verifies that history storage is writable
+type StorageChecker struct {
+	Path    string
+	DirMode os.FileMode
+}
+
+// Name implements Checker
+func (c *StorageChecker) Name() string {
+	return "History storage"
+}
+
+// Check implements Checker
+func (c *StorageChecker) Check(context.Context) Result {
+	remediation := fmt.Sprintf("Make %s writable or point GO_COVERAGE_HISTORY_PATH to a writable directory", c.Path)
+	if err := os.MkdirAll(c.Path, c.DirMode); err != nil {
+		return Result{Status: StatusFail, Message: fmt.Sprintf("cannot create %s: %v", c.Path, err), Remediation: remediation}
+	}
+
+	probe, err := os.CreateTemp(c.Path, ".health-*")
+	if err != nil {
+		return Result{Status: StatusFail, Message: fmt.Sprintf("cannot write to %s: %v", c.Path, err), Remediation: remediation}
+	}
+	name := probe.Name()
+	_ = probe.Close()
+	if err = os.Remove(name); err != nil {
+		return Result{
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("cannot remove %s: %v", filepath.Base(name), err),
+			Remediation: remediation,
+		}
+	}
+	return Result{Status: StatusPass, Message: c.Path + " is writable"}
+}
@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/github"
+)
+
+func TestPagesChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		switch r.URL.Path {
+		case "/coverage.svg":
+			w.WriteHeader(http.StatusOK)
+		case "/unavailable.svg":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		status Status
+	}{
+		{"published", server.URL + "/coverage.svg", StatusPass},
+		{"not deployed", server.URL + "/missing.svg", StatusFail},
+		{"degraded", server.URL + "/unavailable.svg", StatusWarn},
+		{"no url", "", StatusSkip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := (&PagesChecker{URL: tt.url, Client: server.Client()}).Check(context.Background())
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			if tt.status == StatusFail {
+				assert.Contains(t, result.Remediation, "setup-pages")
+			}
+		})
+	}
+}
+
+// fakeTokenAPI returns fixed token permissions
+type fakeTokenAPI struct {
+	permissions *github.TokenPermissions
+	err         error
+}
+
+func (f *fakeTokenAPI) GetTokenPermissions(context.Context, string, string) (*github.TokenPermissions, error) {
+	return f.permissions, f.err
+}
+
+func TestTokenChecker(t *testing.T) {
+	both := []string{OperationStatuses, OperationComments}
+	tests := []struct {
+		name        string
+		api         *fakeTokenAPI
+		token       string
+		operations  []string
+		status      Status
+		remediation string
+	}{
+		{
+			name:       "classic token with repo",
+			api:        &fakeTokenAPI{permissions: &github.TokenPermissions{Private: true, ScopesKnown: true, Scopes: []string{"repo"}}},
+			operations: both,
+			status:     StatusPass,
+		},
+		{
+			name:        "classic token missing scopes",
+			api:         &fakeTokenAPI{permissions: &github.TokenPermissions{Private: true, ScopesKnown: true, Scopes: []string{"repo:status"}}},
+			operations:  both,
+			status:      StatusFail,
+			remediation: "Regenerate the token",
+		},
+		{
+			name:       "public repository",
+			api:        &fakeTokenAPI{permissions: &github.TokenPermissions{ScopesKnown: true, Scopes: []string{"public_repo", "repo:status"}}},
+			operations: both,
+			status:     StatusPass,
+		},
+		{
+			name:        "actions token",
+			api:         &fakeTokenAPI{permissions: &github.TokenPermissions{}},
+			operations:  both,
+			status:      StatusWarn,
+			remediation: "statuses: write and pull-requests: write",
+		},
+		{
+			name:        "read-only token",
+			api:         &fakeTokenAPI{permissions: &github.TokenPermissions{PermissionsKnown: true}},
+			operations:  []string{OperationComments},
+			status:      StatusFail,
+			remediation: "write access",
+		},
+		{
+			name:        "rejected token",
+			api:         &fakeTokenAPI{err: github.ErrBadCredentials},
+			operations:  both,
+			status:      StatusFail,
+			remediation: "invalid or expired",
+		},
+		{
+			name:        "no repository access",
+			api:         &fakeTokenAPI{err: github.ErrRepositoryNotFound},
+			operations:  both,
+			status:      StatusFail,
+			remediation: "access to the repository",
+		},
+		{
+			name:       "nothing to do",
+			api:        &fakeTokenAPI{err: errors.New("not called")},
+			operations: nil,
+			status:     StatusSkip,
+		},
+		{
+			name:        "no token",
+			api:         &fakeTokenAPI{},
+			token:       "-",
+			operations:  both,
+			status:      StatusFail,
+			remediation: "GITHUB_TOKEN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := "ghp_test"
+			if tt.token == "-" {
+				token = ""
+			}
+			checker := &TokenChecker{API: tt.api, Token: token, Owner: "owner", Repository: "repo", Operations: tt.operations}
+			result := checker.Check(context.Background())
+			assert.Equal(t, tt.status, result.Status, result.Message)
+			assert.Contains(t, result.Remediation, tt.remediation)
+		})
+	}
+}
+
+func TestStorageChecker(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	result := (&StorageChecker{Path: dir, DirMode: 0o750}).Check(context.Background())
+	assert.Equal(t, StatusPass, result.Status, result.Message)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the probe file is removed")
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	result = (&StorageChecker{Path: filepath.Join(file, "history"), DirMode: 0o750}).Check(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Contains(t, result.Remediation, "GO_COVERAGE_HISTORY_PATH")
+}
+
+func TestRunAndHealthy(t *testing.T) {
+	results := Run(context.Background(),
+		&StorageChecker{Path: t.TempDir(), DirMode: 0o750},
+		&TokenChecker{},
+	)
+	require.Len(t, results, 2)
+	assert.Equal(t, "History storage", results[0].Name)
+	assert.Equal(t, "GitHub token", results[1].Name)
+	assert.True(t, Healthy(results))
+
+	results = append(results, Result{Status: StatusFail})
+	assert.False(t, Healthy(results))
+}
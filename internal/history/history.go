@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/mrz1836/go-coverage/internal/schema"
 )
 
 // ErrNoHistory indicates no coverage history is available
@@ -23,6 +25,28 @@ type CoverageRecord struct {
 	CoveredLines int       `json:"covered_lines"`
 }
 
+// RecordsSchema versions coverage-history.json. Version 1 wrapped the bare array of records
+// in an object carrying the schema version.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var RecordsSchema = schema.Schema{
+	Name: "coverage history",
+	Migrations: []schema.Migration{
+		func(doc any) (any, error) {
+			if records, ok := doc.([]any); ok {
+				return map[string]any{"records": records}, nil
+			}
+			return doc, nil
+		},
+	},
+}
+
+// recordsFile is the stored form of the coverage history
+type recordsFile struct {
+	SchemaVersion int              `json:"schema_version"`
+	Records       []CoverageRecord `json:"records"`
+}
+
 // Manager manages coverage history storage and retrieval
 type Manager struct {
 	historyFile string
@@ -109,12 +133,12 @@ func (m *Manager) loadHistory() ([]CoverageRecord, error) {
 		return nil, err
 	}
 
-	var history []CoverageRecord
-	if err := json.Unmarshal(data, &history); err != nil {
+	var file recordsFile
+	if err := RecordsSchema.Decode(data, &file); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal history: %w", err)
 	}
 
-	return history, nil
+	return file.Records, nil
 }
 
 // ensureHistoryDir ensures the history directory exists
@@ -130,7 +154,7 @@ func (m *Manager) ensureHistoryDir() error {
 
 // saveHistory saves the coverage history to the JSON file
 func (m *Manager) saveHistory(history []CoverageRecord) error {
-	data, err := json.MarshalIndent(history, "", "  ")
+	data, err := json.MarshalIndent(recordsFile{SchemaVersion: RecordsSchema.Version(), Records: history}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal history: %w", err)
 	}
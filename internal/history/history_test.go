@@ -12,6 +12,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/schema"
 )
 
 var (
@@ -501,6 +503,15 @@ func TestLoadHistory(t *testing.T) {
 		assert.Empty(t, history)
 	})
 
+	t.Run("Load history written by a newer release", func(t *testing.T) {
+		err := os.WriteFile(manager.historyFile, []byte(`{"schema_version":99,"records":[]}`), 0o600)
+		require.NoError(t, err)
+
+		history, err := manager.loadHistory()
+		assert.Nil(t, history)
+		require.ErrorIs(t, err, schema.ErrUnsupportedVersion)
+	})
+
 	t.Run("Load history from valid JSON file", func(t *testing.T) {
 		expectedRecords := []CoverageRecord{
 			{
@@ -636,9 +647,11 @@ func TestSaveHistory(t *testing.T) {
 		data, readErr := os.ReadFile(manager.historyFile)
 		require.NoError(t, readErr)
 
-		var loadedHistory []CoverageRecord
-		err = json.Unmarshal(data, &loadedHistory)
+		var loaded recordsFile
+		err = json.Unmarshal(data, &loaded)
 		require.NoError(t, err)
+		assert.Equal(t, RecordsSchema.Version(), loaded.SchemaVersion)
+		loadedHistory := loaded.Records
 		require.Len(t, loadedHistory, 2)
 
 		for i, expected := range history {
@@ -664,14 +677,14 @@ func TestSaveHistory(t *testing.T) {
 		saveErr := manager.saveHistory(emptyHistory)
 		require.NoError(t, saveErr)
 
-		// Verify file exists and contains empty array
+		// Verify file exists and contains no records
 		data, readErr := os.ReadFile(manager.historyFile)
 		require.NoError(t, readErr)
 
-		var loadedHistory []CoverageRecord
-		err = json.Unmarshal(data, &loadedHistory)
+		var loaded recordsFile
+		err = json.Unmarshal(data, &loaded)
 		require.NoError(t, err)
-		assert.Empty(t, loadedHistory)
+		assert.Empty(t, loaded.Records)
 	})
 
 	t.Run("Save history creates nested directory", func(t *testing.T) {
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"time"
+)
+
+// clockSkewTolerance is how far ahead of the reading clock a timestamp may be before it is
+// treated as skewed, allowing for runners whose clocks drift slightly
+const clockSkewTolerance = 5 * time.Minute
+
+// compareRuns orders two runs of a branch, oldest first: by sequence number, then by position
+// in the commit graph for runs recorded concurrently, then by timestamp. Entries recorded before
+// sequence numbers have none and come before the entries that do.
+func compareRuns(a, b Entry) int {
+	if c := cmp.Compare(a.Sequence, b.Sequence); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.CommitDepth, b.CommitDepth); c != 0 {
+		return c
+	}
+	return a.Timestamp.Compare(b.Timestamp)
+}
+
+// orderEntries sorts entries newest first. The runs of each branch are ordered by compareRuns and
+// their timestamps normalized with NormalizeTimestamps, keeping the original of every skewed
+// timestamp in SkewedTimestamp, before the branches are interleaved by timestamp.
+func orderEntries(entries []Entry, now time.Time) {
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		if c := cmp.Compare(a.Branch, b.Branch); c != 0 {
+			return c
+		}
+		return compareRuns(a, b)
+	})
+
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Branch == entries[start].Branch {
+			end++
+		}
+		runs := entries[start:end]
+		times := make([]time.Time, len(runs))
+		for i := range runs {
+			times[i] = runs[i].Timestamp
+		}
+		normalized, skewed := NormalizeTimestamps(times, now)
+		for i := range runs {
+			if skewed[i] {
+				runs[i].SkewedTimestamp = runs[i].Timestamp
+				runs[i].Timestamp = normalized[i]
+			}
+		}
+		start = end
+	}
+
+	// Normalized timestamps never decrease along a branch, so a stable sort of the reversed runs
+	// keeps the runs of a branch that share a timestamp newest first
+	slices.Reverse(entries)
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+}
+
+// NormalizeTimestamps detects the skewed timestamps of runs given in the order they happened,
+// oldest first, and returns the timestamps with the skewed ones replaced, along with which were.
+// A timestamp is skewed when it is ahead of now by more than a few minutes, or when it is not part
+// of the longest run of timestamps that never go backwards, so a single runner with a wrong clock
+// is blamed rather than every run after it. A skewed timestamp takes the timestamp of the run
+// before it, or of the run after it when no run before it has a trusted timestamp.
+func NormalizeTimestamps(times []time.Time, now time.Time) ([]time.Time, []bool) {
+	limit := now.Add(clockSkewTolerance)
+
+	// Longest non-decreasing subsequence of the timestamps not in the future: tails[k] is the
+	// index of the smallest last timestamp of such a subsequence of length k+1
+	var tails []int
+	previous := make([]int, len(times))
+	for i, timestamp := range times {
+		previous[i] = -1
+		if timestamp.After(limit) {
+			continue
+		}
+		k := sort.Search(len(tails), func(j int) bool { return times[tails[j]].After(timestamp) })
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	skewed := make([]bool, len(times))
+	for i := range skewed {
+		skewed[i] = true
+	}
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+			skewed[i] = false
+		}
+	}
+
+	// Until the first trusted timestamp, skewed ones take it, or now when every timestamp is
+	// in the future
+	normalized := slices.Clone(times)
+	fallback := now
+	if first := slices.Index(skewed, false); first >= 0 {
+		fallback = times[first]
+	}
+	for i := range normalized {
+		if skewed[i] {
+			normalized[i] = fallback
+		} else {
+			fallback = normalized[i]
+		}
+	}
+	return normalized, skewed
+}
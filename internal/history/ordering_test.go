@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTimestamps(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(hours ...int) []time.Time {
+		times := make([]time.Time, len(hours))
+		for i, hour := range hours {
+			times[i] = now.Add(time.Duration(hour) * time.Hour)
+		}
+		return times
+	}
+
+	tests := []struct {
+		name       string
+		times      []time.Time
+		normalized []time.Time
+		skewed     []bool
+	}{
+		{
+			name:       "in order",
+			times:      at(-4, -3, -3, -1),
+			normalized: at(-4, -3, -3, -1),
+			skewed:     []bool{false, false, false, false},
+		},
+		{
+			name:       "clock behind",
+			times:      at(-5, -4, -9, -2, -1),
+			normalized: at(-5, -4, -4, -2, -1),
+			skewed:     []bool{false, false, true, false, false},
+		},
+		{
+			name:       "clock ahead",
+			times:      at(-5, -4, -1, -3, -2),
+			normalized: at(-5, -4, -4, -3, -2),
+			skewed:     []bool{false, false, true, false, false},
+		},
+		{
+			name:       "first run skewed",
+			times:      at(-1, -4, -3),
+			normalized: at(-4, -4, -3),
+			skewed:     []bool{true, false, false},
+		},
+		{
+			name:       "future",
+			times:      at(-2, 24),
+			normalized: at(-2, -2),
+			skewed:     []bool{false, true},
+		},
+		{
+			name:       "all future",
+			times:      at(24, 48),
+			normalized: at(0, 0),
+			skewed:     []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, skewed := NormalizeTimestamps(tt.times, now)
+			assert.Equal(t, tt.normalized, normalized)
+			assert.Equal(t, tt.skewed, skewed)
+		})
+	}
+}
+
+func TestRecordSequenceOrdersSkewedRuns(t *testing.T) {
+	storage := t.TempDir()
+	tracker := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/repo"})
+	ctx := context.Background()
+
+	for _, commit := range []string{"c1", "c2", "c3", "c4"} {
+		require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithCommit(commit, ""), WithCommitDepth(7)))
+	}
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("feature"), WithCommit("f1", "")))
+
+	// The runner recording c3 had a clock two hours behind
+	files, err := filepath.Glob(filepath.Join(storage, "owner", "repo", DefaultBranch, "*.json"))
+	require.NoError(t, err)
+	var recorded time.Time
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // test fixture
+		require.NoError(t, err)
+		var entry Entry
+		require.NoError(t, json.Unmarshal(data, &entry))
+		if entry.CommitSHA != "c3" {
+			continue
+		}
+		recorded = entry.Timestamp.Add(-2 * time.Hour)
+		entry.Timestamp = recorded
+		data, err = json.Marshal(entry)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(file, data, 0o600))
+	}
+	require.False(t, recorded.IsZero())
+
+	trend, err := tracker.GetTrend(ctx, WithTrendBranch(DefaultBranch))
+	require.NoError(t, err)
+	require.Len(t, trend.Entries, 4)
+	for i, commit := range []string{"c4", "c3", "c2", "c1"} {
+		assert.Equal(t, commit, trend.Entries[i].CommitSHA)
+		assert.Equal(t, int64(4-i), trend.Entries[i].Sequence)
+		assert.Equal(t, 7, trend.Entries[i].CommitDepth)
+	}
+	assert.True(t, trend.Entries[1].SkewedTimestamp.Equal(recorded))
+	assert.True(t, trend.Entries[1].Timestamp.Equal(trend.Entries[2].Timestamp), "the skewed run takes the timestamp of the run before it")
+	assert.True(t, trend.Entries[0].SkewedTimestamp.IsZero())
+	assert.Equal(t, 1, trend.Summary.SkewedEntries)
+
+	feature, err := tracker.GetLatestEntry(ctx, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), feature.Sequence, "sequence numbers count per branch")
+}
@@ -0,0 +1,39 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultSuite names the unit test runs, whose entries are recorded without a suite
+const DefaultSuite = "unit"
+
+// BenchSuite names runs that capture coverage while benchmarks run
+const BenchSuite = "bench"
+
+// ErrInvalidSuite indicates a suite name that is not made of letters, digits, '-' and '_'
+var ErrInvalidSuite = errors.New("history suite must be letters, digits, '-' or '_'")
+
+// ParseSuite returns the suite name entries are recorded and read under: lower case, and empty
+// for the unit tests, given as "" or DefaultSuite
+func ParseSuite(name string) (string, error) {
+	suite := strings.ToLower(strings.TrimSpace(name))
+	if suite == DefaultSuite {
+		return "", nil
+	}
+	for i, r := range suite {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && (i == 0 || (r != '-' && r != '_')) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidSuite, name)
+		}
+	}
+	return suite, nil
+}
+
+// SuiteName returns the name of the suite of an entry, DefaultSuite for unit test entries
+func (e *Entry) SuiteName() string {
+	if e.Suite == "" {
+		return DefaultSuite
+	}
+	return e.Suite
+}
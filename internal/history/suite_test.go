@@ -0,0 +1,79 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuite(t *testing.T) {
+	for name, want := range map[string]string{
+		"":           "",
+		"unit":       "",
+		" Unit ":     "",
+		"bench":      "bench",
+		"BENCH":      "bench",
+		"e2e":        "e2e",
+		"load-tests": "load-tests",
+		"fuzz_1":     "fuzz_1",
+	} {
+		suite, err := ParseSuite(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, suite, name)
+	}
+
+	for _, name := range []string{"bench suite", "-bench", "_bench", "bench/x", "bänch"} {
+		_, err := ParseSuite(name)
+		require.ErrorIs(t, err, ErrInvalidSuite, name)
+	}
+}
+
+func TestEntrySuiteName(t *testing.T) {
+	assert.Equal(t, DefaultSuite, (&Entry{}).SuiteName())
+	assert.Equal(t, BenchSuite, (&Entry{Suite: BenchSuite}).SuiteName())
+}
+
+func TestTrackerSuites(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	unit := createTestCoverage()
+	unit.Percentage = 80.0
+	require.NoError(t, tracker.Record(ctx, unit, WithBranch(DefaultBranch), WithCommit("0123456789abcdef", "")))
+
+	time.Sleep(10 * time.Millisecond)
+
+	bench := createTestCoverage()
+	bench.Percentage = 40.0
+	require.NoError(t, tracker.Record(ctx, bench, WithBranch(DefaultBranch), WithCommit("0123456789abcdef", ""), WithSuite(BenchSuite)))
+
+	trend, err := tracker.GetTrend(ctx)
+	require.NoError(t, err)
+	require.Len(t, trend.Entries, 1)
+	assert.InDelta(t, 80.0, trend.Entries[0].Coverage.Percentage, 0.001)
+
+	trend, err = tracker.GetTrend(ctx, WithTrendSuite(BenchSuite))
+	require.NoError(t, err)
+	require.Len(t, trend.Entries, 1)
+	assert.Equal(t, BenchSuite, trend.Entries[0].Suite)
+	assert.InDelta(t, 40.0, trend.Entries[0].Coverage.Percentage, 0.001)
+
+	latest, err := tracker.GetLatestEntry(ctx, DefaultBranch)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSuite, latest.SuiteName())
+
+	latest, err = tracker.GetLatestEntry(ctx, DefaultBranch, WithTrendSuite(BenchSuite))
+	require.NoError(t, err)
+	assert.Equal(t, BenchSuite, latest.SuiteName())
+
+	found, err := tracker.FindEntry(ctx, "0123456789abcdef")
+	require.NoError(t, err)
+	assert.Empty(t, found.Suite, "a commit resolves to its unit test entry")
+
+	stats, err := tracker.GetStatistics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{BenchSuite: 1}, stats.UniqueSuites)
+}
@@ -10,14 +10,19 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+	"github.com/mrz1836/go-coverage/internal/schema"
+	"github.com/mrz1836/go-coverage/internal/testrun"
 )
 
 // Constants
 const (
-	DefaultBranch = "master" // Default branch for the repository
+	DefaultBranch   = "master" // Default branch for the repository
+	minCommitPrefix = 7        // Shortest abbreviated commit SHA accepted by FindEntry
 )
 
 // Static error definitions
@@ -35,16 +40,21 @@ var (
 	ErrWrittenFileSizeMismatch = errors.New("written file size mismatch")
 	ErrStoragePathNotDir       = errors.New("storage path exists but is not a directory")
 	ErrCreatedPathNotDir       = errors.New("created path is not a directory")
+	ErrAnnotationInvalid       = errors.New("annotation needs a commit and a note")
 )
 
 // Tracker manages coverage history and trend analysis
 type Tracker struct {
 	config *Config
+
+	migrateOnce sync.Once
+	migrateErr  error
 }
 
 // Config holds history tracking configuration
 type Config struct {
 	StoragePath      string // Path to store history files
+	Repository       string // Repository the entries belong to (owner/repo), namespaces the storage layout
 	RetentionDays    int    // Days to retain history data
 	MaxEntries       int    // Maximum number of entries to keep
 	CompressionLevel int    // Compression level for stored data (0-9)
@@ -55,15 +65,68 @@ type Config struct {
 
 // Entry represents a single coverage history entry
 type Entry struct {
-	Timestamp    time.Time                       `json:"timestamp"`
-	Branch       string                          `json:"branch"`
-	CommitSHA    string                          `json:"commit_sha"`
-	CommitURL    string                          `json:"commit_url,omitempty"`
-	Coverage     *parser.CoverageData            `json:"coverage"`
-	Metadata     map[string]string               `json:"metadata,omitempty"`
-	BuildInfo    *BuildInfo                      `json:"build_info,omitempty"`
-	FileHashes   map[string]string               `json:"file_hashes,omitempty"`
-	PackageStats map[string]*PackageHistoryStats `json:"package_stats,omitempty"`
+	SchemaVersion int                             `json:"schema_version"`
+	Timestamp     time.Time                       `json:"timestamp"`
+	Repository    string                          `json:"repository,omitempty"`
+	Branch        string                          `json:"branch"`
+	CommitSHA     string                          `json:"commit_sha"`
+	CommitURL     string                          `json:"commit_url,omitempty"`
+	Coverage      *parser.CoverageData            `json:"coverage"`
+	Metadata      map[string]string               `json:"metadata,omitempty"`
+	BuildInfo     *BuildInfo                      `json:"build_info,omitempty"`
+	FileHashes    map[string]string               `json:"file_hashes,omitempty"`
+	PackageStats  map[string]*PackageHistoryStats `json:"package_stats,omitempty"`
+	Tests         *testrun.Summary                `json:"tests,omitempty"`
+	// Bypass is why an emergency bypass downgraded the gates of the run to warnings
+	Bypass string `json:"bypass,omitempty"`
+	// Debt is the coverage debt of the run, when it was measured
+	Debt *Debt `json:"debt,omitempty"`
+	// Annotations are notes people added later to explain the coverage of the run
+	Annotations []Annotation `json:"annotations,omitempty"`
+	// Sequence numbers the runs of a branch in the order they were recorded, and orders the
+	// history ahead of Timestamp, which comes from the clock of whichever runner recorded it
+	Sequence int64 `json:"sequence,omitempty"`
+	// CommitDepth is the number of commits reachable from the commit, its position in the commit
+	// graph, ordering runs recorded concurrently with the same sequence number
+	CommitDepth int `json:"commit_depth,omitempty"`
+	// SkewedTimestamp is the timestamp the run recorded when it ran against the order of the runs
+	// around it, and Timestamp was normalized
+	SkewedTimestamp time.Time `json:"skewed_timestamp,omitzero"`
+	// Suite names the kind of test run the coverage was captured from, such as bench, so it is
+	// tracked apart from the unit test coverage recorded without one
+	Suite string `json:"suite,omitempty"`
+}
+
+// Debt is the coverage debt of a run: its uncovered statements, and the same statements weighted
+// by the complexity of their functions and whether they are on a critical path
+type Debt struct {
+	Uncovered int     `json:"uncovered"`
+	Weighted  float64 `json:"weighted"`
+}
+
+// Annotation is a human note on a history entry, such as why coverage jumped or dropped
+type Annotation struct {
+	Note      string    `json:"note"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EntrySchema versions the stored history entries. Version 1 replaced the tracker_version
+// metadata with the schema version.
+//
+//nolint:gochecknoglobals // read-only schema definition
+var EntrySchema = schema.Schema{
+	Name: "history entry",
+	Migrations: []schema.Migration{
+		func(doc any) (any, error) {
+			if entry, ok := doc.(map[string]any); ok {
+				if metadata, ok := entry["metadata"].(map[string]any); ok {
+					delete(metadata, "tracker_version")
+				}
+			}
+			return doc, nil
+		},
+	},
 }
 
 // BuildInfo contains build-related information
@@ -107,6 +170,8 @@ type TrendSummary struct {
 	CurrentTrend      string    `json:"current_trend"`
 	TrendStrength     string    `json:"trend_strength"`  // "strong", "moderate", "weak"
 	StabilityScore    float64   `json:"stability_score"` // 0-100
+	// SkewedEntries counts the entries whose timestamps were normalized for clock skew
+	SkewedEntries int `json:"skewed_entries,omitempty"`
 }
 
 // DateRange represents a time range
@@ -217,24 +282,37 @@ func (t *Tracker) Record(ctx context.Context, coverage *parser.CoverageData, opt
 		opts.CommitSHA = fmt.Sprintf("auto_%d", time.Now().UnixNano())
 	}
 
+	// Number the run after the runs already recorded on the branch, whatever the clocks say
+	sequence, err := t.nextSequence(ctx, opts.Branch)
+	if err != nil {
+		return err
+	}
+
 	// Create entry with comprehensive error context
 	entry := &Entry{
-		Timestamp:    time.Now(),
-		Branch:       opts.Branch,
-		CommitSHA:    opts.CommitSHA,
-		CommitURL:    opts.CommitURL,
-		Coverage:     coverage,
-		Metadata:     opts.Metadata,
-		BuildInfo:    opts.BuildInfo,
-		FileHashes:   t.calculateFileHashes(coverage),
-		PackageStats: t.calculatePackageStats(coverage, opts.Branch),
+		SchemaVersion: EntrySchema.Version(),
+		Timestamp:     time.Now(),
+		Repository:    t.config.Repository,
+		Branch:        opts.Branch,
+		CommitSHA:     opts.CommitSHA,
+		CommitURL:     opts.CommitURL,
+		Coverage:      coverage,
+		Metadata:      opts.Metadata,
+		BuildInfo:     opts.BuildInfo,
+		FileHashes:    t.calculateFileHashes(coverage),
+		PackageStats:  t.calculatePackageStats(coverage, opts.Branch),
+		Tests:         opts.Tests,
+		Bypass:        opts.Bypass,
+		Debt:          opts.Debt,
+		Sequence:      sequence,
+		CommitDepth:   opts.CommitDepth,
+		Suite:         opts.Suite,
 	}
 
 	// Add debug logging context to metadata
 	if entry.Metadata == nil {
 		entry.Metadata = make(map[string]string)
 	}
-	entry.Metadata["tracker_version"] = "1.0"
 	entry.Metadata["storage_path"] = t.config.StoragePath
 	entry.Metadata["record_timestamp"] = time.Now().Format(time.RFC3339)
 
@@ -289,8 +367,9 @@ func (t *Tracker) GetTrend(ctx context.Context, options ...TrendOption) (*TrendD
 	}, nil
 }
 
-// GetLatestEntry returns the most recent coverage entry
-func (t *Tracker) GetLatestEntry(ctx context.Context, branch string) (*Entry, error) {
+// GetLatestEntry returns the most recent coverage entry of the last week. Trend options such as
+// WithTrendEnvironment narrow down the entries considered.
+func (t *Tracker) GetLatestEntry(ctx context.Context, branch string, options ...TrendOption) (*Entry, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -302,6 +381,9 @@ func (t *Tracker) GetLatestEntry(ctx context.Context, branch string) (*Entry, er
 		Days:      7,
 		MaxPoints: 1,
 	}
+	for _, opt := range options {
+		opt(opts)
+	}
 
 	entries, err := t.loadEntries(ctx, opts)
 	if err != nil {
@@ -315,6 +397,184 @@ func (t *Tracker) GetLatestEntry(ctx context.Context, branch string) (*Entry, er
 	return &entries[0], nil
 }
 
+// FindEntry returns the most recent unit test entry recorded for any of the given refs.
+// A ref matches an entry's commit SHA exactly or as an abbreviated prefix of at
+// least minCommitPrefix characters; branch names are only considered when no
+// commit matches.
+func (t *Tracker) FindEntry(ctx context.Context, refs ...string) (*Entry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	entries, err := t.loadAllEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load entries: %w", err)
+	}
+
+	entries = slices.DeleteFunc(entries, func(entry Entry) bool { return entry.Suite != "" })
+	for i := range entries {
+		if matchesCommit(entries[i].CommitSHA, refs) {
+			return &entries[i], nil
+		}
+	}
+	for i := range entries {
+		if slices.Contains(refs, entries[i].Branch) {
+			return &entries[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoEntriesFound, strings.Join(refs, ", "))
+}
+
+// Annotate adds a note to every entry recorded for commit, on any branch. The commit matches
+// exactly or as an abbreviated prefix of at least minCommitPrefix characters. It returns the
+// number of annotated entries.
+func (t *Tracker) Annotate(ctx context.Context, commit string, annotation Annotation) (int, error) {
+	if strings.TrimSpace(commit) == "" || strings.TrimSpace(annotation.Note) == "" {
+		return 0, ErrAnnotationInvalid
+	}
+	if annotation.Timestamp.IsZero() {
+		annotation.Timestamp = time.Now()
+	}
+
+	files, err := t.EntryFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	annotated := 0
+	for _, file := range files {
+		select {
+		case <-ctx.Done():
+			return annotated, ctx.Err()
+		default:
+		}
+
+		data, err := os.ReadFile(file) //nolint:gosec // File path from controlled directory listing
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := EntrySchema.Decode(data, &entry); err != nil || !matchesCommit(entry.CommitSHA, []string{commit}) {
+			continue
+		}
+
+		entry.SchemaVersion = EntrySchema.Version()
+		entry.Annotations = append(entry.Annotations, annotation)
+		updated, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return annotated, fmt.Errorf("failed to marshal annotated entry '%s': %w", file, err)
+		}
+		if err := os.WriteFile(file, updated, 0o600); err != nil {
+			return annotated, fmt.Errorf("failed to write annotated entry '%s': %w", file, err)
+		}
+		annotated++
+	}
+
+	if annotated == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoEntriesFound, commit)
+	}
+	return annotated, nil
+}
+
+// Branches returns the branches with entries in the history, with the time of their newest entry
+func (t *Tracker) Branches(ctx context.Context) (map[string]time.Time, error) {
+	entries, err := t.loadAllEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load entries: %w", err)
+	}
+
+	branches := make(map[string]time.Time)
+	for _, entry := range entries {
+		branch := entry.Branch
+		if branch == "" {
+			branch = DefaultBranch
+		}
+		if newest, ok := branches[branch]; !ok || entry.Timestamp.After(newest) {
+			branches[branch] = entry.Timestamp
+		}
+	}
+	return branches, nil
+}
+
+// PruneBranches removes every entry of the given branches. With an archive directory the
+// entries are moved there instead, under the same <owner>/<repo>/<branch> layout as in the
+// storage directory. It returns the number of pruned entries.
+func (t *Tracker) PruneBranches(ctx context.Context, branches []string, archiveDir string) (int, error) {
+	files, err := t.EntryFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, file := range files {
+		select {
+		case <-ctx.Done():
+			return pruned, ctx.Err()
+		default:
+		}
+
+		data, err := os.ReadFile(file) //nolint:gosec // File path from controlled directory listing
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := EntrySchema.Decode(data, &entry); err != nil {
+			continue
+		}
+		branch := entry.Branch
+		if branch == "" {
+			branch = DefaultBranch
+		}
+		if !slices.Contains(branches, branch) {
+			continue
+		}
+
+		if archiveDir != "" {
+			if err := t.archiveEntry(file, data, archiveDir); err != nil {
+				return pruned, err
+			}
+		}
+		if err := os.Remove(file); err != nil {
+			return pruned, fmt.Errorf("failed to remove entry '%s': %w", file, err)
+		}
+		_ = os.Remove(filepath.Dir(file)) // Only succeeds once the branch directory is empty
+		pruned++
+	}
+	return pruned, nil
+}
+
+// archiveEntry writes the data of an entry file to the same path under the archive directory
+func (t *Tracker) archiveEntry(file string, data []byte, archiveDir string) error {
+	rel, err := filepath.Rel(t.config.StoragePath, file)
+	if err != nil {
+		return fmt.Errorf("failed to locate entry '%s': %w", file, err)
+	}
+	target := filepath.Join(archiveDir, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0o600); err != nil {
+		return fmt.Errorf("failed to archive entry '%s': %w", file, err)
+	}
+	return nil
+}
+
+// matchesCommit reports whether sha equals or starts with one of the refs
+func matchesCommit(sha string, refs []string) bool {
+	if sha == "" {
+		return false
+	}
+	for _, ref := range refs {
+		if ref == sha || (len(ref) >= minCommitPrefix && strings.HasPrefix(sha, ref)) {
+			return true
+		}
+	}
+	return false
+}
+
 // Cleanup removes old entries based on retention policy
 func (t *Tracker) Cleanup(ctx context.Context) error {
 	select {
@@ -371,7 +631,8 @@ func (t *Tracker) GetStatistics(ctx context.Context) (*Statistics, error) {
 		TotalEntries:   len(entries),
 		UniqueProjects: make(map[string]int),
 		UniqueBranches: make(map[string]int),
-		StorageSize:    t.calculateStorageSize(),
+		UniqueSuites:   make(map[string]int),
+		StorageSize:    t.calculateStorageSize(ctx),
 		GeneratedAt:    time.Now(),
 	}
 
@@ -384,6 +645,9 @@ func (t *Tracker) GetStatistics(ctx context.Context) (*Statistics, error) {
 				stats.UniqueProjects[project]++
 			}
 			stats.UniqueBranches[entry.Branch]++
+			if entry.Suite != "" {
+				stats.UniqueSuites[entry.Suite]++
+			}
 		}
 	}
 
@@ -424,9 +688,17 @@ func (t *Tracker) saveEntry(ctx context.Context, entry *Entry) error {
 		return fmt.Errorf("failed to ensure storage directory '%s': %w", t.config.StoragePath, err)
 	}
 
-	// Generate filename and full path
+	if err := t.migrate(ctx); err != nil {
+		return err
+	}
+
+	// Generate filename and full path inside the repository and branch namespace
+	dir := t.entryDir(entry)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create history directory '%s': %w", dir, err)
+	}
 	filename := t.getEntryFilename(entry)
-	filePath := filepath.Join(t.config.StoragePath, filename)
+	filePath := filepath.Join(dir, filename)
 
 	// Add detailed path information to metadata for debugging
 	if entry.Metadata == nil {
@@ -471,15 +743,24 @@ func (t *Tracker) saveEntry(ctx context.Context, entry *Entry) error {
 
 // loadEntries loads entries based on trend options
 func (t *Tracker) loadEntries(ctx context.Context, opts *TrendOptions) ([]Entry, error) {
-	entries, err := t.loadAllEntries(ctx)
+	if err := t.prepareStorage(ctx); err != nil {
+		return nil, err
+	}
+
+	dir := t.entryDir(&Entry{Repository: t.config.Repository, Branch: opts.Branch})
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob entry files: %w", err)
+	}
+	entries, err := readEntries(ctx, files)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter by branch
+	// Filter by branch, suite and environment
 	var filtered []Entry
 	for _, entry := range entries {
-		if entry.Branch == opts.Branch {
+		if entry.Branch == opts.Branch && entry.Suite == opts.Suite && runenv.Matches(entry.Metadata, opts.Environment) {
 			filtered = append(filtered, entry)
 		}
 	}
@@ -501,17 +782,31 @@ func (t *Tracker) loadEntries(ctx context.Context, opts *TrendOptions) ([]Entry,
 	return recent, nil
 }
 
-// loadAllEntries loads all entries from storage
+// loadAllEntries loads all entries of the configured repository from storage
 func (t *Tracker) loadAllEntries(ctx context.Context) ([]Entry, error) {
-	if err := t.ensureStorageDir(); err != nil {
-		return nil, fmt.Errorf("failed to ensure storage directory: %w", err)
+	files, err := t.EntryFiles(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return readEntries(ctx, files)
+}
 
-	files, err := filepath.Glob(filepath.Join(t.config.StoragePath, "*.json"))
+// EntryFiles returns the paths of all entry files of the configured repository, moving
+// entries left in the flat layout of earlier versions into their namespace first
+func (t *Tracker) EntryFiles(ctx context.Context) ([]string, error) {
+	if err := t.prepareStorage(ctx); err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(t.repositoryDir(t.config.Repository), "*", "*.json"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to glob entry files: %w", err)
 	}
+	return files, nil
+}
 
+// readEntries reads the given entry files, newest entry first, in the order of orderEntries
+func readEntries(ctx context.Context, files []string) ([]Entry, error) {
 	entries := make([]Entry, 0, len(files))
 	for _, file := range files {
 		select {
@@ -526,27 +821,44 @@ func (t *Tracker) loadAllEntries(ctx context.Context) ([]Entry, error) {
 		}
 
 		var entry Entry
-		if err := json.Unmarshal(data, &entry); err != nil {
+		if err := EntrySchema.Decode(data, &entry); err != nil {
 			continue // Skip corrupted files
 		}
 
 		entries = append(entries, entry)
 	}
 
-	// Sort by timestamp (newest first)
-	slices.SortFunc(entries, func(a, b Entry) int {
-		return b.Timestamp.Compare(a.Timestamp)
-	})
+	orderEntries(entries, time.Now())
 
 	return entries, nil
 }
 
+// nextSequence returns the sequence number of the next run of the branch, one past the highest
+// recorded
+func (t *Tracker) nextSequence(ctx context.Context, branch string) (int64, error) {
+	dir := t.entryDir(&Entry{Repository: t.config.Repository, Branch: branch})
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to glob entry files: %w", err)
+	}
+	entries, err := readEntries(ctx, files)
+	if err != nil {
+		return 0, err
+	}
+
+	var last int64
+	for _, entry := range entries {
+		last = max(last, entry.Sequence)
+	}
+	return last + 1, nil
+}
+
 // saveAllEntries saves all entries to storage (used for cleanup)
 func (t *Tracker) saveAllEntries(ctx context.Context, entries []Entry) error {
 	// Remove existing files
-	files, err := filepath.Glob(filepath.Join(t.config.StoragePath, "*.json"))
+	files, err := t.EntryFiles(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to glob existing files: %w", err)
+		return fmt.Errorf("failed to list existing files: %w", err)
 	}
 
 	for _, file := range files {
@@ -571,6 +883,99 @@ func (t *Tracker) saveAllEntries(ctx context.Context, entries []Entry) error {
 
 // Helper functions
 
+// prepareStorage ensures the storage directory exists and holds no entries in the flat layout
+func (t *Tracker) prepareStorage(ctx context.Context) error {
+	if err := t.ensureStorageDir(); err != nil {
+		return fmt.Errorf("failed to ensure storage directory: %w", err)
+	}
+	return t.migrate(ctx)
+}
+
+// migrate moves entries stored directly in the storage directory, the layout used before
+// entries were namespaced, into their repository and branch directory. It runs once per tracker.
+func (t *Tracker) migrate(ctx context.Context) error {
+	t.migrateOnce.Do(func() {
+		t.migrateErr = t.migrateFlatLayout(ctx)
+	})
+	return t.migrateErr
+}
+
+// migrateFlatLayout moves every flat entry file into the namespace of its repository and branch.
+// Entries without a repository are attributed to their project metadata when it names one,
+// otherwise to the configured repository. Unreadable files are left where they are.
+func (t *Tracker) migrateFlatLayout(ctx context.Context) error {
+	files, err := filepath.Glob(filepath.Join(t.config.StoragePath, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to glob flat entry files: %w", err)
+	}
+
+	for _, file := range files {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		data, err := os.ReadFile(file) //nolint:gosec // File path from controlled directory listing
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := EntrySchema.Decode(data, &entry); err != nil {
+			continue
+		}
+
+		if entry.Repository == "" {
+			entry.Repository = t.config.Repository
+			if project := entry.Metadata["project"]; strings.Count(project, "/") == 1 {
+				entry.Repository = project
+			}
+		}
+
+		dir := t.entryDir(&entry)
+		target := filepath.Join(dir, filepath.Base(file))
+		if _, err := os.Stat(target); err == nil {
+			// Already migrated, e.g. restored again from an artifact
+			_ = os.Remove(file)
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create history directory '%s': %w", dir, err)
+		}
+		migrated, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal migrated entry '%s': %w", file, err)
+		}
+		if err := os.WriteFile(target, migrated, 0o600); err != nil {
+			return fmt.Errorf("failed to write migrated entry '%s': %w", target, err)
+		}
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove migrated entry '%s': %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// repositoryDir returns the directory holding the branch directories of a repository
+// (owner/repo); entries without a repository keep their branch directories at the root
+func (t *Tracker) repositoryDir(repository string) string {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" {
+		return t.config.StoragePath
+	}
+	return filepath.Join(t.config.StoragePath, t.sanitizeBranchName(owner), t.sanitizeBranchName(repo))
+}
+
+// entryDir returns the directory an entry is stored in: <storage>/<owner>/<repo>/<branch>
+func (t *Tracker) entryDir(entry *Entry) string {
+	branch := entry.Branch
+	if branch == "" {
+		branch = DefaultBranch
+	}
+	return filepath.Join(t.repositoryDir(entry.Repository), t.sanitizeBranchName(branch))
+}
+
 func (t *Tracker) ensureStorageDir() error {
 	if t.config.StoragePath == "" {
 		return ErrStoragePathEmpty
@@ -695,6 +1100,7 @@ func (t *Tracker) calculateSummary(entries []Entry) *TrendSummary {
 	}
 
 	var total float64
+	skewed := 0
 	minCov := entries[0].Coverage.Percentage
 	maxCov := entries[0].Coverage.Percentage
 
@@ -706,6 +1112,9 @@ func (t *Tracker) calculateSummary(entries []Entry) *TrendSummary {
 		if entry.Coverage.Percentage > maxCov {
 			maxCov = entry.Coverage.Percentage
 		}
+		if !entry.SkewedTimestamp.IsZero() {
+			skewed++
+		}
 	}
 
 	trend := "stable"
@@ -728,6 +1137,7 @@ func (t *Tracker) calculateSummary(entries []Entry) *TrendSummary {
 		CurrentTrend:      trend,
 		TrendStrength:     "moderate",
 		StabilityScore:    85.0,
+		SkewedEntries:     skewed,
 	}
 }
 
@@ -850,9 +1260,9 @@ func (t *Tracker) generatePrediction(entries []Entry) *Prediction {
 	}
 }
 
-func (t *Tracker) calculateStorageSize() int64 {
+func (t *Tracker) calculateStorageSize(ctx context.Context) int64 {
 	var size int64
-	files, err := filepath.Glob(filepath.Join(t.config.StoragePath, "*.json"))
+	files, err := t.EntryFiles(ctx)
 	if err != nil {
 		return 0
 	}
@@ -873,6 +1283,7 @@ type Statistics struct {
 	NewestEntry    time.Time      `json:"newest_entry"`
 	UniqueProjects map[string]int `json:"unique_projects"`
 	UniqueBranches map[string]int `json:"unique_branches"`
+	UniqueSuites   map[string]int `json:"unique_suites,omitempty"` // Entries of suites other than the unit tests
 	StorageSize    int64          `json:"storage_size"`
 	GeneratedAt    time.Time      `json:"generated_at"`
 }
@@ -884,6 +1295,13 @@ type RecordOptions struct {
 	CommitURL string
 	Metadata  map[string]string
 	BuildInfo *BuildInfo
+	Tests     *testrun.Summary
+	Bypass    string
+	Debt      *Debt
+	// CommitDepth is the position of the commit in the commit graph, if known
+	CommitDepth int
+	// Suite names the test run the coverage was captured from, empty for unit tests
+	Suite string
 }
 
 // TrendOptions contains configuration options for generating coverage trends.
@@ -891,6 +1309,10 @@ type TrendOptions struct {
 	Branch    string
 	Days      int
 	MaxPoints int
+	// Environment restricts the trend to entries whose environment metadata matches every pair
+	Environment map[string]string
+	// Suite restricts the trend to the entries of a suite, by default the unit test entries
+	Suite string
 }
 
 type (
@@ -925,6 +1347,18 @@ func WithMetadata(key, value string) Option {
 	}
 }
 
+// WithEnvironment records the environment of the run in the metadata, keyed as runenv.Keys.
+func WithEnvironment(env map[string]string) Option {
+	return func(opts *RecordOptions) {
+		if opts.Metadata == nil {
+			opts.Metadata = make(map[string]string)
+		}
+		for key, value := range env {
+			opts.Metadata[key] = value
+		}
+	}
+}
+
 // WithBuildInfo sets build information for recording coverage data.
 func WithBuildInfo(info *BuildInfo) Option {
 	return func(opts *RecordOptions) {
@@ -932,6 +1366,43 @@ func WithBuildInfo(info *BuildInfo) Option {
 	}
 }
 
+// WithTestRun sets the test results of the run for recording coverage data.
+func WithTestRun(summary *testrun.Summary) Option {
+	return func(opts *RecordOptions) {
+		opts.Tests = summary
+	}
+}
+
+// WithBypass records that an emergency bypass downgraded the gates of the run, and why.
+func WithBypass(reason string) Option {
+	return func(opts *RecordOptions) {
+		opts.Bypass = reason
+	}
+}
+
+// WithDebt sets the coverage debt of the run for recording coverage data.
+func WithDebt(debt *Debt) Option {
+	return func(opts *RecordOptions) {
+		opts.Debt = debt
+	}
+}
+
+// WithCommitDepth sets the number of commits reachable from the commit, ordering the run among
+// runs recorded concurrently.
+func WithCommitDepth(depth int) Option {
+	return func(opts *RecordOptions) {
+		opts.CommitDepth = depth
+	}
+}
+
+// WithSuite records the coverage as captured from a suite other than the unit tests, such as
+// bench, so it is tracked apart from them.
+func WithSuite(suite string) Option {
+	return func(opts *RecordOptions) {
+		opts.Suite = suite
+	}
+}
+
 // WithTrendBranch sets the branch name for generating coverage trends.
 func WithTrendBranch(branch string) TrendOption {
 	return func(opts *TrendOptions) {
@@ -946,6 +1417,22 @@ func WithTrendDays(days int) TrendOption {
 	}
 }
 
+// WithTrendEnvironment restricts trend analysis to runs of an environment, as parsed by
+// runenv.ParseFilter.
+func WithTrendEnvironment(filter map[string]string) TrendOption {
+	return func(opts *TrendOptions) {
+		opts.Environment = filter
+	}
+}
+
+// WithTrendSuite restricts trend analysis to the entries of a suite, such as bench, instead of
+// the unit test entries.
+func WithTrendSuite(suite string) TrendOption {
+	return func(opts *TrendOptions) {
+		opts.Suite = suite
+	}
+}
+
 // WithMaxDataPoints sets the maximum number of data points in trend analysis.
 func WithMaxDataPoints(maxPoints int) TrendOption {
 	return func(opts *TrendOptions) {
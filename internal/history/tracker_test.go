@@ -2,9 +2,12 @@ package history
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/mrz1836/go-coverage/internal/parser"
+	"github.com/mrz1836/go-coverage/internal/runenv"
+	"github.com/mrz1836/go-coverage/internal/testrun"
 )
 
 func TestNew(t *testing.T) {
@@ -69,7 +74,7 @@ func TestRecord(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify file was created
-	files, err := filepath.Glob(filepath.Join(tempDir, "*.json"))
+	files, err := filepath.Glob(filepath.Join(tempDir, DefaultBranch, "*.json"))
 	require.NoError(t, err)
 	assert.Len(t, files, 1)
 }
@@ -193,6 +198,56 @@ func TestGetLatestEntryNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "no entries found for branch: nonexistent")
 }
 
+func TestFindEntry(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	release := createTestCoverage()
+	release.Percentage = 70.0
+	require.NoError(t, tracker.Record(ctx, release, WithBranch(DefaultBranch), WithCommit("0123456789abcdef", "")))
+
+	time.Sleep(10 * time.Millisecond)
+
+	latest := createTestCoverage()
+	latest.Percentage = 80.0
+	require.NoError(t, tracker.Record(ctx, latest, WithBranch(DefaultBranch), WithCommit("fedcba9876543210", "")))
+
+	t.Run("full commit SHA", func(t *testing.T) {
+		entry, err := tracker.FindEntry(ctx, "0123456789abcdef")
+		require.NoError(t, err)
+		assert.InDelta(t, 70.0, entry.Coverage.Percentage, 0.001)
+	})
+
+	t.Run("abbreviated commit SHA", func(t *testing.T) {
+		entry, err := tracker.FindEntry(ctx, "0123456")
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789abcdef", entry.CommitSHA)
+	})
+
+	t.Run("prefix too short", func(t *testing.T) {
+		_, err := tracker.FindEntry(ctx, "0123")
+		require.ErrorIs(t, err, ErrNoEntriesFound)
+	})
+
+	t.Run("branch falls back to the newest entry", func(t *testing.T) {
+		entry, err := tracker.FindEntry(ctx, DefaultBranch)
+		require.NoError(t, err)
+		assert.Equal(t, "fedcba9876543210", entry.CommitSHA)
+	})
+
+	t.Run("commit match wins over branch", func(t *testing.T) {
+		entry, err := tracker.FindEntry(ctx, DefaultBranch, "0123456789abcdef")
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789abcdef", entry.CommitSHA)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		_, err := tracker.FindEntry(ctx, "v9.9.9")
+		require.ErrorIs(t, err, ErrNoEntriesFound)
+		assert.Contains(t, err.Error(), "v9.9.9")
+	})
+}
+
 func TestCleanup(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "history_test_*")
 	require.NoError(t, err)
@@ -220,7 +275,7 @@ func TestCleanup(t *testing.T) {
 	}
 
 	// Verify all entries exist
-	files, err := filepath.Glob(filepath.Join(tempDir, "*.json"))
+	files, err := filepath.Glob(filepath.Join(tempDir, DefaultBranch, "*.json"))
 	require.NoError(t, err)
 	assert.Len(t, files, 3)
 
@@ -229,7 +284,7 @@ func TestCleanup(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify cleanup kept only MaxEntries
-	files, err = filepath.Glob(filepath.Join(tempDir, "*.json"))
+	files, err = filepath.Glob(filepath.Join(tempDir, DefaultBranch, "*.json"))
 	require.NoError(t, err)
 	assert.Len(t, files, 2)
 }
@@ -261,7 +316,7 @@ func TestCleanupDisabled(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify both entries still exist
-	files, err := filepath.Glob(filepath.Join(tempDir, "*.json"))
+	files, err := filepath.Glob(filepath.Join(tempDir, DefaultBranch, "*.json"))
 	require.NoError(t, err)
 	assert.Len(t, files, 2)
 }
@@ -348,7 +403,7 @@ func TestLegacyAdd(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify entry was created
-	files, err := filepath.Glob(filepath.Join(tempDir, "*.json"))
+	files, err := filepath.Glob(filepath.Join(tempDir, DefaultBranch, "*.json"))
 	require.NoError(t, err)
 	assert.Len(t, files, 1)
 }
@@ -444,6 +499,139 @@ func TestBuildInfo(t *testing.T) {
 	assert.Equal(t, buildInfo.BuildNumber, latest.BuildInfo.BuildNumber)
 }
 
+func TestRecordTestRun(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	tests := &testrun.Summary{Format: testrun.FormatGoTestJSON, Tests: 42, Packages: 3, Seconds: 12.5}
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithTestRun(tests)))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("feature")))
+
+	latest, err := tracker.GetLatestEntry(ctx, DefaultBranch)
+	require.NoError(t, err)
+	assert.Equal(t, tests, latest.Tests)
+
+	latest, err = tracker.GetLatestEntry(ctx, "feature")
+	require.NoError(t, err)
+	assert.Nil(t, latest.Tests)
+}
+
+func TestRecordBypass(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithBypass("commit message contains [hotfix]")))
+
+	latest, err := tracker.GetLatestEntry(ctx, DefaultBranch)
+	require.NoError(t, err)
+	assert.Equal(t, "commit message contains [hotfix]", latest.Bypass)
+}
+
+func TestRecordDebt(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	debt := &Debt{Uncovered: 120, Weighted: 187.5}
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithDebt(debt)))
+
+	latest, err := tracker.GetLatestEntry(ctx, DefaultBranch)
+	require.NoError(t, err)
+	assert.Equal(t, debt, latest.Debt)
+}
+
+func TestTrendEnvironment(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir()})
+	ctx := context.Background()
+
+	linux := map[string]string{runenv.KeyGoVersion: "go1.25.1", runenv.KeyOS: "linux"}
+	darwin := map[string]string{runenv.KeyGoVersion: "go1.24.6", runenv.KeyOS: "darwin"}
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithEnvironment(linux)))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithEnvironment(darwin)))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch)))
+
+	trend, err := tracker.GetTrend(ctx)
+	require.NoError(t, err)
+	assert.Len(t, trend.Entries, 3)
+
+	trend, err = tracker.GetTrend(ctx, WithTrendEnvironment(map[string]string{runenv.KeyOS: "linux"}))
+	require.NoError(t, err)
+	require.Len(t, trend.Entries, 1)
+	assert.Equal(t, "go1.25.1", trend.Entries[0].Metadata[runenv.KeyGoVersion])
+
+	trend, err = tracker.GetTrend(ctx, WithTrendEnvironment(map[string]string{runenv.KeyGoVersion: "go1.2*"}))
+	require.NoError(t, err)
+	assert.Len(t, trend.Entries, 2, "entries without an environment match no filter")
+}
+
+func TestPruneBranches(t *testing.T) {
+	storage := t.TempDir()
+	tracker := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/repo"})
+	ctx := context.Background()
+
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch)))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("feature/login")))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("feature/login")))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("fix-typo")))
+
+	branches, err := tracker.Branches(ctx)
+	require.NoError(t, err)
+	assert.Len(t, branches, 3)
+	assert.False(t, branches["feature/login"].IsZero())
+
+	archive := filepath.Join(t.TempDir(), "archive")
+	pruned, err := tracker.PruneBranches(ctx, []string{"feature/login"}, archive)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+	archived, err := filepath.Glob(filepath.Join(archive, "owner", "repo", "feature-login", "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, archived, 2)
+	assert.NoDirExists(t, filepath.Join(storage, "owner", "repo", "feature-login"))
+
+	pruned, err = tracker.PruneBranches(ctx, []string{"fix-typo", "gone"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	branches, err = tracker.Branches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultBranch}, slices.Collect(maps.Keys(branches)))
+}
+
+func TestAnnotate(t *testing.T) {
+	tracker := NewWithConfig(&Config{StoragePath: t.TempDir(), Repository: "owner/repo"})
+	ctx := context.Background()
+
+	const commit = "0123456789abcdef"
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithCommit(commit, "")))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch("feature"), WithCommit(commit, "")))
+	require.NoError(t, tracker.Record(ctx, createTestCoverage(), WithBranch(DefaultBranch), WithCommit("fedcba9876543210", "")))
+
+	count, err := tracker.Annotate(ctx, commit[:7], Annotation{Note: "migrated to testify", Author: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "every branch recording the commit is annotated")
+
+	entry, err := tracker.FindEntry(ctx, commit)
+	require.NoError(t, err)
+	require.Len(t, entry.Annotations, 1)
+	assert.Equal(t, "migrated to testify", entry.Annotations[0].Note)
+	assert.Equal(t, "alice", entry.Annotations[0].Author)
+	assert.False(t, entry.Annotations[0].Timestamp.IsZero())
+
+	_, err = tracker.Annotate(ctx, commit, Annotation{Note: "second note"})
+	require.NoError(t, err)
+	entry, err = tracker.FindEntry(ctx, commit)
+	require.NoError(t, err)
+	assert.Len(t, entry.Annotations, 2)
+
+	other, err := tracker.FindEntry(ctx, "fedcba9876543210")
+	require.NoError(t, err)
+	assert.Empty(t, other.Annotations)
+
+	_, err = tracker.Annotate(ctx, "abc", Annotation{Note: "too short"})
+	require.ErrorIs(t, err, ErrNoEntriesFound)
+	_, err = tracker.Annotate(ctx, commit, Annotation{Note: " "})
+	require.ErrorIs(t, err, ErrAnnotationInvalid)
+}
+
 func TestPackageStats(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "history_test_*")
 	require.NoError(t, err)
@@ -672,6 +860,92 @@ func TestMomentumCalculation(t *testing.T) {
 	assert.InDelta(t, 0.0, momentum, 0.001)
 }
 
+func TestRepositoryNamespace(t *testing.T) {
+	storage := t.TempDir()
+	ctx := context.Background()
+	ours := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/repo", RetentionDays: 30, MaxEntries: 100})
+	theirs := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/other", RetentionDays: 30, MaxEntries: 100})
+
+	require.NoError(t, ours.Record(ctx, createTestCoverage(), WithBranch("feature/x"), WithCommit("aaaaaaaa1", "")))
+	require.NoError(t, ours.Record(ctx, createTestCoverage(), WithBranch("main"), WithCommit("bbbbbbbb1", "")))
+	require.NoError(t, theirs.Record(ctx, createTestCoverage(), WithBranch("main"), WithCommit("cccccccc1", "")))
+
+	files, err := filepath.Glob(filepath.Join(storage, "owner", "repo", "feature-x", "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	files, err = ours.EntryFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	latest, err := ours.GetLatestEntry(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, "bbbbbbbb1", latest.CommitSHA)
+	assert.Equal(t, "owner/repo", latest.Repository)
+
+	_, err = ours.FindEntry(ctx, "cccccccc1")
+	require.ErrorIs(t, err, ErrNoEntriesFound)
+
+	stats, err := theirs.GetStatistics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalEntries)
+}
+
+func TestMigrateFlatLayout(t *testing.T) {
+	storage := t.TempDir()
+	ctx := context.Background()
+
+	writeFlat := func(name string, entry Entry) {
+		data, err := json.Marshal(entry)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(storage, name), data, 0o600))
+	}
+	now := time.Now()
+	writeFlat("1-main-aaaa.json", Entry{Timestamp: now, Branch: "main", CommitSHA: "aaaa", Coverage: createTestCoverage()})
+	writeFlat("2-main-bbbb.json", Entry{
+		Timestamp: now.Add(-time.Minute), Branch: "main", CommitSHA: "bbbb", Coverage: createTestCoverage(),
+		Metadata: map[string]string{"project": "owner/other"},
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(storage, "corrupt.json"), []byte("{"), 0o600))
+
+	tracker := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/repo", RetentionDays: 30, MaxEntries: 100})
+	latest, err := tracker.GetLatestEntry(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa", latest.CommitSHA)
+	assert.Equal(t, "owner/repo", latest.Repository)
+
+	assert.FileExists(t, filepath.Join(storage, "owner", "repo", "main", "1-main-aaaa.json"))
+	assert.FileExists(t, filepath.Join(storage, "owner", "other", "main", "2-main-bbbb.json"))
+	assert.NoFileExists(t, filepath.Join(storage, "1-main-aaaa.json"))
+	assert.FileExists(t, filepath.Join(storage, "corrupt.json"))
+
+	// A restored copy of an already migrated entry is dropped
+	writeFlat("1-main-aaaa.json", Entry{Timestamp: now, Branch: "main", CommitSHA: "aaaa", Coverage: createTestCoverage()})
+	files, err := NewWithConfig(&Config{StoragePath: storage, Repository: "owner/repo"}).EntryFiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.NoFileExists(t, filepath.Join(storage, "1-main-aaaa.json"))
+}
+
+func TestEntrySchema(t *testing.T) {
+	legacy := `{"timestamp":"2024-01-02T03:04:05Z","branch":"main","commit_sha":"abc",` +
+		`"coverage":{"percentage":80},"metadata":{"tracker_version":"1.0","project":"owner/repo"}}`
+
+	var entry Entry
+	require.NoError(t, EntrySchema.Decode([]byte(legacy), &entry))
+	assert.Equal(t, EntrySchema.Version(), entry.SchemaVersion)
+	assert.Equal(t, map[string]string{"project": "owner/repo"}, entry.Metadata)
+	assert.InDelta(t, 80.0, entry.Coverage.Percentage, 0.001)
+
+	storage := t.TempDir()
+	tracker := NewWithConfig(&Config{StoragePath: storage, RetentionDays: 30, MaxEntries: 10})
+	require.NoError(t, tracker.Record(context.Background(), createTestCoverage(), WithBranch("main")))
+	latest, err := tracker.GetLatestEntry(context.Background(), "main")
+	require.NoError(t, err)
+	assert.Equal(t, EntrySchema.Version(), latest.SchemaVersion)
+	assert.NotContains(t, latest.Metadata, "tracker_version")
+}
+
 func TestConfigurationOptions(t *testing.T) {
 	// Test record options
 	opts := &RecordOptions{}
@@ -0,0 +1,149 @@
+// Package httpclient builds HTTP clients that work on restricted networks (proxies, TLS interception)
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/fixture"
+	"github.com/mrz1836/go-coverage/internal/logger"
+	"github.com/mrz1836/go-coverage/internal/redact"
+)
+
+// Static error definitions
+var (
+	ErrInvalidProxyURL = errors.New("invalid proxy URL")
+	ErrInvalidCABundle = errors.New("CA bundle contains no valid PEM certificates")
+	ErrNetworkDisabled = errors.New("network access is disabled in offline mode")
+)
+
+// Options configures the HTTP clients created by this package
+type Options struct {
+	Timeout            time.Duration    // Overall request timeout (0 = no timeout)
+	ProxyURL           string           // Explicit proxy URL; empty uses HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	CABundle           string           // Path to a PEM bundle trusted in addition to the system roots
+	InsecureSkipVerify bool             // Disable TLS certificate verification (never use in production)
+	Logger             logger.Logger    // Logger used for security warnings (defaults to logger.NewFromEnv)
+	Offline            bool             // Refuse every request with ErrNetworkDisabled
+	RecordDir          string           // Record every response into this fixture directory
+	ReplayDir          string           // Answer every request from this fixture directory instead of the network
+	Redactor           *redact.Redactor // Masks secrets in recorded fixtures (defaults to the built-in patterns)
+}
+
+// New creates an HTTP client honoring proxy and TLS settings
+func New(opts Options) (*http.Client, error) {
+	if opts.ReplayDir != "" {
+		return &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: fixture.NewPlayer(opts.ReplayDir),
+		}, nil
+	}
+	if opts.Offline {
+		return &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: offlineTransport{},
+		}, nil
+	}
+
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if opts.RecordDir != "" {
+		roundTripper = fixture.NewRecorder(opts.RecordDir, transport, opts.Redactor)
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: roundTripper,
+	}, nil
+}
+
+// NewTransport creates an HTTP transport honoring proxy and TLS settings.
+// Without an explicit proxy URL the standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables are used.
+func NewTransport(opts Options) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		base = &http.Transport{}
+	}
+	transport := base.Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidProxyURL, opts.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CABundle == "" && !opts.InsecureSkipVerify {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+
+	if opts.CABundle != "" {
+		pool, err := loadCertPool(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.InsecureSkipVerify {
+		log := opts.Logger
+		if log == nil {
+			log = logger.NewFromEnv()
+		}
+		log.Warn("⚠️  TLS certificate verification is DISABLED - connections are vulnerable to interception. " +
+			"Configure a CA bundle instead of skipping verification.")
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // G402: explicitly requested by configuration and loudly warned
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// loadCertPool returns the system cert pool extended with the certificates in the PEM bundle
+func loadCertPool(bundlePath string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(filepath.Clean(bundlePath))
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCABundle, bundlePath)
+	}
+
+	return pool, nil
+}
+
+// offlineTransport fails every request without touching the network
+type offlineTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNetworkDisabled, req.Method, req.URL.Redacted())
+}
@@ -0,0 +1,190 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-coverage/internal/logger"
+)
+
+// writeServerCA writes the certificate of a TLS test server to a PEM file
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+	return path
+}
+
+// get performs a GET request against the URL with the given client
+func get(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestNewDefaults(t *testing.T) {
+	client, err := New(Options{Timeout: 5 * time.Second})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, client.Timeout)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, transport.Proxy, "environment proxy settings should be honored")
+	if transport.TLSClientConfig != nil {
+		assert.Nil(t, transport.TLSClientConfig.RootCAs, "system roots should be used by default")
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	t.Run("requests are routed through the explicit proxy", func(t *testing.T) {
+		var proxiedHost string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// A forward proxy receives the absolute target URL
+			proxiedHost = r.URL.Host
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("via proxy"))
+		}))
+		defer proxy.Close()
+
+		client, err := New(Options{Timeout: 5 * time.Second, ProxyURL: proxy.URL})
+		require.NoError(t, err)
+
+		resp, err := get(t, client, "http://coverage.example.invalid/data.json")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "coverage.example.invalid", proxiedHost)
+	})
+
+	t.Run("invalid proxy URL", func(t *testing.T) {
+		for _, proxyURL := range []string{"::not-a-url", "proxy.local:8080"} {
+			_, err := NewTransport(Options{ProxyURL: proxyURL})
+			require.ErrorIs(t, err, ErrInvalidProxyURL, proxyURL)
+		}
+	})
+}
+
+func TestNewTransportCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("untrusted certificate is rejected", func(t *testing.T) {
+		client, err := New(Options{Timeout: 5 * time.Second})
+		require.NoError(t, err)
+
+		resp, err := get(t, client, server.URL)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		require.Error(t, err)
+	})
+
+	t.Run("custom CA bundle is trusted", func(t *testing.T) {
+		client, err := New(Options{Timeout: 5 * time.Second, CABundle: writeServerCA(t, server)})
+		require.NoError(t, err)
+
+		resp, err := get(t, client, server.URL)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("missing bundle file", func(t *testing.T) {
+		_, err := NewTransport(Options{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+		require.Error(t, err)
+	})
+
+	t.Run("bundle without certificates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+		_, err := NewTransport(Options{CABundle: path})
+		require.ErrorIs(t, err, ErrInvalidCABundle)
+	})
+}
+
+func TestNewTransportInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client, err := New(Options{
+		Timeout:            5 * time.Second,
+		InsecureSkipVerify: true,
+		Logger:             logger.NewLogger(&logger.Config{Level: logger.InfoLevel, Format: logger.FormatText, Output: &logs}),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "TLS certificate verification is DISABLED")
+
+	resp, err := get(t, client, server.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewOffline(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(Options{Timeout: 5 * time.Second, Offline: true, ProxyURL: "not a proxy"})
+	require.NoError(t, err, "network settings are irrelevant when offline")
+
+	resp, err := get(t, client, server.URL)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	require.ErrorIs(t, err, ErrNetworkDisabled)
+	assert.Zero(t, hits, "no request should reach the server")
+}
+
+func TestNewRecordReplay(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recording, err := New(Options{Timeout: 5 * time.Second, RecordDir: dir})
+	require.NoError(t, err)
+	resp, err := get(t, recording, server.URL+"/user")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, 1, hits)
+
+	replaying, err := New(Options{Timeout: 5 * time.Second, ReplayDir: dir, Offline: true})
+	require.NoError(t, err)
+	resp, err = get(t, replaying, server.URL+"/user")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var body bytes.Buffer
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"login":"octocat"}`, body.String())
+	assert.Equal(t, 1, hits, "replayed requests should not reach the server")
+}
@@ -0,0 +1,220 @@
+// Package impact determines which packages' tests must run to refresh coverage for a change
+package impact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrListPackages indicates that the package graph could not be loaded with go list
+var ErrListPackages = errors.New("failed to list packages")
+
+// Package is a package of the module along with everything it and its tests import
+type Package struct {
+	ImportPath string   `json:"ImportPath"`
+	Dir        string   `json:"Dir"`
+	Imports    []string `json:"Imports,omitempty"`
+	// TestImports and XTestImports count as dependencies: a test importing a
+	// changed package has to run again even if the package itself does not
+	TestImports  []string `json:"TestImports,omitempty"`
+	XTestImports []string `json:"XTestImports,omitempty"`
+	// TestGoFiles and XTestGoFiles tell packages with tests apart from packages without
+	TestGoFiles  []string `json:"TestGoFiles,omitempty"`
+	XTestGoFiles []string `json:"XTestGoFiles,omitempty"`
+}
+
+// Graph is the reverse import graph of a module's packages
+type Graph struct {
+	packages   map[string]*Package
+	byDir      map[string]string
+	dependents map[string][]string
+}
+
+// Result lists the packages affected by a set of changed files
+type Result struct {
+	// Files are the changed files that were considered
+	Files []string `json:"files"`
+	// Changed are the packages containing changed files
+	Changed []string `json:"changed_packages"`
+	// Affected are the changed packages and every package that transitively imports them
+	Affected []string `json:"affected_packages"`
+	// All is set when a module file changed and every package has to be tested
+	All bool `json:"all"`
+}
+
+// NewGraph builds the reverse import graph of the given packages. Imports of
+// packages outside the set, such as the standard library, are ignored.
+func NewGraph(packages []Package) *Graph {
+	g := &Graph{
+		packages:   make(map[string]*Package, len(packages)),
+		byDir:      make(map[string]string, len(packages)),
+		dependents: make(map[string][]string),
+	}
+	for i := range packages {
+		pkg := &packages[i]
+		g.packages[pkg.ImportPath] = pkg
+		g.byDir[filepath.Clean(pkg.Dir)] = pkg.ImportPath
+	}
+
+	for _, pkg := range g.packages {
+		seen := make(map[string]bool)
+		for _, imports := range [][]string{pkg.Imports, pkg.TestImports, pkg.XTestImports} {
+			for _, imported := range imports {
+				if imported == pkg.ImportPath || seen[imported] || g.packages[imported] == nil {
+					continue
+				}
+				seen[imported] = true
+				g.dependents[imported] = append(g.dependents[imported], pkg.ImportPath)
+			}
+		}
+	}
+	return g
+}
+
+// LoadGraph loads the package graph of the module in dir with go list
+func LoadGraph(ctx context.Context, dir string) (*Graph, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-json=ImportPath,Dir,Imports,TestImports,XTestImports,TestGoFiles,XTestGoFiles", "./...")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %s", ErrListPackages, err, strings.TrimSpace(stderr.String()))
+	}
+	return decodeGraph(bytes.NewReader(output))
+}
+
+// decodeGraph reads the stream of JSON objects printed by go list -json
+func decodeGraph(r io.Reader) (*Graph, error) {
+	var packages []Package
+	decoder := json.NewDecoder(r)
+	for {
+		var pkg Package
+		if err := decoder.Decode(&pkg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrListPackages, err)
+		}
+		packages = append(packages, pkg)
+	}
+	return NewGraph(packages), nil
+}
+
+// Packages returns the import paths of all packages in the graph, sorted
+func (g *Graph) Packages() []string {
+	paths := make([]string, 0, len(g.packages))
+	for path := range g.packages {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// Affected maps files changed relative to root onto packages. A file belongs to the
+// package in its directory, and files under testdata belong to the package above it.
+// Markdown files never affect a package; go.mod, go.sum and go.work affect all of them.
+func (g *Graph) Affected(root string, files []string) *Result {
+	result := &Result{Files: files, Changed: []string{}, Affected: []string{}}
+
+	changed := make(map[string]bool)
+	for _, file := range files {
+		switch filepath.Base(file) {
+		case "go.mod", "go.sum", "go.work", "go.work.sum":
+			result.All = true
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(file), ".md") {
+			continue
+		}
+		if pkg, ok := g.packageForFile(filepath.Join(root, file)); ok {
+			changed[pkg] = true
+		}
+	}
+
+	for pkg := range changed {
+		result.Changed = append(result.Changed, pkg)
+	}
+	slices.Sort(result.Changed)
+
+	if result.All {
+		result.Affected = g.Packages()
+		return result
+	}
+
+	affected := make(map[string]bool, len(changed))
+	queue := slices.Clone(result.Changed)
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if affected[pkg] {
+			continue
+		}
+		affected[pkg] = true
+		queue = append(queue, g.dependents[pkg]...)
+	}
+	for pkg := range affected {
+		result.Affected = append(result.Affected, pkg)
+	}
+	slices.Sort(result.Affected)
+	return result
+}
+
+// Untested returns the files, relative to root, whose package no test binary of the module
+// links: the package has no tests of its own, and no package with tests imports it, directly
+// or through other packages. Files outside the packages of the graph are left out.
+func (g *Graph) Untested(root string, files []string) []string {
+	tested := make(map[string]bool, len(g.packages))
+	var queue []string
+	for path, pkg := range g.packages {
+		if len(pkg.TestGoFiles) > 0 || len(pkg.XTestGoFiles) > 0 {
+			queue = append(queue, path)
+		}
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		pkg := g.packages[path]
+		if tested[path] || pkg == nil {
+			continue
+		}
+		tested[path] = true
+		for _, imports := range [][]string{pkg.Imports, pkg.TestImports, pkg.XTestImports} {
+			queue = append(queue, imports...)
+		}
+	}
+
+	var untested []string
+	for _, file := range files {
+		if pkg, ok := g.packageForFile(filepath.Join(root, file)); ok && !tested[pkg] {
+			untested = append(untested, file)
+		}
+	}
+	return untested
+}
+
+// packageForFile returns the package a file belongs to
+func (g *Graph) packageForFile(path string) (string, bool) {
+	dir := filepath.Dir(filepath.Clean(path))
+	for {
+		if pkg, ok := g.byDir[dir]; ok {
+			return pkg, true
+		}
+		// Fixtures under testdata belong to the package whose tests read them
+		if !slices.Contains(strings.Split(filepath.ToSlash(dir), "/"), "testdata") {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
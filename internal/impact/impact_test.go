@@ -0,0 +1,120 @@
+package impact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() *Graph {
+	return NewGraph([]Package{
+		{ImportPath: "example.com/app", Dir: "/src/app", Imports: []string{"example.com/app/api", "fmt"}},
+		{ImportPath: "example.com/app/api", Dir: "/src/app/api", Imports: []string{"example.com/app/store"}},
+		{ImportPath: "example.com/app/store", Dir: "/src/app/store", Imports: []string{"database/sql"}},
+		{ImportPath: "example.com/app/e2e", Dir: "/src/app/e2e", TestImports: []string{"example.com/app/api"}},
+		{ImportPath: "example.com/app/tools", Dir: "/src/app/tools", XTestImports: []string{"example.com/app/tools"}},
+	})
+}
+
+func TestAffected(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		name     string
+		files    []string
+		changed  []string
+		affected []string
+		all      bool
+	}{
+		{
+			name:     "leaf change propagates to importers and their tests",
+			files:    []string{"store/store.go"},
+			changed:  []string{"example.com/app/store"},
+			affected: []string{"example.com/app", "example.com/app/api", "example.com/app/e2e", "example.com/app/store"},
+		},
+		{
+			name:     "testdata belongs to the package above it",
+			files:    []string{"tools/testdata/golden/out.txt"},
+			changed:  []string{"example.com/app/tools"},
+			affected: []string{"example.com/app/tools"},
+		},
+		{
+			name:     "docs and files outside packages are ignored",
+			files:    []string{"README.md", "api/README.md", "scripts/release.sh"},
+			changed:  []string{},
+			affected: []string{},
+		},
+		{
+			name:     "module files affect everything",
+			files:    []string{"go.sum"},
+			changed:  []string{},
+			affected: g.Packages(),
+			all:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := g.Affected("/src/app", tt.files)
+			assert.Equal(t, tt.changed, result.Changed)
+			assert.Equal(t, tt.affected, result.Affected)
+			assert.Equal(t, tt.all, result.All)
+		})
+	}
+}
+
+func TestUntested(t *testing.T) {
+	g := NewGraph([]Package{
+		{ImportPath: "example.com/app", Dir: "/src/app", Imports: []string{"example.com/app/api"}},
+		{ImportPath: "example.com/app/api", Dir: "/src/app/api", Imports: []string{"example.com/app/store", "fmt"}, TestGoFiles: []string{"api_test.go"}},
+		{ImportPath: "example.com/app/store", Dir: "/src/app/store"},
+		{ImportPath: "example.com/app/legacy", Dir: "/src/app/legacy"},
+		{ImportPath: "example.com/app/helpers", Dir: "/src/app/helpers"},
+		{ImportPath: "example.com/app/e2e", Dir: "/src/app/e2e", XTestImports: []string{"example.com/app/helpers"}, XTestGoFiles: []string{"e2e_test.go"}},
+	})
+
+	untested := g.Untested("/src/app", []string{"main.go", "api/api.go", "store/store.go", "legacy/old.go", "helpers/helpers.go", "scripts/gen.go"})
+	assert.Equal(t, []string{"main.go", "legacy/old.go"}, untested)
+}
+
+func TestDecodeGraph(t *testing.T) {
+	g, err := decodeGraph(strings.NewReader(`{"ImportPath": "example.com/app", "Dir": "/src/app", "Imports": ["example.com/app/lib"]}
+{"ImportPath": "example.com/app/lib", "Dir": "/src/app/lib"}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/app", "example.com/app/lib"}, g.Packages())
+	assert.Equal(t, []string{"example.com/app"}, g.dependents["example.com/app/lib"])
+
+	_, err = decodeGraph(strings.NewReader(`{"ImportPath": `))
+	require.ErrorIs(t, err, ErrListPackages)
+}
+
+func TestLoadGraph(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"main.go":       "package main\n\nimport _ \"example.com/app/lib\"\n\nfunc main() {}\n",
+		"lib/lib.go":    "package lib\n",
+		"other/doc.go":  "package other\n",
+		"lib/README.md": "lib\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	g, err := LoadGraph(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/app", "example.com/app/lib", "example.com/app/other"}, g.Packages())
+
+	result := g.Affected(dir, []string{"lib/lib.go"})
+	assert.Equal(t, []string{"example.com/app", "example.com/app/lib"}, result.Affected)
+
+	_, err = LoadGraph(context.Background(), filepath.Join(dir, "missing"))
+	require.ErrorIs(t, err, ErrListPackages)
+}
@@ -13,6 +13,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-coverage/internal/redact"
 )
 
 // Logger defines the interface compatible with logrus.Entry patterns from the main module
@@ -71,9 +73,10 @@ func (l Level) String() string {
 
 // Config holds logger configuration
 type Config struct {
-	Level  Level
-	Format string // "text" or "json"
-	Output io.Writer
+	Level    Level
+	Format   string // "text" or "json"
+	Output   io.Writer
+	Redactor *redact.Redactor // Scrubs secrets from every entry (defaults to redact.FromEnv)
 }
 
 // entry represents a log entry with accumulated fields
@@ -104,6 +107,10 @@ func NewLogger(config *Config) Logger {
 		config.Output = os.Stderr
 	}
 
+	if config.Redactor == nil {
+		config.Redactor = redact.FromEnv()
+	}
+
 	return &simpleLogger{
 		config: config,
 	}
@@ -393,6 +400,6 @@ func (l *simpleLogger) writeEntry(entry logEntry) {
 		output = fmt.Sprintf("%s [%s] %s%s\n", entry.Time.Format("2006-01-02 15:04:05"), entry.Level, entry.Message, fieldsStr)
 	}
 
-	// Write to configured output (normally stderr)
-	_, _ = l.config.Output.Write([]byte(output))
+	// Write to configured output (normally stderr) with secrets masked
+	_, _ = l.config.Output.Write([]byte(l.config.Redactor.String(output)))
 }
@@ -0,0 +1,241 @@
+// Package orgpolicy fetches the policy file an organization publishes for all of its
+// repositories. The file uses the .env format of the local configuration and is signed with
+// Ed25519, so platform teams can roll out threshold and policy changes from one place while
+// every repository keeps the final say through its own configuration.
+package orgpolicy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/envfile"
+)
+
+// Static error definitions
+var (
+	ErrPublicKeyRequired = errors.New("organization policy public key is required")
+	ErrInvalidPublicKey  = errors.New("invalid organization policy public key")
+	ErrInvalidSignature  = errors.New("organization policy signature does not verify")
+	ErrUnavailable       = errors.New("organization policy is unavailable")
+)
+
+const (
+	// SignatureSuffix is appended to the policy URL to find its detached signature by default
+	SignatureSuffix = ".sig"
+	// maxPolicySize bounds the size of a downloaded policy or signature
+	maxPolicySize = 1 << 20
+	// settingPrefix is the prefix of the settings a policy may set
+	settingPrefix = "GO_COVERAGE_"
+	// selfPrefix is the prefix of the settings that locate the policy, which it may not change
+	selfPrefix = "GO_COVERAGE_ORG_POLICY_"
+)
+
+// Sources a policy is loaded from
+const (
+	// SourceNetwork is a policy downloaded by this run
+	SourceNetwork = "network"
+	// SourceCache is a policy downloaded by an earlier run, still within the cache TTL
+	SourceCache = "cache"
+	// SourceStaleCache is an expired cached policy used because the download failed
+	SourceStaleCache = "stale cache"
+)
+
+// Options locate, verify and cache a policy
+type Options struct {
+	// URL of the policy file, e.g. a raw file in a central repository
+	URL string
+	// SignatureURL of the detached base64 Ed25519 signature (default: URL + SignatureSuffix)
+	SignatureURL string
+	// PublicKey is the base64 Ed25519 key the policy is signed with
+	PublicKey string
+	// CacheDir keeps the last verified policy for reuse and for runs without network access
+	CacheDir string
+	// TTL is how long a cached policy is used without downloading it again (0 always downloads)
+	TTL time.Duration
+	// Client downloads the policy
+	Client *http.Client
+	// Offline uses the cached policy only
+	Offline bool
+}
+
+// Policy is a verified policy
+type Policy struct {
+	// Values are the settings of the policy
+	Values map[string]string
+	// Source is where the policy was loaded from
+	Source string
+	// FetchedAt is when the policy was downloaded
+	FetchedAt time.Time
+}
+
+// Allowed reports whether a policy may set a setting: only go-coverage settings, and none of
+// those locating the policy itself
+func Allowed(key string) bool {
+	return strings.HasPrefix(key, settingPrefix) && !strings.HasPrefix(key, selfPrefix)
+}
+
+// Fetch returns the verified policy, from the cache while it is fresh, downloading it otherwise.
+// When the download fails, the cached policy is used however old it is.
+func Fetch(ctx context.Context, opts Options) (*Policy, error) {
+	key, err := parsePublicKey(opts.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(opts.CacheDir, cacheName(opts.URL))
+
+	cached, cachedErr := readCache(cachePath, key)
+	if cachedErr == nil && (opts.Offline || (opts.TTL > 0 && time.Since(cached.FetchedAt) < opts.TTL)) {
+		return cached, nil
+	}
+	if opts.Offline {
+		return nil, fmt.Errorf("%w: offline and not cached: %w", ErrUnavailable, cachedErr)
+	}
+
+	policy, err := download(ctx, opts, key, cachePath)
+	if err == nil {
+		return policy, nil
+	}
+	if cachedErr == nil {
+		cached.Source = SourceStaleCache
+		return cached, nil
+	}
+	return nil, err
+}
+
+// Apply sets the settings of the policy that are not set already, so local configuration wins,
+// and returns the names of those it set
+func (p *Policy) Apply() ([]string, error) {
+	applied := make([]string, 0, len(p.Values))
+	for key, value := range p.Values {
+		if !Allowed(key) {
+			continue
+		}
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		applied = append(applied, key)
+	}
+	slices.Sort(applied)
+	return applied, nil
+}
+
+// parsePublicKey decodes the base64 Ed25519 public key
+func parsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	if encoded == "" {
+		return nil, ErrPublicKeyRequired
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidPublicKey, len(key), ed25519.PublicKeySize)
+	}
+	return key, nil
+}
+
+// verify checks the detached base64 signature of a policy
+func verify(key ed25519.PublicKey, data, signature []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !ed25519.Verify(key, data, decoded) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// cacheName names the cache file of a policy URL
+func cacheName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:8]) + ".env"
+}
+
+// readCache loads the cached policy, verifying it again so a tampered cache is never used
+func readCache(path string, key ed25519.PublicKey) (*Policy, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from the configured cache directory
+	if err != nil {
+		return nil, err
+	}
+	signature, err := os.ReadFile(path + SignatureSuffix) //nolint:gosec // path is derived from the configured cache directory
+	if err != nil {
+		return nil, err
+	}
+	if err = verify(key, data, signature); err != nil {
+		return nil, err
+	}
+	return &Policy{Values: envfile.Parse(data), Source: SourceCache, FetchedAt: info.ModTime()}, nil
+}
+
+// download fetches and verifies the policy and its signature, then caches both
+func download(ctx context.Context, opts Options, key ed25519.PublicKey, cachePath string) (*Policy, error) {
+	signatureURL := opts.SignatureURL
+	if signatureURL == "" {
+		signatureURL = opts.URL + SignatureSuffix
+	}
+
+	data, err := get(ctx, opts.Client, opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := get(ctx, opts.Client, signatureURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = verify(key, data, signature); err != nil {
+		return nil, err
+	}
+
+	// The policy applies even when it cannot be cached
+	if err = os.MkdirAll(opts.CacheDir, 0o750); err == nil {
+		if err = os.WriteFile(cachePath+SignatureSuffix, signature, 0o600); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o600)
+		}
+	}
+	return &Policy{Values: envfile.Parse(data), Source: SourceNetwork, FetchedAt: time.Now()}, nil
+}
+
+// get downloads a file of at most maxPolicySize bytes
+func get(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	resp, err := client.Do(req) //nolint:gosec // G704: the URL is the configured policy location
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: GET %s: %s", ErrUnavailable, url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if len(data) > maxPolicySize {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrUnavailable, url, maxPolicySize)
+	}
+	return data, nil
+}
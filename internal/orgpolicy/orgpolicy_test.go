@@ -0,0 +1,172 @@
+package orgpolicy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `# Platform team defaults
+GO_COVERAGE_THRESHOLD=75
+GO_COVERAGE_POLICY_MAX_DROP=1
+GITHUB_TOKEN=stolen
+GO_COVERAGE_ORG_POLICY_URL=https://elsewhere.example.com/policy.env
+`
+
+// policyServer serves a policy and its signature, counting downloads
+type policyServer struct {
+	policy    []byte
+	signature []byte
+	requests  atomic.Int32
+	down      atomic.Bool
+}
+
+func (s *policyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	switch {
+	case s.down.Load():
+		w.WriteHeader(http.StatusBadGateway)
+	case r.URL.Path == "/policy.env":
+		_, _ = w.Write(s.policy)
+	case r.URL.Path == "/policy.env.sig":
+		_, _ = w.Write(s.signature)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newPolicyServer(t *testing.T, policy string) (*policyServer, *httptest.Server, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	fake := &policyServer{
+		policy:    []byte(policy),
+		signature: []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(policy)))),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server, base64.StdEncoding.EncodeToString(public)
+}
+
+func TestFetch(t *testing.T) {
+	fake, server, publicKey := newPolicyServer(t, testPolicy)
+	opts := Options{
+		URL:       server.URL + "/policy.env",
+		PublicKey: publicKey,
+		CacheDir:  t.TempDir(),
+		TTL:       time.Hour,
+		Client:    server.Client(),
+	}
+	ctx := context.Background()
+
+	policy, err := Fetch(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SourceNetwork, policy.Source)
+	assert.Equal(t, "75", policy.Values["GO_COVERAGE_THRESHOLD"])
+	assert.Equal(t, int32(2), fake.requests.Load())
+
+	// Fresh cached policies are not downloaded again
+	policy, err = Fetch(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SourceCache, policy.Source)
+	assert.Equal(t, int32(2), fake.requests.Load())
+
+	// Expired policies are used when the download fails
+	opts.TTL = 0
+	fake.down.Store(true)
+	policy, err = Fetch(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SourceStaleCache, policy.Source)
+
+	opts.Offline = true
+	policy, err = Fetch(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SourceCache, policy.Source)
+
+	opts.CacheDir = t.TempDir()
+	_, err = Fetch(ctx, opts)
+	require.ErrorIs(t, err, ErrUnavailable)
+	opts.Offline = false
+	_, err = Fetch(ctx, opts)
+	require.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestFetchRejectsUnsignedPolicies(t *testing.T) {
+	fake, server, publicKey := newPolicyServer(t, testPolicy)
+	fake.policy = []byte("GO_COVERAGE_THRESHOLD=0\n")
+	opts := Options{
+		URL:       server.URL + "/policy.env",
+		PublicKey: publicKey,
+		CacheDir:  t.TempDir(),
+		Client:    server.Client(),
+	}
+
+	_, err := Fetch(context.Background(), opts)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+	assert.NoFileExists(t, filepath.Join(opts.CacheDir, cacheName(opts.URL)))
+
+	opts.PublicKey = ""
+	_, err = Fetch(context.Background(), opts)
+	require.ErrorIs(t, err, ErrPublicKeyRequired)
+
+	opts.PublicKey = "c2hvcnQ="
+	_, err = Fetch(context.Background(), opts)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+}
+
+func TestFetchRejectsTamperedCache(t *testing.T) {
+	fake, server, publicKey := newPolicyServer(t, testPolicy)
+	opts := Options{
+		URL:       server.URL + "/policy.env",
+		PublicKey: publicKey,
+		CacheDir:  t.TempDir(),
+		TTL:       time.Hour,
+		Client:    server.Client(),
+	}
+	_, err := Fetch(context.Background(), opts)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(opts.CacheDir, cacheName(opts.URL)), []byte("GO_COVERAGE_THRESHOLD=0\n"), 0o600))
+	policy, err := Fetch(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, SourceNetwork, policy.Source, "a tampered cache is downloaded again")
+
+	require.NoError(t, os.WriteFile(filepath.Join(opts.CacheDir, cacheName(opts.URL)), []byte("GO_COVERAGE_THRESHOLD=0\n"), 0o600))
+	fake.down.Store(true)
+	_, err = Fetch(context.Background(), opts)
+	require.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("GO_COVERAGE_POLICY_MAX_DROP", "3")
+	for _, key := range []string{"GO_COVERAGE_THRESHOLD", "GITHUB_TOKEN", "GO_COVERAGE_ORG_POLICY_URL"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	policy := &Policy{Values: map[string]string{
+		"GO_COVERAGE_THRESHOLD":       "75",
+		"GO_COVERAGE_POLICY_MAX_DROP": "1",
+		"GITHUB_TOKEN":                "stolen",
+		"GO_COVERAGE_ORG_POLICY_URL":  "https://elsewhere.example.com/policy.env",
+	}}
+	applied, err := policy.Apply()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GO_COVERAGE_THRESHOLD"}, applied)
+	assert.Equal(t, "75", os.Getenv("GO_COVERAGE_THRESHOLD"))
+	assert.Equal(t, "3", os.Getenv("GO_COVERAGE_POLICY_MAX_DROP"), "local settings win")
+	assert.Empty(t, os.Getenv("GITHUB_TOKEN"))
+	assert.Empty(t, os.Getenv("GO_COVERAGE_ORG_POLICY_URL"))
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileClass categorizes a source file so blended totals can be split apart
+type FileClass string
+
+// File classes reported in coverage breakdowns
+const (
+	ClassHandwritten FileClass = "handwritten"
+	ClassGenerated   FileClass = "generated"
+	ClassExample     FileClass = "example"
+	ClassFuzz        FileClass = "fuzz"
+	ClassTest        FileClass = "test"
+)
+
+// FileClasses returns the file classes in reporting order
+func FileClasses() []FileClass {
+	return []FileClass{ClassHandwritten, ClassGenerated, ClassExample, ClassFuzz, ClassTest}
+}
+
+// ClassCoverage is the coverage total for one file class
+type ClassCoverage struct {
+	Class             FileClass `json:"class"`
+	Files             int       `json:"files"`
+	TotalStatements   int       `json:"total_statements"`
+	CoveredStatements int       `json:"covered_statements"`
+	Percentage        float64   `json:"percentage"`
+}
+
+// ClassifyFile returns the class of a source file. Test files and test doubles are
+// classified first, then example programs and fuzz harnesses by directory and name, then
+// generated files by name and by their "Code generated" header.
+func (p *Parser) ClassifyFile(filename string) FileClass {
+	// Test files, test doubles, fixtures and test helper packages
+	testFilePatterns := []string{"*_test.go", "*_mock.go", "mock_*.go"}
+
+	// Example programs and fuzz harnesses outside of test files, such as go-fuzz functions
+	exampleDirectories := []string{"examples", "_examples"}
+	fuzzFilePatterns := []string{"fuzz.go", "*_fuzz.go", "fuzz_*.go"}
+	fuzzDirectories := []string{"fuzz", "fuzzing"}
+
+	// File names produced by common code generators
+	generatedFilePatterns := []string{
+		"*.pb.go", "*.pb.gw.go", "*.gen.go", "*_gen.go", "*_generated.go",
+		"zz_generated*.go", "generated_*.go",
+	}
+
+	slashed := filepath.ToSlash(filename)
+	basename := filepath.Base(slashed)
+
+	if matchesAny(basename, testFilePatterns) || p.IsTestHelper(slashed) {
+		return ClassTest
+	}
+	segments := strings.Split(filepath.Dir(slashed), "/")
+	if slices.Contains(segments, "testdata") {
+		return ClassTest
+	}
+	for _, segment := range segments {
+		if slices.Contains(exampleDirectories, segment) {
+			return ClassExample
+		}
+	}
+	if matchesAny(basename, fuzzFilePatterns) || slices.ContainsFunc(segments, func(segment string) bool {
+		return slices.Contains(fuzzDirectories, segment)
+	}) {
+		return ClassFuzz
+	}
+
+	if matchesAny(basename, generatedFilePatterns) || p.isGeneratedFile(filename) {
+		return ClassGenerated
+	}
+	return ClassHandwritten
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassBreakdown returns coverage totals per file class in FileClasses order.
+// Classes without statements are omitted; files parsed before classification
+// existed count as handwritten.
+func (c *CoverageData) ClassBreakdown() []ClassCoverage {
+	totals := make(map[FileClass]*ClassCoverage)
+	for _, pkg := range c.Packages {
+		for _, file := range pkg.Files {
+			class := file.Class
+			if class == "" {
+				class = ClassHandwritten
+			}
+			total := totals[class]
+			if total == nil {
+				total = &ClassCoverage{Class: class}
+				totals[class] = total
+			}
+			total.Files++
+			total.TotalStatements += file.TotalLines
+			total.CoveredStatements += file.CoveredLines
+		}
+	}
+
+	breakdown := make([]ClassCoverage, 0, len(totals))
+	for _, class := range FileClasses() {
+		total := totals[class]
+		if total == nil || total.TotalStatements == 0 {
+			continue
+		}
+		total.Percentage = float64(total.CoveredStatements) / float64(total.TotalStatements) * 100
+		breakdown = append(breakdown, *total)
+	}
+	return breakdown
+}
+
+// SplitClasses separates the files of the given classes from the rest of the profile, so they
+// can be reported with numbers of their own instead of counting toward the totals
+func (c *CoverageData) SplitClasses(classes ...FileClass) (rest, split *CoverageData) {
+	inClasses := make(map[string]bool)
+	for _, pkg := range c.Packages {
+		for filename, file := range pkg.Files {
+			inClasses[filename] = slices.Contains(classes, file.Class)
+		}
+	}
+	rest = c.Subset(func(file string) bool { return !inClasses[file] })
+	split = c.Subset(func(file string) bool { return inClasses[file] })
+	return rest, split
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyFile(t *testing.T) {
+	dir := t.TempDir()
+	headerFile := filepath.Join(dir, "kind.go")
+	require.NoError(t, os.WriteFile(headerFile, []byte("// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage lib\n"), 0o600))
+	plainFile := filepath.Join(dir, "plain.go")
+	require.NoError(t, os.WriteFile(plainFile, []byte("package lib\n"), 0o600))
+
+	tests := []struct {
+		filename string
+		expected FileClass
+	}{
+		{"github.com/example/repo/lib/lib.go", ClassHandwritten},
+		{"github.com/example/repo/lib/lib_test.go", ClassTest},
+		{"github.com/example/repo/lib/mock_store.go", ClassTest},
+		{"github.com/example/repo/lib/store_mock.go", ClassTest},
+		{"github.com/example/repo/internal/testutil/helpers.go", ClassTest},
+		{"github.com/example/repo/api/api.pb.go", ClassGenerated},
+		{"github.com/example/repo/api/api_gen.go", ClassGenerated},
+		{"github.com/example/repo/apis/zz_generated.deepcopy.go", ClassGenerated},
+		{"github.com/example/repo/api/mocks/api.pb.go", ClassTest},
+		{"github.com/example/repo/examples/server/main.go", ClassExample},
+		{"github.com/example/repo/_examples/cli/cli.go", ClassExample},
+		{"github.com/example/repo/examples/testdata/input.go", ClassTest},
+		{"github.com/example/repo/parser/fuzz.go", ClassFuzz},
+		{"github.com/example/repo/parser/decode_fuzz.go", ClassFuzz},
+		{"github.com/example/repo/internal/fuzzing/corpus.go", ClassFuzz},
+		{headerFile, ClassGenerated},
+		{plainFile, ClassHandwritten},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.filename), func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.ClassifyFile(tt.filename))
+		})
+	}
+}
+
+func TestClassBreakdown(t *testing.T) {
+	profile := `mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/lib/lib.go:15.2,17.16 1 0
+github.com/example/repo/api/api.pb.go:5.2,6.10 4 0
+github.com/example/repo/api/api.pb.go:8.2,9.10 1 1
+github.com/example/repo/lib/mock_store.go:3.2,4.10 2 2
+`
+	p := NewWithConfig(&Config{})
+	coverage, err := p.Parse(context.Background(), strings.NewReader(profile))
+	require.NoError(t, err)
+
+	assert.Equal(t, []ClassCoverage{
+		{Class: ClassHandwritten, Files: 1, TotalStatements: 4, CoveredStatements: 3, Percentage: 75},
+		{Class: ClassGenerated, Files: 1, TotalStatements: 5, CoveredStatements: 1, Percentage: 20},
+		{Class: ClassTest, Files: 1, TotalStatements: 2, CoveredStatements: 2, Percentage: 100},
+	}, coverage.ClassBreakdown())
+}
+
+func TestSplitClasses(t *testing.T) {
+	coverage := &CoverageData{Packages: map[string]*PackageCoverage{
+		"lib": {Name: "lib", Files: map[string]*FileCoverage{
+			"lib/lib.go":  {Class: ClassHandwritten, TotalLines: 4, CoveredLines: 3},
+			"lib/fuzz.go": {Class: ClassFuzz, TotalLines: 2, CoveredLines: 0},
+		}},
+		"examples/server": {Name: "examples/server", Files: map[string]*FileCoverage{
+			"examples/server/main.go": {Class: ClassExample, TotalLines: 6, CoveredLines: 3},
+		}},
+	}}
+	coverage.recalculate()
+
+	rest, split := coverage.SplitClasses(ClassExample, ClassFuzz)
+	assert.Equal(t, 4, rest.TotalLines)
+	assert.InDelta(t, 75.0, rest.Percentage, 0.001)
+	assert.Len(t, rest.Packages["lib"].Files, 1)
+	assert.Equal(t, 8, split.TotalLines)
+	assert.Equal(t, 3, split.CoveredLines)
+	assert.Len(t, split.Packages, 2)
+}
+
+func TestClassBreakdownUnclassified(t *testing.T) {
+	coverage := &CoverageData{Packages: map[string]*PackageCoverage{
+		"lib": {Files: map[string]*FileCoverage{
+			"lib.go":   {TotalLines: 4, CoveredLines: 1},
+			"empty.go": {Class: ClassGenerated},
+		}},
+	}}
+
+	assert.Equal(t, []ClassCoverage{
+		{Class: ClassHandwritten, Files: 1, TotalStatements: 4, CoveredStatements: 1, Percentage: 25},
+	}, coverage.ClassBreakdown(), "files without a class count as handwritten and empty classes are omitted")
+}
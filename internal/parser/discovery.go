@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDiscoveryIgnore returns the patterns of trees file discovery skips by default:
+// Bazel's output symlinks, JavaScript dependencies and the git directory
+func DefaultDiscoveryIgnore() []string {
+	return []string{"bazel-*", "node_modules", ".git"}
+}
+
+// discovery walks a module for eligible files, following symlinks that lead out of it. Each
+// directory and file is visited once by its real path, so symlink cycles end and files that
+// are reachable through several links are counted once.
+type discovery struct {
+	parser   *Parser
+	root     string
+	realRoot string
+	dirs     map[string]struct{} // Real paths of the walked directories
+	files    map[string]struct{} // Real paths of the counted files
+	eligible []string
+}
+
+// walk adds the eligible files below dir
+func (d *discovery) walk(ctx context.Context, dir string) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := d.dirs[real]; ok {
+		return nil
+	}
+	d.dirs[real] = struct{}{}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		relPath, relErr := filepath.Rel(d.root, path)
+		if relErr != nil {
+			relPath = path
+		}
+		if d.parser.ignoredInDiscovery(relPath) {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// Targets inside the module are visited at their real location, and dangling links
+			// are skipped
+			target, evalErr := filepath.EvalSymlinks(path)
+			if evalErr != nil || withinDir(d.realRoot, target) {
+				continue
+			}
+			info, statErr := os.Stat(target)
+			if statErr != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			if err = d.walk(ctx, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		realFile, evalErr := filepath.EvalSymlinks(path)
+		if evalErr != nil {
+			continue
+		}
+		if _, ok := d.files[realFile]; ok {
+			continue
+		}
+		d.files[realFile] = struct{}{}
+
+		// Use relPath for pattern matching, but pass the path for isGeneratedFile
+		if !d.parser.shouldExcludeFileForDiscovery(relPath, path) {
+			d.eligible = append(d.eligible, relPath)
+		}
+	}
+	return nil
+}
+
+// ignoredInDiscovery reports whether a path matches a discovery ignore pattern. Patterns
+// match the name of a file or directory, or its path relative to the module.
+func (p *Parser) ignoredInDiscovery(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	name := path.Base(relPath)
+	for _, pattern := range p.config.DiscoveryIgnore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, relPath); matched || strings.HasPrefix(relPath, pattern+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// withinDir reports whether path is dir or below it
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
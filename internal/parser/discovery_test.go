@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGoFiles creates Go files at the slash-separated paths below root
+func writeGoFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n"), 0o600))
+	}
+}
+
+// symlink creates a symlink or skips the test where the platform does not allow one
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+func TestDiscoverEligibleFilesSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeGoFiles(t, root, "main.go", "pkg/lib.go")
+	writeGoFiles(t, outside, "shared/util.go")
+
+	// A cycle back to the root, a second path to pkg, a file link to a counted file, a
+	// directory outside the module and a dangling link
+	symlink(t, root, filepath.Join(root, "pkg", "loop"))
+	symlink(t, filepath.Join(root, "pkg"), filepath.Join(root, "alias"))
+	symlink(t, filepath.Join(root, "main.go"), filepath.Join(root, "main_link.go"))
+	symlink(t, filepath.Join(outside, "shared"), filepath.Join(root, "shared"))
+	symlink(t, filepath.Join(root, "missing"), filepath.Join(root, "dangling.go"))
+
+	p := NewWithConfig(&Config{})
+	files, err := p.DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", filepath.Join("pkg", "lib.go"), filepath.Join("shared", "util.go")}, files)
+}
+
+func TestDiscoverEligibleFilesSymlinkCycleOutsideModule(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeGoFiles(t, root, "main.go")
+	writeGoFiles(t, outside, "ext/ext.go")
+	symlink(t, outside, filepath.Join(outside, "ext", "back"))
+	symlink(t, filepath.Join(outside, "ext"), filepath.Join(root, "ext"))
+
+	files, err := NewWithConfig(&Config{}).DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", filepath.Join("ext", "ext.go")}, files)
+}
+
+func TestDiscoverEligibleFilesIgnore(t *testing.T) {
+	root := t.TempDir()
+	cache := t.TempDir()
+	writeGoFiles(t, root, "main.go", "node_modules/pkg/gen.go", "web/node_modules/dep/dep.go", "build/out/out.go")
+	writeGoFiles(t, cache, "execroot/gen/gen.go")
+	symlink(t, cache, filepath.Join(root, "bazel-out"))
+
+	files, err := NewWithConfig(&Config{}).DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.Len(t, files, 5, "without ignore patterns every tree is counted")
+
+	p := NewWithConfig(&Config{DiscoveryIgnore: append(DefaultDiscoveryIgnore(), "build/out/")})
+	files, err = p.DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, files)
+
+	// Ignore patterns are separate from coverage exclusions, which the parser still applies
+	assert.False(t, p.shouldExcludeFile("github.com/example/repo/build/out/out.go"))
+}
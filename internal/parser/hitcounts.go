@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+)
+
+// DefaultHotspotHits is the hit count from which a block counts as an over-tested hotspot
+const DefaultHotspotHits = 1000
+
+// hitBucketBounds are the lower bounds of the hit count buckets, one per order of magnitude
+var hitBucketBounds = []int{1, 2, 10, 100, 1000, 10000}
+
+// HitBucket counts the covered statements executed between Min and Max times
+type HitBucket struct {
+	Min        int `json:"min"`
+	Max        int `json:"max,omitempty"` // Zero for the last bucket, which has no upper bound
+	Statements int `json:"statements"`
+}
+
+// Hotspot is a block executed at least as often as the hotspot threshold
+type Hotspot struct {
+	Path       string `json:"path"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Statements int    `json:"statements"`
+	Hits       int    `json:"hits"`
+}
+
+// HitDistribution is how often the covered statements were executed: code run by a great many
+// tests can point at slow, redundant suites, and code covered exactly once at fragile coverage
+type HitDistribution struct {
+	Covered  int         `json:"covered"` // Covered statements
+	Once     int         `json:"once"`    // Statements executed exactly once
+	Buckets  []HitBucket `json:"buckets"`
+	Hotspots []Hotspot   `json:"hotspots,omitempty"` // Most executed first
+	// HotspotStatements are the statements of all hotspots, of which Hotspots may list fewer
+	HotspotStatements int `json:"hotspot_statements"`
+	HotspotHits       int `json:"hotspot_hits"`
+}
+
+// HasHitCounts reports whether the profile records how often each block ran, which profiles
+// written with -covermode=count or -covermode=atomic do
+func (c *CoverageData) HasHitCounts() bool {
+	return c.Mode == "count" || c.Mode == "atomic"
+}
+
+// HitDistribution returns the distribution of the hit counts of the covered statements, listing
+// up to maxHotspots blocks executed at least hotspotHits times. Profiles in set mode only record
+// whether a statement ran and return nil.
+func (c *CoverageData) HitDistribution(hotspotHits, maxHotspots int) *HitDistribution {
+	if !c.HasHitCounts() {
+		return nil
+	}
+
+	dist := &HitDistribution{HotspotHits: hotspotHits}
+	for i, low := range hitBucketBounds {
+		bucket := HitBucket{Min: low}
+		if i+1 < len(hitBucketBounds) {
+			bucket.Max = hitBucketBounds[i+1] - 1
+		}
+		dist.Buckets = append(dist.Buckets, bucket)
+	}
+
+	for _, pkg := range c.Packages {
+		for _, file := range pkg.Files {
+			for _, stmt := range file.Statements {
+				if stmt.Count <= 0 {
+					continue
+				}
+				dist.Covered += stmt.NumStmt
+				if stmt.Count == 1 {
+					dist.Once += stmt.NumStmt
+				}
+				i, _ := slices.BinarySearch(hitBucketBounds, stmt.Count+1)
+				dist.Buckets[i-1].Statements += stmt.NumStmt
+
+				if hotspotHits > 0 && stmt.Count >= hotspotHits {
+					dist.HotspotStatements += stmt.NumStmt
+					dist.Hotspots = append(dist.Hotspots, Hotspot{
+						Path:       file.Path,
+						StartLine:  stmt.StartLine,
+						EndLine:    stmt.EndLine,
+						Statements: stmt.NumStmt,
+						Hits:       stmt.Count,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(dist.Hotspots, func(a, b Hotspot) int {
+		return cmp.Or(cmp.Compare(b.Hits, a.Hits), cmp.Compare(a.Path, b.Path), cmp.Compare(a.StartLine, b.StartLine))
+	})
+	if len(dist.Hotspots) > maxHotspots {
+		dist.Hotspots = dist.Hotspots[:max(maxHotspots, 0)]
+	}
+	return dist
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHitDistribution(t *testing.T) {
+	coverage := &CoverageData{
+		Mode: "count",
+		Packages: map[string]*PackageCoverage{
+			"api": {Files: map[string]*FileCoverage{
+				"api/server.go": {Path: "api/server.go", Statements: []Statement{
+					{StartLine: 1, EndLine: 2, NumStmt: 2, Count: 1},
+					{StartLine: 3, EndLine: 3, NumStmt: 1, Count: 0},
+					{StartLine: 4, EndLine: 6, NumStmt: 3, Count: 9},
+					{StartLine: 7, EndLine: 7, NumStmt: 1, Count: 10},
+					{StartLine: 8, EndLine: 9, NumStmt: 2, Count: 1000},
+					{StartLine: 10, EndLine: 12, NumStmt: 4, Count: 25000},
+				}},
+			}},
+			"db": {Files: map[string]*FileCoverage{
+				"db/query.go": {Path: "db/query.go", Statements: []Statement{
+					{StartLine: 5, EndLine: 5, NumStmt: 1, Count: 4200},
+				}},
+			}},
+		},
+	}
+
+	dist := coverage.HitDistribution(1000, 2)
+	require.NotNil(t, dist)
+	assert.Equal(t, 13, dist.Covered)
+	assert.Equal(t, 2, dist.Once)
+	assert.Equal(t, []HitBucket{
+		{Min: 1, Max: 1, Statements: 2},
+		{Min: 2, Max: 9, Statements: 3},
+		{Min: 10, Max: 99, Statements: 1},
+		{Min: 100, Max: 999, Statements: 0},
+		{Min: 1000, Max: 9999, Statements: 3},
+		{Min: 10000, Statements: 4},
+	}, dist.Buckets)
+	assert.Equal(t, 7, dist.HotspotStatements, "every hotspot counts, not only the listed ones")
+	assert.Equal(t, []Hotspot{
+		{Path: "api/server.go", StartLine: 10, EndLine: 12, Statements: 4, Hits: 25000},
+		{Path: "db/query.go", StartLine: 5, EndLine: 5, Statements: 1, Hits: 4200},
+	}, dist.Hotspots)
+
+	dist = coverage.HitDistribution(0, 10)
+	assert.Empty(t, dist.Hotspots, "a zero threshold finds no hotspots")
+
+	assert.True(t, coverage.HasHitCounts())
+	coverage.Mode = "set"
+	assert.False(t, coverage.HasHitCounts())
+	assert.Nil(t, coverage.HitDistribution(1000, 10), "set mode records no hit counts")
+}
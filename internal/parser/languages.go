@@ -0,0 +1,475 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Static errors for coverage reports of other languages
+var (
+	ErrUnknownReportFormat = errors.New("coverage report is neither LCOV nor Cobertura")
+	ErrInvalidLCOV         = errors.New("invalid LCOV record")
+	ErrInvalidCobertura    = errors.New("invalid Cobertura report")
+	ErrInvalidInputArg     = errors.New("coverage report must be given as path or language=path")
+)
+
+// LanguageGo is the language of files parsed from Go coverage profiles
+const LanguageGo = "go"
+
+// Formats of coverage reports written by tools of other languages
+const (
+	FormatLCOV      = "lcov"
+	FormatCobertura = "cobertura"
+)
+
+// languageExtensions maps source file extensions to the language reported for them
+//
+//nolint:gochecknoglobals // read-only lookup table
+var languageExtensions = map[string]string{
+	".go":     LanguageGo,
+	".ts":     "typescript",
+	".tsx":    "typescript",
+	".mts":    "typescript",
+	".cts":    "typescript",
+	".js":     "javascript",
+	".jsx":    "javascript",
+	".mjs":    "javascript",
+	".cjs":    "javascript",
+	".vue":    "vue",
+	".svelte": "svelte",
+	".py":     "python",
+	".rb":     "ruby",
+	".rs":     "rust",
+	".java":   "java",
+	".kt":     "kotlin",
+	".scala":  "scala",
+	".cs":     "csharp",
+	".php":    "php",
+	".swift":  "swift",
+	".c":      "c",
+	".h":      "c",
+	".cc":     "cpp",
+	".cpp":    "cpp",
+	".hpp":    "cpp",
+	".dart":   "dart",
+	".ex":     "elixir",
+}
+
+// LanguageCoverage is the coverage total of the files of one language
+type LanguageCoverage struct {
+	Language          string  `json:"language"`
+	Files             int     `json:"files"`
+	TotalStatements   int     `json:"total_statements"`
+	CoveredStatements int     `json:"covered_statements"`
+	Percentage        float64 `json:"percentage"`
+}
+
+// DetectLanguage names the language of a source file from its extension; unknown extensions
+// are reported as the extension itself and files without one as "other"
+func DetectLanguage(filename string) string {
+	ext := strings.ToLower(path.Ext(strings.ReplaceAll(filename, "\\", "/")))
+	if language, ok := languageExtensions[ext]; ok {
+		return language
+	}
+	if ext == "" || ext == "." {
+		return "other"
+	}
+	return ext[1:]
+}
+
+// ParseInputArg splits a "[language=]path" command line argument. Without a language the
+// language of each file is detected from its extension.
+func ParseInputArg(arg string) (string, string, error) {
+	language, reportPath, ok := strings.Cut(arg, "=")
+	if !ok {
+		language, reportPath = "", language
+	}
+	language, reportPath = strings.ToLower(strings.TrimSpace(language)), strings.TrimSpace(reportPath)
+	if reportPath == "" || (ok && language == "") {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidInputArg, arg)
+	}
+	return language, reportPath, nil
+}
+
+// ParseReportFile parses an LCOV or Cobertura report, telling the two apart by their content.
+// Files are tagged with language, or with the language of their extension when it is empty.
+func (p *Parser) ParseReportFile(ctx context.Context, filename, language string) (*CoverageData, error) {
+	file, err := os.Open(filename) //nolint:gosec // filename is controlled and validated by caller
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open coverage report %q: %w: %w", filename, ErrProfileNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report %q: %w", filename, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	format, err := detectReportFormat(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse coverage report %q: %w", filename, err)
+	}
+	if format == FormatCobertura {
+		return p.ParseCobertura(ctx, reader, language)
+	}
+	return p.ParseLCOV(ctx, reader, language)
+}
+
+// detectReportFormat peeks at the first non-blank bytes of a report: Cobertura is XML and
+// LCOV starts with a test name or source file record
+func detectReportFormat(reader *bufio.Reader) (string, error) {
+	for {
+		head, err := reader.Peek(1)
+		if err != nil {
+			return "", ErrUnknownReportFormat
+		}
+		// Skip leading whitespace and a UTF-8 byte order mark
+		if bytes.IndexByte([]byte(" \t\r\n\xef\xbb\xbf"), head[0]) < 0 {
+			break
+		}
+		_, _ = reader.ReadByte()
+	}
+
+	head, _ := reader.Peek(3)
+	switch {
+	case head[0] == '<':
+		return FormatCobertura, nil
+	case bytes.HasPrefix(head, []byte("TN:")), bytes.HasPrefix(head, []byte("SF:")):
+		return FormatLCOV, nil
+	default:
+		return "", ErrUnknownReportFormat
+	}
+}
+
+// ParseLCOV parses an LCOV tracefile, as written by Istanbul, c8, coverage.py and others.
+// Each DA record becomes a one-statement block on its line.
+func (p *Parser) ParseLCOV(ctx context.Context, reader io.Reader, language string) (*CoverageData, error) {
+	limits := p.config.Limits.withDefaults()
+
+	limited := newSizeLimitedReader(reader, limits.MaxFileSize)
+	scanner := bufio.NewScanner(limited)
+	scanner.Buffer(make([]byte, 0, min(limits.MaxLineLength, bufio.MaxScanTokenSize)), limits.MaxLineLength)
+
+	collector := newReportCollector(p, limits)
+	current := ""
+	lineNum := 0
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		if limited.exceeded() {
+			break
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		record, value, _ := strings.Cut(line, ":")
+		switch record {
+		case "SF":
+			current = relativeReportPath("", value)
+			if err := collector.file(current); err != nil {
+				return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+			}
+		case "DA":
+			if current == "" {
+				return nil, fmt.Errorf("%w: line %d has DA outside of a source file", ErrInvalidLCOV, lineNum)
+			}
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidLCOV, lineNum, line)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid line number %q", ErrInvalidLCOV, lineNum, fields[0])
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid hit count %q", ErrInvalidLCOV, lineNum, fields[1])
+			}
+			if err = collector.line(current, number, hits); err != nil {
+				return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+			}
+		case "end_of_record":
+			current = ""
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w after line %d (limit %d bytes)", ErrLineTooLong, lineNum, limits.MaxLineLength)
+		}
+		return nil, fmt.Errorf("error reading LCOV report: %w", err)
+	}
+
+	return collector.build(FormatLCOV, language)
+}
+
+// coberturaLine is a line element of a Cobertura class
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// coberturaClass is a class element of a Cobertura report; the filename is relative to one
+// of the report's sources
+type coberturaClass struct {
+	Filename string          `xml:"filename,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+// ParseCobertura parses a Cobertura XML report, as written by coverage.py, Jest, JaCoCo
+// converters and others. Each line element becomes a one-statement block on its line.
+func (p *Parser) ParseCobertura(ctx context.Context, reader io.Reader, language string) (*CoverageData, error) {
+	limits := p.config.Limits.withDefaults()
+
+	limited := newSizeLimitedReader(reader, limits.MaxFileSize)
+	decoder := xml.NewDecoder(limited)
+	collector := newReportCollector(p, limits)
+	source := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if limited.exceeded() {
+				return nil, fmt.Errorf("%w (%d bytes)", ErrProfileTooLarge, limits.MaxFileSize)
+			}
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCobertura, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "source":
+			// Only the first source is used to resolve relative file names
+			var value string
+			if err = decoder.DecodeElement(&value, &start); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCobertura, err)
+			}
+			if source == "" {
+				source = strings.TrimSpace(value)
+			}
+		case "class":
+			var class coberturaClass
+			if err = decoder.DecodeElement(&class, &start); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCobertura, err)
+			}
+			filename := relativeReportPath(source, class.Filename)
+			if err = collector.file(filename); err != nil {
+				return nil, err
+			}
+			for _, line := range class.Lines {
+				if err = collector.line(filename, line.Number, line.Hits); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if collector.files == 0 {
+		return nil, fmt.Errorf("%w: no classes found", ErrInvalidCobertura)
+	}
+	return collector.build(FormatCobertura, language)
+}
+
+// relativeReportPath resolves a file name of a report against its source directory and makes
+// it relative to the working directory when it lies within it, so it matches the repository
+func relativeReportPath(source, filename string) string {
+	filename = strings.TrimSpace(filename)
+	if source != "" && !filepath.IsAbs(filename) {
+		filename = filepath.Join(source, filename)
+	}
+	if filepath.IsAbs(filename) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, relErr := filepath.Rel(wd, filename); relErr == nil && filepath.IsLocal(rel) {
+				filename = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
+// reportCollector gathers the lines of a foreign report under the parser's limits and exclusions
+type reportCollector struct {
+	parser     *Parser
+	limits     Limits
+	statements []StatementWithFile
+	seenFiles  map[string]bool // File name -> excluded
+	files      int
+	blocks     int
+}
+
+func newReportCollector(p *Parser, limits Limits) *reportCollector {
+	return &reportCollector{parser: p, limits: limits, seenFiles: make(map[string]bool)}
+}
+
+// file registers a source file of the report
+func (c *reportCollector) file(filename string) error {
+	if err := validateProfilePath(filename); err != nil {
+		return err
+	}
+	if _, ok := c.seenFiles[filename]; ok {
+		return nil
+	}
+	if len(c.seenFiles) >= c.limits.MaxFiles {
+		return fmt.Errorf("%w (limit %d)", ErrTooManyFiles, c.limits.MaxFiles)
+	}
+	c.seenFiles[filename] = c.parser.shouldExcludeFile(filename)
+	c.files++
+	return nil
+}
+
+// line records the hit count of one line of a registered file
+func (c *reportCollector) line(filename string, number, hits int) error {
+	if number < 0 || hits < 0 {
+		return ErrNegativeValue
+	}
+	if c.blocks++; c.blocks > c.limits.MaxBlocks {
+		return fmt.Errorf("%w (limit %d)", ErrTooManyBlocks, c.limits.MaxBlocks)
+	}
+	if c.seenFiles[filename] {
+		return nil
+	}
+	c.statements = append(c.statements, StatementWithFile{
+		Statement: Statement{StartLine: number, StartCol: 1, EndLine: number, EndCol: 1, NumStmt: 1, Count: hits},
+		Filename:  filename,
+	})
+	return nil
+}
+
+// build groups the collected lines into packages named after the directory of each file, so
+// they do not mix with Go packages, and tags every file with its language
+func (c *reportCollector) build(format, language string) (*CoverageData, error) {
+	data, err := c.parser.buildCoverageData(format, c.statements, c.parser.config.MergeRepeatedBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]*PackageCoverage, len(data.Packages))
+	for _, pkg := range data.Packages {
+		for filename, file := range pkg.Files {
+			file.Language = language
+			if file.Language == "" {
+				file.Language = DetectLanguage(filename)
+			}
+			name := path.Dir(filename)
+			if name == "." {
+				name = file.Language
+			}
+			target := packages[name]
+			if target == nil {
+				target = &PackageCoverage{Name: name, Files: make(map[string]*FileCoverage)}
+				packages[name] = target
+			}
+			target.Files[filename] = file
+		}
+	}
+	data.Packages = packages
+	data.recalculate()
+	return data, nil
+}
+
+// MergeReports adds the files of coverage reports of other languages to a Go profile and
+// recomputes the totals. A package name used by both keeps the files of each.
+func (c *CoverageData) MergeReports(reports ...*CoverageData) {
+	if c.Packages == nil {
+		c.Packages = make(map[string]*PackageCoverage)
+	}
+	for _, report := range reports {
+		for name, pkg := range report.Packages {
+			target := c.Packages[name]
+			if target == nil {
+				target = &PackageCoverage{Name: name, Files: make(map[string]*FileCoverage, len(pkg.Files))}
+				c.Packages[name] = target
+			}
+			for filename, file := range pkg.Files {
+				target.Files[filename] = file
+			}
+		}
+	}
+	c.recalculate()
+}
+
+// recalculate sums package and total statement counts from the files
+func (c *CoverageData) recalculate() {
+	c.TotalLines, c.CoveredLines, c.Percentage = 0, 0, 0
+	for _, pkg := range c.Packages {
+		pkg.TotalLines, pkg.CoveredLines, pkg.Percentage = 0, 0, 0
+		for _, file := range pkg.Files {
+			pkg.TotalLines += file.TotalLines
+			pkg.CoveredLines += file.CoveredLines
+		}
+		if pkg.TotalLines > 0 {
+			pkg.Percentage = float64(pkg.CoveredLines) / float64(pkg.TotalLines) * 100
+		}
+		c.TotalLines += pkg.TotalLines
+		c.CoveredLines += pkg.CoveredLines
+	}
+	if c.TotalLines > 0 {
+		c.Percentage = float64(c.CoveredLines) / float64(c.TotalLines) * 100
+	}
+}
+
+// LanguageBreakdown returns coverage totals per language, Go first and the others by name.
+// Files without a language come from Go profiles.
+func (c *CoverageData) LanguageBreakdown() []LanguageCoverage {
+	totals := make(map[string]*LanguageCoverage)
+	for _, pkg := range c.Packages {
+		for _, file := range pkg.Files {
+			language := file.Language
+			if language == "" {
+				language = LanguageGo
+			}
+			total := totals[language]
+			if total == nil {
+				total = &LanguageCoverage{Language: language}
+				totals[language] = total
+			}
+			total.Files++
+			total.TotalStatements += file.TotalLines
+			total.CoveredStatements += file.CoveredLines
+		}
+	}
+
+	breakdown := make([]LanguageCoverage, 0, len(totals))
+	for _, total := range totals {
+		if total.TotalStatements > 0 {
+			total.Percentage = float64(total.CoveredStatements) / float64(total.TotalStatements) * 100
+		}
+		breakdown = append(breakdown, *total)
+	}
+	slices.SortFunc(breakdown, func(a, b LanguageCoverage) int {
+		if (a.Language == LanguageGo) != (b.Language == LanguageGo) {
+			if a.Language == LanguageGo {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Language, b.Language)
+	})
+	return breakdown
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLCOV = `TN:
+SF:web/src/api.ts
+FN:1,fetchUser
+DA:1,4
+DA:2,4
+DA:5,0
+LF:3
+LH:2
+end_of_record
+SF:web/src/index.js
+DA:1,1
+end_of_record
+`
+
+const testCobertura = `<?xml version="1.0" ?>
+<coverage line-rate="0.5" version="7.4">
+	<sources>
+		<source>scripts</source>
+	</sources>
+	<packages>
+		<package name="tools">
+			<classes>
+				<class name="build.py" filename="tools/build.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+func TestDetectLanguage(t *testing.T) {
+	assert.Equal(t, "typescript", DetectLanguage("web/src/App.TSX"))
+	assert.Equal(t, "python", DetectLanguage("tools/build.py"))
+	assert.Equal(t, LanguageGo, DetectLanguage("internal/config/config.go"))
+	assert.Equal(t, "zig", DetectLanguage("lib/main.zig"))
+	assert.Equal(t, "other", DetectLanguage("Makefile"))
+}
+
+func TestParseInputArg(t *testing.T) {
+	language, path, err := ParseInputArg("TypeScript=web/coverage/lcov.info")
+	require.NoError(t, err)
+	assert.Equal(t, "typescript", language)
+	assert.Equal(t, "web/coverage/lcov.info", path)
+
+	language, path, err = ParseInputArg("coverage.xml")
+	require.NoError(t, err)
+	assert.Empty(t, language)
+	assert.Equal(t, "coverage.xml", path)
+
+	for _, arg := range []string{"", "=lcov.info", "typescript="} {
+		_, _, err = ParseInputArg(arg)
+		require.ErrorIs(t, err, ErrInvalidInputArg, arg)
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	p := NewWithConfig(&Config{})
+	coverage, err := p.ParseLCOV(context.Background(), strings.NewReader(testLCOV), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, coverage.TotalLines)
+	assert.Equal(t, 3, coverage.CoveredLines)
+	require.Contains(t, coverage.Packages, "web/src")
+	api := coverage.Packages["web/src"].Files["web/src/api.ts"]
+	require.NotNil(t, api)
+	assert.Equal(t, "typescript", api.Language)
+	assert.Equal(t, 3, api.TotalLines)
+	assert.Equal(t, 2, api.CoveredLines)
+	assert.Equal(t, Statement{StartLine: 5, StartCol: 1, EndLine: 5, EndCol: 1, NumStmt: 1}, api.Statements[2])
+	assert.Equal(t, "javascript", coverage.Packages["web/src"].Files["web/src/index.js"].Language)
+
+	// An explicit language applies to every file of the report
+	coverage, err = p.ParseLCOV(context.Background(), strings.NewReader(testLCOV), "frontend")
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", coverage.Packages["web/src"].Files["web/src/index.js"].Language)
+}
+
+func TestParseLCOVRejectsHostileInput(t *testing.T) {
+	p := NewWithConfig(&Config{Limits: Limits{MaxFiles: 1}})
+	ctx := context.Background()
+
+	_, err := p.ParseLCOV(ctx, strings.NewReader(testLCOV), "")
+	require.ErrorIs(t, err, ErrTooManyFiles)
+
+	_, err = p.ParseLCOV(ctx, strings.NewReader("SF:../../etc/passwd\nDA:1,1\n"), "")
+	require.ErrorIs(t, err, ErrUnsafeFilePath)
+
+	_, err = p.ParseLCOV(ctx, strings.NewReader("DA:1,1\n"), "")
+	require.ErrorIs(t, err, ErrInvalidLCOV)
+
+	_, err = p.ParseLCOV(ctx, strings.NewReader("SF:a.ts\nDA:1,-1\n"), "")
+	require.ErrorIs(t, err, ErrNegativeValue)
+}
+
+func TestParseCobertura(t *testing.T) {
+	p := NewWithConfig(&Config{})
+	coverage, err := p.ParseCobertura(context.Background(), strings.NewReader(testCobertura), "")
+	require.NoError(t, err)
+
+	require.Contains(t, coverage.Packages, "scripts/tools")
+	file := coverage.Packages["scripts/tools"].Files["scripts/tools/build.py"]
+	require.NotNil(t, file)
+	assert.Equal(t, "python", file.Language)
+	assert.InDelta(t, 50.0, coverage.Percentage, 0.001)
+
+	_, err = p.ParseCobertura(context.Background(), strings.NewReader("<coverage></coverage>"), "")
+	require.ErrorIs(t, err, ErrInvalidCobertura)
+}
+
+func TestParseReportFile(t *testing.T) {
+	dir := t.TempDir()
+	lcov := filepath.Join(dir, "lcov.info")
+	cobertura := filepath.Join(dir, "coverage.xml")
+	unknown := filepath.Join(dir, "coverage.txt")
+	require.NoError(t, os.WriteFile(lcov, []byte(testLCOV), 0o600))
+	require.NoError(t, os.WriteFile(cobertura, []byte("\xef\xbb\xbf"+testCobertura), 0o600))
+	require.NoError(t, os.WriteFile(unknown, []byte("mode: set\n"), 0o600))
+
+	p := NewWithConfig(&Config{})
+	ctx := context.Background()
+
+	coverage, err := p.ParseReportFile(ctx, lcov, "")
+	require.NoError(t, err)
+	assert.Equal(t, FormatLCOV, coverage.Mode)
+
+	coverage, err = p.ParseReportFile(ctx, cobertura, "")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCobertura, coverage.Mode)
+
+	_, err = p.ParseReportFile(ctx, unknown, "")
+	require.ErrorIs(t, err, ErrUnknownReportFormat)
+
+	_, err = p.ParseReportFile(ctx, filepath.Join(dir, "missing.info"), "")
+	require.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func TestMergeReportsAndLanguageBreakdown(t *testing.T) {
+	p := NewWithConfig(&Config{})
+	ctx := context.Background()
+
+	coverage, err := p.Parse(ctx, strings.NewReader(`mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/lib/lib.go:15.2,17.16 1 0
+`))
+	require.NoError(t, err)
+	lcov, err := p.ParseLCOV(ctx, strings.NewReader(testLCOV), "")
+	require.NoError(t, err)
+	cobertura, err := p.ParseCobertura(ctx, strings.NewReader(testCobertura), "")
+	require.NoError(t, err)
+
+	coverage.MergeReports(lcov, cobertura)
+	assert.Equal(t, "set", coverage.Mode)
+	assert.Equal(t, 10, coverage.TotalLines)
+	assert.Equal(t, 7, coverage.CoveredLines)
+	assert.InDelta(t, 70.0, coverage.Percentage, 0.001)
+	assert.Len(t, coverage.Packages, 3)
+
+	breakdown := coverage.LanguageBreakdown()
+	require.Len(t, breakdown, 4)
+	assert.Equal(t, LanguageCoverage{Language: LanguageGo, Files: 1, TotalStatements: 4, CoveredStatements: 3, Percentage: 75}, breakdown[0])
+	assert.Equal(t, "javascript", breakdown[1].Language)
+	assert.Equal(t, "python", breakdown[2].Language)
+	assert.Equal(t, "typescript", breakdown[3].Language)
+	assert.InDelta(t, 66.667, breakdown[3].Percentage, 0.001)
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Validation errors for untrusted coverage profiles
+var (
+	ErrProfileTooLarge = errors.New("coverage profile exceeds maximum size")
+	ErrLineTooLong     = errors.New("coverage profile line exceeds maximum length")
+	ErrTooManyFiles    = errors.New("coverage profile references too many files")
+	ErrTooManyBlocks   = errors.New("coverage profile contains too many blocks")
+	ErrUnsafeFilePath  = errors.New("unsafe file path in coverage profile")
+	ErrNegativeValue   = errors.New("invalid statement format: negative value")
+)
+
+// Limits bounds the resources a single coverage profile may consume.
+// Zero values fall back to the corresponding DefaultLimits value.
+type Limits struct {
+	MaxFileSize   int64 // Maximum profile size in bytes
+	MaxLineLength int   // Maximum length of a single profile line in bytes
+	MaxFiles      int   // Maximum number of distinct source files
+	MaxBlocks     int   // Maximum number of coverage blocks
+}
+
+// DefaultLimits returns limits generous enough for very large monorepos
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFileSize:   512 << 20, // 512 MiB
+		MaxLineLength: 64 << 10,  // 64 KiB
+		MaxFiles:      100_000,
+		MaxBlocks:     10_000_000,
+	}
+}
+
+// withDefaults fills unset limits with the defaults
+func (l Limits) withDefaults() Limits {
+	defaults := DefaultLimits()
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = defaults.MaxFileSize
+	}
+	if l.MaxLineLength <= 0 {
+		l.MaxLineLength = defaults.MaxLineLength
+	}
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = defaults.MaxFiles
+	}
+	if l.MaxBlocks <= 0 {
+		l.MaxBlocks = defaults.MaxBlocks
+	}
+	return l
+}
+
+// sizeLimitedReader fails with ErrProfileTooLarge once more than max bytes have been read
+type sizeLimitedReader struct {
+	reader    io.Reader
+	max       int64
+	remaining int64
+}
+
+func newSizeLimitedReader(reader io.Reader, maxSize int64) *sizeLimitedReader {
+	return &sizeLimitedReader{reader: reader, max: maxSize, remaining: maxSize}
+}
+
+// Read implements io.Reader
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w (%d bytes)", ErrProfileTooLarge, r.max)
+	}
+	// Allow reading one byte past the limit to detect oversized input
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, fmt.Errorf("%w (%d bytes)", ErrProfileTooLarge, r.max)
+	}
+	return n, err
+}
+
+// exceeded reports whether the reader has gone past its limit
+func (r *sizeLimitedReader) exceeded() bool {
+	return r.remaining < 0
+}
+
+// validateProfilePath rejects file names that can never appear in a genuine profile:
+// control characters and parent directory segments used for path traversal
+func validateProfilePath(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty file name", ErrUnsafeFilePath)
+	}
+	if strings.ContainsFunc(name, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return fmt.Errorf("%w: %q contains control characters", ErrUnsafeFilePath, name)
+	}
+	for _, segment := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return fmt.Errorf("%w: %q contains a parent directory reference", ErrUnsafeFilePath, name)
+		}
+	}
+	return nil
+}
+
+// SafeOutputPath joins a file name taken from a coverage profile, the configuration or
+// the CI context (such as a branch name) onto baseDir for generated output. Absolute
+// paths, volume names and parent directory references are rejected so crafted input
+// cannot write outside baseDir.
+func SafeOutputPath(baseDir, name string) (string, error) {
+	if err := validateProfilePath(name); err != nil {
+		return "", err
+	}
+
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(slashed) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || hasDriveLetter(name) {
+		return "", fmt.Errorf("%w: %q is absolute", ErrUnsafeFilePath, name)
+	}
+
+	cleaned := path.Clean(slashed)
+	if cleaned == "." {
+		return "", fmt.Errorf("%w: %q does not name a file", ErrUnsafeFilePath, name)
+	}
+
+	return filepath.Join(baseDir, filepath.FromSlash(cleaned)), nil
+}
+
+// hasDriveLetter reports whether name starts with a Windows drive letter such as "C:"
+func hasDriveLetter(name string) bool {
+	if len(name) < 2 || name[1] != ':' {
+		return false
+	}
+	c := name[0]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildProfile returns a set-mode profile with one block per file name
+func buildProfile(files ...string) string {
+	var sb strings.Builder
+	sb.WriteString("mode: set\n")
+	for _, file := range files {
+		fmt.Fprintf(&sb, "%s:1.1,2.2 1 1\n", file)
+	}
+	return sb.String()
+}
+
+func TestDefaultLimits(t *testing.T) {
+	limits := Limits{MaxFiles: 5}.withDefaults()
+	defaults := DefaultLimits()
+
+	assert.Equal(t, 5, limits.MaxFiles)
+	assert.Equal(t, defaults.MaxFileSize, limits.MaxFileSize)
+	assert.Equal(t, defaults.MaxLineLength, limits.MaxLineLength)
+	assert.Equal(t, defaults.MaxBlocks, limits.MaxBlocks)
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      Limits
+		profile     string
+		expectedErr error
+	}{
+		{
+			name:    "within limits",
+			limits:  Limits{MaxFileSize: 1024, MaxLineLength: 128, MaxFiles: 2, MaxBlocks: 2},
+			profile: buildProfile("pkg/a.go", "pkg/b.go"),
+		},
+		{
+			name:        "profile too large",
+			limits:      Limits{MaxFileSize: 32},
+			profile:     buildProfile("pkg/a.go", "pkg/b.go"),
+			expectedErr: ErrProfileTooLarge,
+		},
+		{
+			name:        "line too long",
+			limits:      Limits{MaxLineLength: 64},
+			profile:     buildProfile("pkg/" + strings.Repeat("a", 100) + ".go"),
+			expectedErr: ErrLineTooLong,
+		},
+		{
+			name:        "too many files",
+			limits:      Limits{MaxFiles: 1},
+			profile:     buildProfile("pkg/a.go", "pkg/b.go"),
+			expectedErr: ErrTooManyFiles,
+		},
+		{
+			name:        "too many blocks",
+			limits:      Limits{MaxBlocks: 2},
+			profile:     buildProfile("pkg/a.go", "pkg/a.go", "pkg/a.go"),
+			expectedErr: ErrTooManyBlocks,
+		},
+		{
+			name:        "parent traversal",
+			profile:     buildProfile("github.com/owner/repo/../../../etc/passwd"),
+			expectedErr: ErrUnsafeFilePath,
+		},
+		{
+			name:        "windows parent traversal",
+			profile:     buildProfile(`pkg\..\..\secret.go`),
+			expectedErr: ErrUnsafeFilePath,
+		},
+		{
+			name:        "negative counts",
+			profile:     "mode: set\npkg/a.go:1.1,2.2 1 -1\n",
+			expectedErr: ErrNegativeValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewWithConfig(&Config{Limits: tt.limits})
+			data, err := parser.Parse(context.Background(), strings.NewReader(tt.profile))
+			if tt.expectedErr == nil {
+				require.NoError(t, err)
+				assert.NotNil(t, data)
+				return
+			}
+			require.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+func TestSafeOutputPath(t *testing.T) {
+	base := filepath.Join("out", "files")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "module path", input: "internal/parser/parser.go", expected: filepath.Join(base, "internal", "parser", "parser.go")},
+		{name: "redundant segments", input: "./internal//parser.go", expected: filepath.Join(base, "internal", "parser.go")},
+		{name: "backslashes", input: `internal\parser.go`, expected: filepath.Join(base, "internal", "parser.go")},
+		{name: "parent traversal", input: "../escape.go"},
+		{name: "nested traversal", input: "a/../../escape.go"},
+		{name: "absolute unix path", input: "/etc/passwd"},
+		{name: "absolute windows path", input: `C:\Windows\win.ini`},
+		{name: "unc path", input: `\\server\share\file.go`},
+		{name: "nul byte", input: "file\x00.go"},
+		{name: "empty", input: ""},
+		{name: "current directory", input: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SafeOutputPath(base, tt.input)
+			if tt.expected == "" {
+				require.ErrorIs(t, err, ErrUnsafeFilePath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleDir returns the directory of the Go module within the repository at repoRoot
+func (p *Parser) ModuleDir(repoRoot string) string {
+	return filepath.Join(repoRoot, filepath.FromSlash(p.config.ModuleRoot))
+}
+
+// withModuleRoot returns a copy of the config with the module root trimmed from path
+// patterns written relative to the repository, such as "services/api/gen/", so they match
+// the module-relative paths of profiles and discovered files alike
+func (c *Config) withModuleRoot() *Config {
+	if c == nil || c.ModuleRoot == "" {
+		return c
+	}
+	root := strings.Trim(path.Clean("/"+filepath.ToSlash(c.ModuleRoot)), "/")
+
+	trimmed := *c
+	trimmed.ModuleRoot = root
+	trimmed.ExcludePaths = trimModuleRoot(c.ExcludePaths, root)
+	trimmed.IncludeOnlyPaths = trimModuleRoot(c.IncludeOnlyPaths, root)
+	return &trimmed
+}
+
+// trimModuleRoot removes the module root from the patterns that start with it
+func trimModuleRoot(patterns []string, root string) []string {
+	if patterns == nil {
+		return nil
+	}
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if rest, ok := strings.CutPrefix(pattern, root+"/"); ok && rest != "" {
+			pattern = rest
+		}
+		result = append(result, pattern)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleRootExclusions(t *testing.T) {
+	p := NewWithConfig(&Config{
+		ModuleRoot:   "./services/api/",
+		ExcludePaths: []string{"services/api/gen/", "vendor/", "services/other/"},
+	})
+	assert.Equal(t, "services/api", p.config.ModuleRoot)
+	assert.Equal(t, []string{"gen/", "vendor/", "services/other/"}, p.config.ExcludePaths)
+
+	coverage, err := p.Parse(context.Background(), strings.NewReader(`mode: set
+github.com/example/repo/services/api/gen/types.go:1.1,2.2 1 1
+github.com/example/repo/services/api/handler/handler.go:1.1,2.2 1 0
+`))
+	require.NoError(t, err)
+	require.Contains(t, coverage.Packages, "handler")
+	assert.NotContains(t, coverage.Packages, "gen")
+
+	// Configs without a module root are not copied
+	config := &Config{ExcludePaths: []string{"vendor/"}}
+	assert.Same(t, config, config.withModuleRoot())
+}
+
+func TestDiscoverEligibleFilesModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"tools/tool.go", "services/api/main.go", "services/api/gen/types.go", "services/api/handler/handler.go"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n"), 0o600))
+	}
+
+	p := NewWithConfig(&Config{ModuleRoot: "services/api", ExcludePaths: []string{"services/api/gen/"}})
+	assert.Equal(t, filepath.Join(root, "services", "api"), p.ModuleDir(root))
+
+	files, err := p.DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", filepath.Join("handler", "handler.go")}, files)
+
+	p = NewWithConfig(&Config{ModuleRoot: "services/api", IncludeOnlyPaths: []string{"services/api/handler/"}})
+	files, err = p.DiscoverEligibleFiles(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("handler", "handler.go")}, files)
+}
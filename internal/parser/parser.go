@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
@@ -24,6 +25,7 @@ var (
 	ErrMissingColon           = errors.New("invalid statement format: missing colon")
 	ErrMissingComma           = errors.New("invalid position format: missing comma")
 	ErrMissingDot             = errors.New("invalid position format: missing dot")
+	ErrProfileNotFound        = errors.New("coverage profile not found")
 )
 
 // CoverageData represents parsed coverage information
@@ -37,6 +39,8 @@ type CoverageData struct {
 	CoveredLines int                         `json:"covered_lines"` // Actually contains covered statement count
 	Percentage   float64                     `json:"percentage"`
 	Timestamp    time.Time                   `json:"timestamp"`
+	// ExclusionPresets lists the exclusion presets that were active while parsing
+	ExclusionPresets []string `json:"exclusion_presets,omitempty"`
 }
 
 // PackageCoverage represents coverage data for a single package
@@ -55,6 +59,9 @@ type FileCoverage struct {
 	TotalLines   int         `json:"total_lines"`   // Actually contains total statement count
 	CoveredLines int         `json:"covered_lines"` // Actually contains covered statement count
 	Percentage   float64     `json:"percentage"`
+	Class        FileClass   `json:"class,omitempty"`    // Handwritten, generated or test code
+	Language     string      `json:"language,omitempty"` // Set for files of LCOV and Cobertura reports
+	Module       string      `json:"module,omitempty"`   // Workspace module the file belongs to, when parsed with one
 }
 
 // Statement represents a coverage statement in Go coverage format
@@ -80,7 +87,26 @@ type Config struct {
 	IncludeOnlyPaths []string
 	ExcludeGenerated bool
 	ExcludeTestFiles bool
+	ExcludePresets   []string // Built-in exclusion presets, see ExclusionPresets
 	MinFileLines     int
+	Limits           Limits // Resource limits for untrusted profiles (zero values use DefaultLimits)
+	// ModuleRewrites replace module path prefixes of profile paths before any other processing
+	ModuleRewrites []ModuleRewrite
+	// ModuleRoot is the directory of the Go module relative to the repository root, empty
+	// when the module is at the root
+	ModuleRoot string
+	// DiscoveryIgnore are name or path patterns of trees file discovery skips, such as
+	// bazel-out, independent of the coverage exclusions
+	DiscoveryIgnore []string
+	// WorkspaceModules are the modules of a go.work workspace files are attributed to
+	WorkspaceModules []WorkspaceModule
+	// TestHelperPatterns are directory name patterns of test helper packages, such as
+	// testutil or mocks; nil uses DefaultTestHelperPatterns and an empty list none
+	TestHelperPatterns []string
+	// MergeRepeatedBlocks merges blocks a profile lists more than once, as profiles written
+	// with -coverpkg list a block once per test binary. ParseFiles merges the blocks of
+	// several profiles regardless.
+	MergeRepeatedBlocks bool
 }
 
 // New creates a new parser instance with default configuration
@@ -92,26 +118,39 @@ func New() *Parser {
 			ExcludeGenerated: true,
 			ExcludeTestFiles: true,
 			MinFileLines:     10,
+			DiscoveryIgnore:  DefaultDiscoveryIgnore(),
 		},
 	}
 }
 
 // NewWithConfig creates a new parser instance with custom configuration
 func NewWithConfig(config *Config) *Parser {
-	return &Parser{config: config}
+	return &Parser{config: config.withPresets().withModuleRoot()}
 }
 
 // ParseFile parses a coverage profile file and returns structured coverage data
 func (p *Parser) ParseFile(ctx context.Context, filename string) (*CoverageData, error) {
-	file, err := os.Open(filename) //nolint:gosec // filename is controlled and validated by caller
+	file, err := openProfile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open coverage file %q: %w", filename, err)
+		return nil, err
 	}
 	defer func() { _ = file.Close() }()
 
 	return p.Parse(ctx, file)
 }
 
+// openProfile opens a coverage profile, reporting a missing file as ErrProfileNotFound
+func openProfile(filename string) (*os.File, error) {
+	file, err := os.Open(filename) //nolint:gosec // filename is controlled and validated by caller
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open coverage file %q: %w: %w", filename, ErrProfileNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage file %q: %w", filename, err)
+	}
+	return file, nil
+}
+
 // StatementWithFile represents a coverage statement with its associated file
 type StatementWithFile struct {
 	Statement
@@ -121,26 +160,47 @@ type StatementWithFile struct {
 
 // Parse parses coverage data from an io.Reader
 func (p *Parser) Parse(ctx context.Context, reader io.Reader) (*CoverageData, error) {
-	scanner := bufio.NewScanner(reader)
+	mode, statements, err := p.parseStatements(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	return p.buildCoverageData(mode, statements, p.config.MergeRepeatedBlocks)
+}
+
+// parseStatements reads the mode and the statements of the files that are not excluded from
+// a coverage profile
+func (p *Parser) parseStatements(ctx context.Context, reader io.Reader) (string, []StatementWithFile, error) {
+	limits := p.config.Limits.withDefaults()
+
+	limited := newSizeLimitedReader(reader, limits.MaxFileSize)
+	scanner := bufio.NewScanner(limited)
+	scanner.Buffer(make([]byte, 0, min(limits.MaxLineLength, bufio.MaxScanTokenSize)), limits.MaxLineLength)
 
 	var mode string
 	var statements []StatementWithFile
+	seenFiles := make(map[string]struct{})
+	blocks := 0
 
 	lineNum := 0
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return "", nil, ctx.Err()
 		default:
 		}
 
+		// The final token of an oversized profile is a truncated line; report the size instead
+		if limited.exceeded() {
+			break
+		}
+
 		line := strings.TrimSpace(scanner.Text())
 		lineNum++
 
 		if lineNum == 1 {
 			// Parse mode line: "mode: atomic" or "mode: count"
 			if !strings.HasPrefix(line, "mode:") {
-				return nil, fmt.Errorf("%w, got %q", ErrInvalidCoverageMode, line)
+				return "", nil, fmt.Errorf("%w, got %q", ErrInvalidCoverageMode, line)
 			}
 			mode = strings.TrimSpace(strings.TrimPrefix(line, "mode:"))
 			continue
@@ -153,7 +213,22 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader) (*CoverageData, er
 		// Parse coverage statement
 		stmt, file, err := p.parseStatement(line)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+			return "", nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+		}
+		file = RewriteModulePath(file, p.config.ModuleRewrites)
+
+		// Reject hostile input before it can consume memory or reach the filesystem
+		if err = validateProfilePath(file); err != nil {
+			return "", nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+		}
+		if blocks++; blocks > limits.MaxBlocks {
+			return "", nil, fmt.Errorf("%w (limit %d)", ErrTooManyBlocks, limits.MaxBlocks)
+		}
+		if _, ok := seenFiles[file]; !ok {
+			if len(seenFiles) >= limits.MaxFiles {
+				return "", nil, fmt.Errorf("%w (limit %d)", ErrTooManyFiles, limits.MaxFiles)
+			}
+			seenFiles[file] = struct{}{}
 		}
 
 		// Check if file should be excluded
@@ -168,15 +243,18 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader) (*CoverageData, er
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading coverage data: %w", err)
+		if errors.Is(err, bufio.ErrTooLong) {
+			return "", nil, fmt.Errorf("%w after line %d (limit %d bytes)", ErrLineTooLong, lineNum, limits.MaxLineLength)
+		}
+		return "", nil, fmt.Errorf("error reading coverage data: %w", err)
 	}
 
 	// Check if we got a valid mode
 	if mode == "" {
-		return nil, ErrMissingModeDeclaration
+		return "", nil, ErrMissingModeDeclaration
 	}
 
-	return p.buildCoverageData(mode, statements)
+	return mode, statements, nil
 }
 
 // normalizeFilePath removes the module prefix from file paths to create relative paths.
@@ -263,6 +341,10 @@ func (p *Parser) parseStatement(line string) (Statement, string, error) {
 		return Statement{}, "", fmt.Errorf("invalid count %q: %w", parts[2], err)
 	}
 
+	if startLine < 0 || startCol < 0 || endLine < 0 || endCol < 0 || numStmt < 0 || count < 0 {
+		return Statement{}, "", fmt.Errorf("%w in %q", ErrNegativeValue, line)
+	}
+
 	return Statement{
 		StartLine: startLine,
 		StartCol:  startCol,
@@ -368,15 +450,38 @@ func (p *Parser) isGeneratedFile(filename string) bool {
 	return false
 }
 
-// buildCoverageData constructs the final coverage data structure
-func (p *Parser) buildCoverageData(mode string, statements []StatementWithFile) (*CoverageData, error) {
+// buildCoverageData constructs the final coverage data structure, merging blocks listed more than
+// once when mergeRepeated is set
+func (p *Parser) buildCoverageData(mode string, statements []StatementWithFile, mergeRepeated bool) (*CoverageData, error) {
 	packages := make(map[string]*PackageCoverage)
 
 	// Group statements by file (normalize filenames for relative paths)
 	fileStatements := make(map[string][]Statement)
+	fileBlocks := make(map[string]map[blockKey]int)
+	fileModules := make(map[string]string)
 	for _, stmt := range statements {
 		normalizedFilename := normalizeFilePath(stmt.Filename)
-		fileStatements[normalizedFilename] = append(fileStatements[normalizedFilename], stmt.Statement)
+		if len(p.config.WorkspaceModules) > 0 {
+			if _, ok := fileModules[normalizedFilename]; !ok {
+				fileModules[normalizedFilename] = p.moduleOf(stmt.Filename)
+			}
+		}
+		s := stmt.Statement
+		if !mergeRepeated {
+			fileStatements[normalizedFilename] = append(fileStatements[normalizedFilename], s)
+			continue
+		}
+		if fileBlocks[normalizedFilename] == nil {
+			fileBlocks[normalizedFilename] = make(map[blockKey]int)
+		}
+		key := blockKey{s.StartLine, s.StartCol, s.EndLine, s.EndCol}
+		if index, ok := fileBlocks[normalizedFilename][key]; ok {
+			merged := &fileStatements[normalizedFilename][index]
+			merged.Count = mergeCount(mode, merged.Count, s.Count)
+			continue
+		}
+		fileBlocks[normalizedFilename][key] = len(fileStatements[normalizedFilename])
+		fileStatements[normalizedFilename] = append(fileStatements[normalizedFilename], s)
 	}
 
 	// Build coverage data structure
@@ -394,6 +499,8 @@ func (p *Parser) buildCoverageData(mode string, statements []StatementWithFile)
 		}
 
 		fileCov := p.calculateFileCoverage(filename, stmts)
+		fileCov.Class = p.ClassifyFile(filename)
+		fileCov.Module = fileModules[filename]
 		packages[pkg].Files[filename] = fileCov
 
 		packages[pkg].TotalLines += fileCov.TotalLines
@@ -423,9 +530,20 @@ func (p *Parser) buildCoverageData(mode string, statements []StatementWithFile)
 		CoveredLines: coveredLines,
 		Percentage:   percentage,
 		Timestamp:    time.Now(),
+
+		ExclusionPresets: p.config.ExcludePresets,
 	}, nil
 }
 
+// mergeCount combines the hit counts of a block found more than once. Count and atomic profiles
+// record how often the block ran, so the counts add up; set profiles only record whether it ran.
+func mergeCount(mode string, a, b int) int {
+	if mode == "set" {
+		return max(a, b)
+	}
+	return a + b
+}
+
 // extractPackageName extracts the Go package name from a file path
 func (p *Parser) extractPackageName(filename string) string {
 	dir := filepath.Dir(filename)
@@ -435,50 +553,28 @@ func (p *Parser) extractPackageName(filename string) string {
 	return filepath.Base(dir)
 }
 
-// DiscoverEligibleFiles discovers all Go files that should be included in coverage based on exclusion rules
+// DiscoverEligibleFiles discovers all Go files that should be included in coverage based on exclusion rules.
+// rootPath is the repository root; files are returned relative to the module directory under ModuleRoot.
+// Trees matching DiscoveryIgnore are skipped, and symlinks are resolved so no file is counted twice.
 func (p *Parser) DiscoverEligibleFiles(ctx context.Context, rootPath string) ([]string, error) {
-	var eligibleFiles []string
-
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if err != nil {
-			return err
-		}
-
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
-
-		// Only consider Go files
-		if !strings.HasSuffix(path, ".go") {
-			return nil
-		}
-
-		// Convert to relative path for consistent exclusion checking
-		relPath, err := filepath.Rel(rootPath, path)
-		if err != nil {
-			relPath = path
-		}
-
-		// Check if file should be excluded using the same logic as coverage parsing
-		// Use relPath for pattern matching, but pass absolute path for isGeneratedFile
-		if !p.shouldExcludeFileForDiscovery(relPath, path) {
-			eligibleFiles = append(eligibleFiles, relPath)
-		}
-
-		return nil
-	})
+	rootPath = p.ModuleDir(rootPath)
+	realRoot, err := filepath.EvalSymlinks(rootPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover Go files: %w", err)
 	}
 
-	return eligibleFiles, nil
+	d := &discovery{
+		parser:   p,
+		root:     rootPath,
+		realRoot: realRoot,
+		dirs:     make(map[string]struct{}),
+		files:    make(map[string]struct{}),
+	}
+	if err = d.walk(ctx, rootPath); err != nil {
+		return nil, fmt.Errorf("failed to discover Go files: %w", err)
+	}
+
+	return d.eligible, nil
 }
 
 // shouldExcludeFileForDiscovery determines if a file should be excluded from file discovery
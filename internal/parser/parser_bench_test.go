@@ -133,7 +133,7 @@ func BenchmarkBuildCoverageData(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := parser.buildCoverageData("atomic", statements)
+		_, err := parser.buildCoverageData("atomic", statements, false)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -3,11 +3,14 @@
 package parser
 
 import (
+	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // FuzzParseStatementSimple tests the parseStatement method with basic validation
@@ -91,3 +94,68 @@ func FuzzShouldExcludeFileSimple(f *testing.F) {
 		}
 	})
 }
+
+// FuzzParseProfile feeds arbitrary profiles through the full parser under tight limits
+func FuzzParseProfile(f *testing.F) {
+	f.Add("mode: set\nfile.go:1.1,2.2 1 0\n")
+	f.Add("mode: atomic\npkg/a.go:10.5,15.20 5 3\npkg/b.go:1.1,1.2 1 1\n")
+	f.Add("mode: set\n../../etc/passwd:1.1,2.2 1 1\n")
+	f.Add("mode: set\n/abs/path.go:1.1,2.2 1 1\n")
+	f.Add("mode: set\nfile.go:-1.1,2.2 -1 -5\n")
+	f.Add("mode: count\n" + strings.Repeat("x", 300) + ":1.1,2.2 1 1\n")
+	f.Add("")
+
+	limits := Limits{MaxFileSize: 4096, MaxLineLength: 256, MaxFiles: 8, MaxBlocks: 32}
+
+	f.Fuzz(func(t *testing.T, profile string) {
+		parser := NewWithConfig(&Config{Limits: limits})
+
+		data, err := parser.Parse(context.Background(), strings.NewReader(profile))
+		if err != nil {
+			assert.Nil(t, data)
+			return
+		}
+
+		// Successful parses must respect every limit and contain only safe paths
+		assert.LessOrEqual(t, len(profile), int(limits.MaxFileSize))
+		files := 0
+		for _, pkg := range data.Packages {
+			for name, file := range pkg.Files {
+				files++
+				require.NoError(t, validateProfilePath(name))
+				for _, stmt := range file.Statements {
+					assert.GreaterOrEqual(t, stmt.NumStmt, 0)
+					assert.GreaterOrEqual(t, stmt.Count, 0)
+				}
+			}
+		}
+		assert.LessOrEqual(t, files, limits.MaxFiles)
+	})
+}
+
+// FuzzSafeOutputPath ensures generated output paths never escape the base directory
+func FuzzSafeOutputPath(f *testing.F) {
+	f.Add("internal/parser/parser.go")
+	f.Add("../outside.go")
+	f.Add("a/../../b.go")
+	f.Add("/etc/passwd")
+	f.Add("C:\\Windows\\system.ini")
+	f.Add("..\\..\\evil.go")
+	f.Add("./file.go")
+	f.Add("")
+
+	base := filepath.Join(string(filepath.Separator), "srv", "reports")
+
+	f.Fuzz(func(t *testing.T, name string) {
+		out, err := SafeOutputPath(base, name)
+		if err != nil {
+			require.ErrorIs(t, err, ErrUnsafeFilePath)
+			return
+		}
+
+		rel, relErr := filepath.Rel(base, out)
+		require.NoError(t, relErr)
+		assert.NotEqual(t, "..", rel)
+		assert.False(t, strings.HasPrefix(rel, ".."+string(filepath.Separator)), "path %q escaped to %q", name, out)
+	})
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -411,6 +412,34 @@ func TestParseComplexCoverage(t *testing.T) {
 	}
 }
 
+func TestParseMergesRepeatedBlocks(t *testing.T) {
+	// Profiles written with -coverpkg list a block once for every test binary
+	profile := `mode: %s
+github.com/example/repo/lib/lib.go:3.20,5.2 1 0
+github.com/example/repo/lib/lib.go:7.20,9.2 1 1
+github.com/example/repo/lib/lib.go:3.20,5.2 1 2
+github.com/example/repo/lib/lib.go:7.20,9.2 1 3
+`
+	for mode, want := range map[string][]int{"set": {2, 3}, "count": {2, 4}} {
+		t.Run(mode, func(t *testing.T) {
+			coverage, err := New().Parse(context.Background(), strings.NewReader(fmt.Sprintf(profile, mode)))
+			require.NoError(t, err)
+			assert.Equal(t, 4, coverage.TotalLines, "repeated blocks count on their own by default")
+			assert.Equal(t, 3, coverage.CoveredLines)
+
+			coverage, err = NewWithConfig(&Config{MergeRepeatedBlocks: true}).Parse(context.Background(), strings.NewReader(fmt.Sprintf(profile, mode)))
+			require.NoError(t, err)
+			assert.Equal(t, 2, coverage.TotalLines)
+			assert.Equal(t, 2, coverage.CoveredLines)
+
+			file := coverage.Packages["lib"].Files["repo/lib/lib.go"]
+			require.NotNil(t, file)
+			require.Len(t, file.Statements, 2)
+			assert.Equal(t, want, []int{file.Statements[0].Count, file.Statements[1].Count})
+		})
+	}
+}
+
 func TestIsGeneratedFile(t *testing.T) {
 	parser := New()
 
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownExclusionPreset indicates an exclusion preset name that is not built in
+var ErrUnknownExclusionPreset = errors.New("unknown exclusion preset")
+
+// ExclusionPreset is a named set of path and file patterns for code that is
+// usually not worth measuring, such as vendored dependencies or test doubles
+type ExclusionPreset struct {
+	Name        string
+	Description string
+	Paths       []string
+	Files       []string
+}
+
+// ExclusionPresets returns the built-in exclusion presets in display order
+func ExclusionPresets() []ExclusionPreset {
+	return []ExclusionPreset{
+		{
+			Name:        "vendor",
+			Description: "Vendored dependencies",
+			Paths:       []string{"vendor/"},
+		},
+		{
+			Name:        "third_party",
+			Description: "Third-party code copied into the repository",
+			Paths:       []string{"third_party/", "third-party/"},
+		},
+		{
+			Name:        "examples",
+			Description: "Example programs",
+			Paths:       []string{"examples/", "_examples/"},
+		},
+		{
+			Name:        "testdata",
+			Description: "Test fixtures",
+			Paths:       []string{"testdata/"},
+		},
+		{
+			Name:        "mocks",
+			Description: "Generated and handwritten mocks",
+			Paths:       []string{"mocks/", "mock/"},
+			Files:       []string{"mock_*.go", "*_mock.go", "*_mocks.go"},
+		},
+	}
+}
+
+// LookupExclusionPreset returns the built-in preset with the given name (case-insensitive)
+func LookupExclusionPreset(name string) (ExclusionPreset, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for _, preset := range ExclusionPresets() {
+		if preset.Name == normalized {
+			return preset, nil
+		}
+	}
+	return ExclusionPreset{}, fmt.Errorf("%w: %q", ErrUnknownExclusionPreset, name)
+}
+
+// Patterns returns the path and file patterns the preset excludes
+func (e ExclusionPreset) Patterns() []string {
+	return append(append([]string(nil), e.Paths...), e.Files...)
+}
+
+// withPresets returns a copy of the config with the patterns of its exclusion
+// presets appended to ExcludePaths and ExcludeFiles. Unknown names are skipped
+// since they are rejected when the configuration is validated.
+func (c *Config) withPresets() *Config {
+	if c == nil || len(c.ExcludePresets) == 0 {
+		return c
+	}
+
+	expanded := *c
+	expanded.ExcludePaths = append([]string(nil), c.ExcludePaths...)
+	expanded.ExcludeFiles = append([]string(nil), c.ExcludeFiles...)
+	expanded.ExcludePresets = make([]string, 0, len(c.ExcludePresets))
+	for _, name := range c.ExcludePresets {
+		preset, err := LookupExclusionPreset(name)
+		if err != nil {
+			continue
+		}
+		expanded.ExcludePaths = append(expanded.ExcludePaths, preset.Paths...)
+		expanded.ExcludeFiles = append(expanded.ExcludeFiles, preset.Files...)
+		expanded.ExcludePresets = append(expanded.ExcludePresets, preset.Name)
+	}
+	return &expanded
+}
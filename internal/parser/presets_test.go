@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupExclusionPreset(t *testing.T) {
+	preset, err := LookupExclusionPreset(" Third_Party ")
+	require.NoError(t, err)
+	assert.Equal(t, "third_party", preset.Name)
+	assert.Equal(t, []string{"third_party/", "third-party/"}, preset.Patterns())
+
+	_, err = LookupExclusionPreset("node_modules")
+	require.ErrorIs(t, err, ErrUnknownExclusionPreset)
+}
+
+func TestParseWithExclusionPresets(t *testing.T) {
+	profile := `mode: set
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/vendor/github.com/dep/dep.go:5.2,6.10 4 0
+github.com/example/repo/third_party/proto/proto.go:5.2,6.10 2 0
+github.com/example/repo/lib/store_mock.go:3.2,4.10 2 0
+github.com/example/repo/internal/mocks/client.go:3.2,4.10 2 0
+`
+	config := &Config{ExcludePresets: []string{"vendor", "mocks", "unknown"}}
+	coverage, err := NewWithConfig(config).Parse(context.Background(), strings.NewReader(profile))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"vendor", "mocks"}, coverage.ExclusionPresets)
+	assert.Equal(t, 5, coverage.TotalLines, "only lib.go and third_party remain")
+	assert.Contains(t, coverage.Packages, "proto")
+	assert.Empty(t, config.ExcludePaths, "the caller's config is not modified")
+}
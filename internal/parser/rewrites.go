@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidModuleRewrite indicates a module path rewrite rule that is not old=new
+var ErrInvalidModuleRewrite = errors.New("module path rewrite must be given as old-prefix=new-prefix")
+
+// ModuleRewrite replaces the module path prefix of profile paths, for repositories that were
+// forked or renamed or that use a vanity import path, so the paths match the repository layout
+type ModuleRewrite struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ParseModuleRewrite splits an "old-prefix=new-prefix" rule into a module path rewrite
+func ParseModuleRewrite(arg string) (ModuleRewrite, error) {
+	from, to, ok := strings.Cut(arg, "=")
+	from = strings.TrimSuffix(strings.TrimSpace(from), "/")
+	to = strings.TrimSuffix(strings.TrimSpace(to), "/")
+	if !ok || from == "" || to == "" {
+		return ModuleRewrite{}, fmt.Errorf("%w: %q", ErrInvalidModuleRewrite, arg)
+	}
+	return ModuleRewrite{From: from, To: to}, nil
+}
+
+// ParseModuleRewrites parses a list of "old-prefix=new-prefix" rules
+func ParseModuleRewrites(args []string) ([]ModuleRewrite, error) {
+	rewrites := make([]ModuleRewrite, 0, len(args))
+	for _, arg := range args {
+		rewrite, err := ParseModuleRewrite(arg)
+		if err != nil {
+			return nil, err
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+	return rewrites, nil
+}
+
+// RewriteModulePath applies the rule with the longest prefix matching the path at a path
+// segment boundary, so "github.com/old/repo" rewrites "github.com/old/repo/pkg/a.go" but
+// not "github.com/old/repo-tools/a.go". Paths no rule matches are returned unchanged.
+func RewriteModulePath(path string, rewrites []ModuleRewrite) string {
+	var match *ModuleRewrite
+	for i := range rewrites {
+		rewrite := &rewrites[i]
+		if path != rewrite.From && !strings.HasPrefix(path, rewrite.From+"/") {
+			continue
+		}
+		if match == nil || len(rewrite.From) > len(match.From) {
+			match = rewrite
+		}
+	}
+	if match == nil {
+		return path
+	}
+	return match.To + strings.TrimPrefix(path, match.From)
+}
+
+// WithModuleRewrites returns a parser with the configuration of p that also applies the
+// module path rewrites
+func (p *Parser) WithModuleRewrites(rewrites []ModuleRewrite) *Parser {
+	config := *p.config
+	config.ModuleRewrites = rewrites
+	return &Parser{config: &config}
+}
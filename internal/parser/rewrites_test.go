@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleRewrite(t *testing.T) {
+	rewrite, err := ParseModuleRewrite(" go.example.com/lib/ = github.com/example/lib ")
+	require.NoError(t, err)
+	assert.Equal(t, ModuleRewrite{From: "go.example.com/lib", To: "github.com/example/lib"}, rewrite)
+
+	for _, arg := range []string{"go.example.com/lib", "=github.com/example/lib", "go.example.com/lib=", "/=/"} {
+		_, err = ParseModuleRewrite(arg)
+		require.ErrorIs(t, err, ErrInvalidModuleRewrite, arg)
+	}
+
+	_, err = ParseModuleRewrites([]string{"a.com/x=b.com/x", "broken"})
+	require.ErrorIs(t, err, ErrInvalidModuleRewrite)
+}
+
+func TestRewriteModulePath(t *testing.T) {
+	rewrites := []ModuleRewrite{
+		{From: "github.com/upstream/lib", To: "github.com/fork/lib"},
+		{From: "github.com/upstream/lib/v2", To: "github.com/fork/lib"},
+		{From: "go.example.com/tool", To: "github.com/example/tool"},
+	}
+	tests := map[string]string{
+		"github.com/upstream/lib/pkg/a.go":       "github.com/fork/lib/pkg/a.go",
+		"github.com/upstream/lib/v2/pkg/a.go":    "github.com/fork/lib/pkg/a.go",
+		"github.com/upstream/lib-tools/pkg/a.go": "github.com/upstream/lib-tools/pkg/a.go",
+		"go.example.com/tool/main.go":            "github.com/example/tool/main.go",
+		"github.com/other/repo/a.go":             "github.com/other/repo/a.go",
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, RewriteModulePath(path, rewrites), path)
+	}
+	assert.Equal(t, "github.com/upstream/lib/a.go", RewriteModulePath("github.com/upstream/lib/a.go", nil))
+}
+
+func TestParseWithModuleRewrites(t *testing.T) {
+	profile := `mode: set
+github.com/upstream/lib/internal/vendorx/x.go:1.1,2.2 1 1
+go.example.com/lib/pkg/a.go:1.1,2.2 1 0
+`
+	p := NewWithConfig(&Config{
+		ExcludePaths: []string{"vendorx/"},
+		ModuleRewrites: []ModuleRewrite{
+			{From: "go.example.com/lib", To: "github.com/fork/lib"},
+			{From: "github.com/upstream/lib/internal/vendorx", To: "github.com/fork/lib/internal/x"},
+		},
+	})
+	coverage, err := p.Parse(context.Background(), strings.NewReader(profile))
+	require.NoError(t, err)
+
+	// Exclusions match the rewritten paths, and files are keyed by them
+	require.Contains(t, coverage.Packages, "x")
+	assert.Contains(t, coverage.Packages["x"].Files, "lib/internal/x/x.go")
+	require.Contains(t, coverage.Packages, "pkg")
+	assert.Contains(t, coverage.Packages["pkg"].Files, "lib/pkg/a.go")
+
+	// The rewrites of a derived parser leave the original untouched
+	derived := New().WithModuleRewrites(p.config.ModuleRewrites)
+	assert.Len(t, derived.config.ModuleRewrites, 2)
+	assert.Empty(t, New().config.ModuleRewrites)
+}
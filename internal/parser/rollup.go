@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RootDirectory is the rollup path of files at the top of the repository
+const RootDirectory = "."
+
+// DirectoryRollup is the coverage of every file below a directory, with the
+// subdirectories one level down as children
+type DirectoryRollup struct {
+	Path              string            `json:"path"`
+	Depth             int               `json:"depth"`
+	Files             int               `json:"files"`
+	TotalStatements   int               `json:"total_statements"`
+	CoveredStatements int               `json:"covered_statements"`
+	Percentage        float64           `json:"percentage"`
+	Children          []DirectoryRollup `json:"children,omitempty"`
+}
+
+// DirectoryRollup aggregates file coverage into a directory tree at most depth levels deep,
+// so that depth 1 shows only top-level directories. Files below the deepest level count
+// towards their ancestor at that level, and files at the top of the repository are rolled
+// up into RootDirectory. resolvePath maps profile paths to repository relative paths; nil
+// keeps them as they are. A depth below 1 returns nil.
+func (c *CoverageData) DirectoryRollup(depth int, resolvePath func(string) string) []DirectoryRollup {
+	if c == nil || depth < 1 {
+		return nil
+	}
+
+	nodes := make(map[string]*DirectoryRollup)
+	children := make(map[string][]string)
+	var roots []string
+
+	add := func(dirPath, parent string, level int, file *FileCoverage) {
+		node := nodes[dirPath]
+		if node == nil {
+			node = &DirectoryRollup{Path: dirPath, Depth: level}
+			nodes[dirPath] = node
+			if parent == "" {
+				roots = append(roots, dirPath)
+			} else {
+				children[parent] = append(children[parent], dirPath)
+			}
+		}
+		node.Files++
+		node.TotalStatements += file.TotalLines
+		node.CoveredStatements += file.CoveredLines
+	}
+
+	for _, pkg := range c.Packages {
+		for filePath, file := range pkg.Files {
+			if resolvePath != nil {
+				filePath = resolvePath(filePath)
+			}
+			dir := path.Dir(filepath.ToSlash(filePath))
+			if dir == "." || dir == "/" {
+				add(RootDirectory, "", 1, file)
+				continue
+			}
+
+			segments := strings.Split(strings.Trim(dir, "/"), "/")
+			parent := ""
+			for level := 1; level <= min(depth, len(segments)); level++ {
+				dirPath := strings.Join(segments[:level], "/")
+				add(dirPath, parent, level, file)
+				parent = dirPath
+			}
+		}
+	}
+
+	var build func(paths []string) []DirectoryRollup
+	build = func(paths []string) []DirectoryRollup {
+		slices.Sort(paths)
+		rollups := make([]DirectoryRollup, 0, len(paths))
+		for _, dirPath := range paths {
+			node := *nodes[dirPath]
+			if node.TotalStatements > 0 {
+				node.Percentage = float64(node.CoveredStatements) / float64(node.TotalStatements) * 100
+			}
+			if len(children[dirPath]) > 0 {
+				node.Children = build(children[dirPath])
+			}
+			rollups = append(rollups, node)
+		}
+		return rollups
+	}
+	return build(roots)
+}
+
+// Subset returns the coverage of the files keep accepts, with totals recomputed for them.
+// Packages without accepted files are left out.
+func (c *CoverageData) Subset(keep func(file string) bool) *CoverageData {
+	subset := &CoverageData{
+		Mode:             c.Mode,
+		Packages:         make(map[string]*PackageCoverage),
+		Timestamp:        c.Timestamp,
+		ExclusionPresets: c.ExclusionPresets,
+	}
+	for name, pkg := range c.Packages {
+		for filename, file := range pkg.Files {
+			if !keep(filename) {
+				continue
+			}
+			target := subset.Packages[name]
+			if target == nil {
+				target = &PackageCoverage{Name: pkg.Name, Files: make(map[string]*FileCoverage)}
+				subset.Packages[name] = target
+			}
+			target.Files[filename] = file
+		}
+	}
+	subset.recalculate()
+	return subset
+}
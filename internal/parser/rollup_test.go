@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rollupCoverage() *CoverageData {
+	file := func(total, covered int) *FileCoverage {
+		return &FileCoverage{TotalLines: total, CoveredLines: covered}
+	}
+	return &CoverageData{Packages: map[string]*PackageCoverage{
+		"repo": {Files: map[string]*FileCoverage{
+			"repo/main.go": file(2, 2),
+		}},
+		"badge": {Files: map[string]*FileCoverage{
+			"repo/internal/badge/badge.go": file(10, 5),
+		}},
+		"svg": {Files: map[string]*FileCoverage{
+			"repo/internal/badge/svg/svg.go": file(10, 10),
+		}},
+		"parser": {Files: map[string]*FileCoverage{
+			"repo/internal/parser/parser.go": file(20, 15),
+		}},
+		"cmd": {Files: map[string]*FileCoverage{
+			"repo/cmd/tool/main.go": file(4, 0),
+		}},
+	}}
+}
+
+func TestSubset(t *testing.T) {
+	subset := rollupCoverage().Subset(func(file string) bool {
+		return strings.HasPrefix(file, "repo/internal/badge/")
+	})
+
+	assert.Len(t, subset.Packages, 2)
+	assert.Equal(t, 20, subset.TotalLines)
+	assert.Equal(t, 15, subset.CoveredLines)
+	assert.InDelta(t, 75.0, subset.Percentage, 0.001)
+	assert.InDelta(t, 50.0, subset.Packages["badge"].Percentage, 0.001)
+}
+
+func TestDirectoryRollup(t *testing.T) {
+	trimRepo := func(p string) string { return strings.TrimPrefix(p, "repo/") }
+
+	t.Run("top-level directories only", func(t *testing.T) {
+		rollup := rollupCoverage().DirectoryRollup(1, trimRepo)
+		require.Len(t, rollup, 3)
+
+		assert.Equal(t, ".", rollup[0].Path)
+		assert.Equal(t, 1, rollup[0].Files)
+		assert.InDelta(t, 100.0, rollup[0].Percentage, 0.001)
+
+		assert.Equal(t, "cmd", rollup[1].Path)
+		assert.Zero(t, rollup[1].Percentage)
+
+		internal := rollup[2]
+		assert.Equal(t, "internal", internal.Path)
+		assert.Equal(t, 3, internal.Files)
+		assert.Equal(t, 40, internal.TotalStatements)
+		assert.Equal(t, 30, internal.CoveredStatements)
+		assert.InDelta(t, 75.0, internal.Percentage, 0.001)
+		assert.Empty(t, internal.Children)
+	})
+
+	t.Run("drill down", func(t *testing.T) {
+		rollup := rollupCoverage().DirectoryRollup(3, trimRepo)
+		internal := rollup[2]
+		require.Len(t, internal.Children, 2)
+
+		badge := internal.Children[0]
+		assert.Equal(t, "internal/badge", badge.Path)
+		assert.Equal(t, 2, badge.Depth)
+		assert.Equal(t, 2, badge.Files)
+		assert.InDelta(t, 75.0, badge.Percentage, 0.001)
+		require.Len(t, badge.Children, 1)
+		assert.Equal(t, "internal/badge/svg", badge.Children[0].Path)
+		assert.Equal(t, 3, badge.Children[0].Depth)
+
+		assert.Equal(t, "internal/parser", internal.Children[1].Path)
+		assert.Equal(t, "cmd/tool", rollup[1].Children[0].Path)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, rollupCoverage().DirectoryRollup(0, trimRepo))
+		assert.Nil(t, (*CoverageData)(nil).DirectoryRollup(2, nil))
+	})
+
+	t.Run("paths kept without resolver", func(t *testing.T) {
+		rollup := rollupCoverage().DirectoryRollup(1, nil)
+		require.Len(t, rollup, 1)
+		assert.Equal(t, "repo", rollup[0].Path)
+		assert.Equal(t, 5, rollup[0].Files)
+	})
+}
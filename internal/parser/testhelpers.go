@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultTestHelperPatterns returns the directory name patterns of packages holding test
+// support code by default: test utilities and helpers, mocks and fakes
+func DefaultTestHelperPatterns() []string {
+	return []string{"testutil*", "testhelper*", "testing", "mock", "mocks", "fake", "fakes"}
+}
+
+// testHelperPatterns returns the configured test helper patterns, or the defaults when none
+// were configured; an empty, non-nil list disables detection
+func (p *Parser) testHelperPatterns() []string {
+	if p.config.TestHelperPatterns == nil {
+		return DefaultTestHelperPatterns()
+	}
+	return p.config.TestHelperPatterns
+}
+
+// IsTestHelper reports whether a file belongs to a test helper package: a package with a
+// directory matching one of the test helper patterns anywhere in its path
+func (p *Parser) IsTestHelper(filename string) bool {
+	patterns := p.testHelperPatterns()
+	for _, segment := range strings.Split(path.Dir(filepath.ToSlash(filename)), "/") {
+		if matchesAny(segment, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitTestHelpers separates the files of test helper packages from the production code, so
+// helpers neither inflate nor deflate the production totals but keep numbers of their own
+func (p *Parser) SplitTestHelpers(coverage *CoverageData) (production, helpers *CoverageData) {
+	production = coverage.Subset(func(file string) bool { return !p.IsTestHelper(file) })
+	helpers = coverage.Subset(p.IsTestHelper)
+	return production, helpers
+}
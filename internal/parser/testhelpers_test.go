@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTestHelper(t *testing.T) {
+	p := New()
+	tests := map[string]bool{
+		"internal/testutil/helpers.go":   true,
+		"internal/testutils/db.go":       true,
+		"pkg/testhelpers/server.go":      true,
+		"store/mocks/store.go":           true,
+		"store/fakes/clock/clock.go":     true,
+		"internal/testing/fixtures.go":   true,
+		"store/store.go":                 false,
+		"internal/mockingbird/client.go": false,
+		"testutil.go":                    false,
+	}
+	for filename, expected := range tests {
+		assert.Equal(t, expected, p.IsTestHelper(filename), filename)
+	}
+
+	custom := NewWithConfig(&Config{TestHelperPatterns: []string{"*test"}})
+	assert.True(t, custom.IsTestHelper("storage/storagetest/fake.go"))
+	assert.False(t, custom.IsTestHelper("store/mocks/store.go"))
+
+	disabled := NewWithConfig(&Config{TestHelperPatterns: []string{}})
+	assert.False(t, disabled.IsTestHelper("internal/testutil/helpers.go"))
+	assert.Equal(t, ClassHandwritten, disabled.ClassifyFile("internal/testutil/helpers.go"))
+}
+
+func TestSplitTestHelpers(t *testing.T) {
+	coverage := &CoverageData{Packages: map[string]*PackageCoverage{
+		"store": {Name: "store", Files: map[string]*FileCoverage{
+			"store/store.go": {Path: "store/store.go", TotalLines: 8, CoveredLines: 6},
+		}},
+		"store/mocks": {Name: "store/mocks", Files: map[string]*FileCoverage{
+			"store/mocks/store.go": {Path: "store/mocks/store.go", TotalLines: 4, CoveredLines: 0},
+		}},
+	}}
+	coverage.recalculate()
+
+	production, helpers := New().SplitTestHelpers(coverage)
+	assert.Equal(t, []string{"store"}, packageNames(production.Packages))
+	assert.InDelta(t, 75.0, production.Percentage, 0.001)
+	assert.Equal(t, []string{"store/mocks"}, packageNames(helpers.Packages))
+	assert.Equal(t, 4, helpers.TotalLines)
+	assert.InDelta(t, 0.0, helpers.Percentage, 0.001)
+}
+
+// packageNames returns the package names of a coverage profile
+func packageNames(packages map[string]*PackageCoverage) []string {
+	keys := make([]string, 0, len(packages))
+	for key := range packages {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Static errors for build tag variants
+var (
+	ErrNoVariants        = errors.New("at least one coverage variant is required")
+	ErrDuplicateVariant  = errors.New("duplicate coverage variant name")
+	ErrInvalidVariant    = errors.New("coverage variant must have a name and coverage data")
+	ErrInvalidVariantArg = errors.New("coverage variant must be given as name=path")
+)
+
+// Variant is a coverage profile produced under one set of build tags, such as
+// `go test -tags integration`
+type Variant struct {
+	Name     string
+	Coverage *CoverageData
+}
+
+// VariantSummary is the coverage of a single build tag variant
+type VariantSummary struct {
+	Name              string  `json:"name"`
+	TotalStatements   int     `json:"total_statements"`
+	CoveredStatements int     `json:"covered_statements"`
+	Percentage        float64 `json:"percentage"`
+	// ExclusiveStatements are covered by this variant and by no other
+	ExclusiveStatements int `json:"exclusive_statements"`
+}
+
+// VariantFile lists the statements of a file whose coverage depends on the variant
+type VariantFile struct {
+	Path      string         `json:"path"`
+	Exclusive map[string]int `json:"exclusive,omitempty"` // Variant name -> statements only it covers
+	Uncovered int            `json:"uncovered"`           // Statements covered by no variant
+}
+
+// VariantBreakdown is the build tag dimension of a merged coverage profile
+type VariantBreakdown struct {
+	Variants []VariantSummary `json:"variants"`
+	// UncoveredStatements are not covered under any variant
+	UncoveredStatements int `json:"uncovered_statements"`
+	// Files with statements that only some or none of the variants cover, sorted by path
+	Files []VariantFile `json:"files,omitempty"`
+}
+
+// blockKey identifies a coverage block within a file independent of its hit count
+type blockKey struct {
+	startLine, startCol, endLine, endCol int
+}
+
+// mergedBlock is a coverage block along with the variants that cover it
+type mergedBlock struct {
+	stmt      Statement
+	coveredBy []int
+}
+
+// ParseVariantArg splits a "name=path" command line argument into a variant name and profile path
+func ParseVariantArg(arg string) (string, string, error) {
+	name, path, ok := strings.Cut(arg, "=")
+	name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+	if !ok || name == "" || path == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidVariantArg, arg)
+	}
+	return name, path, nil
+}
+
+// MergeVariants combines the profiles of several build tag variants into one profile in which
+// a block is covered when any variant covers it. The breakdown reports per-variant totals, the
+// statements only one variant covers and the statements that no variant covers at all.
+func (p *Parser) MergeVariants(variants []Variant) (*CoverageData, *VariantBreakdown, error) {
+	if len(variants) == 0 {
+		return nil, nil, ErrNoVariants
+	}
+
+	seen := make(map[string]struct{}, len(variants))
+	for _, variant := range variants {
+		if variant.Name == "" || variant.Coverage == nil {
+			return nil, nil, ErrInvalidVariant
+		}
+		if _, ok := seen[variant.Name]; ok {
+			return nil, nil, fmt.Errorf("%w: %q", ErrDuplicateVariant, variant.Name)
+		}
+		seen[variant.Name] = struct{}{}
+	}
+
+	// Collect blocks per file, remembering which package and class each file belongs to
+	blocks := make(map[string]map[blockKey]*mergedBlock)
+	filePackages := make(map[string]string)
+	fileClasses := make(map[string]FileClass)
+	mode := ""
+	for index, variant := range variants {
+		if mode == "" {
+			mode = variant.Coverage.Mode
+		}
+		for pkgName, pkg := range variant.Coverage.Packages {
+			for path, file := range pkg.Files {
+				if blocks[path] == nil {
+					blocks[path] = make(map[blockKey]*mergedBlock)
+					filePackages[path] = pkgName
+					fileClasses[path] = file.Class
+				}
+				for _, stmt := range file.Statements {
+					key := blockKey{stmt.StartLine, stmt.StartCol, stmt.EndLine, stmt.EndCol}
+					block := blocks[path][key]
+					if block == nil {
+						block = &mergedBlock{stmt: stmt}
+						block.stmt.Count = 0
+						blocks[path][key] = block
+					}
+					block.stmt.Count = mergeCount(mode, block.stmt.Count, stmt.Count)
+					if stmt.Count > 0 {
+						block.coveredBy = append(block.coveredBy, index)
+					}
+				}
+			}
+		}
+	}
+
+	breakdown := &VariantBreakdown{Variants: make([]VariantSummary, len(variants))}
+	for index, variant := range variants {
+		breakdown.Variants[index] = VariantSummary{
+			Name:              variant.Name,
+			TotalStatements:   variant.Coverage.TotalLines,
+			CoveredStatements: variant.Coverage.CoveredLines,
+			Percentage:        variant.Coverage.Percentage,
+		}
+	}
+
+	merged := &CoverageData{
+		Mode:             mode,
+		Packages:         make(map[string]*PackageCoverage),
+		Timestamp:        time.Now(),
+		ExclusionPresets: p.config.ExcludePresets,
+	}
+
+	paths := make([]string, 0, len(blocks))
+	for path := range blocks {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		statements := make([]Statement, 0, len(blocks[path]))
+		variantFile := VariantFile{Path: path}
+		for _, block := range blocks[path] {
+			statements = append(statements, block.stmt)
+			switch len(block.coveredBy) {
+			case 0:
+				variantFile.Uncovered += block.stmt.NumStmt
+			case 1:
+				name := variants[block.coveredBy[0]].Name
+				if variantFile.Exclusive == nil {
+					variantFile.Exclusive = make(map[string]int)
+				}
+				variantFile.Exclusive[name] += block.stmt.NumStmt
+				breakdown.Variants[block.coveredBy[0]].ExclusiveStatements += block.stmt.NumStmt
+			}
+		}
+		slices.SortFunc(statements, func(a, b Statement) int {
+			return cmp.Or(cmp.Compare(a.StartLine, b.StartLine), cmp.Compare(a.StartCol, b.StartCol))
+		})
+
+		breakdown.UncoveredStatements += variantFile.Uncovered
+		if variantFile.Uncovered > 0 || len(variantFile.Exclusive) > 0 {
+			breakdown.Files = append(breakdown.Files, variantFile)
+		}
+
+		pkgName := filePackages[path]
+		pkg := merged.Packages[pkgName]
+		if pkg == nil {
+			pkg = &PackageCoverage{Name: pkgName, Files: make(map[string]*FileCoverage)}
+			merged.Packages[pkgName] = pkg
+		}
+		fileCov := p.calculateFileCoverage(path, statements)
+		fileCov.Class = fileClasses[path]
+		pkg.Files[path] = fileCov
+		pkg.TotalLines += fileCov.TotalLines
+		pkg.CoveredLines += fileCov.CoveredLines
+		merged.TotalLines += fileCov.TotalLines
+		merged.CoveredLines += fileCov.CoveredLines
+	}
+
+	for _, pkg := range merged.Packages {
+		if pkg.TotalLines > 0 {
+			pkg.Percentage = float64(pkg.CoveredLines) / float64(pkg.TotalLines) * 100
+		}
+	}
+	if merged.TotalLines > 0 {
+		merged.Percentage = float64(merged.CoveredLines) / float64(merged.TotalLines) * 100
+	}
+
+	return merged, breakdown, nil
+}
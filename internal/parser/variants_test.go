@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariantArg(t *testing.T) {
+	name, path, err := ParseVariantArg("integration=coverage-integration.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "integration", name)
+	assert.Equal(t, "coverage-integration.txt", path)
+
+	for _, arg := range []string{"integration", "=coverage.txt", "unit="} {
+		_, _, err = ParseVariantArg(arg)
+		require.ErrorIs(t, err, ErrInvalidVariantArg, arg)
+	}
+}
+
+func TestMergeVariants(t *testing.T) {
+	p := NewWithConfig(&Config{})
+	parse := func(profile string) *CoverageData {
+		coverage, err := p.Parse(context.Background(), strings.NewReader(profile))
+		require.NoError(t, err)
+		return coverage
+	}
+
+	unit := parse(`mode: count
+github.com/example/repo/lib/lib.go:10.2,12.16 3 2
+github.com/example/repo/lib/lib.go:15.2,17.16 2 0
+github.com/example/repo/lib/lib.go:20.2,21.10 1 0
+github.com/example/repo/lib/unix.go:5.2,6.10 4 1
+`)
+	integration := parse(`mode: count
+github.com/example/repo/lib/lib.go:10.2,12.16 3 1
+github.com/example/repo/lib/lib.go:15.2,17.16 2 5
+github.com/example/repo/lib/lib.go:20.2,21.10 1 0
+github.com/example/repo/db/db.go:3.2,4.10 2 0
+`)
+
+	merged, breakdown, err := p.MergeVariants([]Variant{{Name: "unit", Coverage: unit}, {Name: "integration", Coverage: integration}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 12, merged.TotalLines)
+	assert.Equal(t, 9, merged.CoveredLines)
+	assert.InDelta(t, 75.0, merged.Percentage, 0.001)
+	lib := merged.Packages["lib"].Files["repo/lib/lib.go"]
+	require.NotNil(t, lib)
+	assert.Equal(t, 3, lib.Statements[0].Count, "hit counts are summed across variants")
+	assert.Equal(t, 5, lib.CoveredLines)
+
+	assert.Equal(t, []VariantSummary{
+		{Name: "unit", TotalStatements: 10, CoveredStatements: 7, Percentage: 70, ExclusiveStatements: 4},
+		{Name: "integration", TotalStatements: 8, CoveredStatements: 5, Percentage: 62.5, ExclusiveStatements: 2},
+	}, breakdown.Variants)
+	assert.Equal(t, 3, breakdown.UncoveredStatements)
+	assert.Equal(t, []VariantFile{
+		{Path: "repo/db/db.go", Uncovered: 2},
+		{Path: "repo/lib/lib.go", Exclusive: map[string]int{"integration": 2}, Uncovered: 1},
+		{Path: "repo/lib/unix.go", Exclusive: map[string]int{"unit": 4}},
+	}, breakdown.Files)
+
+	t.Run("set mode", func(t *testing.T) {
+		unit := parse("mode: set\ngithub.com/example/repo/lib/lib.go:10.2,12.16 3 1\n")
+		integration := parse("mode: set\ngithub.com/example/repo/lib/lib.go:10.2,12.16 3 1\n")
+		merged, _, err := p.MergeVariants([]Variant{{Name: "unit", Coverage: unit}, {Name: "integration", Coverage: integration}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, merged.Packages["lib"].Files["repo/lib/lib.go"].Statements[0].Count, "set profiles only record whether a block ran")
+	})
+}
+
+func TestMergeVariantsErrors(t *testing.T) {
+	p := New()
+	coverage := &CoverageData{Packages: map[string]*PackageCoverage{}}
+
+	_, _, err := p.MergeVariants(nil)
+	require.ErrorIs(t, err, ErrNoVariants)
+
+	_, _, err = p.MergeVariants([]Variant{{Name: "unit"}})
+	require.ErrorIs(t, err, ErrInvalidVariant)
+
+	_, _, err = p.MergeVariants([]Variant{{Name: "unit", Coverage: coverage}, {Name: "unit", Coverage: coverage}})
+	require.ErrorIs(t, err, ErrDuplicateVariant)
+}
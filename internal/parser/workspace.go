@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Static errors for go.work workspaces
+var (
+	ErrNoProfiles         = errors.New("at least one coverage profile is required")
+	ErrMixedCoverageModes = errors.New("coverage profiles use different modes")
+	ErrNoWorkspaceModules = errors.New("go.work uses no modules")
+	ErrMissingModulePath  = errors.New("go.mod declares no module path")
+)
+
+// WorkspaceModule is a module a go.work workspace uses
+type WorkspaceModule struct {
+	Dir  string `json:"dir"`  // Directory relative to the workspace root, slash-separated ("." for the root)
+	Path string `json:"path"` // Module path declared in its go.mod
+}
+
+// ModuleCoverage is the coverage total of the files of one workspace module
+type ModuleCoverage struct {
+	Module            WorkspaceModule `json:"module"`
+	Files             int             `json:"files"`
+	TotalStatements   int             `json:"total_statements"`
+	CoveredStatements int             `json:"covered_statements"`
+	Percentage        float64         `json:"percentage"`
+}
+
+// LoadWorkspace reads the modules the go.work file in root uses, sorted by directory
+func LoadWorkspace(root string) ([]WorkspaceModule, error) {
+	file, err := os.Open(filepath.Join(root, "go.work")) //nolint:gosec // root is the repository root
+	if err != nil {
+		return nil, fmt.Errorf("failed to open go.work: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var dirs []string
+	inUseBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case inUseBlock && line == ")":
+			inUseBlock = false
+		case inUseBlock:
+			dirs = append(dirs, unquoteModfileToken(line))
+		case line == "use (" || strings.ReplaceAll(line, " ", "") == "use(":
+			inUseBlock = true
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, unquoteModfileToken(strings.TrimSpace(strings.TrimPrefix(line, "use "))))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+
+	modules := make([]WorkspaceModule, 0, len(dirs))
+	for _, dir := range dirs {
+		dir = path.Clean(filepath.ToSlash(dir))
+		modulePath, pathErr := readModulePath(filepath.Join(root, filepath.FromSlash(dir), "go.mod"))
+		if pathErr != nil {
+			return nil, pathErr
+		}
+		modules = append(modules, WorkspaceModule{Dir: dir, Path: modulePath})
+	}
+	if len(modules) == 0 {
+		return nil, ErrNoWorkspaceModules
+	}
+	slices.SortFunc(modules, func(a, b WorkspaceModule) int { return cmp.Compare(a.Dir, b.Dir) })
+	return slices.CompactFunc(modules, func(a, b WorkspaceModule) bool { return a.Dir == b.Dir }), nil
+}
+
+// readModulePath returns the module path declared in a go.mod file
+func readModulePath(goMod string) (string, error) {
+	data, err := os.ReadFile(goMod) //nolint:gosec // go.mod of a module the workspace uses
+	if err != nil {
+		return "", fmt.Errorf("failed to read workspace module: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			if modulePath := unquoteModfileToken(strings.TrimSpace(rest)); modulePath != "" {
+				return modulePath, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrMissingModulePath, goMod)
+}
+
+// unquoteModfileToken removes the quotes of a quoted go.mod or go.work token
+func unquoteModfileToken(token string) string {
+	if unquoted, err := strconv.Unquote(token); err == nil {
+		return unquoted
+	}
+	return token
+}
+
+// moduleOf returns the path of the workspace module with the longest path the import path is in
+func (p *Parser) moduleOf(importPath string) string {
+	match := ""
+	for _, module := range p.config.WorkspaceModules {
+		if (importPath == module.Path || strings.HasPrefix(importPath, module.Path+"/")) && len(module.Path) > len(match) {
+			match = module.Path
+		}
+	}
+	return match
+}
+
+// ParseFiles parses several coverage profiles, such as one per module of a workspace, into one
+// coverage data set. Blocks found in several profiles are merged like the repeated blocks of a
+// -coverpkg profile, so all profiles must use the same mode.
+func (p *Parser) ParseFiles(ctx context.Context, filenames ...string) (*CoverageData, error) {
+	if len(filenames) == 0 {
+		return nil, ErrNoProfiles
+	}
+
+	var mode string
+	var statements []StatementWithFile
+	for _, filename := range filenames {
+		file, err := openProfile(filename)
+		if err != nil {
+			return nil, err
+		}
+		profileMode, profileStatements, err := p.parseStatements(ctx, file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+		if mode != "" && profileMode != mode {
+			return nil, fmt.Errorf("%w: %s is %q, expected %q", ErrMixedCoverageModes, filename, profileMode, mode)
+		}
+		mode = profileMode
+		statements = append(statements, profileStatements...)
+	}
+	return p.buildCoverageData(mode, statements, len(filenames) > 1 || p.config.MergeRepeatedBlocks)
+}
+
+// ModuleBreakdown returns the coverage totals of the workspace modules in the order given,
+// leaving out modules without files in the profile
+func (c *CoverageData) ModuleBreakdown(modules []WorkspaceModule) []ModuleCoverage {
+	totals := make(map[string]*ModuleCoverage, len(modules))
+	for _, module := range modules {
+		totals[module.Path] = &ModuleCoverage{Module: module}
+	}
+	for _, pkg := range c.Packages {
+		for _, file := range pkg.Files {
+			total := totals[file.Module]
+			if total == nil {
+				continue
+			}
+			total.Files++
+			total.TotalStatements += file.TotalLines
+			total.CoveredStatements += file.CoveredLines
+		}
+	}
+
+	breakdown := make([]ModuleCoverage, 0, len(modules))
+	for _, module := range modules {
+		total := totals[module.Path]
+		if total.Files == 0 {
+			continue
+		}
+		if total.TotalStatements > 0 {
+			total.Percentage = float64(total.CoveredStatements) / float64(total.TotalStatements) * 100
+		}
+		breakdown = append(breakdown, *total)
+	}
+	return breakdown
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile creates a file with the content at the slash-separated path below root
+func writeFile(t *testing.T, root, file, content string) string {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(file))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.work", `go 1.24
+
+use ./tools // build tooling
+use (
+	.
+	"./services/api"
+	./services/api/
+)
+`)
+	writeFile(t, root, "go.mod", "module example.com/repo\n")
+	writeFile(t, root, "tools/go.mod", "// Tooling\nmodule example.com/repo/tools // tools\n\ngo 1.24\n")
+	writeFile(t, root, "services/api/go.mod", "module \"example.com/api\"\n")
+
+	modules, err := LoadWorkspace(root)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkspaceModule{
+		{Dir: ".", Path: "example.com/repo"},
+		{Dir: "services/api", Path: "example.com/api"},
+		{Dir: "tools", Path: "example.com/repo/tools"},
+	}, modules)
+}
+
+func TestLoadWorkspaceErrors(t *testing.T) {
+	_, err := LoadWorkspace(t.TempDir())
+	require.Error(t, err)
+
+	root := t.TempDir()
+	writeFile(t, root, "go.work", "go 1.24\n")
+	_, err = LoadWorkspace(root)
+	require.ErrorIs(t, err, ErrNoWorkspaceModules)
+
+	writeFile(t, root, "go.work", "go 1.24\nuse ./missing\n")
+	_, err = LoadWorkspace(root)
+	require.Error(t, err)
+
+	writeFile(t, root, "empty/go.mod", "go 1.24\n")
+	writeFile(t, root, "go.work", "go 1.24\nuse ./empty\n")
+	_, err = LoadWorkspace(root)
+	require.ErrorIs(t, err, ErrMissingModulePath)
+}
+
+func TestParseFilesWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	root := writeFile(t, dir, "root.out", `mode: set
+example.com/repo/main.go:1.1,2.2 2 1
+example.com/repo/tools/gen.go:1.1,2.2 1 0
+`)
+	api := writeFile(t, dir, "api.out", `mode: set
+example.com/api/handler.go:1.1,2.2 3 0
+example.com/api/handler.go:3.1,4.2 1 1
+example.com/repo/main.go:1.1,2.2 2 0
+`)
+
+	modules := []WorkspaceModule{
+		{Dir: ".", Path: "example.com/repo"},
+		{Dir: "services/api", Path: "example.com/api"},
+		{Dir: "tools", Path: "example.com/repo/tools"},
+	}
+	p := NewWithConfig(&Config{WorkspaceModules: modules})
+	coverage, err := p.ParseFiles(context.Background(), root, api)
+	require.NoError(t, err)
+
+	// main.go is covered by the root profile, so the merged block stays covered
+	assert.Equal(t, 3, coverage.CoveredLines)
+	assert.Equal(t, 7, coverage.TotalLines)
+	fileModules := make(map[string]string)
+	for _, pkg := range coverage.Packages {
+		for _, file := range pkg.Files {
+			fileModules[filepath.Base(file.Path)] = file.Module
+		}
+	}
+	assert.Equal(t, map[string]string{"main.go": "example.com/repo", "handler.go": "example.com/api", "gen.go": "example.com/repo/tools"}, fileModules)
+
+	breakdown := coverage.ModuleBreakdown(modules)
+	require.Len(t, breakdown, 3)
+	assert.Equal(t, ModuleCoverage{Module: modules[0], Files: 1, TotalStatements: 2, CoveredStatements: 2, Percentage: 100}, breakdown[0])
+	assert.Equal(t, ModuleCoverage{Module: modules[1], Files: 1, TotalStatements: 4, CoveredStatements: 1, Percentage: 25}, breakdown[1])
+	assert.Equal(t, ModuleCoverage{Module: modules[2], Files: 1, TotalStatements: 1}, breakdown[2])
+}
+
+func TestParseFilesErrors(t *testing.T) {
+	p := New()
+	ctx := context.Background()
+
+	_, err := p.ParseFiles(ctx)
+	require.ErrorIs(t, err, ErrNoProfiles)
+
+	dir := t.TempDir()
+	_, err = p.ParseFiles(ctx, filepath.Join(dir, "missing.out"))
+	require.ErrorIs(t, err, ErrProfileNotFound)
+
+	set := writeFile(t, dir, "set.out", "mode: set\nexample.com/repo/a.go:1.1,2.2 1 1\n")
+	count := writeFile(t, dir, "count.out", "mode: count\nexample.com/repo/b.go:1.1,2.2 1 3\n")
+	_, err = p.ParseFiles(ctx, set, count)
+	require.ErrorIs(t, err, ErrMixedCoverageModes)
+}
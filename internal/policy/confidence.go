@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+)
+
+// minConfidenceRuns is the number of earlier runs needed before a confidence band is estimated
+const minConfidenceRuns = 3
+
+// Interval is a confidence band around the reported coverage, estimated from the run-to-run
+// noise of recent history
+type Interval struct {
+	Coverage float64 `json:"coverage"` // Reported coverage percentage
+	Lower    float64 `json:"lower"`    // Lower bound of the band
+	Upper    float64 `json:"upper"`    // Upper bound of the band
+	Margin   float64 `json:"margin"`   // Half-width of the band in percentage points
+	Level    float64 `json:"level"`    // Confidence level in percent, e.g. 95
+	Runs     int     `json:"runs"`     // Number of earlier runs the noise was estimated from
+}
+
+// ConfidenceInterval estimates a confidence band around current from the coverage of earlier
+// runs, newest first. The noise is the mean squared successive difference of the series, so a
+// steady trend in coverage does not widen the band the way a plain variance would; only the
+// jitter between consecutive runs (sharding, flaky tests) does. It reports false with fewer than
+// three earlier runs or a level outside (0, 100).
+func ConfidenceInterval(current float64, previous []float64, level float64) (Interval, bool) {
+	if len(previous) < minConfidenceRuns || level <= 0 || level >= 100 {
+		return Interval{}, false
+	}
+
+	series := append([]float64{current}, previous...)
+	var sum float64
+	for i := 1; i < len(series); i++ {
+		diff := series[i-1] - series[i]
+		sum += diff * diff
+	}
+	deviation := math.Sqrt(sum / float64(2*(len(series)-1)))
+	margin := math.Sqrt2 * math.Erfinv(level/100) * deviation
+
+	return Interval{
+		Coverage: current,
+		Lower:    math.Max(current-margin, 0),
+		Upper:    math.Min(current+margin, 100),
+		Margin:   margin,
+		Level:    level,
+		Runs:     len(previous),
+	}, true
+}
+
+// String renders the band as "82.40% ± 0.60"
+func (i Interval) String() string {
+	return fmt.Sprintf("%.2f%% ± %.2f", i.Coverage, i.Margin)
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfidenceInterval(t *testing.T) {
+	t.Run("needs history", func(t *testing.T) {
+		_, ok := ConfidenceInterval(82.4, []float64{82, 82.6}, 95)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects invalid levels", func(t *testing.T) {
+		_, ok := ConfidenceInterval(82.4, []float64{82, 82.6, 82.2}, 100)
+		assert.False(t, ok)
+	})
+
+	t.Run("estimates the band from run-to-run noise", func(t *testing.T) {
+		band, ok := ConfidenceInterval(82.4, []float64{82, 82.6, 82.2}, 95)
+		require.True(t, ok)
+		assert.InDelta(t, 0.66, band.Margin, 0.005)
+		assert.InDelta(t, 81.74, band.Lower, 0.005)
+		assert.InDelta(t, 83.06, band.Upper, 0.005)
+		assert.Equal(t, 3, band.Runs)
+		assert.Equal(t, "82.40% ± 0.66", band.String())
+	})
+
+	t.Run("steady trend does not widen the band", func(t *testing.T) {
+		band, ok := ConfidenceInterval(84, []float64{83, 82, 81, 80}, 95)
+		require.True(t, ok)
+		trendless, _ := ConfidenceInterval(84, []float64{80, 84, 80, 84}, 95)
+		assert.Less(t, band.Margin, trendless.Margin)
+	})
+
+	t.Run("clamps to percentages", func(t *testing.T) {
+		band, ok := ConfidenceInterval(99.5, []float64{97, 100, 97}, 99)
+		require.True(t, ok)
+		assert.InDelta(t, 100, band.Upper, 0)
+	})
+}
+
+func TestEvaluateThresholdLowerBound(t *testing.T) {
+	band, ok := ConfidenceInterval(80.5, []float64{79.5, 80.7, 79.9}, 95)
+	require.True(t, ok)
+	input := Input{Coverage: 80.5, Confidence: &band}
+
+	decision := NewEngine(Config{Threshold: 80, MaxDrop: -1}).Evaluate(input)
+	assert.True(t, decision.Passed)
+	assert.Equal(t, &band, decision.Confidence)
+
+	decision = NewEngine(Config{Threshold: 80, MaxDrop: -1, LowerBound: true}).Evaluate(input)
+	assert.True(t, decision.Failed(RuleThreshold))
+	assert.Contains(t, resultFor(t, decision, RuleThreshold).Message, "is below the 80.00% threshold")
+
+	// Without a band the lower bound is the coverage itself
+	decision = NewEngine(Config{Threshold: 80, MaxDrop: -1, LowerBound: true}).Evaluate(Input{Coverage: 80.5})
+	assert.True(t, decision.Passed)
+}
+
+func TestEngineGateConfidence(t *testing.T) {
+	band := Interval{Coverage: 80, Lower: 79.2, Upper: 80.8, Margin: 0.8, Level: 95, Runs: 5}
+	engine := NewEngine(Config{Threshold: 50, MaxDrop: -1, Gate: "lower >= 79 && margin < 1"})
+
+	assert.True(t, engine.Evaluate(Input{Coverage: 80, Confidence: &band}).Passed)
+
+	// Without a band, the comparisons are skipped
+	decision := engine.Evaluate(Input{Coverage: 80})
+	assert.True(t, decision.Passed)
+}
@@ -0,0 +1,495 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Gate expression errors
+var (
+	ErrInvalidExpression = errors.New("invalid gate expression")
+	ErrUnknownMetric     = errors.New("unknown gate metric")
+)
+
+// Metrics that gate expressions may reference
+const (
+	MetricTotal      = "total"      // Overall coverage percentage
+	MetricBase       = "base"       // Coverage percentage of the comparison base
+	MetricDelta      = "delta"      // Change versus the base in percentage points
+	MetricPatch      = "patch"      // Coverage percentage of the statements touched by the change
+	MetricStatements = "statements" // Total number of statements
+	MetricCovered    = "covered"    // Number of covered statements
+	MetricLower      = "lower"      // Lower bound of the confidence band around total
+	MetricUpper      = "upper"      // Upper bound of the confidence band around total
+	MetricMargin     = "margin"     // Half-width of the confidence band in percentage points
+)
+
+// GateMetrics returns the metric names gate expressions may reference
+func GateMetrics() []string {
+	return []string{MetricTotal, MetricBase, MetricDelta, MetricPatch, MetricStatements, MetricCovered, MetricLower, MetricUpper, MetricMargin}
+}
+
+// Expression is a compiled gate expression such as "total >= 80 && patch >= 90 && delta >= -0.5".
+//
+// The language supports numbers, metric names, + and -, the comparisons >= > <= < == !=,
+// the boolean operators && || ! and parentheses.
+type Expression struct {
+	source string
+	root   boolNode
+}
+
+// ParseExpression compiles a gate expression
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidExpression, tok.text, tok.pos)
+	}
+
+	root, ok := node.(boolNode)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q does not compare anything", ErrInvalidExpression, source)
+	}
+	return &Expression{source: strings.TrimSpace(source), root: root}, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate reports whether the expression holds for the given metrics, with one trace line
+// per comparison in source order. Metrics missing from the map are unavailable: comparisons
+// that use them are skipped and drop out of the expression, so "!(patch < 90)" and
+// "total >= 80 && patch >= 90" are decided by the available metrics alone. An expression
+// without any available comparison holds.
+func (e *Expression) Evaluate(metrics map[string]float64) (bool, []string) {
+	var trace []string
+	return e.root.eval(metrics, &trace) != verdictFalse, trace
+}
+
+// verdict is the result of a boolean expression: true, false, or skipped when it depends
+// only on unavailable metrics
+type verdict int
+
+const (
+	verdictFalse verdict = iota
+	verdictTrue
+	verdictSkipped
+)
+
+// verdictOf converts a boolean to a verdict
+func verdictOf(b bool) verdict {
+	if b {
+		return verdictTrue
+	}
+	return verdictFalse
+}
+
+// boolNode is an expression that yields a verdict
+type boolNode interface {
+	eval(metrics map[string]float64, trace *[]string) verdict
+}
+
+// numberNode is an expression that yields a number. Missing names the first unavailable metric.
+type numberNode interface {
+	value(metrics map[string]float64) (result float64, missing string)
+	String() string
+}
+
+type numberLiteral struct {
+	number float64
+	text   string
+}
+
+func (n numberLiteral) value(map[string]float64) (float64, string) { return n.number, "" }
+func (n numberLiteral) String() string                             { return n.text }
+
+type metricRef struct {
+	name string
+}
+
+func (n metricRef) value(metrics map[string]float64) (float64, string) {
+	v, ok := metrics[n.name]
+	if !ok {
+		return 0, n.name
+	}
+	return v, ""
+}
+func (n metricRef) String() string { return n.name }
+
+type arithmetic struct {
+	op          string
+	left, right numberNode
+}
+
+func (n arithmetic) value(metrics map[string]float64) (float64, string) {
+	left, missing := n.left.value(metrics)
+	if missing != "" {
+		return 0, missing
+	}
+	right, missing := n.right.value(metrics)
+	if missing != "" {
+		return 0, missing
+	}
+	if n.op == "-" {
+		return left - right, ""
+	}
+	return left + right, ""
+}
+func (n arithmetic) String() string { return n.left.String() + " " + n.op + " " + n.right.String() }
+
+type negation struct {
+	operand numberNode
+}
+
+func (n negation) value(metrics map[string]float64) (float64, string) {
+	v, missing := n.operand.value(metrics)
+	return -v, missing
+}
+func (n negation) String() string { return "-" + n.operand.String() }
+
+type comparison struct {
+	op          string
+	left, right numberNode
+}
+
+func (n comparison) eval(metrics map[string]float64, trace *[]string) verdict {
+	text := n.left.String() + " " + n.op + " " + n.right.String()
+
+	left, missing := n.left.value(metrics)
+	if missing == "" {
+		var right float64
+		right, missing = n.right.value(metrics)
+		if missing == "" {
+			result := compare(n.op, left, right)
+			outcome := OutcomePass
+			if !result {
+				outcome = OutcomeFail
+			}
+			*trace = append(*trace, fmt.Sprintf("%s %s (%s %s %s)", outcome.Icon(), text, formatNumber(left), n.op, formatNumber(right)))
+			return verdictOf(result)
+		}
+	}
+
+	*trace = append(*trace, fmt.Sprintf("%s %s (skipped: %s unavailable)", OutcomeSkip.Icon(), text, missing))
+	return verdictSkipped
+}
+
+type logical struct {
+	op          string
+	left, right boolNode
+}
+
+// eval evaluates both sides without short-circuiting so the trace covers every comparison.
+// A skipped side leaves the verdict to the other one.
+func (n logical) eval(metrics map[string]float64, trace *[]string) verdict {
+	left := n.left.eval(metrics, trace)
+	right := n.right.eval(metrics, trace)
+	switch {
+	case left == verdictSkipped:
+		return right
+	case right == verdictSkipped:
+		return left
+	case n.op == "||":
+		return verdictOf(left == verdictTrue || right == verdictTrue)
+	default:
+		return verdictOf(left == verdictTrue && right == verdictTrue)
+	}
+}
+
+type inversion struct {
+	operand boolNode
+}
+
+// eval inverts the operand; a skipped operand stays skipped
+func (n inversion) eval(metrics map[string]float64, trace *[]string) verdict {
+	switch n.operand.eval(metrics, trace) {
+	case verdictTrue:
+		return verdictFalse
+	case verdictFalse:
+		return verdictTrue
+	default:
+		return verdictSkipped
+	}
+}
+
+// compare applies a comparison operator
+func compare(op string, left, right float64) bool {
+	switch op {
+	case ">=":
+		return left >= right
+	case ">":
+		return left > right
+	case "<=":
+		return left <= right
+	case "<":
+		return left < right
+	case "==":
+		return left == right
+	default:
+		return left != right
+	}
+}
+
+// formatNumber renders whole numbers without decimals and percentages with two
+func formatNumber(v float64) string {
+	if v == float64(int64(v)) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists the recognized operators, two-character operators first
+var operators = []string{">=", "<=", "==", "!=", "&&", "||", ">", "<", "!", "+", "-", "(", ")"}
+
+// tokenize splits an expression into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, source[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(source) && (unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i])) || source[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, source[start:i], start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, token{tokenOperator, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidExpression, c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(source)}), nil
+}
+
+// exprParser is a recursive descent parser; each level returns a boolNode or a numberNode
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (any, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (any, error) {
+	return p.parseLogical("&&", p.parseNot)
+}
+
+// parseLogical parses a left-associative chain of op over operands produced by parseOperand
+func (p *exprParser) parseLogical(op string, parseOperand func() (any, error)) (any, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept(op); !ok {
+			return left, nil
+		}
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(boolNode)
+		r, rok := right.(boolNode)
+		if !lok || !rok {
+			return nil, fmt.Errorf("%w: %s needs comparisons on both sides", ErrInvalidExpression, op)
+		}
+		left = logical{op: op, left: l, right: r}
+	}
+}
+
+func (p *exprParser) parseNot() (any, error) {
+	if _, ok := p.accept("!"); !ok {
+		return p.parseComparison()
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	b, ok := operand.(boolNode)
+	if !ok {
+		return nil, fmt.Errorf("%w: ! needs a comparison", ErrInvalidExpression)
+	}
+	return inversion{operand: b}, nil
+}
+
+func (p *exprParser) parseComparison() (any, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept(">=", ">", "<=", "<", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	l, lok := left.(numberNode)
+	r, rok := right.(numberNode)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%w: %s needs numbers on both sides", ErrInvalidExpression, op)
+	}
+	return comparison{op: op, left: l, right: r}, nil
+}
+
+func (p *exprParser) parseSum() (any, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(numberNode)
+		r, rok := right.(numberNode)
+		if !lok || !rok {
+			return nil, fmt.Errorf("%w: %s needs numbers on both sides", ErrInvalidExpression, op)
+		}
+		left = arithmetic{op: op, left: l, right: r}
+	}
+}
+
+func (p *exprParser) parseUnary() (any, error) {
+	if _, ok := p.accept("-"); !ok {
+		return p.parsePrimary()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	switch n := operand.(type) {
+	case numberLiteral:
+		return numberLiteral{number: -n.number, text: "-" + n.text}, nil
+	case numberNode:
+		return negation{operand: n}, nil
+	default:
+		return nil, fmt.Errorf("%w: - needs a number", ErrInvalidExpression)
+	}
+}
+
+func (p *exprParser) parsePrimary() (any, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q at offset %d", ErrInvalidExpression, tok.text, tok.pos)
+		}
+		return numberLiteral{number: v, text: tok.text}, nil
+	case tokenIdent:
+		name := strings.ToLower(tok.text)
+		for _, metric := range GateMetrics() {
+			if metric == name {
+				return metricRef{name: name}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownMetric, tok.text, strings.Join(GateMetrics(), ", "))
+	case tokenOperator:
+		if tok.text == "(" {
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("%w: missing ) at offset %d", ErrInvalidExpression, p.peek().pos)
+			}
+			return parenthesized(node), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidExpression, tok.text, tok.pos)
+}
+
+// parenthesized keeps parentheses around arithmetic in trace output
+func parenthesized(node any) any {
+	if n, ok := node.(arithmetic); ok {
+		return grouped{n}
+	}
+	return node
+}
+
+type grouped struct {
+	arithmetic
+}
+
+func (n grouped) String() string { return "(" + n.arithmetic.String() + ")" }
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    error
+	}{
+		{"empty", "", ErrInvalidExpression},
+		{"number only", "80", ErrInvalidExpression},
+		{"unknown metric", "lines >= 80", ErrUnknownMetric},
+		{"unknown character", "total >= 80%", ErrInvalidExpression},
+		{"dangling operator", "total >=", ErrInvalidExpression},
+		{"missing paren", "(total >= 80", ErrInvalidExpression},
+		{"logical on numbers", "total && patch", ErrInvalidExpression},
+		{"comparison of comparisons", "(total >= 80) >= 1", ErrInvalidExpression},
+		{"trailing tokens", "total >= 80 patch", ErrInvalidExpression},
+		{"invalid number", "total >= 8.0.1", ErrInvalidExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpression(tt.source)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExpressionEvaluate(t *testing.T) {
+	metrics := map[string]float64{
+		MetricTotal:      85.5,
+		MetricBase:       86,
+		MetricDelta:      -0.5,
+		MetricStatements: 200,
+		MetricCovered:    171,
+	}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"total >= 80", true},
+		{"total >= 80 && delta >= -0.5", true},
+		{"total >= 90 || delta > -1", true},
+		{"total >= 90 || delta > 0", false},
+		{"!(total < 80)", true},
+		{"total - base >= -1", true},
+		{"(total - base) >= 0", false},
+		{"covered + 29 == statements", true},
+		{"-delta <= 0.5", true},
+		{"TOTAL != 85.5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := ParseExpression(tt.source)
+			require.NoError(t, err)
+			got, trace := expr.Evaluate(metrics)
+			assert.Equal(t, tt.want, got)
+			assert.NotEmpty(t, trace)
+		})
+	}
+}
+
+func TestExpressionTrace(t *testing.T) {
+	expr, err := ParseExpression("  total >= 80 && patch >= 90 && (total - base) >= -0.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, "total >= 80 && patch >= 90 && (total - base) >= -0.5", expr.String())
+
+	passed, trace := expr.Evaluate(map[string]float64{MetricTotal: 85.25, MetricBase: 86})
+	assert.False(t, passed)
+	assert.Equal(t, []string{
+		"✅ total >= 80 (85.25 >= 80)",
+		"⏭️ patch >= 90 (skipped: patch unavailable)",
+		"❌ (total - base) >= -0.5 (-0.75 >= -0.50)",
+	}, trace)
+}
+
+func TestExpressionMissingMetrics(t *testing.T) {
+	metrics := map[string]float64{MetricTotal: 85, MetricStatements: 200}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"patch >= 90", true},
+		{"!(patch < 90)", true},
+		{"!!(patch < 90)", true},
+		{"!(patch < 90) && total >= 90", false},
+		{"!(patch < 90) && total >= 80", true},
+		{"patch >= 90 || total >= 90", false},
+		{"patch >= 90 || total >= 80", true},
+		{"!(patch >= 90 || delta >= 0)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := ParseExpression(tt.source)
+			require.NoError(t, err)
+			got, trace := expr.Evaluate(metrics)
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, trace[0], "skipped: patch unavailable")
+		})
+	}
+}
+
+func TestEngineGate(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		decision := NewEngine(Config{Threshold: 50, MaxDrop: -1}).Evaluate(Input{Coverage: 60})
+		for _, result := range decision.Results {
+			assert.NotEqual(t, RuleGate, result.Rule)
+		}
+	})
+
+	t.Run("uses patch and delta metrics", func(t *testing.T) {
+		engine := NewEngine(Config{Threshold: 50, MaxDrop: -1, Gate: "patch >= 90 && delta >= -0.5"})
+		decision := engine.Evaluate(Input{Coverage: 80, HasBase: true, Base: 80.2, HasPatch: true, Patch: 75})
+		assert.False(t, decision.Passed)
+		assert.True(t, decision.Failed(RuleGate))
+
+		gate := decision.Results[len(decision.Results)-1]
+		assert.Equal(t, "patch >= 90 && delta >= -0.5 does not hold", gate.Message)
+		assert.Equal(t, []string{"❌ patch >= 90 (75 >= 90)", "✅ delta >= -0.5 (-0.20 >= -0.50)"}, gate.Trace)
+	})
+
+	t.Run("invalid expression fails", func(t *testing.T) {
+		decision := NewEngine(Config{Threshold: 50, MaxDrop: -1, Gate: "lines > 1"}).Evaluate(Input{Coverage: 60})
+		assert.True(t, decision.Failed(RuleGate))
+	})
+}
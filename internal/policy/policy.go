@@ -0,0 +1,319 @@
+// Package policy evaluates declarative coverage gating policies and explains each decision
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule names reported in evaluation results
+const (
+	RuleThreshold        = "threshold"
+	RuleMaxDrop          = "max-drop"
+	RuleSustainedDecline = "sustained-decline"
+	RuleGate             = "gate"
+	RuleTeam             = "team"
+	RuleCritical         = "critical"
+	RuleTests            = "tests"
+	RuleNewFiles         = "new-files"
+	RuleModule           = "module"
+	RuleWaiver           = "waiver"
+)
+
+// Outcome is the result of evaluating a single rule
+type Outcome string
+
+// Rule outcomes; only OutcomeFail fails the gate
+const (
+	OutcomePass Outcome = "pass"
+	OutcomeWarn Outcome = "warn"
+	OutcomeFail Outcome = "fail"
+	OutcomeSkip Outcome = "skip"
+)
+
+// declineTolerance ignores coverage changes smaller than this many percentage points,
+// so rounding noise between runs is not counted as a decline
+const declineTolerance = 0.01
+
+// Config declares the gating policy
+type Config struct {
+	Threshold   float64 // Minimum overall coverage percentage
+	MaxDrop     float64 // Largest allowed decrease versus the base in percentage points; negative disables the rule
+	GraceDrop   float64 // Decrease allowed while coverage stays at or above GraceAbove
+	GraceAbove  float64 // Coverage level that unlocks GraceDrop; 0 disables the grace period
+	DeclineRuns int     // Consecutive declining runs before failing; 0 disables trend-based gating
+	Gate        string  // Gate expression over the metrics, e.g. "total >= 80 && delta >= -0.5"; empty disables it
+	LowerBound  bool    // Compare the lower bound of the confidence band, when there is one, against Threshold
+}
+
+// Input holds the metrics a policy is evaluated against
+type Input struct {
+	Coverage float64   // Current overall coverage percentage
+	HasBase  bool      // Whether Base holds a comparison baseline
+	Base     float64   // Coverage of the comparison base (previous run or base branch)
+	Previous []float64 // Coverage of earlier runs on the same branch, newest first
+
+	HasPatch   bool    // Whether Patch holds the coverage of the changed statements
+	Patch      float64 // Coverage percentage of the statements touched by the change
+	Statements int     // Total number of statements
+	Covered    int     // Number of covered statements
+
+	Confidence *Interval // Confidence band around Coverage; nil without enough history
+}
+
+// Result explains the outcome of one rule
+type Result struct {
+	Rule    string   `json:"rule"`
+	Outcome Outcome  `json:"outcome"`
+	Message string   `json:"message"`
+	Trace   []string `json:"trace,omitempty"`
+}
+
+// Decision is the combined result of all rules
+type Decision struct {
+	Passed     bool      `json:"passed"`
+	Results    []Result  `json:"results"`
+	Confidence *Interval `json:"confidence,omitempty"`
+	// Bypass is why an emergency bypass downgraded the failed rules to warnings, empty otherwise
+	Bypass string `json:"bypass,omitempty"`
+	// Warmup is the progress of the warm-up of a new repository that downgraded the failed rules
+	// to warnings, empty otherwise
+	Warmup string `json:"warmup,omitempty"`
+}
+
+// Engine evaluates a policy configuration
+type Engine struct {
+	config Config
+}
+
+// NewEngine creates a policy engine for the given configuration
+func NewEngine(config Config) *Engine {
+	return &Engine{config: config}
+}
+
+// Evaluate applies every rule to the input. The gate fails if any rule fails.
+func (e *Engine) Evaluate(input Input) *Decision {
+	declines := ConsecutiveDeclines(input.Coverage, input.Previous)
+
+	results := []Result{
+		e.evaluateThreshold(input),
+		e.evaluateMaxDrop(input, declines),
+		e.evaluateSustainedDecline(input, declines),
+	}
+	if e.config.Gate != "" {
+		results = append(results, e.evaluateGate(input))
+	}
+
+	decision := &Decision{Passed: true, Results: results, Confidence: input.Confidence}
+	for _, result := range results {
+		if result.Outcome == OutcomeFail {
+			decision.Passed = false
+		}
+	}
+	return decision
+}
+
+// evaluateThreshold checks overall coverage against the configured minimum, or the lower bound
+// of the confidence band when LowerBound is set
+func (e *Engine) evaluateThreshold(input Input) Result {
+	if e.config.LowerBound && input.Confidence != nil {
+		band := input.Confidence
+		if band.Lower >= e.config.Threshold {
+			return Result{Rule: RuleThreshold, Outcome: OutcomePass,
+				Message: fmt.Sprintf("coverage %s (lower bound %.2f%%) meets the %.2f%% threshold", band, band.Lower, e.config.Threshold)}
+		}
+		return Result{Rule: RuleThreshold, Outcome: OutcomeFail,
+			Message: fmt.Sprintf("coverage %s (lower bound %.2f%%) is below the %.2f%% threshold", band, band.Lower, e.config.Threshold)}
+	}
+	if input.Coverage >= e.config.Threshold {
+		return Result{Rule: RuleThreshold, Outcome: OutcomePass,
+			Message: fmt.Sprintf("coverage %.2f%% meets the %.2f%% threshold", input.Coverage, e.config.Threshold)}
+	}
+	return Result{Rule: RuleThreshold, Outcome: OutcomeFail,
+		Message: fmt.Sprintf("coverage %.2f%% is below the %.2f%% threshold", input.Coverage, e.config.Threshold)}
+}
+
+// evaluateMaxDrop limits the decrease versus the base, with an optional grace period for healthy
+// coverage. With trend-based gating enabled, a violation only warns until the decline is sustained.
+func (e *Engine) evaluateMaxDrop(input Input, declines int) Result {
+	graceEnabled := e.config.GraceAbove > 0
+	if e.config.MaxDrop < 0 && !graceEnabled {
+		return Result{Rule: RuleMaxDrop, Outcome: OutcomeSkip, Message: "no drop limit configured"}
+	}
+	if !input.HasBase {
+		return Result{Rule: RuleMaxDrop, Outcome: OutcomeSkip, Message: "no baseline available for comparison"}
+	}
+
+	drop := input.Base - input.Coverage
+	allowed := max(e.config.MaxDrop, 0)
+	reason := fmt.Sprintf("limit %.2f pts", allowed)
+	if graceEnabled && input.Coverage >= e.config.GraceAbove && e.config.GraceDrop > allowed {
+		allowed = e.config.GraceDrop
+		reason = fmt.Sprintf("grace of %.2f pts applies because coverage is at or above %.2f%%", allowed, e.config.GraceAbove)
+	}
+
+	if drop <= 0 {
+		return Result{Rule: RuleMaxDrop, Outcome: OutcomePass,
+			Message: fmt.Sprintf("coverage did not drop (%.2f%% → %.2f%%)", input.Base, input.Coverage)}
+	}
+	if drop <= allowed {
+		return Result{Rule: RuleMaxDrop, Outcome: OutcomePass,
+			Message: fmt.Sprintf("coverage dropped %.2f pts (%.2f%% → %.2f%%), within %s", drop, input.Base, input.Coverage, reason)}
+	}
+
+	message := fmt.Sprintf("coverage dropped %.2f pts (%.2f%% → %.2f%%), exceeding %s", drop, input.Base, input.Coverage, reason)
+	if e.config.DeclineRuns > 0 && declines < e.config.DeclineRuns {
+		return Result{Rule: RuleMaxDrop, Outcome: OutcomeWarn,
+			Message: fmt.Sprintf("%s; tolerated until coverage declines %d runs in a row (currently %d)", message, e.config.DeclineRuns, declines)}
+	}
+	return Result{Rule: RuleMaxDrop, Outcome: OutcomeFail, Message: message}
+}
+
+// evaluateSustainedDecline fails once coverage has declined for DeclineRuns consecutive runs
+func (e *Engine) evaluateSustainedDecline(input Input, declines int) Result {
+	if e.config.DeclineRuns <= 0 {
+		return Result{Rule: RuleSustainedDecline, Outcome: OutcomeSkip, Message: "trend-based gating disabled"}
+	}
+	if len(input.Previous) < e.config.DeclineRuns && declines == len(input.Previous) {
+		return Result{Rule: RuleSustainedDecline, Outcome: OutcomeSkip,
+			Message: fmt.Sprintf("not enough history (%d of %d previous runs)", len(input.Previous), e.config.DeclineRuns)}
+	}
+	if declines >= e.config.DeclineRuns {
+		return Result{Rule: RuleSustainedDecline, Outcome: OutcomeFail,
+			Message: fmt.Sprintf("coverage declined %d runs in a row (%s), limit is %d",
+				declines, formatSeries(input.Coverage, input.Previous[:declines]), e.config.DeclineRuns)}
+	}
+	return Result{Rule: RuleSustainedDecline, Outcome: OutcomePass,
+		Message: fmt.Sprintf("coverage declined %d of the last %d runs in a row", declines, e.config.DeclineRuns)}
+}
+
+// evaluateGate evaluates the gate expression, tracing every comparison
+func (e *Engine) evaluateGate(input Input) Result {
+	expr, err := ParseExpression(e.config.Gate)
+	if err != nil {
+		return Result{Rule: RuleGate, Outcome: OutcomeFail, Message: err.Error()}
+	}
+
+	passed, trace := expr.Evaluate(input.metrics())
+	if passed {
+		return Result{Rule: RuleGate, Outcome: OutcomePass,
+			Message: fmt.Sprintf("%s holds", expr), Trace: trace}
+	}
+	return Result{Rule: RuleGate, Outcome: OutcomeFail,
+		Message: fmt.Sprintf("%s does not hold", expr), Trace: trace}
+}
+
+// metrics returns the values available to gate expressions; metrics without data are left out
+func (i Input) metrics() map[string]float64 {
+	metrics := map[string]float64{
+		MetricTotal:      i.Coverage,
+		MetricStatements: float64(i.Statements),
+		MetricCovered:    float64(i.Covered),
+	}
+	if i.HasBase {
+		metrics[MetricBase] = i.Base
+		metrics[MetricDelta] = i.Coverage - i.Base
+	}
+	if i.HasPatch {
+		metrics[MetricPatch] = i.Patch
+	}
+	if i.Confidence != nil {
+		metrics[MetricLower] = i.Confidence.Lower
+		metrics[MetricUpper] = i.Confidence.Upper
+		metrics[MetricMargin] = i.Confidence.Margin
+	}
+	return metrics
+}
+
+// ConsecutiveDeclines counts how many runs in a row coverage decreased, ending with the current run,
+// from the coverage of the previous runs, newest first
+func ConsecutiveDeclines(current float64, previous []float64) int {
+	declines := 0
+	for _, earlier := range previous {
+		if current >= earlier-declineTolerance {
+			break
+		}
+		declines++
+		current = earlier
+	}
+	return declines
+}
+
+// formatSeries renders coverage values oldest to newest, e.g. "81.00% → 80.50% → 80.10%"
+func formatSeries(current float64, previous []float64) string {
+	values := make([]string, 0, len(previous)+1)
+	for i := len(previous) - 1; i >= 0; i-- {
+		values = append(values, fmt.Sprintf("%.2f%%", previous[i]))
+	}
+	values = append(values, fmt.Sprintf("%.2f%%", current))
+	return strings.Join(values, " → ")
+}
+
+// Failures returns the results that failed the gate
+func (d *Decision) Failures() []Result {
+	var failures []Result
+	for _, result := range d.Results {
+		if result.Outcome == OutcomeFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Add records the result of a rule evaluated outside the engine, such as the threshold of a team
+func (d *Decision) Add(result Result) {
+	d.Results = append(d.Results, result)
+	if result.Outcome == OutcomeFail {
+		d.Passed = false
+	}
+}
+
+// Downgrade applies an emergency bypass: failed rules become warnings and the decision passes,
+// keeping the reason so the bypass can be reported and audited
+func (d *Decision) Downgrade(reason string) {
+	for i := range d.Results {
+		if d.Results[i].Outcome == OutcomeFail {
+			d.Results[i].Outcome = OutcomeWarn
+			d.Results[i].Message += " (bypassed)"
+		}
+	}
+	d.Passed = true
+	d.Bypass = reason
+}
+
+// WarmUp reports the failed rules as warnings while the history of a new repository
+// accumulates, keeping the progress of the warm-up so it can be reported
+func (d *Decision) WarmUp(progress string) {
+	for i := range d.Results {
+		if d.Results[i].Outcome == OutcomeFail {
+			d.Results[i].Outcome = OutcomeWarn
+			d.Results[i].Message += " (warm-up)"
+		}
+	}
+	d.Passed = true
+	d.Warmup = progress
+}
+
+// Failed reports whether the named rule failed
+func (d *Decision) Failed(rule string) bool {
+	for _, result := range d.Failures() {
+		if result.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Icon returns an emoji for the outcome
+func (o Outcome) Icon() string {
+	switch o {
+	case OutcomePass:
+		return "✅"
+	case OutcomeWarn:
+		return "⚠️"
+	case OutcomeFail:
+		return "❌"
+	default:
+		return "⏭️"
+	}
+}
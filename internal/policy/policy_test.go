@@ -0,0 +1,195 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resultFor returns the result of the named rule
+func resultFor(t *testing.T, decision *Decision, rule string) Result {
+	t.Helper()
+	for _, result := range decision.Results {
+		if result.Rule == rule {
+			return result
+		}
+	}
+	require.Failf(t, "rule not evaluated", "rule %s", rule)
+	return Result{}
+}
+
+func TestEvaluateThreshold(t *testing.T) {
+	engine := NewEngine(Config{Threshold: 80, MaxDrop: -1})
+
+	decision := engine.Evaluate(Input{Coverage: 85})
+	assert.True(t, decision.Passed)
+	assert.Equal(t, OutcomePass, resultFor(t, decision, RuleThreshold).Outcome)
+	assert.Equal(t, OutcomeSkip, resultFor(t, decision, RuleMaxDrop).Outcome)
+	assert.Equal(t, OutcomeSkip, resultFor(t, decision, RuleSustainedDecline).Outcome)
+
+	decision = engine.Evaluate(Input{Coverage: 79.5})
+	assert.False(t, decision.Passed)
+	assert.True(t, decision.Failed(RuleThreshold))
+	assert.Contains(t, resultFor(t, decision, RuleThreshold).Message, "79.50% is below the 80.00% threshold")
+}
+
+func TestEvaluateMaxDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		input    Input
+		expected Outcome
+		message  string
+	}{
+		{
+			name:     "no baseline",
+			config:   Config{MaxDrop: 0},
+			input:    Input{Coverage: 80},
+			expected: OutcomeSkip,
+			message:  "no baseline",
+		},
+		{
+			name:     "improvement",
+			config:   Config{MaxDrop: 0},
+			input:    Input{Coverage: 81, HasBase: true, Base: 80},
+			expected: OutcomePass,
+			message:  "did not drop",
+		},
+		{
+			name:     "drop within limit",
+			config:   Config{MaxDrop: 0.5},
+			input:    Input{Coverage: 79.7, HasBase: true, Base: 80},
+			expected: OutcomePass,
+			message:  "within limit 0.50 pts",
+		},
+		{
+			name:     "drop over limit",
+			config:   Config{MaxDrop: 0.5},
+			input:    Input{Coverage: 79, HasBase: true, Base: 80},
+			expected: OutcomeFail,
+			message:  "dropped 1.00 pts (80.00% → 79.00%), exceeding limit 0.50 pts",
+		},
+		{
+			name:     "grace period for healthy coverage",
+			config:   Config{MaxDrop: 0, GraceDrop: 2, GraceAbove: 85},
+			input:    Input{Coverage: 88.5, HasBase: true, Base: 90},
+			expected: OutcomePass,
+			message:  "grace of 2.00 pts applies because coverage is at or above 85.00%",
+		},
+		{
+			name:     "grace period does not apply below the level",
+			config:   Config{MaxDrop: 0, GraceDrop: 2, GraceAbove: 85},
+			input:    Input{Coverage: 84, HasBase: true, Base: 85},
+			expected: OutcomeFail,
+			message:  "exceeding limit 0.00 pts",
+		},
+		{
+			name:     "grace alone enables the rule",
+			config:   Config{MaxDrop: -1, GraceDrop: 1, GraceAbove: 90},
+			input:    Input{Coverage: 80, HasBase: true, Base: 80.5},
+			expected: OutcomeFail,
+		},
+		{
+			name:     "violation tolerated until sustained",
+			config:   Config{MaxDrop: 0, DeclineRuns: 3},
+			input:    Input{Coverage: 79, HasBase: true, Base: 80, Previous: []float64{80, 80}},
+			expected: OutcomeWarn,
+			message:  "tolerated until coverage declines 3 runs in a row (currently 1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resultFor(t, NewEngine(tt.config).Evaluate(tt.input), RuleMaxDrop)
+			assert.Equal(t, tt.expected, result.Outcome, result.Message)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
+
+func TestEvaluateSustainedDecline(t *testing.T) {
+	engine := NewEngine(Config{MaxDrop: 0, DeclineRuns: 3})
+
+	t.Run("sustained decline fails", func(t *testing.T) {
+		decision := engine.Evaluate(Input{Coverage: 79, HasBase: true, Base: 79.5, Previous: []float64{79.5, 80, 81, 70}})
+		assert.False(t, decision.Passed)
+		result := resultFor(t, decision, RuleSustainedDecline)
+		assert.Equal(t, OutcomeFail, result.Outcome)
+		assert.Contains(t, result.Message, "declined 3 runs in a row (81.00% → 80.00% → 79.50% → 79.00%)")
+		assert.Equal(t, OutcomeFail, resultFor(t, decision, RuleMaxDrop).Outcome)
+	})
+
+	t.Run("interrupted decline passes", func(t *testing.T) {
+		decision := engine.Evaluate(Input{Coverage: 79, HasBase: true, Base: 79.5, Previous: []float64{79.5, 79, 81}})
+		assert.True(t, decision.Passed)
+		assert.Equal(t, OutcomePass, resultFor(t, decision, RuleSustainedDecline).Outcome)
+		assert.Equal(t, OutcomeWarn, resultFor(t, decision, RuleMaxDrop).Outcome)
+	})
+
+	t.Run("rounding noise is not a decline", func(t *testing.T) {
+		assert.Equal(t, 0, ConsecutiveDeclines(80.001, []float64{80.005}))
+	})
+
+	t.Run("insufficient history", func(t *testing.T) {
+		decision := engine.Evaluate(Input{Coverage: 79, Previous: []float64{80}})
+		result := resultFor(t, decision, RuleSustainedDecline)
+		assert.Equal(t, OutcomeSkip, result.Outcome)
+		assert.Contains(t, result.Message, "1 of 3 previous runs")
+	})
+}
+
+func TestDecisionFailures(t *testing.T) {
+	decision := NewEngine(Config{Threshold: 90, MaxDrop: 0}).Evaluate(Input{Coverage: 80, HasBase: true, Base: 85})
+
+	failures := decision.Failures()
+	require.Len(t, failures, 2)
+	assert.Equal(t, RuleThreshold, failures[0].Rule)
+	assert.Equal(t, RuleMaxDrop, failures[1].Rule)
+	assert.False(t, decision.Failed(RuleSustainedDecline))
+}
+
+func TestDecisionDowngrade(t *testing.T) {
+	decision := NewEngine(Config{Threshold: 90, MaxDrop: 0}).Evaluate(Input{Coverage: 80, HasBase: true, Base: 85})
+	require.False(t, decision.Passed)
+
+	decision.Downgrade("commit message contains [hotfix]")
+	assert.True(t, decision.Passed)
+	assert.Empty(t, decision.Failures())
+	assert.Equal(t, "commit message contains [hotfix]", decision.Bypass)
+	assert.Equal(t, OutcomeWarn, resultFor(t, decision, RuleThreshold).Outcome)
+	assert.Contains(t, resultFor(t, decision, RuleThreshold).Message, "(bypassed)")
+	assert.Equal(t, OutcomeSkip, resultFor(t, decision, RuleSustainedDecline).Outcome)
+}
+
+func TestDecisionWarmUp(t *testing.T) {
+	decision := NewEngine(Config{Threshold: 90, MaxDrop: 0}).Evaluate(Input{Coverage: 80, HasBase: true, Base: 85})
+	require.False(t, decision.Passed)
+
+	decision.WarmUp("run 3 of 10")
+	assert.True(t, decision.Passed)
+	assert.Empty(t, decision.Failures())
+	assert.Equal(t, "run 3 of 10", decision.Warmup)
+	assert.Empty(t, decision.Bypass)
+	assert.Equal(t, OutcomeWarn, resultFor(t, decision, RuleThreshold).Outcome)
+	assert.Contains(t, resultFor(t, decision, RuleThreshold).Message, "(warm-up)")
+}
+
+func TestDecisionAdd(t *testing.T) {
+	decision := NewEngine(Config{Threshold: 50, MaxDrop: -1}).Evaluate(Input{Coverage: 80})
+	require.True(t, decision.Passed)
+
+	decision.Add(Result{Rule: RuleTeam, Outcome: OutcomePass, Message: "web: coverage 90.00% meets the 80.00% threshold"})
+	assert.True(t, decision.Passed)
+
+	decision.Add(Result{Rule: RuleTeam, Outcome: OutcomeFail, Message: "payments: coverage 60.00% is below the 80.00% threshold"})
+	assert.False(t, decision.Passed)
+	assert.True(t, decision.Failed(RuleTeam))
+}
+
+func TestOutcomeIcon(t *testing.T) {
+	assert.Equal(t, "✅", OutcomePass.Icon())
+	assert.Equal(t, "⚠️", OutcomeWarn.Icon())
+	assert.Equal(t, "❌", OutcomeFail.Icon())
+	assert.Equal(t, "⏭️", OutcomeSkip.Icon())
+}
@@ -0,0 +1,269 @@
+// Package publicbadge publishes the coverage badge of a private repository to a public location
+// (a gist, a public repository or a bucket), so the badge can be shown where the dashboard of
+// the repository cannot. Only what the badge shows is published: the SVG and the shields.io
+// endpoint JSON, holding the label, coverage percentage and color.
+package publicbadge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Static error definitions
+var (
+	ErrUnknownTarget = errors.New("unknown public badge target")
+	ErrPublishFailed = errors.New("failed to publish public badge")
+)
+
+// Locations the badge is published to
+const (
+	// TargetGist updates the files of an existing gist
+	TargetGist = "gist"
+	// TargetRepo commits the files to a public repository, e.g. one serving GitHub Pages
+	TargetRepo = "repo"
+	// TargetHTTP uploads the files with HTTP PUT, e.g. to a bucket
+	TargetHTTP = "http"
+)
+
+// Content types of the published files
+const (
+	ContentTypeSVG  = "image/svg+xml"
+	ContentTypeJSON = "application/json"
+)
+
+// commitMessage is the message of the commits made to a public repository
+const commitMessage = "Update coverage badge"
+
+// Options locate the public location and the credentials to write to it
+type Options struct {
+	// Target is the kind of location: TargetGist, TargetRepo or TargetHTTP
+	Target string
+	// Token writes to the location. It must be a credential of its own, never the token of the
+	// private repository, so a leak cannot reach the private code.
+	Token string
+	// GistID is the gist updated by TargetGist
+	GistID string
+	// Repository is the owner/name of the public repository written by TargetRepo
+	Repository string
+	// Branch of the public repository
+	Branch string
+	// Dir is the directory of the files in the public repository
+	Dir string
+	// URL is the base URL TargetHTTP uploads to; files are put at URL/name
+	URL string
+	// APIBaseURL is the GitHub REST API endpoint
+	APIBaseURL string
+	// Client sends the requests
+	Client *http.Client
+	// UserAgent identifies the requests
+	UserAgent string
+}
+
+// File is a file to publish
+type File struct {
+	Name        string
+	Content     []byte
+	ContentType string
+}
+
+// Published is a file that was published
+type Published struct {
+	Name string
+	// URL the file is served from publicly
+	URL string
+	// Unchanged reports a file that already held the content, so nothing was written
+	Unchanged bool
+}
+
+// Publish writes the files to the public location
+func Publish(ctx context.Context, opts Options, files []File) ([]Published, error) {
+	switch opts.Target {
+	case TargetGist:
+		return publishGist(ctx, opts, files)
+	case TargetRepo:
+		published := make([]Published, 0, len(files))
+		for _, file := range files {
+			result, err := publishRepoFile(ctx, opts, file)
+			if err != nil {
+				return published, err
+			}
+			published = append(published, *result)
+		}
+		return published, nil
+	case TargetHTTP:
+		published := make([]Published, 0, len(files))
+		for _, file := range files {
+			result, err := publishHTTP(ctx, opts, file)
+			if err != nil {
+				return published, err
+			}
+			published = append(published, *result)
+		}
+		return published, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTarget, opts.Target)
+	}
+}
+
+// publishGist updates the files of a gist in a single revision
+func publishGist(ctx context.Context, opts Options, files []File) ([]Published, error) {
+	type gistFile struct {
+		Content string `json:"content"`
+	}
+	request := struct {
+		Files map[string]gistFile `json:"files"`
+	}{Files: map[string]gistFile{}}
+	for _, file := range files {
+		request.Files[file.Name] = gistFile{Content: string(file.Content)}
+	}
+
+	var response struct {
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	}
+	endpoint := fmt.Sprintf("%s/gists/%s", opts.APIBaseURL, url.PathEscape(opts.GistID))
+	if _, err := githubRequest(ctx, opts, http.MethodPatch, endpoint, request, &response); err != nil {
+		return nil, err
+	}
+
+	published := make([]Published, 0, len(files))
+	for _, file := range files {
+		published = append(published, Published{
+			Name: file.Name,
+			URL:  fmt.Sprintf("https://gist.githubusercontent.com/%s/%s/raw/%s", response.Owner.Login, opts.GistID, file.Name),
+		})
+	}
+	return published, nil
+}
+
+// publishRepoFile commits a file to the public repository unless it holds the content already
+func publishRepoFile(ctx context.Context, opts Options, file File) (*Published, error) {
+	filePath := path.Join(opts.Dir, file.Name)
+	endpoint := fmt.Sprintf("%s/repos/%s/contents/%s", opts.APIBaseURL, opts.Repository, escapePath(filePath))
+	result := &Published{
+		Name: file.Name,
+		URL:  fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", opts.Repository, opts.Branch, escapePath(filePath)),
+	}
+
+	var existing struct {
+		SHA     string `json:"sha"`
+		Content string `json:"content"`
+	}
+	status, err := githubRequest(ctx, opts, http.MethodGet, endpoint+"?ref="+url.QueryEscape(opts.Branch), nil, &existing)
+	if err != nil && status != http.StatusNotFound {
+		return nil, err
+	}
+	if existing.SHA != "" {
+		content, decodeErr := base64.StdEncoding.DecodeString(strings.ReplaceAll(existing.Content, "\n", ""))
+		if decodeErr == nil && bytes.Equal(content, file.Content) {
+			result.Unchanged = true
+			return result, nil
+		}
+	}
+
+	request := struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		Branch  string `json:"branch"`
+		SHA     string `json:"sha,omitempty"`
+	}{
+		Message: commitMessage,
+		Content: base64.StdEncoding.EncodeToString(file.Content),
+		Branch:  opts.Branch,
+		SHA:     existing.SHA,
+	}
+	if _, err = githubRequest(ctx, opts, http.MethodPut, endpoint, request, nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// publishHTTP uploads a file with HTTP PUT
+func publishHTTP(ctx context.Context, opts Options, file File) (*Published, error) {
+	target := strings.TrimSuffix(opts.URL, "/") + "/" + url.PathEscape(file.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(file.Content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+	}
+	req.Header.Set("Content-Type", file.ContentType)
+	// Badges change with every run, so caches must revalidate them
+	req.Header.Set("Cache-Control", "no-cache")
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	}
+
+	resp, err := opts.Client.Do(req) //nolint:gosec // G704: the URL is the configured public location
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%w: PUT %s: %d %s", ErrPublishFailed, target, resp.StatusCode, string(body))
+	}
+	return &Published{Name: file.Name, URL: target}, nil
+}
+
+// githubRequest sends a GitHub API request, decoding the response into out when it is not nil
+func githubRequest(ctx context.Context, opts Options, method, endpoint string, in, out any) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+	}
+	req.Header.Set("Authorization", "token "+opts.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := opts.Client.Do(req) //nolint:gosec // G704: the URL is the configured GitHub API
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp.StatusCode, fmt.Errorf("%w: %s %s: %d %s", ErrPublishFailed, method, endpoint, resp.StatusCode, string(data))
+	}
+	if out != nil {
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%w: failed to decode response: %w", ErrPublishFailed, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// escapePath escapes each segment of a repository path
+func escapePath(filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
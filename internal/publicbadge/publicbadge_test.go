@@ -0,0 +1,139 @@
+package publicbadge
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func badgeFiles() []File {
+	return []File{
+		{Name: "coverage.svg", Content: []byte("<svg/>"), ContentType: ContentTypeSVG},
+		{Name: "coverage.json", Content: []byte(`{"schemaVersion":1}`), ContentType: ContentTypeJSON},
+	}
+}
+
+func TestPublishGist(t *testing.T) {
+	var request struct {
+		Files map[string]struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/gists/abc123", r.URL.Path)
+		assert.Equal(t, "token badge-token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(`{"owner":{"login":"octo"}}`))
+	}))
+	defer server.Close()
+
+	published, err := Publish(context.Background(), Options{
+		Target:     TargetGist,
+		Token:      "badge-token",
+		GistID:     "abc123",
+		APIBaseURL: server.URL,
+		Client:     server.Client(),
+	}, badgeFiles())
+	require.NoError(t, err)
+	require.Len(t, published, 2)
+	assert.Equal(t, "https://gist.githubusercontent.com/octo/abc123/raw/coverage.svg", published[0].URL)
+	assert.Equal(t, "<svg/>", request.Files["coverage.svg"].Content)
+	assert.JSONEq(t, `{"schemaVersion":1}`, request.Files["coverage.json"].Content)
+}
+
+func TestPublishRepo(t *testing.T) {
+	var mu sync.Mutex
+	puts := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/badges/contents/private-app/coverage.svg":
+			assert.Equal(t, "gh-pages", r.URL.Query().Get("ref"))
+			_, _ = w.Write([]byte(`{"sha":"old","content":"` + base64.StdEncoding.EncodeToString([]byte("<svg/>")) + `\n"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut:
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			puts[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	published, err := Publish(context.Background(), Options{
+		Target:     TargetRepo,
+		Token:      "badge-token",
+		Repository: "acme/badges",
+		Branch:     "gh-pages",
+		Dir:        "private-app",
+		APIBaseURL: server.URL,
+		Client:     server.Client(),
+	}, badgeFiles())
+	require.NoError(t, err)
+	require.Len(t, published, 2)
+	assert.True(t, published[0].Unchanged, "unchanged files are not committed")
+	assert.False(t, published[1].Unchanged)
+	assert.Equal(t, "https://raw.githubusercontent.com/acme/badges/gh-pages/private-app/coverage.json", published[1].URL)
+
+	require.Len(t, puts, 1)
+	put := puts["/repos/acme/badges/contents/private-app/coverage.json"]
+	assert.Equal(t, "gh-pages", put["branch"])
+	assert.Empty(t, put["sha"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(`{"schemaVersion":1}`)), put["content"])
+}
+
+func TestPublishHTTP(t *testing.T) {
+	uploads := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer bucket-token", r.Header.Get("Authorization"))
+		data, _ := io.ReadAll(r.Body)
+		uploads[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(data)
+	}))
+	defer server.Close()
+
+	published, err := Publish(context.Background(), Options{
+		Target: TargetHTTP,
+		Token:  "bucket-token",
+		URL:    server.URL + "/badges/",
+		Client: server.Client(),
+	}, badgeFiles())
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/badges/coverage.svg", published[0].URL)
+	assert.Equal(t, "image/svg+xml <svg/>", uploads["/badges/coverage.svg"])
+	assert.Equal(t, `application/json {"schemaVersion":1}`, uploads["/badges/coverage.json"])
+}
+
+func TestPublishErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := Publish(context.Background(), Options{Target: "s3"}, badgeFiles())
+	require.ErrorIs(t, err, ErrUnknownTarget)
+
+	for _, target := range []string{TargetGist, TargetRepo, TargetHTTP} {
+		_, err = Publish(context.Background(), Options{
+			Target:     target,
+			GistID:     "abc",
+			Repository: "acme/badges",
+			URL:        server.URL,
+			APIBaseURL: server.URL,
+			Client:     server.Client(),
+		}, badgeFiles())
+		require.ErrorIs(t, err, ErrPublishFailed, target)
+		assert.Contains(t, err.Error(), "403")
+	}
+}
@@ -0,0 +1,221 @@
+// Package publish detects whether a regenerated coverage site differs from the published one
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrGit indicates that the staged changes of a pages checkout could not be inspected
+var ErrGit = errors.New("failed to inspect pages checkout")
+
+// Change statuses
+const (
+	StatusAdded    = "added"
+	StatusModified = "modified"
+	StatusDeleted  = "deleted"
+)
+
+// placeholder replaces volatile content before hashing
+var placeholder = []byte("~")
+
+// timestampPattern matches generation timestamps in RFC 3339 and dashboard form
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2}| UTC)?`)
+
+// commitPattern matches full commit SHAs
+var commitPattern = regexp.MustCompile(`\b[0-9a-f]{40}\b`)
+
+// Options control which differences count as changes
+type Options struct {
+	// Ignore are path.Match patterns of site files that never count as changes, such as history data
+	Ignore []string
+	// Volatile are literal strings, such as the short commit SHA, stripped before comparing
+	Volatile []string
+}
+
+// Change is a site file whose content differs from the published version
+type Change struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// Result is the outcome of comparing a generated site with the published one
+type Result struct {
+	// Changed is false when publishing the site would not change anything but volatile content
+	Changed bool `json:"changed"`
+	// Files are the meaningful changes, sorted by path
+	Files []Change `json:"files"`
+	// Unchanged counts the files that differ only in volatile content or not at all
+	Unchanged int `json:"unchanged"`
+}
+
+// Hash returns the SHA-256 of content after volatile content has been stripped
+func Hash(content []byte, opts Options) string {
+	sum := sha256.Sum256(normalize(content, opts.Volatile))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize replaces every volatile part of content with a placeholder. Pages show the short
+// form of the commit SHA they link to, so the short form of every full SHA is volatile as well.
+func normalize(content []byte, volatile []string) []byte {
+	for _, sha := range commitPattern.FindAll(content, -1) {
+		volatile = append(volatile, string(sha[:7]))
+	}
+	content = timestampPattern.ReplaceAllLiteral(content, placeholder)
+	content = commitPattern.ReplaceAllLiteral(content, placeholder)
+	for _, value := range volatile {
+		if value != "" {
+			content = bytes.ReplaceAll(content, []byte(value), placeholder)
+		}
+	}
+	return content
+}
+
+// CompareDirs compares every file of the generated site against the file at the same path
+// of the published site. Files only present in the published site are left alone, matching
+// the incremental deployment that never removes published content.
+func CompareDirs(site, published string, opts Options) (*Result, error) {
+	result := &Result{Files: []Change{}}
+	err := filepath.WalkDir(site, func(file string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(site, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(rel, opts.Ignore) {
+			return nil
+		}
+
+		current, err := os.ReadFile(file) //nolint:gosec // path comes from walking the site directory
+		if err != nil {
+			return err
+		}
+		previous, err := os.ReadFile(filepath.Join(published, filepath.FromSlash(rel))) //nolint:gosec // same relative path in the published site
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			result.add(rel, StatusAdded)
+		case err != nil:
+			return err
+		case Hash(current, opts) != Hash(previous, opts):
+			result.add(rel, StatusModified)
+		default:
+			result.Unchanged++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", site, published, err)
+	}
+	result.sort()
+	return result, nil
+}
+
+// CompareStaged compares the changes staged in the git checkout at dir, such as a gh-pages
+// branch the new site was copied into, against its HEAD commit. A checkout without commits
+// is always changed.
+func CompareStaged(ctx context.Context, dir string, opts Options) (*Result, error) {
+	result := &Result{Files: []Change{}}
+	if _, err := git(ctx, dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		result.Changed = true
+		return result, nil
+	}
+
+	output, err := git(ctx, dir, "diff", "--cached", "--name-status", "--no-renames", "-z", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, file := fields[i], fields[i+1]
+		if ignored(file, opts.Ignore) {
+			continue
+		}
+		switch status {
+		case "A":
+			result.add(file, StatusAdded)
+		case "D":
+			result.add(file, StatusDeleted)
+		default:
+			previous, showErr := git(ctx, dir, "show", "HEAD:"+file)
+			if showErr != nil {
+				return nil, showErr
+			}
+			current, showErr := git(ctx, dir, "show", ":"+file)
+			if showErr != nil {
+				return nil, showErr
+			}
+			if Hash(current, opts) != Hash(previous, opts) {
+				result.add(file, StatusModified)
+			} else {
+				result.Unchanged++
+			}
+		}
+	}
+	result.sort()
+	return result, nil
+}
+
+// git runs a git command in dir and returns its standard output
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) //nolint:gosec // arguments are fixed or paths reported by git
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: git %s: %w: %s", ErrGit, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// ignored reports whether file matches one of the ignore patterns, either by its full
+// path or, for patterns without a slash, by its base name
+func ignored(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		target := file
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(file)
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+		// A directory pattern ignores everything below it
+		if strings.HasPrefix(file, strings.TrimSuffix(pattern, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// add records a meaningful change
+func (r *Result) add(file, status string) {
+	r.Files = append(r.Files, Change{Path: file, Status: status})
+	r.Changed = true
+}
+
+// sort orders the changes by path
+func (r *Result) sort() {
+	slices.SortFunc(r.Files, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+}
@@ -0,0 +1,128 @@
+package publish
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSite(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestHash(t *testing.T) {
+	opts := Options{Volatile: []string{"abc1234", "def5678"}}
+
+	assert.Equal(t,
+		Hash([]byte(`{"timestamp":"2026-01-02T03:04:05.123456789Z","commit":"0123456789abcdef0123456789abcdef01234567"}`), opts),
+		Hash([]byte(`{"timestamp":"2026-02-03T10:11:12+02:00","commit":"89abcdef0123456789abcdef0123456789abcdef"}`), opts),
+		"timestamps and commit SHAs are volatile")
+	assert.Equal(t,
+		Hash([]byte("Generated 2026-01-02 03:04:05 UTC for abc1234"), opts),
+		Hash([]byte("Generated 2026-03-04 05:06:07 UTC for def5678"), opts),
+		"dashboard timestamps and volatile strings are stripped")
+	assert.NotEqual(t,
+		Hash([]byte(`<text>85.2%</text>`), opts),
+		Hash([]byte(`<text>85.3%</text>`), opts),
+		"coverage changes are meaningful")
+}
+
+func TestCompareDirs(t *testing.T) {
+	published := t.TempDir()
+	writeSite(t, published, map[string]string{
+		"coverage.svg":      "<svg>85.2%</svg>",
+		"index.html":        "<p>Generated 2026-01-02 03:04:05 UTC</p>",
+		"data/history.json": `{"entries":1}`,
+		"pr/7/index.html":   "<p>PR 7</p>",
+	})
+
+	t.Run("only volatile content changed", func(t *testing.T) {
+		site := t.TempDir()
+		writeSite(t, site, map[string]string{
+			"coverage.svg": "<svg>85.2%</svg>",
+			"index.html":   "<p>Generated 2026-05-06 07:08:09 UTC</p>",
+		})
+
+		result, err := CompareDirs(site, published, Options{})
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+		assert.Empty(t, result.Files)
+		assert.Equal(t, 2, result.Unchanged)
+	})
+
+	t.Run("coverage changed", func(t *testing.T) {
+		site := t.TempDir()
+		writeSite(t, site, map[string]string{
+			"coverage.svg":      "<svg>86.0%</svg>",
+			"index.html":        "<p>Generated 2026-05-06 07:08:09 UTC</p>",
+			"data/history.json": `{"entries":2}`,
+			"pr/8/index.html":   "<p>PR 8</p>",
+		})
+
+		result, err := CompareDirs(site, published, Options{Ignore: []string{"data/"}})
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, []Change{
+			{Path: "coverage.svg", Status: StatusModified},
+			{Path: "pr/8/index.html", Status: StatusAdded},
+		}, result.Files)
+	})
+}
+
+func TestCompareStaged(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...) //nolint:gosec,noctx // fixed test arguments
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	run("init", "--quiet")
+	run("config", "user.email", "dev@example.com")
+	run("config", "user.name", "Dev")
+
+	ctx := context.Background()
+	writeSite(t, dir, map[string]string{
+		"coverage.svg": "<svg>85.2%</svg>",
+		"index.html":   "<p>Generated 2026-01-02 03:04:05 UTC</p>",
+		"old.html":     "<p>old</p>",
+	})
+	run("add", ".")
+
+	result, err := CompareStaged(ctx, dir, Options{})
+	require.NoError(t, err)
+	assert.True(t, result.Changed, "a branch without commits is always changed")
+
+	run("commit", "--quiet", "-m", "publish")
+
+	writeSite(t, dir, map[string]string{"index.html": "<p>Generated 2026-05-06 07:08:09 UTC</p>"})
+	run("add", ".")
+	result, err = CompareStaged(ctx, dir, Options{})
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 1, result.Unchanged)
+
+	writeSite(t, dir, map[string]string{"coverage.svg": "<svg>90.0%</svg>", "new.html": "<p>new</p>"})
+	require.NoError(t, os.Remove(filepath.Join(dir, "old.html")))
+	run("add", "-A")
+	result, err = CompareStaged(ctx, dir, Options{})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, []Change{
+		{Path: "coverage.svg", Status: StatusModified},
+		{Path: "new.html", Status: StatusAdded},
+		{Path: "old.html", Status: StatusDeleted},
+	}, result.Files)
+}
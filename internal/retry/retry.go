@@ -0,0 +1,185 @@
+// Package retry provides a shared retry policy with exponential backoff, jitter and retry budgets
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Static error definitions
+var (
+	ErrBudgetExhausted = errors.New("retry budget exhausted")
+	ErrMaxAttempts     = errors.New("maximum retry attempts reached")
+)
+
+// Operation names used to look up per-operation policy overrides
+const (
+	OpGitHubAPI        = "github_api"
+	OpArtifactDownload = "artifact_download"
+	OpGerritAPI        = "gerrit_api"
+	OpBitbucketAPI     = "bitbucket_api"
+	OpAzureDevOpsAPI   = "azure_devops_api"
+)
+
+// Policy describes how an operation is retried
+type Policy struct {
+	MaxAttempts  int           // Total attempts including the first one (minimum 1)
+	InitialDelay time.Duration // Delay before the first retry
+	MaxDelay     time.Duration // Upper bound for a single delay (0 = unbounded)
+	Multiplier   float64       // Backoff multiplier applied per attempt
+	Jitter       float64       // Random jitter fraction applied to each delay (0-1)
+	Budget       *Budget       // Optional retry budget shared between operations
+}
+
+// DefaultPolicy returns the default retry policy used for network operations
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:  3,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+		Jitter:       0.2,
+	}
+}
+
+// Delay returns the backoff delay before the given retry (1 = first retry)
+func (p Policy) Delay(retry int) time.Duration {
+	if retry < 1 || p.InitialDelay <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		// Spread the delay uniformly within +/- jitter of its nominal value
+		delay += delay * jitter * (rand.Float64()*2 - 1) //nolint:gosec // G404: jitter does not need a cryptographically secure source
+	}
+
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// attempts returns the number of attempts, never less than one
+func (p Policy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Budget limits the total number of retries performed across operations
+type Budget struct {
+	mu        sync.Mutex
+	remaining int
+}
+
+// NewBudget creates a retry budget allowing the given number of retries
+func NewBudget(retries int) *Budget {
+	return &Budget{remaining: retries}
+}
+
+// Remaining returns the number of retries left in the budget
+func (b *Budget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining
+}
+
+// take consumes one retry from the budget, returning false if none are left
+func (b *Budget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining <= 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so that Do stops retrying and returns it immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether the error was marked as permanent
+func IsPermanent(err error) bool {
+	var perm *permanentError
+	return errors.As(err, &perm)
+}
+
+// Do runs fn until it succeeds, returns a permanent error, the context is done,
+// the attempts are used up or the retry budget is exhausted
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	var lastErr error
+	maxAttempts := policy.attempts()
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if policy.Budget != nil && !policy.Budget.take() {
+				return fmt.Errorf("%w: %w", ErrBudgetExhausted, lastErr)
+			}
+			if err := sleep(ctx, policy.Delay(attempt-1)); err != nil {
+				return fmt.Errorf("%w: %w", err, lastErr)
+			}
+		}
+
+		lastErr = fn(ctx)
+		if lastErr == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(lastErr, &perm) {
+			return perm.err
+		}
+	}
+
+	if maxAttempts == 1 {
+		return lastErr
+	}
+	return fmt.Errorf("%w (%d): %w", ErrMaxAttempts, maxAttempts, lastErr)
+}
+
+// sleep waits for the given duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
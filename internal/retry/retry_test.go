@@ -0,0 +1,165 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient failure")
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	assert.Equal(t, 3, policy.MaxAttempts)
+	assert.Equal(t, 500*time.Millisecond, policy.InitialDelay)
+	assert.Equal(t, 10*time.Second, policy.MaxDelay)
+	assert.InDelta(t, 2.0, policy.Multiplier, 0.001)
+	assert.InDelta(t, 0.2, policy.Jitter, 0.001)
+	assert.Nil(t, policy.Budget)
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+	}
+
+	tests := []struct {
+		retry    int
+		expected time.Duration
+	}{
+		{0, 0},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second}, // capped by MaxDelay
+		{50, time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, policy.Delay(tt.retry), "retry %d", tt.retry)
+	}
+
+	t.Run("multiplier below one keeps delay constant", func(t *testing.T) {
+		constant := Policy{InitialDelay: 50 * time.Millisecond}
+		assert.Equal(t, 50*time.Millisecond, constant.Delay(1))
+		assert.Equal(t, 50*time.Millisecond, constant.Delay(4))
+	})
+
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		jittered := Policy{InitialDelay: 100 * time.Millisecond, Multiplier: 1, Jitter: 0.5}
+		for range 100 {
+			delay := jittered.Delay(1)
+			assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+			assert.LessOrEqual(t, delay, 150*time.Millisecond)
+		}
+	})
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	fast := Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 1}
+
+	t.Run("succeeds first time", func(t *testing.T) {
+		calls := 0
+		err := Do(ctx, fast, func(context.Context) error {
+			calls++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		calls := 0
+		err := Do(ctx, fast, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errTransient
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := Do(ctx, fast, func(context.Context) error {
+			calls++
+			return errTransient
+		})
+		require.ErrorIs(t, err, ErrMaxAttempts)
+		require.ErrorIs(t, err, errTransient)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("single attempt returns error unwrapped", func(t *testing.T) {
+		err := Do(ctx, Policy{}, func(context.Context) error {
+			return errTransient
+		})
+		assert.Equal(t, errTransient, err)
+	})
+
+	t.Run("permanent error stops retrying", func(t *testing.T) {
+		calls := 0
+		err := Do(ctx, fast, func(context.Context) error {
+			calls++
+			return Permanent(errTransient)
+		})
+		assert.Equal(t, errTransient, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context cancellation stops retrying", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		calls := 0
+		err := Do(cancelCtx, Policy{MaxAttempts: 5, InitialDelay: time.Hour}, func(context.Context) error {
+			calls++
+			cancel()
+			return errTransient
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, errTransient)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("budget limits retries across operations", func(t *testing.T) {
+		budget := NewBudget(2)
+		policy := fast
+		policy.Budget = budget
+
+		calls := 0
+		failing := func(context.Context) error {
+			calls++
+			return errTransient
+		}
+
+		err := Do(ctx, policy, failing)
+		require.ErrorIs(t, err, ErrMaxAttempts)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 0, budget.Remaining())
+
+		calls = 0
+		err = Do(ctx, policy, failing)
+		require.ErrorIs(t, err, ErrBudgetExhausted)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestPermanent(t *testing.T) {
+	require.NoError(t, Permanent(nil))
+
+	err := Permanent(errTransient)
+	assert.True(t, IsPermanent(err))
+	require.ErrorIs(t, err, errTransient)
+	assert.Equal(t, errTransient.Error(), err.Error())
+	assert.False(t, IsPermanent(errTransient))
+}
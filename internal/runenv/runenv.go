@@ -0,0 +1,216 @@
+// Package runenv captures the environment a coverage run happens in - the Go toolchain, the
+// platform, the runner and the flags the profile was generated with - so coverage differences
+// between environments can be told apart in the history
+package runenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/go-coverage/internal/ci"
+)
+
+// ErrInvalidFilter indicates an environment filter that is not a list of key=value pairs
+var ErrInvalidFilter = errors.New("invalid environment filter")
+
+// Metadata keys of the environment, as recorded in the metadata of history entries
+const (
+	KeyGoVersion    = "go_version"
+	KeyOS           = "os"
+	KeyArch         = "arch"
+	KeyRunner       = "runner"
+	KeyTestTags     = "test_tags"
+	KeyProfileFlags = "profile_flags"
+)
+
+// Overrides of what is detected, for runs whose test tags or profile flags are not in GOFLAGS
+const (
+	EnvRunner       = "GO_COVERAGE_RUNNER"
+	EnvTestTags     = "GO_COVERAGE_TEST_TAGS"
+	EnvProfileFlags = "GO_COVERAGE_PROFILE_FLAGS"
+)
+
+// RunnerLocal names runs outside CI
+const RunnerLocal = "local"
+
+// Keys returns the metadata keys of the environment, in display order
+func Keys() []string {
+	return []string{KeyGoVersion, KeyOS, KeyArch, KeyRunner, KeyTestTags, KeyProfileFlags}
+}
+
+// profileFlagNames are the GOFLAGS entries that change the profile go test writes
+func profileFlagNames() []string {
+	return []string{"-race", "-coverpkg", "-short"}
+}
+
+// Capture returns the environment of the run: the Go version reported by the toolchain (the
+// version go-coverage was built with when no toolchain is found), the platform, the runner of
+// the CI provider and the test tags and profile flags of GOFLAGS. mode is the cover mode of the
+// profile, if known. Keys without a value are left out.
+func Capture(ctx context.Context, getenv func(string) string, mode string) map[string]string {
+	return Describe(getenv, toolchainVersion(ctx), runtime.GOOS, runtime.GOARCH, mode)
+}
+
+// FromEnv captures the environment of the run from the process environment
+func FromEnv(ctx context.Context, mode string) map[string]string {
+	return Capture(ctx, os.Getenv, mode)
+}
+
+// Describe returns the environment of a run on goos/goarch with the given Go version, reading
+// the runner, test tags and profile flags through getenv
+func Describe(getenv func(string) string, goVersion, goos, goarch, mode string) map[string]string {
+	flags := strings.Fields(getenv("GOFLAGS"))
+	env := map[string]string{
+		KeyGoVersion:    goVersion,
+		KeyOS:           goos,
+		KeyArch:         goarch,
+		KeyRunner:       runner(getenv),
+		KeyTestTags:     testTags(getenv, flags),
+		KeyProfileFlags: profileFlags(getenv, flags, mode),
+	}
+	for key, value := range env {
+		if value == "" {
+			delete(env, key)
+		}
+	}
+	return env
+}
+
+// toolchainVersion returns the version of the go command on the PATH, which built the tests
+func toolchainVersion(ctx context.Context) string {
+	output, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output()
+	if version := strings.TrimSpace(string(output)); err == nil && version != "" {
+		return version
+	}
+	return runtime.Version()
+}
+
+// runner names the CI provider running the process and, on GitHub Actions, whether the runner is
+// GitHub-hosted or self-hosted; local outside CI
+func runner(getenv func(string) string) string {
+	if name := strings.TrimSpace(getenv(EnvRunner)); name != "" {
+		return name
+	}
+	detector := ci.Detect(getenv)
+	if detector == nil {
+		if getenv("CI") == "true" {
+			return "ci"
+		}
+		return RunnerLocal
+	}
+	if kind := getenv("RUNNER_ENVIRONMENT"); detector.Name() == ci.ProviderGitHubActions && kind != "" {
+		return detector.Name() + "/" + kind
+	}
+	return detector.Name()
+}
+
+// testTags returns the build tags the tests were built with, from the -tags flag of GOFLAGS
+func testTags(getenv func(string) string, flags []string) string {
+	if tags := strings.TrimSpace(getenv(EnvTestTags)); tags != "" {
+		return tags
+	}
+	for _, flag := range flags {
+		if tags, ok := strings.CutPrefix("-"+strings.TrimLeft(flag, "-"), "-tags="); ok {
+			return tags
+		}
+	}
+	return ""
+}
+
+// profileFlags returns the flags that shaped the profile: the cover mode and the GOFLAGS entries
+// that change what is instrumented or run
+func profileFlags(getenv func(string) string, flags []string, mode string) string {
+	if value := strings.TrimSpace(getenv(EnvProfileFlags)); value != "" {
+		return value
+	}
+	var shaping []string
+	if mode != "" {
+		shaping = append(shaping, "-covermode="+mode)
+	}
+	for _, flag := range flags {
+		flag = "-" + strings.TrimLeft(flag, "-")
+		name, _, _ := strings.Cut(flag, "=")
+		for _, candidate := range profileFlagNames() {
+			if name == candidate {
+				shaping = append(shaping, flag)
+			}
+		}
+	}
+	return strings.Join(shaping, " ")
+}
+
+// ParseFilter parses an environment filter: comma separated key=value pairs, such as
+// "go_version=go1.25*,os=linux". Values may hold path.Match wildcards.
+func ParseFilter(spec string) (map[string]string, error) {
+	filter := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q is not key=value", ErrInvalidFilter, pair)
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFilter, pair, err)
+		}
+		filter[key] = value
+	}
+	return filter, nil
+}
+
+// FormatFilter formats a filter back into its key=value form, keys sorted
+func FormatFilter(filter map[string]string) string {
+	pairs := make([]string, 0, len(filter))
+	for _, key := range slices.Sorted(maps.Keys(filter)) {
+		pairs = append(pairs, key+"="+filter[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Matches reports whether the metadata of an entry matches every pair of the filter. Entries
+// recorded before the environment was captured match no filter on it.
+func Matches(metadata, filter map[string]string) bool {
+	for key, pattern := range filter {
+		value, ok := metadata[key]
+		if !ok {
+			return false
+		}
+		if matched, err := path.Match(pattern, value); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Label describes an environment in one line, such as "go1.25.1 linux/amd64
+// github-actions/github-hosted tags=integration -covermode=atomic", or "" when none of it is known
+func Label(metadata map[string]string) string {
+	var parts []string
+	if version := metadata[KeyGoVersion]; version != "" {
+		parts = append(parts, version)
+	}
+	if goos, goarch := metadata[KeyOS], metadata[KeyArch]; goos != "" || goarch != "" {
+		parts = append(parts, strings.Trim(goos+"/"+goarch, "/"))
+	}
+	if name := metadata[KeyRunner]; name != "" {
+		parts = append(parts, name)
+	}
+	if tags := metadata[KeyTestTags]; tags != "" {
+		parts = append(parts, "tags="+tags)
+	}
+	if flags := metadata[KeyProfileFlags]; flags != "" {
+		parts = append(parts, flags)
+	}
+	return strings.Join(parts, " ")
+}
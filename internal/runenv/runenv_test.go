@@ -0,0 +1,101 @@
+package runenv
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getenvOf returns a getenv reading the variables of env
+func getenvOf(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("github hosted runner with GOFLAGS", func(t *testing.T) {
+		env := Describe(getenvOf(map[string]string{
+			"GITHUB_ACTIONS":     "true",
+			"RUNNER_ENVIRONMENT": "self-hosted",
+			"GOFLAGS":            "-mod=mod -tags=integration,pg --race -count=1",
+		}), "go1.25.1", "linux", "amd64", "atomic")
+
+		assert.Equal(t, map[string]string{
+			KeyGoVersion:    "go1.25.1",
+			KeyOS:           "linux",
+			KeyArch:         "amd64",
+			KeyRunner:       "github-actions/self-hosted",
+			KeyTestTags:     "integration,pg",
+			KeyProfileFlags: "-covermode=atomic -race",
+		}, env)
+	})
+
+	t.Run("local run leaves unknown keys out", func(t *testing.T) {
+		env := Describe(getenvOf(map[string]string{}), "go1.25.1", "darwin", "arm64", "")
+
+		assert.Equal(t, RunnerLocal, env[KeyRunner])
+		assert.NotContains(t, env, KeyTestTags)
+		assert.NotContains(t, env, KeyProfileFlags)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		env := Describe(getenvOf(map[string]string{
+			"GITLAB_CI":     "true",
+			"GOFLAGS":       "-tags=unit",
+			EnvRunner:       "gitlab/shared",
+			EnvTestTags:     "e2e",
+			EnvProfileFlags: "-covermode=set -coverpkg=./...",
+		}), "go1.24.0", "linux", "arm64", "atomic")
+
+		assert.Equal(t, "gitlab/shared", env[KeyRunner])
+		assert.Equal(t, "e2e", env[KeyTestTags])
+		assert.Equal(t, "-covermode=set -coverpkg=./...", env[KeyProfileFlags])
+	})
+}
+
+func TestCapture(t *testing.T) {
+	env := Capture(context.Background(), getenvOf(map[string]string{}), "set")
+
+	assert.NotEmpty(t, env[KeyGoVersion])
+	assert.Equal(t, runtime.GOOS, env[KeyOS])
+	assert.Equal(t, runtime.GOARCH, env[KeyArch])
+	assert.Equal(t, "-covermode=set", env[KeyProfileFlags])
+}
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(" go_version=go1.25* , os=linux,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{KeyGoVersion: "go1.25*", KeyOS: "linux"}, filter)
+	assert.Equal(t, "go_version=go1.25*,os=linux", FormatFilter(filter))
+
+	_, err = ParseFilter("linux")
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = ParseFilter("os=[linux")
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestMatches(t *testing.T) {
+	metadata := map[string]string{KeyGoVersion: "go1.25.1", KeyOS: "linux", "project": "app"}
+
+	assert.True(t, Matches(metadata, nil))
+	assert.True(t, Matches(metadata, map[string]string{KeyGoVersion: "go1.25*", KeyOS: "linux"}))
+	assert.False(t, Matches(metadata, map[string]string{KeyOS: "darwin"}))
+	assert.False(t, Matches(metadata, map[string]string{KeyRunner: "*"}))
+	assert.False(t, Matches(nil, map[string]string{KeyOS: "linux"}))
+}
+
+func TestLabel(t *testing.T) {
+	assert.Equal(t, "go1.25.1 linux/amd64 github-actions/github-hosted tags=integration -covermode=atomic", Label(map[string]string{
+		KeyGoVersion:    "go1.25.1",
+		KeyOS:           "linux",
+		KeyArch:         "amd64",
+		KeyRunner:       "github-actions/github-hosted",
+		KeyTestTags:     "integration",
+		KeyProfileFlags: "-covermode=atomic",
+	}))
+	assert.Equal(t, "linux", Label(map[string]string{KeyOS: "linux"}))
+	assert.Empty(t, Label(map[string]string{"project": "app"}))
+}
@@ -0,0 +1,123 @@
+// Package schema versions the JSON documents go-coverage persists and upgrades documents
+// written by older releases, so stored history, artifacts and published sites stay readable
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// VersionField is the JSON field holding the schema version of a document
+const VersionField = "schema_version"
+
+// Static error definitions
+var (
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrInvalidDocument    = errors.New("invalid document")
+)
+
+// Migration upgrades a decoded document by one version. Objects are map[string]any, arrays
+// []any and numbers json.Number. The version field is set by the caller.
+type Migration func(doc any) (any, error)
+
+// Unchanged is the migration for a version that only introduced the version field
+func Unchanged(doc any) (any, error) {
+	return doc, nil
+}
+
+// Schema describes one kind of persisted document
+type Schema struct {
+	// Name identifies the document in errors
+	Name string
+	// Migrations[i] upgrades a document of version i to version i+1; documents without a
+	// version field are version 0
+	Migrations []Migration
+}
+
+// Version returns the version written by this build
+func (s *Schema) Version() int {
+	return len(s.Migrations)
+}
+
+// Upgrade returns data migrated to the current version. Documents already at the current
+// version are returned as-is; documents from a newer release are rejected.
+func (s *Schema) Upgrade(data []byte) ([]byte, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidDocument, s.Name, err)
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidDocument, s.Name, err)
+	}
+	switch {
+	case version == s.Version():
+		return data, nil
+	case version > s.Version():
+		return nil, fmt.Errorf("%w: %s version %d is newer than %d", ErrUnsupportedVersion, s.Name, version, s.Version())
+	}
+
+	for ; version < s.Version(); version++ {
+		if doc, err = s.Migrations[version](doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s from version %d: %w", s.Name, version, err)
+		}
+	}
+	object, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s migrated to a %T, expected an object", ErrInvalidDocument, s.Name, doc)
+	}
+	object[VersionField] = version
+
+	upgraded, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal migrated %s: %w", s.Name, err)
+	}
+	return upgraded, nil
+}
+
+// Decode upgrades data to the current version and unmarshals it into v
+func (s *Schema) Decode(data []byte, v any) error {
+	upgraded, err := s.Upgrade(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(upgraded, v); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidDocument, s.Name, err)
+	}
+	return nil
+}
+
+// decode parses a document keeping numbers exact
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// documentVersion returns the version field of an object, 0 when it has none
+func documentVersion(doc any) (int, error) {
+	object, ok := doc.(map[string]any)
+	if !ok {
+		return 0, nil
+	}
+	raw, ok := object[VersionField]
+	if !ok {
+		return 0, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s is %v, expected a number", VersionField, raw)
+	}
+	version, err := number.Int64()
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%s is %s, expected a non-negative integer", VersionField, number)
+	}
+	return int(version), nil
+}
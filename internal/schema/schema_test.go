@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBrokenMigration = errors.New("broken")
+
+// testSchema wraps bare arrays in version 1 and renames total to statements in version 2
+//
+//nolint:gochecknoglobals // read-only schema definition
+var testSchema = Schema{
+	Name: "test document",
+	Migrations: []Migration{
+		func(doc any) (any, error) {
+			if items, ok := doc.([]any); ok {
+				return map[string]any{"items": items}, nil
+			}
+			return doc, nil
+		},
+		func(doc any) (any, error) {
+			object := doc.(map[string]any) //nolint:errcheck,forcetypeassert // version 1 is always an object
+			if total, ok := object["total"]; ok {
+				object["statements"] = total
+				delete(object, "total")
+			}
+			return object, nil
+		},
+	},
+}
+
+type testDocument struct {
+	SchemaVersion int      `json:"schema_version"`
+	Items         []string `json:"items"`
+	Statements    int64    `json:"statements"`
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected testDocument
+	}{
+		{"legacy array", `["a","b"]`, testDocument{SchemaVersion: 2, Items: []string{"a", "b"}}},
+		{"version 1", `{"schema_version":1,"items":["a"],"total":9007199254740993}`, testDocument{SchemaVersion: 2, Items: []string{"a"}, Statements: 9007199254740993}},
+		{"current", `{"schema_version":2,"statements":3}`, testDocument{SchemaVersion: 2, Statements: 3}},
+		{"unversioned object", `{"items":["x"],"total":1}`, testDocument{SchemaVersion: 2, Items: []string{"x"}, Statements: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc testDocument
+			require.NoError(t, testSchema.Decode([]byte(tt.input), &doc))
+			assert.Equal(t, tt.expected, doc)
+		})
+	}
+}
+
+func TestUpgradeCurrentIsUnchanged(t *testing.T) {
+	input := []byte(`{"schema_version": 2, "statements": 3}`)
+	upgraded, err := testSchema.Upgrade(input)
+	require.NoError(t, err)
+	assert.Equal(t, input, upgraded)
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	_, err := testSchema.Upgrade([]byte(`{"schema_version":3}`))
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = testSchema.Upgrade([]byte(`{"schema_version":"1"}`))
+	require.ErrorIs(t, err, ErrInvalidDocument)
+
+	_, err = testSchema.Upgrade([]byte(`{`))
+	require.ErrorIs(t, err, ErrInvalidDocument)
+
+	broken := Schema{Name: "broken", Migrations: []Migration{func(any) (any, error) { return nil, errBrokenMigration }}}
+	_, err = broken.Upgrade([]byte(`{}`))
+	require.ErrorIs(t, err, errBrokenMigration)
+
+	scalar := Schema{Name: "scalar", Migrations: []Migration{Unchanged}}
+	_, err = scalar.Upgrade([]byte(`[1]`))
+	require.ErrorIs(t, err, ErrInvalidDocument)
+}
+
+func TestUnchanged(t *testing.T) {
+	var doc testDocument
+	single := Schema{Name: "single", Migrations: []Migration{Unchanged}}
+	require.NoError(t, single.Decode([]byte(`{"items":["a"]}`), &doc))
+	assert.Equal(t, 1, doc.SchemaVersion)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"schema_version":1`)
+}
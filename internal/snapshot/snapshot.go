@@ -0,0 +1,148 @@
+// Package snapshot keeps the coverage data of each pull request and branch next to its report
+// and indexes them, so the published site serves stable per-context data alongside the
+// coverage-data.json of the latest run.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mrz1836/go-coverage/internal/schema"
+)
+
+// ErrInvalidPath indicates an index entry pointing outside the site or at another file
+var ErrInvalidPath = errors.New("invalid snapshot path")
+
+const (
+	// DataFile is the name of the coverage data of a run, at the site root and in each snapshot
+	DataFile = "coverage-data.json"
+	// IndexFile is the index of the snapshots at the site root
+	IndexFile = "coverage-snapshots.json"
+)
+
+// Contexts a snapshot belongs to
+const (
+	KindPullRequest = "pr"
+	KindBranch      = "branch"
+)
+
+// IndexSchema versions the snapshot index
+//
+//nolint:gochecknoglobals // read-only schema definition
+var IndexSchema = schema.Schema{
+	Name:       "coverage snapshot index",
+	Migrations: []schema.Migration{schema.Unchanged},
+}
+
+// Entry describes the snapshot of one pull request or branch
+type Entry struct {
+	// Kind is KindPullRequest or KindBranch
+	Kind string `json:"kind"`
+	// Name is the pull request number or the branch name
+	Name string `json:"name"`
+	// Path of the snapshot relative to the site root, with forward slashes
+	Path      string    `json:"path"`
+	CommitSHA string    `json:"commit_sha,omitempty"`
+	Coverage  float64   `json:"coverage"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Index lists the snapshots of a site, pull requests by number first, then branches by name
+type Index struct {
+	SchemaVersion int     `json:"schema_version"`
+	Snapshots     []Entry `json:"snapshots"`
+}
+
+// Load reads the index of the site in dir; a site without one has an empty index
+func Load(dir string) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile)) //nolint:gosec // dir is the configured output directory
+	if errors.Is(err, os.ErrNotExist) {
+		return &Index{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+
+	var index Index
+	if err = IndexSchema.Decode(data, &index); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// Upsert records the snapshot of a context, replacing the earlier one
+func (idx *Index) Upsert(entry Entry) {
+	for i := range idx.Snapshots {
+		if idx.Snapshots[i].Kind == entry.Kind && idx.Snapshots[i].Name == entry.Name {
+			idx.Snapshots[i] = entry
+			return
+		}
+	}
+	idx.Snapshots = append(idx.Snapshots, entry)
+}
+
+// Prune drops the snapshots not updated within retention and returns them; a retention of zero
+// keeps all of them
+func (idx *Index) Prune(now time.Time, retention time.Duration) []Entry {
+	if retention <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-retention)
+	var kept, pruned []Entry
+	for _, entry := range idx.Snapshots {
+		if entry.UpdatedAt.Before(cutoff) {
+			pruned = append(pruned, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	idx.Snapshots = kept
+	return pruned
+}
+
+// Save writes the index to the site in dir
+func (idx *Index) Save(dir string, mode os.FileMode) error {
+	idx.SchemaVersion = IndexSchema.Version()
+	if idx.Snapshots == nil {
+		idx.Snapshots = []Entry{}
+	}
+	sort.SliceStable(idx.Snapshots, func(i, j int) bool {
+		a, b := idx.Snapshots[i], idx.Snapshots[j]
+		if a.Kind != b.Kind {
+			return a.Kind == KindPullRequest
+		}
+		// Pull requests sort by number
+		if len(a.Name) != len(b.Name) && a.Kind == KindPullRequest {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot index: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, IndexFile), data, mode); err != nil {
+		return fmt.Errorf("failed to write snapshot index: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the snapshot file of an entry from the site in dir; the report next to it is
+// left in place
+func Remove(dir string, entry Entry) error {
+	rel := path.Clean(entry.Path)
+	if !filepath.IsLocal(filepath.FromSlash(rel)) || path.Base(rel) != DataFile {
+		return fmt.Errorf("%w: refusing to remove %q", ErrInvalidPath, entry.Path)
+	}
+	if err := os.Remove(filepath.Join(dir, filepath.FromSlash(rel))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
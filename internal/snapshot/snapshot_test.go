@@ -0,0 +1,69 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	index, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, index.Snapshots)
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	index.Upsert(Entry{Kind: KindBranch, Name: "master", Path: "reports/branch/master/coverage-data.json", Coverage: 80, UpdatedAt: now})
+	index.Upsert(Entry{Kind: KindPullRequest, Name: "10", Path: "pr/10/coverage-data.json", Coverage: 81, UpdatedAt: now})
+	index.Upsert(Entry{Kind: KindPullRequest, Name: "9", Path: "pr/9/coverage-data.json", Coverage: 79, UpdatedAt: now})
+	index.Upsert(Entry{Kind: KindBranch, Name: "master", Path: "reports/branch/master/coverage-data.json", Coverage: 82, UpdatedAt: now})
+	require.NoError(t, index.Save(dir, 0o600))
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, IndexSchema.Version(), loaded.SchemaVersion)
+	require.Len(t, loaded.Snapshots, 3)
+	assert.Equal(t, "9", loaded.Snapshots[0].Name)
+	assert.Equal(t, "10", loaded.Snapshots[1].Name)
+	assert.Equal(t, "master", loaded.Snapshots[2].Name)
+	assert.InDelta(t, 82.0, loaded.Snapshots[2].Coverage, 0.001, "a context keeps its latest snapshot")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, IndexFile), []byte("{"), 0o600))
+	_, err = Load(dir)
+	require.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	index := &Index{Snapshots: []Entry{
+		{Kind: KindPullRequest, Name: "1", UpdatedAt: now.AddDate(0, 0, -40)},
+		{Kind: KindPullRequest, Name: "2", UpdatedAt: now.AddDate(0, 0, -2)},
+	}}
+
+	assert.Empty(t, index.Prune(now, 0), "a retention of zero keeps everything")
+	pruned := index.Prune(now, 30*24*time.Hour)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "1", pruned[0].Name)
+	require.Len(t, index.Snapshots, 1)
+	assert.Equal(t, "2", index.Snapshots[0].Name)
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	snapshotDir := filepath.Join(dir, "pr", "1")
+	require.NoError(t, os.MkdirAll(snapshotDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(snapshotDir, DataFile), []byte("{}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(snapshotDir, "coverage.html"), []byte("report"), 0o600))
+
+	require.NoError(t, Remove(dir, Entry{Path: "pr/1/coverage-data.json"}))
+	assert.NoFileExists(t, filepath.Join(snapshotDir, DataFile))
+	assert.FileExists(t, filepath.Join(snapshotDir, "coverage.html"), "the report stays")
+	require.NoError(t, Remove(dir, Entry{Path: "pr/1/coverage-data.json"}), "missing snapshots are fine")
+
+	require.ErrorIs(t, Remove(dir, Entry{Path: "../outside/coverage-data.json"}), ErrInvalidPath)
+	require.ErrorIs(t, Remove(dir, Entry{Path: "pr/1/coverage.html"}), ErrInvalidPath)
+}
@@ -0,0 +1,212 @@
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxCommentLength is the largest comment body GitHub accepts, in characters
+const MaxCommentLength = 65536
+
+// sectionPinned marks sections that are never shortened or omitted
+const sectionPinned = -1
+
+// sectionPriorities ranks the comment sections by heading; sections with a lower priority are
+// shortened or omitted first. Sections of custom templates that are not listed get
+// defaultSectionPriority.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var sectionPriorities = map[string]int{
+	"Coverage Metrics":   sectionPinned,
+	"Coverage Policy":    sectionPinned,
+	"Resources":          sectionPinned,
+	"Insights":           50,
+	"File Changes":       40,
+	"Quality Assessment": 30,
+	"Recommendations":    20,
+	"Trend Analysis":     10,
+}
+
+// defaultSectionPriority is the priority of sections without an entry in sectionPriorities
+const defaultSectionPriority = 25
+
+// commentSection is a "## " section of a rendered comment, or the preamble before the first one
+type commentSection struct {
+	title    string
+	text     string
+	priority int
+}
+
+// FitComment shortens a rendered Markdown comment to at most limit characters. Sections are
+// given up from the least important: tables lose their last rows first, and a section that
+// still does not fit is replaced by a short notice. Both point readers at fullURL, where the
+// untruncated comment lives. Pinned sections, such as the metrics and resources, are kept; if
+// the comment still exceeds the limit it is cut at a line boundary as a last resort.
+// It returns the fitted comment and the titles of the sections that were shortened or omitted.
+func FitComment(body string, limit int, fullURL string) (string, []string) {
+	if limit <= 0 || utf8.RuneCountInString(body) <= limit {
+		return body, nil
+	}
+
+	sections := splitSections(body)
+	order := make([]int, 0, len(sections))
+	for i, section := range sections {
+		if section.priority != sectionPinned {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return sections[a].priority - sections[b].priority
+	})
+
+	var shortened []string
+	for _, i := range order {
+		excess := joinedLength(sections) - limit
+		if excess <= 0 {
+			break
+		}
+		if text, ok := shrinkTable(sections[i].text, excess, fullURL); ok {
+			sections[i].text = text
+			shortened = append(shortened, sections[i].title)
+			continue
+		}
+		// Sections shorter than the notice are left alone
+		if notice := omittedSection(sections[i].title, fullURL); utf8.RuneCountInString(notice) < utf8.RuneCountInString(sections[i].text) {
+			sections[i].text = notice
+			shortened = append(shortened, sections[i].title)
+		}
+	}
+
+	fitted := joinSections(sections)
+	if utf8.RuneCountInString(fitted) > limit {
+		fitted = cutComment(fitted, limit, fullURL)
+	}
+	return fitted, shortened
+}
+
+// splitSections splits a comment at its "## " headings
+func splitSections(body string) []commentSection {
+	lines := strings.SplitAfter(body, "\n")
+	sections := []commentSection{{priority: sectionPinned}}
+	for _, line := range lines {
+		if title, ok := strings.CutPrefix(line, "## "); ok {
+			title = strings.TrimSpace(title)
+			sections = append(sections, commentSection{title: title, priority: sectionPriority(title)})
+		}
+		sections[len(sections)-1].text += line
+	}
+	return sections
+}
+
+// sectionPriority looks up the priority of a heading, ignoring suffixes such as "(12)"
+func sectionPriority(title string) int {
+	for name, priority := range sectionPriorities {
+		if title == name || strings.HasPrefix(title, name+" ") {
+			return priority
+		}
+	}
+	return defaultSectionPriority
+}
+
+// joinSections reassembles the sections of a comment
+func joinSections(sections []commentSection) string {
+	var b strings.Builder
+	for _, section := range sections {
+		b.WriteString(section.text)
+	}
+	return b.String()
+}
+
+// joinedLength returns the length of the reassembled comment in characters
+func joinedLength(sections []commentSection) int {
+	total := 0
+	for _, section := range sections {
+		total += utf8.RuneCountInString(section.text)
+	}
+	return total
+}
+
+// shrinkTable removes the last rows of the first table in a section until the section is at
+// least excess characters shorter, noting how many rows are shown. It reports false when the
+// section has no table or would have to lose every row.
+func shrinkTable(text string, excess int, fullURL string) (string, bool) {
+	lines := strings.SplitAfter(text, "\n")
+
+	var rows []int
+	inTable := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inTable && strings.HasPrefix(trimmed, "|-"):
+			inTable = true
+		case inTable && strings.HasPrefix(trimmed, "|"):
+			rows = append(rows, i)
+		case inTable && trimmed != "":
+			inTable = false
+		}
+		if !inTable && len(rows) > 0 {
+			break
+		}
+	}
+
+	for keep := len(rows) - 1; keep > 0; keep-- {
+		note := fmt.Sprintf("\n_Showing %d of %d rows. %s_\n", keep, len(rows), fullReportSentence(fullURL))
+		removed := 0
+		for _, i := range rows[keep:] {
+			removed += utf8.RuneCountInString(lines[i])
+		}
+		if removed-utf8.RuneCountInString(note) < excess {
+			continue
+		}
+
+		last := rows[keep-1]
+		var b strings.Builder
+		for i, line := range lines {
+			if i > last && i <= rows[len(rows)-1] {
+				continue
+			}
+			b.WriteString(line)
+			if i == last {
+				b.WriteString(note)
+			}
+		}
+		return b.String(), true
+	}
+	return "", false
+}
+
+// omittedSection replaces a section that does not fit with a notice
+func omittedSection(title, fullURL string) string {
+	return fmt.Sprintf("## %s\n\n_Omitted to fit GitHub's comment size limit. %s_\n\n", title, fullReportSentence(fullURL))
+}
+
+// cutComment cuts a comment at the last line boundary that leaves room for a truncation notice,
+// closing a collapsible section left open by the cut
+func cutComment(body string, limit int, fullURL string) string {
+	notice := fmt.Sprintf("\n\n---\n\n_Comment truncated to fit GitHub's comment size limit. %s_\n", fullReportSentence(fullURL))
+	const closeDetails = "\n</details>\n"
+
+	room := limit - utf8.RuneCountInString(notice) - utf8.RuneCountInString(closeDetails)
+	if room <= 0 {
+		return string([]rune(body)[:limit])
+	}
+
+	cut := string([]rune(body)[:room])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	if strings.Count(cut, "<details>") > strings.Count(cut, "</details>") {
+		cut += closeDetails
+	}
+	return cut + notice
+}
+
+// fullReportSentence points readers at the untruncated comment when its location is known
+func fullReportSentence(fullURL string) string {
+	if fullURL == "" {
+		return "The full report is available in the workflow run."
+	}
+	return fmt.Sprintf("See the [full report](%s).", fullURL)
+}
@@ -0,0 +1,142 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFullURL = "https://owner.github.io/repo/pr/1/comment.md"
+
+// budgetTestComment builds a comment with a large file table and the usual sections
+func budgetTestComment(rows int) string {
+	var b strings.Builder
+	b.WriteString("[//]: # (go-coverage-v1)\n\n# Code Coverage Analysis\n\n")
+	b.WriteString("## Coverage Metrics\n\n| Metric | Value |\n|--------|-------|\n| **Percentage** | 80% |\n\n")
+	fmt.Fprintf(&b, "## File Changes (%d)\n\n<details>\n<summary>View file coverage changes</summary>\n\n", rows)
+	b.WriteString("| File | Coverage |\n|------|----------|\n")
+	for i := range rows {
+		fmt.Fprintf(&b, "\n| `internal/package/file_%04d.go` | 80.0%% |\n", i)
+	}
+	b.WriteString("\n</details>\n\n")
+	b.WriteString("## Recommendations\n\n### Improve coverage\n\nAdd tests for the parser.\n\n")
+	b.WriteString("## Trend Analysis\n\n- **Direction**: up\n- **Momentum**: steady\n- **Trend**: " + strings.Repeat("▁▂▃▄▅▆▇█", 20) + "\n\n")
+	b.WriteString("## Resources\n- 📊 [PR Coverage Report](https://example.com)\n\n---\n\n*Generated via go-coverage*\n")
+	return b.String()
+}
+
+func TestFitCommentWithinLimit(t *testing.T) {
+	body := budgetTestComment(3)
+	fitted, shortened := FitComment(body, MaxCommentLength, testFullURL)
+	assert.Equal(t, body, fitted)
+	assert.Empty(t, shortened)
+
+	fitted, shortened = FitComment(body, 0, testFullURL)
+	assert.Equal(t, body, fitted)
+	assert.Empty(t, shortened)
+}
+
+func TestFitCommentDropsLowPrioritySectionsFirst(t *testing.T) {
+	body := budgetTestComment(3)
+	limit := utf8.RuneCountInString(body) - 10
+
+	fitted, shortened := FitComment(body, limit, testFullURL)
+	assert.LessOrEqual(t, utf8.RuneCountInString(fitted), limit)
+	assert.Equal(t, []string{"Trend Analysis"}, shortened)
+	assert.Contains(t, fitted, "## Trend Analysis\n\n_Omitted to fit GitHub's comment size limit. See the [full report]("+testFullURL+")._")
+	assert.Contains(t, fitted, "## Recommendations")
+	assert.Contains(t, fitted, "file_0002.go")
+}
+
+func TestFitCommentShrinksTables(t *testing.T) {
+	body := budgetTestComment(500)
+	limit := utf8.RuneCountInString(body) / 2
+
+	fitted, shortened := FitComment(body, limit, testFullURL)
+	assert.LessOrEqual(t, utf8.RuneCountInString(fitted), limit)
+	assert.Equal(t, []string{"Trend Analysis", "File Changes (500)"}, shortened)
+	assert.Contains(t, fitted, "file_0000.go")
+	assert.NotContains(t, fitted, "file_0499.go")
+	assert.Regexp(t, `_Showing \d+ of 500 rows\. See the \[full report\]`, fitted)
+	assert.Contains(t, fitted, "</details>")
+	assert.Contains(t, fitted, "## Coverage Metrics")
+	assert.Contains(t, fitted, "## Resources")
+	assert.True(t, strings.HasPrefix(fitted, "[//]: # (go-coverage-v1)"))
+}
+
+func TestFitCommentCutsAsLastResort(t *testing.T) {
+	body := budgetTestComment(2) + strings.Repeat("pinned footer line\n", 200)
+	limit := 1000
+
+	fitted, _ := FitComment(body, limit, "")
+	assert.LessOrEqual(t, utf8.RuneCountInString(fitted), limit)
+	assert.True(t, strings.HasPrefix(fitted, "[//]: # (go-coverage-v1)"))
+	assert.Contains(t, fitted, "_Comment truncated to fit GitHub's comment size limit. The full report is available in the workflow run._")
+	assert.Equal(t, strings.Count(fitted, "<details>"), strings.Count(fitted, "</details>"))
+}
+
+func TestShrinkTableWithoutTable(t *testing.T) {
+	_, ok := shrinkTable("## Trend Analysis\n\n- up\n", 5, testFullURL)
+	assert.False(t, ok)
+}
+
+func TestSectionPriority(t *testing.T) {
+	assert.Equal(t, sectionPinned, sectionPriority("Coverage Metrics"))
+	assert.Equal(t, 40, sectionPriority("File Changes (12)"))
+	assert.Equal(t, defaultSectionPriority, sectionPriority("Custom Section"))
+	assert.Equal(t, defaultSectionPriority, sectionPriority("File Changesets"))
+}
+
+func TestRenderBudgetedComment(t *testing.T) {
+	config := &TemplateConfig{
+		MaxFileChanges:         1000,
+		UseMarkdownTables:      true,
+		UseCollapsibleSections: true,
+		BrandingEnabled:        true,
+		MaxCommentLength:       4000,
+	}
+	engine := NewPRTemplateEngine(config)
+
+	files := make([]FileCoverageData, 200)
+	for i := range files {
+		files[i] = FileCoverageData{
+			Filename:   fmt.Sprintf("internal/package/file_%03d.go", i),
+			Percentage: 50,
+			Change:     float64(i%7) + 1,
+			Status:     "improved",
+			Risk:       priorityLow,
+			IsModified: true,
+		}
+	}
+	data := &TemplateData{
+		Coverage: CoverageData{
+			Overall: CoverageMetrics{Percentage: 80, TotalStatements: 100, CoveredStatements: 80, Status: "good"},
+			Files:   files,
+		},
+		Resources: ResourceLinks{ReportURL: "https://owner.github.io/repo/pr/1/coverage.html"},
+	}
+
+	rendered, err := engine.RenderBudgetedComment(context.Background(), data)
+	require.NoError(t, err)
+	assert.True(t, rendered.Truncated())
+	assert.LessOrEqual(t, utf8.RuneCountInString(rendered.Body), 4000)
+	assert.Contains(t, rendered.Full, "file_199.go")
+	assert.Contains(t, rendered.Body, "[full report](https://owner.github.io/repo/pr/1/coverage.html)")
+	assert.NotEmpty(t, rendered.Shortened)
+
+	data.Resources.FullReportURL = testFullURL
+	body, err := engine.RenderComment(context.Background(), "", data)
+	require.NoError(t, err)
+	assert.Contains(t, body, "[full report]("+testFullURL+")")
+
+	config.MaxCommentLength = 0
+	rendered, err = engine.RenderBudgetedComment(context.Background(), data)
+	require.NoError(t, err)
+	assert.False(t, rendered.Truncated())
+	assert.Empty(t, rendered.Shortened)
+}
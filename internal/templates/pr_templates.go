@@ -32,6 +32,9 @@ const (
 	priorityLow    = "low"
 )
 
+// DefaultSignature identifies the PR comments of go-coverage when no signature is configured
+const DefaultSignature = "go-coverage-v1"
+
 // PRTemplateEngine handles advanced PR comment template rendering
 type PRTemplateEngine struct {
 	templates map[string]*template.Template
@@ -48,6 +51,7 @@ type TemplateConfig struct {
 	MaxFileChanges     int  // Maximum file changes to show
 	MaxPackageChanges  int  // Maximum package changes to show
 	MaxRecommendations int  // Maximum recommendations to show
+	MaxInsights        int  // Maximum insights to show (0 hides the section)
 	HideStableFiles    bool // Hide files with no significant changes
 
 	// Styling options
@@ -62,11 +66,15 @@ type TemplateConfig struct {
 	WarningThreshold   float64 // Threshold for warning coverage
 	CriticalThreshold  float64 // Threshold for critical coverage
 
+	// Size budget
+	MaxCommentLength int // Maximum comment length in characters (0 uses MaxCommentLength)
+
 	// Customization
 	CustomFooter    string // Custom footer text
 	CustomHeader    string // Custom header text
 	BrandingEnabled bool   // Include branding
 	TimestampFormat string // Timestamp format
+	Signature       string // Signature identifying the comment (empty uses DefaultSignature)
 }
 
 // TemplateData represents all data available to templates
@@ -84,6 +92,13 @@ type TemplateData struct {
 	// Analysis results
 	Quality         QualityData          `json:"quality"`
 	Recommendations []RecommendationData `json:"recommendations"`
+	Insights        []InsightData        `json:"insights,omitempty"`
+
+	// Gating policy evaluation (nil when not evaluated)
+	Policy *PolicyData `json:"policy,omitempty"`
+
+	// Coverage of the base branch expected after the merge (nil without base branch history)
+	Forecast *ForecastData `json:"forecast,omitempty"`
 
 	// PR file analysis
 	PRFiles *PRFileAnalysisData `json:"pr_files,omitempty"`
@@ -197,6 +212,37 @@ type QualityData struct {
 	Weaknesses    []string `json:"weaknesses"`
 }
 
+// PolicyData represents the outcome of the coverage gating policies
+type PolicyData struct {
+	Passed     bool               `json:"passed"`
+	Results    []PolicyResultData `json:"results"`
+	Confidence *ConfidenceData    `json:"confidence,omitempty"` // Band around overall coverage, nil without enough history
+	Bypass     string             `json:"bypass,omitempty"`     // Why an emergency bypass downgraded failed rules to warnings
+	Warmup     string             `json:"warmup,omitempty"`     // Progress of the warm-up that downgraded failed rules to warnings
+	// WarmupEnded is set on the first run whose gates are enforced after the warm-up
+	WarmupEnded bool `json:"warmup_ended,omitempty"`
+	// Critical paths below their required coverage, called out above the metrics
+	CriticalFailures []PolicyResultData `json:"critical_failures,omitempty"`
+}
+
+// ConfidenceData is the confidence band around overall coverage, estimated from recent history
+type ConfidenceData struct {
+	Margin float64 `json:"margin"` // Half-width in percentage points
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+	Level  float64 `json:"level"` // Confidence level in percent
+	Runs   int     `json:"runs"`  // Earlier runs the band was estimated from
+}
+
+// PolicyResultData explains the outcome of a single policy rule
+type PolicyResultData struct {
+	Rule    string   `json:"rule"`
+	Outcome string   `json:"outcome"` // "pass", "warn", "fail", "skip"
+	Icon    string   `json:"icon"`
+	Message string   `json:"message"`
+	Trace   []string `json:"trace,omitempty"` // Per-comparison evaluation steps for gate expressions
+}
+
 // RecommendationData represents recommendation information
 type RecommendationData struct {
 	Type        string   `json:"type"`
@@ -207,12 +253,30 @@ type RecommendationData struct {
 	Impact      string   `json:"impact"`
 }
 
+// InsightData is one actionable finding for reviewers, such as new files with low coverage
+type InsightData struct {
+	Priority string `json:"priority"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	URL      string `json:"url,omitempty"` // Where to act on the insight
+}
+
+// ForecastData is the coverage the base branch is expected to reach once the PR is merged
+type ForecastData struct {
+	Branch     string   `json:"branch"`
+	Current    float64  `json:"current"`
+	Projected  float64  `json:"projected"`
+	Violations []string `json:"violations,omitempty"` // Limits of the branch the projection breaks
+}
+
 // ResourceLinks contains URLs and links for the PR comment
 type ResourceLinks struct {
-	BadgeURL      string `json:"badge_url"`
-	ReportURL     string `json:"report_url"`
-	DashboardURL  string `json:"dashboard_url"`
-	HistoricalURL string `json:"historical_url"`
+	BadgeURL       string `json:"badge_url"`
+	ReportURL      string `json:"report_url"`
+	DashboardURL   string `json:"dashboard_url"`
+	HistoricalURL  string `json:"historical_url"`
+	FullReportURL  string `json:"full_report_url"`            // Untruncated comment, linked when the comment is shortened
+	PackageDiffURL string `json:"package_diff_url,omitempty"` // Package coverage before and after the pull request, as an image
 }
 
 // TemplateMetadata contains template metadata
@@ -271,6 +335,7 @@ func NewPRTemplateEngine(config *TemplateConfig) *PRTemplateEngine {
 			MaxFileChanges:         20,
 			MaxPackageChanges:      10,
 			MaxRecommendations:     5,
+			MaxInsights:            3,
 			HideStableFiles:        true,
 			UseMarkdownTables:      true,
 			UseCollapsibleSections: true,
@@ -296,11 +361,60 @@ func NewPRTemplateEngine(config *TemplateConfig) *PRTemplateEngine {
 	return engine
 }
 
-// RenderComment renders a PR comment using the comprehensive template
-func (e *PRTemplateEngine) RenderComment(_ context.Context, _ string, data *TemplateData) (string, error) {
-	// Always use comprehensive template (only template available)
-	templateName := "comprehensive"
+// RenderedComment is a comment fitted to the size budget together with its untruncated version
+type RenderedComment struct {
+	Body      string   // Comment to post, within the size budget
+	Full      string   // Untruncated comment
+	Shortened []string // Titles of the sections that were shortened or omitted
+}
+
+// Truncated reports whether the comment had to be shortened to fit the size budget
+func (r *RenderedComment) Truncated() bool {
+	return r.Body != r.Full
+}
+
+// RenderComment renders a PR comment using the comprehensive template, shortened to the size budget
+func (e *PRTemplateEngine) RenderComment(ctx context.Context, _ string, data *TemplateData) (string, error) {
+	rendered, err := e.RenderBudgetedComment(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	return rendered.Body, nil
+}
+
+// RenderBudgetedComment renders a PR comment using the comprehensive template and fits it to
+// the size budget, keeping the untruncated version so it can be published elsewhere.
+// Shortened sections link to Resources.FullReportURL, falling back to the report URL.
+func (e *PRTemplateEngine) RenderBudgetedComment(_ context.Context, data *TemplateData) (*RenderedComment, error) {
+	full, err := e.render("comprehensive", data)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := e.config.MaxCommentLength
+	if limit <= 0 {
+		limit = MaxCommentLength
+	}
+	fullURL := data.Resources.FullReportURL
+	if fullURL == "" {
+		fullURL = data.Resources.ReportURL
+	}
+	if fullURL == "" {
+		fullURL = data.Resources.DashboardURL
+	}
 
+	body, shortened := FitComment(full, limit, fullURL)
+	return &RenderedComment{Body: body, Full: full, Shortened: shortened}, nil
+}
+
+// RenderNoCodeChangesComment renders the short comment posted when a PR changes no code.
+// Only the PR file analysis, resources and metadata of data are used.
+func (e *PRTemplateEngine) RenderNoCodeChangesComment(_ context.Context, data *TemplateData) (string, error) {
+	return e.render("no-code-changes", data)
+}
+
+// render executes the named template with data
+func (e *PRTemplateEngine) render(templateName string, data *TemplateData) (string, error) {
 	// Add configuration to template data
 	data.Config = *e.config
 
@@ -310,14 +424,16 @@ func (e *PRTemplateEngine) RenderComment(_ context.Context, _ string, data *Temp
 			Version:      "2.0",
 			GeneratedAt:  time.Now(),
 			TemplateUsed: templateName,
-			Signature:    "go-coverage-v1",
+			Signature:    e.config.Signature,
+		}
+		if data.Metadata.Signature == "" {
+			data.Metadata.Signature = DefaultSignature
 		}
 	} else {
 		// Update template used
 		data.Metadata.TemplateUsed = templateName
 	}
 
-	// Get the comprehensive template (only one available)
 	tmpl, exists := e.templates[templateName]
 	if !exists {
 		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, templateName)
@@ -344,8 +460,11 @@ func (e *PRTemplateEngine) formatCommitSHA(sha string) string {
 func (e *PRTemplateEngine) initializeTemplates() {
 	funcMap := e.createTemplateFuncMap()
 
-	// Comprehensive template (only template)
+	// Comprehensive template (used for every coverage report)
 	e.templates["comprehensive"] = template.Must(template.New("comprehensive").Funcs(funcMap).Parse(comprehensiveTemplate))
+
+	// Short comment for PRs that change no code
+	e.templates["no-code-changes"] = template.Must(template.New("no-code-changes").Funcs(funcMap).Parse(noCodeChangesTemplate))
 }
 
 // createTemplateFuncMap creates the function map for templates
@@ -375,6 +494,7 @@ func (e *PRTemplateEngine) createTemplateFuncMap() template.FuncMap {
 		"filterFiles":           e.filterFiles,
 		"filterPackages":        e.filterPackages,
 		"filterRecommendations": e.filterRecommendations,
+		"filterInsights":        e.filterInsights,
 		"sortFilesByRisk":       e.sortFilesByRisk,
 		"sortByChange":          e.sortByChange,
 
@@ -693,6 +813,24 @@ func (e *PRTemplateEngine) filterRecommendations(recommendations []Recommendatio
 	return recommendations
 }
 
+// filterInsights returns the most important insights, at most MaxInsights of them
+func (e *PRTemplateEngine) filterInsights(insights []InsightData) []InsightData {
+	if e.config.MaxInsights <= 0 {
+		return nil
+	}
+
+	sorted := slices.Clone(insights)
+	slices.SortStableFunc(sorted, func(a, b InsightData) int {
+		priorities := map[string]int{priorityHigh: 3, priorityMedium: 2, priorityLow: 1}
+		return cmp.Compare(priorities[b.Priority], priorities[a.Priority])
+	})
+	if len(sorted) > e.config.MaxInsights {
+		sorted = sorted[:e.config.MaxInsights]
+	}
+
+	return sorted
+}
+
 func (e *PRTemplateEngine) sortFilesByRisk(files []FileCoverageData) []FileCoverageData {
 	sorted := make([]FileCoverageData, len(files))
 	copy(sorted, files)
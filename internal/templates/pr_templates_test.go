@@ -2,6 +2,7 @@ package templates
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -182,3 +183,210 @@ func TestProgressBar(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderCommentPolicy(t *testing.T) {
+	engine := NewPRTemplateEngine(nil)
+	ctx := context.Background()
+
+	data := &TemplateData{
+		Repository: RepositoryInfo{Owner: "testowner", Name: "testrepo"},
+		Coverage: CoverageData{
+			Overall: CoverageMetrics{Percentage: 82.0, TotalStatements: 100, CoveredStatements: 82},
+		},
+		Config: TemplateConfig{IncludeEmojis: true},
+	}
+
+	t.Run("omitted without policy", func(t *testing.T) {
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.NotContains(t, result, "Coverage Policy")
+	})
+
+	t.Run("explains each rule", func(t *testing.T) {
+		data.Policy = &PolicyData{
+			Passed: false,
+			Results: []PolicyResultData{
+				{Rule: "threshold", Outcome: "pass", Icon: "✅", Message: "coverage 82.00% meets threshold 80.00%"},
+				{Rule: "sustained-decline", Outcome: "fail", Icon: "❌", Message: "coverage declined for 3 consecutive runs"},
+				{
+					Rule: "gate", Outcome: "pass", Icon: "✅", Message: "total >= 80 holds",
+					Trace: []string{"✅ total >= 80 (82 >= 80)"},
+				},
+			},
+		}
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "## Coverage Policy")
+		assert.Contains(t, result, "`threshold` | ✅ Pass | coverage 82.00% meets threshold 80.00%")
+		assert.Contains(t, result, "`sustained-decline` | ❌ Fail | coverage declined for 3 consecutive runs")
+		// html/template escapes comparison operators; GitHub renders the entities
+		assert.Contains(t, result, "`gate` | ✅ Pass | total &gt;= 80 holds<br>✅ total &gt;= 80 (82 &gt;= 80) |")
+		assert.NotContains(t, result, "confidence band")
+	})
+
+	t.Run("shows the confidence band", func(t *testing.T) {
+		data.Policy = &PolicyData{
+			Passed:     true,
+			Confidence: &ConfidenceData{Margin: 0.63, Lower: 81.37, Upper: 82.63, Level: 95, Runs: 10},
+		}
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "**Overall Coverage: 82.0% ± 0.6**")
+		assert.Contains(t, result, "| **Percentage** | 82.0% ± 0.6 |")
+		assert.Contains(t, result, "📏 95% confidence band: 81.4% – 82.6% (run-to-run noise of the last 10 runs)")
+	})
+
+	t.Run("shows an emergency bypass", func(t *testing.T) {
+		data.Policy = &PolicyData{
+			Passed:  true,
+			Bypass:  "bypass token [hotfix] in the commit message",
+			Results: []PolicyResultData{{Rule: "threshold", Outcome: "warn", Icon: "⚠️", Message: "coverage 72.00% is below the 80.00% threshold (bypassed)"}},
+		}
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "🚨 **Coverage policy bypassed:** bypass token [hotfix] in the commit message")
+		assert.NotContains(t, result, "All coverage policies passed")
+		assert.Contains(t, result, "`threshold` | ⚠️ Warn | coverage 72.00% is below the 80.00% threshold (bypassed)")
+		assert.NotContains(t, result, "Critical paths below")
+	})
+
+	t.Run("shows the warm-up", func(t *testing.T) {
+		data.Policy = &PolicyData{
+			Passed:  true,
+			Warmup:  "run 3 of 10",
+			Results: []PolicyResultData{{Rule: "threshold", Outcome: "warn", Icon: "⚠️", Message: "coverage 72.00% is below the 80.00% threshold (warm-up)"}},
+		}
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "🌱 **Coverage policy warming up:** run 3 of 10")
+		assert.NotContains(t, result, "All coverage policies passed")
+
+		data.Policy = &PolicyData{Passed: true, WarmupEnded: true}
+		result, err = engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "🎓 **Warm-up complete:** coverage gates are now enforced")
+	})
+
+	t.Run("calls out critical paths", func(t *testing.T) {
+		critical := PolicyResultData{
+			Rule: "critical", Outcome: "fail", Icon: "❌",
+			Message: "internal/auth/**: coverage 90.00% is below the required 100.00% (2 of 20 statements missed)",
+			Trace:   []string{"internal/auth/token.go: 80.00% (2 of 10 statements missed)"},
+		}
+		data.Policy = &PolicyData{Results: []PolicyResultData{critical}, CriticalFailures: []PolicyResultData{critical}}
+		result, err := engine.RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "> [!CAUTION]\n> **Critical paths below their required coverage**\n>\n"+
+			"> - internal/auth/**: coverage 90.00% is below the required 100.00% (2 of 20 statements missed)"+
+			"<br>internal/auth/token.go: 80.00% (2 of 10 statements missed)\n")
+		assert.Less(t, strings.Index(result, "[!CAUTION]"), strings.Index(result, "## Coverage Metrics"))
+	})
+}
+
+func TestRenderCommentForecast(t *testing.T) {
+	engine := NewPRTemplateEngine(nil)
+	ctx := context.Background()
+
+	data := &TemplateData{
+		Coverage: CoverageData{Overall: CoverageMetrics{Percentage: 82.0, TotalStatements: 100, CoveredStatements: 82}},
+		Config:   TemplateConfig{IncludeEmojis: true},
+	}
+	result, err := engine.RenderComment(ctx, "comprehensive", data)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Merge forecast")
+
+	data.Forecast = &ForecastData{Branch: "main", Current: 81.2, Projected: 81.6}
+	result, err = engine.RenderComment(ctx, "comprehensive", data)
+	require.NoError(t, err)
+	assert.Contains(t, result, "🔮 **Merge forecast:** merging will move `main` from 81.2% → 81.6%\n")
+
+	data.Forecast = &ForecastData{
+		Branch: "main", Current: 81.2, Projected: 79.4,
+		Violations: []string{"threshold of 80.00%", "max drop of 1.00 points (1.80 projected)"},
+	}
+	result, err = engine.RenderComment(ctx, "comprehensive", data)
+	require.NoError(t, err)
+	assert.Contains(t, result, "from 81.2% → 79.4%, which would violate its threshold of 80.00% and max drop of 1.00 points (1.80 projected) ⚠️")
+}
+
+func TestRenderCommentInsights(t *testing.T) {
+	ctx := context.Background()
+	data := &TemplateData{
+		Repository: RepositoryInfo{Owner: "testowner", Name: "testrepo"},
+		Coverage: CoverageData{
+			Overall: CoverageMetrics{Percentage: 82.0, TotalStatements: 100, CoveredStatements: 82},
+		},
+		Insights: []InsightData{
+			{Priority: "low", Title: "Add benchmarks"},
+			{Priority: "medium", Title: "2 files lost coverage", Detail: "`lib.go` (-12.0%)", URL: "https://example.com/report"},
+			{Priority: "high", Title: "3 new files under 50% coverage", URL: "https://example.com/report"},
+			{Priority: "medium", Title: "Address High-Risk Files"},
+		},
+	}
+
+	t.Run("shows the most important insights", func(t *testing.T) {
+		result, err := NewPRTemplateEngine(nil).RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.Contains(t, result, "## Insights")
+		assert.Contains(t, result, "- 🔥 [3 new files under 50% coverage](https://example.com/report)\n- 📌 [2 files lost coverage](https://example.com/report): `lib.go` (-12.0%)\n- 📌 **Address High-Risk Files**")
+		assert.NotContains(t, result, "Add benchmarks")
+	})
+
+	t.Run("hidden when disabled", func(t *testing.T) {
+		result, err := NewPRTemplateEngine(&TemplateConfig{IncludeEmojis: true}).RenderComment(ctx, "comprehensive", data)
+		require.NoError(t, err)
+		assert.NotContains(t, result, "## Insights")
+	})
+}
+
+func TestRenderCommentPackageDiff(t *testing.T) {
+	ctx := context.Background()
+	data := &TemplateData{
+		Repository: RepositoryInfo{Owner: "testowner", Name: "testrepo"},
+		Coverage: CoverageData{
+			Overall: CoverageMetrics{Percentage: 82.0, TotalStatements: 100, CoveredStatements: 82},
+		},
+	}
+
+	result, err := NewPRTemplateEngine(nil).RenderComment(ctx, "comprehensive", data)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Package coverage before and after")
+
+	data.Resources.PackageDiffURL = "https://owner.github.io/repo/coverage/pr/7/coverage-diff.svg"
+	result, err = NewPRTemplateEngine(nil).RenderComment(ctx, "comprehensive", data)
+	require.NoError(t, err)
+	assert.Contains(t, result, "![Package coverage before and after this pull request](https://owner.github.io/repo/coverage/pr/7/coverage-diff.svg)")
+}
+
+func TestRenderCommentAttribution(t *testing.T) {
+	ctx := context.Background()
+	data := func() *TemplateData {
+		return &TemplateData{Coverage: CoverageData{Overall: CoverageMetrics{Percentage: 82.0, TotalStatements: 100, CoveredStatements: 82}}}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		result, err := NewPRTemplateEngine(&TemplateConfig{BrandingEnabled: true}).RenderComment(ctx, "comprehensive", data())
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result, "[//]: # ("+DefaultSignature+")"))
+		assert.Contains(t, result, "# Code Coverage Analysis")
+		assert.Contains(t, result, "Generated via [go-coverage]")
+	})
+
+	t.Run("custom signature, header and footer", func(t *testing.T) {
+		engine := NewPRTemplateEngine(&TemplateConfig{
+			Signature:    "acme-coverage-bot",
+			CustomHeader: "🤖 Acme Coverage Bot",
+			CustomFooter: "*Posted by the Acme platform team*",
+		})
+		result, err := engine.RenderComment(ctx, "comprehensive", data())
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result, "[//]: # (acme-coverage-bot)"))
+		assert.Contains(t, result, "# 🤖 Acme Coverage Bot")
+		assert.Contains(t, result, "*Posted by the Acme platform team*")
+		assert.NotContains(t, result, "go-coverage")
+
+		result, err = engine.RenderNoCodeChangesComment(ctx, data())
+		require.NoError(t, err)
+		assert.Contains(t, result, "# 🤖 Acme Coverage Bot")
+	})
+}
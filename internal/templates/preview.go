@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"context"
+	"time"
+)
+
+// previewTemplateName is the name custom templates are registered under for previews
+const previewTemplateName = "preview"
+
+// PreviewComment renders a custom comment template with data, or the built-in comment, fitted to
+// the size budget, when content is empty. It lets template authors iterate without a pull request.
+func (e *PRTemplateEngine) PreviewComment(ctx context.Context, content string, data *TemplateData) (string, error) {
+	if content == "" {
+		rendered, err := e.RenderBudgetedComment(ctx, data)
+		if err != nil {
+			return "", err
+		}
+		return rendered.Body, nil
+	}
+
+	if err := e.AddCustomTemplate(previewTemplateName, content); err != nil {
+		return "", err
+	}
+	return e.render(previewTemplateName, data)
+}
+
+// SampleTemplateData returns the data of a typical pull request comment: coverage improving
+// against the base branch with a new file, a degraded file, insights, recommendations and a
+// passed policy. Template previews use it when no captured data is given.
+func SampleTemplateData() *TemplateData {
+	return &TemplateData{
+		Repository: RepositoryInfo{
+			Owner:         "example",
+			Name:          "project",
+			DefaultBranch: "main",
+			URL:           "https://github.com/example/project",
+		},
+		PullRequest: PullRequestInfo{
+			Number:     42,
+			Title:      "Add retry support to the client",
+			Branch:     "feature/retries",
+			BaseBranch: "main",
+			Author:     "octocat",
+			CommitSHA:  "4f2c9d1e8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d",
+			URL:        "https://github.com/example/project/pull/42",
+		},
+		Timestamp: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Coverage: CoverageData{
+			Overall: CoverageMetrics{
+				Percentage:        84.6,
+				TotalStatements:   1300,
+				CoveredStatements: 1100,
+				Grade:             "B+",
+				Status:            "good",
+			},
+			Files: []FileCoverageData{
+				{Filename: "internal/client/retry.go", Percentage: 91.3, IsNew: true, Status: "excellent", LinesAdded: 120, Risk: "low"},
+				{Filename: "internal/client/client.go", Percentage: 72.5, Change: -4.2, IsModified: true, Status: "warning", LinesAdded: 18, LinesRemoved: 6, Risk: "medium"},
+			},
+			Packages: []PackageCoverageData{
+				{Package: "internal/client", Percentage: 82.1, Change: 2.4, FileCount: 4, Status: "good"},
+				{Package: "internal/config", Percentage: 93.0, FileCount: 2, Status: "excellent"},
+			},
+			Summary: CoverageSummary{
+				Direction:       "improved",
+				Magnitude:       "minor",
+				KeyAchievements: []string{"New retry logic is well covered"},
+				KeyConcerns:     []string{"client.go lost coverage"},
+				OverallImpact:   "positive",
+			},
+		},
+		Comparison: ComparisonData{
+			BasePercentage:    83.9,
+			CurrentPercentage: 84.6,
+			Change:            0.7,
+			Direction:         "improved",
+			Magnitude:         "minor",
+			IsSignificant:     true,
+		},
+		Trends: TrendData{Direction: "upward", Momentum: "steady", Volatility: 0.8, Prediction: 85.2, Confidence: 0.7},
+		Quality: QualityData{
+			OverallGrade:  "B+",
+			CoverageGrade: "B+",
+			TrendGrade:    "A",
+			RiskLevel:     "low",
+			Score:         84,
+			Strengths:     []string{"Coverage is above the threshold"},
+			Weaknesses:    []string{"Error paths of client.go are untested"},
+		},
+		Recommendations: []RecommendationData{{
+			Type:        "coverage",
+			Priority:    "medium",
+			Title:       "Cover the error paths of client.go",
+			Description: "Coverage of client.go dropped by 4.2%",
+			Actions:     []string{"Test request timeouts", "Test non-retryable status codes"},
+			Impact:      "medium",
+		}},
+		Insights: []InsightData{{
+			Priority: "medium",
+			Title:    "1 file lost coverage",
+			Detail:   "`internal/client/client.go` (-4.2%)",
+			URL:      "https://github.com/example/project/pull/42/files",
+		}},
+		Policy: &PolicyData{
+			Passed: true,
+			Results: []PolicyResultData{
+				{Rule: "threshold", Outcome: "pass", Icon: "✅", Message: "Coverage 84.6% meets the 80.0% threshold"},
+			},
+		},
+		Resources: ResourceLinks{
+			BadgeURL:  "https://example.github.io/project/coverage/pr/42/coverage.svg",
+			ReportURL: "https://example.github.io/project/coverage/pr/42/",
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewComment(t *testing.T) {
+	ctx := context.Background()
+	engine := NewPRTemplateEngine(nil)
+
+	builtIn, err := engine.PreviewComment(ctx, "", SampleTemplateData())
+	require.NoError(t, err)
+	assert.Contains(t, builtIn, "## Coverage Metrics")
+	assert.Contains(t, builtIn, "## Insights")
+
+	custom, err := engine.PreviewComment(ctx, "{{ trendEmoji .Trends.Direction }} {{ .Repository.Owner }}/{{ .Repository.Name }}", SampleTemplateData())
+	require.NoError(t, err)
+	assert.Equal(t, "📈 example/project", custom)
+
+	_, err = engine.PreviewComment(ctx, "{{ .Coverage.Missing }}", SampleTemplateData())
+	require.Error(t, err)
+	_, err = engine.PreviewComment(ctx, "{{ if }}", SampleTemplateData())
+	require.Error(t, err)
+}
@@ -7,11 +7,11 @@ import (
 
 // Comprehensive template - detailed coverage report with all features
 const comprehensiveTemplate = `[//]: # ({{ .Metadata.Signature }})
-[//]: # (metadata: {"version":"{{ .Metadata.Version }}","generated_at":"{{ .Metadata.GeneratedAt.Format "2006-01-02T15:04:05Z07:00" }}","template":"{{ .Metadata.TemplateUsed }}"})
+[//]: # (metadata: {"version":"{{ .Metadata.Version }}","generated_at":"{{ .Metadata.GeneratedAt.Format "2006-01-02T15:04:05Z07:00" }}","template":"{{ .Metadata.TemplateUsed }}"{{ if .Policy }},"status":"{{ if .Policy.Passed }}passed{{ else }}failed{{ end }}"{{ end }}})
 
-# Code Coverage Analysis
+# {{ or .Config.CustomHeader "Code Coverage Analysis" }}
 
-{{ statusEmoji .Coverage.Overall.Status }} **Overall Coverage: {{ formatPercent .Coverage.Overall.Percentage }}**
+{{ statusEmoji .Coverage.Overall.Status }} **Overall Coverage: {{ formatPercent .Coverage.Overall.Percentage }}{{ with .Policy }}{{ with .Confidence }} ± {{ printf "%.1f" .Margin }}{{ end }}{{ end }}**
 
 {{- if .PRFiles -}}
     {{- if not .PRFiles.Summary.HasGoChanges -}}
@@ -52,15 +52,49 @@ Changes: {{ .PRFiles.Summary.SummaryText }}
 {{- end -}}
 
 <br>
-
+{{ with .Forecast }}
+🔮 **Merge forecast:** merging will move ` + "`" + `{{ .Branch }}` + "`" + ` from {{ formatPercent .Current }} → {{ formatPercent .Projected }}{{ if .Violations }}, which would violate its {{ range $i, $v := .Violations }}{{ if $i }} and {{ end }}{{ $v }}{{ end }} ⚠️{{ end }}
+{{ end }}{{ with .Policy }}{{ with .CriticalFailures }}
+> [!CAUTION]
+> **Critical paths below their required coverage**
+>
+{{ range . }}> - {{ .Message }}{{ range .Trace }}<br>{{ . }}{{ end }}
+{{ end }}{{ end }}{{ end }}
 ## Coverage Metrics
 
 | Metric | Value | Grade | Trend |
 |--------|-------|-------|--------|
-| **Percentage** | {{ formatPercent .Coverage.Overall.Percentage }} | {{ formatGrade .Quality.CoverageGrade }} | {{ trendEmoji .Trends.Direction }} {{ .Trends.Direction }} |
+| **Percentage** | {{ formatPercent .Coverage.Overall.Percentage }}{{ with .Policy }}{{ with .Confidence }} ± {{ printf "%.1f" .Margin }}{{ end }}{{ end }} | {{ formatGrade .Quality.CoverageGrade }} | {{ trendEmoji .Trends.Direction }} {{ .Trends.Direction }} |
 | **Statements** | {{ formatNumber .Coverage.Overall.CoveredStatements }}/{{ formatNumber .Coverage.Overall.TotalStatements }} | {{ formatGrade .Quality.OverallGrade }} | {{ if .PRFiles }}{{ if not .PRFiles.Summary.HasGoChanges }}No change{{ else }}{{ if ne .Comparison.BasePercentage 0.0 }}{{ formatChange .Comparison.Change }}{{ else }}First report{{ end }}{{ end }}{{ else }}{{ if ne .Comparison.BasePercentage 0.0 }}{{ formatChange .Comparison.Change }}{{ else }}First report{{ end }}{{ end }} |
 | **Quality Score** | {{ round .Quality.Score }}/100 | {{ formatGrade .Quality.OverallGrade }} | {{ if gt .Quality.Score 80.0 }}📈{{ else if lt .Quality.Score 60.0 }}📉{{ else }}📊{{ end }} |
 
+{{ if .Policy }}
+## Coverage Policy
+
+{{ if .Policy.Bypass }}🚨 **Coverage policy bypassed:** {{ .Policy.Bypass }} (failed rules are reported as warnings and recorded in the coverage history){{ else if .Policy.Warmup }}🌱 **Coverage policy warming up:** {{ .Policy.Warmup }} (failed rules are reported as warnings while the coverage history accumulates){{ else if .Policy.Passed }}✅ **All coverage policies passed**{{ else }}❌ **Coverage policy failed**{{ end }}
+{{ if .Policy.WarmupEnded }}
+🎓 **Warm-up complete:** coverage gates are now enforced
+{{ end }}{{ with .Policy.Confidence }}
+📏 {{ printf "%.0f" .Level }}% confidence band: {{ formatPercent .Lower }} – {{ formatPercent .Upper }} (run-to-run noise of the last {{ .Runs }} runs)
+{{ end }}
+| Rule | Result | Details |
+|------|--------|---------|
+{{ range .Policy.Results }}| ` + "`" + `{{ .Rule }}` + "`" + ` | {{ .Icon }} {{ humanize .Outcome }} | {{ .Message }}{{ range .Trace }}<br>{{ . }}{{ end }} |
+{{ end }}
+{{ end }}
+
+{{ $insights := filterInsights .Insights }}
+{{ if $insights }}
+## Insights
+{{ range $insights }}
+- {{ priorityEmoji .Priority }} {{ if .URL }}[{{ .Title }}]({{ .URL }}){{ else }}**{{ .Title }}**{{ end }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{- end }}
+{{ end }}
+
+{{ with .Resources.PackageDiffURL }}
+![Package coverage before and after this pull request]({{ . }})
+{{ end }}
+
 {{ if .Config.IncludeProgressBars }}
 ### Coverage Breakdown
 
@@ -174,6 +208,37 @@ Changes: {{ .PRFiles.Summary.SummaryText }}
 *Coverage report generated at {{ .Metadata.GeneratedAt.Format "2006-01-02 15:04:05 UTC" }}*
 {{ end }}`
 
+// No code changes template - posted instead of a coverage report when a PR changes no code
+const noCodeChangesTemplate = `[//]: # ({{ .Metadata.Signature }})
+[//]: # (metadata: {"version":"{{ .Metadata.Version }}","generated_at":"{{ .Metadata.GeneratedAt.Format "2006-01-02T15:04:05Z07:00" }}","template":"{{ .Metadata.TemplateUsed }}"})
+
+# {{ or .Config.CustomHeader "Code Coverage Analysis" }}
+
+✅ **No code changes — coverage unaffected**
+
+{{- if .PRFiles }}
+
+This PR changes {{ .PRFiles.Summary.SummaryText }} and no Go code, tests or module files, so coverage was not measured.
+{{- end }}
+{{- if .Resources.BadgeURL }}
+
+Current coverage: ![coverage]({{ .Resources.BadgeURL }})
+{{- end }}
+{{- if or .Resources.ReportURL .Resources.DashboardURL }}
+
+📊 [Coverage Report]({{ if .Resources.ReportURL }}{{ .Resources.ReportURL }}{{ else }}{{ .Resources.DashboardURL }}{{ end }})
+{{- end }}
+
+---
+
+{{ if .Config.CustomFooter }}
+{{ .Config.CustomFooter }}
+{{ else if .Config.BrandingEnabled }}
+*Generated via [go-coverage](https://github.com/mrz1836/go-coverage)* • *{{ .Metadata.GeneratedAt.Format "2006-01-02 15:04:05 UTC" }}*
+{{ else }}
+*Coverage report generated at {{ .Metadata.GeneratedAt.Format "2006-01-02 15:04:05 UTC" }}*
+{{ end }}`
+
 // GetSharedFooter returns the standardized footer HTML with configurable CSS class and timestamp field
 // cssClass: pass " dashboard" for dashboard styling, or "" for regular styling
 // timestampField: pass "Timestamp" or "GeneratedAt" for the appropriate timestamp field
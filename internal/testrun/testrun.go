@@ -0,0 +1,223 @@
+// Package testrun reads test results, from go test -json output or JUnit XML reports, so the
+// time the test suite takes can be tracked alongside the coverage it produces
+package testrun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoResults indicates a file holding no test results
+var ErrNoResults = errors.New("no test results found")
+
+// Formats of test result files
+const (
+	FormatGoTestJSON = "go-test-json"
+	FormatJUnit      = "junit"
+)
+
+// minTimeGrowth ignores test time growth below 10%, so ordinary jitter in run times is not flagged
+const minTimeGrowth = 0.1
+
+// Summary is the outcome of a test run
+type Summary struct {
+	Format   string  `json:"format"`
+	Tests    int     `json:"tests"`
+	Failed   int     `json:"failed,omitempty"`
+	Skipped  int     `json:"skipped,omitempty"`
+	Packages int     `json:"packages,omitempty"`
+	Seconds  float64 `json:"seconds"` // Total test time: the sum of the run times of the packages or suites
+}
+
+// Duration returns the total test time
+func (s *Summary) Duration() time.Duration {
+	return time.Duration(s.Seconds * float64(time.Second))
+}
+
+// ParseFile reads a go test -json output or JUnit XML report
+func ParseFile(path string) (*Summary, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test results: %w", err)
+	}
+	return Parse(bytes.NewReader(data))
+}
+
+// Parse reads test results, telling JUnit XML from go test -json output by the first character
+func Parse(r io.Reader) (*Summary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test results: %w", err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+		return parseJUnit(trimmed)
+	}
+	return parseGoTestJSON(data)
+}
+
+// testEvent is a line of go test -json output (see go doc test2json)
+type testEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+// parseGoTestJSON sums the run times of the packages and counts the top-level tests. Lines that
+// are not events, such as build output, are ignored.
+func parseGoTestJSON(data []byte) (*Summary, error) {
+	summary := &Summary{Format: FormatGoTestJSON}
+	var testSeconds float64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Action == "" {
+			continue
+		}
+		switch {
+		case event.Test == "" && (event.Action == "pass" || event.Action == "fail"):
+			summary.Packages++
+			summary.Seconds += event.Elapsed
+		case event.Test == "" || strings.Contains(event.Test, "/"):
+			// Packages without tests and subtests, which their parent test already counts
+		case event.Action == "pass":
+			summary.Tests++
+			testSeconds += event.Elapsed
+		case event.Action == "fail":
+			summary.Tests++
+			summary.Failed++
+			testSeconds += event.Elapsed
+		case event.Action == "skip":
+			summary.Tests++
+			summary.Skipped++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read test results: %w", err)
+	}
+	if summary.Packages == 0 && summary.Tests == 0 {
+		return nil, ErrNoResults
+	}
+	// Interrupted output may lack the package events; the tests' own times are the next best
+	if summary.Packages == 0 {
+		summary.Seconds = testSeconds
+	}
+	return summary, nil
+}
+
+// junitSuite is a testsuite or testsuites element of a JUnit report
+type junitSuite struct {
+	XMLName  xml.Name
+	Tests    string       `xml:"tests,attr"`
+	Failures string       `xml:"failures,attr"`
+	Errors   string       `xml:"errors,attr"`
+	Skipped  string       `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// parseJUnit reads a testsuites or testsuite report. The totals of a suite holding other
+// suites are the sums of its children, since many tools leave the attributes of the root out.
+func parseJUnit(data []byte) (*Summary, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+	}
+	if root.XMLName.Local != "testsuites" && root.XMLName.Local != "testsuite" {
+		return nil, fmt.Errorf("%w: unexpected <%s> element", ErrNoResults, root.XMLName.Local)
+	}
+
+	summary := &Summary{Format: FormatJUnit}
+	root.addTo(summary)
+	if summary.Tests == 0 && summary.Packages == 0 {
+		return nil, ErrNoResults
+	}
+	return summary, nil
+}
+
+// addTo adds the totals of the suite to summary, counting every leaf suite as a package
+func (s *junitSuite) addTo(summary *Summary) {
+	if len(s.Suites) > 0 {
+		for i := range s.Suites {
+			s.Suites[i].addTo(summary)
+		}
+		return
+	}
+	if s.XMLName.Local != "testsuite" {
+		return
+	}
+	summary.Packages++
+	summary.Tests += attrInt(s.Tests)
+	summary.Failed += attrInt(s.Failures) + attrInt(s.Errors)
+	summary.Skipped += attrInt(s.Skipped)
+	summary.Seconds += attrFloat(s.Time)
+}
+
+// attrInt parses a count attribute, treating a missing or malformed value as 0
+func attrInt(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// attrFloat parses a time attribute in seconds, accepting thousands separators as some tools write them
+func attrFloat(value string) float64 {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return f
+}
+
+// Efficiency returns the covered statements per second of test time, 0 without test time
+func Efficiency(covered int, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(covered) / seconds
+}
+
+// Sample is the test time and coverage of one run
+type Sample struct {
+	Seconds float64
+	Covered int
+}
+
+// Growth compares the test time and coverage of two runs
+type Growth struct {
+	Time     float64 // Relative growth of test time, e.g. 0.5 for 50% slower
+	Coverage float64 // Relative growth of covered statements
+}
+
+// CompareGrowth returns the growth from the first run to the last, or false when the first run
+// has no test time or coverage to compare with
+func CompareGrowth(first, last Sample) (Growth, bool) {
+	if first.Seconds <= 0 || first.Covered <= 0 {
+		return Growth{}, false
+	}
+	return Growth{
+		Time:     last.Seconds/first.Seconds - 1,
+		Coverage: float64(last.Covered)/float64(first.Covered) - 1,
+	}, true
+}
+
+// Outpaces reports whether test time grew more than ratio times as fast as coverage. Time growth
+// under 10% is never flagged, nor is anything with a ratio of 0.
+func (g Growth) Outpaces(ratio float64) bool {
+	if ratio <= 0 || g.Time < minTimeGrowth {
+		return false
+	}
+	return g.Time > ratio*max(g.Coverage, 0)
+}
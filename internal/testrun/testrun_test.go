@@ -0,0 +1,107 @@
+package testrun
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goTestJSON = `# github.com/example/app/internal/db
+{"Action":"start","Package":"github.com/example/app/internal/api"}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestServe"}
+{"Action":"run","Package":"github.com/example/app/internal/api","Test":"TestServe/get"}
+{"Action":"pass","Package":"github.com/example/app/internal/api","Test":"TestServe/get","Elapsed":0.4}
+{"Action":"pass","Package":"github.com/example/app/internal/api","Test":"TestServe","Elapsed":0.5}
+{"Action":"fail","Package":"github.com/example/app/internal/api","Test":"TestRetry","Elapsed":1.2}
+{"Action":"skip","Package":"github.com/example/app/internal/api","Test":"TestSlow","Elapsed":0}
+{"Action":"fail","Package":"github.com/example/app/internal/api","Elapsed":2.25}
+{"Action":"pass","Package":"github.com/example/app/internal/db","Test":"TestQuery","Elapsed":3}
+{"Action":"pass","Package":"github.com/example/app/internal/db","Elapsed":3.5}
+{"Action":"skip","Package":"github.com/example/app/cmd","Elapsed":0}
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	summary, err := Parse(strings.NewReader(goTestJSON))
+	require.NoError(t, err)
+	assert.Equal(t, &Summary{Format: FormatGoTestJSON, Tests: 4, Failed: 1, Skipped: 1, Packages: 2, Seconds: 5.75}, summary)
+	assert.Equal(t, 5750*time.Millisecond, summary.Duration())
+
+	t.Run("interrupted output falls back to test times", func(t *testing.T) {
+		summary, err := Parse(strings.NewReader(`{"Action":"pass","Package":"p","Test":"TestA","Elapsed":1.5}`))
+		require.NoError(t, err)
+		assert.InDelta(t, 1.5, summary.Seconds, 0)
+		assert.Equal(t, 1, summary.Tests)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("ok  \tgithub.com/example/app\t0.01s\n"))
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+}
+
+func TestParseJUnit(t *testing.T) {
+	t.Run("testsuites", func(t *testing.T) {
+		summary, err := Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+	<testsuite name="github.com/example/app/internal/api" tests="3" failures="1" errors="1" skipped="1" time="1,250.5">
+		<testcase name="TestServe" time="0.5"></testcase>
+	</testsuite>
+	<testsuite name="github.com/example/app/internal/db" tests="2" time="3.5"></testsuite>
+</testsuites>`))
+		require.NoError(t, err)
+		assert.Equal(t, &Summary{Format: FormatJUnit, Tests: 5, Failed: 2, Skipped: 1, Packages: 2, Seconds: 1254}, summary)
+	})
+
+	t.Run("single testsuite", func(t *testing.T) {
+		summary, err := Parse(strings.NewReader(`<testsuite tests="4" time="2"></testsuite>`))
+		require.NoError(t, err)
+		assert.Equal(t, 4, summary.Tests)
+		assert.InDelta(t, 2, summary.Seconds, 0)
+	})
+
+	t.Run("other documents", func(t *testing.T) {
+		_, err := Parse(strings.NewReader(`<coverage line-rate="0.8"></coverage>`))
+		require.ErrorIs(t, err, ErrNoResults)
+
+		_, err = Parse(strings.NewReader(`<testsuites><testsuite`))
+		require.Error(t, err)
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	require.NoError(t, os.WriteFile(path, []byte(goTestJSON), 0o600))
+
+	summary, err := ParseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Packages)
+
+	_, err = ParseFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestEfficiency(t *testing.T) {
+	assert.InDelta(t, 40, Efficiency(800, 20), 0)
+	assert.InDelta(t, 0, Efficiency(800, 0), 0)
+}
+
+func TestCompareGrowth(t *testing.T) {
+	_, ok := CompareGrowth(Sample{}, Sample{Seconds: 10, Covered: 100})
+	assert.False(t, ok)
+
+	growth, ok := CompareGrowth(Sample{Seconds: 20, Covered: 1000}, Sample{Seconds: 30, Covered: 1100})
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, growth.Time, 1e-9)
+	assert.InDelta(t, 0.1, growth.Coverage, 1e-9)
+	assert.True(t, growth.Outpaces(2), "time grew 5x as fast as coverage")
+	assert.False(t, growth.Outpaces(6))
+	assert.False(t, growth.Outpaces(0))
+
+	assert.False(t, Growth{Time: 0.08, Coverage: 0}.Outpaces(2), "small time growth is jitter")
+	assert.True(t, Growth{Time: 0.2, Coverage: -0.05}.Outpaces(2), "slower tests covering less")
+}
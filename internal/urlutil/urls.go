@@ -124,6 +124,12 @@ func CleanModulePath(fullPath string) string {
 
 // BuildGitHubFileURL builds a GitHub file URL with automatic path cleaning
 func BuildGitHubFileURL(owner, repo, branch, filePath string) string {
+	return BuildGitHubModuleFileURL(owner, repo, branch, "", filePath)
+}
+
+// BuildGitHubModuleFileURL builds a GitHub file URL for a file of a Go module in a
+// sub-directory of the repository, such as "services/api", from its module-relative path
+func BuildGitHubModuleFileURL(owner, repo, branch, moduleRoot, filePath string) string {
 	if owner == "" || repo == "" || branch == "" || filePath == "" {
 		return ""
 	}
@@ -131,10 +137,22 @@ func BuildGitHubFileURL(owner, repo, branch, filePath string) string {
 	owner = sanitizeUTF8(owner)
 	repo = sanitizeUTF8(repo)
 	branch = sanitizeUTF8(branch)
-	cleanPath := CleanModulePathWithRepo(filePath, repo)
+	cleanPath := RepoPath(moduleRoot, CleanModulePathWithRepo(filePath, repo))
 	return fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", owner, repo, branch, cleanPath)
 }
 
+// RepoPath returns the repository path of a module-relative path, for a module in the
+// moduleRoot sub-directory of the repository (empty for the repository root). Paths that
+// already start with the module root, as those of modules whose import path ends in the
+// sub-directory, are returned unchanged.
+func RepoPath(moduleRoot, modulePath string) string {
+	moduleRoot = strings.Trim(moduleRoot, "/")
+	if moduleRoot == "" || moduleRoot == "." || modulePath == moduleRoot || strings.HasPrefix(modulePath, moduleRoot+"/") {
+		return modulePath
+	}
+	return sanitizeUTF8(path.Join(moduleRoot, modulePath))
+}
+
 // CleanModulePathWithRepo removes the Go module prefix from a file path with repository-specific awareness
 // This handles cases where paths have been pre-normalized to start with just the repo name
 // e.g., "go-coverage/internal/badge/generator.go" -> "internal/badge/generator.go"
@@ -437,3 +437,24 @@ func TestBuildGitHubFileURL(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildGitHubModuleFileURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		moduleRoot string
+		filePath   string
+		expected   string
+	}{
+		{"module at the repository root", "", "internal/cli/cancel.go", "https://github.com/mrz1836/go-broadcast/blob/master/internal/cli/cancel.go"},
+		{"module-relative path", "services/api", "internal/cli/cancel.go", "https://github.com/mrz1836/go-broadcast/blob/master/services/api/internal/cli/cancel.go"},
+		{"trailing slash of the module root", "services/api/", "main.go", "https://github.com/mrz1836/go-broadcast/blob/master/services/api/main.go"},
+		{"import path ending in the module root", "services/api", "github.com/mrz1836/go-broadcast/services/api/main.go", "https://github.com/mrz1836/go-broadcast/blob/master/services/api/main.go"},
+		{"sibling directory with the same prefix", "services/api", "services/api-gateway/main.go", "https://github.com/mrz1836/go-broadcast/blob/master/services/api/services/api-gateway/main.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, BuildGitHubModuleFileURL(testOwner, testRepo, testBranch, tt.moduleRoot, tt.filePath))
+		})
+	}
+	require.Empty(t, BuildGitHubModuleFileURL(testOwner, testRepo, testBranch, "services/api", ""))
+}
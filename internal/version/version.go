@@ -38,11 +38,12 @@ type Info struct {
 
 // GetLatestRelease fetches the latest release from GitHub
 func GetLatestRelease(owner, repo string) (*GitHubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	return GetLatestReleaseWithClient(&http.Client{Timeout: 10 * time.Second}, owner, repo)
+}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+// GetLatestReleaseWithClient fetches the latest release from GitHub using the given HTTP client
+func GetLatestReleaseWithClient(client *http.Client, owner, repo string) (*GitHubRelease, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
 
 	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
 	if err != nil {
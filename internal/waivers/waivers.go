@@ -0,0 +1,211 @@
+// Package waivers records why paths and files are excluded from coverage. A waiver gives an
+// exclusion a reason, an owner and optionally an expiry date, so exclusions are revisited
+// instead of silently outliving their purpose.
+package waivers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidWaiver indicates a waiver that cannot be parsed
+	ErrInvalidWaiver = errors.New("invalid coverage waiver")
+	// ErrUnknownSetting indicates a waiver setting other than reason, owner and expires
+	ErrUnknownSetting = errors.New("unknown setting, expected reason, owner or expires")
+)
+
+// DateLayout is the layout of expiry dates
+const DateLayout = time.DateOnly
+
+// ExpiringWithin is how long before its expiry date a waiver is reported as expiring
+const ExpiringWithin = 30 * 24 * time.Hour
+
+// Kinds of exclusions
+const (
+	// KindPath excludes every file whose path contains the pattern, e.g. internal/legacy/
+	KindPath = "path"
+	// KindFile excludes files whose name matches the glob pattern, e.g. *_mock.go
+	KindFile = "file"
+)
+
+// Statuses of exclusions
+const (
+	// StatusActive is an exclusion with a reason that has not expired
+	StatusActive = "active"
+	// StatusExpiring is an exclusion that expires within ExpiringWithin
+	StatusExpiring = "expiring"
+	// StatusExpired is an exclusion past its expiry date, to be revisited
+	StatusExpired = "expired"
+	// StatusUnexplained is an exclusion nobody gave a reason for
+	StatusUnexplained = "unexplained"
+)
+
+// defaultReasons explain the exclusions go-coverage applies out of the box
+var defaultReasons = map[string]string{
+	"vendor/":   "third-party code",
+	"test/":     "test code",
+	"testdata/": "test fixtures",
+	"*_test.go": "test files",
+	"*.pb.go":   "generated protocol buffer code",
+}
+
+// Waiver explains the exclusion of a pattern
+type Waiver struct {
+	Pattern string    `json:"pattern"`
+	Reason  string    `json:"reason,omitempty"`
+	Owner   string    `json:"owner,omitempty"`
+	Expires time.Time `json:"expires,omitzero"` // Zero when the waiver does not expire
+}
+
+// Kind returns whether the waiver excludes a path or a file name pattern: globs without a
+// slash match file names, anything else is a path
+func (w Waiver) Kind() string {
+	return KindOf(w.Pattern)
+}
+
+// KindOf returns whether a pattern excludes a path or a file name pattern
+func KindOf(pattern string) string {
+	if strings.ContainsAny(pattern, "*?[") && !strings.Contains(pattern, "/") {
+		return KindFile
+	}
+	return KindPath
+}
+
+// Parse reads waivers given as "pattern: reason=text, owner=@team, expires=2026-12-31; ...".
+// Reasons cannot contain commas or semicolons.
+func Parse(value string) ([]Waiver, error) {
+	var waivers []Waiver
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		pattern, settings, found := strings.Cut(entry, ":")
+		waiver := Waiver{Pattern: strings.TrimSpace(pattern)}
+		if !found || waiver.Pattern == "" {
+			return nil, fmt.Errorf("%w: %q (expected pattern: reason=text, owner=name, expires=YYYY-MM-DD)", ErrInvalidWaiver, strings.TrimSpace(entry))
+		}
+		for _, setting := range strings.Split(settings, ",") {
+			if strings.TrimSpace(setting) == "" {
+				continue
+			}
+			key, raw, ok := strings.Cut(setting, "=")
+			key, raw = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(raw)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: %q is not key=value", ErrInvalidWaiver, waiver.Pattern, strings.TrimSpace(setting))
+			}
+			switch key {
+			case "reason":
+				waiver.Reason = raw
+			case "owner":
+				waiver.Owner = raw
+			case "expires":
+				expires, err := time.Parse(DateLayout, raw)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidWaiver, waiver.Pattern, key, err)
+				}
+				waiver.Expires = expires
+			default:
+				return nil, fmt.Errorf("%w: %s: %s: %w", ErrInvalidWaiver, waiver.Pattern, key, ErrUnknownSetting)
+			}
+		}
+		waivers = append(waivers, waiver)
+	}
+	return waivers, nil
+}
+
+// Exclusion is a configured exclusion with the waiver that explains it
+type Exclusion struct {
+	Pattern string    `json:"pattern"`
+	Kind    string    `json:"kind"`
+	Reason  string    `json:"reason,omitempty"`
+	Owners  []string  `json:"owners,omitempty"`
+	Expires time.Time `json:"expires,omitzero"`
+	Status  string    `json:"status"`
+}
+
+// Report lists every exclusion, expired ones first
+type Report struct {
+	Exclusions  []Exclusion `json:"exclusions"`
+	Expired     int         `json:"expired"`
+	Expiring    int         `json:"expiring"`
+	Unexplained int         `json:"unexplained"`
+}
+
+// ExpiredWaivers returns the exclusions past their expiry date
+func (r *Report) ExpiredWaivers() []Exclusion {
+	return slices.DeleteFunc(slices.Clone(r.Exclusions), func(e Exclusion) bool { return e.Status != StatusExpired })
+}
+
+// Build reports the excluded paths and file patterns with their waivers. Exclusions without a
+// waiver get the built-in reason of the default exclusions, if any. owners finds the owners of a
+// path without a waiver owner, such as from CODEOWNERS, and may be nil.
+func Build(paths, files []string, waivers []Waiver, owners func(path string) []string, now time.Time) *Report {
+	byPattern := make(map[string]Waiver, len(waivers))
+	for _, waiver := range waivers {
+		byPattern[waiver.Pattern] = waiver
+	}
+
+	report := &Report{Exclusions: []Exclusion{}}
+	seen := make(map[string]bool)
+	for _, patterns := range []struct {
+		kind     string
+		patterns []string
+	}{{KindPath, paths}, {KindFile, files}} {
+		for _, pattern := range patterns.patterns {
+			if pattern == "" || seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+
+			waiver := byPattern[pattern]
+			exclusion := Exclusion{Pattern: pattern, Kind: patterns.kind, Reason: waiver.Reason, Expires: waiver.Expires}
+			if exclusion.Reason == "" {
+				exclusion.Reason = defaultReasons[pattern]
+			}
+			switch {
+			case waiver.Owner != "":
+				exclusion.Owners = []string{waiver.Owner}
+			case owners != nil && patterns.kind == KindPath:
+				exclusion.Owners = owners(strings.TrimSuffix(pattern, "/"))
+			}
+			exclusion.Status = status(exclusion, now)
+			switch exclusion.Status {
+			case StatusExpired:
+				report.Expired++
+			case StatusExpiring:
+				report.Expiring++
+			case StatusUnexplained:
+				report.Unexplained++
+			}
+			report.Exclusions = append(report.Exclusions, exclusion)
+		}
+	}
+
+	rank := map[string]int{StatusExpired: 0, StatusExpiring: 1, StatusUnexplained: 2, StatusActive: 3}
+	slices.SortStableFunc(report.Exclusions, func(a, b Exclusion) int {
+		return cmp.Compare(rank[a.Status], rank[b.Status])
+	})
+	return report
+}
+
+// status classifies an exclusion; a waiver expires at the end of its expiry date
+func status(exclusion Exclusion, now time.Time) string {
+	if !exclusion.Expires.IsZero() {
+		end := exclusion.Expires.AddDate(0, 0, 1)
+		if !now.Before(end) {
+			return StatusExpired
+		}
+		if end.Sub(now) <= ExpiringWithin {
+			return StatusExpiring
+		}
+	}
+	if exclusion.Reason == "" {
+		return StatusUnexplained
+	}
+	return StatusActive
+}
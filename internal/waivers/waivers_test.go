@@ -0,0 +1,82 @@
+package waivers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	waivers, err := Parse(" internal/legacy/: reason=rewrite in progress, owner=@org/core, expires=2026-12-31 ;*_mock.go: reason=generated mocks; ")
+	require.NoError(t, err)
+	assert.Equal(t, []Waiver{
+		{Pattern: "internal/legacy/", Reason: "rewrite in progress", Owner: "@org/core", Expires: date("2026-12-31")},
+		{Pattern: "*_mock.go", Reason: "generated mocks"},
+	}, waivers)
+	assert.Equal(t, KindPath, waivers[0].Kind())
+	assert.Equal(t, KindFile, waivers[1].Kind())
+	assert.Equal(t, KindPath, KindOf("cmd/*/main.go"), "globs with a slash match paths")
+
+	waivers, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, waivers)
+
+	for _, value := range []string{
+		"internal/legacy/",
+		": reason=nothing",
+		"internal/legacy/: reason",
+		"internal/legacy/: expires=someday",
+	} {
+		_, err = Parse(value)
+		require.ErrorIs(t, err, ErrInvalidWaiver, value)
+	}
+	_, err = Parse("internal/legacy/: ticket=123")
+	require.ErrorIs(t, err, ErrUnknownSetting)
+}
+
+func TestBuild(t *testing.T) {
+	now := date("2026-10-17").Add(12 * time.Hour)
+	waivers := []Waiver{
+		{Pattern: "internal/legacy/", Reason: "rewrite in progress", Owner: "@org/core", Expires: date("2026-10-16")},
+		{Pattern: "internal/beta/", Reason: "experimental", Expires: date("2026-11-01")},
+		{Pattern: "*_mock.go", Reason: "generated mocks", Expires: date("2026-10-17")},
+	}
+	owners := func(path string) []string {
+		if path == "internal/beta" {
+			return []string{"@org/beta"}
+		}
+		return nil
+	}
+
+	report := Build(
+		[]string{"vendor/", "internal/legacy/", "internal/beta/", "scripts/", "vendor/"},
+		[]string{"*_test.go", "*_mock.go"},
+		waivers, owners, now)
+
+	assert.Equal(t, []Exclusion{
+		{Pattern: "internal/legacy/", Kind: KindPath, Reason: "rewrite in progress", Owners: []string{"@org/core"}, Expires: date("2026-10-16"), Status: StatusExpired},
+		{Pattern: "internal/beta/", Kind: KindPath, Reason: "experimental", Owners: []string{"@org/beta"}, Expires: date("2026-11-01"), Status: StatusExpiring},
+		{Pattern: "*_mock.go", Kind: KindFile, Reason: "generated mocks", Expires: date("2026-10-17"), Status: StatusExpiring},
+		{Pattern: "scripts/", Kind: KindPath, Status: StatusUnexplained},
+		{Pattern: "vendor/", Kind: KindPath, Reason: "third-party code", Status: StatusActive},
+		{Pattern: "*_test.go", Kind: KindFile, Reason: "test files", Status: StatusActive},
+	}, report.Exclusions)
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, 2, report.Expiring, "a waiver lasts until the end of its expiry date")
+	assert.Equal(t, 1, report.Unexplained)
+	assert.Equal(t, []Exclusion{report.Exclusions[0]}, report.ExpiredWaivers())
+
+	report = Build(nil, nil, nil, nil, now)
+	assert.Empty(t, report.Exclusions)
+	assert.NotNil(t, report.Exclusions, "encoded as an empty list")
+}